@@ -0,0 +1,261 @@
+package bdns
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jmhodges/clock"
+	"github.com/miekg/dns"
+)
+
+// CacheConfig configures the cache of DNS responses shared by all of a
+// Client's lookups.
+type CacheConfig struct {
+	// Size is the maximum number of responses cached. The least recently used
+	// response is evicted to make room for a new one.
+	Size int
+	// MaxTTL caps how long a response is cached for, whatever the TTLs of its
+	// records.
+	MaxTTL time.Duration
+}
+
+// cacheKey identifies a query.
+type cacheKey struct {
+	qtype uint16
+	name  string
+}
+
+func newCacheKey(hostname string, qtype uint16) cacheKey {
+	return cacheKey{qtype: qtype, name: strings.ToLower(dns.Fqdn(hostname))}
+}
+
+// responseCache is a size-bounded cache of DNS responses, each of which
+// expires after its TTL.
+type responseCache struct {
+	sync.Mutex
+	maxEntries int
+	maxTTL     time.Duration
+	clk        clock.Clock
+	// lru holds *cacheEntry values, most recently used first.
+	lru     *list.List
+	entries map[cacheKey]*list.Element
+}
+
+type cacheEntry struct {
+	key     cacheKey
+	resp    *dns.Msg
+	expires time.Time
+}
+
+func newResponseCache(config CacheConfig, clk clock.Clock) *responseCache {
+	return &responseCache{
+		maxEntries: config.Size,
+		maxTTL:     config.MaxTTL,
+		clk:        clk,
+		lru:        list.New(),
+		entries:    make(map[cacheKey]*list.Element),
+	}
+}
+
+// get returns a copy of the cached response to a query, or nil if it isn't
+// cached or has expired.
+func (c *responseCache) get(key cacheKey) *dns.Msg {
+	c.Lock()
+	defer c.Unlock()
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil
+	}
+	entry := elem.Value.(*cacheEntry)
+	if !c.clk.Now().Before(entry.expires) {
+		c.lru.Remove(elem)
+		delete(c.entries, key)
+		return nil
+	}
+	c.lru.MoveToFront(elem)
+	return entry.resp.Copy()
+}
+
+// add caches a response for the lesser of its TTL and the cache's maximum.
+// Responses which shouldn't be cached are ignored.
+func (c *responseCache) add(key cacheKey, resp *dns.Msg) {
+	ttl := responseTTL(resp)
+	if ttl > c.maxTTL {
+		ttl = c.maxTTL
+	}
+	if ttl <= 0 {
+		return
+	}
+	entry := &cacheEntry{key: key, resp: resp.Copy(), expires: c.clk.Now().Add(ttl)}
+
+	c.Lock()
+	defer c.Unlock()
+	if elem, ok := c.entries[key]; ok {
+		elem.Value = entry
+		c.lru.MoveToFront(elem)
+		return
+	}
+	c.entries[key] = c.lru.PushFront(entry)
+	for c.lru.Len() > c.maxEntries {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
+
+// responseTTL returns how long a response may be cached for: the lowest TTL
+// of its answers or, for a negative response, the negative caching TTL of RFC
+// 2308, section 5. Responses other than successes and NXDOMAINs, truncated
+// responses, and negative responses without an SOA record aren't cached, and
+// get a TTL of zero.
+func responseTTL(resp *dns.Msg) time.Duration {
+	if resp.Truncated || (resp.Rcode != dns.RcodeSuccess && resp.Rcode != dns.RcodeNameError) {
+		return 0
+	}
+	var ttl uint32
+	if resp.Rcode == dns.RcodeSuccess && len(resp.Answer) > 0 {
+		ttl = resp.Answer[0].Header().Ttl
+		for _, rr := range resp.Answer[1:] {
+			if rr.Header().Ttl < ttl {
+				ttl = rr.Header().Ttl
+			}
+		}
+	} else {
+		var soa *dns.SOA
+		for _, rr := range resp.Ns {
+			if s, ok := rr.(*dns.SOA); ok {
+				soa = s
+				break
+			}
+		}
+		if soa == nil {
+			return 0
+		}
+		ttl = soa.Hdr.Ttl
+		if soa.Minttl < ttl {
+			ttl = soa.Minttl
+		}
+	}
+	return time.Duration(ttl) * time.Second
+}
+
+type attemptCacheKey struct{}
+
+// attemptCache holds the responses to the queries made during one validation
+// attempt. Concurrent identical queries share a single exchange.
+type attemptCache struct {
+	sync.Mutex
+	entries map[cacheKey]*attemptEntry
+}
+
+type attemptEntry struct {
+	// done is closed once resp and err are set.
+	done chan struct{}
+	resp *dns.Msg
+	err  error
+}
+
+// WithAttemptCache returns a context in which a Client's identical queries are
+// only made once, such as for the lookups made by a single validation attempt.
+// Responses are reused whatever their TTLs, so the context should be short
+// lived. Failed queries aren't cached.
+func WithAttemptCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, attemptCacheKey{}, &attemptCache{entries: make(map[cacheKey]*attemptEntry)})
+}
+
+// exchangeCached returns the response to a query from the context's attempt
+// cache or the Client's shared cache, if either has it, and otherwise makes the
+// query with exchangeOne and caches the response.
+func (dnsClient *impl) exchangeCached(ctx context.Context, hostname string, qtype uint16) (*dns.Msg, error) {
+	key := newCacheKey(hostname, qtype)
+	attempt, _ := ctx.Value(attemptCacheKey{}).(*attemptCache)
+	if attempt == nil {
+		return dnsClient.exchangeShared(ctx, key, hostname, qtype)
+	}
+
+	attempt.Lock()
+	entry, ok := attempt.entries[key]
+	if ok {
+		attempt.Unlock()
+		dnsClient.cacheLookups.WithLabelValues("attempt", "hit").Inc()
+		select {
+		case <-entry.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		if entry.err != nil {
+			return nil, entry.err
+		}
+		return entry.resp.Copy(), nil
+	}
+	entry = &attemptEntry{done: make(chan struct{})}
+	attempt.entries[key] = entry
+	attempt.Unlock()
+	dnsClient.cacheLookups.WithLabelValues("attempt", "miss").Inc()
+
+	entry.resp, entry.err = dnsClient.exchangeShared(ctx, key, hostname, qtype)
+	if entry.err != nil {
+		attempt.Lock()
+		delete(attempt.entries, key)
+		attempt.Unlock()
+	}
+	close(entry.done)
+	if entry.err != nil {
+		return nil, entry.err
+	}
+	return entry.resp.Copy(), nil
+}
+
+// challengeLabel is the label prepended to a domain name to find its DNS-01
+// challenge records, core.DNSPrefix.
+const challengeLabel = "_acme-challenge"
+
+// sharedCacheable returns false for queries whose responses mustn't be shared
+// between lookups: those for DNS-01 challenge records, which the subscriber
+// has typically only just provisioned. A cached answer from before then,
+// including a cached NXDOMAIN, would fail the challenge until it expired.
+func sharedCacheable(hostname string, qtype uint16) bool {
+	if qtype != dns.TypeTXT {
+		return true
+	}
+	label := strings.SplitN(hostname, ".", 2)[0]
+	return !strings.EqualFold(label, challengeLabel)
+}
+
+// exchangeShared returns the response to a query from the Client's shared
+// cache, if it's enabled and the query can be cached there, or otherwise makes
+// the query.
+func (dnsClient *impl) exchangeShared(ctx context.Context, key cacheKey, hostname string, qtype uint16) (*dns.Msg, error) {
+	if dnsClient.cache == nil || !sharedCacheable(hostname, qtype) {
+		return dnsClient.exchangeOne(ctx, hostname, qtype)
+	}
+	if resp := dnsClient.cache.get(key); resp != nil {
+		dnsClient.cacheLookups.WithLabelValues("shared", "hit").Inc()
+		return resp, nil
+	}
+	dnsClient.cacheLookups.WithLabelValues("shared", "miss").Inc()
+	resp, err := dnsClient.exchangeOne(ctx, hostname, qtype)
+	if err != nil {
+		return nil, err
+	}
+	dnsClient.cache.add(key, resp)
+	return resp, nil
+}
+
+// EnableCache makes a Client returned by New or NewTest cache the responses to
+// its queries, for all of its callers, for up to their TTLs.
+func EnableCache(client Client, config CacheConfig) error {
+	if config.Size <= 0 || config.MaxTTL <= 0 {
+		return errors.New("DNS cache size and maximum TTL must be positive")
+	}
+	c, ok := client.(*impl)
+	if !ok {
+		return errors.New("DNS client doesn't support caching")
+	}
+	c.cache = newResponseCache(config, c.clk)
+	return nil
+}
@@ -0,0 +1,196 @@
+package bdns
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jmhodges/clock"
+	"github.com/miekg/dns"
+
+	blog "github.com/letsencrypt/boulder/log"
+	"github.com/letsencrypt/boulder/metrics"
+	"github.com/letsencrypt/boulder/test"
+)
+
+// countingExchanger answers TXT queries with a record with the configured TTL,
+// or with the configured rcode and an SOA record, and counts the queries it
+// receives by name.
+type countingExchanger struct {
+	sync.Mutex
+	ttl     uint32
+	rcode   int
+	queries map[string]int
+}
+
+func (e *countingExchanger) Exchange(m *dns.Msg, a string) (*dns.Msg, time.Duration, error) {
+	e.Lock()
+	defer e.Unlock()
+	q := m.Question[0]
+	e.queries[q.Name]++
+
+	resp := new(dns.Msg)
+	resp.SetReply(m)
+	resp.Rcode = e.rcode
+	hdr := dns.RR_Header{Name: q.Name, Rrtype: q.Qtype, Class: dns.ClassINET, Ttl: e.ttl}
+	if e.rcode == dns.RcodeSuccess {
+		resp.Answer = append(resp.Answer, &dns.TXT{Hdr: hdr, Txt: []string{"abc"}})
+	} else {
+		hdr.Rrtype = dns.TypeSOA
+		resp.Ns = append(resp.Ns, &dns.SOA{Hdr: hdr, Minttl: 5})
+	}
+	return resp, time.Millisecond, nil
+}
+
+func cachingTestClient(t *testing.T, config *CacheConfig) (*impl, *countingExchanger, clock.FakeClock) {
+	fc := clock.NewFake()
+	client := NewTest(time.Second, []string{dnsLoopbackAddr}, metrics.NoopRegisterer, fc, 1, blog.UseMock())
+	if config != nil {
+		test.AssertNotError(t, EnableCache(client, *config), "EnableCache failed")
+	}
+	e := &countingExchanger{ttl: 60, queries: make(map[string]int)}
+	client.(*impl).dnsClient = e
+	return client.(*impl), e, fc
+}
+
+func TestSharedCache(t *testing.T) {
+	client, e, fc := cachingTestClient(t, &CacheConfig{Size: 2, MaxTTL: 30 * time.Second})
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		txts, err := client.LookupTXT(ctx, "Example.com")
+		test.AssertNotError(t, err, "LookupTXT failed")
+		test.AssertDeepEquals(t, txts, []string{"abc"})
+	}
+	test.AssertEquals(t, e.queries["Example.com."], 1)
+	test.AssertEquals(t, test.CountCounter(client.cacheLookups.WithLabelValues("shared", "hit")), 2)
+	test.AssertEquals(t, test.CountCounter(client.cacheLookups.WithLabelValues("shared", "miss")), 1)
+
+	// The record's TTL is 60s, but responses are cached for at most 30s.
+	fc.Add(30 * time.Second)
+	_, err := client.LookupTXT(ctx, "example.com")
+	test.AssertNotError(t, err, "LookupTXT failed")
+	test.AssertEquals(t, e.queries["example.com."], 1)
+
+	// Records with lower TTLs expire sooner.
+	e.ttl = 10
+	_, err = client.LookupTXT(ctx, "short.example.com")
+	test.AssertNotError(t, err, "LookupTXT failed")
+	fc.Add(10 * time.Second)
+	_, err = client.LookupTXT(ctx, "short.example.com")
+	test.AssertNotError(t, err, "LookupTXT failed")
+	test.AssertEquals(t, e.queries["short.example.com."], 2)
+
+	// The least recently used response is evicted to make room for new ones.
+	_, err = client.LookupTXT(ctx, "other.example.com")
+	test.AssertNotError(t, err, "LookupTXT failed")
+	_, err = client.LookupTXT(ctx, "example.com")
+	test.AssertNotError(t, err, "LookupTXT failed")
+	test.AssertEquals(t, e.queries["example.com."], 2)
+	_, err = client.LookupTXT(ctx, "short.example.com")
+	test.AssertNotError(t, err, "LookupTXT failed")
+	test.AssertEquals(t, e.queries["short.example.com."], 3)
+}
+
+func TestSharedCacheNegativeResponses(t *testing.T) {
+	client, e, fc := cachingTestClient(t, &CacheConfig{Size: 10, MaxTTL: time.Minute})
+	ctx := context.Background()
+
+	// NXDOMAINs are cached for the SOA's minimum TTL.
+	e.rcode = dns.RcodeNameError
+	for i := 0; i < 2; i++ {
+		_, err := client.LookupTXT(ctx, "nx.example.com")
+		test.AssertError(t, err, "LookupTXT succeeded for NXDOMAIN")
+	}
+	test.AssertEquals(t, e.queries["nx.example.com."], 1)
+	fc.Add(5 * time.Second)
+	_, err := client.LookupTXT(ctx, "nx.example.com")
+	test.AssertError(t, err, "LookupTXT succeeded for NXDOMAIN")
+	test.AssertEquals(t, e.queries["nx.example.com."], 2)
+
+	// SERVFAILs aren't cached.
+	e.rcode = dns.RcodeServerFailure
+	for i := 0; i < 2; i++ {
+		_, err := client.LookupTXT(ctx, "servfail.example.com")
+		test.AssertError(t, err, "LookupTXT succeeded for SERVFAIL")
+	}
+	test.AssertEquals(t, e.queries["servfail.example.com."], 2)
+}
+
+func TestSharedCacheSkipsChallenges(t *testing.T) {
+	client, e, _ := cachingTestClient(t, &CacheConfig{Size: 10, MaxTTL: time.Minute})
+	ctx := context.Background()
+
+	// DNS-01 challenge records, and their absence, are looked up afresh each
+	// time, since they're likely to have just changed.
+	for i := 0; i < 2; i++ {
+		_, err := client.LookupTXT(ctx, "_ACME-challenge.example.com")
+		test.AssertNotError(t, err, "LookupTXT failed")
+	}
+	test.AssertEquals(t, e.queries["_ACME-challenge.example.com."], 2)
+	e.rcode = dns.RcodeNameError
+	for i := 0; i < 2; i++ {
+		_, err := client.LookupTXT(ctx, "_acme-challenge.nx.example.com")
+		test.AssertError(t, err, "LookupTXT succeeded for NXDOMAIN")
+	}
+	test.AssertEquals(t, e.queries["_acme-challenge.nx.example.com."], 2)
+	test.AssertEquals(t, test.CountCounter(client.cacheLookups.WithLabelValues("shared", "miss")), 0)
+
+	// Other names beginning with the same label are cached.
+	e.rcode = dns.RcodeSuccess
+	for i := 0; i < 2; i++ {
+		_, err := client.LookupTXT(ctx, "_acme-challenge-other.example.com")
+		test.AssertNotError(t, err, "LookupTXT failed")
+	}
+	test.AssertEquals(t, e.queries["_acme-challenge-other.example.com."], 1)
+}
+
+func TestAttemptCache(t *testing.T) {
+	client, e, _ := cachingTestClient(t, nil)
+
+	// Without an attempt cache, or a shared cache, every lookup is queried.
+	for i := 0; i < 2; i++ {
+		_, err := client.LookupTXT(context.Background(), "example.com")
+		test.AssertNotError(t, err, "LookupTXT failed")
+	}
+	test.AssertEquals(t, e.queries["example.com."], 2)
+
+	// With one, concurrent and repeated lookups share a query, whatever the
+	// record's TTL.
+	e.ttl = 0
+	ctx := WithAttemptCache(context.Background())
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			txts, err := client.LookupTXT(ctx, "attempt.example.com")
+			test.AssertNotError(t, err, "LookupTXT failed")
+			test.AssertDeepEquals(t, txts, []string{"abc"})
+		}()
+	}
+	wg.Wait()
+	test.AssertEquals(t, e.queries["attempt.example.com."], 1)
+	test.AssertEquals(t, test.CountCounter(client.cacheLookups.WithLabelValues("attempt", "hit")), 4)
+
+	// But a new attempt queries again.
+	_, err := client.LookupTXT(WithAttemptCache(context.Background()), "attempt.example.com")
+	test.AssertNotError(t, err, "LookupTXT failed")
+	test.AssertEquals(t, e.queries["attempt.example.com."], 2)
+
+	// Failed queries are retried within an attempt.
+	client.dnsClient = &testExchanger{errs: []error{errTooManyRequests}}
+	_, err = client.LookupTXT(ctx, "fail.example.com")
+	test.AssertError(t, err, "LookupTXT succeeded")
+	client.dnsClient = e
+	_, err = client.LookupTXT(ctx, "fail.example.com")
+	test.AssertNotError(t, err, "LookupTXT failed after a failure")
+}
+
+func TestEnableCache(t *testing.T) {
+	client := NewTest(time.Second, []string{dnsLoopbackAddr}, metrics.NoopRegisterer, clock.NewFake(), 1, blog.UseMock())
+	test.AssertError(t, EnableCache(client, CacheConfig{Size: 0, MaxTTL: time.Minute}), "accepted zero size")
+	test.AssertError(t, EnableCache(client, CacheConfig{Size: 10}), "accepted zero max TTL")
+	test.AssertError(t, EnableCache(&MockClient{}, CacheConfig{Size: 10, MaxTTL: time.Minute}), "enabled cache on a mock")
+}
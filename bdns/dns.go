@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"math/rand"
 	"net"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -151,6 +152,7 @@ type Client interface {
 	LookupTXT(context.Context, string) (txts []string, err error)
 	LookupHost(context.Context, string) ([]net.IP, error)
 	LookupCAA(context.Context, string) ([]*dns.CAA, string, error)
+	LookupMX(context.Context, string) ([]string, error)
 }
 
 // impl represents a client that talks to an external resolver
@@ -166,6 +168,10 @@ type impl struct {
 	totalLookupTime   *prometheus.HistogramVec
 	timeoutCounter    *prometheus.CounterVec
 	idMismatchCounter *prometheus.CounterVec
+	cacheLookups      *prometheus.CounterVec
+
+	// cache, if set, holds responses shared by all lookups. See EnableCache.
+	cache *responseCache
 }
 
 var _ Client = &impl{}
@@ -220,7 +226,14 @@ func New(
 		},
 		[]string{"qtype", "resolver"},
 	)
-	stats.MustRegister(queryTime, totalLookupTime, timeoutCounter, idMismatchCounter)
+	cacheLookups := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "dns_cache_lookups",
+			Help: "Counter of DNS cache lookups sliced by cache (attempt or shared) and result (hit or miss)",
+		},
+		[]string{"cache", "result"},
+	)
+	stats.MustRegister(queryTime, totalLookupTime, timeoutCounter, idMismatchCounter, cacheLookups)
 
 	return &impl{
 		dnsClient:                dnsClient,
@@ -232,6 +245,7 @@ func New(
 		totalLookupTime:          totalLookupTime,
 		timeoutCounter:           timeoutCounter,
 		idMismatchCounter:        idMismatchCounter,
+		cacheLookups:             cacheLookups,
 		log:                      log,
 	}
 }
@@ -388,7 +402,7 @@ type dnsResp struct {
 func (dnsClient *impl) LookupTXT(ctx context.Context, hostname string) ([]string, error) {
 	var txt []string
 	dnsType := dns.TypeTXT
-	r, err := dnsClient.exchangeOne(ctx, hostname, dnsType)
+	r, err := dnsClient.exchangeCached(ctx, hostname, dnsType)
 	if err != nil {
 		return nil, &Error{dnsType, hostname, err, -1}
 	}
@@ -425,8 +439,17 @@ func isPrivateV6(ip net.IP) bool {
 	return false
 }
 
+// IsPrivateIP returns true if the IP address is in one of the private or
+// reserved networks whose addresses LookupHost never returns.
+func IsPrivateIP(ip net.IP) bool {
+	if ip.To4() != nil {
+		return isPrivateV4(ip)
+	}
+	return isPrivateV6(ip)
+}
+
 func (dnsClient *impl) lookupIP(ctx context.Context, hostname string, ipType uint16) ([]dns.RR, error) {
-	resp, err := dnsClient.exchangeOne(ctx, hostname, ipType)
+	resp, err := dnsClient.exchangeCached(ctx, hostname, ipType)
 	if err != nil {
 		return nil, &Error{ipType, hostname, err, -1}
 	}
@@ -489,7 +512,7 @@ func (dnsClient *impl) LookupHost(ctx context.Context, hostname string) ([]net.I
 // response is non-empty.
 func (dnsClient *impl) LookupCAA(ctx context.Context, hostname string) ([]*dns.CAA, string, error) {
 	dnsType := dns.TypeCAA
-	r, err := dnsClient.exchangeOne(ctx, hostname, dnsType)
+	r, err := dnsClient.exchangeCached(ctx, hostname, dnsType)
 	if err != nil {
 		return nil, "", &Error{dnsType, hostname, err, -1}
 	}
@@ -511,6 +534,35 @@ func (dnsClient *impl) LookupCAA(ctx context.Context, hostname string) ([]*dns.C
 	return CAAs, response, nil
 }
 
+// LookupMX sends a DNS query to find the MX records associated with the
+// provided hostname, and returns their exchange hosts, most preferred first.
+// A null MX (RFC 7505) is returned as the single host ".".
+func (dnsClient *impl) LookupMX(ctx context.Context, hostname string) ([]string, error) {
+	dnsType := dns.TypeMX
+	r, err := dnsClient.exchangeCached(ctx, hostname, dnsType)
+	if err != nil {
+		return nil, &Error{dnsType, hostname, err, -1}
+	}
+	if r.Rcode != dns.RcodeSuccess {
+		return nil, &Error{dnsType, hostname, nil, r.Rcode}
+	}
+
+	var mxs []*dns.MX
+	for _, answer := range r.Answer {
+		if mx, ok := answer.(*dns.MX); ok {
+			mxs = append(mxs, mx)
+		}
+	}
+	sort.SliceStable(mxs, func(i, j int) bool {
+		return mxs[i].Preference < mxs[j].Preference
+	})
+	hosts := make([]string, len(mxs))
+	for i, mx := range mxs {
+		hosts[i] = mx.Mx
+	}
+	return hosts, nil
+}
+
 // logDNSError logs the provided err result from making a query for hostname to
 // the chosenServer. If the err is a `dns.ErrId` instance then the Base64
 // encoded bytes of the query (and if not-nil, the response) in wire format
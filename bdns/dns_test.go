@@ -142,6 +142,21 @@ func mockDNSQuery(w dns.ResponseWriter, r *dns.Msg) {
 				record.Flag = 1
 				appendAnswer(record)
 			}
+		case dns.TypeMX:
+			if q.Name == "mx.letsencrypt.org." {
+				for _, mx := range []*dns.MX{{Preference: 20, Mx: "backup.letsencrypt.org."}, {Preference: 10, Mx: "mail.letsencrypt.org."}} {
+					mx.Hdr = dns.RR_Header{Name: q.Name, Rrtype: dns.TypeMX, Class: dns.ClassINET, Ttl: 0}
+					appendAnswer(mx)
+				}
+			}
+			if q.Name == "null-mx.letsencrypt.org." {
+				record := &dns.MX{Preference: 0, Mx: "."}
+				record.Hdr = dns.RR_Header{Name: q.Name, Rrtype: dns.TypeMX, Class: dns.ClassINET, Ttl: 0}
+				appendAnswer(record)
+			}
+			if q.Name == "nxdomain.letsencrypt.org." {
+				m.SetRcode(r, dns.RcodeNameError)
+			}
 		case dns.TypeTXT:
 			if q.Name == "split-txt.letsencrypt.org." {
 				record := new(dns.TXT)
@@ -424,6 +439,26 @@ caa.example.com.	0	IN	CAA	1 issue "letsencrypt.org"
 	test.AssertEquals(t, removeIDExp.ReplaceAllString(resp, " id: XXXX"), expectedResp)
 }
 
+func TestDNSLookupMX(t *testing.T) {
+	obj := NewTest(time.Second*10, []string{dnsLoopbackAddr}, metrics.NoopRegisterer, clock.NewFake(), 1, blog.UseMock())
+
+	hosts, err := obj.LookupMX(context.Background(), "mx.letsencrypt.org")
+	test.AssertNotError(t, err, "MX lookup failed")
+	test.AssertDeepEquals(t, hosts, []string{"mail.letsencrypt.org.", "backup.letsencrypt.org."})
+
+	hosts, err = obj.LookupMX(context.Background(), "null-mx.letsencrypt.org")
+	test.AssertNotError(t, err, "MX lookup failed")
+	test.AssertDeepEquals(t, hosts, []string{"."})
+
+	hosts, err = obj.LookupMX(context.Background(), "letsencrypt.org")
+	test.AssertNotError(t, err, "MX lookup failed")
+	test.AssertEquals(t, len(hosts), 0)
+
+	_, err = obj.LookupMX(context.Background(), "nxdomain.letsencrypt.org")
+	test.AssertDeepEquals(t, err, &Error{dns.TypeMX, "nxdomain.letsencrypt.org", nil, dns.RcodeNameError})
+	test.Assert(t, err.(*Error).NameError(), "NXDOMAIN wasn't a NameError")
+}
+
 func TestIsPrivateIP(t *testing.T) {
 	test.Assert(t, isPrivateV4(net.ParseIP("127.0.0.1")), "should be private")
 	test.Assert(t, isPrivateV4(net.ParseIP("192.168.254.254")), "should be private")
@@ -69,7 +69,8 @@ func (t timeoutError) Timeout() bool {
 // LookupHost is a mock
 func (mock *MockClient) LookupHost(_ context.Context, hostname string) ([]net.IP, error) {
 	if hostname == "always.invalid" ||
-		hostname == "invalid.invalid" {
+		hostname == "invalid.invalid" ||
+		hostname == "no-mx.com" {
 		return []net.IP{}, nil
 	}
 	if hostname == "always.timeout" {
@@ -122,3 +123,18 @@ func (mock *MockClient) LookupHost(_ context.Context, hostname string) ([]net.IP
 func (mock *MockClient) LookupCAA(_ context.Context, domain string) ([]*dns.CAA, string, error) {
 	return nil, "", nil
 }
+
+// LookupMX is a mock
+func (mock *MockClient) LookupMX(_ context.Context, domain string) ([]string, error) {
+	switch domain {
+	case "nxdomain.com":
+		return nil, &Error{dns.TypeMX, domain, nil, dns.RcodeNameError}
+	case "servfail.com":
+		return nil, &Error{dns.TypeMX, domain, nil, dns.RcodeServerFailure}
+	case "null-mx.com":
+		return []string{"."}, nil
+	case "no-mx.com":
+		return nil, nil
+	}
+	return []string{"mail." + domain + "."}, nil
+}
@@ -49,6 +49,12 @@ func (d Error) Error() string {
 		dns.TypeToString[d.recordType], d.hostname, additional)
 }
 
+// NameError returns true if the lookup failed because the name doesn't
+// exist (NXDOMAIN).
+func (d Error) NameError() bool {
+	return d.underlying == nil && d.rCode == dns.RcodeNameError
+}
+
 // Timeout returns true if the underlying error was a timeout
 func (d Error) Timeout() bool {
 	if netErr, ok := d.underlying.(*net.OpError); ok {
@@ -7,6 +7,7 @@ import (
 	"crypto/ecdsa"
 	"crypto/rand"
 	"crypto/rsa"
+	"crypto/sha256"
 	"crypto/x509"
 	"encoding/asn1"
 	"encoding/hex"
@@ -38,6 +39,7 @@ import (
 	"github.com/letsencrypt/boulder/features"
 	"github.com/letsencrypt/boulder/goodkey"
 	"github.com/letsencrypt/boulder/issuance"
+	"github.com/letsencrypt/boulder/issuancelog"
 	blog "github.com/letsencrypt/boulder/log"
 	sapb "github.com/letsencrypt/boulder/sa/proto"
 )
@@ -117,11 +119,14 @@ const (
 // where features.StoreIssuerInfo is true and the OCSP request is identified
 // by Serial and IssuerID rather than by the full cert. Lookup by NameID is
 // useful as a easier-to-compute replacement for both byName and byID lookups.
+// S/MIME issuers are in smimeByAlg rather than byAlg, so that the issuers of
+// TLS and S/MIME certificates are kept separate.
 type issuerMaps struct {
-	byAlg    map[x509.PublicKeyAlgorithm]*internalIssuer
-	byName   map[string]*internalIssuer
-	byID     map[issuance.IssuerID]*internalIssuer
-	byNameID map[issuance.IssuerNameID]*internalIssuer
+	byAlg      map[x509.PublicKeyAlgorithm]*internalIssuer
+	smimeByAlg map[x509.PublicKeyAlgorithm]*internalIssuer
+	byName     map[string]*internalIssuer
+	byID       map[issuance.IssuerID]*internalIssuer
+	byNameID   map[issuance.IssuerNameID]*internalIssuer
 }
 
 // CertificateAuthorityImpl represents a CA that signs certificates, CRLs, and
@@ -148,6 +153,10 @@ type CertificateAuthorityImpl struct {
 	orphanCount        *prometheus.CounterVec
 	adoptedOrphanCount *prometheus.CounterVec
 	signErrorCounter   *prometheus.CounterVec
+
+	// issuanceLog, if set, records each certificate before it's signed. See
+	// SetIssuanceLog.
+	issuanceLog *issuancelog.Log
 }
 
 // Issuer represents a single issuer certificate, along with its key.
@@ -177,6 +186,7 @@ type internalIssuer struct {
 
 func makeInternalIssuers(issuers []*issuance.Issuer, lifespanOCSP time.Duration) (issuerMaps, error) {
 	issuersByAlg := make(map[x509.PublicKeyAlgorithm]*internalIssuer, 2)
+	smimeIssuersByAlg := make(map[x509.PublicKeyAlgorithm]*internalIssuer, 2)
 	issuersByName := make(map[string]*internalIssuer, len(issuers))
 	issuersByID := make(map[issuance.IssuerID]*internalIssuer, len(issuers))
 	issuersByNameID := make(map[issuance.IssuerNameID]*internalIssuer, len(issuers))
@@ -186,11 +196,15 @@ func makeInternalIssuers(issuers []*issuance.Issuer, lifespanOCSP time.Duration)
 			ocspSigner:    issuer.Signer,
 			boulderIssuer: issuer,
 		}
+		byAlg := issuersByAlg
+		if issuer.SMIME() {
+			byAlg = smimeIssuersByAlg
+		}
 		for _, alg := range issuer.Algs() {
 			// TODO(#5259): Enforce that there is only one issuer for each algorithm,
 			// instead of taking the first issuer for each algorithm type.
-			if issuersByAlg[alg] == nil {
-				issuersByAlg[alg] = ii
+			if byAlg[alg] == nil {
+				byAlg[alg] = ii
 			}
 		}
 		if issuersByName[issuer.Name()] != nil {
@@ -200,7 +214,7 @@ func makeInternalIssuers(issuers []*issuance.Issuer, lifespanOCSP time.Duration)
 		issuersByID[issuer.ID()] = ii
 		issuersByNameID[issuer.Cert.NameID()] = ii
 	}
-	return issuerMaps{issuersByAlg, issuersByName, issuersByID, issuersByNameID}, nil
+	return issuerMaps{issuersByAlg, smimeIssuersByAlg, issuersByName, issuersByID, issuersByNameID}, nil
 }
 
 func makeCFSSLInternalIssuers(issuers []Issuer, policy *cfsslConfig.Signing, lifespanOCSP time.Duration) (issuerMaps, error) {
@@ -245,7 +259,7 @@ func makeCFSSLInternalIssuers(issuers []Issuer, policy *cfsslConfig.Signing, lif
 		issuersByID[iss.Cert.ID()] = ii
 		issuersByNameID[iss.Cert.NameID()] = ii
 	}
-	return issuerMaps{issuersByAlg, issuersByName, issuersByID, issuersByNameID}, nil
+	return issuerMaps{issuersByAlg, nil, issuersByName, issuersByID, issuersByNameID}, nil
 }
 
 // NewCertificateAuthorityImpl creates a CA instance that can sign certificates
@@ -400,6 +414,38 @@ func NewCertificateAuthorityImpl(
 	return ca, nil
 }
 
+// SetIssuanceLog makes the CA append an entry to the hash-chained issuance
+// log for each precertificate and certificate before signing it, and refuse
+// to sign it if the entry can't be appended.
+func (ca *CertificateAuthorityImpl) SetIssuanceLog(log *issuancelog.Log) {
+	ca.issuanceLog = log
+}
+
+// recordIssuance appends an entry to the issuance log, if there is one, for a
+// certificate with the given names which is about to be signed.
+func (ca *CertificateAuthorityImpl) recordIssuance(ctx context.Context, entryType certificateType, serialHex string, profile string, dnsNames []string, emailAddresses []string, csrDER []byte) error {
+	if ca.issuanceLog == nil {
+		return nil
+	}
+	entry := issuancelog.Entry{
+		Type:      string(entryType),
+		Serial:    serialHex,
+		Profile:   profile,
+		NamesHash: issuancelog.NamesHash(append(append([]string{}, dnsNames...), emailAddresses...)),
+	}
+	if csrDER != nil {
+		csrHash := sha256.Sum256(csrDER)
+		entry.CSRHash = hex.EncodeToString(csrHash[:])
+	}
+	_, err := ca.issuanceLog.Append(ctx, entry)
+	if err != nil {
+		err = berrors.InternalServerError("failed to record issuance: %s", err)
+		ca.log.AuditErrf("Issuance log append failed, not signing: serial=[%s] err=[%v]", serialHex, err)
+		return err
+	}
+	return nil
+}
+
 // noteSignError is called after operations that may cause a CFSSL
 // or PKCS11 signing error.
 func (ca *CertificateAuthorityImpl) noteSignError(err error) {
@@ -654,9 +700,13 @@ func (ca *CertificateAuthorityImpl) IssuePrecertificate(ctx context.Context, iss
 // final certificate, but this is just a belt-and-suspenders measure, since
 // there could be race conditions where two goroutines are issuing for the same
 // serial number at the same time.
+//
+// S/MIME certificates aren't logged to CT, so the certificate issued for them by
+// IssuePrecertificate is final. It's stored as the final certificate, without
+// SCTs, rather than being signed again.
 func (ca *CertificateAuthorityImpl) IssueCertificateForPrecertificate(ctx context.Context, req *capb.IssueCertificateForPrecertificateRequest) (*corepb.Certificate, error) {
 	// issueReq.orderID may be zero, for ACMEv1 requests.
-	if core.IsAnyNilOrZero(req, req.DER, req.SCTs, req.RegistrationID) {
+	if core.IsAnyNilOrZero(req, req.DER, req.RegistrationID) {
 		return nil, berrors.InternalServerError("Incomplete cert for precertificate request")
 	}
 
@@ -664,6 +714,10 @@ func (ca *CertificateAuthorityImpl) IssueCertificateForPrecertificate(ctx contex
 	if err != nil {
 		return nil, err
 	}
+	smime := len(precert.EmailAddresses) > 0
+	if !smime && len(req.SCTs) == 0 {
+		return nil, berrors.InternalServerError("Incomplete cert for precertificate request")
+	}
 
 	serialHex := core.SerialToString(precert.SerialNumber)
 	if _, err = ca.sa.GetCertificate(ctx, serialHex); err == nil {
@@ -688,6 +742,15 @@ func (ca *CertificateAuthorityImpl) IssueCertificateForPrecertificate(ctx contex
 		return nil, berrors.InternalServerError("no issuer found for Issuer Name %s", precert.Issuer)
 	}
 
+	if smime {
+		return ca.storeSMIMECertificate(ctx, req, precert, issuer)
+	}
+
+	err = ca.recordIssuance(ctx, certType, serialHex, req.CertificateProfileName, precert.DNSNames, precert.EmailAddresses, nil)
+	if err != nil {
+		return nil, err
+	}
+
 	var certDER []byte
 	if features.Enabled(features.NonCFSSLSigner) {
 		issuanceReq, err := issuance.RequestFromPrecert(precert, scts)
@@ -729,6 +792,31 @@ func (ca *CertificateAuthorityImpl) IssueCertificateForPrecertificate(ctx contex
 	}, nil
 }
 
+// storeSMIMECertificate stores the S/MIME certificate issued by
+// IssuePrecertificate as the final certificate, after checking that it was
+// signed by an S/MIME issuer.
+func (ca *CertificateAuthorityImpl) storeSMIMECertificate(ctx context.Context, req *capb.IssueCertificateForPrecertificateRequest, cert *x509.Certificate, issuer *internalIssuer) (*corepb.Certificate, error) {
+	if issuer.boulderIssuer == nil || !issuer.boulderIssuer.SMIME() {
+		return nil, berrors.InternalServerError("certificate for email addresses wasn't issued by an S/MIME issuer")
+	}
+	err := cert.CheckSignatureFrom(issuer.cert.Certificate)
+	if err != nil {
+		return nil, berrors.InternalServerError("S/MIME certificate wasn't signed by its issuer: %s", err)
+	}
+	err = ca.storeCertificate(ctx, req.RegistrationID, req.OrderID, cert.SerialNumber, req.DER, int64(issuer.cert.ID()))
+	if err != nil {
+		return nil, err
+	}
+	return &corepb.Certificate{
+		RegistrationID: req.RegistrationID,
+		Serial:         core.SerialToString(cert.SerialNumber),
+		Der:            req.DER,
+		Digest:         core.Fingerprint256(req.DER),
+		Issued:         cert.NotBefore.UnixNano(),
+		Expires:        cert.NotAfter.UnixNano(),
+	}, nil
+}
+
 type validity struct {
 	NotBefore time.Time
 	NotAfter  time.Time
@@ -782,6 +870,14 @@ func (ca *CertificateAuthorityImpl) issuePrecertificateInner(ctx context.Context
 		return nil, nil, err
 	}
 
+	// S/MIME certificates are issued by separate issuers, and aren't logged
+	// to CT, so the certificate issued for them is final rather than a
+	// precertificate.
+	smime := len(csr.EmailAddresses) > 0
+	if smime && !features.Enabled(features.NonCFSSLSigner) {
+		return nil, nil, berrors.InternalServerError("S/MIME certificates can only be issued by the NonCFSSLSigner")
+	}
+
 	var issuer *internalIssuer
 	var ok bool
 	if issueReq.IssuerNameID == 0 {
@@ -792,7 +888,11 @@ func (ca *CertificateAuthorityImpl) issuePrecertificateInner(ctx context.Context
 		if alg == x509.ECDSA && !features.Enabled(features.ECDSAForAll) && !ca.ecdsaAllowedRegIDs[issueReq.RegistrationID] {
 			alg = x509.RSA
 		}
-		issuer, ok = ca.issuers.byAlg[alg]
+		if smime {
+			issuer, ok = ca.issuers.smimeByAlg[alg]
+		} else {
+			issuer, ok = ca.issuers.byAlg[alg]
+		}
 		if !ok {
 			return nil, nil, berrors.InternalServerError("no issuer found for public key algorithm %s", csr.PublicKeyAlgorithm)
 		}
@@ -801,6 +901,9 @@ func (ca *CertificateAuthorityImpl) issuePrecertificateInner(ctx context.Context
 		if !ok {
 			return nil, nil, berrors.InternalServerError("no issuer found for IssuerNameID %d", issueReq.IssuerNameID)
 		}
+		if issuer.boulderIssuer != nil && issuer.boulderIssuer.SMIME() != smime {
+			return nil, nil, berrors.InternalServerError("issuer for IssuerNameID %d can't issue this kind of certificate", issueReq.IssuerNameID)
+		}
 	}
 
 	if issuer.cert.NotAfter.Before(validity.NotAfter) {
@@ -811,6 +914,11 @@ func (ca *CertificateAuthorityImpl) issuePrecertificateInner(ctx context.Context
 
 	serialHex := core.SerialToString(serialBigInt)
 
+	err = ca.recordIssuance(ctx, precertType, serialHex, issueReq.CertificateProfileName, csr.DNSNames, csr.EmailAddresses, csr.Raw)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	var certDER []byte
 	if features.Enabled(features.NonCFSSLSigner) {
 		ca.log.AuditInfof("Signing: serial=[%s] names=[%s] csr=[%s]",
@@ -820,7 +928,8 @@ func (ca *CertificateAuthorityImpl) issuePrecertificateInner(ctx context.Context
 			Serial:            serialBigInt.Bytes(),
 			CommonName:        csr.Subject.CommonName,
 			DNSNames:          csr.DNSNames,
-			IncludeCTPoison:   true,
+			EmailAddresses:    csr.EmailAddresses,
+			IncludeCTPoison:   !smime,
 			IncludeMustStaple: issuance.ContainsMustStaple(csr.Extensions),
 			NotBefore:         validity.NotBefore,
 			NotAfter:          validity.NotAfter,
@@ -986,6 +1095,9 @@ func (ca *CertificateAuthorityImpl) Stop() {
 	if ca.ocspLogQueue != nil {
 		ca.ocspLogQueue.stop()
 	}
+	if ca.issuanceLog != nil {
+		_ = ca.issuanceLog.Close()
+	}
 }
 
 // integrateOrpan removes an orphan from the queue and adds it to the database. The
@@ -1,6 +1,7 @@
 package ca
 
 import (
+	"bytes"
 	"context"
 	"crypto"
 	"crypto/ecdsa"
@@ -14,6 +15,7 @@ import (
 	"io/ioutil"
 	"math/big"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -38,6 +40,7 @@ import (
 	"github.com/letsencrypt/boulder/features"
 	"github.com/letsencrypt/boulder/goodkey"
 	"github.com/letsencrypt/boulder/issuance"
+	"github.com/letsencrypt/boulder/issuancelog"
 	"github.com/letsencrypt/boulder/lint"
 	blog "github.com/letsencrypt/boulder/log"
 	"github.com/letsencrypt/boulder/metrics"
@@ -541,6 +544,38 @@ func TestMultipleIssuers(t *testing.T) {
 	test.AssertNotError(t, err, "Certificate failed signature validation")
 }
 
+func TestSMIMEIssuersSeparate(t *testing.T) {
+	profile := func(smime bool) *issuance.Profile {
+		res, err := issuance.NewProfile(
+			issuance.ProfileConfig{
+				AllowEmailAddresses: smime,
+				MaxValidityPeriod:   cmd.ConfigDuration{Duration: time.Hour * 8760},
+				MaxValidityBackdate: cmd.ConfigDuration{Duration: time.Hour},
+			},
+			issuance.IssuerConfig{
+				UseForECDSALeaves: true,
+				UseForRSALeaves:   smime,
+				IssuerURL:         "http://not-example.com/issuer-url",
+				OCSPURL:           "http://not-example.com/ocsp",
+			},
+		)
+		test.AssertNotError(t, err, "Failed to make profile")
+		return res
+	}
+	tlsIssuer := &issuance.Issuer{Cert: caCert2, Signer: caKey, Profile: profile(false)}
+	smimeIssuer := &issuance.Issuer{Cert: caCert, Signer: caKey, Profile: profile(true)}
+
+	// The S/MIME issuer is never used for TLS certificates, even for
+	// algorithms without a TLS issuer.
+	issuers, err := makeInternalIssuers([]*issuance.Issuer{smimeIssuer, tlsIssuer}, time.Hour)
+	test.AssertNotError(t, err, "Failed to make issuers")
+	test.AssertEquals(t, issuers.byAlg[x509.ECDSA].boulderIssuer, tlsIssuer)
+	test.AssertEquals(t, issuers.byAlg[x509.RSA], (*internalIssuer)(nil))
+	test.AssertEquals(t, issuers.smimeByAlg[x509.ECDSA].boulderIssuer, smimeIssuer)
+	test.AssertEquals(t, issuers.smimeByAlg[x509.RSA].boulderIssuer, smimeIssuer)
+	test.AssertEquals(t, issuers.byNameID[caCert.NameID()].boulderIssuer, smimeIssuer)
+}
+
 func TestECDSAAllowList(t *testing.T) {
 	req := &capb.IssueCertificateRequest{Csr: ECDSACSR, RegistrationID: arbitraryRegID}
 
@@ -1040,6 +1075,85 @@ func TestIssueCertificateForPrecertificate(t *testing.T) {
 	}
 }
 
+func TestIssuanceLog(t *testing.T) {
+	testCtx := setup(t)
+	ca, err := NewCertificateAuthorityImpl(
+		&mockSA{},
+		testCtx.pa,
+		testCtx.cfsslProfiles,
+		testCtx.cfsslRSAProfile,
+		testCtx.cfsslECDSAProfile,
+		testCtx.cfsslIssuers,
+		testCtx.boulderIssuers,
+		nil,
+		testCtx.certExpiry,
+		testCtx.certBackdate,
+		testCtx.serialPrefix,
+		testCtx.maxNames,
+		testCtx.ocspLifetime,
+		testCtx.keyPolicy,
+		nil,
+		0,
+		time.Second,
+		testCtx.logger,
+		testCtx.stats,
+		testCtx.fc)
+	test.AssertNotError(t, err, "Failed to create CA")
+
+	dir, err := ioutil.TempDir("", "issuance-log")
+	test.AssertNotError(t, err, "Failed to create temp dir")
+	defer os.RemoveAll(dir)
+	logFile := filepath.Join(dir, "issuance-log.jsonl")
+	issuanceLog, err := issuancelog.Open(logFile, "ca-test", nil, testCtx.fc, testCtx.logger)
+	test.AssertNotError(t, err, "Failed to open issuance log")
+	ca.SetIssuanceLog(issuanceLog)
+
+	precert, err := ca.IssuePrecertificate(ctx, &capb.IssueCertificateRequest{
+		Csr:                    CNandSANCSR,
+		RegistrationID:         arbitraryRegID,
+		CertificateProfileName: "default",
+	})
+	test.AssertNotError(t, err, "Failed to issue precert")
+	parsedPrecert, err := x509.ParseCertificate(precert.DER)
+	test.AssertNotError(t, err, "Failed to parse precert")
+	sctBytes, err := makeSCTs()
+	test.AssertNotError(t, err, "Failed to make SCTs")
+	_, err = ca.IssueCertificateForPrecertificate(ctx, &capb.IssueCertificateForPrecertificateRequest{
+		DER:                    precert.DER,
+		SCTs:                   sctBytes,
+		RegistrationID:         arbitraryRegID,
+		CertificateProfileName: "default",
+	})
+	test.AssertNotError(t, err, "Failed to issue cert from precert")
+
+	contents, err := ioutil.ReadFile(logFile)
+	test.AssertNotError(t, err, "Failed to read issuance log")
+	var entries []issuancelog.Entry
+	_, err = issuancelog.Verify(bytes.NewReader(contents), func(e issuancelog.Entry) error {
+		entries = append(entries, e)
+		return nil
+	})
+	test.AssertNotError(t, err, "Failed to verify issuance log")
+	test.AssertEquals(t, len(entries), 2)
+	serial := core.SerialToString(parsedPrecert.SerialNumber)
+	namesHash := issuancelog.NamesHash(parsedPrecert.DNSNames)
+	test.AssertEquals(t, entries[0].Type, issuancelog.TypePrecertificate)
+	test.AssertEquals(t, entries[0].Serial, serial)
+	test.AssertEquals(t, entries[0].Profile, "default")
+	test.AssertEquals(t, entries[0].NamesHash, namesHash)
+	test.AssertNotEquals(t, entries[0].CSRHash, "")
+	test.AssertEquals(t, entries[1].Type, issuancelog.TypeCertificate)
+	test.AssertEquals(t, entries[1].Serial, serial)
+	test.AssertEquals(t, entries[1].NamesHash, namesHash)
+	test.AssertEquals(t, entries[1].CSRHash, "")
+
+	// Nothing is signed once the log can't be appended to.
+	test.AssertNotError(t, issuanceLog.Close(), "Failed to close issuance log")
+	_, err = ca.IssuePrecertificate(ctx, &capb.IssueCertificateRequest{Csr: CNandSANCSR, RegistrationID: arbitraryRegID})
+	test.AssertError(t, err, "Issued precert without appending to the issuance log")
+	test.Assert(t, errors.Is(err, berrors.InternalServer), "Wrong error type")
+}
+
 // dupeSA returns a non-error to GetCertificate in order to simulate a request
 // to issue a final certificate with a duplicate serial.
 type dupeSA struct {
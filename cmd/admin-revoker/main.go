@@ -1,10 +1,14 @@
 package main
 
 import (
+	"bufio"
 	"context"
 	"crypto/x509"
+	"encoding/hex"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
 	"os/user"
@@ -12,6 +16,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/letsencrypt/boulder/cmd"
 	"github.com/letsencrypt/boulder/core"
@@ -33,12 +38,32 @@ admin-revoker serial-revoke --config <path> <serial> <reason-code>
 admin-revoker batched-serial-revoke --config <path> <serial-file-path> <reason-code> <parallelism>
 admin-revoker reg-revoke --config <path> <registration-id> <reason-code>
 admin-revoker list-reasons --config <path>
+admin-revoker incident-create --config <path> <name> <url> <renew-by>
+admin-revoker incident-import-serials --config <path> <incident-id> <serial-file-path>
+admin-revoker incident-set-status --config <path> <incident-id> <enabled> <resolved>
+admin-revoker incident-check-serial --config <path> <serial>
+admin-revoker notifications-get --config <path> <registration-id>
+admin-revoker notifications-set --config <path> <registration-id> <setting>=<value>...
 
 command descriptions:
   serial-revoke       Revoke a single certificate by the hex serial number
   batched-serial-revoke Revokes all certificates contained in a file of hex serial numbers
   reg-revoke          Revoke all certificates associated with a registration ID
   list-reasons        List all revocation reason codes
+  incident-create     Create a new, disabled incident. renew-by is an RFC 3339 timestamp
+  incident-import-serials Associate all serials (and optional registration IDs)
+                      contained in a file with an incident. Use "-" to read from stdin.
+                      Each line is "<serial>" or "<serial>,<registration-id>"
+  incident-set-status Set the enabled and resolved flags of an incident
+  incident-check-serial List the enabled, unresolved incidents affecting a serial
+  notifications-get   Show the expiration notification preferences of a registration
+  notifications-set   Change the expiration notification preferences of a registration.
+                      Settings not given are left unchanged. Valid settings are:
+                        email-opt-out=<true|false>
+                        email=<address>            (empty to use the registration's contacts)
+                        nag-times=<duration>,...   (e.g. "24h,168h"; empty for the default schedule)
+                        webhook-url=<https URL>    (empty to disable the webhook)
+                        webhook-secret=<hex key>
 
 args:
   config    File path to the configuration file for this service
@@ -83,6 +108,8 @@ func setupContext(c config) (core.RegistrationAuthority, blog.Logger, *db.Wrappe
 	}
 	dbMap, err := sa.NewDbMap(dbURL, dbSettings)
 	cmd.FailOnError(err, "Couldn't setup database connection")
+	err = c.Revoker.DBConfig.StartAdaptivePool(dbMap.Db, metrics.NoopRegisterer, logger)
+	cmd.FailOnError(err, "Invalid adaptive DB pool config")
 
 	saConn, err := bgrpc.ClientSetup(c.Revoker.SAService, tlsConfig, clientMetrics, clk)
 	cmd.FailOnError(err, "Failed to load credentials and create gRPC connection to SA")
@@ -171,6 +198,127 @@ func revokeBatch(rac core.RegistrationAuthority, logger blog.Logger, dbMap *db.W
 	return nil
 }
 
+// incidentImportBatchSize is the number of serials sent to the SA in each
+// AddIncidentSerials request when importing serials for an incident.
+const incidentImportBatchSize = 1000
+
+// parseIncidentSerial parses a single line of an incident serial file, which
+// is either a bare hex serial or a serial and registration ID separated by a
+// comma.
+func parseIncidentSerial(line string) (*sapb.IncidentSerial, error) {
+	fields := strings.Split(line, ",")
+	if len(fields) > 2 {
+		return nil, fmt.Errorf("malformed line %q", line)
+	}
+	serial := strings.TrimSpace(fields[0])
+	if !core.ValidSerial(serial) {
+		return nil, fmt.Errorf("invalid serial %q", serial)
+	}
+	is := &sapb.IncidentSerial{Serial: serial}
+	if len(fields) == 2 {
+		regID, err := strconv.ParseInt(strings.TrimSpace(fields[1]), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid registration ID in line %q: %s", line, err)
+		}
+		is.RegistrationID = regID
+	}
+	return is, nil
+}
+
+// importIncidentSerials reads serials from r, one per line, and sends them to
+// the SA in batches of batchSize. It returns the number of serials imported.
+func importIncidentSerials(ctx context.Context, sac core.StorageAuthority, incidentID int64, r io.Reader, batchSize int) (int, error) {
+	var count int
+	var batch []*sapb.IncidentSerial
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		_, err := sac.AddIncidentSerials(ctx, &sapb.AddIncidentSerialsRequest{
+			IncidentID: incidentID,
+			Serials:    batch,
+		})
+		if err != nil {
+			return err
+		}
+		count += len(batch)
+		batch = nil
+		return nil
+	}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		// handle blank lines gracefully
+		if line == "" {
+			continue
+		}
+		is, err := parseIncidentSerial(line)
+		if err != nil {
+			return count, err
+		}
+		batch = append(batch, is)
+		if len(batch) >= batchSize {
+			err = flush()
+			if err != nil {
+				return count, err
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return count, err
+	}
+	return count, flush()
+}
+
+// applyNotificationSettings updates prefs according to a list of
+// "<setting>=<value>" arguments to the notifications-set command.
+func applyNotificationSettings(prefs *sapb.NotificationPreferences, settings []string) error {
+	for _, setting := range settings {
+		fields := strings.SplitN(setting, "=", 2)
+		if len(fields) != 2 {
+			return fmt.Errorf("malformed setting %q, expected <setting>=<value>", setting)
+		}
+		name, value := fields[0], fields[1]
+		switch name {
+		case "email-opt-out":
+			optOut, err := strconv.ParseBool(value)
+			if err != nil {
+				return fmt.Errorf("email-opt-out must be a boolean: %s", err)
+			}
+			prefs.EmailOptOut = optOut
+		case "email":
+			prefs.Email = value
+		case "nag-times":
+			prefs.NagTimes = nil
+			if value == "" {
+				continue
+			}
+			for _, s := range strings.Split(value, ",") {
+				d, err := time.ParseDuration(strings.TrimSpace(s))
+				if err != nil {
+					return fmt.Errorf("invalid nag time %q: %s", s, err)
+				}
+				prefs.NagTimes = append(prefs.NagTimes, int64(d))
+			}
+		case "webhook-url":
+			prefs.WebhookURL = value
+			if value == "" {
+				prefs.WebhookSecret = nil
+			}
+		case "webhook-secret":
+			secret, err := hex.DecodeString(value)
+			if err != nil {
+				return fmt.Errorf("webhook-secret must be hex encoded: %s", err)
+			}
+			prefs.WebhookSecret = secret
+		default:
+			return fmt.Errorf("unknown setting %q", name)
+		}
+	}
+	return nil
+}
+
 // This abstraction is needed so that we can use sort.Sort below
 type revocationCodes []revocation.Reason
 
@@ -266,6 +414,124 @@ func main() {
 			fmt.Printf("%d: %s\n", k, revocation.ReasonToString[k])
 		}
 
+	case command == "incident-create" && len(args) == 3:
+		// 1: name, 2: url, 3: renew-by
+		renewBy, err := time.Parse(time.RFC3339, args[2])
+		cmd.FailOnError(err, "renew-by argument must be an RFC 3339 timestamp")
+
+		_, logger, _, sac := setupContext(c)
+		defer logger.AuditPanic()
+
+		incident, err := sac.AddIncident(ctx, &sapb.AddIncidentRequest{
+			Name:    args[0],
+			Url:     args[1],
+			RenewBy: renewBy.UnixNano(),
+		})
+		cmd.FailOnError(err, "Couldn't create incident")
+		logger.Infof("Created incident %d (%q)", incident.Id, incident.Name)
+
+	case command == "incident-import-serials" && len(args) == 2:
+		// 1: incident ID, 2: serial file path
+		incidentID, err := strconv.ParseInt(args[0], 10, 64)
+		cmd.FailOnError(err, "Incident ID argument must be an integer")
+
+		var input io.Reader = os.Stdin
+		if args[1] != "-" {
+			f, err := os.Open(args[1])
+			cmd.FailOnError(err, "Couldn't open serial file")
+			defer f.Close()
+			input = f
+		}
+
+		_, logger, _, sac := setupContext(c)
+		defer logger.AuditPanic()
+
+		count, err := importIncidentSerials(ctx, sac, incidentID, input, incidentImportBatchSize)
+		logger.Infof("Imported %d serials for incident %d", count, incidentID)
+		cmd.FailOnError(err, "Couldn't import incident serials")
+
+	case command == "incident-set-status" && len(args) == 3:
+		// 1: incident ID, 2: enabled, 3: resolved
+		incidentID, err := strconv.ParseInt(args[0], 10, 64)
+		cmd.FailOnError(err, "Incident ID argument must be an integer")
+		enabled, err := strconv.ParseBool(args[1])
+		cmd.FailOnError(err, "enabled argument must be a boolean")
+		resolved, err := strconv.ParseBool(args[2])
+		cmd.FailOnError(err, "resolved argument must be a boolean")
+
+		_, logger, _, sac := setupContext(c)
+		defer logger.AuditPanic()
+
+		_, err = sac.SetIncidentStatus(ctx, &sapb.SetIncidentStatusRequest{
+			IncidentID: incidentID,
+			Enabled:    enabled,
+			Resolved:   resolved,
+		})
+		cmd.FailOnError(err, "Couldn't set incident status")
+		logger.Infof("Set incident %d to enabled=%t resolved=%t", incidentID, enabled, resolved)
+
+	case command == "incident-check-serial" && len(args) == 1:
+		// 1: serial
+		_, logger, _, sac := setupContext(c)
+		defer logger.AuditPanic()
+
+		incidents, err := sac.IncidentsForSerial(ctx, &sapb.Serial{Serial: args[0]})
+		cmd.FailOnError(err, "Couldn't look up incidents for serial")
+		if len(incidents.Incidents) == 0 {
+			fmt.Printf("Serial %s is not affected by any active incidents\n", args[0])
+		}
+		for _, incident := range incidents.Incidents {
+			fmt.Printf("%d: %s (%s), renew by %s\n", incident.Id, incident.Name, incident.Url, time.Unix(0, incident.RenewBy).UTC().Format(time.RFC3339))
+		}
+
+	case command == "notifications-get" && len(args) == 1:
+		// 1: registration ID
+		regID, err := strconv.ParseInt(args[0], 10, 64)
+		cmd.FailOnError(err, "Registration ID argument must be an integer")
+
+		_, logger, _, sac := setupContext(c)
+		defer logger.AuditPanic()
+
+		prefs, err := sac.GetNotificationPreferences(ctx, &sapb.RegistrationID{Id: regID})
+		if errors.Is(err, berrors.NotFound) {
+			fmt.Printf("Registration %d uses the default notification preferences\n", regID)
+			return
+		}
+		cmd.FailOnError(err, "Couldn't get notification preferences")
+		var nagTimes []string
+		for _, nt := range prefs.NagTimes {
+			nagTimes = append(nagTimes, time.Duration(nt).String())
+		}
+		fmt.Printf("email-opt-out: %t\nemail: %s\nnag-times: %s\nwebhook-url: %s\n",
+			prefs.EmailOptOut, prefs.Email, strings.Join(nagTimes, ","), prefs.WebhookURL)
+
+	case command == "notifications-set" && len(args) >= 2:
+		// 1: registration ID, 2+: settings
+		regID, err := strconv.ParseInt(args[0], 10, 64)
+		cmd.FailOnError(err, "Registration ID argument must be an integer")
+
+		_, logger, _, sac := setupContext(c)
+		defer logger.AuditPanic()
+
+		_, err = sac.GetRegistration(ctx, regID)
+		cmd.FailOnError(err, "Couldn't fetch registration")
+		prefs, err := sac.GetNotificationPreferences(ctx, &sapb.RegistrationID{Id: regID})
+		if errors.Is(err, berrors.NotFound) {
+			prefs, err = &sapb.NotificationPreferences{RegistrationID: regID}, nil
+		}
+		cmd.FailOnError(err, "Couldn't get notification preferences")
+		err = applyNotificationSettings(prefs, args[1:])
+		cmd.FailOnError(err, "Invalid notification settings")
+		_, err = sac.SetNotificationPreferences(ctx, prefs)
+		cmd.FailOnError(err, "Couldn't set notification preferences")
+		// Only log the names of the changed settings, to keep webhook secrets
+		// out of the logs.
+		var names []string
+		for _, setting := range args[1:] {
+			names = append(names, strings.SplitN(setting, "=", 2)[0])
+		}
+		logger.AuditInfof("Updated notification preferences for registration %d: %s", regID, strings.Join(names, ", "))
+
 	default:
 		usage()
 	}
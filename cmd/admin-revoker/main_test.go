@@ -9,12 +9,14 @@ import (
 	"io/ioutil"
 	"math/big"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/jmhodges/clock"
 	capb "github.com/letsencrypt/boulder/ca/proto"
 	"github.com/letsencrypt/boulder/core"
+	corepb "github.com/letsencrypt/boulder/core/proto"
 	"github.com/letsencrypt/boulder/goodkey"
 	"github.com/letsencrypt/boulder/issuance"
 	blog "github.com/letsencrypt/boulder/log"
@@ -45,7 +47,7 @@ func TestRevokeBatch(t *testing.T) {
 	if err != nil {
 		t.Fatalf("Failed to create dbMap: %s", err)
 	}
-	ssa, err := sa.NewSQLStorageAuthority(dbMap, fc, log, metrics.NoopRegisterer, 1)
+	ssa, err := sa.NewSQLStorageAuthority(dbMap, fc, log, metrics.NoopRegisterer, 1, 0, nil)
 	if err != nil {
 		t.Fatalf("Failed to create SA: %s", err)
 	}
@@ -108,3 +110,62 @@ func TestRevokeBatch(t *testing.T) {
 		test.AssertEquals(t, status.Status, core.OCSPStatusRevoked)
 	}
 }
+
+type mockIncidentSA struct {
+	mocks.StorageAuthority
+	requests []*sapb.AddIncidentSerialsRequest
+}
+
+func (sa *mockIncidentSA) AddIncidentSerials(ctx context.Context, req *sapb.AddIncidentSerialsRequest) (*corepb.Empty, error) {
+	sa.requests = append(sa.requests, req)
+	return &corepb.Empty{}, nil
+}
+
+func TestImportIncidentSerials(t *testing.T) {
+	msa := &mockIncidentSA{}
+	input := strings.NewReader("00000000000000000000000000000000000a\n\n00000000000000000000000000000000000b,1\n00000000000000000000000000000000000c\n")
+	count, err := importIncidentSerials(context.Background(), msa, 5, input, 2)
+	test.AssertNotError(t, err, "importIncidentSerials failed")
+	test.AssertEquals(t, count, 3)
+	test.AssertEquals(t, len(msa.requests), 2)
+	test.AssertEquals(t, msa.requests[0].IncidentID, int64(5))
+	test.AssertEquals(t, len(msa.requests[0].Serials), 2)
+	test.AssertEquals(t, msa.requests[0].Serials[1].RegistrationID, int64(1))
+	test.AssertEquals(t, msa.requests[1].Serials[0].Serial, "00000000000000000000000000000000000c")
+
+	msa = &mockIncidentSA{}
+	_, err = importIncidentSerials(context.Background(), msa, 5, strings.NewReader("not-a-serial\n"), 2)
+	test.AssertError(t, err, "importIncidentSerials didn't fail for an invalid serial")
+	test.AssertEquals(t, len(msa.requests), 0)
+
+	_, err = importIncidentSerials(context.Background(), msa, 5, strings.NewReader("00000000000000000000000000000000000a,x\n"), 2)
+	test.AssertError(t, err, "importIncidentSerials didn't fail for an invalid registration ID")
+}
+
+func TestApplyNotificationSettings(t *testing.T) {
+	prefs := &sapb.NotificationPreferences{RegistrationID: 1, Email: "old@example.com"}
+	err := applyNotificationSettings(prefs, []string{
+		"email-opt-out=true",
+		"nag-times=24h, 168h",
+		"webhook-url=https://example.com/hook",
+		"webhook-secret=000102030405060708090a0b0c0d0e0f",
+	})
+	test.AssertNotError(t, err, "applyNotificationSettings failed")
+	test.Assert(t, prefs.EmailOptOut, "email-opt-out wasn't applied")
+	test.AssertEquals(t, prefs.Email, "old@example.com")
+	test.AssertDeepEquals(t, prefs.NagTimes, []int64{int64(24 * time.Hour), int64(168 * time.Hour)})
+	test.AssertEquals(t, prefs.WebhookURL, "https://example.com/hook")
+	test.AssertEquals(t, len(prefs.WebhookSecret), 16)
+
+	// Empty values reset settings to their defaults.
+	err = applyNotificationSettings(prefs, []string{"email=", "nag-times=", "webhook-url="})
+	test.AssertNotError(t, err, "applyNotificationSettings failed")
+	test.AssertEquals(t, prefs.Email, "")
+	test.AssertEquals(t, len(prefs.NagTimes), 0)
+	test.AssertEquals(t, len(prefs.WebhookSecret), 0)
+
+	for _, bad := range []string{"email-opt-out", "email-opt-out=maybe", "nag-times=1d", "webhook-secret=xyz", "colour=blue"} {
+		err = applyNotificationSettings(prefs, []string{bad})
+		test.AssertError(t, err, fmt.Sprintf("applyNotificationSettings accepted %q", bad))
+	}
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/letsencrypt/boulder/revocation"
+	sapb "github.com/letsencrypt/boulder/sa/proto"
+)
+
+// accountResult is the outcome of deactivating a single account as part of a
+// batch.
+type accountResult struct {
+	ID                 int64
+	Deactivated        bool
+	AlreadyDeactivated bool   `json:",omitempty"`
+	Revoked            int    `json:",omitempty"`
+	Error              string `json:",omitempty"`
+}
+
+// accountIDColumns are the names, matched case-insensitively, which mark the
+// first row of a CSV list of account IDs as a header.
+var accountIDColumns = []string{"id", "accountID", "registrationID"}
+
+// isAccountIDHeader returns true if the given first CSV field names the
+// account ID column.
+func isAccountIDHeader(field string) bool {
+	for _, name := range accountIDColumns {
+		if strings.EqualFold(field, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseAccountIDs parses a list of registration IDs, given either as a JSON
+// array of integers or as CSV with the ID in the first column of each row. A
+// CSV header row, whose first column is named one of accountIDColumns, is
+// skipped. Duplicate IDs are removed.
+func parseAccountIDs(contents []byte) ([]int64, error) {
+	var ids []int64
+	if trimmed := bytes.TrimSpace(contents); len(trimmed) > 0 && trimmed[0] == '[' {
+		err := json.Unmarshal(trimmed, &ids)
+		if err != nil {
+			return nil, fmt.Errorf("parsing JSON account IDs: %s", err)
+		}
+	} else {
+		r := csv.NewReader(bytes.NewReader(contents))
+		r.FieldsPerRecord = -1
+		for row := 0; ; row++ {
+			record, err := r.Read()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				return nil, fmt.Errorf("parsing CSV account IDs: %s", err)
+			}
+			field := strings.TrimSpace(record[0])
+			if row == 0 && isAccountIDHeader(field) {
+				continue
+			}
+			id, err := strconv.ParseInt(field, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid account ID %q on line %d", field, row+1)
+			}
+			ids = append(ids, id)
+		}
+	}
+
+	seen := make(map[int64]bool)
+	var unique []int64
+	for _, id := range ids {
+		if id <= 0 {
+			return nil, fmt.Errorf("invalid account ID %d", id)
+		}
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique, nil
+}
+
+// deactivateAccounts deactivates each of the given accounts, waiting interval
+// between them so as not to overload the RA. If revoke is true, each
+// account's unexpired certificates are then revoked with the given reason,
+// including those of accounts which were already deactivated. It carries on
+// past failures, recording the outcome for each account in the report.
+func (a *admin) deactivateAccounts(ctx context.Context, ids []int64, revoke bool, reason revocation.Reason, interval time.Duration) error {
+	var failed int
+	for i, id := range ids {
+		// A dry run makes no requests to the RA, so needn't be limited.
+		if i > 0 && !a.dryRun {
+			a.clk.Sleep(interval)
+		}
+		result := accountResult{ID: id}
+		err := a.deactivateAccount(ctx, id)
+		if errors.Is(err, errAlreadyDeactivated) {
+			result.AlreadyDeactivated = true
+			err = nil
+		} else if err == nil {
+			result.Deactivated = true
+		}
+		if err == nil && revoke {
+			result.Revoked, err = a.revokeAccountCerts(ctx, id, reason)
+		}
+		if err != nil {
+			a.log.Errf("Failed to deactivate account %d: %s", id, err)
+			result.Error = err.Error()
+			failed++
+		}
+		a.report.AccountResults = append(a.report.AccountResults, result)
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to deactivate %d of %d accounts", failed, len(ids))
+	}
+	return nil
+}
+
+// revokeAccountCerts revokes all of the unexpired certificates issued to an
+// account, returning the number revoked.
+func (a *admin) revokeAccountCerts(ctx context.Context, regID int64, reason revocation.Reason) (int, error) {
+	serials, err := a.sac.GetSerialsByAccount(ctx, &sapb.RegistrationID{Id: regID})
+	if err != nil {
+		return 0, err
+	}
+	return a.revokeSerials(ctx, serials.Serials, reason)
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/jmhodges/clock"
+
+	"github.com/letsencrypt/boulder/core"
+	"github.com/letsencrypt/boulder/test"
+)
+
+func TestParseAccountIDs(t *testing.T) {
+	for _, tc := range []struct {
+		input string
+		ids   []int64
+	}{
+		{"[1, 2, 3]", []int64{1, 2, 3}},
+		{"  \n[3, 1, 3]\n", []int64{3, 1}},
+		{"1\n2\n3\n", []int64{1, 2, 3}},
+		{"id,reason\n1,spam\n2,phishing\n", []int64{1, 2}},
+		{" RegistrationID \n1\n", []int64{1}},
+		{" 1 ,x\n\n2\n", []int64{1, 2}},
+	} {
+		ids, err := parseAccountIDs([]byte(tc.input))
+		test.AssertNotError(t, err, "parseAccountIDs failed for "+tc.input)
+		test.AssertDeepEquals(t, ids, tc.ids)
+	}
+
+	for _, input := range []string{
+		`["1"]`,
+		"[0]",
+		"1\nabc\n",
+		// A malformed first row isn't mistaken for a header.
+		"abc\n1\n",
+		"1x,reason\n2,spam\n",
+		"1\n-5\n",
+	} {
+		_, err := parseAccountIDs([]byte(input))
+		test.AssertError(t, err, "parseAccountIDs accepted "+input)
+	}
+}
+
+func TestDeactivateAccounts(t *testing.T) {
+	a, sa, _ := newTestAdmin(t)
+	fc := a.clk.(clock.FakeClock)
+	start := fc.Now()
+
+	err := a.deactivateAccounts(context.Background(), []int64{1, 2, 3}, true, 9, time.Second)
+	test.AssertError(t, err, "deactivateAccounts didn't report the missing account")
+	test.AssertDeepEquals(t, a.report.AccountResults, []accountResult{
+		{ID: 1, Deactivated: true, Revoked: 1},
+		// Already deactivated accounts still have their certificates revoked.
+		{ID: 2, AlreadyDeactivated: true, Revoked: 1},
+		{ID: 3, Error: "no registration with ID 3"},
+	})
+	test.AssertDeepEquals(t, sa.deactivated, []int64{1})
+	test.Assert(t, sa.revoked[core.SerialToString(big.NewInt(1))], "account 1's certificate wasn't revoked")
+	test.Assert(t, sa.revoked[core.SerialToString(big.NewInt(2))], "account 2's certificate wasn't revoked")
+	test.Assert(t, !sa.revoked[core.SerialToString(big.NewInt(3))], "account 3's certificate was revoked")
+	// Deactivations are spaced out by the interval.
+	test.AssertEquals(t, fc.Now().Sub(start), 2*time.Second)
+
+	// Without a reason code, certificates are left alone.
+	a, sa, _ = newTestAdmin(t)
+	err = a.deactivateAccounts(context.Background(), []int64{1}, false, 0, time.Second)
+	test.AssertNotError(t, err, "deactivateAccounts failed")
+	test.AssertEquals(t, len(sa.revoked), 0)
+	test.AssertDeepEquals(t, a.report.AccountResults, []accountResult{{ID: 1, Deactivated: true}})
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/letsencrypt/boulder/policy"
+	sapb "github.com/letsencrypt/boulder/sa/proto"
+)
+
+// allowlistChange is a registered domain added to or removed from an
+// account's allowlist by an admin command, or the clearing of an account's
+// allowlist.
+type allowlistChange struct {
+	RegistrationID int64
+	Domain         string `json:",omitempty"`
+	Added          bool
+	Cleared        bool `json:",omitempty"`
+}
+
+// allowDomain adds a registered domain to a registration's allowlist, which
+// restricts the registration to the domains on it. The RA rejects restricted
+// registrations' requests to issue for names outside them.
+func (a *admin) allowDomain(ctx context.Context, regID int64, domain string) (err error) {
+	defer func() {
+		a.audit(auditEvent{
+			Command: "allow-domain",
+			Target:  strconv.FormatInt(regID, 10),
+			Comment: domain,
+		}, err)
+	}()
+	domain = strings.ToLower(domain)
+	registered, err := policy.RegisteredDomain(domain)
+	if err != nil {
+		return fmt.Errorf("domain %q is invalid: %s", domain, err)
+	}
+	if registered != domain {
+		return fmt.Errorf("domain %q isn't a registered domain, did you mean %q?", domain, registered)
+	}
+	_, err = a.sac.GetRegistration(ctx, regID)
+	if err != nil {
+		return err
+	}
+	if !a.dryRun {
+		_, err = a.sac.AddAccountAllowlistEntry(ctx, &sapb.AccountAllowlistEntry{
+			RegistrationID: regID,
+			Domain:         domain,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	a.report.AllowlistChanges = append(a.report.AllowlistChanges, allowlistChange{
+		RegistrationID: regID,
+		Domain:         domain,
+		Added:          true,
+	})
+	return nil
+}
+
+// disallowDomain removes a registered domain from a registration's allowlist.
+// The registration stays restricted, so removing the last one leaves it
+// unable to issue for any names.
+func (a *admin) disallowDomain(ctx context.Context, regID int64, domain string) (err error) {
+	defer func() {
+		a.audit(auditEvent{
+			Command: "disallow-domain",
+			Target:  strconv.FormatInt(regID, 10),
+			Comment: domain,
+		}, err)
+	}()
+	domain = strings.ToLower(domain)
+	if a.dryRun {
+		allowlist, err := a.sac.GetAccountAllowlist(ctx, &sapb.RegistrationID{Id: regID})
+		if err != nil {
+			return err
+		}
+		found := false
+		for _, allowed := range allowlist.Domains {
+			found = found || allowed == domain
+		}
+		if !found {
+			return fmt.Errorf("registration %d has no allowlist entry for %q", regID, domain)
+		}
+	} else {
+		_, err = a.sac.RemoveAccountAllowlistEntry(ctx, &sapb.AccountAllowlistEntry{
+			RegistrationID: regID,
+			Domain:         domain,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	a.report.AllowlistChanges = append(a.report.AllowlistChanges, allowlistChange{
+		RegistrationID: regID,
+		Domain:         domain,
+	})
+	return nil
+}
+
+// clearAllowlist removes all of a registration's allowlist entries and lifts
+// its restriction to them.
+func (a *admin) clearAllowlist(ctx context.Context, regID int64) (err error) {
+	defer func() {
+		a.audit(auditEvent{
+			Command: "clear-allowlist",
+			Target:  strconv.FormatInt(regID, 10),
+		}, err)
+	}()
+	if a.dryRun {
+		allowlist, err := a.sac.GetAccountAllowlist(ctx, &sapb.RegistrationID{Id: regID})
+		if err != nil {
+			return err
+		}
+		if !allowlist.Restricted {
+			return fmt.Errorf("registration %d isn't restricted to an allowlist", regID)
+		}
+	} else {
+		_, err = a.sac.ClearAccountAllowlist(ctx, &sapb.RegistrationID{Id: regID})
+		if err != nil {
+			return err
+		}
+	}
+	a.report.AllowlistChanges = append(a.report.AllowlistChanges, allowlistChange{
+		RegistrationID: regID,
+		Cleared:        true,
+	})
+	return nil
+}
+
+// listAllowedDomains writes the registered domains on a registration's
+// allowlist to w.
+func (a *admin) listAllowedDomains(ctx context.Context, w io.Writer, regID int64) error {
+	allowlist, err := a.sac.GetAccountAllowlist(ctx, &sapb.RegistrationID{Id: regID})
+	if err != nil {
+		return err
+	}
+	if !allowlist.Restricted {
+		fmt.Fprintf(w, "Registration %d may issue for any domain\n", regID)
+		return nil
+	}
+	if len(allowlist.Domains) == 0 {
+		fmt.Fprintf(w, "Registration %d may not issue for any domain\n", regID)
+		return nil
+	}
+	for _, domain := range allowlist.Domains {
+		fmt.Fprintln(w, domain)
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	corepb "github.com/letsencrypt/boulder/core/proto"
+	berrors "github.com/letsencrypt/boulder/errors"
+	blog "github.com/letsencrypt/boulder/log"
+	sapb "github.com/letsencrypt/boulder/sa/proto"
+	"github.com/letsencrypt/boulder/test"
+)
+
+// A registration is restricted if it has an entry in fakeSA.allowlists, even
+// an empty one.
+func (sa *fakeSA) GetAccountAllowlist(_ context.Context, req *sapb.RegistrationID) (*sapb.AccountAllowlist, error) {
+	domains, restricted := sa.allowlists[req.Id]
+	return &sapb.AccountAllowlist{Domains: domains, Restricted: restricted}, nil
+}
+
+func (sa *fakeSA) AddAccountAllowlistEntry(_ context.Context, req *sapb.AccountAllowlistEntry) (*corepb.Empty, error) {
+	for _, domain := range sa.allowlists[req.RegistrationID] {
+		if domain == req.Domain {
+			return &corepb.Empty{}, nil
+		}
+	}
+	sa.allowlists[req.RegistrationID] = append(sa.allowlists[req.RegistrationID], req.Domain)
+	return &corepb.Empty{}, nil
+}
+
+func (sa *fakeSA) RemoveAccountAllowlistEntry(_ context.Context, req *sapb.AccountAllowlistEntry) (*corepb.Empty, error) {
+	domains := sa.allowlists[req.RegistrationID]
+	for i, domain := range domains {
+		if domain == req.Domain {
+			sa.allowlists[req.RegistrationID] = append(domains[:i], domains[i+1:]...)
+			return &corepb.Empty{}, nil
+		}
+	}
+	return nil, berrors.NotFoundError("registration %d has no allowlist entry for %q", req.RegistrationID, req.Domain)
+}
+
+func (sa *fakeSA) ClearAccountAllowlist(_ context.Context, req *sapb.RegistrationID) (*corepb.Empty, error) {
+	if _, ok := sa.allowlists[req.Id]; !ok {
+		return nil, berrors.NotFoundError("registration %d isn't restricted to an allowlist", req.Id)
+	}
+	delete(sa.allowlists, req.Id)
+	return &corepb.Empty{}, nil
+}
+
+func TestAllowlist(t *testing.T) {
+	a, sa, log := newTestAdmin(t)
+	mockLog := log.(*blog.Mock)
+	ctx := context.Background()
+
+	var out bytes.Buffer
+	err := a.listAllowedDomains(ctx, &out, 1)
+	test.AssertNotError(t, err, "listAllowedDomains failed")
+	test.AssertEquals(t, out.String(), "Registration 1 may issue for any domain\n")
+
+	err = a.allowDomain(ctx, 1, "Example.com")
+	test.AssertNotError(t, err, "allowDomain failed")
+	test.AssertDeepEquals(t, sa.allowlists[1], []string{"example.com"})
+	test.AssertEquals(t, len(mockLog.GetAllMatching(`"Command":"allow-domain","Target":"1","Comment":"example.com"`)), 1)
+
+	err = a.allowDomain(ctx, 1, "www.example.com")
+	test.AssertError(t, err, "allowDomain accepted a subdomain")
+	err = a.allowDomain(ctx, 1, "co.uk")
+	test.AssertError(t, err, "allowDomain accepted a public suffix")
+	err = a.allowDomain(ctx, 3, "example.com")
+	test.AssertError(t, err, "allowDomain accepted an unknown registration")
+
+	// A dry run validates the domain without adding it.
+	a.dryRun = true
+	err = a.allowDomain(ctx, 1, "example.co.uk")
+	test.AssertNotError(t, err, "allowDomain failed")
+	test.AssertEquals(t, len(sa.allowlists[1]), 1)
+	err = a.disallowDomain(ctx, 1, "example.com")
+	test.AssertNotError(t, err, "disallowDomain failed")
+	test.AssertEquals(t, len(sa.allowlists[1]), 1)
+	err = a.disallowDomain(ctx, 1, "example.co.uk")
+	test.AssertError(t, err, "disallowDomain removed a missing entry in a dry run")
+	a.dryRun = false
+
+	out.Reset()
+	err = a.listAllowedDomains(ctx, &out, 1)
+	test.AssertNotError(t, err, "listAllowedDomains failed")
+	test.AssertEquals(t, out.String(), "example.com\n")
+
+	err = a.disallowDomain(ctx, 1, "example.com")
+	test.AssertNotError(t, err, "disallowDomain failed")
+	test.AssertEquals(t, len(sa.allowlists[1]), 0)
+	err = a.disallowDomain(ctx, 1, "example.com")
+	test.AssertError(t, err, "disallowDomain removed a missing entry")
+
+	// Removing the last entry leaves the registration restricted, until its
+	// allowlist is cleared.
+	out.Reset()
+	err = a.listAllowedDomains(ctx, &out, 1)
+	test.AssertNotError(t, err, "listAllowedDomains failed")
+	test.AssertEquals(t, out.String(), "Registration 1 may not issue for any domain\n")
+
+	a.dryRun = true
+	err = a.clearAllowlist(ctx, 1)
+	test.AssertNotError(t, err, "clearAllowlist failed")
+	_, restricted := sa.allowlists[1]
+	test.Assert(t, restricted, "clearAllowlist lifted the restriction in a dry run")
+	a.dryRun = false
+	err = a.clearAllowlist(ctx, 1)
+	test.AssertNotError(t, err, "clearAllowlist failed")
+	_, restricted = sa.allowlists[1]
+	test.Assert(t, !restricted, "clearAllowlist didn't lift the restriction")
+	err = a.clearAllowlist(ctx, 1)
+	test.AssertError(t, err, "clearAllowlist succeeded for an unrestricted registration")
+	test.AssertEquals(t, len(mockLog.GetAllMatching(`"Command":"clear-allowlist","Target":"1"`)), 2)
+
+	test.AssertEquals(t, len(a.report.AllowlistChanges), 6)
+	test.AssertDeepEquals(t, a.report.accounts(), []int64{1})
+}
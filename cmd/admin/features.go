@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/letsencrypt/boulder/features"
+	sapb "github.com/letsencrypt/boulder/sa/proto"
+)
+
+// featureOverrideChange is a per-account feature override set or cleared by
+// an admin command. Enabled is nil if the override was cleared.
+type featureOverrideChange struct {
+	RegistrationID int64
+	Feature        string
+	Enabled        *bool `json:",omitempty"`
+}
+
+// setFeatureOverride enables or disables a feature for a single registration,
+// regardless of the feature's setting in the services' configs. Only features
+// which honour per-account overrides can be set.
+func (a *admin) setFeatureOverride(ctx context.Context, regID int64, feature string, enabled bool) (err error) {
+	defer func() {
+		a.audit(auditEvent{
+			Command: "set-feature",
+			Target:  strconv.FormatInt(regID, 10),
+			Comment: fmt.Sprintf("%s=%t", feature, enabled),
+		}, err)
+	}()
+	if !features.Exists(feature) {
+		return fmt.Errorf("feature %q doesn't exist", feature)
+	}
+	if !features.Overridable(feature) {
+		return fmt.Errorf("feature %q doesn't honour per-account overrides", feature)
+	}
+	_, err = a.sac.GetRegistration(ctx, regID)
+	if err != nil {
+		return err
+	}
+	if !a.dryRun {
+		_, err = a.sac.SetFeatureOverride(ctx, &sapb.FeatureOverrideRequest{
+			RegistrationID: regID,
+			Feature:        feature,
+			Enabled:        enabled,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	a.report.FeatureOverrides = append(a.report.FeatureOverrides, featureOverrideChange{
+		RegistrationID: regID,
+		Feature:        feature,
+		Enabled:        &enabled,
+	})
+	return nil
+}
+
+// clearFeatureOverride removes a registration's override for a feature, so
+// that the feature's setting in the services' configs applies to it again.
+func (a *admin) clearFeatureOverride(ctx context.Context, regID int64, feature string) (err error) {
+	defer func() {
+		a.audit(auditEvent{
+			Command: "clear-feature",
+			Target:  strconv.FormatInt(regID, 10),
+			Comment: feature,
+		}, err)
+	}()
+	if a.dryRun {
+		overrides, err := a.sac.GetFeatureOverrides(ctx, &sapb.RegistrationID{Id: regID})
+		if err != nil {
+			return err
+		}
+		found := false
+		for _, override := range overrides.Overrides {
+			found = found || override.Feature == feature
+		}
+		if !found {
+			return fmt.Errorf("registration %d has no override for feature %q", regID, feature)
+		}
+	} else {
+		_, err = a.sac.ClearFeatureOverride(ctx, &sapb.FeatureOverrideRequest{
+			RegistrationID: regID,
+			Feature:        feature,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	a.report.FeatureOverrides = append(a.report.FeatureOverrides, featureOverrideChange{
+		RegistrationID: regID,
+		Feature:        feature,
+	})
+	return nil
+}
+
+// listFeatureOverrides writes a registration's feature overrides to w.
+func (a *admin) listFeatureOverrides(ctx context.Context, w io.Writer, regID int64) error {
+	overrides, err := a.sac.GetFeatureOverrides(ctx, &sapb.RegistrationID{Id: regID})
+	if err != nil {
+		return err
+	}
+	for _, override := range overrides.Overrides {
+		fmt.Fprintf(w, "%s\t%t\n", override.Feature, override.Enabled)
+	}
+	return nil
+}
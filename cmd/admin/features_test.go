@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	corepb "github.com/letsencrypt/boulder/core/proto"
+	berrors "github.com/letsencrypt/boulder/errors"
+	blog "github.com/letsencrypt/boulder/log"
+	sapb "github.com/letsencrypt/boulder/sa/proto"
+	"github.com/letsencrypt/boulder/test"
+)
+
+func (sa *fakeSA) GetFeatureOverrides(_ context.Context, req *sapb.RegistrationID) (*sapb.FeatureOverrides, error) {
+	resp := &sapb.FeatureOverrides{}
+	for feature, enabled := range sa.overrides[req.Id] {
+		resp.Overrides = append(resp.Overrides, &sapb.FeatureOverride{Feature: feature, Enabled: enabled})
+	}
+	return resp, nil
+}
+
+func (sa *fakeSA) SetFeatureOverride(_ context.Context, req *sapb.FeatureOverrideRequest) (*corepb.Empty, error) {
+	if sa.overrides[req.RegistrationID] == nil {
+		sa.overrides[req.RegistrationID] = make(map[string]bool)
+	}
+	sa.overrides[req.RegistrationID][req.Feature] = req.Enabled
+	return &corepb.Empty{}, nil
+}
+
+func (sa *fakeSA) ClearFeatureOverride(_ context.Context, req *sapb.FeatureOverrideRequest) (*corepb.Empty, error) {
+	if _, ok := sa.overrides[req.RegistrationID][req.Feature]; !ok {
+		return nil, berrors.NotFoundError("registration %d has no override for feature %q", req.RegistrationID, req.Feature)
+	}
+	delete(sa.overrides[req.RegistrationID], req.Feature)
+	return &corepb.Empty{}, nil
+}
+
+func TestFeatureOverrides(t *testing.T) {
+	a, sa, log := newTestAdmin(t)
+	mockLog := log.(*blog.Mock)
+	ctx := context.Background()
+
+	err := a.setFeatureOverride(ctx, 1, "EnforceMultiVA", true)
+	test.AssertNotError(t, err, "setFeatureOverride failed")
+	test.AssertDeepEquals(t, sa.overrides[1], map[string]bool{"EnforceMultiVA": true})
+	test.AssertEquals(t, len(mockLog.GetAllMatching(`"Command":"set-feature","Target":"1","Comment":"EnforceMultiVA=true"`)), 1)
+
+	var out bytes.Buffer
+	err = a.listFeatureOverrides(ctx, &out, 1)
+	test.AssertNotError(t, err, "listFeatureOverrides failed")
+	test.AssertEquals(t, out.String(), "EnforceMultiVA\ttrue\n")
+
+	err = a.setFeatureOverride(ctx, 1, "NoSuchFeature", true)
+	test.AssertError(t, err, "setFeatureOverride accepted an unknown feature")
+	err = a.setFeatureOverride(ctx, 1, "CAAAccountURI", true)
+	test.AssertError(t, err, "setFeatureOverride accepted a feature which doesn't honour overrides")
+	err = a.setFeatureOverride(ctx, 3, "EnforceMultiVA", true)
+	test.AssertError(t, err, "setFeatureOverride accepted an unknown registration")
+
+	// A dry run checks the override exists without clearing it.
+	a.dryRun = true
+	err = a.clearFeatureOverride(ctx, 1, "EnforceMultiVA")
+	test.AssertNotError(t, err, "clearFeatureOverride failed")
+	test.AssertEquals(t, len(sa.overrides[1]), 1)
+	err = a.clearFeatureOverride(ctx, 1, "CAAAccountURI")
+	test.AssertError(t, err, "clearFeatureOverride cleared a missing override in a dry run")
+	a.dryRun = false
+
+	err = a.clearFeatureOverride(ctx, 1, "EnforceMultiVA")
+	test.AssertNotError(t, err, "clearFeatureOverride failed")
+	test.AssertEquals(t, len(sa.overrides[1]), 0)
+	err = a.clearFeatureOverride(ctx, 1, "EnforceMultiVA")
+	test.AssertError(t, err, "clearFeatureOverride cleared a missing override")
+
+	test.AssertEquals(t, len(a.report.FeatureOverrides), 3)
+	test.AssertDeepEquals(t, a.report.accounts(), []int64{1})
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	corepb "github.com/letsencrypt/boulder/core/proto"
+	"github.com/letsencrypt/boulder/policy"
+	policypb "github.com/letsencrypt/boulder/policy/proto"
+	sapb "github.com/letsencrypt/boulder/sa/proto"
+)
+
+// hostnamePolicyRefresher is a single RA or CA instance which can be asked to
+// load the latest hostname policy from the SA.
+type hostnamePolicyRefresher struct {
+	address string
+	client  policypb.HostnamePolicyRefresherClient
+}
+
+// updateHostnamePolicy stores the hostname policy file's contents in the SA as
+// a new version of the policy, then asks every configured RA and CA instance
+// to load it. Any others load it at their next refresh. It returns the new
+// version, or 0 in a dry run.
+func (a *admin) updateHostnamePolicy(ctx context.Context, contents []byte, comment string) (version int64, err error) {
+	defer func() {
+		a.audit(auditEvent{
+			Command: "update-hostname-policy",
+			Target:  strconv.FormatInt(version, 10),
+			Comment: comment,
+		}, err)
+	}()
+	newPolicy, err := policy.ParseHostnamePolicy(contents)
+	if err != nil {
+		return 0, err
+	}
+	if a.dryRun {
+		return 0, nil
+	}
+	newPolicy.CreatedBy = a.user
+	newPolicy.Comment = comment
+	stored, err := a.sac.AddHostnamePolicy(ctx, newPolicy)
+	if err != nil {
+		return 0, err
+	}
+	version = stored.Version
+
+	var failures []string
+	for _, r := range a.refreshers {
+		resp, err := r.client.RefreshHostnamePolicy(ctx, &corepb.Empty{})
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %s", r.address, err))
+			continue
+		}
+		if resp.Version != version {
+			a.log.Infof("%s is using hostname policy version %d, not the new version %d", r.address, resp.Version, version)
+		}
+	}
+	if len(failures) > 0 {
+		return version, fmt.Errorf("stored hostname policy version %d, but %d of %d RAs and CAs couldn't load it: %s",
+			version, len(failures), len(a.refreshers), strings.Join(failures, "; "))
+	}
+	return version, nil
+}
+
+// showHostnamePolicy writes a summary of the latest version of the hostname
+// policy stored in the SA to w.
+func (a *admin) showHostnamePolicy(ctx context.Context, w io.Writer) error {
+	p, err := a.sac.GetHostnamePolicy(ctx, &sapb.GetHostnamePolicyRequest{})
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "Version %d, created %s by %s: %s\n",
+		p.Version, time.Unix(0, p.Created).UTC().Format(time.RFC3339), p.CreatedBy, p.Comment)
+	fmt.Fprintf(w, "  ExactBlockedNames: %d\n", len(p.ExactBlockedNames))
+	fmt.Fprintf(w, "  HighRiskBlockedNames: %d\n", len(p.HighRiskBlockedNames))
+	fmt.Fprintf(w, "  AdminBlockedNames: %d\n", len(p.AdminBlockedNames))
+	return nil
+}
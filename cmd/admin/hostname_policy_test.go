@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc"
+
+	corepb "github.com/letsencrypt/boulder/core/proto"
+	policypb "github.com/letsencrypt/boulder/policy/proto"
+	sapb "github.com/letsencrypt/boulder/sa/proto"
+	"github.com/letsencrypt/boulder/test"
+)
+
+func (sa *fakeSA) AddHostnamePolicy(_ context.Context, req *sapb.HostnamePolicy) (*sapb.HostnamePolicy, error) {
+	sa.policies = append(sa.policies, req)
+	req.Version = int64(len(sa.policies))
+	return req, nil
+}
+
+// fakeRefresher is an RA or CA instance which loads the latest hostname policy
+// stored in the SA, unless it has an error.
+type fakeRefresher struct {
+	sa        *fakeSA
+	err       error
+	refreshes int
+}
+
+func (r *fakeRefresher) RefreshHostnamePolicy(_ context.Context, _ *corepb.Empty, _ ...grpc.CallOption) (*policypb.HostnamePolicyVersion, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	r.refreshes++
+	return &policypb.HostnamePolicyVersion{Version: int64(len(r.sa.policies))}, nil
+}
+
+func TestUpdateHostnamePolicy(t *testing.T) {
+	a, sa, _ := newTestAdmin(t)
+	ra := &fakeRefresher{sa: sa}
+	ca := &fakeRefresher{sa: sa}
+	a.refreshers = []hostnamePolicyRefresher{
+		{address: "ra1.boulder:9094", client: ra},
+		{address: "ca1.boulder:9093", client: ca},
+	}
+	ctx := context.Background()
+	policyFile := []byte(`
+HighRiskBlockedNames:
+  - example.org
+ExactBlockedNames:
+  - highvalue.example.net
+`)
+
+	a.dryRun = true
+	version, err := a.updateHostnamePolicy(ctx, policyFile, "initial policy")
+	test.AssertNotError(t, err, "updateHostnamePolicy failed")
+	test.AssertEquals(t, version, int64(0))
+	test.AssertEquals(t, len(sa.policies), 0)
+	test.AssertEquals(t, ra.refreshes, 0)
+	a.dryRun = false
+
+	version, err = a.updateHostnamePolicy(ctx, policyFile, "initial policy")
+	test.AssertNotError(t, err, "updateHostnamePolicy failed")
+	test.AssertEquals(t, version, int64(1))
+	test.AssertEquals(t, len(sa.policies), 1)
+	test.AssertDeepEquals(t, sa.policies[0].HighRiskBlockedNames, []string{"example.org"})
+	test.AssertEquals(t, sa.policies[0].CreatedBy, "operator")
+	test.AssertEquals(t, sa.policies[0].Comment, "initial policy")
+	// Every RA and CA is asked to load the new version.
+	test.AssertEquals(t, ra.refreshes, 1)
+	test.AssertEquals(t, ca.refreshes, 1)
+
+	// An instance which can't load the new version is reported, after the
+	// others have been asked to.
+	ra.err = errors.New("SA unavailable")
+	version, err = a.updateHostnamePolicy(ctx, policyFile, "second policy")
+	test.AssertError(t, err, "updateHostnamePolicy didn't report the RA's error")
+	test.Assert(t, strings.Contains(err.Error(), "ra1.boulder:9094: SA unavailable"), err.Error())
+	test.AssertEquals(t, version, int64(2))
+	test.AssertEquals(t, ca.refreshes, 2)
+	ra.err = nil
+
+	_, err = a.updateHostnamePolicy(ctx, []byte("HighRiskBlockedNames: [example.org]"), "no exact names")
+	test.AssertError(t, err, "updateHostnamePolicy accepted a policy without exact blocked names")
+	test.AssertEquals(t, len(sa.policies), 2)
+}
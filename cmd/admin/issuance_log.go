@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+
+	berrors "github.com/letsencrypt/boulder/errors"
+	"github.com/letsencrypt/boulder/issuancelog"
+	sapb "github.com/letsencrypt/boulder/sa/proto"
+)
+
+// verifyIssuanceLog checks that a CA's issuance log is an unbroken hash chain
+// and cross-checks each entry against the certificate the SA stored for it
+// and the SA's copy of the entry, writing any discrepancies and a summary to
+// w. Entries without a stored certificate or SA copy are reported but aren't
+// errors, since the CA appends each entry before signing and storing the
+// certificate, and copies it to the SA on a best effort basis.
+func (a *admin) verifyIssuanceLog(ctx context.Context, w io.Writer, r io.Reader) error {
+	var entries, mismatched, missingCerts, missingCopies int
+	head, err := issuancelog.Verify(r, func(e issuancelog.Entry) error {
+		entries++
+		namesHash, err := a.storedNamesHash(ctx, e)
+		if errors.Is(err, berrors.NotFound) {
+			missingCerts++
+			fmt.Fprintf(w, "Entry %d: no %s with serial %s\n", e.Seq, e.Type, e.Serial)
+		} else if err != nil {
+			return fmt.Errorf("entry %d: %s", e.Seq, err)
+		} else if namesHash != e.NamesHash {
+			mismatched++
+			fmt.Fprintf(w, "Entry %d: names of %s %s don't match the entry\n", e.Seq, e.Type, e.Serial)
+		}
+
+		stored, err := a.sac.GetIssuanceLogEntry(ctx, &sapb.GetIssuanceLogEntryRequest{ChainID: e.ChainID, Seq: e.Seq})
+		if errors.Is(err, berrors.NotFound) {
+			missingCopies++
+			fmt.Fprintf(w, "Entry %d: not stored in the SA\n", e.Seq)
+		} else if err != nil {
+			return fmt.Errorf("entry %d: %s", e.Seq, err)
+		} else if saEntry := issuancelog.PBToEntry(stored); saEntry.Hash != e.Hash || saEntry.Serial != e.Serial {
+			mismatched++
+			fmt.Fprintf(w, "Entry %d: SA's copy doesn't match the entry\n", e.Seq)
+		}
+		return nil
+	})
+	fmt.Fprintf(w, "Verified %d entries of chain %q, head entry %d has hash %s\n", entries, head.ChainID, head.Seq, head.Hash)
+	fmt.Fprintf(w, "%d mismatched, %d without a stored certificate, %d not stored in the SA\n", mismatched, missingCerts, missingCopies)
+	if err != nil {
+		return fmt.Errorf("issuance log is broken after entry %d: %s", head.Seq, err)
+	}
+	if mismatched != 0 {
+		return fmt.Errorf("%d issuance log entries don't match the SA", mismatched)
+	}
+	return nil
+}
+
+// storedNamesHash returns the hash of the names of the certificate or
+// precertificate an issuance log entry records.
+func (a *admin) storedNamesHash(ctx context.Context, e issuancelog.Entry) (string, error) {
+	var der []byte
+	switch e.Type {
+	case issuancelog.TypePrecertificate:
+		precert, err := a.sac.GetPrecertificate(ctx, &sapb.Serial{Serial: e.Serial})
+		if err != nil {
+			return "", err
+		}
+		der = precert.Der
+	case issuancelog.TypeCertificate:
+		cert, err := a.sac.GetCertificate(ctx, e.Serial)
+		if err != nil {
+			return "", err
+		}
+		der = cert.DER
+	default:
+		return "", fmt.Errorf("unknown entry type %q", e.Type)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return "", err
+	}
+	return issuancelog.NamesHash(append(cert.DNSNames, cert.EmailAddresses...)), nil
+}
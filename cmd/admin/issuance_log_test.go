@@ -0,0 +1,115 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jmhodges/clock"
+
+	"github.com/letsencrypt/boulder/core"
+	corepb "github.com/letsencrypt/boulder/core/proto"
+	berrors "github.com/letsencrypt/boulder/errors"
+	"github.com/letsencrypt/boulder/issuancelog"
+	blog "github.com/letsencrypt/boulder/log"
+	sapb "github.com/letsencrypt/boulder/sa/proto"
+	"github.com/letsencrypt/boulder/test"
+)
+
+func (sa *fakeSA) GetPrecertificate(_ context.Context, req *sapb.Serial) (*corepb.Certificate, error) {
+	der, ok := sa.precerts[req.Serial]
+	if !ok {
+		return nil, berrors.NotFoundError("no precertificate with serial %s", req.Serial)
+	}
+	return &corepb.Certificate{Serial: req.Serial, Der: der}, nil
+}
+
+func (sa *fakeSA) AddIssuanceLogEntry(_ context.Context, req *sapb.IssuanceLogEntry) (*corepb.Empty, error) {
+	sa.issuanceLogEntries = append(sa.issuanceLogEntries, req)
+	return &corepb.Empty{}, nil
+}
+
+func (sa *fakeSA) GetIssuanceLogEntry(_ context.Context, req *sapb.GetIssuanceLogEntryRequest) (*sapb.IssuanceLogEntry, error) {
+	for _, e := range sa.issuanceLogEntries {
+		if e.ChainID == req.ChainID && e.Seq == req.Seq {
+			return e, nil
+		}
+	}
+	return nil, berrors.NotFoundError("no entry %d in issuance log chain %q", req.Seq, req.ChainID)
+}
+
+func TestVerifyIssuanceLog(t *testing.T) {
+	a, sa, _ := newTestAdmin(t)
+	ctx := context.Background()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	test.AssertNotError(t, err, "generating key")
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(10),
+		DNSNames:     []string{"example.com"},
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	test.AssertNotError(t, err, "creating certificate")
+	serial := core.SerialToString(template.SerialNumber)
+	sa.precerts = map[string][]byte{serial: der}
+	sa.certs[serial] = core.Certificate{Serial: serial, DER: der}
+
+	dir, err := ioutil.TempDir("", "issuance-log")
+	test.AssertNotError(t, err, "creating temp dir")
+	defer os.RemoveAll(dir)
+	logFile := filepath.Join(dir, "issuance-log.jsonl")
+	issuanceLog, err := issuancelog.Open(logFile, "ca-a", sa, clock.NewFake(), blog.NewMock())
+	test.AssertNotError(t, err, "opening issuance log")
+	namesHash := issuancelog.NamesHash([]string{"example.com"})
+	for _, entryType := range []string{issuancelog.TypePrecertificate, issuancelog.TypeCertificate} {
+		_, err = issuanceLog.Append(ctx, issuancelog.Entry{Type: entryType, Serial: serial, NamesHash: namesHash})
+		test.AssertNotError(t, err, "appending entry")
+	}
+	test.AssertNotError(t, issuanceLog.Close(), "closing issuance log")
+	contents, err := ioutil.ReadFile(logFile)
+	test.AssertNotError(t, err, "reading issuance log")
+
+	var out bytes.Buffer
+	err = a.verifyIssuanceLog(ctx, &out, bytes.NewReader(contents))
+	test.AssertNotError(t, err, "verifyIssuanceLog failed")
+	test.AssertContains(t, out.String(), `Verified 2 entries of chain "ca-a", head entry 2`)
+	test.AssertContains(t, out.String(), "0 mismatched, 0 without a stored certificate, 0 not stored in the SA")
+
+	// Entries without a stored certificate or SA copy are reported, but aren't
+	// errors.
+	delete(sa.certs, serial)
+	sa.issuanceLogEntries = sa.issuanceLogEntries[:1]
+	out.Reset()
+	err = a.verifyIssuanceLog(ctx, &out, bytes.NewReader(contents))
+	test.AssertNotError(t, err, "verifyIssuanceLog failed")
+	test.AssertContains(t, out.String(), "Entry 2: no certificate with serial "+serial)
+	test.AssertContains(t, out.String(), "Entry 2: not stored in the SA")
+	test.AssertContains(t, out.String(), "0 mismatched, 1 without a stored certificate, 1 not stored in the SA")
+
+	// A precertificate whose names don't match its entry is an error.
+	template.DNSNames = []string{"example.net"}
+	sa.precerts[serial], err = x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	test.AssertNotError(t, err, "creating certificate")
+	out.Reset()
+	err = a.verifyIssuanceLog(ctx, &out, bytes.NewReader(contents))
+	test.AssertError(t, err, "verifyIssuanceLog accepted mismatched names")
+	test.AssertContains(t, out.String(), "Entry 1: names of precertificate "+serial+" don't match the entry")
+
+	// So is a broken chain.
+	tampered := strings.Replace(string(contents), `"seq":1`, `"seq":3`, 1)
+	out.Reset()
+	err = a.verifyIssuanceLog(ctx, &out, strings.NewReader(tampered))
+	test.AssertError(t, err, "verifyIssuanceLog accepted a broken chain")
+	test.AssertContains(t, err.Error(), "broken after entry 0")
+}
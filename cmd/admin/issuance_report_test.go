@@ -0,0 +1,61 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	sapb "github.com/letsencrypt/boulder/sa/proto"
+	"github.com/letsencrypt/boulder/test"
+)
+
+func (sa *fakeSA) GetIssuanceCounts(_ context.Context, req *sapb.IssuanceCountsRequest) (*sapb.IssuanceCounts, error) {
+	sa.issuanceCountRequests = append(sa.issuanceCountRequests, req)
+	return &sapb.IssuanceCounts{Counts: []*sapb.IssuanceCount{
+		{Day: "2021-02-11", KeyAlgorithm: "ECDSA P-256", Count: 3},
+		{Day: "2021-02-11", KeyAlgorithm: "RSA 2048", Count: 5},
+	}}, nil
+}
+
+func TestIssuanceReport(t *testing.T) {
+	a, sa, _ := newTestAdmin(t)
+	ctx := context.Background()
+	since := time.Date(2021, 2, 11, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2021, 2, 12, 0, 0, 0, 0, time.UTC)
+
+	var out bytes.Buffer
+	groupBy := parseGroupBy("day, keyAlgorithm")
+	err := a.issuanceReport(ctx, &out, since, until, groupBy, "csv")
+	test.AssertNotError(t, err, "issuanceReport failed")
+	test.AssertEquals(t, out.String(), "day,keyAlgorithm,count\n2021-02-11,ECDSA P-256,3\n2021-02-11,RSA 2048,5\n")
+
+	// The end date is inclusive.
+	test.AssertEquals(t, len(sa.issuanceCountRequests), 1)
+	test.AssertDeepEquals(t, sa.issuanceCountRequests[0], &sapb.IssuanceCountsRequest{
+		Range:   &sapb.Range{Earliest: since.UnixNano(), Latest: until.Add(24 * time.Hour).UnixNano()},
+		GroupBy: []string{"day", "keyAlgorithm"},
+	})
+
+	out.Reset()
+	err = a.issuanceReport(ctx, &out, since, until, parseGroupBy("keyAlgorithm"), "json")
+	test.AssertNotError(t, err, "issuanceReport failed")
+	test.AssertEquals(t, out.String(), `[
+  {
+    "count": 3,
+    "keyAlgorithm": "ECDSA P-256"
+  },
+  {
+    "count": 5,
+    "keyAlgorithm": "RSA 2048"
+  }
+]
+`)
+
+	err = a.issuanceReport(ctx, &out, since, until, nil, "xml")
+	test.AssertError(t, err, "issuanceReport accepted an unknown format")
+	err = a.issuanceReport(ctx, &out, until, since, nil, "csv")
+	test.AssertError(t, err, "issuanceReport accepted an end date before its start")
+	test.AssertEquals(t, len(sa.issuanceCountRequests), 2)
+	test.AssertEquals(t, len(parseGroupBy("")), 0)
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+
+	"golang.org/x/crypto/ocsp"
+
+	"github.com/letsencrypt/boulder/core"
+)
+
+// loadPrivateKey parses a PEM encoded PKCS #1, SEC 1 or PKCS #8 private key.
+func loadPrivateKey(pemBytes []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(pemBytes)
+	if block == nil {
+		return nil, errors.New("no PEM data found")
+	}
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		return x509.ParseECPrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			return nil, fmt.Errorf("unsupported private key type %T", key)
+		}
+		return signer, nil
+	default:
+		return nil, fmt.Errorf("unexpected PEM block type %q", block.Type)
+	}
+}
+
+// proveKeyPossession signs a random value with key and checks the signature
+// against its public key, so that a key hash is only blocked and revoked on
+// the strength of a private key which really corresponds to it.
+func proveKeyPossession(key crypto.Signer) error {
+	if rsaKey, ok := key.(*rsa.PrivateKey); ok {
+		err := rsaKey.Validate()
+		if err != nil {
+			return err
+		}
+	}
+	challenge := make([]byte, 32)
+	_, err := rand.Read(challenge)
+	if err != nil {
+		return err
+	}
+	digest := sha256.Sum256(challenge)
+	sig, err := key.Sign(rand.Reader, digest[:], crypto.SHA256)
+	if err != nil {
+		return fmt.Errorf("signing with private key: %s", err)
+	}
+	switch pub := key.Public().(type) {
+	case *rsa.PublicKey:
+		err = rsa.VerifyPKCS1v15(pub, crypto.SHA256, digest[:], sig)
+	case *ecdsa.PublicKey:
+		var ecdsaSig struct{ R, S *big.Int }
+		_, err = asn1.Unmarshal(sig, &ecdsaSig)
+		if err == nil && !ecdsa.Verify(pub, digest[:], ecdsaSig.R, ecdsaSig.S) {
+			err = errors.New("signature didn't verify")
+		}
+	default:
+		err = fmt.Errorf("unsupported public key type %T", pub)
+	}
+	if err != nil {
+		return fmt.Errorf("private key doesn't match its public key: %s", err)
+	}
+	return nil
+}
+
+// readKeyHashes reads hex encoded SPKI hashes from r, one per line. Blank
+// lines and lines starting with "#" are ignored.
+func readKeyHashes(r io.Reader) ([][]byte, error) {
+	var hashes [][]byte
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		hash, err := parseKeyHash(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %q: %s", line, err)
+		}
+		hashes = append(hashes, hash)
+	}
+	return hashes, scanner.Err()
+}
+
+// keyHashForPrivateKey proves possession of the PEM encoded private key and
+// returns the SHA-256 hash of its SubjectPublicKeyInfo.
+func keyHashForPrivateKey(pemBytes []byte) ([]byte, error) {
+	key, err := loadPrivateKey(pemBytes)
+	if err != nil {
+		return nil, err
+	}
+	err = proveKeyPossession(key)
+	if err != nil {
+		return nil, err
+	}
+	digest, err := core.KeyDigest(key.Public())
+	if err != nil {
+		return nil, err
+	}
+	return digest[:], nil
+}
+
+// revokeCompromisedKeys blocks each of the given keys, so nothing more can be
+// issued for them, and then revokes all unexpired certificates using them
+// with reason keyCompromise. It carries on past failures, and returns the
+// total number of certificates revoked.
+func (a *admin) revokeCompromisedKeys(ctx context.Context, keyHashes [][]byte, comment string) (int, error) {
+	var revoked, failed int
+	for _, keyHash := range keyHashes {
+		err := a.blockKey(ctx, keyHash, comment)
+		if err != nil {
+			a.log.Errf("Failed to block key %x: %s", keyHash, err)
+			failed++
+			continue
+		}
+		count, err := a.revokeByKey(ctx, keyHash, ocsp.KeyCompromise)
+		revoked += count
+		if err != nil {
+			a.log.Errf("Failed to revoke all certificates with key %x: %s", keyHash, err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		return revoked, fmt.Errorf("failed to block or revoke certificates for %d of %d keys", failed, len(keyHashes))
+	}
+	return revoked, nil
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"time"
+
+	sapb "github.com/letsencrypt/boulder/sa/proto"
+)
+
+// listKeyRollovers writes to w the key rollovers of either a registration or
+// a key, newest first. Only rollovers made while the SA's KeyRolloverHistory
+// feature was enabled are recorded.
+func (a *admin) listKeyRollovers(ctx context.Context, w io.Writer, req *sapb.GetKeyRolloversRequest) error {
+	rollovers, err := a.sac.GetKeyRollovers(ctx, req)
+	if err != nil {
+		return err
+	}
+	if len(rollovers.Rollovers) == 0 {
+		fmt.Fprintln(w, "No key rollovers found")
+		return nil
+	}
+	for _, r := range rollovers.Rollovers {
+		fmt.Fprintf(w, "%s registration %d: %s -> %s\n",
+			time.Unix(0, r.RolledOver).UTC().Format(time.RFC3339),
+			r.RegistrationID,
+			hex.EncodeToString(r.OldKeyHash),
+			hex.EncodeToString(r.NewKeyHash))
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	sapb "github.com/letsencrypt/boulder/sa/proto"
+	"github.com/letsencrypt/boulder/test"
+)
+
+func (sa *fakeSA) GetKeyRollovers(_ context.Context, req *sapb.GetKeyRolloversRequest) (*sapb.KeyRollovers, error) {
+	var matching []*sapb.KeyRollover
+	for _, r := range sa.keyRollovers {
+		if r.RegistrationID == req.RegistrationID ||
+			(req.KeyHash != nil && (bytes.Equal(r.OldKeyHash, req.KeyHash) || bytes.Equal(r.NewKeyHash, req.KeyHash))) {
+			matching = append(matching, r)
+		}
+	}
+	return &sapb.KeyRollovers{Rollovers: matching}, nil
+}
+
+func TestListKeyRollovers(t *testing.T) {
+	a, sa, _ := newTestAdmin(t)
+	ctx := context.Background()
+
+	var out bytes.Buffer
+	err := a.listKeyRollovers(ctx, &out, &sapb.GetKeyRolloversRequest{RegistrationID: 1})
+	test.AssertNotError(t, err, "listKeyRollovers failed")
+	test.AssertEquals(t, out.String(), "No key rollovers found\n")
+
+	oldHash := bytes.Repeat([]byte{0xaa}, 32)
+	newHash := bytes.Repeat([]byte{0xbb}, 32)
+	sa.keyRollovers = []*sapb.KeyRollover{{
+		RegistrationID: 1,
+		OldKeyHash:     oldHash,
+		NewKeyHash:     newHash,
+		RolledOver:     time.Date(2021, 2, 13, 12, 0, 0, 0, time.UTC).UnixNano(),
+	}}
+	expected := "2021-02-13T12:00:00Z registration 1: " +
+		"aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa -> " +
+		"bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb\n"
+
+	out.Reset()
+	err = a.listKeyRollovers(ctx, &out, &sapb.GetKeyRolloversRequest{RegistrationID: 1})
+	test.AssertNotError(t, err, "listKeyRollovers failed")
+	test.AssertEquals(t, out.String(), expected)
+
+	out.Reset()
+	err = a.listKeyRollovers(ctx, &out, &sapb.GetKeyRolloversRequest{KeyHash: oldHash})
+	test.AssertNotError(t, err, "listKeyRollovers failed")
+	test.AssertEquals(t, out.String(), expected)
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/letsencrypt/boulder/core"
+	blog "github.com/letsencrypt/boulder/log"
+	"github.com/letsencrypt/boulder/test"
+)
+
+func TestKeyHashForPrivateKey(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	test.AssertNotError(t, err, "generating RSA key")
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	test.AssertNotError(t, err, "generating ECDSA key")
+	ecDER, err := x509.MarshalECPrivateKey(ecKey)
+	test.AssertNotError(t, err, "marshaling ECDSA key")
+	pkcs8DER, err := x509.MarshalPKCS8PrivateKey(rsaKey)
+	test.AssertNotError(t, err, "marshaling PKCS #8 key")
+
+	rsaHash, err := core.KeyDigest(rsaKey.Public())
+	test.AssertNotError(t, err, "hashing RSA key")
+	ecHash, err := core.KeyDigest(ecKey.Public())
+	test.AssertNotError(t, err, "hashing ECDSA key")
+
+	for _, tc := range []struct {
+		block *pem.Block
+		hash  []byte
+	}{
+		{&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)}, rsaHash[:]},
+		{&pem.Block{Type: "EC PRIVATE KEY", Bytes: ecDER}, ecHash[:]},
+		{&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8DER}, rsaHash[:]},
+	} {
+		hash, err := keyHashForPrivateKey(pem.EncodeToMemory(tc.block))
+		test.AssertNotError(t, err, "keyHashForPrivateKey failed for "+tc.block.Type)
+		test.AssertByteEquals(t, hash, tc.hash)
+	}
+
+	_, err = keyHashForPrivateKey([]byte("not a key"))
+	test.AssertError(t, err, "keyHashForPrivateKey accepted non-PEM input")
+
+	// A private key whose public half has been tampered with doesn't prove
+	// possession of that public key.
+	badKey := *rsaKey
+	badKey.N = new(big.Int).Add(rsaKey.N, big.NewInt(2))
+	_, err = keyHashForPrivateKey(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(&badKey)}))
+	test.AssertError(t, err, "keyHashForPrivateKey accepted an inconsistent key")
+}
+
+func TestReadKeyHashes(t *testing.T) {
+	a, b := strings.Repeat("aa", 32), strings.Repeat("bb", 32)
+	hashes, err := readKeyHashes(strings.NewReader("# leaked keys\n" + a + "\n\n  " + b + "  \n"))
+	test.AssertNotError(t, err, "readKeyHashes failed")
+	test.AssertEquals(t, len(hashes), 2)
+	test.AssertByteEquals(t, hashes[1], []byte(strings.Repeat("\xbb", 32)))
+
+	_, err = readKeyHashes(strings.NewReader(a + "\nabcd\n"))
+	test.AssertError(t, err, "readKeyHashes accepted a malformed hash")
+}
+
+func TestRevokeCompromisedKeys(t *testing.T) {
+	a, sa, log := newTestAdmin(t)
+	mockLog := log.(*blog.Mock)
+
+	count, err := a.revokeCompromisedKeys(context.Background(), [][]byte{[]byte("key"), []byte("unused")}, "leaked")
+	test.AssertNotError(t, err, "revokeCompromisedKeys failed")
+	test.AssertEquals(t, count, 3)
+	test.AssertEquals(t, len(sa.blocked), 2)
+	test.AssertEquals(t, sa.blocked[0].Comment, "leaked")
+	test.AssertEquals(t, len(sa.revoked), 3)
+	test.AssertEquals(t, len(mockLog.GetAllMatching(`"Command":"revoke-cert".*"Reason":"keyCompromise"`)), 3)
+}
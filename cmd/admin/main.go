@@ -0,0 +1,758 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/user"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/jmhodges/clock"
+
+	"github.com/letsencrypt/boulder/cmd"
+	"github.com/letsencrypt/boulder/core"
+	"github.com/letsencrypt/boulder/features"
+	bgrpc "github.com/letsencrypt/boulder/grpc"
+	blog "github.com/letsencrypt/boulder/log"
+	"github.com/letsencrypt/boulder/metrics"
+	policypb "github.com/letsencrypt/boulder/policy/proto"
+	rapb "github.com/letsencrypt/boulder/ra/proto"
+	"github.com/letsencrypt/boulder/ratelimit"
+	"github.com/letsencrypt/boulder/revocation"
+	sapb "github.com/letsencrypt/boulder/sa/proto"
+)
+
+const usageString = `
+usage:
+admin revoke-cert --config <path> <serial> <reason-code>
+admin revoke-by-key --config <path> <spki-hash> <reason-code>
+admin block-key --config <path> <spki-hash> <comment>
+admin revoke-compromised-key --config <path> private-key <key-file>
+admin revoke-compromised-key --config <path> spki-hashes <hash-file>
+admin deactivate-account --config <path> <registration-id>
+admin deactivate-accounts --config <path> <account-file> [<reason-code>]
+admin list-overrides --config <path> [<registration-id>]
+admin set-feature --config <path> <registration-id> <feature> <true|false>
+admin clear-feature --config <path> <registration-id> <feature>
+admin list-features --config <path> <registration-id>
+admin update-hostname-policy --config <path> <policy-file> <comment>
+admin show-hostname-policy --config <path>
+admin allow-domain --config <path> <registration-id> <domain>
+admin disallow-domain --config <path> <registration-id> <domain>
+admin list-allowed-domains --config <path> <registration-id>
+admin clear-allowlist --config <path> <registration-id>
+admin list-key-rollovers --config <path> <account|key> <registration-id|spki-hash>
+admin issuance-report --config <path> [--group-by <dimensions>] [--format <csv|json>] <start-date> <end-date>
+admin verify-issuance-log --config <path> <log-file>
+
+command descriptions:
+  revoke-cert         Revoke a single certificate by its hex serial number
+  revoke-by-key       Revoke all unexpired certificates, from any account, whose
+                      SubjectPublicKeyInfo has the given hex SHA-256 hash
+  block-key           Block future issuance for the key with the given hex
+                      SHA-256 SubjectPublicKeyInfo hash
+  revoke-compromised-key Block a compromised key and revoke all unexpired
+                      certificates, from any account, using it with reason
+                      keyCompromise. The key is either a PEM private key, the
+                      possession of which is checked, or a file of hex SHA-256
+                      SubjectPublicKeyInfo hashes, one per line
+  deactivate-account  Deactivate a registration, as if its owner had done so
+  deactivate-accounts Deactivate each registration listed in a file, either as
+                      a JSON array of IDs or as CSV with the ID in the first
+                      column. If a reason code is given, their unexpired
+                      certificates are also revoked
+  list-overrides      List the rate limit overrides in the RA's rate limit
+                      policy file, optionally only those for one registration
+  set-feature         Enable or disable a feature flag for one registration,
+                      overriding the services' configs. Services cache
+                      overrides, so the change may take a minute to apply
+  clear-feature       Remove a registration's override for a feature flag
+  list-features       List the feature flag overrides for a registration
+  update-hostname-policy Store a YAML hostname policy file in the SA as a new
+                      version of the hostname policy, and have each RA and CA
+                      in HostnamePolicyRefreshers load it. Others load it at
+                      their next refresh (see HostnamePolicyRefreshInterval)
+  show-hostname-policy Summarize the latest hostname policy stored in the SA
+  allow-domain        Add a registered domain to a registration's allowlist,
+                      restricting it to being issued certificates for names
+                      under the domains on it
+  disallow-domain     Remove a registered domain from a registration's
+                      allowlist. The registration stays restricted, so
+                      removing the last one leaves it unable to issue
+  list-allowed-domains List the registered domains on a registration's
+                      allowlist
+  clear-allowlist     Remove all of a registration's allowlist entries and
+                      lift its restriction to them
+  list-key-rollovers  List the key rollovers of a registration, or those from or
+                      to the key with the given hex SHA-256
+                      SubjectPublicKeyInfo hash. Only those made while the
+                      SA's KeyRolloverHistory feature was enabled are listed
+  issuance-report     Count the certificates issued between two dates, given
+                      as YYYY-MM-DD in UTC and both inclusive. Only those
+                      issued while the RA's StoreCertificateMetadata feature
+                      was enabled are counted
+  verify-issuance-log Check that a CA's issuance log is an unbroken hash chain,
+                      and that its entries match the certificates and entry
+                      copies stored in the SA. Prints the hash of the last
+                      entry, which attests to the whole log
+
+Revocation reason codes are listed by "admin-revoker list-reasons". Every
+change is audit logged along with the name of the user who made it.
+
+args:
+  config    File path to the configuration file for this service
+  dry-run   Perform all lookups and checks, and report what would change
+            without changing anything
+  accounts-per-second Limit the rate at which deactivate-accounts deactivates
+            registrations
+  report-file Write the full list of changes made, or with --dry-run the
+            changes which would have been made, to this file as JSON
+  group-by  Comma separated dimensions to break issuance-report's counts down
+            by, of day, certificateProfileName, keyAlgorithm and
+            validationMethods. Defaults to day
+  format    Write issuance-report's counts as csv, the default, or json
+`
+
+type config struct {
+	Admin struct {
+		// The admin tool needs a TLSConfig to set up its gRPC client certs, but
+		// doesn't get the TLS field from ServiceConfig, so declares its own.
+		TLS cmd.TLSConfig
+
+		RAService *cmd.GRPCClientConfig
+		SAService *cmd.GRPCClientConfig
+
+		// HostnamePolicyRefreshers address the gRPC server of every RA and CA
+		// instance, each of which update-hostname-policy asks to load the new
+		// version of the hostname policy as soon as it's stored. Each must
+		// address a single instance, rather than a load balanced service.
+		HostnamePolicyRefreshers []*cmd.GRPCClientConfig
+
+		// RateLimitPoliciesFilename is the RA's rate limit policy file, which
+		// holds the overrides shown by list-overrides.
+		RateLimitPoliciesFilename string
+
+		Features map[string]bool
+	}
+
+	Syslog cmd.SyslogConfig
+}
+
+// admin performs administrative operations through the RA and SA, audit
+// logging each change along with the user who made it.
+type admin struct {
+	rac  core.RegistrationAuthority
+	sac  core.StorageAuthority
+	clk  clock.Clock
+	log  blog.Logger
+	user string
+
+	// refreshers are the RA and CA instances which load a new hostname policy
+	// as soon as it's stored.
+	refreshers []hostnamePolicyRefresher
+
+	// dryRun, if true, makes every change a no-op which is only recorded in
+	// the report.
+	dryRun bool
+	report impactReport
+}
+
+func setupAdmin(c config, dryRun bool) *admin {
+	logger := cmd.NewLogger(c.Syslog)
+
+	tlsConfig, err := c.Admin.TLS.Load()
+	cmd.FailOnError(err, "TLS config")
+
+	clk := cmd.Clock()
+
+	clientMetrics := bgrpc.NewClientMetrics(metrics.NoopRegisterer)
+	raConn, err := bgrpc.ClientSetup(c.Admin.RAService, tlsConfig, clientMetrics, clk)
+	cmd.FailOnError(err, "Failed to load credentials and create gRPC connection to RA")
+	rac := bgrpc.NewRegistrationAuthorityClient(rapb.NewRegistrationAuthorityClient(raConn))
+
+	saConn, err := bgrpc.ClientSetup(c.Admin.SAService, tlsConfig, clientMetrics, clk)
+	cmd.FailOnError(err, "Failed to load credentials and create gRPC connection to SA")
+	sac := bgrpc.NewStorageAuthorityClient(sapb.NewStorageAuthorityClient(saConn))
+
+	var refreshers []hostnamePolicyRefresher
+	for _, refresherConfig := range c.Admin.HostnamePolicyRefreshers {
+		conn, err := bgrpc.ClientSetup(refresherConfig, tlsConfig, clientMetrics, clk)
+		cmd.FailOnError(err, fmt.Sprintf("Failed to create gRPC connection to %s", refresherConfig.ServerAddress))
+		refreshers = append(refreshers, hostnamePolicyRefresher{
+			address: refresherConfig.ServerAddress,
+			client:  policypb.NewHostnamePolicyRefresherClient(conn),
+		})
+	}
+
+	u, err := user.Current()
+	cmd.FailOnError(err, "Couldn't determine the current user")
+
+	return &admin{
+		rac:  rac,
+		sac:  sac,
+		clk:  clk,
+		log:  logger,
+		user: u.Username,
+
+		refreshers: refreshers,
+
+		dryRun: dryRun,
+		report: impactReport{DryRun: dryRun},
+	}
+}
+
+// finish prints a summary of the changes made, or which would have been made,
+// and writes the full report to reportPath if it isn't empty.
+func (a *admin) finish(reportPath string) {
+	a.report.summarize(os.Stdout)
+	if reportPath != "" {
+		err := a.report.writeFile(reportPath)
+		cmd.FailOnError(err, "Couldn't write report file")
+	}
+}
+
+// auditEvent is the structured audit log entry written for every change made
+// by the admin tool.
+type auditEvent struct {
+	User    string
+	Command string
+	Target  string
+	Reason  string `json:",omitempty"`
+	Comment string `json:",omitempty"`
+	Error   string `json:",omitempty"`
+}
+
+func (a *admin) audit(event auditEvent, err error) {
+	if a.dryRun {
+		// Nothing was changed.
+		return
+	}
+	event.User = a.user
+	if err != nil {
+		event.Error = err.Error()
+	}
+	a.log.AuditObject("Admin operation", event)
+}
+
+// parseReason parses a numeric revocation reason code.
+func parseReason(s string) (revocation.Reason, error) {
+	code, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("reason code must be an integer: %s", err)
+	}
+	reason := revocation.Reason(code)
+	if _, ok := revocation.ReasonToString[reason]; !ok {
+		return 0, fmt.Errorf("invalid reason code %d", code)
+	}
+	return reason, nil
+}
+
+// parseKeyHash parses a hex encoded SHA-256 hash of a SubjectPublicKeyInfo.
+func parseKeyHash(s string) ([]byte, error) {
+	hash, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("key hash must be hex encoded: %s", err)
+	}
+	if len(hash) != 32 {
+		return nil, fmt.Errorf("key hash must be a SHA-256 hash, got %d bytes", len(hash))
+	}
+	return hash, nil
+}
+
+func (a *admin) revokeCert(ctx context.Context, serial string, reason revocation.Reason) (err error) {
+	defer func() {
+		a.audit(auditEvent{
+			Command: "revoke-cert",
+			Target:  serial,
+			Reason:  revocation.ReasonToString[reason],
+		}, err)
+	}()
+	certObj, err := a.sac.GetCertificate(ctx, serial)
+	if err != nil {
+		return err
+	}
+	cert, err := x509.ParseCertificate(certObj.DER)
+	if err != nil {
+		return err
+	}
+	if a.dryRun {
+		// The RA refuses to revoke a certificate twice.
+		status, err := a.sac.GetCertificateStatus(ctx, serial)
+		if err != nil {
+			return err
+		}
+		if status.Status == core.OCSPStatusRevoked {
+			return errors.New("certificate is already revoked")
+		}
+	} else {
+		err = a.rac.AdministrativelyRevokeCertificate(ctx, *cert, reason, a.user)
+		if err != nil {
+			return err
+		}
+	}
+	a.report.Revocations = append(a.report.Revocations, revokedCert{
+		Serial:         serial,
+		RegistrationID: certObj.RegistrationID,
+		Reason:         revocation.ReasonToString[reason],
+	})
+	return nil
+}
+
+// revokeByKey revokes every unexpired certificate with the given key, skipping
+// those which are already revoked. It carries on past failures, and returns
+// the number of certificates revoked.
+func (a *admin) revokeByKey(ctx context.Context, keyHash []byte, reason revocation.Reason) (int, error) {
+	serials, err := a.sac.GetSerialsByKey(ctx, &sapb.SPKIHash{KeyHash: keyHash})
+	if err != nil {
+		return 0, err
+	}
+	revoked, err := a.revokeSerials(ctx, serials.Serials, reason)
+	if err != nil {
+		return revoked, fmt.Errorf("revoking certificates with key %x: %w", keyHash, err)
+	}
+	return revoked, nil
+}
+
+// revokeSerials revokes each of the given certificates, skipping those which
+// are already revoked. It carries on past failures, and returns the number of
+// certificates revoked.
+func (a *admin) revokeSerials(ctx context.Context, serials []string, reason revocation.Reason) (int, error) {
+	var revoked, failed int
+	for _, serial := range serials {
+		status, err := a.sac.GetCertificateStatus(ctx, serial)
+		if err != nil {
+			a.log.Errf("Failed to get status of certificate %s: %s", serial, err)
+			failed++
+			continue
+		}
+		if status.Status == core.OCSPStatusRevoked {
+			a.log.Infof("Certificate %s is already revoked", serial)
+			continue
+		}
+		err = a.revokeCert(ctx, serial, reason)
+		if err != nil {
+			a.log.Errf("Failed to revoke certificate %s: %s", serial, err)
+			failed++
+			continue
+		}
+		revoked++
+	}
+	if failed > 0 {
+		return revoked, fmt.Errorf("failed to revoke %d of %d certificates", failed, len(serials))
+	}
+	return revoked, nil
+}
+
+func (a *admin) blockKey(ctx context.Context, keyHash []byte, comment string) error {
+	var err error
+	if !a.dryRun {
+		_, err = a.sac.AddBlockedKey(ctx, &sapb.AddBlockedKeyRequest{
+			KeyHash: keyHash,
+			Added:   a.clk.Now().UnixNano(),
+			Source:  "admin-revoker",
+			Comment: comment,
+		})
+	}
+	a.audit(auditEvent{
+		Command: "block-key",
+		Target:  hex.EncodeToString(keyHash),
+		Comment: comment,
+	}, err)
+	if err != nil {
+		return err
+	}
+	a.report.BlockedKeys = append(a.report.BlockedKeys, hex.EncodeToString(keyHash))
+	return nil
+}
+
+var errAlreadyDeactivated = errors.New("registration is already deactivated")
+
+func (a *admin) deactivateAccount(ctx context.Context, regID int64) (err error) {
+	defer func() {
+		a.audit(auditEvent{
+			Command: "deactivate-account",
+			Target:  strconv.FormatInt(regID, 10),
+		}, err)
+	}()
+	reg, err := a.sac.GetRegistration(ctx, regID)
+	if err != nil {
+		return err
+	}
+	if reg.Status == core.StatusDeactivated {
+		return errAlreadyDeactivated
+	}
+	if reg.Status != core.StatusValid {
+		return fmt.Errorf("only valid registrations can be deactivated, registration is %s", reg.Status)
+	}
+	if !a.dryRun {
+		err = a.rac.DeactivateRegistration(ctx, reg)
+		if err != nil {
+			return err
+		}
+	}
+	a.report.Deactivated = append(a.report.Deactivated, regID)
+	return nil
+}
+
+// listOverrides writes the rate limit overrides in the given policy file to
+// w. If regID is non-zero, only registration overrides for it are listed.
+func listOverrides(w io.Writer, policyFile string, regID int64) error {
+	contents, err := ioutil.ReadFile(policyFile)
+	if err != nil {
+		return err
+	}
+	limits := ratelimit.New()
+	err = limits.LoadPolicies(contents)
+	if err != nil {
+		return err
+	}
+	policies := []struct {
+		name   string
+		policy ratelimit.RateLimitPolicy
+	}{
+		{"certificatesPerName", limits.CertificatesPerName()},
+		{"registrationsPerIP", limits.RegistrationsPerIP()},
+		{"registrationsPerIPRange", limits.RegistrationsPerIPRange()},
+		{"pendingAuthorizationsPerAccount", limits.PendingAuthorizationsPerAccount()},
+		{"invalidAuthorizationsPerAccount", limits.InvalidAuthorizationsPerAccount()},
+		{"certificatesPerFQDNSet", limits.CertificatesPerFQDNSet()},
+		{"pendingOrdersPerAccount", limits.PendingOrdersPerAccount()},
+		{"newOrdersPerAccount", limits.NewOrdersPerAccount()},
+	}
+	for _, p := range policies {
+		if regID == 0 {
+			var keys []string
+			for k := range p.policy.Overrides {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				fmt.Fprintf(w, "%s\tkey %s\t%d (default %d)\n", p.name, k, p.policy.Overrides[k], p.policy.Threshold)
+			}
+		}
+		var ids []int64
+		for id := range p.policy.RegistrationOverrides {
+			if regID == 0 || id == regID {
+				ids = append(ids, id)
+			}
+		}
+		sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+		for _, id := range ids {
+			fmt.Fprintf(w, "%s\tregistration %d\t%d (default %d)\n", p.name, id, p.policy.RegistrationOverrides[id], p.policy.Threshold)
+		}
+	}
+	return nil
+}
+
+func main() {
+	usage := func() {
+		fmt.Fprint(os.Stderr, usageString)
+		os.Exit(1)
+	}
+	if len(os.Args) <= 2 {
+		usage()
+	}
+
+	command := os.Args[1]
+	flagSet := flag.NewFlagSet(command, flag.ContinueOnError)
+	configFile := flagSet.String("config", "", "File path to the configuration file for this service")
+	dryRun := flagSet.Bool("dry-run", false, "Report what would change without changing anything")
+	accountsPerSecond := flagSet.Float64("accounts-per-second", 5, "Rate at which deactivate-accounts deactivates registrations")
+	reportFile := flagSet.String("report-file", "", "File to write the full JSON report of changes to")
+	groupBy := flagSet.String("group-by", "day", "Comma separated dimensions to break issuance-report's counts down by")
+	format := flagSet.String("format", "csv", "Format to write issuance-report's counts in, csv or json")
+	err := flagSet.Parse(os.Args[2:])
+	cmd.FailOnError(err, "Error parsing flagset")
+
+	if *configFile == "" {
+		usage()
+	}
+
+	var c config
+	err = cmd.ReadConfigFile(*configFile, &c)
+	cmd.FailOnError(err, "Reading JSON config file into config structure")
+	err = features.Set(c.Admin.Features)
+	cmd.FailOnError(err, "Failed to set feature flags")
+
+	ctx := context.Background()
+	args := flagSet.Args()
+	switch {
+	case command == "revoke-cert" && len(args) == 2:
+		// 1: serial, 2: reasonCode
+		reason, err := parseReason(args[1])
+		cmd.FailOnError(err, "Invalid reason code")
+
+		a := setupAdmin(c, *dryRun)
+		defer a.log.AuditPanic()
+
+		err = a.revokeCert(ctx, args[0], reason)
+		a.finish(*reportFile)
+		cmd.FailOnError(err, "Couldn't revoke certificate")
+
+	case command == "revoke-by-key" && len(args) == 2:
+		// 1: SPKI hash, 2: reasonCode
+		keyHash, err := parseKeyHash(args[0])
+		cmd.FailOnError(err, "Invalid key hash")
+		reason, err := parseReason(args[1])
+		cmd.FailOnError(err, "Invalid reason code")
+
+		a := setupAdmin(c, *dryRun)
+		defer a.log.AuditPanic()
+
+		_, err = a.revokeByKey(ctx, keyHash, reason)
+		a.finish(*reportFile)
+		cmd.FailOnError(err, "Couldn't revoke certificates by key")
+
+	case command == "block-key" && len(args) == 2:
+		// 1: SPKI hash, 2: comment
+		keyHash, err := parseKeyHash(args[0])
+		cmd.FailOnError(err, "Invalid key hash")
+
+		a := setupAdmin(c, *dryRun)
+		defer a.log.AuditPanic()
+
+		err = a.blockKey(ctx, keyHash, args[1])
+		a.finish(*reportFile)
+		cmd.FailOnError(err, "Couldn't block key")
+
+	case command == "revoke-compromised-key" && len(args) == 2:
+		// 1: "private-key" or "spki-hashes", 2: file path
+		contents, err := ioutil.ReadFile(args[1])
+		cmd.FailOnError(err, "Couldn't read key file")
+		var keyHashes [][]byte
+		var comment string
+		switch args[0] {
+		case "private-key":
+			keyHash, err := keyHashForPrivateKey(contents)
+			cmd.FailOnError(err, "Couldn't prove possession of private key")
+			keyHashes = [][]byte{keyHash}
+			comment = "compromised private key provided"
+		case "spki-hashes":
+			keyHashes, err = readKeyHashes(bytes.NewReader(contents))
+			cmd.FailOnError(err, "Couldn't read key hashes")
+			comment = "compromised key hash provided"
+		default:
+			usage()
+		}
+
+		a := setupAdmin(c, *dryRun)
+		defer a.log.AuditPanic()
+
+		_, err = a.revokeCompromisedKeys(ctx, keyHashes, comment)
+		a.finish(*reportFile)
+		cmd.FailOnError(err, "Couldn't revoke all certificates using compromised keys")
+
+	case command == "deactivate-account" && len(args) == 1:
+		// 1: registration ID
+		regID, err := strconv.ParseInt(args[0], 10, 64)
+		cmd.FailOnError(err, "Registration ID argument must be an integer")
+
+		a := setupAdmin(c, *dryRun)
+		defer a.log.AuditPanic()
+
+		err = a.deactivateAccount(ctx, regID)
+		a.finish(*reportFile)
+		cmd.FailOnError(err, "Couldn't deactivate registration")
+
+	case command == "deactivate-accounts" && (len(args) == 1 || len(args) == 2):
+		// 1: account file, 2: optional reasonCode
+		contents, err := ioutil.ReadFile(args[0])
+		cmd.FailOnError(err, "Couldn't read account file")
+		ids, err := parseAccountIDs(contents)
+		cmd.FailOnError(err, "Couldn't parse account file")
+		var reason revocation.Reason
+		revoke := len(args) == 2
+		if revoke {
+			reason, err = parseReason(args[1])
+			cmd.FailOnError(err, "Invalid reason code")
+		}
+		if *accountsPerSecond <= 0 {
+			cmd.Fail("accounts-per-second must be positive")
+		}
+		interval := time.Duration(float64(time.Second) / *accountsPerSecond)
+
+		a := setupAdmin(c, *dryRun)
+		defer a.log.AuditPanic()
+
+		err = a.deactivateAccounts(ctx, ids, revoke, reason, interval)
+		a.finish(*reportFile)
+		cmd.FailOnError(err, "Couldn't deactivate all accounts")
+
+	case command == "list-overrides" && len(args) <= 1:
+		// 1: optional registration ID
+		var regID int64
+		if len(args) == 1 {
+			regID, err = strconv.ParseInt(args[0], 10, 64)
+			cmd.FailOnError(err, "Registration ID argument must be an integer")
+		}
+		if c.Admin.RateLimitPoliciesFilename == "" {
+			cmd.Fail("rateLimitPoliciesFilename must be configured to list overrides")
+		}
+		err = listOverrides(os.Stdout, c.Admin.RateLimitPoliciesFilename, regID)
+		cmd.FailOnError(err, "Couldn't list rate limit overrides")
+
+	case command == "set-feature" && len(args) == 3:
+		// 1: registration ID, 2: feature, 3: enabled
+		regID, err := strconv.ParseInt(args[0], 10, 64)
+		cmd.FailOnError(err, "Registration ID argument must be an integer")
+		enabled, err := strconv.ParseBool(args[2])
+		cmd.FailOnError(err, "Feature setting must be true or false")
+
+		a := setupAdmin(c, *dryRun)
+		defer a.log.AuditPanic()
+
+		err = a.setFeatureOverride(ctx, regID, args[1], enabled)
+		a.finish(*reportFile)
+		cmd.FailOnError(err, "Couldn't set feature override")
+
+	case command == "clear-feature" && len(args) == 2:
+		// 1: registration ID, 2: feature
+		regID, err := strconv.ParseInt(args[0], 10, 64)
+		cmd.FailOnError(err, "Registration ID argument must be an integer")
+
+		a := setupAdmin(c, *dryRun)
+		defer a.log.AuditPanic()
+
+		err = a.clearFeatureOverride(ctx, regID, args[1])
+		a.finish(*reportFile)
+		cmd.FailOnError(err, "Couldn't clear feature override")
+
+	case command == "list-features" && len(args) == 1:
+		// 1: registration ID
+		regID, err := strconv.ParseInt(args[0], 10, 64)
+		cmd.FailOnError(err, "Registration ID argument must be an integer")
+
+		a := setupAdmin(c, *dryRun)
+		defer a.log.AuditPanic()
+
+		err = a.listFeatureOverrides(ctx, os.Stdout, regID)
+		cmd.FailOnError(err, "Couldn't list feature overrides")
+
+	case command == "update-hostname-policy" && len(args) == 2:
+		// 1: policy file, 2: comment
+		contents, err := ioutil.ReadFile(args[0])
+		cmd.FailOnError(err, "Couldn't read hostname policy file")
+
+		a := setupAdmin(c, *dryRun)
+		defer a.log.AuditPanic()
+
+		version, err := a.updateHostnamePolicy(ctx, contents, args[1])
+		cmd.FailOnError(err, "Couldn't update hostname policy")
+		if *dryRun {
+			fmt.Println("Dry run, hostname policy is valid")
+		} else {
+			fmt.Printf("Stored hostname policy version %d, and had %d RAs and CAs load it\n", version, len(a.refreshers))
+		}
+
+	case command == "show-hostname-policy" && len(args) == 0:
+		a := setupAdmin(c, *dryRun)
+		defer a.log.AuditPanic()
+
+		err = a.showHostnamePolicy(ctx, os.Stdout)
+		cmd.FailOnError(err, "Couldn't show hostname policy")
+
+	case command == "allow-domain" && len(args) == 2:
+		// 1: registration ID, 2: domain
+		regID, err := strconv.ParseInt(args[0], 10, 64)
+		cmd.FailOnError(err, "Registration ID argument must be an integer")
+
+		a := setupAdmin(c, *dryRun)
+		defer a.log.AuditPanic()
+
+		err = a.allowDomain(ctx, regID, args[1])
+		a.finish(*reportFile)
+		cmd.FailOnError(err, "Couldn't add allowlist entry")
+
+	case command == "disallow-domain" && len(args) == 2:
+		// 1: registration ID, 2: domain
+		regID, err := strconv.ParseInt(args[0], 10, 64)
+		cmd.FailOnError(err, "Registration ID argument must be an integer")
+
+		a := setupAdmin(c, *dryRun)
+		defer a.log.AuditPanic()
+
+		err = a.disallowDomain(ctx, regID, args[1])
+		a.finish(*reportFile)
+		cmd.FailOnError(err, "Couldn't remove allowlist entry")
+
+	case command == "list-allowed-domains" && len(args) == 1:
+		// 1: registration ID
+		regID, err := strconv.ParseInt(args[0], 10, 64)
+		cmd.FailOnError(err, "Registration ID argument must be an integer")
+
+		a := setupAdmin(c, *dryRun)
+		defer a.log.AuditPanic()
+
+		err = a.listAllowedDomains(ctx, os.Stdout, regID)
+		cmd.FailOnError(err, "Couldn't list allowed domains")
+
+	case command == "clear-allowlist" && len(args) == 1:
+		// 1: registration ID
+		regID, err := strconv.ParseInt(args[0], 10, 64)
+		cmd.FailOnError(err, "Registration ID argument must be an integer")
+
+		a := setupAdmin(c, *dryRun)
+		defer a.log.AuditPanic()
+
+		err = a.clearAllowlist(ctx, regID)
+		a.finish(*reportFile)
+		cmd.FailOnError(err, "Couldn't clear allowlist")
+
+	case command == "list-key-rollovers" && len(args) == 2:
+		// 1: "account" or "key", 2: registration ID or SPKI hash
+		req := &sapb.GetKeyRolloversRequest{}
+		switch args[0] {
+		case "account":
+			req.RegistrationID, err = strconv.ParseInt(args[1], 10, 64)
+			cmd.FailOnError(err, "Registration ID argument must be an integer")
+		case "key":
+			req.KeyHash, err = parseKeyHash(args[1])
+			cmd.FailOnError(err, "Invalid key hash")
+		default:
+			usage()
+		}
+
+		a := setupAdmin(c, *dryRun)
+		defer a.log.AuditPanic()
+
+		err = a.listKeyRollovers(ctx, os.Stdout, req)
+		cmd.FailOnError(err, "Couldn't list key rollovers")
+
+	case command == "issuance-report" && len(args) == 2:
+		// 1: start date, 2: end date
+		since, err := time.Parse(reportDateLayout, args[0])
+		cmd.FailOnError(err, "Start date must be formatted as YYYY-MM-DD")
+		until, err := time.Parse(reportDateLayout, args[1])
+		cmd.FailOnError(err, "End date must be formatted as YYYY-MM-DD")
+
+		a := setupAdmin(c, *dryRun)
+		defer a.log.AuditPanic()
+
+		err = a.issuanceReport(ctx, os.Stdout, since, until, parseGroupBy(*groupBy), *format)
+		cmd.FailOnError(err, "Couldn't write issuance report")
+
+	case command == "verify-issuance-log" && len(args) == 1:
+		// 1: issuance log file
+		file, err := os.Open(args[0])
+		cmd.FailOnError(err, "Couldn't open issuance log")
+		defer file.Close()
+
+		a := setupAdmin(c, *dryRun)
+		defer a.log.AuditPanic()
+
+		err = a.verifyIssuanceLog(ctx, os.Stdout, file)
+		cmd.FailOnError(err, "Issuance log verification failed")
+
+	default:
+		usage()
+	}
+}
@@ -0,0 +1,306 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jmhodges/clock"
+
+	"github.com/letsencrypt/boulder/core"
+	corepb "github.com/letsencrypt/boulder/core/proto"
+	berrors "github.com/letsencrypt/boulder/errors"
+	blog "github.com/letsencrypt/boulder/log"
+	"github.com/letsencrypt/boulder/revocation"
+	sapb "github.com/letsencrypt/boulder/sa/proto"
+	"github.com/letsencrypt/boulder/test"
+)
+
+// fakeSA implements the parts of core.StorageAuthority the admin tool uses.
+type fakeSA struct {
+	core.StorageAuthority
+	certs       map[string]core.Certificate
+	revoked     map[string]bool
+	keySerials  map[string][]string
+	blocked     []*sapb.AddBlockedKeyRequest
+	regs        map[int64]core.Registration
+	deactivated []int64
+	overrides   map[int64]map[string]bool
+	policies    []*sapb.HostnamePolicy
+	allowlists  map[int64][]string
+
+	keyRollovers []*sapb.KeyRollover
+
+	precerts           map[string][]byte
+	issuanceLogEntries []*sapb.IssuanceLogEntry
+
+	issuanceCountRequests []*sapb.IssuanceCountsRequest
+}
+
+func (sa *fakeSA) GetCertificate(_ context.Context, serial string) (core.Certificate, error) {
+	cert, ok := sa.certs[serial]
+	if !ok {
+		return core.Certificate{}, berrors.NotFoundError("no certificate with serial %s", serial)
+	}
+	return cert, nil
+}
+
+func (sa *fakeSA) GetCertificateStatus(_ context.Context, serial string) (core.CertificateStatus, error) {
+	if sa.revoked[serial] {
+		return core.CertificateStatus{Serial: serial, Status: core.OCSPStatusRevoked}, nil
+	}
+	return core.CertificateStatus{Serial: serial, Status: core.OCSPStatusGood}, nil
+}
+
+func (sa *fakeSA) GetSerialsByKey(_ context.Context, req *sapb.SPKIHash) (*sapb.Serials, error) {
+	return &sapb.Serials{Serials: sa.keySerials[string(req.KeyHash)]}, nil
+}
+
+func (sa *fakeSA) GetSerialsByAccount(_ context.Context, req *sapb.RegistrationID) (*sapb.Serials, error) {
+	var serials []string
+	for serial, cert := range sa.certs {
+		if cert.RegistrationID == req.Id {
+			serials = append(serials, serial)
+		}
+	}
+	return &sapb.Serials{Serials: serials}, nil
+}
+
+func (sa *fakeSA) AddBlockedKey(_ context.Context, req *sapb.AddBlockedKeyRequest) (*corepb.Empty, error) {
+	sa.blocked = append(sa.blocked, req)
+	return &corepb.Empty{}, nil
+}
+
+func (sa *fakeSA) GetRegistration(_ context.Context, id int64) (core.Registration, error) {
+	reg, ok := sa.regs[id]
+	if !ok {
+		return core.Registration{}, berrors.NotFoundError("no registration with ID %d", id)
+	}
+	return reg, nil
+}
+
+// fakeRA implements the parts of core.RegistrationAuthority the admin tool
+// uses, recording its changes in the fakeSA.
+type fakeRA struct {
+	core.RegistrationAuthority
+	sa *fakeSA
+}
+
+func (ra *fakeRA) AdministrativelyRevokeCertificate(_ context.Context, cert x509.Certificate, _ revocation.Reason, _ string) error {
+	ra.sa.revoked[core.SerialToString(cert.SerialNumber)] = true
+	return nil
+}
+
+func (ra *fakeRA) DeactivateRegistration(_ context.Context, reg core.Registration) error {
+	ra.sa.deactivated = append(ra.sa.deactivated, reg.ID)
+	return nil
+}
+
+func newTestAdmin(t *testing.T) (*admin, *fakeSA, blog.Logger) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	test.AssertNotError(t, err, "generating key")
+	sa := &fakeSA{
+		certs:      make(map[string]core.Certificate),
+		revoked:    make(map[string]bool),
+		keySerials: make(map[string][]string),
+		overrides:  make(map[int64]map[string]bool),
+		allowlists: make(map[int64][]string),
+		regs: map[int64]core.Registration{
+			1: {ID: 1, Status: core.StatusValid},
+			2: {ID: 2, Status: core.StatusDeactivated},
+		},
+	}
+	for i := int64(1); i <= 3; i++ {
+		template := &x509.Certificate{
+			SerialNumber: big.NewInt(i),
+			NotAfter:     time.Now().Add(time.Hour),
+		}
+		der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+		test.AssertNotError(t, err, "creating certificate")
+		serial := core.SerialToString(template.SerialNumber)
+		sa.certs[serial] = core.Certificate{Serial: serial, DER: der, RegistrationID: i}
+		sa.keySerials["key"] = append(sa.keySerials["key"], serial)
+	}
+	log := blog.NewMock()
+	return &admin{
+		rac:  &fakeRA{sa: sa},
+		sac:  sa,
+		clk:  clock.NewFake(),
+		log:  log,
+		user: "operator",
+	}, sa, log
+}
+
+func TestRevokeCert(t *testing.T) {
+	a, sa, log := newTestAdmin(t)
+	mockLog := log.(*blog.Mock)
+
+	serial := core.SerialToString(big.NewInt(1))
+	err := a.revokeCert(context.Background(), serial, 1)
+	test.AssertNotError(t, err, "revokeCert failed")
+	test.Assert(t, sa.revoked[serial], "certificate wasn't revoked")
+	test.AssertEquals(t, len(mockLog.GetAllMatching(`"User":"operator","Command":"revoke-cert","Target":"`+serial+`","Reason":"keyCompromise"`)), 1)
+
+	err = a.revokeCert(context.Background(), "missing", 1)
+	test.AssertError(t, err, "revokeCert didn't fail for a missing certificate")
+	test.AssertEquals(t, len(mockLog.GetAllMatching(`"Target":"missing".*"Error"`)), 1)
+}
+
+func TestRevokeByKey(t *testing.T) {
+	a, sa, log := newTestAdmin(t)
+	mockLog := log.(*blog.Mock)
+
+	sa.revoked[core.SerialToString(big.NewInt(2))] = true
+	count, err := a.revokeByKey(context.Background(), []byte("key"), 1)
+	test.AssertNotError(t, err, "revokeByKey failed")
+	// The already revoked certificate is skipped.
+	test.AssertEquals(t, count, 2)
+	test.AssertEquals(t, len(sa.revoked), 3)
+	test.AssertEquals(t, len(mockLog.GetAllMatching(`"Command":"revoke-cert"`)), 2)
+
+	// Failures don't stop the remaining certificates being revoked.
+	sa.revoked = make(map[string]bool)
+	sa.keySerials["key"] = append([]string{"missing"}, sa.keySerials["key"]...)
+	count, err = a.revokeByKey(context.Background(), []byte("key"), 1)
+	test.AssertError(t, err, "revokeByKey didn't report a failure")
+	test.AssertEquals(t, count, 3)
+}
+
+func TestBlockKey(t *testing.T) {
+	a, sa, log := newTestAdmin(t)
+	mockLog := log.(*blog.Mock)
+
+	hash := bytes.Repeat([]byte{1}, 32)
+	err := a.blockKey(context.Background(), hash, "leaked on a pastebin")
+	test.AssertNotError(t, err, "blockKey failed")
+	test.AssertEquals(t, len(sa.blocked), 1)
+	test.AssertByteEquals(t, sa.blocked[0].KeyHash, hash)
+	test.AssertEquals(t, sa.blocked[0].Source, "admin-revoker")
+	test.AssertEquals(t, sa.blocked[0].Added, a.clk.Now().UnixNano())
+	test.AssertEquals(t, len(mockLog.GetAllMatching(`"Command":"block-key".*"Comment":"leaked on a pastebin"`)), 1)
+}
+
+func TestDeactivateAccount(t *testing.T) {
+	a, sa, log := newTestAdmin(t)
+	mockLog := log.(*blog.Mock)
+
+	err := a.deactivateAccount(context.Background(), 1)
+	test.AssertNotError(t, err, "deactivateAccount failed")
+	test.AssertDeepEquals(t, sa.deactivated, []int64{1})
+
+	err = a.deactivateAccount(context.Background(), 2)
+	test.AssertError(t, err, "deactivateAccount didn't fail for a deactivated registration")
+	err = a.deactivateAccount(context.Background(), 3)
+	test.AssertError(t, err, "deactivateAccount didn't fail for a missing registration")
+	test.AssertDeepEquals(t, sa.deactivated, []int64{1})
+	test.AssertEquals(t, len(mockLog.GetAllMatching(`"Command":"deactivate-account"`)), 3)
+}
+
+func TestDryRun(t *testing.T) {
+	a, sa, log := newTestAdmin(t)
+	mockLog := log.(*blog.Mock)
+	a.dryRun = true
+	a.report.DryRun = true
+
+	sa.revoked[core.SerialToString(big.NewInt(2))] = true
+	_, err := a.revokeCompromisedKeys(context.Background(), [][]byte{[]byte("key")}, "leaked")
+	test.AssertNotError(t, err, "revokeCompromisedKeys failed")
+	err = a.deactivateAccount(context.Background(), 1)
+	test.AssertNotError(t, err, "deactivateAccount failed")
+	// Checks are still made in a dry run.
+	err = a.revokeCert(context.Background(), core.SerialToString(big.NewInt(2)), 1)
+	test.AssertError(t, err, "revokeCert didn't fail for a revoked certificate")
+	err = a.deactivateAccount(context.Background(), 2)
+	test.AssertError(t, err, "deactivateAccount didn't fail for a deactivated registration")
+
+	// Nothing was changed or audit logged...
+	test.AssertEquals(t, len(sa.revoked), 1)
+	test.AssertEquals(t, len(sa.blocked), 0)
+	test.AssertEquals(t, len(sa.deactivated), 0)
+	test.AssertEquals(t, len(mockLog.GetAllMatching("Admin operation")), 0)
+
+	// ...but the report says what would have been.
+	test.AssertDeepEquals(t, a.report.Revocations, []revokedCert{
+		{Serial: core.SerialToString(big.NewInt(1)), RegistrationID: 1, Reason: "keyCompromise"},
+		{Serial: core.SerialToString(big.NewInt(3)), RegistrationID: 3, Reason: "keyCompromise"},
+	})
+	test.AssertDeepEquals(t, a.report.BlockedKeys, []string{hex.EncodeToString([]byte("key"))})
+	test.AssertDeepEquals(t, a.report.Deactivated, []int64{1})
+	test.AssertDeepEquals(t, a.report.accounts(), []int64{1, 3})
+}
+
+func TestReportSummary(t *testing.T) {
+	report := impactReport{DryRun: true, Deactivated: []int64{7}}
+	for i := 0; i < reportSampleSize+2; i++ {
+		report.Revocations = append(report.Revocations, revokedCert{Serial: fmt.Sprintf("%02d", i), RegistrationID: 7, Reason: "unspecified"})
+	}
+	var out bytes.Buffer
+	report.summarize(&out)
+	test.AssertContains(t, out.String(), "Dry run, would have made")
+	test.AssertContains(t, out.String(), "Certificates revoked: 12\n")
+	test.AssertContains(t, out.String(), "    09 (registration 7, reason unspecified)\n    ... and 2 more\n")
+	test.AssertContains(t, out.String(), "Registrations affected: 1\n")
+}
+
+func TestParseArgs(t *testing.T) {
+	_, err := parseReason("1")
+	test.AssertNotError(t, err, "parseReason failed")
+	_, err = parseReason("7")
+	test.AssertError(t, err, "parseReason accepted an unused reason code")
+	_, err = parseReason("keyCompromise")
+	test.AssertError(t, err, "parseReason accepted a non-numeric reason code")
+
+	_, err = parseKeyHash(strings.Repeat("ab", 32))
+	test.AssertNotError(t, err, "parseKeyHash failed")
+	_, err = parseKeyHash("abcd")
+	test.AssertError(t, err, "parseKeyHash accepted a short hash")
+	_, err = parseKeyHash(strings.Repeat("zz", 32))
+	test.AssertError(t, err, "parseKeyHash accepted a non-hex hash")
+}
+
+func TestListOverrides(t *testing.T) {
+	f, err := ioutil.TempFile("", "rate-limit-policies")
+	test.AssertNotError(t, err, "creating policy file")
+	defer os.Remove(f.Name())
+	_, err = f.WriteString(`
+certificatesPerName:
+  window: 2160h
+  threshold: 2
+  overrides:
+    ratelimit.me: 1
+  registrationOverrides:
+    101: 1000
+newOrdersPerAccount:
+  window: 3h
+  threshold: 1500
+  registrationOverrides:
+    101: 3000
+    102: 4000
+`)
+	test.AssertNotError(t, err, "writing policy file")
+	test.AssertNotError(t, f.Close(), "closing policy file")
+
+	var out bytes.Buffer
+	err = listOverrides(&out, f.Name(), 0)
+	test.AssertNotError(t, err, "listOverrides failed")
+	test.AssertEquals(t, out.String(), "certificatesPerName\tkey ratelimit.me\t1 (default 2)\n"+
+		"certificatesPerName\tregistration 101\t1000 (default 2)\n"+
+		"newOrdersPerAccount\tregistration 101\t3000 (default 1500)\n"+
+		"newOrdersPerAccount\tregistration 102\t4000 (default 1500)\n")
+
+	out.Reset()
+	err = listOverrides(&out, f.Name(), 102)
+	test.AssertNotError(t, err, "listOverrides failed")
+	test.AssertEquals(t, out.String(), "newOrdersPerAccount\tregistration 102\t4000 (default 1500)\n")
+}
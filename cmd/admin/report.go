@@ -0,0 +1,157 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+)
+
+// reportSampleSize is the number of entries of each kind shown in the
+// summary of an impactReport.
+const reportSampleSize = 10
+
+// revokedCert is a certificate revoked by an admin command.
+type revokedCert struct {
+	Serial         string
+	RegistrationID int64
+	Reason         string
+}
+
+// impactReport records the changes made by an admin command or, in a dry run,
+// the changes which it would have made.
+type impactReport struct {
+	DryRun      bool
+	Revocations []revokedCert
+	BlockedKeys []string
+	Deactivated []int64
+
+	// FeatureOverrides holds the per-account feature overrides set or
+	// cleared.
+	FeatureOverrides []featureOverrideChange `json:",omitempty"`
+
+	// AllowlistChanges holds the registered domains added to or removed from
+	// accounts' allowlists.
+	AllowlistChanges []allowlistChange `json:",omitempty"`
+
+	// AccountResults holds the outcome for each account in a batch
+	// deactivation.
+	AccountResults []accountResult `json:",omitempty"`
+}
+
+// accounts returns the IDs of the registrations affected by the report's
+// revocations, deactivations, feature overrides and allowlist changes.
+func (r *impactReport) accounts() []int64 {
+	seen := make(map[int64]bool)
+	var ids []int64
+	add := func(id int64) {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	for _, rev := range r.Revocations {
+		add(rev.RegistrationID)
+	}
+	for _, id := range r.Deactivated {
+		add(id)
+	}
+	for _, change := range r.FeatureOverrides {
+		add(change.RegistrationID)
+	}
+	for _, change := range r.AllowlistChanges {
+		add(change.RegistrationID)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+// summarize writes the number of each kind of change in the report, and a
+// sample of them, to w.
+func (r *impactReport) summarize(w io.Writer) {
+	verb := "Made"
+	if r.DryRun {
+		verb = "Dry run, would have made"
+	}
+	fmt.Fprintf(w, "%s the following changes:\n", verb)
+	fmt.Fprintf(w, "  Certificates revoked: %d\n", len(r.Revocations))
+	for i, rev := range r.Revocations {
+		if i == reportSampleSize {
+			fmt.Fprintf(w, "    ... and %d more\n", len(r.Revocations)-i)
+			break
+		}
+		fmt.Fprintf(w, "    %s (registration %d, reason %s)\n", rev.Serial, rev.RegistrationID, rev.Reason)
+	}
+	fmt.Fprintf(w, "  Keys blocked: %d\n", len(r.BlockedKeys))
+	for i, key := range r.BlockedKeys {
+		if i == reportSampleSize {
+			fmt.Fprintf(w, "    ... and %d more\n", len(r.BlockedKeys)-i)
+			break
+		}
+		fmt.Fprintf(w, "    %s\n", key)
+	}
+	fmt.Fprintf(w, "  Registrations deactivated: %d\n", len(r.Deactivated))
+	for i, id := range r.Deactivated {
+		if i == reportSampleSize {
+			fmt.Fprintf(w, "    ... and %d more\n", len(r.Deactivated)-i)
+			break
+		}
+		fmt.Fprintf(w, "    %d\n", id)
+	}
+	if len(r.FeatureOverrides) > 0 {
+		fmt.Fprintf(w, "  Feature overrides changed: %d\n", len(r.FeatureOverrides))
+	}
+	for i, change := range r.FeatureOverrides {
+		if i == reportSampleSize {
+			fmt.Fprintf(w, "    ... and %d more\n", len(r.FeatureOverrides)-i)
+			break
+		}
+		setting := "cleared"
+		if change.Enabled != nil {
+			setting = fmt.Sprintf("set to %t", *change.Enabled)
+		}
+		fmt.Fprintf(w, "    %s for registration %d %s\n", change.Feature, change.RegistrationID, setting)
+	}
+	if len(r.AllowlistChanges) > 0 {
+		fmt.Fprintf(w, "  Allowlist entries changed: %d\n", len(r.AllowlistChanges))
+	}
+	for i, change := range r.AllowlistChanges {
+		if i == reportSampleSize {
+			fmt.Fprintf(w, "    ... and %d more\n", len(r.AllowlistChanges)-i)
+			break
+		}
+		if change.Cleared {
+			fmt.Fprintf(w, "    allowlist of registration %d cleared\n", change.RegistrationID)
+			continue
+		}
+		action := "removed from"
+		if change.Added {
+			action = "added to"
+		}
+		fmt.Fprintf(w, "    %s %s the allowlist of registration %d\n", change.Domain, action, change.RegistrationID)
+	}
+	fmt.Fprintf(w, "  Registrations affected: %d\n", len(r.accounts()))
+	if len(r.AccountResults) > 0 {
+		fmt.Fprintf(w, "Results by account:\n")
+	}
+	for _, result := range r.AccountResults {
+		outcome := "deactivated"
+		if result.AlreadyDeactivated {
+			outcome = "already deactivated"
+		}
+		if result.Error != "" {
+			outcome = "failed: " + result.Error
+		}
+		fmt.Fprintf(w, "  %d\t%s\t%d certificates revoked\n", result.ID, outcome, result.Revoked)
+	}
+}
+
+// writeFile writes the full report to path as JSON.
+func (r *impactReport) writeFile(path string) error {
+	reportJSON, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, reportJSON, 0600)
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jmhodges/clock"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/letsencrypt/boulder/cmd"
+	"github.com/letsencrypt/boulder/core"
+	blog "github.com/letsencrypt/boulder/log"
+)
+
+// purgeBackend is a CDN which can be asked to evict URLs from its cache.
+// akamai.CachePurgeClient and cdnClient both implement it.
+type purgeBackend interface {
+	Purge(urls []string) error
+}
+
+// cdnAPI makes purge requests to a particular CDN's API. Retries and rate
+// limiting are handled by the cdnClient wrapping it.
+type cdnAPI interface {
+	// send makes a single purge request for at most batchSize URLs.
+	send(urls []string) error
+	batchSize() int
+}
+
+// errFatalPurge is returned by a cdnAPI to indicate that a purge request
+// failed for a reason that cannot be remediated by retrying it.
+type errFatalPurge string
+
+func (e errFatalPurge) Error() string { return string(e) }
+
+// errPartialPurge is returned by a purgeBackend which purged the first purged
+// URLs it was given before failing to purge the rest, so that only the rest
+// need to be purged again.
+type errPartialPurge struct {
+	purged int
+	err    error
+}
+
+func (e errPartialPurge) Error() string {
+	return fmt.Sprintf("purged %d URLs before failing: %s", e.purged, e.err)
+}
+
+func (e errPartialPurge) Unwrap() error { return e.err }
+
+// backendPolicy holds the retry and rate limit settings of a single purge
+// backend, to be embedded in its config.
+type backendPolicy struct {
+	// PurgeRetries is the number of times a failed purge request is retried
+	// before the URLs in it are returned to the queue.
+	PurgeRetries int
+	// PurgeRetryBackoff is the base of the exponential backoff between
+	// retries.
+	PurgeRetryBackoff cmd.ConfigDuration
+	// RequestsPerSecond, if set, limits the rate at which purge requests are
+	// made to the backend.
+	RequestsPerSecond float64
+}
+
+// cdnClient implements purgeBackend on top of a cdnAPI, batching URLs,
+// limiting the request rate, and retrying failed requests according to its
+// backendPolicy. It is safe for concurrent use.
+type cdnClient struct {
+	mu          sync.Mutex
+	name        string
+	api         cdnAPI
+	retries     int
+	backoff     time.Duration
+	minInterval time.Duration
+	lastRequest time.Time
+	clk         clock.Clock
+	log         blog.Logger
+	purges      *prometheus.CounterVec
+}
+
+func newCDNClient(name string, api cdnAPI, policy backendPolicy, clk clock.Clock, log blog.Logger, purges *prometheus.CounterVec) *cdnClient {
+	var minInterval time.Duration
+	if policy.RequestsPerSecond > 0 {
+		minInterval = time.Duration(float64(time.Second) / policy.RequestsPerSecond)
+	}
+	return &cdnClient{
+		name:        name,
+		api:         api,
+		retries:     policy.PurgeRetries,
+		backoff:     policy.PurgeRetryBackoff.Duration,
+		minInterval: minInterval,
+		clk:         clk,
+		log:         log,
+		purges:      purges,
+	}
+}
+
+// throttle sleeps until a request can be made without exceeding the
+// configured request rate.
+func (cc *cdnClient) throttle() {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	if cc.minInterval == 0 {
+		return
+	}
+	if wait := cc.lastRequest.Add(cc.minInterval).Sub(cc.clk.Now()); wait > 0 {
+		cc.clk.Sleep(wait)
+	}
+	cc.lastRequest = cc.clk.Now()
+}
+
+func (cc *cdnClient) purgeBatch(urls []string) error {
+	var err error
+	for i := 0; i <= cc.retries; i++ {
+		cc.clk.Sleep(core.RetryBackoff(i, cc.backoff, time.Minute, 1.3))
+		cc.throttle()
+
+		err = cc.api.send(urls)
+		if err == nil {
+			cc.purges.WithLabelValues(cc.name, "success").Inc()
+			return nil
+		}
+		var errorFatal errFatalPurge
+		if errors.As(err, &errorFatal) {
+			break
+		}
+		cc.log.AuditErrf("%s cache purge failed, retrying: %s", cc.name, err)
+		cc.purges.WithLabelValues(cc.name, "retryable failure").Inc()
+	}
+	cc.purges.WithLabelValues(cc.name, "fatal failure").Inc()
+	return fmt.Errorf("%s cache purge failed: %w", cc.name, err)
+}
+
+// Purge sends purge requests for urls in batches, retrying each batch
+// according to the client's policy. It stops at the first batch which can't
+// be purged, returning an errPartialPurge if any batches before it were.
+func (cc *cdnClient) Purge(urls []string) error {
+	batchSize := cc.api.batchSize()
+	for i := 0; i < len(urls); i += batchSize {
+		end := i + batchSize
+		if end > len(urls) {
+			end = len(urls)
+		}
+		err := cc.purgeBatch(urls[i:end])
+		if err != nil {
+			if i > 0 {
+				return errPartialPurge{purged: i, err: err}
+			}
+			return err
+		}
+	}
+	return nil
+}
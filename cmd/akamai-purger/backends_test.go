@@ -0,0 +1,209 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jmhodges/clock"
+	"github.com/prometheus/client_golang/prometheus"
+
+	akamaipb "github.com/letsencrypt/boulder/akamai/proto"
+	"github.com/letsencrypt/boulder/cmd"
+	blog "github.com/letsencrypt/boulder/log"
+	"github.com/letsencrypt/boulder/test"
+	"github.com/letsencrypt/boulder/webhook"
+)
+
+// fakeAPI is a cdnAPI which records the batches it's sent and fails the
+// first failures of them. If failAfter is set, every batch after that many
+// have been sent fails fatally.
+type fakeAPI struct {
+	size      int
+	failures  int
+	fatal     bool
+	failAfter int
+	batches   [][]string
+}
+
+func (fa *fakeAPI) batchSize() int { return fa.size }
+
+func (fa *fakeAPI) send(urls []string) error {
+	if fa.failures > 0 {
+		fa.failures--
+		if fa.fatal {
+			return errFatalPurge("fatal")
+		}
+		return errors.New("retryable")
+	}
+	if fa.failAfter > 0 && len(fa.batches) >= fa.failAfter {
+		return errFatalPurge("fatal")
+	}
+	fa.batches = append(fa.batches, urls)
+	return nil
+}
+
+func newTestCDNClient(api cdnAPI, policy backendPolicy) (*cdnClient, *prometheus.CounterVec) {
+	purges := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "cdn_purges",
+		Help: "A counter of non-Akamai CDN purges labelled by backend and result",
+	}, []string{"backend", "type"})
+	return newCDNClient("Fake", api, policy, clock.NewFake(), blog.NewMock(), purges), purges
+}
+
+func TestCDNClientPurge(t *testing.T) {
+	api := &fakeAPI{size: 2, failures: 2}
+	cc, purges := newTestCDNClient(api, backendPolicy{PurgeRetries: 2, PurgeRetryBackoff: cmd.ConfigDuration{Duration: time.Second}})
+
+	err := cc.Purge([]string{"a", "b", "c"})
+	test.AssertNotError(t, err, "Purge failed")
+	test.AssertDeepEquals(t, api.batches, [][]string{{"a", "b"}, {"c"}})
+	test.AssertEquals(t, test.CountCounter(purges.WithLabelValues("Fake", "retryable failure")), 2)
+	test.AssertEquals(t, test.CountCounter(purges.WithLabelValues("Fake", "success")), 2)
+
+	// Retries are exhausted.
+	api = &fakeAPI{size: 2, failures: 3}
+	cc, _ = newTestCDNClient(api, backendPolicy{PurgeRetries: 2})
+	err = cc.Purge([]string{"a"})
+	test.AssertError(t, err, "Purge didn't fail after exhausting retries")
+
+	// Fatal errors aren't retried.
+	api = &fakeAPI{size: 2, failures: 1, fatal: true}
+	cc, _ = newTestCDNClient(api, backendPolicy{PurgeRetries: 2})
+	err = cc.Purge([]string{"a"})
+	test.AssertError(t, err, "Purge didn't fail on a fatal error")
+	test.AssertEquals(t, len(api.batches), 0)
+	var partial errPartialPurge
+	test.Assert(t, !errors.As(err, &partial), "Purge reported a partial purge when nothing was purged")
+
+	// A failure after some batches were purged reports how many were.
+	api = &fakeAPI{size: 2, failAfter: 1}
+	cc, _ = newTestCDNClient(api, backendPolicy{})
+	err = cc.Purge([]string{"a", "b", "c"})
+	test.AssertError(t, err, "Purge didn't fail")
+	test.Assert(t, errors.As(err, &partial), "Purge didn't report a partial purge")
+	test.AssertEquals(t, partial.purged, 2)
+}
+
+func TestCDNClientThrottle(t *testing.T) {
+	api := &fakeAPI{size: 1}
+	cc, _ := newTestCDNClient(api, backendPolicy{RequestsPerSecond: 2})
+	fc := cc.clk.(clock.FakeClock)
+	start := fc.Now()
+
+	err := cc.Purge([]string{"a", "b", "c"})
+	test.AssertNotError(t, err, "Purge failed")
+	test.AssertEquals(t, len(api.batches), 3)
+	// The first request is made immediately, each following one waits half a
+	// second.
+	test.AssertEquals(t, fc.Now().Sub(start), time.Second)
+}
+
+func TestPurgeFansOut(t *testing.T) {
+	good := &fakeAPI{size: 10}
+	bad := &fakeAPI{size: 10, failures: 1, fatal: true}
+	goodClient, _ := newTestCDNClient(good, backendPolicy{})
+	badClient, _ := newTestCDNClient(bad, backendPolicy{})
+	log := blog.NewMock()
+	ap := &akamaiPurger{
+		queues: []*purgeQueue{
+			{name: "good", backend: goodClient, clk: clock.NewFake(), log: log},
+			{name: "bad", backend: badClient, clk: clock.NewFake(), log: log},
+		},
+		log: log,
+	}
+
+	err := ap.purge()
+	test.AssertNotError(t, err, "purging empty queues failed")
+
+	_, err = ap.Purge(context.Background(), &akamaipb.PurgeRequest{Urls: []string{"a", "b"}})
+	test.AssertNotError(t, err, "Purge failed")
+	test.AssertEquals(t, ap.len(), 4)
+
+	// A failing backend keeps its URLs queued without affecting the others.
+	err = ap.purge()
+	test.AssertError(t, err, "purge didn't report the failing backend")
+	test.AssertDeepEquals(t, good.batches, [][]string{{"a", "b"}})
+	test.AssertEquals(t, ap.queues[0].len(), 0)
+	test.AssertEquals(t, ap.queues[1].len(), 2)
+
+	err = ap.purge()
+	test.AssertNotError(t, err, "purge failed")
+	test.AssertDeepEquals(t, bad.batches, [][]string{{"a", "b"}})
+	test.AssertEquals(t, len(good.batches), 1)
+	test.AssertEquals(t, ap.len(), 0)
+}
+
+func TestFastlySend(t *testing.T) {
+	var gotPath, gotKey string
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotKey = r.Header.Get("Fastly-Key")
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	api := newFastlyAPI(server.URL, "fastly-key")
+	err := api.send([]string{"http://ocsp.example.com/MFQwUjBQ"})
+	test.AssertNotError(t, err, "send failed")
+	test.AssertEquals(t, gotPath, "/purge/ocsp.example.com/MFQwUjBQ")
+	test.AssertEquals(t, gotKey, "fastly-key")
+
+	status = http.StatusForbidden
+	err = api.send([]string{"http://ocsp.example.com/MFQwUjBQ"})
+	var errorFatal errFatalPurge
+	test.Assert(t, errors.As(err, &errorFatal), "expected a fatal error for a 403")
+}
+
+func TestCloudflareSend(t *testing.T) {
+	var gotPath, gotAuth string
+	var gotReq cloudflarePurgeRequest
+	resp := `{"success": true, "errors": []}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotAuth = r.Header.Get("Authorization")
+		body, _ := ioutil.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &gotReq)
+		_, _ = w.Write([]byte(resp))
+	}))
+	defer server.Close()
+
+	api := newCloudflareAPI(server.URL, "zone", "cf-token")
+	err := api.send([]string{"http://ocsp.example.com/a", "http://ocsp.example.com/b"})
+	test.AssertNotError(t, err, "send failed")
+	test.AssertEquals(t, gotPath, "/zones/zone/purge_cache")
+	test.AssertEquals(t, gotAuth, "Bearer cf-token")
+	test.AssertDeepEquals(t, gotReq.Files, []string{"http://ocsp.example.com/a", "http://ocsp.example.com/b"})
+
+	resp = `{"success": false, "errors": [{"code": 1234, "message": "nope"}]}`
+	err = api.send([]string{"http://ocsp.example.com/a"})
+	test.AssertError(t, err, "send didn't fail when the purge was unsuccessful")
+}
+
+func TestWebhookSend(t *testing.T) {
+	fc := clock.NewFake()
+	secret := []byte("webhook-secret")
+	var gotSignature string
+	var gotBody []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotSignature = r.Header.Get(webhook.SignatureHeader)
+		gotBody, _ = ioutil.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	api := newWebhookAPI(server.URL, secret, fc)
+	err := api.send([]string{"http://ocsp.example.com/a"})
+	test.AssertNotError(t, err, "send failed")
+	test.AssertEquals(t, gotSignature, webhook.Sign(secret, fc.Now(), gotBody))
+	var req webhookPurgeRequest
+	err = json.Unmarshal(gotBody, &req)
+	test.AssertNotError(t, err, "unmarshaling webhook body")
+	test.AssertDeepEquals(t, req.URLs, []string{"http://ocsp.example.com/a"})
+}
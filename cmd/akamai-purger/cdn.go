@@ -0,0 +1,193 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/jmhodges/clock"
+
+	"github.com/letsencrypt/boulder/webhook"
+)
+
+const (
+	// purgeRequestTimeout bounds each request to a CDN API.
+	purgeRequestTimeout = 30 * time.Second
+
+	fastlyBaseURL     = "https://api.fastly.com"
+	cloudflareBaseURL = "https://api.cloudflare.com/client/v4"
+
+	// cloudflareBatchSize is the maximum number of files Cloudflare accepts in
+	// a single purge request.
+	cloudflareBatchSize = 30
+	webhookBatchSize    = 100
+)
+
+// doPurgeRequest sends req and returns the response status and body. Auth
+// failures are fatal, since retrying them won't help.
+func doPurgeRequest(client *http.Client, req *http.Request) (int, []byte, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return 0, nil, err
+	}
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		return 0, nil, errFatalPurge(fmt.Sprintf("unauthorized to purge URLs: %d %s", resp.StatusCode, body))
+	}
+	return resp.StatusCode, body, nil
+}
+
+// fastlyAPI purges URLs using the Fastly single URL purge API, which accepts
+// one URL per request.
+type fastlyAPI struct {
+	client  *http.Client
+	baseURL string
+	apiKey  string
+}
+
+func newFastlyAPI(baseURL, apiKey string) *fastlyAPI {
+	if baseURL == "" {
+		baseURL = fastlyBaseURL
+	}
+	return &fastlyAPI{
+		client:  &http.Client{Timeout: purgeRequestTimeout},
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		apiKey:  apiKey,
+	}
+}
+
+func (fa *fastlyAPI) batchSize() int { return 1 }
+
+func (fa *fastlyAPI) send(urls []string) error {
+	// The URL to purge is given without its scheme, as a path suffix.
+	target := urls[0]
+	if i := strings.Index(target, "://"); i != -1 {
+		target = target[i+3:]
+	}
+	req, err := http.NewRequest("POST", fa.baseURL+"/purge/"+target, nil)
+	if err != nil {
+		return errFatalPurge(err.Error())
+	}
+	req.Header.Set("Fastly-Key", fa.apiKey)
+	req.Header.Set("Accept", "application/json")
+	status, body, err := doPurgeRequest(fa.client, req)
+	if err != nil {
+		return err
+	}
+	if status != http.StatusOK {
+		return fmt.Errorf("unexpected HTTP status code %d: %s", status, body)
+	}
+	return nil
+}
+
+// cloudflareAPI purges URLs from a single Cloudflare zone.
+type cloudflareAPI struct {
+	client   *http.Client
+	endpoint string
+	apiToken string
+}
+
+type cloudflarePurgeRequest struct {
+	Files []string `json:"files"`
+}
+
+type cloudflarePurgeResponse struct {
+	Success bool `json:"success"`
+	Errors  []struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+func newCloudflareAPI(baseURL, zoneID, apiToken string) *cloudflareAPI {
+	if baseURL == "" {
+		baseURL = cloudflareBaseURL
+	}
+	return &cloudflareAPI{
+		client:   &http.Client{Timeout: purgeRequestTimeout},
+		endpoint: fmt.Sprintf("%s/zones/%s/purge_cache", strings.TrimSuffix(baseURL, "/"), url.PathEscape(zoneID)),
+		apiToken: apiToken,
+	}
+}
+
+func (ca *cloudflareAPI) batchSize() int { return cloudflareBatchSize }
+
+func (ca *cloudflareAPI) send(urls []string) error {
+	reqJSON, err := json.Marshal(cloudflarePurgeRequest{Files: urls})
+	if err != nil {
+		return errFatalPurge(err.Error())
+	}
+	req, err := http.NewRequest("POST", ca.endpoint, bytes.NewReader(reqJSON))
+	if err != nil {
+		return errFatalPurge(err.Error())
+	}
+	req.Header.Set("Authorization", "Bearer "+ca.apiToken)
+	req.Header.Set("Content-Type", "application/json")
+	status, body, err := doPurgeRequest(ca.client, req)
+	if err != nil {
+		return err
+	}
+	var purgeResp cloudflarePurgeResponse
+	err = json.Unmarshal(body, &purgeResp)
+	if err != nil {
+		return fmt.Errorf("%s. Body was: %s", err, body)
+	}
+	if status != http.StatusOK || !purgeResp.Success {
+		return fmt.Errorf("unexpected HTTP status code %d: %s", status, body)
+	}
+	return nil
+}
+
+// webhookAPI POSTs the URLs to purge to an arbitrary endpoint, signed in the
+// same way as the expiration-mailer's webhook notifications.
+type webhookAPI struct {
+	client *http.Client
+	url    string
+	secret []byte
+	clk    clock.Clock
+}
+
+type webhookPurgeRequest struct {
+	URLs []string `json:"urls"`
+}
+
+func newWebhookAPI(url string, secret []byte, clk clock.Clock) *webhookAPI {
+	return &webhookAPI{
+		client: &http.Client{Timeout: purgeRequestTimeout},
+		url:    url,
+		secret: secret,
+		clk:    clk,
+	}
+}
+
+func (wa *webhookAPI) batchSize() int { return webhookBatchSize }
+
+func (wa *webhookAPI) send(urls []string) error {
+	reqJSON, err := json.Marshal(webhookPurgeRequest{URLs: urls})
+	if err != nil {
+		return errFatalPurge(err.Error())
+	}
+	req, err := http.NewRequest("POST", wa.url, bytes.NewReader(reqJSON))
+	if err != nil {
+		return errFatalPurge(err.Error())
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(webhook.SignatureHeader, webhook.Sign(wa.secret, wa.clk.Now(), reqJSON))
+	status, body, err := doPurgeRequest(wa.client, req)
+	if err != nil {
+		return err
+	}
+	if status < 200 || status > 299 {
+		return fmt.Errorf("unexpected HTTP status code %d: %s", status, body)
+	}
+	return nil
+}
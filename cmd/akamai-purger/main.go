@@ -6,9 +6,12 @@ import (
 	"flag"
 	"fmt"
 	"os"
-	"sync"
+	"path/filepath"
+	"strings"
 	"time"
 
+	"github.com/jmhodges/clock"
+	"github.com/prometheus/client_golang/prometheus"
 	"google.golang.org/grpc/health"
 	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 
@@ -27,6 +30,15 @@ type config struct {
 		// PurgeInterval is how often we will send a purge request
 		PurgeInterval cmd.ConfigDuration
 
+		// QueueDir, if set, is a directory in which each backend's purge queue
+		// is journaled, so that queued purges survive a restart.
+		QueueDir string
+		// InvalidationDeadline is how long a URL may wait to be purged before
+		// it's reported as overdue. Defaults to 24h, the deadline for
+		// publishing revocation information.
+		InvalidationDeadline cmd.ConfigDuration
+
+		// The Akamai backend is used if BaseURL is set.
 		BaseURL           string
 		ClientToken       string
 		ClientSecret      string
@@ -34,55 +46,109 @@ type config struct {
 		V3Network         string
 		PurgeRetries      int
 		PurgeRetryBackoff cmd.ConfigDuration
+
+		// Fastly, if present, purges URLs from Fastly using the given API key.
+		// BaseURL defaults to the Fastly API.
+		Fastly *struct {
+			backendPolicy
+			BaseURL string
+			APIKey  cmd.PasswordConfig
+		}
+		// Cloudflare, if present, purges URLs from the given Cloudflare zone
+		// using the given API token. BaseURL defaults to the Cloudflare API.
+		Cloudflare *struct {
+			backendPolicy
+			BaseURL  string
+			ZoneID   string
+			APIToken cmd.PasswordConfig
+		}
+		// Webhook, if present, POSTs the URLs to purge to URL as a JSON object,
+		// signed with Secret in the Boulder-Signature header.
+		Webhook *struct {
+			backendPolicy
+			URL    string
+			Secret cmd.PasswordConfig
+		}
 	}
 	Syslog cmd.SyslogConfig
 }
 
+// akamaiPurger fans each purge request out to the queues of all configured
+// backends.
 type akamaiPurger struct {
-	mu      sync.Mutex
-	toPurge []string
-
-	client *akamai.CachePurgeClient
+	queues []*purgeQueue
 	log    blog.Logger
+
+	clk          clock.Clock
+	deadline     time.Duration
+	queueDepth   *prometheus.GaugeVec
+	queueAge     *prometheus.GaugeVec
+	queueOverdue *prometheus.GaugeVec
+}
+
+// checkQueues updates the queue metrics of each backend, and logs an error
+// when a backend's queue first contains URLs which have waited longer than
+// the invalidation deadline.
+func (ap *akamaiPurger) checkQueues() {
+	now := ap.clk.Now()
+	for _, q := range ap.queues {
+		ap.queueDepth.WithLabelValues(q.name).Set(float64(q.len()))
+		var age time.Duration
+		if oldest := q.oldest(); !oldest.IsZero() {
+			age = now.Sub(oldest)
+		}
+		ap.queueAge.WithLabelValues(q.name).Set(age.Seconds())
+		overdue := q.olderThan(now.Add(-ap.deadline))
+		ap.queueOverdue.WithLabelValues(q.name).Set(float64(overdue))
+		if overdue > 0 && !q.overdue {
+			ap.log.AuditErrf("%d URLs have been waiting to be purged from %s for more than %s", overdue, q.name, ap.deadline)
+		}
+		q.overdue = overdue > 0
+	}
 }
 
+// len returns the number of URLs waiting to be purged, summed across all
+// backends.
 func (ap *akamaiPurger) len() int {
-	ap.mu.Lock()
-	defer ap.mu.Unlock()
-	return len(ap.toPurge)
+	var total int
+	for _, q := range ap.queues {
+		total += q.len()
+	}
+	return total
 }
 
+// purge empties the queues of all backends concurrently, returning an error
+// if any of them failed.
 func (ap *akamaiPurger) purge() error {
-	ap.mu.Lock()
-	urls := ap.toPurge[:]
-	ap.toPurge = []string{}
-	ap.mu.Unlock()
-	if len(urls) == 0 {
-		return nil
-	}
-
-	if err := ap.client.Purge(urls); err != nil {
-		// Add the URLs back to the queue
-		ap.mu.Lock()
-		ap.toPurge = append(urls, ap.toPurge...)
-		ap.mu.Unlock()
-		ap.log.Errf("Failed to purge %d URLs: %s", len(urls), err)
-		return err
-	}
-	return nil
+	errs := make(chan error, len(ap.queues))
+	for _, q := range ap.queues {
+		go func(q *purgeQueue) {
+			errs <- q.purge()
+		}(q)
+	}
+	var firstErr error
+	for range ap.queues {
+		if err := <-errs; err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
 }
 
-// maxQueueSize is used to reject Purge requests if the queue contains
+// maxQueueSize is used to reject Purge requests if a backend's queue contains
 // >= the number of URLs to purge so that it can catch up.
 var maxQueueSize = 1000000
 
 func (ap *akamaiPurger) Purge(ctx context.Context, req *akamaipb.PurgeRequest) (*corepb.Empty, error) {
-	ap.mu.Lock()
-	defer ap.mu.Unlock()
-	if len(ap.toPurge) >= maxQueueSize {
-		return nil, errors.New("Akamai purge queue too large")
+	var errs []string
+	for _, q := range ap.queues {
+		if err := q.add(req.Urls); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errors.New(strings.Join(errs, "; "))
 	}
-	ap.toPurge = append(ap.toPurge, req.Urls...)
 	return &corepb.Empty{}, nil
 }
 
@@ -90,6 +156,7 @@ func main() {
 	grpcAddr := flag.String("addr", "", "gRPC listen address override")
 	debugAddr := flag.String("debug-addr", "", "Debug server address override")
 	configFile := flag.String("config", "", "File path to the configuration file for this service")
+	checkConfig := cmd.CheckConfigFlag()
 	flag.Parse()
 	if *configFile == "" {
 		flag.Usage()
@@ -99,6 +166,9 @@ func main() {
 	var c config
 	err := cmd.ReadConfigFile(*configFile, &c)
 	cmd.FailOnError(err, "Reading JSON config file into config structure")
+	if *checkConfig {
+		cmd.CheckConfig(&c)
+	}
 
 	if *grpcAddr != "" {
 		c.AkamaiPurger.GRPC.Address = *grpcAddr
@@ -120,22 +190,86 @@ func main() {
 		cmd.Fail("PurgeInterval must be > 0")
 	}
 
-	ccu, err := akamai.NewCachePurgeClient(
-		c.AkamaiPurger.BaseURL,
-		c.AkamaiPurger.ClientToken,
-		c.AkamaiPurger.ClientSecret,
-		c.AkamaiPurger.AccessToken,
-		c.AkamaiPurger.V3Network,
-		c.AkamaiPurger.PurgeRetries,
-		c.AkamaiPurger.PurgeRetryBackoff.Duration,
-		logger,
-		scope,
-	)
-	cmd.FailOnError(err, "Failed to setup Akamai CCU client")
-
+	if c.AkamaiPurger.InvalidationDeadline.Duration == 0 {
+		c.AkamaiPurger.InvalidationDeadline.Duration = 24 * time.Hour
+	}
 	ap := akamaiPurger{
-		client: ccu,
-		log:    logger,
+		log:      logger,
+		clk:      clk,
+		deadline: c.AkamaiPurger.InvalidationDeadline.Duration,
+		queueDepth: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "purge_queue_depth",
+			Help: "The number of URLs waiting to be purged, labelled by backend",
+		}, []string{"backend"}),
+		queueAge: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "purge_queue_oldest_seconds",
+			Help: "How long the oldest URL waiting to be purged has been queued, labelled by backend",
+		}, []string{"backend"}),
+		queueOverdue: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "purge_queue_overdue",
+			Help: "The number of URLs which have waited longer than the invalidation deadline to be purged, labelled by backend",
+		}, []string{"backend"}),
+	}
+	scope.MustRegister(ap.queueDepth, ap.queueAge, ap.queueOverdue)
+	addBackend := func(name string, backend purgeBackend) {
+		q := &purgeQueue{name: name, backend: backend, clk: clk, log: logger}
+		if c.AkamaiPurger.QueueDir != "" {
+			path := filepath.Join(c.AkamaiPurger.QueueDir, strings.ToLower(name)+".journal")
+			j, entries, err := openJournal(path)
+			cmd.FailOnError(err, fmt.Sprintf("Failed to open %s purge journal", name))
+			q.journal = j
+			q.toPurge = entries
+			logger.Infof("Replayed %d queued URLs for %s from %s", len(entries), name, path)
+		}
+		ap.queues = append(ap.queues, q)
+	}
+	cdnPurges := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "cdn_purges",
+		Help: "A counter of non-Akamai CDN purges labelled by backend and result",
+	}, []string{"backend", "type"})
+	scope.MustRegister(cdnPurges)
+
+	if c.AkamaiPurger.BaseURL != "" {
+		ccu, err := akamai.NewCachePurgeClient(
+			c.AkamaiPurger.BaseURL,
+			c.AkamaiPurger.ClientToken,
+			c.AkamaiPurger.ClientSecret,
+			c.AkamaiPurger.AccessToken,
+			c.AkamaiPurger.V3Network,
+			c.AkamaiPurger.PurgeRetries,
+			c.AkamaiPurger.PurgeRetryBackoff.Duration,
+			logger,
+			scope,
+		)
+		cmd.FailOnError(err, "Failed to setup Akamai CCU client")
+		addBackend("Akamai", ccu)
+	}
+	if conf := c.AkamaiPurger.Fastly; conf != nil {
+		apiKey, err := conf.APIKey.Pass()
+		cmd.FailOnError(err, "Failed to load Fastly API key")
+		api := newFastlyAPI(conf.BaseURL, apiKey)
+		addBackend("Fastly", newCDNClient("Fastly", api, conf.backendPolicy, clk, logger, cdnPurges))
+	}
+	if conf := c.AkamaiPurger.Cloudflare; conf != nil {
+		if conf.ZoneID == "" {
+			cmd.Fail("Cloudflare ZoneID must be configured")
+		}
+		apiToken, err := conf.APIToken.Pass()
+		cmd.FailOnError(err, "Failed to load Cloudflare API token")
+		api := newCloudflareAPI(conf.BaseURL, conf.ZoneID, apiToken)
+		addBackend("Cloudflare", newCDNClient("Cloudflare", api, conf.backendPolicy, clk, logger, cdnPurges))
+	}
+	if conf := c.AkamaiPurger.Webhook; conf != nil {
+		if conf.URL == "" {
+			cmd.Fail("Webhook URL must be configured")
+		}
+		secret, err := conf.Secret.Pass()
+		cmd.FailOnError(err, "Failed to load webhook secret")
+		api := newWebhookAPI(conf.URL, []byte(secret), clk)
+		addBackend("Webhook", newCDNClient("Webhook", api, conf.backendPolicy, clk, logger, cdnPurges))
+	}
+	if len(ap.queues) == 0 {
+		cmd.Fail("At least one purge backend must be configured")
 	}
 
 	stop, stopped := make(chan bool, 1), make(chan bool, 1)
@@ -146,6 +280,7 @@ func main() {
 			select {
 			case <-ticker.C:
 				_ = ap.purge()
+				ap.checkQueues()
 			case <-stop:
 				break loop
 			}
@@ -0,0 +1,267 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/jmhodges/clock"
+
+	blog "github.com/letsencrypt/boulder/log"
+)
+
+// compactThreshold is the number of records after which a journal is
+// rewritten to contain only the URLs still queued.
+const compactThreshold = 10000
+
+// queuedURL is a URL waiting to be purged, and when it was queued.
+type queuedURL struct {
+	url   string
+	added time.Time
+}
+
+// purgeQueue holds the URLs waiting to be purged from a single backend, so
+// that an outage of one backend doesn't hold up, or cause repeated purges
+// of, the others. If it has a journal, the queue survives restarts.
+type purgeQueue struct {
+	mu      sync.Mutex
+	toPurge []queuedURL
+	journal *journal
+	overdue bool
+
+	name    string
+	backend purgeBackend
+	clk     clock.Clock
+	log     blog.Logger
+}
+
+func (pq *purgeQueue) len() int {
+	pq.mu.Lock()
+	defer pq.mu.Unlock()
+	return len(pq.toPurge)
+}
+
+// oldest returns the time the longest-queued URL was added, or the zero time
+// if the queue is empty.
+func (pq *purgeQueue) oldest() time.Time {
+	pq.mu.Lock()
+	defer pq.mu.Unlock()
+	if len(pq.toPurge) == 0 {
+		return time.Time{}
+	}
+	return pq.toPurge[0].added
+}
+
+// olderThan returns the number of URLs which were queued before cutoff.
+func (pq *purgeQueue) olderThan(cutoff time.Time) int {
+	pq.mu.Lock()
+	defer pq.mu.Unlock()
+	// The queue is ordered by the time URLs were added.
+	var n int
+	for n < len(pq.toPurge) && pq.toPurge[n].added.Before(cutoff) {
+		n++
+	}
+	return n
+}
+
+// add appends urls to the queue, unless it's already full. If the queue has
+// a journal, the URLs are only added once they've been written to it.
+func (pq *purgeQueue) add(urls []string) error {
+	pq.mu.Lock()
+	defer pq.mu.Unlock()
+	if len(pq.toPurge) >= maxQueueSize {
+		return fmt.Errorf("%s purge queue too large", pq.name)
+	}
+	now := pq.clk.Now()
+	if pq.journal != nil {
+		err := pq.journal.append(journalRecord{Added: now.UnixNano(), URLs: urls})
+		if err != nil {
+			return fmt.Errorf("writing %s purge journal: %w", pq.name, err)
+		}
+	}
+	for _, u := range urls {
+		pq.toPurge = append(pq.toPurge, queuedURL{url: u, added: now})
+	}
+	return nil
+}
+
+func (pq *purgeQueue) purge() error {
+	pq.mu.Lock()
+	entries := pq.toPurge[:]
+	pq.toPurge = []queuedURL{}
+	pq.mu.Unlock()
+	if len(entries) == 0 {
+		return nil
+	}
+
+	urls := make([]string, len(entries))
+	for i, e := range entries {
+		urls[i] = e.url
+	}
+	purged := len(entries)
+	err := pq.backend.Purge(urls)
+	if err != nil {
+		purged = 0
+		var partial errPartialPurge
+		if errors.As(err, &partial) {
+			purged = partial.purged
+		}
+		pq.log.Errf("Failed to purge %d URLs from %s: %s", len(urls)-purged, pq.name, err)
+	}
+
+	pq.mu.Lock()
+	defer pq.mu.Unlock()
+	if purged < len(entries) {
+		// Add the URLs which weren't purged back to the queue
+		pq.toPurge = append(entries[purged:], pq.toPurge...)
+	}
+	if pq.journal != nil && purged > 0 {
+		// Failing to record the purge only means the URLs will be purged again
+		// after a restart.
+		var journalErr error
+		if len(pq.toPurge) == 0 || pq.journal.records >= compactThreshold {
+			journalErr = pq.journal.compact(pq.toPurge)
+		} else {
+			journalErr = pq.journal.append(journalRecord{Purged: purged})
+		}
+		if journalErr != nil {
+			pq.log.Errf("Failed to record purge in %s purge journal: %s", pq.name, journalErr)
+		}
+	}
+	return err
+}
+
+// journalRecord is a single line of a purge journal. It either records URLs
+// being added to the end of the queue, or the given number of URLs being
+// purged from the start of it.
+type journalRecord struct {
+	Added  int64    `json:"added,omitempty"` // Unix timestamp (nanoseconds)
+	URLs   []string `json:"urls,omitempty"`
+	Purged int      `json:"purged,omitempty"`
+}
+
+// journal is an append-only log of changes to a purgeQueue, which is replayed
+// on startup. It is not safe for concurrent use.
+type journal struct {
+	path    string
+	f       *os.File
+	records int
+}
+
+// openJournal opens the journal at path, creating it if necessary, and
+// returns the URLs still queued according to it.
+func openJournal(path string) (*journal, []queuedURL, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, nil, err
+	}
+	var entries []queuedURL
+	var records int
+	r := bufio.NewReader(f)
+	for {
+		line, err := r.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			// A partial final line is the result of a crash part way through
+			// writing it, which means the record was never acknowledged.
+			break
+		}
+		if err != nil {
+			_ = f.Close()
+			return nil, nil, err
+		}
+		var rec journalRecord
+		err = json.Unmarshal(line, &rec)
+		if err != nil {
+			_ = f.Close()
+			return nil, nil, fmt.Errorf("reading purge journal %s: %w", path, err)
+		}
+		records++
+		if rec.Purged > len(entries) {
+			rec.Purged = len(entries)
+		}
+		entries = entries[rec.Purged:]
+		for _, u := range rec.URLs {
+			entries = append(entries, queuedURL{url: u, added: time.Unix(0, rec.Added)})
+		}
+	}
+	j := &journal{path: path, f: f, records: records}
+	// Rewrite the journal, both to drop any partial final line and so that it
+	// starts out compacted.
+	err = j.compact(entries)
+	if err != nil {
+		_ = f.Close()
+		return nil, nil, err
+	}
+	return j, entries, nil
+}
+
+// append writes rec to the journal, returning once it has been synced to
+// disk.
+func (j *journal) append(rec journalRecord) error {
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	_, err = j.f.Write(append(line, '\n'))
+	if err != nil {
+		return err
+	}
+	j.records++
+	return j.f.Sync()
+}
+
+// compact atomically replaces the journal with one containing only entries.
+func (j *journal) compact(entries []queuedURL) error {
+	tmpPath := j.path + ".tmp"
+	tmp, err := os.OpenFile(tmpPath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(tmp)
+	var records int
+	for i := 0; i < len(entries); {
+		// Group consecutive URLs added at the same time into one record.
+		added := entries[i].added
+		rec := journalRecord{Added: added.UnixNano()}
+		for i < len(entries) && entries[i].added.Equal(added) {
+			rec.URLs = append(rec.URLs, entries[i].url)
+			i++
+		}
+		line, err := json.Marshal(rec)
+		if err != nil {
+			_ = tmp.Close()
+			return err
+		}
+		_, err = w.Write(append(line, '\n'))
+		if err != nil {
+			_ = tmp.Close()
+			return err
+		}
+		records++
+	}
+	err = w.Flush()
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if err == nil {
+		err = os.Rename(tmpPath, j.path)
+	}
+	if err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	// tmp is positioned at its end, so further records are appended to it.
+	_ = j.f.Close()
+	j.f = tmp
+	j.records = records
+	return nil
+}
+
+func (j *journal) close() error {
+	return j.f.Close()
+}
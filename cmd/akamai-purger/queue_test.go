@@ -0,0 +1,142 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jmhodges/clock"
+	"github.com/prometheus/client_golang/prometheus"
+
+	blog "github.com/letsencrypt/boulder/log"
+	"github.com/letsencrypt/boulder/test"
+)
+
+func queuedURLs(entries []queuedURL) []string {
+	var urls []string
+	for _, e := range entries {
+		urls = append(urls, e.url)
+	}
+	return urls
+}
+
+func TestJournalReplay(t *testing.T) {
+	dir, err := ioutil.TempDir("", "purge-journal")
+	test.AssertNotError(t, err, "creating temp dir")
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "fake.journal")
+	fc := clock.NewFake()
+
+	j, entries, err := openJournal(path)
+	test.AssertNotError(t, err, "opening new journal")
+	test.AssertEquals(t, len(entries), 0)
+
+	api := &fakeAPI{size: 10, failures: 1}
+	backend, _ := newTestCDNClient(api, backendPolicy{})
+	q := &purgeQueue{name: "Fake", backend: backend, journal: j, clk: fc, log: blog.NewMock()}
+	test.AssertNotError(t, q.add([]string{"a", "b"}), "add failed")
+	fc.Add(time.Minute)
+	test.AssertNotError(t, q.add([]string{"c"}), "add failed")
+
+	// A failed purge leaves the journal untouched.
+	test.AssertError(t, q.purge(), "purge didn't fail")
+	test.AssertNotError(t, j.close(), "closing journal")
+	j, entries, err = openJournal(path)
+	test.AssertNotError(t, err, "reopening journal")
+	test.AssertDeepEquals(t, queuedURLs(entries), []string{"a", "b", "c"})
+	test.Assert(t, entries[0].added.Equal(fc.Now().Add(-time.Minute)), "wrong added time after replay")
+	test.Assert(t, entries[2].added.Equal(fc.Now()), "wrong added time after replay")
+
+	// Purged URLs aren't replayed, and URLs added afterwards are.
+	q = &purgeQueue{name: "Fake", backend: backend, journal: j, toPurge: entries, clk: fc, log: blog.NewMock()}
+	test.AssertNotError(t, q.purge(), "purge failed")
+	test.AssertNotError(t, q.add([]string{"d"}), "add failed")
+	test.AssertNotError(t, q.add([]string{"e"}), "add failed")
+	test.AssertNotError(t, j.append(journalRecord{Purged: 1}), "append failed")
+	test.AssertNotError(t, j.close(), "closing journal")
+
+	// A partially written final record is ignored.
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0600)
+	test.AssertNotError(t, err, "opening journal for append")
+	_, err = f.Write([]byte(`{"added":1,"urls":["f"`))
+	test.AssertNotError(t, err, "writing partial record")
+	test.AssertNotError(t, f.Close(), "closing journal")
+
+	j, entries, err = openJournal(path)
+	test.AssertNotError(t, err, "reopening journal")
+	test.AssertDeepEquals(t, queuedURLs(entries), []string{"e"})
+	test.AssertEquals(t, j.records, 1)
+	test.AssertNotError(t, j.close(), "closing journal")
+}
+
+func TestPartialPurge(t *testing.T) {
+	dir, err := ioutil.TempDir("", "purge-journal")
+	test.AssertNotError(t, err, "creating temp dir")
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "fake.journal")
+	fc := clock.NewFake()
+
+	j, _, err := openJournal(path)
+	test.AssertNotError(t, err, "opening new journal")
+	api := &fakeAPI{size: 2, failAfter: 1}
+	backend, _ := newTestCDNClient(api, backendPolicy{})
+	q := &purgeQueue{name: "Fake", backend: backend, journal: j, clk: fc, log: blog.NewMock()}
+	test.AssertNotError(t, q.add([]string{"a", "b", "c"}), "add failed")
+
+	// Only the URLs which weren't purged are queued again, and recorded as
+	// such in the journal.
+	test.AssertError(t, q.purge(), "purge didn't fail")
+	test.AssertDeepEquals(t, queuedURLs(q.toPurge), []string{"c"})
+	test.AssertNotError(t, j.close(), "closing journal")
+	_, entries, err := openJournal(path)
+	test.AssertNotError(t, err, "reopening journal")
+	test.AssertDeepEquals(t, queuedURLs(entries), []string{"c"})
+}
+
+func TestCheckQueues(t *testing.T) {
+	fc := clock.NewFake()
+	log := blog.NewMock()
+	q := &purgeQueue{name: "Fake", clk: fc, log: log}
+	ap := &akamaiPurger{
+		queues:   []*purgeQueue{q},
+		log:      log,
+		clk:      fc,
+		deadline: 24 * time.Hour,
+		queueDepth: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "purge_queue_depth",
+		}, []string{"backend"}),
+		queueAge: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "purge_queue_oldest_seconds",
+		}, []string{"backend"}),
+		queueOverdue: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "purge_queue_overdue",
+		}, []string{"backend"}),
+	}
+	labels := prometheus.Labels{"backend": "Fake"}
+
+	test.AssertNotError(t, q.add([]string{"a", "b"}), "add failed")
+	fc.Add(12 * time.Hour)
+	test.AssertNotError(t, q.add([]string{"c"}), "add failed")
+	ap.checkQueues()
+	depth, err := test.GaugeValueWithLabels(ap.queueDepth, labels)
+	test.AssertNotError(t, err, "getting queue depth")
+	test.AssertEquals(t, depth, 3)
+	age, err := test.GaugeValueWithLabels(ap.queueAge, labels)
+	test.AssertNotError(t, err, "getting queue age")
+	test.AssertEquals(t, age, int((12 * time.Hour).Seconds()))
+	overdue, err := test.GaugeValueWithLabels(ap.queueOverdue, labels)
+	test.AssertNotError(t, err, "getting overdue count")
+	test.AssertEquals(t, overdue, 0)
+	test.AssertEquals(t, len(log.GetAllMatching("waiting to be purged")), 0)
+
+	// The first two URLs pass the deadline, which is logged only once.
+	fc.Add(13 * time.Hour)
+	ap.checkQueues()
+	ap.checkQueues()
+	overdue, err = test.GaugeValueWithLabels(ap.queueOverdue, labels)
+	test.AssertNotError(t, err, "getting overdue count")
+	test.AssertEquals(t, overdue, 2)
+	test.AssertEquals(t, len(log.GetAllMatching("2 URLs have been waiting to be purged from Fake")), 1)
+}
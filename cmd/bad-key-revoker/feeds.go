@@ -0,0 +1,168 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/jmhodges/clock"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/letsencrypt/boulder/cmd"
+	corepb "github.com/letsencrypt/boulder/core/proto"
+	"github.com/letsencrypt/boulder/log"
+	sapb "github.com/letsencrypt/boulder/sa/proto"
+)
+
+var feedPolls = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "bad_keys_feed_polls",
+	Help: "A counter of compromised key feed polls labelled by feed and result",
+}, []string{"feed", "result"})
+var feedKeysAdded = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "bad_keys_feed_keys_added",
+	Help: "A counter of key hashes from compromised key feeds submitted to blockedKeys labelled by feed",
+}, []string{"feed"})
+
+// feedConfig describes a feed of compromised keys to poll.
+type feedConfig struct {
+	// Name identifies the feed in metrics, logs, and the comment recorded
+	// with each blockedKeys row it adds.
+	Name string
+	// URL is an HTTPS endpoint serving a list of hex encoded SHA-256 hashes
+	// of the SubjectPublicKeyInfo of compromised keys, one per line. Blank
+	// lines and lines beginning with '#' are ignored.
+	URL string
+	// Interval is how often the feed is fetched.
+	Interval cmd.ConfigDuration
+}
+
+// blockedKeyAdder is the subset of the SA used by the feed poller, to allow
+// mocking in tests.
+type blockedKeyAdder interface {
+	AddBlockedKey(ctx context.Context, req *sapb.AddBlockedKeyRequest) (*corepb.Empty, error)
+}
+
+// feedPoller periodically fetches a compromised key feed and adds any keys it
+// hasn't seen before to the blockedKeys table. Newly added keys are picked up
+// by the main bad-key-revoker loop, which revokes any certificates using them.
+type feedPoller struct {
+	feed   feedConfig
+	sa     blockedKeyAdder
+	client *http.Client
+	clk    clock.Clock
+	logger log.Logger
+
+	// etag is the ETag of the last successful fetch, used to skip processing
+	// the feed if it hasn't changed.
+	etag string
+	// seen contains the hashes already submitted to the SA. AddBlockedKey
+	// ignores duplicates, so this only avoids repeating work between polls.
+	seen map[string]bool
+}
+
+func newFeedPoller(feed feedConfig, sa blockedKeyAdder, client *http.Client, clk clock.Clock, logger log.Logger) *feedPoller {
+	return &feedPoller{
+		feed:   feed,
+		sa:     sa,
+		client: client,
+		clk:    clk,
+		logger: logger,
+		seen:   make(map[string]bool),
+	}
+}
+
+// run polls the feed forever.
+func (fp *feedPoller) run() {
+	for {
+		added, err := fp.poll(context.Background())
+		if err != nil {
+			feedPolls.WithLabelValues(fp.feed.Name, "error").Inc()
+			fp.logger.AuditErrf("polling compromised key feed %q: %s", fp.feed.Name, err)
+		} else {
+			feedPolls.WithLabelValues(fp.feed.Name, "success").Inc()
+			if added > 0 {
+				fp.logger.AuditInfo(fmt.Sprintf("added %d key hashes from compromised key feed %q", added, fp.feed.Name))
+			}
+		}
+		fp.clk.Sleep(fp.feed.Interval.Duration)
+	}
+}
+
+// poll fetches the feed once and submits every hash in it which hasn't been
+// submitted before, returning the number of hashes submitted. A malformed
+// line fails the whole poll, since it suggests the feed isn't what we expect.
+func (fp *feedPoller) poll(ctx context.Context) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", fp.feed.URL, nil)
+	if err != nil {
+		return 0, err
+	}
+	if fp.etag != "" {
+		req.Header.Set("If-None-Match", fp.etag)
+	}
+	resp, err := fp.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotModified {
+		return 0, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	hashes, err := parseFeed(resp.Body)
+	if err != nil {
+		return 0, err
+	}
+
+	added := 0
+	for _, hash := range hashes {
+		if fp.seen[string(hash)] {
+			continue
+		}
+		_, err := fp.sa.AddBlockedKey(ctx, &sapb.AddBlockedKeyRequest{
+			KeyHash: hash,
+			Added:   fp.clk.Now().UnixNano(),
+			Source:  "feed",
+			Comment: fmt.Sprintf("feed %s (%s)", fp.feed.Name, fp.feed.URL),
+		})
+		if err != nil {
+			// Return without updating the ETag so the next poll retries the
+			// remaining hashes.
+			return added, err
+		}
+		fp.seen[string(hash)] = true
+		feedKeysAdded.WithLabelValues(fp.feed.Name).Inc()
+		added++
+	}
+	fp.etag = resp.Header.Get("ETag")
+	return added, nil
+}
+
+// parseFeed parses a list of hex encoded SHA-256 hashes, one per line.
+func parseFeed(r io.Reader) ([][]byte, error) {
+	var hashes [][]byte
+	scanner := bufio.NewScanner(r)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		hash, err := hex.DecodeString(line)
+		if err != nil || len(hash) != 32 {
+			return nil, fmt.Errorf("line %d: not a hex encoded SHA-256 hash: %q", lineNum, line)
+		}
+		hashes = append(hashes, hash)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return hashes, nil
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jmhodges/clock"
+
+	corepb "github.com/letsencrypt/boulder/core/proto"
+	blog "github.com/letsencrypt/boulder/log"
+	sapb "github.com/letsencrypt/boulder/sa/proto"
+	"github.com/letsencrypt/boulder/test"
+)
+
+type recordingSA struct {
+	added []*sapb.AddBlockedKeyRequest
+	err   error
+}
+
+func (rsa *recordingSA) AddBlockedKey(_ context.Context, req *sapb.AddBlockedKeyRequest) (*corepb.Empty, error) {
+	if rsa.err != nil {
+		return nil, rsa.err
+	}
+	rsa.added = append(rsa.added, req)
+	return &corepb.Empty{}, nil
+}
+
+func TestParseFeed(t *testing.T) {
+	hashA, hashB := randHash(t), randHash(t)
+	hashes, err := parseFeed(strings.NewReader(fmt.Sprintf("# compromised keys\n%x\n\n  %X  \n", hashA, hashB)))
+	test.AssertNotError(t, err, "parseFeed failed")
+	test.AssertEquals(t, len(hashes), 2)
+	test.AssertByteEquals(t, hashes[0], hashA)
+	test.AssertByteEquals(t, hashes[1], hashB)
+
+	_, err = parseFeed(strings.NewReader("not a hash\n"))
+	test.AssertError(t, err, "parseFeed accepted a malformed line")
+	_, err = parseFeed(strings.NewReader(hex.EncodeToString(hashA[:16]) + "\n"))
+	test.AssertError(t, err, "parseFeed accepted a short hash")
+}
+
+func TestFeedPoll(t *testing.T) {
+	hashA, hashB := randHash(t), randHash(t)
+	body := fmt.Sprintf("%x\n", hashA)
+	etag := `"1"`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		fmt.Fprint(w, body)
+	}))
+	defer server.Close()
+
+	sa := &recordingSA{}
+	fp := newFeedPoller(feedConfig{Name: "test", URL: server.URL}, sa, server.Client(), clock.NewFake(), blog.NewMock())
+
+	added, err := fp.poll(context.Background())
+	test.AssertNotError(t, err, "poll failed")
+	test.AssertEquals(t, added, 1)
+	test.AssertEquals(t, len(sa.added), 1)
+	test.AssertByteEquals(t, sa.added[0].KeyHash, hashA)
+	test.AssertEquals(t, sa.added[0].Source, "feed")
+	test.AssertContains(t, sa.added[0].Comment, "test")
+
+	// An unchanged feed isn't processed again.
+	added, err = fp.poll(context.Background())
+	test.AssertNotError(t, err, "poll failed")
+	test.AssertEquals(t, added, 0)
+
+	// When the feed changes, only new hashes are submitted.
+	body = fmt.Sprintf("%x\n%x\n", hashA, hashB)
+	etag = `"2"`
+	added, err = fp.poll(context.Background())
+	test.AssertNotError(t, err, "poll failed")
+	test.AssertEquals(t, added, 1)
+	test.AssertEquals(t, len(sa.added), 2)
+	test.AssertByteEquals(t, sa.added[1].KeyHash, hashB)
+
+	// If the SA fails, the ETag isn't updated so the next poll retries.
+	body = fmt.Sprintf("%x\n", randHash(t))
+	etag = `"3"`
+	sa.err = errors.New("SA unavailable")
+	_, err = fp.poll(context.Background())
+	test.AssertError(t, err, "poll didn't fail when the SA failed")
+	sa.err = nil
+	added, err = fp.poll(context.Background())
+	test.AssertNotError(t, err, "poll failed")
+	test.AssertEquals(t, added, 1)
+}
@@ -8,9 +8,11 @@ import (
 	"fmt"
 	"html/template"
 	"io/ioutil"
+	"net/http"
 	netmail "net/mail"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/letsencrypt/boulder/cmd"
@@ -22,6 +24,7 @@ import (
 	"github.com/letsencrypt/boulder/mail"
 	rapb "github.com/letsencrypt/boulder/ra/proto"
 	"github.com/letsencrypt/boulder/sa"
+	sapb "github.com/letsencrypt/boulder/sa/proto"
 	"github.com/prometheus/client_golang/prometheus"
 	"golang.org/x/crypto/ocsp"
 
@@ -40,6 +43,10 @@ var mailErrors = prometheus.NewCounter(prometheus.CounterOpts{
 	Name: "bad_keys_mail_errors",
 	Help: "A counter of email send errors",
 })
+var certsPending = prometheus.NewGauge(prometheus.GaugeOpts{
+	Name: "bad_keys_certs_pending",
+	Help: "The number of certificates associated with the blockedKeys row currently being processed that have yet to be revoked",
+})
 
 // revoker is an interface used to reduce the scope of a RA gRPC client
 // to only the single method we need to use, this makes testing significantly
@@ -52,11 +59,17 @@ type badKeyRevoker struct {
 	dbMap           *db.WrappedMap
 	maxRevocations  int
 	serialBatchSize int
+	parallelism     int
+	// revocationTicks, if not nil, limits the rate of revocation requests
+	// sent to the RA: each request waits for a value from the channel.
+	revocationTicks <-chan time.Time
 	raClient        revoker
 	mailer          mail.Mailer
 	emailSubject    string
 	emailTemplate   *template.Template
-	logger          log.Logger
+	// contactCipher, if not nil, decrypts registrations' contacts.
+	contactCipher *sa.ContactCipher
+	logger        log.Logger
 }
 
 // uncheckedBlockedKey represents a row in the blockedKeys table
@@ -99,9 +112,11 @@ func (uc unrevokedCertificate) String() string {
 
 // findUnrevoked looks for all unexpired, currently valid certificates which have a specific SPKI hash,
 // by looking first at the keyHashToSerial table and then the certificateStatus and certificates tables.
-// If the number of certificates it finds is larger than bkr.maxRevocations it'll error out.
+// Serials are read from keyHashToSerial in batches of bkr.serialBatchSize, and the certificates for
+// each batch are looked up concurrently. If the number of certificates it finds is larger than
+// bkr.maxRevocations it'll error out.
 func (bkr *badKeyRevoker) findUnrevoked(unchecked uncheckedBlockedKey) ([]unrevokedCertificate, error) {
-	var unrevokedCerts []unrevokedCertificate
+	var batches [][]string
 	initialID := 0
 	for {
 		var batch []struct {
@@ -122,24 +137,33 @@ func (bkr *badKeyRevoker) findUnrevoked(unchecked uncheckedBlockedKey) ([]unrevo
 			break
 		}
 		initialID = batch[len(batch)-1].ID
-		for _, serial := range batch {
-			var unrevokedCert unrevokedCertificate
-			err = bkr.dbMap.SelectOne(
-				&unrevokedCert,
-				`SELECT cs.id, cs.serial, c.registrationID, c.der, cs.status, cs.isExpired
-				FROM certificateStatus AS cs
-				JOIN precertificates AS c
-				ON cs.serial = c.serial
-				WHERE cs.serial = ?`,
-				serial.CertSerial,
-			)
-			if err != nil {
-				return nil, err
-			}
-			if unrevokedCert.IsExpired || unrevokedCert.Status == core.OCSPStatusRevoked {
+		serials := make([]string, len(batch))
+		for i, row := range batch {
+			serials[i] = row.CertSerial
+		}
+		batches = append(batches, serials)
+	}
+
+	results := make([][]unrevokedCertificate, len(batches))
+	err := bkr.forEach(len(batches), func(i int) error {
+		certs, err := bkr.lookupCerts(batches[i])
+		if err != nil {
+			return err
+		}
+		results[i] = certs
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var unrevokedCerts []unrevokedCertificate
+	for _, certs := range results {
+		for _, cert := range certs {
+			if cert.IsExpired || cert.Status == core.OCSPStatusRevoked {
 				continue
 			}
-			unrevokedCerts = append(unrevokedCerts, unrevokedCert)
+			unrevokedCerts = append(unrevokedCerts, cert)
 		}
 	}
 	if len(unrevokedCerts) > bkr.maxRevocations {
@@ -148,6 +172,91 @@ func (bkr *badKeyRevoker) findUnrevoked(unchecked uncheckedBlockedKey) ([]unrevo
 	return unrevokedCerts, nil
 }
 
+const unrevokedCertificateQuery = `SELECT cs.id, cs.serial, c.registrationID, c.der, cs.status, cs.isExpired
+	FROM certificateStatus AS cs
+	JOIN precertificates AS c
+	ON cs.serial = c.serial`
+
+// lookupCerts looks up the certificates with the given serials in a single
+// query, returning them in the same order as serials. Every serial is expected
+// to have a certificate; if one is missing, the error from looking it up
+// individually is returned.
+func (bkr *badKeyRevoker) lookupCerts(serials []string) ([]unrevokedCertificate, error) {
+	qmarks := make([]string, len(serials))
+	args := make([]interface{}, len(serials))
+	for i, serial := range serials {
+		qmarks[i] = "?"
+		args[i] = serial
+	}
+	var rows []unrevokedCertificate
+	_, err := bkr.dbMap.Select(
+		&rows,
+		fmt.Sprintf("%s WHERE cs.serial IN (%s)", unrevokedCertificateQuery, strings.Join(qmarks, ",")),
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	bySerial := make(map[string]unrevokedCertificate, len(rows))
+	for _, row := range rows {
+		bySerial[row.Serial] = row
+	}
+
+	certs := make([]unrevokedCertificate, 0, len(serials))
+	for _, serial := range serials {
+		cert, ok := bySerial[serial]
+		if !ok {
+			err = bkr.dbMap.SelectOne(&cert, unrevokedCertificateQuery+" WHERE cs.serial = ?", serial)
+			if err != nil {
+				return nil, err
+			}
+		}
+		certs = append(certs, cert)
+	}
+	return certs, nil
+}
+
+// forEach calls work for each index in [0, n), using up to bkr.parallelism
+// goroutines. It stops handing out new work after the first error, and
+// returns that error.
+func (bkr *badKeyRevoker) forEach(n int, work func(i int) error) error {
+	parallelism := bkr.parallelism
+	if parallelism < 1 {
+		parallelism = 1
+	}
+	indexes := make(chan int)
+	errs := make(chan error, parallelism)
+	stop := make(chan struct{})
+	var stopOnce sync.Once
+	wg := new(sync.WaitGroup)
+	for w := 0; w < parallelism; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				err := work(i)
+				if err != nil {
+					errs <- err
+					stopOnce.Do(func() { close(stop) })
+					return
+				}
+			}
+		}()
+	}
+feed:
+	for i := 0; i < n; i++ {
+		select {
+		case indexes <- i:
+		case <-stop:
+			break feed
+		}
+	}
+	close(indexes)
+	wg.Wait()
+	close(errs)
+	return <-errs
+}
+
 // markRowChecked updates a row in the blockedKeys table to mark a keyHash
 // as having been checked for extant unrevoked certificates.
 func (bkr *badKeyRevoker) markRowChecked(unchecked uncheckedBlockedKey) error {
@@ -168,6 +277,10 @@ func (bkr *badKeyRevoker) resolveContacts(ids []int64) (map[int64][]string, erro
 			// row for the registration, even if there are no contacts
 			return nil, err
 		}
+		emails.Contact, err = sa.DecryptContacts(bkr.contactCipher, emails.Contact)
+		if err != nil {
+			return nil, fmt.Errorf("decrypting contacts of registration %d: %s", id, err)
+		}
 		if len(emails.Contact) != 0 {
 			for _, email := range emails.Contact {
 				idToEmail[id] = append(idToEmail[id], strings.TrimPrefix(email, "mailto:"))
@@ -216,35 +329,57 @@ func (bkr *badKeyRevoker) sendMessage(addr string, serials []string) error {
 // revokeCerts revokes all the certificates associated with a particular key hash and sends
 // emails to the users that issued the certificates. Emails are not sent to the user which
 // requested revocation of the original certificate which marked the key as compromised.
+// Certificates are revoked concurrently, limited by bkr.parallelism and bkr.revocationTicks,
+// and emails are only sent once every certificate has been revoked.
 func (bkr *badKeyRevoker) revokeCerts(revokerEmails []string, emailToCerts map[string][]unrevokedCertificate) error {
 	revokerEmailsMap := map[string]bool{}
 	for _, email := range revokerEmails {
 		revokerEmailsMap[email] = true
 	}
 
-	alreadyRevoked := map[int]bool{}
-	for email, certs := range emailToCerts {
-		var revokedSerials []string
+	var toRevoke []unrevokedCertificate
+	seen := map[int]bool{}
+	for _, certs := range emailToCerts {
 		for _, cert := range certs {
-			revokedSerials = append(revokedSerials, cert.Serial)
-			if alreadyRevoked[cert.ID] {
+			if seen[cert.ID] {
 				continue
 			}
-			_, err := bkr.raClient.AdministrativelyRevokeCertificate(context.Background(), &rapb.AdministrativelyRevokeCertificateRequest{
-				Cert:      cert.DER,
-				Code:      int64(ocsp.KeyCompromise),
-				AdminName: "bad-key-revoker",
-			})
-			if err != nil {
-				return err
-			}
-			certsRevoked.Inc()
-			alreadyRevoked[cert.ID] = true
+			seen[cert.ID] = true
+			toRevoke = append(toRevoke, cert)
+		}
+	}
+	certsPending.Set(float64(len(toRevoke)))
+	defer certsPending.Set(0)
+
+	err := bkr.forEach(len(toRevoke), func(i int) error {
+		if bkr.revocationTicks != nil {
+			<-bkr.revocationTicks
+		}
+		_, err := bkr.raClient.AdministrativelyRevokeCertificate(context.Background(), &rapb.AdministrativelyRevokeCertificateRequest{
+			Cert:      toRevoke[i].DER,
+			Code:      int64(ocsp.KeyCompromise),
+			AdminName: "bad-key-revoker",
+		})
+		if err != nil {
+			return err
 		}
+		certsRevoked.Inc()
+		certsPending.Dec()
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for email, certs := range emailToCerts {
 		// don't send emails to the person who revoked the certificate
 		if revokerEmailsMap[email] || email == "" {
 			continue
 		}
+		var revokedSerials []string
+		for _, cert := range certs {
+			revokedSerials = append(revokedSerials, cert.Serial)
+		}
 		err := bkr.sendMessage(email, revokedSerials)
 		if err != nil {
 			mailErrors.Inc()
@@ -336,6 +471,20 @@ func (bkr *badKeyRevoker) invoke() (bool, error) {
 	return false, nil
 }
 
+// revocationInterval returns the interval between revocation requests which
+// limits them to rate per second. It's an error for the rate to be negative
+// or so high that the interval would be shorter than a nanosecond.
+func revocationInterval(rate float64) (time.Duration, error) {
+	if rate <= 0 {
+		return 0, fmt.Errorf("rate must be positive, got %g", rate)
+	}
+	interval := time.Duration(float64(time.Second) / rate)
+	if interval <= 0 {
+		return 0, fmt.Errorf("rate must be at most %d per second, got %g", time.Second, rate)
+	}
+	return interval, nil
+}
+
 func main() {
 	var config struct {
 		BadKeyRevoker struct {
@@ -354,10 +503,30 @@ func main() {
 			// keyHashToSerial table at once
 			FindCertificatesBatchSize int
 
+			// Parallelism is the number of batches of certificates looked up,
+			// and the number of revocation requests made to the RA,
+			// concurrently. Defaults to 1.
+			Parallelism int
+
+			// RevocationsPerSecond limits the rate of revocation requests made
+			// to the RA. If zero, the rate is not limited.
+			RevocationsPerSecond float64
+
 			// Interval specifies how long bad-key-revoker should sleep between attempting to find
 			// blockedKeys rows to process when there is no work to do
 			Interval cmd.ConfigDuration
 
+			// Feeds lists compromised key feeds to poll. Keys from the feeds
+			// are added to the blockedKeys table via the SA, and then revoked
+			// like any other blocked key. SAService must be set if any feeds
+			// are configured.
+			Feeds     []feedConfig
+			SAService *cmd.GRPCClientConfig
+
+			// ContactEncryption must be set, with the SA's keys, if the SA
+			// encrypts registration contacts.
+			ContactEncryption *cmd.ContactEncryptionConfig
+
 			Mailer struct {
 				cmd.SMTPConfig
 				// Path to a file containing a list of trusted root certificates for use
@@ -373,6 +542,7 @@ func main() {
 		Syslog cmd.SyslogConfig
 	}
 	configPath := flag.String("config", "", "File path to the configuration file for this service")
+	checkConfig := cmd.CheckConfigFlag()
 	flag.Parse()
 
 	if *configPath == "" {
@@ -381,6 +551,9 @@ func main() {
 	}
 	err := cmd.ReadConfigFile(*configPath, &config)
 	cmd.FailOnError(err, "Failed reading config file")
+	if *checkConfig {
+		cmd.CheckConfig(&config)
+	}
 
 	scope, logger := cmd.StatsAndLogging(config.Syslog, config.BadKeyRevoker.DebugAddr)
 	clk := cmd.Clock()
@@ -388,6 +561,9 @@ func main() {
 	scope.MustRegister(keysProcessed)
 	scope.MustRegister(certsRevoked)
 	scope.MustRegister(mailErrors)
+	scope.MustRegister(certsPending)
+	scope.MustRegister(feedPolls)
+	scope.MustRegister(feedKeysAdded)
 
 	dbURL, err := config.BadKeyRevoker.DBConfig.URL()
 	cmd.FailOnError(err, "Couldn't load DB URL")
@@ -402,6 +578,8 @@ func main() {
 	cmd.FailOnError(err, "Could not connect to database")
 	sa.SetSQLDebug(dbMap, logger)
 	sa.InitDBMetrics(dbMap, scope, dbSettings)
+	err = config.BadKeyRevoker.DBConfig.StartAdaptivePool(dbMap.Db, scope, logger)
+	cmd.FailOnError(err, "Invalid adaptive DB pool config")
 
 	tlsConfig, err := config.BadKeyRevoker.TLS.Load()
 	cmd.FailOnError(err, "TLS config")
@@ -411,6 +589,22 @@ func main() {
 	cmd.FailOnError(err, "Failed to load credentials and create gRPC connection to RA")
 	rac := rapb.NewRegistrationAuthorityClient(conn)
 
+	if len(config.BadKeyRevoker.Feeds) > 0 {
+		if config.BadKeyRevoker.SAService == nil {
+			cmd.Fail("BadKeyRevoker.SAService must be configured when feeds are configured")
+		}
+		saConn, err := bgrpc.ClientSetup(config.BadKeyRevoker.SAService, tlsConfig, clientMetrics, clk)
+		cmd.FailOnError(err, "Failed to load credentials and create gRPC connection to SA")
+		sac := bgrpc.NewStorageAuthorityClient(sapb.NewStorageAuthorityClient(saConn))
+		httpClient := &http.Client{Timeout: time.Minute}
+		for _, feed := range config.BadKeyRevoker.Feeds {
+			if feed.Name == "" || feed.URL == "" || feed.Interval.Duration == 0 {
+				cmd.Fail("Each of BadKeyRevoker.Feeds must have a name, URL, and interval")
+			}
+			go newFeedPoller(feed, sac, httpClient, clk, logger).run()
+		}
+	}
+
 	var smtpRoots *x509.CertPool
 	if config.BadKeyRevoker.Mailer.SMTPTrustedRootFile != "" {
 		pem, err := ioutil.ReadFile(config.BadKeyRevoker.Mailer.SMTPTrustedRootFile)
@@ -447,14 +641,32 @@ func main() {
 	emailTemplate, err := template.New("email").Parse(string(templateBytes))
 	cmd.FailOnError(err, fmt.Sprintf("failed to parse email template %q: %s", config.BadKeyRevoker.Mailer.EmailTemplate, err))
 
+	var revocationTicks <-chan time.Time
+	if config.BadKeyRevoker.RevocationsPerSecond != 0 {
+		interval, err := revocationInterval(config.BadKeyRevoker.RevocationsPerSecond)
+		cmd.FailOnError(err, "Invalid BadKeyRevoker.RevocationsPerSecond")
+		revocationTicks = time.NewTicker(interval).C
+	}
+
+	var contactCipher *sa.ContactCipher
+	if config.BadKeyRevoker.ContactEncryption != nil {
+		keys, err := config.BadKeyRevoker.ContactEncryption.Keys()
+		cmd.FailOnError(err, "Failed to load contact encryption keys")
+		contactCipher, err = sa.NewContactCipher(keys, config.BadKeyRevoker.ContactEncryption.ActiveKeyID)
+		cmd.FailOnError(err, "Failed to create contact cipher")
+	}
+
 	bkr := &badKeyRevoker{
 		dbMap:           dbMap,
 		maxRevocations:  config.BadKeyRevoker.MaximumRevocations,
 		serialBatchSize: config.BadKeyRevoker.FindCertificatesBatchSize,
+		parallelism:     config.BadKeyRevoker.Parallelism,
+		revocationTicks: revocationTicks,
 		raClient:        rac,
 		mailer:          mailClient,
 		emailSubject:    config.BadKeyRevoker.Mailer.EmailSubject,
 		emailTemplate:   emailTemplate,
+		contactCipher:   contactCipher,
 		logger:          logger,
 	}
 	for {
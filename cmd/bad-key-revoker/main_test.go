@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"crypto/rand"
+	"encoding/json"
 	"fmt"
 	"html/template"
 	"strings"
@@ -70,9 +71,6 @@ func TestSelectUncheckedRows(t *testing.T) {
 
 func insertRegistration(t *testing.T, dbMap *db.WrappedMap, addrs ...string) int64 {
 	t.Helper()
-	jwkHash := make([]byte, 2)
-	_, err := rand.Read(jwkHash)
-	test.AssertNotError(t, err, "failed to read rand")
 	contactStr := "[]"
 	if len(addrs) > 0 {
 		contacts := []string{}
@@ -81,6 +79,16 @@ func insertRegistration(t *testing.T, dbMap *db.WrappedMap, addrs ...string) int
 		}
 		contactStr = fmt.Sprintf("[%s]", strings.Join(contacts, ","))
 	}
+	return insertRegistrationWithContact(t, dbMap, contactStr)
+}
+
+// insertRegistrationWithContact inserts a registration whose contact column
+// holds contactStr.
+func insertRegistrationWithContact(t *testing.T, dbMap *db.WrappedMap, contactStr string) int64 {
+	t.Helper()
+	jwkHash := make([]byte, 2)
+	_, err := rand.Read(jwkHash)
+	test.AssertNotError(t, err, "failed to read rand")
 	res, err := dbMap.Exec(
 		"INSERT INTO registrations (jwk, jwk_sha256, contact, agreement, initialIP, createdAt, status, LockCol) VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
 		[]byte{},
@@ -227,6 +235,27 @@ func TestResolveContacts(t *testing.T) {
 		regIDC: {"example.com"},
 		regIDD: {"example-2.com"},
 	})
+
+	// Encrypted contacts are decrypted, and can't be resolved without the
+	// contact cipher.
+	cipher, err := sa.NewContactCipher(map[string][]byte{"k1": make([]byte, 32)}, "k1")
+	test.AssertNotError(t, err, "creating contact cipher")
+	encrypted, err := cipher.Encrypt([]string{"mailto:example.com"})
+	test.AssertNotError(t, err, "encrypting contacts")
+	contactJSON, err := json.Marshal(encrypted)
+	test.AssertNotError(t, err, "marshalling contacts")
+	regIDE := insertRegistrationWithContact(t, dbMap, string(contactJSON))
+
+	_, err = bkr.resolveContacts([]int64{regIDE})
+	test.AssertError(t, err, "resolved encrypted contacts without the contact cipher")
+
+	bkr.contactCipher = cipher
+	idToEmail, err = bkr.resolveContacts([]int64{regIDA, regIDE})
+	test.AssertNotError(t, err, "resolveContacts failed")
+	test.AssertDeepEquals(t, idToEmail, map[int64][]string{
+		regIDA: {""},
+		regIDE: {"example.com"},
+	})
 }
 
 var testTemplate = template.Must(template.New("testing").Parse("{{range .}}{{.}}\n{{end}}"))
@@ -418,3 +447,75 @@ func TestInvokeRevokerHasNoExtantCerts(t *testing.T) {
 	test.AssertEquals(t, len(mm.Messages), 1)
 	test.AssertEquals(t, mm.Messages[0].To, "b@example.com")
 }
+
+func TestRevokeCertsParallel(t *testing.T) {
+	mm := &mocks.Mailer{}
+	mr := &mockRevoker{}
+	ticks := make(chan time.Time)
+	go func() {
+		for {
+			ticks <- time.Now()
+		}
+	}()
+	bkr := &badKeyRevoker{
+		parallelism:     4,
+		revocationTicks: ticks,
+		raClient:        mr,
+		mailer:          mm,
+		emailSubject:    "testing",
+		emailTemplate:   testTemplate,
+		logger:          blog.NewMock(),
+	}
+
+	emailToCerts := map[string][]unrevokedCertificate{}
+	for i := 0; i < 50; i++ {
+		email := fmt.Sprintf("%d@example.com", i%5)
+		emailToCerts[email] = append(emailToCerts[email], unrevokedCertificate{ID: i, Serial: fmt.Sprintf("%02x", i)})
+	}
+	// A certificate shared by two addresses is only revoked once.
+	emailToCerts["revoker@example.com"] = []unrevokedCertificate{{ID: 0, Serial: "00"}}
+
+	err := bkr.revokeCerts([]string{"revoker@example.com"}, emailToCerts)
+	test.AssertNotError(t, err, "revokeCerts failed")
+	test.AssertEquals(t, mr.revoked, 50)
+	test.AssertEquals(t, len(mm.Messages), 5)
+}
+
+func TestForEach(t *testing.T) {
+	bkr := &badKeyRevoker{parallelism: 3}
+	var mu sync.Mutex
+	seen := map[int]bool{}
+	err := bkr.forEach(20, func(i int) error {
+		mu.Lock()
+		defer mu.Unlock()
+		seen[i] = true
+		return nil
+	})
+	test.AssertNotError(t, err, "forEach failed")
+	test.AssertEquals(t, len(seen), 20)
+
+	// The first error is returned, and no more work is started after it.
+	var calls int
+	err = bkr.forEach(1000, func(i int) error {
+		mu.Lock()
+		defer mu.Unlock()
+		calls++
+		return fmt.Errorf("failed on %d", i)
+	})
+	test.AssertError(t, err, "forEach didn't return an error")
+	test.Assert(t, calls < 1000, "forEach kept working after an error")
+}
+
+func TestRevocationInterval(t *testing.T) {
+	interval, err := revocationInterval(4)
+	test.AssertNotError(t, err, "revocationInterval failed")
+	test.AssertEquals(t, interval, 250*time.Millisecond)
+
+	_, err = revocationInterval(-1)
+	test.AssertError(t, err, "revocationInterval accepted a negative rate")
+
+	// Rates above one per nanosecond would make the interval zero, which
+	// time.NewTicker panics on.
+	_, err = revocationInterval(2e9)
+	test.AssertError(t, err, "revocationInterval accepted a rate above one per nanosecond")
+}
@@ -1,17 +1,24 @@
 package main
 
 import (
+	"context"
 	"crypto"
+	"crypto/rand"
+	"crypto/sha256"
 	"crypto/x509"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"io/ioutil"
 	"os"
+	"sync"
+	"time"
 
 	"github.com/beeker1121/goque"
 	cfsslConfig "github.com/cloudflare/cfssl/config"
 	"github.com/cloudflare/cfssl/helpers"
+	"github.com/jmhodges/clock"
 	pkcs11key "github.com/letsencrypt/pkcs11key/v4"
 	"google.golang.org/grpc/health"
 	healthpb "google.golang.org/grpc/health/grpc_health_v1"
@@ -24,8 +31,10 @@ import (
 	"github.com/letsencrypt/boulder/goodkey"
 	bgrpc "github.com/letsencrypt/boulder/grpc"
 	"github.com/letsencrypt/boulder/issuance"
+	"github.com/letsencrypt/boulder/issuancelog"
 	"github.com/letsencrypt/boulder/lint"
 	"github.com/letsencrypt/boulder/policy"
+	policypb "github.com/letsencrypt/boulder/policy/proto"
 	sapb "github.com/letsencrypt/boulder/sa/proto"
 )
 
@@ -85,6 +94,15 @@ type config struct {
 		// administratively blocked.
 		BlockedKeyFile string
 
+		// FermatRounds is the number of rounds of Fermat's factorization method
+		// to attempt against RSA keys, to detect keys whose prime factors are
+		// too close together. If zero, the check is disabled.
+		FermatRounds int
+
+		// DisableROCACheck disables rejection of RSA keys generated by
+		// Infineon hardware vulnerable to ROCA.
+		DisableROCACheck bool
+
 		// Path to directory holding orphan queue files, if not provided an orphan queue
 		// is not used.
 		OrphanQueueDir string
@@ -112,6 +130,17 @@ type config struct {
 		// ECDSA issuance, but will then be removed.
 		ECDSAAllowedAccounts []int64
 
+		// IssuanceLog, if present, configures a hash-chained log to which an
+		// entry is appended for each precertificate and certificate before it
+		// is signed. Each CA instance must have its own File and ChainID.
+		IssuanceLog *struct {
+			File    string
+			ChainID string
+			// StoreInSA makes the CA also copy each entry to the SA, so that
+			// the log can be cross-checked against it.
+			StoreInSA bool
+		}
+
 		Features map[string]bool
 	}
 
@@ -138,7 +167,9 @@ func loadCFSSLIssuers(configs []IssuerConfig) ([]ca.Issuer, error) {
 	var issuers []ca.Issuer
 	for _, issuerConfig := range configs {
 		signer, cert, err := loadCFSSLIssuer(issuerConfig)
-		cmd.FailOnError(err, "Couldn't load private key")
+		if err != nil {
+			return nil, fmt.Errorf("Couldn't load private key: %s", err)
+		}
 		issuers = append(issuers, ca.Issuer{
 			Signer: signer,
 			Cert:   cert,
@@ -233,11 +264,45 @@ func loadBoulderIssuers(profileConfig issuance.ProfileConfig, issuerConfigs []is
 	return issuers, nil
 }
 
+// signerCheckInterval is how often signerHealthCheck signs with the issuers'
+// keys. Readiness is probed much more often than that, and every signature is
+// an HSM operation, so the result is reused in between.
+const signerCheckInterval = 5 * time.Minute
+
+// signerHealthCheck returns a cmd.HealthCheck which signs a digest with each
+// of the issuers' keys, so that a lost HSM session makes the CA unready. It
+// signs at most once per signerCheckInterval, returning the last result
+// otherwise.
+func signerHealthCheck(signers []crypto.Signer, clk clock.Clock) cmd.HealthCheck {
+	digest := sha256.Sum256([]byte("boulder-ca health check"))
+	var mu sync.Mutex
+	var checked time.Time
+	var lastErr error
+	return func(ctx context.Context) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if !checked.IsZero() && clk.Since(checked) < signerCheckInterval {
+			return lastErr
+		}
+		lastErr = nil
+		for i, signer := range signers {
+			_, err := signer.Sign(rand.Reader, digest[:], crypto.SHA256)
+			if err != nil {
+				lastErr = fmt.Errorf("signing with issuer %d: %s", i, err)
+				break
+			}
+		}
+		checked = clk.Now()
+		return lastErr
+	}
+}
+
 func main() {
 	caAddr := flag.String("ca-addr", "", "CA gRPC listen address override")
 	ocspAddr := flag.String("ocsp-addr", "", "OCSP gRPC listen address override")
 	debugAddr := flag.String("debug-addr", "", "Debug server address override")
 	configFile := flag.String("config", "", "File path to the configuration file for this service")
+	checkConfig := cmd.CheckConfigFlag()
 	flag.Parse()
 	if *configFile == "" {
 		flag.Usage()
@@ -247,6 +312,41 @@ func main() {
 	var c config
 	err := cmd.ReadConfigFile(*configFile, &c)
 	cmd.FailOnError(err, "Reading JSON config file into config structure")
+	if *checkConfig {
+		cmd.CheckConfig(&c,
+			cmd.ConfigCheck{
+				Name: "CA.MaxNames",
+				Check: func() error {
+					if c.CA.MaxNames == 0 {
+						return errors.New("must not be 0")
+					}
+					return nil
+				},
+			},
+			cmd.ConfigCheck{
+				Name: "CA.HostnamePolicyFile",
+				Check: func() error {
+					pa, err := policy.New(c.PA.Challenges)
+					if err != nil {
+						return err
+					}
+					return pa.SetHostnamePolicyFile(c.CA.HostnamePolicyFile)
+				},
+			},
+			cmd.ConfigCheck{
+				Name: "CA issuers",
+				Check: func() error {
+					// CheckConfig has already set the feature flags.
+					if features.Enabled(features.NonCFSSLSigner) {
+						_, err := loadBoulderIssuers(c.CA.Issuance.Profile, c.CA.Issuance.Issuers, c.CA.Issuance.IgnoredLints)
+						return err
+					}
+					_, err := loadCFSSLIssuers(c.CA.Issuers)
+					return err
+				},
+			},
+		)
+	}
 
 	err = features.Set(c.CA.Features)
 	cmd.FailOnError(err, "Failed to set feature flags")
@@ -280,6 +380,13 @@ func main() {
 	err = pa.SetHostnamePolicyFile(c.CA.HostnamePolicyFile)
 	cmd.FailOnError(err, "Couldn't load hostname policy file")
 
+	err = pa.SetWildcardPolicy(policy.WildcardPolicy{
+		MaxDepth:            c.PA.MaxWildcardDepth,
+		ForbiddenDomains:    c.PA.ForbiddenWildcardDomains,
+		RequireRevalidation: c.PA.RequireWildcardRevalidation,
+	})
+	cmd.FailOnError(err, "Invalid wildcard policy")
+
 	var cfsslIssuers []ca.Issuer
 	var boulderIssuers []*issuance.Issuer
 	if features.Enabled(features.NonCFSSLSigner) {
@@ -293,14 +400,32 @@ func main() {
 	tlsConfig, err := c.CA.TLS.Load()
 	cmd.FailOnError(err, "TLS config")
 
+	var signers []crypto.Signer
+	for _, issuer := range cfsslIssuers {
+		signers = append(signers, issuer.Signer)
+	}
+	for _, issuer := range boulderIssuers {
+		signers = append(signers, issuer.Signer)
+	}
 	clk := cmd.Clock()
+	cmd.RegisterReadinessCheck("issuer-keys", signerHealthCheck(signers, clk))
 
 	clientMetrics := bgrpc.NewClientMetrics(scope)
 	conn, err := bgrpc.ClientSetup(c.CA.SAService, tlsConfig, clientMetrics, clk)
 	cmd.FailOnError(err, "Failed to load credentials and create gRPC connection to SA")
+	cmd.RegisterReadinessCheck("sa", bgrpc.HealthCheck(conn))
 	sa := bgrpc.NewStorageAuthorityClient(sapb.NewStorageAuthorityClient(conn))
 
-	kp, err := goodkey.NewKeyPolicy(c.CA.WeakKeyFile, c.CA.BlockedKeyFile, sa.KeyBlocked)
+	if c.CA.HostnamePolicyRefreshInterval.Duration > 0 {
+		pa.SetHostnamePolicySource(sa, c.CA.HostnamePolicyRefreshInterval.Duration)
+	}
+
+	kp, err := goodkey.NewKeyPolicy(&goodkey.Config{
+		WeakKeyFile:      c.CA.WeakKeyFile,
+		BlockedKeyFile:   c.CA.BlockedKeyFile,
+		FermatRounds:     c.CA.FermatRounds,
+		DisableROCACheck: c.CA.DisableROCACheck,
+	}, sa.KeyBlocked, scope)
 	cmd.FailOnError(err, "Unable to create key policy")
 
 	var orphanQueue *goque.Queue
@@ -333,6 +458,16 @@ func main() {
 		clk)
 	cmd.FailOnError(err, "Failed to create CA impl")
 
+	if c.CA.IssuanceLog != nil {
+		var storage issuancelog.Storage
+		if c.CA.IssuanceLog.StoreInSA {
+			storage = sa
+		}
+		issuanceLog, err := issuancelog.Open(c.CA.IssuanceLog.File, c.CA.IssuanceLog.ChainID, storage, clk, logger)
+		cmd.FailOnError(err, "Failed to open issuance log")
+		cai.SetIssuanceLog(issuanceLog)
+	}
+
 	if orphanQueue != nil {
 		go cai.OrphanIntegrationLoop()
 	}
@@ -344,6 +479,7 @@ func main() {
 	cmd.FailOnError(err, "Unable to setup CA gRPC server")
 	caWrapper := bgrpc.NewCertificateAuthorityServer(cai)
 	capb.RegisterCertificateAuthorityServer(caSrv, caWrapper)
+	policypb.RegisterHostnamePolicyRefresherServer(caSrv, policy.NewRefresherServer(pa))
 	caHealth := health.NewServer()
 	healthpb.RegisterHealthServer(caSrv, caHealth)
 	go func() {
@@ -1,7 +1,15 @@
 package main
 
 import (
+	"context"
+	"crypto"
+	"errors"
+	"io"
 	"testing"
+	"time"
+
+	"github.com/jmhodges/clock"
+	"github.com/letsencrypt/boulder/test"
 )
 
 func TestLoadIssuerSuccess(t *testing.T) {
@@ -39,3 +47,37 @@ func TestLoadIssuerBadCert(t *testing.T) {
 		t.Fatal("loadIssuer succeeded when loading key from /dev/null")
 	}
 }
+
+// countingSigner is a crypto.Signer which counts its signatures, failing them
+// with err if it's set.
+type countingSigner struct {
+	crypto.Signer
+	signatures int
+	err        error
+}
+
+func (s *countingSigner) Sign(_ io.Reader, _ []byte, _ crypto.SignerOpts) ([]byte, error) {
+	s.signatures++
+	return nil, s.err
+}
+
+func TestSignerHealthCheck(t *testing.T) {
+	signer := &countingSigner{}
+	clk := clock.NewFake()
+	check := signerHealthCheck([]crypto.Signer{signer}, clk)
+
+	test.AssertNotError(t, check(context.Background()), "healthy signer failed check")
+	test.AssertEquals(t, signer.signatures, 1)
+
+	// Within the interval, the last result is returned without signing.
+	signer.err = errors.New("HSM session lost")
+	clk.Add(signerCheckInterval - time.Second)
+	test.AssertNotError(t, check(context.Background()), "cached check failed")
+	test.AssertEquals(t, signer.signatures, 1)
+
+	clk.Add(time.Second)
+	test.AssertError(t, check(context.Background()), "broken signer passed check")
+	test.AssertEquals(t, signer.signatures, 2)
+	test.AssertError(t, check(context.Background()), "cached failure passed check")
+	test.AssertEquals(t, signer.signatures, 2)
+}
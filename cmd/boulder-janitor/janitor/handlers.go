@@ -12,6 +12,7 @@ import (
 var deleteHandlers = map[string]func(*batchedDBJob, int64) error{
 	"default":     deleteDefault,
 	"deleteOrder": deleteOrder,
+	"deleteAuthz": deleteAuthz,
 }
 
 // deleteDefault performs a delete of the given ID from the batchedDBJob's
@@ -64,3 +65,28 @@ func deleteOrder(j *batchedDBJob, orderID int64) error {
 	})
 	return err
 }
+
+// deleteAuthz performs a delete of the given ID from the batchedDBJob's `authz2`
+// table or returns an error. It also deletes the rows from the
+// `orderToAuthz2` table which reference the authorization.
+func deleteAuthz(j *batchedDBJob, authzID int64) error {
+	ctx := context.Background()
+	_, err := db.WithTransaction(ctx, j.db, func(txWithCtx db.Executor) (interface{}, error) {
+		res, err := txWithCtx.Exec(`DELETE FROM orderToAuthz2 WHERE authzID = ?`, authzID)
+		if err != nil {
+			return nil, err
+		}
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return nil, err
+		}
+		deletedStat.WithLabelValues("orderToAuthz2").Add(float64(affected))
+		if _, err := txWithCtx.Exec(`DELETE FROM authz2 WHERE id = ?`, authzID); err != nil {
+			return nil, err
+		}
+		deletedStat.WithLabelValues("authz2").Inc()
+		j.log.Debugf("deleted authz ID %d and associated rows", authzID)
+		return nil, nil
+	})
+	return err
+}
@@ -26,7 +26,7 @@ func TestDeleteOrder(t *testing.T) {
 	dbMap, err := sa.NewDbMap(vars.DBConnSA, sa.DbSettings{})
 	test.AssertNotError(t, err, "error creating db map")
 	// Create a SSA backed by the SA user dbMap
-	ssa, err := sa.NewSQLStorageAuthority(dbMap, fc, log, metrics.NoopRegisterer, 1)
+	ssa, err := sa.NewSQLStorageAuthority(dbMap, fc, log, metrics.NoopRegisterer, 1, 0, nil)
 	test.AssertNotError(t, err, "error creating SA")
 
 	// Don't forget to cleanup!
@@ -130,3 +130,72 @@ func TestDeleteOrder(t *testing.T) {
 	test.AssertNotError(t, err, "error finding orderFqdnSets rows")
 	test.AssertEquals(t, len(orderFqdnSetIDs), 0)
 }
+
+func TestDeleteAuthz(t *testing.T) {
+	ctx := context.Background()
+	log, fc := setup()
+
+	dbMap, err := sa.NewDbMap(vars.DBConnSA, sa.DbSettings{})
+	test.AssertNotError(t, err, "error creating db map")
+	ssa, err := sa.NewSQLStorageAuthority(dbMap, fc, log, metrics.NoopRegisterer, 1, 0, nil)
+	test.AssertNotError(t, err, "error creating SA")
+	defer func() {
+		test.ResetSATestDatabase(t)
+	}()
+
+	// Create a test registration, and an order with a pending authorization
+	reg, err := ssa.NewRegistration(ctx, core.Registration{
+		Key:       satest.GoodJWK(),
+		InitialIP: net.ParseIP("127.0.0.1"),
+	})
+	test.AssertNotError(t, err, "error creating test registration")
+	expires := fc.Now().Add(time.Hour).UTC().UnixNano()
+	ids, err := ssa.NewAuthorizations2(ctx, &sapb.AddPendingAuthorizationsRequest{Authz: []*corepb.Authorization{{
+		Identifier:     "test.example.com",
+		RegistrationID: reg.ID,
+		Status:         string(core.StatusPending),
+		Expires:        expires,
+		Challenges: []*corepb.Challenge{
+			{
+				Status: string(core.StatusPending),
+				Type:   string(core.ChallengeTypeDNS01),
+				Token:  "YXNkAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA",
+			},
+		},
+	}}})
+	test.AssertNotError(t, err, "error adding test authz2")
+	testOrder, err := ssa.NewOrder(ctx, &corepb.Order{
+		RegistrationID:   reg.ID,
+		Status:           string(core.StatusPending),
+		Expires:          expires,
+		Names:            []string{"test.example.com"},
+		V2Authorizations: []int64{ids.Ids[0]},
+	})
+	test.AssertNotError(t, err, "error creating test order")
+
+	janitorDbMap, err := sa.NewDbMap("janitor@tcp(boulder-mysql:3306)/boulder_sa_test", sa.DbSettings{})
+	test.AssertNotError(t, err, "error creating db map")
+	j := newJob(JobConfig{
+		Enabled:       true,
+		Table:         "authz2",
+		BatchSize:     1,
+		Parallelism:   1,
+		DeleteHandler: "deleteAuthz",
+	}, janitorDbMap, log, fc)
+
+	err = j.deleteHandler(j, ids.Ids[0])
+	test.AssertNotError(t, err, "error calling deleteHandler")
+
+	// The authorization should be gone
+	_, err = ssa.GetAuthorization2(ctx, &sapb.AuthorizationID2{Id: ids.Ids[0]})
+	test.AssertErrorIs(t, err, berrors.NotFound)
+
+	// And so should the orderToAuthz2 row referencing it
+	var authzIDs []int64
+	_, err = janitorDbMap.Select(
+		&authzIDs,
+		"SELECT authzID FROM orderToAuthz2 WHERE orderID = ?;",
+		testOrder.Id)
+	test.AssertNotError(t, err, "error finding orderToAuthz2 rows")
+	test.AssertEquals(t, len(authzIDs), 0)
+}
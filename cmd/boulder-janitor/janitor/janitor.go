@@ -58,6 +58,7 @@ func New(clk clock.Clock, config JanitorConfig) (*Janitor, error) {
 	scope, logger := cmd.StatsAndLogging(config.Syslog, config.DebugAddr)
 	scope.MustRegister(errStat)
 	scope.MustRegister(deletedStat)
+	scope.MustRegister(dryRunStat)
 	scope.MustRegister(workStat)
 	defer logger.AuditPanic()
 	logger.Info(cmd.VersionString())
@@ -78,6 +79,10 @@ func New(clk clock.Clock, config JanitorConfig) (*Janitor, error) {
 		return nil, err
 	}
 	sa.SetSQLDebug(dbMap, logger)
+	err = config.DBConfig.StartAdaptivePool(dbMap.Db, scope, logger)
+	if err != nil {
+		return nil, err
+	}
 
 	// Construct configured jobs
 	jobs, err := newJobs(config.JobConfigs, dbMap, logger, clk)
@@ -40,6 +40,15 @@ var (
 			Help: "Number of deletions by table the boulder-janitor has performed.",
 		},
 		[]string{"table"})
+	// dryRunStat is a prometheus counter vector tracking the number of rows
+	// which would have been deleted by jobs in dry-run mode, sliced by a table
+	// label.
+	dryRunStat = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "janitor_dry_run_deletions",
+			Help: "Number of deletions by table the boulder-janitor would have performed if its jobs weren't in dry-run mode.",
+		},
+		[]string{"table"})
 	// workStat is a prometheus counter vector tracking the number of rows found
 	// during a batchedJob's getWork stage and queued into the work channel sliced
 	// by a table label.
@@ -79,6 +88,10 @@ type JobConfig struct {
 	// DeleteHandler is the string name of a function (found in handlers.go) to
 	// use to handle deletion of rows.
 	DeleteHandler string
+	// DryRun makes the job find and count the rows it would delete, in the
+	// janitor_dry_run_deletions metric, without deleting them. It's intended
+	// for checking a new job's configuration before letting it delete rows.
+	DryRun bool
 }
 
 // batchedDBJob is a struct abstracting the common properties of a long running
@@ -112,6 +125,8 @@ type batchedDBJob struct {
 	// More complex deletion logic may be necessary e.g. if there are other
 	// tables with foreign keys which reference the given row.
 	deleteHandler func(job *batchedDBJob, id int64) error
+	// dryRun indicates that rows should be counted rather than deleted.
+	dryRun bool
 }
 
 func newJob(config JobConfig, dbMap db.DatabaseMap, log blog.Logger, clk clock.Clock) *batchedDBJob {
@@ -142,6 +157,7 @@ func newJob(config JobConfig, dbMap db.DatabaseMap, log blog.Logger, clk clock.C
 		maxDPS:        config.MaxDPS,
 		parallelism:   config.Parallelism,
 		deleteHandler: delete,
+		dryRun:        config.DryRun,
 	}
 }
 
@@ -234,8 +250,13 @@ LIMIT :limit`, j.expiresColumn, j.table)
 // that read ID values from the work channel and delete the corresponding table
 // rows. If the batchedDBJob configures a maxDPS rate then it will be enforced by
 // synchronizing the delete operations on a ticker based on the maxDPS.
-// cleanResource will block until all of the worker go routines complete.
+// cleanResource will block until all of the worker go routines complete. If the
+// batchedDBJob is in dry-run mode the rows are only counted.
 func (j batchedDBJob) cleanResource(work <-chan int64) {
+	if j.dryRun {
+		j.countResource(work)
+		return
+	}
 	wg := new(sync.WaitGroup)
 	deleted := int64(0)
 
@@ -270,6 +291,21 @@ func (j batchedDBJob) cleanResource(work <-chan int64) {
 		deleted, j.table)
 }
 
+// countResource reads ID values from the work channel and counts the rows
+// which would have been deleted, without deleting them. It blocks until the
+// work channel is closed.
+func (j batchedDBJob) countResource(work <-chan int64) {
+	var counted int64
+	for id := range work {
+		j.log.Debugf("dry run: would have deleted ID %d in table %q", id, j.table)
+		dryRunStat.WithLabelValues(j.table).Inc()
+		counted++
+	}
+	j.log.Infof(
+		"dry run: would have deleted a total of %d rows from table %q",
+		counted, j.table)
+}
+
 // RunForever starts a go routine that will run forever getting work with
 // getWork and deleting rows with cleanResource.
 func (j batchedDBJob) runForever() {
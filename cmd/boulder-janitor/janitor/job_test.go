@@ -307,6 +307,32 @@ func TestCleanResource(t *testing.T) {
 	test.AssertEquals(t, len(matches), 1)
 }
 
+func TestCleanResourceDryRun(t *testing.T) {
+	log, _ := setup()
+
+	// A mockDB with no expected query fails the test if it's used.
+	job := batchedDBJob{
+		db:            mockDB{t: t},
+		log:           log,
+		table:         "dryRunExample",
+		expiresColumn: "expires",
+		parallelism:   1,
+		deleteHandler: deleteDefault,
+		dryRun:        true,
+	}
+
+	work := make(chan int64, 2)
+	work <- 1
+	work <- 2
+	close(work)
+	job.cleanResource(work)
+
+	matches := log.GetAllMatching(`dry run: would have deleted a total of 2 rows from table "dryRunExample"`)
+	test.AssertEquals(t, len(matches), 1)
+	test.AssertEquals(t, test.CountCounterVec("table", "dryRunExample", dryRunStat), 2)
+	test.AssertEquals(t, test.CountCounterVec("table", "dryRunExample", deletedStat), 0)
+}
+
 func TestBatchedDBJobValid(t *testing.T) {
 	testCases := []struct {
 		name        string
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"flag"
 	"os"
 	"runtime"
@@ -26,6 +27,18 @@ type config struct {
 		// https://golang.org/pkg/runtime/#SetBlockProfileRate
 		BlockProfileRate int
 		UserAgent        string
+
+		// FinalCertQueue configures the queue final certificates are kept in
+		// until they've been submitted to CT logs, for QueueFinalCert requests,
+		// which are rejected if it has no Dir.
+		FinalCertQueue struct {
+			Dir               string
+			Workers           int
+			SubmissionTimeout cmd.ConfigDuration
+			RetryBackoff      cmd.ConfigDuration
+			MaxRetryBackoff   cmd.ConfigDuration
+			MaxAge            cmd.ConfigDuration
+		}
 	}
 
 	Syslog cmd.SyslogConfig
@@ -37,10 +50,26 @@ type config struct {
 	}
 }
 
+// loadBundle loads the intermediate certificates to be submitted to CT logs
+// along with each certificate.
+func loadBundle(filename string) ([]ct.ASN1Cert, error) {
+	pemBundle, err := core.LoadCertBundle(filename)
+	if err != nil {
+		return nil, err
+	}
+	bundle := []ct.ASN1Cert{}
+	for _, cert := range pemBundle {
+		bundle = append(bundle, ct.ASN1Cert{Data: cert.Raw})
+	}
+	return bundle, nil
+}
+
 func main() {
 	grpcAddr := flag.String("addr", "", "gRPC listen address override")
 	debugAddr := flag.String("debug-addr", "", "Debug server address override")
+	queueDir := flag.String("final-cert-queue-dir", "", "Final certificate queue directory override")
 	configFile := flag.String("config", "", "File path to the configuration file for this service")
+	checkConfig := cmd.CheckConfigFlag()
 	flag.Parse()
 	if *configFile == "" {
 		flag.Usage()
@@ -50,6 +79,15 @@ func main() {
 	var c config
 	err := cmd.ReadConfigFile(*configFile, &c)
 	cmd.FailOnError(err, "Reading JSON config file into config structure")
+	if *checkConfig {
+		cmd.CheckConfig(&c, cmd.ConfigCheck{
+			Name: "Common.CT.IntermediateBundleFilename",
+			Check: func() error {
+				_, err := loadBundle(c.Common.CT.IntermediateBundleFilename)
+				return err
+			},
+		})
+	}
 	err = features.Set(c.Publisher.Features)
 	cmd.FailOnError(err, "Failed to set feature flags")
 
@@ -61,6 +99,9 @@ func main() {
 	if *debugAddr != "" {
 		c.Publisher.DebugAddr = *debugAddr
 	}
+	if *queueDir != "" {
+		c.Publisher.FinalCertQueue.Dir = *queueDir
+	}
 	if c.Publisher.UserAgent == "" {
 		c.Publisher.UserAgent = "certificate-transparency-go/1.0"
 	}
@@ -73,12 +114,8 @@ func main() {
 		logger.AuditErr("No CT submission bundle provided")
 		os.Exit(1)
 	}
-	pemBundle, err := core.LoadCertBundle(c.Common.CT.IntermediateBundleFilename)
+	bundle, err := loadBundle(c.Common.CT.IntermediateBundleFilename)
 	cmd.FailOnError(err, "Failed to load CT submission bundle")
-	bundle := []ct.ASN1Cert{}
-	for _, cert := range pemBundle {
-		bundle = append(bundle, ct.ASN1Cert{Data: cert.Raw})
-	}
 
 	tlsConfig, err := c.Publisher.TLS.Load()
 	cmd.FailOnError(err, "TLS config")
@@ -91,6 +128,35 @@ func main() {
 		logger,
 		scope)
 
+	if c.Publisher.FinalCertQueue.Dir != "" {
+		qc := c.Publisher.FinalCertQueue
+		err = pubi.EnableFinalCertQueue(publisher.FinalCertQueueConfig{
+			Dir:               qc.Dir,
+			Workers:           qc.Workers,
+			SubmissionTimeout: qc.SubmissionTimeout.Duration,
+			RetryBackoff:      qc.RetryBackoff.Duration,
+			MaxRetryBackoff:   qc.MaxRetryBackoff.Duration,
+			MaxAge:            qc.MaxAge.Duration,
+		}, clk, scope)
+		cmd.FailOnError(err, "Failed to load final certificate queue")
+	}
+
+	// The CT submission bundle can be changed without a restart, by sending
+	// SIGHUP once the new bundle is in place.
+	reloader := cmd.NewConfigReloader(*configFile, func() interface{} { return &config{} }, logger)
+	reloader.Register("Common.CT.IntermediateBundleFilename", func(newConfig interface{}) (func(), error) {
+		filename := newConfig.(*config).Common.CT.IntermediateBundleFilename
+		if filename == "" {
+			return nil, errors.New("no CT submission bundle provided")
+		}
+		bundle, err := loadBundle(filename)
+		if err != nil {
+			return nil, err
+		}
+		return func() { pubi.SetIssuerBundle(bundle) }, nil
+	})
+	reloader.Start()
+
 	serverMetrics := bgrpc.NewServerMetrics(scope)
 	grpcSrv, l, err := bgrpc.NewServer(c.Publisher.GRPC, tlsConfig, serverMetrics, clk)
 	cmd.FailOnError(err, "Unable to setup Publisher gRPC server")
@@ -102,6 +168,7 @@ func main() {
 	go cmd.CatchSignals(logger, func() {
 		hs.Shutdown()
 		grpcSrv.GracefulStop()
+		pubi.StopFinalCertQueue()
 	})
 
 	err = cmd.FilterShutdownErrors(grpcSrv.Serve(l))
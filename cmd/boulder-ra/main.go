@@ -10,6 +10,7 @@ import (
 	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 
 	akamaipb "github.com/letsencrypt/boulder/akamai/proto"
+	"github.com/letsencrypt/boulder/bdns"
 	capb "github.com/letsencrypt/boulder/ca/proto"
 	"github.com/letsencrypt/boulder/cmd"
 	"github.com/letsencrypt/boulder/ctpolicy"
@@ -19,6 +20,7 @@ import (
 	bgrpc "github.com/letsencrypt/boulder/grpc"
 	"github.com/letsencrypt/boulder/issuance"
 	"github.com/letsencrypt/boulder/policy"
+	policypb "github.com/letsencrypt/boulder/policy/proto"
 	pubpb "github.com/letsencrypt/boulder/publisher/proto"
 	"github.com/letsencrypt/boulder/ra"
 	rapb "github.com/letsencrypt/boulder/ra/proto"
@@ -35,6 +37,32 @@ type config struct {
 
 		MaxContactsPerRegistration int
 
+		// ContactDomainChecks enables optional checks of the domains of
+		// account contact email addresses, at registration and update.
+		ContactDomainChecks *struct {
+			// BlockedDomains are domains, such as common typos of popular mail
+			// providers, at which, and under which, contact email addresses
+			// are rejected.
+			BlockedDomains []string
+			// DNSResolvers, if set, are used to check that contact email
+			// domains exist and can receive mail, having MX records other
+			// than a null MX, or else addresses.
+			DNSResolvers []string
+			// DNSTimeout defaults to 10 seconds, and DNSTries to 1.
+			DNSTimeout cmd.ConfigDuration
+			DNSTries   int
+		}
+
+		// RolledOverKeyBlockPeriod, if set, is how long after an account's
+		// key is rolled over the old key is blocked from registering new
+		// accounts. It requires the SA's KeyRolloverHistory feature.
+		RolledOverKeyBlockPeriod cmd.ConfigDuration
+
+		// ObjectStorage, if set, is the bucket the ocsp-updater writes OCSP
+		// responses to, to which the RA writes the response for each
+		// certificate it revokes.
+		ObjectStorage *cmd.ObjectStorageConfig
+
 		SAService           *cmd.GRPCClientConfig
 		VAService           *cmd.GRPCClientConfig
 		CAService           *cmd.GRPCClientConfig
@@ -70,8 +98,27 @@ type config struct {
 		// administratively blocked.
 		BlockedKeyFile string
 
+		// FermatRounds is the number of rounds of Fermat's factorization method
+		// to attempt against RSA keys, to detect keys whose prime factors are
+		// too close together. If zero, the check is disabled.
+		FermatRounds int
+
+		// DisableROCACheck disables rejection of RSA keys generated by
+		// Infineon hardware vulnerable to ROCA.
+		DisableROCACheck bool
+
 		OrderLifetime cmd.ConfigDuration
 
+		// CertificateProfiles are the certificate profiles which orders may
+		// request, configuring the lifetimes of the orders which request each
+		// and of the pending authorizations created for them. Unset lifetimes
+		// use OrderLifetime and PendingAuthorizationLifetimeDays. Orders which
+		// request any other profile are rejected.
+		CertificateProfiles map[string]struct {
+			OrderLifetime                cmd.ConfigDuration
+			PendingAuthorizationLifetime cmd.ConfigDuration
+		}
+
 		// CTLogGroups contains groupings of CT logs which we want SCTs from.
 		// When we retrieve SCTs we will submit the certificate to each log
 		// in a group and the first SCT returned will be used. This allows
@@ -83,6 +130,11 @@ type config struct {
 		// test them or because they are not yet approved by a browser/root
 		// program but we still want our certs to end up there.
 		InformationalCTLogs []ctconfig.LogDescription
+		// CTMinOperators is the number of distinct log operators, named by the
+		// logs' operator fields, whose SCTs every certificate must have. If the
+		// groups' SCTs are from fewer, more are obtained from other operators'
+		// logs. Zero disables the check.
+		CTMinOperators int
 
 		// IssuerCertPath is the path to the intermediate used to issue certificates.
 		// It is used to generate OCSP URLs to purge at revocation time.
@@ -94,6 +146,12 @@ type config struct {
 		IssuerCerts []string
 
 		Features map[string]bool
+		// FeatureRollouts enables features for a percentage of accounts. See
+		// features.SetRollouts.
+		FeatureRollouts map[string]float64
+		// FeatureOverrideCacheTTL is how long each account's feature overrides,
+		// as set with the admin tool, are cached for. Defaults to one minute.
+		FeatureOverrideCacheTTL cmd.ConfigDuration
 	}
 
 	PA cmd.PAConfig
@@ -105,6 +163,7 @@ func main() {
 	grpcAddr := flag.String("addr", "", "gRPC listen address override")
 	debugAddr := flag.String("debug-addr", "", "Debug server address override")
 	configFile := flag.String("config", "", "File path to the configuration file for this service")
+	checkConfig := cmd.CheckConfigFlag()
 	flag.Parse()
 	if *configFile == "" {
 		flag.Usage()
@@ -114,9 +173,14 @@ func main() {
 	var c config
 	err := cmd.ReadConfigFile(*configFile, &c)
 	cmd.FailOnError(err, "Reading JSON config file into config structure")
+	if *checkConfig {
+		cmd.CheckConfig(&c)
+	}
 
 	err = features.Set(c.RA.Features)
 	cmd.FailOnError(err, "Failed to set feature flags")
+	err = features.SetRollouts(c.RA.FeatureRollouts)
+	cmd.FailOnError(err, "Failed to set feature rollouts")
 
 	if *grpcAddr != "" {
 		c.RA.GRPC.Address = *grpcAddr
@@ -141,6 +205,13 @@ func main() {
 	err = pa.SetHostnamePolicyFile(c.RA.HostnamePolicyFile)
 	cmd.FailOnError(err, "Couldn't load hostname policy file")
 
+	err = pa.SetWildcardPolicy(policy.WildcardPolicy{
+		MaxDepth:            c.PA.MaxWildcardDepth,
+		ForbiddenDomains:    c.PA.ForbiddenWildcardDomains,
+		RequireRevalidation: c.PA.RequireWildcardRevalidation,
+	})
+	cmd.FailOnError(err, "Invalid wildcard policy")
+
 	tlsConfig, err := c.RA.TLS.Load()
 	cmd.FailOnError(err, "TLS config")
 
@@ -149,21 +220,25 @@ func main() {
 	clientMetrics := bgrpc.NewClientMetrics(scope)
 	vaConn, err := bgrpc.ClientSetup(c.RA.VAService, tlsConfig, clientMetrics, clk)
 	cmd.FailOnError(err, "Unable to create VA client")
+	cmd.RegisterReadinessCheck("va", bgrpc.HealthCheck(vaConn))
 	vac := bgrpc.NewValidationAuthorityGRPCClient(vaConn)
 
 	caaClient := vapb.NewCAAClient(vaConn)
 
 	caConn, err := bgrpc.ClientSetup(c.RA.CAService, tlsConfig, clientMetrics, clk)
 	cmd.FailOnError(err, "Unable to create CA client")
+	cmd.RegisterReadinessCheck("ca", bgrpc.HealthCheck(caConn))
 	cac := bgrpc.NewCertificateAuthorityClient(capb.NewCertificateAuthorityClient(caConn))
 
 	var ctp *ctpolicy.CTPolicy
 	conn, err := bgrpc.ClientSetup(c.RA.PublisherService, tlsConfig, clientMetrics, clk)
 	cmd.FailOnError(err, "Failed to load credentials and create gRPC connection to Publisher")
+	cmd.RegisterReadinessCheck("publisher", bgrpc.HealthCheck(conn))
 	pubc := bgrpc.NewPublisherClientWrapper(pubpb.NewPublisherClient(conn))
 
 	apConn, err := bgrpc.ClientSetup(c.RA.AkamaiPurgerService, tlsConfig, clientMetrics, clk)
 	cmd.FailOnError(err, "Unable to create a Akamai Purger client")
+	cmd.RegisterReadinessCheck("akamai-purger", bgrpc.HealthCheck(apConn))
 	apc := akamaipb.NewAkamaiPurgerClient(apConn)
 
 	issuerCertPaths := c.RA.IssuerCerts
@@ -197,12 +272,22 @@ func main() {
 			}
 		}
 	}
-	ctp = ctpolicy.New(pubc, c.RA.CTLogGroups2, c.RA.InformationalCTLogs, logger, scope)
+	ctp, err = ctpolicy.New(pubc, c.RA.CTLogGroups2, c.RA.InformationalCTLogs, logger, scope)
+	cmd.FailOnError(err, "Failed to create CT policy")
+	err = ctp.SetMinOperators(c.RA.CTMinOperators)
+	cmd.FailOnError(err, "Failed to configure CT log operator diversity")
 
 	saConn, err := bgrpc.ClientSetup(c.RA.SAService, tlsConfig, clientMetrics, clk)
 	cmd.FailOnError(err, "Failed to load credentials and create gRPC connection to SA")
+	cmd.RegisterReadinessCheck("sa", bgrpc.HealthCheck(saConn))
 	sac := bgrpc.NewStorageAuthorityClient(sapb.NewStorageAuthorityClient(saConn))
 
+	if c.RA.HostnamePolicyRefreshInterval.Duration > 0 {
+		pa.SetHostnamePolicySource(sac, c.RA.HostnamePolicyRefreshInterval.Duration)
+	}
+
+	cmd.SetFeatureOverrideLookup(sac, c.RA.FeatureOverrideCacheTTL.Duration, clk)
+
 	// TODO(patf): remove once RA.authorizationLifetimeDays is deployed
 	authorizationLifetime := 300 * 24 * time.Hour
 	if c.RA.AuthorizationLifetimeDays != 0 {
@@ -215,7 +300,12 @@ func main() {
 		pendingAuthorizationLifetime = time.Duration(c.RA.PendingAuthorizationLifetimeDays) * 24 * time.Hour
 	}
 
-	kp, err := goodkey.NewKeyPolicy(c.RA.WeakKeyFile, c.RA.BlockedKeyFile, sac.KeyBlocked)
+	kp, err := goodkey.NewKeyPolicy(&goodkey.Config{
+		WeakKeyFile:      c.RA.WeakKeyFile,
+		BlockedKeyFile:   c.RA.BlockedKeyFile,
+		FermatRounds:     c.RA.FermatRounds,
+		DisableROCACheck: c.RA.DisableROCACheck,
+	}, sac.KeyBlocked, scope)
 	cmd.FailOnError(err, "Unable to create key policy")
 
 	if c.RA.MaxNames == 0 {
@@ -242,8 +332,38 @@ func main() {
 
 	policyErr := rai.SetRateLimitPoliciesFile(c.RA.RateLimitPoliciesFilename)
 	cmd.FailOnError(policyErr, "Couldn't load rate limit policies file")
+
+	profileLifetimes := make(map[string]ra.ProfileLifetimes, len(c.RA.CertificateProfiles))
+	for name, profile := range c.RA.CertificateProfiles {
+		profileLifetimes[name] = ra.ProfileLifetimes{
+			OrderLifetime:                profile.OrderLifetime.Duration,
+			PendingAuthorizationLifetime: profile.PendingAuthorizationLifetime.Duration,
+		}
+	}
+	err = rai.SetProfileLifetimes(profileLifetimes)
+	cmd.FailOnError(err, "Invalid certificate profile lifetimes")
 	rai.PA = pa
 
+	if cdc := c.RA.ContactDomainChecks; cdc != nil {
+		var resolver bdns.Client
+		if len(cdc.DNSResolvers) > 0 {
+			if cdc.DNSTimeout.Duration == 0 {
+				cdc.DNSTimeout.Duration = 10 * time.Second
+			}
+			if cdc.DNSTries < 1 {
+				cdc.DNSTries = 1
+			}
+			resolver = bdns.New(cdc.DNSTimeout.Duration, cdc.DNSResolvers, scope, clk, cdc.DNSTries, logger)
+		}
+		rai.SetContactDomainChecks(cdc.BlockedDomains, resolver)
+	}
+	rai.SetRolledOverKeyBlockPeriod(c.RA.RolledOverKeyBlockPeriod.Duration)
+	if c.RA.ObjectStorage != nil {
+		objects, err := c.RA.ObjectStorage.Load(clk)
+		cmd.FailOnError(err, "Failed to load object storage config")
+		rai.SetOCSPObjectStorage(objects)
+	}
+
 	rai.VA = vac
 	rai.CA = cac
 	rai.SA = sac
@@ -253,6 +373,7 @@ func main() {
 	cmd.FailOnError(err, "Unable to setup RA gRPC server")
 	gw := bgrpc.NewRegistrationAuthorityServer(rai)
 	rapb.RegisterRegistrationAuthorityServer(grpcSrv, gw)
+	policypb.RegisterHostnamePolicyRefresherServer(grpcSrv, policy.NewRefresherServer(pa))
 	hs := health.NewServer()
 	healthpb.RegisterHealthServer(grpcSrv, hs)
 
@@ -1,13 +1,16 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"os"
+	"time"
 
 	"google.golang.org/grpc/health"
 	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 
 	"github.com/letsencrypt/boulder/cmd"
+	"github.com/letsencrypt/boulder/db"
 	"github.com/letsencrypt/boulder/features"
 	bgrpc "github.com/letsencrypt/boulder/grpc"
 	"github.com/letsencrypt/boulder/sa"
@@ -23,6 +26,20 @@ type config struct {
 
 		// Max simultaneous SQL queries caused by a single RPC.
 		ParallelismPerRPC int
+
+		// Max rows written by a single multi-row INSERT statement, e.g. when
+		// storing the authorizations for a new order. Defaults to 100.
+		MaxInsertBatchSize int
+
+		// ContactEncryption, if present, causes registration contacts to be
+		// encrypted before they are written to the database. Tools which
+		// read the registrations table directly can't use encrypted
+		// contacts, so this shouldn't be enabled until they've been updated.
+		ContactEncryption *cmd.ContactEncryptionConfig
+
+		// AccountEventRetention is how long accounts' audit events are kept
+		// for. Defaults to 90 days.
+		AccountEventRetention cmd.ConfigDuration
 	}
 
 	Syslog cmd.SyslogConfig
@@ -32,6 +49,7 @@ func main() {
 	grpcAddr := flag.String("addr", "", "gRPC listen address override")
 	debugAddr := flag.String("debug-addr", "", "Debug server address override")
 	configFile := flag.String("config", "", "File path to the configuration file for this service")
+	checkConfig := cmd.CheckConfigFlag()
 	flag.Parse()
 	if *configFile == "" {
 		flag.Usage()
@@ -41,6 +59,9 @@ func main() {
 	var c config
 	err := cmd.ReadConfigFile(*configFile, &c)
 	cmd.FailOnError(err, "Reading JSON config file into config structure")
+	if *checkConfig {
+		cmd.CheckConfig(&c)
+	}
 
 	err = features.Set(c.SA.Features)
 	cmd.FailOnError(err, "Failed to set feature flags")
@@ -69,18 +90,54 @@ func main() {
 
 	dbMap, err := sa.NewDbMap(dbURL, saDbSettings)
 	cmd.FailOnError(err, "Couldn't connect to SA database")
+	err = sa.CheckSchemaVersion(context.Background(), dbMap.Db, sa.ExpectedSchemaVersion)
+	cmd.FailOnError(err, "SA database schema doesn't match, migrate it with sa-migrate")
+	cmd.RegisterReadinessCheck("db", dbMap.Db.PingContext)
 
 	// Collect and periodically report DB metrics using the DBMap and prometheus scope.
 	sa.InitDBMetrics(dbMap, scope, saDbSettings)
 
+	err = saConf.DBConfig.StartAdaptivePool(dbMap.Db, scope, logger)
+	cmd.FailOnError(err, "Invalid adaptive DB pool config")
+
 	clk := cmd.Clock()
 
 	parallel := saConf.ParallelismPerRPC
 	if parallel < 1 {
 		parallel = 1
 	}
-	sai, err := sa.NewSQLStorageAuthority(dbMap, clk, logger, scope, parallel)
+	batchSize := saConf.MaxInsertBatchSize
+	if batchSize < 1 {
+		batchSize = 100
+	}
+	var contactCipher *sa.ContactCipher
+	if saConf.ContactEncryption != nil {
+		keys, err := saConf.ContactEncryption.Keys()
+		cmd.FailOnError(err, "Failed to load contact encryption keys")
+		contactCipher, err = sa.NewContactCipher(keys, saConf.ContactEncryption.ActiveKeyID)
+		cmd.FailOnError(err, "Failed to create contact cipher")
+	}
+	sai, err := sa.NewSQLStorageAuthority(dbMap, clk, logger, scope, parallel, batchSize, contactCipher)
 	cmd.FailOnError(err, "Failed to create SA impl")
+	if retries := saConf.DBConfig.TransactionRetries; retries != nil {
+		backoff := retries.Backoff.Duration
+		if backoff == 0 {
+			backoff = 10 * time.Millisecond
+		}
+		maxBackoff := retries.MaxBackoff.Duration
+		if maxBackoff == 0 {
+			maxBackoff = 200 * time.Millisecond
+		}
+		sai.SetTransactionRetryPolicy(db.RetryPolicy{
+			MaxRetries: retries.MaxRetries,
+			Backoff:    backoff,
+			MaxBackoff: maxBackoff,
+		})
+	}
+
+	if saConf.AccountEventRetention.Duration > 0 {
+		sai.SetAccountEventRetention(saConf.AccountEventRetention.Duration)
+	}
 
 	tls, err := c.SA.TLS.Load()
 	cmd.FailOnError(err, "TLS config")
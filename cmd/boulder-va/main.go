@@ -2,6 +2,9 @@ package main
 
 import (
 	"flag"
+	"fmt"
+	"io/ioutil"
+	netmail "net/mail"
 	"os"
 	"time"
 
@@ -12,6 +15,8 @@ import (
 	"github.com/letsencrypt/boulder/cmd"
 	"github.com/letsencrypt/boulder/features"
 	bgrpc "github.com/letsencrypt/boulder/grpc"
+	bmail "github.com/letsencrypt/boulder/mail"
+	sapb "github.com/letsencrypt/boulder/sa/proto"
 	"github.com/letsencrypt/boulder/va"
 	vapb "github.com/letsencrypt/boulder/va/proto"
 )
@@ -40,12 +45,55 @@ type config struct {
 		DNSTries     int
 		DNSResolvers []string
 
+		// DNSCacheSize, if positive, is the number of DNS responses cached
+		// for reuse by later validations, for up to the lesser of their TTLs
+		// and DNSCacheMaxTTL, which defaults to one minute.
+		DNSCacheSize   int
+		DNSCacheMaxTTL cmd.ConfigDuration
+
 		RemoteVAs                   []cmd.GRPCClientConfig
 		MaxRemoteValidationFailures int
 
 		Features map[string]bool
+		// FeatureRollouts enables features for a percentage of accounts. See
+		// features.SetRollouts.
+		FeatureRollouts map[string]float64
+		// SAService, if set, is used to look up the per-account feature
+		// overrides set with the admin tool, which are cached for
+		// FeatureOverrideCacheTTL, one minute by default. Without it overrides
+		// are ignored.
+		SAService               *cmd.GRPCClientConfig
+		FeatureOverrideCacheTTL cmd.ConfigDuration
 
 		AccountURIPrefixes []string
+
+		// EmailReply configures the validation of email-reply-00 challenges,
+		// which are rejected if it's absent. The mail server delivering replies
+		// into Maildir must only deliver mail whose sender it has
+		// authenticated, with DMARC for instance.
+		EmailReply *struct {
+			cmd.SMTPConfig
+			// From is the address challenge emails are sent from, to which
+			// replies are sent.
+			From string
+			// SecretFile holds the key with which the token-part1 sent in
+			// challenge emails is derived from challenges' tokens.
+			SecretFile string
+			Maildir    string
+			// ResendInterval is how long after a challenge email is sent that
+			// it's sent again, if the challenge is retried without a reply
+			// having been received. It defaults to one hour.
+			ResendInterval cmd.ConfigDuration
+		}
+
+		// HTTP01RedirectPolicy configures which redirects are followed while
+		// validating HTTP-01 challenges. Its zero value is the default policy.
+		HTTP01RedirectPolicy va.RedirectPolicy
+
+		// ChallengeLimits configures, by challenge type, connect timeouts,
+		// attempt budgets and retries of transient failures. Challenge types
+		// without limits use the VA's defaults.
+		ChallengeLimits map[string]va.ChallengeLimits
 	}
 
 	Syslog cmd.SyslogConfig
@@ -61,6 +109,7 @@ func main() {
 	grpcAddr := flag.String("addr", "", "gRPC listen address override")
 	debugAddr := flag.String("debug-addr", "", "Debug server address override")
 	configFile := flag.String("config", "", "File path to the configuration file for this service")
+	checkConfig := cmd.CheckConfigFlag()
 	flag.Parse()
 	if *configFile == "" {
 		flag.Usage()
@@ -70,9 +119,14 @@ func main() {
 	var c config
 	err := cmd.ReadConfigFile(*configFile, &c)
 	cmd.FailOnError(err, "Reading JSON config file into config structure")
+	if *checkConfig {
+		cmd.CheckConfig(&c)
+	}
 
 	err = features.Set(c.VA.Features)
 	cmd.FailOnError(err, "Failed to set feature flags")
+	err = features.SetRollouts(c.VA.FeatureRollouts)
+	cmd.FailOnError(err, "Failed to set feature rollouts")
 
 	if *grpcAddr != "" {
 		c.VA.GRPC.Address = *grpcAddr
@@ -130,6 +184,16 @@ func main() {
 			logger)
 		resolver = r
 	}
+	if c.VA.DNSCacheSize > 0 {
+		if c.VA.DNSCacheMaxTTL.Duration == 0 {
+			c.VA.DNSCacheMaxTTL.Duration = time.Minute
+		}
+		err = bdns.EnableCache(resolver, bdns.CacheConfig{
+			Size:   c.VA.DNSCacheSize,
+			MaxTTL: c.VA.DNSCacheMaxTTL.Duration,
+		})
+		cmd.FailOnError(err, "Couldn't enable DNS cache")
+	}
 
 	tlsConfig, err := c.VA.TLS.Load()
 	cmd.FailOnError(err, "tlsConfig config")
@@ -151,6 +215,13 @@ func main() {
 		}
 	}
 
+	if c.VA.SAService != nil {
+		saConn, err := bgrpc.ClientSetup(c.VA.SAService, tlsConfig, clientMetrics, clk)
+		cmd.FailOnError(err, "Failed to load credentials and create gRPC connection to SA")
+		sac := bgrpc.NewStorageAuthorityClient(sapb.NewStorageAuthorityClient(saConn))
+		cmd.SetFeatureOverrideLookup(sac, c.VA.FeatureOverrideCacheTTL.Duration, clk)
+	}
+
 	vai, err := va.NewValidationAuthorityImpl(
 		pc,
 		resolver,
@@ -164,6 +235,32 @@ func main() {
 		c.VA.AccountURIPrefixes)
 	cmd.FailOnError(err, "Unable to create VA server")
 
+	err = vai.SetRedirectPolicy(c.VA.HTTP01RedirectPolicy)
+	cmd.FailOnError(err, "Invalid HTTP-01 redirect policy")
+
+	err = vai.SetChallengeLimits(c.VA.ChallengeLimits)
+	cmd.FailOnError(err, "Invalid challenge limits")
+
+	if c.VA.EmailReply != nil {
+		er := c.VA.EmailReply
+		from, err := netmail.ParseAddress(er.From)
+		cmd.FailOnError(err, fmt.Sprintf("Could not parse emailReply from address: %s", er.From))
+		password, err := er.PasswordConfig.Pass()
+		cmd.FailOnError(err, "Failed to load emailReply SMTP password")
+		secret, err := ioutil.ReadFile(er.SecretFile)
+		cmd.FailOnError(err, "Failed to load emailReply secret")
+		if er.ResendInterval.Duration == 0 {
+			er.ResendInterval.Duration = time.Hour
+		}
+		err = vai.SetEmailReply(va.EmailReplyConfig{
+			Mailer:         bmail.New(er.Server, er.Port, er.Username, password, nil, *from, logger, scope, time.Second, time.Minute),
+			Replies:        va.MaildirReplies{Dir: er.Maildir},
+			Secret:         secret,
+			ResendInterval: er.ResendInterval.Duration,
+		})
+		cmd.FailOnError(err, "Unable to configure email-reply-00 validation")
+	}
+
 	serverMetrics := bgrpc.NewServerMetrics(scope)
 	grpcSrv, l, err := bgrpc.NewServer(c.VA.GRPC, tlsConfig, serverMetrics, clk)
 	cmd.FailOnError(err, "Unable to setup VA gRPC server")
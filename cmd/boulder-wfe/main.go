@@ -18,6 +18,7 @@ import (
 	bgrpc "github.com/letsencrypt/boulder/grpc"
 	"github.com/letsencrypt/boulder/issuance"
 	blog "github.com/letsencrypt/boulder/log"
+	"github.com/letsencrypt/boulder/nonce"
 	noncepb "github.com/letsencrypt/boulder/nonce/proto"
 	rapb "github.com/letsencrypt/boulder/ra/proto"
 	sapb "github.com/letsencrypt/boulder/sa/proto"
@@ -53,6 +54,18 @@ type config struct {
 		// this should contain all nonce-services from all DCs as we want to be
 		// able to redeem nonces generated at any DC.
 		RedeemNonceServices map[string]cmd.GRPCClientConfig
+		// DerivedPrefixNonceServices contains gRPC configs for nonce-services
+		// whose prefixes are derived from NoncePrefixKey rather than listed
+		// explicitly. It may be used alongside RedeemNonceServices.
+		DerivedPrefixNonceServices []cmd.GRPCClientConfig
+		// NoncePrefixKey is the HMAC key the nonce-services in
+		// DerivedPrefixNonceServices derive their prefixes from.
+		NoncePrefixKey *cmd.PasswordConfig
+		// PreviousNoncePrefixKey, if set, is the key being rotated away from.
+		// Prefixes derived from both keys are routed to the same nonce-service
+		// so that nonces generated before and after the rotation can be
+		// redeemed.
+		PreviousNoncePrefixKey *cmd.PasswordConfig
 
 		Features map[string]bool
 
@@ -68,6 +81,20 @@ type config struct {
 		// SHA256 hashes of SubjectPublicKeyInfo's that should be considered
 		// administratively blocked.
 		BlockedKeyFile string
+
+		// WeakKeyFile is the path to a JSON file containing truncated RSA modulus
+		// hashes of known easily enumerable keys. If set, account keys on the
+		// list are rejected.
+		WeakKeyFile string
+
+		// FermatRounds is the number of rounds of Fermat's factorization method
+		// to attempt against RSA keys, to detect keys whose prime factors are
+		// too close together. If zero, the check is disabled.
+		FermatRounds int
+
+		// DisableROCACheck disables rejection of RSA keys generated by
+		// Infineon hardware vulnerable to ROCA.
+		DisableROCACheck bool
 	}
 
 	Syslog cmd.SyslogConfig
@@ -102,6 +129,33 @@ func setupWFE(c config, logger blog.Logger, stats prometheus.Registerer, clk clo
 			cmd.FailOnError(err, "Failed to load credentials and create gRPC connection to redeem nonce service")
 			npm[prefix] = noncepb.NewNonceServiceClient(conn)
 		}
+		if len(c.WFE.DerivedPrefixNonceServices) > 0 {
+			if c.WFE.NoncePrefixKey == nil {
+				cmd.Fail("NoncePrefixKey must be configured when using DerivedPrefixNonceServices")
+			}
+			var keys [][]byte
+			for _, keyConfig := range []*cmd.PasswordConfig{c.WFE.NoncePrefixKey, c.WFE.PreviousNoncePrefixKey} {
+				if keyConfig == nil {
+					continue
+				}
+				key, err := keyConfig.Pass()
+				cmd.FailOnError(err, "Failed to load nonce prefix key")
+				keys = append(keys, []byte(key))
+			}
+			for _, serviceConfig := range c.WFE.DerivedPrefixNonceServices {
+				serviceConfig := serviceConfig
+				conn, err := bgrpc.ClientSetup(&serviceConfig, tlsConfig, clientMetrics, clk)
+				cmd.FailOnError(err, "Failed to load credentials and create gRPC connection to redeem nonce service")
+				client := noncepb.NewNonceServiceClient(conn)
+				for _, key := range keys {
+					prefix := nonce.DerivePrefix(key, serviceConfig.ServerAddress)
+					if _, present := npm[prefix]; present {
+						cmd.Fail(fmt.Sprintf("Nonce prefix %q for %s is already in use", prefix, serviceConfig.ServerAddress))
+					}
+					npm[prefix] = client
+				}
+			}
+		}
 	}
 
 	return rac, sac, rns, npm
@@ -125,6 +179,7 @@ func (ew errorWriter) Write(p []byte) (n int, err error) {
 
 func main() {
 	configFile := flag.String("config", "", "File path to the configuration file for this service")
+	checkConfig := cmd.CheckConfigFlag()
 	flag.Parse()
 	if *configFile == "" {
 		flag.Usage()
@@ -134,6 +189,9 @@ func main() {
 	var c config
 	err := cmd.ReadConfigFile(*configFile, &c)
 	cmd.FailOnError(err, "Reading JSON config file into config structure")
+	if *checkConfig {
+		cmd.CheckConfig(&c)
+	}
 
 	err = features.Set(c.WFE.Features)
 	cmd.FailOnError(err, "Failed to set feature flags")
@@ -145,8 +203,12 @@ func main() {
 	clk := cmd.Clock()
 
 	rac, sac, rns, npm := setupWFE(c, logger, stats, clk)
-	// don't load any weak keys, but do load blocked keys
-	kp, err := goodkey.NewKeyPolicy("", c.WFE.BlockedKeyFile, sac.KeyBlocked)
+	kp, err := goodkey.NewKeyPolicy(&goodkey.Config{
+		WeakKeyFile:      c.WFE.WeakKeyFile,
+		BlockedKeyFile:   c.WFE.BlockedKeyFile,
+		FermatRounds:     c.WFE.FermatRounds,
+		DisableROCACheck: c.WFE.DisableROCACheck,
+	}, sac.KeyBlocked, stats)
 	cmd.FailOnError(err, "Unable to create key policy")
 	wfe, err := wfe.NewWebFrontEndImpl(stats, clk, kp, rns, npm, logger)
 	cmd.FailOnError(err, "Unable to create WFE")
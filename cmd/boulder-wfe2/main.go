@@ -22,10 +22,12 @@ import (
 	bgrpc "github.com/letsencrypt/boulder/grpc"
 	"github.com/letsencrypt/boulder/issuance"
 	blog "github.com/letsencrypt/boulder/log"
+	"github.com/letsencrypt/boulder/nonce"
 	noncepb "github.com/letsencrypt/boulder/nonce/proto"
 	rapb "github.com/letsencrypt/boulder/ra/proto"
 	sapb "github.com/letsencrypt/boulder/sa/proto"
 	"github.com/letsencrypt/boulder/wfe2"
+	"github.com/letsencrypt/boulder/wfe2/policy"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
@@ -42,6 +44,29 @@ type config struct {
 
 		ShutdownStopTimeout cmd.ConfigDuration
 
+		// RequestTimeout is the budget for handling each request, after which
+		// the request fails with a 503 and a Retry-After of OverloadRetryAfter.
+		// GETRequestTimeout, if set, is the budget for GET and HEAD requests,
+		// and EndpointTimeouts, keyed by path such as "/acme/finalize/",
+		// override both for particular endpoints. RequestTimeout defaults to 5
+		// minutes, and OverloadRetryAfter to 30 seconds.
+		RequestTimeout     cmd.ConfigDuration
+		GETRequestTimeout  cmd.ConfigDuration
+		EndpointTimeouts   map[string]cmd.ConfigDuration
+		OverloadRetryAfter cmd.ConfigDuration
+
+		// IDNPolicy, if set, normalizes the DNS names in new orders: they're
+		// lowercased, U-labels are converted to A-labels if AllowUnicode is
+		// set, and internationalized labels must be valid IDNA2008 and obey
+		// the ScriptPolicy ("any", "single-script" or "highly-restrictive")
+		// and AllowedScripts.
+		IDNPolicy *policy.IDNPolicy
+
+		// CertificateProfiles are the names of the certificate profiles which
+		// new orders may request. They should match the RA's
+		// certificateProfiles.
+		CertificateProfiles []string
+
 		SubscriberAgreementURL string
 
 		TLS cmd.TLSConfig
@@ -57,6 +82,38 @@ type config struct {
 		// this should contain all nonce-services from all DCs as we want to be
 		// able to redeem nonces generated at any DC.
 		RedeemNonceServices map[string]cmd.GRPCClientConfig
+		// DerivedPrefixNonceServices contains gRPC configs for nonce-services
+		// whose prefixes are derived from NoncePrefixKey rather than listed
+		// explicitly. It may be used alongside RedeemNonceServices.
+		DerivedPrefixNonceServices []cmd.GRPCClientConfig
+		// NoncePrefixKey is the HMAC key the nonce-services in
+		// DerivedPrefixNonceServices derive their prefixes from.
+		NoncePrefixKey *cmd.PasswordConfig
+		// PreviousNoncePrefixKey, if set, is the key being rotated away from.
+		// Prefixes derived from both keys are routed to the same nonce-service
+		// so that nonces generated before and after the rotation can be
+		// redeemed.
+		PreviousNoncePrefixKey *cmd.PasswordConfig
+		// NoncePool, if present, makes the WFE keep a pool of nonces fetched
+		// in batches from each of its Backends, rather than fetching a nonce
+		// from GetNonceService for every response. If the pools run dry the
+		// WFE falls back to fetching nonces one at a time from the Backends.
+		NoncePool *struct {
+			// Backends are the nonce-services to fill the pool from, which in
+			// a multi-DC deployment should be the local instances. Defaults
+			// to GetNonceService.
+			Backends []cmd.GRPCClientConfig
+			// Size is the number of nonces kept for each backend. Defaults
+			// to 100.
+			Size int
+			// BatchSize is the number of nonces fetched at once. Defaults to
+			// half of Size.
+			BatchSize int
+			// MaxAge is how long a nonce may be kept in the pool. It should
+			// be well below the time the nonce-services take to forget a
+			// nonce. Defaults to 30s.
+			MaxAge cmd.ConfigDuration
+		}
 
 		// CertificateChains maps AIA issuer URLs to certificate filenames.
 		// Certificates are read into the chain in the order they are defined in the
@@ -94,6 +151,10 @@ type config struct {
 		// "website" field.
 		DirectoryWebsite string
 
+		// EmailChallengeFrom is the address the VA sends email-reply-00
+		// challenge emails from. It should match the VA's emailReply.from.
+		EmailChallengeFrom string
+
 		// ACMEv2 requests (outside some registration/revocation messages) use a JWS with
 		// a KeyID header containing the full account URL. For new accounts this
 		// will be a KeyID based on the HTTP request's Host header and the ACMEv2
@@ -108,6 +169,20 @@ type config struct {
 		// administratively blocked.
 		BlockedKeyFile string
 
+		// WeakKeyFile is the path to a JSON file containing truncated RSA modulus
+		// hashes of known easily enumerable keys. If set, account keys on the
+		// list are rejected.
+		WeakKeyFile string
+
+		// FermatRounds is the number of rounds of Fermat's factorization method
+		// to attempt against RSA keys, to detect keys whose prime factors are
+		// too close together. If zero, the check is disabled.
+		FermatRounds int
+
+		// DisableROCACheck disables rejection of RSA keys generated by
+		// Infineon hardware vulnerable to ROCA.
+		DisableROCACheck bool
+
 		// StaleTimeout determines how old should data be to be accessed via Boulder-specific GET-able APIs
 		StaleTimeout cmd.ConfigDuration
 
@@ -287,20 +362,23 @@ func loadChain(certFiles []string) (*issuance.Certificate, []byte, error) {
 	return &issuance.Certificate{Certificate: certs[0]}, buf.Bytes(), nil
 }
 
-func setupWFE(c config, logger blog.Logger, stats prometheus.Registerer, clk clock.Clock) (core.RegistrationAuthority, core.StorageAuthority, noncepb.NonceServiceClient, map[string]noncepb.NonceServiceClient) {
+func setupWFE(c config, logger blog.Logger, stats prometheus.Registerer, clk clock.Clock) (core.RegistrationAuthority, core.StorageAuthority, noncepb.NonceServiceClient, map[string]noncepb.NonceServiceClient, *nonce.Pool) {
 	tlsConfig, err := c.WFE.TLS.Load()
 	cmd.FailOnError(err, "TLS config")
 	clientMetrics := bgrpc.NewClientMetrics(stats)
 	raConn, err := bgrpc.ClientSetup(c.WFE.RAService, tlsConfig, clientMetrics, clk)
 	cmd.FailOnError(err, "Failed to load credentials and create gRPC connection to RA")
+	cmd.RegisterReadinessCheck("ra", bgrpc.HealthCheck(raConn))
 	rac := bgrpc.NewRegistrationAuthorityClient(rapb.NewRegistrationAuthorityClient(raConn))
 
 	saConn, err := bgrpc.ClientSetup(c.WFE.SAService, tlsConfig, clientMetrics, clk)
 	cmd.FailOnError(err, "Failed to load credentials and create gRPC connection to SA")
+	cmd.RegisterReadinessCheck("sa", bgrpc.HealthCheck(saConn))
 	sac := bgrpc.NewStorageAuthorityClient(sapb.NewStorageAuthorityClient(saConn))
 
 	var rns noncepb.NonceServiceClient
 	npm := map[string]noncepb.NonceServiceClient{}
+	var pool *nonce.Pool
 	if c.WFE.GetNonceService != nil {
 		rnsConn, err := bgrpc.ClientSetup(c.WFE.GetNonceService, tlsConfig, clientMetrics, clk)
 		cmd.FailOnError(err, "Failed to load credentials and create gRPC connection to get nonce service")
@@ -311,9 +389,53 @@ func setupWFE(c config, logger blog.Logger, stats prometheus.Registerer, clk clo
 			cmd.FailOnError(err, "Failed to load credentials and create gRPC connection to redeem nonce service")
 			npm[prefix] = noncepb.NewNonceServiceClient(conn)
 		}
+		if len(c.WFE.DerivedPrefixNonceServices) > 0 {
+			if c.WFE.NoncePrefixKey == nil {
+				cmd.Fail("NoncePrefixKey must be configured when using DerivedPrefixNonceServices")
+			}
+			var keys [][]byte
+			for _, keyConfig := range []*cmd.PasswordConfig{c.WFE.NoncePrefixKey, c.WFE.PreviousNoncePrefixKey} {
+				if keyConfig == nil {
+					continue
+				}
+				key, err := keyConfig.Pass()
+				cmd.FailOnError(err, "Failed to load nonce prefix key")
+				keys = append(keys, []byte(key))
+			}
+			for _, serviceConfig := range c.WFE.DerivedPrefixNonceServices {
+				serviceConfig := serviceConfig
+				conn, err := bgrpc.ClientSetup(&serviceConfig, tlsConfig, clientMetrics, clk)
+				cmd.FailOnError(err, "Failed to load credentials and create gRPC connection to redeem nonce service")
+				client := noncepb.NewNonceServiceClient(conn)
+				for _, key := range keys {
+					prefix := nonce.DerivePrefix(key, serviceConfig.ServerAddress)
+					if _, present := npm[prefix]; present {
+						cmd.Fail(fmt.Sprintf("Nonce prefix %q for %s is already in use", prefix, serviceConfig.ServerAddress))
+					}
+					npm[prefix] = client
+				}
+			}
+		}
+		if c.WFE.NoncePool != nil {
+			backends := []noncepb.NonceServiceClient{rns}
+			if len(c.WFE.NoncePool.Backends) > 0 {
+				backends = nil
+				for _, serviceConfig := range c.WFE.NoncePool.Backends {
+					serviceConfig := serviceConfig
+					conn, err := bgrpc.ClientSetup(&serviceConfig, tlsConfig, clientMetrics, clk)
+					cmd.FailOnError(err, "Failed to load credentials and create gRPC connection to nonce pool backend")
+					backends = append(backends, noncepb.NewNonceServiceClient(conn))
+				}
+			}
+			pool = nonce.NewPool(backends, nonce.PoolConfig{
+				Size:      c.WFE.NoncePool.Size,
+				BatchSize: c.WFE.NoncePool.BatchSize,
+				MaxAge:    c.WFE.NoncePool.MaxAge.Duration,
+			}, clk, stats, logger)
+		}
 	}
 
-	return rac, sac, rns, npm
+	return rac, sac, rns, npm, pool
 }
 
 type errorWriter struct {
@@ -334,6 +456,7 @@ func (ew errorWriter) Write(p []byte) (n int, err error) {
 
 func main() {
 	configFile := flag.String("config", "", "File path to the configuration file for this service")
+	checkConfig := cmd.CheckConfigFlag()
 	flag.Parse()
 	if *configFile == "" {
 		flag.Usage()
@@ -343,6 +466,28 @@ func main() {
 	var c config
 	err := cmd.ReadConfigFile(*configFile, &c)
 	cmd.FailOnError(err, "Reading JSON config file into config structure")
+	if *checkConfig {
+		cmd.CheckConfig(&c, cmd.ConfigCheck{
+			Name: "WFE certificate chains",
+			Check: func() error {
+				if c.WFE.Chains == nil {
+					_, _, err := loadCertificateChains(c.WFE.CertificateChains, true)
+					if err != nil {
+						return err
+					}
+					_, _, err = loadCertificateChains(c.WFE.AlternateCertificateChains, false)
+					return err
+				}
+				for _, files := range c.WFE.Chains {
+					_, _, err := loadChain(files)
+					if err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		})
+	}
 
 	err = features.Set(c.WFE.Features)
 	cmd.FailOnError(err, "Failed to set feature flags")
@@ -393,9 +538,13 @@ func main() {
 
 	clk := cmd.Clock()
 
-	rac, sac, rns, npm := setupWFE(c, logger, stats, clk)
-	// don't load any weak keys, but do load blocked keys
-	kp, err := goodkey.NewKeyPolicy("", c.WFE.BlockedKeyFile, sac.KeyBlocked)
+	rac, sac, rns, npm, noncePool := setupWFE(c, logger, stats, clk)
+	kp, err := goodkey.NewKeyPolicy(&goodkey.Config{
+		WeakKeyFile:      c.WFE.WeakKeyFile,
+		BlockedKeyFile:   c.WFE.BlockedKeyFile,
+		FermatRounds:     c.WFE.FermatRounds,
+		DisableROCACheck: c.WFE.DisableROCACheck,
+	}, sac.KeyBlocked, stats)
 	cmd.FailOnError(err, "Unable to create key policy")
 
 	if c.WFE.StaleTimeout.Duration == 0 {
@@ -416,12 +565,27 @@ func main() {
 	cmd.FailOnError(err, "Unable to create WFE")
 	wfe.RA = rac
 	wfe.SA = sac
+	wfe.NoncePool = noncePool
 
 	wfe.SubscriberAgreementURL = c.WFE.SubscriberAgreementURL
 	wfe.AllowOrigins = c.WFE.AllowOrigins
 	wfe.DirectoryCAAIdentity = c.WFE.DirectoryCAAIdentity
 	wfe.DirectoryWebsite = c.WFE.DirectoryWebsite
+	wfe.EmailChallengeFrom = c.WFE.EmailChallengeFrom
+	wfe.CertificateProfiles = c.WFE.CertificateProfiles
 	wfe.LegacyKeyIDPrefix = c.WFE.LegacyKeyIDPrefix
+	wfe.RequestTimeout = c.WFE.RequestTimeout.Duration
+	wfe.GETRequestTimeout = c.WFE.GETRequestTimeout.Duration
+	wfe.OverloadRetryAfter = c.WFE.OverloadRetryAfter.Duration
+	wfe.EndpointTimeouts = make(map[string]time.Duration, len(c.WFE.EndpointTimeouts))
+	for endpoint, timeout := range c.WFE.EndpointTimeouts {
+		wfe.EndpointTimeouts[endpoint] = timeout.Duration
+	}
+	if c.WFE.IDNPolicy != nil {
+		err = c.WFE.IDNPolicy.Validate()
+		cmd.FailOnError(err, "Invalid IDNPolicy")
+		wfe.IDNPolicy = c.WFE.IDNPolicy
+	}
 
 	logger.Infof("WFE using key policy: %#v", kp)
 
@@ -466,6 +630,9 @@ func main() {
 		defer cancel()
 		_ = srv.Shutdown(ctx)
 		_ = tlsSrv.Shutdown(ctx)
+		if noncePool != nil {
+			noncePool.Stop()
+		}
 		done <- true
 	})
 
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"crypto"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+
+	"github.com/letsencrypt/boulder/pkcs11helpers"
+	"gopkg.in/yaml.v2"
+)
+
+// keyBackup describes one wrapped copy of a key, encrypted under a wrapping
+// key held by one of the key's custodians. Each copy is complete: the key is
+// replicated to every custodian rather than split into shares, so any one of
+// them can restore it.
+type keyBackup struct {
+	WrappingKeyLabel string `yaml:"wrapping-key-label"`
+	WrappedKeyPath   string `yaml:"wrapped-key-path"`
+}
+
+type replicatedKeyBackupConfig struct {
+	CeremonyType string              `yaml:"ceremony-type"`
+	PKCS11       PKCS11SigningConfig `yaml:"pkcs11"`
+	Inputs       struct {
+		PublicKeyPath string `yaml:"public-key-path"`
+	} `yaml:"inputs"`
+	Outputs struct {
+		Backups []keyBackup `yaml:"backups"`
+	} `yaml:"outputs"`
+}
+
+func (kbc replicatedKeyBackupConfig) validate() error {
+	if err := kbc.PKCS11.validate(); err != nil {
+		return err
+	}
+
+	// Input fields
+	if kbc.Inputs.PublicKeyPath == "" {
+		return errors.New("inputs.public-key-path is required")
+	}
+
+	// Output fields
+	if len(kbc.Outputs.Backups) == 0 {
+		return errors.New("outputs.backups is required")
+	}
+	labels := make(map[string]bool)
+	paths := make(map[string]bool)
+	for _, backup := range kbc.Outputs.Backups {
+		if backup.WrappingKeyLabel == "" {
+			return errors.New("outputs.backups.wrapping-key-label is required")
+		}
+		if labels[backup.WrappingKeyLabel] {
+			return fmt.Errorf("outputs.backups.wrapping-key-label %q is used more than once", backup.WrappingKeyLabel)
+		}
+		labels[backup.WrappingKeyLabel] = true
+		if err := checkOutputFile(backup.WrappedKeyPath, "backups.wrapped-key-path"); err != nil {
+			return err
+		}
+		if paths[backup.WrappedKeyPath] {
+			return fmt.Errorf("outputs.backups.wrapped-key-path %q is used more than once", backup.WrappedKeyPath)
+		}
+		paths[backup.WrappedKeyPath] = true
+	}
+
+	return nil
+}
+
+// backupKey wraps the private key associated with the given label and public
+// key under each backup's wrapping key, and writes the wrapped keys as PEM to
+// the backups' paths.
+func backupKey(session *pkcs11helpers.Session, label string, pub crypto.PublicKey, backups []keyBackup) error {
+	for _, backup := range backups {
+		wrapped, err := session.WrapPrivateKey(label, pub, backup.WrappingKeyLabel)
+		if err != nil {
+			return fmt.Errorf("failed to wrap key with %q: %s", backup.WrappingKeyLabel, err)
+		}
+		wrappedPEM := pem.EncodeToMemory(&pem.Block{
+			Type: "WRAPPED PRIVATE KEY",
+			Headers: map[string]string{
+				"Key-Label":          label,
+				"Wrapping-Key-Label": backup.WrappingKeyLabel,
+				"Mechanism":          "CKM_AES_KEY_WRAP_PAD",
+			},
+			Bytes: wrapped,
+		})
+		if err := writeFile(backup.WrappedKeyPath, wrappedPEM); err != nil {
+			return fmt.Errorf("failed to write wrapped key to %q: %s", backup.WrappedKeyPath, err)
+		}
+		log.Printf("Key wrapped with %q written to %q\n", backup.WrappingKeyLabel, backup.WrappedKeyPath)
+	}
+	return nil
+}
+
+func replicatedKeyBackupCeremony(configBytes []byte) error {
+	var config replicatedKeyBackupConfig
+	err := yaml.UnmarshalStrict(configBytes, &config)
+	if err != nil {
+		return fmt.Errorf("failed to parse config: %s", err)
+	}
+	if err := config.validate(); err != nil {
+		return fmt.Errorf("failed to validate config: %s", err)
+	}
+
+	pubPEMBytes, err := ioutil.ReadFile(config.Inputs.PublicKeyPath)
+	if err != nil {
+		return fmt.Errorf("failed to read public key %q: %s", config.Inputs.PublicKeyPath, err)
+	}
+	pubPEM, _ := pem.Decode(pubPEMBytes)
+	if pubPEM == nil {
+		return fmt.Errorf("failed to parse public key")
+	}
+	pub, err := x509.ParsePKIXPublicKey(pubPEM.Bytes)
+	if err != nil {
+		return fmt.Errorf("failed to parse public key: %s", err)
+	}
+
+	session, err := pkcs11helpers.Initialize(config.PKCS11.Module, config.PKCS11.SigningSlot, config.PKCS11.PIN)
+	if err != nil {
+		return fmt.Errorf("failed to setup session and PKCS#11 context for slot %d: %s", config.PKCS11.SigningSlot, err)
+	}
+	log.Printf("Opened PKCS#11 session for slot %d\n", config.PKCS11.SigningSlot)
+
+	err = backupKey(session, config.PKCS11.SigningLabel, pub, config.Outputs.Backups)
+	if err != nil {
+		// Remove any backups which were written, so that the ceremony can be
+		// retried without an incomplete set of them lying around.
+		for _, backup := range config.Outputs.Backups {
+			os.Remove(backup.WrappedKeyPath)
+		}
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"bytes"
+	"crypto/rsa"
+	"encoding/pem"
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/letsencrypt/boulder/pkcs11helpers"
+	"github.com/letsencrypt/boulder/test"
+	"github.com/miekg/pkcs11"
+)
+
+func TestKeyBackupConfigValidate(t *testing.T) {
+	pkcs11Config := PKCS11SigningConfig{
+		Module:       "module",
+		SigningLabel: "label",
+	}
+	cases := []struct {
+		name          string
+		config        replicatedKeyBackupConfig
+		expectedError string
+	}{
+		{
+			name:          "no pkcs11.module",
+			config:        replicatedKeyBackupConfig{},
+			expectedError: "pkcs11.module is required",
+		},
+		{
+			name:          "no inputs.public-key-path",
+			config:        replicatedKeyBackupConfig{PKCS11: pkcs11Config},
+			expectedError: "inputs.public-key-path is required",
+		},
+		{
+			name:          "no outputs.backups",
+			config:        replicatedKeyBackupConfig{PKCS11: pkcs11Config},
+			expectedError: "outputs.backups is required",
+		},
+		{
+			name:          "no outputs.backups.wrapping-key-label",
+			config:        replicatedKeyBackupConfig{PKCS11: pkcs11Config},
+			expectedError: "outputs.backups.wrapping-key-label is required",
+		},
+		{
+			name:          "no outputs.backups.wrapped-key-path",
+			config:        replicatedKeyBackupConfig{PKCS11: pkcs11Config},
+			expectedError: "outputs.backups.wrapped-key-path is required",
+		},
+		{
+			name:          "duplicate outputs.backups.wrapping-key-label",
+			config:        replicatedKeyBackupConfig{PKCS11: pkcs11Config},
+			expectedError: `outputs.backups.wrapping-key-label "a" is used more than once`,
+		},
+		{
+			name:          "duplicate outputs.backups.wrapped-key-path",
+			config:        replicatedKeyBackupConfig{PKCS11: pkcs11Config},
+			expectedError: `outputs.backups.wrapped-key-path "path" is used more than once`,
+		},
+		{
+			name:   "good config",
+			config: replicatedKeyBackupConfig{PKCS11: pkcs11Config},
+		},
+	}
+	for i := 2; i < len(cases); i++ {
+		cases[i].config.Inputs.PublicKeyPath = "path"
+	}
+	cases[3].config.Outputs.Backups = []keyBackup{{WrappedKeyPath: "path"}}
+	cases[4].config.Outputs.Backups = []keyBackup{{WrappingKeyLabel: "a"}}
+	cases[5].config.Outputs.Backups = []keyBackup{
+		{WrappingKeyLabel: "a", WrappedKeyPath: "path-a"},
+		{WrappingKeyLabel: "a", WrappedKeyPath: "path-b"},
+	}
+	cases[6].config.Outputs.Backups = []keyBackup{
+		{WrappingKeyLabel: "a", WrappedKeyPath: "path"},
+		{WrappingKeyLabel: "b", WrappedKeyPath: "path"},
+	}
+	cases[7].config.Outputs.Backups = []keyBackup{
+		{WrappingKeyLabel: "a", WrappedKeyPath: "path-a"},
+		{WrappingKeyLabel: "b", WrappedKeyPath: "path-b"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.config.validate()
+			if err != nil && err.Error() != tc.expectedError {
+				t.Fatalf("Unexpected error, wanted: %q, got: %q", tc.expectedError, err)
+			} else if err == nil && tc.expectedError != "" {
+				t.Fatalf("validate didn't fail, wanted: %q", tc.expectedError)
+			}
+		})
+	}
+}
+
+func TestBackupKey(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "ceremony-testing-backup")
+	test.AssertNotError(t, err, "Failed to create temporary directory")
+	defer os.RemoveAll(tmp)
+
+	ctx := setupCtx()
+	ctx.GetAttributeValueFunc = func(pkcs11.SessionHandle, pkcs11.ObjectHandle, []*pkcs11.Attribute) ([]*pkcs11.Attribute, error) {
+		return []*pkcs11.Attribute{pkcs11.NewAttribute(pkcs11.CKA_ID, []byte{1})}, nil
+	}
+	ctx.FindObjectsFunc = func(pkcs11.SessionHandle, int) ([]pkcs11.ObjectHandle, bool, error) {
+		return []pkcs11.ObjectHandle{1}, false, nil
+	}
+	ctx.WrapKeyFunc = func(pkcs11.SessionHandle, []*pkcs11.Mechanism, pkcs11.ObjectHandle, pkcs11.ObjectHandle) ([]byte, error) {
+		return []byte("wrapped"), nil
+	}
+	s := &pkcs11helpers.Session{Module: &ctx, Session: 0}
+	pub := &rsa.PublicKey{N: big.NewInt(1), E: 1}
+
+	backups := []keyBackup{
+		{WrappingKeyLabel: "custodian a", WrappedKeyPath: path.Join(tmp, "a.pem")},
+		{WrappingKeyLabel: "custodian b", WrappedKeyPath: path.Join(tmp, "b.pem")},
+	}
+	err = backupKey(s, "label", pub, backups)
+	test.AssertNotError(t, err, "backupKey failed")
+	for _, backup := range backups {
+		wrappedPEM, err := ioutil.ReadFile(backup.WrappedKeyPath)
+		test.AssertNotError(t, err, "Failed to read wrapped key")
+		block, _ := pem.Decode(wrappedPEM)
+		test.AssertNotNil(t, block, "Failed to decode wrapped key")
+		test.AssertEquals(t, block.Type, "WRAPPED PRIVATE KEY")
+		test.AssertEquals(t, block.Headers["Wrapping-Key-Label"], backup.WrappingKeyLabel)
+		test.Assert(t, bytes.Equal(block.Bytes, []byte("wrapped")), "Unexpected wrapped key")
+	}
+
+	// Backups aren't written over existing files.
+	err = backupKey(s, "label", pub, backups[:1])
+	test.AssertError(t, err, "backupKey didn't fail when the backup already existed")
+
+	ctx.WrapKeyFunc = func(pkcs11.SessionHandle, []*pkcs11.Mechanism, pkcs11.ObjectHandle, pkcs11.ObjectHandle) ([]byte, error) {
+		return nil, errors.New("CKR_KEY_UNEXTRACTABLE")
+	}
+	err = backupKey(s, "label", pub, []keyBackup{{WrappingKeyLabel: "custodian c", WrappedKeyPath: path.Join(tmp, "c.pem")}})
+	test.AssertError(t, err, "backupKey didn't fail when the key couldn't be wrapped")
+	test.AssertContains(t, err.Error(), "CKR_KEY_UNEXTRACTABLE")
+}
@@ -94,8 +94,9 @@ func ecPub(
 // ecGenerate is used to generate and verify a ECDSA key pair of the type
 // specified by curveStr and with the provided label. It returns the public
 // part of the generated key pair as a ecdsa.PublicKey and the random key ID
-// that the HSM uses to identify the key pair.
-func ecGenerate(session *pkcs11helpers.Session, label, curveStr string) (*ecdsa.PublicKey, []byte, error) {
+// that the HSM uses to identify the key pair. If extractable is true the
+// private key can be wrapped for backup.
+func ecGenerate(session *pkcs11helpers.Session, label, curveStr string, extractable bool) (*ecdsa.PublicKey, []byte, error) {
 	curve, present := stringToCurve[curveStr]
 	if !present {
 		return nil, nil, fmt.Errorf("curve %q not supported", curveStr)
@@ -107,6 +108,9 @@ func ecGenerate(session *pkcs11helpers.Session, label, curveStr string) (*ecdsa.
 	}
 	log.Printf("Generating ECDSA key with curve %s and ID %x\n", curveStr, keyID)
 	args := ecArgs(label, curve, keyID)
+	if extractable {
+		args.allowWrapping()
+	}
 	pub, _, err := session.GenerateKeyPair(args.mechanism, args.publicAttrs, args.privateAttrs)
 	if err != nil {
 		return nil, nil, err
@@ -34,6 +34,23 @@ type generateArgs struct {
 	publicAttrs  []*pkcs11.Attribute
 }
 
+// allowWrapping replaces the private key's CKA_EXTRACTABLE attribute so that
+// it can be exported for backup. CKA_WRAP_WITH_TRUSTED limits this to wrapping
+// by keys marked as trusted on the device, so it still can't be extracted in
+// the clear.
+func (ga *generateArgs) allowWrapping() {
+	var attrs []*pkcs11.Attribute
+	for _, attr := range ga.privateAttrs {
+		if attr.Type != pkcs11.CKA_EXTRACTABLE {
+			attrs = append(attrs, attr)
+		}
+	}
+	ga.privateAttrs = append(attrs,
+		pkcs11.NewAttribute(pkcs11.CKA_EXTRACTABLE, true),
+		pkcs11.NewAttribute(pkcs11.CKA_WRAP_WITH_TRUSTED, true),
+	)
+}
+
 const (
 	rsaExp = 65537
 )
@@ -60,12 +77,12 @@ func generateKey(session *pkcs11helpers.Session, label string, outputPath string
 	var keyID []byte
 	switch config.Type {
 	case "rsa":
-		pubKey, keyID, err = rsaGenerate(session, label, config.RSAModLength, rsaExp)
+		pubKey, keyID, err = rsaGenerate(session, label, config.RSAModLength, rsaExp, config.Extractable)
 		if err != nil {
 			return nil, fmt.Errorf("failed to generate RSA key pair: %s", err)
 		}
 	case "ecdsa":
-		pubKey, keyID, err = ecGenerate(session, label, config.ECDSACurve)
+		pubKey, keyID, err = ecGenerate(session, label, config.ECDSACurve, config.Extractable)
 		if err != nil {
 			return nil, fmt.Errorf("failed to generate ECDSA key pair: %s", err)
 		}
@@ -167,3 +167,21 @@ func TestGenerateKeySlotHasSomethingWithDifferentLabel(t *testing.T) {
 	})
 	test.AssertNotError(t, err, "expected success even though there was an object with a different label")
 }
+
+func TestAllowWrapping(t *testing.T) {
+	args := ecArgs("label", elliptic.P256(), []byte{1})
+	args.allowWrapping()
+	extractable, wrapWithTrusted := 0, 0
+	for _, attr := range args.privateAttrs {
+		switch attr.Type {
+		case pkcs11.CKA_EXTRACTABLE:
+			extractable++
+			test.AssertByteEquals(t, attr.Value, pkcs11.NewAttribute(pkcs11.CKA_EXTRACTABLE, true).Value)
+		case pkcs11.CKA_WRAP_WITH_TRUSTED:
+			wrapWithTrusted++
+			test.AssertByteEquals(t, attr.Value, pkcs11.NewAttribute(pkcs11.CKA_WRAP_WITH_TRUSTED, true).Value)
+		}
+	}
+	test.AssertEquals(t, extractable, 1)
+	test.AssertEquals(t, wrapWithTrusted, 1)
+}
@@ -28,6 +28,7 @@ type keyGenConfig struct {
 	Type         string `yaml:"type"`
 	RSAModLength uint   `yaml:"rsa-mod-length"`
 	ECDSACurve   string `yaml:"ecdsa-curve"`
+	Extractable  bool   `yaml:"extractable"`
 }
 
 var allowedCurves = map[string]bool{
@@ -153,8 +154,9 @@ type intermediateConfig struct {
 	CeremonyType string              `yaml:"ceremony-type"`
 	PKCS11       PKCS11SigningConfig `yaml:"pkcs11"`
 	Inputs       struct {
-		PublicKeyPath         string `yaml:"public-key-path"`
-		IssuerCertificatePath string `yaml:"issuer-certificate-path"`
+		PublicKeyPath              string `yaml:"public-key-path"`
+		CertificateToCrossSignPath string `yaml:"certificate-to-cross-sign-path"`
+		IssuerCertificatePath      string `yaml:"issuer-certificate-path"`
 	} `yaml:"inputs"`
 	Outputs struct {
 		CertificatePath string `yaml:"certificate-path"`
@@ -169,7 +171,14 @@ func (ic intermediateConfig) validate(ct certType) error {
 	}
 
 	// Input fields
-	if ic.Inputs.PublicKeyPath == "" {
+	if ic.Inputs.CertificateToCrossSignPath != "" {
+		if ct != intermediateCert && ct != crossCert {
+			return errors.New("inputs.certificate-to-cross-sign-path is only used for intermediate and cross-certificate ceremonies")
+		}
+		if ic.Inputs.PublicKeyPath != "" {
+			return errors.New("inputs.public-key-path is not used if inputs.certificate-to-cross-sign-path is set")
+		}
+	} else if ic.Inputs.PublicKeyPath == "" {
 		return errors.New("inputs.public-key-path is required")
 	}
 	if ic.Inputs.IssuerCertificatePath == "" {
@@ -489,17 +498,29 @@ func intermediateCeremony(configBytes []byte, ct certType) error {
 		return fmt.Errorf("failed to validate config: %s", err)
 	}
 
-	pubPEMBytes, err := ioutil.ReadFile(config.Inputs.PublicKeyPath)
-	if err != nil {
-		return fmt.Errorf("failed to read public key %q: %s", config.Inputs.PublicKeyPath, err)
-	}
-	pubPEM, _ := pem.Decode(pubPEMBytes)
-	if pubPEM == nil {
-		return fmt.Errorf("failed to parse public key")
-	}
-	pub, err := x509.ParsePKIXPublicKey(pubPEM.Bytes)
-	if err != nil {
-		return fmt.Errorf("failed to parse public key: %s", err)
+	var pub crypto.PublicKey
+	var pubDER []byte
+	var crossSigned *x509.Certificate
+	if config.Inputs.CertificateToCrossSignPath != "" {
+		crossSigned, err = loadCert(config.Inputs.CertificateToCrossSignPath)
+		if err != nil {
+			return fmt.Errorf("failed to load certificate to cross-sign %q: %s", config.Inputs.CertificateToCrossSignPath, err)
+		}
+		pub, pubDER = crossSigned.PublicKey, crossSigned.RawSubjectPublicKeyInfo
+	} else {
+		pubPEMBytes, err := ioutil.ReadFile(config.Inputs.PublicKeyPath)
+		if err != nil {
+			return fmt.Errorf("failed to read public key %q: %s", config.Inputs.PublicKeyPath, err)
+		}
+		pubPEM, _ := pem.Decode(pubPEMBytes)
+		if pubPEM == nil {
+			return fmt.Errorf("failed to parse public key")
+		}
+		pub, err = x509.ParsePKIXPublicKey(pubPEM.Bytes)
+		if err != nil {
+			return fmt.Errorf("failed to parse public key: %s", err)
+		}
+		pubDER = pubPEM.Bytes
 	}
 	issuer, err := loadCert(config.Inputs.IssuerCertificatePath)
 	if err != nil {
@@ -511,11 +532,16 @@ func intermediateCeremony(configBytes []byte, ct certType) error {
 		return err
 	}
 
-	template, err := makeTemplate(randReader, &config.CertProfile, pubPEM.Bytes, ct)
+	template, err := makeTemplate(randReader, &config.CertProfile, pubDER, ct)
 	if err != nil {
 		return fmt.Errorf("failed to create certificate profile: %s", err)
 	}
 	template.AuthorityKeyId = issuer.SubjectKeyId
+	if crossSigned != nil {
+		if err := crossSignTemplate(template, crossSigned); err != nil {
+			return err
+		}
+	}
 
 	err = signAndWriteCert(template, issuer, pub, signer, config.Outputs.CertificatePath, config.SkipLints)
 	if err != nil {
@@ -525,6 +551,23 @@ func intermediateCeremony(configBytes []byte, ct certType) error {
 	return nil
 }
 
+// crossSignTemplate makes the template a cross-signed copy of an existing
+// certificate, so that either can be used to build a path from the
+// certificates it issued. The profile's subject must match the existing
+// certificate's, which is then copied byte for byte along with its subject key
+// ID.
+func crossSignTemplate(template, existing *x509.Certificate) error {
+	if template.Subject.String() != existing.Subject.String() {
+		return fmt.Errorf("certificate-profile subject %q doesn't match the subject %q of the certificate to cross-sign",
+			template.Subject, existing.Subject)
+	}
+	template.RawSubject = existing.RawSubject
+	if len(existing.SubjectKeyId) > 0 {
+		template.SubjectKeyId = existing.SubjectKeyId
+	}
+	return nil
+}
+
 // csrSelfSigner is a crypto.Signer that returns an empty signature. When generating a CSR we first
 // generate a self-signed certificate so that we can get extension generation for free. Instead of
 // creating a throwaway key to sign that certificate we just use a signer that returns an empty
@@ -804,7 +847,12 @@ func main() {
 		if err != nil {
 			log.Fatalf("crl signer ceremony failed: %s", err)
 		}
+	case "replicated-key-backup":
+		err = replicatedKeyBackupCeremony(configBytes)
+		if err != nil {
+			log.Fatalf("replicated key backup ceremony failed: %s", err)
+		}
 	default:
-		log.Fatalf("unknown ceremony-type, must be one of: root, intermediate, ocsp-signer, crl-signer, key, ocsp-response")
+		log.Fatalf("unknown ceremony-type, must be one of: root, intermediate, cross-certificate, cross-csr, ocsp-signer, crl-signer, key, replicated-key-backup, ocsp-response, crl")
 	}
 }
@@ -1,9 +1,13 @@
 package main
 
 import (
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"io/ioutil"
 	"strings"
 	"testing"
+
+	"github.com/letsencrypt/boulder/test"
 )
 
 func TestCheckOutputFileSucceeds(t *testing.T) {
@@ -300,14 +304,34 @@ func TestIntermediateConfigValidate(t *testing.T) {
 					SigningLabel: "label",
 				},
 				Inputs: struct {
-					PublicKeyPath         string `yaml:"public-key-path"`
-					IssuerCertificatePath string `yaml:"issuer-certificate-path"`
+					PublicKeyPath              string `yaml:"public-key-path"`
+					CertificateToCrossSignPath string `yaml:"certificate-to-cross-sign-path"`
+					IssuerCertificatePath      string `yaml:"issuer-certificate-path"`
 				}{
 					PublicKeyPath: "path",
 				},
 			},
 			expectedError: "inputs.issuer-certificate is required",
 		},
+		{
+			name: "inputs.public-key-path and inputs.certificate-to-cross-sign-path",
+			config: intermediateConfig{
+				PKCS11: PKCS11SigningConfig{
+					Module:       "module",
+					SigningLabel: "label",
+				},
+				Inputs: struct {
+					PublicKeyPath              string `yaml:"public-key-path"`
+					CertificateToCrossSignPath string `yaml:"certificate-to-cross-sign-path"`
+					IssuerCertificatePath      string `yaml:"issuer-certificate-path"`
+				}{
+					PublicKeyPath:              "path",
+					CertificateToCrossSignPath: "path",
+					IssuerCertificatePath:      "path",
+				},
+			},
+			expectedError: "inputs.public-key-path is not used if inputs.certificate-to-cross-sign-path is set",
+		},
 		{
 			name: "no outputs.certificate-path",
 			config: intermediateConfig{
@@ -316,8 +340,9 @@ func TestIntermediateConfigValidate(t *testing.T) {
 					SigningLabel: "label",
 				},
 				Inputs: struct {
-					PublicKeyPath         string `yaml:"public-key-path"`
-					IssuerCertificatePath string `yaml:"issuer-certificate-path"`
+					PublicKeyPath              string `yaml:"public-key-path"`
+					CertificateToCrossSignPath string `yaml:"certificate-to-cross-sign-path"`
+					IssuerCertificatePath      string `yaml:"issuer-certificate-path"`
 				}{
 					PublicKeyPath:         "path",
 					IssuerCertificatePath: "path",
@@ -333,8 +358,9 @@ func TestIntermediateConfigValidate(t *testing.T) {
 					SigningLabel: "label",
 				},
 				Inputs: struct {
-					PublicKeyPath         string `yaml:"public-key-path"`
-					IssuerCertificatePath string `yaml:"issuer-certificate-path"`
+					PublicKeyPath              string `yaml:"public-key-path"`
+					CertificateToCrossSignPath string `yaml:"certificate-to-cross-sign-path"`
+					IssuerCertificatePath      string `yaml:"issuer-certificate-path"`
 				}{
 					PublicKeyPath:         "path",
 					IssuerCertificatePath: "path",
@@ -355,8 +381,9 @@ func TestIntermediateConfigValidate(t *testing.T) {
 					SigningLabel: "label",
 				},
 				Inputs: struct {
-					PublicKeyPath         string `yaml:"public-key-path"`
-					IssuerCertificatePath string `yaml:"issuer-certificate-path"`
+					PublicKeyPath              string `yaml:"public-key-path"`
+					CertificateToCrossSignPath string `yaml:"certificate-to-cross-sign-path"`
+					IssuerCertificatePath      string `yaml:"issuer-certificate-path"`
 				}{
 					PublicKeyPath:         "path",
 					IssuerCertificatePath: "path",
@@ -391,6 +418,43 @@ func TestIntermediateConfigValidate(t *testing.T) {
 			}
 		})
 	}
+
+	// Only intermediates and cross-certificates can cross-sign an existing
+	// certificate.
+	config := intermediateConfig{
+		PKCS11: PKCS11SigningConfig{
+			Module:       "module",
+			SigningLabel: "label",
+		},
+	}
+	config.Inputs.CertificateToCrossSignPath = "path"
+	err := config.validate(ocspCert)
+	test.AssertError(t, err, "validate didn't fail for an ocsp-signer cross-signing a certificate")
+	test.AssertEquals(t, err.Error(), "inputs.certificate-to-cross-sign-path is only used for intermediate and cross-certificate ceremonies")
+	err = config.validate(crossCert)
+	test.AssertError(t, err, "validate didn't fail without inputs.issuer-certificate-path")
+	test.AssertEquals(t, err.Error(), "inputs.issuer-certificate is required")
+}
+
+func TestCrossSignTemplate(t *testing.T) {
+	existing := &x509.Certificate{
+		Subject:      pkix.Name{CommonName: "intermediate", Organization: []string{"good guys"}, Country: []string{"US"}},
+		RawSubject:   []byte("raw subject"),
+		SubjectKeyId: []byte{1, 2, 3},
+	}
+
+	template := &x509.Certificate{
+		Subject:      pkix.Name{CommonName: "other intermediate", Organization: []string{"good guys"}, Country: []string{"US"}},
+		SubjectKeyId: []byte{4, 5, 6},
+	}
+	err := crossSignTemplate(template, existing)
+	test.AssertError(t, err, "crossSignTemplate didn't fail with a different subject")
+
+	template.Subject.CommonName = "intermediate"
+	err = crossSignTemplate(template, existing)
+	test.AssertNotError(t, err, "crossSignTemplate failed with a matching subject")
+	test.AssertByteEquals(t, template.RawSubject, existing.RawSubject)
+	test.AssertByteEquals(t, template.SubjectKeyId, existing.SubjectKeyId)
 }
 
 func TestCSRConfigValidate(t *testing.T) {
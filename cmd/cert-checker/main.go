@@ -5,11 +5,13 @@ import (
 	"encoding/json"
 	"flag"
 	"fmt"
+	"io/ioutil"
 	"log/syslog"
 	"os"
 	"reflect"
 	"regexp"
 	"runtime"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -22,10 +24,12 @@ import (
 
 	"github.com/letsencrypt/boulder/cmd"
 	"github.com/letsencrypt/boulder/core"
+	"github.com/letsencrypt/boulder/db"
 	"github.com/letsencrypt/boulder/features"
 	"github.com/letsencrypt/boulder/identifier"
 	blog "github.com/letsencrypt/boulder/log"
 	"github.com/letsencrypt/boulder/policy"
+	"github.com/letsencrypt/boulder/precert"
 	"github.com/letsencrypt/boulder/sa"
 )
 
@@ -71,11 +75,187 @@ func (r *report) dump() error {
 	return nil
 }
 
+// dumpToFile writes the report to path as JSON.
+func (r *report) dumpToFile(path string) error {
+	content, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, content, 0640)
+}
+
 type reportEntry struct {
-	Valid    bool     `json:"valid"`
+	Valid bool `json:"valid"`
+	// Critical is set if any of the problems indicate a misissuance, such as
+	// a final certificate which doesn't correspond to its precertificate.
+	Critical bool     `json:"critical,omitempty"`
 	Problems []string `json:"problems,omitempty"`
 }
 
+// idRange is a range of certificate IDs, (start, end], which is fetched and
+// checked as a unit so that progress can be checkpointed.
+type idRange struct {
+	start int64
+	end   int64
+
+	// pending counts the certificates fetched from the range which have yet
+	// to be checked, plus one until the range has been completely fetched.
+	pending int64
+	// good, bad and entries are the results for certificates in this range,
+	// guarded by the checker's rMu.
+	good    int64
+	bad     int64
+	entries map[string]reportEntry
+}
+
+// checkItem is a certificate to check, and the range it was fetched from.
+type checkItem struct {
+	cert core.Certificate
+	rng  *idRange
+}
+
+// checkpoint records the progress of a scan so that it can be resumed if
+// interrupted. The checkpoint file holds a JSON header line, followed by a
+// JSON line for each completed range, appended as the range is completed.
+// Only the results of completed ranges are recorded, so that no certificate
+// is counted twice.
+type checkpoint struct {
+	checkpointHeader
+	// Completed holds the start of each completed range, and Report their
+	// combined results.
+	Completed []int64
+	Report    report
+	// file is where completed ranges are appended. It's nil until the scan
+	// has started and the header has been written.
+	file *os.File
+}
+
+// checkpointHeader describes the scan being checkpointed. Ranges are offsets
+// from Origin, so it's recorded rather than recomputed when resuming, by
+// which time the lowest ID in the window may have changed.
+type checkpointHeader struct {
+	Begin     time.Time `json:"begin"`
+	End       time.Time `json:"end"`
+	RangeSize int64     `json:"rangeSize"`
+	// Origin and MaxID bound the IDs being scanned, (Origin, MaxID].
+	Origin int64 `json:"origin"`
+	MaxID  int64 `json:"maxID"`
+}
+
+// checkpointRange is the line appended to the checkpoint file for a completed
+// range.
+type checkpointRange struct {
+	Start     int64                  `json:"start"`
+	GoodCerts int64                  `json:"good-certs"`
+	BadCerts  int64                  `json:"bad-certs"`
+	Entries   map[string]reportEntry `json:"entries,omitempty"`
+}
+
+// loadCheckpoint reads the checkpoint at path and opens it so that further
+// ranges can be appended, returning nil if there is none. A final line left
+// incomplete by an interrupted append is discarded.
+func loadCheckpoint(path string) (*checkpoint, error) {
+	content, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	content = content[:bytes.LastIndexByte(content, '\n')+1]
+	if len(content) == 0 {
+		return nil, nil
+	}
+	lines := bytes.Split(bytes.TrimSuffix(content, []byte("\n")), []byte("\n"))
+	cp := &checkpoint{Report: report{Entries: make(map[string]reportEntry)}}
+	err = json.Unmarshal(lines[0], &cp.checkpointHeader)
+	if err != nil {
+		return nil, fmt.Errorf("parsing checkpoint %s header: %w", path, err)
+	}
+	for i, line := range lines[1:] {
+		var r checkpointRange
+		err = json.Unmarshal(line, &r)
+		if err != nil {
+			return nil, fmt.Errorf("parsing checkpoint %s line %d: %w", path, i+2, err)
+		}
+		cp.add(r)
+	}
+
+	cp.file, err = os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		return nil, err
+	}
+	err = cp.file.Truncate(int64(len(content)))
+	if err != nil {
+		_ = cp.file.Close()
+		return nil, err
+	}
+	return cp, nil
+}
+
+// add includes a completed range's results in the checkpoint.
+func (cp *checkpoint) add(r checkpointRange) {
+	cp.Completed = append(cp.Completed, r.Start)
+	cp.Report.GoodCerts += r.GoodCerts
+	cp.Report.BadCerts += r.BadCerts
+	for serial, entry := range r.Entries {
+		cp.Report.Entries[serial] = entry
+	}
+}
+
+// start records the IDs being scanned, (origin, maxID], and creates the
+// checkpoint file at path with its header.
+func (cp *checkpoint) start(path string, origin, maxID int64) error {
+	cp.Origin = origin
+	cp.MaxID = maxID
+	header, err := json.Marshal(cp.checkpointHeader)
+	if err != nil {
+		return err
+	}
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC|os.O_APPEND, 0640)
+	if err != nil {
+		return err
+	}
+	err = writeLine(file, header)
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+	cp.file = file
+	return nil
+}
+
+// complete includes a completed range's results in the checkpoint, and
+// appends them to the checkpoint file, if it's open.
+func (cp *checkpoint) complete(r checkpointRange) error {
+	cp.add(r)
+	if cp.file == nil {
+		return nil
+	}
+	line, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	return writeLine(cp.file, line)
+}
+
+// close closes the checkpoint file, if it's open.
+func (cp *checkpoint) close() error {
+	if cp.file == nil {
+		return nil
+	}
+	return cp.file.Close()
+}
+
+// writeLine writes line, followed by a newline, to file and syncs it.
+func writeLine(file *os.File, line []byte) error {
+	_, err := file.Write(append(line, '\n'))
+	if err != nil {
+		return err
+	}
+	return file.Sync()
+}
+
 /*
  * certDB is an interface collecting the gorp.DbMap functions that the
  * various parts of cert-checker rely on. Using this adapter shim allows tests to
@@ -83,112 +263,348 @@ type reportEntry struct {
  */
 type certDB interface {
 	Select(i interface{}, query string, args ...interface{}) ([]interface{}, error)
+	SelectOne(holder interface{}, query string, args ...interface{}) error
 	SelectInt(query string, args ...interface{}) (int64, error)
 }
 
+// defaultRangeSize is the number of certificate IDs in each range fetched by
+// a single worker and tracked by the checkpoint.
+const defaultRangeSize = 100000
+
 type certChecker struct {
 	pa           core.PolicyAuthority
 	dbMap        certDB
-	certs        chan core.Certificate
+	certs        chan checkItem
 	clock        clock.Clock
 	rMu          *sync.Mutex
 	issuedReport report
 	checkPeriod  time.Duration
+	logger       blog.Logger
+
+	// issuedAfter and issuedBefore, if set, override checkPeriod to select
+	// the certificates to check.
+	issuedAfter  time.Time
+	issuedBefore time.Time
+	// rangeSize and fetchers control how certificates are fetched: the IDs
+	// to check are split into ranges of rangeSize, which are fetched by
+	// fetchers goroutines in parallel.
+	rangeSize int64
+	fetchers  int
+	// checkpointFile, if set, is where progress is recorded after each range
+	// is completed, and checkpoint is the progress so far.
+	checkpointFile string
+	checkpoint     *checkpoint
+	// checkPrecerts enables checking that each certificate corresponds to its
+	// stored precertificate.
+	checkPrecerts bool
 }
 
 func newChecker(saDbMap certDB, clk clock.Clock, pa core.PolicyAuthority, period time.Duration) certChecker {
 	c := certChecker{
 		pa:          pa,
 		dbMap:       saDbMap,
-		certs:       make(chan core.Certificate, batchSize),
+		certs:       make(chan checkItem, batchSize),
 		rMu:         new(sync.Mutex),
 		clock:       clk,
 		checkPeriod: period,
+		logger:      blog.Get(),
+		rangeSize:   defaultRangeSize,
+		fetchers:    1,
 	}
 	c.issuedReport.Entries = make(map[string]reportEntry)
 
 	return c
 }
 
-func (c *certChecker) getCerts(unexpiredOnly bool) error {
-	c.issuedReport.end = c.clock.Now()
-	c.issuedReport.begin = c.issuedReport.end.Add(-c.checkPeriod)
-
-	args := map[string]interface{}{"issued": c.issuedReport.begin, "now": 0}
-	if unexpiredOnly {
-		now := c.clock.Now()
-		args["now"] = now
+// window returns the issuance window to check.
+func (c *certChecker) window() (time.Time, time.Time) {
+	end := c.issuedBefore
+	if end.IsZero() {
+		end = c.clock.Now()
 	}
-	count, err := c.dbMap.SelectInt(
-		"SELECT count(*) FROM certificates WHERE issued >= :issued AND expires >= :now",
-		args,
-	)
-	if err != nil {
-		return err
+	begin := c.issuedAfter
+	if begin.IsZero() {
+		begin = end.Add(-c.checkPeriod)
 	}
+	return begin, end
+}
 
-	initialID, err := c.dbMap.SelectInt(
-		"SELECT id FROM certificates WHERE issued >= :issued AND expires >= :now LIMIT 1",
-		args,
-	)
+// resume loads the checkpoint from c.checkpointFile, if there is one, so
+// that ranges it records as complete are skipped and their results included
+// in the report. The scan's window, range size and IDs are taken from the
+// checkpoint.
+func (c *certChecker) resume() error {
+	cp, err := loadCheckpoint(c.checkpointFile)
 	if err != nil {
 		return err
 	}
-	if initialID > 0 {
-		// decrement the initial ID so that we select below as we aren't using >=
-		initialID -= 1
+	if cp == nil {
+		begin, end := c.window()
+		c.checkpoint = &checkpoint{
+			checkpointHeader: checkpointHeader{
+				Begin:     begin,
+				End:       end,
+				RangeSize: c.rangeSize,
+			},
+			Report: report{Entries: make(map[string]reportEntry)},
+		}
+		return nil
+	}
+	c.checkpoint = cp
+	c.issuedAfter = cp.Begin
+	c.issuedBefore = cp.End
+	c.rangeSize = cp.RangeSize
+	c.issuedReport.GoodCerts = cp.Report.GoodCerts
+	c.issuedReport.BadCerts = cp.Report.BadCerts
+	for serial, entry := range cp.Report.Entries {
+		c.issuedReport.Entries[serial] = entry
+	}
+	return nil
+}
+
+// ranges splits the IDs (origin, maxID] into ranges of c.rangeSize, leaving
+// out those the checkpoint records as complete.
+func (c *certChecker) ranges(origin, maxID int64) []*idRange {
+	completed := make(map[int64]bool)
+	if c.checkpoint != nil {
+		for _, start := range c.checkpoint.Completed {
+			completed[start] = true
+		}
+	}
+	rangeSize := c.rangeSize
+	if rangeSize <= 0 {
+		rangeSize = defaultRangeSize
 	}
+	var ranges []*idRange
+	for start := origin; start < maxID; start += rangeSize {
+		if completed[start] {
+			continue
+		}
+		end := start + rangeSize
+		if end > maxID {
+			end = maxID
+		}
+		ranges = append(ranges, &idRange{
+			start:   start,
+			end:     end,
+			pending: 1,
+			entries: make(map[string]reportEntry),
+		})
+	}
+	return ranges
+}
 
+// fetchRange sends every certificate in r to c.certs.
+func (c *certChecker) fetchRange(r *idRange, unexpiredOnly bool) error {
+	args := map[string]interface{}{"id": r.start, "end": r.end, "now": 0, "limit": batchSize}
+	if unexpiredOnly {
+		args["now"] = c.clock.Now()
+	}
 	// Retrieve certs in batches of 1000 (the size of the certificate channel)
 	// so that we don't eat unnecessary amounts of memory and avoid the 16MB MySQL
 	// packet limit.
-	args["limit"] = batchSize
-	args["id"] = initialID
-	for offset := 0; offset < int(count); {
+	for {
 		certs, err := sa.SelectCertificates(
 			c.dbMap,
-			"WHERE id > :id AND expires >= :now ORDER BY id LIMIT :limit",
+			"WHERE id > :id AND id <= :end AND expires >= :now ORDER BY id LIMIT :limit",
 			args,
 		)
 		if err != nil {
 			return err
 		}
-		for _, cert := range certs {
-			c.certs <- cert.Certificate
-		}
 		if len(certs) == 0 {
 			break
 		}
+		for _, cert := range certs {
+			atomic.AddInt64(&r.pending, 1)
+			c.certs <- checkItem{cert: cert.Certificate, rng: r}
+		}
 		args["id"] = certs[len(certs)-1].ID
-		offset += len(certs)
+	}
+	c.checked(r)
+	return nil
+}
+
+// checked is called once the range has been completely fetched, and once for
+// each certificate from it which has been checked. When there's nothing left
+// to do, the range's results are recorded in the checkpoint.
+func (c *certChecker) checked(r *idRange) {
+	if atomic.AddInt64(&r.pending, -1) != 0 || c.checkpoint == nil {
+		return
+	}
+	c.rMu.Lock()
+	defer c.rMu.Unlock()
+	err := c.checkpoint.complete(checkpointRange{
+		Start:     r.start,
+		GoodCerts: r.good,
+		BadCerts:  r.bad,
+		Entries:   r.entries,
+	})
+	if err != nil {
+		c.logger.Errf("Failed to write checkpoint: %s", err)
+	}
+}
+
+// idBounds returns the IDs to check, (origin, maxID]. A resumed scan uses
+// those recorded in its checkpoint, and a new one records them.
+func (c *certChecker) idBounds(args map[string]interface{}) (int64, int64, error) {
+	cp := c.checkpoint
+	if cp != nil && cp.file != nil {
+		return cp.Origin, cp.MaxID, nil
+	}
+	// Certificate IDs increase with issuance time, so the window can be
+	// checked by ID.
+	minID, err := c.dbMap.SelectInt(
+		"SELECT MIN(id) FROM certificates WHERE issued >= :issued AND expires >= :now",
+		args,
+	)
+	if err != nil {
+		return 0, 0, err
+	}
+	var origin, maxID int64
+	if minID > 0 {
+		origin = minID - 1
+		maxID, err = c.dbMap.SelectInt(
+			"SELECT MAX(id) FROM certificates WHERE issued <= :end",
+			args,
+		)
+		if err != nil {
+			return 0, 0, err
+		}
+	}
+	if cp != nil && c.checkpointFile != "" {
+		err = cp.start(c.checkpointFile, origin, maxID)
+		if err != nil {
+			return 0, 0, err
+		}
+	}
+	return origin, maxID, nil
+}
+
+func (c *certChecker) getCerts(unexpiredOnly bool) error {
+	c.issuedReport.begin, c.issuedReport.end = c.window()
+
+	args := map[string]interface{}{
+		"issued": c.issuedReport.begin,
+		"end":    c.issuedReport.end,
+		"now":    0,
+	}
+	if unexpiredOnly {
+		now := c.clock.Now()
+		args["now"] = now
+	}
+	origin, maxID, err := c.idBounds(args)
+	if err != nil {
+		return err
+	}
+
+	ranges := make(chan *idRange)
+	go func() {
+		for _, r := range c.ranges(origin, maxID) {
+			ranges <- r
+		}
+		close(ranges)
+	}()
+
+	fetchers := c.fetchers
+	if fetchers < 1 {
+		fetchers = 1
+	}
+	errs := make(chan error, fetchers)
+	for i := 0; i < fetchers; i++ {
+		go func() {
+			for r := range ranges {
+				err := c.fetchRange(r, unexpiredOnly)
+				if err != nil {
+					// Drain the remaining ranges so that the other fetchers
+					// finish.
+					for range ranges {
+					}
+					errs <- err
+					return
+				}
+			}
+			errs <- nil
+		}()
+	}
+	var firstErr error
+	for i := 0; i < fetchers; i++ {
+		if err := <-errs; err != nil && firstErr == nil {
+			firstErr = err
+		}
 	}
 
 	// Close channel so range operations won't block once the channel empties out
 	close(c.certs)
-	return nil
+	return firstErr
 }
 
 func (c *certChecker) processCerts(wg *sync.WaitGroup, badResultsOnly bool, ignoredLints map[string]bool) {
-	for cert := range c.certs {
+	for item := range c.certs {
+		cert := item.cert
 		problems := c.checkCert(cert, ignoredLints)
+		var critical bool
+		if c.checkPrecerts {
+			precertProblems, precertCritical := c.checkPrecert(cert)
+			problems = append(problems, precertProblems...)
+			critical = precertCritical
+		}
 		valid := len(problems) == 0
+		if critical {
+			c.logger.AuditErrf("CRITICAL: Certificate %s has problems: %s", cert.Serial, strings.Join(problems, "; "))
+		} else if !valid {
+			c.logger.Errf("Certificate %s has problems: %s", cert.Serial, strings.Join(problems, "; "))
+		}
 		c.rMu.Lock()
 		if !badResultsOnly || (badResultsOnly && !valid) {
-			c.issuedReport.Entries[cert.Serial] = reportEntry{
+			entry := reportEntry{
 				Valid:    valid,
+				Critical: critical,
 				Problems: problems,
 			}
+			c.issuedReport.Entries[cert.Serial] = entry
+			item.rng.entries[cert.Serial] = entry
 		}
-		c.rMu.Unlock()
 		if !valid {
 			atomic.AddInt64(&c.issuedReport.BadCerts, 1)
+			item.rng.bad++
 		} else {
 			atomic.AddInt64(&c.issuedReport.GoodCerts, 1)
+			item.rng.good++
 		}
+		c.rMu.Unlock()
+		c.checked(item.rng)
 	}
 	wg.Done()
 }
 
+// checkPrecert checks that cert corresponds to its stored precertificate. A
+// mismatch is critical, since it means the precertificate logged to CT
+// doesn't represent the certificate actually issued. S/MIME certificates
+// aren't logged to CT, and the certificate stored as the precertificate of
+// one is the certificate itself, so they must be identical instead.
+func (c *certChecker) checkPrecert(cert core.Certificate) (problems []string, critical bool) {
+	stored, err := sa.SelectPrecertificate(c.dbMap, cert.Serial)
+	if err != nil {
+		if db.IsNoRows(err) {
+			return []string{"No stored precertificate for certificate"}, false
+		}
+		return []string{fmt.Sprintf("Couldn't fetch stored precertificate: %s", err)}, false
+	}
+	// A certificate which can't be parsed is reported by checkCert.
+	if parsed, err := x509.ParseCertificate(cert.DER); err == nil && isSMIME(parsed) {
+		if !bytes.Equal(stored.DER, cert.DER) {
+			return []string{"S/MIME certificate doesn't match the certificate stored as its precertificate"}, true
+		}
+		return nil, false
+	}
+	err = precert.Correspond(stored.DER, cert.DER)
+	if err != nil {
+		return []string{fmt.Sprintf("Certificate doesn't correspond to its precertificate: %s", err)}, true
+	}
+	return nil, false
+}
+
 // Extensions that we allow in certificates
 var allowedExtensions = map[string]bool{
 	"1.3.6.1.5.5.7.1.1":       true, // Authority info access
@@ -204,6 +620,15 @@ var allowedExtensions = map[string]bool{
 	"1.3.6.1.5.5.7.1.24":      true, // TLS feature
 }
 
+// sctListExtension is the OID of the SCT list extension, which S/MIME
+// certificates, not being logged to CT, mustn't have.
+const sctListExtension = "1.3.6.1.4.1.11129.2.4.2"
+
+// isSMIME returns true if cert is an S/MIME certificate, for email addresses.
+func isSMIME(cert *x509.Certificate) bool {
+	return len(cert.EmailAddresses) > 0
+}
+
 // For extensions that have a fixed value we check that it contains that value
 var expectedExtensionContent = map[string][]byte{
 	"1.3.6.1.5.5.7.1.24": {0x30, 0x03, 0x02, 0x01, 0x05}, // Must staple feature
@@ -269,29 +694,15 @@ func (c *certChecker) checkCert(cert core.Certificate, ignoredLints map[string]b
 				fmt.Sprintf("Certificate has common name >64 characters long (%d)", len(parsedCert.Subject.CommonName)),
 			)
 		}
-		// Check that the PA is still willing to issue for each name in DNSNames + CommonName
-		for _, name := range append(parsedCert.DNSNames, parsedCert.Subject.CommonName) {
-			id := identifier.ACMEIdentifier{Type: identifier.DNS, Value: name}
-			// TODO(https://github.com/letsencrypt/boulder/issues/3371): Distinguish
-			// between certificates issued by v1 and v2 API.
-			if err = c.pa.WillingToIssueWildcards([]identifier.ACMEIdentifier{id}); err != nil {
-				problems = append(problems, fmt.Sprintf("Policy Authority isn't willing to issue for '%s': %s", name, err))
-			} else {
-				// For defense-in-depth, even if the PA was willing to issue for a name
-				// we double check it against a list of forbidden domains. This way even
-				// if the hostnamePolicyFile malfunctions we will flag the forbidden
-				// domain matches
-				if forbidden, pattern := isForbiddenDomain(name); forbidden {
-					problems = append(problems, fmt.Sprintf(
-						"Policy Authority was willing to issue but domain '%s' matches "+
-							"forbiddenDomains entry %q", name, pattern))
-				}
+		if isSMIME(parsedCert) {
+			problems = append(problems, c.checkSMIME(parsedCert)...)
+		} else {
+			problems = append(problems, c.checkTLSNames(parsedCert)...)
+			// Check the cert has the correct key usage extensions
+			if !reflect.DeepEqual(parsedCert.ExtKeyUsage, []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth}) {
+				problems = append(problems, "Certificate has incorrect key usage extensions")
 			}
 		}
-		// Check the cert has the correct key usage extensions
-		if !reflect.DeepEqual(parsedCert.ExtKeyUsage, []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth}) {
-			problems = append(problems, "Certificate has incorrect key usage extensions")
-		}
 
 		for _, ext := range parsedCert.Extensions {
 			if _, ok := allowedExtensions[ext.Id.String()]; !ok {
@@ -307,17 +718,90 @@ func (c *certChecker) checkCert(cert core.Certificate, ignoredLints map[string]b
 	return problems
 }
 
+// checkTLSNames checks that the PA is still willing to issue for each name in
+// a TLS certificate's DNSNames and CommonName.
+func (c *certChecker) checkTLSNames(parsedCert *x509.Certificate) (problems []string) {
+	for _, name := range append(parsedCert.DNSNames, parsedCert.Subject.CommonName) {
+		id := identifier.ACMEIdentifier{Type: identifier.DNS, Value: name}
+		// TODO(https://github.com/letsencrypt/boulder/issues/3371): Distinguish
+		// between certificates issued by v1 and v2 API.
+		if err := c.pa.WillingToIssueWildcards([]identifier.ACMEIdentifier{id}); err != nil {
+			problems = append(problems, fmt.Sprintf("Policy Authority isn't willing to issue for '%s': %s", name, err))
+		} else {
+			// For defense-in-depth, even if the PA was willing to issue for a name
+			// we double check it against a list of forbidden domains. This way even
+			// if the hostnamePolicyFile malfunctions we will flag the forbidden
+			// domain matches
+			if forbidden, pattern := isForbiddenDomain(name); forbidden {
+				problems = append(problems, fmt.Sprintf(
+					"Policy Authority was willing to issue but domain '%s' matches "+
+						"forbiddenDomains entry %q", name, pattern))
+			}
+		}
+	}
+	return problems
+}
+
+// checkSMIME checks the names, key usages and extensions of an S/MIME
+// certificate, which must be for email addresses alone, which the PA is still
+// willing to issue for, and usable only for email protection.
+func (c *certChecker) checkSMIME(parsedCert *x509.Certificate) (problems []string) {
+	if len(parsedCert.DNSNames) > 0 {
+		problems = append(problems, "S/MIME certificate has DNS names")
+	}
+	if cn := parsedCert.Subject.CommonName; cn != "" {
+		found := false
+		for _, address := range parsedCert.EmailAddresses {
+			found = found || address == cn
+		}
+		if !found {
+			problems = append(problems, fmt.Sprintf("S/MIME certificate common name '%s' isn't one of its email addresses", cn))
+		}
+	}
+	for _, address := range parsedCert.EmailAddresses {
+		if err := c.pa.WillingToIssue(identifier.EmailIdentifier(address)); err != nil {
+			problems = append(problems, fmt.Sprintf("Policy Authority isn't willing to issue for '%s': %s", address, err))
+			continue
+		}
+		domain := address[strings.LastIndex(address, "@")+1:]
+		if forbidden, pattern := isForbiddenDomain(domain); forbidden {
+			problems = append(problems, fmt.Sprintf(
+				"Policy Authority was willing to issue but email address '%s' matches "+
+					"forbiddenDomains entry %q", address, pattern))
+		}
+	}
+	if !reflect.DeepEqual(parsedCert.ExtKeyUsage, []x509.ExtKeyUsage{x509.ExtKeyUsageEmailProtection}) {
+		problems = append(problems, "S/MIME certificate has incorrect extended key usages")
+	}
+	if parsedCert.KeyUsage&x509.KeyUsageDigitalSignature == 0 {
+		problems = append(problems, "S/MIME certificate doesn't have the digitalSignature key usage")
+	}
+	for _, ext := range parsedCert.Extensions {
+		if ext.Id.String() == sctListExtension {
+			problems = append(problems, "S/MIME certificate contains an SCT list")
+		}
+	}
+	return problems
+}
+
 type config struct {
 	CertChecker struct {
 		cmd.DBConfig
 		cmd.HostnamePolicyConfig
 
-		Workers             int
+		Workers int
+		// Fetchers is the number of ID ranges fetched from the database in
+		// parallel.
+		Fetchers            int
 		ReportDirectoryPath string
 		UnexpiredOnly       bool
 		BadResultsOnly      bool
 		CheckPeriod         cmd.ConfigDuration
 
+		// CheckPrecertificates enables checking that each certificate
+		// corresponds to its stored precertificate.
+		CheckPrecertificates bool
+
 		// IgnoredLints is a list of zlint names. Any lint results from a lint in
 		// the IgnoredLists list are ignored regardless of LintStatus level.
 		IgnoredLints []string
@@ -337,6 +821,14 @@ func main() {
 	connect := flag.String("db-connect", "", "SQL URI if not provided in the configuration file")
 	cp := flag.Duration("check-period", time.Hour*2160, "How far back to check")
 	unexpiredOnly := flag.Bool("unexpired-only", false, "Only check currently unexpired certificates")
+	fetchers := flag.Int("fetchers", 0, "The number of certificate ID ranges fetched from the database in parallel")
+	rangeSize := flag.Int64("range-size", defaultRangeSize, "The number of certificate IDs in each range fetched by a single worker")
+	issuedAfter := flag.String("issued-after", "", "Only check certificates issued at or after this RFC 3339 time, instead of using -check-period")
+	issuedBefore := flag.String("issued-before", "", "Only check certificates issued at or before this RFC 3339 time (default now)")
+	checkpointFile := flag.String("checkpoint-file", "", "File in which to record progress; an interrupted scan is resumed from it, and it is removed once the scan completes")
+	checkPrecerts := flag.Bool("check-precertificates", false, "Check that each certificate corresponds to its stored precertificate")
+	reportFile := flag.String("report-file", "", "File to write the JSON findings report to, in addition to stdout")
+	checkConfig := cmd.CheckConfigFlag()
 
 	flag.Parse()
 	if *configFile == "" {
@@ -347,6 +839,9 @@ func main() {
 	var config config
 	err := cmd.ReadConfigFile(*configFile, &config)
 	cmd.FailOnError(err, "Reading JSON config file into config structure")
+	if *checkConfig {
+		cmd.CheckConfig(&config)
+	}
 
 	err = features.Set(config.CertChecker.Features)
 	cmd.FailOnError(err, "Failed to set feature flags")
@@ -367,6 +862,9 @@ func main() {
 	config.CertChecker.UnexpiredOnly = *unexpiredOnly
 	config.CertChecker.BadResultsOnly = *badResultsOnly
 	config.CertChecker.CheckPeriod.Duration = *cp
+	if *fetchers != 0 {
+		config.CertChecker.Fetchers = *fetchers
+	}
 
 	// Validate PA config and set defaults if needed
 	cmd.FailOnError(config.PA.CheckChallenges(), "Invalid PA configuration")
@@ -383,6 +881,8 @@ func main() {
 	cmd.FailOnError(err, "Could not connect to database")
 
 	sa.InitDBMetrics(saDbMap, prometheus.DefaultRegisterer, dbSettings)
+	err = config.CertChecker.DBConfig.StartAdaptivePool(saDbMap.Db, prometheus.DefaultRegisterer, logger)
+	cmd.FailOnError(err, "Invalid adaptive DB pool config")
 
 	checkerLatency := prometheus.NewHistogram(prometheus.HistogramOpts{
 		Name: "cert_checker_latency",
@@ -401,7 +901,27 @@ func main() {
 		pa,
 		config.CertChecker.CheckPeriod.Duration,
 	)
-	fmt.Fprintf(os.Stderr, "# Getting certificates issued in the last %s\n", config.CertChecker.CheckPeriod)
+	checker.fetchers = config.CertChecker.Fetchers
+	checker.checkPrecerts = config.CertChecker.CheckPrecertificates || *checkPrecerts
+	checker.rangeSize = *rangeSize
+	if *issuedAfter != "" {
+		checker.issuedAfter, err = time.Parse(time.RFC3339, *issuedAfter)
+		cmd.FailOnError(err, "Failed to parse -issued-after")
+	}
+	if *issuedBefore != "" {
+		checker.issuedBefore, err = time.Parse(time.RFC3339, *issuedBefore)
+		cmd.FailOnError(err, "Failed to parse -issued-before")
+	}
+	if *checkpointFile != "" {
+		checker.checkpointFile = *checkpointFile
+		err = checker.resume()
+		cmd.FailOnError(err, "Failed to load checkpoint")
+		if len(checker.checkpoint.Completed) > 0 {
+			fmt.Fprintf(os.Stderr, "# Resuming from %s with %d ranges complete\n", *checkpointFile, len(checker.checkpoint.Completed))
+		}
+	}
+	begin, end := checker.window()
+	fmt.Fprintf(os.Stderr, "# Getting certificates issued between %s and %s\n", begin, end)
 
 	ignoredLintsMap := make(map[string]bool)
 	for _, name := range config.CertChecker.IgnoredLints {
@@ -436,5 +956,15 @@ func main() {
 	)
 	err = checker.issuedReport.dump()
 	cmd.FailOnError(err, "Failed to dump results: %s\n")
+	if *reportFile != "" {
+		err = checker.issuedReport.dumpToFile(*reportFile)
+		cmd.FailOnError(err, "Failed to write report file")
+	}
+	if *checkpointFile != "" {
+		err = checker.checkpoint.close()
+		cmd.FailOnError(err, "Failed to close checkpoint")
+		err = os.Remove(*checkpointFile)
+		cmd.FailOnError(err, "Failed to remove checkpoint")
+	}
 
 }
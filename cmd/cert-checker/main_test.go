@@ -6,13 +6,19 @@ import (
 	"crypto/rsa"
 	"crypto/x509"
 	"crypto/x509/pkix"
+	"database/sql"
 	"encoding/asn1"
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"log"
 	"math/big"
 	mrand "math/rand"
+	"os"
+	"path/filepath"
 	"reflect"
 	"sort"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -20,6 +26,8 @@ import (
 	"github.com/jmhodges/clock"
 
 	"github.com/letsencrypt/boulder/core"
+	"github.com/letsencrypt/boulder/db"
+	"github.com/letsencrypt/boulder/features"
 	blog "github.com/letsencrypt/boulder/log"
 	"github.com/letsencrypt/boulder/metrics"
 	"github.com/letsencrypt/boulder/policy"
@@ -257,7 +265,7 @@ func TestGetAndProcessCerts(t *testing.T) {
 	fc := clock.NewFake()
 
 	checker := newChecker(saDbMap, fc, pa, expectedValidityPeriod)
-	sa, err := sa.NewSQLStorageAuthority(saDbMap, fc, blog.NewMock(), metrics.NoopRegisterer, 1)
+	sa, err := sa.NewSQLStorageAuthority(saDbMap, fc, blog.NewMock(), metrics.NoopRegisterer, 1, 0, nil)
 	test.AssertNotError(t, err, "Couldn't create SA to insert certificates")
 	saCleanUp := test.ResetSATestDatabase(t)
 	defer func() {
@@ -309,6 +317,10 @@ func (db mismatchedCountDB) SelectInt(_ string, _ ...interface{}) (int64, error)
 	return 99999, nil
 }
 
+func (mdb mismatchedCountDB) SelectOne(_ interface{}, _ string, _ ...interface{}) error {
+	return db.ErrDatabaseOp{Op: "SelectOne", Err: sql.ErrNoRows}
+}
+
 // `getCerts` then calls `Select` to retrieve the Certificate rows. We pull
 // a dastardly switch-a-roo here and return an empty set
 func (db mismatchedCountDB) Select(output interface{}, _ string, _ ...interface{}) ([]interface{}, error) {
@@ -348,6 +360,276 @@ func TestGetCertsEmptyResults(t *testing.T) {
 	test.AssertNotError(t, err, "Failed to retrieve certificates")
 }
 
+// rangeDB is a certDB implementation for `getCerts` holding certificates
+// with IDs 1 to n, of which those from min, if set, are in the window.
+type rangeDB struct {
+	n   int64
+	min int64
+}
+
+func (db rangeDB) SelectInt(query string, _ ...interface{}) (int64, error) {
+	if strings.HasPrefix(query, "SELECT MIN(id)") {
+		if db.min > 0 {
+			return db.min, nil
+		}
+		return 1, nil
+	}
+	return db.n, nil
+}
+
+func (db rangeDB) Select(output interface{}, _ string, args ...interface{}) ([]interface{}, error) {
+	argMap := args[0].(map[string]interface{})
+	after, end, limit := argMap["id"].(int64), argMap["end"].(int64), argMap["limit"].(int)
+	outputPtr, _ := output.(*[]sa.CertWithID)
+	*outputPtr = []sa.CertWithID{}
+	for id := after + 1; id <= end && id <= db.n && len(*outputPtr) < limit; id++ {
+		*outputPtr = append(*outputPtr, sa.CertWithID{
+			ID:          id,
+			Certificate: core.Certificate{Serial: fmt.Sprintf("%036x", id), DER: []byte{0x30}},
+		})
+	}
+	return nil, nil
+}
+
+// rangeDB's certificates have no stored precertificates.
+func (rdb rangeDB) SelectOne(_ interface{}, _ string, _ ...interface{}) error {
+	return db.ErrDatabaseOp{Op: "SelectOne", Err: sql.ErrNoRows}
+}
+
+// precertDB is a certDB implementation for `checkPrecert` which holds a
+// single precertificate.
+type precertDB struct {
+	rangeDB
+	der []byte
+}
+
+func (pdb precertDB) SelectOne(holder interface{}, _ string, _ ...interface{}) error {
+	reflect.ValueOf(holder).Elem().FieldByName("DER").SetBytes(pdb.der)
+	return nil
+}
+
+func TestCheckPrecert(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	test.AssertNotError(t, err, "Couldn't generate key")
+	template := x509.Certificate{
+		SerialNumber: big.NewInt(1337),
+		Subject:      pkix.Name{CommonName: "example.com"},
+		DNSNames:     []string{"example.com"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(expectedValidityPeriod),
+	}
+	issue := func(ext pkix.Extension) []byte {
+		tmpl := template
+		tmpl.ExtraExtensions = []pkix.Extension{ext}
+		der, err := x509.CreateCertificate(rand.Reader, &tmpl, &tmpl, &key.PublicKey, key)
+		test.AssertNotError(t, err, "Couldn't create certificate")
+		return der
+	}
+	poison := pkix.Extension{Id: asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 11129, 2, 4, 3}, Critical: true, Value: asn1.NullBytes}
+	scts := pkix.Extension{Id: asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 11129, 2, 4, 2}, Value: []byte{0x04, 0x00}}
+	precertDER := issue(poison)
+	finalDER := issue(scts)
+
+	checker := newChecker(precertDB{der: precertDER}, clock.NewFake(), pa, expectedValidityPeriod)
+	problems, critical := checker.checkPrecert(core.Certificate{Serial: "1337", DER: finalDER})
+	test.AssertEquals(t, len(problems), 0)
+	test.Assert(t, !critical, "Corresponding certificate was critical")
+
+	template.DNSNames = []string{"example.com", "other.example.com"}
+	problems, critical = checker.checkPrecert(core.Certificate{Serial: "1337", DER: issue(scts)})
+	test.AssertEquals(t, len(problems), 1)
+	test.Assert(t, critical, "Mismatched certificate wasn't critical")
+
+	checker = newChecker(rangeDB{}, clock.NewFake(), pa, expectedValidityPeriod)
+	problems, critical = checker.checkPrecert(core.Certificate{Serial: "1337", DER: finalDER})
+	test.AssertDeepEquals(t, problems, []string{"No stored precertificate for certificate"})
+	test.Assert(t, !critical, "Missing precertificate was critical")
+}
+
+func TestCheckPrecertSMIME(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	test.AssertNotError(t, err, "Couldn't generate key")
+	template := x509.Certificate{
+		SerialNumber:   big.NewInt(1337),
+		EmailAddresses: []string{"someone@example.com"},
+		NotBefore:      time.Now(),
+		NotAfter:       time.Now().Add(expectedValidityPeriod),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	test.AssertNotError(t, err, "Couldn't create certificate")
+
+	// The certificate stored as an S/MIME certificate's precertificate is the
+	// certificate itself, without a poison extension.
+	checker := newChecker(precertDB{der: der}, clock.NewFake(), pa, expectedValidityPeriod)
+	problems, critical := checker.checkPrecert(core.Certificate{Serial: "1337", DER: der})
+	test.AssertEquals(t, len(problems), 0)
+	test.Assert(t, !critical, "Identical S/MIME certificate was critical")
+
+	template.EmailAddresses = []string{"someone-else@example.com"}
+	other, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	test.AssertNotError(t, err, "Couldn't create certificate")
+	problems, critical = checker.checkPrecert(core.Certificate{Serial: "1337", DER: other})
+	test.AssertEquals(t, len(problems), 1)
+	test.Assert(t, critical, "Mismatched S/MIME certificate wasn't critical")
+}
+
+func TestCheckSMIMECert(t *testing.T) {
+	err := features.Set(map[string]bool{"EmailIdentifiers": true})
+	test.AssertNotError(t, err, "features.Set failed")
+	defer features.Reset()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	test.AssertNotError(t, err, "Couldn't generate key")
+	fc := clock.NewFake()
+	checker := newChecker(rangeDB{}, fc, pa, expectedValidityPeriod)
+	issue := func(template x509.Certificate) core.Certificate {
+		template.SerialNumber = big.NewInt(1337)
+		template.NotBefore = fc.Now()
+		template.NotAfter = fc.Now().Add(expectedValidityPeriod - time.Second)
+		der, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+		test.AssertNotError(t, err, "Couldn't create certificate")
+		return core.Certificate{
+			Serial:  core.SerialToString(template.SerialNumber),
+			Digest:  core.Fingerprint256(der),
+			DER:     der,
+			Issued:  template.NotBefore,
+			Expires: template.NotAfter,
+		}
+	}
+	has := func(problems []string, problem string) bool {
+		for _, p := range problems {
+			if strings.HasPrefix(p, problem) {
+				return true
+			}
+		}
+		return false
+	}
+
+	problems := checker.checkCert(issue(x509.Certificate{
+		Subject:        pkix.Name{CommonName: "someone@example.com"},
+		EmailAddresses: []string{"someone@example.com"},
+		KeyUsage:       x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:    []x509.ExtKeyUsage{x509.ExtKeyUsageEmailProtection},
+	}), nil)
+	for _, problem := range problems {
+		test.Assert(t, !strings.Contains(problem, "S/MIME") && !strings.Contains(problem, "key usage") &&
+			!strings.HasPrefix(problem, "Policy Authority"), fmt.Sprintf("Unexpected problem: %s", problem))
+	}
+
+	problems = checker.checkCert(issue(x509.Certificate{
+		Subject:        pkix.Name{CommonName: "someone-else@example.com"},
+		EmailAddresses: []string{"someone@example.com", "not-an-address"},
+		DNSNames:       []string{"example.com"},
+		KeyUsage:       x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:    []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		ExtraExtensions: []pkix.Extension{
+			{Id: asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 11129, 2, 4, 2}, Value: []byte{0x04, 0x02, 0x00, 0x00}},
+		},
+	}), nil)
+	for _, problem := range []string{
+		"S/MIME certificate has DNS names",
+		"S/MIME certificate common name 'someone-else@example.com' isn't one of its email addresses",
+		"Policy Authority isn't willing to issue for 'not-an-address'",
+		"S/MIME certificate has incorrect extended key usages",
+		"S/MIME certificate doesn't have the digitalSignature key usage",
+		"S/MIME certificate contains an SCT list",
+	} {
+		test.Assert(t, has(problems, problem), fmt.Sprintf("Missing problem %q in %q", problem, problems))
+	}
+}
+
+func runChecker(t *testing.T, checker *certChecker) {
+	t.Helper()
+	wg := new(sync.WaitGroup)
+	wg.Add(2)
+	go checker.processCerts(wg, false, nil)
+	go checker.processCerts(wg, false, nil)
+	err := checker.getCerts(false)
+	test.AssertNotError(t, err, "Failed to retrieve certificates")
+	wg.Wait()
+}
+
+func TestCheckpointedScan(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cert-checker")
+	test.AssertNotError(t, err, "Failed to create temp dir")
+	defer os.RemoveAll(dir)
+	checkpointFile := filepath.Join(dir, "checkpoint.json")
+	fc := clock.NewFake()
+	batchSize = 2
+
+	checker := newChecker(rangeDB{n: 10}, fc, pa, expectedValidityPeriod)
+	checker.rangeSize = 3
+	checker.fetchers = 2
+	checker.checkpointFile = checkpointFile
+	test.AssertNotError(t, checker.resume(), "Failed to start checkpoint")
+	runChecker(t, &checker)
+	test.AssertEquals(t, checker.issuedReport.BadCerts, int64(10))
+	test.AssertEquals(t, len(checker.issuedReport.Entries), 10)
+
+	test.AssertNotError(t, checker.checkpoint.close(), "Failed to close checkpoint")
+	cp, err := loadCheckpoint(checkpointFile)
+	test.AssertNotError(t, err, "Failed to load checkpoint")
+	test.AssertNotError(t, cp.close(), "Failed to close checkpoint")
+	sort.Slice(cp.Completed, func(i, j int) bool { return cp.Completed[i] < cp.Completed[j] })
+	test.AssertDeepEquals(t, cp.Completed, []int64{0, 3, 6, 9})
+	test.AssertEquals(t, cp.Report.BadCerts, int64(10))
+	test.AssertEquals(t, cp.Origin, int64(0))
+	test.AssertEquals(t, cp.MaxID, int64(10))
+
+	// Pretend the scan was interrupted after the first two ranges, while a
+	// third was being appended. Resuming checks only the remaining
+	// certificates, and reports on all of them, even though the lowest ID in
+	// the window has since changed.
+	content, err := ioutil.ReadFile(checkpointFile)
+	test.AssertNotError(t, err, "Failed to read checkpoint")
+	lines := strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+	truncated := lines[0] + "\n"
+	for _, line := range lines[1:] {
+		var r checkpointRange
+		test.AssertNotError(t, json.Unmarshal([]byte(line), &r), "Failed to parse checkpoint line")
+		if r.Start < 6 {
+			truncated += line + "\n"
+		}
+	}
+	truncated += `{"start":6,"good-`
+	test.AssertNotError(t, ioutil.WriteFile(checkpointFile, []byte(truncated), 0640), "Failed to write checkpoint")
+
+	fc.Add(time.Hour)
+	checker = newChecker(rangeDB{n: 10, min: 2}, fc, pa, expectedValidityPeriod)
+	checker.fetchers = 2
+	checker.checkpointFile = checkpointFile
+	test.AssertNotError(t, checker.resume(), "Failed to resume checkpoint")
+	test.AssertEquals(t, checker.rangeSize, int64(3))
+	begin, end := checker.window()
+	test.Assert(t, begin.Equal(cp.Begin) && end.Equal(cp.End), "Resumed scan has a different window")
+	test.AssertEquals(t, len(checker.checkpoint.Completed), 2)
+	runChecker(t, &checker)
+	test.AssertEquals(t, checker.issuedReport.BadCerts, int64(10))
+	test.AssertEquals(t, len(checker.issuedReport.Entries), 10)
+
+	// The resumed ranges were appended after the ones already recorded.
+	test.AssertNotError(t, checker.checkpoint.close(), "Failed to close checkpoint")
+	cp, err = loadCheckpoint(checkpointFile)
+	test.AssertNotError(t, err, "Failed to load checkpoint")
+	test.AssertNotError(t, cp.close(), "Failed to close checkpoint")
+	test.AssertEquals(t, len(cp.Completed), 4)
+	test.AssertEquals(t, cp.Report.BadCerts, int64(10))
+}
+
+func TestWindow(t *testing.T) {
+	fc := clock.NewFake()
+	checker := newChecker(rangeDB{}, fc, pa, time.Hour)
+	begin, end := checker.window()
+	test.Assert(t, end.Equal(fc.Now()), "Default window doesn't end now")
+	test.Assert(t, begin.Equal(fc.Now().Add(-time.Hour)), "Default window doesn't cover the check period")
+
+	checker.issuedAfter = fc.Now().Add(-48 * time.Hour)
+	checker.issuedBefore = fc.Now().Add(-24 * time.Hour)
+	begin, end = checker.window()
+	test.Assert(t, begin.Equal(checker.issuedAfter), "Window doesn't start at issuedAfter")
+	test.Assert(t, end.Equal(checker.issuedBefore), "Window doesn't end at issuedBefore")
+}
+
 func TestSaveReport(t *testing.T) {
 	r := report{
 		begin:     time.Time{},
@@ -0,0 +1,182 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"reflect"
+
+	"github.com/letsencrypt/boulder/features"
+	blog "github.com/letsencrypt/boulder/log"
+)
+
+// CheckConfigFlag registers the --check-config flag shared by Boulder's
+// binaries. A binary which registers it should call CheckConfig, after
+// reading its config, if the flag is set.
+func CheckConfigFlag() *bool {
+	return flag.Bool("check-config", false, "Validate the config file, including the TLS material and other files it refers to, then exit with status 0 if it is valid or 1 if it isn't")
+}
+
+// ConfigCheck is a check of part of a config which only a particular binary
+// knows how to validate, such as loading its issuers or certificate chains.
+type ConfigCheck struct {
+	Name  string
+	Check func() error
+}
+
+// validator is implemented by config types which can check themselves for
+// invalid values and combinations of values.
+type validator interface {
+	Validate() error
+}
+
+// CheckConfig validates the config c, which must be a pointer to a struct,
+// then runs each of the binary's own checks. Every problem found is printed
+// to stderr, and the process exits with status 1 if there were any, or 0 if
+// there weren't.
+func CheckConfig(c interface{}, checks ...ConfigCheck) {
+	// Checking a config mustn't require a syslog daemon, as it may be run in
+	// CI or on a deploy host. Anything the checks log is superseded by the
+	// report of problems.
+	_ = blog.Set(blog.NewMock())
+	problems := validateConfig(c, checks)
+	if len(problems) == 0 {
+		fmt.Println("Config is valid")
+		os.Exit(0)
+	}
+	fmt.Fprintf(os.Stderr, "Config has %d problems:\n", len(problems))
+	for _, problem := range problems {
+		fmt.Fprintf(os.Stderr, "  %s\n", problem)
+	}
+	os.Exit(1)
+}
+
+// validateConfig walks c, checking each of the config types from this package
+// that it finds and setting any Features, then runs checks. Each problem
+// found is returned, prefixed with the path to the offending field.
+func validateConfig(c interface{}, checks []ConfigCheck) []string {
+	var problems []string
+	walkConfig(reflect.ValueOf(c), "", func(path string, err error) {
+		problems = append(problems, fmt.Sprintf("%s: %s", path, err))
+	})
+	for _, check := range checks {
+		err := check.Check()
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %s", check.Name, err))
+		}
+	}
+	return problems
+}
+
+func walkConfig(v reflect.Value, path string, report func(string, error)) {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+	if v.CanAddr() {
+		checked, err := checkConfigValue(v.Addr().Interface())
+		if err != nil {
+			report(path, err)
+		}
+		if checked {
+			return
+		}
+	}
+	switch v.Kind() {
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if field.PkgPath != "" {
+				// Unexported fields aren't set from the config file.
+				continue
+			}
+			// Fields of embedded structs are reported as if they were
+			// fields of the embedding struct, as they are in the config file.
+			fieldPath := path
+			if !field.Anonymous && path != "" {
+				fieldPath = path + "." + field.Name
+			} else if !field.Anonymous {
+				fieldPath = field.Name
+			}
+			if field.Name == "Features" && field.Type == reflect.TypeOf(map[string]bool{}) {
+				err := features.Set(v.Field(i).Interface().(map[string]bool))
+				if err != nil {
+					report(fieldPath, err)
+				}
+				continue
+			}
+			if field.Name == "FeatureRollouts" && field.Type == reflect.TypeOf(map[string]float64{}) {
+				err := features.SetRollouts(v.Field(i).Interface().(map[string]float64))
+				if err != nil {
+					report(fieldPath, err)
+				}
+				continue
+			}
+			walkConfig(v.Field(i), fieldPath, report)
+		}
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			walkConfig(v.Index(i), fmt.Sprintf("%s[%d]", path, i), report)
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			// Map values aren't addressable, so copy each one to check it.
+			value := reflect.New(iter.Value().Type()).Elem()
+			value.Set(iter.Value())
+			walkConfig(value, fmt.Sprintf("%s[%v]", path, iter.Key()), report)
+		}
+	}
+}
+
+// checkConfigValue checks a single config value, given as a pointer, if it's
+// one of the config types from this package. It returns true if the value was
+// checked as a whole, and so its fields needn't be walked.
+func checkConfigValue(v interface{}) (bool, error) {
+	switch c := v.(type) {
+	case *TLSConfig:
+		if c.CertFile == nil && c.KeyFile == nil && c.CACertFile == nil {
+			// Not configured, which is fine where TLS is optional. Binaries
+			// which require it fail at startup.
+			return true, nil
+		}
+		_, err := c.Load()
+		return true, err
+	case *GRPCServerConfig:
+		if c.Address != "" {
+			_, _, err := net.SplitHostPort(c.Address)
+			if err != nil {
+				return true, fmt.Errorf("invalid address: %s", err)
+			}
+		}
+		return true, nil
+	case *DBConfig:
+		_, err := c.URL()
+		return true, err
+	case *PasswordConfig:
+		_, err := c.Pass()
+		return true, err
+	case *PAConfig:
+		err := c.CheckChallenges()
+		if err == nil {
+			_, err = c.URL()
+		}
+		return true, err
+	case *HostnamePolicyConfig:
+		if c.HostnamePolicyFile != "" {
+			_, err := ioutil.ReadFile(c.HostnamePolicyFile)
+			return true, err
+		}
+		return true, nil
+	case *ContactEncryptionConfig:
+		_, err := c.Keys()
+		return true, err
+	case validator:
+		return true, c.Validate()
+	}
+	return false, nil
+}
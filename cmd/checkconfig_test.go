@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/letsencrypt/boulder/features"
+	"github.com/letsencrypt/boulder/test"
+)
+
+func TestValidateConfig(t *testing.T) {
+	defer features.Reset()
+	cert, key, caCert := "testdata/cert.pem", "testdata/key.pem", "testdata/minica.pem"
+	missing := "testdata/missing.pem"
+	var c struct {
+		WFE struct {
+			ServiceConfig
+			Features        map[string]bool
+			FeatureRollouts map[string]float64
+			SAService       *GRPCClientConfig
+			RAService       *GRPCClientConfig
+			DB              DBConfig
+			Redis           []RedisConfig
+		}
+	}
+	c.WFE.TLS = TLSConfig{&cert, &key, &caCert}
+	c.WFE.GRPC = &GRPCServerConfig{Address: ":9090"}
+	c.WFE.SAService = &GRPCClientConfig{ServerAddress: "sa.boulder:9095", Timeout: ConfigDuration{time.Second}}
+	c.WFE.DB = DBConfig{DBConnectFile: "testdata/test_dburl"}
+	c.WFE.Redis = []RedisConfig{{Address: "redis:6379"}}
+
+	passing := ConfigCheck{"Chains", func() error { return nil }}
+	test.AssertEquals(t, len(validateConfig(&c, []ConfigCheck{passing})), 0)
+
+	c.WFE.TLS.KeyFile = &missing
+	c.WFE.GRPC.Address = "nonsense"
+	c.WFE.Features = map[string]bool{"NoSuchFeature": true}
+	c.WFE.FeatureRollouts = map[string]float64{"EnforceMultiVA": 150}
+	c.WFE.RAService = &GRPCClientConfig{}
+	c.WFE.Redis[0].PasswordFile = "testdata/missing"
+	failing := ConfigCheck{"Chains", func() error { return errors.New("no chains") }}
+	problems := validateConfig(&c, []ConfigCheck{passing, failing})
+	test.AssertEquals(t, len(problems), 7)
+	for i, prefix := range []string{
+		"WFE.GRPC: invalid address",
+		"WFE.TLS: loading key pair",
+		"WFE.Features: feature 'NoSuchFeature' doesn't exist",
+		"WFE.FeatureRollouts: rollout percentage for feature 'EnforceMultiVA' must be between 0 and 100",
+		"WFE.RAService: ServerAddress must not be empty",
+		"WFE.Redis[0]: open testdata/missing",
+		"Chains: no chains",
+	} {
+		test.Assert(t, strings.HasPrefix(problems[i], prefix), "got problem "+problems[i]+", wanted "+prefix)
+	}
+}
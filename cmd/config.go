@@ -2,15 +2,26 @@ package cmd
 
 import (
 	"crypto/tls"
-	"crypto/x509"
+	"database/sql"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"net"
+	"net/http"
+	"path/filepath"
 	"strings"
 	"time"
 
+	"github.com/jmhodges/clock"
+	"github.com/prometheus/client_golang/prometheus"
+
 	"github.com/letsencrypt/boulder/core"
+	"github.com/letsencrypt/boulder/db"
+	blog "github.com/letsencrypt/boulder/log"
+	"github.com/letsencrypt/boulder/mail"
+	"github.com/letsencrypt/boulder/objectstore"
 )
 
 // PasswordConfig either contains a password or the path to a file
@@ -82,6 +93,42 @@ type DBConfig struct {
 	// If d < 0, connections are not closed due to a connection's idle
 	// time.
 	ConnMaxIdleTime ConfigDuration
+
+	// AdaptivePool, if set, makes MaxOpenConns an upper bound, with the
+	// pool's actual maximum number of open connections growing and
+	// shrinking with demand.
+	AdaptivePool *AdaptivePoolConfig
+
+	// TransactionRetries, if set, makes services which support it retry
+	// transactions which fail with deadlocks, lock wait timeouts or lost
+	// connections.
+	TransactionRetries *TransactionRetryConfig
+}
+
+// AdaptivePoolConfig configures the adaptive sizing of a DB connection pool
+// (see db.AdaptivePool).
+type AdaptivePoolConfig struct {
+	// MinOpenConns is the smallest the pool's maximum number of open
+	// connections may shrink to.
+	MinOpenConns int
+	// TargetWait is the average time spent waiting for a connection above
+	// which the pool grows.
+	TargetWait ConfigDuration
+	// Interval is how often the pool's size is reconsidered. It defaults to
+	// 10 seconds.
+	Interval ConfigDuration
+}
+
+// TransactionRetryConfig configures the retrying of DB transactions which fail
+// with transient errors (see db.Retrier).
+type TransactionRetryConfig struct {
+	// MaxRetries is the number of times a transaction is retried.
+	MaxRetries int
+	// Backoff is the delay before the first retry, which doubles for each
+	// subsequent retry up to MaxBackoff. They default to 10 and 200
+	// milliseconds.
+	Backoff    ConfigDuration
+	MaxBackoff ConfigDuration
 }
 
 // URL returns the DBConnect URL represented by this DBConfig object, either
@@ -106,11 +153,174 @@ func (d *DBConfig) GetMaxOpenConns() int {
 	return d.MaxOpenConns
 }
 
+// StartAdaptivePool starts resizing sqlDB's connection pool, bounded by the
+// configured MaxOpenConns, if AdaptivePool is set. It does nothing otherwise.
+func (d *DBConfig) StartAdaptivePool(sqlDB *sql.DB, stats prometheus.Registerer, logger blog.Logger) error {
+	if d.AdaptivePool == nil {
+		return nil
+	}
+	interval := d.AdaptivePool.Interval.Duration
+	if interval == 0 {
+		interval = 10 * time.Second
+	}
+	pool, err := db.NewAdaptivePool(sqlDB, db.AdaptivePoolConfig{
+		MinOpenConns: d.AdaptivePool.MinOpenConns,
+		MaxOpenConns: d.GetMaxOpenConns(),
+		MaxIdleConns: d.MaxIdleConns,
+		TargetWait:   d.AdaptivePool.TargetWait.Duration,
+		Interval:     interval,
+	}, stats, logger)
+	if err != nil {
+		return err
+	}
+	pool.Start()
+	return nil
+}
+
+// ContactEncryptionConfig configures application-level encryption of the
+// registrations table's contact column. Keys are hex-encoded 32 byte AES-256
+// keys, each stored in its own file and referred to by a short key ID. New and
+// updated contacts are encrypted with the active key; the remaining keys are
+// only used for decryption, so a key can be rotated by adding a new key, making
+// it active, and re-encrypting existing rows with contact-encrypter.
+type ContactEncryptionConfig struct {
+	// KeyFiles maps key IDs to the files containing those keys.
+	KeyFiles map[string]string
+	// ActiveKeyID is the ID of the key used to encrypt contacts.
+	ActiveKeyID string
+}
+
+// Keys reads and decodes all of the configured keys, returning them indexed by
+// key ID.
+func (c *ContactEncryptionConfig) Keys() (map[string][]byte, error) {
+	keys := make(map[string][]byte, len(c.KeyFiles))
+	for id, path := range c.KeyFiles {
+		contents, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		key, err := hex.DecodeString(strings.TrimSpace(string(contents)))
+		if err != nil {
+			return nil, fmt.Errorf("decoding contact encryption key %q from %q: %s", id, path, err)
+		}
+		keys[id] = key
+	}
+	return keys, nil
+}
+
+// RedisConfig defines how to connect to a Redis server.
+type RedisConfig struct {
+	// Password, if set, is used to authenticate to the server.
+	PasswordConfig
+	// Address is the host:port of the server.
+	Address string
+	// TLS, if present, causes the connection to be made over TLS using the
+	// given client certificate and CA.
+	TLS *TLSConfig
+	// Timeout bounds each command sent to the server.
+	Timeout ConfigDuration
+	// PoolSize is the maximum number of idle connections kept open to the
+	// server.
+	PoolSize int
+}
+
+// ObjectStorageConfig defines how to read and write objects in an S3 or GCS
+// bucket over HTTPS.
+type ObjectStorageConfig struct {
+	// BaseURL is the URL of the bucket, including any path prefix, to which
+	// object names are appended. For example,
+	// "https://example-bucket.s3.amazonaws.com/ocsp" or
+	// "https://storage.googleapis.com/example-bucket/ocsp".
+	BaseURL string
+	// AccessKeyID, if set, causes each request to be signed with AWS
+	// Signature Version 4, as S3 and S3 compatible stores require, using the
+	// Password as the secret access key.
+	AccessKeyID string
+	// Region is the region of the bucket, which SigV4 signatures cover. It
+	// defaults to "us-east-1".
+	Region string
+	// Password is the secret access key if AccessKeyID is set. Otherwise, if
+	// it's set, it's sent as a bearer token in the Authorization header of
+	// each request. If neither is set, the bucket must allow unauthenticated
+	// access from the requesting host.
+	PasswordConfig
+	// Timeout bounds each request to the bucket.
+	Timeout ConfigDuration
+}
+
+// Load returns a client for the configured bucket.
+func (oc *ObjectStorageConfig) Load(clk clock.Clock) (*objectstore.Client, error) {
+	secret, err := oc.Pass()
+	if err != nil {
+		return nil, fmt.Errorf("loading object storage secret: %s", err)
+	}
+	return objectstore.New(oc.BaseURL, objectstore.Credentials{
+		AccessKeyID: oc.AccessKeyID,
+		Secret:      secret,
+		Region:      oc.Region,
+	}, &http.Client{Timeout: oc.Timeout.Duration}, clk)
+}
+
 type SMTPConfig struct {
 	PasswordConfig
 	Server   string
 	Port     string
 	Username string
+	Pool     SMTPPoolConfig
+	// DKIM, if set, configures DKIM signing of outgoing messages.
+	DKIM *DKIMConfig
+}
+
+// SMTPPoolConfig configures the SMTP connections a mailer keeps open. See
+// mail.PoolConfig.
+type SMTPPoolConfig struct {
+	Size               int
+	MaxMessagesPerConn int
+	KeepAliveInterval  ConfigDuration
+}
+
+// PoolConfig returns the mail.PoolConfig for the configuration.
+func (pc SMTPPoolConfig) PoolConfig() mail.PoolConfig {
+	return mail.PoolConfig{
+		Size:               pc.Size,
+		MaxMessagesPerConn: pc.MaxMessagesPerConn,
+		KeepAliveInterval:  pc.KeepAliveInterval.Duration,
+	}
+}
+
+// DKIMConfig configures DKIM signing of outgoing messages. To rotate keys,
+// publish the new key under a new selector and add it with a NotBefore after
+// the DNS record will have propagated; the old key can be removed once the new
+// one is in use.
+type DKIMConfig struct {
+	// Domain is the signing domain (the d= tag).
+	Domain string
+	Keys   []DKIMKeyConfig
+}
+
+// DKIMKeyConfig is a DKIM signing key.
+type DKIMKeyConfig struct {
+	Selector string
+	// KeyFile is the path to a PEM-encoded RSA or Ed25519 private key.
+	KeyFile   string
+	NotBefore time.Time
+}
+
+// Load reads the configured keys and returns a signer which uses them.
+func (dc *DKIMConfig) Load(clk clock.Clock) (*mail.DKIMSigner, error) {
+	var keys []mail.DKIMKey
+	for _, kc := range dc.Keys {
+		signer, err := mail.LoadDKIMKey(kc.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("loading DKIM key %q: %s", kc.Selector, err)
+		}
+		keys = append(keys, mail.DKIMKey{
+			Selector:  kc.Selector,
+			Signer:    signer,
+			NotBefore: kc.NotBefore,
+		})
+	}
+	return mail.NewDKIMSigner(dc.Domain, keys, clk)
 }
 
 // PAConfig specifies how a policy authority should connect to its
@@ -119,6 +329,18 @@ type SMTPConfig struct {
 type PAConfig struct {
 	DBConfig
 	Challenges map[core.AcmeChallenge]bool
+
+	// MaxWildcardDepth, if non-zero, is the greatest number of labels,
+	// including the wildcard label, which a wildcard name may have in front of
+	// its registered domain. "*.example.com" has a depth of 1.
+	MaxWildcardDepth int
+	// ForbiddenWildcardDomains are registered domains under which no wildcard
+	// name may be issued.
+	ForbiddenWildcardDomains []string
+	// RequireWildcardRevalidation makes new orders for wildcard names always
+	// create new authorizations, validated with DNS-01, rather than reuse
+	// valid ones.
+	RequireWildcardRevalidation bool
 }
 
 // CheckChallenges checks whether the list of challenges in the PA config
@@ -139,6 +361,13 @@ func (pc PAConfig) CheckChallenges() error {
 // what hostnames to issue for.
 type HostnamePolicyConfig struct {
 	HostnamePolicyFile string
+	// HostnamePolicyRefreshInterval, if set, makes the service load the
+	// hostname policy from the SA, checking for a new version this often. The
+	// HostnamePolicyFile is used until a version has been loaded. The service
+	// also loads a new version as soon as the admin tool stores it, through
+	// the HostnamePolicyRefresher service on its gRPC server, whose
+	// clientNames must include the admin tool's.
+	HostnamePolicyRefreshInterval ConfigDuration
 }
 
 // TLSConfig represents certificates and a key for authenticated TLS.
@@ -149,7 +378,14 @@ type TLSConfig struct {
 }
 
 // Load reads and parses the certificates and key listed in the TLSConfig, and
-// returns a *tls.Config suitable for either client or server use.
+// returns a *tls.Config suitable for either client or server use. The files
+// are watched for changes, and new certificates, keys and CA bundles are used
+// for subsequent handshakes without a restart. Configs loaded from the same
+// files share their watchers. Servers pick them up through
+// GetConfigForClient and clients through GetClientCertificate. Calling
+// GetConfigForClient with a nil argument returns a *tls.Config holding the
+// current material, for clients which manage their own handshakes and need
+// the current RootCAs.
 func (t *TLSConfig) Load() (*tls.Config, error) {
 	if t == nil {
 		return nil, fmt.Errorf("nil TLS section in config")
@@ -163,30 +399,17 @@ func (t *TLSConfig) Load() (*tls.Config, error) {
 	if t.CACertFile == nil {
 		return nil, fmt.Errorf("nil CACertFile in TLSConfig")
 	}
-	caCertBytes, err := ioutil.ReadFile(*t.CACertFile)
+	rt, err := watchTLSFiles(*t.CertFile, *t.KeyFile, *t.CACertFile)
 	if err != nil {
-		return nil, fmt.Errorf("reading CA cert from %q: %s", *t.CACertFile, err)
+		return nil, err
 	}
-	rootCAs := x509.NewCertPool()
-	if ok := rootCAs.AppendCertsFromPEM(caCertBytes); !ok {
-		return nil, fmt.Errorf("parsing CA certs from %s failed", *t.CACertFile)
+	config := rt.config()
+	config.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+		return rt.config(), nil
 	}
-	cert, err := tls.LoadX509KeyPair(*t.CertFile, *t.KeyFile)
-	if err != nil {
-		return nil, fmt.Errorf("loading key pair from %q and %q: %s",
-			*t.CertFile, *t.KeyFile, err)
-	}
-	return &tls.Config{
-		RootCAs:      rootCAs,
-		ClientCAs:    rootCAs,
-		ClientAuth:   tls.RequireAndVerifyClientCert,
-		Certificates: []tls.Certificate{cert},
-		// Set the only acceptable TLS version to 1.2 and the only acceptable cipher suite
-		// to ECDHE-RSA-CHACHA20-POLY1305.
-		MinVersion:   tls.VersionTLS12,
-		MaxVersion:   tls.VersionTLS12,
-		CipherSuites: []uint16{tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305},
-	}, nil
+	config.GetClientCertificate = rt.clientCertificate
+	reloadingConfigs.Store(config, rt)
+	return config, nil
 }
 
 // RPCServerConfig contains configuration particular to a specific RPC server
@@ -200,6 +423,36 @@ type RPCServerConfig struct {
 type SyslogConfig struct {
 	StdoutLevel int
 	SyslogLevel int
+	// Format is either "text", the default, or "json", which logs each
+	// message as a JSON object (see log.JSONLine) for log pipelines to parse.
+	Format string
+	// DedupWindows, keyed by level name ("warning", "info" or "debug"),
+	// makes a message logged at that level more than once within the window
+	// be logged once, followed by a line with its repeat count once the window
+	// is over. Audit log lines, which include all err level lines, are never
+	// deduplicated.
+	DedupWindows map[string]ConfigDuration
+}
+
+// Validate checks that the log format is known, and that each deduplication
+// window is for a known level and is positive.
+func (c *SyslogConfig) Validate() error {
+	if c.Format != "" && c.Format != "text" && c.Format != "json" {
+		return fmt.Errorf("unknown log Format %q", c.Format)
+	}
+	for level, window := range c.DedupWindows {
+		switch level {
+		case "warning", "info", "debug":
+		case "err":
+			return errors.New("err messages are audit logged, so can't be deduplicated")
+		default:
+			return fmt.Errorf("unknown log level %q in DedupWindows", level)
+		}
+		if window.Duration <= 0 {
+			return fmt.Errorf("DedupWindows[%s] must be positive", level)
+		}
+	}
+	return nil
 }
 
 // ConfigDuration is just an alias for time.Duration that allows
@@ -253,19 +506,154 @@ func (d *ConfigDuration) UnmarshalYAML(unmarshal func(interface{}) error) error
 
 // GRPCClientConfig contains the information needed to talk to the gRPC service
 type GRPCClientConfig struct {
+	// ServerAddress is the host:port of the service, or one of the non-TCP
+	// addresses described by ParseGRPCAddress.
 	ServerAddress string
 	Timeout       ConfigDuration
+
+	// Ejection, if set, makes the client stop sending RPCs to backends which
+	// are up but erroring or slow.
+	Ejection *GRPCEjectionConfig
+
+	// HedgeAfter, if set, makes the client send a second, identical request
+	// for any of the HedgeMethods which hasn't completed after this long, and
+	// use whichever response arrives first. Only idempotent, read-only methods
+	// should be hedged.
+	HedgeAfter   ConfigDuration
+	HedgeMethods []string
+
+	// Methods overrides Timeout and configures retries for individual
+	// methods, keyed by method name without the service name, e.g.
+	// "GetRegistration".
+	Methods map[string]GRPCMethodConfig
+}
+
+// GRPCMethodConfig configures the deadline and retries for a single gRPC
+// method. A non-zero Timeout replaces the client's Timeout for the method, and
+// covers all attempts. If MaxRetries is non-zero, requests which fail because
+// no backend was available are retried up to MaxRetries times, waiting an
+// exponentially increasing, jittered delay between BackoffBase and BackoffMax
+// before each retry. Only idempotent methods should be retried.
+type GRPCMethodConfig struct {
+	Timeout       ConfigDuration
+	MaxRetries    int
+	BackoffBase   ConfigDuration
+	BackoffMax    ConfigDuration
+	BackoffFactor float64
+}
+
+// Validate checks that the client config has a server address, and that its
+// hedging, ejection and per-method settings are consistent.
+func (c *GRPCClientConfig) Validate() error {
+	if c.ServerAddress == "" {
+		return errors.New("ServerAddress must not be empty")
+	}
+	_, _, err := ParseGRPCAddress(c.ServerAddress)
+	if err != nil {
+		return fmt.Errorf("invalid ServerAddress: %s", err)
+	}
+	if len(c.HedgeMethods) > 0 {
+		if c.HedgeAfter.Duration <= 0 || c.HedgeAfter.Duration >= c.Timeout.Duration {
+			return errors.New("HedgeAfter must be positive and less than Timeout when HedgeMethods are given")
+		}
+	}
+	if c.Ejection != nil {
+		err = c.Ejection.Validate()
+		if err != nil {
+			return err
+		}
+	}
+	for name, mc := range c.Methods {
+		err := mc.Validate()
+		if err != nil {
+			return fmt.Errorf("invalid config for method %q: %s", name, err)
+		}
+	}
+	return nil
+}
+
+// Validate checks that a method's timeout isn't negative and that, if it's
+// retried, it has a usable backoff.
+func (c GRPCMethodConfig) Validate() error {
+	if c.Timeout.Duration < 0 {
+		return errors.New("Timeout must not be negative")
+	}
+	if c.MaxRetries < 0 {
+		return errors.New("MaxRetries must not be negative")
+	}
+	if c.MaxRetries == 0 {
+		return nil
+	}
+	if c.BackoffBase.Duration <= 0 || c.BackoffMax.Duration < c.BackoffBase.Duration {
+		return errors.New("BackoffBase must be positive and no greater than BackoffMax when MaxRetries is set")
+	}
+	if c.BackoffFactor < 1 {
+		return errors.New("BackoffFactor must be at least 1 when MaxRetries is set")
+	}
+	return nil
+}
+
+// GRPCEjectionConfig configures the ejection of unhealthy backends by a gRPC
+// client. The RPCs sent to each backend are counted over an Interval, and if
+// there were at least MinRequests of them and either their error rate exceeded
+// MaxErrorRate or their mean latency exceeded MaxLatency, the backend is
+// ejected for EjectionDuration. At most half of a client's backends are ejected
+// at once.
+type GRPCEjectionConfig struct {
+	Interval         ConfigDuration
+	MinRequests      int
+	MaxErrorRate     float64
+	MaxLatency       ConfigDuration
+	EjectionDuration ConfigDuration
+}
+
+// Validate checks that an ejection config has an interval and ejection
+// duration, and at least one threshold.
+func (c *GRPCEjectionConfig) Validate() error {
+	if c.Interval.Duration <= 0 || c.EjectionDuration.Duration <= 0 {
+		return errors.New("Ejection Interval and EjectionDuration must be positive")
+	}
+	if c.MaxErrorRate < 0 || c.MaxErrorRate > 1 {
+		return errors.New("Ejection MaxErrorRate must be between 0 and 1")
+	}
+	if c.MaxErrorRate == 0 && c.MaxLatency.Duration <= 0 {
+		return errors.New("Ejection requires a MaxErrorRate or MaxLatency")
+	}
+	return nil
 }
 
 // GRPCServerConfig contains the information needed to run a gRPC service
 type GRPCServerConfig struct {
+	// Address is the host:port to listen on, or one of the non-TCP addresses
+	// described by ParseGRPCAddress.
 	Address string `json:"address"`
 	// ClientNames is a list of allowed client certificate subject alternate names
 	// (SANs). The server will reject clients that do not present a certificate
-	// with a SAN present on the `ClientNames` list.
+	// with a SAN present on the `ClientNames` list. It's unused for non-TCP
+	// addresses, which don't use TLS.
 	ClientNames []string `json:"clientNames"`
 }
 
+// ParseGRPCAddress returns the network and address of a gRPC server or client
+// address. Besides the host:port of a TCP address, which is used with mTLS, it
+// accepts "unix:///path/to/socket" for a Unix domain socket, to which access
+// is controlled by filesystem permissions rather than TLS. The network is
+// "tcp" or "unix".
+func ParseGRPCAddress(address string) (string, string, error) {
+	if strings.HasPrefix(address, "unix://") {
+		path := strings.TrimPrefix(address, "unix://")
+		if !filepath.IsAbs(path) {
+			return "", "", fmt.Errorf("Unix domain socket path %q must be absolute", path)
+		}
+		return "unix", path, nil
+	}
+	_, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return "", "", err
+	}
+	return "tcp", address, nil
+}
+
 // PortConfig specifies what ports the VA should call to on the remote
 // host when performing its checks.
 type PortConfig struct {
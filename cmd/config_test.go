@@ -1,10 +1,25 @@
 package cmd
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
 	"regexp"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+
+	blog "github.com/letsencrypt/boulder/log"
 	"github.com/letsencrypt/boulder/test"
 )
 
@@ -65,6 +80,21 @@ func TestGetMaxOpenConns(t *testing.T) {
 	}
 }
 
+func TestStartAdaptivePool(t *testing.T) {
+	// Without an AdaptivePool config nothing is started.
+	conf := DBConfig{MaxOpenConns: 10}
+	err := conf.StartAdaptivePool(nil, prometheus.NewRegistry(), blog.NewMock())
+	test.AssertNotError(t, err, "StartAdaptivePool failed without an AdaptivePool config")
+
+	// The pool's bounds come from MinOpenConns and the DBConfig's MaxOpenConns.
+	conf.AdaptivePool = &AdaptivePoolConfig{
+		MinOpenConns: 20,
+		TargetWait:   ConfigDuration{Duration: time.Millisecond},
+	}
+	err = conf.StartAdaptivePool(nil, prometheus.NewRegistry(), blog.NewMock())
+	test.AssertError(t, err, "StartAdaptivePool accepted MinOpenConns above MaxOpenConns")
+}
+
 func TestPasswordConfig(t *testing.T) {
 	tests := []struct {
 		pc       PasswordConfig
@@ -131,3 +161,167 @@ func TestTLSConfigLoad(t *testing.T) {
 		})
 	}
 }
+
+// writeTestCert writes a self-signed certificate valid from notBefore to
+// notAfter, and its key, to certFile and keyFile, returning the certificate.
+func writeTestCert(t *testing.T, certFile, keyFile string, notBefore, notAfter time.Time) []byte {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	test.AssertNotError(t, err, "generating key")
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(notAfter.UnixNano()),
+		DNSNames:     []string{"test.boulder"},
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	test.AssertNotError(t, err, "creating certificate")
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	test.AssertNotError(t, err, "marshaling key")
+	if certFile != "" {
+		err = ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600)
+		test.AssertNotError(t, err, "writing certificate")
+	}
+	if keyFile != "" {
+		err = ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600)
+		test.AssertNotError(t, err, "writing key")
+	}
+	return der
+}
+
+func TestTLSConfigReload(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tls-reload")
+	test.AssertNotError(t, err, "creating temp dir")
+	defer os.RemoveAll(dir)
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+	caCertFile := "testdata/minica.pem"
+	now := time.Now()
+	first := writeTestCert(t, certFile, keyFile, now.Add(-time.Hour), now.Add(time.Hour))
+
+	config, err := (&TLSConfig{&certFile, &keyFile, &caCertFile}).Load()
+	test.AssertNotError(t, err, "Load failed")
+	test.AssertByteEquals(t, config.Certificates[0].Certificate[0], first)
+	current := CurrentTLSConfig(config)
+	test.Assert(t, current != nil, "CurrentTLSConfig returned nil for a loaded config")
+	test.AssertByteEquals(t, current().Certificates[0].Certificate[0], first)
+	test.Assert(t, CurrentTLSConfig(&tls.Config{}) == nil, "CurrentTLSConfig returned non-nil for a config that doesn't reload")
+
+	// Loading the same files again shares the first config's reloader and
+	// the watchers on its files.
+	again, err := (&TLSConfig{&certFile, &keyFile, &caCertFile}).Load()
+	test.AssertNotError(t, err, "second Load failed")
+	rt1, _ := reloadingConfigs.Load(config)
+	rt2, _ := reloadingConfigs.Load(again)
+	test.Assert(t, rt1 == rt2, "configs loaded from the same files don't share a reloader")
+	tlsFiles.Lock()
+	test.AssertEquals(t, len(tlsFiles.watched[certFile]), 1)
+	tlsFiles.Unlock()
+
+	rt := &reloadingTLS{certFile: certFile, keyFile: keyFile, caCertFile: caCertFile}
+	test.AssertNotError(t, rt.reload(), "initial reload failed")
+	notAfter, err := test.GaugeValueWithLabels(tlsCertNotAfter, prometheus.Labels{"certfile": certFile})
+	test.AssertNotError(t, err, "getting certificate expiry")
+	test.AssertEquals(t, int64(notAfter), now.Add(time.Hour).Unix())
+
+	second := writeTestCert(t, certFile, keyFile, now.Add(-time.Hour), now.Add(2*time.Hour))
+	test.AssertNotError(t, rt.reload(), "reload failed")
+	test.AssertByteEquals(t, rt.config().Certificates[0].Certificate[0], second)
+	cert, err := rt.clientCertificate(nil)
+	test.AssertNotError(t, err, "clientCertificate failed")
+	test.AssertByteEquals(t, cert.Certificate[0], second)
+
+	// A certificate without its matching key isn't used.
+	writeTestCert(t, certFile, "", now.Add(-time.Hour), now.Add(3*time.Hour))
+	test.AssertError(t, rt.reload(), "reload accepted a certificate without its key")
+	test.AssertByteEquals(t, rt.config().Certificates[0].Certificate[0], second)
+
+	// Nor is an expired certificate.
+	writeTestCert(t, certFile, keyFile, now.Add(-2*time.Hour), now.Add(-time.Hour))
+	err = rt.reload()
+	test.AssertError(t, err, "reload accepted an expired certificate")
+	test.AssertContains(t, err.Error(), "is not valid now")
+	test.AssertByteEquals(t, rt.config().Certificates[0].Certificate[0], second)
+	notAfter, err = test.GaugeValueWithLabels(tlsCertNotAfter, prometheus.Labels{"certfile": certFile})
+	test.AssertNotError(t, err, "getting certificate expiry")
+	test.AssertEquals(t, int64(notAfter), now.Add(2*time.Hour).Unix())
+}
+
+func TestGRPCClientConfigValidate(t *testing.T) {
+	method := GRPCMethodConfig{
+		Timeout:       ConfigDuration{time.Second},
+		MaxRetries:    3,
+		BackoffBase:   ConfigDuration{10 * time.Millisecond},
+		BackoffMax:    ConfigDuration{100 * time.Millisecond},
+		BackoffFactor: 2,
+	}
+	valid := func() GRPCClientConfig {
+		return GRPCClientConfig{
+			ServerAddress: "sa.boulder:9095",
+			Timeout:       ConfigDuration{15 * time.Second},
+			HedgeAfter:    ConfigDuration{time.Second},
+			HedgeMethods:  []string{"GetRegistration"},
+			Ejection: &GRPCEjectionConfig{
+				Interval:         ConfigDuration{time.Minute},
+				MaxErrorRate:     0.5,
+				EjectionDuration: ConfigDuration{time.Minute},
+			},
+			Methods: map[string]GRPCMethodConfig{
+				"GetRegistration": method,
+				"FinalizeOrder":   {Timeout: ConfigDuration{time.Minute}},
+			},
+		}
+	}
+	c := valid()
+	test.AssertNotError(t, c.Validate(), "valid config rejected")
+
+	for _, modify := range []func(*GRPCClientConfig){
+		func(c *GRPCClientConfig) { c.ServerAddress = "" },
+		func(c *GRPCClientConfig) { c.ServerAddress = "sa.boulder" },
+		func(c *GRPCClientConfig) { c.ServerAddress = "unix://sa.sock" },
+		func(c *GRPCClientConfig) { c.HedgeAfter.Duration = 0 },
+		func(c *GRPCClientConfig) { c.HedgeAfter.Duration = time.Minute },
+		func(c *GRPCClientConfig) { c.Ejection.EjectionDuration.Duration = 0 },
+		func(c *GRPCClientConfig) { c.Ejection.MaxErrorRate = 2 },
+		func(c *GRPCClientConfig) { c.Ejection.MaxErrorRate = 0 },
+		func(c *GRPCClientConfig) { c.Methods["Bad"] = GRPCMethodConfig{Timeout: ConfigDuration{-time.Second}} },
+		func(c *GRPCClientConfig) { c.Methods["Bad"] = GRPCMethodConfig{MaxRetries: -1} },
+		func(c *GRPCClientConfig) { c.Methods["Bad"] = GRPCMethodConfig{MaxRetries: 1} },
+		func(c *GRPCClientConfig) {
+			m := method
+			m.BackoffMax.Duration = time.Millisecond
+			c.Methods["Bad"] = m
+		},
+		func(c *GRPCClientConfig) {
+			m := method
+			m.BackoffFactor = 0.5
+			c.Methods["Bad"] = m
+		},
+	} {
+		c := valid()
+		modify(&c)
+		test.AssertError(t, c.Validate(), "invalid config accepted")
+	}
+}
+
+func TestParseGRPCAddress(t *testing.T) {
+	for _, tc := range []struct {
+		address         string
+		expectedNetwork string
+		expectedAddress string
+	}{
+		{"sa.boulder:9095", "tcp", "sa.boulder:9095"},
+		{":9095", "tcp", ":9095"},
+		{"unix:///run/boulder/sa.sock", "unix", "/run/boulder/sa.sock"},
+	} {
+		network, address, err := ParseGRPCAddress(tc.address)
+		test.AssertNotError(t, err, "ParseGRPCAddress failed")
+		test.AssertEquals(t, network, tc.expectedNetwork)
+		test.AssertEquals(t, address, tc.expectedAddress)
+	}
+
+	for _, address := range []string{"", "sa.boulder", "unix://", "unix://sa.sock"} {
+		_, _, err := ParseGRPCAddress(address)
+		test.AssertError(t, err, fmt.Sprintf("ParseGRPCAddress accepted %q", address))
+	}
+}
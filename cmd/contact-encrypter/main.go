@@ -0,0 +1,208 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/letsencrypt/boulder/cmd"
+	"github.com/letsencrypt/boulder/db"
+	"github.com/letsencrypt/boulder/features"
+	blog "github.com/letsencrypt/boulder/log"
+	"github.com/letsencrypt/boulder/metrics"
+	"github.com/letsencrypt/boulder/sa"
+)
+
+type config struct {
+	ContactEncrypter struct {
+		cmd.DBConfig
+		ContactEncryption cmd.ContactEncryptionConfig
+		DebugAddr         string
+		Features          map[string]bool
+
+		// BatchSize is the number of registrations read from the database at
+		// a time. Defaults to 1000.
+		BatchSize int
+		// BatchDelay is how long to sleep between batches, to limit the load
+		// placed on the database.
+		BatchDelay cmd.ConfigDuration
+	}
+
+	Syslog cmd.SyslogConfig
+}
+
+var encryptedStat = prometheus.NewCounter(
+	prometheus.CounterOpts{
+		Name: "contact_encrypter_registrations_encrypted",
+		Help: "Number of registrations whose contacts the contact-encrypter tool has (re-)encrypted.",
+	},
+)
+
+// contactRow is a registration's ID and the raw contents of its contact
+// column. The contact is read as a string, rather than through the type
+// converter, so that it can be compared exactly when updating the row.
+type contactRow struct {
+	ID      int64  `db:"id"`
+	Contact string `db:"contact"`
+}
+
+type contactEncrypter struct {
+	dbMap     db.Executor
+	cipher    *sa.ContactCipher
+	log       blog.Logger
+	batchSize int
+}
+
+// encryptBatch encrypts, or re-encrypts with the active key, the contacts of
+// up to batchSize registrations with IDs greater than afterID. It returns the
+// highest ID examined, which is zero if there were no more registrations, and
+// the number of registrations updated.
+func (ce *contactEncrypter) encryptBatch(afterID int64) (int64, int, error) {
+	var rows []contactRow
+	_, err := ce.dbMap.Select(
+		&rows,
+		"SELECT id, contact FROM registrations WHERE id > ? ORDER BY id LIMIT ?",
+		afterID,
+		ce.batchSize,
+	)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var lastID int64
+	var updated int
+	for _, row := range rows {
+		lastID = row.ID
+		var stored []string
+		err := json.Unmarshal([]byte(row.Contact), &stored)
+		if err != nil {
+			ce.log.Errf("registration %d has malformed contact: %s", row.ID, err)
+			continue
+		}
+		if !ce.cipher.NeedsEncryption(stored) {
+			continue
+		}
+		contacts, err := ce.cipher.Decrypt(stored)
+		if err != nil {
+			return lastID, updated, fmt.Errorf("decrypting contact for registration %d: %s", row.ID, err)
+		}
+		encrypted, err := ce.cipher.Encrypt(contacts)
+		if err != nil {
+			return lastID, updated, err
+		}
+		newContact, err := json.Marshal(encrypted)
+		if err != nil {
+			return lastID, updated, err
+		}
+		// Only update the row if its contact hasn't changed since it was read,
+		// so a concurrent update by the SA isn't overwritten. If it has changed
+		// the SA will have encrypted it with the active key anyway.
+		res, err := ce.dbMap.Exec(
+			"UPDATE registrations SET contact = ? WHERE id = ? AND contact = ?",
+			string(newContact),
+			row.ID,
+			row.Contact,
+		)
+		if err != nil {
+			return lastID, updated, err
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return lastID, updated, err
+		}
+		if n == 0 {
+			ce.log.Infof("registration %d changed while being encrypted, skipping", row.ID)
+			continue
+		}
+		updated++
+	}
+	return lastID, updated, nil
+}
+
+// encryptAll walks the entire registrations table in batches, starting after
+// startID, and returns the total number of registrations updated.
+func (ce *contactEncrypter) encryptAll(startID int64, delay time.Duration) (int, error) {
+	var total int
+	afterID := startID
+	for {
+		lastID, updated, err := ce.encryptBatch(afterID)
+		total += updated
+		encryptedStat.Add(float64(updated))
+		if err != nil {
+			return total, err
+		}
+		if lastID == 0 {
+			return total, nil
+		}
+		ce.log.Infof("encrypted %d registrations with IDs in (%d, %d]", updated, afterID, lastID)
+		afterID = lastID
+		time.Sleep(delay)
+	}
+}
+
+func main() {
+	configPath := flag.String("config", "", "File path to the configuration file for this service")
+	startID := flag.Int64("start-id", 0, "Only encrypt registrations with IDs greater than this, e.g. to resume an interrupted run")
+	checkConfig := cmd.CheckConfigFlag()
+	flag.Parse()
+	if *configPath == "" {
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	var c config
+	err := cmd.ReadConfigFile(*configPath, &c)
+	cmd.FailOnError(err, "Reading JSON config file into config structure")
+	if *checkConfig {
+		cmd.CheckConfig(&c)
+	}
+	err = features.Set(c.ContactEncrypter.Features)
+	cmd.FailOnError(err, "Failed to set feature flags")
+
+	var logger blog.Logger
+	var stats prometheus.Registerer = metrics.NoopRegisterer
+	if c.ContactEncrypter.DebugAddr != "" {
+		stats, logger = cmd.StatsAndLogging(c.Syslog, c.ContactEncrypter.DebugAddr)
+		stats.MustRegister(encryptedStat)
+	} else {
+		logger = cmd.NewLogger(c.Syslog)
+	}
+	defer logger.AuditPanic()
+	logger.Info(cmd.VersionString())
+
+	keys, err := c.ContactEncrypter.ContactEncryption.Keys()
+	cmd.FailOnError(err, "Failed to load contact encryption keys")
+	cipher, err := sa.NewContactCipher(keys, c.ContactEncrypter.ContactEncryption.ActiveKeyID)
+	cmd.FailOnError(err, "Failed to create contact cipher")
+
+	dbURL, err := c.ContactEncrypter.DBConfig.URL()
+	cmd.FailOnError(err, "Couldn't load DB URL")
+	dbSettings := sa.DbSettings{
+		MaxOpenConns:    c.ContactEncrypter.DBConfig.GetMaxOpenConns(),
+		MaxIdleConns:    c.ContactEncrypter.DBConfig.MaxIdleConns,
+		ConnMaxLifetime: c.ContactEncrypter.DBConfig.ConnMaxLifetime.Duration,
+		ConnMaxIdleTime: c.ContactEncrypter.DBConfig.ConnMaxIdleTime.Duration,
+	}
+	dbMap, err := sa.NewDbMap(dbURL, dbSettings)
+	cmd.FailOnError(err, "Could not connect to database")
+	err = c.ContactEncrypter.DBConfig.StartAdaptivePool(dbMap.Db, stats, logger)
+	cmd.FailOnError(err, "Invalid adaptive DB pool config")
+
+	batchSize := c.ContactEncrypter.BatchSize
+	if batchSize < 1 {
+		batchSize = 1000
+	}
+	ce := &contactEncrypter{
+		dbMap:     dbMap,
+		cipher:    cipher,
+		log:       logger,
+		batchSize: batchSize,
+	}
+	total, err := ce.encryptAll(*startID, c.ContactEncrypter.BatchDelay.Duration)
+	logger.Infof("encrypted contacts for %d registrations", total)
+	cmd.FailOnError(err, "Failed to encrypt contacts")
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/jmhodges/clock"
+	"github.com/letsencrypt/boulder/core"
+	blog "github.com/letsencrypt/boulder/log"
+	"github.com/letsencrypt/boulder/metrics"
+	"github.com/letsencrypt/boulder/sa"
+	"github.com/letsencrypt/boulder/sa/satest"
+	"github.com/letsencrypt/boulder/test"
+	"github.com/letsencrypt/boulder/test/vars"
+	"gopkg.in/square/go-jose.v2"
+)
+
+func TestEncryptAll(t *testing.T) {
+	log := blog.UseMock()
+	fc := clock.NewFake()
+	fc.Set(time.Date(2015, 3, 4, 5, 0, 0, 0, time.UTC))
+
+	dbMap, err := sa.NewDbMap(vars.DBConnSA, sa.DbSettings{})
+	test.AssertNotError(t, err, "Couldn't connect to the database")
+	defer test.ResetSATestDatabase(t)()
+
+	// Create registrations in plaintext with an SA that has no cipher.
+	plainSA, err := sa.NewSQLStorageAuthority(dbMap, fc, log, metrics.NoopRegisterer, 1, 0, nil)
+	test.AssertNotError(t, err, "Failed to create SA")
+	reg := satest.CreateWorkingRegistration(t, plainSA)
+	contacts := reg.Contact
+	k, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	test.AssertNotError(t, err, "failed to generate test key")
+	_, err = plainSA.NewRegistration(context.Background(), core.Registration{
+		Key:       &jose.JSONWebKey{Key: k.Public()},
+		Contact:   &[]string{"mailto:two@example.com"},
+		InitialIP: net.ParseIP("1.2.3.4"),
+	})
+	test.AssertNotError(t, err, "NewRegistration failed")
+
+	oldKey := bytes.Repeat([]byte{1}, 32)
+	oldCipher, err := sa.NewContactCipher(map[string][]byte{"old": oldKey}, "old")
+	test.AssertNotError(t, err, "NewContactCipher failed")
+	ce := &contactEncrypter{
+		dbMap:     dbMap,
+		cipher:    oldCipher,
+		log:       log,
+		batchSize: 1,
+	}
+	total, err := ce.encryptAll(0, 0)
+	test.AssertNotError(t, err, "encryptAll failed")
+	test.AssertEquals(t, total, 2)
+
+	var stored string
+	err = dbMap.SelectOne(&stored, "SELECT contact FROM registrations WHERE id = ?", reg.ID)
+	test.AssertNotError(t, err, "selecting contact failed")
+	test.AssertContains(t, stored, "enc1:old:")
+
+	// Running again shouldn't re-encrypt anything.
+	total, err = ce.encryptAll(0, 0)
+	test.AssertNotError(t, err, "encryptAll failed")
+	test.AssertEquals(t, total, 0)
+
+	// Rotate to a new key.
+	newCipher, err := sa.NewContactCipher(map[string][]byte{"old": oldKey, "new": bytes.Repeat([]byte{2}, 32)}, "new")
+	test.AssertNotError(t, err, "NewContactCipher failed")
+	ce.cipher = newCipher
+	total, err = ce.encryptAll(0, 0)
+	test.AssertNotError(t, err, "encryptAll failed")
+	test.AssertEquals(t, total, 2)
+
+	// The rotated contacts can be read by an SA with only the new key.
+	newOnlyCipher, err := sa.NewContactCipher(map[string][]byte{"new": bytes.Repeat([]byte{2}, 32)}, "new")
+	test.AssertNotError(t, err, "NewContactCipher failed")
+	encSA, err := sa.NewSQLStorageAuthority(dbMap, fc, log, metrics.NoopRegisterer, 1, 0, newOnlyCipher)
+	test.AssertNotError(t, err, "Failed to create SA")
+	dbReg, err := encSA.GetRegistration(context.Background(), reg.ID)
+	test.AssertNotError(t, err, "GetRegistration failed")
+	test.AssertDeepEquals(t, dbReg.Contact, contacts)
+}
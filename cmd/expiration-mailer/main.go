@@ -22,6 +22,7 @@ import (
 	"github.com/letsencrypt/boulder/cmd"
 	"github.com/letsencrypt/boulder/core"
 	"github.com/letsencrypt/boulder/db"
+	berrors "github.com/letsencrypt/boulder/errors"
 	"github.com/letsencrypt/boulder/features"
 	bgrpc "github.com/letsencrypt/boulder/grpc"
 	blog "github.com/letsencrypt/boulder/log"
@@ -35,10 +36,18 @@ import (
 const (
 	defaultNagCheckInterval  = 24 * time.Hour
 	defaultExpirationSubject = "Let's Encrypt certificate expiration notice for domain {{.ExpirationSubject}}"
+
+	// nagChannelEmail and nagChannelWebhook identify the channel an
+	// expiration notice failed to be delivered over in expirationNagRetries.
+	nagChannelEmail   = "email"
+	nagChannelWebhook = "webhook"
 )
 
 type regStore interface {
 	GetRegistration(context.Context, int64) (core.Registration, error)
+	GetNotificationPreferences(context.Context, *sapb.RegistrationID) (*sapb.NotificationPreferences, error)
+	ReplacementCertificateExists(context.Context, *sapb.ReplacementCertificateExistsRequest) (*sapb.Exists, error)
+	GetCertificateStatuses(context.Context, *sapb.Serials) (*sapb.CertificateStatuses, error)
 }
 
 type mailer struct {
@@ -46,18 +55,24 @@ type mailer struct {
 	dbMap           *db.WrappedMap
 	rs              regStore
 	mailer          bmail.Mailer
+	webhook         *webhookNotifier
 	emailTemplate   *template.Template
 	subjectTemplate *template.Template
 	nagTimes        []time.Duration
-	limit           int
-	clk             clock.Clock
-	stats           mailerStats
+	// nagCheckInterval is added to the nag times in accounts' custom
+	// reminder schedules, as it is to nagTimes.
+	nagCheckInterval time.Duration
+	limit            int
+	clk              clock.Clock
+	stats            mailerStats
 }
 
 type mailerStats struct {
 	nagsAtCapacity    *prometheus.GaugeVec
 	errorCount        *prometheus.CounterVec
 	renewalCount      *prometheus.CounterVec
+	replacementCount  *prometheus.CounterVec
+	optOutCount       *prometheus.CounterVec
 	sendLatency       prometheus.Histogram
 	processingLatency prometheus.Histogram
 }
@@ -199,8 +214,22 @@ func (m *mailer) processCerts(allCerts []core.Certificate) {
 			m.stats.errorCount.With(prometheus.Labels{"type": "GetRegistration"}).Inc()
 			continue
 		}
+		prefs := m.notificationPreferences(ctx, regID)
+
+		// The certificates' statuses are only needed to check custom reminder
+		// schedules against the last notice sent.
+		var statuses map[string]core.CertificateStatus
+		if prefs != nil && len(prefs.NagTimes) > 0 {
+			statuses, err = m.certificateStatuses(ctx, certs)
+			if err != nil {
+				m.log.AuditErrf("Error fetching certificate statuses for registration %d: %s", regID, err)
+				m.stats.errorCount.With(prometheus.Labels{"type": "GetCertificateStatuses"}).Inc()
+				continue
+			}
+		}
 
 		parsedCerts := []*x509.Certificate{}
+		renewedCerts := []*x509.Certificate{}
 		for _, cert := range certs {
 			parsedCert, err := x509.ParseCertificate(cert.DER)
 			if err != nil {
@@ -220,29 +249,103 @@ func (m *mailer) processCerts(allCerts []core.Certificate) {
 					m.log.AuditErrf("Error updating certificate status for %s: %s", cert.Serial, err)
 					m.stats.errorCount.With(prometheus.Labels{"type": "UpdateCertificateStatus"}).Inc()
 				}
+				renewedCerts = append(renewedCerts, parsedCert)
 				continue
 			}
 
+			// The account may also have replaced the certificate with one
+			// covering additional names, which the fqdnSets check above
+			// doesn't detect.
+			replaced, err := m.rs.ReplacementCertificateExists(ctx, &sapb.ReplacementCertificateExistsRequest{
+				Serial:      cert.Serial,
+				RegID:       regID,
+				DnsNames:    parsedCert.DNSNames,
+				IssuedAfter: parsedCert.NotBefore.UnixNano(),
+			})
+			if err != nil {
+				m.log.AuditErrf("expiration-mailer: error checking for replacement of %s: %v", cert.Serial, err)
+				m.stats.errorCount.With(prometheus.Labels{"type": "ReplacementCertificateExists"}).Inc()
+				// assume not replaced
+			} else if replaced.Exists {
+				m.log.Debugf("Cert %s has been replaced", cert.Serial)
+				m.stats.replacementCount.With(prometheus.Labels{}).Inc()
+				if err := m.updateCertStatus(cert.Serial); err != nil {
+					m.log.AuditErrf("Error updating certificate status for %s: %s", cert.Serial, err)
+					m.stats.errorCount.With(prometheus.Labels{"type": "UpdateCertificateStatus"}).Inc()
+				}
+				renewedCerts = append(renewedCerts, parsedCert)
+				continue
+			}
+
+			if prefs != nil && len(prefs.NagTimes) > 0 {
+				status, ok := statuses[cert.Serial]
+				if !ok {
+					m.log.AuditErrf("Error checking reminder schedule for %s: no certificate status", cert.Serial)
+					m.stats.errorCount.With(prometheus.Labels{"type": "CheckNagSchedule"}).Inc()
+					continue
+				}
+				if !m.nagDue(parsedCert, status, prefs.NagTimes) {
+					// Leave the certificate status untouched so the
+					// certificate is selected again once it reaches one
+					// of the account's nag times.
+					continue
+				}
+			}
+
 			parsedCerts = append(parsedCerts, parsedCert)
 		}
 
 		if len(parsedCerts) == 0 {
-			// all certificates are renewed
+			// all certificates are renewed or not yet due
 			continue
 		}
 
-		if reg.Contact == nil {
-			continue
+		// Notices are sent by email to the account's contacts, or the address
+		// in its notification preferences, and, if the account has configured
+		// one, to its webhook. Accounts which have opted out of email are
+		// treated as having been emailed. The certificates are marked as
+		// notified once any channel has succeeded, and any channel which
+		// failed is recorded in expirationNagRetries, so that only it is
+		// retried by retryFailedNags. If every channel failed the certificates
+		// are left to be selected again on the next run.
+		delivered := false
+		var failed []string
+		contacts := nagContacts(reg, prefs)
+		if prefs != nil && prefs.EmailOptOut {
+			delivered = true
+			m.stats.optOutCount.With(prometheus.Labels{}).Add(float64(len(parsedCerts)))
+		} else if contacts != nil {
+			err = m.sendNags(*contacts, parsedCerts)
+			if err != nil {
+				failed = append(failed, nagChannelEmail)
+				m.stats.errorCount.With(prometheus.Labels{"type": "SendNags"}).Inc()
+				m.log.AuditErrf("Error sending nag emails: %s", err)
+			} else {
+				delivered = true
+			}
 		}
-
-		err = m.sendNags(*reg.Contact, parsedCerts)
-		if err != nil {
-			m.stats.errorCount.With(prometheus.Labels{"type": "SendNags"}).Inc()
-			m.log.AuditErrf("Error sending nag emails: %s", err)
+		if m.webhookEnabled(prefs) {
+			err = m.webhook.send(ctx, prefs, parsedCerts, renewedCerts)
+			if err != nil {
+				failed = append(failed, nagChannelWebhook)
+				m.stats.errorCount.With(prometheus.Labels{"type": "SendWebhook"}).Inc()
+				m.log.AuditErrf("Error sending nag webhook for registration %d: %s", regID, err)
+			} else {
+				delivered = true
+			}
+		}
+		if !delivered {
 			continue
 		}
 		for _, cert := range parsedCerts {
 			serial := core.SerialToString(cert.SerialNumber)
+			for _, channel := range failed {
+				err = m.addNagRetry(serial, channel, regID)
+				if err != nil {
+					m.log.AuditErrf("Error recording %s nag retry for %s: %s", channel, serial, err)
+					m.stats.errorCount.With(prometheus.Labels{"type": "AddNagRetry"}).Inc()
+				}
+			}
 			err = m.updateCertStatus(serial)
 			if err != nil {
 				m.log.AuditErrf("Error updating certificate status for %s: %s", serial, err)
@@ -253,7 +356,306 @@ func (m *mailer) processCerts(allCerts []core.Certificate) {
 	}
 }
 
+// nagContacts returns the contacts which should be emailed expiration notices
+// for reg: the address in its notification preferences, if any, or else its
+// own contacts.
+func nagContacts(reg core.Registration, prefs *sapb.NotificationPreferences) *[]string {
+	if prefs != nil && prefs.Email != "" {
+		return &[]string{"mailto:" + prefs.Email}
+	}
+	return reg.Contact
+}
+
+// webhookEnabled returns true if expiration notices should be sent to the
+// webhook in prefs.
+func (m *mailer) webhookEnabled(prefs *sapb.NotificationPreferences) bool {
+	return m.webhook != nil && prefs != nil && prefs.WebhookURL != ""
+}
+
+// nagRetry represents a row in the expirationNagRetries table: an expiration
+// notice for a certificate which was marked as notified, but wasn't delivered
+// over one of its account's channels.
+type nagRetry struct {
+	Serial         string `db:"serial"`
+	Channel        string `db:"channel"`
+	RegistrationID int64  `db:"registrationID"`
+}
+
+func (m *mailer) addNagRetry(serial, channel string, regID int64) error {
+	_, err := m.dbMap.Exec(
+		`INSERT IGNORE INTO expirationNagRetries (serial, channel, registrationID, created)
+		VALUES (?, ?, ?, ?)`,
+		serial, channel, regID, m.clk.Now())
+	return err
+}
+
+func (m *mailer) deleteNagRetry(serial, channel string) {
+	_, err := m.dbMap.Exec(
+		"DELETE FROM expirationNagRetries WHERE serial = ? AND channel = ?",
+		serial, channel)
+	if err != nil {
+		m.log.AuditErrf("Error deleting %s nag retry for %s: %s", channel, serial, err)
+		m.stats.errorCount.With(prometheus.Labels{"type": "DeleteNagRetry"}).Inc()
+	}
+}
+
+// retryFailedNags resends the expiration notices recorded in
+// expirationNagRetries, over only the channel which failed, grouped by
+// account. A retry is dropped once it succeeds, once its certificate has
+// expired, or once the account no longer uses the channel. Errors are logged
+// rather than returned, so they don't prevent new notices being sent.
+func (m *mailer) retryFailedNags() {
+	ctx := context.Background()
+
+	var retries []nagRetry
+	_, err := m.dbMap.Select(
+		&retries,
+		`SELECT serial, channel, registrationID
+		FROM expirationNagRetries
+		ORDER BY created ASC
+		LIMIT :limit`,
+		map[string]interface{}{"limit": m.limit},
+	)
+	if err != nil {
+		m.log.AuditErrf("expiration-mailer: Error loading nag retries: %s", err)
+		m.stats.errorCount.With(prometheus.Labels{"type": "GetNagRetries"}).Inc()
+		return
+	}
+	if len(retries) == 0 {
+		return
+	}
+
+	type retryGroup struct {
+		regID   int64
+		channel string
+	}
+	groups := make(map[retryGroup][]*x509.Certificate)
+	for _, retry := range retries {
+		cert, err := sa.SelectCertificate(m.dbMap, retry.Serial)
+		if err != nil {
+			if db.IsNoRows(err) {
+				m.deleteNagRetry(retry.Serial, retry.Channel)
+				continue
+			}
+			m.log.AuditErrf("expiration-mailer: Error loading cert %q: %s", retry.Serial, err)
+			continue
+		}
+		parsedCert, err := x509.ParseCertificate(cert.DER)
+		if err != nil {
+			m.log.AuditErrf("Error parsing certificate %s: %s", cert.Serial, err)
+			m.stats.errorCount.With(prometheus.Labels{"type": "ParseCertificate"}).Inc()
+			m.deleteNagRetry(retry.Serial, retry.Channel)
+			continue
+		}
+		if !parsedCert.NotAfter.After(m.clk.Now()) {
+			m.deleteNagRetry(retry.Serial, retry.Channel)
+			continue
+		}
+		group := retryGroup{regID: retry.RegistrationID, channel: retry.Channel}
+		groups[group] = append(groups[group], parsedCert)
+	}
+
+	connected := false
+	defer func() {
+		if connected {
+			_ = m.mailer.Close()
+		}
+	}()
+	for group, certs := range groups {
+		prefs := m.notificationPreferences(ctx, group.regID)
+		switch group.channel {
+		case nagChannelEmail:
+			if prefs != nil && prefs.EmailOptOut {
+				break
+			}
+			reg, err := m.rs.GetRegistration(ctx, group.regID)
+			if err != nil {
+				m.log.AuditErrf("Error fetching registration %d: %s", group.regID, err)
+				m.stats.errorCount.With(prometheus.Labels{"type": "GetRegistration"}).Inc()
+				continue
+			}
+			contacts := nagContacts(reg, prefs)
+			if contacts == nil {
+				break
+			}
+			if !connected {
+				err = m.mailer.Connect()
+				if err != nil {
+					m.log.AuditErrf("Error connecting to send nag emails: %s", err)
+					continue
+				}
+				connected = true
+			}
+			err = m.sendNags(*contacts, certs)
+			if err != nil {
+				m.stats.errorCount.With(prometheus.Labels{"type": "SendNags"}).Inc()
+				m.log.AuditErrf("Error resending nag emails: %s", err)
+				continue
+			}
+		case nagChannelWebhook:
+			if !m.webhookEnabled(prefs) {
+				break
+			}
+			err = m.webhook.send(ctx, prefs, certs, nil)
+			if err != nil {
+				m.stats.errorCount.With(prometheus.Labels{"type": "SendWebhook"}).Inc()
+				m.log.AuditErrf("Error resending nag webhook for registration %d: %s", group.regID, err)
+				continue
+			}
+		}
+		for _, cert := range certs {
+			m.deleteNagRetry(core.SerialToString(cert.SerialNumber), group.channel)
+		}
+	}
+}
+
+// notificationPreferences returns the notification preferences of the given
+// registration, or nil if it has none or they couldn't be retrieved.
+func (m *mailer) notificationPreferences(ctx context.Context, regID int64) *sapb.NotificationPreferences {
+	prefs, err := m.rs.GetNotificationPreferences(ctx, &sapb.RegistrationID{Id: regID})
+	if err != nil {
+		if !errors.Is(err, berrors.NotFound) {
+			m.log.AuditErrf("Error fetching notification preferences for registration %d: %s", regID, err)
+			m.stats.errorCount.With(prometheus.Labels{"type": "GetNotificationPreferences"}).Inc()
+		}
+		return nil
+	}
+	return prefs
+}
+
+// certificateStatuses returns the statuses of certs, by serial, fetched from
+// the SA in as few requests as it allows.
+func (m *mailer) certificateStatuses(ctx context.Context, certs []core.Certificate) (map[string]core.CertificateStatus, error) {
+	statuses := make(map[string]core.CertificateStatus, len(certs))
+	for start := 0; start < len(certs); start += sa.MaxCertificateStatusesPerRequest {
+		end := start + sa.MaxCertificateStatusesPerRequest
+		if end > len(certs) {
+			end = len(certs)
+		}
+		serials := make([]string, 0, end-start)
+		for _, cert := range certs[start:end] {
+			serials = append(serials, cert.Serial)
+		}
+		resp, err := m.rs.GetCertificateStatuses(ctx, &sapb.Serials{Serials: serials})
+		if err != nil {
+			return nil, err
+		}
+		for _, pb := range resp.Statuses {
+			status, err := bgrpc.PBToCertStatus(pb)
+			if err != nil {
+				return nil, err
+			}
+			statuses[status.Serial] = status
+		}
+	}
+	return statuses, nil
+}
+
+// nagDue returns true if a notice is due for cert, whose status is given,
+// under a custom reminder schedule: its remaining lifetime has fallen within
+// one of nagTimes, and no notice has been sent since it did. Like the
+// configured nag times, each entry is extended by nagCheckInterval. Custom
+// schedules can only take effect within the windows searched by
+// findExpiringCertificates, so an entry longer than the longest configured nag
+// time behaves like that nag time.
+func (m *mailer) nagDue(cert *x509.Certificate, status core.CertificateStatus, nagTimes []int64) bool {
+	remaining := cert.NotAfter.Sub(m.clk.Now())
+	for _, nt := range nagTimes {
+		nagTime := time.Duration(nt) + m.nagCheckInterval
+		if remaining > nagTime {
+			continue
+		}
+		if status.LastExpirationNagSent.IsZero() || cert.NotAfter.Sub(status.LastExpirationNagSent) > nagTime {
+			return true
+		}
+	}
+	return false
+}
+
+// nagTimeModel represents a row in the notificationNagTimes table.
+type nagTimeModel struct {
+	RegistrationID int64 `db:"registrationID"`
+	NagTime        int64 `db:"nagTime"`
+}
+
+// loadNagSchedules returns the custom reminder schedule, in seconds, of each
+// account which has one.
+func (m *mailer) loadNagSchedules() (map[int64][]int64, error) {
+	var rows []nagTimeModel
+	_, err := m.dbMap.Select(&rows, "SELECT registrationID, nagTime FROM notificationNagTimes")
+	if err != nil {
+		return nil, err
+	}
+	schedules := make(map[int64][]int64)
+	for _, row := range rows {
+		schedules[row.RegistrationID] = append(schedules[row.RegistrationID], row.NagTime)
+	}
+	return schedules, nil
+}
+
+// findScheduledSerials returns the serials of the certificates expiring
+// between left and right whose accounts have a custom reminder schedule, and
+// those of them which are due a notice under it, like nagDue. The accounts'
+// certificates are found by registration ID, and then their statuses checked
+// against the schedule, rather than by joining the two tables.
+func (m *mailer) findScheduledSerials(schedules map[int64][]int64, left, right time.Time) ([]string, []string, error) {
+	var all, due []string
+	for regID, nagTimes := range schedules {
+		var serials []string
+		_, err := m.dbMap.Select(
+			&serials,
+			"SELECT serial FROM certificates WHERE registrationID = ? AND expires > ? AND expires <= ?",
+			regID,
+			left,
+			right,
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(serials) == 0 {
+			continue
+		}
+		all = append(all, serials...)
+
+		var args []interface{}
+		qmarks := make([]string, len(serials))
+		for i, serial := range serials {
+			qmarks[i] = "?"
+			args = append(args, serial)
+		}
+		conds := make([]string, len(nagTimes))
+		for i, nagTime := range nagTimes {
+			conds[i] = `(TIMESTAMPDIFF(SECOND, ?, notAfter) <= ?
+				AND COALESCE(TIMESTAMPDIFF(SECOND, lastExpirationNagSent, notAfter) > ?, 1))`
+			window := float64(nagTime) + m.nagCheckInterval.Seconds()
+			args = append(args, m.clk.Now(), window, window)
+		}
+		var dueSerials []string
+		_, err = m.dbMap.Select(
+			&dueSerials,
+			`SELECT serial FROM certificateStatus
+			WHERE serial IN (`+strings.Join(qmarks, ",")+`)
+			AND status != "revoked"
+			AND (`+strings.Join(conds, " OR ")+`)`,
+			args...,
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+		due = append(due, dueSerials...)
+	}
+	return all, due, nil
+}
+
 func (m *mailer) findExpiringCertificates() error {
+	m.retryFailedNags()
+
+	schedules, err := m.loadNagSchedules()
+	if err != nil {
+		m.log.AuditErrf("expiration-mailer: Error loading reminder schedules: %s", err)
+		return err
+	}
+
 	now := m.clk.Now()
 	// E.g. m.nagTimes = [2, 4, 8, 15] days from expiration
 	for i, expiresIn := range m.nagTimes {
@@ -266,33 +668,52 @@ func (m *mailer) findExpiringCertificates() error {
 		m.log.Infof("expiration-mailer: Searching for certificates that expire between %s and %s and had last nag >%s before expiry",
 			left.UTC(), right.UTC(), expiresIn)
 
+		// Certificates whose accounts have a custom reminder schedule are
+		// selected only when a notice is due under it, and are excluded from
+		// the query below so that they don't count towards its limit while
+		// they aren't.
+		scheduled, due, err := m.findScheduledSerials(schedules, left, right)
+		if err != nil {
+			m.log.AuditErrf("expiration-mailer: Error loading scheduled certificate serials: %s", err)
+			return err
+		}
+
 		// First we do a query on the certificateStatus table to find certificates
 		// nearing expiry meeting our criteria for email notification. We later
 		// sequentially fetch the certificate details. This avoids an expensive
 		// JOIN.
+		args := []interface{}{left, right, expiresIn.Seconds()}
+		var exclude string
+		if len(scheduled) > 0 {
+			qmarks := make([]string, len(scheduled))
+			for i, serial := range scheduled {
+				qmarks[i] = "?"
+				args = append(args, serial)
+			}
+			exclude = "AND cs.serial NOT IN (" + strings.Join(qmarks, ",") + ")"
+		}
+		args = append(args, m.limit)
 		var serials []string
-		_, err := m.dbMap.Select(
+		_, err = m.dbMap.Select(
 			&serials,
 			`SELECT
 				cs.serial
 				FROM certificateStatus AS cs
-				WHERE cs.notAfter > :cutoffA
-				AND cs.notAfter <= :cutoffB
+				WHERE cs.notAfter > ?
+				AND cs.notAfter <= ?
 				AND cs.status != "revoked"
-				AND COALESCE(TIMESTAMPDIFF(SECOND, cs.lastExpirationNagSent, cs.notAfter) > :nagCutoff, 1)
+				AND COALESCE(TIMESTAMPDIFF(SECOND, cs.lastExpirationNagSent, cs.notAfter) > ?, 1)
+				`+exclude+`
 				ORDER BY cs.notAfter ASC
-				LIMIT :limit`,
-			map[string]interface{}{
-				"cutoffA":   left,
-				"cutoffB":   right,
-				"nagCutoff": expiresIn.Seconds(),
-				"limit":     m.limit,
-			},
+				LIMIT ?`,
+			args...,
 		)
 		if err != nil {
 			m.log.AuditErrf("expiration-mailer: Error loading certificate serials: %s", err)
 			return err
 		}
+		atLimit := len(serials) == m.limit
+		serials = append(serials, due...)
 
 		// Now we can sequentially retrieve the certificate details for each of the
 		// certificate status rows
@@ -317,7 +738,7 @@ func (m *mailer) findExpiringCertificates() error {
 		m.log.Infof("Found %d certificates expiring between %s and %s", len(certs),
 			left.Format("2006-01-02 03:04"), right.Format("2006-01-02 03:04"))
 
-		// If the `certs` result was exactly `m.limit` rows we need to increment
+		// If the `serials` result was exactly `m.limit` rows we need to increment
 		// a stat indicating that this nag group is at capacity based on the
 		// configured cert limit. If this condition continually occurs across mailer
 		// runs then we will not catch up, resulting in under-sending expiration
@@ -325,7 +746,7 @@ func (m *mailer) findExpiringCertificates() error {
 		//
 		// 0: https://github.com/letsencrypt/boulder/issues/2002
 		atCapacity := float64(0)
-		if len(certs) == m.limit {
+		if atLimit {
 			m.log.Infof("nag group %s expiring certificates at configured capacity (cert limit %d)",
 				expiresIn.String(), m.limit)
 			atCapacity = float64(1)
@@ -387,6 +808,10 @@ type config struct {
 		// during the SMTP connection (as opposed to the gRPC connections).
 		SMTPTrustedRootFile string
 
+		// WebhookTimeout bounds each request made to an account's webhook
+		// URL. Defaults to 10 seconds.
+		WebhookTimeout cmd.ConfigDuration
+
 		Features map[string]bool
 	}
 
@@ -418,6 +843,22 @@ func initStats(stats prometheus.Registerer) mailerStats {
 		nil)
 	stats.MustRegister(renewalCount)
 
+	replacementCount := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "replacements",
+			Help: "Number of messages skipped because the account has issued a certificate covering the same names",
+		},
+		nil)
+	stats.MustRegister(replacementCount)
+
+	optOutCount := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "opt_outs",
+			Help: "Number of certificates skipped because their account opted out of expiration email",
+		},
+		nil)
+	stats.MustRegister(optOutCount)
+
 	sendLatency := prometheus.NewHistogram(
 		prometheus.HistogramOpts{
 			Name:    "send_latency",
@@ -437,6 +878,8 @@ func initStats(stats prometheus.Registerer) mailerStats {
 		nagsAtCapacity:    nagsAtCapacity,
 		errorCount:        errorCount,
 		renewalCount:      renewalCount,
+		replacementCount:  replacementCount,
+		optOutCount:       optOutCount,
 		sendLatency:       sendLatency,
 		processingLatency: processingLatency,
 	}
@@ -449,6 +892,7 @@ func main() {
 	reconnMax := flag.Duration("reconnectMax", 5*60*time.Second, "Max sleep duration between reconnect attempts after exponential backoff")
 	daemon := flag.Bool("daemon", false, "Run in daemon mode")
 
+	checkConfig := cmd.CheckConfigFlag()
 	flag.Parse()
 
 	if *configFile == "" {
@@ -459,6 +903,9 @@ func main() {
 	var c config
 	err := cmd.ReadConfigFile(*configFile, &c)
 	cmd.FailOnError(err, "Reading JSON config file into config structure")
+	if *checkConfig {
+		cmd.CheckConfig(&c)
+	}
 	err = features.Set(c.Mailer.Features)
 	cmd.FailOnError(err, "Failed to set feature flags")
 
@@ -489,6 +936,8 @@ func main() {
 
 	// Collect and periodically report DB metrics using the DBMap and prometheus scope.
 	sa.InitDBMetrics(dbMap, scope, dbSettings)
+	err = c.Mailer.DBConfig.StartAdaptivePool(dbMap.Db, scope, logger)
+	cmd.FailOnError(err, "Invalid adaptive DB pool config")
 
 	tlsConfig, err := c.Mailer.TLS.Load()
 	cmd.FailOnError(err, "TLS config")
@@ -540,6 +989,21 @@ func main() {
 		scope,
 		*reconnBase,
 		*reconnMax)
+	err = mailClient.SetPoolConfig(c.Mailer.Pool.PoolConfig())
+	cmd.FailOnError(err, "Invalid SMTP pool config")
+	if c.Mailer.DKIM != nil {
+		dkimSigner, err := c.Mailer.DKIM.Load(clk)
+		cmd.FailOnError(err, "Failed to load DKIM keys")
+		mailClient.SetDKIMSigner(dkimSigner)
+	}
+
+	if c.Mailer.WebhookTimeout.Duration == 0 {
+		c.Mailer.WebhookTimeout.Duration = 10 * time.Second
+	}
+	webhook := &webhookNotifier{
+		client: newWebhookClient(c.Mailer.WebhookTimeout.Duration),
+		clk:    clk,
+	}
 
 	nagCheckInterval := defaultNagCheckInterval
 	if s := c.Mailer.NagCheckInterval; s != "" {
@@ -563,16 +1027,18 @@ func main() {
 	sort.Sort(nags)
 
 	m := mailer{
-		log:             logger,
-		dbMap:           dbMap,
-		rs:              sac,
-		mailer:          mailClient,
-		subjectTemplate: subjTmpl,
-		emailTemplate:   tmpl,
-		nagTimes:        nags,
-		limit:           c.Mailer.CertLimit,
-		clk:             clk,
-		stats:           initStats(scope),
+		log:              logger,
+		dbMap:            dbMap,
+		rs:               sac,
+		mailer:           mailClient,
+		webhook:          webhook,
+		subjectTemplate:  subjTmpl,
+		emailTemplate:    tmpl,
+		nagTimes:         nags,
+		nagCheckInterval: nagCheckInterval,
+		limit:            c.Mailer.CertLimit,
+		clk:              clk,
+		stats:            initStats(scope),
 	}
 
 	// Prefill this labelled stat with the possible label values, so each value is
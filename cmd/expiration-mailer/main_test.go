@@ -11,6 +11,8 @@ import (
 	"fmt"
 	"math/big"
 	"net"
+	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 	"text/template"
@@ -24,6 +26,7 @@ import (
 	"github.com/letsencrypt/boulder/metrics"
 	"github.com/letsencrypt/boulder/mocks"
 	"github.com/letsencrypt/boulder/sa"
+	sapb "github.com/letsencrypt/boulder/sa/proto"
 	"github.com/letsencrypt/boulder/sa/satest"
 	"github.com/letsencrypt/boulder/test"
 	"github.com/letsencrypt/boulder/test/vars"
@@ -55,6 +58,18 @@ func (f fakeRegStore) GetRegistration(ctx context.Context, id int64) (core.Regis
 	return r, nil
 }
 
+func (f fakeRegStore) GetNotificationPreferences(ctx context.Context, req *sapb.RegistrationID) (*sapb.NotificationPreferences, error) {
+	return nil, berrors.NotFoundError("no notification preferences for registration %d", req.Id)
+}
+
+func (f fakeRegStore) ReplacementCertificateExists(ctx context.Context, req *sapb.ReplacementCertificateExistsRequest) (*sapb.Exists, error) {
+	return &sapb.Exists{Exists: false}, nil
+}
+
+func (f fakeRegStore) GetCertificateStatuses(ctx context.Context, req *sapb.Serials) (*sapb.CertificateStatuses, error) {
+	return &sapb.CertificateStatuses{}, nil
+}
+
 func newFakeRegStore() fakeRegStore {
 	return fakeRegStore{RegByID: make(map[int64]core.Registration)}
 }
@@ -800,6 +815,204 @@ func TestDedupOnRegistration(t *testing.T) {
 	test.AssertEquals(t, expected, testCtx.mc.Messages[0])
 }
 
+func TestProcessCertsNotificationPreferences(t *testing.T) {
+	testCtx := setup(t, []time.Duration{7 * 24 * time.Hour})
+	defer testCtx.cleanUp()
+
+	var keyA jose.JSONWebKey
+	err := json.Unmarshal(jsonKeyA, &keyA)
+	test.AssertNotError(t, err, "Failed to unmarshal public JWK")
+	reg, err := testCtx.ssa.NewRegistration(ctx, core.Registration{
+		Contact:   &[]string{emailA},
+		Key:       &keyA,
+		InitialIP: net.ParseIP("6.5.5.6"),
+	})
+	test.AssertNotError(t, err, "Couldn't store registration")
+
+	rawCert := newX509Cert("happy A", testCtx.fc.Now().Add(72*time.Hour), []string{"example-a.com"}, serial1)
+	certDer, _ := x509.CreateCertificate(rand.Reader, rawCert, rawCert, &testKey.PublicKey, &testKey)
+	cert := core.Certificate{
+		RegistrationID: reg.ID,
+		Serial:         serial1String,
+		Expires:        rawCert.NotAfter,
+		DER:            certDer,
+	}
+	setupDBMap, err := sa.NewDbMap(vars.DBConnSAFullPerms, sa.DbSettings{})
+	test.AssertNotError(t, err, "sa.NewDbMap failed")
+	err = setupDBMap.Insert(&cert)
+	test.AssertNotError(t, err, "Couldn't add cert")
+	resetStatus := func() {
+		_, err = setupDBMap.Exec("REPLACE INTO certificateStatus (serial, lastExpirationNagSent, status, notAfter, ocspLastUpdated, revokedDate, revokedReason) VALUES (?,?,?,?,?,?,?)", serial1String, time.Unix(0, 0), string(core.OCSPStatusGood), rawCert.NotAfter, time.Time{}, time.Time{}, 0)
+		test.AssertNotError(t, err, "Couldn't add certificate status")
+		testCtx.mc.Clear()
+	}
+	lastNagSent := func() time.Time {
+		status, err := sa.SelectCertificateStatus(setupDBMap, serial1String)
+		test.AssertNotError(t, err, "Couldn't get certificate status")
+		return status.LastExpirationNagSent
+	}
+	setPrefs := func(prefs *sapb.NotificationPreferences) {
+		prefs.RegistrationID = reg.ID
+		_, err := testCtx.ssa.SetNotificationPreferences(ctx, prefs)
+		test.AssertNotError(t, err, "SetNotificationPreferences failed")
+	}
+
+	// A notification address replaces the registration's contacts.
+	resetStatus()
+	setPrefs(&sapb.NotificationPreferences{Email: "ops@example.com"})
+	testCtx.m.processCerts([]core.Certificate{cert})
+	test.AssertEquals(t, len(testCtx.mc.Messages), 1)
+	test.AssertEquals(t, testCtx.mc.Messages[0].To, "ops@example.com")
+
+	// Opted out accounts aren't emailed, but their certificates are marked as
+	// notified so they aren't selected again.
+	resetStatus()
+	setPrefs(&sapb.NotificationPreferences{EmailOptOut: true})
+	testCtx.m.processCerts([]core.Certificate{cert})
+	test.AssertEquals(t, len(testCtx.mc.Messages), 0)
+	test.Assert(t, lastNagSent().After(time.Unix(0, 0)), "opted out certificate wasn't marked as notified")
+
+	// With a custom schedule, nothing is sent until the certificate reaches
+	// one of the account's nag times.
+	resetStatus()
+	setPrefs(&sapb.NotificationPreferences{NagTimes: []int64{int64(24 * time.Hour)}})
+	testCtx.m.processCerts([]core.Certificate{cert})
+	test.AssertEquals(t, len(testCtx.mc.Messages), 0)
+	test.Assert(t, lastNagSent().Equal(time.Unix(0, 0)), "certificate was marked as notified before it was due")
+	testCtx.fc.Add(25 * time.Hour)
+	testCtx.m.processCerts([]core.Certificate{cert})
+	test.AssertEquals(t, len(testCtx.mc.Messages), 1)
+	test.AssertEquals(t, testCtx.mc.Messages[0].To, emailARaw)
+}
+
+func TestFindExpiringCertificatesNagTimes(t *testing.T) {
+	testCtx := setup(t, []time.Duration{7 * 24 * time.Hour})
+	defer testCtx.cleanUp()
+
+	var keyA jose.JSONWebKey
+	err := json.Unmarshal(jsonKeyA, &keyA)
+	test.AssertNotError(t, err, "Failed to unmarshal public JWK")
+	reg, err := testCtx.ssa.NewRegistration(ctx, core.Registration{
+		Contact:   &[]string{emailA},
+		Key:       &keyA,
+		InitialIP: net.ParseIP("6.5.5.6"),
+	})
+	test.AssertNotError(t, err, "Couldn't store registration")
+	_, err = testCtx.ssa.SetNotificationPreferences(ctx, &sapb.NotificationPreferences{
+		RegistrationID: reg.ID,
+		NagTimes:       []int64{int64(24 * time.Hour)},
+	})
+	test.AssertNotError(t, err, "SetNotificationPreferences failed")
+
+	rawCert := newX509Cert("happy A", testCtx.fc.Now().Add(72*time.Hour), []string{"example-a.com"}, serial1)
+	certDer, _ := x509.CreateCertificate(rand.Reader, rawCert, rawCert, &testKey.PublicKey, &testKey)
+	setupDBMap, err := sa.NewDbMap(vars.DBConnSAFullPerms, sa.DbSettings{})
+	test.AssertNotError(t, err, "sa.NewDbMap failed")
+	err = setupDBMap.Insert(&core.Certificate{
+		RegistrationID: reg.ID,
+		Serial:         serial1String,
+		Expires:        rawCert.NotAfter,
+		DER:            certDer,
+	})
+	test.AssertNotError(t, err, "Couldn't add cert")
+	_, err = setupDBMap.Exec("INSERT INTO certificateStatus (serial, lastExpirationNagSent, status, notAfter, ocspLastUpdated, revokedDate, revokedReason) VALUES (?,?,?,?,?,?,?)", serial1String, time.Unix(0, 0), string(core.OCSPStatusGood), rawCert.NotAfter, time.Time{}, time.Time{}, 0)
+	test.AssertNotError(t, err, "Couldn't add certificate status")
+
+	// The certificate is within the configured nag window, but isn't
+	// selected until it's due under the account's schedule, so it doesn't
+	// count towards the limit.
+	testCtx.m.limit = 1
+	err = testCtx.m.findExpiringCertificates()
+	test.AssertNotError(t, err, "Failed on no certificates")
+	test.AssertEquals(t, len(testCtx.mc.Messages), 0)
+	test.AssertEquals(t, countGroupsAtCapacity("168h0m0s", testCtx.m.stats.nagsAtCapacity), 0)
+
+	testCtx.fc.Add(25 * time.Hour)
+	err = testCtx.m.findExpiringCertificates()
+	test.AssertNotError(t, err, "Failed to find expiring certificates")
+	test.AssertEquals(t, len(testCtx.mc.Messages), 1)
+
+	// Once notified, it isn't selected again for the same nag time.
+	testCtx.mc.Clear()
+	err = testCtx.m.findExpiringCertificates()
+	test.AssertNotError(t, err, "Failed to find expiring certificates")
+	test.AssertEquals(t, len(testCtx.mc.Messages), 0)
+}
+
+func TestProcessCertsWebhookRetry(t *testing.T) {
+	testCtx := setup(t, []time.Duration{7 * 24 * time.Hour})
+	defer testCtx.cleanUp()
+
+	hookStatus := http.StatusInternalServerError
+	hookCalls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hookCalls++
+		w.WriteHeader(hookStatus)
+	}))
+	defer server.Close()
+	testCtx.m.webhook = &webhookNotifier{client: server.Client(), clk: testCtx.fc}
+
+	var keyA jose.JSONWebKey
+	err := json.Unmarshal(jsonKeyA, &keyA)
+	test.AssertNotError(t, err, "Failed to unmarshal public JWK")
+	reg, err := testCtx.ssa.NewRegistration(ctx, core.Registration{
+		Contact:   &[]string{emailA},
+		Key:       &keyA,
+		InitialIP: net.ParseIP("6.5.5.6"),
+	})
+	test.AssertNotError(t, err, "Couldn't store registration")
+	_, err = testCtx.ssa.SetNotificationPreferences(ctx, &sapb.NotificationPreferences{
+		RegistrationID: reg.ID,
+		WebhookURL:     server.URL,
+		WebhookSecret:  []byte("0123456789abcdef"),
+	})
+	test.AssertNotError(t, err, "SetNotificationPreferences failed")
+
+	rawCert := newX509Cert("happy A", testCtx.fc.Now().Add(72*time.Hour), []string{"example-a.com"}, serial1)
+	certDer, _ := x509.CreateCertificate(rand.Reader, rawCert, rawCert, &testKey.PublicKey, &testKey)
+	cert := core.Certificate{
+		RegistrationID: reg.ID,
+		Serial:         serial1String,
+		Expires:        rawCert.NotAfter,
+		DER:            certDer,
+	}
+	setupDBMap, err := sa.NewDbMap(vars.DBConnSAFullPerms, sa.DbSettings{})
+	test.AssertNotError(t, err, "sa.NewDbMap failed")
+	err = setupDBMap.Insert(&cert)
+	test.AssertNotError(t, err, "Couldn't add cert")
+	_, err = setupDBMap.Exec("INSERT INTO certificateStatus (serial, lastExpirationNagSent, status, notAfter, ocspLastUpdated, revokedDate, revokedReason) VALUES (?,?,?,?,?,?,?)", serial1String, time.Unix(0, 0), string(core.OCSPStatusGood), rawCert.NotAfter, time.Time{}, time.Time{}, 0)
+	test.AssertNotError(t, err, "Couldn't add certificate status")
+	retries := func() int64 {
+		count, err := setupDBMap.SelectInt("SELECT COUNT(*) FROM expirationNagRetries WHERE serial = ?", serial1String)
+		test.AssertNotError(t, err, "counting nag retries")
+		return count
+	}
+
+	// The email is delivered but the webhook fails, so the certificate is
+	// marked as notified and only the webhook is retried.
+	testCtx.m.processCerts([]core.Certificate{cert})
+	test.AssertEquals(t, len(testCtx.mc.Messages), 1)
+	test.AssertEquals(t, hookCalls, 1)
+	status, err := sa.SelectCertificateStatus(setupDBMap, serial1String)
+	test.AssertNotError(t, err, "Couldn't get certificate status")
+	test.Assert(t, status.LastExpirationNagSent.After(time.Unix(0, 0)), "certificate wasn't marked as notified")
+	test.AssertEquals(t, retries(), int64(1))
+
+	// While the webhook keeps failing the retry is kept, and no more email is
+	// sent.
+	testCtx.mc.Clear()
+	testCtx.m.retryFailedNags()
+	test.AssertEquals(t, len(testCtx.mc.Messages), 0)
+	test.AssertEquals(t, hookCalls, 2)
+	test.AssertEquals(t, retries(), int64(1))
+
+	hookStatus = http.StatusOK
+	testCtx.m.retryFailedNags()
+	test.AssertEquals(t, len(testCtx.mc.Messages), 0)
+	test.AssertEquals(t, hookCalls, 3)
+	test.AssertEquals(t, retries(), int64(0))
+}
+
 type testCtx struct {
 	dbMap   *db.WrappedMap
 	ssa     core.StorageAdder
@@ -817,7 +1030,7 @@ func setup(t *testing.T, nagTimes []time.Duration) *testCtx {
 		t.Fatalf("Couldn't connect the database: %s", err)
 	}
 	fc := newFakeClock(t)
-	ssa, err := sa.NewSQLStorageAuthority(dbMap, fc, log, metrics.NoopRegisterer, 1)
+	ssa, err := sa.NewSQLStorageAuthority(dbMap, fc, log, metrics.NoopRegisterer, 1, 0, nil)
 	if err != nil {
 		t.Fatalf("unable to create SQLStorageAuthority: %s", err)
 	}
@@ -831,16 +1044,17 @@ func setup(t *testing.T, nagTimes []time.Duration) *testCtx {
 	}
 
 	m := &mailer{
-		log:             log,
-		mailer:          mc,
-		emailTemplate:   tmpl,
-		subjectTemplate: subjTmpl,
-		dbMap:           dbMap,
-		rs:              ssa,
-		nagTimes:        offsetNags,
-		limit:           100,
-		clk:             fc,
-		stats:           initStats(metrics.NoopRegisterer),
+		log:              log,
+		mailer:           mc,
+		emailTemplate:    tmpl,
+		subjectTemplate:  subjTmpl,
+		dbMap:            dbMap,
+		rs:               ssa,
+		nagTimes:         offsetNags,
+		nagCheckInterval: defaultNagCheckInterval,
+		limit:            100,
+		clk:              fc,
+		stats:            initStats(metrics.NoopRegisterer),
 	}
 	return &testCtx{
 		dbMap:   dbMap,
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+
+	"github.com/jmhodges/clock"
+
+	"github.com/letsencrypt/boulder/bdns"
+	"github.com/letsencrypt/boulder/core"
+	sapb "github.com/letsencrypt/boulder/sa/proto"
+	"github.com/letsencrypt/boulder/webhook"
+)
+
+// webhookCert describes a single certificate in a webhook notification.
+type webhookCert struct {
+	Serial   string    `json:"serial"`
+	DNSNames []string  `json:"dnsNames"`
+	NotAfter time.Time `json:"notAfter"`
+	Renewed  bool      `json:"renewed"`
+}
+
+// webhookNotification is the JSON body POSTed to an account's webhook URL.
+type webhookNotification struct {
+	RegistrationID int64         `json:"registrationID"`
+	Certificates   []webhookCert `json:"certificates"`
+}
+
+// newWebhookClient returns an HTTP client for requests to the webhook URLs
+// subscribers configure. Since it would otherwise let them make requests from
+// the mailer's network position, it doesn't follow redirects, use a proxy, or
+// connect to private or reserved addresses, which are checked once a URL's
+// host has been resolved.
+func newWebhookClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: publicAddressesOnly,
+	}
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: timeout,
+			MaxIdleConns:        100,
+			IdleConnTimeout:     90 * time.Second,
+		},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return errWebhookRedirect
+		},
+	}
+}
+
+// errWebhookRedirect is returned by a webhook request which was redirected.
+var errWebhookRedirect = errors.New("webhook redirects are not followed")
+
+// publicAddressesOnly is a net.Dialer Control function which refuses to
+// connect to private or reserved addresses. It's called with the resolved
+// address of each connection attempt.
+func publicAddressesOnly(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || bdns.IsPrivateIP(ip) {
+		return fmt.Errorf("webhook address %s is not public", host)
+	}
+	return nil
+}
+
+// webhookNotifier delivers expiration notices to the webhook URLs configured
+// in accounts' notification preferences.
+type webhookNotifier struct {
+	client *http.Client
+	clk    clock.Clock
+}
+
+// send POSTs a notification about the expiring certs, and any certs which
+// have already been renewed, to the webhook URL in prefs. Any response other
+// than a 2xx is treated as a failure.
+func (wn *webhookNotifier) send(ctx context.Context, prefs *sapb.NotificationPreferences, expiring, renewed []*x509.Certificate) error {
+	notification := webhookNotification{RegistrationID: prefs.RegistrationID}
+	for _, cert := range expiring {
+		notification.Certificates = append(notification.Certificates, newWebhookCert(cert, false))
+	}
+	for _, cert := range renewed {
+		notification.Certificates = append(notification.Certificates, newWebhookCert(cert, true))
+	}
+	body, err := json.Marshal(notification)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", prefs.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(webhook.SignatureHeader, webhook.Sign(prefs.WebhookSecret, wn.clk.Now(), body))
+
+	resp, err := wn.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	// Drain a bounded amount of the body so the connection can be reused.
+	_, _ = io.Copy(ioutil.Discard, io.LimitReader(resp.Body, 4096))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook for registration %d returned status %d", prefs.RegistrationID, resp.StatusCode)
+	}
+	return nil
+}
+
+func newWebhookCert(cert *x509.Certificate, renewed bool) webhookCert {
+	return webhookCert{
+		Serial:   core.SerialToString(cert.SerialNumber),
+		DNSNames: cert.DNSNames,
+		NotAfter: cert.NotAfter.UTC(),
+		Renewed:  renewed,
+	}
+}
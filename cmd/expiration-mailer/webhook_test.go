@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	sapb "github.com/letsencrypt/boulder/sa/proto"
+	"github.com/letsencrypt/boulder/test"
+	"github.com/letsencrypt/boulder/webhook"
+)
+
+func TestWebhookSend(t *testing.T) {
+	fc := newFakeClock(t)
+	secret := []byte("0123456789abcdef")
+
+	var gotSignature string
+	var gotBody []byte
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		test.AssertEquals(t, r.Method, "POST")
+		test.AssertEquals(t, r.Header.Get("Content-Type"), "application/json")
+		gotSignature = r.Header.Get(webhook.SignatureHeader)
+		gotBody, _ = ioutil.ReadAll(r.Body)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	wn := &webhookNotifier{client: server.Client(), clk: fc}
+	prefs := &sapb.NotificationPreferences{
+		RegistrationID: 1,
+		WebhookURL:     server.URL,
+		WebhookSecret:  secret,
+	}
+	expiring := newX509Cert("happy A", fc.Now().AddDate(0, 0, 2), []string{"example-a.com"}, serial1)
+	renewed := newX509Cert("happy B", fc.Now().AddDate(0, 0, 3), []string{"example-b.com"}, serial2)
+
+	err := wn.send(context.Background(), prefs, []*x509.Certificate{expiring}, []*x509.Certificate{renewed})
+	test.AssertNotError(t, err, "webhook send failed")
+	test.AssertEquals(t, gotSignature, webhook.Sign(secret, fc.Now(), gotBody))
+
+	var notification webhookNotification
+	err = json.Unmarshal(gotBody, &notification)
+	test.AssertNotError(t, err, "unmarshaling webhook body")
+	test.AssertEquals(t, notification.RegistrationID, int64(1))
+	test.AssertEquals(t, len(notification.Certificates), 2)
+	test.AssertEquals(t, notification.Certificates[0].Serial, serial1String)
+	test.AssertDeepEquals(t, notification.Certificates[0].DNSNames, []string{"example-a.com"})
+	test.Assert(t, notification.Certificates[0].NotAfter.Equal(expiring.NotAfter), "wrong notAfter")
+	test.Assert(t, !notification.Certificates[0].Renewed, "expiring cert marked renewed")
+	test.AssertEquals(t, notification.Certificates[1].Serial, serial2String)
+	test.Assert(t, notification.Certificates[1].Renewed, "renewed cert not marked renewed")
+
+	// A signature made with a different secret doesn't match.
+	test.Assert(t, gotSignature != webhook.Sign([]byte("fedcba9876543210"), fc.Now(), gotBody), "signature doesn't depend on secret")
+
+	status = http.StatusInternalServerError
+	err = wn.send(context.Background(), prefs, []*x509.Certificate{expiring}, nil)
+	test.AssertError(t, err, "webhook send didn't fail on a 500")
+}
+
+func TestWebhookClient(t *testing.T) {
+	fc := newFakeClock(t)
+	hits := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		http.Redirect(w, r, "https://169.254.169.254/latest/meta-data", http.StatusFound)
+	}))
+	defer server.Close()
+	prefs := &sapb.NotificationPreferences{
+		RegistrationID: 1,
+		WebhookURL:     server.URL,
+		WebhookSecret:  []byte("0123456789abcdef"),
+	}
+	expiring := newX509Cert("happy A", fc.Now().AddDate(0, 0, 2), []string{"example-a.com"}, serial1)
+
+	// The test server is on a loopback address, which isn't public.
+	wn := &webhookNotifier{client: newWebhookClient(time.Second), clk: fc}
+	err := wn.send(context.Background(), prefs, []*x509.Certificate{expiring}, nil)
+	test.AssertError(t, err, "webhook send connected to a loopback address")
+	test.AssertContains(t, err.Error(), "is not public")
+	test.AssertEquals(t, hits, 0)
+
+	// Redirects aren't followed.
+	client := server.Client()
+	client.CheckRedirect = newWebhookClient(time.Second).CheckRedirect
+	wn = &webhookNotifier{client: client, clk: fc}
+	err = wn.send(context.Background(), prefs, []*x509.Certificate{expiring}, nil)
+	test.AssertErrorIs(t, err, errWebhookRedirect)
+	test.AssertEquals(t, hits, 1)
+
+	for address, public := range map[string]bool{
+		"93.184.216.34:443":        true,
+		"[2606:2800:220:1::]:443":  true,
+		"127.0.0.1:443":            false,
+		"10.1.2.3:443":             false,
+		"169.254.169.254:443":      false,
+		"[::1]:443":                false,
+		"[fe80::1]:443":            false,
+		"[::ffff:192.168.1.1]:443": false,
+	} {
+		err := publicAddressesOnly("tcp", address, nil)
+		test.AssertEquals(t, err == nil, public)
+	}
+}
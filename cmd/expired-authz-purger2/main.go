@@ -11,6 +11,7 @@ import (
 	"github.com/letsencrypt/boulder/db"
 	"github.com/letsencrypt/boulder/features"
 	blog "github.com/letsencrypt/boulder/log"
+	"github.com/letsencrypt/boulder/metrics"
 	"github.com/letsencrypt/boulder/sa"
 	"github.com/prometheus/client_golang/prometheus"
 )
@@ -64,8 +65,8 @@ func main() {
 	cmd.FailOnError(err, "Failed to set feature flags")
 
 	var logger blog.Logger
+	var stats prometheus.Registerer = metrics.NoopRegisterer
 	if c.ExpiredAuthzPurger2.DebugAddr != "" {
-		var stats prometheus.Registerer
 		stats, logger = cmd.StatsAndLogging(c.ExpiredAuthzPurger2.Syslog, c.ExpiredAuthzPurger2.DebugAddr)
 		stats.MustRegister(deletedStat)
 	} else {
@@ -86,6 +87,8 @@ func main() {
 	}
 	dbMap, err := sa.NewDbMap(dbURL, dbSettings)
 	cmd.FailOnError(err, "Could not connect to database")
+	err = c.ExpiredAuthzPurger2.DBConfig.StartAdaptivePool(dbMap.Db, stats, logger)
+	cmd.FailOnError(err, "Invalid adaptive DB pool config")
 
 	for {
 		deleted, err := deleteExpired(clk, c.ExpiredAuthzPurger2.GracePeriod.Duration, c.ExpiredAuthzPurger2.BatchSize, dbMap)
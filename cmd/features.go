@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"context"
+	"time"
+
+	"github.com/jmhodges/clock"
+
+	"github.com/letsencrypt/boulder/features"
+	sapb "github.com/letsencrypt/boulder/sa/proto"
+)
+
+// defaultFeatureOverrideCacheTTL is how long each account's feature overrides
+// are cached for if a service's config doesn't say.
+const defaultFeatureOverrideCacheTTL = time.Minute
+
+// featureOverrideGetter is the part of the SA used to look up per-account
+// feature overrides.
+type featureOverrideGetter interface {
+	GetFeatureOverrides(context.Context, *sapb.RegistrationID) (*sapb.FeatureOverrides, error)
+}
+
+// SetFeatureOverrideLookup makes features.EnabledCtx apply the per-account
+// feature overrides stored in the SA, each of which is cached for ttl, or for
+// one minute if ttl is zero.
+func SetFeatureOverrideLookup(sa featureOverrideGetter, ttl time.Duration, clk clock.Clock) {
+	if ttl == 0 {
+		ttl = defaultFeatureOverrideCacheTTL
+	}
+	features.SetOverrideLookup(func(ctx context.Context, regID int64) (map[string]bool, error) {
+		resp, err := sa.GetFeatureOverrides(ctx, &sapb.RegistrationID{Id: regID})
+		if err != nil {
+			return nil, err
+		}
+		overrides := make(map[string]bool, len(resp.Overrides))
+		for _, override := range resp.Overrides {
+			overrides[override.Feature] = override.Enabled
+		}
+		return overrides, nil
+	}, ttl, clk)
+}
@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jmhodges/clock"
+
+	"github.com/letsencrypt/boulder/features"
+	sapb "github.com/letsencrypt/boulder/sa/proto"
+	"github.com/letsencrypt/boulder/test"
+)
+
+type fakeOverrideSA struct {
+	overrides map[int64][]*sapb.FeatureOverride
+}
+
+func (sa fakeOverrideSA) GetFeatureOverrides(_ context.Context, req *sapb.RegistrationID) (*sapb.FeatureOverrides, error) {
+	return &sapb.FeatureOverrides{Overrides: sa.overrides[req.Id]}, nil
+}
+
+func TestSetFeatureOverrideLookup(t *testing.T) {
+	defer features.Reset()
+	SetFeatureOverrideLookup(fakeOverrideSA{overrides: map[int64][]*sapb.FeatureOverride{
+		1: {{Feature: "EnforceMultiVA", Enabled: true}},
+	}}, 0, clock.NewFake())
+
+	ctx := context.Background()
+	test.Assert(t, features.EnabledCtx(features.WithAccountID(ctx, 1), features.EnforceMultiVA), "override wasn't applied")
+	test.Assert(t, !features.EnabledCtx(features.WithAccountID(ctx, 2), features.EnforceMultiVA), "override applied to the wrong account")
+}
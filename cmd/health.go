@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// HealthCheck checks one component a service depends on, such as its database
+// or a gRPC backend, returning an error describing the problem if the
+// component is unhealthy. It must return promptly once ctx is done.
+type HealthCheck func(ctx context.Context) error
+
+// healthCheckTimeout bounds how long a single health check may take before
+// it's reported as failed.
+const healthCheckTimeout = 5 * time.Second
+
+type healthChecks struct {
+	sync.RWMutex
+	checks map[string]HealthCheck
+}
+
+// livenessChecks are run by /healthz, and readinessChecks by /readyz.
+var (
+	livenessChecks  = &healthChecks{checks: make(map[string]HealthCheck)}
+	readinessChecks = &healthChecks{checks: make(map[string]HealthCheck)}
+)
+
+// RegisterLivenessCheck adds a check to the debug server's /healthz endpoint,
+// which reports whether the process is working at all and should otherwise be
+// restarted. A liveness check mustn't depend on anything outside the process,
+// since restarting the process won't fix that. With no liveness checks
+// registered, /healthz reports healthy as long as the debug server is up.
+func RegisterLivenessCheck(name string, check HealthCheck) {
+	livenessChecks.register(name, check)
+}
+
+// RegisterReadinessCheck adds a check to the debug server's /readyz endpoint,
+// which reports whether the service's dependencies are usable, and so whether
+// it should be sent traffic.
+func RegisterReadinessCheck(name string, check HealthCheck) {
+	readinessChecks.register(name, check)
+}
+
+func (hc *healthChecks) register(name string, check HealthCheck) {
+	hc.Lock()
+	defer hc.Unlock()
+	hc.checks[name] = check
+}
+
+type checkResult struct {
+	Status   string `json:"status"`
+	Error    string `json:"error,omitempty"`
+	Duration string `json:"duration"`
+}
+
+type healthResult struct {
+	Status string                 `json:"status"`
+	Checks map[string]checkResult `json:"checks"`
+}
+
+// run runs every check concurrently, each with healthCheckTimeout, and
+// reports the result of each, and whether all of them passed.
+func (hc *healthChecks) run(ctx context.Context) (healthResult, bool) {
+	hc.RLock()
+	names := make([]string, 0, len(hc.checks))
+	for name := range hc.checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	checks := make([]HealthCheck, len(names))
+	for i, name := range names {
+		checks[i] = hc.checks[name]
+	}
+	hc.RUnlock()
+
+	results := make([]checkResult, len(checks))
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func(i int, check HealthCheck) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+			defer cancel()
+			start := time.Now()
+			err := check(ctx)
+			results[i] = checkResult{Status: "ok", Duration: time.Since(start).String()}
+			if err != nil {
+				results[i].Status = "failed"
+				results[i].Error = err.Error()
+			}
+		}(i, check)
+	}
+	wg.Wait()
+
+	healthy := true
+	result := healthResult{Status: "ok", Checks: make(map[string]checkResult, len(names))}
+	for i, name := range names {
+		result.Checks[name] = results[i]
+		if results[i].Error != "" {
+			healthy = false
+			result.Status = "failed"
+		}
+	}
+	return result, healthy
+}
+
+// handler serves the result of running the checks as JSON, with a status of
+// 200 if they all passed and 503 if any failed.
+func (hc *healthChecks) handler(w http.ResponseWriter, r *http.Request) {
+	result, healthy := hc.run(r.Context())
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if healthy {
+		w.WriteHeader(http.StatusOK)
+	} else {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	_ = json.NewEncoder(w).Encode(result)
+}
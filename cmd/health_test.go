@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/letsencrypt/boulder/test"
+)
+
+func TestHealthChecks(t *testing.T) {
+	hc := &healthChecks{checks: make(map[string]HealthCheck)}
+	get := func() (int, healthResult) {
+		w := httptest.NewRecorder()
+		hc.handler(w, httptest.NewRequest("GET", "/readyz", nil))
+		var result healthResult
+		err := json.Unmarshal(w.Body.Bytes(), &result)
+		test.AssertNotError(t, err, "decoding health check result")
+		test.AssertEquals(t, w.Header().Get("Content-Type"), "application/json")
+		return w.Code, result
+	}
+
+	// With no checks registered, the service is healthy.
+	code, result := get()
+	test.AssertEquals(t, code, http.StatusOK)
+	test.AssertEquals(t, result.Status, "ok")
+	test.AssertEquals(t, len(result.Checks), 0)
+
+	hc.register("db", func(context.Context) error { return nil })
+	code, result = get()
+	test.AssertEquals(t, code, http.StatusOK)
+	test.AssertEquals(t, result.Checks["db"].Status, "ok")
+
+	// A single failing check makes the service unhealthy, and is reported
+	// alongside the passing ones.
+	hc.register("sa", func(context.Context) error { return errors.New("connection refused") })
+	code, result = get()
+	test.AssertEquals(t, code, http.StatusServiceUnavailable)
+	test.AssertEquals(t, result.Status, "failed")
+	test.AssertEquals(t, result.Checks["db"].Status, "ok")
+	test.AssertEquals(t, result.Checks["sa"].Status, "failed")
+	test.AssertEquals(t, result.Checks["sa"].Error, "connection refused")
+
+	// Checks are given a deadline.
+	hc.register("sa", func(ctx context.Context) error {
+		_, ok := ctx.Deadline()
+		if !ok {
+			return errors.New("no deadline")
+		}
+		return nil
+	})
+	code, result = get()
+	test.AssertEquals(t, code, http.StatusOK)
+	test.AssertEquals(t, result.Checks["sa"].Status, "ok")
+}
@@ -176,6 +176,9 @@ func main() {
 
 	dbURL, err := cfg.ContactExporter.DBConfig.URL()
 	cmd.FailOnError(err, "Couldn't load DB URL")
+	if cfg.ContactExporter.DBConfig.AdaptivePool != nil {
+		cmd.Fail("adaptivePool isn't supported, this tool uses a fixed size connection pool")
+	}
 	dbSettings := sa.DbSettings{
 		MaxOpenConns: 10,
 	}
@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path"
+	"runtime"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/letsencrypt/boulder/core"
+	"github.com/letsencrypt/boulder/features"
+)
+
+// loadedConfig is the path and SHA-256 hash of the config file most recently
+// loaded by ReadConfigFile, which for a service is its own config, or applied
+// by a ConfigReloader.
+var loadedConfig struct {
+	sync.RWMutex
+	file string
+	hash string
+}
+
+// recordConfig records the path and contents of a config file that's been
+// loaded. The hash is of the file as read, before any secrets are resolved, so
+// that it doesn't reveal them and doesn't change when they're rotated.
+func recordConfig(filename string, contents []byte) {
+	hash := sha256.Sum256(contents)
+	loadedConfig.Lock()
+	defer loadedConfig.Unlock()
+	loadedConfig.file = filename
+	loadedConfig.hash = hex.EncodeToString(hash[:])
+}
+
+// buildInfo describes the running binary, its enabled features and its config,
+// as served by the debug server's /info endpoint.
+type buildInfo struct {
+	Name       string             `json:"name"`
+	Revision   string             `json:"revision"`
+	BuildTime  string             `json:"buildTime"`
+	BuildHost  string             `json:"buildHost"`
+	GoVersion  string             `json:"goVersion"`
+	ConfigFile string             `json:"configFile,omitempty"`
+	ConfigHash string             `json:"configSHA256,omitempty"`
+	Features   map[string]float64 `json:"features"`
+}
+
+func currentBuildInfo() buildInfo {
+	loadedConfig.RLock()
+	defer loadedConfig.RUnlock()
+	return buildInfo{
+		Name:       path.Base(os.Args[0]),
+		Revision:   core.GetBuildID(),
+		BuildTime:  core.GetBuildTime(),
+		BuildHost:  core.GetBuildHost(),
+		GoVersion:  runtime.Version(),
+		ConfigFile: loadedConfig.file,
+		ConfigHash: loadedConfig.hash,
+		Features:   features.Percentages(),
+	}
+}
+
+// infoHandler serves the build info as JSON.
+func infoHandler(w http.ResponseWriter, r *http.Request) {
+	body, err := json.Marshal(currentBuildInfo())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(body)
+}
+
+var (
+	buildInfoDesc = prometheus.NewDesc(
+		"build_info",
+		"Always 1, labelled with the revision and Go version the binary was built with",
+		[]string{"revision", "build_time", "build_host", "go_version"}, nil)
+	configInfoDesc = prometheus.NewDesc(
+		"config_info",
+		"Always 1, labelled with the SHA-256 hash of the most recently loaded config file",
+		[]string{"sha256"}, nil)
+	featurePercentageDesc = prometheus.NewDesc(
+		"feature_enabled_percentage",
+		"Percentage of accounts or other keys each feature flag is enabled for",
+		[]string{"feature"}, nil)
+)
+
+// infoCollector exports the build info as metrics, so that config drift and
+// partially rolled out features can be seen across every instance of a
+// service. It reads the info when collected, so the metrics follow config
+// reloads.
+type infoCollector struct{}
+
+func (infoCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- buildInfoDesc
+	ch <- configInfoDesc
+	ch <- featurePercentageDesc
+}
+
+func (infoCollector) Collect(ch chan<- prometheus.Metric) {
+	info := currentBuildInfo()
+	ch <- prometheus.MustNewConstMetric(buildInfoDesc, prometheus.GaugeValue, 1,
+		info.Revision, info.BuildTime, info.BuildHost, info.GoVersion)
+	if info.ConfigHash != "" {
+		ch <- prometheus.MustNewConstMetric(configInfoDesc, prometheus.GaugeValue, 1, info.ConfigHash)
+	}
+	for name, percentage := range info.Features {
+		ch <- prometheus.MustNewConstMetric(featurePercentageDesc, prometheus.GaugeValue, percentage, name)
+	}
+}
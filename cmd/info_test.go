@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http/httptest"
+	"os"
+	"runtime"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/letsencrypt/boulder/features"
+	"github.com/letsencrypt/boulder/test"
+)
+
+func TestInfo(t *testing.T) {
+	defer features.Reset()
+	err := features.Set(map[string]bool{"StoreIssuerInfo": true})
+	test.AssertNotError(t, err, "setting features")
+	err = features.SetRollouts(map[string]float64{"EmailIdentifiers": 5})
+	test.AssertNotError(t, err, "setting feature rollouts")
+
+	f, err := ioutil.TempFile("", "info-config")
+	test.AssertNotError(t, err, "creating config file")
+	defer os.Remove(f.Name())
+	contents := []byte(`{"a": "b"}`)
+	_, err = f.Write(contents)
+	test.AssertNotError(t, err, "writing config file")
+	f.Close()
+	var config map[string]string
+	err = ReadConfigFile(f.Name(), &config)
+	test.AssertNotError(t, err, "reading config file")
+	hash := sha256.Sum256(contents)
+	expectedHash := hex.EncodeToString(hash[:])
+
+	// A config which fails to load isn't recorded.
+	bad, err := ioutil.TempFile("", "info-config")
+	test.AssertNotError(t, err, "creating config file")
+	defer os.Remove(bad.Name())
+	_, err = bad.Write([]byte(`{"a": `))
+	test.AssertNotError(t, err, "writing config file")
+	bad.Close()
+	err = ReadConfigFile(bad.Name(), &config)
+	test.AssertError(t, err, "reading malformed config file")
+
+	w := httptest.NewRecorder()
+	infoHandler(w, httptest.NewRequest("GET", "/info", nil))
+	test.AssertEquals(t, w.Header().Get("Content-Type"), "application/json")
+	var info buildInfo
+	err = json.Unmarshal(w.Body.Bytes(), &info)
+	test.AssertNotError(t, err, "decoding info")
+	test.AssertEquals(t, info.GoVersion, runtime.Version())
+	test.AssertEquals(t, info.ConfigFile, f.Name())
+	test.AssertEquals(t, info.ConfigHash, expectedHash)
+	test.AssertEquals(t, info.Features["StoreIssuerInfo"], float64(100))
+	test.AssertEquals(t, info.Features["EmailIdentifiers"], float64(5))
+	test.AssertEquals(t, info.Features["StoreRevokerInfo"], float64(0))
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(infoCollector{})
+	families, err := registry.Gather()
+	test.AssertNotError(t, err, "gathering metrics")
+	labels := make(map[string]map[string]string)
+	values := make(map[string]float64)
+	for _, family := range families {
+		for _, m := range family.GetMetric() {
+			l := make(map[string]string)
+			for _, pair := range m.GetLabel() {
+				l[pair.GetName()] = pair.GetValue()
+			}
+			if family.GetName() == "feature_enabled_percentage" {
+				values[l["feature"]] = m.GetGauge().GetValue()
+				continue
+			}
+			labels[family.GetName()] = l
+		}
+	}
+	test.AssertEquals(t, labels["build_info"]["go_version"], runtime.Version())
+	test.AssertEquals(t, labels["config_info"]["sha256"], expectedHash)
+	test.AssertEquals(t, values["StoreIssuerInfo"], float64(100))
+	test.AssertEquals(t, values["EmailIdentifiers"], float64(5))
+}
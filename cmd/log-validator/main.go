@@ -51,6 +51,19 @@ func lineValid(text string) error {
 	if strings.Contains(text, errorPrefix) {
 		return nil
 	}
+	// Services using the JSON log format log a JSON object in place of the
+	// checksum and message, which carries its own checksum.
+	if strings.HasPrefix(checksum, "{") {
+		var jsonLine blog.JSONLine
+		err := json.Unmarshal([]byte(strings.Join(fields[5:], " ")), &jsonLine)
+		if err != nil {
+			return fmt.Errorf("%sline doesn't contain a valid JSON object: %s", errorPrefix, err)
+		}
+		if computedChecksum := blog.JSONLineChecksum(jsonLine.Message, jsonLine.Fields); jsonLine.Checksum != computedChecksum {
+			return fmt.Errorf("%s invalid checksum (expected %q, got %q)", errorPrefix, computedChecksum, jsonLine.Checksum)
+		}
+		return nil
+	}
 	// Check the extracted checksum against the computed checksum
 	if computedChecksum := blog.LogLineChecksum(line); checksum != computedChecksum {
 		return fmt.Errorf("%s invalid checksum (expected %q, got %q)", errorPrefix, computedChecksum, checksum)
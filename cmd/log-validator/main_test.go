@@ -24,3 +24,15 @@ func TestLineValidNonOurobouros(t *testing.T) {
 	err2 := lineValid(selfOutput)
 	test.AssertNotError(t, err2, "expected no error when feeding lineValid's error output into itself")
 }
+
+func TestLineValidJSON(t *testing.T) {
+	prefix := "2020-07-06T18:07:43.109389+00:00 70877f679c72 datacenter 6 boulder-wfe[1595]: "
+	err := lineValid(prefix + `{"time":"2020-07-06T18:07:43Z","level":"info","component":"boulder-wfe","checksum":"kKG6cwA","msg":"Caught SIGTERM"}`)
+	test.AssertNotError(t, err, "errored on valid checksum")
+
+	err = lineValid(prefix + `{"time":"2020-07-06T18:07:43Z","level":"info","component":"boulder-wfe","checksum":"kKG6cwA","msg":"Caught SIGINT"}`)
+	test.AssertError(t, err, "didn't error on invalid checksum")
+
+	err = lineValid(prefix + `{"level":"info","checksum":"kKG6cwA","msg":"Caught`)
+	test.AssertError(t, err, "didn't error on truncated JSON")
+}
@@ -2,7 +2,14 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
 	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"google.golang.org/grpc/health"
 	healthpb "google.golang.org/grpc/health/grpc_health_v1"
@@ -10,8 +17,10 @@ import (
 	"github.com/letsencrypt/boulder/cmd"
 	corepb "github.com/letsencrypt/boulder/core/proto"
 	bgrpc "github.com/letsencrypt/boulder/grpc"
+	blog "github.com/letsencrypt/boulder/log"
 	"github.com/letsencrypt/boulder/nonce"
 	noncepb "github.com/letsencrypt/boulder/nonce/proto"
+	"github.com/letsencrypt/boulder/rocsp"
 )
 
 type config struct {
@@ -20,6 +29,51 @@ type config struct {
 		Syslog      cmd.SyslogConfig
 		MaxUsed     int
 		NoncePrefix string
+
+		// NoncePrefixKey, if set, is an HMAC key from which the nonce prefix is
+		// derived, replacing NoncePrefix. WFEs configured with the same key
+		// derive the same prefix from this instance's address, so routing
+		// doesn't need to be configured prefix by prefix. Sending the service
+		// SIGUSR1 re-reads the key and rotates to the newly derived prefix.
+		NoncePrefixKey *cmd.PasswordConfig
+		// PreviousNoncePrefixKey, if set, is the key being rotated away from.
+		// Prefixes derived from it for the peers in SharedStore.PeerAddresses
+		// are accepted too, so that nonces from peers which haven't rotated
+		// yet, or already have, can be redeemed during a rotation.
+		PreviousNoncePrefixKey *cmd.PasswordConfig
+		// NoncePrefixAddress is the address the prefix is derived from, which
+		// must match the serverAddress WFEs use to reach this instance.
+		// Defaults to the gRPC listen address.
+		NoncePrefixAddress string
+		// PrefixRotationWindow is how long nonces bearing the previous prefix
+		// are still accepted after a rotation. Defaults to 1h.
+		PrefixRotationWindow cmd.ConfigDuration
+
+		// SharedStore, if present, records generated nonces in Redis so that
+		// they can be redeemed by any nonce service using the same server.
+		// The WFE's RedeemNonceServices should then map every prefix in use
+		// to a reachable nonce service.
+		SharedStore *struct {
+			cmd.RedisConfig
+			// PeerPrefixes are the nonce prefixes of the other nonce services
+			// using the same server. Nonces bearing any prefix other than
+			// these or this service's own are rejected. It can't be used
+			// with NoncePrefixKey, whose derived prefixes change when the key
+			// is rotated; use PeerAddresses instead.
+			PeerPrefixes []string
+			// PeerAddresses are the NoncePrefixAddresses of the other nonce
+			// services using the same server, from which their prefixes are
+			// derived with NoncePrefixKey and PreviousNoncePrefixKey, and
+			// derived again when the key is rotated.
+			PeerAddresses []string
+			// NonceTTL is how long an unredeemed nonce is kept in the store.
+			// Defaults to 1h.
+			NonceTTL cmd.ConfigDuration
+			// LatencyBudget bounds each operation on the store, after which the
+			// nonce service falls back to validating nonces locally. Defaults
+			// to 100ms.
+			LatencyBudget cmd.ConfigDuration
+		}
 	}
 }
 
@@ -39,16 +93,105 @@ func (ns *nonceServer) Nonce(_ context.Context, _ *corepb.Empty) (*noncepb.Nonce
 	return &noncepb.NonceMessage{Nonce: nonce}, nil
 }
 
+// maxNonceBatch bounds the number of nonces sent in response to a single
+// NonceStream request.
+const maxNonceBatch = 1000
+
+func (ns *nonceServer) NonceStream(req *noncepb.NonceStreamRequest, stream noncepb.NonceService_NonceStreamServer) error {
+	if req.Count <= 0 || req.Count > maxNonceBatch {
+		return fmt.Errorf("nonce batch size must be between 1 and %d", maxNonceBatch)
+	}
+	for i := int64(0); i < req.Count; i++ {
+		nonce, err := ns.inner.Nonce()
+		if err != nil {
+			return err
+		}
+		err = stream.Send(&noncepb.NonceMessage{Nonce: nonce})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// derivePrefix reads the current prefix key and derives this instance's
+// nonce prefix from it.
+func derivePrefix(keyConfig *cmd.PasswordConfig, addr string) (string, error) {
+	key, err := keyConfig.Pass()
+	if err != nil {
+		return "", err
+	}
+	if key == "" {
+		return "", errors.New("nonce prefix key is empty")
+	}
+	return nonce.DerivePrefix([]byte(key), addr), nil
+}
+
+// derivePeerPrefixes reads the current and, if configured, previous prefix
+// keys and derives from each the nonce prefixes of the peers at addrs.
+func derivePeerPrefixes(keyConfig, previousKeyConfig *cmd.PasswordConfig, addrs []string) ([]string, error) {
+	var prefixes []string
+	for _, kc := range []*cmd.PasswordConfig{keyConfig, previousKeyConfig} {
+		if kc == nil {
+			continue
+		}
+		for _, addr := range addrs {
+			prefix, err := derivePrefix(kc, addr)
+			if err != nil {
+				return nil, err
+			}
+			prefixes = append(prefixes, prefix)
+		}
+	}
+	return prefixes, nil
+}
+
+// rotateOnSignal rotates the nonce prefix to one derived from the current
+// prefix key each time the process receives SIGUSR1, along with the prefixes
+// of the peers at peerAddrs. This allows the key to be replaced without a
+// restart, which would forget all outstanding nonces.
+func rotateOnSignal(ns *nonce.NonceService, keyConfig, previousKeyConfig *cmd.PasswordConfig, addr string, peerAddrs []string, window time.Duration, logger blog.Logger) {
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGUSR1)
+	for range sigChan {
+		prefix, err := derivePrefix(keyConfig, addr)
+		if err != nil {
+			logger.Errf("Failed to derive new nonce prefix: %s", err)
+			continue
+		}
+		err = ns.RotatePrefix(prefix, window)
+		if err != nil {
+			logger.Errf("Failed to rotate nonce prefix: %s", err)
+			continue
+		}
+		logger.AuditInfof("Rotated nonce prefix to %q, previous prefix accepted for %s", prefix, window)
+		if len(peerAddrs) == 0 {
+			continue
+		}
+		peerPrefixes, err := derivePeerPrefixes(keyConfig, previousKeyConfig, peerAddrs)
+		if err != nil {
+			logger.Errf("Failed to derive new peer nonce prefixes: %s", err)
+			continue
+		}
+		ns.RotatePeerPrefixes(peerPrefixes, window)
+		logger.AuditInfof("Rotated peer nonce prefixes to %q, previous prefixes accepted for %s", peerPrefixes, window)
+	}
+}
+
 func main() {
 	grpcAddr := flag.String("addr", "", "gRPC listen address override")
 	debugAddr := flag.String("debug-addr", "", "Debug server address override")
 	prefixOverride := flag.String("prefix", "", "Override the configured nonce prefix")
 	configFile := flag.String("config", "", "File path to the configuration file for this service")
+	checkConfig := cmd.CheckConfigFlag()
 	flag.Parse()
 
 	var c config
 	err := cmd.ReadConfigFile(*configFile, &c)
 	cmd.FailOnError(err, "Reading JSON config file into config structure")
+	if *checkConfig {
+		cmd.CheckConfig(&c)
+	}
 
 	if *grpcAddr != "" {
 		c.NonceService.GRPC.Address = *grpcAddr
@@ -58,15 +201,69 @@ func main() {
 	}
 	if *prefixOverride != "" {
 		c.NonceService.NoncePrefix = *prefixOverride
+		c.NonceService.NoncePrefixKey = nil
+		c.NonceService.PreviousNoncePrefixKey = nil
 	}
 
 	scope, logger := cmd.StatsAndLogging(c.NonceService.Syslog, c.NonceService.DebugAddr)
 	defer logger.AuditPanic()
 	logger.Info(cmd.VersionString())
 
+	prefixAddr := c.NonceService.NoncePrefixAddress
+	if prefixAddr == "" {
+		prefixAddr = c.NonceService.GRPC.Address
+	}
+	if c.NonceService.NoncePrefixKey != nil {
+		c.NonceService.NoncePrefix, err = derivePrefix(c.NonceService.NoncePrefixKey, prefixAddr)
+		cmd.FailOnError(err, "Failed to derive nonce prefix")
+		logger.Infof("Derived nonce prefix %q for %s", c.NonceService.NoncePrefix, prefixAddr)
+	}
+
 	ns, err := nonce.NewNonceService(scope, c.NonceService.MaxUsed, c.NonceService.NoncePrefix)
 	cmd.FailOnError(err, "Failed to initialize nonce service")
 
+	if c.NonceService.SharedStore != nil {
+		conf := c.NonceService.SharedStore
+		password, err := conf.Pass()
+		cmd.FailOnError(err, "Failed to load Redis password")
+		var redisTLS *tls.Config
+		if conf.TLS != nil {
+			redisTLS, err = conf.TLS.Load()
+			cmd.FailOnError(err, "Failed to load Redis TLS config")
+		}
+		if conf.NonceTTL.Duration == 0 {
+			conf.NonceTTL.Duration = time.Hour
+		}
+		if conf.LatencyBudget.Duration == 0 {
+			conf.LatencyBudget.Duration = 100 * time.Millisecond
+		}
+		peerPrefixes := conf.PeerPrefixes
+		if c.NonceService.NoncePrefixKey != nil {
+			if len(conf.PeerPrefixes) > 0 {
+				cmd.Fail("SharedStore.PeerPrefixes can't be used with NoncePrefixKey, use SharedStore.PeerAddresses")
+			}
+			peerPrefixes, err = derivePeerPrefixes(c.NonceService.NoncePrefixKey, c.NonceService.PreviousNoncePrefixKey, conf.PeerAddresses)
+			cmd.FailOnError(err, "Failed to derive peer nonce prefixes")
+		} else if len(conf.PeerAddresses) > 0 {
+			cmd.Fail("SharedStore.PeerAddresses requires NoncePrefixKey")
+		}
+		client := rocsp.NewClient(conf.Address, password, redisTLS, conf.Timeout.Duration, conf.PoolSize)
+		defer client.Close()
+		ns.UseSharedStore(client, conf.NonceTTL.Duration, conf.LatencyBudget.Duration, peerPrefixes)
+	}
+
+	if c.NonceService.NoncePrefixKey != nil {
+		window := c.NonceService.PrefixRotationWindow.Duration
+		if window == 0 {
+			window = time.Hour
+		}
+		var peerAddrs []string
+		if c.NonceService.SharedStore != nil {
+			peerAddrs = c.NonceService.SharedStore.PeerAddresses
+		}
+		go rotateOnSignal(ns, c.NonceService.NoncePrefixKey, c.NonceService.PreviousNoncePrefixKey, prefixAddr, peerAddrs, window, logger)
+	}
+
 	tlsConfig, err := c.NonceService.TLS.Load()
 	cmd.FailOnError(err, "tlsConfig config")
 
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/letsencrypt/boulder/cmd"
+	"github.com/letsencrypt/boulder/nonce"
+	"github.com/letsencrypt/boulder/test"
+)
+
+func TestDerivePeerPrefixes(t *testing.T) {
+	key := &cmd.PasswordConfig{Password: "current key"}
+	previousKey := &cmd.PasswordConfig{Password: "previous key"}
+	addrs := []string{"nonce2.boulder:9101", "nonce3.boulder:9101"}
+
+	prefixes, err := derivePeerPrefixes(key, nil, addrs)
+	test.AssertNotError(t, err, "derivePeerPrefixes failed")
+	test.AssertDeepEquals(t, prefixes, []string{
+		nonce.DerivePrefix([]byte("current key"), addrs[0]),
+		nonce.DerivePrefix([]byte("current key"), addrs[1]),
+	})
+
+	// During a rotation, the prefixes derived from both keys are accepted.
+	prefixes, err = derivePeerPrefixes(key, previousKey, addrs)
+	test.AssertNotError(t, err, "derivePeerPrefixes failed")
+	test.AssertDeepEquals(t, prefixes, []string{
+		nonce.DerivePrefix([]byte("current key"), addrs[0]),
+		nonce.DerivePrefix([]byte("current key"), addrs[1]),
+		nonce.DerivePrefix([]byte("previous key"), addrs[0]),
+		nonce.DerivePrefix([]byte("previous key"), addrs[1]),
+	})
+
+	_, err = derivePeerPrefixes(&cmd.PasswordConfig{}, nil, addrs)
+	test.AssertError(t, err, "derivePeerPrefixes accepted an empty key")
+}
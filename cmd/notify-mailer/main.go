@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"math/rand"
 	"net/mail"
 	"os"
 	"sort"
@@ -38,6 +39,106 @@ type mailer struct {
 	destinations  []recipient
 	targetRange   interval
 	sleepInterval time.Duration
+	// jitter is the maximum random delay added to sleepInterval after each
+	// message, so that sending doesn't proceed in lockstep with the MTA's
+	// rate limiting windows.
+	jitter time.Duration
+	// checkpointFile, if set, is where progress is recorded after each
+	// address is processed, so an interrupted run can be resumed.
+	checkpointFile string
+	// progress is the state recorded in checkpointFile. When resuming, it's
+	// loaded from checkpointFile before the run starts, and addresses up to
+	// and including progress.LastAddress are skipped.
+	progress checkpoint
+	// report, if set, receives a CSV line for each address processed.
+	report *csv.Writer
+	// contactCipher, if not nil, decrypts registrations' contacts.
+	contactCipher *sa.ContactCipher
+}
+
+// runSummary counts the outcome of processing each address.
+type runSummary struct {
+	Sent    int
+	Skipped int
+	Failed  int
+}
+
+// checkpoint records the progress of a run. Addresses are processed in
+// sorted order, so everything up to and including LastAddress has been
+// processed.
+type checkpoint struct {
+	// Subject and RecipientList identify the campaign, so that a checkpoint
+	// can't accidentally be used to resume a different one.
+	Subject       string
+	RecipientList string
+	LastAddress   string
+	Summary       runSummary
+}
+
+// loadCheckpoint reads a checkpoint written by a previous run.
+func loadCheckpoint(filename string) (checkpoint, error) {
+	var cp checkpoint
+	contents, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return cp, err
+	}
+	err = json.Unmarshal(contents, &cp)
+	if err != nil {
+		return cp, fmt.Errorf("parsing checkpoint %q: %s", filename, err)
+	}
+	return cp, nil
+}
+
+// writeCheckpoint atomically replaces the checkpoint file with the current
+// progress.
+func (m *mailer) writeCheckpoint() error {
+	if m.checkpointFile == "" {
+		return nil
+	}
+	contents, err := json.Marshal(m.progress)
+	if err != nil {
+		return err
+	}
+	tmp := m.checkpointFile + ".tmp"
+	err = ioutil.WriteFile(tmp, contents, 0600)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp, m.checkpointFile)
+}
+
+// recordResult updates the run's progress after processing an address,
+// writing the checkpoint and report.
+func (m *mailer) recordResult(address, result, reason string) error {
+	switch result {
+	case "sent":
+		m.progress.Summary.Sent++
+	case "skipped":
+		m.progress.Summary.Skipped++
+	case "failed":
+		m.progress.Summary.Failed++
+	}
+	m.progress.LastAddress = address
+	if m.report != nil {
+		err := m.report.Write([]string{address, result, reason})
+		if err != nil {
+			return err
+		}
+		m.report.Flush()
+		if err := m.report.Error(); err != nil {
+			return err
+		}
+	}
+	return m.writeCheckpoint()
+}
+
+// pause waits between messages for the sleep interval plus a random jitter.
+func (m *mailer) pause() {
+	delay := m.sleepInterval
+	if m.jitter > 0 {
+		delay += time.Duration(rand.Int63n(int64(m.jitter)))
+	}
+	m.clk.Sleep(delay)
 }
 
 // interval defines a range of email addresses to send to, alphabetically.
@@ -79,6 +180,10 @@ func (m *mailer) ok() error {
 			"sleep interval (%d) is < 0", m.sleepInterval)
 	}
 
+	if m.jitter < 0 {
+		return fmt.Errorf("jitter (%d) is < 0", m.jitter)
+	}
+
 	return nil
 }
 
@@ -141,14 +246,30 @@ func (m *mailer) run() error {
 	sortedAddresses := sortAddresses(addressesToRecipients)
 	numAddresses := len(addressesToRecipients)
 
-	var sent int
+	if m.progress.LastAddress != "" {
+		m.log.Infof("Resuming after %q. Previously sent %d, skipped %d, failed %d.",
+			m.progress.LastAddress, m.progress.Summary.Sent, m.progress.Summary.Skipped, m.progress.Summary.Failed)
+	}
+	defer func() {
+		m.log.AuditInfof("Summary: sent %d, skipped %d, failed %d. Last address processed: %q",
+			m.progress.Summary.Sent, m.progress.Summary.Skipped, m.progress.Summary.Failed, m.progress.LastAddress)
+	}()
+
 	for i, address := range sortedAddresses {
 		if !m.targetRange.includes(address) {
-			m.log.Debugf("skipping %q: out of target range")
+			m.log.Debugf("skipping %q: out of target range", address)
+			continue
+		}
+		if m.progress.LastAddress != "" && address <= m.progress.LastAddress {
+			// Already processed by the run being resumed.
 			continue
 		}
 		if err := policy.ValidEmail(address); err != nil {
 			m.log.Infof("skipping %q: %s", address, err)
+			err = m.recordResult(address, "skipped", err.Error())
+			if err != nil {
+				return err
+			}
 			continue
 		}
 		recipients := addressesToRecipients[address]
@@ -166,15 +287,22 @@ func (m *mailer) run() error {
 			var recoverableSMTPErr bmail.RecoverableSMTPError
 			if errors.As(err, &recoverableSMTPErr) {
 				m.log.Errf("address %q was rejected by server: %s", address, err)
+				err = m.recordResult(address, "failed", err.Error())
+				if err != nil {
+					return err
+				}
 				continue
 			}
 			return fmt.Errorf("sending mail %d of %d to %q: %s",
 				i, len(sortedAddresses), address, err)
 		}
-		sent++
-		m.clk.Sleep(m.sleepInterval)
+		err = m.recordResult(address, "sent", "")
+		if err != nil {
+			return err
+		}
+		m.pause()
 	}
-	if sent == 0 {
+	if m.progress.Summary.Sent == 0 {
 		return fmt.Errorf("sent zero messages. Check recipients and configured interval")
 	}
 	return nil
@@ -188,7 +316,7 @@ func (m *mailer) resolveEmailAddresses() (emailToRecipientMap, error) {
 
 	for _, r := range m.destinations {
 		// Get the email address for the reg ID
-		emails, err := emailsForReg(r.id, m.dbMap)
+		emails, err := emailsForReg(r.id, m.dbMap, m.contactCipher)
 		if err != nil {
 			return nil, err
 		}
@@ -214,8 +342,9 @@ type dbSelector interface {
 	SelectOne(holder interface{}, query string, args ...interface{}) error
 }
 
-// Finds the email addresses associated with a reg ID
-func emailsForReg(id int, dbMap dbSelector) ([]string, error) {
+// Finds the email addresses associated with a reg ID, decrypting its contacts
+// with cc if they're encrypted.
+func emailsForReg(id int, dbMap dbSelector, cc *sa.ContactCipher) ([]string, error) {
 	var contact contactJSON
 	err := dbMap.SelectOne(&contact,
 		`SELECT id, contact
@@ -237,6 +366,10 @@ func emailsForReg(id int, dbMap dbSelector) ([]string, error) {
 	if err != nil {
 		return nil, err
 	}
+	contactFields, err = sa.DecryptContacts(cc, contactFields)
+	if err != nil {
+		return nil, fmt.Errorf("decrypting contacts of registration %d: %s", id, err)
+	}
 	for _, entry := range contactFields {
 		if strings.HasPrefix(entry, "mailto:") {
 			addresses = append(addresses, strings.TrimPrefix(entry, "mailto:"))
@@ -354,11 +487,23 @@ Notify-mailer de-duplicates email addresses and groups together the resulting re
 structs, so a person who has multiple accounts using the same address will only receive
 one email.
 
+A run can be made resumable with the -checkpointFile argument. After each address
+is processed the file is updated with the last address processed and the number of
+messages sent, skipped, and failed so far. If the run is interrupted, running the
+same command again with -resume=true skips every address up to and including the
+last one processed. A run can only be resumed with the same -subject and
+-recipientList as the run that wrote the checkpoint. The -report argument names a
+file to which a CSV line of address, result (sent, skipped, or failed), and reason
+is appended for each address processed. A summary of the results is logged when
+the run finishes.
+
 During mailing the -sleep argument is used to space out individual messages.
 This can be used to ensure that the mailing happens at a steady pace with ample
 opportunity for the operator to terminate early in the event of error. The
 -sleep flag honours durations with a unit suffix (e.g. 1m for 1 minute, 10s for
 10 seconds, etc). Using -sleep=0 will disable the sleep and send at full speed.
+Alternatively, -messagesPerSecond sets the sleep to match a target sending rate.
+The -jitter argument adds a random delay of up to the given duration to each sleep.
 
 Examples:
   Send an email with subject "Hello!" from the email "hello@goodbye.com" with
@@ -404,12 +549,20 @@ func main() {
 	end := flag.String("end", "\xFF", "Alphabetically highest email address (exclusive).")
 	reconnBase := flag.Duration("reconnectBase", 1*time.Second, "Base sleep duration between reconnect attempts")
 	reconnMax := flag.Duration("reconnectMax", 5*60*time.Second, "Max sleep duration between reconnect attempts after exponential backoff")
+	messagesPerSecond := flag.Float64("messagesPerSecond", 0, "If set, overrides -sleep to send at most this many messages per second.")
+	jitter := flag.Duration("jitter", 0, "Maximum random delay added to the sleep between emails.")
+	checkpointFile := flag.String("checkpointFile", "", "File in which to record progress, so an interrupted run can be resumed.")
+	resume := flag.Bool("resume", false, "Whether to resume from the progress recorded in -checkpointFile.")
+	reportFile := flag.String("report", "", "File to which a CSV line is appended for each address processed.")
 	type config struct {
 		NotifyMailer struct {
 			cmd.DBConfig
 			cmd.PasswordConfig
 			cmd.SMTPConfig
 			Features map[string]bool
+			// ContactEncryption must be set, with the SA's keys, if the SA
+			// encrypts registration contacts.
+			ContactEncryption *cmd.ContactEncryptionConfig
 		}
 		Syslog cmd.SyslogConfig
 	}
@@ -441,12 +594,23 @@ func main() {
 
 	dbURL, err := cfg.NotifyMailer.DBConfig.URL()
 	cmd.FailOnError(err, "Couldn't load DB URL")
+	if cfg.NotifyMailer.DBConfig.AdaptivePool != nil {
+		cmd.Fail("adaptivePool isn't supported, this tool uses a fixed size connection pool")
+	}
 	dbSettings := sa.DbSettings{
 		MaxOpenConns: 10,
 	}
 	dbMap, err := sa.NewDbMap(dbURL, dbSettings)
 	cmd.FailOnError(err, "Could not connect to database")
 
+	var contactCipher *sa.ContactCipher
+	if cfg.NotifyMailer.ContactEncryption != nil {
+		keys, err := cfg.NotifyMailer.ContactEncryption.Keys()
+		cmd.FailOnError(err, "Failed to load contact encryption keys")
+		contactCipher, err = sa.NewContactCipher(keys, cfg.NotifyMailer.ContactEncryption.ActiveKeyID)
+		cmd.FailOnError(err, "Failed to create contact cipher")
+	}
+
 	// Load email body
 	body, err := ioutil.ReadFile(*bodyFile)
 	cmd.FailOnError(err, fmt.Sprintf("Reading %q", *bodyFile))
@@ -464,6 +628,42 @@ func main() {
 		end:   *end,
 	}
 
+	sleepInterval := *sleep
+	if *messagesPerSecond < 0 {
+		cmd.Fail("-messagesPerSecond must not be negative")
+	}
+	if *messagesPerSecond > 0 {
+		sleepInterval = time.Duration(float64(time.Second) / *messagesPerSecond)
+	}
+
+	progress := checkpoint{
+		Subject:       *subject,
+		RecipientList: *recipientListFile,
+	}
+	if *resume {
+		if *checkpointFile == "" {
+			cmd.Fail("-resume requires -checkpointFile")
+		}
+		progress, err = loadCheckpoint(*checkpointFile)
+		cmd.FailOnError(err, "Loading checkpoint")
+		if progress.Subject != *subject || progress.RecipientList != *recipientListFile {
+			cmd.Fail(fmt.Sprintf("Checkpoint %q is for subject %q and recipient list %q",
+				*checkpointFile, progress.Subject, progress.RecipientList))
+		}
+	}
+
+	var report *csv.Writer
+	if *reportFile != "" {
+		flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+		if *resume {
+			flags = os.O_WRONLY | os.O_CREATE | os.O_APPEND
+		}
+		f, err := os.OpenFile(*reportFile, flags, 0600)
+		cmd.FailOnError(err, fmt.Sprintf("Opening %q", *reportFile))
+		defer f.Close()
+		report = csv.NewWriter(f)
+	}
+
 	var mailClient bmail.Mailer
 	if *dryRun {
 		log.Infof("Doing a dry run.")
@@ -471,7 +671,7 @@ func main() {
 	} else {
 		smtpPassword, err := cfg.NotifyMailer.PasswordConfig.Pass()
 		cmd.FailOnError(err, "Failed to load SMTP password")
-		mailClient = bmail.New(
+		smtpClient := bmail.New(
 			cfg.NotifyMailer.Server,
 			cfg.NotifyMailer.Port,
 			cfg.NotifyMailer.Username,
@@ -482,18 +682,31 @@ func main() {
 			metrics.NoopRegisterer,
 			*reconnBase,
 			*reconnMax)
+		err = smtpClient.SetPoolConfig(cfg.NotifyMailer.Pool.PoolConfig())
+		cmd.FailOnError(err, "Invalid SMTP pool config")
+		if cfg.NotifyMailer.DKIM != nil {
+			dkimSigner, err := cfg.NotifyMailer.DKIM.Load(cmd.Clock())
+			cmd.FailOnError(err, "Failed to load DKIM keys")
+			smtpClient.SetDKIMSigner(dkimSigner)
+		}
+		mailClient = smtpClient
 	}
 
 	m := mailer{
-		clk:           cmd.Clock(),
-		log:           log,
-		dbMap:         dbMap,
-		mailer:        mailClient,
-		subject:       *subject,
-		destinations:  recipients,
-		emailTemplate: template,
-		targetRange:   targetRange,
-		sleepInterval: *sleep,
+		clk:            cmd.Clock(),
+		log:            log,
+		dbMap:          dbMap,
+		contactCipher:  contactCipher,
+		mailer:         mailClient,
+		subject:        *subject,
+		destinations:   recipients,
+		emailTemplate:  template,
+		targetRange:    targetRange,
+		sleepInterval:  sleepInterval,
+		jitter:         *jitter,
+		checkpointFile: *checkpointFile,
+		progress:       progress,
+		report:         report,
 	}
 
 	err = m.run()
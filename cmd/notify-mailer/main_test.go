@@ -1,10 +1,15 @@
 package main
 
 import (
+	"bytes"
 	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 	"testing"
 	"text/template"
 	"time"
@@ -13,7 +18,9 @@ import (
 
 	"github.com/letsencrypt/boulder/db"
 	blog "github.com/letsencrypt/boulder/log"
+	bmail "github.com/letsencrypt/boulder/mail"
 	"github.com/letsencrypt/boulder/mocks"
+	"github.com/letsencrypt/boulder/sa"
 	"github.com/letsencrypt/boulder/test"
 )
 
@@ -231,6 +238,101 @@ func TestMailIntervals(t *testing.T) {
 	}, mc.Messages[1])
 }
 
+// flakyMailer is a mock mailer which rejects messages to some addresses, and
+// fails entirely when sending to others.
+type flakyMailer struct {
+	mocks.Mailer
+	reject map[string]bool
+	fail   map[string]bool
+}
+
+func (fm *flakyMailer) SendMail(to []string, subject, msg string) error {
+	if fm.reject[to[0]] {
+		return bmail.RecoverableSMTPError{Message: "550: mailbox unavailable"}
+	}
+	if fm.fail[to[0]] {
+		return errors.New("connection reset")
+	}
+	return fm.Mailer.SendMail(to, subject, msg)
+}
+
+func TestResume(t *testing.T) {
+	dir, err := ioutil.TempDir("", "notify-mailer")
+	test.AssertNotError(t, err, "creating temp dir")
+	defer os.RemoveAll(dir)
+	checkpointFile := filepath.Join(dir, "checkpoint.json")
+
+	var reportBuf bytes.Buffer
+	fm := &flakyMailer{
+		reject: map[string]bool{"example@letsencrypt.org": true},
+		fail:   map[string]bool{"test-example-updated@letsencrypt.org": true},
+	}
+	m := &mailer{
+		log:            blog.UseMock(),
+		mailer:         fm,
+		dbMap:          mockEmailResolver{},
+		subject:        "Test Subject",
+		destinations:   []recipient{{id: 1}, {id: 2}, {id: 3}, {id: 4}},
+		emailTemplate:  template.Must(template.New("letter").Parse("an email body")),
+		targetRange:    interval{end: "\xFF"},
+		clk:            newFakeClock(t),
+		checkpointFile: checkpointFile,
+		progress:       checkpoint{Subject: "Test Subject", RecipientList: "recipients.csv"},
+		report:         csv.NewWriter(&reportBuf),
+	}
+
+	// The run stops at the first unrecoverable failure, having sent one
+	// message and had another rejected.
+	err = m.run()
+	test.AssertError(t, err, "run() didn't fail")
+	test.AssertEquals(t, len(fm.Messages), 1)
+	test.AssertEquals(t, fm.Messages[0].To, "example-example-example@letsencrypt.org")
+
+	cp, err := loadCheckpoint(checkpointFile)
+	test.AssertNotError(t, err, "loading checkpoint")
+	test.AssertEquals(t, cp.Subject, "Test Subject")
+	test.AssertEquals(t, cp.LastAddress, "example@letsencrypt.org")
+	test.AssertEquals(t, cp.Summary, runSummary{Sent: 1, Failed: 1})
+	test.AssertEquals(t, reportBuf.String(),
+		"example-example-example@letsencrypt.org,sent,\n"+
+			"example@letsencrypt.org,failed,550: mailbox unavailable\n")
+
+	// Resuming from the checkpoint sends only to the remaining addresses.
+	fm.fail = nil
+	fm.Clear()
+	m.progress = cp
+	err = m.run()
+	test.AssertNotError(t, err, "resumed run() failed")
+	test.AssertEquals(t, len(fm.Messages), 2)
+	test.AssertEquals(t, fm.Messages[0].To, "test-example-updated@letsencrypt.org")
+	test.AssertEquals(t, fm.Messages[1].To, "test-test-test@letsencrypt.org")
+
+	cp, err = loadCheckpoint(checkpointFile)
+	test.AssertNotError(t, err, "loading checkpoint")
+	test.AssertEquals(t, cp.LastAddress, "test-test-test@letsencrypt.org")
+	test.AssertEquals(t, cp.Summary, runSummary{Sent: 3, Failed: 1})
+}
+
+func TestJitter(t *testing.T) {
+	fc := newFakeClock(t)
+	start := fc.Now()
+	m := &mailer{
+		clk:           fc,
+		sleepInterval: time.Second,
+		jitter:        time.Second,
+	}
+	for i := 0; i < 10; i++ {
+		m.pause()
+	}
+	elapsed := fc.Now().Sub(start)
+	test.Assert(t, elapsed >= 10*time.Second && elapsed < 20*time.Second,
+		fmt.Sprintf("unexpected elapsed time %s", elapsed))
+
+	m.jitter = -time.Second
+	m.targetRange = interval{end: "\xFF"}
+	test.AssertError(t, m.ok(), "negative jitter was accepted")
+}
+
 func TestMessageContentStatic(t *testing.T) {
 	// Create a mailer with fixed content
 	const (
@@ -533,6 +635,34 @@ func TestResolveEmails(t *testing.T) {
 	}
 }
 
+// contactResolver is a dbSelector holding one registration's contact column.
+type contactResolver struct {
+	contact []byte
+}
+
+func (cr contactResolver) SelectOne(output interface{}, _ string, _ ...interface{}) error {
+	*output.(*contactJSON) = contactJSON{ID: 1, Contact: cr.contact}
+	return nil
+}
+
+func TestEmailsForRegEncrypted(t *testing.T) {
+	cipher, err := sa.NewContactCipher(map[string][]byte{"k1": make([]byte, 32)}, "k1")
+	test.AssertNotError(t, err, "creating contact cipher")
+	encrypted, err := cipher.Encrypt([]string{"mailto:example@letsencrypt.org", "tel:123"})
+	test.AssertNotError(t, err, "encrypting contacts")
+	contact, err := json.Marshal(encrypted)
+	test.AssertNotError(t, err, "marshalling contacts")
+
+	addresses, err := emailsForReg(1, contactResolver{contact}, cipher)
+	test.AssertNotError(t, err, "getting emails for registration")
+	test.AssertDeepEquals(t, addresses, []string{"example@letsencrypt.org"})
+
+	// Without the cipher, encrypted contacts are an error rather than being
+	// silently dropped.
+	_, err = emailsForReg(1, contactResolver{contact}, nil)
+	test.AssertError(t, err, "got emails for encrypted registration without the contact cipher")
+}
+
 func newFakeClock(t *testing.T) clock.FakeClock {
 	const fakeTimeFormat = "2006-01-02T15:04:05.999999999Z"
 	ft, err := time.Parse(fakeTimeFormat, fakeTimeFormat)
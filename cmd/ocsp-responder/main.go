@@ -136,7 +136,7 @@ func (f *ocspFilter) responseMatchesIssuer(req *ocsp.Request, status core.Certif
 // since we will always query on it.
 type dbSource struct {
 	dbMap   dbSelector
-	filter  *ocspFilter
+	filter  requestFilter
 	timeout time.Duration
 	log     blog.Logger
 }
@@ -221,6 +221,42 @@ type config struct {
 
 		RequiredSerialPrefixes []string
 
+		// FilterConfigFile, if set, names a YAML file containing the
+		// issuerCerts and requiredSerialPrefixes to use instead of the
+		// IssuerCerts and RequiredSerialPrefixes fields above. The file is
+		// watched and reloaded when it changes, so issuers can be added
+		// without a restart. A reload which removes an issuer that still has
+		// unexpired certificates is rejected.
+		FilterConfigFile string
+
+		// Redis, if present, causes OCSP responses to be looked up in Redis
+		// rather than the database. On a miss, or if the stored response is
+		// older than MaxResponseAge, the database is used to look up the
+		// certificate's status and a fresh response is requested from the
+		// OCSPGeneratorService, which is then written back to Redis.
+		Redis                *cmd.RedisConfig
+		OCSPGeneratorService *cmd.GRPCClientConfig
+		MaxResponseAge       cmd.ConfigDuration
+
+		// ObjectStorage, if present, causes OCSP responses to be served from
+		// an S3 or GCS bucket of pre-generated responses, each named by the
+		// hex serial of its certificate, instead of from the database. Neither
+		// the database nor Redis is used in this mode, so it can keep OCSP
+		// available when they are down. The filter is built from IssuerCerts
+		// and RequiredSerialPrefixes; FilterConfigFile is not supported.
+		ObjectStorage *cmd.ObjectStorageConfig
+		// ObjectStorageRefreshInterval is how long a response fetched from
+		// object storage is cached before being revalidated with a
+		// conditional GET. Defaults to 5 minutes.
+		ObjectStorageRefreshInterval cmd.ConfigDuration
+		// ObjectStorageMaxStaleness is how old (measured from its thisUpdate)
+		// a response from object storage can be and still be served. If zero,
+		// responses are served until their nextUpdate.
+		ObjectStorageMaxStaleness cmd.ConfigDuration
+		// ObjectStorageCacheSize is the maximum number of responses cached in
+		// memory. Defaults to 100000.
+		ObjectStorageCacheSize int
+
 		Features map[string]bool
 	}
 
@@ -234,6 +270,7 @@ type config struct {
 
 func main() {
 	configFile := flag.String("config", "", "File path to the configuration file for this service")
+	checkConfig := cmd.CheckConfigFlag()
 	flag.Parse()
 	if *configFile == "" {
 		fmt.Fprintf(os.Stderr, `Usage of %s:
@@ -248,6 +285,9 @@ as generated by Boulder's ceremony command.
 	var c config
 	err := cmd.ReadConfigFile(*configFile, &c)
 	cmd.FailOnError(err, "Reading JSON config file into config structure")
+	if *checkConfig {
+		cmd.CheckConfig(&c)
+	}
 	err = features.Set(c.OCSPResponder.Features)
 	cmd.FailOnError(err, "Failed to set feature flags")
 
@@ -269,6 +309,23 @@ as generated by Boulder's ceremony command.
 		}
 		source, err = bocsp.NewMemorySourceFromFile(filename, logger)
 		cmd.FailOnError(err, fmt.Sprintf("Couldn't read file: %s", url.Path))
+	} else if config.ObjectStorage != nil {
+		issuerCerts := config.IssuerCerts
+		if len(issuerCerts) == 0 {
+			issuerCerts = []string{c.Common.IssuerCert}
+		}
+		filter, err := newFilter(issuerCerts, config.RequiredSerialPrefixes)
+		cmd.FailOnError(err, "Couldn't create OCSP filter")
+
+		refreshInterval := config.ObjectStorageRefreshInterval.Duration
+		if refreshInterval == 0 {
+			refreshInterval = 5 * time.Minute
+		}
+		cacheSize := config.ObjectStorageCacheSize
+		if cacheSize == 0 {
+			cacheSize = 100000
+		}
+		source = newObjectSourceFromConfig(config.ObjectStorage, filter, refreshInterval, config.ObjectStorageMaxStaleness.Duration, cacheSize, stats, logger)
 	} else {
 		// For databases, DBConfig takes precedence over Source, if present.
 		dbConnect, err := config.DBConfig.URL()
@@ -287,17 +344,28 @@ as generated by Boulder's ceremony command.
 		cmd.FailOnError(err, "Could not connect to database")
 		sa.SetSQLDebug(dbMap, logger)
 		sa.InitDBMetrics(dbMap, stats, dbSettings)
+		err = config.DBConfig.StartAdaptivePool(dbMap.Db, stats, logger)
+		cmd.FailOnError(err, "Invalid adaptive DB pool config")
 
 		issuerCerts := c.OCSPResponder.IssuerCerts
 		if len(issuerCerts) == 0 {
 			issuerCerts = []string{c.Common.IssuerCert}
 		}
 
-		filter, err := newFilter(issuerCerts, c.OCSPResponder.RequiredSerialPrefixes)
+		var filter requestFilter
+		if config.FilterConfigFile != "" {
+			filter, err = newReloadingFilter(config.FilterConfigFile, dbMap, cmd.Clock(), config.Timeout.Duration, logger)
+		} else {
+			filter, err = newFilter(issuerCerts, c.OCSPResponder.RequiredSerialPrefixes)
+		}
 		cmd.FailOnError(err, "Couldn't create OCSP filter")
 
 		source = &dbSource{dbMap, filter, c.OCSPResponder.Timeout.Duration, logger}
 
+		if config.Redis != nil {
+			source = newRedisSourceFromConfig(config.Redis, config.OCSPGeneratorService, config.TLS, config.MaxResponseAge.Duration, dbMap, filter, config.Timeout.Duration, stats, logger)
+		}
+
 		// Export the value for dbSettings.MaxOpenConns
 		dbConnStat := prometheus.NewGauge(prometheus.GaugeOpts{
 			Name: "max_db_connections",
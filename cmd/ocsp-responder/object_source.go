@@ -0,0 +1,265 @@
+package main
+
+import (
+	"container/list"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/jmhodges/clock"
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/crypto/ocsp"
+
+	"github.com/letsencrypt/boulder/cmd"
+	"github.com/letsencrypt/boulder/core"
+	blog "github.com/letsencrypt/boulder/log"
+	"github.com/letsencrypt/boulder/objectstore"
+	bocsp "github.com/letsencrypt/boulder/ocsp"
+)
+
+// maxObjectSize bounds the size of an OCSP response fetched from object
+// storage. Real responses are a few kilobytes at most.
+const maxObjectSize = 64 * 1024
+
+// cachedObject is an OCSP response fetched from object storage.
+type cachedObject struct {
+	serial     string
+	der        []byte
+	etag       string
+	thisUpdate time.Time
+	nextUpdate time.Time
+	// fetched is when the object was last fetched or revalidated.
+	fetched time.Time
+}
+
+// objectSource serves pre-generated OCSP responses from an object storage
+// bucket, in which each response is stored as an object named by the hex
+// serial of the certificate. It doesn't depend on the database or Redis, so
+// it can be used to keep serving OCSP when they are unavailable.
+//
+// Fetched responses are cached in memory. A cached response is served without
+// contacting the bucket for refreshInterval, after which it is revalidated
+// with a conditional GET. If the bucket can't be reached, cached responses
+// continue to be served for as long as they remain within the staleness
+// limits.
+type objectSource struct {
+	bucket *objectstore.Client
+	filter requestFilter
+	clk    clock.Clock
+	log    blog.Logger
+
+	// refreshInterval is how long a cached response is served before being
+	// revalidated against the bucket.
+	refreshInterval time.Duration
+	// maxStaleness is how old (measured from its thisUpdate) a response can be
+	// before it is no longer served. Responses are never served after their
+	// nextUpdate, regardless of this setting.
+	maxStaleness time.Duration
+
+	mu         sync.Mutex
+	maxEntries int
+	entries    map[string]*list.Element
+	lru        *list.List
+
+	lookups *prometheus.CounterVec
+}
+
+func newObjectSource(
+	bucket *objectstore.Client,
+	filter requestFilter,
+	clk clock.Clock,
+	refreshInterval time.Duration,
+	maxStaleness time.Duration,
+	maxEntries int,
+	stats prometheus.Registerer,
+	log blog.Logger,
+) *objectSource {
+	lookups := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "ocsp_object_storage_lookups",
+		Help: "Count of OCSP response lookups from object storage, by result (cached, fetched, not_modified, not_found, stale, error)",
+	}, []string{"result"})
+	stats.MustRegister(lookups)
+
+	return &objectSource{
+		bucket:          bucket,
+		filter:          filter,
+		clk:             clk,
+		log:             log,
+		refreshInterval: refreshInterval,
+		maxStaleness:    maxStaleness,
+		maxEntries:      maxEntries,
+		entries:         make(map[string]*list.Element),
+		lru:             list.New(),
+		lookups:         lookups,
+	}
+}
+
+// Response is called by the HTTP server to handle a new OCSP request.
+func (src *objectSource) Response(req *ocsp.Request) ([]byte, http.Header, error) {
+	err := src.filter.checkRequest(req)
+	if err != nil {
+		src.log.Debugf("Not responding to filtered OCSP request: %s", err.Error())
+		return nil, nil, err
+	}
+
+	serialString := core.SerialToString(req.SerialNumber)
+	now := src.clk.Now()
+	cached := src.get(serialString)
+	if cached != nil && now.Sub(cached.fetched) < src.refreshInterval {
+		if !src.servable(cached, now) {
+			src.lookups.WithLabelValues("stale").Inc()
+			return nil, nil, bocsp.ErrNotFound
+		}
+		src.lookups.WithLabelValues("cached").Inc()
+		return cached.der, nil, nil
+	}
+
+	fetched, err := src.fetch(req, serialString, cached)
+	if err != nil {
+		if err == bocsp.ErrNotFound {
+			src.lookups.WithLabelValues("not_found").Inc()
+			src.remove(serialString)
+			return nil, nil, err
+		}
+		src.lookups.WithLabelValues("error").Inc()
+		src.log.Errf("Fetching OCSP response for serial %s from object storage: %s", serialString, err)
+		if cached != nil && src.servable(cached, now) {
+			src.log.Warningf("Serving cached OCSP response for serial %s after fetch failed", serialString)
+			return cached.der, nil, nil
+		}
+		return nil, nil, err
+	}
+
+	src.put(fetched)
+	if !src.servable(fetched, now) {
+		src.lookups.WithLabelValues("stale").Inc()
+		src.log.Warningf("OCSP response for serial %s in object storage is too old to serve", serialString)
+		return nil, nil, bocsp.ErrNotFound
+	}
+	return fetched.der, nil, nil
+}
+
+// servable returns true if a response is within the configured staleness
+// limits and hasn't passed its nextUpdate.
+func (src *objectSource) servable(obj *cachedObject, now time.Time) bool {
+	if !obj.nextUpdate.IsZero() && !now.Before(obj.nextUpdate) {
+		return false
+	}
+	if src.maxStaleness > 0 && now.Sub(obj.thisUpdate) > src.maxStaleness {
+		return false
+	}
+	return true
+}
+
+// fetch retrieves the response for serial from the bucket. If cached is not
+// nil, the request is made conditional on its ETag, and if the object hasn't
+// changed a copy of cached with an updated fetch time is returned. A missing
+// object results in bocsp.ErrNotFound.
+func (src *objectSource) fetch(req *ocsp.Request, serial string, cached *cachedObject) (*cachedObject, error) {
+	var etag string
+	if cached != nil {
+		etag = cached.etag
+	}
+	resp, err := src.bucket.Get(serial, etag)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotModified:
+		if cached == nil {
+			return nil, fmt.Errorf("unexpected status %d for unconditional request", resp.StatusCode)
+		}
+		src.lookups.WithLabelValues("not_modified").Inc()
+		revalidated := *cached
+		revalidated.fetched = src.clk.Now()
+		return &revalidated, nil
+	case http.StatusNotFound, http.StatusForbidden:
+		// Buckets which don't grant list permission return 403 rather than
+		// 404 for objects that don't exist.
+		return nil, bocsp.ErrNotFound
+	default:
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	der, err := ioutil.ReadAll(http.MaxBytesReader(nil, resp.Body, maxObjectSize))
+	if err != nil {
+		return nil, err
+	}
+	parsed, err := ocsp.ParseResponse(der, nil)
+	if err != nil {
+		return nil, fmt.Errorf("parsing OCSP response: %w", err)
+	}
+	if parsed.SerialNumber == nil || parsed.SerialNumber.Cmp(req.SerialNumber) != 0 {
+		return nil, fmt.Errorf("object contains OCSP response for serial %s", core.SerialToString(parsed.SerialNumber))
+	}
+	src.lookups.WithLabelValues("fetched").Inc()
+	return &cachedObject{
+		serial:     serial,
+		der:        der,
+		etag:       resp.Header.Get("ETag"),
+		thisUpdate: parsed.ThisUpdate,
+		nextUpdate: parsed.NextUpdate,
+		fetched:    src.clk.Now(),
+	}, nil
+}
+
+func (src *objectSource) get(serial string) *cachedObject {
+	src.mu.Lock()
+	defer src.mu.Unlock()
+	elem, ok := src.entries[serial]
+	if !ok {
+		return nil
+	}
+	src.lru.MoveToFront(elem)
+	return elem.Value.(*cachedObject)
+}
+
+func (src *objectSource) put(obj *cachedObject) {
+	if src.maxEntries <= 0 {
+		return
+	}
+	src.mu.Lock()
+	defer src.mu.Unlock()
+	if elem, ok := src.entries[obj.serial]; ok {
+		elem.Value = obj
+		src.lru.MoveToFront(elem)
+		return
+	}
+	src.entries[obj.serial] = src.lru.PushFront(obj)
+	for src.lru.Len() > src.maxEntries {
+		oldest := src.lru.Back()
+		src.lru.Remove(oldest)
+		delete(src.entries, oldest.Value.(*cachedObject).serial)
+	}
+}
+
+func (src *objectSource) remove(serial string) {
+	src.mu.Lock()
+	defer src.mu.Unlock()
+	if elem, ok := src.entries[serial]; ok {
+		src.lru.Remove(elem)
+		delete(src.entries, serial)
+	}
+}
+
+// newObjectSourceFromConfig returns an objectSource reading from the
+// configured bucket, exiting on failure.
+func newObjectSourceFromConfig(
+	conf *cmd.ObjectStorageConfig,
+	filter requestFilter,
+	refreshInterval time.Duration,
+	maxStaleness time.Duration,
+	maxEntries int,
+	stats prometheus.Registerer,
+	logger blog.Logger,
+) *objectSource {
+	clk := cmd.Clock()
+	bucket, err := conf.Load(clk)
+	cmd.FailOnError(err, "Failed to configure object storage")
+	return newObjectSource(bucket, filter, clk, refreshInterval, maxStaleness, maxEntries, stats, logger)
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jmhodges/clock"
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/crypto/ocsp"
+
+	"github.com/letsencrypt/boulder/core"
+	blog "github.com/letsencrypt/boulder/log"
+	"github.com/letsencrypt/boulder/objectstore"
+	bocsp "github.com/letsencrypt/boulder/ocsp"
+	"github.com/letsencrypt/boulder/test"
+)
+
+// fakeBucket serves objects over HTTP, honoring If-None-Match.
+type fakeBucket struct {
+	sync.Mutex
+	objects     map[string][]byte
+	unavailable bool
+	requests    int
+	conditional int
+}
+
+func (fb *fakeBucket) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	fb.Lock()
+	defer fb.Unlock()
+	fb.requests++
+	if fb.unavailable {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	object, ok := fb.objects[r.URL.Path]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if r.Header.Get("If-None-Match") != "" {
+		fb.conditional++
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+	}
+	w.Header().Set("ETag", `"v1"`)
+	_, _ = w.Write(object)
+}
+
+func setupObjectSource(t *testing.T, maxStaleness time.Duration) (*objectSource, *fakeBucket, clock.FakeClock, *ocsp.Request, func()) {
+	t.Helper()
+	f, err := newFilter([]string{"./testdata/test-ca.der.pem"}, nil)
+	test.AssertNotError(t, err, "newFilter failed")
+	ocspReq, err := ocsp.ParseRequest(req)
+	test.AssertNotError(t, err, "ocsp.ParseRequest failed")
+
+	bucket := &fakeBucket{objects: map[string][]byte{
+		"/ocsp/" + core.SerialToString(ocspReq.SerialNumber): mustRead("./testdata/ocsp.resp"),
+	}}
+	server := httptest.NewServer(bucket)
+
+	fc := clock.NewFake()
+	// The test response is valid from 2015-09-23 until 2030-08-26.
+	fc.Set(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	bucketClient, err := objectstore.New(server.URL+"/ocsp/", objectstore.Credentials{}, server.Client(), fc)
+	test.AssertNotError(t, err, "objectstore.New failed")
+	src := newObjectSource(bucketClient, f, fc, time.Minute, maxStaleness, 10, prometheus.NewRegistry(), blog.NewMock())
+	return src, bucket, fc, ocspReq, server.Close
+}
+
+func TestObjectSourceCaching(t *testing.T) {
+	src, bucket, fc, ocspReq, done := setupObjectSource(t, 0)
+	defer done()
+	expected := mustRead("./testdata/ocsp.resp")
+
+	body, _, err := src.Response(ocspReq)
+	test.AssertNotError(t, err, "Response failed")
+	test.AssertByteEquals(t, body, expected)
+	test.AssertEquals(t, test.CountCounterVec("result", "fetched", src.lookups), 1)
+
+	// Within the refresh interval, the cached response is served without
+	// contacting the bucket.
+	body, _, err = src.Response(ocspReq)
+	test.AssertNotError(t, err, "Response failed")
+	test.AssertByteEquals(t, body, expected)
+	test.AssertEquals(t, bucket.requests, 1)
+	test.AssertEquals(t, test.CountCounterVec("result", "cached", src.lookups), 1)
+
+	// After the refresh interval, the response is revalidated with a
+	// conditional GET.
+	fc.Add(2 * time.Minute)
+	body, _, err = src.Response(ocspReq)
+	test.AssertNotError(t, err, "Response failed")
+	test.AssertByteEquals(t, body, expected)
+	test.AssertEquals(t, bucket.requests, 2)
+	test.AssertEquals(t, bucket.conditional, 1)
+	test.AssertEquals(t, test.CountCounterVec("result", "not_modified", src.lookups), 1)
+
+	// If the bucket becomes unavailable, the cached response is still served.
+	fc.Add(2 * time.Minute)
+	bucket.unavailable = true
+	body, _, err = src.Response(ocspReq)
+	test.AssertNotError(t, err, "Response failed while bucket unavailable")
+	test.AssertByteEquals(t, body, expected)
+	test.AssertEquals(t, test.CountCounterVec("result", "error", src.lookups), 1)
+}
+
+func TestObjectSourceNotFound(t *testing.T) {
+	src, bucket, _, ocspReq, done := setupObjectSource(t, 0)
+	defer done()
+	bucket.objects = map[string][]byte{}
+
+	_, _, err := src.Response(ocspReq)
+	test.AssertErrorIs(t, err, bocsp.ErrNotFound)
+	test.AssertEquals(t, test.CountCounterVec("result", "not_found", src.lookups), 1)
+
+	// With no cached response, an unavailable bucket is an internal error.
+	bucket.unavailable = true
+	_, _, err = src.Response(ocspReq)
+	test.AssertError(t, err, "Response didn't fail")
+	test.Assert(t, !errors.Is(err, bocsp.ErrNotFound), "expected an internal error")
+}
+
+func TestObjectSourceStaleness(t *testing.T) {
+	src, bucket, fc, ocspReq, done := setupObjectSource(t, 24*time.Hour)
+	defer done()
+
+	// The response's thisUpdate is much older than the staleness limit.
+	_, _, err := src.Response(ocspReq)
+	test.AssertErrorIs(t, err, bocsp.ErrNotFound)
+	test.AssertEquals(t, test.CountCounterVec("result", "stale", src.lookups), 1)
+
+	// Without a staleness limit it's served, but never after its nextUpdate,
+	// even from the cache while the bucket is unavailable.
+	src.maxStaleness = 0
+	_, _, err = src.Response(ocspReq)
+	test.AssertNotError(t, err, "Response failed")
+	fc.Set(time.Date(2031, 1, 1, 0, 0, 0, 0, time.UTC))
+	bucket.unavailable = true
+	_, _, err = src.Response(ocspReq)
+	test.AssertError(t, err, "Response didn't fail for an expired response")
+}
+
+func TestObjectSourceEviction(t *testing.T) {
+	src, _, _, _, done := setupObjectSource(t, 0)
+	defer done()
+	src.maxEntries = 2
+
+	src.put(&cachedObject{serial: "a"})
+	src.put(&cachedObject{serial: "b"})
+	test.Assert(t, src.get("a") != nil, "a missing from cache")
+	src.put(&cachedObject{serial: "c"})
+	// b was least recently used.
+	test.Assert(t, src.get("b") == nil, "b wasn't evicted")
+	test.Assert(t, src.get("a") != nil, "a was evicted")
+	test.Assert(t, src.get("c") != nil, "c missing from cache")
+}
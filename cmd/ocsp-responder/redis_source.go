@@ -0,0 +1,250 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/jmhodges/clock"
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/crypto/ocsp"
+
+	capb "github.com/letsencrypt/boulder/ca/proto"
+	"github.com/letsencrypt/boulder/cmd"
+	"github.com/letsencrypt/boulder/core"
+	"github.com/letsencrypt/boulder/db"
+	bgrpc "github.com/letsencrypt/boulder/grpc"
+	blog "github.com/letsencrypt/boulder/log"
+	bocsp "github.com/letsencrypt/boulder/ocsp"
+	"github.com/letsencrypt/boulder/rocsp"
+	"github.com/letsencrypt/boulder/sa"
+)
+
+// rocspClient is the subset of *rocsp.Client used by redisSource, to allow
+// mocking in tests.
+type rocspClient interface {
+	GetResponse(ctx context.Context, serial string) ([]byte, error)
+	StoreResponse(ctx context.Context, serial string, response []byte, ttl time.Duration) error
+}
+
+// redisSource looks up pre-signed OCSP responses in Redis. If there is no
+// response for a serial, or the stored response is stale, it looks up the
+// certificate's status in the database, asks the OCSP signer for a fresh
+// response, and writes that response back to Redis before serving it.
+type redisSource struct {
+	client  rocspClient
+	signer  capb.OCSPGeneratorClient
+	dbMap   dbSelector
+	filter  requestFilter
+	clk     clock.Clock
+	timeout time.Duration
+	// maxAge is how old (measured from its thisUpdate) a response in Redis
+	// can be before it is considered stale and replaced with a freshly
+	// signed one. A stale response is still served if live signing fails, as
+	// long as it hasn't passed its nextUpdate.
+	maxAge time.Duration
+	log    blog.Logger
+
+	lookups *prometheus.CounterVec
+	signs   *prometheus.CounterVec
+}
+
+func newRedisSource(
+	client rocspClient,
+	signer capb.OCSPGeneratorClient,
+	dbMap dbSelector,
+	filter requestFilter,
+	clk clock.Clock,
+	timeout time.Duration,
+	maxAge time.Duration,
+	stats prometheus.Registerer,
+	log blog.Logger,
+) *redisSource {
+	lookups := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "ocsp_redis_lookups",
+		Help: "Count of OCSP response lookups in Redis, by result (hit, miss, stale, expired, mismatch, error)",
+	}, []string{"result"})
+	stats.MustRegister(lookups)
+	signs := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "ocsp_live_signing",
+		Help: "Count of OCSP responses signed on demand after a Redis lookup failed, by result (success, failed, notfound)",
+	}, []string{"result"})
+	stats.MustRegister(signs)
+
+	return &redisSource{
+		client:  client,
+		signer:  signer,
+		dbMap:   dbMap,
+		filter:  filter,
+		clk:     clk,
+		timeout: timeout,
+		maxAge:  maxAge,
+		log:     log,
+		lookups: lookups,
+		signs:   signs,
+	}
+}
+
+// Response is called by the HTTP server to handle a new OCSP request.
+func (src *redisSource) Response(req *ocsp.Request) ([]byte, http.Header, error) {
+	err := src.filter.checkRequest(req)
+	if err != nil {
+		src.log.Debugf("Not responding to filtered OCSP request: %s", err.Error())
+		return nil, nil, err
+	}
+
+	ctx := context.Background()
+	if src.timeout != 0 {
+		var cancel func()
+		ctx, cancel = context.WithTimeout(ctx, src.timeout)
+		defer cancel()
+	}
+
+	serialString := core.SerialToString(req.SerialNumber)
+	stale, err := src.lookup(ctx, req, serialString)
+	if err == nil {
+		return stale, nil, nil
+	}
+
+	fresh, err := src.signAndStore(ctx, req, serialString)
+	if err != nil {
+		if stale != nil && !errors.Is(err, bocsp.ErrNotFound) {
+			src.log.Warningf("Serving stale OCSP response for serial %s after live signing failed: %s", serialString, err)
+			return stale, nil, nil
+		}
+		return nil, nil, err
+	}
+	return fresh, nil, nil
+}
+
+// errStale indicates that a response was found in Redis, but is older than
+// maxAge.
+var errStale = errors.New("stale OCSP response")
+
+// lookup returns the response stored in Redis for serial. If the response is
+// stale it is returned along with errStale. Any other error indicates that no
+// usable response was found.
+func (src *redisSource) lookup(ctx context.Context, req *ocsp.Request, serial string) ([]byte, error) {
+	der, err := src.client.GetResponse(ctx, serial)
+	if err != nil {
+		if errors.Is(err, rocsp.ErrRedisNotFound) {
+			src.lookups.WithLabelValues("miss").Inc()
+		} else {
+			src.lookups.WithLabelValues("error").Inc()
+			src.log.Errf("Looking up OCSP response for serial %s in Redis: %s", serial, err)
+		}
+		return nil, err
+	}
+
+	parsed, err := ocsp.ParseResponse(der, nil)
+	if err != nil {
+		src.lookups.WithLabelValues("error").Inc()
+		src.log.Errf("Parsing OCSP response for serial %s from Redis: %s", serial, err)
+		return nil, err
+	}
+	if parsed.SerialNumber == nil || parsed.SerialNumber.Cmp(req.SerialNumber) != 0 {
+		src.lookups.WithLabelValues("mismatch").Inc()
+		src.log.Errf("OCSP response in Redis for serial %s has serial %s", serial, core.SerialToString(parsed.SerialNumber))
+		return nil, bocsp.ErrNotFound
+	}
+
+	now := src.clk.Now()
+	if !parsed.NextUpdate.IsZero() && !now.Before(parsed.NextUpdate) {
+		src.lookups.WithLabelValues("expired").Inc()
+		return nil, errStale
+	}
+	if src.maxAge > 0 && now.Sub(parsed.ThisUpdate) > src.maxAge {
+		src.lookups.WithLabelValues("stale").Inc()
+		return der, errStale
+	}
+	src.lookups.WithLabelValues("hit").Inc()
+	return der, nil
+}
+
+// signAndStore looks up the certificate status for serial in the database,
+// requests a freshly signed response for it, and stores the response in
+// Redis until its nextUpdate.
+func (src *redisSource) signAndStore(ctx context.Context, req *ocsp.Request, serial string) ([]byte, error) {
+	status, err := sa.SelectCertificateStatus(src.dbMap.WithContext(ctx), serial)
+	if err != nil {
+		if db.IsNoRows(err) {
+			src.signs.WithLabelValues("notfound").Inc()
+			return nil, bocsp.ErrNotFound
+		}
+		src.signs.WithLabelValues("failed").Inc()
+		src.log.AuditErrf("Looking up certificate status for serial %s: %s", serial, err)
+		return nil, err
+	}
+	if status.IsExpired || status.IssuerID == nil || !src.filter.responseMatchesIssuer(req, status) {
+		src.signs.WithLabelValues("notfound").Inc()
+		src.log.Warningf("OCSP Response not signed (expired or issuer mismatch) for CA=%s, Serial=%s", hex.EncodeToString(req.IssuerKeyHash), serial)
+		return nil, bocsp.ErrNotFound
+	}
+
+	resp, err := src.signer.GenerateOCSP(ctx, &capb.GenerateOCSPRequest{
+		Serial:    status.Serial,
+		IssuerID:  *status.IssuerID,
+		Status:    string(status.Status),
+		Reason:    int32(status.RevokedReason),
+		RevokedAt: status.RevokedDate.UnixNano(),
+	})
+	if err != nil {
+		src.signs.WithLabelValues("failed").Inc()
+		src.log.Errf("Signing OCSP response for serial %s: %s", serial, err)
+		return nil, err
+	}
+	src.signs.WithLabelValues("success").Inc()
+
+	parsed, err := ocsp.ParseResponse(resp.Response, nil)
+	if err != nil {
+		src.log.Errf("Parsing freshly signed OCSP response for serial %s: %s", serial, err)
+		return resp.Response, nil
+	}
+	ttl := parsed.NextUpdate.Sub(src.clk.Now())
+	err = src.client.StoreResponse(ctx, serial, resp.Response, ttl)
+	if err != nil {
+		// Failing to store the response doesn't prevent us from serving it.
+		src.log.Errf("Storing OCSP response for serial %s in Redis: %s", serial, err)
+	}
+	return resp.Response, nil
+}
+
+// newRedisSourceFromConfig connects to Redis and the OCSP signer and returns a
+// redisSource using them, exiting on failure.
+func newRedisSourceFromConfig(
+	redisConf *cmd.RedisConfig,
+	signerConf *cmd.GRPCClientConfig,
+	tlsConf cmd.TLSConfig,
+	maxAge time.Duration,
+	dbMap dbSelector,
+	filter requestFilter,
+	timeout time.Duration,
+	stats prometheus.Registerer,
+	logger blog.Logger,
+) *redisSource {
+	if signerConf == nil {
+		cmd.Fail("OCSPGeneratorService must be configured when using Redis")
+	}
+
+	password, err := redisConf.Pass()
+	cmd.FailOnError(err, "Failed to load Redis password")
+	var redisTLS *tls.Config
+	if redisConf.TLS != nil {
+		redisTLS, err = redisConf.TLS.Load()
+		cmd.FailOnError(err, "Failed to load Redis TLS config")
+	}
+	client := rocsp.NewClient(redisConf.Address, password, redisTLS, redisConf.Timeout.Duration, redisConf.PoolSize)
+
+	clk := cmd.Clock()
+	tlsConfig, err := tlsConf.Load()
+	cmd.FailOnError(err, "TLS config")
+	clientMetrics := bgrpc.NewClientMetrics(stats)
+	conn, err := bgrpc.ClientSetup(signerConf, tlsConfig, clientMetrics, clk)
+	cmd.FailOnError(err, "Failed to load credentials and create gRPC connection to OCSP signer")
+	signer := bgrpc.NewOCSPGeneratorClient(capb.NewOCSPGeneratorClient(conn))
+
+	return newRedisSource(client, signer, dbMap, filter, clk, timeout, maxAge, stats, logger)
+}
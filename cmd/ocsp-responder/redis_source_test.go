@@ -0,0 +1,185 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-gorp/gorp/v3"
+	"github.com/jmhodges/clock"
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/crypto/ocsp"
+	"google.golang.org/grpc"
+
+	capb "github.com/letsencrypt/boulder/ca/proto"
+	"github.com/letsencrypt/boulder/core"
+	blog "github.com/letsencrypt/boulder/log"
+	bocsp "github.com/letsencrypt/boulder/ocsp"
+	"github.com/letsencrypt/boulder/rocsp"
+	"github.com/letsencrypt/boulder/test"
+)
+
+type mockRedis struct {
+	responses map[string][]byte
+	ttls      map[string]time.Duration
+	err       error
+}
+
+func (mr *mockRedis) GetResponse(_ context.Context, serial string) ([]byte, error) {
+	if mr.err != nil {
+		return nil, mr.err
+	}
+	response, ok := mr.responses[serial]
+	if !ok {
+		return nil, rocsp.ErrRedisNotFound
+	}
+	return response, nil
+}
+
+func (mr *mockRedis) StoreResponse(_ context.Context, serial string, response []byte, ttl time.Duration) error {
+	if mr.err != nil {
+		return mr.err
+	}
+	mr.responses[serial] = response
+	mr.ttls[serial] = ttl
+	return nil
+}
+
+type mockSigner struct {
+	calls int
+	err   error
+}
+
+func (ms *mockSigner) GenerateOCSP(_ context.Context, req *capb.GenerateOCSPRequest, _ ...grpc.CallOption) (*capb.OCSPResponse, error) {
+	ms.calls++
+	if ms.err != nil {
+		return nil, ms.err
+	}
+	return &capb.OCSPResponse{Response: resp.OCSPResponse}, nil
+}
+
+// statusSelector returns a fixed certificateStatus for the test certificate,
+// independent of the shared resp variable which other tests modify.
+type statusSelector struct {
+	mockSqlExecutor
+}
+
+func (ss statusSelector) WithContext(context.Context) gorp.SqlExecutor {
+	return ss
+}
+
+func (ss statusSelector) SelectOne(output interface{}, _ string, _ ...interface{}) error {
+	outputPtr, ok := output.(*core.CertificateStatus)
+	if !ok {
+		return fmt.Errorf("incorrect output type %T", output)
+	}
+	testIssuerID := int64(3568119531)
+	*outputPtr = core.CertificateStatus{
+		Status:   core.OCSPStatusGood,
+		IssuerID: &testIssuerID,
+	}
+	return nil
+}
+
+func setupRedisSource(t *testing.T, maxAge time.Duration) (*redisSource, *mockRedis, *mockSigner, clock.FakeClock, *ocsp.Request) {
+	t.Helper()
+	f, err := newFilter([]string{"./testdata/test-ca.der.pem"}, nil)
+	test.AssertNotError(t, err, "newFilter failed")
+	ocspReq, err := ocsp.ParseRequest(req)
+	test.AssertNotError(t, err, "ocsp.ParseRequest failed")
+
+	fc := clock.NewFake()
+	// The test response is valid from 2015-09-23 until 2030-08-26.
+	fc.Set(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	mr := &mockRedis{responses: make(map[string][]byte), ttls: make(map[string]time.Duration)}
+	ms := &mockSigner{}
+	src := newRedisSource(mr, ms, statusSelector{}, f, fc, time.Second, maxAge, prometheus.NewRegistry(), blog.NewMock())
+	return src, mr, ms, fc, ocspReq
+}
+
+func TestRedisSourceHit(t *testing.T) {
+	src, mr, ms, _, ocspReq := setupRedisSource(t, 0)
+	serial := core.SerialToString(ocspReq.SerialNumber)
+	mr.responses[serial] = resp.OCSPResponse
+
+	body, _, err := src.Response(ocspReq)
+	test.AssertNotError(t, err, "Response failed")
+	test.AssertByteEquals(t, body, resp.OCSPResponse)
+	test.AssertEquals(t, ms.calls, 0)
+	test.AssertEquals(t, test.CountCounterVec("result", "hit", src.lookups), 1)
+}
+
+func TestRedisSourceMiss(t *testing.T) {
+	src, mr, ms, fc, ocspReq := setupRedisSource(t, 0)
+	serial := core.SerialToString(ocspReq.SerialNumber)
+
+	body, _, err := src.Response(ocspReq)
+	test.AssertNotError(t, err, "Response failed")
+	test.AssertByteEquals(t, body, resp.OCSPResponse)
+	test.AssertEquals(t, ms.calls, 1)
+	test.AssertEquals(t, test.CountCounterVec("result", "miss", src.lookups), 1)
+	test.AssertEquals(t, test.CountCounterVec("result", "success", src.signs), 1)
+
+	// The fresh response should have been written back, expiring at its
+	// nextUpdate.
+	test.AssertByteEquals(t, mr.responses[serial], resp.OCSPResponse)
+	test.AssertEquals(t, mr.ttls[serial], time.Date(2030, 8, 26, 0, 0, 0, 0, time.UTC).Sub(fc.Now()))
+
+	// If signing fails on a miss, there's nothing to serve.
+	delete(mr.responses, serial)
+	ms.err = errors.New("signer unavailable")
+	_, _, err = src.Response(ocspReq)
+	test.AssertError(t, err, "Response didn't fail when signing failed")
+}
+
+func TestRedisSourceStale(t *testing.T) {
+	src, mr, ms, _, ocspReq := setupRedisSource(t, 24*time.Hour)
+	serial := core.SerialToString(ocspReq.SerialNumber)
+	mr.responses[serial] = resp.OCSPResponse
+
+	// The stored response is older than maxAge, so a fresh one is signed.
+	_, _, err := src.Response(ocspReq)
+	test.AssertNotError(t, err, "Response failed")
+	test.AssertEquals(t, ms.calls, 1)
+	test.AssertEquals(t, test.CountCounterVec("result", "stale", src.lookups), 1)
+
+	// If signing fails, the stale response is served instead.
+	ms.err = errors.New("signer unavailable")
+	body, _, err := src.Response(ocspReq)
+	test.AssertNotError(t, err, "Response failed")
+	test.AssertByteEquals(t, body, resp.OCSPResponse)
+	test.AssertEquals(t, ms.calls, 2)
+}
+
+func TestRedisSourceExpired(t *testing.T) {
+	src, mr, ms, fc, ocspReq := setupRedisSource(t, 0)
+	serial := core.SerialToString(ocspReq.SerialNumber)
+	mr.responses[serial] = resp.OCSPResponse
+	fc.Set(time.Date(2031, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	// A response past its nextUpdate is never served, even if signing fails.
+	ms.err = errors.New("signer unavailable")
+	_, _, err := src.Response(ocspReq)
+	test.AssertError(t, err, "Response didn't fail for an expired response")
+	test.AssertEquals(t, test.CountCounterVec("result", "expired", src.lookups), 1)
+}
+
+func TestRedisSourceErrors(t *testing.T) {
+	src, mr, ms, _, ocspReq := setupRedisSource(t, 0)
+
+	// If Redis is unavailable, responses are signed live.
+	mr.err = errors.New("connection refused")
+	body, _, err := src.Response(ocspReq)
+	test.AssertNotError(t, err, "Response failed")
+	test.AssertByteEquals(t, body, resp.OCSPResponse)
+	test.AssertEquals(t, ms.calls, 1)
+	test.AssertEquals(t, test.CountCounterVec("result", "error", src.lookups), 1)
+
+	// If the database is unavailable too, the request fails.
+	src.dbMap = brokenSelector{}
+	_, _, err = src.Response(ocspReq)
+	test.AssertError(t, err, "Response didn't fail")
+	test.Assert(t, !errors.Is(err, bocsp.ErrNotFound), "expected an internal error")
+}
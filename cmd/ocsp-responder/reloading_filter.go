@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/jmhodges/clock"
+	"golang.org/x/crypto/ocsp"
+	"gopkg.in/yaml.v2"
+
+	"github.com/letsencrypt/boulder/core"
+	"github.com/letsencrypt/boulder/db"
+	"github.com/letsencrypt/boulder/issuance"
+	blog "github.com/letsencrypt/boulder/log"
+	"github.com/letsencrypt/boulder/reloader"
+)
+
+// requestFilter decides which OCSP requests and responses a source should
+// handle. It is implemented by *ocspFilter, and by *reloadingFilter which
+// wraps an *ocspFilter that can be replaced at runtime.
+type requestFilter interface {
+	checkRequest(req *ocsp.Request) error
+	responseMatchesIssuer(req *ocsp.Request, status core.CertificateStatus) bool
+}
+
+// filterConfig is the format of the file named by the FilterConfigFile config
+// field.
+type filterConfig struct {
+	IssuerCerts            []string `yaml:"issuerCerts"`
+	RequiredSerialPrefixes []string `yaml:"requiredSerialPrefixes"`
+}
+
+// reloadingFilter is a requestFilter whose issuer certificates and required
+// serial prefixes are loaded from a file, and reloaded whenever that file
+// changes. Each reload builds a complete new ocspFilter which replaces the
+// previous one atomically, so a request is never checked against a partially
+// applied config.
+type reloadingFilter struct {
+	current atomic.Value // *ocspFilter
+	// hasUnexpired returns true if the issuer has issued certificates which
+	// haven't yet expired. A reload which would remove such an issuer is
+	// rejected, since we would stop serving OCSP for those certificates.
+	hasUnexpired func(issuance.IssuerID) (bool, error)
+	log          blog.Logger
+}
+
+// newReloadingFilter loads the filter config from filename and starts watching
+// it for changes. An error is returned if the initial load fails; errors from
+// later reloads are logged and the previous config remains in effect.
+func newReloadingFilter(filename string, dbMap dbSelector, clk clock.Clock, timeout time.Duration, log blog.Logger) (*reloadingFilter, error) {
+	rf := &reloadingFilter{
+		hasUnexpired: func(id issuance.IssuerID) (bool, error) {
+			return issuerHasUnexpired(dbMap, clk, timeout, id)
+		},
+		log: log,
+	}
+	_, err := reloader.New(filename, rf.load, rf.loadError)
+	if err != nil {
+		return nil, err
+	}
+	return rf, nil
+}
+
+// issuerHasUnexpired queries the certificateStatus table for any unexpired
+// certificate issued by the given issuer. The query is answered from the
+// issuerID_notAfter_idx index by looking up a single row, so it's cheap
+// however many certificates there are.
+func issuerHasUnexpired(dbMap dbSelector, clk clock.Clock, timeout time.Duration, id issuance.IssuerID) (bool, error) {
+	ctx := context.Background()
+	if timeout != 0 {
+		var cancel func()
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	var one int64
+	err := dbMap.WithContext(ctx).SelectOne(
+		&one,
+		"SELECT 1 FROM certificateStatus FORCE INDEX (issuerID_notAfter_idx) WHERE issuerID = ? AND notAfter > ? LIMIT 1",
+		int64(id),
+		clk.Now(),
+	)
+	if err != nil {
+		if db.IsNoRows(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// load is a callback suitable for use with reloader.New which parses and
+// validates a new filter config and, if it's acceptable, starts using it.
+func (rf *reloadingFilter) load(contents []byte) error {
+	var fc filterConfig
+	err := yaml.UnmarshalStrict(contents, &fc)
+	if err != nil {
+		return fmt.Errorf("parsing OCSP filter config: %w", err)
+	}
+	f, err := newFilter(fc.IssuerCerts, fc.RequiredSerialPrefixes)
+	if err != nil {
+		return err
+	}
+
+	old, _ := rf.current.Load().(*ocspFilter)
+	if old != nil {
+		for id := range old.issuerKeyHashes {
+			if _, ok := f.issuerKeyHashes[id]; ok {
+				continue
+			}
+			unexpired, err := rf.hasUnexpired(id)
+			if err != nil {
+				return fmt.Errorf("checking for unexpired certificates from issuer %d: %w", id, err)
+			}
+			if unexpired {
+				return fmt.Errorf("refusing to remove issuer %d, which has unexpired certificates", id)
+			}
+		}
+	}
+
+	rf.current.Store(f)
+	rf.log.Infof("Loaded OCSP filter config with %d issuers and %d required serial prefixes",
+		len(f.issuerKeyHashes), len(f.serialPrefixes))
+	return nil
+}
+
+// loadError is a callback suitable for use with reloader.New.
+func (rf *reloadingFilter) loadError(err error) {
+	rf.log.AuditErrf("error reloading OCSP filter config, keeping the previous config: %s", err)
+}
+
+func (rf *reloadingFilter) checkRequest(req *ocsp.Request) error {
+	return rf.current.Load().(*ocspFilter).checkRequest(req)
+}
+
+func (rf *reloadingFilter) responseMatchesIssuer(req *ocsp.Request, status core.CertificateStatus) bool {
+	return rf.current.Load().(*ocspFilter).responseMatchesIssuer(req, status)
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"golang.org/x/crypto/ocsp"
+
+	"github.com/letsencrypt/boulder/issuance"
+	blog "github.com/letsencrypt/boulder/log"
+	"github.com/letsencrypt/boulder/test"
+)
+
+const (
+	oneIssuerConfig = `
+issuerCerts:
+  - ./testdata/test-ca.der.pem
+`
+	twoIssuersConfig = `
+issuerCerts:
+  - ./testdata/test-ca.der.pem
+  - ../../test/test-root.pem
+requiredSerialPrefixes:
+  - "00"
+`
+	otherIssuerConfig = `
+issuerCerts:
+  - ../../test/test-root.pem
+`
+)
+
+func TestReloadingFilter(t *testing.T) {
+	ocspReq, err := ocsp.ParseRequest(req)
+	test.AssertNotError(t, err, "ocsp.ParseRequest failed")
+
+	unexpired := map[issuance.IssuerID]bool{}
+	var checkErr error
+	rf := &reloadingFilter{
+		hasUnexpired: func(id issuance.IssuerID) (bool, error) {
+			return unexpired[id], checkErr
+		},
+		log: blog.NewMock(),
+	}
+
+	err = rf.load([]byte("issuerCerts: [\n"))
+	test.AssertError(t, err, "load didn't fail for malformed YAML")
+	err = rf.load([]byte("issuerCertz: []\n"))
+	test.AssertError(t, err, "load didn't fail for an unknown field")
+	err = rf.load([]byte("issuerCerts: []\n"))
+	test.AssertError(t, err, "load didn't fail with no issuers")
+
+	err = rf.load([]byte(oneIssuerConfig))
+	test.AssertNotError(t, err, "load failed")
+	test.AssertNotError(t, rf.checkRequest(ocspReq), "request should be accepted")
+
+	// Adding an issuer and a serial prefix is always allowed.
+	err = rf.load([]byte(twoIssuersConfig))
+	test.AssertNotError(t, err, "load failed")
+	test.AssertNotError(t, rf.checkRequest(ocspReq), "request should be accepted")
+	test.AssertEquals(t, len(rf.current.Load().(*ocspFilter).issuerKeyHashes), 2)
+
+	// Removing an issuer with unexpired certificates is rejected, and the
+	// previous config stays in effect.
+	testCAID := issuance.IssuerID(3568119531)
+	unexpired[testCAID] = true
+	err = rf.load([]byte(otherIssuerConfig))
+	test.AssertError(t, err, "load didn't fail when removing an issuer with unexpired certificates")
+	test.AssertNotError(t, rf.checkRequest(ocspReq), "previous config should still be in effect")
+
+	// If the check fails, the reload is also rejected.
+	checkErr = errors.New("database unavailable")
+	err = rf.load([]byte(otherIssuerConfig))
+	test.AssertError(t, err, "load didn't fail when the unexpired check failed")
+	checkErr = nil
+
+	// Once the issuer's certificates have expired, it can be removed.
+	unexpired[testCAID] = false
+	err = rf.load([]byte(otherIssuerConfig))
+	test.AssertNotError(t, err, "load failed")
+	test.AssertError(t, rf.checkRequest(ocspReq), "request for removed issuer should be rejected")
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"container/heap"
 	"context"
 	"database/sql"
 	"errors"
@@ -31,6 +32,12 @@ type ocspDB interface {
 	Exec(query string, args ...interface{}) (sql.Result, error)
 }
 
+// objectWriter writes objects to a bucket. It's satisfied by
+// *objectstore.Client.
+type objectWriter interface {
+	Put(name string, body []byte, contentType string) error
+}
+
 // OCSPUpdater contains the useful objects for the Updater
 type OCSPUpdater struct {
 	log blog.Logger
@@ -40,8 +47,14 @@ type OCSPUpdater struct {
 
 	ogc capb.OCSPGeneratorClient
 
+	// objects, if not nil, is the bucket to which each stored response is
+	// also written, for the ocsp-responder to serve from if the database is
+	// unavailable.
+	objects objectWriter
+
 	tickWindow    time.Duration
 	batchSize     int
+	newBatchSize  int
 	tickHistogram *prometheus.HistogramVec
 
 	maxBackoff    time.Duration
@@ -58,6 +71,10 @@ type OCSPUpdater struct {
 	genStoreHistogram  prometheus.Histogram
 	generatedCounter   *prometheus.CounterVec
 	storedCounter      *prometheus.CounterVec
+	objectsCounter     *prometheus.CounterVec
+	queuedCounter      *prometheus.CounterVec
+	queuedAgeHistogram prometheus.Histogram
+	oldestQueuedAge    prometheus.Gauge
 }
 
 func newUpdater(
@@ -94,6 +111,11 @@ func newUpdater(
 		Help: "A counter of OCSP response storage calls labelled by result",
 	}, []string{"result"})
 	stats.MustRegister(storedCounter)
+	objectsCounter := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "ocsp_updater_object_storage_writes",
+		Help: "A counter of OCSP responses written to object storage labelled by result",
+	}, []string{"result"})
+	stats.MustRegister(objectsCounter)
 	tickHistogram := prometheus.NewHistogramVec(prometheus.HistogramOpts{
 		Name: "ocsp_updater_ticks",
 		Help: "A histogram of ocsp-updater tick latencies labelled by result and whether the tick was considered longer than expected",
@@ -105,6 +127,22 @@ func newUpdater(
 		Buckets: []float64{10, 100, 1000, 10000, 43200},
 	})
 	stats.MustRegister(stalenessHistogram)
+	queuedCounter := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "ocsp_updater_queued",
+		Help: "A counter of certificate statuses queued for OCSP signing, labelled by kind: new, for certificates without a response, or refresh",
+	}, []string{"kind"})
+	stats.MustRegister(queuedCounter)
+	queuedAgeHistogram := prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "ocsp_updater_queued_response_age",
+		Help:    "Age, in seconds, of the OCSP responses queued for refreshing",
+		Buckets: []float64{3600, 43200, 86400, 172800, 259200, 345600, 432000, 518400, 604800},
+	})
+	stats.MustRegister(queuedAgeHistogram)
+	oldestQueuedAge := prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "ocsp_updater_oldest_queued_response_age",
+		Help: "Age, in seconds, of the oldest OCSP response queued for refreshing in the latest tick",
+	})
+	stats.MustRegister(oldestQueuedAge)
 
 	updater := OCSPUpdater{
 		clk:                          clk,
@@ -116,10 +154,15 @@ func newUpdater(
 		genStoreHistogram:            genStoreHistogram,
 		generatedCounter:             generatedCounter,
 		storedCounter:                storedCounter,
+		objectsCounter:               objectsCounter,
 		stalenessHistogram:           stalenessHistogram,
 		tickHistogram:                tickHistogram,
 		tickWindow:                   config.OldOCSPWindow.Duration,
 		batchSize:                    config.OldOCSPBatchSize,
+		newBatchSize:                 config.NewOCSPBatchSize,
+		queuedCounter:                queuedCounter,
+		queuedAgeHistogram:           queuedAgeHistogram,
+		oldestQueuedAge:              oldestQueuedAge,
 		maxBackoff:                   config.SignFailureBackoffMax.Duration,
 		backoffFactor:                config.SignFailureBackoffFactor,
 	}
@@ -127,15 +170,28 @@ func newUpdater(
 	return &updater, nil
 }
 
+// findStaleOCSPResponses finds up to batchSize certificate statuses whose
+// responses were last signed before oldestLastUpdatedTime, oldest first. If
+// certificates without responses have a budget of their own, they're left
+// out.
 func (updater *OCSPUpdater) findStaleOCSPResponses(oldestLastUpdatedTime time.Time, batchSize int) ([]core.CertificateStatus, error) {
-	statuses, err := sa.SelectCertificateStatuses(
-		updater.dbMap,
-		`WHERE ocspLastUpdated < :lastUpdate
+	clause := `WHERE ocspLastUpdated < :lastUpdate
 		 AND NOT isExpired
 		 ORDER BY ocspLastUpdated ASC
-		 LIMIT :limit`,
+		 LIMIT :limit`
+	if updater.newBatchSize > 0 {
+		clause = `WHERE ocspLastUpdated < :lastUpdate
+		 AND ocspLastUpdated > :never
+		 AND NOT isExpired
+		 ORDER BY ocspLastUpdated ASC
+		 LIMIT :limit`
+	}
+	statuses, err := sa.SelectCertificateStatuses(
+		updater.dbMap,
+		clause,
 		map[string]interface{}{
 			"lastUpdate": oldestLastUpdatedTime,
+			"never":      time.Time{},
 			"limit":      batchSize,
 		},
 	)
@@ -151,6 +207,85 @@ func (updater *OCSPUpdater) findStaleOCSPResponses(oldestLastUpdatedTime time.Ti
 	return statuses, err
 }
 
+// findNewOCSPResponses finds up to batchSize certificate statuses which have
+// never had a response signed.
+func (updater *OCSPUpdater) findNewOCSPResponses(batchSize int) ([]core.CertificateStatus, error) {
+	statuses, err := sa.SelectCertificateStatuses(
+		updater.dbMap,
+		`WHERE ocspLastUpdated <= :never
+		 AND NOT isExpired
+		 LIMIT :limit`,
+		map[string]interface{}{
+			"never": time.Time{},
+			"limit": batchSize,
+		},
+	)
+	if db.IsNoRows(err) {
+		return nil, nil
+	}
+	return statuses, err
+}
+
+// workQueue is a priority queue, for use with container/heap, of certificate
+// statuses whose OCSP responses need signing. Since every response is valid
+// for the same period, the response that will go stale soonest is the one
+// signed longest ago, so statuses are ordered by when they were last signed.
+// Those never signed come first.
+type workQueue []core.CertificateStatus
+
+func (q workQueue) Len() int           { return len(q) }
+func (q workQueue) Less(i, j int) bool { return q[i].OCSPLastUpdated.Before(q[j].OCSPLastUpdated) }
+func (q workQueue) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+
+func (q *workQueue) Push(x interface{}) {
+	*q = append(*q, x.(core.CertificateStatus))
+}
+
+func (q *workQueue) Pop() interface{} {
+	old := *q
+	status := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return status
+}
+
+// findWork queues the certificate statuses whose responses need signing this
+// tick: up to batchSize whose responses were last signed before
+// oldestLastUpdatedTime, and, if new certificates have a budget of their own,
+// up to newBatchSize which have never had a response signed.
+func (updater *OCSPUpdater) findWork(oldestLastUpdatedTime time.Time, batchSize int) (*workQueue, error) {
+	stale, err := updater.findStaleOCSPResponses(oldestLastUpdatedTime, batchSize)
+	if err != nil {
+		return nil, err
+	}
+	var fresh []core.CertificateStatus
+	if updater.newBatchSize > 0 {
+		fresh, err = updater.findNewOCSPResponses(updater.newBatchSize)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	now := updater.clk.Now()
+	queue := make(workQueue, 0, len(stale)+len(fresh))
+	var oldest time.Duration
+	for _, status := range append(stale, fresh...) {
+		if status.OCSPLastUpdated.IsZero() {
+			updater.queuedCounter.WithLabelValues("new").Inc()
+		} else {
+			updater.queuedCounter.WithLabelValues("refresh").Inc()
+			age := now.Sub(status.OCSPLastUpdated)
+			updater.queuedAgeHistogram.Observe(age.Seconds())
+			if age > oldest {
+				oldest = age
+			}
+		}
+		queue = append(queue, status)
+	}
+	updater.oldestQueuedAge.Set(oldest.Seconds())
+	heap.Init(&queue)
+	return &queue, nil
+}
+
 func (updater *OCSPUpdater) generateResponse(ctx context.Context, status core.CertificateStatus) (*core.CertificateStatus, error) {
 	if status.IssuerID == nil || *status.IssuerID == 0 {
 		return nil, errors.New("cert status has nil or 0 IssuerID")
@@ -178,7 +313,7 @@ func (updater *OCSPUpdater) storeResponse(status *core.CertificateStatus) error
 	// Update the certificateStatus table with the new OCSP response, the status
 	// WHERE is used make sure we don't overwrite a revoked response with a one
 	// containing a 'good' status.
-	_, err := updater.dbMap.Exec(
+	result, err := updater.dbMap.Exec(
 		`UPDATE certificateStatus
 		 SET ocspResponse=?,ocspLastUpdated=?
 		 WHERE serial=?
@@ -188,7 +323,32 @@ func (updater *OCSPUpdater) storeResponse(status *core.CertificateStatus) error
 		status.Serial,
 		string(status.Status),
 	)
-	return err
+	if err != nil || updater.objects == nil {
+		return err
+	}
+	// Only a response which was stored is written to the bucket, so that a
+	// revoked response there isn't overwritten with a 'good' one either.
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		updater.writeObject(status)
+	}
+	return nil
+}
+
+// writeObject writes a stored response to the bucket, named by its serial.
+// The database remains the source of truth, so failures are only logged, and
+// the response is written again when it's next refreshed.
+func (updater *OCSPUpdater) writeObject(status *core.CertificateStatus) {
+	err := updater.objects.Put(status.Serial, status.OCSPResponse, "application/ocsp-response")
+	if err != nil {
+		updater.log.Errf("Failed to write OCSP response for serial %s to object storage: %s", status.Serial, err)
+		updater.objectsCounter.WithLabelValues("failed").Inc()
+		return
+	}
+	updater.objectsCounter.WithLabelValues("success").Inc()
 }
 
 // markExpired updates a given CertificateStatus to have `isExpired` set.
@@ -202,7 +362,9 @@ func (updater *OCSPUpdater) markExpired(status core.CertificateStatus) error {
 	return err
 }
 
-func (updater *OCSPUpdater) generateOCSPResponses(ctx context.Context, statuses []core.CertificateStatus) error {
+// generateOCSPResponses signs and stores responses for the queued statuses,
+// in order of priority.
+func (updater *OCSPUpdater) generateOCSPResponses(ctx context.Context, queue *workQueue) error {
 	// Use the semaphore pattern from
 	// https://github.com/golang/go/wiki/BoundingResourceUse to send a number of
 	// GenerateOCSP / storeResponse requests in parallel, while limiting the total number of
@@ -235,7 +397,8 @@ func (updater *OCSPUpdater) generateOCSPResponses(ctx context.Context, statuses
 		updater.storedCounter.WithLabelValues("success").Inc()
 	}
 
-	for _, status := range statuses {
+	for queue.Len() > 0 {
+		status := heap.Pop(queue).(core.CertificateStatus)
 		wait()
 		go work(status)
 	}
@@ -251,13 +414,13 @@ func (updater *OCSPUpdater) generateOCSPResponses(ctx context.Context, statuses
 // generates/stores new ones
 func (updater *OCSPUpdater) updateOCSPResponses(ctx context.Context, batchSize int) error {
 	tickStart := updater.clk.Now()
-	statuses, err := updater.findStaleOCSPResponses(tickStart.Add(-updater.ocspMinTimeToExpiry), batchSize)
+	queue, err := updater.findWork(tickStart.Add(-updater.ocspMinTimeToExpiry), batchSize)
 	if err != nil {
 		updater.log.AuditErrf("Failed to find stale OCSP responses: %s", err)
 		return err
 	}
 
-	for _, s := range statuses {
+	for _, s := range *queue {
 		if !s.IsExpired && tickStart.After(s.NotAfter) {
 			err := updater.markExpired(s)
 			if err != nil {
@@ -266,7 +429,7 @@ func (updater *OCSPUpdater) updateOCSPResponses(ctx context.Context, batchSize i
 		}
 	}
 
-	return updater.generateOCSPResponses(ctx, statuses)
+	return updater.generateOCSPResponses(ctx, queue)
 }
 
 type config struct {
@@ -283,6 +446,10 @@ type OCSPUpdaterConfig struct {
 
 	OldOCSPWindow    cmd.ConfigDuration
 	OldOCSPBatchSize int
+	// NewOCSPBatchSize, if non-zero, is the most certificates without an OCSP
+	// response signed in each tick, in addition to OldOCSPBatchSize
+	// refreshes. Otherwise they share OldOCSPBatchSize with refreshes.
+	NewOCSPBatchSize int
 
 	OCSPMinTimeToExpiry          cmd.ConfigDuration
 	ParallelGenerateOCSPRequests int
@@ -292,6 +459,11 @@ type OCSPUpdaterConfig struct {
 
 	OCSPGeneratorService *cmd.GRPCClientConfig
 
+	// ObjectStorage, if present, is a bucket to which each newly signed OCSP
+	// response is also written, for an ocsp-responder configured with the same
+	// bucket to serve from.
+	ObjectStorage *cmd.ObjectStorageConfig
+
 	Features map[string]bool
 }
 
@@ -323,6 +495,7 @@ func (updater *OCSPUpdater) tick() {
 
 func main() {
 	configFile := flag.String("config", "", "File path to the configuration file for this service")
+	checkConfig := cmd.CheckConfigFlag()
 	flag.Parse()
 	if *configFile == "" {
 		flag.Usage()
@@ -332,6 +505,9 @@ func main() {
 	var c config
 	err := cmd.ReadConfigFile(*configFile, &c)
 	cmd.FailOnError(err, "Reading JSON config file into config structure")
+	if *checkConfig {
+		cmd.CheckConfig(&c)
+	}
 
 	conf := c.OCSPUpdater
 	err = features.Set(conf.Features)
@@ -355,6 +531,8 @@ func main() {
 
 	// Collect and periodically report DB metrics using the DBMap and prometheus stats.
 	sa.InitDBMetrics(dbMap, stats, dbSettings)
+	err = conf.DBConfig.StartAdaptivePool(dbMap.Db, stats, logger)
+	cmd.FailOnError(err, "Invalid adaptive DB pool config")
 
 	clk := cmd.Clock()
 
@@ -375,6 +553,10 @@ func main() {
 		logger,
 	)
 	cmd.FailOnError(err, "Failed to create updater")
+	if conf.ObjectStorage != nil {
+		updater.objects, err = conf.ObjectStorage.Load(clk)
+		cmd.FailOnError(err, "Failed to configure object storage")
+	}
 
 	go cmd.CatchSignals(logger, nil)
 
@@ -6,9 +6,12 @@ import (
 	"crypto/rsa"
 	"crypto/x509"
 	"database/sql"
+	"database/sql/driver"
 	"errors"
 	"fmt"
 	"math/big"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -49,7 +52,7 @@ func setup(t *testing.T) (*OCSPUpdater, core.StorageAuthority, *db.WrappedMap, c
 	fc := clock.NewFake()
 	fc.Add(1 * time.Hour)
 
-	sa, err := sa.NewSQLStorageAuthority(dbMap, fc, log, metrics.NoopRegisterer, 1)
+	sa, err := sa.NewSQLStorageAuthority(dbMap, fc, log, metrics.NoopRegisterer, 1, 0, nil)
 	test.AssertNotError(t, err, "Failed to create SA")
 
 	cleanUp := test.ResetSATestDatabase(t)
@@ -177,9 +180,9 @@ func TestGenerateOCSPResponses(t *testing.T) {
 	earliest := fc.Now().Add(-time.Hour)
 
 	// We should have 2 stale responses now.
-	statuses, err := updater.findStaleOCSPResponses(earliest, 10)
+	queue, err := updater.findWork(earliest, 10)
 	test.AssertNotError(t, err, "Couldn't find stale responses")
-	test.AssertEquals(t, len(statuses), 2)
+	test.AssertEquals(t, queue.Len(), 2)
 
 	// Hacky test of parallelism: Make each request to the CA take 1 second, and
 	// produce 2 requests to the CA. If the pair of requests complete in about a
@@ -189,7 +192,7 @@ func TestGenerateOCSPResponses(t *testing.T) {
 	start := time.Now()
 	updater.ogc = &mockOCSP{time.Second}
 	updater.parallelGenerateOCSPRequests = 10
-	err = updater.generateOCSPResponses(ctx, statuses)
+	err = updater.generateOCSPResponses(ctx, queue)
 	test.AssertNotError(t, err, "Couldn't generate OCSP responses")
 	elapsed := time.Since(start)
 	if elapsed > 1500*time.Millisecond {
@@ -198,7 +201,7 @@ func TestGenerateOCSPResponses(t *testing.T) {
 
 	// generateOCSPResponses should have updated the ocspLastUpdate for each
 	// cert, so there shouldn't be any stale responses anymore.
-	statuses, err = updater.findStaleOCSPResponses(earliest, 10)
+	statuses, err := updater.findStaleOCSPResponses(earliest, 10)
 	test.AssertNotError(t, err, "Failed to find stale responses")
 	test.AssertEquals(t, len(statuses), 0)
 }
@@ -500,6 +503,158 @@ func TestIssuerInfo(t *testing.T) {
 	test.Assert(t, m.gotIssuer, "generateResponse didn't send issuer information and serial")
 }
 
+// statusDB is an ocspDB which answers the updater's queries for statuses
+// never signed with newStatuses, and for stale statuses with staleStatuses,
+// and records the updates it's asked to make. Updates to the serials in
+// changed match no rows, as if their status had changed since they were read.
+type statusDB struct {
+	newStatuses   []core.CertificateStatus
+	staleStatuses []core.CertificateStatus
+	changed       map[string]bool
+	queries       []string
+	mu            sync.Mutex
+	stored        []string
+}
+
+func (sdb *statusDB) Select(i interface{}, query string, args ...interface{}) ([]interface{}, error) {
+	sdb.queries = append(sdb.queries, query)
+	statuses := sdb.staleStatuses
+	if strings.Contains(query, "ocspLastUpdated <= :never") {
+		statuses = sdb.newStatuses
+	}
+	*i.(*[]core.CertificateStatus) = append([]core.CertificateStatus(nil), statuses...)
+	return nil, nil
+}
+
+func (sdb *statusDB) Exec(query string, args ...interface{}) (sql.Result, error) {
+	if strings.Contains(query, "SET ocspResponse") {
+		serial := args[2].(string)
+		if sdb.changed[serial] {
+			return driver.RowsAffected(0), nil
+		}
+		sdb.mu.Lock()
+		sdb.stored = append(sdb.stored, serial)
+		sdb.mu.Unlock()
+	}
+	return driver.RowsAffected(1), nil
+}
+
+// objectRecorder is an objectWriter which records the objects written to it,
+// failing if failWrites is set.
+type objectRecorder struct {
+	objects    map[string][]byte
+	failWrites bool
+}
+
+func (or *objectRecorder) Put(name string, body []byte, contentType string) error {
+	if or.failWrites {
+		return errors.New("broken bucket")
+	}
+	or.objects[name] = body
+	return nil
+}
+
+func TestStoreResponseObjects(t *testing.T) {
+	sdb := &statusDB{changed: map[string]bool{"revoked": true}}
+	updater, err := newUpdater(
+		metrics.NoopRegisterer,
+		clock.NewFake(),
+		sdb,
+		&mockOCSP{},
+		OCSPUpdaterConfig{
+			OldOCSPBatchSize: 1,
+			OldOCSPWindow:    cmd.ConfigDuration{Duration: time.Second},
+		},
+		blog.NewMock(),
+	)
+	test.AssertNotError(t, err, "Failed to create newUpdater")
+	objects := &objectRecorder{objects: map[string][]byte{}}
+	updater.objects = objects
+
+	// A stored response is also written to the bucket.
+	err = updater.storeResponse(&core.CertificateStatus{
+		Serial:       "good",
+		Status:       core.OCSPStatusGood,
+		OCSPResponse: []byte{1, 2, 3},
+	})
+	test.AssertNotError(t, err, "storeResponse failed")
+	test.AssertDeepEquals(t, objects.objects["good"], []byte{1, 2, 3})
+	test.AssertEquals(t, test.CountCounter(updater.objectsCounter.WithLabelValues("success")), 1)
+
+	// A response the status guard kept out of the database isn't written.
+	err = updater.storeResponse(&core.CertificateStatus{
+		Serial:       "revoked",
+		Status:       core.OCSPStatusGood,
+		OCSPResponse: []byte{4, 5, 6},
+	})
+	test.AssertNotError(t, err, "storeResponse failed")
+	_, ok := objects.objects["revoked"]
+	test.Assert(t, !ok, "response blocked by the status guard was written to the bucket")
+
+	// Failing to write to the bucket doesn't fail the store.
+	objects.failWrites = true
+	err = updater.storeResponse(&core.CertificateStatus{
+		Serial:       "other",
+		Status:       core.OCSPStatusGood,
+		OCSPResponse: []byte{7, 8, 9},
+	})
+	test.AssertNotError(t, err, "storeResponse failed when the bucket did")
+	test.AssertEquals(t, test.CountCounter(updater.objectsCounter.WithLabelValues("failed")), 1)
+	test.AssertDeepEquals(t, sdb.stored, []string{"good", "other"})
+}
+
+func TestWorkQueue(t *testing.T) {
+	fc := clock.NewFake()
+	fc.Set(time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC))
+	issuer := int64(1)
+	status := func(serial string, lastUpdated time.Time) core.CertificateStatus {
+		return core.CertificateStatus{
+			Serial:          serial,
+			Status:          core.OCSPStatusGood,
+			OCSPLastUpdated: lastUpdated,
+			NotAfter:        fc.Now().Add(time.Hour),
+			IssuerID:        &issuer,
+		}
+	}
+	sdb := &statusDB{
+		newStatuses: []core.CertificateStatus{status("new", time.Time{})},
+		staleStatuses: []core.CertificateStatus{
+			status("day", fc.Now().Add(-24*time.Hour)),
+			status("week", fc.Now().Add(-7*24*time.Hour)),
+			status("hour", fc.Now().Add(-time.Hour)),
+		},
+	}
+	updater, err := newUpdater(
+		metrics.NoopRegisterer,
+		fc,
+		sdb,
+		&mockOCSP{},
+		OCSPUpdaterConfig{
+			OldOCSPBatchSize: 3,
+			NewOCSPBatchSize: 1,
+			OldOCSPWindow:    cmd.ConfigDuration{Duration: time.Second},
+		},
+		blog.NewMock(),
+	)
+	test.AssertNotError(t, err, "Failed to create newUpdater")
+
+	// With a budget of their own, new certificates are found by a separate
+	// query, which the refresh query excludes them from.
+	queue, err := updater.findWork(fc.Now(), 3)
+	test.AssertNotError(t, err, "findWork failed")
+	test.AssertEquals(t, len(sdb.queries), 2)
+	test.AssertContains(t, sdb.queries[0], "ocspLastUpdated > :never")
+	test.AssertEquals(t, test.CountCounter(updater.queuedCounter.WithLabelValues("new")), 1)
+	test.AssertEquals(t, test.CountCounter(updater.queuedCounter.WithLabelValues("refresh")), 3)
+	test.AssertEquals(t, test.CountHistogramSamples(updater.queuedAgeHistogram), 3)
+
+	// The work is done in order of how soon each response goes stale.
+	updater.parallelGenerateOCSPRequests = 1
+	err = updater.generateOCSPResponses(ctx, queue)
+	test.AssertNotError(t, err, "generateOCSPResponses failed")
+	test.AssertDeepEquals(t, sdb.stored, []string{"new", "week", "day", "hour"})
+}
+
 type brokenDB struct{}
 
 func (bdb *brokenDB) Select(i interface{}, query string, args ...interface{}) ([]interface{}, error) {
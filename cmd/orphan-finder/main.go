@@ -25,7 +25,9 @@ import (
 	bgrpc "github.com/letsencrypt/boulder/grpc"
 	blog "github.com/letsencrypt/boulder/log"
 	"github.com/letsencrypt/boulder/metrics"
+	pubpb "github.com/letsencrypt/boulder/publisher/proto"
 	sapb "github.com/letsencrypt/boulder/sa/proto"
+	"github.com/prometheus/client_golang/prometheus"
 	"google.golang.org/grpc"
 )
 
@@ -36,10 +38,15 @@ name:
 usage:
   orphan-finder parse-ca-log --config <path> --log-file <path>
   orphan-finder parse-der --config <path> --der-file <path> --regID <registration-id>
+  orphan-finder watch-ca-log --config <path> --log-file <path> [--from-start]
 
 command descriptions:
   parse-ca-log    Parses boulder-ca logs to add multiple orphaned certificates
   parse-der       Parses a single orphaned DER certificate file and adds it to the database
+  watch-ca-log    Runs continuously, following a boulder-ca log and adding the orphaned certificates
+                  logged to it which are still missing from the database after a grace period, then
+                  submitting them to CT. Its progress is saved to watch.stateFile, if configured,
+                  and resumed from when it's restarted
 `
 
 type config struct {
@@ -52,6 +59,10 @@ type config struct {
 	// `test/config/ca.json` for the CA "backdate" value.
 	Backdate cmd.ConfigDuration
 	Features map[string]bool
+
+	// PublisherService and Watch are only used by the watch-ca-log command.
+	PublisherService *cmd.GRPCClientConfig
+	Watch            watchConfig
 }
 
 type certificateStorage interface {
@@ -276,7 +287,7 @@ func generateOCSP(ctx context.Context, ca ocspGenerator, certDER []byte) ([]byte
 	return ocspResponse.Response, nil
 }
 
-func setup(configFile string) (blog.Logger, core.StorageAuthority, capb.OCSPGeneratorClient) {
+func loadConfig(configFile string) config {
 	configJSON, err := ioutil.ReadFile(configFile)
 	cmd.FailOnError(err, "Failed to read config file")
 	var conf config
@@ -284,12 +295,14 @@ func setup(configFile string) (blog.Logger, core.StorageAuthority, capb.OCSPGene
 	cmd.FailOnError(err, "Failed to parse config file")
 	err = features.Set(conf.Features)
 	cmd.FailOnError(err, "Failed to set feature flags")
-	logger := cmd.NewLogger(conf.Syslog)
+	return conf
+}
 
+func setupClients(conf config, stats prometheus.Registerer) (core.StorageAuthority, capb.OCSPGeneratorClient, core.Publisher) {
 	tlsConfig, err := conf.TLS.Load()
 	cmd.FailOnError(err, "TLS config")
 
-	clientMetrics := bgrpc.NewClientMetrics(metrics.NoopRegisterer)
+	clientMetrics := bgrpc.NewClientMetrics(stats)
 	saConn, err := bgrpc.ClientSetup(conf.SAService, tlsConfig, clientMetrics, cmd.Clock())
 	cmd.FailOnError(err, "Failed to load credentials and create gRPC connection to SA")
 	sac := bgrpc.NewStorageAuthorityClient(sapb.NewStorageAuthorityClient(saConn))
@@ -298,7 +311,21 @@ func setup(configFile string) (blog.Logger, core.StorageAuthority, capb.OCSPGene
 	cmd.FailOnError(err, "Failed to load credentials and create gRPC connection to CA")
 	cac := capb.NewOCSPGeneratorClient(caConn)
 
+	var pubc core.Publisher
+	if conf.PublisherService != nil {
+		pubConn, err := bgrpc.ClientSetup(conf.PublisherService, tlsConfig, clientMetrics, cmd.Clock())
+		cmd.FailOnError(err, "Failed to load credentials and create gRPC connection to Publisher")
+		pubc = bgrpc.NewPublisherClientWrapper(pubpb.NewPublisherClient(pubConn))
+	}
+
 	backdateDuration = conf.Backdate.Duration
+	return sac, cac, pubc
+}
+
+func setup(configFile string) (blog.Logger, core.StorageAuthority, capb.OCSPGeneratorClient) {
+	conf := loadConfig(configFile)
+	logger := cmd.NewLogger(conf.Syslog)
+	sac, cac, _ := setupClients(conf, metrics.NoopRegisterer)
 	return logger, sac, cac
 }
 
@@ -314,6 +341,7 @@ func main() {
 	logPath := flagSet.String("log-file", "", "Path to boulder-ca log file to parse")
 	derPath := flagSet.String("der-file", "", "Path to DER certificate file")
 	regID := flagSet.Int64("regID", 0, "Registration ID of user who requested the certificate")
+	fromStart := flagSet.Bool("from-start", false, "Read the log file being watched from its start, rather than only what's appended to it")
 	err := flagSet.Parse(os.Args[2:])
 	cmd.FailOnError(err, "Error parsing flagset")
 
@@ -397,6 +425,43 @@ func main() {
 		}
 		cmd.FailOnError(err, "Failed to add certificate to database")
 
+	case "watch-ca-log":
+		if *logPath == "" {
+			usage()
+		}
+		conf := loadConfig(*configFile)
+		stats, logger := cmd.StatsAndLogging(conf.Syslog, conf.Watch.DebugAddr)
+		logger.Info(cmd.VersionString())
+		if conf.Watch.GracePeriod.Duration <= 0 || conf.Watch.PollInterval.Duration <= 0 || conf.Watch.MaxAttempts <= 0 {
+			cmd.Fail("watch.gracePeriod, watch.pollInterval and watch.maxAttempts must be positive")
+		}
+		if len(conf.Watch.CTLogs) > 0 && conf.PublisherService == nil {
+			cmd.Fail("publisherService must be configured to submit orphans to watch.ctLogs")
+		}
+		for _, l := range conf.Watch.CTLogs {
+			if l.TemporalSet != nil {
+				err := l.Setup()
+				cmd.FailOnError(err, "Failed to setup a temporal log set")
+			}
+		}
+		sa, ca, pub := setupClients(conf, stats)
+
+		watcher := newOrphanWatcher(sa, ca, pub, conf.Watch, logger, cmd.Clock(), stats)
+		state, err := loadWatchState(conf.Watch.StateFile)
+		cmd.FailOnError(err, "Failed to load state file")
+		var tailer *logTailer
+		if state != nil && !*fromStart {
+			tailer, err = resumeLogTailer(*logPath, state.Position)
+		} else {
+			tailer, err = newLogTailer(*logPath, *fromStart)
+		}
+		cmd.FailOnError(err, "Failed to open log file")
+		if state != nil {
+			watcher.restore(state)
+		}
+		go cmd.CatchSignals(logger, nil)
+		watcher.watch(tailer, conf.Watch.PollInterval.Duration)
+
 	default:
 		usage()
 	}
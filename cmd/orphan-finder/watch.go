@@ -0,0 +1,486 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/jmhodges/clock"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/letsencrypt/boulder/cmd"
+	"github.com/letsencrypt/boulder/core"
+	"github.com/letsencrypt/boulder/ctpolicy/ctconfig"
+	blog "github.com/letsencrypt/boulder/log"
+	pubpb "github.com/letsencrypt/boulder/publisher/proto"
+)
+
+// logTailer reads the lines appended to a log file, following it when it's
+// rotated or truncated.
+type logTailer struct {
+	path    string
+	file    *os.File
+	reader  *bufio.Reader
+	partial string
+	// offset is the offset in file of the end of the last complete line read.
+	offset int64
+}
+
+// tailPosition is how far a logTailer has read: the end of the last complete
+// line read from the file with the given device and inode numbers.
+type tailPosition struct {
+	Dev    uint64 `json:"dev"`
+	Inode  uint64 `json:"inode"`
+	Offset int64  `json:"offset"`
+}
+
+// newLogTailer opens the log file at path. Only lines appended after it's
+// opened are read, unless fromStart is true.
+func newLogTailer(path string, fromStart bool) (*logTailer, error) {
+	t := &logTailer{path: path}
+	whence := io.SeekEnd
+	if fromStart {
+		whence = io.SeekStart
+	}
+	err := t.open(0, whence)
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// resumeLogTailer opens the log file at path and continues reading it from
+// pos. If the file has been rotated or truncated since pos was recorded, the
+// new file is read from its start.
+func resumeLogTailer(path string, pos tailPosition) (*logTailer, error) {
+	t := &logTailer{path: path}
+	err := t.open(0, io.SeekStart)
+	if err != nil {
+		return nil, err
+	}
+	info, err := t.file.Stat()
+	if err != nil {
+		t.file.Close()
+		return nil, err
+	}
+	dev, inode := fileID(info)
+	if dev != pos.Dev || inode != pos.Inode || info.Size() < pos.Offset {
+		return t, nil
+	}
+	t.file.Close()
+	err = t.open(pos.Offset, io.SeekStart)
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (t *logTailer) open(offset int64, whence int) error {
+	f, err := os.Open(t.path)
+	if err != nil {
+		return err
+	}
+	t.offset, err = f.Seek(offset, whence)
+	if err != nil {
+		f.Close()
+		return err
+	}
+	t.file = f
+	t.reader = bufio.NewReader(f)
+	t.partial = ""
+	return nil
+}
+
+// position returns how far t has read, ignoring any partial line.
+func (t *logTailer) position() (tailPosition, error) {
+	info, err := t.file.Stat()
+	if err != nil {
+		return tailPosition{}, err
+	}
+	dev, inode := fileID(info)
+	return tailPosition{Dev: dev, Inode: inode, Offset: t.offset}, nil
+}
+
+// fileID returns the device and inode numbers of a file, which identify it
+// across restarts.
+func fileID(info os.FileInfo) (uint64, uint64) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, 0
+	}
+	return uint64(stat.Dev), uint64(stat.Ino)
+}
+
+// readLines returns the complete lines appended to the log file since it was
+// last called. If the file has been rotated or truncated, the rest of the old
+// file is read and then the new one from its start.
+func (t *logTailer) readLines() ([]string, error) {
+	var lines []string
+	for {
+		s, err := t.reader.ReadString('\n')
+		t.partial += s
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return lines, err
+		}
+		lines = append(lines, strings.TrimSuffix(t.partial, "\n"))
+		t.offset += int64(len(t.partial))
+		t.partial = ""
+	}
+	rotated, err := t.rotated()
+	if err != nil || !rotated {
+		return lines, err
+	}
+	t.file.Close()
+	err = t.open(0, io.SeekStart)
+	if err != nil {
+		return lines, err
+	}
+	more, err := t.readLines()
+	return append(lines, more...), err
+}
+
+// rotated returns true if the path now names a different file to the one
+// being read, or if the file has been truncated.
+func (t *logTailer) rotated() (bool, error) {
+	pathInfo, err := os.Stat(t.path)
+	if os.IsNotExist(err) {
+		// The file has been moved, but not yet replaced.
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	fileInfo, err := t.file.Stat()
+	if err != nil {
+		return false, err
+	}
+	if !os.SameFile(pathInfo, fileInfo) {
+		return true, nil
+	}
+	offset, err := t.file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return false, err
+	}
+	return fileInfo.Size() < offset, nil
+}
+
+// pendingOrphan is an orphan found in the CA's log which hasn't yet been
+// found in, or added to, the database.
+type pendingOrphan struct {
+	line     string
+	cert     *x509.Certificate
+	typ      orphanType
+	due      time.Time
+	attempts int
+}
+
+// watchConfig configures the watch-ca-log command.
+type watchConfig struct {
+	// DebugAddr is the address on which metrics are served.
+	DebugAddr string
+	// GracePeriod is how long after an orphan is logged the database is
+	// checked for it, giving any store which was in flight when it was logged
+	// time to land.
+	GracePeriod cmd.ConfigDuration
+	// PollInterval is how often the log file is read.
+	PollInterval cmd.ConfigDuration
+	// MaxAttempts is how many times adding an orphan to the database is tried
+	// before giving up on it. Retries back off from GracePeriod to an hour.
+	MaxAttempts int
+	// CTLogs are the logs to which recovered orphans are submitted, using the
+	// PublisherService.
+	CTLogs []ctconfig.LogDescription
+	// StateFile, if set, is where how far the log file has been read and the
+	// orphans still pending are saved after each poll, so that they survive
+	// a restart.
+	StateFile string
+}
+
+// watchState is what's saved to the StateFile.
+type watchState struct {
+	Position tailPosition         `json:"position"`
+	Pending  []pendingOrphanState `json:"pending"`
+}
+
+// pendingOrphanState is a pendingOrphan as saved to the StateFile. The
+// certificate is parsed from the line again when it's loaded.
+type pendingOrphanState struct {
+	Line     string    `json:"line"`
+	Due      time.Time `json:"due"`
+	Attempts int       `json:"attempts"`
+}
+
+// loadWatchState reads the state saved to path. It returns nil if path is
+// empty or doesn't exist yet.
+func loadWatchState(path string) (*watchState, error) {
+	if path == "" {
+		return nil, nil
+	}
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var state watchState
+	err = json.Unmarshal(data, &state)
+	if err != nil {
+		return nil, fmt.Errorf("parsing state file %q: %s", path, err)
+	}
+	return &state, nil
+}
+
+// orphanWatcher recovers the orphans found in the lines of the CA's log,
+// once they've been missing from the database for a grace period, and
+// submits them to CT.
+type orphanWatcher struct {
+	sa     certificateStorage
+	ca     ocspGenerator
+	pub    core.Publisher
+	ctLogs []ctconfig.LogDescription
+	log    blog.Logger
+	clk    clock.Clock
+
+	gracePeriod time.Duration
+	maxAttempts int
+	stateFile   string
+	pending     []*pendingOrphan
+
+	found         *prometheus.CounterVec
+	recovered     *prometheus.CounterVec
+	pendingGauge  prometheus.Gauge
+	ctSubmissions *prometheus.CounterVec
+}
+
+func newOrphanWatcher(
+	sa certificateStorage,
+	ca ocspGenerator,
+	pub core.Publisher,
+	conf watchConfig,
+	logger blog.Logger,
+	clk clock.Clock,
+	stats prometheus.Registerer,
+) *orphanWatcher {
+	found := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "orphans_found",
+		Help: "Number of orphans found in the CA's log, by type",
+	}, []string{"type"})
+	stats.MustRegister(found)
+	recovered := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "orphans_recovered",
+		Help: "Number of orphans whose recovery finished, by type and result: added, already_stored or abandoned",
+	}, []string{"type", "result"})
+	stats.MustRegister(recovered)
+	pendingGauge := prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "orphans_pending",
+		Help: "Number of orphans waiting to be checked for, or added to, the database",
+	})
+	stats.MustRegister(pendingGauge)
+	ctSubmissions := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "orphan_ct_submissions",
+		Help: "Number of submissions of recovered orphans to CT logs, by result",
+	}, []string{"result"})
+	stats.MustRegister(ctSubmissions)
+
+	return &orphanWatcher{
+		sa:            sa,
+		ca:            ca,
+		pub:           pub,
+		ctLogs:        conf.CTLogs,
+		log:           logger,
+		clk:           clk,
+		gracePeriod:   conf.GracePeriod.Duration,
+		maxAttempts:   conf.MaxAttempts,
+		stateFile:     conf.StateFile,
+		found:         found,
+		recovered:     recovered,
+		pendingGauge:  pendingGauge,
+		ctSubmissions: ctSubmissions,
+	}
+}
+
+// addLine queues the orphan in a line of the CA's log, if there is one, to be
+// recovered once the grace period has passed.
+func (w *orphanWatcher) addLine(line string) {
+	if !strings.Contains(line, fmt.Sprintf("orphaning %s", certOrphan)) &&
+		!strings.Contains(line, fmt.Sprintf("orphaning %s", precertOrphan)) {
+		return
+	}
+	orphan, err := w.parseOrphan(line)
+	if err != nil {
+		w.log.AuditErr(err.Error())
+		return
+	}
+	orphan.due = w.clk.Now().Add(w.gracePeriod)
+	w.found.WithLabelValues(orphan.typ.String()).Inc()
+	w.pending = append(w.pending, orphan)
+	w.pendingGauge.Set(float64(len(w.pending)))
+}
+
+// parseOrphan returns the orphan logged in line.
+func (w *orphanWatcher) parseOrphan(line string) (*pendingOrphan, error) {
+	parsed, err := parseLogLine(line, w.log)
+	if err != nil {
+		return nil, fmt.Errorf("Couldn't parse log line: %s", err)
+	}
+	cert, err := x509.ParseCertificate(parsed.certDER)
+	if err != nil {
+		return nil, fmt.Errorf("Couldn't parse orphan DER: %s, [%s]", err, line)
+	}
+	return &pendingOrphan{
+		line: line,
+		cert: cert,
+		typ:  orphanTypeForCert(cert),
+	}, nil
+}
+
+// restore queues the orphans which were pending when state was saved.
+func (w *orphanWatcher) restore(state *watchState) {
+	for _, s := range state.Pending {
+		orphan, err := w.parseOrphan(s.Line)
+		if err != nil {
+			w.log.AuditErr(err.Error())
+			continue
+		}
+		orphan.due = s.Due
+		orphan.attempts = s.Attempts
+		w.pending = append(w.pending, orphan)
+	}
+	w.pendingGauge.Set(float64(len(w.pending)))
+}
+
+// saveState writes how far tailer has read, and the orphans still pending,
+// to the StateFile, replacing it atomically.
+func (w *orphanWatcher) saveState(tailer *logTailer) error {
+	if w.stateFile == "" {
+		return nil
+	}
+	pos, err := tailer.position()
+	if err != nil {
+		return err
+	}
+	state := watchState{Position: pos, Pending: []pendingOrphanState{}}
+	for _, orphan := range w.pending {
+		state.Pending = append(state.Pending, pendingOrphanState{
+			Line:     orphan.line,
+			Due:      orphan.due,
+			Attempts: orphan.attempts,
+		})
+	}
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(w.stateFile), filepath.Base(w.stateFile)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	_, err = tmp.Write(data)
+	if err == nil {
+		err = tmp.Sync()
+	}
+	closeErr := tmp.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), w.stateFile)
+}
+
+// processDue tries to recover each orphan whose grace period, or retry
+// backoff, has passed. Orphans which couldn't be added are retried later,
+// until they've been tried maxAttempts times.
+func (w *orphanWatcher) processDue() {
+	now := w.clk.Now()
+	var remaining []*pendingOrphan
+	for _, orphan := range w.pending {
+		if orphan.due.After(now) {
+			remaining = append(remaining, orphan)
+			continue
+		}
+		_, added, _ := storeParsedLogLine(w.sa, w.ca, w.log, orphan.line)
+		if added {
+			w.recovered.WithLabelValues(orphan.typ.String(), "added").Inc()
+			w.submitToCT(orphan)
+			continue
+		}
+		_, _, err := checkDER(w.sa, orphan.cert.Raw)
+		if err == errAlreadyExists {
+			w.recovered.WithLabelValues(orphan.typ.String(), "already_stored").Inc()
+			continue
+		}
+		orphan.attempts++
+		if orphan.attempts >= w.maxAttempts {
+			w.log.AuditErrf("Giving up on adding %s orphan after %d attempts, [%s]", orphan.typ, orphan.attempts, orphan.line)
+			w.recovered.WithLabelValues(orphan.typ.String(), "abandoned").Inc()
+			continue
+		}
+		orphan.due = now.Add(core.RetryBackoff(orphan.attempts, w.gracePeriod, time.Hour, 2))
+		remaining = append(remaining, orphan)
+	}
+	w.pending = remaining
+	w.pendingGauge.Set(float64(len(w.pending)))
+}
+
+// submitToCT submits a recovered orphan to each of the CT logs, since it may
+// never have been submitted after it was orphaned.
+func (w *orphanWatcher) submitToCT(orphan *pendingOrphan) {
+	for _, ld := range w.ctLogs {
+		uri, key, err := ld.Info(orphan.cert.NotAfter)
+		if err != nil {
+			w.log.Errf("unable to get log info: %s", err)
+			w.ctSubmissions.WithLabelValues("failure").Inc()
+			continue
+		}
+		_, err = w.pub.SubmitToSingleCTWithResult(context.Background(), &pubpb.Request{
+			LogURL:       uri,
+			LogPublicKey: key,
+			Der:          orphan.cert.Raw,
+			Precert:      orphan.typ == precertOrphan,
+		})
+		if err != nil {
+			w.log.Warningf("ct submission of orphaned %s %s to log %q failed: %s",
+				orphan.typ, core.SerialToString(orphan.cert.SerialNumber), uri, err)
+			w.ctSubmissions.WithLabelValues("failure").Inc()
+			continue
+		}
+		w.ctSubmissions.WithLabelValues("success").Inc()
+	}
+}
+
+// watch reads the lines appended to the CA's log every poll interval,
+// recovering the orphans in them, until the process exits. After each poll,
+// its progress is saved to the StateFile.
+func (w *orphanWatcher) watch(tailer *logTailer, pollInterval time.Duration) {
+	for {
+		lines, err := tailer.readLines()
+		if err != nil {
+			w.log.Errf("Reading CA log file: %s", err)
+		}
+		for _, line := range lines {
+			w.addLine(line)
+		}
+		w.processDue()
+		err = w.saveState(tailer)
+		if err != nil {
+			w.log.Errf("Saving state file: %s", err)
+		}
+		w.clk.Sleep(pollInterval)
+	}
+}
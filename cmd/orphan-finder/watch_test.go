@@ -0,0 +1,287 @@
+package main
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jmhodges/clock"
+
+	"github.com/letsencrypt/boulder/cmd"
+	"github.com/letsencrypt/boulder/ctpolicy/ctconfig"
+	"github.com/letsencrypt/boulder/metrics"
+	pubpb "github.com/letsencrypt/boulder/publisher/proto"
+	"github.com/letsencrypt/boulder/test"
+)
+
+func appendToFile(t *testing.T, path, data string) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	test.AssertNotError(t, err, "opening log file")
+	defer f.Close()
+	_, err = f.WriteString(data)
+	test.AssertNotError(t, err, "writing log file")
+}
+
+func TestLogTailer(t *testing.T) {
+	dir, err := ioutil.TempDir("", "orphan-finder")
+	test.AssertNotError(t, err, "creating temp dir")
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "ca.log")
+	appendToFile(t, path, "old line\n")
+
+	// Lines already in the file are skipped, and partial lines are held back
+	// until they're complete.
+	tailer, err := newLogTailer(path, false)
+	test.AssertNotError(t, err, "newLogTailer failed")
+	appendToFile(t, path, "one\ntw")
+	lines, err := tailer.readLines()
+	test.AssertNotError(t, err, "readLines failed")
+	test.AssertDeepEquals(t, lines, []string{"one"})
+	appendToFile(t, path, "o\n")
+	lines, err = tailer.readLines()
+	test.AssertNotError(t, err, "readLines failed")
+	test.AssertDeepEquals(t, lines, []string{"two"})
+
+	// When the file is rotated, the new file is read from its start.
+	test.AssertNotError(t, os.Rename(path, path+".1"), "rotating log file")
+	lines, err = tailer.readLines()
+	test.AssertNotError(t, err, "readLines failed")
+	test.AssertEquals(t, len(lines), 0)
+	appendToFile(t, path, "three\n")
+	lines, err = tailer.readLines()
+	test.AssertNotError(t, err, "readLines failed")
+	test.AssertDeepEquals(t, lines, []string{"three"})
+
+	// And so is a truncated file.
+	test.AssertNotError(t, ioutil.WriteFile(path, []byte("4\n"), 0600), "truncating log file")
+	lines, err = tailer.readLines()
+	test.AssertNotError(t, err, "readLines failed")
+	test.AssertDeepEquals(t, lines, []string{"4"})
+
+	// Unless asked to read from the start.
+	tailer, err = newLogTailer(path, true)
+	test.AssertNotError(t, err, "newLogTailer failed")
+	lines, err = tailer.readLines()
+	test.AssertNotError(t, err, "readLines failed")
+	test.AssertDeepEquals(t, lines, []string{"4"})
+}
+
+// flakySA is a mockSA whose AddCertificate fails the first failAdds times
+// it's called.
+type flakySA struct {
+	*mockSA
+	failAdds int
+}
+
+func (f *flakySA) AddCertificate(ctx context.Context, der []byte, regID int64, ocsp []byte, issued *time.Time) (string, error) {
+	if f.failAdds > 0 {
+		f.failAdds--
+		return "", errors.New("SA is down")
+	}
+	return f.mockSA.AddCertificate(ctx, der, regID, ocsp, issued)
+}
+
+// countingPub is a mock publisher which records the logs submitted to.
+type countingPub struct {
+	logs []string
+}
+
+func (p *countingPub) SubmitToSingleCTWithResult(_ context.Context, req *pubpb.Request) (*pubpb.Result, error) {
+	p.logs = append(p.logs, req.LogURL)
+	return &pubpb.Result{}, nil
+}
+
+func (p *countingPub) QueueFinalCert(_ context.Context, _ *pubpb.FinalCertRequest) (*pubpb.FinalCertQueued, error) {
+	return &pubpb.FinalCertQueued{}, nil
+}
+
+func orphanLine(t *testing.T, serial int64) string {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	test.AssertNotError(t, err, "generating key")
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "example.com"},
+		NotBefore:    time.Date(2015, 3, 4, 5, 0, 0, 0, time.UTC),
+		NotAfter:     time.Date(2015, 6, 4, 5, 0, 0, 0, time.UTC),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	test.AssertNotError(t, err, "creating certificate")
+	return fmt.Sprintf("boulder-ca[pid]: [AUDIT] Failed RPC to store at SA, orphaning certificate: "+
+		"serial=[unused], cert=[%s], issuerID=[1], regID=[1001], orderID=[0], err=[context deadline exceeded]",
+		hex.EncodeToString(der))
+}
+
+func TestOrphanWatcher(t *testing.T) {
+	fc := clock.NewFake()
+	sa := &flakySA{mockSA: &mockSA{}, failAdds: 1}
+	pub := &countingPub{}
+	w := newOrphanWatcher(sa, &mockCA{}, pub, watchConfig{
+		GracePeriod: cmd.ConfigDuration{Duration: time.Minute},
+		MaxAttempts: 2,
+		CTLogs:      []ctconfig.LogDescription{{URI: "log", Key: "key"}},
+	}, log, fc, metrics.NoopRegisterer)
+
+	// Lines which aren't orphans are ignored.
+	w.addLine("boulder-ca[pid]: issued certificate")
+	test.AssertEquals(t, len(w.pending), 0)
+
+	// Orphans aren't looked for until the grace period has passed.
+	w.addLine(orphanLine(t, 1))
+	test.AssertEquals(t, len(w.pending), 1)
+	test.AssertEquals(t, test.CountCounter(w.found.WithLabelValues("certificate")), 1)
+	fc.Add(30 * time.Second)
+	w.processDue()
+	test.AssertEquals(t, len(w.pending), 1)
+	test.AssertEquals(t, len(sa.certificates), 0)
+
+	// An orphan which can't be added is retried after a backoff.
+	fc.Add(30 * time.Second)
+	w.processDue()
+	test.AssertEquals(t, len(w.pending), 1)
+	test.AssertEquals(t, w.pending[0].attempts, 1)
+	w.processDue()
+	test.AssertEquals(t, len(w.pending), 1)
+
+	// Once it's added, it's submitted to CT.
+	fc.Add(time.Hour)
+	w.processDue()
+	test.AssertEquals(t, len(w.pending), 0)
+	test.AssertEquals(t, len(sa.certificates), 1)
+	test.AssertEquals(t, test.CountCounter(w.recovered.WithLabelValues("certificate", "added")), 1)
+	test.AssertDeepEquals(t, pub.logs, []string{"log"})
+
+	// An orphan which was stored by the time the grace period passed isn't
+	// added or submitted again.
+	w.addLine(orphanLine(t, 1))
+	fc.Add(time.Minute)
+	w.processDue()
+	test.AssertEquals(t, len(w.pending), 0)
+	test.AssertEquals(t, len(sa.certificates), 1)
+	test.AssertEquals(t, test.CountCounter(w.recovered.WithLabelValues("certificate", "already_stored")), 1)
+	test.AssertEquals(t, len(pub.logs), 1)
+
+	// An orphan which still can't be added after MaxAttempts is abandoned.
+	sa.failAdds = 2
+	w.addLine(orphanLine(t, 2))
+	fc.Add(time.Minute)
+	w.processDue()
+	fc.Add(time.Hour)
+	w.processDue()
+	test.AssertEquals(t, len(w.pending), 0)
+	test.AssertEquals(t, len(sa.certificates), 1)
+	test.AssertEquals(t, test.CountCounter(w.recovered.WithLabelValues("certificate", "abandoned")), 1)
+}
+
+func TestResumeLogTailer(t *testing.T) {
+	dir, err := ioutil.TempDir("", "orphan-finder")
+	test.AssertNotError(t, err, "creating temp dir")
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "ca.log")
+	appendToFile(t, path, "one\n")
+
+	// The position doesn't include a partial line.
+	tailer, err := newLogTailer(path, true)
+	test.AssertNotError(t, err, "newLogTailer failed")
+	appendToFile(t, path, "tw")
+	lines, err := tailer.readLines()
+	test.AssertNotError(t, err, "readLines failed")
+	test.AssertDeepEquals(t, lines, []string{"one"})
+	pos, err := tailer.position()
+	test.AssertNotError(t, err, "position failed")
+	test.AssertEquals(t, pos.Offset, int64(4))
+
+	// Resuming reads on from the position, including lines appended while
+	// nothing was reading the file.
+	appendToFile(t, path, "o\nthree\n")
+	tailer, err = resumeLogTailer(path, pos)
+	test.AssertNotError(t, err, "resumeLogTailer failed")
+	lines, err = tailer.readLines()
+	test.AssertNotError(t, err, "readLines failed")
+	test.AssertDeepEquals(t, lines, []string{"two", "three"})
+
+	// If the file's been rotated since, the new file is read from its start.
+	test.AssertNotError(t, os.Rename(path, path+".1"), "rotating log file")
+	appendToFile(t, path, "four\n")
+	tailer, err = resumeLogTailer(path, pos)
+	test.AssertNotError(t, err, "resumeLogTailer failed")
+	lines, err = tailer.readLines()
+	test.AssertNotError(t, err, "readLines failed")
+	test.AssertDeepEquals(t, lines, []string{"four"})
+
+	// And so is a file which has been truncated.
+	pos, err = tailer.position()
+	test.AssertNotError(t, err, "position failed")
+	test.AssertNotError(t, ioutil.WriteFile(path, []byte("5\n"), 0600), "truncating log file")
+	tailer, err = resumeLogTailer(path, pos)
+	test.AssertNotError(t, err, "resumeLogTailer failed")
+	lines, err = tailer.readLines()
+	test.AssertNotError(t, err, "readLines failed")
+	test.AssertDeepEquals(t, lines, []string{"5"})
+}
+
+func TestWatchState(t *testing.T) {
+	dir, err := ioutil.TempDir("", "orphan-finder")
+	test.AssertNotError(t, err, "creating temp dir")
+	defer os.RemoveAll(dir)
+	logPath := filepath.Join(dir, "ca.log")
+	statePath := filepath.Join(dir, "state.json")
+	appendToFile(t, logPath, orphanLine(t, 1)+"\n")
+
+	state, err := loadWatchState(statePath)
+	test.AssertNotError(t, err, "loadWatchState failed")
+	test.Assert(t, state == nil, "expected no state before it's saved")
+
+	fc := clock.NewFake()
+	conf := watchConfig{
+		GracePeriod: cmd.ConfigDuration{Duration: time.Minute},
+		MaxAttempts: 3,
+		StateFile:   statePath,
+	}
+	sa := &flakySA{mockSA: &mockSA{}, failAdds: 1}
+	w := newOrphanWatcher(sa, &mockCA{}, &countingPub{}, conf, log, fc, metrics.NoopRegisterer)
+	tailer, err := newLogTailer(logPath, true)
+	test.AssertNotError(t, err, "newLogTailer failed")
+	lines, err := tailer.readLines()
+	test.AssertNotError(t, err, "readLines failed")
+	for _, line := range lines {
+		w.addLine(line)
+	}
+	fc.Add(time.Minute)
+	w.processDue()
+	test.AssertEquals(t, len(w.pending), 1)
+	test.AssertNotError(t, w.saveState(tailer), "saveState failed")
+	due := w.pending[0].due
+
+	// A restarted watcher picks up the pending orphan, with its attempts and
+	// backoff, and doesn't read its line again.
+	state, err = loadWatchState(statePath)
+	test.AssertNotError(t, err, "loadWatchState failed")
+	test.AssertNotNil(t, state, "expected saved state")
+	w = newOrphanWatcher(sa, &mockCA{}, &countingPub{}, conf, log, fc, metrics.NoopRegisterer)
+	w.restore(state)
+	test.AssertEquals(t, len(w.pending), 1)
+	test.AssertEquals(t, w.pending[0].attempts, 1)
+	test.Assert(t, w.pending[0].due.Equal(due), "expected due time to be restored")
+	tailer, err = resumeLogTailer(logPath, state.Position)
+	test.AssertNotError(t, err, "resumeLogTailer failed")
+	lines, err = tailer.readLines()
+	test.AssertNotError(t, err, "readLines failed")
+	test.AssertEquals(t, len(lines), 0)
+
+	fc.Add(time.Hour)
+	w.processDue()
+	test.AssertEquals(t, len(w.pending), 0)
+	test.AssertEquals(t, len(sa.certificates), 1)
+}
@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+
+	blog "github.com/letsencrypt/boulder/log"
+)
+
+// ReloadFunc validates a reloadable section of a newly read config, which is
+// passed as returned by the ConfigReloader's newConfig function, and prepares
+// everything needed to use it. It returns a function which puts the section
+// into use, and which must not fail.
+type ReloadFunc func(config interface{}) (apply func(), err error)
+
+type reloadableSection struct {
+	name   string
+	reload ReloadFunc
+}
+
+// ConfigReloader re-reads a service's config file when the service receives
+// SIGHUP, or when its debug server receives a POST to /debug/reload, and
+// applies the sections of the new config which the service has registered as
+// reloadable. Every section is validated and prepared before any is applied,
+// so a config which is invalid in any reloadable section is rejected as a
+// whole. Changes to other sections take effect at the next restart.
+type ConfigReloader struct {
+	filename  string
+	newConfig func() interface{}
+	log       blog.Logger
+
+	mu       sync.Mutex
+	sections []reloadableSection
+}
+
+// activeReloader holds the *ConfigReloader which handles SIGHUP and
+// /debug/reload, if one has been started.
+var activeReloader atomic.Value
+
+// NewConfigReloader returns a ConfigReloader which reads filename into the
+// value returned by newConfig, which must be a pointer to a new, empty config
+// struct of the service's config type.
+func NewConfigReloader(filename string, newConfig func() interface{}, logger blog.Logger) *ConfigReloader {
+	return &ConfigReloader{
+		filename:  filename,
+		newConfig: newConfig,
+		log:       logger,
+	}
+}
+
+// Register adds a reloadable config section. The name is used in logs and
+// errors, and is conventionally the path to the section in the config file.
+func (cr *ConfigReloader) Register(name string, reload ReloadFunc) {
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+	cr.sections = append(cr.sections, reloadableSection{name, reload})
+}
+
+// Reload reads the config file and, if every registered section of it is
+// valid, applies them all and records the file as the loaded config.
+// Otherwise none are applied and an error describing each invalid section is
+// returned.
+func (cr *ConfigReloader) Reload() error {
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+	config := cr.newConfig()
+	configData, secrets, err := readConfigFile(cr.filename, config)
+	if err != nil {
+		return fmt.Errorf("reading config file %q: %s", cr.filename, err)
+	}
+
+	var problems, names []string
+	var applies []func()
+	for _, section := range cr.sections {
+		apply, err := section.reload(config)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %s", section.name, err))
+			continue
+		}
+		applies = append(applies, apply)
+		names = append(names, section.name)
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("not reloading config, invalid sections: %s", strings.Join(problems, "; "))
+	}
+	for _, apply := range applies {
+		apply()
+	}
+	// The secrets of the config being replaced are no longer in use, but
+	// until now they might have been, so only now are they dropped.
+	blog.SetRedactedSecrets(cr.filename, secrets)
+	recordConfig(cr.filename, configData)
+	cr.log.Infof("Reloaded config sections from %q: %s", cr.filename, strings.Join(names, ", "))
+	return nil
+}
+
+// Start makes the reloader reload the config whenever the process receives
+// SIGHUP, or a POST to /debug/reload. Once it's called, CatchSignals no longer
+// treats SIGHUP as a request to shut down. Only one reloader may be started in
+// a process.
+func (cr *ConfigReloader) Start() {
+	activeReloader.Store(cr)
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGHUP)
+	go func() {
+		for range sigChan {
+			cr.log.Info("Caught SIGHUP, reloading config")
+			err := cr.Reload()
+			if err != nil {
+				cr.log.Errf("Reloading config: %s", err)
+			}
+		}
+	}()
+}
+
+// reloadHandler serves /debug/reload, reloading the config using the
+// started ConfigReloader, if there is one.
+func reloadHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "reloading requires a POST", http.StatusMethodNotAllowed)
+		return
+	}
+	cr, ok := activeReloader.Load().(*ConfigReloader)
+	if !ok {
+		http.Error(w, "this service doesn't support reloading its config", http.StatusNotFound)
+		return
+	}
+	err := cr.Reload()
+	if err != nil {
+		cr.log.Errf("Reloading config: %s", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	fmt.Fprintln(w, "Config reloaded")
+}
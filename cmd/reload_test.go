@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	blog "github.com/letsencrypt/boulder/log"
+	"github.com/letsencrypt/boulder/test"
+)
+
+type reloadTestConfig struct {
+	Chain    string
+	Profile  string
+	Listener string
+}
+
+func TestConfigReloader(t *testing.T) {
+	dir, err := ioutil.TempDir("", "reload")
+	test.AssertNotError(t, err, "creating temp dir")
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "config.json")
+	writeConfig := func(contents string) {
+		test.AssertNotError(t, ioutil.WriteFile(filename, []byte(contents), 0600), "writing config")
+	}
+
+	var chain, profile string
+	cr := NewConfigReloader(filename, func() interface{} { return &reloadTestConfig{} }, blog.NewMock())
+	cr.Register("Chain", func(c interface{}) (func(), error) {
+		newChain := c.(*reloadTestConfig).Chain
+		return func() { chain = newChain }, nil
+	})
+	cr.Register("Profile", func(c interface{}) (func(), error) {
+		newProfile := c.(*reloadTestConfig).Profile
+		if newProfile == "" {
+			return nil, errors.New("Profile must not be empty")
+		}
+		return func() { profile = newProfile }, nil
+	})
+
+	writeConfig(`{"chain": "a", "profile": "one", "listener": ":80"}`)
+	test.AssertNotError(t, cr.Reload(), "Reload failed")
+	test.AssertEquals(t, chain, "a")
+	test.AssertEquals(t, profile, "one")
+	loadedHash := currentBuildInfo().ConfigHash
+	test.AssertNotEquals(t, loadedHash, "")
+
+	// If any section is invalid, none are applied.
+	writeConfig(`{"chain": "b", "profile": ""}`)
+	err = cr.Reload()
+	test.AssertError(t, err, "Reload accepted an invalid section")
+	test.AssertContains(t, err.Error(), "Profile: Profile must not be empty")
+	test.AssertEquals(t, chain, "a")
+	// And the config that was rejected isn't reported as loaded.
+	test.AssertEquals(t, currentBuildInfo().ConfigHash, loadedHash)
+
+	writeConfig(`{"chain": `)
+	test.AssertError(t, cr.Reload(), "Reload accepted malformed JSON")
+	test.AssertEquals(t, chain, "a")
+	test.AssertEquals(t, currentBuildInfo().ConfigHash, loadedHash)
+
+	// The debug endpoint reloads using the started reloader.
+	req := httptest.NewRequest("POST", "/debug/reload", nil)
+	rec := httptest.NewRecorder()
+	reloadHandler(rec, req)
+	test.AssertEquals(t, rec.Code, http.StatusNotFound)
+
+	activeReloader.Store(cr)
+	writeConfig(`{"chain": "c", "profile": "two"}`)
+	rec = httptest.NewRecorder()
+	reloadHandler(rec, httptest.NewRequest("GET", "/debug/reload", nil))
+	test.AssertEquals(t, rec.Code, http.StatusMethodNotAllowed)
+	rec = httptest.NewRecorder()
+	reloadHandler(rec, req)
+	test.AssertEquals(t, rec.Code, http.StatusOK)
+	test.AssertEquals(t, chain, "c")
+	test.AssertEquals(t, profile, "two")
+	test.AssertNotEquals(t, currentBuildInfo().ConfigHash, loadedHash)
+}
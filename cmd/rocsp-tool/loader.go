@@ -0,0 +1,344 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jmhodges/clock"
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/crypto/ocsp"
+	"google.golang.org/grpc"
+
+	capb "github.com/letsencrypt/boulder/ca/proto"
+	"github.com/letsencrypt/boulder/core"
+	corepb "github.com/letsencrypt/boulder/core/proto"
+	bgrpc "github.com/letsencrypt/boulder/grpc"
+	blog "github.com/letsencrypt/boulder/log"
+	sapb "github.com/letsencrypt/boulder/sa/proto"
+)
+
+// progressInterval is how often overall progress is logged while loading.
+var progressInterval = 30 * time.Second
+
+// partitionSize is the number of certificateStatus row IDs covered by each
+// partition. It is fixed, rather than derived from the number of rows, so that
+// partitions recorded in a checkpoint still mean the same thing when loading
+// is resumed after more certificates have been issued.
+var partitionSize int64 = 1000000
+
+// statusSource is the subset of sapb.StorageAuthorityClient used by the
+// loader, to allow mocking in tests.
+type statusSource interface {
+	GetMaxCertificateStatusID(ctx context.Context, in *corepb.Empty, opts ...grpc.CallOption) (*sapb.CertificateStatusID, error)
+	GetCertificateStatusesByID(ctx context.Context, in *sapb.CertificateStatusesByIDRequest, opts ...grpc.CallOption) (sapb.StorageAuthority_GetCertificateStatusesByIDClient, error)
+}
+
+// responseStorer is the subset of *rocsp.Client used by the loader, to allow
+// mocking in tests.
+type responseStorer interface {
+	StoreResponse(ctx context.Context, serial string, response []byte, ttl time.Duration) error
+}
+
+// checkpoint records how far loading has progressed in each partition, so that
+// an interrupted load can be resumed. Partitions are identified by the row ID
+// after which they start, in decimal.
+type checkpoint struct {
+	// LastIDs maps each partition to the ID of the last row loaded in it.
+	LastIDs map[string]int64 `json:"lastIDs"`
+	// Done is the set of partitions which have been completely loaded.
+	Done map[string]bool `json:"done"`
+}
+
+// loader loads OCSP responses for the certificates in the certificateStatus
+// table into Redis. The rows are read from the SA, split into partitions of
+// partitionSize row IDs, which are loaded in parallel.
+type loader struct {
+	sa          statusSource
+	storer      responseStorer
+	signer      capb.OCSPGeneratorClient
+	clk         clock.Clock
+	log         blog.Logger
+	batchSize   int
+	parallelism int
+	minTTL      time.Duration
+
+	results *prometheus.CounterVec
+
+	// Counts of rows by result, for progress logging.
+	loaded, signed, skipped, failed int64
+
+	checkpointMu   sync.Mutex
+	checkpoint     checkpoint
+	checkpointFile string
+}
+
+func newLoader(
+	sa statusSource,
+	storer responseStorer,
+	signer capb.OCSPGeneratorClient,
+	clk clock.Clock,
+	log blog.Logger,
+	stats prometheus.Registerer,
+	batchSize int,
+	parallelism int,
+	minTTL time.Duration,
+) *loader {
+	results := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "rocsp_tool_load_results",
+		Help: "Count of certificateStatus rows processed by rocsp-tool load, by result (loaded, signed, skipped, failed)",
+	}, []string{"result"})
+	stats.MustRegister(results)
+	return &loader{
+		sa:          sa,
+		storer:      storer,
+		signer:      signer,
+		clk:         clk,
+		log:         log,
+		batchSize:   batchSize,
+		parallelism: parallelism,
+		minTTL:      minTTL,
+		results:     results,
+		checkpoint: checkpoint{
+			LastIDs: make(map[string]int64),
+			Done:    make(map[string]bool),
+		},
+	}
+}
+
+// partitions returns the row ID after which each partition starts, for a
+// table whose largest row ID is maxID.
+func partitions(maxID int64) []int64 {
+	var starts []int64
+	for start := int64(0); start < maxID; start += partitionSize {
+		starts = append(starts, start)
+	}
+	return starts
+}
+
+// loadAll loads every partition, resuming from checkpointFile if it exists
+// and recording progress in it if it is non-empty. A partition which fails is
+// logged and skipped, so that the other partitions can complete; an error is
+// returned at the end if any partition failed.
+func (l *loader) loadAll(ctx context.Context, checkpointFile string) error {
+	if checkpointFile != "" {
+		l.checkpointFile = checkpointFile
+		contents, err := ioutil.ReadFile(checkpointFile)
+		if err == nil {
+			err = json.Unmarshal(contents, &l.checkpoint)
+			if err != nil {
+				return fmt.Errorf("parsing checkpoint file %q: %w", checkpointFile, err)
+			}
+			l.log.Infof("Resuming from checkpoint with %d partitions done", len(l.checkpoint.Done))
+		} else if !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	maxID, err := l.sa.GetMaxCertificateStatusID(ctx, &corepb.Empty{})
+	if err != nil {
+		return fmt.Errorf("getting largest certificateStatus ID: %w", err)
+	}
+
+	stop := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(progressInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				l.logProgress()
+			}
+		}
+	}()
+	defer close(stop)
+
+	var failedPartitions int64
+	work := make(chan int64)
+	wg := new(sync.WaitGroup)
+	for i := 0; i < l.parallelism; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for start := range work {
+				err := l.loadPartition(ctx, start)
+				if err != nil {
+					atomic.AddInt64(&failedPartitions, 1)
+					l.log.AuditErrf("failed to load partition after ID %d: %s", start, err)
+				}
+			}
+		}()
+	}
+	for _, start := range partitions(maxID.Id) {
+		l.checkpointMu.Lock()
+		done := l.checkpoint.Done[strconv.FormatInt(start, 10)]
+		l.checkpointMu.Unlock()
+		if done {
+			continue
+		}
+		work <- start
+	}
+	close(work)
+	wg.Wait()
+
+	l.logProgress()
+	if failedPartitions > 0 {
+		return fmt.Errorf("%d partitions failed to load", failedPartitions)
+	}
+	return nil
+}
+
+func (l *loader) logProgress() {
+	l.log.Infof("Progress: loaded=%d signed=%d skipped=%d failed=%d",
+		atomic.LoadInt64(&l.loaded),
+		atomic.LoadInt64(&l.signed),
+		atomic.LoadInt64(&l.skipped),
+		atomic.LoadInt64(&l.failed))
+}
+
+// loadPartition loads all rows with IDs after start, and at most
+// start+partitionSize, starting after the last ID recorded in the checkpoint.
+// The rows are streamed from the SA, and progress is checkpointed after every
+// batchSize rows.
+func (l *loader) loadPartition(ctx context.Context, start int64) error {
+	partition := strconv.FormatInt(start, 10)
+	end := start + partitionSize
+	l.checkpointMu.Lock()
+	after, ok := l.checkpoint.LastIDs[partition]
+	l.checkpointMu.Unlock()
+	if !ok || after < start {
+		after = start
+	}
+	if after >= end {
+		return l.saveProgress(partition, after, true)
+	}
+
+	stream, err := l.sa.GetCertificateStatusesByID(ctx, &sapb.CertificateStatusesByIDRequest{
+		AfterID: after,
+		MaxID:   end,
+	})
+	if err != nil {
+		return err
+	}
+	var count int
+	for {
+		row, err := stream.Recv()
+		if err == io.EOF {
+			return l.saveProgress(partition, after, true)
+		}
+		if err != nil {
+			return err
+		}
+		status, err := bgrpc.PBToCertStatus(row.Status)
+		if err != nil {
+			return err
+		}
+		result, err := l.loadOne(ctx, status)
+		if err != nil {
+			l.log.Errf("failed to load response for serial %s: %s", status.Serial, err)
+		}
+		l.results.WithLabelValues(result).Inc()
+		after = row.Id
+		count++
+		if count%l.batchSize == 0 {
+			err = l.saveProgress(partition, after, false)
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// loadOne stores a response for a single certificate in Redis, signing a new
+// one if necessary. It returns the result to be counted.
+func (l *loader) loadOne(ctx context.Context, status core.CertificateStatus) (string, error) {
+	now := l.clk.Now()
+	if status.IsExpired || (!status.NotAfter.IsZero() && !now.Before(status.NotAfter)) {
+		atomic.AddInt64(&l.skipped, 1)
+		return "skipped", nil
+	}
+
+	if len(status.OCSPResponse) > 0 {
+		parsed, err := ocsp.ParseResponse(status.OCSPResponse, nil)
+		if err == nil {
+			ttl := parsed.NextUpdate.Sub(now)
+			if ttl >= l.minTTL && ttl >= time.Second {
+				err = l.storer.StoreResponse(ctx, status.Serial, status.OCSPResponse, ttl)
+				if err != nil {
+					atomic.AddInt64(&l.failed, 1)
+					return "failed", err
+				}
+				atomic.AddInt64(&l.loaded, 1)
+				return "loaded", nil
+			}
+		}
+	}
+
+	if l.signer == nil {
+		atomic.AddInt64(&l.skipped, 1)
+		return "skipped", nil
+	}
+	if status.IssuerID == nil || *status.IssuerID == 0 {
+		atomic.AddInt64(&l.failed, 1)
+		return "failed", errors.New("cert status has nil or 0 IssuerID")
+	}
+	resp, err := l.signer.GenerateOCSP(ctx, &capb.GenerateOCSPRequest{
+		Serial:    status.Serial,
+		IssuerID:  *status.IssuerID,
+		Status:    string(status.Status),
+		Reason:    int32(status.RevokedReason),
+		RevokedAt: status.RevokedDate.UnixNano(),
+	})
+	if err != nil {
+		atomic.AddInt64(&l.failed, 1)
+		return "failed", err
+	}
+	parsed, err := ocsp.ParseResponse(resp.Response, nil)
+	if err != nil {
+		atomic.AddInt64(&l.failed, 1)
+		return "failed", err
+	}
+	err = l.storer.StoreResponse(ctx, status.Serial, resp.Response, parsed.NextUpdate.Sub(now))
+	if err != nil {
+		atomic.AddInt64(&l.failed, 1)
+		return "failed", err
+	}
+	atomic.AddInt64(&l.signed, 1)
+	return "signed", nil
+}
+
+// saveProgress records the ID of the last row loaded in a partition, and
+// whether the partition is complete, writing the checkpoint file if one is
+// configured. The file is replaced atomically so an interruption can't corrupt
+// it.
+func (l *loader) saveProgress(partition string, lastID int64, done bool) error {
+	l.checkpointMu.Lock()
+	defer l.checkpointMu.Unlock()
+	l.checkpoint.LastIDs[partition] = lastID
+	if done {
+		l.checkpoint.Done[partition] = true
+	}
+	if l.checkpointFile == "" {
+		return nil
+	}
+	contents, err := json.Marshal(l.checkpoint)
+	if err != nil {
+		return err
+	}
+	tmp := l.checkpointFile + ".tmp"
+	err = ioutil.WriteFile(tmp, contents, 0600)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp, l.checkpointFile)
+}
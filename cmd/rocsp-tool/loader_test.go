@@ -0,0 +1,223 @@
+package main
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jmhodges/clock"
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/crypto/ocsp"
+	"google.golang.org/grpc"
+
+	capb "github.com/letsencrypt/boulder/ca/proto"
+	"github.com/letsencrypt/boulder/core"
+	corepb "github.com/letsencrypt/boulder/core/proto"
+	bgrpc "github.com/letsencrypt/boulder/grpc"
+	blog "github.com/letsencrypt/boulder/log"
+	sapb "github.com/letsencrypt/boulder/sa/proto"
+	"github.com/letsencrypt/boulder/test"
+)
+
+// fakeSA serves certificateStatus rows from memory, emulating the SA's
+// streaming of rows by ID. The row with index i has ID i+1.
+type fakeSA struct {
+	statuses []core.CertificateStatus
+}
+
+func (fs *fakeSA) GetMaxCertificateStatusID(context.Context, *corepb.Empty, ...grpc.CallOption) (*sapb.CertificateStatusID, error) {
+	return &sapb.CertificateStatusID{Id: int64(len(fs.statuses))}, nil
+}
+
+func (fs *fakeSA) GetCertificateStatusesByID(_ context.Context, req *sapb.CertificateStatusesByIDRequest, _ ...grpc.CallOption) (sapb.StorageAuthority_GetCertificateStatusesByIDClient, error) {
+	stream := &fakeStatusStream{}
+	for i, status := range fs.statuses {
+		id := int64(i + 1)
+		if id > req.AfterID && id <= req.MaxID {
+			stream.rows = append(stream.rows, &sapb.CertificateStatusRow{Id: id, Status: bgrpc.CertStatusToPB(status)})
+		}
+	}
+	return stream, nil
+}
+
+type fakeStatusStream struct {
+	grpc.ClientStream
+	rows []*sapb.CertificateStatusRow
+}
+
+func (s *fakeStatusStream) Recv() (*sapb.CertificateStatusRow, error) {
+	if len(s.rows) == 0 {
+		return nil, io.EOF
+	}
+	row := s.rows[0]
+	s.rows = s.rows[1:]
+	return row, nil
+}
+
+type fakeStorer struct {
+	sync.Mutex
+	ttls map[string]time.Duration
+}
+
+func (fs *fakeStorer) StoreResponse(_ context.Context, serial string, _ []byte, ttl time.Duration) error {
+	fs.Lock()
+	defer fs.Unlock()
+	fs.ttls[serial] = ttl
+	return nil
+}
+
+type fakeSigner struct {
+	response []byte
+}
+
+func (fs *fakeSigner) GenerateOCSP(context.Context, *capb.GenerateOCSPRequest, ...grpc.CallOption) (*capb.OCSPResponse, error) {
+	return &capb.OCSPResponse{Response: fs.response}, nil
+}
+
+func makeResponse(t *testing.T, serial int64, thisUpdate, nextUpdate time.Time) []byte {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	test.AssertNotError(t, err, "generating key")
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test issuer"},
+		NotBefore:             thisUpdate.Add(-time.Hour),
+		NotAfter:              nextUpdate.Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	test.AssertNotError(t, err, "creating issuer")
+	issuer, err := x509.ParseCertificate(der)
+	test.AssertNotError(t, err, "parsing issuer")
+	resp, err := ocsp.CreateResponse(issuer, issuer, ocsp.Response{
+		SerialNumber: big.NewInt(serial),
+		Status:       ocsp.Good,
+		ThisUpdate:   thisUpdate,
+		NextUpdate:   nextUpdate,
+	}, key)
+	test.AssertNotError(t, err, "creating OCSP response")
+	return resp
+}
+
+func TestPartitions(t *testing.T) {
+	defer func(size int64) { partitionSize = size }(partitionSize)
+	partitionSize = 10
+	test.AssertDeepEquals(t, partitions(0), []int64(nil))
+	test.AssertDeepEquals(t, partitions(10), []int64{0})
+	test.AssertDeepEquals(t, partitions(25), []int64{0, 10, 20})
+}
+
+func TestLoadAll(t *testing.T) {
+	defer func(size int64) { partitionSize = size }(partitionSize)
+	partitionSize = 6
+
+	clk := clock.NewFake()
+	clk.Set(time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC))
+	now := clk.Now()
+	issuerID := int64(1)
+
+	fresh := makeResponse(t, 1, now, now.Add(72*time.Hour))
+	nearlyExpired := makeResponse(t, 2, now.Add(-70*time.Hour), now.Add(time.Hour))
+	signed := makeResponse(t, 3, now, now.Add(96*time.Hour))
+
+	sa := &fakeSA{}
+	for i := 0; i < 20; i++ {
+		// The serials all share a prefix, as they do when issued by one CA.
+		status := core.CertificateStatus{
+			Serial:   fmt.Sprintf("00%036d", i),
+			Status:   core.OCSPStatusGood,
+			NotAfter: now.Add(90 * 24 * time.Hour),
+			IssuerID: &issuerID,
+		}
+		switch i % 4 {
+		case 0:
+			status.OCSPResponse = fresh
+		case 1:
+			status.OCSPResponse = nearlyExpired
+		case 2:
+			// No stored response.
+		case 3:
+			status.NotAfter = now.Add(-time.Hour)
+		}
+		sa.statuses = append(sa.statuses, status)
+	}
+
+	tmp, err := ioutil.TempDir("", "rocsp-tool")
+	test.AssertNotError(t, err, "creating temp dir")
+	defer os.RemoveAll(tmp)
+	checkpointFile := filepath.Join(tmp, "checkpoint.json")
+
+	storer := &fakeStorer{ttls: make(map[string]time.Duration)}
+	l := newLoader(sa, storer, &fakeSigner{signed}, clk, blog.NewMock(), prometheus.NewRegistry(), 2, 4, 24*time.Hour)
+	err = l.loadAll(context.Background(), checkpointFile)
+	test.AssertNotError(t, err, "loadAll failed")
+
+	test.AssertEquals(t, l.loaded, int64(5))
+	test.AssertEquals(t, l.signed, int64(10))
+	test.AssertEquals(t, l.skipped, int64(5))
+	test.AssertEquals(t, l.failed, int64(0))
+	test.AssertEquals(t, len(storer.ttls), 15)
+	test.AssertEquals(t, storer.ttls[fmt.Sprintf("00%036d", 0)], 72*time.Hour)
+	test.AssertEquals(t, storer.ttls[fmt.Sprintf("00%036d", 1)], 96*time.Hour)
+
+	// Every partition should be recorded as done, so a second run using the
+	// same checkpoint shouldn't load anything.
+	storer = &fakeStorer{ttls: make(map[string]time.Duration)}
+	l = newLoader(sa, storer, &fakeSigner{signed}, clk, blog.NewMock(), prometheus.NewRegistry(), 2, 4, 24*time.Hour)
+	err = l.loadAll(context.Background(), checkpointFile)
+	test.AssertNotError(t, err, "loadAll with complete checkpoint failed")
+	test.AssertEquals(t, len(l.checkpoint.Done), 4)
+	test.AssertEquals(t, len(storer.ttls), 0)
+}
+
+func TestLoadAllResume(t *testing.T) {
+	clk := clock.NewFake()
+	now := clk.Now()
+	issuerID := int64(1)
+	fresh := makeResponse(t, 1, now, now.Add(72*time.Hour))
+
+	sa := &fakeSA{}
+	for i := 0; i < 5; i++ {
+		sa.statuses = append(sa.statuses, core.CertificateStatus{
+			Serial:       fmt.Sprintf("aa%036d", i),
+			Status:       core.OCSPStatusGood,
+			NotAfter:     now.Add(90 * 24 * time.Hour),
+			IssuerID:     &issuerID,
+			OCSPResponse: fresh,
+		})
+	}
+
+	tmp, err := ioutil.TempDir("", "rocsp-tool")
+	test.AssertNotError(t, err, "creating temp dir")
+	defer os.RemoveAll(tmp)
+	checkpointFile := filepath.Join(tmp, "checkpoint.json")
+	// Rows with IDs up to 3, and so serials up to aa...2, have been loaded.
+	err = ioutil.WriteFile(checkpointFile, []byte(`{"lastIDs":{"0":3}}`), 0600)
+	test.AssertNotError(t, err, "writing checkpoint")
+
+	storer := &fakeStorer{ttls: make(map[string]time.Duration)}
+	l := newLoader(sa, storer, nil, clk, blog.NewMock(), prometheus.NewRegistry(), 10, 1, 0)
+	err = l.loadAll(context.Background(), checkpointFile)
+	test.AssertNotError(t, err, "loadAll failed")
+
+	// Only the serials after the checkpointed one should have been loaded.
+	test.AssertEquals(t, len(storer.ttls), 2)
+	_, ok := storer.ttls[fmt.Sprintf("aa%036d", 3)]
+	test.Assert(t, ok, "serial after checkpoint wasn't loaded")
+	_, ok = storer.ttls[fmt.Sprintf("aa%036d", 2)]
+	test.Assert(t, !ok, "checkpointed serial was loaded again")
+}
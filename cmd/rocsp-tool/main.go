@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"flag"
+	"fmt"
+	"os"
+
+	capb "github.com/letsencrypt/boulder/ca/proto"
+	"github.com/letsencrypt/boulder/cmd"
+	"github.com/letsencrypt/boulder/features"
+	bgrpc "github.com/letsencrypt/boulder/grpc"
+	"github.com/letsencrypt/boulder/metrics"
+	"github.com/letsencrypt/boulder/rocsp"
+	sapb "github.com/letsencrypt/boulder/sa/proto"
+)
+
+const usageString = `
+usage:
+rocsp-tool load --config <path> [--checkpoint <path>]
+
+command descriptions:
+  load      Load OCSP responses for all unexpired certificates into Redis,
+            signing new responses for certificates whose stored response is
+            missing or about to expire.
+
+args:
+  config      File path to the configuration file for this service
+  checkpoint  File path used to record progress. If the file exists, loading
+              resumes from the progress recorded in it.
+`
+
+type config struct {
+	ROCSPTool struct {
+		Redis cmd.RedisConfig
+
+		// TLS configures the connections to the SA, from which certificate
+		// statuses are read, and to the OCSP signer. If OCSPGeneratorService
+		// is absent, only responses already stored in the database are loaded.
+		TLS                  cmd.TLSConfig
+		SAService            *cmd.GRPCClientConfig
+		OCSPGeneratorService *cmd.GRPCClientConfig
+
+		// Parallelism is the number of partitions of certificateStatus row IDs
+		// loaded concurrently. Defaults to 1.
+		Parallelism int
+		// BatchSize is the number of certificateStatus rows loaded in a
+		// partition between each save of the checkpoint. Defaults to 1000.
+		BatchSize int
+		// MinTTL is the minimum remaining lifetime of a stored response for
+		// it to be loaded as-is. Responses expiring sooner than this are
+		// re-signed.
+		MinTTL cmd.ConfigDuration
+
+		DebugAddr string
+		Features  map[string]bool
+	}
+
+	Syslog cmd.SyslogConfig
+}
+
+func main() {
+	usage := func() {
+		fmt.Fprint(os.Stderr, usageString)
+		os.Exit(1)
+	}
+	if len(os.Args) <= 2 {
+		usage()
+	}
+
+	command := os.Args[1]
+	flagSet := flag.NewFlagSet(command, flag.ContinueOnError)
+	configFile := flagSet.String("config", "", "File path to the configuration file for this service")
+	checkpointFile := flagSet.String("checkpoint", "", "File path used to record and resume progress")
+	err := flagSet.Parse(os.Args[2:])
+	cmd.FailOnError(err, "Error parsing flagset")
+
+	if *configFile == "" {
+		usage()
+	}
+
+	var c config
+	err = cmd.ReadConfigFile(*configFile, &c)
+	cmd.FailOnError(err, "Reading JSON config file into config structure")
+	err = features.Set(c.ROCSPTool.Features)
+	cmd.FailOnError(err, "Failed to set feature flags")
+
+	switch command {
+	case "load":
+		conf := c.ROCSPTool
+		stats, logger := cmd.StatsAndLogging(c.Syslog, conf.DebugAddr)
+		defer logger.AuditPanic()
+		logger.Info(cmd.VersionString())
+		clk := cmd.Clock()
+
+		if conf.SAService == nil {
+			cmd.Fail("ROCSPTool.SAService must be configured")
+		}
+		tlsConfig, err := conf.TLS.Load()
+		cmd.FailOnError(err, "TLS config")
+		clientMetrics := bgrpc.NewClientMetrics(metrics.NoopRegisterer)
+		saConn, err := bgrpc.ClientSetup(conf.SAService, tlsConfig, clientMetrics, clk)
+		cmd.FailOnError(err, "Failed to load credentials and create gRPC connection to SA")
+		sac := sapb.NewStorageAuthorityClient(saConn)
+
+		password, err := conf.Redis.Pass()
+		cmd.FailOnError(err, "Failed to load Redis password")
+		var redisTLS *tls.Config
+		if conf.Redis.TLS != nil {
+			redisTLS, err = conf.Redis.TLS.Load()
+			cmd.FailOnError(err, "Failed to load Redis TLS config")
+		}
+		parallelism := conf.Parallelism
+		if parallelism < 1 {
+			parallelism = 1
+		}
+		client := rocsp.NewClient(conf.Redis.Address, password, redisTLS, conf.Redis.Timeout.Duration, parallelism)
+		defer client.Close()
+
+		var signer capb.OCSPGeneratorClient
+		if conf.OCSPGeneratorService != nil {
+			conn, err := bgrpc.ClientSetup(conf.OCSPGeneratorService, tlsConfig, clientMetrics, clk)
+			cmd.FailOnError(err, "Failed to load credentials and create gRPC connection to OCSP signer")
+			signer = bgrpc.NewOCSPGeneratorClient(capb.NewOCSPGeneratorClient(conn))
+		}
+
+		batchSize := conf.BatchSize
+		if batchSize < 1 {
+			batchSize = 1000
+		}
+		l := newLoader(sac, client, signer, clk, logger, stats, batchSize, parallelism, conf.MinTTL.Duration)
+		err = l.loadAll(context.Background(), *checkpointFile)
+		cmd.FailOnError(err, "Loading OCSP responses into Redis failed")
+
+	default:
+		usage()
+	}
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/letsencrypt/boulder/cmd"
+	"github.com/letsencrypt/boulder/sa"
+)
+
+const usageIntro = `
+sa-migrate applies the SA's versioned schema migrations from a migrations
+directory (e.g. sa/_db) to a database, recording each with a checksum of its
+file in the schemaMigrations table. Before migrating, it checks that no
+migration has changed since it was applied. Migrations without DDL statements
+are applied in a transaction.
+
+Migrations recorded as applied by goose are adopted, i.e. recorded without
+being applied again.
+
+The migrations from each directory, such as sa/_db and sa/_db-next, are
+recorded separately. The SA refuses to start unless the newest migration
+applied from sa/_db is at least as new as the one it expects.
+`
+
+func main() {
+	dir := flag.String("dir", "", "Directory containing the migrations directory, e.g. sa/_db")
+	dbConnect := flag.String("db-connect", "", "Connect URL for the database")
+	dbConnectFile := flag.String("db-connect-file", "", "File containing the connect URL for the database")
+	to := flag.Int64("to", 0, "Version to migrate to. Defaults to the newest migration")
+	dryRun := flag.Bool("dry-run", false, "Print the migrations which would be applied, without applying them")
+
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "%s\n", usageIntro)
+		fmt.Fprintf(os.Stderr, "Usage of %s:\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+
+	flag.Parse()
+	if *dir == "" || (*dbConnect == "") == (*dbConnectFile == "") {
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	log := cmd.NewLogger(cmd.SyslogConfig{StdoutLevel: 6})
+
+	migrations, err := sa.LoadMigrations(*dir)
+	cmd.FailOnError(err, "Loading migrations")
+
+	dbConfig := cmd.DBConfig{DBConnect: *dbConnect, DBConnectFile: *dbConnectFile}
+	dbURL, err := dbConfig.URL()
+	cmd.FailOnError(err, "Couldn't load DB URL")
+	dbMap, err := sa.NewDbMap(dbURL, sa.DbSettings{MaxOpenConns: 2})
+	cmd.FailOnError(err, "Couldn't connect to database")
+
+	ctx := context.Background()
+	migrator := sa.NewMigrator(dbMap.Db, cmd.Clock(), log)
+	plan, err := migrator.Plan(ctx, migrations, *to)
+	cmd.FailOnError(err, "Planning migrations")
+
+	printPlan(os.Stdout, plan)
+	if *dryRun {
+		return
+	}
+	err = migrator.Apply(ctx, plan)
+	cmd.FailOnError(err, "Applying migrations")
+}
+
+// printPlan describes the migrations a plan would record and apply.
+func printPlan(w io.Writer, plan sa.MigrationPlan) {
+	if len(plan.Adopt) == 0 && len(plan.Apply) == 0 {
+		fmt.Fprintln(w, "The database is up to date")
+		return
+	}
+	for _, m := range plan.Adopt {
+		fmt.Fprintf(w, "Record %s, applied by goose\n", m.Name)
+	}
+	for _, m := range plan.Apply {
+		if m.Transactional() {
+			fmt.Fprintf(w, "Apply %s (%d statements, in a transaction)\n", m.Name, len(m.Statements))
+		} else {
+			fmt.Fprintf(w, "Apply %s (%d statements, not in a transaction as it contains DDL)\n", m.Name, len(m.Statements))
+		}
+	}
+}
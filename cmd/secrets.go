@@ -0,0 +1,218 @@
+package cmd
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SecretBackend resolves references to secrets in config files. A string value
+// of the form "<scheme>:<reference>", where scheme is that of a registered
+// backend, is replaced by the secret which the backend resolves the reference
+// to. For example, "env:DB_PASSWORD" is replaced by the value of the
+// DB_PASSWORD environment variable.
+type SecretBackend interface {
+	Resolve(reference string) (string, error)
+}
+
+var secretBackends = struct {
+	sync.RWMutex
+	backends map[string]SecretBackend
+}{
+	backends: map[string]SecretBackend{
+		"env":   envSecrets{},
+		"file":  fileSecrets{},
+		"vault": vaultSecrets{},
+	},
+}
+
+// RegisterSecretBackend makes a SecretBackend available to config files under
+// the given scheme. It must be called before ReadConfigFile.
+func RegisterSecretBackend(scheme string, backend SecretBackend) {
+	secretBackends.Lock()
+	defer secretBackends.Unlock()
+	secretBackends.backends[scheme] = backend
+}
+
+// minRedactedSecretLength is the length below which resolved secrets aren't
+// redacted from logging. Replacing every occurrence of a short value, such as
+// a port number or "true", would garble every log line, while revealing little
+// of any secret worth the name.
+const minRedactedSecretLength = 8
+
+// resolveSecrets replaces each secret reference in the JSON config data with
+// the secret it refers to, and returns the secrets to redact from logging. If
+// there are no references, or the data isn't valid JSON, the data is returned
+// unchanged.
+func resolveSecrets(configData []byte) ([]byte, []string, error) {
+	decoder := json.NewDecoder(bytes.NewReader(configData))
+	// Decode numbers as json.Number so that large integers survive being
+	// encoded again.
+	decoder.UseNumber()
+	var config interface{}
+	err := decoder.Decode(&config)
+	if err != nil {
+		return configData, nil, nil
+	}
+	var secrets []string
+	config, resolved, err := resolveSecretsIn(config, "", &secrets)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !resolved {
+		return configData, nil, nil
+	}
+	resolvedData, err := json.Marshal(config)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resolvedData, secrets, nil
+}
+
+// resolveSecretsIn walks a decoded JSON value, returning it with any secret
+// references replaced, and whether there were any. Each secret of at least
+// minRedactedSecretLength is appended to secrets.
+func resolveSecretsIn(v interface{}, path string, secrets *[]string) (interface{}, bool, error) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		var found bool
+		for key, value := range v {
+			resolved, ok, err := resolveSecretsIn(value, strings.TrimPrefix(path+"."+key, "."), secrets)
+			if err != nil {
+				return nil, false, err
+			}
+			v[key] = resolved
+			found = found || ok
+		}
+		return v, found, nil
+	case []interface{}:
+		var found bool
+		for i, value := range v {
+			resolved, ok, err := resolveSecretsIn(value, fmt.Sprintf("%s[%d]", path, i), secrets)
+			if err != nil {
+				return nil, false, err
+			}
+			v[i] = resolved
+			found = found || ok
+		}
+		return v, found, nil
+	case string:
+		i := strings.Index(v, ":")
+		if i < 0 {
+			return v, false, nil
+		}
+		secretBackends.RLock()
+		backend, ok := secretBackends.backends[v[:i]]
+		secretBackends.RUnlock()
+		if !ok {
+			return v, false, nil
+		}
+		secret, err := backend.Resolve(v[i+1:])
+		if err != nil {
+			return nil, false, fmt.Errorf("resolving secret for %s: %s", path, err)
+		}
+		if len(secret) >= minRedactedSecretLength {
+			*secrets = append(*secrets, secret)
+		}
+		return secret, true, nil
+	}
+	return v, false, nil
+}
+
+// envSecrets resolves references to environment variables.
+type envSecrets struct{}
+
+func (envSecrets) Resolve(name string) (string, error) {
+	value, ok := os.LookupEnv(name)
+	if !ok {
+		return "", fmt.Errorf("environment variable %q is not set", name)
+	}
+	return value, nil
+}
+
+// fileSecrets resolves references to files, without their trailing newline.
+type fileSecrets struct{}
+
+func (fileSecrets) Resolve(path string) (string, error) {
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(contents), "\n"), nil
+}
+
+// vaultSecrets resolves references of the form "path#key" to the key in the
+// secret at the given path in HashiCorp Vault, supporting both versions of the
+// KV secrets engine. The Vault server and token are taken from the standard
+// VAULT_ADDR and VAULT_TOKEN environment variables, and VAULT_CACERT, if set,
+// names a file of CA certificates to verify the server with.
+type vaultSecrets struct{}
+
+func (vaultSecrets) Resolve(reference string) (string, error) {
+	i := strings.LastIndex(reference, "#")
+	if i < 0 {
+		return "", errors.New("vault secret references must be of the form path#key")
+	}
+	path, key := reference[:i], reference[i+1:]
+	addr := os.Getenv("VAULT_ADDR")
+	token := os.Getenv("VAULT_TOKEN")
+	if addr == "" || token == "" {
+		return "", errors.New("VAULT_ADDR and VAULT_TOKEN must be set to use vault secrets")
+	}
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	if caFile := os.Getenv("VAULT_CACERT"); caFile != "" {
+		caCertBytes, err := ioutil.ReadFile(caFile)
+		if err != nil {
+			return "", err
+		}
+		roots := x509.NewCertPool()
+		if ok := roots.AppendCertsFromPEM(caCertBytes); !ok {
+			return "", fmt.Errorf("parsing CA certs from %s failed", caFile)
+		}
+		client.Transport = &http.Transport{TLSClientConfig: &tls.Config{RootCAs: roots}}
+	}
+	req, err := http.NewRequest("GET", strings.TrimRight(addr, "/")+"/v1/"+strings.TrimLeft(path, "/"), nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("X-Vault-Token", token)
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("reading %q from vault: %s", path, resp.Status)
+	}
+
+	var secret struct {
+		Data map[string]interface{}
+	}
+	err = json.NewDecoder(resp.Body).Decode(&secret)
+	if err != nil {
+		return "", fmt.Errorf("decoding vault response for %q: %s", path, err)
+	}
+	data := secret.Data
+	// Version 2 of the KV engine nests the secret's data alongside its
+	// metadata.
+	if nested, ok := data["data"].(map[string]interface{}); ok {
+		if _, ok := data["metadata"]; ok {
+			data = nested
+		}
+	}
+	value, ok := data[key].(string)
+	if !ok {
+		return "", fmt.Errorf("vault secret %q has no string key %q", path, key)
+	}
+	return value, nil
+}
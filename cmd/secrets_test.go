@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	blog "github.com/letsencrypt/boulder/log"
+	"github.com/letsencrypt/boulder/test"
+)
+
+type upperSecrets struct{}
+
+func (upperSecrets) Resolve(reference string) (string, error) {
+	return strings.ToUpper(reference), nil
+}
+
+func TestReadConfigFileSecrets(t *testing.T) {
+	dir, err := ioutil.TempDir("", "secrets")
+	test.AssertNotError(t, err, "creating temp dir")
+	defer os.RemoveAll(dir)
+	secretFile := filepath.Join(dir, "secret")
+	test.AssertNotError(t, ioutil.WriteFile(secretFile, []byte("file-secret\n"), 0600), "writing secret")
+
+	vault := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/secret/data/boulder":
+			fmt.Fprint(w, `{"data": {"data": {"hmacKey": "vault-v2-secret"}, "metadata": {"version": 3}}}`)
+		case "/v1/kv/boulder":
+			fmt.Fprint(w, `{"data": {"hmacKey": "vault-v1-secret"}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer vault.Close()
+	os.Setenv("BOULDER_TEST_SECRET", "env-secret")
+	defer os.Unsetenv("BOULDER_TEST_SECRET")
+	os.Setenv("VAULT_ADDR", vault.URL)
+	defer os.Unsetenv("VAULT_ADDR")
+	os.Setenv("VAULT_TOKEN", "token")
+	defer os.Unsetenv("VAULT_TOKEN")
+	RegisterSecretBackend("upper", upperSecrets{})
+
+	writeConfig := func(config map[string]interface{}) string {
+		configJSON, err := json.Marshal(config)
+		test.AssertNotError(t, err, "marshaling config")
+		filename := filepath.Join(dir, "config.json")
+		test.AssertNotError(t, ioutil.WriteFile(filename, configJSON, 0600), "writing config")
+		return filename
+	}
+
+	var c struct {
+		Env     string
+		File    string
+		VaultV2 string
+		VaultV1 string
+		Custom  []string
+		URL     string
+		Serial  int64
+	}
+	err = ReadConfigFile(writeConfig(map[string]interface{}{
+		"env":     "env:BOULDER_TEST_SECRET",
+		"file":    "file:" + secretFile,
+		"vaultV2": "vault:secret/data/boulder#hmacKey",
+		"vaultV1": "vault:kv/boulder#hmacKey",
+		"custom":  []string{"upper:shout"},
+		"url":     "http://boulder:4000",
+		"serial":  int64(9007199254740993),
+	}), &c)
+	test.AssertNotError(t, err, "ReadConfigFile failed")
+	test.AssertEquals(t, c.Env, "env-secret")
+	test.AssertEquals(t, c.File, "file-secret")
+	test.AssertEquals(t, c.VaultV2, "vault-v2-secret")
+	test.AssertEquals(t, c.VaultV1, "vault-v1-secret")
+	test.AssertEquals(t, c.Custom[0], "SHOUT")
+	test.AssertEquals(t, c.URL, "http://boulder:4000")
+	test.AssertEquals(t, c.Serial, int64(9007199254740993))
+
+	log := blog.NewMock()
+	log.Infof("connecting with %s", c.VaultV2)
+	test.AssertEquals(t, log.GetAll()[0], "INFO: connecting with [REDACTED]")
+
+	// Secrets too short to be worth redacting aren't, and reading the config
+	// again replaces the secrets redacted for it.
+	err = ReadConfigFile(writeConfig(map[string]interface{}{
+		"env":    "env:BOULDER_TEST_SECRET",
+		"custom": []string{"upper:on"},
+	}), &c)
+	test.AssertNotError(t, err, "ReadConfigFile failed")
+	test.AssertEquals(t, c.Custom[0], "ON")
+	log.Clear()
+	log.Infof("%s %s %s", c.Env, c.VaultV2, c.Custom[0])
+	test.AssertEquals(t, log.GetAll()[0], "INFO: [REDACTED] vault-v2-secret ON")
+
+	for _, ref := range []string{
+		"env:BOULDER_TEST_MISSING",
+		"file:" + filepath.Join(dir, "missing"),
+		"vault:secret/data/boulder",
+		"vault:secret/data/boulder#missing",
+		"vault:secret/data/missing#hmacKey",
+	} {
+		err = ReadConfigFile(writeConfig(map[string]interface{}{"nested": map[string]string{"password": ref}}), &c)
+		test.AssertError(t, err, "ReadConfigFile resolved "+ref)
+		test.AssertContains(t, err.Error(), "resolving secret for nested.password")
+	}
+}
@@ -22,10 +22,12 @@ import (
 
 	cfsslLog "github.com/cloudflare/cfssl/log"
 	"github.com/go-sql-driver/mysql"
+	"github.com/jmhodges/clock"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 
 	"github.com/letsencrypt/boulder/core"
+	"github.com/letsencrypt/boulder/features"
 	blog "github.com/letsencrypt/boulder/log"
 )
 
@@ -144,7 +146,10 @@ func (lw logWriter) Write(p []byte) (n int, err error) {
 // StatsAndLogging constructs a prometheus registerer and an AuditLogger based
 // on its config parameters, and return them both. It also spawns off an HTTP
 // server on the provided port to report the stats and provide pprof profiling
-// handlers. NewLogger and newStatsRegistry will call os.Exit on errors.
+// handlers, the /healthz and /readyz health check endpoints (see
+// RegisterReadinessCheck), and the /info endpoint, which describes the build,
+// feature flags and config, as do the build_info, config_info and
+// feature_enabled_percentage metrics. NewLogger and newStatsRegistry will call os.Exit on errors.
 // Also sets the constructed AuditLogger as the default logger, and configures
 // the cfssl, mysql, and grpc packages to use our logger.
 // This must be called before any gRPC code is called, because gRPC's SetLogger
@@ -166,8 +171,24 @@ func NewLogger(logConf SyslogConfig) blog.Logger {
 	if logConf.SyslogLevel != 0 {
 		syslogLevel = logConf.SyslogLevel
 	}
-	logger, err := blog.New(syslogger, logConf.StdoutLevel, syslogLevel)
+	var logger blog.Logger
+	switch logConf.Format {
+	case "", "text":
+		logger, err = blog.New(syslogger, logConf.StdoutLevel, syslogLevel)
+	case "json":
+		logger, err = blog.NewJSON(syslogger, logConf.StdoutLevel, syslogLevel, tag)
+	default:
+		err = fmt.Errorf("unknown log format %q", logConf.Format)
+	}
 	FailOnError(err, "Could not connect to Syslog")
+	if len(logConf.DedupWindows) > 0 {
+		windows := make(map[string]time.Duration, len(logConf.DedupWindows))
+		for level, window := range logConf.DedupWindows {
+			windows[level] = window.Duration
+		}
+		logger, err = blog.NewDeduplicating(logger, windows, clock.New())
+		FailOnError(err, "Invalid log deduplication config")
+	}
 
 	_ = blog.Set(logger)
 	// We set the cfssl logging level to Debug as it
@@ -197,6 +218,10 @@ func newStatsRegistry(addr string, logger blog.Logger) prometheus.Registerer {
 	registry.MustRegister(prometheus.NewGoCollector())
 	registry.MustRegister(prometheus.NewProcessCollector(
 		prometheus.ProcessCollectorOpts{}))
+	registry.MustRegister(tlsCertNotAfter)
+	registry.MustRegister(features.RolloutDecisions)
+	registry.MustRegister(features.OverrideLookups)
+	registry.MustRegister(infoCollector{})
 
 	mux := http.NewServeMux()
 	// Register the available pprof handlers. These are all registered on
@@ -215,6 +240,10 @@ func newStatsRegistry(addr string, logger blog.Logger) prometheus.Registerer {
 	mux.Handle("/debug/pprof/threadcreate", pprof.Handler("threadcreate"))
 
 	mux.Handle("/debug/vars", expvar.Handler())
+	mux.Handle("/debug/reload", http.HandlerFunc(reloadHandler))
+	mux.Handle("/healthz", http.HandlerFunc(livenessChecks.handler))
+	mux.Handle("/readyz", http.HandlerFunc(readinessChecks.handler))
+	mux.Handle("/info", http.HandlerFunc(infoHandler))
 	mux.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{
 		ErrorLog: promLogger{logger},
 	}))
@@ -252,13 +281,41 @@ func FailOnError(err error, msg string) {
 
 // ReadConfigFile takes a file path as an argument and attempts to
 // unmarshal the content of the file into a struct containing a
-// configuration of a boulder component.
+// configuration of a boulder component. Any string value which refers to a
+// secret, such as "env:DB_PASSWORD", is first replaced by that secret (see
+// SecretBackend), and the secret is redacted from all logging. Once it's
+// loaded, the file's hash is reported by the debug server's /info endpoint and
+// config_info metric.
 func ReadConfigFile(filename string, out interface{}) error {
-	configData, err := ioutil.ReadFile(filename)
+	configData, secrets, err := readConfigFile(filename, out)
 	if err != nil {
 		return err
 	}
-	return json.Unmarshal(configData, out)
+	blog.SetRedactedSecrets(filename, secrets)
+	recordConfig(filename, configData)
+	return nil
+}
+
+// readConfigFile is ReadConfigFile without recording the config, for callers
+// which only record it once it's been put into use. It returns the contents
+// of the file as read, and the secrets resolved from it, which are redacted
+// from logging along with those of the config already in use until the config
+// is recorded.
+func readConfigFile(filename string, out interface{}) ([]byte, []string, error) {
+	configData, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, nil, err
+	}
+	resolved, secrets, err := resolveSecrets(configData)
+	if err != nil {
+		return nil, nil, err
+	}
+	blog.AddRedactedSecrets(filename, secrets)
+	err = json.Unmarshal(resolved, out)
+	if err != nil {
+		return nil, nil, err
+	}
+	return configData, secrets, nil
 }
 
 // VersionString produces a friendly Application version string.
@@ -274,7 +331,8 @@ var signalToName = map[os.Signal]string{
 }
 
 // CatchSignals catches SIGTERM, SIGINT, SIGHUP and executes a callback
-// method before exiting
+// method before exiting. If a ConfigReloader has been started, SIGHUP reloads
+// the config instead.
 func CatchSignals(logger blog.Logger, callback func()) {
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGTERM)
@@ -282,6 +340,10 @@ func CatchSignals(logger blog.Logger, callback func()) {
 	signal.Notify(sigChan, syscall.SIGHUP)
 
 	sig := <-sigChan
+	for sig == syscall.SIGHUP && activeReloader.Load() != nil {
+		// A started ConfigReloader handles SIGHUP by reloading the config.
+		sig = <-sigChan
+	}
 	if logger != nil {
 		logger.Infof("Caught %s", signalToName[sig])
 	}
@@ -292,6 +354,7 @@ func CatchSignals(logger blog.Logger, callback func()) {
 
 	if logger != nil {
 		logger.Info("Exiting")
+		blog.StopDeduplicating(logger)
 	}
 	os.Exit(0)
 }
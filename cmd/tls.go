@@ -0,0 +1,201 @@
+package cmd
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	blog "github.com/letsencrypt/boulder/log"
+	"github.com/letsencrypt/boulder/reloader"
+)
+
+// tlsCertNotAfter exports the expiry of each certificate loaded by a
+// TLSConfig, so that alerts can fire before a certificate which has failed to
+// be rotated expires. It is registered by newStatsRegistry.
+var tlsCertNotAfter = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "tls_cert_not_after",
+	Help: "The notAfter date of the TLS certificate currently in use, in seconds since the epoch, by certificate file",
+}, []string{"certfile"})
+
+// reloadingTLS holds the certificate, key and CA bundle named by a TLSConfig,
+// and reloads all three whenever any of the files change. New material is only
+// swapped in once it has been verified, so a rotation which writes the
+// certificate and key one after the other keeps using the old pair until both
+// have been written.
+type reloadingTLS struct {
+	certFile   string
+	keyFile    string
+	caCertFile string
+
+	mu    sync.RWMutex
+	cert  tls.Certificate
+	roots *x509.CertPool
+}
+
+// reload reads the certificate, key and CA bundle from disk and, if they are
+// valid, replaces the current ones.
+func (rt *reloadingTLS) reload() error {
+	caCertBytes, err := ioutil.ReadFile(rt.caCertFile)
+	if err != nil {
+		return fmt.Errorf("reading CA cert from %q: %s", rt.caCertFile, err)
+	}
+	roots := x509.NewCertPool()
+	if ok := roots.AppendCertsFromPEM(caCertBytes); !ok {
+		return fmt.Errorf("parsing CA certs from %s failed", rt.caCertFile)
+	}
+	// LoadX509KeyPair checks that the key matches the certificate.
+	cert, err := tls.LoadX509KeyPair(rt.certFile, rt.keyFile)
+	if err != nil {
+		return fmt.Errorf("loading key pair from %q and %q: %s",
+			rt.certFile, rt.keyFile, err)
+	}
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		return fmt.Errorf("parsing certificate from %q: %s", rt.certFile, err)
+	}
+	now := time.Now()
+	if now.Before(leaf.NotBefore) || now.After(leaf.NotAfter) {
+		return fmt.Errorf("certificate from %q is not valid now: valid from %s to %s",
+			rt.certFile, leaf.NotBefore, leaf.NotAfter)
+	}
+	cert.Leaf = leaf
+
+	rt.mu.Lock()
+	rt.cert = cert
+	rt.roots = roots
+	rt.mu.Unlock()
+	tlsCertNotAfter.WithLabelValues(rt.certFile).Set(float64(leaf.NotAfter.Unix()))
+	return nil
+}
+
+func (rt *reloadingTLS) reloadError(err error) {
+	blog.Get().Errf("reloading TLS certificates, continuing to use the previous ones: %s", err)
+}
+
+// config returns a *tls.Config using the current certificate and CA bundle.
+func (rt *reloadingTLS) config() *tls.Config {
+	rt.mu.RLock()
+	defer rt.mu.RUnlock()
+	return &tls.Config{
+		RootCAs:      rt.roots,
+		ClientCAs:    rt.roots,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		Certificates: []tls.Certificate{rt.cert},
+		// Set the only acceptable TLS version to 1.2 and the only acceptable cipher suite
+		// to ECDHE-RSA-CHACHA20-POLY1305.
+		MinVersion:   tls.VersionTLS12,
+		MaxVersion:   tls.VersionTLS12,
+		CipherSuites: []uint16{tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305},
+	}
+}
+
+// tlsFiles holds the reloadingTLS for each set of files loaded by
+// TLSConfig.Load, and those using each file which is being watched for
+// changes. Services load the same files for each of their gRPC clients and
+// servers, which share a reloadingTLS and a single watcher per file.
+var tlsFiles = struct {
+	sync.Mutex
+	reloaders map[[3]string]*reloadingTLS
+	watched   map[string][]*reloadingTLS
+}{
+	reloaders: make(map[[3]string]*reloadingTLS),
+	watched:   make(map[string][]*reloadingTLS),
+}
+
+// watchTLSFiles returns the reloadingTLS for the given certificate, key and CA
+// bundle, loading them and watching them for changes if they haven't already
+// been.
+func watchTLSFiles(certFile, keyFile, caCertFile string) (*reloadingTLS, error) {
+	tlsFiles.Lock()
+	key := [3]string{certFile, keyFile, caCertFile}
+	if rt, ok := tlsFiles.reloaders[key]; ok {
+		tlsFiles.Unlock()
+		return rt, nil
+	}
+	rt := &reloadingTLS{certFile: certFile, keyFile: keyFile, caCertFile: caCertFile}
+	err := rt.reload()
+	if err != nil {
+		tlsFiles.Unlock()
+		return nil, err
+	}
+	var started []*reloader.Reloader
+	starting := make(map[string]bool)
+	for _, filename := range key {
+		if _, ok := tlsFiles.watched[filename]; ok || starting[filename] {
+			continue
+		}
+		starting[filename] = true
+		filename := filename
+		r, err := reloader.Watch(filename, func([]byte) error { reloadTLSFile(filename); return nil }, rt.reloadError)
+		if err != nil {
+			tlsFiles.Unlock()
+			// The watchers are stopped without holding the lock, which their
+			// callbacks take.
+			for _, r := range started {
+				r.Stop()
+			}
+			return nil, err
+		}
+		started = append(started, r)
+	}
+	for _, filename := range key {
+		if !containsReloader(tlsFiles.watched[filename], rt) {
+			tlsFiles.watched[filename] = append(tlsFiles.watched[filename], rt)
+		}
+	}
+	tlsFiles.reloaders[key] = rt
+	tlsFiles.Unlock()
+	return rt, nil
+}
+
+// reloadTLSFile reloads everything loaded alongside filename, which has
+// changed. All of a reloadingTLS's files are reloaded together whichever of
+// them changed, since a new certificate is only usable with its key.
+func reloadTLSFile(filename string) {
+	tlsFiles.Lock()
+	rts := append([]*reloadingTLS(nil), tlsFiles.watched[filename]...)
+	tlsFiles.Unlock()
+	for _, rt := range rts {
+		err := rt.reload()
+		if err != nil {
+			rt.reloadError(err)
+		}
+	}
+}
+
+func containsReloader(rts []*reloadingTLS, rt *reloadingTLS) bool {
+	for _, r := range rts {
+		if r == rt {
+			return true
+		}
+	}
+	return false
+}
+
+// reloadingConfigs maps each *tls.Config returned by TLSConfig.Load to the
+// reloadingTLS it uses, for CurrentTLSConfig.
+var reloadingConfigs sync.Map
+
+// CurrentTLSConfig returns a function which returns a *tls.Config using the
+// current certificate and CA bundle of a config returned by TLSConfig.Load,
+// which reloads them from disk when they change. It returns nil for any other
+// config.
+func CurrentTLSConfig(config *tls.Config) func() *tls.Config {
+	rt, ok := reloadingConfigs.Load(config)
+	if !ok {
+		return nil
+	}
+	return rt.(*reloadingTLS).config
+}
+
+func (rt *reloadingTLS) clientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	rt.mu.RLock()
+	defer rt.mu.RUnlock()
+	cert := rt.cert
+	return &cert, nil
+}
@@ -25,3 +25,10 @@ func DNSChallenge01(token string) Challenge {
 func TLSALPNChallenge01(token string) Challenge {
 	return newChallenge(ChallengeTypeTLSALPN01, token)
 }
+
+// EmailReplyChallenge00 constructs an email-reply-00 challenge whose
+// token-part2 is the provided token. Token-part1 is sent by email when the
+// challenge is validated.
+func EmailReplyChallenge00(token string) Challenge {
+	return newChallenge(ChallengeTypeEmailReply00, token)
+}
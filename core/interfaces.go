@@ -122,6 +122,8 @@ type StorageGetter interface {
 	GetCertificate(ctx context.Context, serial string) (Certificate, error)
 	GetPrecertificate(ctx context.Context, req *sapb.Serial) (*corepb.Certificate, error)
 	GetCertificateStatus(ctx context.Context, serial string) (CertificateStatus, error)
+	GetCertificateStatuses(ctx context.Context, req *sapb.Serials) (*sapb.CertificateStatuses, error)
+	GetMaxCertificateStatusID(ctx context.Context, req *corepb.Empty) (*sapb.CertificateStatusID, error)
 	CountCertificatesByNames(ctx context.Context, domains []string, earliest, latest time.Time) (countByDomain []*sapb.CountByNames_MapElement, err error)
 	CountRegistrationsByIP(ctx context.Context, ip net.IP, earliest, latest time.Time) (int, error)
 	CountRegistrationsByIPRange(ctx context.Context, ip net.IP, earliest, latest time.Time) (int, error)
@@ -129,6 +131,16 @@ type StorageGetter interface {
 	CountFQDNSets(ctx context.Context, window time.Duration, domains []string) (count int64, err error)
 	FQDNSetExists(ctx context.Context, domains []string) (exists bool, err error)
 	PreviousCertificateExists(ctx context.Context, req *sapb.PreviousCertificateExistsRequest) (exists *sapb.Exists, err error)
+	ReplacementCertificateExists(ctx context.Context, req *sapb.ReplacementCertificateExistsRequest) (*sapb.Exists, error)
+	GetSerialsByKey(ctx context.Context, req *sapb.SPKIHash) (*sapb.Serials, error)
+	GetSerialsByAccount(ctx context.Context, req *sapb.RegistrationID) (*sapb.Serials, error)
+	GetFeatureOverrides(ctx context.Context, req *sapb.RegistrationID) (*sapb.FeatureOverrides, error)
+	GetHostnamePolicy(ctx context.Context, req *sapb.GetHostnamePolicyRequest) (*sapb.HostnamePolicy, error)
+	GetAccountAllowlist(ctx context.Context, req *sapb.RegistrationID) (*sapb.AccountAllowlist, error)
+	GetIssuanceCounts(ctx context.Context, req *sapb.IssuanceCountsRequest) (*sapb.IssuanceCounts, error)
+	GetAccountEvents(ctx context.Context, req *sapb.GetAccountEventsRequest) (*sapb.AccountEvents, error)
+	GetKeyRollovers(ctx context.Context, req *sapb.GetKeyRolloversRequest) (*sapb.KeyRollovers, error)
+	GetIssuanceLogEntry(ctx context.Context, req *sapb.GetIssuanceLogEntryRequest) (*sapb.IssuanceLogEntry, error)
 	GetOrder(ctx context.Context, req *sapb.OrderRequest) (*corepb.Order, error)
 	GetOrderForNames(ctx context.Context, req *sapb.GetOrderForNamesRequest) (*corepb.Order, error)
 	// New authz2 methods
@@ -140,6 +152,8 @@ type StorageGetter interface {
 	CountInvalidAuthorizations2(ctx context.Context, req *sapb.CountInvalidAuthorizationsRequest) (*sapb.Count, error)
 	GetValidAuthorizations2(ctx context.Context, req *sapb.GetValidAuthorizationsRequest) (*sapb.Authorizations, error)
 	KeyBlocked(ctx context.Context, req *sapb.KeyBlockedRequest) (*sapb.Exists, error)
+	IncidentsForSerial(ctx context.Context, req *sapb.Serial) (*sapb.Incidents, error)
+	GetNotificationPreferences(ctx context.Context, req *sapb.RegistrationID) (*sapb.NotificationPreferences, error)
 }
 
 // StorageAdder are the Boulder SA's write/update methods
@@ -151,6 +165,7 @@ type StorageAdder interface {
 	AddSerial(ctx context.Context, req *sapb.AddSerialRequest) (*corepb.Empty, error)
 	DeactivateRegistration(ctx context.Context, id int64) error
 	NewOrder(ctx context.Context, order *corepb.Order) (*corepb.Order, error)
+	NewOrderAndAuthzs(ctx context.Context, req *sapb.NewOrderAndAuthzsRequest) (*corepb.Order, error)
 	SetOrderProcessing(ctx context.Context, order *corepb.Order) error
 	FinalizeOrder(ctx context.Context, order *corepb.Order) error
 	SetOrderError(ctx context.Context, order *corepb.Order) error
@@ -160,6 +175,19 @@ type StorageAdder interface {
 	FinalizeAuthorization2(ctx context.Context, req *sapb.FinalizeAuthorizationRequest) error
 	DeactivateAuthorization2(ctx context.Context, req *sapb.AuthorizationID2) (*corepb.Empty, error)
 	AddBlockedKey(ctx context.Context, req *sapb.AddBlockedKeyRequest) (*corepb.Empty, error)
+	AddIncident(ctx context.Context, req *sapb.AddIncidentRequest) (*sapb.Incident, error)
+	AddIncidentSerials(ctx context.Context, req *sapb.AddIncidentSerialsRequest) (*corepb.Empty, error)
+	SetIncidentStatus(ctx context.Context, req *sapb.SetIncidentStatusRequest) (*corepb.Empty, error)
+	SetNotificationPreferences(ctx context.Context, req *sapb.NotificationPreferences) (*corepb.Empty, error)
+	SetFeatureOverride(ctx context.Context, req *sapb.FeatureOverrideRequest) (*corepb.Empty, error)
+	ClearFeatureOverride(ctx context.Context, req *sapb.FeatureOverrideRequest) (*corepb.Empty, error)
+	AddHostnamePolicy(ctx context.Context, req *sapb.HostnamePolicy) (*sapb.HostnamePolicy, error)
+	AddAccountAllowlistEntry(ctx context.Context, req *sapb.AccountAllowlistEntry) (*corepb.Empty, error)
+	RemoveAccountAllowlistEntry(ctx context.Context, req *sapb.AccountAllowlistEntry) (*corepb.Empty, error)
+	ClearAccountAllowlist(ctx context.Context, req *sapb.RegistrationID) (*corepb.Empty, error)
+	AddCertificateMetadata(ctx context.Context, req *sapb.CertificateMetadata) (*corepb.Empty, error)
+	AddAccountEvent(ctx context.Context, req *sapb.AccountEvent) (*corepb.Empty, error)
+	AddIssuanceLogEntry(ctx context.Context, req *sapb.IssuanceLogEntry) (*corepb.Empty, error)
 }
 
 // StorageAuthority interface represents a simple key/value
@@ -173,4 +201,5 @@ type StorageAuthority interface {
 // Publisher defines the public interface for the Boulder Publisher
 type Publisher interface {
 	SubmitToSingleCTWithResult(ctx context.Context, req *pubpb.Request) (*pubpb.Result, error)
+	QueueFinalCert(ctx context.Context, req *pubpb.FinalCertRequest) (*pubpb.FinalCertQueued, error)
 }
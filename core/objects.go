@@ -57,18 +57,34 @@ const (
 	ChallengeTypeHTTP01    = AcmeChallenge("http-01")
 	ChallengeTypeDNS01     = AcmeChallenge("dns-01")
 	ChallengeTypeTLSALPN01 = AcmeChallenge("tls-alpn-01")
+	// ChallengeTypeEmailReply00 is the RFC 8823 challenge for email
+	// identifiers.
+	ChallengeTypeEmailReply00 = AcmeChallenge("email-reply-00")
 )
 
 // IsValid tests whether the challenge is a known challenge
 func (c AcmeChallenge) IsValid() bool {
 	switch c {
-	case ChallengeTypeHTTP01, ChallengeTypeDNS01, ChallengeTypeTLSALPN01:
+	case ChallengeTypeHTTP01, ChallengeTypeDNS01, ChallengeTypeTLSALPN01, ChallengeTypeEmailReply00:
 		return true
 	default:
 		return false
 	}
 }
 
+// AccountEventType identifies the kind of action recorded in an account's
+// audit events
+type AccountEventType string
+
+// These types are the actions recorded in accounts' audit events
+const (
+	AccountEventNewOrder          = AccountEventType("newOrder")
+	AccountEventFinalizeOrder     = AccountEventType("finalizeOrder")
+	AccountEventRevokeCertificate = AccountEventType("revokeCertificate")
+	AccountEventKeyRollover       = AccountEventType("keyRollover")
+	AccountEventContactChange     = AccountEventType("contactChange")
+)
+
 // OCSPStatus defines the state of OCSP for a domain
 type OCSPStatus string
 
@@ -168,6 +184,24 @@ type ValidationRecord struct {
 	//   ...
 	// }
 	AddressesTried []net.IP `json:"addressesTried,omitempty"`
+
+	// ConnectTimeout and AttemptBudget are the limits the VA was configured
+	// with for the challenge's type, if any, when it made the validation, and
+	// Attempts is the number of times it attempted the validation before
+	// producing this record.
+	ConnectTimeout string `json:"connectTimeout,omitempty"`
+	AttemptBudget  string `json:"attemptBudget,omitempty"`
+	Attempts       int64  `json:"attempts,omitempty"`
+
+	// ServerHeader, NegotiatedProtocol and CertificateSHA256 describe the
+	// server which answered the validation, for later investigation: the
+	// Server header of its HTTP response, the ALPN protocol negotiated over
+	// TLS, and the hex SHA-256 fingerprint of the leaf certificate it
+	// presented. Each is only set when the validation saw it, e.g. the TLS
+	// fields for http-01 only after a redirect to HTTPS.
+	ServerHeader       string `json:"serverHeader,omitempty"`
+	NegotiatedProtocol string `json:"negotiatedProtocol,omitempty"`
+	CertificateSHA256  string `json:"certificateSHA256,omitempty"`
 }
 
 func looksLikeKeyAuthorization(str string) error {
@@ -205,9 +239,15 @@ type Challenge struct {
 	// For the V2 API the "URI" field is deprecated in favour of URL.
 	URL string `json:"url,omitempty"`
 
-	// Used by http-01, tls-sni-01, tls-alpn-01 and dns-01 challenges
+	// Used by http-01, tls-sni-01, tls-alpn-01 and dns-01 challenges. For
+	// email-reply-00 challenges it is token-part2.
 	Token string `json:"token,omitempty"`
 
+	// From is the address email-reply-00 challenge emails are sent from. It
+	// isn't stored, and is filled in by the WFE when the challenge is
+	// displayed.
+	From string `json:"from,omitempty"`
+
 	// The expected KeyAuthorization for validation of the challenge. Populated by
 	// the RA prior to passing the challenge to the VA. For legacy reasons this
 	// field is called "ProvidedKeyAuthorization" because it was initially set by
@@ -273,6 +313,13 @@ func (ch Challenge) RecordsSane() bool {
 			return false
 		}
 		return true
+	case ChallengeTypeEmailReply00:
+		// The record's Hostname is the email address the challenge email was
+		// sent to.
+		if len(ch.ValidationRecord) > 1 {
+			return false
+		}
+		return ch.ValidationRecord[0].Hostname != ""
 	default: // Unsupported challenge type
 		return false
 	}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/letsencrypt/boulder/core"
 	berrors "github.com/letsencrypt/boulder/errors"
+	"github.com/letsencrypt/boulder/features"
 	"github.com/letsencrypt/boulder/goodkey"
 	"github.com/letsencrypt/boulder/identifier"
 )
@@ -41,13 +42,18 @@ var (
 	invalidIPPresent     = berrors.BadCSRError("CSR contains one or more IP address fields")
 	invalidNoDNS         = berrors.BadCSRError("at least one DNS name is required")
 	invalidAllSANTooLong = berrors.BadCSRError("CSR doesn't contain a SAN short enough to fit in CN")
+	invalidDNSAndEmail   = berrors.BadCSRError("CSR contains both DNS names and email addresses")
 )
 
 // VerifyCSR checks the validity of a x509.CertificateRequest. Before doing checks it normalizes
 // the CSR which lowers the case of DNS names and subject CN, and hoist a DNS name into the CN
-// if it is empty.
+// if it is empty. When the EmailIdentifiers feature is enabled, CSRs with email
+// addresses instead of DNS names are accepted too (see verifyEmailCSR).
 func VerifyCSR(ctx context.Context, csr *x509.CertificateRequest, maxNames int, keyPolicy *goodkey.KeyPolicy, pa core.PolicyAuthority, regID int64) error {
-	normalizeCSR(csr)
+	emailCSR := len(csr.EmailAddresses) > 0 && features.Enabled(features.EmailIdentifiers)
+	if !emailCSR {
+		normalizeCSR(csr)
+	}
 	key, ok := csr.PublicKey.(crypto.PublicKey)
 	if !ok {
 		return invalidPubKey
@@ -64,6 +70,9 @@ func VerifyCSR(ctx context.Context, csr *x509.CertificateRequest, maxNames int,
 	if err := csr.CheckSignature(); err != nil {
 		return invalidSig
 	}
+	if emailCSR {
+		return verifyEmailCSR(csr, maxNames, pa)
+	}
 	if len(csr.EmailAddresses) > 0 {
 		return invalidEmailPresent
 	}
@@ -92,6 +101,39 @@ func VerifyCSR(ctx context.Context, csr *x509.CertificateRequest, maxNames int,
 	return nil
 }
 
+// verifyEmailCSR checks the names in a CSR for an S/MIME certificate, which
+// has only email addresses. Its CN, if any, must be one of them.
+func verifyEmailCSR(csr *x509.CertificateRequest, maxNames int, pa core.PolicyAuthority) error {
+	csr.EmailAddresses = core.UniqueLowerNames(csr.EmailAddresses)
+	csr.Subject.CommonName = strings.ToLower(csr.Subject.CommonName)
+	if len(csr.DNSNames) > 0 {
+		return invalidDNSAndEmail
+	}
+	if len(csr.IPAddresses) > 0 {
+		return invalidIPPresent
+	}
+	if csr.Subject.CommonName != "" {
+		found := false
+		for _, address := range csr.EmailAddresses {
+			if address == csr.Subject.CommonName {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return berrors.BadCSRError("CN must be one of the CSR's email addresses")
+		}
+	}
+	if len(csr.EmailAddresses) > maxNames {
+		return berrors.BadCSRError("CSR contains more than %d email addresses", maxNames)
+	}
+	idents := make([]identifier.ACMEIdentifier, len(csr.EmailAddresses))
+	for i, address := range csr.EmailAddresses {
+		idents[i] = identifier.EmailIdentifier(address)
+	}
+	return pa.WillingToIssueWildcards(idents)
+}
+
 // normalizeCSR deduplicates and lowers the case of dNSNames and the subject CN.
 // It will also hoist a dNSName into the CN if it is empty.
 func normalizeCSR(csr *x509.CertificateRequest) {
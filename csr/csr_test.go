@@ -13,6 +13,7 @@ import (
 
 	"github.com/letsencrypt/boulder/core"
 	berrors "github.com/letsencrypt/boulder/errors"
+	"github.com/letsencrypt/boulder/features"
 	"github.com/letsencrypt/boulder/goodkey"
 	"github.com/letsencrypt/boulder/identifier"
 	"github.com/letsencrypt/boulder/test"
@@ -226,3 +227,79 @@ func TestNormalizeCSR(t *testing.T) {
 		})
 	}
 }
+
+func TestVerifyEmailCSR(t *testing.T) {
+	_ = features.Set(map[string]bool{"EmailIdentifiers": true})
+	defer features.Reset()
+
+	private, err := rsa.GenerateKey(rand.Reader, 2048)
+	test.AssertNotError(t, err, "error generating test key")
+	makeCSR := func(template *x509.CertificateRequest) *x509.CertificateRequest {
+		template.SignatureAlgorithm = x509.SHA256WithRSA
+		der, err := x509.CreateCertificateRequest(rand.Reader, template, private)
+		test.AssertNotError(t, err, "error generating test CSR")
+		csr, err := x509.ParseCertificateRequest(der)
+		test.AssertNotError(t, err, "error parsing test CSR")
+		return csr
+	}
+
+	cases := []struct {
+		csr           *x509.CertificateRequest
+		maxNames      int
+		expectedError error
+	}{
+		{
+			makeCSR(&x509.CertificateRequest{EmailAddresses: []string{"User@Example.com", "user@example.com"}}),
+			1,
+			nil,
+		},
+		{
+			makeCSR(&x509.CertificateRequest{
+				Subject:        pkix.Name{CommonName: "user@example.com"},
+				EmailAddresses: []string{"user@example.com"},
+			}),
+			100,
+			nil,
+		},
+		{
+			makeCSR(&x509.CertificateRequest{
+				Subject:        pkix.Name{CommonName: "other@example.com"},
+				EmailAddresses: []string{"user@example.com"},
+			}),
+			100,
+			berrors.BadCSRError("CN must be one of the CSR's email addresses"),
+		},
+		{
+			makeCSR(&x509.CertificateRequest{
+				DNSNames:       []string{"example.com"},
+				EmailAddresses: []string{"user@example.com"},
+			}),
+			100,
+			invalidDNSAndEmail,
+		},
+		{
+			makeCSR(&x509.CertificateRequest{
+				IPAddresses:    []net.IP{net.ParseIP("1.2.3.4")},
+				EmailAddresses: []string{"user@example.com"},
+			}),
+			100,
+			invalidIPPresent,
+		},
+		{
+			makeCSR(&x509.CertificateRequest{EmailAddresses: []string{"a@example.com", "b@example.com"}}),
+			1,
+			berrors.BadCSRError("CSR contains more than 1 email addresses"),
+		},
+	}
+	for _, c := range cases {
+		err := VerifyCSR(context.Background(), c.csr, c.maxNames, testingPolicy, &mockPA{}, 0)
+		test.AssertDeepEquals(t, c.expectedError, err)
+	}
+
+	// A valid CSR has its addresses lowercased and deduplicated, and its CN
+	// left alone rather than having a DNS name hoisted into it.
+	csr := makeCSR(&x509.CertificateRequest{EmailAddresses: []string{"User@Example.com", "user@example.com"}})
+	test.AssertNotError(t, VerifyCSR(context.Background(), csr, 100, testingPolicy, &mockPA{}, 0), "VerifyCSR failed")
+	test.AssertDeepEquals(t, csr.EmailAddresses, []string{"user@example.com"})
+	test.AssertEquals(t, csr.Subject.CommonName, "")
+}
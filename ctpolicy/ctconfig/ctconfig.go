@@ -57,6 +57,21 @@ func (ts *TemporalSet) pick(exp time.Time) (*LogShard, error) {
 	return nil, fmt.Errorf("no valid shard available for temporal set %q for expiration date %q", ts.Name, exp)
 }
 
+// Log tiers, which say whether a log's SCTs count towards the SCTs required
+// for a certificate.
+const (
+	// TierRequired logs are raced against the other logs in their group, and
+	// the first SCT from any of them satisfies the group. It's the default.
+	TierRequired = "required"
+	// TierOptional logs are submitted to alongside the groups, and their SCTs
+	// are included if they arrive before the groups are satisfied, but they're
+	// never waited for.
+	TierOptional = "optional"
+	// TierInformational logs are submitted to in the background once the
+	// groups are satisfied, and their SCTs are discarded.
+	TierInformational = "informational"
+)
+
 // LogDescription contains the information needed to submit certificates
 // to a CT log and verify returned receipts. If TemporalSet is non-nil then
 // URI and Key should be empty.
@@ -64,10 +79,28 @@ type LogDescription struct {
 	URI             string
 	Key             string
 	SubmitFinalCert bool
+	// Tier is one of TierRequired, TierOptional and TierInformational. If
+	// it's empty the log is required, unless it's configured as an
+	// informational log.
+	Tier string
+	// Operator names the organization which runs the log, so that SCTs can
+	// be required from logs run by several operators.
+	Operator string
 
 	*TemporalSet
 }
 
+// GetTier returns the log's tier, or an error if it isn't a known one.
+func (ld LogDescription) GetTier() (string, error) {
+	switch ld.Tier {
+	case "":
+		return TierRequired, nil
+	case TierRequired, TierOptional, TierInformational:
+		return ld.Tier, nil
+	}
+	return "", fmt.Errorf("unknown CT log tier %q", ld.Tier)
+}
+
 // Info returns the URI and key of the log, either from a plain log description
 // or from the earliest valid shard from a temporal log set
 func (ld LogDescription) Info(exp time.Time) (string, string, error) {
@@ -3,46 +3,92 @@ package ctpolicy
 import (
 	"context"
 	"errors"
-	"math/rand"
+	"fmt"
 	"time"
 
 	"github.com/letsencrypt/boulder/canceled"
 	"github.com/letsencrypt/boulder/core"
 	"github.com/letsencrypt/boulder/ctpolicy/ctconfig"
 	berrors "github.com/letsencrypt/boulder/errors"
+	"github.com/letsencrypt/boulder/features"
 	blog "github.com/letsencrypt/boulder/log"
 	pubpb "github.com/letsencrypt/boulder/publisher/proto"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
+// queueFinalCertTimeout bounds the request asking the publisher to queue a
+// final certificate, which only has to record it, so that a publisher which
+// hangs doesn't leave a goroutine waiting on it for each issuance.
+const queueFinalCertTimeout = 5 * time.Second
+
 // CTPolicy is used to hold information about SCTs required from various
 // groupings
 type CTPolicy struct {
 	pub           core.Publisher
 	groups        []ctconfig.CTGroup
+	optional      []ctconfig.LogDescription
 	informational []ctconfig.LogDescription
 	finalLogs     []ctconfig.LogDescription
 	log           blog.Logger
+	scores        *logScores
+	// minOperators is the number of distinct log operators whose SCTs
+	// GetSCTs must return.
+	minOperators int
 
-	winnerCounter *prometheus.CounterVec
+	winnerCounter        *prometheus.CounterVec
+	optionalCounter      *prometheus.CounterVec
+	informationalCounter *prometheus.CounterVec
 }
 
-// New creates a new CTPolicy struct
+// New creates a new CTPolicy struct. Logs in the groups whose tier is
+// optional or informational are taken out of their groups, so that they
+// never count towards the SCTs a certificate requires, and the informational
+// logs are added to those given, which mustn't have any other tier.
 func New(pub core.Publisher,
 	groups []ctconfig.CTGroup,
 	informational []ctconfig.LogDescription,
 	log blog.Logger,
 	stats prometheus.Registerer,
-) *CTPolicy {
+) (*CTPolicy, error) {
+	// Copy the informational logs so that those from the groups can be added
+	// without modifying the caller's slice.
+	informational = append([]ctconfig.LogDescription(nil), informational...)
+	var required []ctconfig.CTGroup
+	var optional []ctconfig.LogDescription
 	var finalLogs []ctconfig.LogDescription
 	for _, group := range groups {
+		var logs []ctconfig.LogDescription
 		for _, log := range group.Logs {
-			if log.SubmitFinalCert {
+			tier, err := log.GetTier()
+			if err != nil {
+				return nil, fmt.Errorf("CT log group %q: %s", group.Name, err)
+			}
+			switch tier {
+			case ctconfig.TierRequired:
+				logs = append(logs, log)
+			case ctconfig.TierOptional:
+				optional = append(optional, log)
+			case ctconfig.TierInformational:
+				informational = append(informational, log)
+			}
+			if log.SubmitFinalCert && tier != ctconfig.TierInformational {
 				finalLogs = append(finalLogs, log)
 			}
 		}
+		if len(group.Logs) > 0 && len(logs) == 0 {
+			return nil, fmt.Errorf("CT log group %q has no required logs", group.Name)
+		}
+		group.Logs = logs
+		required = append(required, group)
 	}
 	for _, log := range informational {
+		tier, err := log.GetTier()
+		if err != nil {
+			return nil, err
+		}
+		if log.Tier != "" && tier != ctconfig.TierInformational {
+			return nil, fmt.Errorf("informational CT log has tier %q", tier)
+		}
 		if log.SubmitFinalCert {
 			finalLogs = append(finalLogs, log)
 		}
@@ -56,35 +102,106 @@ func New(pub core.Publisher,
 		[]string{"log", "group"},
 	)
 	stats.MustRegister(winnerCounter)
+	optionalCounter := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "sct_optional_submissions",
+			Help: "Counter of precertificate submissions to optional logs which completed before the required SCTs were obtained, by log and result.",
+		},
+		[]string{"log", "result"},
+	)
+	stats.MustRegister(optionalCounter)
+	informationalCounter := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "sct_informational_submissions",
+			Help: "Counter of precertificate submissions to informational logs, by log and result.",
+		},
+		[]string{"log", "result"},
+	)
+	stats.MustRegister(informationalCounter)
 
 	return &CTPolicy{
-		pub:           pub,
-		groups:        groups,
-		informational: informational,
-		finalLogs:     finalLogs,
-		log:           log,
-		winnerCounter: winnerCounter,
+		pub:                  pub,
+		groups:               required,
+		optional:             optional,
+		informational:        informational,
+		finalLogs:            finalLogs,
+		log:                  log,
+		scores:               newLogScores(stats),
+		winnerCounter:        winnerCounter,
+		optionalCounter:      optionalCounter,
+		informationalCounter: informationalCounter,
+	}, nil
+}
+
+// SetMinOperators requires the SCTs returned by GetSCTs to include SCTs from
+// logs run by at least n distinct operators. Only required and optional logs
+// with an Operator count towards this, and if the groups' SCTs fall short,
+// more are obtained from the required logs of other operators. It returns an
+// error if the required logs aren't run by enough operators.
+func (ctp *CTPolicy) SetMinOperators(n int) error {
+	operators := make(map[string]bool)
+	for _, g := range ctp.groups {
+		for _, log := range g.Logs {
+			if log.Operator != "" {
+				operators[log.Operator] = true
+			}
+		}
+	}
+	if len(operators) < n {
+		return fmt.Errorf("SCTs from %d distinct log operators are required, but the required logs are run by %d", n, len(operators))
 	}
+	ctp.minOperators = n
+	return nil
 }
 
 type result struct {
-	sct []byte
-	log string
-	err error
+	sct      []byte
+	log      string
+	operator string
+	err      error
+}
+
+// submit submits a certificate to a log, recording the latency and outcome
+// of the submission in the log's score unless it was canceled.
+func (ctp *CTPolicy) submit(ctx context.Context, uri, key string, cert core.CertDER, precert bool) (*pubpb.Result, error) {
+	start := time.Now()
+	sct, err := ctp.pub.SubmitToSingleCTWithResult(ctx, &pubpb.Request{
+		LogURL:       uri,
+		LogPublicKey: key,
+		Der:          cert,
+		Precert:      precert,
+	})
+	if err == nil || !canceled.Is(err) {
+		ctp.scores.observe(uri, time.Since(start), err == nil)
+	}
+	return sct, err
 }
 
 // race submits an SCT to each log in a group and waits for the first response back,
 // once it has the first SCT it cancels all of the other submissions and returns.
 // It allows up to len(group)-1 of the submissions to fail as we only care about
 // getting a single SCT.
-func (ctp *CTPolicy) race(ctx context.Context, cert core.CertDER, group ctconfig.CTGroup, expiration time.Time) ([]byte, error) {
+func (ctp *CTPolicy) race(ctx context.Context, cert core.CertDER, group ctconfig.CTGroup, expiration time.Time) (result, error) {
 	results := make(chan result, len(group.Logs))
-	isPrecert := true
-	// Randomize the order in which we send requests to the logs in a group
-	// so we maximize the distribution of logs we get SCTs from.
-	for i, logNum := range rand.Perm(len(group.Logs)) {
-		ld := group.Logs[logNum]
-		go func(i int, ld ctconfig.LogDescription) {
+	uris := make([]string, len(group.Logs))
+	keys := make([]string, len(group.Logs))
+	for i, ld := range group.Logs {
+		uri, key, err := ld.Info(expiration)
+		if err != nil {
+			ctp.log.Errf("unable to get log info: %s", err)
+			results <- result{err: err}
+			continue
+		}
+		uris[i], keys[i] = uri, key
+	}
+	// Order the requests to the logs in a group randomly, weighted by their
+	// scores, so we maximize the distribution of logs we get SCTs from while
+	// favouring those which have recently been quick to return them.
+	for i, logNum := range ctp.scores.order(uris) {
+		if uris[logNum] == "" {
+			continue
+		}
+		go func(i int, uri, key, operator string) {
 			// Each submission waits a bit longer than the previous one, to give the
 			// previous log a chance to reply. If the context is already done by the
 			// time we get here, don't bother submitting. That generally means the
@@ -93,17 +210,7 @@ func (ctp *CTPolicy) race(ctx context.Context, cert core.CertDER, group ctconfig
 			if ctx.Err() != nil {
 				return
 			}
-			uri, key, err := ld.Info(expiration)
-			if err != nil {
-				ctp.log.Errf("unable to get log info: %s", err)
-				return
-			}
-			sct, err := ctp.pub.SubmitToSingleCTWithResult(ctx, &pubpb.Request{
-				LogURL:       uri,
-				LogPublicKey: key,
-				Der:          cert,
-				Precert:      isPrecert,
-			})
+			sct, err := ctp.submit(ctx, uri, key, cert, true)
 			if err != nil {
 				// Only log the error if it is not a result of the context being canceled
 				if !canceled.Is(err) {
@@ -112,21 +219,21 @@ func (ctp *CTPolicy) race(ctx context.Context, cert core.CertDER, group ctconfig
 				results <- result{err: err}
 				return
 			}
-			results <- result{sct: sct.Sct, log: uri}
-		}(i, ld)
+			results <- result{sct: sct.Sct, log: uri, operator: operator}
+		}(i, uris[logNum], keys[logNum], group.Logs[logNum].Operator)
 	}
 
 	for i := 0; i < len(group.Logs); i++ {
 		select {
 		case <-ctx.Done():
 			ctp.winnerCounter.With(prometheus.Labels{"log": "timeout", "group": group.Name}).Inc()
-			return nil, ctx.Err()
+			return result{}, ctx.Err()
 		case res := <-results:
 			if res.sct != nil {
 				ctp.winnerCounter.With(prometheus.Labels{"log": res.log, "group": group.Name}).Inc()
 				// Return the very first SCT we get back. Returning triggers
 				// the defer'd context cancellation method.
-				return res.sct, nil
+				return res, nil
 			}
 			// We will continue waiting for an SCT until we've seen the same number
 			// of errors as there are logs in the group as we may still get a SCT
@@ -134,50 +241,54 @@ func (ctp *CTPolicy) race(ctx context.Context, cert core.CertDER, group ctconfig
 		}
 	}
 	ctp.winnerCounter.With(prometheus.Labels{"log": "all_failed", "group": group.Name}).Inc()
-	return nil, errors.New("all submissions failed")
+	return result{}, errors.New("all submissions failed")
 }
 
 // GetSCTs attempts to retrieve a SCT from each configured grouping of logs and returns
-// the set of SCTs to the caller.
+// the set of SCTs to the caller, along with any SCTs from optional logs which
+// arrived in the meantime. Once it's done the certificate is submitted to the
+// informational logs in the background, so they never delay issuance.
 func (ctp *CTPolicy) GetSCTs(ctx context.Context, cert core.CertDER, expiration time.Time) (core.SCTDERs, error) {
+	defer ctp.submitInformational(cert, expiration)
+
 	results := make(chan result, len(ctp.groups))
 	subCtx, cancel := context.WithCancel(ctx)
 	defer cancel()
 	for i, g := range ctp.groups {
 		go func(i int, g ctconfig.CTGroup) {
-			sct, err := ctp.race(subCtx, cert, g, expiration)
-			// Only one of these will be non-nil
+			res, err := ctp.race(subCtx, cert, g, expiration)
 			if err != nil {
 				results <- result{err: berrors.MissingSCTsError("CT log group %q: %s", g.Name, err)}
+				return
 			}
-			results <- result{sct: sct}
+			results <- res
 		}(i, g)
 	}
-	isPrecert := true
-	for _, log := range ctp.informational {
+	optionalResults := make(chan result, len(ctp.optional))
+	for _, log := range ctp.optional {
 		go func(l ctconfig.LogDescription) {
-			// We use a context.Background() here instead of subCtx because these
-			// submissions are running in a goroutine and we don't want them to be
-			// cancelled when the caller of CTPolicy.GetSCTs returns and cancels
-			// its RPC context.
 			uri, key, err := l.Info(expiration)
 			if err != nil {
 				ctp.log.Errf("unable to get log info: %s", err)
 				return
 			}
-			_, err = ctp.pub.SubmitToSingleCTWithResult(context.Background(), &pubpb.Request{
-				LogURL:       uri,
-				LogPublicKey: key,
-				Der:          cert,
-				Precert:      isPrecert,
-			})
+			sct, err := ctp.submit(subCtx, uri, key, cert, true)
 			if err != nil {
-				ctp.log.Warningf("ct submission to informational log %q failed: %s", uri, err)
+				// Submissions still in flight are canceled once the required SCTs
+				// have been obtained, which isn't a failure of the log.
+				if canceled.Is(err) {
+					return
+				}
+				ctp.log.Warningf("ct submission to optional log %q failed: %s", uri, err)
+				optionalResults <- result{log: uri, err: err}
+				return
 			}
+			optionalResults <- result{sct: sct.Sct, log: uri, operator: l.Operator}
 		}(log)
 	}
 
 	var ret core.SCTDERs
+	operators := make(map[string]bool)
 	for i := 0; i < len(ctp.groups); i++ {
 		res := <-results
 		// If any one group fails to get a SCT then we fail out immediately
@@ -187,13 +298,94 @@ func (ctp *CTPolicy) GetSCTs(ctx context.Context, cert core.CertDER, expiration
 			return nil, res.err
 		}
 		ret = append(ret, res.sct)
+		if res.operator != "" {
+			operators[res.operator] = true
+		}
+	}
+	// Take whatever the optional logs have returned by now, without waiting
+	// for the rest.
+optional:
+	for {
+		select {
+		case res := <-optionalResults:
+			if res.err != nil {
+				ctp.optionalCounter.With(prometheus.Labels{"log": res.log, "result": "failure"}).Inc()
+				continue
+			}
+			ctp.optionalCounter.With(prometheus.Labels{"log": res.log, "result": "success"}).Inc()
+			ret = append(ret, res.sct)
+			if res.operator != "" {
+				operators[res.operator] = true
+			}
+		default:
+			break optional
+		}
+	}
+	// If the SCTs are from too few operators, race the required logs of the
+	// operators not yet represented until there are enough.
+	for len(operators) < ctp.minOperators {
+		res, err := ctp.race(subCtx, cert, ctp.diversityGroup(operators), expiration)
+		if err != nil {
+			return nil, berrors.MissingSCTsError(
+				"SCTs from %d distinct log operators are required, but only %d were obtained: %s",
+				ctp.minOperators, len(operators), err)
+		}
+		ret = append(ret, res.sct)
+		operators[res.operator] = true
 	}
 	return ret, nil
 }
 
+// diversityGroup returns a group of the required logs, from any group, whose
+// operators aren't among those given.
+func (ctp *CTPolicy) diversityGroup(operators map[string]bool) ctconfig.CTGroup {
+	group := ctconfig.CTGroup{Name: "operator_diversity"}
+	seen := make(map[ctconfig.LogDescription]bool)
+	for _, g := range ctp.groups {
+		if g.Stagger.Duration > group.Stagger.Duration {
+			group.Stagger = g.Stagger
+		}
+		for _, log := range g.Logs {
+			if log.Operator == "" || operators[log.Operator] || seen[log] {
+				continue
+			}
+			seen[log] = true
+			group.Logs = append(group.Logs, log)
+		}
+	}
+	return group
+}
+
+// submitInformational submits a precertificate to each informational log in
+// its own goroutine, and returns without waiting for them.
+func (ctp *CTPolicy) submitInformational(cert core.CertDER, expiration time.Time) {
+	for _, log := range ctp.informational {
+		go func(l ctconfig.LogDescription) {
+			// We use a context.Background() here because these submissions are
+			// running in a goroutine and we don't want them to be cancelled when
+			// the caller of CTPolicy.GetSCTs returns and cancels its RPC context.
+			uri, key, err := l.Info(expiration)
+			if err != nil {
+				ctp.log.Errf("unable to get log info: %s", err)
+				return
+			}
+			_, err = ctp.submit(context.Background(), uri, key, cert, true)
+			if err != nil {
+				ctp.log.Warningf("ct submission to informational log %q failed: %s", uri, err)
+				ctp.informationalCounter.With(prometheus.Labels{"log": uri, "result": "failure"}).Inc()
+				return
+			}
+			ctp.informationalCounter.With(prometheus.Labels{"log": uri, "result": "success"}).Inc()
+		}(log)
+	}
+}
+
 // SubmitFinalCert submits finalized certificates created from precertificates
 // to any configured logs
 func (ctp *CTPolicy) SubmitFinalCert(cert []byte, expiration time.Time) {
+	if features.Enabled(features.QueueFinalCerts) && ctp.queueFinalCert(cert, expiration) {
+		return
+	}
 	for _, log := range ctp.finalLogs {
 		go func(l ctconfig.LogDescription) {
 			uri, key, err := l.Info(expiration)
@@ -214,3 +406,35 @@ func (ctp *CTPolicy) SubmitFinalCert(cert []byte, expiration time.Time) {
 		}(log)
 	}
 }
+
+// queueFinalCert asks the publisher to submit a final certificate to all of
+// the configured logs in the background. It returns false if the certificate
+// couldn't be queued, in which case the caller should submit it itself.
+func (ctp *CTPolicy) queueFinalCert(cert []byte, expiration time.Time) bool {
+	if len(ctp.finalLogs) == 0 {
+		return true
+	}
+	var logs []*pubpb.FinalCertLog
+	for _, l := range ctp.finalLogs {
+		uri, key, err := l.Info(expiration)
+		if err != nil {
+			ctp.log.Errf("unable to get log info: %s", err)
+			continue
+		}
+		logs = append(logs, &pubpb.FinalCertLog{LogURL: uri, LogPublicKey: key})
+	}
+	if len(logs) == 0 {
+		return true
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), queueFinalCertTimeout)
+	defer cancel()
+	_, err := ctp.pub.QueueFinalCert(ctx, &pubpb.FinalCertRequest{
+		Der:  cert,
+		Logs: logs,
+	})
+	if err != nil {
+		ctp.log.Warningf("queueing final cert for ct submission failed, submitting it directly: %s", err)
+		return false
+	}
+	return true
+}
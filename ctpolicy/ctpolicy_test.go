@@ -11,6 +11,7 @@ import (
 	"github.com/letsencrypt/boulder/core"
 	"github.com/letsencrypt/boulder/ctpolicy/ctconfig"
 	berrors "github.com/letsencrypt/boulder/errors"
+	"github.com/letsencrypt/boulder/features"
 	blog "github.com/letsencrypt/boulder/log"
 	"github.com/letsencrypt/boulder/metrics"
 	pubpb "github.com/letsencrypt/boulder/publisher/proto"
@@ -25,6 +26,10 @@ func (mp *mockPub) SubmitToSingleCTWithResult(_ context.Context, _ *pubpb.Reques
 	return &pubpb.Result{Sct: []byte{0}}, nil
 }
 
+func (mp *mockPub) QueueFinalCert(_ context.Context, _ *pubpb.FinalCertRequest) (*pubpb.FinalCertQueued, error) {
+	return &pubpb.FinalCertQueued{}, nil
+}
+
 type alwaysFail struct {
 	mockPub
 }
@@ -117,7 +122,8 @@ func TestGetSCTs(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			ctp := New(tc.mock, tc.groups, nil, blog.NewMock(), metrics.NoopRegisterer)
+			ctp, err := New(tc.mock, tc.groups, nil, blog.NewMock(), metrics.NoopRegisterer)
+			test.AssertNotError(t, err, "New failed")
 			ret, err := ctp.GetSCTs(tc.ctx, []byte{0}, time.Time{})
 			if tc.result != nil {
 				test.AssertDeepEquals(t, ret, tc.result)
@@ -156,7 +162,7 @@ func (sp *slowPublisher) SubmitToSingleCTWithResult(_ context.Context, req *pubp
 }
 
 func TestGetSCTsMetrics(t *testing.T) {
-	ctp := New(&failOne{badURL: "abc"}, []ctconfig.CTGroup{
+	ctp, err := New(&failOne{badURL: "abc"}, []ctconfig.CTGroup{
 		{
 			Name: "a",
 			Logs: []ctconfig.LogDescription{
@@ -172,7 +178,8 @@ func TestGetSCTsMetrics(t *testing.T) {
 			},
 		},
 	}, nil, blog.NewMock(), metrics.NoopRegisterer)
-	_, err := ctp.GetSCTs(context.Background(), []byte{0}, time.Time{})
+	test.AssertNotError(t, err, "New failed")
+	_, err = ctp.GetSCTs(context.Background(), []byte{0}, time.Time{})
 	test.AssertNotError(t, err, "GetSCTs failed")
 	test.AssertEquals(t, test.CountCounter(ctp.winnerCounter.With(prometheus.Labels{"log": "ghi", "group": "a"})), 1)
 	test.AssertEquals(t, test.CountCounter(ctp.winnerCounter.With(prometheus.Labels{"log": "ghi", "group": "b"})), 1)
@@ -181,7 +188,7 @@ func TestGetSCTsMetrics(t *testing.T) {
 func TestGetSCTsFailMetrics(t *testing.T) {
 	// When an entire log group fails, we should increment the "winner of SCT
 	// race" stat for that group under the fictional log "all_failed".
-	ctp := New(&failOne{badURL: "abc"}, []ctconfig.CTGroup{
+	ctp, err := New(&failOne{badURL: "abc"}, []ctconfig.CTGroup{
 		{
 			Name: "a",
 			Logs: []ctconfig.LogDescription{
@@ -189,7 +196,8 @@ func TestGetSCTsFailMetrics(t *testing.T) {
 			},
 		},
 	}, nil, blog.NewMock(), metrics.NoopRegisterer)
-	_, err := ctp.GetSCTs(context.Background(), []byte{0}, time.Time{})
+	test.AssertNotError(t, err, "New failed")
+	_, err = ctp.GetSCTs(context.Background(), []byte{0}, time.Time{})
 	if err == nil {
 		t.Fatal("GetSCTs should have failed")
 	}
@@ -199,7 +207,7 @@ func TestGetSCTsFailMetrics(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
 	defer cancel()
 
-	ctp = New(&slowPublisher{}, []ctconfig.CTGroup{
+	ctp, err = New(&slowPublisher{}, []ctconfig.CTGroup{
 		{
 			Name: "a",
 			Logs: []ctconfig.LogDescription{
@@ -207,6 +215,7 @@ func TestGetSCTsFailMetrics(t *testing.T) {
 			},
 		},
 	}, nil, blog.NewMock(), metrics.NoopRegisterer)
+	test.AssertNotError(t, err, "New failed")
 	_, err = ctp.GetSCTs(ctx, []byte{0}, time.Time{})
 	if err == nil {
 		t.Fatal("GetSCTs should have failed")
@@ -224,9 +233,13 @@ func (ce *countEm) SubmitToSingleCTWithResult(_ context.Context, _ *pubpb.Reques
 	return &pubpb.Result{Sct: []byte{0}}, nil
 }
 
+func (ce *countEm) QueueFinalCert(_ context.Context, _ *pubpb.FinalCertRequest) (*pubpb.FinalCertQueued, error) {
+	return &pubpb.FinalCertQueued{}, nil
+}
+
 func TestStagger(t *testing.T) {
 	countingPub := &countEm{}
-	ctp := New(countingPub, []ctconfig.CTGroup{
+	ctp, err := New(countingPub, []ctconfig.CTGroup{
 		{
 			Name:    "a",
 			Stagger: cmd.ConfigDuration{Duration: 500 * time.Millisecond},
@@ -236,9 +249,240 @@ func TestStagger(t *testing.T) {
 			},
 		},
 	}, nil, blog.NewMock(), metrics.NoopRegisterer)
-	_, err := ctp.GetSCTs(context.Background(), []byte{0}, time.Time{})
+	test.AssertNotError(t, err, "New failed")
+	_, err = ctp.GetSCTs(context.Background(), []byte{0}, time.Time{})
 	test.AssertNotError(t, err, "GetSCTs failed")
 	if countingPub.count != 1 {
 		t.Errorf("wrong number of requests to publisher. got %d, expected 1", countingPub.count)
 	}
 }
+
+func TestNewTiers(t *testing.T) {
+	ctp, err := New(&mockPub{}, []ctconfig.CTGroup{
+		{
+			Name: "a",
+			Logs: []ctconfig.LogDescription{
+				{URI: "required", Key: "def"},
+				{URI: "explicit", Key: "def", Tier: ctconfig.TierRequired},
+				{URI: "optional", Key: "def", Tier: ctconfig.TierOptional, SubmitFinalCert: true},
+				{URI: "informational", Key: "def", Tier: ctconfig.TierInformational, SubmitFinalCert: true},
+			},
+		},
+	}, []ctconfig.LogDescription{{URI: "other", Key: "def"}}, blog.NewMock(), metrics.NoopRegisterer)
+	test.AssertNotError(t, err, "New failed")
+	test.AssertEquals(t, len(ctp.groups), 1)
+	test.AssertEquals(t, len(ctp.groups[0].Logs), 2)
+	test.AssertEquals(t, len(ctp.optional), 1)
+	test.AssertEquals(t, ctp.optional[0].URI, "optional")
+	test.AssertEquals(t, len(ctp.informational), 2)
+	test.AssertEquals(t, len(ctp.finalLogs), 2)
+
+	// Unknown tiers are rejected.
+	_, err = New(&mockPub{}, []ctconfig.CTGroup{
+		{Name: "a", Logs: []ctconfig.LogDescription{{URI: "abc", Key: "def", Tier: "mandatory"}}},
+	}, nil, blog.NewMock(), metrics.NoopRegisterer)
+	test.AssertError(t, err, "New accepted an unknown tier")
+
+	// A group needs a required log.
+	_, err = New(&mockPub{}, []ctconfig.CTGroup{
+		{Name: "a", Logs: []ctconfig.LogDescription{{URI: "abc", Key: "def", Tier: ctconfig.TierOptional}}},
+	}, nil, blog.NewMock(), metrics.NoopRegisterer)
+	test.AssertError(t, err, "New accepted a group without required logs")
+
+	// Informational logs can't be given another tier.
+	_, err = New(&mockPub{}, nil, []ctconfig.LogDescription{
+		{URI: "abc", Key: "def", Tier: ctconfig.TierRequired},
+	}, blog.NewMock(), metrics.NoopRegisterer)
+	test.AssertError(t, err, "New accepted a required informational log")
+}
+
+// A mock publisher whose SCTs are the URIs of the logs, and which delays its
+// replies from the logs in delays, never replying if the delay is negative.
+// Submissions are reported on the submitted channel, if it's non-nil.
+type tieredPub struct {
+	delays    map[string]time.Duration
+	submitted chan string
+}
+
+func (tp *tieredPub) SubmitToSingleCTWithResult(ctx context.Context, req *pubpb.Request) (*pubpb.Result, error) {
+	if tp.submitted != nil {
+		defer func() { tp.submitted <- req.LogURL }()
+	}
+	delay := tp.delays[req.LogURL]
+	if delay < 0 {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	time.Sleep(delay)
+	return &pubpb.Result{Sct: []byte(req.LogURL)}, nil
+}
+
+func (tp *tieredPub) QueueFinalCert(_ context.Context, _ *pubpb.FinalCertRequest) (*pubpb.FinalCertQueued, error) {
+	return &pubpb.FinalCertQueued{}, nil
+}
+
+func TestGetSCTsTiers(t *testing.T) {
+	groups := []ctconfig.CTGroup{
+		{
+			Name: "a",
+			Logs: []ctconfig.LogDescription{
+				{URI: "required", Key: "def"},
+				{URI: "fast", Key: "def", Tier: ctconfig.TierOptional},
+				{URI: "slow", Key: "def", Tier: ctconfig.TierOptional},
+			},
+		},
+	}
+	informational := []ctconfig.LogDescription{{URI: "informational", Key: "def"}}
+	pub := &tieredPub{
+		delays: map[string]time.Duration{
+			"required":      50 * time.Millisecond,
+			"slow":          -1,
+			"informational": -1,
+		},
+	}
+	ctp, err := New(pub, groups, informational, blog.NewMock(), metrics.NoopRegisterer)
+	test.AssertNotError(t, err, "New failed")
+
+	// The optional log which replied before the required one is included, and
+	// neither the slow optional log nor the informational log, which never
+	// replies, hold up GetSCTs.
+	ret, err := ctp.GetSCTs(context.Background(), []byte{0}, time.Time{})
+	test.AssertNotError(t, err, "GetSCTs failed")
+	test.AssertDeepEquals(t, ret, core.SCTDERs{[]byte("required"), []byte("fast")})
+	test.AssertEquals(t, test.CountCounter(ctp.optionalCounter.With(prometheus.Labels{"log": "fast", "result": "success"})), 1)
+	test.AssertEquals(t, test.CountCounter(ctp.optionalCounter.With(prometheus.Labels{"log": "slow", "result": "success"})), 0)
+
+	// Informational submissions are made once the required SCTs have been
+	// obtained, and counted.
+	pub = &tieredPub{submitted: make(chan string, 4)}
+	ctp, err = New(pub, groups, informational, blog.NewMock(), metrics.NoopRegisterer)
+	test.AssertNotError(t, err, "New failed")
+	_, err = ctp.GetSCTs(context.Background(), []byte{0}, time.Time{})
+	test.AssertNotError(t, err, "GetSCTs failed")
+	for i := 0; i < 4; i++ {
+		select {
+		case <-pub.submitted:
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for submissions")
+		}
+	}
+	// The counter is incremented just after the submission is reported.
+	for i := 0; i < 100; i++ {
+		if test.CountCounter(ctp.informationalCounter.With(prometheus.Labels{"log": "informational", "result": "success"})) == 1 {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Error("informational submission wasn't counted")
+}
+
+func TestMinOperators(t *testing.T) {
+	groups := []ctconfig.CTGroup{
+		{
+			Name: "a",
+			Logs: []ctconfig.LogDescription{
+				{URI: "a1", Key: "def", Operator: "X"},
+				{URI: "a2", Key: "def", Operator: "Y"},
+			},
+		},
+		{
+			Name: "b",
+			Logs: []ctconfig.LogDescription{
+				{URI: "b1", Key: "def", Operator: "X"},
+			},
+		},
+	}
+
+	// The required logs must be run by enough operators.
+	ctp, err := New(&mockPub{}, groups, nil, blog.NewMock(), metrics.NoopRegisterer)
+	test.AssertNotError(t, err, "New failed")
+	err = ctp.SetMinOperators(3)
+	test.AssertError(t, err, "SetMinOperators accepted more operators than there are")
+
+	// When the groups' SCTs are all from one operator, another is obtained
+	// from a log run by a different one.
+	pub := &tieredPub{delays: map[string]time.Duration{"a2": 50 * time.Millisecond}}
+	ctp, err = New(pub, groups, nil, blog.NewMock(), metrics.NoopRegisterer)
+	test.AssertNotError(t, err, "New failed")
+	test.AssertNotError(t, ctp.SetMinOperators(2), "SetMinOperators failed")
+	ret, err := ctp.GetSCTs(context.Background(), []byte{0}, time.Time{})
+	test.AssertNotError(t, err, "GetSCTs failed")
+	test.AssertEquals(t, len(ret), 3)
+	test.AssertDeepEquals(t, ret[2], []byte("a2"))
+
+	// If no other operator's log returns an SCT, GetSCTs fails.
+	ctp, err = New(&failOne{badURL: "a2"}, groups, nil, blog.NewMock(), metrics.NoopRegisterer)
+	test.AssertNotError(t, err, "New failed")
+	test.AssertNotError(t, ctp.SetMinOperators(2), "SetMinOperators failed")
+	_, err = ctp.GetSCTs(context.Background(), []byte{0}, time.Time{})
+	test.AssertError(t, err, "GetSCTs succeeded without SCTs from enough operators")
+	test.AssertErrorIs(t, err, berrors.MissingSCTs)
+}
+
+// A mock publisher which records the final certificates queued with it, and
+// the logs submitted to directly, failing to queue if queueErr is set or the
+// request has no deadline.
+type queueingPub struct {
+	mockPub
+
+	queueErr  error
+	queued    chan *pubpb.FinalCertRequest
+	submitted chan string
+}
+
+func (qp *queueingPub) SubmitToSingleCTWithResult(_ context.Context, req *pubpb.Request) (*pubpb.Result, error) {
+	qp.submitted <- req.LogURL
+	return &pubpb.Result{Sct: []byte{0}}, nil
+}
+
+func (qp *queueingPub) QueueFinalCert(ctx context.Context, req *pubpb.FinalCertRequest) (*pubpb.FinalCertQueued, error) {
+	qp.queued <- req
+	if _, ok := ctx.Deadline(); !ok {
+		return nil, errors.New("queueing request has no deadline")
+	}
+	if qp.queueErr != nil {
+		return nil, qp.queueErr
+	}
+	return &pubpb.FinalCertQueued{}, nil
+}
+
+func TestSubmitFinalCertQueued(t *testing.T) {
+	err := features.Set(map[string]bool{"QueueFinalCerts": true})
+	test.AssertNotError(t, err, "setting feature")
+	defer features.Reset()
+
+	groups := []ctconfig.CTGroup{
+		{
+			Name: "a",
+			Logs: []ctconfig.LogDescription{
+				{URI: "required", Key: "abc", SubmitFinalCert: true},
+				{URI: "other", Key: "def"},
+			},
+		},
+	}
+	informational := []ctconfig.LogDescription{{URI: "informational", Key: "ghi", SubmitFinalCert: true}}
+	pub := &queueingPub{
+		queued:    make(chan *pubpb.FinalCertRequest, 1),
+		submitted: make(chan string, 2),
+	}
+	ctp, err := New(pub, groups, informational, blog.NewMock(), metrics.NoopRegisterer)
+	test.AssertNotError(t, err, "New failed")
+
+	// All of the final logs are sent to the publisher in a single request,
+	// and none are submitted to directly.
+	ctp.SubmitFinalCert([]byte{1}, time.Time{})
+	req := <-pub.queued
+	test.AssertByteEquals(t, req.Der, []byte{1})
+	test.AssertEquals(t, len(req.Logs), 2)
+	test.AssertEquals(t, req.Logs[0].LogURL, "required")
+	test.AssertEquals(t, req.Logs[0].LogPublicKey, "abc")
+	test.AssertEquals(t, req.Logs[1].LogURL, "informational")
+	test.AssertEquals(t, len(pub.submitted), 0)
+
+	// If the certificate can't be queued it's submitted to each log directly.
+	pub.queueErr = errors.New("queue unavailable")
+	ctp.SubmitFinalCert([]byte{1}, time.Time{})
+	<-pub.queued
+	submitted := map[string]bool{<-pub.submitted: true, <-pub.submitted: true}
+	test.Assert(t, submitted["required"] && submitted["informational"], "final cert wasn't submitted to each log")
+}
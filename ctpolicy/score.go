@@ -0,0 +1,128 @@
+package ctpolicy
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// scoreDecay is the weight given to each new submission in a log's
+	// rolling latency and success rate.
+	scoreDecay = 0.2
+	// initialLatency is the latency assumed for a log before any submissions
+	// to it have completed.
+	initialLatency = time.Second
+	// minSuccessRate and minLatency bound a log's score, so that even a log
+	// which has been failing still gets some submissions and can recover.
+	minSuccessRate = 0.05
+	minLatency     = 10 * time.Millisecond
+)
+
+// logStats holds the rolling latency, in seconds, and success rate of the
+// recent submissions to a log.
+type logStats struct {
+	latency     float64
+	successRate float64
+}
+
+// logScores scores logs by the latency and success rate of the recent
+// submissions to them, so that the logs which are likely to return an SCT
+// soonest are submitted to first.
+type logScores struct {
+	mu    sync.Mutex
+	stats map[string]*logStats
+
+	gauge *prometheus.GaugeVec
+}
+
+func newLogScores(stats prometheus.Registerer) *logScores {
+	gauge := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "sct_log_score",
+			Help: "Score of each log, from the rolling latency and success rate of submissions to it, by which logs are ordered in SCT races.",
+		},
+		[]string{"log"},
+	)
+	stats.MustRegister(gauge)
+	return &logScores{stats: make(map[string]*logStats), gauge: gauge}
+}
+
+// observe records the outcome of a submission to the log.
+func (s *logScores) observe(uri string, latency time.Duration, success bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st, ok := s.stats[uri]
+	if !ok {
+		st = &logStats{latency: initialLatency.Seconds(), successRate: 1}
+		s.stats[uri] = st
+	}
+	outcome := 0.0
+	if success {
+		outcome = 1
+	}
+	st.latency += scoreDecay * (latency.Seconds() - st.latency)
+	st.successRate += scoreDecay * (outcome - st.successRate)
+	s.gauge.With(prometheus.Labels{"log": uri}).Set(st.score())
+}
+
+// score is the expected rate of SCTs from the log: its success rate divided
+// by its latency.
+func (st *logStats) score() float64 {
+	successRate, latency := st.successRate, st.latency
+	if successRate < minSuccessRate {
+		successRate = minSuccessRate
+	}
+	if latency < minLatency.Seconds() {
+		latency = minLatency.Seconds()
+	}
+	return successRate / latency
+}
+
+// score returns the log's score. Logs with no completed submissions get the
+// score of a log which succeeds in initialLatency.
+func (s *logScores) score(uri string) float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st, ok := s.stats[uri]
+	if !ok {
+		return (&logStats{latency: initialLatency.Seconds(), successRate: 1}).score()
+	}
+	return st.score()
+}
+
+// order returns a random permutation of the indices of the logs, in which
+// each position is filled by one of the remaining logs with a probability
+// proportional to its score. Better logs therefore tend to come first, while
+// submissions are still spread across all of them.
+func (s *logScores) order(uris []string) []int {
+	weights := make([]float64, len(uris))
+	var total float64
+	for i, uri := range uris {
+		weights[i] = s.score(uri)
+		total += weights[i]
+	}
+	remaining := make([]int, len(uris))
+	for i := range remaining {
+		remaining[i] = i
+	}
+	var order []int
+	for len(remaining) > 0 {
+		pick := rand.Float64() * total
+		chosen := len(remaining) - 1
+		for j, i := range remaining {
+			pick -= weights[i]
+			if pick < 0 {
+				chosen = j
+				break
+			}
+		}
+		i := remaining[chosen]
+		order = append(order, i)
+		total -= weights[i]
+		remaining = append(remaining[:chosen], remaining[chosen+1:]...)
+	}
+	return order
+}
@@ -0,0 +1,41 @@
+package ctpolicy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/letsencrypt/boulder/metrics"
+	"github.com/letsencrypt/boulder/test"
+)
+
+func TestLogScores(t *testing.T) {
+	s := newLogScores(metrics.NoopRegisterer)
+
+	// Logs start out with the same score.
+	test.AssertEquals(t, s.score("fast"), s.score("slow"))
+
+	for i := 0; i < 20; i++ {
+		s.observe("fast", 50*time.Millisecond, true)
+		s.observe("slow", 2*time.Second, true)
+		s.observe("failing", 50*time.Millisecond, false)
+	}
+	test.Assert(t, s.score("fast") > s.score("slow"), "fast log didn't score better than slow log")
+	test.Assert(t, s.score("fast") > s.score("failing"), "fast log didn't score better than failing log")
+	test.Assert(t, s.score("failing") > 0, "failing log's score wasn't positive")
+
+	// The best log usually comes first, but every log is always included.
+	uris := []string{"slow", "failing", "fast"}
+	first := make(map[string]int)
+	for i := 0; i < 1000; i++ {
+		order := s.order(uris)
+		test.AssertEquals(t, len(order), len(uris))
+		seen := make(map[int]bool)
+		for _, i := range order {
+			seen[i] = true
+		}
+		test.AssertEquals(t, len(seen), len(uris))
+		first[uris[order[0]]]++
+	}
+	test.Assert(t, first["fast"] > first["slow"], "fast log wasn't first more often than slow log")
+	test.Assert(t, first["fast"] > first["failing"], "fast log wasn't first more often than failing log")
+}
@@ -52,6 +52,11 @@ func (e ErrDatabaseOp) Error() string {
 		e.Err)
 }
 
+// Unwrap returns the underlying error.
+func (e ErrDatabaseOp) Unwrap() error {
+	return e.Err
+}
+
 // IsNoRows is a utility function for casting an error to ErrDatabaseOp and
 // returning true if its wrapped err is sql.ErrNoRows. If the error is not an
 // ErrDatabaseOp the return value of IsNoRows will always be false.
@@ -267,7 +272,7 @@ var (
 	// selectTableRegexp matches the table name from an SQL select statement
 	selectTableRegexp = regexp.MustCompile(`(?i)^\s*select\s+[a-z\d:\.\(\), \_\*` + "`" + `]+\s+from\s+([a-z\d\_,` + "`" + `]+)`)
 	// insertTableRegexp matches the table name from an SQL insert statement
-	insertTableRegexp = regexp.MustCompile(`(?i)^\s*insert\s+into\s+([a-z\d \_,` + "`" + `]+)\s+(?:set|\()`)
+	insertTableRegexp = regexp.MustCompile(`(?i)^\s*insert\s+(?:ignore\s+)?into\s+([a-z\d \_,` + "`" + `]+)\s+(?:set|\()`)
 	// updateTableRegexp matches the table name from an SQL update statement
 	updateTableRegexp = regexp.MustCompile(`(?i)^\s*update\s+([a-z\d \_,` + "`" + `]+)\s+set`)
 	// deleteTableRegexp matches the table name from an SQL delete statement
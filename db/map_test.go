@@ -197,6 +197,10 @@ func TestTableFromQuery(t *testing.T) {
 			query:         "insert into `fqdnSets` (`ID`,`SetHash`,`Serial`,`Issued`,`Expires`) values (null,?,?,?,?);",
 			expectedTable: "`fqdnSets`",
 		},
+		{
+			query:         "INSERT IGNORE INTO incidentSerials (incidentID,serial,registrationID) VALUES (?,?,?)",
+			expectedTable: "incidentSerials",
+		},
 		{
 			query:         "UPDATE orders SET certificateSerial = ? WHERE id = ? AND beganProcessing = true",
 			expectedTable: "orders",
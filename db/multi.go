@@ -0,0 +1,160 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MultiInserter makes it easy to construct a
+// `INSERT INTO table (...) VALUES ... [RETURNING id];`
+// query which inserts multiple rows into the same table. It can also execute
+// the resulting query, splitting the rows across several statements if there
+// are more of them than the configured maximum batch size.
+type MultiInserter struct {
+	table        string
+	fields       []string
+	retCol       string
+	maxBatchSize int
+	ignore       bool
+
+	values [][]interface{}
+}
+
+// NewMultiInserter creates a new MultiInserter, checking for reasonable table
+// name and list of fields. If retCol is not empty, the inserted rows' values
+// for that column (typically an auto-increment ID) are returned by Insert, in
+// the order the rows were added. A maxBatchSize less than one means that all
+// rows are inserted with a single statement.
+func NewMultiInserter(table string, fields []string, retCol string, maxBatchSize int) (*MultiInserter, error) {
+	if len(table) == 0 || len(fields) == 0 {
+		return nil, fmt.Errorf("empty table name or fields list")
+	}
+	if strings.Contains(table, " ") {
+		return nil, fmt.Errorf("table name %q contains illegal character", table)
+	}
+	for _, field := range fields {
+		if strings.Contains(field, " ") {
+			return nil, fmt.Errorf("field name %q contains illegal character", field)
+		}
+	}
+	if strings.Contains(retCol, " ") {
+		return nil, fmt.Errorf("return column name %q contains illegal character", retCol)
+	}
+	return &MultiInserter{
+		table:        table,
+		fields:       fields,
+		retCol:       retCol,
+		maxBatchSize: maxBatchSize,
+	}, nil
+}
+
+// Add registers another row to be included in the Insert query.
+func (mi *MultiInserter) Add(row []interface{}) error {
+	if len(row) != len(mi.fields) {
+		return fmt.Errorf("field count mismatch, got %d, expected %d", len(row), len(mi.fields))
+	}
+	mi.values = append(mi.values, row)
+	return nil
+}
+
+// IgnoreDuplicates causes the MultiInserter to use `INSERT IGNORE`, so rows
+// which would violate a unique key are silently skipped rather than causing
+// the whole statement to fail. It can't be combined with a return column,
+// since skipped rows don't return a value.
+func (mi *MultiInserter) IgnoreDuplicates() {
+	mi.ignore = true
+}
+
+// Len returns the number of rows which have been added to the MultiInserter.
+func (mi *MultiInserter) Len() int {
+	return len(mi.values)
+}
+
+// batches splits the added rows into groups of at most maxBatchSize rows.
+func (mi *MultiInserter) batches() [][][]interface{} {
+	if mi.maxBatchSize < 1 || len(mi.values) <= mi.maxBatchSize {
+		return [][][]interface{}{mi.values}
+	}
+	var batches [][][]interface{}
+	for start := 0; start < len(mi.values); start += mi.maxBatchSize {
+		end := start + mi.maxBatchSize
+		if end > len(mi.values) {
+			end = len(mi.values)
+		}
+		batches = append(batches, mi.values[start:end])
+	}
+	return batches
+}
+
+// query returns the formatted query string and the flattened arguments for a
+// single batch of rows.
+func (mi *MultiInserter) query(rows [][]interface{}) (string, []interface{}) {
+	questionsRow := strings.TrimRight(strings.Repeat("?,", len(mi.fields)), ",")
+
+	var questionsBuf strings.Builder
+	var queryArgs []interface{}
+	for i, row := range rows {
+		if i > 0 {
+			questionsBuf.WriteString(",")
+		}
+		fmt.Fprintf(&questionsBuf, "(%s)", questionsRow)
+		queryArgs = append(queryArgs, row...)
+	}
+
+	returning := ""
+	if mi.retCol != "" {
+		returning = fmt.Sprintf(" RETURNING %s", mi.retCol)
+	}
+
+	ignore := ""
+	if mi.ignore {
+		ignore = "IGNORE "
+	}
+
+	query := fmt.Sprintf(
+		"INSERT %sINTO %s (%s) VALUES %s%s",
+		ignore,
+		mi.table,
+		strings.Join(mi.fields, ","),
+		questionsBuf.String(),
+		returning,
+	)
+
+	return query, queryArgs
+}
+
+// Insert performs the constructed queries against the provided executor. If
+// the MultiInserter was constructed with a return column, the values of that
+// column for every inserted row are returned. By convention the executor
+// should have a context applied, and should be a transaction if the rows must
+// be inserted atomically.
+func (mi *MultiInserter) Insert(exec SelectExecer) ([]int64, error) {
+	if len(mi.values) == 0 {
+		return nil, nil
+	}
+	if mi.ignore && mi.retCol != "" {
+		return nil, fmt.Errorf("can't return %s values when ignoring duplicates", mi.retCol)
+	}
+
+	var ids []int64
+	for _, rows := range mi.batches() {
+		query, queryArgs := mi.query(rows)
+		if mi.retCol == "" {
+			_, err := exec.Exec(query, queryArgs...)
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+		var batchIDs []int64
+		_, err := exec.Select(&batchIDs, query, queryArgs...)
+		if err != nil {
+			return nil, err
+		}
+		if len(batchIDs) != len(rows) {
+			return nil, fmt.Errorf("inserted %d rows into %s but got %d returned values", len(rows), mi.table, len(batchIDs))
+		}
+		ids = append(ids, batchIDs...)
+	}
+	return ids, nil
+}
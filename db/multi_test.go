@@ -0,0 +1,127 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/letsencrypt/boulder/test"
+)
+
+func TestNewMulti(t *testing.T) {
+	_, err := NewMultiInserter("", []string{"colA"}, "", 0)
+	test.AssertError(t, err, "Empty table name should fail")
+
+	_, err = NewMultiInserter("myTable", nil, "", 0)
+	test.AssertError(t, err, "Empty fields list should fail")
+
+	_, err = NewMultiInserter("myTable", []string{"colA", "col B"}, "", 0)
+	test.AssertError(t, err, "Field names with spaces should fail")
+
+	mi, err := NewMultiInserter("myTable", []string{"colA", "colB", "colC"}, "id", 0)
+	test.AssertNotError(t, err, "Failed to create MultiInserter")
+	test.AssertEquals(t, mi.table, "myTable")
+	test.AssertEquals(t, mi.retCol, "id")
+	test.AssertEquals(t, len(mi.fields), 3)
+}
+
+func TestMultiAdd(t *testing.T) {
+	mi, err := NewMultiInserter("myTable", []string{"colA", "colB", "colC"}, "", 0)
+	test.AssertNotError(t, err, "Failed to create MultiInserter")
+
+	err = mi.Add([]interface{}{})
+	test.AssertError(t, err, "Adding an empty row should fail")
+
+	err = mi.Add([]interface{}{"foo"})
+	test.AssertError(t, err, "Adding a short row should fail")
+
+	err = mi.Add([]interface{}{"foo", "bar", "baz", "bing"})
+	test.AssertError(t, err, "Adding a long row should fail")
+
+	err = mi.Add([]interface{}{"one", "two", "three"})
+	test.AssertNotError(t, err, "Adding a correct row should succeed")
+	test.AssertEquals(t, mi.Len(), 1)
+}
+
+func TestMultiQuery(t *testing.T) {
+	mi, err := NewMultiInserter("myTable", []string{"colA", "colB", "colC"}, "", 0)
+	test.AssertNotError(t, err, "Failed to create MultiInserter")
+	_ = mi.Add([]interface{}{"one", "two", "three"})
+	_ = mi.Add([]interface{}{"egy", "kettö", "három"})
+
+	query, queryArgs := mi.query(mi.values)
+	test.AssertEquals(t, query, "INSERT INTO myTable (colA,colB,colC) VALUES (?,?,?),(?,?,?)")
+	test.AssertDeepEquals(t, queryArgs, []interface{}{"one", "two", "three", "egy", "kettö", "három"})
+
+	mi, err = NewMultiInserter("myTable", []string{"colA", "colB", "colC"}, "id", 0)
+	test.AssertNotError(t, err, "Failed to create MultiInserter")
+	_ = mi.Add([]interface{}{"one", "two", "three"})
+
+	query, _ = mi.query(mi.values)
+	test.AssertEquals(t, query, "INSERT INTO myTable (colA,colB,colC) VALUES (?,?,?) RETURNING id")
+
+	mi, err = NewMultiInserter("myTable", []string{"colA", "colB", "colC"}, "", 0)
+	test.AssertNotError(t, err, "Failed to create MultiInserter")
+	mi.IgnoreDuplicates()
+	_ = mi.Add([]interface{}{"one", "two", "three"})
+
+	query, _ = mi.query(mi.values)
+	test.AssertEquals(t, query, "INSERT IGNORE INTO myTable (colA,colB,colC) VALUES (?,?,?)")
+}
+
+// fakeSelectExecer records the queries it is asked to run and returns
+// sequential IDs for queries with a RETURNING clause.
+type fakeSelectExecer struct {
+	queries []string
+	nextID  int64
+	err     error
+}
+
+func (f *fakeSelectExecer) Select(holder interface{}, query string, args ...interface{}) ([]interface{}, error) {
+	f.queries = append(f.queries, query)
+	if f.err != nil {
+		return nil, f.err
+	}
+	ids := holder.(*[]int64)
+	// Every row in the fake tables has exactly two columns.
+	for i := 0; i < len(args)/2; i++ {
+		f.nextID++
+		*ids = append(*ids, f.nextID)
+	}
+	return nil, nil
+}
+
+func (f *fakeSelectExecer) Exec(query string, args ...interface{}) (sql.Result, error) {
+	f.queries = append(f.queries, query)
+	return nil, f.err
+}
+
+func TestMultiInsertBatches(t *testing.T) {
+	mi, err := NewMultiInserter("myTable", []string{"colA", "colB"}, "id", 2)
+	test.AssertNotError(t, err, "Failed to create MultiInserter")
+	for i := 0; i < 5; i++ {
+		_ = mi.Add([]interface{}{i, i})
+	}
+
+	exec := &fakeSelectExecer{}
+	ids, err := mi.Insert(exec)
+	test.AssertNotError(t, err, "Insert failed")
+	test.AssertDeepEquals(t, ids, []int64{1, 2, 3, 4, 5})
+	test.AssertEquals(t, len(exec.queries), 3)
+	test.AssertEquals(t, exec.queries[2], "INSERT INTO myTable (colA,colB) VALUES (?,?) RETURNING id")
+
+	mi, err = NewMultiInserter("myTable", []string{"colA", "colB"}, "", 0)
+	test.AssertNotError(t, err, "Failed to create MultiInserter")
+	for i := 0; i < 5; i++ {
+		_ = mi.Add([]interface{}{i, i})
+	}
+	exec = &fakeSelectExecer{}
+	ids, err = mi.Insert(exec)
+	test.AssertNotError(t, err, "Insert failed")
+	test.AssertEquals(t, len(ids), 0)
+	test.AssertEquals(t, len(exec.queries), 1)
+
+	exec = &fakeSelectExecer{err: errors.New("oops")}
+	_, err = mi.Insert(exec)
+	test.AssertError(t, err, "Insert should have failed")
+}
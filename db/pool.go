@@ -0,0 +1,179 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	blog "github.com/letsencrypt/boulder/log"
+)
+
+// AdaptivePoolConfig bounds the maximum number of open connections an
+// AdaptivePool may set, and says when to change it.
+type AdaptivePoolConfig struct {
+	// MinOpenConns and MaxOpenConns bound the pool's maximum number of open
+	// connections.
+	MinOpenConns int
+	MaxOpenConns int
+	// MaxIdleConns is the configured maximum number of idle connections,
+	// which database/sql lowers whenever the maximum number of open
+	// connections drops below it. It's restored, as far as the maximum number
+	// of open connections allows, when the pool grows. Zero leaves it alone.
+	MaxIdleConns int
+	// TargetWait is the average time spent waiting for a connection above
+	// which the pool grows.
+	TargetWait time.Duration
+	// Interval is how often the pool's size is reconsidered.
+	Interval time.Duration
+}
+
+// poolDB is the part of a *sql.DB an AdaptivePool resizes.
+type poolDB interface {
+	Stats() sql.DBStats
+	SetMaxOpenConns(n int)
+	SetMaxIdleConns(n int)
+}
+
+// AdaptivePool grows and shrinks a database's maximum number of open
+// connections between configured bounds. Each interval, if callers waited for
+// connections longer than the target on average it grows by a quarter plus
+// one, and if nobody waited and fewer than half the connections are in use it
+// shrinks by an eighth plus one.
+type AdaptivePool struct {
+	db      poolDB
+	config  AdaptivePoolConfig
+	log     blog.Logger
+	resizes *prometheus.CounterVec
+
+	mu        sync.Mutex
+	size      int
+	lastStats sql.DBStats
+	stop      chan struct{}
+}
+
+// NewAdaptivePool returns an AdaptivePool for db, which starts out allowing
+// config.MaxOpenConns connections. It doesn't resize the pool until Start is
+// called.
+func NewAdaptivePool(db *sql.DB, config AdaptivePoolConfig, stats prometheus.Registerer, logger blog.Logger) (*AdaptivePool, error) {
+	return newAdaptivePool(db, config, stats, logger)
+}
+
+func newAdaptivePool(db poolDB, config AdaptivePoolConfig, stats prometheus.Registerer, logger blog.Logger) (*AdaptivePool, error) {
+	if config.MinOpenConns < 1 || config.MaxOpenConns < config.MinOpenConns {
+		return nil, fmt.Errorf("adaptive pool bounds must satisfy 1 <= MinOpenConns (%d) <= MaxOpenConns (%d)",
+			config.MinOpenConns, config.MaxOpenConns)
+	}
+	if config.TargetWait <= 0 || config.Interval <= 0 {
+		return nil, errors.New("adaptive pool TargetWait and Interval must be positive")
+	}
+	resizes := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "db_adaptive_pool_resizes",
+		Help: "Number of times the adaptive DB pool changed its maximum number of open connections, by direction",
+	}, []string{"direction"})
+	stats.MustRegister(resizes)
+
+	p := &AdaptivePool{
+		db:        db,
+		config:    config,
+		log:       logger,
+		resizes:   resizes,
+		size:      config.MaxOpenConns,
+		lastStats: db.Stats(),
+	}
+	p.setSize(config.MaxOpenConns)
+	return p, nil
+}
+
+// Start resizes the pool every interval until Stop is called.
+func (p *AdaptivePool) Start() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.stop != nil {
+		return
+	}
+	p.stop = make(chan struct{})
+	go func(stop <-chan struct{}) {
+		ticker := time.NewTicker(p.config.Interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				p.adjust()
+			case <-stop:
+				return
+			}
+		}
+	}(p.stop)
+}
+
+// Stop stops resizing the pool, leaving it at its current size.
+func (p *AdaptivePool) Stop() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.stop != nil {
+		close(p.stop)
+		p.stop = nil
+	}
+}
+
+// adjust resizes the pool based on the waits for connections since it was
+// last called.
+func (p *AdaptivePool) adjust() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	stats := p.db.Stats()
+	waits := stats.WaitCount - p.lastStats.WaitCount
+	waited := stats.WaitDuration - p.lastStats.WaitDuration
+	p.lastStats = stats
+
+	size := p.size
+	switch {
+	case waits > 0 && waited/time.Duration(waits) > p.config.TargetWait:
+		size += p.size/4 + 1
+		if size > p.config.MaxOpenConns {
+			size = p.config.MaxOpenConns
+		}
+	case waits == 0 && stats.InUse < p.size/2:
+		size -= p.size/8 + 1
+		if size < p.config.MinOpenConns {
+			size = p.config.MinOpenConns
+		}
+	}
+	if size == p.size {
+		return
+	}
+
+	direction := "grow"
+	if size < p.size {
+		direction = "shrink"
+	}
+	p.resizes.WithLabelValues(direction).Inc()
+	p.log.Infof("adaptive DB pool: changing max open connections from %d to %d (%d waits, %s waited, %d in use)",
+		p.size, size, waits, waited, stats.InUse)
+	p.size = size
+	p.setSize(size)
+}
+
+// setSize sets the maximum number of open connections, and restores the
+// configured maximum number of idle connections as far as it allows.
+func (p *AdaptivePool) setSize(size int) {
+	p.db.SetMaxOpenConns(size)
+	if p.config.MaxIdleConns > 0 {
+		idle := p.config.MaxIdleConns
+		if idle > size {
+			idle = size
+		}
+		p.db.SetMaxIdleConns(idle)
+	}
+}
+
+// Size returns the pool's current maximum number of open connections.
+func (p *AdaptivePool) Size() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.size
+}
@@ -0,0 +1,93 @@
+package db
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	blog "github.com/letsencrypt/boulder/log"
+	"github.com/letsencrypt/boulder/metrics"
+	"github.com/letsencrypt/boulder/test"
+)
+
+// fakePoolDB records the limits set on it, and reports whatever stats it's
+// given.
+type fakePoolDB struct {
+	stats    sql.DBStats
+	maxOpen  int
+	maxIdle  int
+	setCalls int
+}
+
+func (db *fakePoolDB) Stats() sql.DBStats    { return db.stats }
+func (db *fakePoolDB) SetMaxOpenConns(n int) { db.maxOpen = n; db.setCalls++ }
+func (db *fakePoolDB) SetMaxIdleConns(n int) { db.maxIdle = n }
+
+func TestAdaptivePoolConfig(t *testing.T) {
+	for _, config := range []AdaptivePoolConfig{
+		{MinOpenConns: 0, MaxOpenConns: 10, TargetWait: time.Millisecond, Interval: time.Second},
+		{MinOpenConns: 11, MaxOpenConns: 10, TargetWait: time.Millisecond, Interval: time.Second},
+		{MinOpenConns: 1, MaxOpenConns: 10, Interval: time.Second},
+		{MinOpenConns: 1, MaxOpenConns: 10, TargetWait: time.Millisecond},
+	} {
+		_, err := newAdaptivePool(&fakePoolDB{}, config, metrics.NoopRegisterer, blog.NewMock())
+		test.AssertError(t, err, "invalid adaptive pool config was accepted")
+	}
+}
+
+func TestAdaptivePool(t *testing.T) {
+	fake := &fakePoolDB{}
+	stats := prometheus.NewRegistry()
+	p, err := newAdaptivePool(fake, AdaptivePoolConfig{
+		MinOpenConns: 4,
+		MaxOpenConns: 40,
+		MaxIdleConns: 10,
+		TargetWait:   10 * time.Millisecond,
+		Interval:     time.Second,
+	}, stats, blog.NewMock())
+	test.AssertNotError(t, err, "newAdaptivePool failed")
+	test.AssertEquals(t, fake.maxOpen, 40)
+	test.AssertEquals(t, fake.maxIdle, 10)
+
+	// With nothing waiting and little in use, the pool shrinks to its
+	// minimum, taking the idle limit with it.
+	fake.stats.InUse = 1
+	for i := 0; i < 20; i++ {
+		p.adjust()
+	}
+	test.AssertEquals(t, p.Size(), 4)
+	test.AssertEquals(t, fake.maxOpen, 4)
+	test.AssertEquals(t, fake.maxIdle, 4)
+	test.AssertEquals(t, test.CountCounterVec("direction", "shrink", p.resizes), 13)
+
+	// Short waits don't grow the pool, and busy pools don't shrink.
+	fake.stats.InUse = 4
+	fake.stats.WaitCount = 10
+	fake.stats.WaitDuration = 50 * time.Millisecond
+	p.adjust()
+	test.AssertEquals(t, p.Size(), 4)
+
+	// Long waits grow the pool, restoring the idle limit, up to its maximum.
+	for i := 0; i < 20; i++ {
+		fake.stats.WaitCount += 10
+		fake.stats.WaitDuration += time.Second
+		p.adjust()
+	}
+	test.AssertEquals(t, p.Size(), 40)
+	test.AssertEquals(t, fake.maxOpen, 40)
+	test.AssertEquals(t, fake.maxIdle, 10)
+	test.Assert(t, test.CountCounterVec("direction", "grow", p.resizes) > 0, "no growth recorded")
+
+	// Adjusting without a change in size doesn't touch the pool.
+	calls := fake.setCalls
+	fake.stats.WaitCount += 10
+	fake.stats.WaitDuration += time.Second
+	p.adjust()
+	test.AssertEquals(t, fake.setCalls, calls)
+
+	p.Start()
+	p.Stop()
+	p.Stop()
+}
@@ -0,0 +1,166 @@
+package db
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/letsencrypt/boulder/core"
+)
+
+// MariaDB error numbers for errors after which the transaction has been
+// rolled back, and can safely be run again.
+const (
+	errDeadlock        = 1213
+	errLockWaitTimeout = 1205
+)
+
+// RetryPolicy says how many times, and how quickly, a Retrier retries a
+// transaction which failed with a transient error.
+type RetryPolicy struct {
+	// MaxRetries is the number of times a transaction is retried. Zero
+	// disables retries.
+	MaxRetries int
+	// Backoff is the delay before the first retry, which doubles for each
+	// subsequent retry up to MaxBackoff. Delays are jittered.
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+}
+
+// Retrier runs transactions, retrying those which fail with deadlocks, lock
+// wait timeouts, or lost connections according to its RetryPolicy.
+type Retrier struct {
+	retries   *prometheus.CounterVec
+	exhausted *prometheus.CounterVec
+
+	mu     sync.RWMutex
+	policy RetryPolicy
+}
+
+// NewRetrier returns a Retrier which doesn't retry anything until it's given
+// a RetryPolicy with SetPolicy.
+func NewRetrier(stats prometheus.Registerer) *Retrier {
+	retries := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "db_transaction_retries",
+		Help: "Number of times a DB transaction or write was retried after a transient error, by method and reason",
+	}, []string{"method", "reason"})
+	stats.MustRegister(retries)
+	exhausted := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "db_transaction_retries_exhausted",
+		Help: "Number of DB transactions or writes which still failed with a transient error after being retried as many times as allowed, by method",
+	}, []string{"method"})
+	stats.MustRegister(exhausted)
+	return &Retrier{retries: retries, exhausted: exhausted}
+}
+
+// SetPolicy replaces the Retrier's RetryPolicy.
+func (r *Retrier) SetPolicy(policy RetryPolicy) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.policy = policy
+}
+
+func (r *Retrier) getPolicy() RetryPolicy {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.policy
+}
+
+// WithTransaction is like the package-level WithTransaction, except that a
+// transaction which fails with a deadlock, a lock wait timeout, or a
+// connection lost before committing is run again, f included. f must
+// therefore be safe to run more than once. A transaction whose commit fails
+// because the connection was lost isn't retried, since it may have been
+// committed. The method, usually the name of the RPC the transaction is part
+// of, labels the Retrier's metrics.
+func (r *Retrier) WithTransaction(ctx context.Context, method string, dbMap DatabaseMap, f txFunc) (interface{}, error) {
+	var result interface{}
+	err := r.retry(ctx, method, true, func() error {
+		var err error
+		result, err = withTransaction(ctx, dbMap, f)
+		return err
+	})
+	var commitErr commitError
+	if errors.As(err, &commitErr) {
+		return nil, commitErr.error
+	}
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Do runs a single write, such as an Insert or an Exec outside of a
+// transaction, retrying it if it fails with a deadlock or a lock wait
+// timeout. It isn't retried after a lost connection, since the write may
+// have happened.
+func (r *Retrier) Do(ctx context.Context, method string, f func() error) error {
+	return r.retry(ctx, method, false, f)
+}
+
+// retry runs f until it succeeds, fails with an error which isn't retryable,
+// or has been retried as many times as the policy allows. Connection errors
+// are only retryable if retryConnErrs is true.
+func (r *Retrier) retry(ctx context.Context, method string, retryConnErrs bool, f func() error) error {
+	policy := r.getPolicy()
+	for attempt := 0; ; attempt++ {
+		err := f()
+		if err == nil {
+			return nil
+		}
+		reason, ok := retryableReason(err)
+		if !ok || (reason == "connection" && !retryConnErrs) {
+			return err
+		}
+		if attempt >= policy.MaxRetries {
+			if policy.MaxRetries > 0 {
+				r.exhausted.WithLabelValues(method).Inc()
+			}
+			return err
+		}
+		r.retries.WithLabelValues(method, reason).Inc()
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(core.RetryBackoff(attempt+1, policy.Backoff, policy.MaxBackoff, 2)):
+		}
+	}
+}
+
+// commitError marks an error returned by Commit, after which a lost
+// connection leaves it unknown whether the transaction was committed.
+type commitError struct {
+	error
+}
+
+func (e commitError) Unwrap() error {
+	return e.error
+}
+
+// retryableReason returns a short description of why err is worth retrying,
+// for labelling metrics, and false if it isn't.
+func retryableReason(err error) (string, bool) {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		switch mysqlErr.Number {
+		case errDeadlock:
+			return "deadlock", true
+		case errLockWaitTimeout:
+			return "lock_wait_timeout", true
+		}
+		return "", false
+	}
+	var commitErr commitError
+	if errors.As(err, &commitErr) {
+		return "", false
+	}
+	if errors.Is(err, mysql.ErrInvalidConn) || errors.Is(err, driver.ErrBadConn) {
+		return "connection", true
+	}
+	return "", false
+}
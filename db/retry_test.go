@@ -0,0 +1,153 @@
+package db
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-gorp/gorp/v3"
+	"github.com/go-sql-driver/mysql"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/letsencrypt/boulder/test"
+)
+
+// fakeTxMap is a DatabaseMap whose transactions fail to commit with the
+// errors in commitErrs, in turn, and then succeed.
+type fakeTxMap struct {
+	DatabaseMap
+	commitErrs []error
+	commits    int
+	rollbacks  int
+}
+
+func (m *fakeTxMap) Begin() (Transaction, error) {
+	return &fakeTx{m: m}, nil
+}
+
+type fakeTx struct {
+	Transaction
+	m *fakeTxMap
+}
+
+func (tx *fakeTx) WithContext(ctx context.Context) gorp.SqlExecutor {
+	return nil
+}
+
+func (tx *fakeTx) Commit() error {
+	tx.m.commits++
+	if len(tx.m.commitErrs) > 0 {
+		err := tx.m.commitErrs[0]
+		tx.m.commitErrs = tx.m.commitErrs[1:]
+		return err
+	}
+	return nil
+}
+
+func (tx *fakeTx) Rollback() error {
+	tx.m.rollbacks++
+	return nil
+}
+
+// failing returns a txFunc which fails with each of errs in turn, and then
+// succeeds, returning "ok". It counts its calls in calls.
+func failing(calls *int, errs ...error) txFunc {
+	return func(Executor) (interface{}, error) {
+		*calls++
+		if *calls <= len(errs) {
+			return nil, errs[*calls-1]
+		}
+		return "ok", nil
+	}
+}
+
+var (
+	deadlock = ErrDatabaseOp{Op: "exec", Err: &mysql.MySQLError{Number: errDeadlock, Message: "Deadlock found"}}
+	lostConn = ErrDatabaseOp{Op: "exec", Err: mysql.ErrInvalidConn}
+)
+
+func TestRetrierWithTransaction(t *testing.T) {
+	r := NewRetrier(prometheus.NewRegistry())
+	r.SetPolicy(RetryPolicy{MaxRetries: 2, Backoff: time.Millisecond, MaxBackoff: time.Millisecond})
+	ctx := context.Background()
+
+	// Transient errors are retried, and the transaction's result returned once
+	// it succeeds.
+	var calls int
+	m := &fakeTxMap{}
+	result, err := r.WithTransaction(ctx, "Method", m, failing(&calls, deadlock, lostConn))
+	test.AssertNotError(t, err, "transaction failed despite retries")
+	test.AssertEquals(t, result, "ok")
+	test.AssertEquals(t, calls, 3)
+	test.AssertEquals(t, m.rollbacks, 2)
+	test.AssertEquals(t, test.CountCounter(r.retries.WithLabelValues("Method", "deadlock")), 1)
+	test.AssertEquals(t, test.CountCounter(r.retries.WithLabelValues("Method", "connection")), 1)
+
+	// Retries stop when the policy's exhausted, and the last error returned.
+	calls = 0
+	result, err = r.WithTransaction(ctx, "Exhausted", &fakeTxMap{}, failing(&calls, deadlock, deadlock, deadlock))
+	test.AssertErrorIs(t, err, deadlock)
+	test.AssertEquals(t, result, nil)
+	test.AssertEquals(t, calls, 3)
+	test.AssertEquals(t, test.CountCounterVec("method", "Exhausted", r.exhausted), 1)
+
+	// Other errors aren't retried.
+	calls = 0
+	other := errors.New("syntax error")
+	_, err = r.WithTransaction(ctx, "Other", &fakeTxMap{}, failing(&calls, other))
+	test.AssertEquals(t, err, other)
+	test.AssertEquals(t, calls, 1)
+
+	// A deadlock when committing is retried, but a lost connection isn't,
+	// since the transaction may have been committed.
+	calls = 0
+	m = &fakeTxMap{commitErrs: []error{&mysql.MySQLError{Number: errDeadlock}}}
+	_, err = r.WithTransaction(ctx, "Commit", m, failing(&calls))
+	test.AssertNotError(t, err, "transaction failed despite retries")
+	test.AssertEquals(t, m.commits, 2)
+
+	calls = 0
+	m = &fakeTxMap{commitErrs: []error{driver.ErrBadConn}}
+	_, err = r.WithTransaction(ctx, "Commit", m, failing(&calls))
+	test.AssertEquals(t, err, driver.ErrBadConn)
+	test.AssertEquals(t, m.commits, 1)
+
+	// A cancelled context stops retries.
+	calls = 0
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	r.SetPolicy(RetryPolicy{MaxRetries: 5, Backoff: time.Hour, MaxBackoff: time.Hour})
+	_, err = r.WithTransaction(cancelled, "Cancelled", &fakeTxMap{}, failing(&calls, deadlock, deadlock))
+	test.AssertErrorIs(t, err, deadlock)
+	test.AssertEquals(t, calls, 1)
+}
+
+func TestRetrierDo(t *testing.T) {
+	r := NewRetrier(prometheus.NewRegistry())
+	ctx := context.Background()
+
+	// With the default policy nothing is retried.
+	var calls int
+	f := func() error {
+		calls++
+		return deadlock
+	}
+	test.AssertErrorIs(t, r.Do(ctx, "Method", f), deadlock)
+	test.AssertEquals(t, calls, 1)
+
+	// Single writes are retried after deadlocks, but not after lost
+	// connections.
+	r.SetPolicy(RetryPolicy{MaxRetries: 1, Backoff: time.Millisecond, MaxBackoff: time.Millisecond})
+	calls = 0
+	test.AssertErrorIs(t, r.Do(ctx, "Method", f), deadlock)
+	test.AssertEquals(t, calls, 2)
+
+	calls = 0
+	test.AssertErrorIs(t, r.Do(ctx, "Method", func() error {
+		calls++
+		return lostConn
+	}), mysql.ErrInvalidConn)
+	test.AssertEquals(t, calls, 1)
+}
@@ -1,6 +1,9 @@
 package db
 
-import "context"
+import (
+	"context"
+	"errors"
+)
 
 // txFunc represents a function that does work in the context of a transaction.
 type txFunc func(txWithCtx Executor) (interface{}, error)
@@ -10,6 +13,17 @@ type txFunc func(txWithCtx Executor) (interface{}, error)
 // to the transaction. WithTransaction also passes through a value returned by
 // `f`, if there is no error.
 func WithTransaction(ctx context.Context, dbMap DatabaseMap, f txFunc) (interface{}, error) {
+	result, err := withTransaction(ctx, dbMap, f)
+	var commitErr commitError
+	if errors.As(err, &commitErr) {
+		return nil, commitErr.error
+	}
+	return result, err
+}
+
+// withTransaction is WithTransaction, except that errors from Commit are
+// wrapped in a commitError.
+func withTransaction(ctx context.Context, dbMap DatabaseMap, f txFunc) (interface{}, error) {
 	tx, err := dbMap.Begin()
 	if err != nil {
 		return nil, err
@@ -21,7 +35,7 @@ func WithTransaction(ctx context.Context, dbMap DatabaseMap, f txFunc) (interfac
 	}
 	err = tx.Commit()
 	if err != nil {
-		return nil, err
+		return nil, commitError{err}
 	}
 	return result, nil
 }
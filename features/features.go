@@ -4,7 +4,11 @@ package features
 
 import (
 	"fmt"
+	"hash/fnv"
+	"strconv"
 	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
 )
 
 type FeatureFlag int
@@ -62,6 +66,32 @@ const (
 	// ECDSAForAll enables all accounts, regardless of their presence in the CA's
 	// ecdsaAllowedAccounts config value, to get issuance from ECDSA issuers.
 	ECDSAForAll
+	// StreamlineOrderAndAuthzs causes the RA to store new orders and their new
+	// pending authorizations with a single batched SA.NewOrderAndAuthzs call.
+	StreamlineOrderAndAuthzs
+	// EmailIdentifiers enables orders for "email" identifiers, validated with
+	// email-reply-00 challenges, and the issuance of S/MIME certificates for
+	// them.
+	EmailIdentifiers
+	// BatchCAARecheck causes the RA to recheck CAA for all of a finalized
+	// order's names with a single VA.IsCAAValidBatch call.
+	BatchCAARecheck
+	// StoreCertificateMetadata causes the RA to record the profile, key
+	// algorithm and validation methods of each certificate it issues in the
+	// SA, for issuance reports.
+	StoreCertificateMetadata
+	// AccountEvents causes the RA to record an audit event in the SA for each
+	// order, finalization, revocation, key rollover and contact change made
+	// with an account, and enables the WFE2's endpoint for reading them.
+	AccountEvents
+	// QueueFinalCerts causes the RA to hand final certificates to the
+	// publisher's persistent queue with a single QueueFinalCert call, rather
+	// than submitting them to each CT log itself.
+	QueueFinalCerts
+	// KeyRolloverHistory causes the SA to record the old and new key hashes
+	// of each account key rollover, which the RA may use to block rolled-away
+	// keys from registering new accounts.
+	KeyRolloverHistory
 )
 
 // List of features and their default value, protected by fMu
@@ -90,10 +120,28 @@ var features = map[FeatureFlag]bool{
 	BlockedKeyTable:               false,
 	NonCFSSLSigner:                false,
 	ECDSAForAll:                   false,
+	StreamlineOrderAndAuthzs:      false,
+	EmailIdentifiers:              false,
+	BatchCAARecheck:               false,
+	StoreCertificateMetadata:      false,
+	AccountEvents:                 false,
+	QueueFinalCerts:               false,
+	KeyRolloverHistory:            false,
 }
 
+// List of features which are enabled for a percentage of keys, protected by
+// fMu. See SetRollouts.
+var rollouts = map[FeatureFlag]float64{}
+
 var fMu = new(sync.RWMutex)
 
+// RolloutDecisions counts, by feature and by whether it applied, the decisions
+// made by EnabledFor. It's registered by cmd.StatsAndLogging.
+var RolloutDecisions = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "feature_rollout_decisions",
+	Help: "Number of times a feature was checked for a key, by whether it applied",
+}, []string{"feature", "enabled"})
+
 var initial = map[FeatureFlag]bool{}
 
 var nameToFeature = make(map[string]FeatureFlag, len(features))
@@ -121,6 +169,67 @@ func Set(featureSet map[string]bool) error {
 	return nil
 }
 
+// SetRollouts accepts a list of features and the percentage, from 0 to 100, of
+// keys passed to EnabledFor which each should apply to. It returns an error if
+// passed a feature name that it doesn't know, or an invalid percentage. A
+// feature which is enabled by Set applies to every key regardless.
+func SetRollouts(rolloutSet map[string]float64) error {
+	fMu.Lock()
+	defer fMu.Unlock()
+	for n, v := range rolloutSet {
+		f, present := nameToFeature[n]
+		if !present {
+			return fmt.Errorf("feature '%s' doesn't exist", n)
+		}
+		if v < 0 || v > 100 {
+			return fmt.Errorf("rollout percentage for feature '%s' must be between 0 and 100, got %g", n, v)
+		}
+		rollouts[f] = v
+	}
+	return nil
+}
+
+// EnabledFor returns true if the feature is enabled, or if it's being rolled
+// out and key, such as an account or order ID, falls within the rollout
+// percentage. Whether a key falls within it is decided by hashing the key,
+// so the decision for a given feature and key is the same each time, and in
+// every process, and a key stays within the rollout as the percentage is
+// raised. Like Enabled, it will panic if passed a feature that it doesn't
+// know.
+func EnabledFor(n FeatureFlag, key string) bool {
+	fMu.RLock()
+	v, present := features[n]
+	percentage := rollouts[n]
+	fMu.RUnlock()
+	if !present {
+		panic(fmt.Sprintf("feature '%s' doesn't exist", n.String()))
+	}
+	if !v && percentage > 0 {
+		v = rolloutBucket(n, key) < percentage*100
+	}
+	RolloutDecisions.WithLabelValues(n.String(), strconv.FormatBool(v)).Inc()
+	return v
+}
+
+// rolloutBucket deterministically maps a feature and key to one of 10000
+// buckets, so that rollout percentages can have a precision of 0.01%. The
+// feature is hashed along with the key so that each feature is rolled out to
+// a different set of keys.
+func rolloutBucket(n FeatureFlag, key string) float64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(n.String()))
+	_, _ = h.Write([]byte{0})
+	_, _ = h.Write([]byte(key))
+	return float64(h.Sum64() % 10000)
+}
+
+// Exists returns true if name is the name of a feature, such as one given in
+// a config file or a per-account override.
+func Exists(name string) bool {
+	_, present := nameToFeature[name]
+	return present
+}
+
 // Enabled returns true if the feature is enabled or false
 // if it isn't, it will panic if passed a feature that it
 // doesn't know.
@@ -134,11 +243,35 @@ func Enabled(n FeatureFlag) bool {
 	return v
 }
 
-// Reset resets the features to their initial state
+// Percentages returns, by name, the percentage of keys passed to EnabledFor
+// which each feature applies to: 100 if it's enabled, its rollout percentage
+// if it's being rolled out, and 0 otherwise. Per-account overrides aren't
+// included.
+func Percentages() map[string]float64 {
+	fMu.RLock()
+	defer fMu.RUnlock()
+	percentages := make(map[string]float64, len(features))
+	for f, v := range features {
+		if v {
+			percentages[f.String()] = 100
+		} else {
+			percentages[f.String()] = rollouts[f]
+		}
+	}
+	return percentages
+}
+
+// Reset resets the features to their initial state, with no rollouts or
+// per-account overrides
 func Reset() {
 	fMu.Lock()
 	defer fMu.Unlock()
 	for k, v := range initial {
 		features[k] = v
 	}
+	rollouts = map[FeatureFlag]float64{}
+	overrideCache.Lock()
+	overrideCache.lookup = nil
+	overrideCache.entries = nil
+	overrideCache.Unlock()
 }
@@ -1,7 +1,14 @@
 package features
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
 	"testing"
+	"time"
+
+	"github.com/jmhodges/clock"
 
 	"github.com/letsencrypt/boulder/test"
 )
@@ -30,3 +37,134 @@ func TestFeatures(t *testing.T) {
 	features = map[FeatureFlag]bool{}
 	Enabled(unused)
 }
+
+func TestEnabledFor(t *testing.T) {
+	defer Reset()
+	features = map[FeatureFlag]bool{
+		unused: false,
+	}
+	keys := make([]string, 1000)
+	for i := range keys {
+		keys[i] = strconv.Itoa(i)
+	}
+	countEnabled := func() int {
+		var n int
+		for _, key := range keys {
+			if EnabledFor(unused, key) {
+				n++
+			}
+		}
+		return n
+	}
+
+	test.AssertEquals(t, countEnabled(), 0)
+
+	err := SetRollouts(map[string]float64{"unused": 25})
+	test.AssertNotError(t, err, "SetRollouts shouldn't have failed for an existing feature")
+	quarter := countEnabled()
+	test.Assert(t, quarter > 200 && quarter < 300, fmt.Sprintf("expected about 250 of 1000 keys enabled, got %d", quarter))
+	test.Assert(t, !Enabled(unused), "'unused' shouldn't be enabled without a key")
+
+	// Decisions are deterministic, and keys stay enabled as the rollout
+	// percentage increases.
+	var enabled []string
+	for _, key := range keys {
+		if EnabledFor(unused, key) {
+			enabled = append(enabled, key)
+		}
+	}
+	test.AssertEquals(t, len(enabled), quarter)
+	err = SetRollouts(map[string]float64{"unused": 50})
+	test.AssertNotError(t, err, "SetRollouts shouldn't have failed for an existing feature")
+	for _, key := range enabled {
+		test.Assert(t, EnabledFor(unused, key), fmt.Sprintf("key %s should still be enabled", key))
+	}
+	test.Assert(t, countEnabled() > quarter, "more keys should be enabled at 50%")
+
+	err = SetRollouts(map[string]float64{"unused": 100})
+	test.AssertNotError(t, err, "SetRollouts shouldn't have failed for an existing feature")
+	test.AssertEquals(t, countEnabled(), len(keys))
+
+	// A fully enabled feature applies to every key.
+	err = SetRollouts(map[string]float64{"unused": 0})
+	test.AssertNotError(t, err, "SetRollouts shouldn't have failed for an existing feature")
+	err = Set(map[string]bool{"unused": true})
+	test.AssertNotError(t, err, "Set shouldn't have failed setting existing features")
+	test.AssertEquals(t, countEnabled(), len(keys))
+
+	Reset()
+	test.AssertEquals(t, countEnabled(), 0)
+
+	err = SetRollouts(map[string]float64{"non-existent": 50})
+	test.AssertError(t, err, "SetRollouts should've failed for a non-existent feature")
+	err = SetRollouts(map[string]float64{"unused": 101})
+	test.AssertError(t, err, "SetRollouts should've failed for a percentage over 100")
+	err = SetRollouts(map[string]float64{"unused": -1})
+	test.AssertError(t, err, "SetRollouts should've failed for a negative percentage")
+}
+
+func TestPercentages(t *testing.T) {
+	defer Reset()
+	features = map[FeatureFlag]bool{
+		unused:                   false,
+		PrecertificateRevocation: false,
+	}
+	test.AssertDeepEquals(t, Percentages(), map[string]float64{"unused": 0, "PrecertificateRevocation": 0})
+
+	err := SetRollouts(map[string]float64{"unused": 25})
+	test.AssertNotError(t, err, "SetRollouts shouldn't have failed for an existing feature")
+	err = Set(map[string]bool{"PrecertificateRevocation": true})
+	test.AssertNotError(t, err, "Set shouldn't have failed setting existing features")
+	test.AssertDeepEquals(t, Percentages(), map[string]float64{"unused": 25, "PrecertificateRevocation": 100})
+}
+
+func TestEnabledCtx(t *testing.T) {
+	defer Reset()
+	features = map[FeatureFlag]bool{
+		unused: false,
+	}
+	ctx := context.Background()
+	test.Assert(t, !EnabledCtx(ctx, unused), "'unused' shouldn't be enabled without an account")
+
+	var lookups int
+	overrides := map[int64]map[string]bool{
+		1: {"unused": true},
+	}
+	fc := clock.NewFake()
+	SetOverrideLookup(func(_ context.Context, regID int64) (map[string]bool, error) {
+		lookups++
+		if regID == 3 {
+			return nil, errors.New("SA unavailable")
+		}
+		return overrides[regID], nil
+	}, time.Minute, fc)
+
+	test.Assert(t, EnabledCtx(WithAccountID(ctx, 1), unused), "'unused' should be enabled for account 1")
+	test.Assert(t, !EnabledCtx(WithAccountID(ctx, 2), unused), "'unused' shouldn't be enabled for account 2")
+	test.Assert(t, !EnabledCtx(ctx, unused), "'unused' shouldn't be enabled without an account")
+	test.AssertEquals(t, lookups, 2)
+
+	// Overrides are cached until the TTL passes.
+	overrides[1] = map[string]bool{"unused": false}
+	test.Assert(t, EnabledCtx(WithAccountID(ctx, 1), unused), "'unused' should still be enabled for account 1")
+	test.AssertEquals(t, lookups, 2)
+	fc.Add(time.Minute)
+	test.Assert(t, !EnabledCtx(WithAccountID(ctx, 1), unused), "'unused' should be disabled for account 1")
+	test.AssertEquals(t, lookups, 3)
+
+	// An override takes precedence over a process-wide setting.
+	err := Set(map[string]bool{"unused": true})
+	test.AssertNotError(t, err, "Set shouldn't have failed setting existing features")
+	test.Assert(t, !EnabledCtx(WithAccountID(ctx, 1), unused), "'unused' should be disabled for account 1")
+	test.Assert(t, EnabledCtx(WithAccountID(ctx, 2), unused), "'unused' should be enabled for account 2")
+
+	// A failed lookup falls back to the process-wide setting.
+	test.Assert(t, EnabledCtx(WithAccountID(ctx, 3), unused), "'unused' should be enabled for account 3")
+	test.AssertEquals(t, lookups, 5)
+}
+
+func TestOverridable(t *testing.T) {
+	test.Assert(t, Overridable("EnforceMultiVA"), "EnforceMultiVA should be overridable")
+	test.Assert(t, !Overridable("CAAAccountURI"), "CAAAccountURI shouldn't be overridable")
+	test.Assert(t, !Overridable("NoSuchFeature"), "an unknown feature shouldn't be overridable")
+}
@@ -0,0 +1,133 @@
+package features
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/jmhodges/clock"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// OverrideLookup returns the features which have been explicitly enabled or
+// disabled for an account, by feature name. It's typically backed by the SA.
+type OverrideLookup func(ctx context.Context, regID int64) (map[string]bool, error)
+
+// maxCachedOverrides bounds the number of accounts whose overrides are cached.
+// When it's reached the cache is emptied.
+const maxCachedOverrides = 100000
+
+type cachedOverrides struct {
+	overrides map[string]bool
+	expires   time.Time
+}
+
+// overrideCache holds the override lookup set by SetOverrideLookup, and the
+// overrides it has returned.
+var overrideCache = struct {
+	sync.Mutex
+	lookup  OverrideLookup
+	ttl     time.Duration
+	clk     clock.Clock
+	entries map[int64]cachedOverrides
+}{}
+
+// OverrideLookups counts, by result, the per-account overrides looked up by
+// EnabledCtx. It's registered by cmd.StatsAndLogging.
+var OverrideLookups = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "feature_override_lookups",
+	Help: "Number of per-account feature override lookups, by result (hit, miss or error)",
+}, []string{"result"})
+
+// overridable holds the features which are checked with EnabledCtx, and so
+// honour per-account overrides. Overrides for any other feature would never
+// take effect.
+var overridable = map[FeatureFlag]bool{
+	EnforceMultiVA:           true,
+	StreamlineOrderAndAuthzs: true,
+}
+
+// Overridable returns true if the named feature honours per-account
+// overrides.
+func Overridable(name string) bool {
+	f, ok := nameToFeature[name]
+	return ok && overridable[f]
+}
+
+type accountIDKey struct{}
+
+// WithAccountID returns a copy of ctx carrying the ID of the account a request
+// is made on behalf of, so that EnabledCtx can apply the account's overrides
+// and rollouts.
+func WithAccountID(ctx context.Context, regID int64) context.Context {
+	return context.WithValue(ctx, accountIDKey{}, regID)
+}
+
+// SetOverrideLookup makes EnabledCtx consult lookup for the overrides of the
+// account carried by its context. Each account's overrides are cached for
+// ttl, so changes to them take up to ttl to take effect.
+func SetOverrideLookup(lookup OverrideLookup, ttl time.Duration, clk clock.Clock) {
+	overrideCache.Lock()
+	defer overrideCache.Unlock()
+	overrideCache.lookup = lookup
+	overrideCache.ttl = ttl
+	overrideCache.clk = clk
+	overrideCache.entries = make(map[int64]cachedOverrides)
+}
+
+// EnabledCtx returns whether the feature applies to the request with the given
+// context. If the context carries an account ID (see WithAccountID), the
+// account's override for the feature, if it has one, takes precedence, and
+// otherwise the feature applies as EnabledFor the account ID. Without an
+// account ID it's the same as Enabled. If the account's overrides can't be
+// looked up, they're ignored. Like Enabled, it will panic if passed a feature
+// that it doesn't know. Features checked with it must be listed in
+// overridable, so that overrides can be set for them.
+func EnabledCtx(ctx context.Context, n FeatureFlag) bool {
+	regID, ok := ctx.Value(accountIDKey{}).(int64)
+	if !ok {
+		return Enabled(n)
+	}
+	if v, ok := accountOverrides(ctx, regID)[n.String()]; ok {
+		return v
+	}
+	return EnabledFor(n, strconv.FormatInt(regID, 10))
+}
+
+// accountOverrides returns the cached overrides for an account, looking them
+// up if they aren't cached or have expired. It returns nil if no lookup has
+// been set.
+func accountOverrides(ctx context.Context, regID int64) map[string]bool {
+	overrideCache.Lock()
+	lookup, ttl, clk := overrideCache.lookup, overrideCache.ttl, overrideCache.clk
+	if lookup == nil {
+		overrideCache.Unlock()
+		return nil
+	}
+	entry, ok := overrideCache.entries[regID]
+	overrideCache.Unlock()
+	if ok && clk.Now().Before(entry.expires) {
+		OverrideLookups.WithLabelValues("hit").Inc()
+		return entry.overrides
+	}
+
+	overrides, err := lookup(ctx, regID)
+	if err != nil {
+		// Fall back to the process-wide settings, and cache that decision
+		// too, so that an unavailable backend doesn't add a failed lookup
+		// to every request.
+		OverrideLookups.WithLabelValues("error").Inc()
+		overrides = nil
+	} else {
+		OverrideLookups.WithLabelValues("miss").Inc()
+	}
+
+	overrideCache.Lock()
+	defer overrideCache.Unlock()
+	if len(overrideCache.entries) >= maxCachedOverrides {
+		overrideCache.entries = make(map[int64]cachedOverrides)
+	}
+	overrideCache.entries[regID] = cachedOverrides{overrides: overrides, expires: clk.Now().Add(ttl)}
+	return overrides
+}
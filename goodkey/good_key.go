@@ -11,6 +11,8 @@ import (
 	"math/big"
 	"sync"
 
+	"github.com/prometheus/client_golang/prometheus"
+
 	"github.com/letsencrypt/boulder/core"
 	berrors "github.com/letsencrypt/boulder/errors"
 	"github.com/letsencrypt/boulder/features"
@@ -56,6 +58,26 @@ func badKey(msg string, args ...interface{}) error {
 // significantly simpler.
 type BlockedKeyCheckFunc func(context.Context, *sapb.KeyBlockedRequest) (*sapb.Exists, error)
 
+// Config configures the optional checks performed by a KeyPolicy.
+type Config struct {
+	// WeakKeyFile is the path to a JSON file containing truncated modulus
+	// hashes of known weak RSA keys, such as those generated by Debian's
+	// vulnerable OpenSSL package. If empty, RSA modulus hash checking is
+	// disabled.
+	WeakKeyFile string
+	// BlockedKeyFile is the path to a YAML file containing Base64 encoded
+	// SHA256 hashes of pkix subject public keys that should be blocked. If
+	// empty, no blocked key file checking is performed.
+	BlockedKeyFile string
+	// FermatRounds is the number of rounds of Fermat's factorization method
+	// to attempt against RSA moduli, to detect keys whose two prime factors
+	// are too close together. If zero, this check is disabled.
+	FermatRounds int
+	// DisableROCACheck disables the check for RSA keys generated by Infineon
+	// hardware vulnerable to ROCA (CVE-2017-15361).
+	DisableROCACheck bool
+}
+
 // KeyPolicy determines which types of key may be used with various boulder
 // operations.
 type KeyPolicy struct {
@@ -65,31 +87,43 @@ type KeyPolicy struct {
 	weakRSAList        *WeakRSAKeys
 	blockedList        *blockedKeys
 	dbCheck            BlockedKeyCheckFunc
+	fermatRounds       int
+	skipROCA           bool
+	rejections         *prometheus.CounterVec
 }
 
-// NewKeyPolicy returns a KeyPolicy that allows RSA, ECDSA256 and ECDSA384.
-// weakKeyFile contains the path to a JSON file containing truncated modulus
-// hashes of known weak RSA keys. If this argument is empty RSA modulus hash
-// checking will be disabled. blockedKeyFile contains the path to a YAML file
-// containing Base64 encoded SHA256 hashes of pkix subject public keys that
-// should be blocked. If this argument is empty then no blocked key checking is
-// performed.
-func NewKeyPolicy(weakKeyFile, blockedKeyFile string, bkc BlockedKeyCheckFunc) (KeyPolicy, error) {
+// NewKeyPolicy returns a KeyPolicy that allows RSA, ECDSA256 and ECDSA384,
+// and performs the optional checks enabled in config. Keys rejected by the
+// blocklists or the weak key checks are counted by reason in stats.
+func NewKeyPolicy(config *Config, bkc BlockedKeyCheckFunc, stats prometheus.Registerer) (KeyPolicy, error) {
+	if config.FermatRounds < 0 {
+		return KeyPolicy{}, fmt.Errorf("FermatRounds must be non-negative, got %d", config.FermatRounds)
+	}
+
+	rejections := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "key_policy_rejections",
+		Help: "Count of keys rejected by the key policy, by reason (blocked, weak_list, roca, fermat)",
+	}, []string{"reason"})
+	stats.MustRegister(rejections)
+
 	kp := KeyPolicy{
 		AllowRSA:           true,
 		AllowECDSANISTP256: true,
 		AllowECDSANISTP384: true,
 		dbCheck:            bkc,
+		fermatRounds:       config.FermatRounds,
+		skipROCA:           config.DisableROCACheck,
+		rejections:         rejections,
 	}
-	if weakKeyFile != "" {
-		keyList, err := LoadWeakRSASuffixes(weakKeyFile)
+	if config.WeakKeyFile != "" {
+		keyList, err := LoadWeakRSASuffixes(config.WeakKeyFile)
 		if err != nil {
 			return KeyPolicy{}, err
 		}
 		kp.weakRSAList = keyList
 	}
-	if blockedKeyFile != "" {
-		blocked, err := loadBlockedKeysList(blockedKeyFile)
+	if config.BlockedKeyFile != "" {
+		blocked, err := loadBlockedKeysList(config.BlockedKeyFile)
 		if err != nil {
 			return KeyPolicy{}, err
 		}
@@ -98,6 +132,13 @@ func NewKeyPolicy(weakKeyFile, blockedKeyFile string, bkc BlockedKeyCheckFunc) (
 	return kp, nil
 }
 
+// reject counts a rejection for the given reason, if metrics are configured.
+func (policy *KeyPolicy) reject(reason string) {
+	if policy.rejections != nil {
+		policy.rejections.WithLabelValues(reason).Inc()
+	}
+}
+
 // GoodKey returns true if the key is acceptable for both TLS use and account
 // key use (our requirements are the same for either one), according to basic
 // strength and algorithm checking. GoodKey only supports pointers: *rsa.PublicKey
@@ -117,6 +158,7 @@ func (policy *KeyPolicy) GoodKey(ctx context.Context, key crypto.PublicKey) erro
 		if blocked, err := policy.blockedList.blocked(key); err != nil {
 			return berrors.InternalServerError("error checking blocklist for key: %v", key)
 		} else if blocked {
+			policy.reject("blocked")
 			return badKey("public key is forbidden")
 		}
 	}
@@ -129,6 +171,7 @@ func (policy *KeyPolicy) GoodKey(ctx context.Context, key crypto.PublicKey) erro
 		if err != nil {
 			return err
 		} else if exists.Exists {
+			policy.reject("blocked")
 			return badKey("public key is forbidden")
 		}
 	}
@@ -265,6 +308,7 @@ func (policy *KeyPolicy) goodKeyRSA(key *rsa.PublicKey) (err error) {
 		return badKey("RSA keys are not allowed")
 	}
 	if policy.weakRSAList != nil && policy.weakRSAList.Known(key) {
+		policy.reject("weak_list")
 		return badKey("key is on a known weak RSA key list")
 	}
 
@@ -316,9 +360,21 @@ func (policy *KeyPolicy) goodKeyRSA(key *rsa.PublicKey) (err error) {
 	}
 	// Check for weak keys generated by Infineon hardware
 	// (see https://crocs.fi.muni.cz/public/papers/rsa_ccs17)
-	if rocacheck.IsWeak(key) {
+	if !policy.skipROCA && rocacheck.IsWeak(key) {
+		policy.reject("roca")
 		return badKey("key generated by vulnerable Infineon-based hardware")
 	}
+	// Check for keys whose prime factors are close enough together that the
+	// modulus can be factored with Fermat's method.
+	if policy.fermatRounds > 0 {
+		// The factors aren't included in the error, which is returned to the
+		// client: they are the private key.
+		_, _, found := fermatFactor(modulus, policy.fermatRounds)
+		if found {
+			policy.reject("fermat")
+			return badKey("RSA modulus has factors that are too close together")
+		}
+	}
 
 	return nil
 }
@@ -350,3 +406,40 @@ func checkSmallPrimes(i *big.Int) bool {
 	result.GCD(nil, nil, i, smallPrimesProduct)
 	return result.Cmp(big.NewInt(1)) != 0
 }
+
+// fermatFactor attempts to factor n using Fermat's method for the given
+// number of rounds, returning the factors if it succeeds. Fermat's method
+// finds factors quickly when they are close to the square root of n, which
+// should never be the case for properly generated RSA keys.
+//
+// The method looks for an a such that a^2 - n = b^2 is a perfect square, since
+// then n = (a - b)(a + b). It starts from a = ceil(sqrt(n)) and increments a
+// each round.
+func fermatFactor(n *big.Int, rounds int) (*big.Int, *big.Int, bool) {
+	a := new(big.Int).Sqrt(n)
+	if new(big.Int).Mul(a, a).Cmp(n) != 0 {
+		a.Add(a, big.NewInt(1))
+	}
+	// b2 = a^2 - n, which is updated each round using
+	// (a+1)^2 - n = (a^2 - n) + 2a + 1.
+	b2 := new(big.Int).Mul(a, a)
+	b2.Sub(b2, n)
+	b := new(big.Int)
+	step := new(big.Int)
+	for i := 0; i < rounds; i++ {
+		b.Sqrt(b2)
+		if step.Mul(b, b).Cmp(b2) == 0 {
+			p := new(big.Int).Sub(a, b)
+			q := new(big.Int).Add(a, b)
+			if p.Cmp(big.NewInt(1)) > 0 {
+				return p, q, true
+			}
+			return nil, nil, false
+		}
+		step.Lsh(a, 1)
+		step.Add(step, big.NewInt(1))
+		b2.Add(b2, step)
+		a.Add(a, big.NewInt(1))
+	}
+	return nil, nil, false
+}
@@ -11,6 +11,7 @@ import (
 	"testing"
 
 	"github.com/letsencrypt/boulder/features"
+	"github.com/letsencrypt/boulder/metrics"
 	sapb "github.com/letsencrypt/boulder/sa/proto"
 	"github.com/letsencrypt/boulder/test"
 )
@@ -128,6 +129,59 @@ func TestROCA(t *testing.T) {
 	err := testingPolicy.GoodKey(context.Background(), &key)
 	test.AssertError(t, err, "Should have rejected ROCA-weak key")
 	test.AssertEquals(t, err.Error(), "key generated by vulnerable Infineon-based hardware")
+
+	// The check can be disabled.
+	noROCAPolicy := *testingPolicy
+	noROCAPolicy.skipROCA = true
+	err = noROCAPolicy.GoodKey(context.Background(), &key)
+	test.AssertNotError(t, err, "Rejected ROCA-weak key with the ROCA check disabled")
+}
+
+func TestFermat(t *testing.T) {
+	// Generate a modulus whose factors are adjacent primes.
+	p, err := rand.Prime(rand.Reader, 1024)
+	test.AssertNotError(t, err, "rand.Prime failed")
+	q := new(big.Int).Add(p, big.NewInt(2))
+	for !q.ProbablyPrime(20) {
+		q.Add(q, big.NewInt(2))
+	}
+	key := &rsa.PublicKey{
+		N: new(big.Int).Mul(p, q),
+		E: 65537,
+	}
+
+	// The check is disabled unless FermatRounds is set.
+	test.AssertNotError(t, testingPolicy.GoodKey(context.Background(), key), "Rejected key with Fermat check disabled")
+
+	policy, err := NewKeyPolicy(&Config{FermatRounds: 100}, nil, metrics.NoopRegisterer)
+	test.AssertNotError(t, err, "NewKeyPolicy failed")
+	err = policy.GoodKey(context.Background(), key)
+	test.AssertError(t, err, "Should have rejected key with close factors")
+	test.AssertErrorIs(t, err, ErrBadKey)
+	test.AssertEquals(t, err.Error(), "RSA modulus has factors that are too close together")
+	test.AssertEquals(t, test.CountCounterVec("reason", "fermat", policy.rejections), 1)
+
+	// A properly generated key isn't factored.
+	private, err := rsa.GenerateKey(rand.Reader, 2048)
+	test.AssertNotError(t, err, "Error generating key")
+	test.AssertNotError(t, policy.GoodKey(context.Background(), &private.PublicKey), "Rejected good key")
+}
+
+func TestFermatFactor(t *testing.T) {
+	p, q, found := fermatFactor(big.NewInt(5959), 10)
+	test.Assert(t, found, "failed to factor 5959")
+	test.AssertEquals(t, p.Int64(), int64(59))
+	test.AssertEquals(t, q.Int64(), int64(101))
+
+	// Perfect squares are factored on the first round.
+	p, q, found = fermatFactor(big.NewInt(49), 1)
+	test.Assert(t, found, "failed to factor 49")
+	test.AssertEquals(t, p.Int64(), int64(7))
+	test.AssertEquals(t, q.Int64(), int64(7))
+
+	// Primes are never factored.
+	_, _, found = fermatFactor(big.NewInt(7919), 100)
+	test.Assert(t, !found, "factored a prime")
 }
 
 func TestGoodKey(t *testing.T) {
@@ -263,7 +317,7 @@ func TestDBBlocklistAccept(t *testing.T) {
 		return &sapb.Exists{Exists: false}, nil
 	}
 
-	policy, err := NewKeyPolicy("", "", testCheck)
+	policy, err := NewKeyPolicy(&Config{}, testCheck, metrics.NoopRegisterer)
 	test.AssertNotError(t, err, "NewKeyPolicy failed")
 
 	k, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
@@ -277,7 +331,7 @@ func TestDBBlocklistReject(t *testing.T) {
 		return &sapb.Exists{Exists: true}, nil
 	}
 
-	policy, err := NewKeyPolicy("", "", testCheck)
+	policy, err := NewKeyPolicy(&Config{}, testCheck, metrics.NoopRegisterer)
 	test.AssertNotError(t, err, "NewKeyPolicy failed")
 
 	k, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
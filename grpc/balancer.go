@@ -0,0 +1,265 @@
+package grpc
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jmhodges/clock"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/balancer"
+	"google.golang.org/grpc/balancer/base"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/resolver"
+	"google.golang.org/grpc/status"
+
+	"github.com/letsencrypt/boulder/cmd"
+)
+
+// ejectingBalancerName is the prefix of the names the ejecting balancers are
+// registered under. Each client gets its own balancer, and so its own view of
+// its backends' health.
+const ejectingBalancerName = "boulder_ejecting"
+
+// healthCheckServiceConfig is the service config which enables client side
+// health checking, against the overall health of each backend's server, for
+// balancers which support it. Without it, they don't health check at all.
+const healthCheckServiceConfig = `{"healthCheckConfig": {"serviceName": ""}}`
+
+var ejectingBalancers int64
+
+// registerEjectingBalancer registers a round robin balancer which ejects
+// backends that are erroring or slow according to c, and returns its name.
+// Clients using it must also use the healthCheckServiceConfig.
+func registerEjectingBalancer(c *cmd.GRPCEjectionConfig, ejections *prometheus.CounterVec, clk clock.Clock) string {
+	name := fmt.Sprintf("%s_%d", ejectingBalancerName, atomic.AddInt64(&ejectingBalancers, 1))
+	tracker := newBackendTracker(c, ejections, clk)
+	// HealthCheck makes the balancer only use backends which report that they
+	// are serving through the gRPC Health Service.
+	balancer.Register(&ejectingBalancerBuilder{
+		Builder: base.NewBalancerBuilderV2(name, &ejectingPickerBuilder{tracker: tracker}, base.Config{HealthCheck: true}),
+		tracker: tracker,
+	})
+	return name
+}
+
+// ejectingBalancerBuilder builds round robin balancers which forget the
+// stats of backends once the resolver no longer returns them.
+type ejectingBalancerBuilder struct {
+	balancer.Builder
+	tracker *backendTracker
+}
+
+func (ebb *ejectingBalancerBuilder) Build(cc balancer.ClientConn, opts balancer.BuildOptions) balancer.Balancer {
+	b := ebb.Builder.Build(cc, opts)
+	return &ejectingBalancer{Balancer: b, v2: b.(balancer.V2Balancer), tracker: ebb.tracker}
+}
+
+// ejectingBalancer wraps a base balancer, pruning its backendTracker on each
+// resolver update.
+type ejectingBalancer struct {
+	balancer.Balancer
+	v2      balancer.V2Balancer
+	tracker *backendTracker
+}
+
+func (eb *ejectingBalancer) UpdateClientConnState(s balancer.ClientConnState) error {
+	eb.tracker.prune(s.ResolverState.Addresses)
+	return eb.v2.UpdateClientConnState(s)
+}
+
+func (eb *ejectingBalancer) ResolverError(err error) {
+	eb.v2.ResolverError(err)
+}
+
+func (eb *ejectingBalancer) UpdateSubConnState(sc balancer.SubConn, state balancer.SubConnState) {
+	eb.v2.UpdateSubConnState(sc, state)
+}
+
+// backendStats holds the outcomes of the RPCs sent to a single backend in the
+// current interval, and whether it is ejected.
+type backendStats struct {
+	intervalStart time.Time
+	requests      int
+	errors        int
+	latency       time.Duration
+	ejectedUntil  time.Time
+}
+
+// backendTracker records the outcome of every RPC sent to each backend,
+// ejecting those whose error rate or mean latency over an interval exceeds
+// the configured thresholds. At most half of the backends are ejected at any
+// one time, so a client with a single backend never ejects it.
+type backendTracker struct {
+	mu       sync.Mutex
+	backends map[string]*backendStats
+
+	interval         time.Duration
+	minRequests      int
+	maxErrorRate     float64
+	maxLatency       time.Duration
+	ejectionDuration time.Duration
+
+	ejections *prometheus.CounterVec
+	clk       clock.Clock
+}
+
+func newBackendTracker(c *cmd.GRPCEjectionConfig, ejections *prometheus.CounterVec, clk clock.Clock) *backendTracker {
+	return &backendTracker{
+		backends:         make(map[string]*backendStats),
+		interval:         c.Interval.Duration,
+		minRequests:      c.MinRequests,
+		maxErrorRate:     c.MaxErrorRate,
+		maxLatency:       c.MaxLatency.Duration,
+		ejectionDuration: c.EjectionDuration.Duration,
+		ejections:        ejections,
+		clk:              clk,
+	}
+}
+
+// isBackendError returns true if err indicates a problem with the backend
+// itself, rather than with the request.
+func isBackendError(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Internal:
+		return true
+	}
+	return false
+}
+
+// record adds the outcome of an RPC to addr's stats. If the backend's current
+// interval is over, its stats are checked against the thresholds, possibly
+// ejecting it, and a new interval is started.
+func (bt *backendTracker) record(addr string, latency time.Duration, err error) {
+	bt.mu.Lock()
+	defer bt.mu.Unlock()
+	now := bt.clk.Now()
+	b, ok := bt.backends[addr]
+	if !ok {
+		b = &backendStats{intervalStart: now}
+		bt.backends[addr] = b
+	}
+	b.requests++
+	b.latency += latency
+	if isBackendError(err) {
+		b.errors++
+	}
+	if now.Sub(b.intervalStart) < bt.interval {
+		return
+	}
+	if b.requests >= bt.minRequests && bt.unhealthy(b) && now.After(b.ejectedUntil) && bt.canEject(now) {
+		b.ejectedUntil = now.Add(bt.ejectionDuration)
+		bt.ejections.WithLabelValues(addr).Inc()
+	}
+	*b = backendStats{intervalStart: now, ejectedUntil: b.ejectedUntil}
+}
+
+// prune forgets every backend not in addrs, so that the stats of backends
+// which have been removed neither accumulate nor count towards the number
+// which can be ejected.
+func (bt *backendTracker) prune(addrs []resolver.Address) {
+	current := make(map[string]bool, len(addrs))
+	for _, addr := range addrs {
+		current[addr.Addr] = true
+	}
+	bt.mu.Lock()
+	defer bt.mu.Unlock()
+	for addr := range bt.backends {
+		if !current[addr] {
+			delete(bt.backends, addr)
+		}
+	}
+}
+
+func (bt *backendTracker) unhealthy(b *backendStats) bool {
+	if bt.maxErrorRate > 0 && float64(b.errors)/float64(b.requests) > bt.maxErrorRate {
+		return true
+	}
+	return bt.maxLatency > 0 && b.latency/time.Duration(b.requests) > bt.maxLatency
+}
+
+// canEject returns true if fewer than half of the known backends would be
+// ejected after ejecting one more. It must be called with bt.mu held.
+func (bt *backendTracker) canEject(now time.Time) bool {
+	ejected := 1
+	for _, b := range bt.backends {
+		if now.Before(b.ejectedUntil) {
+			ejected++
+		}
+	}
+	return ejected*2 <= len(bt.backends)
+}
+
+func (bt *backendTracker) ejected(addr string) bool {
+	bt.mu.Lock()
+	defer bt.mu.Unlock()
+	b, ok := bt.backends[addr]
+	return ok && bt.clk.Now().Before(b.ejectedUntil)
+}
+
+// ejectingPickerBuilder builds ejectingPickers sharing a single
+// backendTracker.
+type ejectingPickerBuilder struct {
+	tracker *backendTracker
+}
+
+func (epb *ejectingPickerBuilder) Build(info base.PickerBuildInfo) balancer.V2Picker {
+	if len(info.ReadySCs) == 0 {
+		return base.NewErrPickerV2(balancer.ErrNoSubConnAvailable)
+	}
+	var backends []pickerBackend
+	for sc, sci := range info.ReadySCs {
+		backends = append(backends, pickerBackend{sc: sc, addr: sci.Address.Addr})
+	}
+	return &ejectingPicker{tracker: epb.tracker, backends: backends}
+}
+
+type pickerBackend struct {
+	sc   balancer.SubConn
+	addr string
+}
+
+// ejectingPicker picks ready backends in round robin order, skipping those
+// which are ejected, and records the outcome of each RPC.
+type ejectingPicker struct {
+	tracker  *backendTracker
+	backends []pickerBackend
+
+	mu   sync.Mutex
+	next int
+}
+
+func (ep *ejectingPicker) Pick(balancer.PickInfo) (balancer.PickResult, error) {
+	ep.mu.Lock()
+	var picked *pickerBackend
+	for i := 0; i < len(ep.backends); i++ {
+		b := &ep.backends[(ep.next+i)%len(ep.backends)]
+		if !ep.tracker.ejected(b.addr) {
+			picked = b
+			ep.next = (ep.next + i + 1) % len(ep.backends)
+			break
+		}
+	}
+	if picked == nil {
+		// Every ready backend is ejected, which can only happen if some have
+		// become unready since they were ejected. Any backend is better than
+		// none.
+		picked = &ep.backends[ep.next]
+		ep.next = (ep.next + 1) % len(ep.backends)
+	}
+	ep.mu.Unlock()
+
+	start := ep.tracker.clk.Now()
+	return balancer.PickResult{
+		SubConn: picked.sc,
+		Done: func(info balancer.DoneInfo) {
+			if info.Err == nil && !info.BytesSent {
+				// The RPC was never sent, because the backend stopped being
+				// ready after it was picked.
+				return
+			}
+			ep.tracker.record(picked.addr, ep.tracker.clk.Since(start), info.Err)
+		},
+	}, nil
+}
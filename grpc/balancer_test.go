@@ -0,0 +1,195 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jmhodges/clock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/balancer"
+	"google.golang.org/grpc/balancer/base"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/resolver"
+	"google.golang.org/grpc/status"
+
+	"github.com/letsencrypt/boulder/cmd"
+	"github.com/letsencrypt/boulder/grpc/test_proto"
+	"github.com/letsencrypt/boulder/metrics"
+	"github.com/letsencrypt/boulder/test"
+)
+
+func newTestTracker() (*backendTracker, clock.FakeClock) {
+	fc := clock.NewFake()
+	c := &cmd.GRPCEjectionConfig{
+		Interval:         cmd.ConfigDuration{Duration: time.Minute},
+		MinRequests:      3,
+		MaxErrorRate:     0.5,
+		MaxLatency:       cmd.ConfigDuration{Duration: time.Second},
+		EjectionDuration: cmd.ConfigDuration{Duration: 5 * time.Minute},
+	}
+	return newBackendTracker(c, NewClientMetrics(metrics.NoopRegisterer).ejections, fc), fc
+}
+
+func TestBackendTrackerEjection(t *testing.T) {
+	bt, fc := newTestTracker()
+	unavailable := status.Error(codes.Unavailable, "down")
+
+	for _, addr := range []string{"a", "b", "c", "d"} {
+		bt.record(addr, time.Millisecond, nil)
+	}
+	// Errors from the backend count against it, errors about the request
+	// don't.
+	bt.record("a", time.Millisecond, unavailable)
+	bt.record("a", time.Millisecond, unavailable)
+	bt.record("b", time.Millisecond, status.Error(codes.Unknown, "malformed"))
+	bt.record("b", time.Millisecond, status.Error(codes.Unknown, "malformed"))
+	// Slow backends are ejected too.
+	bt.record("c", 3*time.Second, nil)
+	bt.record("c", 3*time.Second, nil)
+	test.Assert(t, !bt.ejected("a"), "backend ejected before the end of the interval")
+
+	fc.Add(time.Minute)
+	bt.record("a", time.Millisecond, unavailable)
+	bt.record("b", time.Millisecond, nil)
+	bt.record("c", 3*time.Second, nil)
+	test.Assert(t, bt.ejected("a"), "erroring backend wasn't ejected")
+	test.Assert(t, !bt.ejected("b"), "healthy backend was ejected")
+	test.Assert(t, bt.ejected("c"), "slow backend wasn't ejected")
+	test.AssertEquals(t, test.CountCounter(bt.ejections.WithLabelValues("a")), 1)
+
+	// No more than half of the backends are ejected at once.
+	bt.record("d", time.Millisecond, unavailable)
+	bt.record("d", time.Millisecond, unavailable)
+	fc.Add(time.Minute)
+	bt.record("d", time.Millisecond, unavailable)
+	test.Assert(t, !bt.ejected("d"), "more than half of the backends were ejected")
+
+	fc.Add(5 * time.Minute)
+	test.Assert(t, !bt.ejected("a"), "backend still ejected after the ejection duration")
+}
+
+// fakeSubConn is a balancer.SubConn which does nothing.
+type fakeSubConn struct {
+	balancer.SubConn
+	name string
+}
+
+func TestEjectingPicker(t *testing.T) {
+	bt, _ := newTestTracker()
+	a, b := &fakeSubConn{name: "a"}, &fakeSubConn{name: "b"}
+	picker := (&ejectingPickerBuilder{tracker: bt}).Build(base.PickerBuildInfo{
+		ReadySCs: map[balancer.SubConn]base.SubConnInfo{
+			a: {Address: resolver.Address{Addr: "a"}},
+			b: {Address: resolver.Address{Addr: "b"}},
+		},
+	})
+
+	picks := make(map[string]int)
+	for i := 0; i < 4; i++ {
+		res, err := picker.Pick(balancer.PickInfo{})
+		test.AssertNotError(t, err, "Pick failed")
+		picks[res.SubConn.(*fakeSubConn).name]++
+		res.Done(balancer.DoneInfo{BytesSent: true})
+	}
+	test.AssertEquals(t, picks["a"], 2)
+	test.AssertEquals(t, picks["b"], 2)
+
+	bt.backends["a"].ejectedUntil = bt.clk.Now().Add(time.Hour)
+	for i := 0; i < 4; i++ {
+		res, err := picker.Pick(balancer.PickInfo{})
+		test.AssertNotError(t, err, "Pick failed")
+		test.AssertEquals(t, res.SubConn.(*fakeSubConn).name, "b")
+	}
+
+	_, err := (&ejectingPickerBuilder{tracker: bt}).Build(base.PickerBuildInfo{}).Pick(balancer.PickInfo{})
+	test.AssertEquals(t, err, balancer.ErrNoSubConnAvailable)
+}
+
+// fakeBalancer is a balancer.V2Balancer which records the resolver states
+// it's given.
+type fakeBalancer struct {
+	balancer.Balancer
+	states []resolver.State
+}
+
+func (fb *fakeBalancer) UpdateClientConnState(s balancer.ClientConnState) error {
+	fb.states = append(fb.states, s.ResolverState)
+	return nil
+}
+
+func (fb *fakeBalancer) ResolverError(error) {}
+
+func (fb *fakeBalancer) UpdateSubConnState(balancer.SubConn, balancer.SubConnState) {}
+
+func TestEjectingBalancerPrunes(t *testing.T) {
+	bt, _ := newTestTracker()
+	for _, addr := range []string{"a", "b", "c"} {
+		bt.record(addr, time.Millisecond, nil)
+	}
+	inner := &fakeBalancer{}
+	eb := &ejectingBalancer{Balancer: inner, v2: inner, tracker: bt}
+
+	// Backends which the resolver no longer returns are forgotten, and the
+	// update is passed on.
+	state := resolver.State{Addresses: []resolver.Address{{Addr: "a"}, {Addr: "c"}, {Addr: "d"}}}
+	err := eb.UpdateClientConnState(balancer.ClientConnState{ResolverState: state})
+	test.AssertNotError(t, err, "UpdateClientConnState failed")
+	test.AssertEquals(t, len(inner.states), 1)
+	test.AssertEquals(t, len(bt.backends), 2)
+	_, ok := bt.backends["b"]
+	test.Assert(t, !ok, "removed backend wasn't pruned")
+}
+
+func TestHedging(t *testing.T) {
+	ci := &clientInterceptor{
+		timeout:      time.Second,
+		metrics:      NewClientMetrics(metrics.NoopRegisterer),
+		clk:          clock.New(),
+		hedgeAfter:   10 * time.Millisecond,
+		hedgeMethods: map[string]bool{"Chill": true},
+	}
+	hedged := func() int { return test.CountCounter(ci.metrics.hedgedRPCs.WithLabelValues("Chill", "service")) }
+
+	// The first request hangs, so the hedged request's response is used.
+	var calls int32
+	slowFirst := func(ctx context.Context, _ string, _, reply interface{}, _ *grpc.ClientConn, _ ...grpc.CallOption) error {
+		n := atomic.AddInt32(&calls, 1)
+		if n == 1 {
+			<-ctx.Done()
+			return ctx.Err()
+		}
+		reply.(*test_proto.Time).Time = int64(n)
+		return nil
+	}
+	var reply test_proto.Time
+	err := ci.intercept(context.Background(), "/service/Chill", &test_proto.Time{}, &reply, nil, slowFirst)
+	test.AssertNotError(t, err, "hedged request failed")
+	test.AssertEquals(t, reply.Time, int64(2))
+	test.AssertEquals(t, hedged(), 1)
+
+	// Fast responses aren't hedged, and neither are errors.
+	fast := func(_ context.Context, method string, _, reply interface{}, _ *grpc.ClientConn, _ ...grpc.CallOption) error {
+		reply.(*test_proto.Time).Time = 1
+		return nil
+	}
+	reply = test_proto.Time{}
+	err = ci.intercept(context.Background(), "/service/Chill", &test_proto.Time{}, &reply, nil, fast)
+	test.AssertNotError(t, err, "request failed")
+	test.AssertEquals(t, reply.Time, int64(1))
+	failing := func(context.Context, string, interface{}, interface{}, *grpc.ClientConn, ...grpc.CallOption) error {
+		return errors.New("broken")
+	}
+	err = ci.intercept(context.Background(), "/service/Chill", &test_proto.Time{}, &reply, nil, failing)
+	test.AssertError(t, err, "request didn't fail")
+	test.AssertEquals(t, hedged(), 1)
+
+	// Methods which aren't listed aren't hedged.
+	calls = 0
+	ci.timeout = 50 * time.Millisecond
+	err = ci.intercept(context.Background(), "/service/Other", &test_proto.Time{}, &test_proto.Time{}, nil, slowFirst)
+	test.AssertError(t, err, "unhedged request didn't time out")
+	test.AssertEquals(t, atomic.LoadInt32(&calls), int32(1))
+}
@@ -1,8 +1,10 @@
 package grpc
 
 import (
+	"context"
 	"crypto/tls"
 	"errors"
+	"fmt"
 	"net"
 
 	grpc_prometheus "github.com/grpc-ecosystem/go-grpc-prometheus"
@@ -11,6 +13,7 @@ import (
 	bcreds "github.com/letsencrypt/boulder/grpc/creds"
 	"github.com/prometheus/client_golang/prometheus"
 	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 
 	// Import for its init function, which causes clients to rely on the
 	// Health Service for load-balancing.
@@ -21,31 +24,108 @@ import (
 // a client certificate and validates the the server certificate based
 // on the provided *tls.Config.
 // It dials the remote service and returns a grpc.ClientConn if successful.
+// Unix domain socket addresses are dialed without TLS, so tlsConfig may be nil
+// for them.
 func ClientSetup(c *cmd.GRPCClientConfig, tlsConfig *tls.Config, metrics clientMetrics, clk clock.Clock) (*grpc.ClientConn, error) {
 	if c == nil {
 		return nil, errors.New("nil gRPC client config provided. JSON config is probably missing a fooService section.")
 	}
-	if c.ServerAddress == "" {
-		return nil, errors.New("ServerAddress must not be empty")
+	err := c.Validate()
+	if err != nil {
+		return nil, err
 	}
-	if tlsConfig == nil {
+	network, address, err := cmd.ParseGRPCAddress(c.ServerAddress)
+	if err != nil {
+		return nil, err
+	}
+	if network == "tcp" && tlsConfig == nil {
 		return nil, errNilTLS
 	}
 
-	ci := clientInterceptor{c.Timeout.Duration, metrics, clk}
-	host, _, err := net.SplitHostPort(c.ServerAddress)
+	ci := clientInterceptor{
+		timeout:    c.Timeout.Duration,
+		metrics:    metrics,
+		clk:        clk,
+		hedgeAfter: c.HedgeAfter.Duration,
+	}
+	if len(c.HedgeMethods) > 0 {
+		ci.hedgeMethods = make(map[string]bool)
+		for _, method := range c.HedgeMethods {
+			ci.hedgeMethods[method] = true
+		}
+	}
+	if len(c.Methods) > 0 {
+		ci.methods = make(map[string]methodConfig)
+		for name, mc := range c.Methods {
+			ci.methods[name] = methodConfig{
+				timeout:       mc.Timeout.Duration,
+				maxRetries:    mc.MaxRetries,
+				backoffBase:   mc.BackoffBase.Duration,
+				backoffMax:    mc.BackoffMax.Duration,
+				backoffFactor: mc.BackoffFactor,
+			}
+		}
+	}
+	balancerName := "round_robin"
+	if c.Ejection != nil {
+		balancerName = registerEjectingBalancer(c.Ejection, metrics.ejections, clk)
+	}
+	opts := []grpc.DialOption{
+		grpc.WithBalancerName(balancerName),
+		grpc.WithUnaryInterceptor(ci.intercept),
+		grpc.WithStreamInterceptor(ci.interceptStream),
+	}
+	if c.Ejection != nil {
+		// The resolver's service config is ignored so that it can't disable
+		// health checking.
+		opts = append(opts,
+			grpc.WithDisableServiceConfig(),
+			grpc.WithDefaultServiceConfig(healthCheckServiceConfig),
+		)
+	}
+
+	if network == "unix" {
+		// Local connections aren't encrypted: the server is authorized by
+		// the socket's filesystem permissions.
+		return grpc.Dial(
+			"passthrough:///"+address,
+			append(opts, grpc.WithInsecure(), grpc.WithContextDialer(dialUnix))...,
+		)
+	}
+
+	host, _, err := net.SplitHostPort(address)
 	if err != nil {
 		return nil, err
 	}
 	creds := bcreds.NewClientCredentials(tlsConfig.RootCAs, tlsConfig.Certificates, host)
+	if current := cmd.CurrentTLSConfig(tlsConfig); current != nil {
+		// The certificates and roots may be reloaded from disk, so use the
+		// current ones for each new connection.
+		creds = bcreds.NewReloadingClientCredentials(current, host)
+	}
 	return grpc.Dial(
-		"dns:///"+c.ServerAddress,
-		grpc.WithBalancerName("round_robin"),
-		grpc.WithTransportCredentials(creds),
-		grpc.WithUnaryInterceptor(ci.intercept),
+		"dns:///"+address,
+		append(opts, grpc.WithTransportCredentials(creds))...,
 	)
 }
 
+// HealthCheck returns a cmd.HealthCheck which asks the backends of conn, using
+// the gRPC Health Service, whether they're serving, so that a service can
+// register its gRPC backends as dependencies with cmd.RegisterReadinessCheck.
+func HealthCheck(conn *grpc.ClientConn) cmd.HealthCheck {
+	client := healthpb.NewHealthClient(conn)
+	return func(ctx context.Context) error {
+		resp, err := client.Check(ctx, &healthpb.HealthCheckRequest{})
+		if err != nil {
+			return err
+		}
+		if resp.Status != healthpb.HealthCheckResponse_SERVING {
+			return fmt.Errorf("backend status is %s", resp.Status)
+		}
+		return nil
+	}
+}
+
 type registry interface {
 	MustRegister(...prometheus.Collector)
 }
@@ -57,6 +137,16 @@ type clientMetrics struct {
 	// inFlightRPCs is a labelled gauge that slices by service/method the number
 	// of outstanding/in-flight RPCs.
 	inFlightRPCs *prometheus.GaugeVec
+
+	// hedgedRPCs counts, by service/method, the RPCs for which a second,
+	// hedged request was sent.
+	hedgedRPCs *prometheus.CounterVec
+	// retries counts, by service/method, the requests which were retried
+	// after failing.
+	retries *prometheus.CounterVec
+	// ejections counts, by backend address, the times a backend has been
+	// ejected from load balancing for being unhealthy.
+	ejections *prometheus.CounterVec
 }
 
 // NewClientMetrics constructs a *grpc_prometheus.ClientMetrics, registered with
@@ -75,8 +165,29 @@ func NewClientMetrics(stats registry) clientMetrics {
 	}, []string{"method", "service"})
 	stats.MustRegister(inFlightGauge)
 
+	hedgedRPCs := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "grpc_hedged_rpcs",
+		Help: "Number of RPCs for which a second, hedged request was sent",
+	}, []string{"method", "service"})
+	stats.MustRegister(hedgedRPCs)
+
+	retries := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "grpc_retries",
+		Help: "Number of times an RPC was retried after failing",
+	}, []string{"method", "service"})
+	stats.MustRegister(retries)
+
+	ejections := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "grpc_backend_ejections",
+		Help: "Number of times a gRPC backend has been ejected from load balancing for being unhealthy",
+	}, []string{"backend"})
+	stats.MustRegister(ejections)
+
 	return clientMetrics{
 		grpcMetrics:  grpcMetrics,
 		inFlightRPCs: inFlightGauge,
+		hedgedRPCs:   hedgedRPCs,
+		retries:      retries,
+		ejections:    ejections,
 	}
 }
@@ -43,11 +43,22 @@ type clientTransportCredentials struct {
 	// If set, this is used as the hostname to validate on certificates, instead
 	// of the value passed to ClientHandshake by grpc.
 	hostOverride string
+	// If set, this is called before each handshake to get the current roots
+	// and client certificates, which are used instead of the fields above.
+	current func() *tls.Config
 }
 
 // NewClientCredentials returns a new initialized grpc/credentials.TransportCredentials for client usage
 func NewClientCredentials(rootCAs *x509.CertPool, clientCerts []tls.Certificate, hostOverride string) credentials.TransportCredentials {
-	return &clientTransportCredentials{rootCAs, clientCerts, hostOverride}
+	return &clientTransportCredentials{roots: rootCAs, clients: clientCerts, hostOverride: hostOverride}
+}
+
+// NewReloadingClientCredentials returns a new initialized
+// grpc/credentials.TransportCredentials for client usage, which takes its roots
+// and client certificates from the config returned by current on each
+// handshake, for certificates which are reloaded from disk.
+func NewReloadingClientCredentials(current func() *tls.Config, hostOverride string) credentials.TransportCredentials {
+	return &clientTransportCredentials{hostOverride: hostOverride, current: current}
 }
 
 // ClientHandshake does the authentication handshake specified by the corresponding
@@ -65,10 +76,15 @@ func (tc *clientTransportCredentials) ClientHandshake(ctx context.Context, addr
 			return nil, nil, err
 		}
 	}
+	roots, clients := tc.roots, tc.clients
+	if tc.current != nil {
+		config := tc.current()
+		roots, clients = config.RootCAs, config.Certificates
+	}
 	conn := tls.Client(rawConn, &tls.Config{
 		ServerName:   host,
-		RootCAs:      tc.roots,
-		Certificates: tc.clients,
+		RootCAs:      roots,
+		Certificates: clients,
 		MinVersion:   tls.VersionTLS12, // Override default of tls.VersionTLS10
 		MaxVersion:   tls.VersionTLS12, // Same as default in golang <= 1.6
 	})
@@ -114,7 +130,7 @@ func (tc *clientTransportCredentials) RequireTransportSecurity() bool {
 
 // Clone returns a copy of the clientTransportCredentials
 func (tc *clientTransportCredentials) Clone() credentials.TransportCredentials {
-	return NewClientCredentials(tc.roots, tc.clients, tc.hostOverride)
+	return &clientTransportCredentials{tc.roots, tc.clients, tc.hostOverride, tc.current}
 }
 
 // OverrideServerName is not implemented and here only to satisfy the interface
@@ -14,6 +14,7 @@ import (
 	testproto "github.com/letsencrypt/boulder/grpc/test_proto"
 	"github.com/letsencrypt/boulder/identifier"
 	"github.com/letsencrypt/boulder/metrics"
+	noncepb "github.com/letsencrypt/boulder/nonce/proto"
 	"github.com/letsencrypt/boulder/test"
 )
 
@@ -28,7 +29,11 @@ func (s *errorServer) Chill(_ context.Context, _ *testproto.Time) (*testproto.Ti
 func TestErrorWrapping(t *testing.T) {
 	serverMetrics := NewServerMetrics(metrics.NoopRegisterer)
 	si := newServerInterceptor(serverMetrics, clock.NewFake())
-	ci := clientInterceptor{time.Second, NewClientMetrics(metrics.NoopRegisterer), clock.NewFake()}
+	ci := clientInterceptor{
+		timeout: time.Second,
+		metrics: NewClientMetrics(metrics.NoopRegisterer),
+		clk:     clock.NewFake(),
+	}
 	srv := grpc.NewServer(grpc.UnaryInterceptor(si.intercept))
 	es := &errorServer{}
 	testproto.RegisterChillerServer(srv, es)
@@ -59,7 +64,11 @@ func TestErrorWrapping(t *testing.T) {
 func TestSubErrorWrapping(t *testing.T) {
 	serverMetrics := NewServerMetrics(metrics.NoopRegisterer)
 	si := newServerInterceptor(serverMetrics, clock.NewFake())
-	ci := clientInterceptor{time.Second, NewClientMetrics(metrics.NoopRegisterer), clock.NewFake()}
+	ci := clientInterceptor{
+		timeout: time.Second,
+		metrics: NewClientMetrics(metrics.NoopRegisterer),
+		clk:     clock.NewFake(),
+	}
 	srv := grpc.NewServer(grpc.UnaryInterceptor(si.intercept))
 	es := &errorServer{}
 	testproto.RegisterChillerServer(srv, es)
@@ -95,3 +104,55 @@ func TestSubErrorWrapping(t *testing.T) {
 	test.Assert(t, err != nil, fmt.Sprintf("nil error returned, expected: %s", err))
 	test.AssertDeepEquals(t, err, es.err)
 }
+
+// streamErrorServer is a nonce service whose NonceStream sends one nonce and
+// then fails with err.
+type streamErrorServer struct {
+	noncepb.UnimplementedNonceServiceServer
+	err error
+}
+
+func (s *streamErrorServer) NonceStream(_ *noncepb.NonceStreamRequest, stream noncepb.NonceService_NonceStreamServer) error {
+	err := stream.Send(&noncepb.NonceMessage{Nonce: "nonce"})
+	if err != nil {
+		return err
+	}
+	return s.err
+}
+
+// TestStreamErrorWrapping tests that a boulder error ending a stream is
+// wrapped and unwrapped across the RPC layer, as for unary RPCs.
+func TestStreamErrorWrapping(t *testing.T) {
+	serverMetrics := NewServerMetrics(metrics.NoopRegisterer)
+	si := newServerInterceptor(serverMetrics, clock.NewFake())
+	ci := clientInterceptor{
+		timeout: time.Second,
+		metrics: NewClientMetrics(metrics.NoopRegisterer),
+		clk:     clock.NewFake(),
+	}
+	srv := grpc.NewServer(grpc.StreamInterceptor(si.interceptStream))
+	es := &streamErrorServer{err: berrors.RateLimitError("slow down")}
+	noncepb.RegisterNonceServiceServer(srv, es)
+	lis, err := net.Listen("tcp", "127.0.0.1:")
+	test.AssertNotError(t, err, "Failed to create listener")
+	go func() { _ = srv.Serve(lis) }()
+	defer srv.Stop()
+
+	conn, err := grpc.Dial(
+		lis.Addr().String(),
+		grpc.WithInsecure(),
+		grpc.WithStreamInterceptor(ci.interceptStream),
+	)
+	test.AssertNotError(t, err, "Failed to dial grpc test server")
+	client := noncepb.NewNonceServiceClient(conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	stream, err := client.NonceStream(ctx, &noncepb.NonceStreamRequest{Count: 1})
+	test.AssertNotError(t, err, "NonceStream failed")
+	msg, err := stream.Recv()
+	test.AssertNotError(t, err, "Recv failed")
+	test.AssertEquals(t, msg.Nonce, "nonce")
+	_, err = stream.Recv()
+	test.AssertDeepEquals(t, err, es.err)
+}
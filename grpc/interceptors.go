@@ -3,10 +3,12 @@ package grpc
 import (
 	"context"
 	"fmt"
+	"io"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/jmhodges/clock"
 	"github.com/prometheus/client_golang/prometheus"
 	"google.golang.org/grpc"
@@ -14,6 +16,7 @@ import (
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 
+	"github.com/letsencrypt/boulder/core"
 	berrors "github.com/letsencrypt/boulder/errors"
 )
 
@@ -82,6 +85,26 @@ func (si *serverInterceptor) intercept(ctx context.Context, req interface{}, inf
 	return resp, err
 }
 
+// interceptStream fulfils the grpc.StreamServerInterceptor interface. Like
+// intercept, it observes the RPC's latency and metrics, and wraps any Boulder
+// error the handler returns for transmission in the stream's trailer. Streams
+// may outlive any single request, so their deadlines are left as they are.
+func (si *serverInterceptor) interceptStream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if info == nil {
+		return berrors.InternalServerError("passed nil *grpc.StreamServerInfo")
+	}
+	if md, ok := metadata.FromIncomingContext(ss.Context()); ok && len(md[clientRequestTimeKey]) > 0 {
+		if err := si.observeLatency(md[clientRequestTimeKey][0]); err != nil {
+			return err
+		}
+	}
+	err := si.metrics.grpcMetrics.StreamServerInterceptor()(srv, ss, info, handler)
+	if err != nil {
+		err = wrapError(ss.Context(), err)
+	}
+	return err
+}
+
 // splitMethodName is borrowed directly from
 // `grpc-ecosystem/go-grpc-prometheus/util.go` and is used to extract the
 // service and method name from the `method` argument to
@@ -125,6 +148,25 @@ type clientInterceptor struct {
 	timeout time.Duration
 	metrics clientMetrics
 	clk     clock.Clock
+
+	// hedgeAfter, if non-zero, is how long to wait for a response to one of
+	// hedgeMethods before sending a second request. hedgeMethods is keyed by
+	// method name, without the service name.
+	hedgeAfter   time.Duration
+	hedgeMethods map[string]bool
+
+	// methods holds per-method timeouts and retry policies, keyed by method
+	// name without the service name.
+	methods map[string]methodConfig
+}
+
+// methodConfig is the timeout and retry policy for a single method.
+type methodConfig struct {
+	timeout       time.Duration
+	maxRetries    int
+	backoffBase   time.Duration
+	backoffMax    time.Duration
+	backoffFactor float64
 }
 
 // intercept fulfils the grpc.UnaryClientInterceptor interface, it should be noted that while this API
@@ -143,7 +185,17 @@ func (ci *clientInterceptor) intercept(
 		return berrors.InternalServerError("clientInterceptor has nil inFlightRPCs gauge")
 	}
 
-	localCtx, cancel := context.WithTimeout(ctx, ci.timeout)
+	// Split the method and service name from the fullMethod.
+	// UnaryClientInterceptor's receive a `method` arg of the form
+	// "/ServiceName/MethodName"
+	service, method := splitMethodName(fullMethod)
+	mc := ci.methods[method]
+	timeout := ci.timeout
+	if mc.timeout > 0 {
+		timeout = mc.timeout
+	}
+
+	localCtx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 	// Disable fail-fast so RPCs will retry until deadline, even if all backends
 	// are down.
@@ -158,16 +210,6 @@ func (ci *clientInterceptor) intercept(
 	// Configure the localCtx with the metadata so it gets sent along in the request
 	localCtx = metadata.NewOutgoingContext(localCtx, reqMD)
 
-	// Create a grpc/metadata.Metadata instance for a grpc.Trailer.
-	respMD := metadata.New(nil)
-	// Configure a grpc Trailer with respMD. This allows us to wrap error
-	// types in the server interceptor later on.
-	opts = append(opts, grpc.Trailer(&respMD))
-
-	// Split the method and service name from the fullMethod.
-	// UnaryClientInterceptor's receive a `method` arg of the form
-	// "/ServiceName/MethodName"
-	service, method := splitMethodName(fullMethod)
 	// Slice the inFlightRPC inc/dec calls by method and service
 	labels := prometheus.Labels{
 		"method":  method,
@@ -180,9 +222,27 @@ func (ci *clientInterceptor) intercept(
 	defer ci.metrics.inFlightRPCs.With(labels).Dec()
 	// Handle the RPC
 	begin := ci.clk.Now()
-	err := ci.metrics.grpcMetrics.UnaryClientInterceptor()(localCtx, fullMethod, req, reply, cc, invoker, opts...)
-	if err != nil {
-		err = unwrapError(err, respMD)
+	var err error
+	for attempt := 0; ; attempt++ {
+		if ci.hedgeAfter > 0 && ci.hedgeMethods[method] {
+			err = ci.hedgedInvoke(localCtx, labels, fullMethod, req, reply, cc, invoker, opts...)
+		} else {
+			err = ci.invoke(localCtx, fullMethod, req, reply, cc, invoker, opts...)
+		}
+		// Only Unavailable errors are retried, since they mean the request
+		// never reached a backend, or reached one which was shutting down.
+		if err == nil || attempt >= mc.maxRetries || status.Code(err) != codes.Unavailable {
+			break
+		}
+		ci.metrics.retries.With(labels).Inc()
+		backoff := core.RetryBackoff(attempt+1, mc.backoffBase, mc.backoffMax, mc.backoffFactor)
+		select {
+		case <-localCtx.Done():
+			// There's no time left for another attempt.
+		case <-ci.clk.After(backoff):
+			continue
+		}
+		break
 	}
 	if status.Code(err) == codes.DeadlineExceeded {
 		return deadlineDetails{
@@ -194,6 +254,125 @@ func (ci *clientInterceptor) intercept(
 	return err
 }
 
+// invoke sends a single request, unwrapping any Boulder error in the
+// response.
+func (ci *clientInterceptor) invoke(
+	ctx context.Context,
+	fullMethod string,
+	req,
+	reply interface{},
+	cc *grpc.ClientConn,
+	invoker grpc.UnaryInvoker,
+	opts ...grpc.CallOption) error {
+	// Create a grpc/metadata.Metadata instance for a grpc.Trailer.
+	respMD := metadata.New(nil)
+	// Configure a grpc Trailer with respMD. This allows us to wrap error
+	// types in the server interceptor later on.
+	opts = append(opts, grpc.Trailer(&respMD))
+	err := ci.metrics.grpcMetrics.UnaryClientInterceptor()(ctx, fullMethod, req, reply, cc, invoker, opts...)
+	if err != nil {
+		err = unwrapError(err, respMD)
+	}
+	return err
+}
+
+// hedgedInvoke sends a request and, if there's no response within
+// ci.hedgeAfter, sends a second identical one, most likely to a different
+// backend. The first successful response is copied into reply and the other
+// request is cancelled. If both fail, the first error is returned.
+func (ci *clientInterceptor) hedgedInvoke(
+	ctx context.Context,
+	labels prometheus.Labels,
+	fullMethod string,
+	req,
+	reply interface{},
+	cc *grpc.ClientConn,
+	invoker grpc.UnaryInvoker,
+	opts ...grpc.CallOption) error {
+	replyMsg, ok := reply.(proto.Message)
+	if !ok {
+		return ci.invoke(ctx, fullMethod, req, reply, cc, invoker, opts...)
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type result struct {
+		reply proto.Message
+		err   error
+	}
+	// Buffered so that the losing request never blocks.
+	results := make(chan result, 2)
+	send := func() {
+		// Each request gets its own reply, so the loser can't write to the
+		// winner's.
+		r := proto.Clone(replyMsg)
+		r.Reset()
+		err := ci.invoke(ctx, fullMethod, req, r, cc, invoker, opts...)
+		results <- result{r, err}
+	}
+
+	go send()
+	outstanding := 1
+	var first result
+	select {
+	case first = <-results:
+		outstanding--
+	case <-ci.clk.After(ci.hedgeAfter):
+		ci.metrics.hedgedRPCs.With(labels).Inc()
+		go send()
+		outstanding++
+		first = <-results
+		outstanding--
+	}
+	winner := first
+	if first.err != nil && outstanding > 0 {
+		if second := <-results; second.err == nil {
+			winner = second
+		}
+	}
+	if winner.err != nil {
+		return first.err
+	}
+	replyMsg.Reset()
+	proto.Merge(replyMsg, winner.reply)
+	return nil
+}
+
+// interceptStream fulfils the grpc.StreamClientInterceptor interface. It
+// tags the request with the time it was sent and records the stream's
+// metrics, and the returned stream unwraps any Boulder error the server ends
+// it with. The caller's context must bound the stream, since it isn't given
+// the unary timeout.
+func (ci *clientInterceptor) interceptStream(
+	ctx context.Context,
+	desc *grpc.StreamDesc,
+	cc *grpc.ClientConn,
+	fullMethod string,
+	streamer grpc.Streamer,
+	opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	nowTS := strconv.FormatInt(ci.clk.Now().UnixNano(), 10)
+	ctx = metadata.AppendToOutgoingContext(ctx, clientRequestTimeKey, nowTS)
+	stream, err := ci.metrics.grpcMetrics.StreamClientInterceptor()(ctx, desc, cc, fullMethod, streamer, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &clientStream{ClientStream: stream}, nil
+}
+
+// clientStream unwraps the Boulder errors which end a stream.
+type clientStream struct {
+	grpc.ClientStream
+}
+
+func (cs *clientStream) RecvMsg(m interface{}) error {
+	err := cs.ClientStream.RecvMsg(m)
+	if err != nil && err != io.EOF {
+		// The trailer is available once RecvMsg has returned an error.
+		err = unwrapError(err, cs.ClientStream.Trailer())
+	}
+	return err
+}
+
 // deadlineDetails is an error type that we use in place of gRPC's
 // DeadlineExceeded errors in order to add more detail for debugging.
 type deadlineDetails struct {
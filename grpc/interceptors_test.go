@@ -16,6 +16,7 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 
 	"github.com/letsencrypt/boulder/grpc/test_proto"
 	"github.com/letsencrypt/boulder/metrics"
@@ -73,6 +74,57 @@ func TestClientInterceptor(t *testing.T) {
 	test.AssertError(t, err, "ci.intercept didn't fail when handler returned a error")
 }
 
+func TestMethodRetries(t *testing.T) {
+	ci := clientInterceptor{
+		timeout: time.Second,
+		metrics: NewClientMetrics(metrics.NoopRegisterer),
+		clk:     clock.New(),
+		methods: map[string]methodConfig{
+			"Retried": {
+				timeout:       100 * time.Millisecond,
+				maxRetries:    2,
+				backoffBase:   time.Millisecond,
+				backoffMax:    5 * time.Millisecond,
+				backoffFactor: 2,
+			},
+		},
+	}
+	var calls int
+	var deadlines []time.Duration
+	unavailable := func(ctx context.Context, _ string, _, _ interface{}, _ *grpc.ClientConn, _ ...grpc.CallOption) error {
+		calls++
+		deadline, _ := ctx.Deadline()
+		deadlines = append(deadlines, time.Until(deadline))
+		return status.Error(codes.Unavailable, "no backends")
+	}
+	retries := func(method string) int {
+		return test.CountCounter(ci.metrics.retries.WithLabelValues(method, "service"))
+	}
+
+	err := ci.intercept(context.Background(), "/service/Retried", nil, nil, nil, unavailable)
+	test.AssertEquals(t, status.Code(err), codes.Unavailable)
+	test.AssertEquals(t, calls, 3)
+	test.AssertEquals(t, retries("Retried"), 2)
+	test.Assert(t, deadlines[0] <= 100*time.Millisecond, "method Timeout wasn't used")
+
+	// Methods without a config use the client's timeout and aren't retried.
+	calls, deadlines = 0, nil
+	err = ci.intercept(context.Background(), "/service/Other", nil, nil, nil, unavailable)
+	test.AssertEquals(t, status.Code(err), codes.Unavailable)
+	test.AssertEquals(t, calls, 1)
+	test.AssertEquals(t, retries("Other"), 0)
+	test.Assert(t, deadlines[0] > 100*time.Millisecond, "client Timeout wasn't used")
+
+	// Other errors aren't retried.
+	calls = 0
+	err = ci.intercept(context.Background(), "/service/Retried", nil, nil, nil, func(context.Context, string, interface{}, interface{}, *grpc.ClientConn, ...grpc.CallOption) error {
+		calls++
+		return status.Error(codes.InvalidArgument, "bad request")
+	})
+	test.AssertEquals(t, status.Code(err), codes.InvalidArgument)
+	test.AssertEquals(t, calls, 1)
+}
+
 // TestFailFastFalse sends a gRPC request to a backend that is
 // unavailable, and ensures that the request doesn't error out until the
 // timeout is reached, i.e. that FailFast is set to false.
@@ -138,12 +138,18 @@ func ValidationRecordToPB(record core.ValidationRecord) (*corepb.ValidationRecor
 		return nil, err
 	}
 	return &corepb.ValidationRecord{
-		Hostname:          record.Hostname,
-		Port:              record.Port,
-		AddressesResolved: addrs,
-		AddressUsed:       addrUsed,
-		Url:               record.URL,
-		AddressesTried:    addrsTried,
+		Hostname:           record.Hostname,
+		Port:               record.Port,
+		AddressesResolved:  addrs,
+		AddressUsed:        addrUsed,
+		Url:                record.URL,
+		AddressesTried:     addrsTried,
+		ConnectTimeout:     record.ConnectTimeout,
+		AttemptBudget:      record.AttemptBudget,
+		Attempts:           record.Attempts,
+		ServerHeader:       record.ServerHeader,
+		NegotiatedProtocol: record.NegotiatedProtocol,
+		CertificateSHA256:  record.CertificateSHA256,
 	}, nil
 }
 
@@ -165,12 +171,18 @@ func PBToValidationRecord(in *corepb.ValidationRecord) (record core.ValidationRe
 		return
 	}
 	return core.ValidationRecord{
-		Hostname:          in.Hostname,
-		Port:              in.Port,
-		AddressesResolved: addrs,
-		AddressUsed:       addrUsed,
-		URL:               in.Url,
-		AddressesTried:    addrsTried,
+		Hostname:           in.Hostname,
+		Port:               in.Port,
+		AddressesResolved:  addrs,
+		AddressUsed:        addrUsed,
+		URL:                in.Url,
+		AddressesTried:     addrsTried,
+		ConnectTimeout:     in.ConnectTimeout,
+		AttemptBudget:      in.AttemptBudget,
+		Attempts:           in.Attempts,
+		ServerHeader:       in.ServerHeader,
+		NegotiatedProtocol: in.NegotiatedProtocol,
+		CertificateSHA256:  in.CertificateSHA256,
 	}, nil
 }
 
@@ -312,7 +324,7 @@ func PBToAuthz(pb *corepb.Authorization) (core.Authorization, error) {
 	expires := time.Unix(0, pb.Expires).UTC()
 	authz := core.Authorization{
 		ID:             pb.Id,
-		Identifier:     identifier.ACMEIdentifier{Type: identifier.DNS, Value: pb.Identifier},
+		Identifier:     identifier.FromValue(pb.Identifier),
 		RegistrationID: pb.RegistrationID,
 		Status:         core.AcmeStatus(pb.Status),
 		Expires:        &expires,
@@ -373,6 +385,10 @@ func PBToCert(pb *corepb.Certificate) (core.Certificate, error) {
 }
 
 func CertStatusToPB(certStatus core.CertificateStatus) *corepb.CertificateStatus {
+	var issuerID int64
+	if certStatus.IssuerID != nil {
+		issuerID = *certStatus.IssuerID
+	}
 	return &corepb.CertificateStatus{
 		Serial:                certStatus.Serial,
 		Status:                string(certStatus.Status),
@@ -383,10 +399,15 @@ func CertStatusToPB(certStatus core.CertificateStatus) *corepb.CertificateStatus
 		OcspResponse:          certStatus.OCSPResponse,
 		NotAfter:              certStatus.NotAfter.UnixNano(),
 		IsExpired:             certStatus.IsExpired,
+		IssuerID:              issuerID,
 	}
 }
 
 func PBToCertStatus(pb *corepb.CertificateStatus) (core.CertificateStatus, error) {
+	var issuerID *int64
+	if pb.IssuerID != 0 {
+		issuerID = &pb.IssuerID
+	}
 	return core.CertificateStatus{
 		Serial:                pb.Serial,
 		Status:                core.OCSPStatus(pb.Status),
@@ -397,6 +418,7 @@ func PBToCertStatus(pb *corepb.CertificateStatus) (core.CertificateStatus, error
 		OCSPResponse:          pb.OcspResponse,
 		NotAfter:              time.Unix(0, pb.NotAfter),
 		IsExpired:             pb.IsExpired,
+		IssuerID:              issuerID,
 	}, nil
 }
 
@@ -100,12 +100,18 @@ func TestChallenge(t *testing.T) {
 func TestValidationRecord(t *testing.T) {
 	ip := net.ParseIP("1.1.1.1")
 	vr := core.ValidationRecord{
-		Hostname:          "host",
-		Port:              "2020",
-		AddressesResolved: []net.IP{ip},
-		AddressUsed:       ip,
-		URL:               "url",
-		AddressesTried:    []net.IP{ip},
+		Hostname:           "host",
+		Port:               "2020",
+		AddressesResolved:  []net.IP{ip},
+		AddressUsed:        ip,
+		URL:                "url",
+		AddressesTried:     []net.IP{ip},
+		ConnectTimeout:     "5s",
+		AttemptBudget:      "20s",
+		Attempts:           2,
+		ServerHeader:       "nginx",
+		NegotiatedProtocol: "acme-tls/1",
+		CertificateSHA256:  "00ff",
 	}
 
 	pb, err := ValidationRecordToPB(vr)
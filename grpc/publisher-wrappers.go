@@ -36,6 +36,18 @@ func (pc *PublisherClientWrapper) SubmitToSingleCTWithResult(ctx context.Context
 	return res, nil
 }
 
+// QueueFinalCert is a wrapper
+func (pc *PublisherClientWrapper) QueueFinalCert(ctx context.Context, req *pubpb.FinalCertRequest) (*pubpb.FinalCertQueued, error) {
+	res, err := pc.inner.QueueFinalCert(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if res == nil {
+		return nil, errIncompleteResponse
+	}
+	return res, nil
+}
+
 // PublisherServerWrapper is the gRPC version of a core.Publisher
 type PublisherServerWrapper struct {
 	inner *publisher.Impl
@@ -50,3 +62,8 @@ func NewPublisherServerWrapper(inner *publisher.Impl) *PublisherServerWrapper {
 func (pub *PublisherServerWrapper) SubmitToSingleCTWithResult(ctx context.Context, req *pubpb.Request) (*pubpb.Result, error) {
 	return pub.inner.SubmitToSingleCTWithResult(ctx, req)
 }
+
+// QueueFinalCert is a wrapper
+func (pub *PublisherServerWrapper) QueueFinalCert(ctx context.Context, req *pubpb.FinalCertRequest) (*pubpb.FinalCertQueued, error) {
+	return pub.inner.QueueFinalCert(ctx, req)
+}
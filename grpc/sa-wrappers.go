@@ -8,6 +8,7 @@ package grpc
 
 import (
 	"context"
+	"errors"
 	"net"
 	"time"
 
@@ -91,6 +92,33 @@ func (sac StorageAuthorityClientWrapper) GetCertificateStatus(ctx context.Contex
 	return PBToCertStatus(response)
 }
 
+func (sac StorageAuthorityClientWrapper) GetCertificateStatuses(ctx context.Context, req *sapb.Serials) (*sapb.CertificateStatuses, error) {
+	statuses, err := sac.inner.GetCertificateStatuses(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if statuses == nil {
+		return nil, errIncompleteResponse
+	}
+	for _, status := range statuses.Statuses {
+		if status == nil || status.Serial == "" || status.Status == "" {
+			return nil, errIncompleteResponse
+		}
+	}
+	return statuses, nil
+}
+
+func (sac StorageAuthorityClientWrapper) GetMaxCertificateStatusID(ctx context.Context, req *corepb.Empty) (*sapb.CertificateStatusID, error) {
+	resp, err := sac.inner.GetMaxCertificateStatusID(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if resp == nil {
+		return nil, errIncompleteResponse
+	}
+	return resp, nil
+}
+
 func (sac StorageAuthorityClientWrapper) CountCertificatesByNames(ctx context.Context, domains []string, earliest, latest time.Time) ([]*sapb.CountByNames_MapElement, error) {
 	earliestNano := earliest.UnixNano()
 	latestNano := latest.UnixNano()
@@ -211,6 +239,42 @@ func (sac StorageAuthorityClientWrapper) PreviousCertificateExists(
 	return exists, err
 }
 
+func (sac StorageAuthorityClientWrapper) ReplacementCertificateExists(
+	ctx context.Context,
+	req *sapb.ReplacementCertificateExistsRequest,
+) (*sapb.Exists, error) {
+	exists, err := sac.inner.ReplacementCertificateExists(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if exists == nil {
+		return nil, errIncompleteResponse
+	}
+	return exists, nil
+}
+
+func (sac StorageAuthorityClientWrapper) GetSerialsByKey(ctx context.Context, req *sapb.SPKIHash) (*sapb.Serials, error) {
+	serials, err := sac.inner.GetSerialsByKey(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if serials == nil {
+		return nil, errIncompleteResponse
+	}
+	return serials, nil
+}
+
+func (sac StorageAuthorityClientWrapper) GetSerialsByAccount(ctx context.Context, req *sapb.RegistrationID) (*sapb.Serials, error) {
+	serials, err := sac.inner.GetSerialsByAccount(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if serials == nil {
+		return nil, errIncompleteResponse
+	}
+	return serials, nil
+}
+
 func (sac StorageAuthorityClientWrapper) AddPrecertificate(
 	ctx context.Context,
 	req *sapb.AddCertificateRequest,
@@ -331,6 +395,17 @@ func (sas StorageAuthorityClientWrapper) NewOrder(ctx context.Context, request *
 	return resp, nil
 }
 
+func (sas StorageAuthorityClientWrapper) NewOrderAndAuthzs(ctx context.Context, req *sapb.NewOrderAndAuthzsRequest) (*corepb.Order, error) {
+	resp, err := sas.inner.NewOrderAndAuthzs(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if resp == nil || !orderValid(resp) {
+		return nil, errIncompleteResponse
+	}
+	return resp, nil
+}
+
 func (sac StorageAuthorityClientWrapper) SetOrderProcessing(ctx context.Context, order *corepb.Order) error {
 	if _, err := sac.inner.SetOrderProcessing(ctx, order); err != nil {
 		return err
@@ -488,6 +563,187 @@ func (sac StorageAuthorityClientWrapper) KeyBlocked(ctx context.Context, req *sa
 	return sac.inner.KeyBlocked(ctx, req)
 }
 
+func (sac StorageAuthorityClientWrapper) AddIncident(ctx context.Context, req *sapb.AddIncidentRequest) (*sapb.Incident, error) {
+	resp, err := sac.inner.AddIncident(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if resp == nil || resp.Id == 0 {
+		return nil, errIncompleteResponse
+	}
+	return resp, nil
+}
+
+func (sac StorageAuthorityClientWrapper) AddIncidentSerials(ctx context.Context, req *sapb.AddIncidentSerialsRequest) (*corepb.Empty, error) {
+	// All return checking is done at the call site
+	return sac.inner.AddIncidentSerials(ctx, req)
+}
+
+func (sac StorageAuthorityClientWrapper) SetIncidentStatus(ctx context.Context, req *sapb.SetIncidentStatusRequest) (*corepb.Empty, error) {
+	// All return checking is done at the call site
+	return sac.inner.SetIncidentStatus(ctx, req)
+}
+
+func (sac StorageAuthorityClientWrapper) IncidentsForSerial(ctx context.Context, req *sapb.Serial) (*sapb.Incidents, error) {
+	resp, err := sac.inner.IncidentsForSerial(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if resp == nil {
+		return nil, errIncompleteResponse
+	}
+	return resp, nil
+}
+
+func (sac StorageAuthorityClientWrapper) GetNotificationPreferences(ctx context.Context, req *sapb.RegistrationID) (*sapb.NotificationPreferences, error) {
+	resp, err := sac.inner.GetNotificationPreferences(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if resp == nil || resp.RegistrationID == 0 {
+		return nil, errIncompleteResponse
+	}
+	return resp, nil
+}
+
+func (sac StorageAuthorityClientWrapper) SetNotificationPreferences(ctx context.Context, req *sapb.NotificationPreferences) (*corepb.Empty, error) {
+	// All return checking is done at the call site
+	return sac.inner.SetNotificationPreferences(ctx, req)
+}
+
+func (sac StorageAuthorityClientWrapper) GetFeatureOverrides(ctx context.Context, req *sapb.RegistrationID) (*sapb.FeatureOverrides, error) {
+	resp, err := sac.inner.GetFeatureOverrides(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if resp == nil {
+		return nil, errIncompleteResponse
+	}
+	for _, override := range resp.Overrides {
+		if override == nil || override.Feature == "" {
+			return nil, errIncompleteResponse
+		}
+	}
+	return resp, nil
+}
+
+func (sac StorageAuthorityClientWrapper) SetFeatureOverride(ctx context.Context, req *sapb.FeatureOverrideRequest) (*corepb.Empty, error) {
+	// All return checking is done at the call site
+	return sac.inner.SetFeatureOverride(ctx, req)
+}
+
+func (sac StorageAuthorityClientWrapper) ClearFeatureOverride(ctx context.Context, req *sapb.FeatureOverrideRequest) (*corepb.Empty, error) {
+	// All return checking is done at the call site
+	return sac.inner.ClearFeatureOverride(ctx, req)
+}
+
+func (sac StorageAuthorityClientWrapper) GetHostnamePolicy(ctx context.Context, req *sapb.GetHostnamePolicyRequest) (*sapb.HostnamePolicy, error) {
+	resp, err := sac.inner.GetHostnamePolicy(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if resp == nil || resp.Version == 0 {
+		return nil, errIncompleteResponse
+	}
+	return resp, nil
+}
+
+func (sac StorageAuthorityClientWrapper) AddHostnamePolicy(ctx context.Context, req *sapb.HostnamePolicy) (*sapb.HostnamePolicy, error) {
+	resp, err := sac.inner.AddHostnamePolicy(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if resp == nil || resp.Version == 0 {
+		return nil, errIncompleteResponse
+	}
+	return resp, nil
+}
+
+func (sac StorageAuthorityClientWrapper) GetAccountAllowlist(ctx context.Context, req *sapb.RegistrationID) (*sapb.AccountAllowlist, error) {
+	resp, err := sac.inner.GetAccountAllowlist(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if resp == nil {
+		return nil, errIncompleteResponse
+	}
+	return resp, nil
+}
+
+func (sac StorageAuthorityClientWrapper) AddAccountAllowlistEntry(ctx context.Context, req *sapb.AccountAllowlistEntry) (*corepb.Empty, error) {
+	// All return checking is done at the call site
+	return sac.inner.AddAccountAllowlistEntry(ctx, req)
+}
+
+func (sac StorageAuthorityClientWrapper) RemoveAccountAllowlistEntry(ctx context.Context, req *sapb.AccountAllowlistEntry) (*corepb.Empty, error) {
+	// All return checking is done at the call site
+	return sac.inner.RemoveAccountAllowlistEntry(ctx, req)
+}
+
+func (sac StorageAuthorityClientWrapper) ClearAccountAllowlist(ctx context.Context, req *sapb.RegistrationID) (*corepb.Empty, error) {
+	// All return checking is done at the call site
+	return sac.inner.ClearAccountAllowlist(ctx, req)
+}
+
+func (sac StorageAuthorityClientWrapper) GetIssuanceCounts(ctx context.Context, req *sapb.IssuanceCountsRequest) (*sapb.IssuanceCounts, error) {
+	resp, err := sac.inner.GetIssuanceCounts(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if resp == nil {
+		return nil, errIncompleteResponse
+	}
+	return resp, nil
+}
+
+func (sac StorageAuthorityClientWrapper) AddCertificateMetadata(ctx context.Context, req *sapb.CertificateMetadata) (*corepb.Empty, error) {
+	// All return checking is done at the call site
+	return sac.inner.AddCertificateMetadata(ctx, req)
+}
+
+func (sac StorageAuthorityClientWrapper) GetAccountEvents(ctx context.Context, req *sapb.GetAccountEventsRequest) (*sapb.AccountEvents, error) {
+	resp, err := sac.inner.GetAccountEvents(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if resp == nil {
+		return nil, errIncompleteResponse
+	}
+	return resp, nil
+}
+
+func (sac StorageAuthorityClientWrapper) GetKeyRollovers(ctx context.Context, req *sapb.GetKeyRolloversRequest) (*sapb.KeyRollovers, error) {
+	resp, err := sac.inner.GetKeyRollovers(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if resp == nil {
+		return nil, errIncompleteResponse
+	}
+	return resp, nil
+}
+
+func (sac StorageAuthorityClientWrapper) AddAccountEvent(ctx context.Context, req *sapb.AccountEvent) (*corepb.Empty, error) {
+	// All return checking is done at the call site
+	return sac.inner.AddAccountEvent(ctx, req)
+}
+
+func (sac StorageAuthorityClientWrapper) GetIssuanceLogEntry(ctx context.Context, req *sapb.GetIssuanceLogEntryRequest) (*sapb.IssuanceLogEntry, error) {
+	resp, err := sac.inner.GetIssuanceLogEntry(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if resp == nil {
+		return nil, errIncompleteResponse
+	}
+	return resp, nil
+}
+
+func (sac StorageAuthorityClientWrapper) AddIssuanceLogEntry(ctx context.Context, req *sapb.IssuanceLogEntry) (*corepb.Empty, error) {
+	// All return checking is done at the call site
+	return sac.inner.AddIssuanceLogEntry(ctx, req)
+}
+
 // StorageAuthorityServerWrapper is the gRPC version of a core.ServerAuthority server
 type StorageAuthorityServerWrapper struct {
 	// TODO(#3119): Don't use core.StorageAuthority
@@ -564,6 +820,39 @@ func (sas StorageAuthorityServerWrapper) GetCertificateStatus(ctx context.Contex
 	return CertStatusToPB(certStatus), nil
 }
 
+func (sas StorageAuthorityServerWrapper) GetCertificateStatuses(ctx context.Context, req *sapb.Serials) (*sapb.CertificateStatuses, error) {
+	if req == nil || len(req.Serials) == 0 {
+		return nil, errIncompleteRequest
+	}
+	return sas.inner.GetCertificateStatuses(ctx, req)
+}
+
+func (sas StorageAuthorityServerWrapper) GetMaxCertificateStatusID(ctx context.Context, req *corepb.Empty) (*sapb.CertificateStatusID, error) {
+	if req == nil {
+		return nil, errIncompleteRequest
+	}
+	return sas.inner.GetMaxCertificateStatusID(ctx, req)
+}
+
+// certificateStatusStreamer is implemented by SAs which can stream
+// certificateStatus rows directly to a gRPC stream. Streams have no
+// equivalent in core.StorageAuthority, since its methods are also implemented
+// by StorageAuthorityClientWrapper.
+type certificateStatusStreamer interface {
+	GetCertificateStatusesByID(req *sapb.CertificateStatusesByIDRequest, stream sapb.StorageAuthority_GetCertificateStatusesByIDServer) error
+}
+
+func (sas StorageAuthorityServerWrapper) GetCertificateStatusesByID(req *sapb.CertificateStatusesByIDRequest, stream sapb.StorageAuthority_GetCertificateStatusesByIDServer) error {
+	if req == nil || req.MaxID <= req.AfterID {
+		return errIncompleteRequest
+	}
+	streamer, ok := sas.inner.(certificateStatusStreamer)
+	if !ok {
+		return errors.New("SA doesn't support streaming certificate statuses")
+	}
+	return streamer.GetCertificateStatusesByID(req, stream)
+}
+
 func (sas StorageAuthorityServerWrapper) CountCertificatesByNames(ctx context.Context, request *sapb.CountCertificatesByNamesRequest) (*sapb.CountByNames, error) {
 	if core.IsAnyNilOrZero(request, request.Range, request.Range.Earliest, request.Range.Latest, request.Names) {
 		return nil, errIncompleteRequest
@@ -666,6 +955,30 @@ func (sac StorageAuthorityServerWrapper) PreviousCertificateExists(
 	return sac.inner.PreviousCertificateExists(ctx, req)
 }
 
+func (sac StorageAuthorityServerWrapper) ReplacementCertificateExists(
+	ctx context.Context,
+	req *sapb.ReplacementCertificateExistsRequest,
+) (*sapb.Exists, error) {
+	if core.IsAnyNilOrZero(req, req.Serial, req.RegID, req.DnsNames, req.IssuedAfter) {
+		return nil, errIncompleteRequest
+	}
+	return sac.inner.ReplacementCertificateExists(ctx, req)
+}
+
+func (sas StorageAuthorityServerWrapper) GetSerialsByKey(ctx context.Context, req *sapb.SPKIHash) (*sapb.Serials, error) {
+	if req == nil || len(req.KeyHash) == 0 {
+		return nil, errIncompleteRequest
+	}
+	return sas.inner.GetSerialsByKey(ctx, req)
+}
+
+func (sas StorageAuthorityServerWrapper) GetSerialsByAccount(ctx context.Context, req *sapb.RegistrationID) (*sapb.Serials, error) {
+	if req == nil || req.Id == 0 {
+		return nil, errIncompleteRequest
+	}
+	return sas.inner.GetSerialsByAccount(ctx, req)
+}
+
 func (sas StorageAuthorityServerWrapper) NewRegistration(ctx context.Context, request *corepb.Registration) (*corepb.Registration, error) {
 	if request == nil || !newRegistrationValid(request) {
 		return nil, errIncompleteRequest
@@ -737,6 +1050,14 @@ func (sas StorageAuthorityServerWrapper) NewOrder(ctx context.Context, request *
 	return sas.inner.NewOrder(ctx, request)
 }
 
+func (sas StorageAuthorityServerWrapper) NewOrderAndAuthzs(ctx context.Context, req *sapb.NewOrderAndAuthzsRequest) (*corepb.Order, error) {
+	if req == nil || req.NewOrder == nil || !newOrderValid(req.NewOrder) {
+		return nil, errIncompleteRequest
+	}
+
+	return sas.inner.NewOrderAndAuthzs(ctx, req)
+}
+
 func (sas StorageAuthorityServerWrapper) SetOrderProcessing(ctx context.Context, order *corepb.Order) (*corepb.Empty, error) {
 	if order == nil || !orderValid(order) {
 		return nil, errIncompleteRequest
@@ -886,3 +1207,113 @@ func (sas StorageAuthorityServerWrapper) KeyBlocked(ctx context.Context, req *sa
 	// All request checking is done in the method
 	return sas.inner.KeyBlocked(ctx, req)
 }
+
+func (sas StorageAuthorityServerWrapper) AddIncident(ctx context.Context, req *sapb.AddIncidentRequest) (*sapb.Incident, error) {
+	// All request checking is done in the method
+	return sas.inner.AddIncident(ctx, req)
+}
+
+func (sas StorageAuthorityServerWrapper) AddIncidentSerials(ctx context.Context, req *sapb.AddIncidentSerialsRequest) (*corepb.Empty, error) {
+	// All request checking is done in the method
+	return sas.inner.AddIncidentSerials(ctx, req)
+}
+
+func (sas StorageAuthorityServerWrapper) SetIncidentStatus(ctx context.Context, req *sapb.SetIncidentStatusRequest) (*corepb.Empty, error) {
+	// All request checking is done in the method
+	return sas.inner.SetIncidentStatus(ctx, req)
+}
+
+func (sas StorageAuthorityServerWrapper) IncidentsForSerial(ctx context.Context, req *sapb.Serial) (*sapb.Incidents, error) {
+	// All request checking is done in the method
+	return sas.inner.IncidentsForSerial(ctx, req)
+}
+
+func (sas StorageAuthorityServerWrapper) GetNotificationPreferences(ctx context.Context, req *sapb.RegistrationID) (*sapb.NotificationPreferences, error) {
+	// All request checking is done in the method
+	return sas.inner.GetNotificationPreferences(ctx, req)
+}
+
+func (sas StorageAuthorityServerWrapper) SetNotificationPreferences(ctx context.Context, req *sapb.NotificationPreferences) (*corepb.Empty, error) {
+	// All request checking is done in the method
+	return sas.inner.SetNotificationPreferences(ctx, req)
+}
+
+func (sas StorageAuthorityServerWrapper) GetFeatureOverrides(ctx context.Context, req *sapb.RegistrationID) (*sapb.FeatureOverrides, error) {
+	// All request checking is done in the method
+	return sas.inner.GetFeatureOverrides(ctx, req)
+}
+
+func (sas StorageAuthorityServerWrapper) SetFeatureOverride(ctx context.Context, req *sapb.FeatureOverrideRequest) (*corepb.Empty, error) {
+	// All request checking is done in the method
+	return sas.inner.SetFeatureOverride(ctx, req)
+}
+
+func (sas StorageAuthorityServerWrapper) ClearFeatureOverride(ctx context.Context, req *sapb.FeatureOverrideRequest) (*corepb.Empty, error) {
+	// All request checking is done in the method
+	return sas.inner.ClearFeatureOverride(ctx, req)
+}
+
+func (sas StorageAuthorityServerWrapper) GetHostnamePolicy(ctx context.Context, req *sapb.GetHostnamePolicyRequest) (*sapb.HostnamePolicy, error) {
+	// All request checking is done in the method
+	return sas.inner.GetHostnamePolicy(ctx, req)
+}
+
+func (sas StorageAuthorityServerWrapper) AddHostnamePolicy(ctx context.Context, req *sapb.HostnamePolicy) (*sapb.HostnamePolicy, error) {
+	// All request checking is done in the method
+	return sas.inner.AddHostnamePolicy(ctx, req)
+}
+
+func (sas StorageAuthorityServerWrapper) GetAccountAllowlist(ctx context.Context, req *sapb.RegistrationID) (*sapb.AccountAllowlist, error) {
+	// All request checking is done in the method
+	return sas.inner.GetAccountAllowlist(ctx, req)
+}
+
+func (sas StorageAuthorityServerWrapper) AddAccountAllowlistEntry(ctx context.Context, req *sapb.AccountAllowlistEntry) (*corepb.Empty, error) {
+	// All request checking is done in the method
+	return sas.inner.AddAccountAllowlistEntry(ctx, req)
+}
+
+func (sas StorageAuthorityServerWrapper) RemoveAccountAllowlistEntry(ctx context.Context, req *sapb.AccountAllowlistEntry) (*corepb.Empty, error) {
+	// All request checking is done in the method
+	return sas.inner.RemoveAccountAllowlistEntry(ctx, req)
+}
+
+func (sas StorageAuthorityServerWrapper) ClearAccountAllowlist(ctx context.Context, req *sapb.RegistrationID) (*corepb.Empty, error) {
+	// All request checking is done in the method
+	return sas.inner.ClearAccountAllowlist(ctx, req)
+}
+
+func (sas StorageAuthorityServerWrapper) GetIssuanceCounts(ctx context.Context, req *sapb.IssuanceCountsRequest) (*sapb.IssuanceCounts, error) {
+	// All request checking is done in the method
+	return sas.inner.GetIssuanceCounts(ctx, req)
+}
+
+func (sas StorageAuthorityServerWrapper) AddCertificateMetadata(ctx context.Context, req *sapb.CertificateMetadata) (*corepb.Empty, error) {
+	// All request checking is done in the method
+	return sas.inner.AddCertificateMetadata(ctx, req)
+}
+
+func (sas StorageAuthorityServerWrapper) GetAccountEvents(ctx context.Context, req *sapb.GetAccountEventsRequest) (*sapb.AccountEvents, error) {
+	// All request checking is done in the method
+	return sas.inner.GetAccountEvents(ctx, req)
+}
+
+func (sas StorageAuthorityServerWrapper) GetKeyRollovers(ctx context.Context, req *sapb.GetKeyRolloversRequest) (*sapb.KeyRollovers, error) {
+	// All request checking is done in the method
+	return sas.inner.GetKeyRollovers(ctx, req)
+}
+
+func (sas StorageAuthorityServerWrapper) AddAccountEvent(ctx context.Context, req *sapb.AccountEvent) (*corepb.Empty, error) {
+	// All request checking is done in the method
+	return sas.inner.AddAccountEvent(ctx, req)
+}
+
+func (sas StorageAuthorityServerWrapper) GetIssuanceLogEntry(ctx context.Context, req *sapb.GetIssuanceLogEntryRequest) (*sapb.IssuanceLogEntry, error) {
+	// All request checking is done in the method
+	return sas.inner.GetIssuanceLogEntry(ctx, req)
+}
+
+func (sas StorageAuthorityServerWrapper) AddIssuanceLogEntry(ctx context.Context, req *sapb.IssuanceLogEntry) (*corepb.Empty, error) {
+	// All request checking is done in the method
+	return sas.inner.AddIssuanceLogEntry(ctx, req)
+}
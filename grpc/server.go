@@ -22,30 +22,48 @@ var errNilTLS = errors.New("boulder/grpc: received nil tls.Config")
 // verifies that clients present a certificate that (a) is signed by one of
 // the configured ClientCAs, and (b) contains at least one
 // subjectAlternativeName matching the accepted list from GRPCServerConfig.
+//
+// If the configured address is a Unix domain socket, TLS isn't used and
+// tlsConfig may be nil: clients are instead authorized by the socket's
+// filesystem permissions.
 func NewServer(c *cmd.GRPCServerConfig, tlsConfig *tls.Config, metrics serverMetrics, clk clock.Clock) (*grpc.Server, net.Listener, error) {
-	if tlsConfig == nil {
-		return nil, nil, errNilTLS
-	}
-	acceptedSANs := make(map[string]struct{})
-	for _, name := range c.ClientNames {
-		acceptedSANs[name] = struct{}{}
-	}
-
-	creds, err := bcreds.NewServerCredentials(tlsConfig, acceptedSANs)
+	network, address, err := cmd.ParseGRPCAddress(c.Address)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	l, err := net.Listen("tcp", c.Address)
+	si := newServerInterceptor(metrics, clk)
+	opts := []grpc.ServerOption{
+		grpc.UnaryInterceptor(si.intercept),
+		grpc.StreamInterceptor(si.interceptStream),
+	}
+
+	var l net.Listener
+	switch network {
+	case "unix":
+		l, err = listenUnix(address)
+	default:
+		if tlsConfig == nil {
+			return nil, nil, errNilTLS
+		}
+		acceptedSANs := make(map[string]struct{})
+		for _, name := range c.ClientNames {
+			acceptedSANs[name] = struct{}{}
+		}
+
+		creds, err := bcreds.NewServerCredentials(tlsConfig, acceptedSANs)
+		if err != nil {
+			return nil, nil, err
+		}
+		opts = append(opts, grpc.Creds(creds))
+
+		l, err = net.Listen("tcp", address)
+	}
 	if err != nil {
 		return nil, nil, err
 	}
 
-	si := newServerInterceptor(metrics, clk)
-	return grpc.NewServer(
-		grpc.Creds(creds),
-		grpc.UnaryInterceptor(si.intercept),
-	), l, nil
+	return grpc.NewServer(opts...), l, nil
 }
 
 // serverMetrics is a struct type used to return a few registered metrics from
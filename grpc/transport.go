@@ -0,0 +1,53 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"syscall"
+)
+
+// unixSocketMode is the mode of the Unix domain sockets servers listen on.
+// Only the server's user and group can connect, so clients are authorized by
+// being run as, or in, them.
+const unixSocketMode = 0660
+
+// umaskMu serializes the changes listenUnix makes to the process's umask.
+var umaskMu sync.Mutex
+
+// listenUnix listens on a Unix domain socket at path. A socket left behind by
+// a previous server is removed first, but any other file at path is an error.
+func listenUnix(path string) (net.Listener, error) {
+	fi, err := os.Lstat(path)
+	if err == nil {
+		if fi.Mode()&os.ModeSocket == 0 {
+			return nil, fmt.Errorf("%q exists and isn't a socket", path)
+		}
+		err = os.Remove(path)
+		if err != nil {
+			return nil, err
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+	// The socket is created with unixSocketMode by setting the umask while
+	// listening, rather than by changing its mode afterwards, so that there's
+	// no window in which anyone else can connect to it.
+	umaskMu.Lock()
+	oldMask := syscall.Umask(0777 &^ unixSocketMode)
+	l, err := net.Listen("unix", path)
+	syscall.Umask(oldMask)
+	umaskMu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// dialUnix is a grpc.WithContextDialer dialer for Unix domain sockets.
+func dialUnix(ctx context.Context, path string) (net.Conn, error) {
+	var d net.Dialer
+	return d.DialContext(ctx, "unix", path)
+}
@@ -0,0 +1,73 @@
+package grpc
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jmhodges/clock"
+
+	"github.com/letsencrypt/boulder/cmd"
+	"github.com/letsencrypt/boulder/grpc/test_proto"
+	"github.com/letsencrypt/boulder/metrics"
+	"github.com/letsencrypt/boulder/test"
+)
+
+// chillOver starts a Chiller server on address, without TLS, and checks that a
+// client can call it.
+func chillOver(t *testing.T, address string) {
+	s, l, err := NewServer(&cmd.GRPCServerConfig{Address: address}, nil, NewServerMetrics(metrics.NoopRegisterer), clock.NewFake())
+	test.AssertNotError(t, err, "NewServer failed")
+	test_proto.RegisterChillerServer(s, &testServer{})
+	go func() { _ = s.Serve(l) }()
+	defer s.Stop()
+
+	conn, err := ClientSetup(&cmd.GRPCClientConfig{
+		ServerAddress: address,
+		Timeout:       cmd.ConfigDuration{Duration: 10 * time.Second},
+	}, nil, NewClientMetrics(metrics.NoopRegisterer), clock.NewFake())
+	test.AssertNotError(t, err, "ClientSetup failed")
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	_, err = test_proto.NewChillerClient(conn).Chill(ctx, &test_proto.Time{Time: time.Millisecond.Nanoseconds()})
+	test.AssertNotError(t, err, "Chill failed")
+}
+
+func TestUnixTransport(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bgrpc")
+	test.AssertNotError(t, err, "creating temp dir")
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "chiller.sock")
+
+	chillOver(t, "unix://"+path)
+
+	// The socket is only accessible to the server's user and group, and is
+	// replaced if a previous server left it behind.
+	l, err := listenUnix(path)
+	test.AssertNotError(t, err, "listenUnix failed")
+	fi, err := os.Stat(path)
+	test.AssertNotError(t, err, "socket not created")
+	test.AssertEquals(t, fi.Mode().Perm(), os.FileMode(unixSocketMode))
+	l.(*net.UnixListener).SetUnlinkOnClose(false)
+	l.Close()
+	chillOver(t, "unix://"+path)
+
+	// But other files aren't.
+	notSocket := filepath.Join(dir, "file")
+	test.AssertNotError(t, ioutil.WriteFile(notSocket, nil, 0600), "writing file")
+	_, err = listenUnix(notSocket)
+	test.AssertError(t, err, "listened over a regular file")
+}
+
+func TestTCPTransportRequiresTLS(t *testing.T) {
+	_, _, err := NewServer(&cmd.GRPCServerConfig{Address: "localhost:0"}, nil, NewServerMetrics(metrics.NoopRegisterer), clock.NewFake())
+	test.AssertEquals(t, err, errNilTLS)
+	_, err = ClientSetup(&cmd.GRPCClientConfig{ServerAddress: "localhost:1"}, nil, NewClientMetrics(metrics.NoopRegisterer), clock.NewFake())
+	test.AssertEquals(t, err, errNilTLS)
+}
@@ -1,6 +1,8 @@
 // The identifier package defines types for RFC 8555 ACME identifiers.
 package identifier
 
+import "strings"
+
 // IdentifierType is a named string type for registered ACME identifier types.
 // See https://tools.ietf.org/html/rfc8555#section-9.7.7
 type IdentifierType string
@@ -8,12 +10,16 @@ type IdentifierType string
 const (
 	// DNS is specified in RFC 8555 for DNS type identifiers.
 	DNS = IdentifierType("dns")
+	// Email is the "email" identifier type of RFC 8823, used for S/MIME
+	// issuance.
+	Email = IdentifierType("email")
 )
 
 // ACMEIdentifier is a struct encoding an identifier that can be validated. The
 // protocol allows for different types of identifier to be supported (DNS
 // names, IP addresses, etc.), but currently we only support RFC 8555 DNS type
-// identifiers for domain names.
+// identifiers for domain names and, when the EmailIdentifiers feature is
+// enabled, RFC 8823 email type identifiers for email addresses.
 type ACMEIdentifier struct {
 	// Type is the registered IdentifierType of the identifier.
 	Type IdentifierType `json:"type"`
@@ -30,3 +36,23 @@ func DNSIdentifier(domain string) ACMEIdentifier {
 		Value: domain,
 	}
 }
+
+// EmailIdentifier is a convenience function for creating an ACMEIdentifier
+// with Type Email for a given email address.
+func EmailIdentifier(address string) ACMEIdentifier {
+	return ACMEIdentifier{
+		Type:  Email,
+		Value: address,
+	}
+}
+
+// FromValue returns the ACMEIdentifier for an identifier value stored without
+// its type, as the names of orders and authorizations are. Values containing
+// an "@", which can't appear in a domain name, are email addresses and
+// everything else is a domain name.
+func FromValue(value string) ACMEIdentifier {
+	if strings.Contains(value, "@") {
+		return EmailIdentifier(value)
+	}
+	return DNSIdentifier(value)
+}
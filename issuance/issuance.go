@@ -39,6 +39,12 @@ type ProfileConfig struct {
 	AllowCTPoison   bool
 	AllowSCTList    bool
 	AllowCommonName bool
+	// AllowEmailAddresses makes this an S/MIME profile, which issues only
+	// S/MIME certificates. They have rfc822Name SANs and the emailProtection
+	// EKU instead of dNSName SANs and the serverAuth and clientAuth EKUs, and
+	// aren't logged to CT, so it can't be combined with AllowCTPoison or
+	// AllowSCTList.
+	AllowEmailAddresses bool
 
 	Policies            []PolicyInformation
 	MaxValidityPeriod   cmd.ConfigDuration
@@ -165,6 +171,7 @@ type Profile struct {
 	allowCTPoison   bool
 	allowSCTList    bool
 	allowCommonName bool
+	allowEmail      bool
 
 	sigAlg    x509.SignatureAlgorithm
 	ocspURL   string
@@ -204,6 +211,9 @@ func NewProfile(profileConfig ProfileConfig, issuerConfig IssuerConfig) (*Profil
 	if issuerConfig.OCSPURL == "" {
 		return nil, errors.New("OCSP URL is required")
 	}
+	if profileConfig.AllowEmailAddresses && (profileConfig.AllowCTPoison || profileConfig.AllowSCTList) {
+		return nil, errors.New("S/MIME profiles can't allow CT extensions")
+	}
 	sp := &Profile{
 		useForRSALeaves:   issuerConfig.UseForRSALeaves,
 		useForECDSALeaves: issuerConfig.UseForECDSALeaves,
@@ -211,6 +221,7 @@ func NewProfile(profileConfig ProfileConfig, issuerConfig IssuerConfig) (*Profil
 		allowCTPoison:     profileConfig.AllowCTPoison,
 		allowSCTList:      profileConfig.AllowSCTList,
 		allowCommonName:   profileConfig.AllowCommonName,
+		allowEmail:        profileConfig.AllowEmailAddresses,
 		issuerURL:         issuerConfig.IssuerURL,
 		crlURL:            issuerConfig.CRLURL,
 		ocspURL:           issuerConfig.OCSPURL,
@@ -286,6 +297,17 @@ func (p *Profile) requestValid(clk clock.Clock, req *IssuanceRequest) error {
 		return errors.New("common name cannot be included")
 	}
 
+	if len(req.EmailAddresses) > 0 {
+		if !p.allowEmail {
+			return errors.New("email addresses cannot be included")
+		}
+		if len(req.DNSNames) > 0 {
+			return errors.New("cannot include both DNS names and email addresses")
+		}
+	} else if p.allowEmail {
+		return errors.New("email addresses must be included")
+	}
+
 	validity := req.NotAfter.Sub(req.NotBefore)
 	if validity <= 0 {
 		return errors.New("NotAfter must be after NotBefore")
@@ -313,6 +335,11 @@ var defaultEKU = []x509.ExtKeyUsage{
 	x509.ExtKeyUsageClientAuth,
 }
 
+// emailEKU replaces defaultEKU in S/MIME certificates.
+var emailEKU = []x509.ExtKeyUsage{
+	x509.ExtKeyUsageEmailProtection,
+}
+
 func (p *Profile) generateTemplate(clk clock.Clock) *x509.Certificate {
 	template := &x509.Certificate{
 		SignatureAlgorithm:    p.sigAlg,
@@ -445,6 +472,12 @@ func (i *Issuer) Algs() []x509.PublicKeyAlgorithm {
 	return algs
 }
 
+// SMIME returns whether the issuer issues S/MIME certificates, rather than
+// TLS certificates.
+func (i *Issuer) SMIME() bool {
+	return i.Profile.allowEmail
+}
+
 // Name provides the Common Name specified in the issuer's certificate.
 func (i *Issuer) Name() string {
 	return i.Cert.Subject.CommonName
@@ -524,8 +557,9 @@ type IssuanceRequest struct {
 	NotBefore time.Time
 	NotAfter  time.Time
 
-	CommonName string
-	DNSNames   []string
+	CommonName     string
+	DNSNames       []string
+	EmailAddresses []string
 
 	IncludeMustStaple bool
 	IncludeCTPoison   bool
@@ -553,6 +587,10 @@ func (i *Issuer) Issue(req *IssuanceRequest) ([]byte, error) {
 		template.Subject.CommonName = req.CommonName
 	}
 	template.DNSNames = req.DNSNames
+	if len(req.EmailAddresses) > 0 {
+		template.EmailAddresses = req.EmailAddresses
+		template.ExtKeyUsage = emailEKU
+	}
 	template.AuthorityKeyId = i.Cert.SubjectKeyId
 	skid, err := generateSKID(req.PublicKey)
 	if err != nil {
@@ -622,6 +660,7 @@ func RequestFromPrecert(precert *x509.Certificate, scts []ct.SignedCertificateTi
 		NotAfter:          precert.NotAfter,
 		CommonName:        precert.Subject.CommonName,
 		DNSNames:          precert.DNSNames,
+		EmailAddresses:    precert.EmailAddresses,
 		IncludeMustStaple: ContainsMustStaple(precert.Extensions),
 		SCTList:           scts,
 	}, nil
@@ -135,6 +135,14 @@ func TestNewProfileNoOCSPURL(t *testing.T) {
 	test.AssertEquals(t, err.Error(), "OCSP URL is required")
 }
 
+func TestNewProfileSMIMEWithCT(t *testing.T) {
+	pc := defaultProfileConfig()
+	pc.AllowEmailAddresses = true
+	_, err := NewProfile(pc, defaultIssuerConfig())
+	test.AssertError(t, err, "NewProfile didn't fail with an S/MIME profile allowing CT extensions")
+	test.AssertEquals(t, err.Error(), "S/MIME profiles can't allow CT extensions")
+}
+
 func TestNewProfileInvalidOID(t *testing.T) {
 	_, err := NewProfile(ProfileConfig{
 		Policies: []PolicyInformation{{
@@ -244,6 +252,42 @@ func TestRequestValid(t *testing.T) {
 			},
 			expectedError: "common name cannot be included",
 		},
+		{
+			name: "email addresses not allowed",
+			profile: &Profile{
+				useForECDSALeaves: true,
+			},
+			request: &IssuanceRequest{
+				PublicKey:      &ecdsa.PublicKey{},
+				EmailAddresses: []string{"user@example.com"},
+			},
+			expectedError: "email addresses cannot be included",
+		},
+		{
+			name: "dns names and email addresses not allowed",
+			profile: &Profile{
+				useForECDSALeaves: true,
+				allowEmail:        true,
+			},
+			request: &IssuanceRequest{
+				PublicKey:      &ecdsa.PublicKey{},
+				DNSNames:       []string{"example.com"},
+				EmailAddresses: []string{"user@example.com"},
+			},
+			expectedError: "cannot include both DNS names and email addresses",
+		},
+		{
+			name: "dns names from S/MIME profile not allowed",
+			profile: &Profile{
+				useForECDSALeaves: true,
+				allowEmail:        true,
+			},
+			request: &IssuanceRequest{
+				PublicKey: &ecdsa.PublicKey{},
+				DNSNames:  []string{"example.com"},
+			},
+			expectedError: "email addresses must be included",
+		},
 		{
 			name: "negative validity",
 			profile: &Profile{
@@ -551,6 +595,38 @@ func TestIssue(t *testing.T) {
 	}
 }
 
+func TestIssueEmail(t *testing.T) {
+	fc := clock.NewFake()
+	fc.Set(time.Now())
+	linter, _ := lint.NewLinter(
+		issuerSigner,
+		[]string{"w_ct_sct_policy_count_unsatisfied"},
+	)
+	pc := defaultProfileConfig()
+	pc.AllowEmailAddresses = true
+	pc.AllowCTPoison = false
+	pc.AllowSCTList = false
+	profile, err := NewProfile(pc, defaultIssuerConfig())
+	test.AssertNotError(t, err, "NewProfile failed")
+	signer, err := NewIssuer(issuerCert, issuerSigner, profile, linter, fc)
+	test.AssertNotError(t, err, "NewIssuer failed")
+	pk, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	test.AssertNotError(t, err, "failed to generate test key")
+	certBytes, err := signer.Issue(&IssuanceRequest{
+		PublicKey:      pk.Public(),
+		Serial:         []byte{1, 2, 3, 4, 5, 6, 7, 8},
+		EmailAddresses: []string{"user@example.com"},
+		NotBefore:      fc.Now(),
+		NotAfter:       fc.Now().Add(time.Hour),
+	})
+	test.AssertNotError(t, err, "Issue failed")
+	cert, err := x509.ParseCertificate(certBytes)
+	test.AssertNotError(t, err, "failed to parse certificate")
+	test.AssertDeepEquals(t, cert.EmailAddresses, []string{"user@example.com"})
+	test.AssertEquals(t, len(cert.DNSNames), 0)
+	test.AssertDeepEquals(t, cert.ExtKeyUsage, []x509.ExtKeyUsage{x509.ExtKeyUsageEmailProtection})
+}
+
 func TestIssueRSA(t *testing.T) {
 	fc := clock.NewFake()
 	fc.Set(time.Now())
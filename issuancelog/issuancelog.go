@@ -0,0 +1,328 @@
+// Package issuancelog provides a tamper-evident, hash-chained log of the
+// certificates a CA signs. Each entry records what's about to be signed and
+// includes the hash of the entry before it, so that altering, removing or
+// reordering any entry breaks the chain from that point on. The log is a file
+// of JSON entries, one per line, which can optionally also be copied to the
+// SA's issuanceLog table.
+package issuancelog
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jmhodges/clock"
+
+	"github.com/letsencrypt/boulder/core"
+	corepb "github.com/letsencrypt/boulder/core/proto"
+	blog "github.com/letsencrypt/boulder/log"
+	sapb "github.com/letsencrypt/boulder/sa/proto"
+)
+
+const (
+	// TypePrecertificate entries record the signing of a precertificate.
+	TypePrecertificate = "precertificate"
+	// TypeCertificate entries record the signing of a final certificate for a
+	// precertificate.
+	TypeCertificate = "certificate"
+)
+
+// Entry is a single entry in an issuance log. Hashes are hex encoded.
+type Entry struct {
+	ChainID string `json:"chainID"`
+	Seq     int64  `json:"seq"`
+	Type    string `json:"type"`
+	Serial  string `json:"serial"`
+	Profile string `json:"profile,omitempty"`
+	// NamesHash is the hash of the certificate's names, computed by
+	// NamesHash.
+	NamesHash string `json:"namesHash"`
+	// CSRHash is the SHA-256 hash of the DER CSR a precertificate was
+	// requested with. Final certificates have none.
+	CSRHash  string    `json:"csrHash,omitempty"`
+	Issued   time.Time `json:"issued"`
+	PrevHash string    `json:"prevHash"`
+	Hash     string    `json:"hash"`
+}
+
+// genesisHash is the PrevHash of the first entry in a chain.
+var genesisHash = strings.Repeat("0", 2*sha256.Size)
+
+// NamesHash returns the hex SHA-256 hash of a certificate's names, as lowercase,
+// de-duplicated, sorted and comma separated, like the SA's FQDN set hashes.
+func NamesHash(names []string) string {
+	hash := sha256.Sum256([]byte(strings.Join(core.UniqueLowerNames(names), ",")))
+	return hex.EncodeToString(hash[:])
+}
+
+// computeHash returns the hex hash of the entry, covering every other field
+// and the hash of the entry before it.
+func (e Entry) computeHash() string {
+	h := sha256.New()
+	for _, field := range []string{e.PrevHash, e.ChainID, e.Type, e.Serial, e.Profile, e.NamesHash, e.CSRHash} {
+		// Prefix each field with its length, so that no two different
+		// entries hash the same bytes.
+		_ = binary.Write(h, binary.BigEndian, uint32(len(field)))
+		_, _ = h.Write([]byte(field))
+	}
+	_ = binary.Write(h, binary.BigEndian, e.Seq)
+	_ = binary.Write(h, binary.BigEndian, e.Issued.UnixNano())
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Storage is where entries are copied to, besides the log file. It's
+// implemented by the SA.
+type Storage interface {
+	AddIssuanceLogEntry(ctx context.Context, req *sapb.IssuanceLogEntry) (*corepb.Empty, error)
+}
+
+// Log appends entries to an issuance log file.
+type Log struct {
+	chainID string
+	clk     clock.Clock
+	storage Storage
+	log     blog.Logger
+
+	mu   sync.Mutex
+	file *os.File
+	last Entry
+	// size is the size of the file up to the end of the last entry.
+	size int64
+	// broken is set if an entry may have been left partly written, after
+	// which nothing more can be appended.
+	broken error
+}
+
+// maxEntrySize bounds the size of a single encoded entry, which is used to
+// find the last entry of an existing log.
+const maxEntrySize = 64 * 1024
+
+// Open opens the issuance log at path for appending, creating it if it
+// doesn't exist. Each CA instance must have its own log, whose chainID
+// identifies it in the SA. If storage isn't nil, entries are also copied to
+// it.
+func Open(path string, chainID string, storage Storage, clk clock.Clock, logger blog.Logger) (*Log, error) {
+	if chainID == "" {
+		return nil, errors.New("issuance log chain ID must not be empty")
+	}
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_APPEND|os.O_CREATE, 0640)
+	if err != nil {
+		return nil, err
+	}
+	last, size, err := lastEntry(file)
+	if err != nil {
+		_ = file.Close()
+		return nil, fmt.Errorf("reading last entry of issuance log %q: %s", path, err)
+	}
+	if last.Seq != 0 && last.ChainID != chainID {
+		_ = file.Close()
+		return nil, fmt.Errorf("issuance log %q belongs to chain %q, not %q", path, last.ChainID, chainID)
+	}
+	return &Log{
+		chainID: chainID,
+		clk:     clk,
+		storage: storage,
+		log:     logger,
+		file:    file,
+		last:    last,
+		size:    size,
+	}, nil
+}
+
+// lastEntry returns the last entry in the log file, or an entry with the
+// genesis hash if it's empty, and the file's size.
+func lastEntry(file *os.File) (Entry, int64, error) {
+	info, err := file.Stat()
+	if err != nil {
+		return Entry{}, 0, err
+	}
+	if info.Size() == 0 {
+		return Entry{Hash: genesisHash}, 0, nil
+	}
+	offset := info.Size() - maxEntrySize
+	if offset < 0 {
+		offset = 0
+	}
+	tail := make([]byte, info.Size()-offset)
+	_, err = file.ReadAt(tail, offset)
+	if err != nil {
+		return Entry{}, 0, err
+	}
+	tail = bytes.TrimRight(tail, "\n")
+	line := tail[bytes.LastIndexByte(tail, '\n')+1:]
+	var last Entry
+	err = json.Unmarshal(line, &last)
+	if err != nil {
+		return Entry{}, 0, err
+	}
+	if last.computeHash() != last.Hash {
+		return Entry{}, 0, fmt.Errorf("entry %d doesn't match its hash", last.Seq)
+	}
+	return last, info.Size(), nil
+}
+
+// Append adds an entry for a certificate that's about to be signed, which
+// mustn't be signed if Append fails. The entry's chain ID, sequence number,
+// time and hashes are filled in. The entry is copied to storage, if any, on a
+// best effort basis, since the log file is the record of issuance, and the
+// verifier reports entries missing from storage.
+func (l *Log) Append(ctx context.Context, e Entry) (Entry, error) {
+	e, err := l.write(e)
+	if err != nil {
+		return Entry{}, err
+	}
+
+	// The copy is made without holding the lock, so that a slow SA doesn't
+	// hold up other appends.
+	if l.storage != nil {
+		pb, err := EntryToPB(e)
+		if err == nil {
+			_, err = l.storage.AddIssuanceLogEntry(ctx, pb)
+		}
+		if err != nil {
+			l.log.Errf("Failed to copy issuance log entry %d of chain %q to storage: %s", e.Seq, e.ChainID, err)
+		}
+	}
+	return e, nil
+}
+
+// write fills in the entry and writes it to the log file. If the entry can't
+// be written, the file is truncated back to the end of the last entry, so
+// that the next entry doesn't follow a partial one. If that fails too, or the
+// file can't be synced, after which what's on disk is unknown, the log is
+// broken and every later write fails.
+func (l *Log) write(e Entry) (Entry, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.broken != nil {
+		return Entry{}, fmt.Errorf("issuance log is broken: %s", l.broken)
+	}
+	e.ChainID = l.chainID
+	e.Seq = l.last.Seq + 1
+	e.Issued = l.clk.Now().UTC()
+	e.PrevHash = l.last.Hash
+	e.Hash = e.computeHash()
+	line, err := json.Marshal(e)
+	if err != nil {
+		return Entry{}, err
+	}
+	line = append(line, '\n')
+	_, err = l.file.Write(line)
+	if err != nil {
+		truncErr := l.file.Truncate(l.size)
+		if truncErr != nil {
+			l.broken = fmt.Errorf("writing entry %d: %s, then truncating: %s", e.Seq, err, truncErr)
+			l.log.AuditErrf("Issuance log broken: %s", l.broken)
+		}
+		return Entry{}, err
+	}
+	err = l.file.Sync()
+	if err != nil {
+		l.broken = fmt.Errorf("syncing entry %d: %s", e.Seq, err)
+		l.log.AuditErrf("Issuance log broken: %s", l.broken)
+		return Entry{}, err
+	}
+	l.last = e
+	l.size += int64(len(line))
+	return e, nil
+}
+
+// Close closes the log file.
+func (l *Log) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.file.Close()
+}
+
+// Verify reads a log, checking that each entry matches its hash and chains
+// from the one before it, and passes each entry to visit. It stops at the
+// first broken link, or error returned by visit, and returns the last valid
+// entry, whose hash attests to the whole log up to it.
+func Verify(r io.Reader, visit func(Entry) error) (Entry, error) {
+	last := Entry{Hash: genesisHash}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, maxEntrySize), maxEntrySize)
+	for scanner.Scan() {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var e Entry
+		err := json.Unmarshal(scanner.Bytes(), &e)
+		if err != nil {
+			return last, fmt.Errorf("entry after %d: %s", last.Seq, err)
+		}
+		if e.Seq != last.Seq+1 {
+			return last, fmt.Errorf("entry after %d has sequence number %d", last.Seq, e.Seq)
+		}
+		if last.Seq != 0 && e.ChainID != last.ChainID {
+			return last, fmt.Errorf("entry %d belongs to chain %q, not %q", e.Seq, e.ChainID, last.ChainID)
+		}
+		if e.PrevHash != last.Hash {
+			return last, fmt.Errorf("entry %d doesn't chain from entry %d", e.Seq, last.Seq)
+		}
+		if e.computeHash() != e.Hash {
+			return last, fmt.Errorf("entry %d doesn't match its hash", e.Seq)
+		}
+		err = visit(e)
+		if err != nil {
+			return last, err
+		}
+		last = e
+	}
+	return last, scanner.Err()
+}
+
+// EntryToPB converts an entry to its protobuf form.
+func EntryToPB(e Entry) (*sapb.IssuanceLogEntry, error) {
+	pb := &sapb.IssuanceLogEntry{
+		ChainID:                e.ChainID,
+		Seq:                    e.Seq,
+		Type:                   e.Type,
+		Serial:                 e.Serial,
+		CertificateProfileName: e.Profile,
+		Issued:                 e.Issued.UnixNano(),
+	}
+	var err error
+	for _, h := range []struct {
+		hex string
+		out *[]byte
+	}{
+		{e.NamesHash, &pb.NamesHash},
+		{e.CSRHash, &pb.CsrHash},
+		{e.PrevHash, &pb.PrevHash},
+		{e.Hash, &pb.Hash},
+	} {
+		*h.out, err = hex.DecodeString(h.hex)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return pb, nil
+}
+
+// PBToEntry converts an entry from its protobuf form.
+func PBToEntry(pb *sapb.IssuanceLogEntry) Entry {
+	return Entry{
+		ChainID:   pb.ChainID,
+		Seq:       pb.Seq,
+		Type:      pb.Type,
+		Serial:    pb.Serial,
+		Profile:   pb.CertificateProfileName,
+		NamesHash: hex.EncodeToString(pb.NamesHash),
+		CSRHash:   hex.EncodeToString(pb.CsrHash),
+		Issued:    time.Unix(0, pb.Issued).UTC(),
+		PrevHash:  hex.EncodeToString(pb.PrevHash),
+		Hash:      hex.EncodeToString(pb.Hash),
+	}
+}
@@ -0,0 +1,174 @@
+package issuancelog
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jmhodges/clock"
+
+	corepb "github.com/letsencrypt/boulder/core/proto"
+	blog "github.com/letsencrypt/boulder/log"
+	sapb "github.com/letsencrypt/boulder/sa/proto"
+	"github.com/letsencrypt/boulder/test"
+)
+
+type fakeStorage struct {
+	entries []*sapb.IssuanceLogEntry
+	err     error
+}
+
+func (s *fakeStorage) AddIssuanceLogEntry(_ context.Context, req *sapb.IssuanceLogEntry) (*corepb.Empty, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	s.entries = append(s.entries, req)
+	return &corepb.Empty{}, nil
+}
+
+func TestNamesHash(t *testing.T) {
+	test.AssertEquals(t, NamesHash([]string{"b.com", "A.com", "a.com"}), NamesHash([]string{"a.com", "b.com"}))
+	test.AssertNotEquals(t, NamesHash([]string{"a.com"}), NamesHash([]string{"a.com", "b.com"}))
+}
+
+func TestAppendAndVerify(t *testing.T) {
+	dir, err := ioutil.TempDir("", "issuancelog")
+	test.AssertNotError(t, err, "creating temp dir")
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "log.jsonl")
+	clk := clock.NewFake()
+	clk.Set(time.Date(2021, 2, 14, 12, 0, 0, 0, time.UTC))
+	storage := &fakeStorage{}
+	ctx := context.Background()
+
+	l, err := Open(path, "ca-a", storage, clk, blog.NewMock())
+	test.AssertNotError(t, err, "opening new log")
+	first, err := l.Append(ctx, Entry{Type: TypePrecertificate, Serial: "01", NamesHash: NamesHash([]string{"a.com"}), CSRHash: genesisHash})
+	test.AssertNotError(t, err, "appending entry")
+	test.AssertEquals(t, first.Seq, int64(1))
+	test.AssertEquals(t, first.ChainID, "ca-a")
+	test.AssertEquals(t, first.PrevHash, genesisHash)
+	test.AssertEquals(t, first.Issued, clk.Now())
+	test.AssertNotError(t, l.Close(), "closing log")
+
+	// Reopening the log continues the chain.
+	l, err = Open(path, "ca-a", storage, clk, blog.NewMock())
+	test.AssertNotError(t, err, "reopening log")
+	clk.Add(time.Minute)
+	second, err := l.Append(ctx, Entry{Type: TypeCertificate, Serial: "01", NamesHash: NamesHash([]string{"a.com"})})
+	test.AssertNotError(t, err, "appending entry")
+	test.AssertEquals(t, second.Seq, int64(2))
+	test.AssertEquals(t, second.PrevHash, first.Hash)
+	test.AssertNotError(t, l.Close(), "closing log")
+
+	_, err = Open(path, "ca-b", storage, clk, blog.NewMock())
+	test.AssertError(t, err, "opened another chain's log")
+
+	test.AssertEquals(t, len(storage.entries), 2)
+	test.AssertDeepEquals(t, PBToEntry(storage.entries[1]), second)
+
+	contents, err := ioutil.ReadFile(path)
+	test.AssertNotError(t, err, "reading log")
+	var visited []Entry
+	head, err := Verify(bytes.NewReader(contents), func(e Entry) error {
+		visited = append(visited, e)
+		return nil
+	})
+	test.AssertNotError(t, err, "verifying log")
+	test.AssertDeepEquals(t, head, second)
+	test.AssertDeepEquals(t, visited, []Entry{first, second})
+
+	// Altering an entry breaks the chain at that entry.
+	tampered := strings.Replace(string(contents), `"serial":"01"`, `"serial":"02"`, 1)
+	head, err = Verify(strings.NewReader(tampered), func(Entry) error { return nil })
+	test.AssertError(t, err, "verified tampered log")
+	test.AssertEquals(t, head.Seq, int64(0))
+
+	// So does removing one.
+	lines := strings.SplitAfter(string(contents), "\n")
+	_, err = Verify(strings.NewReader(lines[1]), func(Entry) error { return nil })
+	test.AssertError(t, err, "verified log missing its first entry")
+
+	// Errors from visit stop verification.
+	_, err = Verify(bytes.NewReader(contents), func(Entry) error { return errors.New("oops") })
+	test.AssertError(t, err, "visit error ignored")
+}
+
+func TestAppendStorageFailure(t *testing.T) {
+	dir, err := ioutil.TempDir("", "issuancelog")
+	test.AssertNotError(t, err, "creating temp dir")
+	defer os.RemoveAll(dir)
+	log := blog.NewMock()
+
+	l, err := Open(filepath.Join(dir, "log.jsonl"), "ca-a", &fakeStorage{err: errors.New("SA down")}, clock.NewFake(), log)
+	test.AssertNotError(t, err, "opening new log")
+	defer l.Close()
+	_, err = l.Append(context.Background(), Entry{Type: TypePrecertificate, Serial: "01", NamesHash: NamesHash([]string{"a.com"})})
+	test.AssertNotError(t, err, "append failed when storage did")
+	test.AssertEquals(t, len(log.GetAllMatching("Failed to copy issuance log entry 1")), 1)
+}
+
+// lockCheckingStorage records whether the log was locked when an entry was
+// copied to it.
+type lockCheckingStorage struct {
+	l      *Log
+	locked bool
+}
+
+func (s *lockCheckingStorage) AddIssuanceLogEntry(_ context.Context, _ *sapb.IssuanceLogEntry) (*corepb.Empty, error) {
+	if s.l.mu.TryLock() {
+		s.l.mu.Unlock()
+	} else {
+		s.locked = true
+	}
+	return &corepb.Empty{}, nil
+}
+
+func TestAppendStorageUnlocked(t *testing.T) {
+	dir, err := ioutil.TempDir("", "issuancelog")
+	test.AssertNotError(t, err, "creating temp dir")
+	defer os.RemoveAll(dir)
+
+	storage := &lockCheckingStorage{}
+	l, err := Open(filepath.Join(dir, "log.jsonl"), "ca-a", storage, clock.NewFake(), blog.NewMock())
+	test.AssertNotError(t, err, "opening new log")
+	defer l.Close()
+	storage.l = l
+	_, err = l.Append(context.Background(), Entry{Type: TypePrecertificate, Serial: "01", NamesHash: NamesHash([]string{"a.com"})})
+	test.AssertNotError(t, err, "appending entry")
+	test.Assert(t, !storage.locked, "entry was copied to storage while the log was locked")
+}
+
+func TestAppendBroken(t *testing.T) {
+	dir, err := ioutil.TempDir("", "issuancelog")
+	test.AssertNotError(t, err, "creating temp dir")
+	defer os.RemoveAll(dir)
+	log := blog.NewMock()
+
+	path := filepath.Join(dir, "log.jsonl")
+	l, err := Open(path, "ca-a", nil, clock.NewFake(), log)
+	test.AssertNotError(t, err, "opening new log")
+	_, err = l.Append(context.Background(), Entry{Type: TypePrecertificate, Serial: "01", NamesHash: NamesHash([]string{"a.com"})})
+	test.AssertNotError(t, err, "appending first entry")
+
+	// With the file closed, neither the write nor the truncation can succeed,
+	// so the log is broken and refuses later entries.
+	test.AssertNotError(t, l.file.Close(), "closing log file")
+	_, err = l.Append(context.Background(), Entry{Type: TypePrecertificate, Serial: "02", NamesHash: NamesHash([]string{"a.com"})})
+	test.AssertError(t, err, "append succeeded with the file closed")
+	test.AssertEquals(t, len(log.GetAllMatching("Issuance log broken")), 1)
+	_, err = l.Append(context.Background(), Entry{Type: TypePrecertificate, Serial: "03", NamesHash: NamesHash([]string{"a.com"})})
+	test.AssertError(t, err, "append succeeded on a broken log")
+	test.AssertContains(t, err.Error(), "issuance log is broken")
+
+	reopened, err := Open(path, "ca-a", nil, clock.NewFake(), log)
+	test.AssertNotError(t, err, "reopening log")
+	defer reopened.Close()
+	test.AssertEquals(t, reopened.last.Seq, int64(1))
+}
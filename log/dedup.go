@@ -0,0 +1,221 @@
+package log
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/syslog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jmhodges/clock"
+)
+
+// maxDedupEntries bounds the number of distinct messages a deduplicating
+// Logger tracks at once. Messages beyond it are logged without deduplication.
+const maxDedupEntries = 10000
+
+// dedupEntry tracks a message logged within the current window.
+type dedupEntry struct {
+	level   syslog.Priority
+	msg     string
+	start   time.Time
+	repeats int
+	// emit logs a message with the same level and object as the original.
+	emit func(msg string)
+}
+
+// deduplicator is a Logger which collapses repeated identical messages.
+type deduplicator struct {
+	// Logger is the Logger being wrapped. The Err and audit methods, which
+	// are never deduplicated, are passed straight to it.
+	Logger
+	windows map[syslog.Priority]time.Duration
+	clk     clock.Clock
+
+	mu      sync.Mutex
+	entries map[string]*dedupEntry
+
+	// stop is closed by StopDeduplicating to stop the goroutine which
+	// summarizes messages once their window is over.
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewDeduplicating returns a Logger which logs to inner, except that a message
+// logged at a level with a window in windows is logged only the first time
+// it's seen in that window. If it's repeated in the window, one more line
+// with the repeat count is logged once the window is over. Windows are keyed
+// by the level names "warning", "info" and "debug". Messages logged at the
+// err level are always audit logged, so like those logged by the other audit
+// methods they're never deduplicated.
+func NewDeduplicating(inner Logger, windows map[string]time.Duration, clk clock.Clock) (Logger, error) {
+	d := &deduplicator{
+		Logger:  inner,
+		windows: make(map[syslog.Priority]time.Duration),
+		clk:     clk,
+		entries: make(map[string]*dedupEntry),
+		stop:    make(chan struct{}),
+	}
+	var shortest time.Duration
+	for name, window := range windows {
+		level, ok := levelByName(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown log level %q", name)
+		}
+		if level <= syslog.LOG_ERR {
+			return nil, fmt.Errorf("%s messages are audit logged, so can't be deduplicated", name)
+		}
+		if window <= 0 {
+			return nil, fmt.Errorf("deduplication window for %s must be positive", name)
+		}
+		d.windows[level] = window
+		if shortest == 0 || window < shortest {
+			shortest = window
+		}
+	}
+	if shortest > 0 {
+		ticker := time.NewTicker(shortest)
+		go func() {
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ticker.C:
+					d.flush(false)
+				case <-d.stop:
+					return
+				}
+			}
+		}()
+	}
+	return d, nil
+}
+
+// StopDeduplicating stops a Logger returned by NewDeduplicating from
+// summarizing repeated messages in the background, and logs the summaries of
+// those whose windows aren't over yet, so that they aren't lost when the
+// process exits. It does nothing to any other Logger.
+func StopDeduplicating(logger Logger) {
+	d, ok := logger.(*deduplicator)
+	if !ok {
+		return
+	}
+	d.stopOnce.Do(func() { close(d.stop) })
+	d.flush(true)
+}
+
+// levelByName returns the priority of a level, by its lowercase name.
+func levelByName(name string) (syslog.Priority, bool) {
+	for level, levelName := range levelName {
+		if strings.ToLower(levelName) == name {
+			return level, true
+		}
+	}
+	return 0, false
+}
+
+// log logs msg with emit, unless the same message, identified by key, has
+// already been logged at the same level in the current window.
+func (d *deduplicator) log(level syslog.Priority, key string, msg string, emit func(string)) {
+	window, ok := d.windows[level]
+	if !ok {
+		emit(msg)
+		return
+	}
+	key = fmt.Sprintf("%d\x00%s", level, key)
+	now := d.clk.Now()
+
+	d.mu.Lock()
+	entry, found := d.entries[key]
+	if found && now.Before(entry.start.Add(window)) {
+		entry.repeats++
+		d.mu.Unlock()
+		return
+	}
+	if found {
+		delete(d.entries, key)
+	}
+	if len(d.entries) < maxDedupEntries {
+		d.entries[key] = &dedupEntry{level: level, msg: msg, start: now, emit: emit}
+	}
+	d.mu.Unlock()
+
+	if found {
+		d.summarize(entry)
+	}
+	emit(msg)
+}
+
+// summarize logs the number of times an entry's message was repeated in its
+// window, if it was.
+func (d *deduplicator) summarize(entry *dedupEntry) {
+	if entry.repeats == 0 {
+		return
+	}
+	entry.emit(fmt.Sprintf("%s (repeated %d more times in %s)", entry.msg, entry.repeats, d.windows[entry.level]))
+}
+
+// flush summarizes and forgets every entry whose window is over, or if all is
+// true, every entry.
+func (d *deduplicator) flush(all bool) {
+	now := d.clk.Now()
+	var expired []*dedupEntry
+	d.mu.Lock()
+	for key, entry := range d.entries {
+		if all || !now.Before(entry.start.Add(d.windows[entry.level])) {
+			expired = append(expired, entry)
+			delete(d.entries, key)
+		}
+	}
+	d.mu.Unlock()
+
+	for _, entry := range expired {
+		d.summarize(entry)
+	}
+}
+
+// logObject deduplicates a message logged with an object, identified by the
+// message and the object's JSON serialization.
+func (d *deduplicator) logObject(level syslog.Priority, msg string, obj interface{}, emit func(string, interface{})) {
+	key := msg
+	if jsonObj, err := json.Marshal(obj); err == nil {
+		key = fmt.Sprintf("%s\x00%s", msg, jsonObj)
+	}
+	d.log(level, key, msg, func(msg string) { emit(msg, obj) })
+}
+
+func (d *deduplicator) Warning(msg string) {
+	d.log(syslog.LOG_WARNING, msg, msg, d.Logger.Warning)
+}
+
+func (d *deduplicator) Warningf(format string, a ...interface{}) {
+	d.Warning(fmt.Sprintf(format, a...))
+}
+
+func (d *deduplicator) Info(msg string) {
+	d.log(syslog.LOG_INFO, msg, msg, d.Logger.Info)
+}
+
+func (d *deduplicator) Infof(format string, a ...interface{}) {
+	d.Info(fmt.Sprintf(format, a...))
+}
+
+func (d *deduplicator) Debug(msg string) {
+	d.log(syslog.LOG_DEBUG, msg, msg, d.Logger.Debug)
+}
+
+func (d *deduplicator) Debugf(format string, a ...interface{}) {
+	d.Debug(fmt.Sprintf(format, a...))
+}
+
+func (d *deduplicator) WarningObject(msg string, obj interface{}) {
+	d.logObject(syslog.LOG_WARNING, msg, obj, d.Logger.WarningObject)
+}
+
+func (d *deduplicator) InfoObject(msg string, obj interface{}) {
+	d.logObject(syslog.LOG_INFO, msg, obj, d.Logger.InfoObject)
+}
+
+func (d *deduplicator) DebugObject(msg string, obj interface{}) {
+	d.logObject(syslog.LOG_DEBUG, msg, obj, d.Logger.DebugObject)
+}
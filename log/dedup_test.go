@@ -0,0 +1,114 @@
+package log
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jmhodges/clock"
+	"github.com/letsencrypt/boulder/test"
+)
+
+func TestDeduplicating(t *testing.T) {
+	_, err := NewDeduplicating(NewMock(), map[string]time.Duration{"notice": time.Minute}, clock.NewFake())
+	test.AssertError(t, err, "NewDeduplicating accepted an unknown level")
+	_, err = NewDeduplicating(NewMock(), map[string]time.Duration{"warning": 0}, clock.NewFake())
+	test.AssertError(t, err, "NewDeduplicating accepted a zero window")
+	_, err = NewDeduplicating(NewMock(), map[string]time.Duration{"err": time.Minute}, clock.NewFake())
+	test.AssertError(t, err, "NewDeduplicating accepted a window for audit logged err messages")
+
+	mock := NewMock()
+	clk := clock.NewFake()
+	logger, err := NewDeduplicating(mock, map[string]time.Duration{
+		"warning": time.Minute,
+		"info":    time.Hour,
+	}, clk)
+	test.AssertNotError(t, err, "NewDeduplicating failed")
+	d := logger.(*deduplicator)
+
+	// Err messages are audit logged, so they're never deduplicated.
+	for i := 0; i < 3; i++ {
+		logger.Warningf("connection to %s refused", "sa")
+		logger.Errf("revocation of %d failed", 1)
+		logger.AuditInfo("revoked")
+	}
+	logger.Warning("something else")
+	test.AssertDeepEquals(t, mock.GetAll(), []string{
+		"WARNING: connection to sa refused",
+		"ERR: [AUDIT] revocation of 1 failed",
+		"INFO: [AUDIT] revoked",
+		"ERR: [AUDIT] revocation of 1 failed",
+		"INFO: [AUDIT] revoked",
+		"ERR: [AUDIT] revocation of 1 failed",
+		"INFO: [AUDIT] revoked",
+		"WARNING: something else",
+	})
+
+	// Once the window is over the repeat count is logged, and the message
+	// is logged again the next time it's seen.
+	mock.Clear()
+	clk.Add(time.Minute)
+	d.flush(false)
+	logger.Warning("connection to sa refused")
+	test.AssertDeepEquals(t, mock.GetAll(), []string{
+		"WARNING: connection to sa refused (repeated 2 more times in 1m0s)",
+		"WARNING: connection to sa refused",
+	})
+
+	// A message seen again after its window, before a flush, is summarized
+	// then logged.
+	mock.Clear()
+	logger.Warning("connection to sa refused")
+	clk.Add(time.Minute)
+	logger.Warning("connection to sa refused")
+	test.AssertDeepEquals(t, mock.GetAll(), []string{
+		"WARNING: connection to sa refused (repeated 1 more times in 1m0s)",
+		"WARNING: connection to sa refused",
+	})
+
+	// Objects are part of what makes messages identical.
+	mock.Clear()
+	logger.InfoObject("order created", map[string]int{"id": 1})
+	logger.InfoObject("order created", map[string]int{"id": 1})
+	logger.InfoObject("order created", map[string]int{"id": 2})
+	logger.AuditObject("order created", map[string]int{"id": 2})
+	logger.AuditObject("order created", map[string]int{"id": 2})
+	clk.Add(time.Hour)
+	d.flush(false)
+	test.AssertDeepEquals(t, mock.GetAll(), []string{
+		`INFO: order created JSON={"id":1}`,
+		`INFO: order created JSON={"id":2}`,
+		`INFO: [AUDIT] order created JSON={"id":2}`,
+		`INFO: [AUDIT] order created JSON={"id":2}`,
+		`INFO: order created (repeated 1 more times in 1h0m0s) JSON={"id":1}`,
+	})
+}
+
+func TestStopDeduplicating(t *testing.T) {
+	mock := NewMock()
+	logger, err := NewDeduplicating(mock, map[string]time.Duration{"warning": time.Minute}, clock.NewFake())
+	test.AssertNotError(t, err, "NewDeduplicating failed")
+	logger.Warning("connection to sa refused")
+	logger.Warning("connection to sa refused")
+
+	// Repeats are summarized on stopping, though their window isn't over.
+	StopDeduplicating(logger)
+	StopDeduplicating(logger)
+	test.AssertDeepEquals(t, mock.GetAll(), []string{
+		"WARNING: connection to sa refused",
+		"WARNING: connection to sa refused (repeated 1 more times in 1m0s)",
+	})
+
+	// Other Loggers are left alone.
+	StopDeduplicating(mock)
+}
+
+func TestDeduplicatingAuditPanic(t *testing.T) {
+	mock := NewMock()
+	logger, err := NewDeduplicating(mock, map[string]time.Duration{"warning": time.Minute}, clock.NewFake())
+	test.AssertNotError(t, err, "NewDeduplicating failed")
+	func() {
+		defer logger.AuditPanic()
+		panic("oh no")
+	}()
+	test.AssertEquals(t, len(mock.GetAllMatching(`Panic caused by err: oh no`)), 1)
+}
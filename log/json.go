@@ -0,0 +1,143 @@
+package log
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/syslog"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/jmhodges/clock"
+)
+
+// JSONLine is the object logged, on a line of its own, for each message by a
+// Logger returned by NewJSON.
+type JSONLine struct {
+	Time      string `json:"time"`
+	Level     string `json:"level"`
+	Component string `json:"component"`
+	// Checksum is JSONLineChecksum of Message and Fields.
+	Checksum string `json:"checksum"`
+	// Caller is the directory, file and line of the code outside the log
+	// package which logged the message, like "ra/ra.go:123".
+	Caller string `json:"caller,omitempty"`
+	// Audit is true for messages which are marked with the audit tag by a
+	// text Logger. The tag itself is removed from Message.
+	Audit   bool   `json:"audit,omitempty"`
+	Message string `json:"msg"`
+	// Fields holds the JSON-serialized object logged with the message by
+	// methods like InfoObject, if any.
+	Fields json.RawMessage `json:"fields,omitempty"`
+}
+
+// JSONLineChecksum returns the checksum of a message and its fields, which
+// may be empty, for use in a JSONLine.
+func JSONLineChecksum(msg string, fields json.RawMessage) string {
+	if len(fields) == 0 {
+		return LogLineChecksum(msg)
+	}
+	return LogLineChecksum(msg + " " + string(fields))
+}
+
+// NewJSON returns a new Logger that uses the given syslog.Writer as a backend,
+// like New, but which logs each message as a JSONLine rather than as text.
+// The component is the name of the service doing the logging.
+func NewJSON(log *syslog.Writer, stdoutLogLevel int, syslogLogLevel int, component string) (Logger, error) {
+	if log == nil {
+		return nil, errors.New("Attempted to use a nil System Logger.")
+	}
+	return &impl{
+		&jsonWriter{log, stdoutLogLevel, syslogLogLevel, component, clock.New(), os.Stdout},
+	}, nil
+}
+
+// jsonWriter implements writer and fieldWriter, writing JSONLines to both
+// syslog and stdout.
+type jsonWriter struct {
+	*syslog.Writer
+	stdoutLevel int
+	syslogLevel int
+	component   string
+	clk         clock.Clock
+	stdout      io.Writer
+}
+
+func (w *jsonWriter) logAtLevel(level syslog.Priority, msg string) {
+	w.logFieldsAtLevel(level, msg, nil)
+}
+
+func (w *jsonWriter) logFieldsAtLevel(level syslog.Priority, msg string, fields json.RawMessage) {
+	line := JSONLine{
+		Time:      w.clk.Now().UTC().Format(time.RFC3339Nano),
+		Level:     strings.ToLower(levelName[level]),
+		Component: w.component,
+		Caller:    caller(),
+		Message:   redact(msg),
+	}
+	if strings.HasPrefix(line.Message, auditTag) {
+		line.Audit = true
+		line.Message = strings.TrimPrefix(strings.TrimPrefix(line.Message, auditTag), " ")
+	}
+	if len(fields) > 0 {
+		line.Fields = json.RawMessage(redact(string(fields)))
+	}
+	line.Checksum = JSONLineChecksum(line.Message, line.Fields)
+
+	encoded, err := json.Marshal(line)
+	if err != nil {
+		// Fields is always valid JSON, having been produced by json.Marshal,
+		// and redaction only replaces the contents of strings.
+		encoded = []byte(fmt.Sprintf(`{"level":"err","component":%q,"msg":%q}`, w.component, "failed to encode log line: "+err.Error()))
+	}
+
+	if int(level) <= w.syslogLevel {
+		var err error
+		switch level {
+		case syslog.LOG_ERR:
+			err = w.Err(string(encoded))
+		case syslog.LOG_WARNING:
+			err = w.Warning(string(encoded))
+		case syslog.LOG_INFO:
+			err = w.Info(string(encoded))
+		case syslog.LOG_DEBUG:
+			err = w.Debug(string(encoded))
+		default:
+			err = w.Err(string(encoded))
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to write to syslog: %s (%s)\n", encoded, err)
+		}
+	}
+
+	if int(level) <= w.stdoutLevel {
+		if _, err := fmt.Fprintf(w.stdout, "%s\n", encoded); err != nil {
+			panic(fmt.Sprintf("failed to write to stdout: %v\n", err))
+		}
+	}
+}
+
+// logPackagePrefix is the prefix of the names of the log package's methods,
+// which caller skips.
+const logPackagePrefix = "github.com/letsencrypt/boulder/log.("
+
+// caller returns the directory, file and line of the first caller outside of
+// the log package's methods.
+func caller() string {
+	pcs := make([]uintptr, 16)
+	n := runtime.Callers(2, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, logPackagePrefix) {
+			return fmt.Sprintf("%s/%s:%d", filepath.Base(filepath.Dir(frame.File)), filepath.Base(frame.File), frame.Line)
+		}
+		if !more {
+			return ""
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/syslog"
+	"strings"
+	"testing"
+
+	"github.com/jmhodges/clock"
+	"github.com/letsencrypt/boulder/test"
+)
+
+func TestJSONLogger(t *testing.T) {
+	var buf bytes.Buffer
+	clk := clock.NewFake()
+	log := &impl{&jsonWriter{nil, int(syslog.LOG_INFO), 0, "boulder-ra", clk, &buf}}
+	lines := func() []JSONLine {
+		var result []JSONLine
+		for _, encoded := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+			var line JSONLine
+			err := json.Unmarshal([]byte(encoded), &line)
+			test.AssertNotError(t, err, "decoding logged line")
+			result = append(result, line)
+		}
+		buf.Reset()
+		return result
+	}
+
+	log.Infof("issued %d certificates", 2)
+	line := lines()[0]
+	test.AssertEquals(t, line.Time, clk.Now().UTC().Format("2006-01-02T15:04:05Z"))
+	test.AssertEquals(t, line.Level, "info")
+	test.AssertEquals(t, line.Component, "boulder-ra")
+	test.AssertEquals(t, line.Message, "issued 2 certificates")
+	test.AssertEquals(t, line.Checksum, LogLineChecksum("issued 2 certificates"))
+	test.Assert(t, strings.HasPrefix(line.Caller, "log/json_test.go:"), "wrong caller "+line.Caller)
+	test.Assert(t, !line.Audit, "unaudited line marked as audit")
+
+	// Audit-tagged messages are marked as such, without the tag.
+	log.AuditErr("revocation failed")
+	line = lines()[0]
+	test.AssertEquals(t, line.Level, "err")
+	test.AssertEquals(t, line.Message, "revocation failed")
+	test.Assert(t, line.Audit, "audit line not marked as audit")
+
+	// Objects are logged as fields, covered by the checksum.
+	AddRedactedSecrets("json_test", []string{"hunter2"})
+	defer SetRedactedSecrets("json_test", nil)
+	log.WarningObject("slow query", map[string]interface{}{"table": "orders", "password": "hunter2"})
+	line = lines()[0]
+	test.AssertEquals(t, line.Level, "warning")
+	test.AssertEquals(t, string(line.Fields), `{"password":"[REDACTED]","table":"orders"}`)
+	test.AssertEquals(t, line.Checksum, JSONLineChecksum("slow query", line.Fields))
+	log.AuditObject("Revoked", struct{ Serial string }{"abcd"})
+	line = lines()[0]
+	test.Assert(t, line.Audit, "audit object not marked as audit")
+	test.AssertEquals(t, line.Message, "Revoked")
+	test.AssertEquals(t, string(line.Fields), `{"Serial":"abcd"}`)
+
+	// Messages above the stdout level aren't written.
+	log.DebugObject("details", map[string]string{"a": "b"})
+	test.AssertEquals(t, buf.Len(), 0)
+}
+
+func TestObjectMethods(t *testing.T) {
+	log := NewMock()
+	obj := map[string]int{"n": 1}
+	log.ErrObject("err", obj)
+	log.WarningObject("warning", obj)
+	log.InfoObject("info", obj)
+	log.DebugObject("debug", obj)
+	test.AssertDeepEquals(t, log.GetAll(), []string{
+		`ERR: [AUDIT] err JSON={"n":1}`,
+		`WARNING: warning JSON={"n":1}`,
+		`INFO: info JSON={"n":1}`,
+		`DEBUG: debug JSON={"n":1}`,
+	})
+}
@@ -30,6 +30,10 @@ type Logger interface {
 	Infof(format string, a ...interface{})
 	Debug(msg string)
 	Debugf(format string, a ...interface{})
+	ErrObject(msg string, obj interface{})
+	WarningObject(msg string, obj interface{})
+	InfoObject(msg string, obj interface{})
+	DebugObject(msg string, obj interface{})
 	AuditPanic()
 	AuditInfo(msg string)
 	AuditInfof(format string, a ...interface{})
@@ -109,10 +113,62 @@ func Get() Logger {
 	return _Singleton.log
 }
 
+// redactedSecrets holds the secret values which are replaced with
+// "[REDACTED]" in every logged message, by the source they were read from.
+var redactedSecrets struct {
+	sync.RWMutex
+	values map[string][]string
+}
+
+// AddRedactedSecrets registers secrets, such as passwords read from a secret
+// store, which are then replaced with "[REDACTED]" in every message logged by
+// any Logger. They're added to any already registered for the same source,
+// such as a config file's name.
+func AddRedactedSecrets(source string, secrets []string) {
+	redactedSecrets.Lock()
+	defer redactedSecrets.Unlock()
+	if redactedSecrets.values == nil {
+		redactedSecrets.values = make(map[string][]string)
+	}
+	for _, secret := range secrets {
+		if secret != "" {
+			redactedSecrets.values[source] = append(redactedSecrets.values[source], secret)
+		}
+	}
+}
+
+// SetRedactedSecrets is like AddRedactedSecrets, but replaces any secrets
+// already registered for the source, for when it's been reloaded and the
+// secrets it previously held are no longer in use.
+func SetRedactedSecrets(source string, secrets []string) {
+	redactedSecrets.Lock()
+	delete(redactedSecrets.values, source)
+	redactedSecrets.Unlock()
+	AddRedactedSecrets(source, secrets)
+}
+
+// redact replaces each registered secret in msg with "[REDACTED]".
+func redact(msg string) string {
+	redactedSecrets.RLock()
+	defer redactedSecrets.RUnlock()
+	for _, secrets := range redactedSecrets.values {
+		for _, secret := range secrets {
+			msg = strings.Replace(msg, secret, "[REDACTED]", -1)
+		}
+	}
+	return msg
+}
+
 type writer interface {
 	logAtLevel(syslog.Priority, string)
 }
 
+// A fieldWriter is a writer which logs the fields of an object separately
+// from the message, rather than appending them to it.
+type fieldWriter interface {
+	logFieldsAtLevel(level syslog.Priority, msg string, fields json.RawMessage)
+}
+
 // bothWriter implements writer and writes to both syslog and stdout.
 type bothWriter struct {
 	*syslog.Writer
@@ -142,7 +198,7 @@ func (w *bothWriter) logAtLevel(level syslog.Priority, msg string) {
 
 	// Since messages are delimited by newlines, we have to escape any internal or
 	// trailing newlines before generating the checksum or outputting the message.
-	msg = strings.Replace(msg, "\n", "\\n", -1)
+	msg = strings.Replace(redact(msg), "\n", "\\n", -1)
 	msg = fmt.Sprintf("%s %s", LogLineChecksum(msg), msg)
 
 	switch syslogAllowed := int(level) <= w.syslogLevel; level {
@@ -196,6 +252,22 @@ func (log *impl) auditAtLevel(level syslog.Priority, msg string) {
 	log.w.logAtLevel(level, text)
 }
 
+// logObject logs msg along with the fields of obj, serialized as JSON. If the
+// writer doesn't log fields separately they're appended to the message.
+func (log *impl) logObject(level syslog.Priority, msg string, obj interface{}) {
+	jsonObj, err := json.Marshal(obj)
+	if err != nil {
+		log.auditAtLevel(syslog.LOG_ERR, fmt.Sprintf("Object could not be serialized to JSON. Raw: %+v", obj))
+		return
+	}
+
+	if fw, ok := log.w.(fieldWriter); ok {
+		fw.logFieldsAtLevel(level, msg, jsonObj)
+		return
+	}
+	log.w.logAtLevel(level, fmt.Sprintf("%s JSON=%s", msg, jsonObj))
+}
+
 // AuditPanic catches panicking executables. This method should be added
 // in a defer statement as early as possible
 func (log *impl) AuditPanic() {
@@ -253,6 +325,30 @@ func (log *impl) Debugf(format string, a ...interface{}) {
 	log.Debug(fmt.Sprintf(format, a...))
 }
 
+// ErrObject sends an ERR-severity message along with a JSON-serialized
+// object. Like Err, it's always marked with the audit tag.
+func (log *impl) ErrObject(msg string, obj interface{}) {
+	log.logObject(syslog.LOG_ERR, fmt.Sprintf("%s %s", auditTag, msg), obj)
+}
+
+// WarningObject sends a WARNING-severity message along with a JSON-serialized
+// object.
+func (log *impl) WarningObject(msg string, obj interface{}) {
+	log.logObject(syslog.LOG_WARNING, msg, obj)
+}
+
+// InfoObject sends an INFO-severity message along with a JSON-serialized
+// object.
+func (log *impl) InfoObject(msg string, obj interface{}) {
+	log.logObject(syslog.LOG_INFO, msg, obj)
+}
+
+// DebugObject sends a DEBUG-severity message along with a JSON-serialized
+// object.
+func (log *impl) DebugObject(msg string, obj interface{}) {
+	log.logObject(syslog.LOG_DEBUG, msg, obj)
+}
+
 // AuditInfo sends an INFO-severity message that is prefixed with the
 // audit tag, for special handling at the upstream system logger.
 func (log *impl) AuditInfo(msg string) {
@@ -268,13 +364,7 @@ func (log *impl) AuditInfof(format string, a ...interface{}) {
 // AuditObject sends an INFO-severity JSON-serialized object message that is prefixed
 // with the audit tag, for special handling at the upstream system logger.
 func (log *impl) AuditObject(msg string, obj interface{}) {
-	jsonObj, err := json.Marshal(obj)
-	if err != nil {
-		log.auditAtLevel(syslog.LOG_ERR, fmt.Sprintf("Object could not be serialized to JSON. Raw: %+v", obj))
-		return
-	}
-
-	log.auditAtLevel(syslog.LOG_INFO, fmt.Sprintf("%s JSON=%s", msg, jsonObj))
+	log.logObject(syslog.LOG_INFO, fmt.Sprintf("%s %s", auditTag, msg), obj)
 }
 
 // AuditErr can format an error for auditing; it does so at ERR level.
@@ -349,3 +349,22 @@ func TestLogAtLevelEscapesNewlines(t *testing.T) {
 
 	test.Assert(t, strings.Contains(buf.String(), "foo\\nbar"), "failed to escape newline")
 }
+
+func TestRedactSecrets(t *testing.T) {
+	defer SetRedactedSecrets("test", nil)
+	AddRedactedSecrets("test", []string{"", "hunter2"})
+	var buf bytes.Buffer
+	w := &bothWriter{nil, 6, 0, clock.New(), &buf}
+	w.logAtLevel(6, "connecting with password hunter2")
+	test.Assert(t, strings.Contains(buf.String(), "connecting with password [REDACTED]"), "failed to redact secret")
+
+	m := NewMock()
+	m.Infof("password: %s", "hunter2")
+	test.AssertEquals(t, m.GetAll()[0], "INFO: password: [REDACTED]")
+
+	// Setting a source's secrets replaces those previously registered.
+	SetRedactedSecrets("test", []string{"correcthorse"})
+	m.Clear()
+	m.Infof("passwords: %s %s", "hunter2", "correcthorse")
+	test.AssertEquals(t, m.GetAll()[0], "INFO: passwords: hunter2 [REDACTED]")
+}
@@ -43,7 +43,7 @@ var levelName = map[syslog.Priority]string{
 }
 
 func (w *mockWriter) logAtLevel(p syslog.Priority, msg string) {
-	w.msgChan <- fmt.Sprintf("%s: %s", levelName[p&7], msg)
+	w.msgChan <- fmt.Sprintf("%s: %s", levelName[p&7], redact(msg))
 }
 
 // newMockWriter returns a new mockWriter
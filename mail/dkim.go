@@ -0,0 +1,224 @@
+package mail
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jmhodges/clock"
+)
+
+// dkimSignedHeaders are the headers, as generated by generateMessage, which
+// are covered by DKIM signatures.
+var dkimSignedHeaders = []string{
+	"from",
+	"to",
+	"subject",
+	"date",
+	"message-id",
+	"mime-version",
+	"content-type",
+	"content-transfer-encoding",
+}
+
+// DKIMKey is a key which messages can be signed with, and the selector under
+// which its public key is published in DNS.
+type DKIMKey struct {
+	Selector string
+	// Signer is an RSA or Ed25519 private key.
+	Signer crypto.Signer
+	// NotBefore is when the key starts being used. Keys are rotated by
+	// publishing the new key under a new selector and adding it with a
+	// NotBefore far enough in the future for the record to propagate.
+	NotBefore time.Time
+}
+
+// DKIMSigner adds DKIM signatures (RFC 6376) to messages, using relaxed
+// canonicalization of the headers and body.
+type DKIMSigner struct {
+	domain string
+	// keys are sorted by NotBefore, most recent first.
+	keys []DKIMKey
+	clk  clock.Clock
+}
+
+// NewDKIMSigner returns a DKIMSigner which signs messages on behalf of domain
+// with the most recent of keys whose NotBefore has passed.
+func NewDKIMSigner(domain string, keys []DKIMKey, clk clock.Clock) (*DKIMSigner, error) {
+	if domain == "" {
+		return nil, errors.New("DKIM signing domain is required")
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("at least one DKIM key is required")
+	}
+	sorted := make([]DKIMKey, len(keys))
+	copy(sorted, keys)
+	for _, key := range sorted {
+		if key.Selector == "" {
+			return nil, errors.New("DKIM keys must have a selector")
+		}
+		switch key.Signer.(type) {
+		case *rsa.PrivateKey, ed25519.PrivateKey:
+		default:
+			return nil, fmt.Errorf("DKIM key %q is a %T, not an RSA or Ed25519 key", key.Selector, key.Signer)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].NotBefore.After(sorted[j].NotBefore)
+	})
+	return &DKIMSigner{domain: domain, keys: sorted, clk: clk}, nil
+}
+
+// LoadDKIMKey reads a PEM-encoded PKCS #8 or PKCS #1 private key for DKIM
+// signing from a file.
+func LoadDKIMKey(path string) (crypto.Signer, error) {
+	pemBytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(pemBytes)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM data in %q", path)
+	}
+	if block.Type == "RSA PRIVATE KEY" {
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("key in %q is a %T, which can't sign", path, key)
+	}
+	return signer, nil
+}
+
+// currentKey returns the key messages are currently signed with.
+func (s *DKIMSigner) currentKey() (DKIMKey, error) {
+	now := s.clk.Now()
+	for _, key := range s.keys {
+		if !key.NotBefore.After(now) {
+			return key, nil
+		}
+	}
+	return DKIMKey{}, errors.New("no DKIM key is valid yet")
+}
+
+// Sign returns message, which must use CRLF line endings, with a
+// DKIM-Signature header prepended.
+func (s *DKIMSigner) Sign(message []byte) ([]byte, error) {
+	key, err := s.currentKey()
+	if err != nil {
+		return nil, err
+	}
+
+	headerEnd := bytes.Index(message, []byte("\r\n\r\n"))
+	if headerEnd < 0 {
+		return nil, errors.New("message has no body")
+	}
+	headers := parseHeaders(string(message[:headerEnd+2]))
+	bodyHash := sha256.Sum256(relaxedBody(message[headerEnd+4:]))
+
+	algorithm := "rsa-sha256"
+	if _, ok := key.Signer.(ed25519.PrivateKey); ok {
+		algorithm = "ed25519-sha256"
+	}
+	sigHeader := fmt.Sprintf("DKIM-Signature: v=1; a=%s; c=relaxed/relaxed; d=%s; s=%s; t=%d; h=%s; bh=%s; b=",
+		algorithm, s.domain, key.Selector, s.clk.Now().Unix(),
+		strings.Join(dkimSignedHeaders, ":"), base64.StdEncoding.EncodeToString(bodyHash[:]))
+
+	// The signature covers the signed headers and then the DKIM-Signature
+	// header itself, with an empty signature and no trailing CRLF.
+	var signed strings.Builder
+	for _, name := range dkimSignedHeaders {
+		if value, ok := headers[name]; ok {
+			signed.WriteString(relaxedHeader(name, value))
+		}
+	}
+	signed.WriteString(strings.TrimSuffix(relaxedHeader("dkim-signature", strings.SplitN(sigHeader, ":", 2)[1]), "\r\n"))
+	digest := sha256.Sum256([]byte(signed.String()))
+
+	var sig []byte
+	if algorithm == "ed25519-sha256" {
+		// RFC 8463 signs the SHA-256 digest with PureEdDSA.
+		sig, err = key.Signer.Sign(rand.Reader, digest[:], crypto.Hash(0))
+	} else {
+		sig, err = key.Signer.Sign(rand.Reader, digest[:], crypto.SHA256)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("signing with DKIM key %q: %s", key.Selector, err)
+	}
+
+	var out bytes.Buffer
+	out.WriteString(sigHeader)
+	out.WriteString(base64.StdEncoding.EncodeToString(sig))
+	out.WriteString("\r\n")
+	out.Write(message)
+	return out.Bytes(), nil
+}
+
+// parseHeaders returns the values of a message's headers, keyed by their
+// lowercased names. Folded headers are kept folded. If a header appears more
+// than once its last value is returned, as that's the one DKIM signs first.
+func parseHeaders(header string) map[string]string {
+	headers := make(map[string]string)
+	var name string
+	for _, line := range strings.SplitAfter(header, "\r\n") {
+		if line == "" {
+			continue
+		}
+		if (line[0] == ' ' || line[0] == '\t') && name != "" {
+			headers[name] += line
+			continue
+		}
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 {
+			name = ""
+			continue
+		}
+		name = strings.ToLower(strings.TrimSpace(parts[0]))
+		headers[name] = parts[1]
+	}
+	return headers
+}
+
+// relaxedHeader canonicalizes a header per RFC 6376, section 3.4.2.
+func relaxedHeader(name, value string) string {
+	value = strings.Replace(value, "\r\n", "", -1)
+	return strings.ToLower(name) + ":" + strings.Join(strings.Fields(value), " ") + "\r\n"
+}
+
+// relaxedBody canonicalizes a body per RFC 6376, section 3.4.4.
+func relaxedBody(body []byte) []byte {
+	lines := strings.Split(string(body), "\r\n")
+	for i, line := range lines {
+		line = strings.TrimRight(line, " \t")
+		lines[i] = strings.Join(strings.FieldsFunc(line, func(r rune) bool {
+			return r == ' ' || r == '\t'
+		}), " ")
+		if strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") {
+			lines[i] = " " + lines[i]
+		}
+	}
+	// Empty lines at the end of the body are ignored.
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+	return []byte(strings.Join(lines, "\r\n") + "\r\n")
+}
@@ -0,0 +1,169 @@
+package mail
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"io/ioutil"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jmhodges/clock"
+
+	blog "github.com/letsencrypt/boulder/log"
+	"github.com/letsencrypt/boulder/metrics"
+	"github.com/letsencrypt/boulder/test"
+)
+
+func TestRelaxedCanonicalization(t *testing.T) {
+	// The example from RFC 6376, section 3.4.5.
+	test.AssertEquals(t, relaxedHeader("A", " X"), "a:X\r\n")
+	headers := parseHeaders("A: X\r\nB : Y\t\r\n\tZ  \r\n")
+	test.AssertEquals(t, relaxedHeader("B", headers["b"]), "b:Y Z\r\n")
+	test.AssertEquals(t, string(relaxedBody([]byte(" C \r\nD \t E\r\n\r\n\r\n"))), " C\r\nD E\r\n")
+	test.AssertEquals(t, len(relaxedBody([]byte("\r\n\r\n"))), 0)
+}
+
+// verifyDKIM checks a message's DKIM signature with pub, returning the
+// signature's tags and whether it's valid.
+func verifyDKIM(t *testing.T, signed []byte, pub crypto.PublicKey) (map[string]string, bool) {
+	t.Helper()
+	message := string(signed)
+	sigLineEnd := strings.Index(message, "\r\n")
+	sigHeader := message[:sigLineEnd]
+	test.Assert(t, strings.HasPrefix(sigHeader, "DKIM-Signature: "), "message doesn't start with a DKIM-Signature")
+	tags := make(map[string]string)
+	for _, tag := range strings.Split(strings.TrimPrefix(sigHeader, "DKIM-Signature: "), "; ") {
+		parts := strings.SplitN(tag, "=", 2)
+		tags[parts[0]] = parts[1]
+	}
+
+	rest := message[sigLineEnd+2:]
+	headerEnd := strings.Index(rest, "\r\n\r\n")
+	bodyHash := sha256.Sum256(relaxedBody([]byte(rest[headerEnd+4:])))
+	test.AssertEquals(t, tags["bh"], base64.StdEncoding.EncodeToString(bodyHash[:]))
+
+	headers := parseHeaders(rest[:headerEnd+2])
+	var data string
+	for _, name := range strings.Split(tags["h"], ":") {
+		data += relaxedHeader(name, headers[name])
+	}
+	unsigned := strings.TrimSuffix(sigHeader, tags["b"])
+	data += strings.TrimSuffix(relaxedHeader("DKIM-Signature", strings.SplitN(unsigned, ":", 2)[1]), "\r\n")
+	digest := sha256.Sum256([]byte(data))
+
+	sig, err := base64.StdEncoding.DecodeString(tags["b"])
+	test.AssertNotError(t, err, "decoding signature")
+	switch k := pub.(type) {
+	case *rsa.PublicKey:
+		test.AssertEquals(t, tags["a"], "rsa-sha256")
+		return tags, rsa.VerifyPKCS1v15(k, crypto.SHA256, digest[:], sig) == nil
+	case ed25519.PublicKey:
+		test.AssertEquals(t, tags["a"], "ed25519-sha256")
+		return tags, ed25519.Verify(k, digest[:], sig)
+	}
+	t.Fatalf("unexpected public key type %T", pub)
+	return nil, false
+}
+
+func TestDKIMSign(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	test.AssertNotError(t, err, "generating RSA key")
+	edPub, edKey, err := ed25519.GenerateKey(rand.Reader)
+	test.AssertNotError(t, err, "generating Ed25519 key")
+
+	fc := clock.NewFake()
+	fc.Set(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	signer, err := NewDKIMSigner("example.com", []DKIMKey{
+		{Selector: "rsa", Signer: rsaKey},
+		{Selector: "ed", Signer: edKey, NotBefore: fc.Now().Add(24 * time.Hour)},
+	}, fc)
+	test.AssertNotError(t, err, "NewDKIMSigner failed")
+
+	fromAddress, _ := mail.ParseAddress("happy sender <send@email.com>")
+	m := New("", "", "", "", nil, *fromAddress, blog.UseMock(), metrics.NoopRegisterer, 0, 0)
+	m.clk = fc
+	m.csprgSource = fakeSource{}
+	m.SetDKIMSigner(signer)
+
+	message, err := m.generateMessage([]string{"recv@email.com"}, "test subject", "this is the body  \n")
+	test.AssertNotError(t, err, "generateMessage failed")
+	tags, valid := verifyDKIM(t, message, &rsaKey.PublicKey)
+	test.Assert(t, valid, "bad RSA signature")
+	test.AssertEquals(t, tags["d"], "example.com")
+	test.AssertEquals(t, tags["s"], "rsa")
+	test.AssertEquals(t, tags["c"], "relaxed/relaxed")
+
+	// Once its NotBefore has passed, the Ed25519 key takes over.
+	fc.Add(24 * time.Hour)
+	message, err = m.generateMessage([]string{"recv@email.com"}, "test subject", "this is the body\n")
+	test.AssertNotError(t, err, "generateMessage failed")
+	tags, valid = verifyDKIM(t, message, edPub)
+	test.Assert(t, valid, "bad Ed25519 signature")
+	test.AssertEquals(t, tags["s"], "ed")
+
+	// The signature breaks if a signed header is changed.
+	tampered := strings.Replace(string(message), "Subject: test subject", "Subject: test Subject", 1)
+	_, valid = verifyDKIM(t, []byte(tampered), edPub)
+	test.Assert(t, !valid, "signature valid for tampered message")
+}
+
+func TestNewDKIMSigner(t *testing.T) {
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	test.AssertNotError(t, err, "generating Ed25519 key")
+	fc := clock.NewFake()
+
+	_, err = NewDKIMSigner("", []DKIMKey{{Selector: "ed", Signer: edKey}}, fc)
+	test.AssertError(t, err, "accepted empty domain")
+	_, err = NewDKIMSigner("example.com", nil, fc)
+	test.AssertError(t, err, "accepted no keys")
+	_, err = NewDKIMSigner("example.com", []DKIMKey{{Signer: edKey}}, fc)
+	test.AssertError(t, err, "accepted key without selector")
+
+	signer, err := NewDKIMSigner("example.com", []DKIMKey{
+		{Selector: "ed", Signer: edKey, NotBefore: fc.Now().Add(time.Hour)},
+	}, fc)
+	test.AssertNotError(t, err, "NewDKIMSigner failed")
+	_, err = signer.Sign([]byte("Subject: hi\r\n\r\nbody\r\n"))
+	test.AssertError(t, err, "signed with a key before its NotBefore")
+}
+
+func TestLoadDKIMKey(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dkim")
+	test.AssertNotError(t, err, "creating temp dir")
+	defer os.RemoveAll(dir)
+
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	test.AssertNotError(t, err, "generating Ed25519 key")
+	der, err := x509.MarshalPKCS8PrivateKey(edKey)
+	test.AssertNotError(t, err, "marshalling Ed25519 key")
+	edPath := filepath.Join(dir, "ed.pem")
+	err = ioutil.WriteFile(edPath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0600)
+	test.AssertNotError(t, err, "writing Ed25519 key")
+	signer, err := LoadDKIMKey(edPath)
+	test.AssertNotError(t, err, "loading Ed25519 key")
+	test.AssertDeepEquals(t, signer, edKey)
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	test.AssertNotError(t, err, "generating RSA key")
+	rsaPath := filepath.Join(dir, "rsa.pem")
+	err = ioutil.WriteFile(rsaPath, pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)}), 0600)
+	test.AssertNotError(t, err, "writing RSA key")
+	signer, err = LoadDKIMKey(rsaPath)
+	test.AssertNotError(t, err, "loading RSA key")
+	test.AssertEquals(t, signer.(*rsa.PrivateKey).N.Cmp(rsaKey.N), 0)
+
+	notPEM := filepath.Join(dir, "junk")
+	test.AssertNotError(t, ioutil.WriteFile(notPEM, []byte("junk"), 0600), "writing junk")
+	_, err = LoadDKIMKey(notPEM)
+	test.AssertError(t, err, "loaded a key from junk")
+}
@@ -17,6 +17,7 @@ import (
 	"net/textproto"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/jmhodges/clock"
@@ -49,18 +50,49 @@ type Mailer interface {
 	Close() error
 }
 
-// MailerImpl defines a mail transfer agent to use for sending mail. It is not
-// safe for concurrent access.
+// MailerImpl defines a mail transfer agent to use for sending mail. It keeps
+// a pool of SMTP connections open between messages, and is safe for concurrent
+// access once connected.
 type MailerImpl struct {
 	log              blog.Logger
 	dialer           dialer
 	from             mail.Address
-	client           smtpClient
 	clk              clock.Clock
 	csprgSource      idGenerator
 	reconnectBase    time.Duration
 	reconnectMax     time.Duration
 	sendMailAttempts *prometheus.CounterVec
+	connEvents       *prometheus.CounterVec
+	dkim             *DKIMSigner
+	pool             PoolConfig
+
+	mu        sync.Mutex
+	connected bool
+	// idle are the connections waiting to be reused, most recently used
+	// last.
+	idle []*pooledConn
+}
+
+// PoolConfig configures the SMTP connections a MailerImpl keeps open.
+type PoolConfig struct {
+	// Size is the number of idle connections kept open for reuse. Defaults
+	// to 1.
+	Size int
+	// MaxMessagesPerConn is the number of messages sent over a connection
+	// before it's closed and replaced, to stay within the server's limit on
+	// messages per session. If zero there's no limit.
+	MaxMessagesPerConn int
+	// KeepAliveInterval is how long a connection may be idle before it's
+	// checked with a NOOP, and replaced if that fails, before being reused.
+	// If zero, idle connections are reused without being checked.
+	KeepAliveInterval time.Duration
+}
+
+// pooledConn is an SMTP connection and what's been done with it.
+type pooledConn struct {
+	client   smtpClient
+	sent     int
+	lastUsed time.Time
 }
 
 type dialer interface {
@@ -72,6 +104,7 @@ type smtpClient interface {
 	Rcpt(string) error
 	Data() (io.WriteCloser, error)
 	Reset() error
+	Noop() error
 	Close() error
 }
 
@@ -113,6 +146,18 @@ func (d dryRunClient) Reset() (err error) {
 	return nil
 }
 
+func (d dryRunClient) Noop() error {
+	d.log.Debugf("NOOP")
+	return nil
+}
+
+func newConnEvents() *prometheus.CounterVec {
+	return prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "smtp_connection_events",
+		Help: "A counter of SMTP connection pool events labelled by event",
+	}, []string{"event"})
+}
+
 // New constructs a Mailer to represent an account on a particular mail
 // transfer agent.
 func New(
@@ -132,6 +177,8 @@ func New(
 		Help: "A counter of send mail attempts labelled by result",
 	}, []string{"result", "error"})
 	stats.MustRegister(sendMailAttempts)
+	connEvents := newConnEvents()
+	stats.MustRegister(connEvents)
 
 	return &MailerImpl{
 		dialer: &dialerImpl{
@@ -148,6 +195,7 @@ func New(
 		reconnectBase:    reconnectBase,
 		reconnectMax:     reconnectMax,
 		sendMailAttempts: sendMailAttempts,
+		connEvents:       connEvents,
 	}
 }
 
@@ -163,7 +211,24 @@ func NewDryRun(from mail.Address, logger blog.Logger) *MailerImpl {
 			Name: "send_mail_attempts",
 			Help: "A counter of send mail attempts labelled by result",
 		}, []string{"result", "error"}),
+		connEvents: newConnEvents(),
+	}
+}
+
+// SetPoolConfig configures the mailer's connection pool. It must be called
+// before Connect.
+func (m *MailerImpl) SetPoolConfig(pool PoolConfig) error {
+	if pool.Size < 0 || pool.MaxMessagesPerConn < 0 || pool.KeepAliveInterval < 0 {
+		return errors.New("SMTP pool settings must not be negative")
 	}
+	m.pool = pool
+	return nil
+}
+
+// SetDKIMSigner makes the mailer add DKIM signatures to the messages it
+// sends. It must be called before Connect.
+func (m *MailerImpl) SetDKIMSigner(signer *DKIMSigner) {
+	m.dkim = signer
 }
 
 func (m *MailerImpl) generateMessage(to []string, subject, body string) ([]byte, error) {
@@ -200,40 +265,115 @@ func (m *MailerImpl) generateMessage(to []string, subject, body string) ([]byte,
 	if err != nil {
 		return nil, err
 	}
-	return []byte(fmt.Sprintf(
+	message := []byte(fmt.Sprintf(
 		"%s\r\n\r\n%s\r\n",
 		strings.Join(headers, "\r\n"),
 		bodyBuf.String(),
-	)), nil
+	))
+	if m.dkim != nil {
+		return m.dkim.Sign(message)
+	}
+	return message, nil
 }
 
-func (m *MailerImpl) reconnect() {
+// reconnect replaces a broken connection with a new one, dialing on a backoff
+// schedule until it succeeds.
+func (m *MailerImpl) reconnect(conn *pooledConn) *pooledConn {
+	_ = conn.client.Close()
+	m.connEvents.WithLabelValues("broken").Inc()
 	for i := 0; ; i++ {
 		sleepDuration := core.RetryBackoff(i, m.reconnectBase, m.reconnectMax, 2)
 		m.log.Infof("sleeping for %s before reconnecting mailer", sleepDuration)
 		m.clk.Sleep(sleepDuration)
 		m.log.Info("attempting to reconnect mailer")
-		err := m.Connect()
+		newConn, err := m.dial()
 		if err != nil {
 			m.log.Warningf("reconnect error: %s", err)
 			continue
 		}
-		break
+		m.log.Info("reconnected successfully")
+		return newConn
 	}
-	m.log.Info("reconnected successfully")
 }
 
-// Connect opens a connection to the specified mail server. It must be called
-// before SendMail.
-func (m *MailerImpl) Connect() error {
+func (m *MailerImpl) dial() (*pooledConn, error) {
 	client, err := m.dialer.Dial()
+	if err != nil {
+		return nil, err
+	}
+	m.connEvents.WithLabelValues("dialed").Inc()
+	return &pooledConn{client: client, lastUsed: m.clk.Now()}, nil
+}
+
+// Connect opens a connection to the specified mail server, which is added to
+// the pool. It must be called before SendMail.
+func (m *MailerImpl) Connect() error {
+	conn, err := m.dial()
 	if err != nil {
 		return err
 	}
-	m.client = client
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.connected = true
+	m.idle = append(m.idle, conn)
 	return nil
 }
 
+// getConn returns an idle connection from the pool, if there's one which is
+// still alive, or a new one.
+func (m *MailerImpl) getConn() (*pooledConn, error) {
+	for {
+		m.mu.Lock()
+		if !m.connected {
+			m.mu.Unlock()
+			return nil, errors.New("call Connect before SendMail")
+		}
+		if len(m.idle) == 0 {
+			m.mu.Unlock()
+			return m.dial()
+		}
+		conn := m.idle[len(m.idle)-1]
+		m.idle = m.idle[:len(m.idle)-1]
+		m.mu.Unlock()
+
+		if m.pool.KeepAliveInterval == 0 || m.clk.Since(conn.lastUsed) < m.pool.KeepAliveInterval {
+			m.connEvents.WithLabelValues("reused").Inc()
+			return conn, nil
+		}
+		err := conn.client.Noop()
+		if err == nil {
+			m.connEvents.WithLabelValues("reused").Inc()
+			return conn, nil
+		}
+		m.log.Infof("closing idle SMTP connection which failed a NOOP: %s", err)
+		m.connEvents.WithLabelValues("expired").Inc()
+		_ = conn.client.Close()
+	}
+}
+
+// putConn returns a connection to the pool, unless it's reached its message
+// limit or the pool is full, in which case it's closed.
+func (m *MailerImpl) putConn(conn *pooledConn) {
+	conn.lastUsed = m.clk.Now()
+	if m.pool.MaxMessagesPerConn > 0 && conn.sent >= m.pool.MaxMessagesPerConn {
+		m.connEvents.WithLabelValues("retired").Inc()
+		_ = conn.client.Close()
+		return
+	}
+	size := m.pool.Size
+	if size == 0 {
+		size = 1
+	}
+	m.mu.Lock()
+	if m.connected && len(m.idle) < size {
+		m.idle = append(m.idle, conn)
+		m.mu.Unlock()
+		return
+	}
+	m.mu.Unlock()
+	_ = conn.client.Close()
+}
+
 type dialerImpl struct {
 	username, password, server, port string
 	rootCAs                          *x509.CertPool
@@ -264,43 +404,36 @@ func (di *dialerImpl) Dial() (smtpClient, error) {
 // argument as an error. If the reset command also errors, it combines both
 // errors and returns them. Without this we would get `nested MAIL command`.
 // https://github.com/letsencrypt/boulder/issues/3191
-func (m *MailerImpl) resetAndError(err error) error {
+func resetAndError(client smtpClient, err error) error {
 	if err == io.EOF {
 		return err
 	}
-	if err2 := m.client.Reset(); err2 != nil {
+	if err2 := client.Reset(); err2 != nil {
 		return fmt.Errorf("%s (also, on sending RSET: %s)", err, err2)
 	}
 	return err
 }
 
-func (m *MailerImpl) sendOne(to []string, subject, msg string) error {
-	if m.client == nil {
-		return errors.New("call Connect before SendMail")
-	}
-	body, err := m.generateMessage(to, subject, msg)
-	if err != nil {
-		return err
-	}
-	if err = m.client.Mail(m.from.String()); err != nil {
+func (m *MailerImpl) sendOne(client smtpClient, to []string, body []byte) error {
+	if err := client.Mail(m.from.String()); err != nil {
 		return err
 	}
 	for _, t := range to {
-		if err = m.client.Rcpt(t); err != nil {
-			return m.resetAndError(err)
+		if err := client.Rcpt(t); err != nil {
+			return resetAndError(client, err)
 		}
 	}
-	w, err := m.client.Data()
+	w, err := client.Data()
 	if err != nil {
-		return m.resetAndError(err)
+		return resetAndError(client, err)
 	}
 	_, err = w.Write(body)
 	if err != nil {
-		return m.resetAndError(err)
+		return resetAndError(client, err)
 	}
 	err = w.Close()
 	if err != nil {
-		return m.resetAndError(err)
+		return resetAndError(client, err)
 	}
 	return nil
 }
@@ -336,9 +469,20 @@ var recoverableErrorCodes = map[int]bool{
 // SendMail sends an email to the provided list of recipients. The email body
 // is simple text.
 func (m *MailerImpl) SendMail(to []string, subject, msg string) error {
+	conn, err := m.getConn()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		m.putConn(conn)
+	}()
+	body, err := m.generateMessage(to, subject, msg)
+	if err != nil {
+		return err
+	}
 	var protoErr *textproto.Error
 	for {
-		err := m.sendOne(to, subject, msg)
+		err := m.sendOne(conn.client, to, body)
 		if err == nil {
 			// If the error is nil, we sent the mail without issue. nice!
 			break
@@ -346,7 +490,7 @@ func (m *MailerImpl) SendMail(to []string, subject, msg string) error {
 			m.sendMailAttempts.WithLabelValues("failure", "EOF").Inc()
 			// If the error is an EOF, we should try to reconnect on a backoff
 			// schedule, sleeping between attempts.
-			m.reconnect()
+			conn = m.reconnect(conn)
 			// After reconnecting, loop around and try `sendOne` again.
 			continue
 		} else if errors.As(err, &protoErr) && protoErr.Code == 421 {
@@ -366,7 +510,7 @@ func (m *MailerImpl) SendMail(to []string, subject, msg string) error {
 			 *
 			 * [0] - https://github.com/letsencrypt/boulder/issues/2249
 			 */
-			m.reconnect()
+			conn = m.reconnect(conn)
 		} else if errors.As(err, &protoErr) && recoverableErrorCodes[protoErr.Code] {
 			m.sendMailAttempts.WithLabelValues("failure", fmt.Sprintf("SMTP %d", protoErr.Code)).Inc()
 			return RecoverableSMTPError{fmt.Sprintf("%d: %s", protoErr.Code, protoErr.Msg)}
@@ -378,14 +522,30 @@ func (m *MailerImpl) SendMail(to []string, subject, msg string) error {
 		}
 	}
 
+	conn.sent++
 	m.sendMailAttempts.WithLabelValues("success", "").Inc()
 	return nil
 }
 
-// Close closes the connection.
+// Close closes the pool's idle connections. Connections in use are closed
+// when they're returned to it.
 func (m *MailerImpl) Close() error {
-	if m.client == nil {
+	m.mu.Lock()
+	if !m.connected {
+		m.mu.Unlock()
 		return errors.New("call Connect before Close")
 	}
-	return m.client.Close()
+	m.connected = false
+	idle := m.idle
+	m.idle = nil
+	m.mu.Unlock()
+
+	var firstErr error
+	for _, conn := range idle {
+		err := conn.client.Close()
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
 }
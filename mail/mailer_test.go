@@ -5,6 +5,7 @@ import (
 	"crypto/tls"
 	"crypto/x509"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"math/big"
 	"net"
@@ -422,3 +423,118 @@ func TestOtherError(t *testing.T) {
 	test.AssertError(t, err, "SendMail didn't fail as expected")
 	test.AssertEquals(t, err.Error(), "999 1.1.1 This would probably be bad? (also, on sending RSET: short response: nop)")
 }
+
+// fakeSMTPClient accepts every message, and records how it was used.
+type fakeSMTPClient struct {
+	id      int
+	sent    *[]int
+	noopErr error
+	closed  bool
+}
+
+func (c *fakeSMTPClient) Mail(string) error { return nil }
+func (c *fakeSMTPClient) Rcpt(string) error { return nil }
+func (c *fakeSMTPClient) Data() (io.WriteCloser, error) {
+	*c.sent = append(*c.sent, c.id)
+	return nopWriteCloser{ioutil.Discard}, nil
+}
+func (c *fakeSMTPClient) Reset() error { return nil }
+func (c *fakeSMTPClient) Noop() error  { return c.noopErr }
+func (c *fakeSMTPClient) Close() error {
+	c.closed = true
+	return nil
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
+
+// fakeDialer hands out fakeSMTPClients with increasing IDs.
+type fakeDialer struct {
+	clients []*fakeSMTPClient
+	sent    []int
+}
+
+func (d *fakeDialer) Dial() (smtpClient, error) {
+	c := &fakeSMTPClient{id: len(d.clients) + 1, sent: &d.sent}
+	d.clients = append(d.clients, c)
+	return c, nil
+}
+
+func poolSetup(t *testing.T, pool PoolConfig) (*MailerImpl, *fakeDialer, clock.FakeClock) {
+	fromAddress, _ := mail.ParseAddress("you-are-a-winner@example.com")
+	m := New("", "", "", "", nil, *fromAddress, blog.UseMock(), metrics.NoopRegisterer, 0, 0)
+	d := &fakeDialer{}
+	m.dialer = d
+	fc := clock.NewFake()
+	m.clk = fc
+	test.AssertNotError(t, m.SetPoolConfig(pool), "SetPoolConfig failed")
+	return m, d, fc
+}
+
+func TestPoolReusesConnection(t *testing.T) {
+	m, d, _ := poolSetup(t, PoolConfig{})
+	err := m.SendMail([]string{"hi@bye.com"}, "subject", "body")
+	test.AssertError(t, err, "SendMail succeeded before Connect")
+
+	test.AssertNotError(t, m.Connect(), "Connect failed")
+	for i := 0; i < 3; i++ {
+		test.AssertNotError(t, m.SendMail([]string{"hi@bye.com"}, "subject", "body"), "SendMail failed")
+	}
+	test.AssertDeepEquals(t, d.sent, []int{1, 1, 1})
+	test.AssertNotError(t, m.Close(), "Close failed")
+	test.Assert(t, d.clients[0].closed, "connection not closed")
+	test.AssertError(t, m.Close(), "Close succeeded twice")
+}
+
+func TestPoolMaxMessagesPerConn(t *testing.T) {
+	m, d, _ := poolSetup(t, PoolConfig{MaxMessagesPerConn: 2})
+	test.AssertNotError(t, m.Connect(), "Connect failed")
+	for i := 0; i < 5; i++ {
+		test.AssertNotError(t, m.SendMail([]string{"hi@bye.com"}, "subject", "body"), "SendMail failed")
+	}
+	test.AssertDeepEquals(t, d.sent, []int{1, 1, 2, 2, 3})
+	test.Assert(t, d.clients[0].closed, "connection at its limit not closed")
+	test.Assert(t, d.clients[1].closed, "connection at its limit not closed")
+	test.Assert(t, !d.clients[2].closed, "connection closed early")
+}
+
+func TestPoolKeepAlive(t *testing.T) {
+	m, d, fc := poolSetup(t, PoolConfig{KeepAliveInterval: time.Minute})
+	test.AssertNotError(t, m.Connect(), "Connect failed")
+	test.AssertNotError(t, m.SendMail([]string{"hi@bye.com"}, "subject", "body"), "SendMail failed")
+
+	// A connection which is still alive after being idle is reused, but one
+	// which fails a NOOP is replaced.
+	fc.Add(2 * time.Minute)
+	test.AssertNotError(t, m.SendMail([]string{"hi@bye.com"}, "subject", "body"), "SendMail failed")
+	fc.Add(2 * time.Minute)
+	d.clients[0].noopErr = io.EOF
+	test.AssertNotError(t, m.SendMail([]string{"hi@bye.com"}, "subject", "body"), "SendMail failed")
+	test.AssertDeepEquals(t, d.sent, []int{1, 1, 2})
+	test.Assert(t, d.clients[0].closed, "dead connection not closed")
+}
+
+func TestPoolSize(t *testing.T) {
+	m, d, _ := poolSetup(t, PoolConfig{Size: 2})
+	test.AssertNotError(t, m.Connect(), "Connect failed")
+
+	// Take three connections at once, as concurrent senders would. Only two
+	// are kept once they're returned.
+	var conns []*pooledConn
+	for i := 0; i < 3; i++ {
+		conn, err := m.getConn()
+		test.AssertNotError(t, err, "getConn failed")
+		conns = append(conns, conn)
+	}
+	for _, conn := range conns {
+		m.putConn(conn)
+	}
+	test.AssertEquals(t, len(d.clients), 3)
+	test.AssertEquals(t, len(m.idle), 2)
+	test.Assert(t, d.clients[2].closed, "connection beyond pool size not closed")
+
+	test.AssertError(t, m.SetPoolConfig(PoolConfig{Size: -1}), "accepted negative pool size")
+}
@@ -340,6 +340,28 @@ func (sa *StorageAuthority) GetCertificateStatus(_ context.Context, serial strin
 	}
 }
 
+// GetCertificateStatuses is a mock
+func (sa *StorageAuthority) GetCertificateStatuses(ctx context.Context, req *sapb.Serials) (*sapb.CertificateStatuses, error) {
+	resp := &sapb.CertificateStatuses{}
+	for _, serial := range req.Serials {
+		status, err := sa.GetCertificateStatus(ctx, serial)
+		if err != nil {
+			continue
+		}
+		status.Serial = serial
+		resp.Statuses = append(resp.Statuses, &corepb.CertificateStatus{
+			Serial: status.Serial,
+			Status: string(status.Status),
+		})
+	}
+	return resp, nil
+}
+
+// GetMaxCertificateStatusID is a mock
+func (sa *StorageAuthority) GetMaxCertificateStatusID(_ context.Context, _ *corepb.Empty) (*sapb.CertificateStatusID, error) {
+	return &sapb.CertificateStatusID{}, nil
+}
+
 // AddPrecertificate is a mock
 func (sa *StorageAuthority) AddPrecertificate(ctx context.Context, req *sapb.AddCertificateRequest) (empty *corepb.Empty, err error) {
 	return
@@ -394,6 +416,24 @@ func (sa *StorageAuthority) PreviousCertificateExists(
 	}, nil
 }
 
+// ReplacementCertificateExists is a mock
+func (sa *StorageAuthority) ReplacementCertificateExists(
+	_ context.Context,
+	_ *sapb.ReplacementCertificateExistsRequest,
+) (*sapb.Exists, error) {
+	return &sapb.Exists{Exists: false}, nil
+}
+
+// GetSerialsByKey is a mock
+func (sa *StorageAuthority) GetSerialsByKey(_ context.Context, _ *sapb.SPKIHash) (*sapb.Serials, error) {
+	return &sapb.Serials{}, nil
+}
+
+// GetSerialsByAccount is a mock
+func (sa *StorageAuthority) GetSerialsByAccount(_ context.Context, _ *sapb.RegistrationID) (*sapb.Serials, error) {
+	return &sapb.Serials{}, nil
+}
+
 func (sa *StorageAuthority) GetPendingAuthorization(ctx context.Context, req *sapb.GetPendingAuthorizationRequest) (*core.Authorization, error) {
 	return nil, nil
 }
@@ -471,6 +511,11 @@ func (sa *StorageAuthority) NewOrder(_ context.Context, order *corepb.Order) (*c
 	return order, nil
 }
 
+// NewOrderAndAuthzs is a mock
+func (sa *StorageAuthority) NewOrderAndAuthzs(_ context.Context, req *sapb.NewOrderAndAuthzsRequest) (*corepb.Order, error) {
+	return req.NewOrder, nil
+}
+
 // SetOrderProcessing is a mock
 func (sa *StorageAuthority) SetOrderProcessing(_ context.Context, order *corepb.Order) error {
 	return nil
@@ -702,6 +747,116 @@ func (sa *StorageAuthority) KeyBlocked(ctx context.Context, req *sapb.KeyBlocked
 	return &sapb.Exists{Exists: false}, nil
 }
 
+// AddIncident is a mock
+func (sa *StorageAuthority) AddIncident(ctx context.Context, req *sapb.AddIncidentRequest) (*sapb.Incident, error) {
+	return &sapb.Incident{Id: 1, Name: req.Name, Url: req.Url, RenewBy: req.RenewBy}, nil
+}
+
+// AddIncidentSerials is a mock
+func (sa *StorageAuthority) AddIncidentSerials(ctx context.Context, req *sapb.AddIncidentSerialsRequest) (*corepb.Empty, error) {
+	return &corepb.Empty{}, nil
+}
+
+// SetIncidentStatus is a mock
+func (sa *StorageAuthority) SetIncidentStatus(ctx context.Context, req *sapb.SetIncidentStatusRequest) (*corepb.Empty, error) {
+	return &corepb.Empty{}, nil
+}
+
+// IncidentsForSerial is a mock
+func (sa *StorageAuthority) IncidentsForSerial(ctx context.Context, req *sapb.Serial) (*sapb.Incidents, error) {
+	return &sapb.Incidents{}, nil
+}
+
+// GetNotificationPreferences is a mock
+func (sa *StorageAuthority) GetNotificationPreferences(ctx context.Context, req *sapb.RegistrationID) (*sapb.NotificationPreferences, error) {
+	return nil, berrors.NotFoundError("no notification preferences for registration %d", req.Id)
+}
+
+// SetNotificationPreferences is a mock
+func (sa *StorageAuthority) SetNotificationPreferences(ctx context.Context, req *sapb.NotificationPreferences) (*corepb.Empty, error) {
+	return &corepb.Empty{}, nil
+}
+
+// GetFeatureOverrides is a mock
+func (sa *StorageAuthority) GetFeatureOverrides(ctx context.Context, req *sapb.RegistrationID) (*sapb.FeatureOverrides, error) {
+	return &sapb.FeatureOverrides{}, nil
+}
+
+// SetFeatureOverride is a mock
+func (sa *StorageAuthority) SetFeatureOverride(ctx context.Context, req *sapb.FeatureOverrideRequest) (*corepb.Empty, error) {
+	return &corepb.Empty{}, nil
+}
+
+// ClearFeatureOverride is a mock
+func (sa *StorageAuthority) ClearFeatureOverride(ctx context.Context, req *sapb.FeatureOverrideRequest) (*corepb.Empty, error) {
+	return &corepb.Empty{}, nil
+}
+
+// GetHostnamePolicy is a mock
+func (sa *StorageAuthority) GetHostnamePolicy(ctx context.Context, req *sapb.GetHostnamePolicyRequest) (*sapb.HostnamePolicy, error) {
+	return nil, berrors.NotFoundError("no hostname policy has been stored")
+}
+
+// AddHostnamePolicy is a mock
+func (sa *StorageAuthority) AddHostnamePolicy(ctx context.Context, req *sapb.HostnamePolicy) (*sapb.HostnamePolicy, error) {
+	return req, nil
+}
+
+// GetAccountAllowlist is a mock
+func (sa *StorageAuthority) GetAccountAllowlist(ctx context.Context, req *sapb.RegistrationID) (*sapb.AccountAllowlist, error) {
+	return &sapb.AccountAllowlist{}, nil
+}
+
+// AddAccountAllowlistEntry is a mock
+func (sa *StorageAuthority) AddAccountAllowlistEntry(ctx context.Context, req *sapb.AccountAllowlistEntry) (*corepb.Empty, error) {
+	return &corepb.Empty{}, nil
+}
+
+// RemoveAccountAllowlistEntry is a mock
+func (sa *StorageAuthority) RemoveAccountAllowlistEntry(ctx context.Context, req *sapb.AccountAllowlistEntry) (*corepb.Empty, error) {
+	return &corepb.Empty{}, nil
+}
+
+// ClearAccountAllowlist is a mock
+func (sa *StorageAuthority) ClearAccountAllowlist(ctx context.Context, req *sapb.RegistrationID) (*corepb.Empty, error) {
+	return &corepb.Empty{}, nil
+}
+
+// GetIssuanceCounts is a mock
+func (sa *StorageAuthority) GetIssuanceCounts(ctx context.Context, req *sapb.IssuanceCountsRequest) (*sapb.IssuanceCounts, error) {
+	return &sapb.IssuanceCounts{}, nil
+}
+
+// AddCertificateMetadata is a mock
+func (sa *StorageAuthority) AddCertificateMetadata(ctx context.Context, req *sapb.CertificateMetadata) (*corepb.Empty, error) {
+	return &corepb.Empty{}, nil
+}
+
+// GetAccountEvents is a mock
+func (sa *StorageAuthority) GetAccountEvents(ctx context.Context, req *sapb.GetAccountEventsRequest) (*sapb.AccountEvents, error) {
+	return &sapb.AccountEvents{}, nil
+}
+
+// GetKeyRollovers is a mock
+func (sa *StorageAuthority) GetKeyRollovers(ctx context.Context, req *sapb.GetKeyRolloversRequest) (*sapb.KeyRollovers, error) {
+	return &sapb.KeyRollovers{}, nil
+}
+
+// GetIssuanceLogEntry is a mock
+func (sa *StorageAuthority) GetIssuanceLogEntry(ctx context.Context, req *sapb.GetIssuanceLogEntryRequest) (*sapb.IssuanceLogEntry, error) {
+	return nil, berrors.NotFoundError("no issuance log entry")
+}
+
+// AddIssuanceLogEntry is a mock
+func (sa *StorageAuthority) AddIssuanceLogEntry(ctx context.Context, req *sapb.IssuanceLogEntry) (*corepb.Empty, error) {
+	return &corepb.Empty{}, nil
+}
+
+// AddAccountEvent is a mock
+func (sa *StorageAuthority) AddAccountEvent(ctx context.Context, req *sapb.AccountEvent) (*corepb.Empty, error) {
+	return &corepb.Empty{}, nil
+}
+
 // Publisher is a mock
 type Publisher struct {
 	// empty
@@ -712,6 +867,11 @@ func (*Publisher) SubmitToSingleCTWithResult(_ context.Context, _ *pubpb.Request
 	return nil, nil
 }
 
+// QueueFinalCert is a mock
+func (*Publisher) QueueFinalCert(_ context.Context, _ *pubpb.FinalCertRequest) (*pubpb.FinalCertQueued, error) {
+	return &pubpb.FinalCertQueued{}, nil
+}
+
 // Mailer is a mock
 type Mailer struct {
 	Messages []MailerMessage
@@ -11,6 +11,13 @@
 // The MaxUsed value determines how long a generated nonce can be used before it
 // is forgotten. To calculate that period, divide the MaxUsed value by average
 // redemption rate (valid POSTs per second).
+//
+// Optionally, a NonceService can also record the nonces it generates in a
+// SharedStore, such as Redis, which is consulted when redeeming them. This
+// allows a nonce generated by one instance to be redeemed by any other
+// instance using the same store and configured with its prefix. If
+// the store can't be reached within its latency budget the NonceService falls
+// back to validating nonces locally.
 package nonce
 
 import (
@@ -18,7 +25,9 @@ import (
 	"context"
 	"crypto/aes"
 	"crypto/cipher"
+	"crypto/hmac"
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/base64"
 	"errors"
 	"fmt"
@@ -37,6 +46,16 @@ const (
 
 var errInvalidNonceLength = errors.New("invalid nonce length")
 
+// SharedStore holds unredeemed nonces on behalf of a group of nonce services.
+type SharedStore interface {
+	// StoreNonce records a newly generated nonce, which should be forgotten
+	// after ttl.
+	StoreNonce(ctx context.Context, nonce string, ttl time.Duration) error
+	// RedeemNonce atomically removes a nonce from the store, returning true if
+	// it was present.
+	RedeemNonce(ctx context.Context, nonce string) (bool, error)
+}
+
 // NonceService generates, cancels, and tracks Nonces.
 type NonceService struct {
 	mu               sync.Mutex
@@ -50,6 +69,20 @@ type NonceService struct {
 	nonceCreates     prometheus.Counter
 	nonceRedeems     *prometheus.CounterVec
 	nonceHeapLatency prometheus.Histogram
+	shared           SharedStore
+	sharedPrefixes   map[string]bool
+	sharedTTL        time.Duration
+	sharedTimeout    time.Duration
+	sharedOps        *prometheus.CounterVec
+	// prevPrefix is the prefix in use before the last call to RotatePrefix.
+	// Nonces bearing it are accepted until prevPrefixExpires.
+	prevPrefix        string
+	prevPrefixExpires time.Time
+	// prevSharedPrefixes are the peer prefixes in use before the last call to
+	// RotatePeerPrefixes. Nonces bearing them are redeemed from the shared
+	// store until prevSharedPrefixesExpire.
+	prevSharedPrefixes       map[string]bool
+	prevSharedPrefixesExpire time.Time
 }
 
 type int64Heap []int64
@@ -72,18 +105,9 @@ func (h *int64Heap) Pop() interface{} {
 
 // NewNonceService constructs a NonceService with defaults
 func NewNonceService(stats prometheus.Registerer, maxUsed int, prefix string) (*NonceService, error) {
-	// If a prefix is provided it must be four characters and valid
-	// base64. The prefix is required to be base64url as RFC8555
-	// section 6.5.1 requires that nonces use that encoding.
-	// As base64 operates on three byte binary segments we require
-	// the prefix to be three bytes (four characters) so that the
-	// bytes preceding the prefix wouldn't impact the encoding.
 	if prefix != "" {
-		if len(prefix) != 4 {
-			return nil, errors.New("nonce prefix must be 4 characters")
-		}
-		if _, err := base64.RawURLEncoding.DecodeString(prefix); err != nil {
-			return nil, errors.New("nonce prefix must be valid base64url")
+		if err := validatePrefix(prefix); err != nil {
+			return nil, err
 		}
 	}
 
@@ -120,6 +144,11 @@ func NewNonceService(stats prometheus.Registerer, maxUsed int, prefix string) (*
 		Help: "A histogram of latencies of heap pop operations",
 	})
 	stats.MustRegister(nonceHeapLatency)
+	sharedOps := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "nonce_shared_store",
+		Help: "A counter of shared nonce store operations labelled by operation and result",
+	}, []string{"op", "result"})
+	stats.MustRegister(sharedOps)
 
 	return &NonceService{
 		earliest:         0,
@@ -132,10 +161,128 @@ func NewNonceService(stats prometheus.Registerer, maxUsed int, prefix string) (*
 		nonceCreates:     nonceCreates,
 		nonceRedeems:     nonceRedeems,
 		nonceHeapLatency: nonceHeapLatency,
+		sharedOps:        sharedOps,
 	}, nil
 }
 
-func (ns *NonceService) encrypt(counter int64) (string, error) {
+// UseSharedStore configures the NonceService to record the nonces it
+// generates in store, where they're kept for ttl, and to consult store when
+// redeeming nonces. Each operation on store must complete within timeout,
+// otherwise the NonceService behaves as though no store was configured.
+//
+// Only nonces bearing the NonceService's own prefix, or one of peerPrefixes,
+// those of the other nonce services sharing store, are redeemed from it. Any
+// other nonce is rejected, so that one from a nonce service using a different
+// store, such as in another datacenter, can't be redeemed here, where the
+// store it's recorded in can't cross it off.
+func (ns *NonceService) UseSharedStore(store SharedStore, ttl, timeout time.Duration, peerPrefixes []string) {
+	ns.shared = store
+	ns.sharedPrefixes = prefixSet(peerPrefixes)
+	ns.sharedTTL = ttl
+	ns.sharedTimeout = timeout
+}
+
+// RotatePeerPrefixes replaces the prefixes of the other nonce services sharing
+// the store with peerPrefixes, for instance when they're derived from a nonce
+// prefix key which has been rotated. Nonces bearing the previous peer prefixes
+// continue to be redeemed from the store for window, like those bearing the
+// NonceService's own previous prefix.
+func (ns *NonceService) RotatePeerPrefixes(peerPrefixes []string, window time.Duration) {
+	ns.mu.Lock()
+	defer ns.mu.Unlock()
+	ns.prevSharedPrefixes = ns.sharedPrefixes
+	ns.prevSharedPrefixesExpire = time.Now().Add(window)
+	ns.sharedPrefixes = prefixSet(peerPrefixes)
+}
+
+func prefixSet(prefixes []string) map[string]bool {
+	set := make(map[string]bool, len(prefixes))
+	for _, prefix := range prefixes {
+		set[prefix] = true
+	}
+	return set
+}
+
+// sharedPrefix returns true if the nonce bears a prefix whose nonces are
+// recorded in the shared store. Nonces are unprefixed if the NonceService
+// is, in which case they're all taken to be.
+func (ns *NonceService) sharedPrefix(nonce string) bool {
+	ns.mu.Lock()
+	prefixed := ns.prefix != ""
+	ns.mu.Unlock()
+	if !prefixed {
+		return true
+	}
+	prefix, _, err := splitNonce(nonce)
+	if err != nil {
+		return false
+	}
+	if ns.validPrefix(prefix) {
+		return true
+	}
+	ns.mu.Lock()
+	defer ns.mu.Unlock()
+	return ns.sharedPrefixes[prefix] || (ns.prevSharedPrefixes[prefix] && time.Now().Before(ns.prevSharedPrefixesExpire))
+}
+
+// validatePrefix checks that prefix is four characters and valid base64. The
+// prefix is required to be base64url as RFC8555 section 6.5.1 requires that
+// nonces use that encoding. As base64 operates on three byte binary segments
+// we require the prefix to be three bytes (four characters) so that the bytes
+// preceding the prefix wouldn't impact the encoding.
+func validatePrefix(prefix string) error {
+	if len(prefix) != 4 {
+		return errors.New("nonce prefix must be 4 characters")
+	}
+	if _, err := base64.RawURLEncoding.DecodeString(prefix); err != nil {
+		return errors.New("nonce prefix must be valid base64url")
+	}
+	return nil
+}
+
+// DerivePrefix returns the nonce prefix for the nonce service reachable at
+// addr, derived from key. This allows nonce services and the WFEs which route
+// nonces to them to agree on prefixes given only a shared key and the list of
+// nonce service addresses.
+func DerivePrefix(key []byte, addr string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(addr))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil)[:3])
+}
+
+// RotatePrefix replaces the prefix of newly generated nonces with prefix.
+// Nonces bearing the previous prefix continue to be accepted for window, so
+// that nonces already handed out to clients aren't rejected. Nonces bearing
+// any earlier prefix are rejected immediately.
+func (ns *NonceService) RotatePrefix(prefix string, window time.Duration) error {
+	if err := validatePrefix(prefix); err != nil {
+		return err
+	}
+	ns.mu.Lock()
+	defer ns.mu.Unlock()
+	if ns.prefix == "" {
+		return errors.New("can't rotate the prefix of an unprefixed nonce service")
+	}
+	if prefix == ns.prefix {
+		return nil
+	}
+	ns.prevPrefix = ns.prefix
+	ns.prevPrefixExpires = time.Now().Add(window)
+	ns.prefix = prefix
+	return nil
+}
+
+// validPrefix returns true if nonces bearing prefix should be accepted.
+func (ns *NonceService) validPrefix(prefix string) bool {
+	ns.mu.Lock()
+	defer ns.mu.Unlock()
+	if prefix == ns.prefix {
+		return true
+	}
+	return ns.prevPrefix != "" && prefix == ns.prevPrefix && time.Now().Before(ns.prevPrefixExpires)
+}
+
+func (ns *NonceService) encrypt(counter int64, prefix string) (string, error) {
 	// Generate a nonce with upper 4 bytes zero
 	nonce := make([]byte, 12)
 	for i := 0; i < 4; i++ {
@@ -157,20 +304,23 @@ func (ns *NonceService) encrypt(counter int64) (string, error) {
 	copy(ret, nonce[4:])
 	copy(ret[8:], ct)
 
-	return ns.prefix + base64.RawURLEncoding.EncodeToString(ret), nil
+	return prefix + base64.RawURLEncoding.EncodeToString(ret), nil
 }
 
 func (ns *NonceService) decrypt(nonce string) (int64, error) {
 	body := nonce
-	if ns.prefix != "" {
+	ns.mu.Lock()
+	prefixed := ns.prefix != ""
+	ns.mu.Unlock()
+	if prefixed {
 		var prefix string
 		var err error
 		prefix, body, err = splitNonce(nonce)
 		if err != nil {
 			return 0, err
 		}
-		if ns.prefix != prefix {
-			return 0, fmt.Errorf("nonce contains invalid prefix: expected %q, got %q", ns.prefix, prefix)
+		if !ns.validPrefix(prefix) {
+			return 0, fmt.Errorf("nonce contains invalid prefix %q", prefix)
 		}
 	}
 	decoded, err := base64.RawURLEncoding.DecodeString(body)
@@ -202,14 +352,61 @@ func (ns *NonceService) Nonce() (string, error) {
 	ns.mu.Lock()
 	ns.latest++
 	latest := ns.latest
+	prefix := ns.prefix
 	ns.mu.Unlock()
 	defer ns.nonceCreates.Inc()
-	return ns.encrypt(latest)
+	nonce, err := ns.encrypt(latest, prefix)
+	if err != nil {
+		return "", err
+	}
+	if ns.shared != nil {
+		// Failing to store the nonce isn't fatal: it can still be redeemed
+		// locally if the shared store is unavailable at redemption time too.
+		ctx, cancel := context.WithTimeout(context.Background(), ns.sharedTimeout)
+		defer cancel()
+		err = ns.shared.StoreNonce(ctx, nonce, ns.sharedTTL)
+		if err != nil {
+			ns.sharedOps.WithLabelValues("store", "error").Inc()
+		} else {
+			ns.sharedOps.WithLabelValues("store", "success").Inc()
+		}
+	}
+	return nonce, nil
 }
 
 // Valid determines whether the provided Nonce string is valid, returning
-// true if so.
+// true if so. If a shared store is configured and reachable it is
+// authoritative, so nonces generated by the other nonce services using the
+// same store are accepted, and nonces already redeemed through them are
+// rejected. Nonces with any other prefix are always rejected.
 func (ns *NonceService) Valid(nonce string) bool {
+	if ns.shared != nil {
+		if !ns.sharedPrefix(nonce) {
+			ns.nonceRedeems.WithLabelValues("invalid", "unknown prefix").Inc()
+			return false
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), ns.sharedTimeout)
+		defer cancel()
+		present, err := ns.shared.RedeemNonce(ctx, nonce)
+		if err == nil {
+			ns.sharedOps.WithLabelValues("redeem", "success").Inc()
+			if !present {
+				ns.nonceRedeems.WithLabelValues("invalid", "not in shared store").Inc()
+				return false
+			}
+			// Also cross the nonce off locally, if it's one of ours, so that
+			// it can't be reused should the shared store become unavailable.
+			if c, err := ns.decrypt(nonce); err == nil {
+				ns.mu.Lock()
+				ns.markUsed(c)
+				ns.mu.Unlock()
+			}
+			ns.nonceRedeems.WithLabelValues("valid", "").Inc()
+			return true
+		}
+		ns.sharedOps.WithLabelValues("redeem", "error").Inc()
+	}
+
 	c, err := ns.decrypt(nonce)
 	if err != nil {
 		ns.nonceRedeems.WithLabelValues("invalid", "decrypt").Inc()
@@ -233,6 +430,17 @@ func (ns *NonceService) Valid(nonce string) bool {
 		return false
 	}
 
+	ns.markUsed(c)
+	ns.nonceRedeems.WithLabelValues("valid", "").Inc()
+	return true
+}
+
+// markUsed adds counter c to the cross-off list, retiring the oldest counter
+// value if the list is full. It must be called with ns.mu held.
+func (ns *NonceService) markUsed(c int64) {
+	if c > ns.latest || c <= ns.earliest || ns.used[c] {
+		return
+	}
 	ns.used[c] = true
 	heap.Push(ns.usedHeap, c)
 	if len(ns.used) > ns.maxUsed {
@@ -241,9 +449,6 @@ func (ns *NonceService) Valid(nonce string) bool {
 		ns.nonceHeapLatency.Observe(time.Since(s).Seconds())
 		delete(ns.used, ns.earliest)
 	}
-
-	ns.nonceRedeems.WithLabelValues("valid", "").Inc()
-	return true
 }
 
 func splitNonce(nonce string) (string, string, error) {
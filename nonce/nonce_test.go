@@ -4,7 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
 	"testing"
+	"time"
 
 	corepb "github.com/letsencrypt/boulder/core/proto"
 	"github.com/letsencrypt/boulder/metrics"
@@ -154,6 +156,10 @@ func (mnc *malleableNonceClient) Nonce(ctx context.Context, in *corepb.Empty, op
 	return nil, errors.New("unimplemented")
 }
 
+func (mnc *malleableNonceClient) NonceStream(ctx context.Context, in *noncepb.NonceStreamRequest, opts ...grpc.CallOption) (noncepb.NonceService_NonceStreamClient, error) {
+	return nil, errors.New("unimplemented")
+}
+
 func TestRemoteRedeem(t *testing.T) {
 	valid, err := RemoteRedeem(context.Background(), nil, "q")
 	test.AssertNotError(t, err, "RemoteRedeem failed")
@@ -210,3 +216,160 @@ func TestNoncePrefixValidation(t *testing.T) {
 	_, err = NewNonceService(metrics.NoopRegisterer, 0, "heyy")
 	test.AssertNotError(t, err, "NewNonceService failed with valid nonce prefix")
 }
+
+// fakeSharedStore is an in-memory SharedStore which can be made unavailable.
+type fakeSharedStore struct {
+	sync.Mutex
+	nonces map[string]bool
+	down   bool
+}
+
+func (fss *fakeSharedStore) StoreNonce(_ context.Context, nonce string, _ time.Duration) error {
+	fss.Lock()
+	defer fss.Unlock()
+	if fss.down {
+		return errors.New("shared store unavailable")
+	}
+	fss.nonces[nonce] = true
+	return nil
+}
+
+func (fss *fakeSharedStore) RedeemNonce(_ context.Context, nonce string) (bool, error) {
+	fss.Lock()
+	defer fss.Unlock()
+	if fss.down {
+		return false, errors.New("shared store unavailable")
+	}
+	present := fss.nonces[nonce]
+	delete(fss.nonces, nonce)
+	return present, nil
+}
+
+func TestSharedStore(t *testing.T) {
+	store := &fakeSharedStore{nonces: make(map[string]bool)}
+	ns1, err := NewNonceService(metrics.NoopRegisterer, 0, "aaaa")
+	test.AssertNotError(t, err, "Could not create nonce service")
+	ns1.UseSharedStore(store, time.Hour, time.Second, []string{"bbbb"})
+	ns2, err := NewNonceService(metrics.NoopRegisterer, 0, "bbbb")
+	test.AssertNotError(t, err, "Could not create nonce service")
+	ns2.UseSharedStore(store, time.Hour, time.Second, []string{"aaaa"})
+
+	// A nonce from one service can be redeemed by another, but only once.
+	n, err := ns1.Nonce()
+	test.AssertNotError(t, err, "Could not create nonce")
+	test.Assert(t, ns2.Valid(n), "Rejected a nonce from the shared store")
+	test.Assert(t, !ns1.Valid(n), "Accepted a nonce already redeemed elsewhere")
+
+	// A nonce redeemed through the shared store is also crossed off locally,
+	// so it can't be reused while the store is unavailable.
+	n, err = ns1.Nonce()
+	test.AssertNotError(t, err, "Could not create nonce")
+	test.Assert(t, ns1.Valid(n), "Rejected a fresh nonce")
+	store.down = true
+	test.Assert(t, !ns1.Valid(n), "Accepted a reused nonce while the store was down")
+
+	// While the store is unavailable, nonces are still validated locally.
+	n, err = ns1.Nonce()
+	test.AssertNotError(t, err, "Could not create nonce")
+	test.Assert(t, !ns2.Valid(n), "Accepted a foreign nonce while the store was down")
+	test.Assert(t, ns1.Valid(n), "Rejected a local nonce while the store was down")
+	test.Assert(t, !ns1.Valid(n), "Recognized the same nonce twice while the store was down")
+
+	// Nonces generated while the store was unavailable are rejected once it's
+	// available again, since the store is authoritative.
+	n, err = ns1.Nonce()
+	test.AssertNotError(t, err, "Could not create nonce")
+	store.down = false
+	test.Assert(t, !ns1.Valid(n), "Accepted a nonce missing from the shared store")
+
+	// Nonces with a prefix from outside the group sharing the store are
+	// rejected, even if they're somehow present in it.
+	ns3, err := NewNonceService(metrics.NoopRegisterer, 0, "cccc")
+	test.AssertNotError(t, err, "Could not create nonce service")
+	ns3.UseSharedStore(store, time.Hour, time.Second, nil)
+	n, err = ns3.Nonce()
+	test.AssertNotError(t, err, "Could not create nonce")
+	test.Assert(t, !ns1.Valid(n), "Accepted a nonce with an unknown prefix")
+	test.Assert(t, store.nonces[n], "Redeemed a nonce with an unknown prefix from the store")
+}
+
+func TestRotatePeerPrefixes(t *testing.T) {
+	store := &fakeSharedStore{nonces: make(map[string]bool)}
+	ns1, err := NewNonceService(metrics.NoopRegisterer, 0, "aaaa")
+	test.AssertNotError(t, err, "Could not create nonce service")
+	ns1.UseSharedStore(store, time.Hour, time.Second, []string{"bbbb"})
+	oldPeer, err := NewNonceService(metrics.NoopRegisterer, 0, "bbbb")
+	test.AssertNotError(t, err, "Could not create nonce service")
+	oldPeer.UseSharedStore(store, time.Hour, time.Second, nil)
+	newPeer, err := NewNonceService(metrics.NoopRegisterer, 0, "dddd")
+	test.AssertNotError(t, err, "Could not create nonce service")
+	newPeer.UseSharedStore(store, time.Hour, time.Second, nil)
+
+	oldNonce, err := oldPeer.Nonce()
+	test.AssertNotError(t, err, "Could not create nonce")
+	newNonce, err := newPeer.Nonce()
+	test.AssertNotError(t, err, "Could not create nonce")
+	test.Assert(t, !ns1.Valid(newNonce), "Accepted a nonce bearing a peer prefix before rotation")
+	newNonce, err = newPeer.Nonce()
+	test.AssertNotError(t, err, "Could not create nonce")
+
+	// After rotation both the new and, within the window, the previous peer
+	// prefixes are accepted.
+	ns1.RotatePeerPrefixes([]string{"dddd"}, time.Hour)
+	test.Assert(t, ns1.Valid(newNonce), "Rejected a nonce bearing the new peer prefix")
+	test.Assert(t, ns1.Valid(oldNonce), "Rejected a nonce bearing the previous peer prefix")
+
+	// Once the window has passed the previous peer prefixes are rejected, as
+	// are any from before the previous rotation.
+	oldNonce, err = oldPeer.Nonce()
+	test.AssertNotError(t, err, "Could not create nonce")
+	newNonce, err = newPeer.Nonce()
+	test.AssertNotError(t, err, "Could not create nonce")
+	ns1.RotatePeerPrefixes([]string{"eeee"}, 0)
+	test.Assert(t, !ns1.Valid(newNonce), "Accepted a nonce bearing an expired peer prefix")
+	test.Assert(t, !ns1.Valid(oldNonce), "Accepted a nonce bearing a peer prefix from two rotations ago")
+}
+
+func TestDerivePrefix(t *testing.T) {
+	key := []byte("a nonce prefix key")
+	prefix := DerivePrefix(key, "nonce1.boulder:9101")
+	test.AssertNotError(t, validatePrefix(prefix), "derived an invalid prefix")
+	test.AssertEquals(t, DerivePrefix(key, "nonce1.boulder:9101"), prefix)
+	test.Assert(t, DerivePrefix(key, "nonce2.boulder:9101") != prefix, "different addresses derived the same prefix")
+	test.Assert(t, DerivePrefix([]byte("another key"), "nonce1.boulder:9101") != prefix, "different keys derived the same prefix")
+}
+
+func TestRotatePrefix(t *testing.T) {
+	ns, err := NewNonceService(metrics.NoopRegisterer, 0, "aaaa")
+	test.AssertNotError(t, err, "Could not create nonce service")
+	old1, err := ns.Nonce()
+	test.AssertNotError(t, err, "Could not create nonce")
+	old2, err := ns.Nonce()
+	test.AssertNotError(t, err, "Could not create nonce")
+
+	err = ns.RotatePrefix("bbb", time.Hour)
+	test.AssertError(t, err, "RotatePrefix accepted an invalid prefix")
+	err = ns.RotatePrefix("bbbb", time.Hour)
+	test.AssertNotError(t, err, "RotatePrefix failed")
+
+	// Nonces generated before the rotation are still accepted during the
+	// rotation window, and new nonces bear the new prefix.
+	test.Assert(t, ns.Valid(old1), "Rejected a nonce with the previous prefix")
+	n, err := ns.Nonce()
+	test.AssertNotError(t, err, "Could not create nonce")
+	test.AssertEquals(t, n[:4], "bbbb")
+	test.Assert(t, ns.Valid(n), "Rejected a nonce with the new prefix")
+
+	// Once the window has passed the previous prefix is rejected.
+	err = ns.RotatePrefix("cccc", 0)
+	test.AssertNotError(t, err, "RotatePrefix failed")
+	test.Assert(t, !ns.Valid(old2), "Accepted a nonce with a prefix from two rotations ago")
+	n, err = ns.Nonce()
+	test.AssertNotError(t, err, "Could not create nonce")
+	test.Assert(t, ns.Valid(n), "Rejected a nonce with the new prefix")
+
+	unprefixed, err := NewNonceService(metrics.NoopRegisterer, 0, "")
+	test.AssertNotError(t, err, "Could not create nonce service")
+	err = unprefixed.RotatePrefix("bbbb", time.Hour)
+	test.AssertError(t, err, "RotatePrefix succeeded on an unprefixed nonce service")
+}
@@ -0,0 +1,239 @@
+package nonce
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jmhodges/clock"
+	"github.com/prometheus/client_golang/prometheus"
+
+	corepb "github.com/letsencrypt/boulder/core/proto"
+	blog "github.com/letsencrypt/boulder/log"
+	noncepb "github.com/letsencrypt/boulder/nonce/proto"
+)
+
+const (
+	defaultPoolSize = 100
+	// defaultPoolMaxAge is kept well below the time a busy nonce service takes
+	// to redeem MaxUsed nonces, after which it forgets older ones.
+	defaultPoolMaxAge = 30 * time.Second
+	poolFetchTimeout  = 5 * time.Second
+	poolRetryInterval = time.Second
+)
+
+// PoolConfig configures a Pool. Zero values are replaced by defaults.
+type PoolConfig struct {
+	// Size is the number of nonces kept for each backend. Defaults to 100.
+	Size int
+	// BatchSize is the number of nonces fetched by each NonceStream request,
+	// which is made whenever there's room for a batch in a backend's pool.
+	// Defaults to half of Size.
+	BatchSize int
+	// MaxAge is how long a nonce is kept in the pool before being discarded
+	// unused. Defaults to 30s.
+	MaxAge time.Duration
+}
+
+type pooledNonce struct {
+	nonce   string
+	fetched time.Time
+}
+
+// poolBackend holds the nonces fetched from one nonce service.
+type poolBackend struct {
+	client noncepb.NonceServiceClient
+	nonces chan pooledNonce
+	wake   chan struct{}
+}
+
+// Pool hands out nonces which it fetches in batches from each of a set of
+// nonce services, so that responses don't each wait for a round trip to one.
+// Each backend's pool is refilled asynchronously as it's drawn down. If every
+// pool is empty, for instance because the backends are down, the Pool falls
+// back to fetching a nonce for each request.
+type Pool struct {
+	backends []*poolBackend
+	next     uint64
+	config   PoolConfig
+	clk      clock.Clock
+	log      blog.Logger
+
+	gets    *prometheus.CounterVec
+	fetches *prometheus.CounterVec
+	expired prometheus.Counter
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewPool creates a Pool fetching nonces from the given nonce services and
+// starts filling it. Stop must be called to stop refilling it.
+func NewPool(backends []noncepb.NonceServiceClient, config PoolConfig, clk clock.Clock, stats prometheus.Registerer, logger blog.Logger) *Pool {
+	if config.Size <= 0 {
+		config.Size = defaultPoolSize
+	}
+	if config.BatchSize <= 0 || config.BatchSize > config.Size {
+		config.BatchSize = (config.Size + 1) / 2
+	}
+	if config.MaxAge <= 0 {
+		config.MaxAge = defaultPoolMaxAge
+	}
+
+	gets := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "nonce_pool_gets",
+		Help: "A counter of nonces handed out by the nonce pool, labelled by whether they were pooled, fetched on demand, or couldn't be got",
+	}, []string{"result"})
+	stats.MustRegister(gets)
+	fetches := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "nonce_pool_fetches",
+		Help: "A counter of batches of nonces fetched to refill the nonce pool, labelled by result",
+	}, []string{"result"})
+	stats.MustRegister(fetches)
+	expired := prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "nonce_pool_expired",
+		Help: "A counter of pooled nonces discarded for being older than the pool's MaxAge",
+	})
+	stats.MustRegister(expired)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &Pool{
+		config:  config,
+		clk:     clk,
+		log:     logger,
+		gets:    gets,
+		fetches: fetches,
+		expired: expired,
+		cancel:  cancel,
+	}
+	for _, client := range backends {
+		b := &poolBackend{
+			client: client,
+			nonces: make(chan pooledNonce, config.Size),
+			wake:   make(chan struct{}, 1),
+		}
+		p.backends = append(p.backends, b)
+		p.wg.Add(1)
+		go p.refill(ctx, b)
+	}
+	return p
+}
+
+// Stop stops refilling the pool and waits for in-flight fetches to finish.
+func (p *Pool) Stop() {
+	p.cancel()
+	p.wg.Wait()
+}
+
+// Nonce returns a nonce from one of the backends' pools, taking from each in
+// turn, or fetches one from a backend if they're all empty.
+func (p *Pool) Nonce(ctx context.Context) (string, error) {
+	start := atomic.AddUint64(&p.next, 1)
+	for i := range p.backends {
+		b := p.backends[(start+uint64(i))%uint64(len(p.backends))]
+		nonce, ok := p.take(b)
+		if ok {
+			p.gets.WithLabelValues("pooled").Inc()
+			return nonce, nil
+		}
+	}
+
+	err := errors.New("nonce pool has no backends")
+	for i := range p.backends {
+		b := p.backends[(start+uint64(i))%uint64(len(p.backends))]
+		var msg *noncepb.NonceMessage
+		msg, err = b.client.Nonce(ctx, &corepb.Empty{})
+		if err == nil {
+			p.gets.WithLabelValues("fetched").Inc()
+			return msg.Nonce, nil
+		}
+	}
+	p.gets.WithLabelValues("failed").Inc()
+	return "", err
+}
+
+// take returns a nonce from a backend's pool, discarding any which are too
+// old, and wakes its refill loop if there's now room for a batch.
+func (p *Pool) take(b *poolBackend) (string, bool) {
+	defer func() {
+		if p.config.Size-len(b.nonces) >= p.config.BatchSize {
+			select {
+			case b.wake <- struct{}{}:
+			default:
+			}
+		}
+	}()
+	for {
+		select {
+		case n := <-b.nonces:
+			if p.clk.Since(n.fetched) > p.config.MaxAge {
+				p.expired.Inc()
+				continue
+			}
+			return n.nonce, true
+		default:
+			return "", false
+		}
+	}
+}
+
+// refill keeps a backend's pool topped up, fetching a batch whenever there's
+// room for one, until the Pool is stopped. After a failed fetch it waits
+// before trying the backend again, and meanwhile Nonce falls back to fetching
+// single nonces.
+func (p *Pool) refill(ctx context.Context, b *poolBackend) {
+	defer p.wg.Done()
+	for {
+		for p.config.Size-len(b.nonces) >= p.config.BatchSize {
+			err := p.fetch(ctx, b)
+			if ctx.Err() != nil {
+				return
+			}
+			if err != nil {
+				p.log.Warningf("Failed to fetch nonces for the nonce pool: %s", err)
+				select {
+				case <-ctx.Done():
+					return
+				case <-p.clk.After(poolRetryInterval):
+				}
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-b.wake:
+		}
+	}
+}
+
+// fetch adds a batch of nonces from a backend to its pool.
+func (p *Pool) fetch(ctx context.Context, b *poolBackend) error {
+	ctx, cancel := context.WithTimeout(ctx, poolFetchTimeout)
+	defer cancel()
+	stream, err := b.client.NonceStream(ctx, &noncepb.NonceStreamRequest{Count: int64(p.config.BatchSize)})
+	if err != nil {
+		p.fetches.WithLabelValues("failure").Inc()
+		return err
+	}
+	for {
+		msg, err := stream.Recv()
+		if err == io.EOF {
+			p.fetches.WithLabelValues("success").Inc()
+			return nil
+		}
+		if err != nil {
+			p.fetches.WithLabelValues("failure").Inc()
+			return err
+		}
+		select {
+		case b.nonces <- pooledNonce{nonce: msg.Nonce, fetched: p.clk.Now()}:
+		default:
+			// Only this backend's refill loop adds to its pool, and it only
+			// fetches when there's room for a batch, so this shouldn't
+			// happen. If it does, the nonce is simply never handed out.
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package nonce
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jmhodges/clock"
+	"google.golang.org/grpc"
+
+	corepb "github.com/letsencrypt/boulder/core/proto"
+	blog "github.com/letsencrypt/boulder/log"
+	"github.com/letsencrypt/boulder/metrics"
+	noncepb "github.com/letsencrypt/boulder/nonce/proto"
+	"github.com/letsencrypt/boulder/test"
+)
+
+// poolBackendClient is a nonce service which hands out numbered nonces.
+type poolBackendClient struct {
+	name string
+
+	mu         sync.Mutex
+	generated  int
+	singles    int
+	streamErr  error
+	nonceErr   error
+	batchSizes []int64
+}
+
+func (c *poolBackendClient) nextNonce() string {
+	c.generated++
+	return fmt.Sprintf("%s-%d", c.name, c.generated)
+}
+
+func (c *poolBackendClient) batches() []int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]int64{}, c.batchSizes...)
+}
+
+func (c *poolBackendClient) Nonce(_ context.Context, _ *corepb.Empty, _ ...grpc.CallOption) (*noncepb.NonceMessage, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.nonceErr != nil {
+		return nil, c.nonceErr
+	}
+	c.singles++
+	return &noncepb.NonceMessage{Nonce: c.nextNonce()}, nil
+}
+
+func (c *poolBackendClient) Redeem(_ context.Context, _ *noncepb.NonceMessage, _ ...grpc.CallOption) (*noncepb.ValidMessage, error) {
+	return nil, errors.New("unimplemented")
+}
+
+func (c *poolBackendClient) NonceStream(_ context.Context, req *noncepb.NonceStreamRequest, _ ...grpc.CallOption) (noncepb.NonceService_NonceStreamClient, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.streamErr != nil {
+		return nil, c.streamErr
+	}
+	c.batchSizes = append(c.batchSizes, req.Count)
+	stream := &fakeNonceStream{}
+	for i := int64(0); i < req.Count; i++ {
+		stream.nonces = append(stream.nonces, c.nextNonce())
+	}
+	return stream, nil
+}
+
+type fakeNonceStream struct {
+	grpc.ClientStream
+	nonces []string
+}
+
+func (s *fakeNonceStream) Recv() (*noncepb.NonceMessage, error) {
+	if len(s.nonces) == 0 {
+		return nil, io.EOF
+	}
+	msg := &noncepb.NonceMessage{Nonce: s.nonces[0]}
+	s.nonces = s.nonces[1:]
+	return msg, nil
+}
+
+// waitForPool waits for each of the pool's backends to hold n nonces.
+func waitForPool(t *testing.T, p *Pool, n int) {
+	t.Helper()
+	for i := 0; i < 200; i++ {
+		full := true
+		for _, b := range p.backends {
+			if len(b.nonces) != n {
+				full = false
+			}
+		}
+		if full {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("nonce pool wasn't filled to %d nonces per backend", n)
+}
+
+func TestPoolNonce(t *testing.T) {
+	a := &poolBackendClient{name: "a"}
+	b := &poolBackendClient{name: "b"}
+	p := NewPool([]noncepb.NonceServiceClient{a, b}, PoolConfig{Size: 4, BatchSize: 2}, clock.NewFake(), metrics.NoopRegisterer, blog.NewMock())
+	defer p.Stop()
+	waitForPool(t, p, 4)
+	test.AssertDeepEquals(t, a.batches(), []int64{2, 2})
+
+	// Nonces are taken from each backend's pool in turn.
+	seen := map[string]bool{}
+	for i := 0; i < 4; i++ {
+		nonce, err := p.Nonce(context.Background())
+		test.AssertNotError(t, err, "getting nonce")
+		seen[nonce] = true
+	}
+	test.AssertDeepEquals(t, seen, map[string]bool{"a-1": true, "a-2": true, "b-1": true, "b-2": true})
+	test.AssertEquals(t, test.CountCounterVec("result", "pooled", p.gets), 4)
+	test.AssertEquals(t, a.singles+b.singles, 0)
+
+	// Once there's room for a batch the pools are refilled.
+	waitForPool(t, p, 4)
+	test.AssertDeepEquals(t, a.batches(), []int64{2, 2, 2})
+}
+
+func TestPoolExpiry(t *testing.T) {
+	a := &poolBackendClient{name: "a"}
+	clk := clock.NewFake()
+	p := NewPool([]noncepb.NonceServiceClient{a}, PoolConfig{Size: 2, BatchSize: 2, MaxAge: time.Minute}, clk, metrics.NoopRegisterer, blog.NewMock())
+	defer p.Stop()
+	waitForPool(t, p, 2)
+
+	// Stale nonces are discarded, and a nonce fetched on demand instead.
+	clk.Add(time.Minute + time.Second)
+	_, err := p.Nonce(context.Background())
+	test.AssertNotError(t, err, "getting nonce")
+	test.AssertEquals(t, test.CountCounter(p.expired), 2)
+	test.AssertEquals(t, test.CountCounterVec("result", "fetched", p.gets), 1)
+
+	// Discarding them makes room for a fresh batch.
+	waitForPool(t, p, 2)
+	_, err = p.Nonce(context.Background())
+	test.AssertNotError(t, err, "getting nonce")
+	test.AssertEquals(t, test.CountCounterVec("result", "pooled", p.gets), 1)
+	test.AssertDeepEquals(t, a.batches(), []int64{2, 2})
+}
+
+func TestPoolFallback(t *testing.T) {
+	down := &poolBackendClient{name: "down", streamErr: errors.New("unavailable"), nonceErr: errors.New("unavailable")}
+	streamless := &poolBackendClient{name: "streamless", streamErr: errors.New("unimplemented")}
+	log := blog.NewMock()
+	p := NewPool([]noncepb.NonceServiceClient{down, streamless}, PoolConfig{Size: 2}, clock.NewFake(), metrics.NoopRegisterer, log)
+	defer p.Stop()
+
+	// With the pools empty, nonces are fetched one at a time from whichever
+	// backend is up.
+	for i := 0; i < 2; i++ {
+		nonce, err := p.Nonce(context.Background())
+		test.AssertNotError(t, err, "getting nonce")
+		test.AssertEquals(t, nonce, fmt.Sprintf("streamless-%d", i+1))
+	}
+	test.AssertEquals(t, test.CountCounterVec("result", "fetched", p.gets), 2)
+
+	streamless.mu.Lock()
+	streamless.nonceErr = errors.New("unavailable")
+	streamless.mu.Unlock()
+	_, err := p.Nonce(context.Background())
+	test.AssertError(t, err, "got a nonce with every backend down")
+	test.AssertEquals(t, test.CountCounterVec("result", "failed", p.gets), 1)
+}
+
+func TestPoolRetry(t *testing.T) {
+	b := &poolBackendClient{name: "b", streamErr: errors.New("unavailable")}
+	clk := clock.NewFake()
+	p := NewPool([]noncepb.NonceServiceClient{b}, PoolConfig{Size: 2, BatchSize: 2}, clk, metrics.NoopRegisterer, blog.NewMock())
+	defer p.Stop()
+
+	// A backend which failed is only tried again once the retry interval has
+	// passed on the Pool's clock.
+	for i := 0; i < 200 && test.CountCounterVec("result", "failure", p.fetches) == 0; i++ {
+		time.Sleep(5 * time.Millisecond)
+	}
+	b.mu.Lock()
+	b.streamErr = nil
+	b.mu.Unlock()
+	time.Sleep(50 * time.Millisecond)
+	test.AssertEquals(t, len(b.batches()), 0)
+	for i := 0; i < 200 && len(b.batches()) == 0; i++ {
+		clk.Add(poolRetryInterval)
+		time.Sleep(5 * time.Millisecond)
+	}
+	waitForPool(t, p, 2)
+}
@@ -30,6 +30,53 @@ const (
 // of the legacy proto package is being used.
 const _ = proto.ProtoPackageIsVersion4
 
+type NonceStreamRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Count int64 `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
+}
+
+func (x *NonceStreamRequest) Reset() {
+	*x = NonceStreamRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_nonce_proto_nonce_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *NonceStreamRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NonceStreamRequest) ProtoMessage() {}
+
+func (x *NonceStreamRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_nonce_proto_nonce_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NonceStreamRequest.ProtoReflect.Descriptor instead.
+func (*NonceStreamRequest) Descriptor() ([]byte, []int) {
+	return file_nonce_proto_nonce_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *NonceStreamRequest) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
 type NonceMessage struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -41,7 +88,7 @@ type NonceMessage struct {
 func (x *NonceMessage) Reset() {
 	*x = NonceMessage{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_nonce_proto_nonce_proto_msgTypes[0]
+		mi := &file_nonce_proto_nonce_proto_msgTypes[1]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -54,7 +101,7 @@ func (x *NonceMessage) String() string {
 func (*NonceMessage) ProtoMessage() {}
 
 func (x *NonceMessage) ProtoReflect() protoreflect.Message {
-	mi := &file_nonce_proto_nonce_proto_msgTypes[0]
+	mi := &file_nonce_proto_nonce_proto_msgTypes[1]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -67,7 +114,7 @@ func (x *NonceMessage) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use NonceMessage.ProtoReflect.Descriptor instead.
 func (*NonceMessage) Descriptor() ([]byte, []int) {
-	return file_nonce_proto_nonce_proto_rawDescGZIP(), []int{0}
+	return file_nonce_proto_nonce_proto_rawDescGZIP(), []int{1}
 }
 
 func (x *NonceMessage) GetNonce() string {
@@ -88,7 +135,7 @@ type ValidMessage struct {
 func (x *ValidMessage) Reset() {
 	*x = ValidMessage{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_nonce_proto_nonce_proto_msgTypes[1]
+		mi := &file_nonce_proto_nonce_proto_msgTypes[2]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -101,7 +148,7 @@ func (x *ValidMessage) String() string {
 func (*ValidMessage) ProtoMessage() {}
 
 func (x *ValidMessage) ProtoReflect() protoreflect.Message {
-	mi := &file_nonce_proto_nonce_proto_msgTypes[1]
+	mi := &file_nonce_proto_nonce_proto_msgTypes[2]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -114,7 +161,7 @@ func (x *ValidMessage) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ValidMessage.ProtoReflect.Descriptor instead.
 func (*ValidMessage) Descriptor() ([]byte, []int) {
-	return file_nonce_proto_nonce_proto_rawDescGZIP(), []int{1}
+	return file_nonce_proto_nonce_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *ValidMessage) GetValid() bool {
@@ -130,19 +177,26 @@ var file_nonce_proto_nonce_proto_rawDesc = []byte{
 	0x0a, 0x17, 0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x6e, 0x6f,
 	0x6e, 0x63, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x05, 0x6e, 0x6f, 0x6e, 0x63, 0x65,
 	0x1a, 0x15, 0x63, 0x6f, 0x72, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x63, 0x6f, 0x72,
-	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x24, 0x0a, 0x0c, 0x4e, 0x6f, 0x6e, 0x63, 0x65,
-	0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x6e, 0x6f, 0x6e, 0x63, 0x65,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x22, 0x24, 0x0a,
-	0x0c, 0x56, 0x61, 0x6c, 0x69, 0x64, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x14, 0x0a,
-	0x05, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x76, 0x61,
-	0x6c, 0x69, 0x64, 0x32, 0x71, 0x0a, 0x0c, 0x4e, 0x6f, 0x6e, 0x63, 0x65, 0x53, 0x65, 0x72, 0x76,
-	0x69, 0x63, 0x65, 0x12, 0x2b, 0x0a, 0x05, 0x4e, 0x6f, 0x6e, 0x63, 0x65, 0x12, 0x0b, 0x2e, 0x63,
-	0x6f, 0x72, 0x65, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x13, 0x2e, 0x6e, 0x6f, 0x6e, 0x63,
-	0x65, 0x2e, 0x4e, 0x6f, 0x6e, 0x63, 0x65, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x00,
-	0x12, 0x34, 0x0a, 0x06, 0x52, 0x65, 0x64, 0x65, 0x65, 0x6d, 0x12, 0x13, 0x2e, 0x6e, 0x6f, 0x6e,
-	0x63, 0x65, 0x2e, 0x4e, 0x6f, 0x6e, 0x63, 0x65, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x1a,
-	0x13, 0x2e, 0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x2e, 0x56, 0x61, 0x6c, 0x69, 0x64, 0x4d, 0x65, 0x73,
-	0x73, 0x61, 0x67, 0x65, 0x22, 0x00, 0x42, 0x2c, 0x5a, 0x2a, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x2a, 0x0a, 0x12, 0x4e, 0x6f, 0x6e, 0x63, 0x65,
+	0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a,
+	0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x63, 0x6f,
+	0x75, 0x6e, 0x74, 0x22, 0x24, 0x0a, 0x0c, 0x4e, 0x6f, 0x6e, 0x63, 0x65, 0x4d, 0x65, 0x73, 0x73,
+	0x61, 0x67, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x05, 0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x22, 0x24, 0x0a, 0x0c, 0x56, 0x61, 0x6c,
+	0x69, 0x64, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c,
+	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x32,
+	0xb4, 0x01, 0x0a, 0x0c, 0x4e, 0x6f, 0x6e, 0x63, 0x65, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
+	0x12, 0x2b, 0x0a, 0x05, 0x4e, 0x6f, 0x6e, 0x63, 0x65, 0x12, 0x0b, 0x2e, 0x63, 0x6f, 0x72, 0x65,
+	0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x13, 0x2e, 0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x2e, 0x4e,
+	0x6f, 0x6e, 0x63, 0x65, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x00, 0x12, 0x34, 0x0a,
+	0x06, 0x52, 0x65, 0x64, 0x65, 0x65, 0x6d, 0x12, 0x13, 0x2e, 0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x2e,
+	0x4e, 0x6f, 0x6e, 0x63, 0x65, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x1a, 0x13, 0x2e, 0x6e,
+	0x6f, 0x6e, 0x63, 0x65, 0x2e, 0x56, 0x61, 0x6c, 0x69, 0x64, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x22, 0x00, 0x12, 0x41, 0x0a, 0x0b, 0x4e, 0x6f, 0x6e, 0x63, 0x65, 0x53, 0x74, 0x72, 0x65,
+	0x61, 0x6d, 0x12, 0x19, 0x2e, 0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x2e, 0x4e, 0x6f, 0x6e, 0x63, 0x65,
+	0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x13, 0x2e,
+	0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x2e, 0x4e, 0x6f, 0x6e, 0x63, 0x65, 0x4d, 0x65, 0x73, 0x73, 0x61,
+	0x67, 0x65, 0x22, 0x00, 0x30, 0x01, 0x42, 0x2c, 0x5a, 0x2a, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62,
 	0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6c, 0x65, 0x74, 0x73, 0x65, 0x6e, 0x63, 0x72, 0x79, 0x70, 0x74,
 	0x2f, 0x62, 0x6f, 0x75, 0x6c, 0x64, 0x65, 0x72, 0x2f, 0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x2f, 0x70,
 	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
@@ -160,19 +214,22 @@ func file_nonce_proto_nonce_proto_rawDescGZIP() []byte {
 	return file_nonce_proto_nonce_proto_rawDescData
 }
 
-var file_nonce_proto_nonce_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_nonce_proto_nonce_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
 var file_nonce_proto_nonce_proto_goTypes = []interface{}{
-	(*NonceMessage)(nil), // 0: nonce.NonceMessage
-	(*ValidMessage)(nil), // 1: nonce.ValidMessage
-	(*proto1.Empty)(nil), // 2: core.Empty
+	(*NonceStreamRequest)(nil), // 0: nonce.NonceStreamRequest
+	(*NonceMessage)(nil),       // 1: nonce.NonceMessage
+	(*ValidMessage)(nil),       // 2: nonce.ValidMessage
+	(*proto1.Empty)(nil),       // 3: core.Empty
 }
 var file_nonce_proto_nonce_proto_depIdxs = []int32{
-	2, // 0: nonce.NonceService.Nonce:input_type -> core.Empty
-	0, // 1: nonce.NonceService.Redeem:input_type -> nonce.NonceMessage
-	0, // 2: nonce.NonceService.Nonce:output_type -> nonce.NonceMessage
-	1, // 3: nonce.NonceService.Redeem:output_type -> nonce.ValidMessage
-	2, // [2:4] is the sub-list for method output_type
-	0, // [0:2] is the sub-list for method input_type
+	3, // 0: nonce.NonceService.Nonce:input_type -> core.Empty
+	1, // 1: nonce.NonceService.Redeem:input_type -> nonce.NonceMessage
+	0, // 2: nonce.NonceService.NonceStream:input_type -> nonce.NonceStreamRequest
+	1, // 3: nonce.NonceService.Nonce:output_type -> nonce.NonceMessage
+	2, // 4: nonce.NonceService.Redeem:output_type -> nonce.ValidMessage
+	1, // 5: nonce.NonceService.NonceStream:output_type -> nonce.NonceMessage
+	3, // [3:6] is the sub-list for method output_type
+	0, // [0:3] is the sub-list for method input_type
 	0, // [0:0] is the sub-list for extension type_name
 	0, // [0:0] is the sub-list for extension extendee
 	0, // [0:0] is the sub-list for field type_name
@@ -185,7 +242,7 @@ func file_nonce_proto_nonce_proto_init() {
 	}
 	if !protoimpl.UnsafeEnabled {
 		file_nonce_proto_nonce_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*NonceMessage); i {
+			switch v := v.(*NonceStreamRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -197,6 +254,18 @@ func file_nonce_proto_nonce_proto_init() {
 			}
 		}
 		file_nonce_proto_nonce_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*NonceMessage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_nonce_proto_nonce_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*ValidMessage); i {
 			case 0:
 				return &v.state
@@ -215,7 +284,7 @@ func file_nonce_proto_nonce_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_nonce_proto_nonce_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   2,
+			NumMessages:   3,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -243,6 +312,9 @@ const _ = grpc.SupportPackageIsVersion6
 type NonceServiceClient interface {
 	Nonce(ctx context.Context, in *proto1.Empty, opts ...grpc.CallOption) (*NonceMessage, error)
 	Redeem(ctx context.Context, in *NonceMessage, opts ...grpc.CallOption) (*ValidMessage, error)
+	// NonceStream sends a batch of count nonces, for clients which keep a pool
+	// of them.
+	NonceStream(ctx context.Context, in *NonceStreamRequest, opts ...grpc.CallOption) (NonceService_NonceStreamClient, error)
 }
 
 type nonceServiceClient struct {
@@ -271,10 +343,45 @@ func (c *nonceServiceClient) Redeem(ctx context.Context, in *NonceMessage, opts
 	return out, nil
 }
 
+func (c *nonceServiceClient) NonceStream(ctx context.Context, in *NonceStreamRequest, opts ...grpc.CallOption) (NonceService_NonceStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_NonceService_serviceDesc.Streams[0], "/nonce.NonceService/NonceStream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &nonceServiceNonceStreamClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type NonceService_NonceStreamClient interface {
+	Recv() (*NonceMessage, error)
+	grpc.ClientStream
+}
+
+type nonceServiceNonceStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *nonceServiceNonceStreamClient) Recv() (*NonceMessage, error) {
+	m := new(NonceMessage)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // NonceServiceServer is the server API for NonceService service.
 type NonceServiceServer interface {
 	Nonce(context.Context, *proto1.Empty) (*NonceMessage, error)
 	Redeem(context.Context, *NonceMessage) (*ValidMessage, error)
+	// NonceStream sends a batch of count nonces, for clients which keep a pool
+	// of them.
+	NonceStream(*NonceStreamRequest, NonceService_NonceStreamServer) error
 }
 
 // UnimplementedNonceServiceServer can be embedded to have forward compatible implementations.
@@ -287,6 +394,9 @@ func (*UnimplementedNonceServiceServer) Nonce(context.Context, *proto1.Empty) (*
 func (*UnimplementedNonceServiceServer) Redeem(context.Context, *NonceMessage) (*ValidMessage, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Redeem not implemented")
 }
+func (*UnimplementedNonceServiceServer) NonceStream(*NonceStreamRequest, NonceService_NonceStreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method NonceStream not implemented")
+}
 
 func RegisterNonceServiceServer(s *grpc.Server, srv NonceServiceServer) {
 	s.RegisterService(&_NonceService_serviceDesc, srv)
@@ -328,6 +438,27 @@ func _NonceService_Redeem_Handler(srv interface{}, ctx context.Context, dec func
 	return interceptor(ctx, in, info, handler)
 }
 
+func _NonceService_NonceStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(NonceStreamRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(NonceServiceServer).NonceStream(m, &nonceServiceNonceStreamServer{stream})
+}
+
+type NonceService_NonceStreamServer interface {
+	Send(*NonceMessage) error
+	grpc.ServerStream
+}
+
+type nonceServiceNonceStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *nonceServiceNonceStreamServer) Send(m *NonceMessage) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 var _NonceService_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "nonce.NonceService",
 	HandlerType: (*NonceServiceServer)(nil),
@@ -341,6 +472,12 @@ var _NonceService_serviceDesc = grpc.ServiceDesc{
 			Handler:    _NonceService_Redeem_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "NonceStream",
+			Handler:       _NonceService_NonceStream_Handler,
+			ServerStreams: true,
+		},
+	},
 	Metadata: "nonce/proto/nonce.proto",
 }
@@ -0,0 +1,112 @@
+// Package objectstore reads and writes pre-signed OCSP responses in an S3 or
+// GCS compatible bucket over HTTPS, in which each response is stored as an
+// object named by the hex serial of its certificate.
+package objectstore
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/jmhodges/clock"
+)
+
+// Credentials authenticate requests to a bucket. If AccessKeyID is set,
+// requests are signed with AWS Signature Version 4 using Secret as the secret
+// access key, which S3 and other S3 compatible stores require. Otherwise, if
+// Secret is set it's sent as a bearer token. If neither is set, requests are
+// unauthenticated.
+type Credentials struct {
+	AccessKeyID string
+	Secret      string
+	// Region is the region the bucket is in, which is part of the SigV4
+	// signature. It defaults to "us-east-1".
+	Region string
+}
+
+// Client reads and writes the objects in a bucket. It's safe for concurrent
+// use.
+type Client struct {
+	baseURL string
+	creds   Credentials
+	client  *http.Client
+	clk     clock.Clock
+}
+
+// New returns a Client for the bucket at baseURL, which includes any path
+// prefix to which object names are appended, for example
+// "https://example-bucket.s3.amazonaws.com/ocsp".
+func New(baseURL string, creds Credentials, client *http.Client, clk clock.Clock) (*Client, error) {
+	if baseURL == "" {
+		return nil, fmt.Errorf("bucket URL must be set")
+	}
+	if creds.AccessKeyID != "" && creds.Secret == "" {
+		return nil, fmt.Errorf("a secret access key must be set with an access key ID")
+	}
+	if creds.Region == "" {
+		creds.Region = "us-east-1"
+	}
+	return &Client{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		creds:   creds,
+		client:  client,
+		clk:     clk,
+	}, nil
+}
+
+// newRequest returns an authenticated request for the named object. Any
+// headers must be added by setHeaders, so that they're covered by the
+// signature.
+func (c *Client) newRequest(method, name string, body []byte, setHeaders func(http.Header)) (*http.Request, error) {
+	req, err := http.NewRequest(method, c.baseURL+"/"+name, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if setHeaders != nil {
+		setHeaders(req.Header)
+	}
+	switch {
+	case c.creds.AccessKeyID != "":
+		signV4(req, body, c.creds, c.clk.Now())
+	case c.creds.Secret != "":
+		req.Header.Set("Authorization", "Bearer "+c.creds.Secret)
+	}
+	return req, nil
+}
+
+// Get fetches the named object. If etag isn't empty the request is made
+// conditional on it, so a 304 Not Modified response is returned if the object
+// hasn't changed. The caller must close the response's body.
+func (c *Client) Get(name, etag string) (*http.Response, error) {
+	req, err := c.newRequest("GET", name, nil, func(h http.Header) {
+		if etag != "" {
+			h.Set("If-None-Match", etag)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return c.client.Do(req)
+}
+
+// Put writes body to the named object, replacing it if it exists.
+func (c *Client) Put(name string, body []byte, contentType string) error {
+	req, err := c.newRequest("PUT", name, body, func(h http.Header) {
+		h.Set("Content-Type", contentType)
+	})
+	if err != nil {
+		return err
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusNoContent {
+		msg, _ := ioutil.ReadAll(http.MaxBytesReader(nil, resp.Body, 1024))
+		return fmt.Errorf("writing object %q: unexpected status %d: %s", name, resp.StatusCode, msg)
+	}
+	return nil
+}
@@ -23,6 +23,7 @@ type PKCtx interface {
 	FindObjectsInit(sh pkcs11.SessionHandle, temp []*pkcs11.Attribute) error
 	FindObjects(sh pkcs11.SessionHandle, max int) ([]pkcs11.ObjectHandle, bool, error)
 	FindObjectsFinal(sh pkcs11.SessionHandle) error
+	WrapKey(sh pkcs11.SessionHandle, m []*pkcs11.Mechanism, wrappingKey, key pkcs11.ObjectHandle) ([]byte, error)
 }
 
 // Session represents a session with a given PKCS#11 module. It is not safe for
@@ -405,6 +406,32 @@ func (s *Session) NewSigner(label string, publicKey crypto.PublicKey) (crypto.Si
 	}, nil
 }
 
+// WrapPrivateKey exports the private key object associated with the given
+// label and public key, encrypted under the secret key object labelled
+// wrappingLabel using the AES key wrap with padding mechanism. The private key
+// must have been generated as extractable, and the wrapping key must be
+// permitted to wrap it.
+func (s *Session) WrapPrivateKey(label string, publicKey crypto.PublicKey, wrappingLabel string) ([]byte, error) {
+	publicKeyID, err := s.getPublicKeyID(label, publicKey)
+	if err != nil {
+		return nil, fmt.Errorf("looking up public key: %s", err)
+	}
+	privateKeyHandle, err := s.getPrivateKey(publicKeyID)
+	if err != nil {
+		return nil, fmt.Errorf("getting private key: %s", err)
+	}
+	wrappingKeyHandle, err := s.FindObject([]*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, pkcs11.CKO_SECRET_KEY),
+		pkcs11.NewAttribute(pkcs11.CKA_LABEL, []byte(wrappingLabel)),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("getting wrapping key: %s", err)
+	}
+	return s.Module.WrapKey(s.Session, []*pkcs11.Mechanism{
+		pkcs11.NewMechanism(pkcs11.CKM_AES_KEY_WRAP_PAD, nil),
+	}, wrappingKeyHandle, privateKeyHandle)
+}
+
 func NewMock() *MockCtx {
 	return &MockCtx{}
 }
@@ -423,6 +450,7 @@ type MockCtx struct {
 	FindObjectsInitFunc   func(sh pkcs11.SessionHandle, temp []*pkcs11.Attribute) error
 	FindObjectsFunc       func(sh pkcs11.SessionHandle, max int) ([]pkcs11.ObjectHandle, bool, error)
 	FindObjectsFinalFunc  func(sh pkcs11.SessionHandle) error
+	WrapKeyFunc           func(sh pkcs11.SessionHandle, m []*pkcs11.Mechanism, wrappingKey, key pkcs11.ObjectHandle) ([]byte, error)
 }
 
 func (mc MockCtx) GenerateKeyPair(s pkcs11.SessionHandle, m []*pkcs11.Mechanism, a1 []*pkcs11.Attribute, a2 []*pkcs11.Attribute) (pkcs11.ObjectHandle, pkcs11.ObjectHandle, error) {
@@ -456,3 +484,7 @@ func (mc MockCtx) FindObjects(sh pkcs11.SessionHandle, max int) ([]pkcs11.Object
 func (mc MockCtx) FindObjectsFinal(sh pkcs11.SessionHandle) error {
 	return mc.FindObjectsFinalFunc(sh)
 }
+
+func (mc MockCtx) WrapKey(sh pkcs11.SessionHandle, m []*pkcs11.Mechanism, wrappingKey, key pkcs11.ObjectHandle) ([]byte, error) {
+	return mc.WrapKeyFunc(sh, m, wrappingKey, key)
+}
@@ -418,3 +418,48 @@ func TestGetKeySucceeds(t *testing.T) {
 	_, err := s.NewSigner("label", pubKey)
 	test.AssertNotError(t, err, "newSigner failed when everything worked properly")
 }
+
+func TestWrapPrivateKey(t *testing.T) {
+	s, ctx := newSessionWithMock()
+	pubKey := &rsa.PublicKey{N: big.NewInt(1), E: 1}
+	ctx.GetAttributeValueFunc = func(_ pkcs11.SessionHandle, _ pkcs11.ObjectHandle, attrs []*pkcs11.Attribute) ([]*pkcs11.Attribute, error) {
+		return []*pkcs11.Attribute{pkcs11.NewAttribute(pkcs11.CKA_ID, []byte{99})}, nil
+	}
+	handles := map[string]pkcs11.ObjectHandle{"label": 1, "wrapping": 2}
+	var found pkcs11.ObjectHandle
+	ctx.FindObjectsInitFunc = func(_ pkcs11.SessionHandle, attrs []*pkcs11.Attribute) error {
+		found = 0
+		for _, a := range attrs {
+			if a.Type == pkcs11.CKA_LABEL {
+				found = handles[string(a.Value)]
+			}
+			if a.Type == pkcs11.CKA_CLASS && bytes.Equal(a.Value, pkcs11.NewAttribute(pkcs11.CKA_CLASS, pkcs11.CKO_PRIVATE_KEY).Value) {
+				found = 3
+			}
+		}
+		return nil
+	}
+	ctx.FindObjectsFunc = func(_ pkcs11.SessionHandle, _ int) ([]pkcs11.ObjectHandle, bool, error) {
+		if found == 0 {
+			return nil, false, nil
+		}
+		return []pkcs11.ObjectHandle{found}, false, nil
+	}
+
+	// test WrapPrivateKey fails when the wrapping key can't be found
+	_, err := s.WrapPrivateKey("label", pubKey, "missing")
+	test.AssertError(t, err, "WrapPrivateKey didn't fail when wrapping key was missing")
+	test.AssertContains(t, err.Error(), "getting wrapping key")
+
+	// test WrapPrivateKey wraps the private key under the wrapping key
+	ctx.WrapKeyFunc = func(_ pkcs11.SessionHandle, m []*pkcs11.Mechanism, wrappingKey, key pkcs11.ObjectHandle) ([]byte, error) {
+		test.AssertEquals(t, len(m), 1)
+		test.AssertEquals(t, m[0].Mechanism, uint(pkcs11.CKM_AES_KEY_WRAP_PAD))
+		test.AssertEquals(t, wrappingKey, pkcs11.ObjectHandle(2))
+		test.AssertEquals(t, key, pkcs11.ObjectHandle(3))
+		return []byte("wrapped"), nil
+	}
+	wrapped, err := s.WrapPrivateKey("label", pubKey, "wrapping")
+	test.AssertNotError(t, err, "WrapPrivateKey failed when everything worked properly")
+	test.AssertByteEquals(t, wrapped, []byte("wrapped"))
+}
@@ -1,6 +1,7 @@
 package policy
 
 import (
+	"context"
 	"crypto/sha256"
 	"encoding/hex"
 	"errors"
@@ -11,16 +12,21 @@ import (
 	"regexp"
 	"strings"
 	"sync"
+	"time"
 
 	"golang.org/x/net/idna"
 	"golang.org/x/text/unicode/norm"
 
 	"github.com/letsencrypt/boulder/core"
+	corepb "github.com/letsencrypt/boulder/core/proto"
 	berrors "github.com/letsencrypt/boulder/errors"
+	"github.com/letsencrypt/boulder/features"
 	"github.com/letsencrypt/boulder/iana"
 	"github.com/letsencrypt/boulder/identifier"
 	blog "github.com/letsencrypt/boulder/log"
+	policypb "github.com/letsencrypt/boulder/policy/proto"
 	"github.com/letsencrypt/boulder/reloader"
+	sapb "github.com/letsencrypt/boulder/sa/proto"
 	"gopkg.in/yaml.v2"
 )
 
@@ -31,7 +37,17 @@ type AuthorityImpl struct {
 	blocklist              map[string]bool
 	exactBlocklist         map[string]bool
 	wildcardExactBlocklist map[string]bool
-	blocklistMu            sync.RWMutex
+	// policyVersion is the version of the hostname policy loaded from the SA,
+	// or 0 if the policy was loaded from a file.
+	policyVersion int64
+	blocklistMu   sync.RWMutex
+
+	// policySource, if set, is where newer versions of the hostname policy
+	// are loaded from, and refreshMu serializes loading them.
+	policySource HostnamePolicyGetter
+	refreshMu    sync.Mutex
+
+	wildcards WildcardPolicy
 
 	enabledChallenges map[core.AcmeChallenge]bool
 	pseudoRNG         *rand.Rand
@@ -73,8 +89,84 @@ type blockedNamesPolicy struct {
 	AdminBlockedNames []string `yaml:"AdminBlockedNames"`
 }
 
+// WildcardPolicy constrains issuance for wildcard names, beyond the checks
+// WillingToIssueWildcards always makes.
+type WildcardPolicy struct {
+	// MaxDepth, if non-zero, is the greatest number of labels, including the
+	// wildcard label, which a wildcard name may have in front of its
+	// registered domain. For example, "*.example.com" has a depth of 1 and
+	// "*.www.example.com" has a depth of 2.
+	MaxDepth int
+	// ForbiddenDomains are registered domains, like "example.com", under
+	// which no wildcard name may be issued.
+	ForbiddenDomains []string
+	// RequireRevalidation makes every wildcard name in a new order need a new
+	// authorization, validated with DNS-01, rather than reusing a valid one.
+	RequireRevalidation bool
+}
+
+// SetWildcardPolicy sets the constraints on wildcard names. It must be called
+// before the PA is used.
+func (pa *AuthorityImpl) SetWildcardPolicy(wp WildcardPolicy) error {
+	if wp.MaxDepth < 0 {
+		return fmt.Errorf("wildcard MaxDepth must not be negative, got %d", wp.MaxDepth)
+	}
+	forbidden := make([]string, 0, len(wp.ForbiddenDomains))
+	for _, domain := range wp.ForbiddenDomains {
+		domain = strings.ToLower(domain)
+		registered, _, err := registeredDomain(domain)
+		if err != nil {
+			return fmt.Errorf("forbidden wildcard domain %q: %s", domain, err)
+		}
+		if registered != domain {
+			return fmt.Errorf("forbidden wildcard domain %q is not a registered domain, did you mean %q?", domain, registered)
+		}
+		forbidden = append(forbidden, domain)
+	}
+	wp.ForbiddenDomains = forbidden
+	pa.wildcards = wp
+	return nil
+}
+
+// RequireWildcardRevalidation returns true if valid authorizations for
+// wildcard names mustn't be reused for new orders.
+func (pa *AuthorityImpl) RequireWildcardRevalidation() bool {
+	return pa.wildcards.RequireRevalidation
+}
+
+// RegisteredDomain returns the registered domain of a DNS name, which is its
+// public suffix and the label in front of it. For example, the registered
+// domain of "www.example.co.uk" is "example.co.uk".
+func RegisteredDomain(domain string) (string, error) {
+	registered, _, err := registeredDomain(strings.TrimPrefix(domain, "*."))
+	return registered, err
+}
+
+// registeredDomain returns the registered domain of a name, which is its
+// public suffix and the label in front of it, and the number of labels in
+// front of that.
+func registeredDomain(domain string) (string, int, error) {
+	suffix, err := iana.ExtractSuffix(domain)
+	if err != nil {
+		return "", 0, err
+	}
+	if domain == suffix {
+		return "", 0, fmt.Errorf("%q is a public suffix", domain)
+	}
+	labels := strings.Split(strings.TrimSuffix(domain, "."+suffix), ".")
+	return labels[len(labels)-1] + "." + suffix, len(labels) - 1, nil
+}
+
+// HostnamePolicyGetter is the part of the SA which the PA loads versions of
+// the hostname policy from.
+type HostnamePolicyGetter interface {
+	GetHostnamePolicy(ctx context.Context, req *sapb.GetHostnamePolicyRequest) (*sapb.HostnamePolicy, error)
+}
+
 // SetHostnamePolicyFile will load the given policy file, returning error if it
-// fails. It will also start a reloader in case the file changes
+// fails. It will also start a reloader in case the file changes. Once a policy
+// has been loaded from the SA (see SetHostnamePolicySource), changes to the
+// file are ignored.
 func (pa *AuthorityImpl) SetHostnamePolicyFile(f string) error {
 	if _, err := reloader.New(f, pa.loadHostnamePolicy, pa.hostnamePolicyLoadError); err != nil {
 		return err
@@ -89,6 +181,10 @@ func (pa *AuthorityImpl) hostnamePolicyLoadError(err error) {
 // loadHostnamePolicy is a callback suitable for use with reloader.New() that
 // will unmarshal a YAML hostname policy.
 func (pa *AuthorityImpl) loadHostnamePolicy(contents []byte) error {
+	if version := pa.HostnamePolicyVersion(); version != 0 {
+		pa.log.Infof("ignoring hostname policy file, using version %d of the policy from the SA", version)
+		return nil
+	}
 	hash := sha256.Sum256(contents)
 	pa.log.Infof("loading hostname policy, sha256: %s", hex.EncodeToString(hash[:]))
 	var policy blockedNamesPolicy
@@ -96,20 +192,163 @@ func (pa *AuthorityImpl) loadHostnamePolicy(contents []byte) error {
 	if err != nil {
 		return err
 	}
+	err = validateHostnamePolicy(policy)
+	if err != nil {
+		return err
+	}
+	return pa.processHostnamePolicy(policy, 0)
+}
+
+// SetHostnamePolicySource makes the PA load its hostname policy from the SA,
+// now and then every interval, if there's a newer version. Until a version has
+// been loaded, because none has been stored or the SA can't be reached, the
+// policy file set by SetHostnamePolicyFile remains in use.
+func (pa *AuthorityImpl) SetHostnamePolicySource(source HostnamePolicyGetter, interval time.Duration) {
+	pa.refreshMu.Lock()
+	pa.policySource = source
+	pa.refreshMu.Unlock()
+
+	refresh := func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		_, err := pa.RefreshHostnamePolicy(ctx)
+		if err != nil {
+			pa.log.AuditErrf("error loading hostname policy from the SA: %s", err)
+		}
+	}
+	refresh()
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			refresh()
+		}
+	}()
+}
+
+// HostnamePolicyVersion returns the version of the hostname policy in use, or
+// 0 if it was loaded from a file.
+func (pa *AuthorityImpl) HostnamePolicyVersion() int64 {
+	pa.blocklistMu.RLock()
+	defer pa.blocklistMu.RUnlock()
+	return pa.policyVersion
+}
+
+// RefreshHostnamePolicy loads the latest version of the hostname policy from
+// the SA, if it's newer than the one in use, and returns the version in use
+// afterwards.
+func (pa *AuthorityImpl) RefreshHostnamePolicy(ctx context.Context) (int64, error) {
+	pa.refreshMu.Lock()
+	defer pa.refreshMu.Unlock()
+	if pa.policySource == nil {
+		return 0, errors.New("hostname policy isn't loaded from the SA")
+	}
+	known := pa.HostnamePolicyVersion()
+	resp, err := pa.policySource.GetHostnamePolicy(ctx, &sapb.GetHostnamePolicyRequest{KnownVersion: known})
+	if err != nil {
+		if errors.Is(err, berrors.NotFound) {
+			// No policy has been stored yet, so keep using the file.
+			return known, nil
+		}
+		return known, err
+	}
+	if resp.Version == known {
+		return known, nil
+	}
+	policy := blockedNamesPolicy{
+		ExactBlockedNames:    resp.ExactBlockedNames,
+		HighRiskBlockedNames: resp.HighRiskBlockedNames,
+		AdminBlockedNames:    resp.AdminBlockedNames,
+	}
+	err = validateHostnamePolicy(policy)
+	if err == nil {
+		err = pa.processHostnamePolicy(policy, resp.Version)
+	}
+	if err != nil {
+		return known, fmt.Errorf("hostname policy version %d is invalid: %s", resp.Version, err)
+	}
+	pa.log.Infof("loaded hostname policy version %d from the SA, created by %s: %s",
+		resp.Version, resp.CreatedBy, resp.Comment)
+	return resp.Version, nil
+}
+
+// RefresherServer serves the HostnamePolicyRefresher gRPC service, which the
+// admin tool calls on every RA and CA after it stores a new version of the
+// hostname policy, so that each loads it now rather than at its next refresh.
+type RefresherServer struct {
+	pa *AuthorityImpl
+}
+
+// NewRefresherServer returns a RefresherServer which refreshes pa's hostname
+// policy.
+func NewRefresherServer(pa *AuthorityImpl) *RefresherServer {
+	return &RefresherServer{pa: pa}
+}
+
+// RefreshHostnamePolicy makes the PA load the latest version of the hostname
+// policy from the SA, and returns the version in use afterwards.
+func (rs *RefresherServer) RefreshHostnamePolicy(ctx context.Context, _ *corepb.Empty) (*policypb.HostnamePolicyVersion, error) {
+	version, err := rs.pa.RefreshHostnamePolicy(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &policypb.HostnamePolicyVersion{Version: version}, nil
+}
+
+// ValidateHostnamePolicy checks that a hostname policy to be stored in the SA
+// could be loaded by the PA.
+func ValidateHostnamePolicy(policy *sapb.HostnamePolicy) error {
+	return validateHostnamePolicy(blockedNamesPolicy{
+		ExactBlockedNames:    policy.ExactBlockedNames,
+		HighRiskBlockedNames: policy.HighRiskBlockedNames,
+		AdminBlockedNames:    policy.AdminBlockedNames,
+	})
+}
+
+// ParseHostnamePolicy parses a hostname policy file, returning it in the form
+// stored in the SA.
+func ParseHostnamePolicy(contents []byte) (*sapb.HostnamePolicy, error) {
+	var policy blockedNamesPolicy
+	err := yaml.Unmarshal(contents, &policy)
+	if err != nil {
+		return nil, err
+	}
+	err = validateHostnamePolicy(policy)
+	if err != nil {
+		return nil, err
+	}
+	return &sapb.HostnamePolicy{
+		ExactBlockedNames:    policy.ExactBlockedNames,
+		HighRiskBlockedNames: policy.HighRiskBlockedNames,
+		AdminBlockedNames:    policy.AdminBlockedNames,
+	}, nil
+}
+
+// validateHostnamePolicy checks that the lists in a hostname policy which must
+// not be empty aren't, and that each exact blocked name has at least two
+// labels.
+func validateHostnamePolicy(policy blockedNamesPolicy) error {
 	if len(policy.HighRiskBlockedNames) == 0 {
 		return fmt.Errorf("No entries in HighRiskBlockedNames.")
 	}
 	if len(policy.ExactBlockedNames) == 0 {
 		return fmt.Errorf("No entries in ExactBlockedNames.")
 	}
-	return pa.processHostnamePolicy(policy)
+	for _, v := range policy.ExactBlockedNames {
+		if !strings.Contains(v, ".") {
+			return fmt.Errorf(
+				"Malformed ExactBlockedNames entry, only one label: %q", v)
+		}
+	}
+	return nil
 }
 
-// processHostnamePolicy handles loading a new blockedNamesPolicy into the PA.
+// processHostnamePolicy handles loading a new blockedNamesPolicy into the PA,
+// recording the version of the policy in the SA which it came from, if any.
 // All of the policy.ExactBlockedNames will be added to the
 // wildcardExactBlocklist by processHostnamePolicy to ensure that wildcards for
 // exact blocked names entries are forbidden.
-func (pa *AuthorityImpl) processHostnamePolicy(policy blockedNamesPolicy) error {
+func (pa *AuthorityImpl) processHostnamePolicy(policy blockedNamesPolicy, version int64) error {
 	nameMap := make(map[string]bool)
 	for _, v := range policy.HighRiskBlockedNames {
 		nameMap[v] = true
@@ -143,6 +382,7 @@ func (pa *AuthorityImpl) processHostnamePolicy(policy blockedNamesPolicy) error
 	pa.blocklist = nameMap
 	pa.exactBlocklist = exactNameMap
 	pa.wildcardExactBlocklist = wildcardNameMap
+	pa.policyVersion = version
 	pa.blocklistMu.Unlock()
 	return nil
 }
@@ -199,8 +439,15 @@ var (
 	errMalformedWildcard    = berrors.MalformedError("Domain name contains an invalid wildcard. A wildcard is only permitted before the first dot in a domain name")
 	errICANNTLDWildcard     = berrors.MalformedError("Domain name is a wildcard for an ICANN TLD")
 	errWildcardNotSupported = berrors.MalformedError("Wildcard domain names are not supported")
+	errWildcardForbidden    = berrors.RejectedIdentifierError("The ACME server refuses to issue a certificate for wildcard names under this domain name, because it is forbidden by policy")
+	errMalformedEmail       = berrors.MalformedError("Email address must be a bare, lowercase ASCII address, like user@example.com")
+	errEmailTooLong         = berrors.MalformedError("Email address is longer than 254 bytes")
 )
 
+// maxEmailLength is the longest email address usable in a forward-path, per
+// RFC 5321 and its errata.
+const maxEmailLength = 254
+
 // ValidDomain checks that a domain isn't:
 //
 // * empty
@@ -350,9 +597,15 @@ func ValidEmail(address string) error {
 //  * MUST NOT be a label-wise suffix match for a name on the block list,
 //    where comparison is case-independent (normalized to lower case)
 //
+// When the EmailIdentifiers feature is enabled, email identifiers are also
+// accepted, if willingToIssueEmail accepts them.
+//
 // If WillingToIssue returns an error, it will be of type MalformedRequestError
 // or RejectedIdentifierError
 func (pa *AuthorityImpl) WillingToIssue(id identifier.ACMEIdentifier) error {
+	if id.Type == identifier.Email && features.Enabled(features.EmailIdentifiers) {
+		return pa.willingToIssueEmail(id.Value)
+	}
 	if id.Type != identifier.DNS {
 		return errInvalidIdentifier
 	}
@@ -370,6 +623,27 @@ func (pa *AuthorityImpl) WillingToIssue(id identifier.ACMEIdentifier) error {
 	return nil
 }
 
+// willingToIssueEmail checks an email identifier. The address must be a bare
+// addr-spec, like "user@example.com", in lowercase ASCII, and its domain must
+// be one that WillingToIssue would accept as a DNS identifier.
+func (pa *AuthorityImpl) willingToIssueEmail(address string) error {
+	if len(address) > maxEmailLength {
+		return errEmailTooLong
+	}
+	if !core.IsASCII(address) || strings.ToLower(address) != address {
+		return errMalformedEmail
+	}
+	parsed, err := mail.ParseAddress(address)
+	if err != nil || parsed.Name != "" || parsed.Address != address {
+		return errMalformedEmail
+	}
+	domain := address[strings.LastIndex(address, "@")+1:]
+	if err := ValidDomain(domain); err != nil {
+		return err
+	}
+	return pa.checkHostLists(domain)
+}
+
 // WillingToIssueWildcards is an extension of WillingToIssue that accepts DNS
 // identifiers for well formed wildcard domains in addition to regular
 // identifiers.
@@ -436,6 +710,10 @@ func (pa *AuthorityImpl) WillingToIssueWildcards(idents []identifier.ACMEIdentif
 // willingToIssueWildcard vets a single identifier. It is used by
 // the plural WillingToIssueWildcards when evaluating a list of identifiers.
 func (pa *AuthorityImpl) willingToIssueWildcard(ident identifier.ACMEIdentifier) error {
+	// Email identifiers can't be wildcards, and are checked by WillingToIssue
+	if ident.Type == identifier.Email {
+		return pa.WillingToIssue(ident)
+	}
 	// We're only willing to process DNS identifiers
 	if ident.Type != identifier.DNS {
 		return errInvalidIdentifier
@@ -468,6 +746,10 @@ func (pa *AuthorityImpl) willingToIssueWildcard(ident identifier.ACMEIdentifier)
 		if baseDomain == icannTLD {
 			return errICANNTLDWildcard
 		}
+		// The wildcard must be permitted by the wildcard policy
+		if err := pa.checkWildcardPolicy(baseDomain); err != nil {
+			return err
+		}
 		// The base domain can't be in the wildcard exact blocklist
 		if err := pa.checkWildcardHostList(baseDomain); err != nil {
 			return err
@@ -490,6 +772,27 @@ func (pa *AuthorityImpl) willingToIssueWildcard(ident identifier.ACMEIdentifier)
 	return pa.WillingToIssue(ident)
 }
 
+// checkWildcardPolicy checks that a wildcard for the given base domain (the
+// wildcard name without its "*." prefix) is permitted by the wildcard policy.
+func (pa *AuthorityImpl) checkWildcardPolicy(baseDomain string) error {
+	registered, depth, err := registeredDomain(baseDomain)
+	if err != nil {
+		return errNonPublic
+	}
+	for _, forbidden := range pa.wildcards.ForbiddenDomains {
+		if registered == forbidden {
+			return errWildcardForbidden
+		}
+	}
+	// The wildcard label itself is one more label in front of the registered
+	// domain.
+	if pa.wildcards.MaxDepth > 0 && depth+1 > pa.wildcards.MaxDepth {
+		return berrors.RejectedIdentifierError(
+			"Wildcard domain names may have at most %d labels in front of their registered domain", pa.wildcards.MaxDepth)
+	}
+	return nil
+}
+
 // checkWildcardHostList checks the wildcardExactBlocklist for a given domain.
 // If the domain is not present on the list nil is returned, otherwise
 // errPolicyForbidden is returned.
@@ -532,14 +835,24 @@ func (pa *AuthorityImpl) checkHostLists(domain string) error {
 
 // ChallengesFor makes a decision of what challenges are acceptable for
 // the given identifier.
-func (pa *AuthorityImpl) ChallengesFor(identifier identifier.ACMEIdentifier) ([]core.Challenge, error) {
+func (pa *AuthorityImpl) ChallengesFor(ident identifier.ACMEIdentifier) ([]core.Challenge, error) {
 	challenges := []core.Challenge{}
 
 	token := core.NewToken()
 
+	// Email identifiers can only be validated with email-reply-00.
+	if ident.Type == identifier.Email {
+		if !pa.ChallengeTypeEnabled(core.ChallengeTypeEmailReply00) {
+			return nil, fmt.Errorf(
+				"Challenges requested for email identifier but email-reply-00 " +
+					"challenge type is not enabled")
+		}
+		return []core.Challenge{core.EmailReplyChallenge00(token)}, nil
+	}
+
 	// If the identifier is for a DNS wildcard name we only
 	// provide a DNS-01 challenge as a matter of CA policy.
-	if strings.HasPrefix(identifier.Value, "*.") {
+	if strings.HasPrefix(ident.Value, "*.") {
 		// We must have the DNS-01 challenge type enabled to create challenges for
 		// a wildcard identifier per LE policy.
 		if !pa.ChallengeTypeEnabled(core.ChallengeTypeDNS01) {
@@ -1,14 +1,20 @@
 package policy
 
 import (
+	"context"
+	"errors"
 	"io/ioutil"
 	"os"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/letsencrypt/boulder/core"
+	corepb "github.com/letsencrypt/boulder/core/proto"
 	berrors "github.com/letsencrypt/boulder/errors"
 	"github.com/letsencrypt/boulder/features"
 	"github.com/letsencrypt/boulder/identifier"
+	sapb "github.com/letsencrypt/boulder/sa/proto"
 	"github.com/letsencrypt/boulder/test"
 	"gopkg.in/yaml.v2"
 )
@@ -361,6 +367,51 @@ func TestWillingToIssueWildcards(t *testing.T) {
 	test.AssertEquals(t, berr.Error(), "Cannot issue for \"letsdecrypt.org\": The ACME server refuses to issue a certificate for this domain name, because it is forbidden by policy")
 }
 
+func TestWildcardPolicy(t *testing.T) {
+	pa := paImpl(t)
+	err := pa.processHostnamePolicy(blockedNamesPolicy{
+		HighRiskBlockedNames: []string{"zombo.gov.us"},
+		ExactBlockedNames:    []string{"highvalue.letsdecrypt.org"},
+	}, 0)
+	test.AssertNotError(t, err, "Couldn't load hostname policy")
+
+	err = pa.SetWildcardPolicy(WildcardPolicy{MaxDepth: -1})
+	test.AssertError(t, err, "Negative MaxDepth was accepted")
+	err = pa.SetWildcardPolicy(WildcardPolicy{ForbiddenDomains: []string{"www.example.com"}})
+	test.AssertError(t, err, "Forbidden domain which isn't a registered domain was accepted")
+	err = pa.SetWildcardPolicy(WildcardPolicy{ForbiddenDomains: []string{"co.uk"}})
+	test.AssertError(t, err, "Forbidden domain which is a public suffix was accepted")
+
+	err = pa.SetWildcardPolicy(WildcardPolicy{
+		MaxDepth:            2,
+		ForbiddenDomains:    []string{"Forbidden.co.uk"},
+		RequireRevalidation: true,
+	})
+	test.AssertNotError(t, err, "Couldn't set wildcard policy")
+	test.Assert(t, pa.RequireWildcardRevalidation(), "Wildcard revalidation should be required")
+
+	testCases := []struct {
+		Name        string
+		Domain      string
+		ExpectedErr error
+	}{
+		{"Shallow wildcard", "*.example.com", nil},
+		{"Wildcard at max depth", "*.www.example.com", nil},
+		{"Wildcard at max depth under multi-label suffix", "*.www.example.co.uk", nil},
+		{"Wildcard too deep", "*.a.www.example.com", berrors.RejectedIdentifierError("Wildcard domain names may have at most 2 labels in front of their registered domain")},
+		{"Wildcard for forbidden domain", "*.forbidden.co.uk", errWildcardForbidden},
+		{"Wildcard under forbidden domain", "*.www.forbidden.co.uk", errWildcardForbidden},
+		{"Non-wildcard under forbidden domain", "www.forbidden.co.uk", nil},
+		{"Deep non-wildcard", "a.b.c.example.com", nil},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			err := pa.willingToIssueWildcard(identifier.DNSIdentifier(tc.Domain))
+			test.AssertDeepEquals(t, err, tc.ExpectedErr)
+		})
+	}
+}
+
 func TestChallengesFor(t *testing.T) {
 	pa := paImpl(t)
 
@@ -417,6 +468,64 @@ func TestChallengesForWildcard(t *testing.T) {
 	test.AssertEquals(t, challenges[0].Type, core.ChallengeTypeDNS01)
 }
 
+func TestWillingToIssueEmail(t *testing.T) {
+	bannedBytes, err := yaml.Marshal(blockedNamesPolicy{
+		HighRiskBlockedNames: []string{"zombo.gov.us"},
+		ExactBlockedNames:    []string{"highvalue.example.org"},
+	})
+	test.AssertNotError(t, err, "Couldn't serialize banned list")
+	f, _ := ioutil.TempFile("", "test-email-banlist.*.yaml")
+	defer os.Remove(f.Name())
+	err = ioutil.WriteFile(f.Name(), bannedBytes, 0640)
+	test.AssertNotError(t, err, "Couldn't write serialized banned list to file")
+	pa := paImpl(t)
+	err = pa.SetHostnamePolicyFile(f.Name())
+	test.AssertNotError(t, err, "Couldn't load policy contents from file")
+
+	ident := identifier.EmailIdentifier("user@example.org")
+
+	// Email identifiers are rejected unless the feature is enabled.
+	test.AssertEquals(t, pa.WillingToIssue(ident), errInvalidIdentifier)
+
+	_ = features.Set(map[string]bool{"EmailIdentifiers": true})
+	defer features.Reset()
+
+	testCases := []struct {
+		address string
+		err     error
+	}{
+		{"user@example.org", nil},
+		{"first.last+tag@sub.example.org", nil},
+		{"User@example.org", errMalformedEmail},
+		{"üser@example.org", errMalformedEmail},
+		{"User <user@example.org>", errMalformedEmail},
+		{"user", errMalformedEmail},
+		{"user@" + strings.Repeat("a", 250) + ".org", errEmailTooLong},
+		{"user@example", errTooFewLabels},
+		{"user@127.0.0.1", errIPAddress},
+		{"user@zombo.gov.us", errPolicyForbidden},
+	}
+	for _, tc := range testCases {
+		err := pa.WillingToIssue(identifier.EmailIdentifier(tc.address))
+		if err != tc.err {
+			t.Errorf("WillingToIssue(%q) = %#v, expected %#v", tc.address, err, tc.err)
+		}
+	}
+
+	// Only email-reply-00 challenges are offered for email identifiers.
+	_, err = pa.ChallengesFor(ident)
+	test.AssertError(t, err, "ChallengesFor didn't fail with email-reply-00 disabled")
+	pa, err = New(map[core.AcmeChallenge]bool{
+		core.ChallengeTypeHTTP01:       true,
+		core.ChallengeTypeEmailReply00: true,
+	})
+	test.AssertNotError(t, err, "Couldn't create policy implementation")
+	challenges, err := pa.ChallengesFor(ident)
+	test.AssertNotError(t, err, "ChallengesFor failed")
+	test.AssertEquals(t, len(challenges), 1)
+	test.AssertEquals(t, challenges[0].Type, core.ChallengeTypeEmailReply00)
+}
+
 // TestMalformedExactBlocklist tests that loading a YAML policy file with an
 // invalid exact blocklist entry will fail as expected.
 func TestMalformedExactBlocklist(t *testing.T) {
@@ -464,3 +573,112 @@ func TestValidEmailError(t *testing.T) {
 	err = ValidEmail("example@-foobar.com")
 	test.AssertEquals(t, err.Error(), "contact email \"example@-foobar.com\" has invalid domain : Domain name contains an invalid character")
 }
+
+// fakePolicySource serves a single version of the hostname policy, like the
+// SA's GetHostnamePolicy.
+type fakePolicySource struct {
+	policy *sapb.HostnamePolicy
+	err    error
+}
+
+func (s *fakePolicySource) GetHostnamePolicy(_ context.Context, req *sapb.GetHostnamePolicyRequest) (*sapb.HostnamePolicy, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	if s.policy == nil {
+		return nil, berrors.NotFoundError("no hostname policy has been stored")
+	}
+	if req.KnownVersion == s.policy.Version {
+		return &sapb.HostnamePolicy{Version: s.policy.Version}, nil
+	}
+	return s.policy, nil
+}
+
+func TestHostnamePolicySource(t *testing.T) {
+	bannedBytes, err := yaml.Marshal(blockedNamesPolicy{
+		HighRiskBlockedNames: []string{"zombo.gov.us"},
+		ExactBlockedNames:    []string{"highvalue.letsdecrypt.org"},
+	})
+	test.AssertNotError(t, err, "Couldn't serialize banned list")
+	f, _ := ioutil.TempFile("", "test-source-banlist.*.yaml")
+	defer os.Remove(f.Name())
+	err = ioutil.WriteFile(f.Name(), bannedBytes, 0640)
+	test.AssertNotError(t, err, "Couldn't write serialized banned list to file")
+
+	pa := paImpl(t)
+	err = pa.SetHostnamePolicyFile(f.Name())
+	test.AssertNotError(t, err, "Couldn't load policy contents from file")
+	ctx := context.Background()
+
+	_, err = pa.RefreshHostnamePolicy(ctx)
+	test.AssertError(t, err, "RefreshHostnamePolicy succeeded without a source")
+
+	// With no policy stored in the SA, or the SA unavailable, the file is
+	// used.
+	source := &fakePolicySource{}
+	pa.SetHostnamePolicySource(source, time.Hour)
+	test.AssertEquals(t, pa.HostnamePolicyVersion(), int64(0))
+	source.err = errors.New("SA unavailable")
+	_, err = pa.RefreshHostnamePolicy(ctx)
+	test.AssertError(t, err, "RefreshHostnamePolicy didn't report the SA's error")
+	test.AssertEquals(t, pa.HostnamePolicyVersion(), int64(0))
+	test.AssertEquals(t, pa.WillingToIssue(identifier.DNSIdentifier("zombo.gov.us")), errPolicyForbidden)
+
+	source.err = nil
+	source.policy = &sapb.HostnamePolicy{
+		Version:              2,
+		HighRiskBlockedNames: []string{"example.org"},
+		ExactBlockedNames:    []string{"highvalue.example.net"},
+	}
+	version, err := pa.RefreshHostnamePolicy(ctx)
+	test.AssertNotError(t, err, "RefreshHostnamePolicy failed")
+	test.AssertEquals(t, version, int64(2))
+	test.AssertNotError(t, pa.WillingToIssue(identifier.DNSIdentifier("zombo.gov.us")), "file's policy still in use")
+	test.AssertEquals(t, pa.WillingToIssue(identifier.DNSIdentifier("www.example.org")), errPolicyForbidden)
+	test.AssertEquals(t, pa.WillingToIssue(identifier.DNSIdentifier("highvalue.example.net")), errPolicyForbidden)
+
+	// Once the SA's policy is in use, the file is ignored.
+	err = pa.loadHostnamePolicy(bannedBytes)
+	test.AssertNotError(t, err, "loadHostnamePolicy failed")
+	test.AssertEquals(t, pa.HostnamePolicyVersion(), int64(2))
+	test.AssertNotError(t, pa.WillingToIssue(identifier.DNSIdentifier("zombo.gov.us")), "file's policy was reloaded")
+
+	// An invalid version is rejected, leaving the current one in use.
+	source.policy = &sapb.HostnamePolicy{
+		Version:              3,
+		HighRiskBlockedNames: []string{"example.org"},
+		ExactBlockedNames:    []string{"com"},
+	}
+	version, err = pa.RefreshHostnamePolicy(ctx)
+	test.AssertError(t, err, "RefreshHostnamePolicy accepted an invalid policy")
+	test.AssertEquals(t, version, int64(2))
+	test.AssertEquals(t, pa.WillingToIssue(identifier.DNSIdentifier("www.example.org")), errPolicyForbidden)
+
+	test.AssertError(t, ValidateHostnamePolicy(source.policy), "ValidateHostnamePolicy accepted a one label exact name")
+	test.AssertError(t, ValidateHostnamePolicy(&sapb.HostnamePolicy{ExactBlockedNames: []string{"a.com"}}), "ValidateHostnamePolicy accepted a policy without high risk names")
+}
+
+func TestRefresherServer(t *testing.T) {
+	pa := paImpl(t)
+	rs := NewRefresherServer(pa)
+	ctx := context.Background()
+
+	_, err := rs.RefreshHostnamePolicy(ctx, &corepb.Empty{})
+	test.AssertError(t, err, "RefreshHostnamePolicy succeeded without a source")
+
+	source := &fakePolicySource{policy: &sapb.HostnamePolicy{
+		Version:              1,
+		HighRiskBlockedNames: []string{"example.org"},
+		ExactBlockedNames:    []string{"highvalue.example.net"},
+	}}
+	pa.SetHostnamePolicySource(source, time.Hour)
+	source.policy = &sapb.HostnamePolicy{
+		Version:              2,
+		HighRiskBlockedNames: []string{"example.org", "example.com"},
+		ExactBlockedNames:    []string{"highvalue.example.net"},
+	}
+	resp, err := rs.RefreshHostnamePolicy(ctx, &corepb.Empty{})
+	test.AssertNotError(t, err, "RefreshHostnamePolicy failed")
+	test.AssertEquals(t, resp.Version, int64(2))
+	test.AssertEquals(t, pa.WillingToIssue(identifier.DNSIdentifier("www.example.com")), errPolicyForbidden)
+}
@@ -0,0 +1,248 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.21.0
+// 	protoc        v3.11.4
+// source: policy/proto/policy.proto
+
+package proto
+
+import (
+	context "context"
+	proto "github.com/golang/protobuf/proto"
+	proto1 "github.com/letsencrypt/boulder/core/proto"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// This is a compile-time assertion that a sufficiently up-to-date version
+// of the legacy proto package is being used.
+const _ = proto.ProtoPackageIsVersion4
+
+type HostnamePolicyVersion struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The version of the hostname policy loaded from the SA, or 0 if the policy
+	// file is in use.
+	Version int64 `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+}
+
+func (x *HostnamePolicyVersion) Reset() {
+	*x = HostnamePolicyVersion{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_policy_proto_policy_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *HostnamePolicyVersion) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HostnamePolicyVersion) ProtoMessage() {}
+
+func (x *HostnamePolicyVersion) ProtoReflect() protoreflect.Message {
+	mi := &file_policy_proto_policy_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HostnamePolicyVersion.ProtoReflect.Descriptor instead.
+func (*HostnamePolicyVersion) Descriptor() ([]byte, []int) {
+	return file_policy_proto_policy_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *HostnamePolicyVersion) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+var File_policy_proto_policy_proto protoreflect.FileDescriptor
+
+var file_policy_proto_policy_proto_rawDesc = []byte{
+	0x0a, 0x19, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x70,
+	0x6f, 0x6c, 0x69, 0x63, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x06, 0x70, 0x6f, 0x6c,
+	0x69, 0x63, 0x79, 0x1a, 0x15, 0x63, 0x6f, 0x72, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f,
+	0x63, 0x6f, 0x72, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x31, 0x0a, 0x15, 0x48, 0x6f,
+	0x73, 0x74, 0x6e, 0x61, 0x6d, 0x65, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x56, 0x65, 0x72, 0x73,
+	0x69, 0x6f, 0x6e, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x32, 0x60, 0x0a,
+	0x17, 0x48, 0x6f, 0x73, 0x74, 0x6e, 0x61, 0x6d, 0x65, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x52,
+	0x65, 0x66, 0x72, 0x65, 0x73, 0x68, 0x65, 0x72, 0x12, 0x45, 0x0a, 0x15, 0x52, 0x65, 0x66, 0x72,
+	0x65, 0x73, 0x68, 0x48, 0x6f, 0x73, 0x74, 0x6e, 0x61, 0x6d, 0x65, 0x50, 0x6f, 0x6c, 0x69, 0x63,
+	0x79, 0x12, 0x0b, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x1d,
+	0x2e, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x2e, 0x48, 0x6f, 0x73, 0x74, 0x6e, 0x61, 0x6d, 0x65,
+	0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x22, 0x00, 0x42,
+	0x2d, 0x5a, 0x2b, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6c, 0x65,
+	0x74, 0x73, 0x65, 0x6e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x2f, 0x62, 0x6f, 0x75, 0x6c, 0x64, 0x65,
+	0x72, 0x2f, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_policy_proto_policy_proto_rawDescOnce sync.Once
+	file_policy_proto_policy_proto_rawDescData = file_policy_proto_policy_proto_rawDesc
+)
+
+func file_policy_proto_policy_proto_rawDescGZIP() []byte {
+	file_policy_proto_policy_proto_rawDescOnce.Do(func() {
+		file_policy_proto_policy_proto_rawDescData = protoimpl.X.CompressGZIP(file_policy_proto_policy_proto_rawDescData)
+	})
+	return file_policy_proto_policy_proto_rawDescData
+}
+
+var file_policy_proto_policy_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_policy_proto_policy_proto_goTypes = []interface{}{
+	(*HostnamePolicyVersion)(nil), // 0: policy.HostnamePolicyVersion
+	(*proto1.Empty)(nil),          // 1: core.Empty
+}
+var file_policy_proto_policy_proto_depIdxs = []int32{
+	1, // 0: policy.HostnamePolicyRefresher.RefreshHostnamePolicy:input_type -> core.Empty
+	0, // 1: policy.HostnamePolicyRefresher.RefreshHostnamePolicy:output_type -> policy.HostnamePolicyVersion
+	1, // [1:2] is the sub-list for method output_type
+	0, // [0:1] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_policy_proto_policy_proto_init() }
+func file_policy_proto_policy_proto_init() {
+	if File_policy_proto_policy_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_policy_proto_policy_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*HostnamePolicyVersion); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_policy_proto_policy_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_policy_proto_policy_proto_goTypes,
+		DependencyIndexes: file_policy_proto_policy_proto_depIdxs,
+		MessageInfos:      file_policy_proto_policy_proto_msgTypes,
+	}.Build()
+	File_policy_proto_policy_proto = out.File
+	file_policy_proto_policy_proto_rawDesc = nil
+	file_policy_proto_policy_proto_goTypes = nil
+	file_policy_proto_policy_proto_depIdxs = nil
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConnInterface
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// HostnamePolicyRefresherClient is the client API for HostnamePolicyRefresher service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type HostnamePolicyRefresherClient interface {
+	RefreshHostnamePolicy(ctx context.Context, in *proto1.Empty, opts ...grpc.CallOption) (*HostnamePolicyVersion, error)
+}
+
+type hostnamePolicyRefresherClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewHostnamePolicyRefresherClient(cc grpc.ClientConnInterface) HostnamePolicyRefresherClient {
+	return &hostnamePolicyRefresherClient{cc}
+}
+
+func (c *hostnamePolicyRefresherClient) RefreshHostnamePolicy(ctx context.Context, in *proto1.Empty, opts ...grpc.CallOption) (*HostnamePolicyVersion, error) {
+	out := new(HostnamePolicyVersion)
+	err := c.cc.Invoke(ctx, "/policy.HostnamePolicyRefresher/RefreshHostnamePolicy", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// HostnamePolicyRefresherServer is the server API for HostnamePolicyRefresher service.
+type HostnamePolicyRefresherServer interface {
+	RefreshHostnamePolicy(context.Context, *proto1.Empty) (*HostnamePolicyVersion, error)
+}
+
+// UnimplementedHostnamePolicyRefresherServer can be embedded to have forward compatible implementations.
+type UnimplementedHostnamePolicyRefresherServer struct {
+}
+
+func (*UnimplementedHostnamePolicyRefresherServer) RefreshHostnamePolicy(context.Context, *proto1.Empty) (*HostnamePolicyVersion, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RefreshHostnamePolicy not implemented")
+}
+
+func RegisterHostnamePolicyRefresherServer(s *grpc.Server, srv HostnamePolicyRefresherServer) {
+	s.RegisterService(&_HostnamePolicyRefresher_serviceDesc, srv)
+}
+
+func _HostnamePolicyRefresher_RefreshHostnamePolicy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(proto1.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HostnamePolicyRefresherServer).RefreshHostnamePolicy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/policy.HostnamePolicyRefresher/RefreshHostnamePolicy",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HostnamePolicyRefresherServer).RefreshHostnamePolicy(ctx, req.(*proto1.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _HostnamePolicyRefresher_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "policy.HostnamePolicyRefresher",
+	HandlerType: (*HostnamePolicyRefresherServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "RefreshHostnamePolicy",
+			Handler:    _HostnamePolicyRefresher_RefreshHostnamePolicy_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "policy/proto/policy.proto",
+}
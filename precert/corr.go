@@ -0,0 +1,140 @@
+// Package precert verifies that final certificates correspond to the
+// precertificates they were issued from, as required by RFC 6962 section 3.1.
+package precert
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/cryptobyte"
+	cryptobyte_asn1 "golang.org/x/crypto/cryptobyte/asn1"
+)
+
+var (
+	// ctPoisonOID is the OID of the CT poison extension, which every
+	// precertificate contains.
+	ctPoisonOID = []byte{0x06, 0x0a, 0x2b, 0x06, 0x01, 0x04, 0x01, 0xd6, 0x79, 0x02, 0x04, 0x03}
+	// sctListOID is the OID of the embedded SCT list extension, which takes
+	// the place of the poison extension in the final certificate.
+	sctListOID = []byte{0x06, 0x0a, 0x2b, 0x06, 0x01, 0x04, 0x01, 0xd6, 0x79, 0x02, 0x04, 0x02}
+
+	extensionsTag = cryptobyte_asn1.Tag(3).Constructed().ContextSpecific()
+)
+
+// tbsCertificate holds the encoded fields of a TBSCertificate. Extensions
+// are held separately from the other fields, each one fully encoded.
+type tbsCertificate struct {
+	fields     [][]byte
+	extensions [][]byte
+}
+
+// parseTBS extracts the fields of the TBSCertificate from a DER encoded
+// certificate.
+func parseTBS(der []byte) (*tbsCertificate, error) {
+	input := cryptobyte.String(der)
+	var cert, tbs cryptobyte.String
+	if !input.ReadASN1(&cert, cryptobyte_asn1.SEQUENCE) || !input.Empty() {
+		return nil, errors.New("malformed certificate")
+	}
+	if !cert.ReadASN1(&tbs, cryptobyte_asn1.SEQUENCE) {
+		return nil, errors.New("malformed tbsCertificate")
+	}
+	var parsed tbsCertificate
+	for !tbs.Empty() {
+		var field cryptobyte.String
+		var tag cryptobyte_asn1.Tag
+		if !tbs.ReadAnyASN1Element(&field, &tag) {
+			return nil, errors.New("malformed tbsCertificate field")
+		}
+		if tag != extensionsTag {
+			parsed.fields = append(parsed.fields, field)
+			continue
+		}
+		var exts cryptobyte.String
+		if !field.ReadASN1(&exts, extensionsTag) || !exts.ReadASN1(&exts, cryptobyte_asn1.SEQUENCE) {
+			return nil, errors.New("malformed extensions")
+		}
+		for !exts.Empty() {
+			var ext cryptobyte.String
+			if !exts.ReadASN1Element(&ext, cryptobyte_asn1.SEQUENCE) {
+				return nil, errors.New("malformed extension")
+			}
+			parsed.extensions = append(parsed.extensions, ext)
+		}
+	}
+	return &parsed, nil
+}
+
+// extensionOID returns the encoded OID of an encoded extension.
+func extensionOID(ext []byte) []byte {
+	s := cryptobyte.String(ext)
+	var contents cryptobyte.String
+	var oid cryptobyte.String
+	if !s.ReadASN1(&contents, cryptobyte_asn1.SEQUENCE) || !contents.ReadASN1Element(&oid, cryptobyte_asn1.OBJECT_IDENTIFIER) {
+		return nil
+	}
+	return oid
+}
+
+// withoutExtension returns exts with any extensions with the given OID
+// removed, and whether any were found.
+func withoutExtension(exts [][]byte, oid []byte) ([][]byte, bool) {
+	var filtered [][]byte
+	var found bool
+	for _, ext := range exts {
+		if bytes.Equal(extensionOID(ext), oid) {
+			found = true
+			continue
+		}
+		filtered = append(filtered, ext)
+	}
+	return filtered, found
+}
+
+// Correspond returns an error if the final certificate doesn't correspond to
+// the precertificate: their TBSCertificates must be identical except that
+// the precertificate contains the CT poison extension, and the final
+// certificate may contain an embedded SCT list extension. Both certificates
+// are DER encoded. The signatures aren't checked.
+func Correspond(precertDER, finalDER []byte) error {
+	precert, err := parseTBS(precertDER)
+	if err != nil {
+		return fmt.Errorf("parsing precertificate: %w", err)
+	}
+	final, err := parseTBS(finalDER)
+	if err != nil {
+		return fmt.Errorf("parsing final certificate: %w", err)
+	}
+
+	if len(precert.fields) != len(final.fields) {
+		return fmt.Errorf("precertificate has %d tbsCertificate fields, final certificate has %d",
+			len(precert.fields), len(final.fields))
+	}
+	for i := range precert.fields {
+		if !bytes.Equal(precert.fields[i], final.fields[i]) {
+			return fmt.Errorf("tbsCertificate field %d differs: precertificate has %x, final certificate has %x",
+				i, precert.fields[i], final.fields[i])
+		}
+	}
+
+	precertExts, poisoned := withoutExtension(precert.extensions, ctPoisonOID)
+	if !poisoned {
+		return errors.New("precertificate doesn't contain the CT poison extension")
+	}
+	if _, poisoned := withoutExtension(final.extensions, ctPoisonOID); poisoned {
+		return errors.New("final certificate contains the CT poison extension")
+	}
+	finalExts, _ := withoutExtension(final.extensions, sctListOID)
+	if len(precertExts) != len(finalExts) {
+		return fmt.Errorf("precertificate has %d extensions, final certificate has %d, excluding poison and SCTs",
+			len(precertExts), len(finalExts))
+	}
+	for i := range precertExts {
+		if !bytes.Equal(precertExts[i], finalExts[i]) {
+			return fmt.Errorf("extension %d differs: precertificate has %x, final certificate has %x",
+				i, precertExts[i], finalExts[i])
+		}
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package precert
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/letsencrypt/boulder/test"
+)
+
+var (
+	poisonExt = pkix.Extension{
+		Id:       asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 11129, 2, 4, 3},
+		Critical: true,
+		Value:    asn1.NullBytes,
+	}
+	sctListExt = pkix.Extension{
+		Id:    asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 11129, 2, 4, 2},
+		Value: []byte{0x04, 0x02, 0x00, 0x00},
+	}
+)
+
+func TestCorrespond(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	test.AssertNotError(t, err, "generating key")
+	now := time.Now()
+	template := x509.Certificate{
+		SerialNumber:          big.NewInt(1337),
+		Subject:               pkix.Name{CommonName: "example.com"},
+		DNSNames:              []string{"example.com", "www.example.com"},
+		NotBefore:             now,
+		NotAfter:              now.Add(90 * 24 * time.Hour),
+		BasicConstraintsValid: true,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	issue := func(extra ...pkix.Extension) []byte {
+		tmpl := template
+		tmpl.ExtraExtensions = extra
+		der, err := x509.CreateCertificate(rand.Reader, &tmpl, &tmpl, key.Public(), key)
+		test.AssertNotError(t, err, "creating certificate")
+		return der
+	}
+
+	precert := issue(poisonExt)
+	final := issue(sctListExt)
+	test.AssertNotError(t, Correspond(precert, final), "corresponding certificates didn't correspond")
+	test.AssertNotError(t, Correspond(precert, issue()), "final certificate without SCTs didn't correspond")
+
+	err = Correspond(final, final)
+	test.AssertError(t, err, "precertificate without poison corresponded")
+	test.AssertContains(t, err.Error(), "doesn't contain the CT poison extension")
+
+	err = Correspond(precert, precert)
+	test.AssertError(t, err, "poisoned final certificate corresponded")
+	test.AssertContains(t, err.Error(), "final certificate contains the CT poison extension")
+
+	template.DNSNames = []string{"example.com", "evil.example.com"}
+	err = Correspond(precert, issue(sctListExt))
+	test.AssertError(t, err, "final certificate with different names corresponded")
+
+	template.DNSNames = []string{"example.com", "www.example.com"}
+	template.SerialNumber = big.NewInt(1338)
+	err = Correspond(precert, issue(sctListExt))
+	test.AssertError(t, err, "final certificate with a different serial corresponded")
+	test.AssertContains(t, err.Error(), "tbsCertificate field")
+
+	err = Correspond([]byte{0x30, 0x00}, final)
+	test.AssertError(t, err, "malformed precertificate corresponded")
+}
@@ -201,6 +201,17 @@ func ServerInternal(detail string) *ProblemDetails {
 	}
 }
 
+// ServiceUnavailable returns a ProblemDetails with a ServerInternalProblem and
+// a 503 Service Unavailable status code, for requests which failed because
+// the server is overloaded and which can be retried later.
+func ServiceUnavailable(detail string) *ProblemDetails {
+	return &ProblemDetails{
+		Type:       ServerInternalProblem,
+		Detail:     detail,
+		HTTPStatus: http.StatusServiceUnavailable,
+	}
+}
+
 // Unauthorized returns a ProblemDetails with an UnauthorizedProblem and a 403
 // Forbidden status code.
 func Unauthorized(detail string) *ProblemDetails {
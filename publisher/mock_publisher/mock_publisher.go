@@ -34,6 +34,21 @@ func (m *MockPublisher) EXPECT() *MockPublisherMockRecorder {
 	return m.recorder
 }
 
+// QueueFinalCert mocks base method
+func (m *MockPublisher) QueueFinalCert(arg0 context.Context, arg1 *proto.FinalCertRequest) (*proto.FinalCertQueued, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueueFinalCert", arg0, arg1)
+	ret0, _ := ret[0].(*proto.FinalCertQueued)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueueFinalCert indicates an expected call of QueueFinalCert
+func (mr *MockPublisherMockRecorder) QueueFinalCert(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueueFinalCert", reflect.TypeOf((*MockPublisher)(nil).QueueFinalCert), arg0, arg1)
+}
+
 // SubmitToSingleCTWithResult mocks base method
 func (m *MockPublisher) SubmitToSingleCTWithResult(arg0 context.Context, arg1 *proto.Request) (*proto.Result, error) {
 	m.ctrl.T.Helper()
@@ -155,6 +155,154 @@ func (x *Result) GetSct() []byte {
 	return nil
 }
 
+type FinalCertLog struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	LogURL       string `protobuf:"bytes,1,opt,name=LogURL,proto3" json:"LogURL,omitempty"`
+	LogPublicKey string `protobuf:"bytes,2,opt,name=LogPublicKey,proto3" json:"LogPublicKey,omitempty"`
+}
+
+func (x *FinalCertLog) Reset() {
+	*x = FinalCertLog{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_publisher_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FinalCertLog) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FinalCertLog) ProtoMessage() {}
+
+func (x *FinalCertLog) ProtoReflect() protoreflect.Message {
+	mi := &file_publisher_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FinalCertLog.ProtoReflect.Descriptor instead.
+func (*FinalCertLog) Descriptor() ([]byte, []int) {
+	return file_publisher_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *FinalCertLog) GetLogURL() string {
+	if x != nil {
+		return x.LogURL
+	}
+	return ""
+}
+
+func (x *FinalCertLog) GetLogPublicKey() string {
+	if x != nil {
+		return x.LogPublicKey
+	}
+	return ""
+}
+
+type FinalCertRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Der  []byte          `protobuf:"bytes,1,opt,name=der,proto3" json:"der,omitempty"`
+	Logs []*FinalCertLog `protobuf:"bytes,2,rep,name=logs,proto3" json:"logs,omitempty"`
+}
+
+func (x *FinalCertRequest) Reset() {
+	*x = FinalCertRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_publisher_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FinalCertRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FinalCertRequest) ProtoMessage() {}
+
+func (x *FinalCertRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_publisher_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FinalCertRequest.ProtoReflect.Descriptor instead.
+func (*FinalCertRequest) Descriptor() ([]byte, []int) {
+	return file_publisher_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *FinalCertRequest) GetDer() []byte {
+	if x != nil {
+		return x.Der
+	}
+	return nil
+}
+
+func (x *FinalCertRequest) GetLogs() []*FinalCertLog {
+	if x != nil {
+		return x.Logs
+	}
+	return nil
+}
+
+type FinalCertQueued struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *FinalCertQueued) Reset() {
+	*x = FinalCertQueued{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_publisher_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FinalCertQueued) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FinalCertQueued) ProtoMessage() {}
+
+func (x *FinalCertQueued) ProtoReflect() protoreflect.Message {
+	mi := &file_publisher_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FinalCertQueued.ProtoReflect.Descriptor instead.
+func (*FinalCertQueued) Descriptor() ([]byte, []int) {
+	return file_publisher_proto_rawDescGZIP(), []int{4}
+}
+
 var File_publisher_proto protoreflect.FileDescriptor
 
 var file_publisher_proto_rawDesc = []byte{
@@ -169,13 +317,27 @@ var file_publisher_proto_rawDesc = []byte{
 	0x65, 0x63, 0x65, 0x72, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x53, 0x43,
 	0x54, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x53, 0x43,
 	0x54, 0x22, 0x1a, 0x0a, 0x06, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x73,
-	0x63, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x03, 0x73, 0x63, 0x74, 0x32, 0x3e, 0x0a,
-	0x09, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x65, 0x72, 0x12, 0x31, 0x0a, 0x1a, 0x53, 0x75,
-	0x62, 0x6d, 0x69, 0x74, 0x54, 0x6f, 0x53, 0x69, 0x6e, 0x67, 0x6c, 0x65, 0x43, 0x54, 0x57, 0x69,
-	0x74, 0x68, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x08, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x1a, 0x07, 0x2e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x22, 0x00, 0x42, 0x0d, 0x5a,
-	0x0b, 0x2e, 0x3b, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x65, 0x72, 0x62, 0x06, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x33,
+	0x63, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x03, 0x73, 0x63, 0x74, 0x22, 0x4a, 0x0a,
+	0x0c, 0x46, 0x69, 0x6e, 0x61, 0x6c, 0x43, 0x65, 0x72, 0x74, 0x4c, 0x6f, 0x67, 0x12, 0x16, 0x0a,
+	0x06, 0x4c, 0x6f, 0x67, 0x55, 0x52, 0x4c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x4c,
+	0x6f, 0x67, 0x55, 0x52, 0x4c, 0x12, 0x22, 0x0a, 0x0c, 0x4c, 0x6f, 0x67, 0x50, 0x75, 0x62, 0x6c,
+	0x69, 0x63, 0x4b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x4c, 0x6f, 0x67,
+	0x50, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x4b, 0x65, 0x79, 0x22, 0x47, 0x0a, 0x10, 0x46, 0x69, 0x6e,
+	0x61, 0x6c, 0x43, 0x65, 0x72, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x10, 0x0a,
+	0x03, 0x64, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x03, 0x64, 0x65, 0x72, 0x12,
+	0x21, 0x0a, 0x04, 0x6c, 0x6f, 0x67, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0d, 0x2e,
+	0x46, 0x69, 0x6e, 0x61, 0x6c, 0x43, 0x65, 0x72, 0x74, 0x4c, 0x6f, 0x67, 0x52, 0x04, 0x6c, 0x6f,
+	0x67, 0x73, 0x22, 0x11, 0x0a, 0x0f, 0x46, 0x69, 0x6e, 0x61, 0x6c, 0x43, 0x65, 0x72, 0x74, 0x51,
+	0x75, 0x65, 0x75, 0x65, 0x64, 0x32, 0x77, 0x0a, 0x09, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68,
+	0x65, 0x72, 0x12, 0x31, 0x0a, 0x1a, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x54, 0x6f, 0x53, 0x69,
+	0x6e, 0x67, 0x6c, 0x65, 0x43, 0x54, 0x57, 0x69, 0x74, 0x68, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74,
+	0x12, 0x08, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x07, 0x2e, 0x52, 0x65, 0x73,
+	0x75, 0x6c, 0x74, 0x22, 0x00, 0x12, 0x37, 0x0a, 0x0e, 0x51, 0x75, 0x65, 0x75, 0x65, 0x46, 0x69,
+	0x6e, 0x61, 0x6c, 0x43, 0x65, 0x72, 0x74, 0x12, 0x11, 0x2e, 0x46, 0x69, 0x6e, 0x61, 0x6c, 0x43,
+	0x65, 0x72, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x10, 0x2e, 0x46, 0x69, 0x6e,
+	0x61, 0x6c, 0x43, 0x65, 0x72, 0x74, 0x51, 0x75, 0x65, 0x75, 0x65, 0x64, 0x22, 0x00, 0x42, 0x0d,
+	0x5a, 0x0b, 0x2e, 0x3b, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x65, 0x72, 0x62, 0x06, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -190,19 +352,25 @@ func file_publisher_proto_rawDescGZIP() []byte {
 	return file_publisher_proto_rawDescData
 }
 
-var file_publisher_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_publisher_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
 var file_publisher_proto_goTypes = []interface{}{
-	(*Request)(nil), // 0: Request
-	(*Result)(nil),  // 1: Result
+	(*Request)(nil),          // 0: Request
+	(*Result)(nil),           // 1: Result
+	(*FinalCertLog)(nil),     // 2: FinalCertLog
+	(*FinalCertRequest)(nil), // 3: FinalCertRequest
+	(*FinalCertQueued)(nil),  // 4: FinalCertQueued
 }
 var file_publisher_proto_depIdxs = []int32{
-	0, // 0: Publisher.SubmitToSingleCTWithResult:input_type -> Request
-	1, // 1: Publisher.SubmitToSingleCTWithResult:output_type -> Result
-	1, // [1:2] is the sub-list for method output_type
-	0, // [0:1] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	2, // 0: FinalCertRequest.logs:type_name -> FinalCertLog
+	0, // 1: Publisher.SubmitToSingleCTWithResult:input_type -> Request
+	3, // 2: Publisher.QueueFinalCert:input_type -> FinalCertRequest
+	1, // 3: Publisher.SubmitToSingleCTWithResult:output_type -> Result
+	4, // 4: Publisher.QueueFinalCert:output_type -> FinalCertQueued
+	3, // [3:5] is the sub-list for method output_type
+	1, // [1:3] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
 }
 
 func init() { file_publisher_proto_init() }
@@ -235,6 +403,42 @@ func file_publisher_proto_init() {
 				return nil
 			}
 		}
+		file_publisher_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FinalCertLog); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_publisher_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FinalCertRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_publisher_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FinalCertQueued); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -242,7 +446,7 @@ func file_publisher_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_publisher_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   2,
+			NumMessages:   5,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -269,6 +473,10 @@ const _ = grpc.SupportPackageIsVersion6
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
 type PublisherClient interface {
 	SubmitToSingleCTWithResult(ctx context.Context, in *Request, opts ...grpc.CallOption) (*Result, error)
+	// QueueFinalCert stores a final certificate to be submitted to CT logs in
+	// the background, retrying until each log accepts it, and returns once it's
+	// stored.
+	QueueFinalCert(ctx context.Context, in *FinalCertRequest, opts ...grpc.CallOption) (*FinalCertQueued, error)
 }
 
 type publisherClient struct {
@@ -288,9 +496,22 @@ func (c *publisherClient) SubmitToSingleCTWithResult(ctx context.Context, in *Re
 	return out, nil
 }
 
+func (c *publisherClient) QueueFinalCert(ctx context.Context, in *FinalCertRequest, opts ...grpc.CallOption) (*FinalCertQueued, error) {
+	out := new(FinalCertQueued)
+	err := c.cc.Invoke(ctx, "/Publisher/QueueFinalCert", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // PublisherServer is the server API for Publisher service.
 type PublisherServer interface {
 	SubmitToSingleCTWithResult(context.Context, *Request) (*Result, error)
+	// QueueFinalCert stores a final certificate to be submitted to CT logs in
+	// the background, retrying until each log accepts it, and returns once it's
+	// stored.
+	QueueFinalCert(context.Context, *FinalCertRequest) (*FinalCertQueued, error)
 }
 
 // UnimplementedPublisherServer can be embedded to have forward compatible implementations.
@@ -300,6 +521,9 @@ type UnimplementedPublisherServer struct {
 func (*UnimplementedPublisherServer) SubmitToSingleCTWithResult(context.Context, *Request) (*Result, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SubmitToSingleCTWithResult not implemented")
 }
+func (*UnimplementedPublisherServer) QueueFinalCert(context.Context, *FinalCertRequest) (*FinalCertQueued, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QueueFinalCert not implemented")
+}
 
 func RegisterPublisherServer(s *grpc.Server, srv PublisherServer) {
 	s.RegisterService(&_Publisher_serviceDesc, srv)
@@ -323,6 +547,24 @@ func _Publisher_SubmitToSingleCTWithResult_Handler(srv interface{}, ctx context.
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Publisher_QueueFinalCert_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FinalCertRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PublisherServer).QueueFinalCert(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Publisher/QueueFinalCert",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PublisherServer).QueueFinalCert(ctx, req.(*FinalCertRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Publisher_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "Publisher",
 	HandlerType: (*PublisherServer)(nil),
@@ -331,6 +573,10 @@ var _Publisher_serviceDesc = grpc.ServiceDesc{
 			MethodName: "SubmitToSingleCTWithResult",
 			Handler:    _Publisher_SubmitToSingleCTWithResult_Handler,
 		},
+		{
+			MethodName: "QueueFinalCert",
+			Handler:    _Publisher_QueueFinalCert_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "publisher.proto",
@@ -196,11 +196,18 @@ func initMetrics(stats prometheus.Registerer) *pubMetrics {
 
 // Impl defines a Publisher
 type Impl struct {
-	log          blog.Logger
-	userAgent    string
+	log         blog.Logger
+	userAgent   string
+	ctLogsCache logCache
+	metrics     *pubMetrics
+
+	// bundleMu protects issuerBundle, which can be replaced when the config is
+	// reloaded.
+	bundleMu     sync.RWMutex
 	issuerBundle []ct.ASN1Cert
-	ctLogsCache  logCache
-	metrics      *pubMetrics
+
+	// finalCertQueue is nil unless EnableFinalCertQueue has been called.
+	finalCertQueue *finalCertQueue
 }
 
 // New creates a Publisher that will submit certificates
@@ -222,6 +229,14 @@ func New(
 	}
 }
 
+// SetIssuerBundle replaces the intermediate certificates which are appended to
+// each certificate submitted to a log.
+func (pub *Impl) SetIssuerBundle(bundle []ct.ASN1Cert) {
+	pub.bundleMu.Lock()
+	defer pub.bundleMu.Unlock()
+	pub.issuerBundle = bundle
+}
+
 // SubmitToSingleCTWithResult will submit the certificate represented by certDER to the CT
 // log specified by log URL and public key (base64) and return the SCT to the caller
 func (pub *Impl) SubmitToSingleCTWithResult(ctx context.Context, req *pubpb.Request) (*pubpb.Result, error) {
@@ -231,7 +246,9 @@ func (pub *Impl) SubmitToSingleCTWithResult(ctx context.Context, req *pubpb.Requ
 		return nil, err
 	}
 
+	pub.bundleMu.RLock()
 	chain := append([]ct.ASN1Cert{{Data: req.Der}}, pub.issuerBundle...)
+	pub.bundleMu.RUnlock()
 
 	// Add a log URL/pubkey to the cache, if already present the
 	// existing *Log will be returned, otherwise one will be constructed, added
@@ -0,0 +1,421 @@
+package publisher
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jmhodges/clock"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/letsencrypt/boulder/core"
+	blog "github.com/letsencrypt/boulder/log"
+	pubpb "github.com/letsencrypt/boulder/publisher/proto"
+)
+
+// FinalCertQueueConfig configures the queue the publisher keeps final
+// certificates in until every CT log they were queued for has accepted them.
+type FinalCertQueueConfig struct {
+	// Dir is the directory the queue is kept in, as one file per certificate,
+	// so that it survives restarts. It must not be shared with another
+	// publisher.
+	Dir string
+	// Workers is the number of submissions made at once. It defaults to 5.
+	Workers int
+	// SubmissionTimeout bounds each attempt to submit a certificate to a log.
+	// It defaults to 30 seconds.
+	SubmissionTimeout time.Duration
+	// RetryBackoff is the delay before a failed submission is first retried,
+	// which doubles with each further failure up to MaxRetryBackoff. It's also
+	// how often the queue is checked for submissions due to be retried. It
+	// defaults to one minute, and MaxRetryBackoff to one hour.
+	RetryBackoff    time.Duration
+	MaxRetryBackoff time.Duration
+	// MaxAge is how long a certificate is retried for before it's dropped from
+	// the queue. Certificates are also dropped once they expire. It defaults to
+	// one week.
+	MaxAge time.Duration
+}
+
+// queuedLog is a CT log a queued certificate is still to be submitted to.
+type queuedLog struct {
+	URL       string `json:"url"`
+	PublicKey string `json:"publicKey"`
+}
+
+// queuedFinalCert is a certificate in the final certificate queue, as it's
+// stored on disk.
+type queuedFinalCert struct {
+	DER         []byte      `json:"der"`
+	Logs        []queuedLog `json:"logs"`
+	Queued      time.Time   `json:"queued"`
+	NotAfter    time.Time   `json:"notAfter"`
+	Attempts    int         `json:"attempts"`
+	NextAttempt time.Time   `json:"nextAttempt"`
+}
+
+// finalCertQueue submits final certificates to CT logs in the background,
+// retrying each log with exponential backoff until it accepts the
+// certificate. Certificates are keyed by serial, so queueing one twice only
+// adds any logs it wasn't already queued for.
+type finalCertQueue struct {
+	mu    sync.Mutex
+	certs map[string]*queuedFinalCert
+
+	config FinalCertQueueConfig
+	submit func(context.Context, *pubpb.Request) (*pubpb.Result, error)
+	clk    clock.Clock
+	log    blog.Logger
+
+	length      prometheus.Gauge
+	submissions *prometheus.CounterVec
+
+	wake chan struct{}
+	stop chan struct{}
+	done chan struct{}
+}
+
+// loadFinalCertQueue returns a queue holding the certificates stored in
+// config.Dir, creating the directory if it doesn't exist.
+func (pub *Impl) loadFinalCertQueue(config FinalCertQueueConfig, clk clock.Clock, stats prometheus.Registerer) (*finalCertQueue, error) {
+	if config.Dir == "" {
+		return nil, errors.New("final certificate queue directory is required")
+	}
+	if config.Workers <= 0 {
+		config.Workers = 5
+	}
+	if config.SubmissionTimeout <= 0 {
+		config.SubmissionTimeout = 30 * time.Second
+	}
+	if config.RetryBackoff <= 0 {
+		config.RetryBackoff = time.Minute
+	}
+	if config.MaxRetryBackoff <= 0 {
+		config.MaxRetryBackoff = time.Hour
+	}
+	if config.MaxRetryBackoff < config.RetryBackoff {
+		config.MaxRetryBackoff = config.RetryBackoff
+	}
+	if config.MaxAge <= 0 {
+		config.MaxAge = 7 * 24 * time.Hour
+	}
+
+	err := os.MkdirAll(config.Dir, 0700)
+	if err != nil {
+		return nil, err
+	}
+	files, err := ioutil.ReadDir(config.Dir)
+	if err != nil {
+		return nil, err
+	}
+	certs := make(map[string]*queuedFinalCert)
+	for _, file := range files {
+		if file.IsDir() || filepath.Ext(file.Name()) != ".json" {
+			continue
+		}
+		contents, err := ioutil.ReadFile(filepath.Join(config.Dir, file.Name()))
+		if err != nil {
+			return nil, err
+		}
+		var qc queuedFinalCert
+		err = json.Unmarshal(contents, &qc)
+		if err != nil {
+			// A file can't be left partially written, since each is written
+			// to a temporary file first, so this is worth stopping for.
+			return nil, fmt.Errorf("reading queued final certificate %s: %w", file.Name(), err)
+		}
+		certs[strings.TrimSuffix(file.Name(), ".json")] = &qc
+	}
+
+	length := prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "final_cert_queue_length",
+		Help: "Number of final certificates waiting to be submitted to CT logs",
+	})
+	stats.MustRegister(length)
+	submissions := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "final_cert_queue_submissions",
+		Help: "Number of queued final certificate submissions to CT logs, by log and result (success, failure or dropped)",
+	}, []string{"log", "result"})
+	stats.MustRegister(submissions)
+	length.Set(float64(len(certs)))
+
+	return &finalCertQueue{
+		certs:       certs,
+		config:      config,
+		submit:      pub.SubmitToSingleCTWithResult,
+		clk:         clk,
+		log:         pub.log,
+		length:      length,
+		submissions: submissions,
+		wake:        make(chan struct{}, 1),
+		stop:        make(chan struct{}),
+		done:        make(chan struct{}),
+	}, nil
+}
+
+// path returns the file the certificate with the given serial is stored in.
+func (q *finalCertQueue) path(serial string) string {
+	return filepath.Join(q.config.Dir, serial+".json")
+}
+
+// write atomically replaces the file the certificate with the given serial is
+// stored in. It must be called with q.mu held.
+func (q *finalCertQueue) write(serial string, qc *queuedFinalCert) error {
+	contents, err := json.Marshal(qc)
+	if err != nil {
+		return err
+	}
+	path := q.path(serial)
+	tmpPath := path + ".tmp"
+	tmp, err := os.OpenFile(tmpPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	_, err = tmp.Write(contents)
+	if err == nil {
+		err = tmp.Sync()
+	}
+	closeErr := tmp.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmpPath, path)
+	}
+	if err != nil {
+		_ = os.Remove(tmpPath)
+	}
+	return err
+}
+
+// remove removes the certificate with the given serial from the queue. It
+// must be called with q.mu held.
+func (q *finalCertQueue) remove(serial string) {
+	delete(q.certs, serial)
+	q.length.Set(float64(len(q.certs)))
+	err := os.Remove(q.path(serial))
+	if err != nil && !os.IsNotExist(err) {
+		// The certificate will be submitted again after a restart, which logs
+		// will accept.
+		q.log.Warningf("Failed to remove queued final certificate %s: %s", serial, err)
+	}
+}
+
+// add queues cert for submission to logs, returning once it's been stored.
+func (q *finalCertQueue) add(cert *x509.Certificate, logs []*pubpb.FinalCertLog) error {
+	serial := core.SerialToString(cert.SerialNumber)
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	qc, present := q.certs[serial]
+	var updated queuedFinalCert
+	if present {
+		updated = *qc
+		updated.Logs = append([]queuedLog{}, qc.Logs...)
+	} else {
+		updated = queuedFinalCert{
+			DER:         cert.Raw,
+			Queued:      q.clk.Now(),
+			NotAfter:    cert.NotAfter,
+			NextAttempt: q.clk.Now(),
+		}
+	}
+	var added bool
+	for _, l := range logs {
+		var queued bool
+		for _, ql := range updated.Logs {
+			if ql.PublicKey == l.LogPublicKey {
+				queued = true
+				break
+			}
+		}
+		if !queued {
+			updated.Logs = append(updated.Logs, queuedLog{URL: l.LogURL, PublicKey: l.LogPublicKey})
+			added = true
+		}
+	}
+	if !added {
+		return nil
+	}
+	if present {
+		// Submit to the newly added logs without waiting for the existing
+		// ones' backoff.
+		updated.NextAttempt = q.clk.Now()
+	}
+
+	err := q.write(serial, &updated)
+	if err != nil {
+		return fmt.Errorf("storing queued final certificate %s: %w", serial, err)
+	}
+	q.certs[serial] = &updated
+	q.length.Set(float64(len(q.certs)))
+
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// processDue drops any certificates which have expired or been queued for
+// longer than the maximum age, then makes one attempt to submit each of the
+// remaining certificates which are due to each of their logs, returning once
+// every attempt has finished.
+func (q *finalCertQueue) processDue(ctx context.Context) {
+	now := q.clk.Now()
+	var due []string
+	q.mu.Lock()
+	for serial, qc := range q.certs {
+		if now.After(qc.NotAfter) || now.Sub(qc.Queued) > q.config.MaxAge {
+			q.log.AuditErrf("Dropping queued final certificate %s after %d attempts, without submitting it to %d CT logs",
+				serial, qc.Attempts, len(qc.Logs))
+			for _, l := range qc.Logs {
+				q.submissions.With(prometheus.Labels{"log": l.URL, "result": "dropped"}).Inc()
+			}
+			q.remove(serial)
+			continue
+		}
+		if !qc.NextAttempt.After(now) {
+			due = append(due, serial)
+		}
+	}
+	q.mu.Unlock()
+
+	sem := make(chan struct{}, q.config.Workers)
+	var wg sync.WaitGroup
+	for _, serial := range due {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(serial string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			q.attempt(ctx, serial)
+		}(serial)
+	}
+	wg.Wait()
+}
+
+// attempt submits the certificate with the given serial to each of the logs
+// it's queued for. It's removed from the queue if every log accepts it, and
+// otherwise retried later for the logs which didn't.
+func (q *finalCertQueue) attempt(ctx context.Context, serial string) {
+	// Only processDue removes certificates, so this one is still queued, but
+	// logs may be added to it while it's being submitted, so it's copied.
+	q.mu.Lock()
+	der := q.certs[serial].DER
+	logs := append([]queuedLog{}, q.certs[serial].Logs...)
+	q.mu.Unlock()
+
+	accepted := make(map[string]bool, len(logs))
+	for _, l := range logs {
+		submitCtx, cancel := context.WithTimeout(ctx, q.config.SubmissionTimeout)
+		_, err := q.submit(submitCtx, &pubpb.Request{
+			Der:          der,
+			LogURL:       l.URL,
+			LogPublicKey: l.PublicKey,
+		})
+		cancel()
+		if err != nil {
+			q.submissions.With(prometheus.Labels{"log": l.URL, "result": "failure"}).Inc()
+			continue
+		}
+		q.submissions.With(prometheus.Labels{"log": l.URL, "result": "success"}).Inc()
+		accepted[l.PublicKey] = true
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	updated := *q.certs[serial]
+	updated.Logs = nil
+	for _, l := range q.certs[serial].Logs {
+		if !accepted[l.PublicKey] {
+			updated.Logs = append(updated.Logs, l)
+		}
+	}
+	if len(updated.Logs) == 0 {
+		q.remove(serial)
+		return
+	}
+	updated.Attempts++
+	updated.NextAttempt = q.clk.Now().Add(core.RetryBackoff(updated.Attempts, q.config.RetryBackoff, q.config.MaxRetryBackoff, 2))
+	err := q.write(serial, &updated)
+	if err != nil {
+		// The logs which accepted the certificate will be submitted to again
+		// after a restart, which they'll accept.
+		q.log.Warningf("Failed to update queued final certificate %s: %s", serial, err)
+	}
+	q.certs[serial] = &updated
+}
+
+// run processes the queue whenever a certificate is added to it, or
+// config.RetryBackoff has passed, until stopped.
+func (q *finalCertQueue) run() {
+	defer close(q.done)
+	for {
+		q.processDue(context.Background())
+		select {
+		case <-q.stop:
+			return
+		case <-q.wake:
+		case <-q.clk.After(q.config.RetryBackoff):
+		}
+	}
+}
+
+// EnableFinalCertQueue loads the queue of final certificates from
+// config.Dir, and starts submitting them to CT logs in the background. It
+// must be called before the publisher starts serving QueueFinalCert
+// requests.
+func (pub *Impl) EnableFinalCertQueue(config FinalCertQueueConfig, clk clock.Clock, stats prometheus.Registerer) error {
+	q, err := pub.loadFinalCertQueue(config, clk, stats)
+	if err != nil {
+		return err
+	}
+	pub.log.Infof("Loaded %d queued final certificates from %s", len(q.certs), q.config.Dir)
+	pub.finalCertQueue = q
+	go q.run()
+	return nil
+}
+
+// StopFinalCertQueue waits for any submissions in progress to finish, and
+// stops the final certificate queue. Certificates which are still queued are
+// submitted once the queue is next loaded.
+func (pub *Impl) StopFinalCertQueue() {
+	if pub.finalCertQueue == nil {
+		return
+	}
+	close(pub.finalCertQueue.stop)
+	<-pub.finalCertQueue.done
+}
+
+// QueueFinalCert stores a final certificate to be submitted to each of the
+// requested CT logs in the background, returning once it's stored.
+func (pub *Impl) QueueFinalCert(ctx context.Context, req *pubpb.FinalCertRequest) (*pubpb.FinalCertQueued, error) {
+	if pub.finalCertQueue == nil {
+		return nil, errors.New("final certificate queue isn't enabled")
+	}
+	if req == nil || len(req.Der) == 0 || len(req.Logs) == 0 {
+		return nil, errors.New("incomplete final certificate request")
+	}
+	cert, err := x509.ParseCertificate(req.Der)
+	if err != nil {
+		pub.log.AuditErrf("Failed to parse certificate: %s", err)
+		return nil, err
+	}
+	err = pub.finalCertQueue.add(cert, req.Logs)
+	if err != nil {
+		pub.log.AuditErrf("Failed to queue final certificate: %s", err)
+		return nil, err
+	}
+	return &pubpb.FinalCertQueued{}, nil
+}
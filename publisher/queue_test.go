@@ -0,0 +1,194 @@
+package publisher
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jmhodges/clock"
+
+	"github.com/letsencrypt/boulder/core"
+	"github.com/letsencrypt/boulder/metrics"
+	pubpb "github.com/letsencrypt/boulder/publisher/proto"
+	"github.com/letsencrypt/boulder/test"
+)
+
+// queueTestCert returns a certificate which expires 90 days after now.
+func queueTestCert(t *testing.T, serial int64, now time.Time) *x509.Certificate {
+	k, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	test.AssertNotError(t, err, "generating key")
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		NotBefore:    now,
+		NotAfter:     now.Add(90 * 24 * time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, k.Public(), k)
+	test.AssertNotError(t, err, "creating certificate")
+	cert, err := x509.ParseCertificate(der)
+	test.AssertNotError(t, err, "parsing certificate")
+	return cert
+}
+
+// fakeLogs records the logs certificates are submitted to, failing the
+// submissions to those in failing.
+type fakeLogs struct {
+	sync.Mutex
+	failing   map[string]bool
+	submitted []string
+}
+
+func (fl *fakeLogs) submit(_ context.Context, req *pubpb.Request) (*pubpb.Result, error) {
+	fl.Lock()
+	defer fl.Unlock()
+	fl.submitted = append(fl.submitted, req.LogURL)
+	if fl.failing[req.LogURL] {
+		return nil, errors.New("log unavailable")
+	}
+	return &pubpb.Result{}, nil
+}
+
+func (fl *fakeLogs) reset() []string {
+	fl.Lock()
+	defer fl.Unlock()
+	submitted := fl.submitted
+	fl.submitted = nil
+	return submitted
+}
+
+func testQueue(t *testing.T, dir string, fc clock.Clock, fl *fakeLogs) *finalCertQueue {
+	pub, _, _ := setup(t)
+	q, err := pub.loadFinalCertQueue(FinalCertQueueConfig{
+		Dir:             dir,
+		RetryBackoff:    time.Minute,
+		MaxRetryBackoff: time.Hour,
+		MaxAge:          24 * time.Hour,
+	}, fc, metrics.NoopRegisterer)
+	test.AssertNotError(t, err, "loading final cert queue")
+	q.submit = fl.submit
+	return q
+}
+
+func TestFinalCertQueueRetries(t *testing.T) {
+	dir, err := ioutil.TempDir("", "final-cert-queue")
+	test.AssertNotError(t, err, "creating temp dir")
+	defer os.RemoveAll(dir)
+	fc := clock.NewFake()
+	fc.Set(time.Date(2021, 2, 15, 0, 0, 0, 0, time.UTC))
+	fl := &fakeLogs{failing: map[string]bool{"bad": true}}
+	q := testQueue(t, dir, fc, fl)
+
+	cert := queueTestCert(t, 1, fc.Now())
+	serial := core.SerialToString(cert.SerialNumber)
+	err = q.add(cert, []*pubpb.FinalCertLog{
+		{LogURL: "good", LogPublicKey: "a"},
+		{LogURL: "bad", LogPublicKey: "b"},
+	})
+	test.AssertNotError(t, err, "queueing certificate")
+
+	// Both logs are attempted, and only the one which failed is kept.
+	q.processDue(context.Background())
+	test.AssertEquals(t, len(fl.reset()), 2)
+	test.AssertEquals(t, len(q.certs), 1)
+	test.AssertDeepEquals(t, q.certs[serial].Logs, []queuedLog{{URL: "bad", PublicKey: "b"}})
+	test.AssertEquals(t, q.certs[serial].Attempts, 1)
+
+	// The failed log isn't retried until its backoff has passed.
+	q.processDue(context.Background())
+	test.AssertEquals(t, len(fl.reset()), 0)
+
+	// The queue survives a restart.
+	q = testQueue(t, dir, fc, fl)
+	test.AssertEquals(t, len(q.certs), 1)
+	test.AssertDeepEquals(t, q.certs[serial].Logs, []queuedLog{{URL: "bad", PublicKey: "b"}})
+	test.AssertByteEquals(t, q.certs[serial].DER, cert.Raw)
+
+	// Once the log accepts the certificate it's removed from the queue and
+	// from disk.
+	fl.failing = nil
+	fc.Add(2 * time.Minute)
+	q.processDue(context.Background())
+	test.AssertDeepEquals(t, fl.reset(), []string{"bad"})
+	test.AssertEquals(t, len(q.certs), 0)
+	_, err = os.Stat(filepath.Join(dir, serial+".json"))
+	test.Assert(t, os.IsNotExist(err), "submitted certificate wasn't removed from disk")
+}
+
+func TestFinalCertQueueDuplicates(t *testing.T) {
+	dir, err := ioutil.TempDir("", "final-cert-queue")
+	test.AssertNotError(t, err, "creating temp dir")
+	defer os.RemoveAll(dir)
+	fc := clock.NewFake()
+	fc.Set(time.Date(2021, 2, 15, 0, 0, 0, 0, time.UTC))
+	fl := &fakeLogs{failing: map[string]bool{"a": true}}
+	q := testQueue(t, dir, fc, fl)
+
+	cert := queueTestCert(t, 1, fc.Now())
+	serial := core.SerialToString(cert.SerialNumber)
+	err = q.add(cert, []*pubpb.FinalCertLog{{LogURL: "a", LogPublicKey: "a"}})
+	test.AssertNotError(t, err, "queueing certificate")
+	q.processDue(context.Background())
+	fl.reset()
+
+	// Queueing the certificate again for the same log changes nothing.
+	err = q.add(cert, []*pubpb.FinalCertLog{{LogURL: "a", LogPublicKey: "a"}})
+	test.AssertNotError(t, err, "queueing certificate again")
+	test.AssertEquals(t, len(q.certs), 1)
+	test.AssertEquals(t, q.certs[serial].Attempts, 1)
+	q.processDue(context.Background())
+	test.AssertEquals(t, len(fl.reset()), 0)
+
+	// Queueing it for another log adds that log, which is submitted to
+	// without waiting for the backoff.
+	err = q.add(cert, []*pubpb.FinalCertLog{{LogURL: "b", LogPublicKey: "b"}})
+	test.AssertNotError(t, err, "queueing certificate for another log")
+	test.AssertEquals(t, len(q.certs), 1)
+	test.AssertEquals(t, len(q.certs[serial].Logs), 2)
+	q.processDue(context.Background())
+	test.AssertEquals(t, len(fl.reset()), 2)
+	test.AssertDeepEquals(t, q.certs[serial].Logs, []queuedLog{{URL: "a", PublicKey: "a"}})
+}
+
+func TestFinalCertQueueDropsOld(t *testing.T) {
+	dir, err := ioutil.TempDir("", "final-cert-queue")
+	test.AssertNotError(t, err, "creating temp dir")
+	defer os.RemoveAll(dir)
+	fc := clock.NewFake()
+	fc.Set(time.Date(2021, 2, 15, 0, 0, 0, 0, time.UTC))
+	fl := &fakeLogs{failing: map[string]bool{"a": true}}
+	q := testQueue(t, dir, fc, fl)
+
+	cert := queueTestCert(t, 1, fc.Now())
+	err = q.add(cert, []*pubpb.FinalCertLog{{LogURL: "a", LogPublicKey: "a"}})
+	test.AssertNotError(t, err, "queueing certificate")
+	q.processDue(context.Background())
+	fl.reset()
+
+	// Once the certificate has been queued for longer than MaxAge it's
+	// dropped without being submitted again.
+	fc.Add(25 * time.Hour)
+	q.processDue(context.Background())
+	test.AssertEquals(t, len(fl.reset()), 0)
+	test.AssertEquals(t, len(q.certs), 0)
+	files, err := ioutil.ReadDir(dir)
+	test.AssertNotError(t, err, "reading queue dir")
+	test.AssertEquals(t, len(files), 0)
+}
+
+func TestQueueFinalCertNotEnabled(t *testing.T) {
+	pub, leaf, _ := setup(t)
+	_, err := pub.QueueFinalCert(context.Background(), &pubpb.FinalCertRequest{
+		Der:  leaf.Raw,
+		Logs: []*pubpb.FinalCertLog{{LogURL: "a", LogPublicKey: "a"}},
+	})
+	test.AssertError(t, err, "QueueFinalCert succeeded without a queue")
+}
@@ -1,8 +1,11 @@
 package ra
 
 import (
+	"bytes"
 	"context"
+	"crypto"
 	"crypto/x509"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -17,6 +20,7 @@ import (
 	"github.com/jmhodges/clock"
 	"github.com/letsencrypt/boulder/akamai"
 	akamaipb "github.com/letsencrypt/boulder/akamai/proto"
+	"github.com/letsencrypt/boulder/bdns"
 	capb "github.com/letsencrypt/boulder/ca/proto"
 	"github.com/letsencrypt/boulder/core"
 	corepb "github.com/letsencrypt/boulder/core/proto"
@@ -43,6 +47,7 @@ import (
 	"github.com/weppos/publicsuffix-go/publicsuffix"
 	"golang.org/x/crypto/ocsp"
 	grpc "google.golang.org/grpc"
+	jose "gopkg.in/square/go-jose.v2"
 )
 
 type caaChecker interface {
@@ -51,6 +56,11 @@ type caaChecker interface {
 		in *vapb.IsCAAValidRequest,
 		opts ...grpc.CallOption,
 	) (*vapb.IsCAAValidResponse, error)
+	IsCAAValidBatch(
+		ctx context.Context,
+		in *vapb.IsCAAValidBatchRequest,
+		opts ...grpc.CallOption,
+	) (*vapb.IsCAAValidBatchResponse, error)
 }
 
 // RegistrationAuthorityImpl defines an RA.
@@ -76,6 +86,9 @@ type RegistrationAuthorityImpl struct {
 	maxNames                     int
 	reuseValidAuthz              bool
 	orderLifetime                time.Duration
+	// profileLifetimes overrides orderLifetime and pendingAuthorizationLifetime
+	// for orders which request a certificate profile. See SetProfileLifetimes.
+	profileLifetimes map[string]ProfileLifetimes
 
 	issuers map[issuance.IssuerNameID]*issuance.Certificate
 	purger  akamaipb.AkamaiPurgerClient
@@ -90,6 +103,27 @@ type RegistrationAuthorityImpl struct {
 	reusedValidAuthzCounter prometheus.Counter
 	recheckCAACounter       prometheus.Counter
 	newCertCounter          prometheus.Counter
+	contactRejections       *prometheus.CounterVec
+
+	// blockedContactDomains and contactDNS configure the optional checks of
+	// contact email domains. See SetContactDomainChecks.
+	blockedContactDomains map[string]bool
+	contactDNS            bdns.Client
+
+	// rolledOverKeyBlockPeriod is how long after an account's key is rolled
+	// over the old key can't be used to register a new account. See
+	// SetRolledOverKeyBlockPeriod.
+	rolledOverKeyBlockPeriod time.Duration
+
+	// ocspObjects, if not nil, is the bucket to which revoked OCSP responses
+	// are written. See SetOCSPObjectStorage.
+	ocspObjects ocspObjectWriter
+}
+
+// ocspObjectWriter writes objects to a bucket. It's satisfied by
+// *objectstore.Client.
+type ocspObjectWriter interface {
+	Put(name string, body []byte, contentType string) error
 }
 
 // NewRegistrationAuthorityImpl constructs a new RA object.
@@ -135,7 +169,7 @@ func NewRegistrationAuthorityImpl(
 
 	rateLimitCounter := prometheus.NewCounterVec(prometheus.CounterOpts{
 		Name: "ra_ratelimits",
-		Help: "A counter of RA ratelimit checks labelled by type and pass/exceed",
+		Help: "A counter of RA ratelimit checks labelled by type and pass/exceed/exempt",
 	}, []string{"limit", "result"})
 	stats.MustRegister(rateLimitCounter)
 
@@ -169,6 +203,12 @@ func NewRegistrationAuthorityImpl(
 	}, []string{"reason"})
 	stats.MustRegister(revocationReasonCounter)
 
+	contactRejections := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "contact_rejections",
+		Help: "A counter of account contacts rejected at registration or update, by reason",
+	}, []string{"reason"})
+	stats.MustRegister(contactRejections)
+
 	issuersByID := make(map[issuance.IssuerNameID]*issuance.Certificate)
 	for _, issuer := range issuers {
 		issuersByID[issuer.NameID()] = issuer
@@ -198,6 +238,7 @@ func NewRegistrationAuthorityImpl(
 		recheckCAACounter:            recheckCAACounter,
 		newCertCounter:               newCertCounter,
 		revocationReasonCounter:      revocationReasonCounter,
+		contactRejections:            contactRejections,
 	}
 	return ra
 }
@@ -293,19 +334,42 @@ func (ra *RegistrationAuthorityImpl) checkRegistrationIPLimit(
 	return nil
 }
 
+// exemptIP returns true if ip is exempt from the limit, counting the exemption
+// under the limit's name, so that the use of exemptions can be seen.
+func (ra *RegistrationAuthorityImpl) exemptIP(limit ratelimit.RateLimitPolicy, name string, ip net.IP) bool {
+	if !limit.ExemptIP(ip) {
+		return false
+	}
+	ra.rateLimitCounter.WithLabelValues(name, "exempt").Inc()
+	return true
+}
+
+// exemptRegistration returns true if regID is exempt from the limit, counting
+// the exemption under the limit's name, so that the use of exemptions can be
+// seen.
+func (ra *RegistrationAuthorityImpl) exemptRegistration(limit ratelimit.RateLimitPolicy, name string, regID int64) bool {
+	if !limit.ExemptRegistration(regID) {
+		return false
+	}
+	ra.rateLimitCounter.WithLabelValues(name, "exempt").Inc()
+	return true
+}
+
 // checkRegistrationLimits enforces the RegistrationsPerIP and
 // RegistrationsPerIPRange limits
 func (ra *RegistrationAuthorityImpl) checkRegistrationLimits(ctx context.Context, ip net.IP) error {
 	// Check the registrations per IP limit using the CountRegistrationsByIP SA
 	// function that matches IP addresses exactly
 	exactRegLimit := ra.rlPolicies.RegistrationsPerIP()
-	err := ra.checkRegistrationIPLimit(ctx, exactRegLimit, ip, ra.SA.CountRegistrationsByIP)
-	if err != nil {
-		ra.rateLimitCounter.WithLabelValues("registrations_by_ip", "exceeded").Inc()
-		ra.log.Infof("Rate limit exceeded, RegistrationsByIP, IP: %s", ip)
-		return err
+	if !ra.exemptIP(exactRegLimit, "registrations_by_ip", ip) {
+		err := ra.checkRegistrationIPLimit(ctx, exactRegLimit, ip, ra.SA.CountRegistrationsByIP)
+		if err != nil {
+			ra.rateLimitCounter.WithLabelValues("registrations_by_ip", "exceeded").Inc()
+			ra.log.Infof("Rate limit exceeded, RegistrationsByIP, IP: %s", ip)
+			return err
+		}
+		ra.rateLimitCounter.WithLabelValues("registrations_by_ip", "pass").Inc()
 	}
-	ra.rateLimitCounter.WithLabelValues("registrations_by_ip", "pass").Inc()
 
 	// We only apply the fuzzy reg limit to IPv6 addresses.
 	// Per https://golang.org/pkg/net/#IP.To4 "If ip is not an IPv4 address, To4
@@ -318,7 +382,10 @@ func (ra *RegistrationAuthorityImpl) checkRegistrationLimits(ctx context.Context
 	// CountRegistrationsByIPRange SA function that fuzzy-matches IPv6 addresses
 	// within a larger address range
 	fuzzyRegLimit := ra.rlPolicies.RegistrationsPerIPRange()
-	err = ra.checkRegistrationIPLimit(ctx, fuzzyRegLimit, ip, ra.SA.CountRegistrationsByIPRange)
+	if ra.exemptIP(fuzzyRegLimit, "registrations_by_ip_range", ip) {
+		return nil
+	}
+	err := ra.checkRegistrationIPLimit(ctx, fuzzyRegLimit, ip, ra.SA.CountRegistrationsByIPRange)
 	if err != nil {
 		ra.rateLimitCounter.WithLabelValues("registrations_by_ip_range", "exceeded").Inc()
 		ra.log.Infof("Rate limit exceeded, RegistrationsByIPRange, IP: %s", ip)
@@ -339,6 +406,9 @@ func (ra *RegistrationAuthorityImpl) NewRegistration(ctx context.Context, init c
 	if err := ra.checkRegistrationLimits(ctx, init.InitialIP); err != nil {
 		return core.Registration{}, err
 	}
+	if err := ra.checkRolledOverKey(ctx, init.Key); err != nil {
+		return core.Registration{}, err
+	}
 
 	reg := core.Registration{
 		Key:    init.Key,
@@ -364,6 +434,49 @@ func (ra *RegistrationAuthorityImpl) NewRegistration(ctx context.Context, init c
 	return reg, nil
 }
 
+// SetRolledOverKeyBlockPeriod stops keys which an account has rolled over
+// away from from being used to register new accounts for the given period
+// after the rollover. It relies on the SA's key rollover history, and does
+// nothing if the period is zero.
+func (ra *RegistrationAuthorityImpl) SetRolledOverKeyBlockPeriod(period time.Duration) {
+	ra.rolledOverKeyBlockPeriod = period
+}
+
+// SetOCSPObjectStorage makes the RA write the OCSP response for each
+// certificate it revokes to the given bucket, in which the object storage
+// OCSP responder serves the responses the ocsp-updater writes, so that it
+// serves the revoked response straight away.
+func (ra *RegistrationAuthorityImpl) SetOCSPObjectStorage(objects ocspObjectWriter) {
+	ra.ocspObjects = objects
+}
+
+// checkRolledOverKey returns an error if key has been rolled over away from
+// by an account within the period set by SetRolledOverKeyBlockPeriod.
+func (ra *RegistrationAuthorityImpl) checkRolledOverKey(ctx context.Context, key *jose.JSONWebKey) error {
+	if ra.rolledOverKeyBlockPeriod == 0 {
+		return nil
+	}
+	keyHash, err := core.KeyDigest(key)
+	if err != nil {
+		return berrors.MalformedError("invalid public key: %s", err)
+	}
+	rollovers, err := ra.SA.GetKeyRollovers(ctx, &sapb.GetKeyRolloversRequest{
+		KeyHash: keyHash[:],
+		Since:   ra.clk.Now().Add(-ra.rolledOverKeyBlockPeriod).UnixNano(),
+	})
+	if err != nil {
+		return err
+	}
+	for _, rollover := range rollovers.Rollovers {
+		if bytes.Equal(rollover.OldKeyHash, keyHash[:]) {
+			return berrors.UnauthorizedError(
+				"key was recently rolled over away from by another account and can't be used for a new one until %s",
+				time.Unix(0, rollover.RolledOver).Add(ra.rolledOverKeyBlockPeriod).UTC().Format(time.RFC3339))
+		}
+	}
+	return nil
+}
+
 // validateContacts checks the provided list of contacts, returning an error if
 // any are not acceptable. Unacceptable contacts lists include:
 // * An empty list
@@ -379,6 +492,7 @@ func (ra *RegistrationAuthorityImpl) validateContacts(ctx context.Context, conta
 		return nil // Nothing to validate
 	}
 	if ra.maxContactsPerReg > 0 && len(*contacts) > ra.maxContactsPerReg {
+		ra.contactRejections.WithLabelValues("too_many").Inc()
 		return berrors.MalformedError(
 			"too many contacts provided: %d > %d",
 			len(*contacts),
@@ -387,34 +501,19 @@ func (ra *RegistrationAuthorityImpl) validateContacts(ctx context.Context, conta
 	}
 
 	for _, contact := range *contacts {
-		if contact == "" {
-			return berrors.InvalidEmailError("empty contact")
-		}
-		parsed, err := url.Parse(contact)
+		reason, err := ra.validateContact(ctx, contact)
 		if err != nil {
-			return berrors.InvalidEmailError("invalid contact")
-		}
-		if parsed.Scheme != "mailto" {
-			return berrors.InvalidEmailError("contact method %q is not supported", parsed.Scheme)
-		}
-		if parsed.RawQuery != "" {
-			return berrors.InvalidEmailError("contact email [%q] contains hfields", contact)
-		}
-		if !core.IsASCII(contact) {
-			return berrors.InvalidEmailError(
-				"contact email [%q] contains non-ASCII characters",
-				contact,
-			)
-		}
-		if err := policy.ValidEmail(parsed.Opaque); err != nil {
+			ra.contactRejections.WithLabelValues(reason).Inc()
 			return err
 		}
 	}
 
 	// NOTE(@cpu): For historical reasons (</3) we store ACME account contact
 	// information de-normalized in a fixed size `contact` field on the
-	// `registrations` table. At the time of writing this field is VARCHAR(191)
-	// That means the largest marshalled JSON value we can store is 191 bytes.
+	// `registrations` table. At the time of writing this field is VARCHAR(512),
+	// but the SA may encrypt contacts before storing them, and ciphertext is
+	// longer than plaintext. We limit the marshalled JSON value to 191 bytes so
+	// that encrypted contacts still fit.
 	const maxContactBytes = 191
 	if jsonBytes, err := json.Marshal(*contacts); err != nil {
 		// This shouldn't happen with a simple []string but if it does we want the
@@ -422,6 +521,7 @@ func (ra *RegistrationAuthorityImpl) validateContacts(ctx context.Context, conta
 		// return a bare error and not a berror here.
 		return fmt.Errorf("failed to marshal reg.Contact to JSON: %#v", *contacts)
 	} else if len(jsonBytes) >= maxContactBytes {
+		ra.contactRejections.WithLabelValues("too_long").Inc()
 		return berrors.InvalidEmailError(
 			"too many/too long contact(s). Please use shorter or fewer email addresses")
 	}
@@ -429,9 +529,100 @@ func (ra *RegistrationAuthorityImpl) validateContacts(ctx context.Context, conta
 	return nil
 }
 
+// validateContact checks a single contact URL. If it's rejected, the reason,
+// for labelling metrics, is returned along with the error.
+func (ra *RegistrationAuthorityImpl) validateContact(ctx context.Context, contact string) (string, error) {
+	if contact == "" {
+		return "empty", berrors.InvalidEmailError("empty contact")
+	}
+	parsed, err := url.Parse(contact)
+	if err != nil {
+		return "unparseable", berrors.InvalidEmailError("invalid contact")
+	}
+	if parsed.Scheme != "mailto" {
+		return "unsupported_scheme", berrors.InvalidEmailError("contact method %q is not supported", parsed.Scheme)
+	}
+	if parsed.RawQuery != "" {
+		return "hfields", berrors.InvalidEmailError("contact email [%q] contains hfields", contact)
+	}
+	if !core.IsASCII(contact) {
+		return "non_ascii", berrors.InvalidEmailError(
+			"contact email [%q] contains non-ASCII characters",
+			contact,
+		)
+	}
+	if err := policy.ValidEmail(parsed.Opaque); err != nil {
+		return "invalid_email", err
+	}
+	return ra.checkContactDomain(ctx, parsed.Opaque)
+}
+
+// SetContactDomainChecks enables optional checks of the domains of contact
+// email addresses, beyond those made by policy.ValidEmail. Addresses at the
+// blocked domains, such as common typos of popular mail providers, or their
+// subdomains, are rejected. If dnsClient isn't nil, addresses at domains
+// which can't receive mail, because they don't exist, have a null MX (RFC
+// 7505), or have neither MX records nor addresses, are rejected too.
+func (ra *RegistrationAuthorityImpl) SetContactDomainChecks(blockedDomains []string, dnsClient bdns.Client) {
+	blocked := make(map[string]bool, len(blockedDomains))
+	for _, domain := range blockedDomains {
+		blocked[strings.ToLower(strings.TrimSuffix(domain, "."))] = true
+	}
+	ra.blockedContactDomains = blocked
+	ra.contactDNS = dnsClient
+}
+
+// checkContactDomain makes the checks enabled by SetContactDomainChecks of the
+// domain of a contact email address, which policy.ValidEmail has accepted.
+func (ra *RegistrationAuthorityImpl) checkContactDomain(ctx context.Context, address string) (string, error) {
+	domain := strings.ToLower(address[strings.LastIndex(address, "@")+1:])
+	labels := strings.Split(domain, ".")
+	for i := range labels {
+		if ra.blockedContactDomains[strings.Join(labels[i:], ".")] {
+			return "blocked_domain", berrors.InvalidEmailError(
+				"contact email %q has forbidden domain %q", address, domain)
+		}
+	}
+	if ra.contactDNS == nil {
+		return "", nil
+	}
+
+	hosts, err := ra.contactDNS.LookupMX(ctx, domain)
+	if err != nil {
+		var dnsErr *bdns.Error
+		if errors.As(err, &dnsErr) && dnsErr.NameError() {
+			return "nxdomain", berrors.InvalidEmailError(
+				"contact email %q has domain %q, which doesn't exist", address, domain)
+		}
+		// A failure to resolve the domain may be temporary, and isn't reason
+		// enough to reject the contact.
+		ra.log.Warningf("checking MX records of contact email domain %q: %s", domain, err)
+		return "", nil
+	}
+	if len(hosts) == 1 && hosts[0] == "." {
+		return "null_mx", berrors.InvalidEmailError(
+			"contact email %q has domain %q, which doesn't accept email (it has a null MX record)", address, domain)
+	}
+	if len(hosts) > 0 {
+		return "", nil
+	}
+	// Without MX records, mail is delivered to the domain's own address
+	// (RFC 5321 section 5.1).
+	addrs, err := ra.contactDNS.LookupHost(ctx, domain)
+	if err != nil {
+		ra.log.Warningf("looking up addresses of contact email domain %q: %s", domain, err)
+		return "", nil
+	}
+	if len(addrs) == 0 {
+		return "no_mx", berrors.InvalidEmailError(
+			"contact email %q has domain %q, which can't receive email (it has no MX records or addresses)", address, domain)
+	}
+	return "", nil
+}
+
 func (ra *RegistrationAuthorityImpl) checkPendingAuthorizationLimit(ctx context.Context, regID int64) error {
 	limit := ra.rlPolicies.PendingAuthorizationsPerAccount()
-	if limit.Enabled() {
+	if limit.Enabled() && !ra.exemptRegistration(limit, "pending_authorizations_by_registration_id", regID) {
 		countPB, err := ra.SA.CountPendingAuthorizations2(ctx, &sapb.RegistrationID{
 			Id: regID,
 		})
@@ -473,7 +664,7 @@ func (ra *RegistrationAuthorityImpl) checkInvalidAuthorizationLimits(ctx context
 
 func (ra *RegistrationAuthorityImpl) checkInvalidAuthorizationLimit(ctx context.Context, regID int64, hostname string) error {
 	limit := ra.rlPolicies.InvalidAuthorizationsPerAccount()
-	if !limit.Enabled() {
+	if !limit.Enabled() || ra.exemptRegistration(limit, "invalid_authorizations_by_registration_id", regID) {
 		return nil
 	}
 	latest := ra.clk.Now().Add(ra.pendingAuthorizationLifetime)
@@ -505,7 +696,7 @@ func (ra *RegistrationAuthorityImpl) checkInvalidAuthorizationLimit(ctx context.
 // specified threshold of new orders within the specified time window.
 func (ra *RegistrationAuthorityImpl) checkNewOrdersPerAccountLimit(ctx context.Context, acctID int64) error {
 	limit := ra.rlPolicies.NewOrdersPerAccount()
-	if !limit.Enabled() {
+	if !limit.Enabled() || ra.exemptRegistration(limit, "new_order_by_registration_id", acctID) {
 		return nil
 	}
 	latest := ra.clk.Now()
@@ -535,6 +726,11 @@ func (ra *RegistrationAuthorityImpl) NewAuthorization(ctx context.Context, reque
 		return core.Authorization{}, err
 	}
 
+	// Dedicated accounts may only authorize their own domains
+	if err := ra.checkAccountAllowlist(ctx, regID, []string{identifier.Value}); err != nil {
+		return core.Authorization{}, err
+	}
+
 	if err := ra.checkPendingAuthorizationLimit(ctx, regID); err != nil {
 		return core.Authorization{}, err
 	}
@@ -609,7 +805,7 @@ func (ra *RegistrationAuthorityImpl) NewAuthorization(ctx context.Context, reque
 		}
 	}
 
-	authzPB, err := ra.createPendingAuthz(ctx, regID, identifier)
+	authzPB, err := ra.createPendingAuthz(ctx, regID, identifier, ra.pendingAuthorizationLifetime)
 	if err != nil {
 		return core.Authorization{}, err
 	}
@@ -785,8 +981,9 @@ func (ra *RegistrationAuthorityImpl) checkAuthorizationsCAA(
 			return berrors.InternalServerError("found an authorization with a nil Expires field: id %s", authz.ID)
 		} else if authz.Expires.Before(now) {
 			badNames = append(badNames, name)
-		} else if authz.Expires.Before(caaRecheckTime) {
-			// Ensure that CAA is rechecked for this name
+		} else if authz.Expires.Before(caaRecheckTime) && authz.Identifier.Type == identifier.DNS {
+			// Ensure that CAA is rechecked for this name. There's no CAA for
+			// email addresses.
 			recheckAuthzs = append(recheckAuthzs, authz)
 		}
 	}
@@ -807,39 +1004,36 @@ func (ra *RegistrationAuthorityImpl) checkAuthorizationsCAA(
 	return nil
 }
 
-// recheckCAA accepts a list of of names that need to have their CAA records
-// rechecked because their associated authorizations are sufficiently old and
-// performs the CAA checks required for each. If any of the rechecks fail an
-// error is returned.
-func (ra *RegistrationAuthorityImpl) recheckCAA(ctx context.Context, authzs []*core.Authorization) error {
-	ra.recheckCAACounter.Add(float64(len(authzs)))
+// authzCAAResult is the result of rechecking CAA for an authorization's name.
+type authzCAAResult struct {
+	authz *core.Authorization
+	err   error
+}
 
-	type authzCAAResult struct {
-		authz *core.Authorization
-		err   error
+// caaValidationMethod returns the validation method CAA is rechecked for. If
+// an authorization has multiple valid challenges, the type of the first valid
+// challenge is used.
+func caaValidationMethod(authz *core.Authorization) (string, error) {
+	for _, challenge := range authz.Challenges {
+		if challenge.Status == core.StatusValid {
+			return string(challenge.Type), nil
+		}
 	}
+	return "", berrors.InternalServerError(
+		"Internal error determining validation method for authorization ID %v (%v)",
+		authz.ID, authz.Identifier.Value)
+}
+
+// recheckCAAIndividually rechecks CAA for each authorization with a separate,
+// concurrent, IsCAAValid call.
+func (ra *RegistrationAuthorityImpl) recheckCAAIndividually(ctx context.Context, authzs []*core.Authorization) []authzCAAResult {
 	ch := make(chan authzCAAResult, len(authzs))
 	for _, authz := range authzs {
 		go func(authz *core.Authorization) {
 			name := authz.Identifier.Value
-
-			// If an authorization has multiple valid challenges,
-			// the type of the first valid challenge is used for
-			// the purposes of CAA rechecking.
-			var method string
-			for _, challenge := range authz.Challenges {
-				if challenge.Status == core.StatusValid {
-					method = string(challenge.Type)
-					break
-				}
-			}
-			if method == "" {
-				ch <- authzCAAResult{
-					authz: authz,
-					err: berrors.InternalServerError(
-						"Internal error determining validation method for authorization ID %v (%v)",
-						authz.ID, name),
-				}
+			method, err := caaValidationMethod(authz)
+			if err != nil {
+				ch <- authzCAAResult{authz: authz, err: err}
 				return
 			}
 
@@ -863,10 +1057,78 @@ func (ra *RegistrationAuthorityImpl) recheckCAA(ctx context.Context, authzs []*c
 			}
 		}(authz)
 	}
+	results := make([]authzCAAResult, len(authzs))
+	for i := range results {
+		results[i] = <-ch
+	}
+	return results
+}
+
+// recheckCAABatch rechecks CAA for all of the authorizations with a single
+// IsCAAValidBatch call, in which the VA checks them concurrently.
+func (ra *RegistrationAuthorityImpl) recheckCAABatch(ctx context.Context, authzs []*core.Authorization) []authzCAAResult {
+	var results []authzCAAResult
+	var checked []*core.Authorization
+	req := &vapb.IsCAAValidBatchRequest{}
+	for _, authz := range authzs {
+		method, err := caaValidationMethod(authz)
+		if err != nil {
+			results = append(results, authzCAAResult{authz: authz, err: err})
+			continue
+		}
+		checked = append(checked, authz)
+		req.Checks = append(req.Checks, &vapb.IsCAAValidRequest{
+			Domain:           authz.Identifier.Value,
+			ValidationMethod: method,
+			AccountURIID:     authz.RegistrationID,
+		})
+	}
+	if len(checked) == 0 {
+		return results
+	}
+
+	resp, err := ra.caa.IsCAAValidBatch(ctx, req)
+	if err == nil && len(resp.Results) != len(checked) {
+		err = fmt.Errorf("VA returned %d CAA results for %d names", len(resp.Results), len(checked))
+	}
+	if err != nil {
+		ra.log.AuditErrf("Rechecking CAA: %s", err)
+		for _, authz := range checked {
+			results = append(results, authzCAAResult{
+				authz: authz,
+				err: berrors.InternalServerError(
+					"Internal error rechecking CAA for authorization ID %v (%v)",
+					authz.ID, authz.Identifier.Value,
+				),
+			})
+		}
+		return results
+	}
+	for i, authz := range checked {
+		var err error
+		if problem := resp.Results[i].Problem; problem != nil {
+			err = berrors.CAAError(problem.Detail)
+		}
+		results = append(results, authzCAAResult{authz: authz, err: err})
+	}
+	return results
+}
+
+// recheckCAA accepts a list of of names that need to have their CAA records
+// rechecked because their associated authorizations are sufficiently old and
+// performs the CAA checks required for each. If any of the rechecks fail an
+// error is returned.
+func (ra *RegistrationAuthorityImpl) recheckCAA(ctx context.Context, authzs []*core.Authorization) error {
+	ra.recheckCAACounter.Add(float64(len(authzs)))
+
+	var results []authzCAAResult
+	if features.Enabled(features.BatchCAARecheck) {
+		results = ra.recheckCAABatch(ctx, authzs)
+	} else {
+		results = ra.recheckCAAIndividually(ctx, authzs)
+	}
 	var subErrors []berrors.SubBoulderError
-	// Read a recheckResult for each authz from the results channel
-	for i := 0; i < len(authzs); i++ {
-		recheckResult := <-ch
+	for _, recheckResult := range results {
 		// If the result had a CAA boulder error, construct a suberror with the
 		// identifier from the authorization that was checked.
 		if err := recheckResult.err; err != nil {
@@ -947,6 +1209,10 @@ func (ra *RegistrationAuthorityImpl) FinalizeOrder(ctx context.Context, req *rap
 		return nil, berrors.InternalServerError("Order has no associated names")
 	}
 
+	if err := ra.checkOrderLifetime(order); err != nil {
+		return nil, err
+	}
+
 	// Parse the CSR from the request
 	csrOb, err := x509.ParseCertificateRequest(req.Csr)
 	if err != nil {
@@ -960,8 +1226,9 @@ func (ra *RegistrationAuthorityImpl) FinalizeOrder(ctx context.Context, req *rap
 	}
 
 	// Dedupe, lowercase and sort both the names from the CSR and the names in the
-	// order.
-	csrNames := core.UniqueLowerNames(csrOb.DNSNames)
+	// order. A CSR for an S/MIME certificate has email addresses instead of DNS
+	// names.
+	csrNames := core.UniqueLowerNames(append(csrOb.DNSNames, csrOb.EmailAddresses...))
 	orderNames := core.UniqueLowerNames(order.Names)
 
 	// Immediately reject the request if the number of names differ
@@ -1001,7 +1268,7 @@ func (ra *RegistrationAuthorityImpl) FinalizeOrder(ctx context.Context, req *rap
 	// We use IssuerNameID 0 here because (as of now) only the v1 flow sets this
 	// field. This v2 flow allows the CA to select the issuer based on the CSR's
 	// PublicKeyAlgorithm.
-	cert, err := ra.issueCertificate(ctx, issueReq, accountID(order.RegistrationID), orderID(order.Id), issuance.IssuerNameID(0))
+	cert, err := ra.issueCertificate(ctx, issueReq, accountID(order.RegistrationID), orderID(order.Id), order.CertificateProfileName, issuance.IssuerNameID(0))
 	if err != nil {
 		// Fail the order. The problem is computed using
 		// `web.ProblemDetailsForError`, the same function the WFE uses to convert
@@ -1031,6 +1298,11 @@ func (ra *RegistrationAuthorityImpl) FinalizeOrder(ctx context.Context, req *rap
 		return nil, err
 	}
 
+	ra.recordAccountEvent(ctx, order.RegistrationID, core.AccountEventFinalizeOrder, map[string]interface{}{
+		"orderID": order.Id,
+		"serial":  order.CertificateSerial,
+	})
+
 	// Note how many names were in this finalized certificate order.
 	ra.namesPerCert.With(
 		prometheus.Labels{"type": "issued"},
@@ -1051,7 +1323,7 @@ func (ra *RegistrationAuthorityImpl) NewCertificate(ctx context.Context, req cor
 	// NewCertificate provides an order ID of 0, indicating this is a classic ACME
 	// v1 issuance request from the new certificate endpoint that is not
 	// associated with an ACME v2 order.
-	return ra.issueCertificate(ctx, req, accountID(regID), orderID(0), issuance.IssuerNameID(issuerNameID))
+	return ra.issueCertificate(ctx, req, accountID(regID), orderID(0), "", issuance.IssuerNameID(issuerNameID))
 }
 
 // To help minimize the chance that an accountID would be used as an order ID
@@ -1068,6 +1340,7 @@ func (ra *RegistrationAuthorityImpl) issueCertificate(
 	req core.CertificateRequest,
 	acctID accountID,
 	oID orderID,
+	profile string,
 	issuerNameID issuance.IssuerNameID) (core.Certificate, error) {
 	// Construct the log event
 	logEvent := certificateRequestEvent{
@@ -1077,7 +1350,7 @@ func (ra *RegistrationAuthorityImpl) issueCertificate(
 		RequestTime: ra.clk.Now(),
 	}
 	var result string
-	cert, err := ra.issueCertificateInner(ctx, req, acctID, oID, issuerNameID, &logEvent)
+	cert, err := ra.issueCertificateInner(ctx, req, acctID, oID, profile, issuerNameID, &logEvent)
 	if err != nil {
 		logEvent.Error = err.Error()
 		result = "error"
@@ -1108,6 +1381,7 @@ func (ra *RegistrationAuthorityImpl) issueCertificateInner(
 	req core.CertificateRequest,
 	acctID accountID,
 	oID orderID,
+	profile string,
 	issuerNameID issuance.IssuerNameID,
 	logEvent *certificateRequestEvent) (core.Certificate, error) {
 	emptyCert := core.Certificate{}
@@ -1129,15 +1403,28 @@ func (ra *RegistrationAuthorityImpl) issueCertificateInner(
 	csr := req.CSR
 	logEvent.CommonName = csr.Subject.CommonName
 	logEvent.Names = csr.DNSNames
+	if len(csr.EmailAddresses) > 0 {
+		logEvent.Names = csr.EmailAddresses
+	}
 
-	// Validate that authorization key is authorized for all domains in the CSR
-	names := make([]string, len(csr.DNSNames))
-	copy(names, csr.DNSNames)
+	// Validate that authorization key is authorized for all names in the CSR
+	names := make([]string, 0, len(csr.DNSNames)+len(csr.EmailAddresses))
+	names = append(names, csr.DNSNames...)
+	names = append(names, csr.EmailAddresses...)
 
 	if core.KeyDigestEquals(csr.PublicKey, account.Key) {
 		return emptyCert, berrors.MalformedError("certificate public key must be different than account key")
 	}
 
+	// Dedicated accounts may only be issued certificates for their own
+	// domains. This is checked here as well as when orders are created, since
+	// an account may be restricted after creating an order, and ACMEv1
+	// issuance has no order.
+	err = ra.checkAccountAllowlist(ctx, int64(acctID), names)
+	if err != nil {
+		return emptyCert, err
+	}
+
 	// Check rate limits before checking authorizations. If someone is unable to
 	// issue a cert due to rate limiting, we don't want to tell them to go get the
 	// necessary authorizations, only to later fail the rate limit check.
@@ -1186,10 +1473,11 @@ func (ra *RegistrationAuthorityImpl) issueCertificateInner(
 
 	// Create the certificate and log the result
 	issueReq := &capb.IssueCertificateRequest{
-		Csr:            csr.Raw,
-		RegistrationID: int64(acctID),
-		OrderID:        int64(oID),
-		IssuerNameID:   int64(issuerNameID),
+		Csr:                    csr.Raw,
+		RegistrationID:         int64(acctID),
+		OrderID:                int64(oID),
+		IssuerNameID:           int64(issuerNameID),
+		CertificateProfileName: profile,
 	}
 
 	// wrapError adds a prefix to an error. If the error is a boulder error then
@@ -1211,15 +1499,21 @@ func (ra *RegistrationAuthorityImpl) issueCertificateInner(
 	if err != nil {
 		return emptyCert, wrapError(err, "parsing precertificate")
 	}
-	scts, err := ra.getSCTs(ctx, precert.DER, parsedPrecert.NotAfter)
-	if err != nil {
-		return emptyCert, wrapError(err, "getting SCTs")
+	// S/MIME certificates aren't submitted to CT logs, which only accept
+	// certificates for TLS.
+	var scts [][]byte
+	if len(parsedPrecert.EmailAddresses) == 0 {
+		scts, err = ra.getSCTs(ctx, precert.DER, parsedPrecert.NotAfter)
+		if err != nil {
+			return emptyCert, wrapError(err, "getting SCTs")
+		}
 	}
 	cert, err := ra.CA.IssueCertificateForPrecertificate(ctx, &capb.IssueCertificateForPrecertificateRequest{
-		DER:            precert.DER,
-		SCTs:           scts,
-		RegistrationID: int64(acctID),
-		OrderID:        int64(oID),
+		DER:                    precert.DER,
+		SCTs:                   scts,
+		RegistrationID:         int64(acctID),
+		OrderID:                int64(oID),
+		CertificateProfileName: profile,
 	})
 	if err != nil {
 		return emptyCert, wrapError(err, "issuing certificate for precertificate")
@@ -1245,6 +1539,10 @@ func (ra *RegistrationAuthorityImpl) issueCertificateInner(
 	logEvent.NotBefore = parsedCertificate.NotBefore
 	logEvent.NotAfter = parsedCertificate.NotAfter
 
+	if features.Enabled(features.StoreCertificateMetadata) {
+		ra.storeCertificateMetadata(ctx, parsedCertificate, acctID, profile, logEventAuthzs)
+	}
+
 	ra.newCertCounter.Inc()
 	res, err := bgrpc.PBToCert(cert)
 	if err != nil {
@@ -1253,6 +1551,39 @@ func (ra *RegistrationAuthorityImpl) issueCertificateInner(
 	return res, nil
 }
 
+// storeCertificateMetadata records the properties of a newly issued
+// certificate which issuance reports are broken down by. The certificate has
+// already been issued, so failing to record them is only logged.
+func (ra *RegistrationAuthorityImpl) storeCertificateMetadata(
+	ctx context.Context,
+	cert *x509.Certificate,
+	acctID accountID,
+	profile string,
+	authzs map[string]certificateRequestAuthz) {
+	seen := make(map[string]bool, len(authzs))
+	var methods []string
+	for _, authz := range authzs {
+		method := string(authz.ChallengeType)
+		if method != "" && !seen[method] {
+			seen[method] = true
+			methods = append(methods, method)
+		}
+	}
+	sort.Strings(methods)
+	serial := core.SerialToString(cert.SerialNumber)
+	_, err := ra.SA.AddCertificateMetadata(ctx, &sapb.CertificateMetadata{
+		Serial:                 serial,
+		RegistrationID:         int64(acctID),
+		Issued:                 ra.clk.Now().UnixNano(),
+		CertificateProfileName: profile,
+		KeyAlgorithm:           web.KeyTypeToString(cert.PublicKey),
+		ValidationMethods:      methods,
+	})
+	if err != nil {
+		ra.log.Errf("Failed to store metadata for certificate %s: %s", serial, err)
+	}
+}
+
 func (ra *RegistrationAuthorityImpl) getSCTs(ctx context.Context, cert []byte, expiration time.Time) (core.SCTDERs, error) {
 	started := ra.clk.Now()
 	scts, err := ra.ctpolicy.GetSCTs(ctx, cert, expiration)
@@ -1282,6 +1613,10 @@ func (ra *RegistrationAuthorityImpl) getSCTs(ctx context.Context, cert []byte, e
 func domainsForRateLimiting(names []string) ([]string, error) {
 	var domains []string
 	for _, name := range names {
+		// Email addresses count against their domain.
+		if at := strings.LastIndex(name, "@"); at >= 0 {
+			name = name[at+1:]
+		}
 		domain, err := publicsuffix.Domain(name)
 		if err != nil {
 			// The only possible errors are:
@@ -1395,7 +1730,7 @@ func (ra *RegistrationAuthorityImpl) checkCertificatesPerFQDNSetLimit(ctx contex
 
 func (ra *RegistrationAuthorityImpl) checkLimits(ctx context.Context, names []string, regID int64) error {
 	certNameLimits := ra.rlPolicies.CertificatesPerName()
-	if certNameLimits.Enabled() {
+	if certNameLimits.Enabled() && !ra.exemptRegistration(certNameLimits, "certificates_for_domain", regID) {
 		err := ra.checkCertificatesPerNameLimit(ctx, names, certNameLimits, regID)
 		if err != nil {
 			return err
@@ -1403,7 +1738,7 @@ func (ra *RegistrationAuthorityImpl) checkLimits(ctx context.Context, names []st
 	}
 
 	fqdnLimits := ra.rlPolicies.CertificatesPerFQDNSet()
-	if fqdnLimits.Enabled() {
+	if fqdnLimits.Enabled() && !ra.exemptRegistration(fqdnLimits, "certificates_for_fqdn_set", regID) {
 		err := ra.checkCertificatesPerFQDNSetLimit(ctx, names, fqdnLimits, regID)
 		if err != nil {
 			return err
@@ -1416,6 +1751,7 @@ func (ra *RegistrationAuthorityImpl) checkLimits(ctx context.Context, names []st
 // is responsible for making sure that update.Key is only different from base.Key
 // if it is being called from the WFE key change endpoint.
 func (ra *RegistrationAuthorityImpl) UpdateRegistration(ctx context.Context, base core.Registration, update core.Registration) (core.Registration, error) {
+	oldKey, oldContact := base.Key, base.Contact
 	if changed := mergeUpdate(&base, update); !changed {
 		// If merging the update didn't actually change the base then our work is
 		// done, we can return before calling ra.SA.UpdateRegistration since there's
@@ -1436,9 +1772,94 @@ func (ra *RegistrationAuthorityImpl) UpdateRegistration(ctx context.Context, bas
 		return core.Registration{}, err
 	}
 
+	// mergeUpdate only replaces the key and contacts if they've changed.
+	if base.Key != oldKey {
+		ra.recordAccountEvent(ctx, base.ID, core.AccountEventKeyRollover, map[string]interface{}{
+			"oldKeyThumbprint": keyThumbprint(oldKey),
+			"newKeyThumbprint": keyThumbprint(base.Key),
+		})
+	}
+	if base.Contact != oldContact {
+		var contact, previous []string
+		if base.Contact != nil {
+			contact = *base.Contact
+		}
+		if oldContact != nil {
+			previous = *oldContact
+		}
+		// The contacts themselves are personal data, which the audit trail
+		// mustn't keep, so only how many there are and how many changed are.
+		added, removed := contactChanges(previous, contact)
+		ra.recordAccountEvent(ctx, base.ID, core.AccountEventContactChange, map[string]interface{}{
+			"contactCount":    len(contact),
+			"contactsAdded":   added,
+			"contactsRemoved": removed,
+		})
+	}
+
 	return base, nil
 }
 
+// contactChanges returns the number of contacts in contact which weren't in
+// previous, and the number in previous which aren't in contact.
+func contactChanges(previous, contact []string) (int, int) {
+	before := make(map[string]bool, len(previous))
+	for _, c := range previous {
+		before[c] = true
+	}
+	after := make(map[string]bool, len(contact))
+	added := 0
+	for _, c := range contact {
+		if !before[c] && !after[c] {
+			added++
+		}
+		after[c] = true
+	}
+	removed := 0
+	for c := range before {
+		if !after[c] {
+			removed++
+		}
+	}
+	return added, removed
+}
+
+// keyThumbprint returns the base64url encoded RFC 7638 SHA-256 thumbprint of
+// an account key, as ACME clients show it, or the empty string if it can't be
+// computed.
+func keyThumbprint(key *jose.JSONWebKey) string {
+	if key == nil {
+		return ""
+	}
+	thumbprint, err := key.Thumbprint(crypto.SHA256)
+	if err != nil {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString(thumbprint)
+}
+
+// recordAccountEvent adds an event, described by details, to an account's
+// audit trail if the AccountEvents feature is enabled. The action it describes
+// has already been taken, so failing to record it is only logged.
+func (ra *RegistrationAuthorityImpl) recordAccountEvent(ctx context.Context, regID int64, eventType core.AccountEventType, details map[string]interface{}) {
+	if !features.Enabled(features.AccountEvents) {
+		return
+	}
+	detailsJSON, err := json.Marshal(details)
+	if err != nil {
+		ra.log.Errf("Failed to marshal %s event for registration %d: %s", eventType, regID, err)
+		return
+	}
+	_, err = ra.SA.AddAccountEvent(ctx, &sapb.AccountEvent{
+		RegistrationID: regID,
+		Type:           string(eventType),
+		Details:        string(detailsJSON),
+	})
+	if err != nil {
+		ra.log.Errf("Failed to record %s event for registration %d: %s", eventType, regID, err)
+	}
+}
+
 func contactsEqual(r *core.Registration, other core.Registration) bool {
 	// If there is no existing contact slice, or the contact slice lengths
 	// differ, then the other contact is not equal
@@ -1620,6 +2041,11 @@ func (ra *RegistrationAuthorityImpl) PerformValidation(
 			},
 		}
 		res, err := ra.VA.PerformValidation(vaCtx, &req)
+		if err == nil && res.AwaitingReply {
+			// The challenge email of an email-reply-00 challenge was sent. The
+			// challenge stays pending until it's retried after the reply.
+			return
+		}
 
 		challenge := &authz.Challenges[challIndex]
 		var prob *probs.ProblemDetails
@@ -1702,6 +2128,15 @@ func (ra *RegistrationAuthorityImpl) revokeCertificate(ctx context.Context, cert
 	if err != nil {
 		return err
 	}
+	if ra.ocspObjects != nil {
+		// The database remains the source of truth, from which the
+		// ocsp-updater writes the response again when it's next refreshed,
+		// so a failure is only logged.
+		err = ra.ocspObjects.Put(serial, ocspResponse.Response, "application/ocsp-response")
+		if err != nil {
+			ra.log.AuditErrf("Failed to write revoked OCSP response for serial %s to object storage: %s", serial, err)
+		}
+	}
 	if reason == ocsp.KeyCompromise {
 		digest, err := core.KeyDigest(cert.PublicKey)
 		if err != nil {
@@ -1764,6 +2199,15 @@ func (ra *RegistrationAuthorityImpl) RevokeCertificateWithReg(ctx context.Contex
 
 	ra.revocationReasonCounter.WithLabelValues(revocation.ReasonToString[revocationCode]).Inc()
 	state = "Success"
+
+	// Revocations requested with the certificate's key rather than an account
+	// aren't part of any account's audit trail.
+	if regID != 0 {
+		ra.recordAccountEvent(ctx, regID, core.AccountEventRevokeCertificate, map[string]interface{}{
+			"serial": serialString,
+			"reason": revocation.ReasonToString[revocationCode],
+		})
+	}
 	return nil
 }
 
@@ -1800,6 +2244,19 @@ func (ra *RegistrationAuthorityImpl) AdministrativelyRevokeCertificate(ctx conte
 	return nil
 }
 
+// wildcardRevalidator is implemented by policy authorities which can require
+// wildcard names to be validated anew for each order.
+type wildcardRevalidator interface {
+	RequireWildcardRevalidation() bool
+}
+
+// requireWildcardRevalidation returns true if the RA's PA forbids reusing
+// valid authorizations for wildcard names.
+func (ra *RegistrationAuthorityImpl) requireWildcardRevalidation() bool {
+	revalidator, ok := ra.PA.(wildcardRevalidator)
+	return ok && revalidator.RequireWildcardRevalidation()
+}
+
 // DeactivateRegistration deactivates a valid registration
 func (ra *RegistrationAuthorityImpl) DeactivateRegistration(ctx context.Context, reg core.Registration) error {
 	if reg.Status != core.StatusValid {
@@ -1830,11 +2287,15 @@ func (ra *RegistrationAuthorityImpl) DeactivateAuthorization(ctx context.Context
 // checkOrderNames validates that the RA's policy authority allows issuing for
 // each of the names in an order. If any of the names are unacceptable a
 // malformed or rejectedIdentifier error with suberrors for each rejected
-// identifier is returned.
+// identifier is returned. Since a certificate is either for TLS or for S/MIME,
+// an order can't have both DNS and email identifiers.
 func (ra *RegistrationAuthorityImpl) checkOrderNames(names []string) error {
 	idents := make([]identifier.ACMEIdentifier, len(names))
 	for i, name := range names {
-		idents[i] = identifier.DNSIdentifier(name)
+		idents[i] = identifier.FromValue(name)
+		if idents[i].Type != idents[0].Type {
+			return berrors.MalformedError("Order cannot contain both DNS names and email addresses")
+		}
 	}
 	if err := ra.PA.WillingToIssueWildcards(idents); err != nil {
 		return err
@@ -1842,12 +2303,145 @@ func (ra *RegistrationAuthorityImpl) checkOrderNames(names []string) error {
 	return nil
 }
 
+// checkAccountAllowlist checks that each of the names is under one of the
+// registered domains on the account's allowlist, if it's restricted to one.
+// Restricted accounts are dedicated to issuing for the domains of a single
+// subscriber, and may issue for nothing if their allowlist is empty. It must
+// be checked on every path to issuance.
+func (ra *RegistrationAuthorityImpl) checkAccountAllowlist(ctx context.Context, regID int64, names []string) error {
+	allowlist, err := ra.SA.GetAccountAllowlist(ctx, &sapb.RegistrationID{Id: regID})
+	if err != nil {
+		return err
+	}
+	if !allowlist.Restricted {
+		return nil
+	}
+
+	detail := "This account may not issue certificates for any names"
+	if len(allowlist.Domains) != 0 {
+		detail = fmt.Sprintf(
+			"This account may only issue certificates for names under its allowed domains: %s",
+			strings.Join(allowlist.Domains, ", "))
+	}
+	var subErrors []berrors.SubBoulderError
+	for _, name := range names {
+		base := strings.TrimPrefix(name, "*.")
+		if at := strings.LastIndex(base, "@"); at >= 0 {
+			base = base[at+1:]
+		}
+		allowed := false
+		for _, domain := range allowlist.Domains {
+			if base == domain || strings.HasSuffix(base, "."+domain) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			subErrors = append(subErrors, berrors.SubBoulderError{
+				Identifier: identifier.FromValue(name),
+				BoulderError: &berrors.BoulderError{
+					Type:   berrors.RejectedIdentifier,
+					Detail: detail,
+				},
+			})
+		}
+	}
+	if len(subErrors) == 0 {
+		return nil
+	}
+	if len(subErrors) == 1 {
+		return berrors.RejectedIdentifierError("Cannot issue for %q: %s", subErrors[0].Identifier.Value, detail)
+	}
+	return (&berrors.BoulderError{
+		Type: berrors.RejectedIdentifier,
+		Detail: fmt.Sprintf(
+			"Cannot issue for %q: %s (and %d more problems. Refer to sub-problems for more information.)",
+			subErrors[0].Identifier.Value, detail, len(subErrors)-1),
+	}).WithSubErrors(subErrors)
+}
+
+// maxProfileNameLength is the length of the longest certificate profile name
+// which can be stored with an order.
+const maxProfileNameLength = 32
+
+// profileLifetimeSlack allows for skew between the RA's clock, by which an
+// order's expiry is set, and the SA's, by which its creation time is, when an
+// order's lifetime is checked against its profile at finalization.
+const profileLifetimeSlack = time.Minute
+
+// ProfileLifetimes configures the lifetimes of the orders which request a
+// certificate profile, and of the pending authorizations created for them. A
+// zero lifetime leaves the RA's default in place.
+type ProfileLifetimes struct {
+	OrderLifetime                time.Duration
+	PendingAuthorizationLifetime time.Duration
+}
+
+// SetProfileLifetimes configures the certificate profiles which orders may
+// request, with their lifetimes, keyed by profile name. Orders which request
+// any other profile are rejected, and those which don't request a profile get
+// the RA's default lifetimes.
+func (ra *RegistrationAuthorityImpl) SetProfileLifetimes(profiles map[string]ProfileLifetimes) error {
+	for name, lifetimes := range profiles {
+		if name == "" || len(name) > maxProfileNameLength {
+			return fmt.Errorf("invalid certificate profile name %q", name)
+		}
+		if lifetimes.OrderLifetime < 0 || lifetimes.PendingAuthorizationLifetime < 0 {
+			return fmt.Errorf("certificate profile %q has a negative lifetime", name)
+		}
+	}
+	ra.profileLifetimes = profiles
+	return nil
+}
+
+// lifetimesFor returns the order and pending authorization lifetimes for the
+// orders which request a certificate profile.
+func (ra *RegistrationAuthorityImpl) lifetimesFor(profile string) (time.Duration, time.Duration) {
+	orderLifetime, pendingAuthzLifetime := ra.orderLifetime, ra.pendingAuthorizationLifetime
+	lifetimes := ra.profileLifetimes[profile]
+	if lifetimes.OrderLifetime != 0 {
+		orderLifetime = lifetimes.OrderLifetime
+	}
+	if lifetimes.PendingAuthorizationLifetime != 0 {
+		pendingAuthzLifetime = lifetimes.PendingAuthorizationLifetime
+	}
+	return orderLifetime, pendingAuthzLifetime
+}
+
+// checkOrderLifetime returns an error if an order outlives the order lifetime
+// configured for its certificate profile, which may have been shortened since
+// the order was created. Orders for profiles without a configured order
+// lifetime aren't checked.
+func (ra *RegistrationAuthorityImpl) checkOrderLifetime(order *corepb.Order) error {
+	maxLifetime := ra.profileLifetimes[order.CertificateProfileName].OrderLifetime
+	if maxLifetime == 0 {
+		return nil
+	}
+	lifetime := time.Unix(0, order.Expires).Sub(time.Unix(0, order.Created))
+	if lifetime > maxLifetime+profileLifetimeSlack {
+		return berrors.UnauthorizedError(
+			"Order's lifetime of %s exceeds the %s allowed for certificate profile %q",
+			lifetime, maxLifetime, order.CertificateProfileName)
+	}
+	return nil
+}
+
 // NewOrder creates a new order object
 func (ra *RegistrationAuthorityImpl) NewOrder(ctx context.Context, req *rapb.NewOrderRequest) (*corepb.Order, error) {
 	order := &corepb.Order{
-		RegistrationID: req.RegistrationID,
-		Names:          core.UniqueLowerNames(req.Names),
+		RegistrationID:         req.RegistrationID,
+		Names:                  core.UniqueLowerNames(req.Names),
+		CertificateProfileName: req.CertificateProfileName,
+	}
+
+	if len(order.CertificateProfileName) > maxProfileNameLength {
+		return nil, berrors.MalformedError(
+			"Certificate profile name cannot be longer than %d characters", maxProfileNameLength)
+	}
+	if _, ok := ra.profileLifetimes[order.CertificateProfileName]; order.CertificateProfileName != "" && !ok {
+		return nil, berrors.MalformedError("Unknown certificate profile %q", order.CertificateProfileName)
 	}
+	orderLifetime, pendingAuthzLifetime := ra.lifetimesFor(order.CertificateProfileName)
 
 	if len(order.Names) > ra.maxNames {
 		return nil, berrors.MalformedError(
@@ -1859,6 +2453,11 @@ func (ra *RegistrationAuthorityImpl) NewOrder(ctx context.Context, req *rapb.New
 		return nil, err
 	}
 
+	// Dedicated accounts may only order certificates for their own domains
+	if err := ra.checkAccountAllowlist(ctx, order.RegistrationID, order.Names); err != nil {
+		return nil, err
+	}
+
 	if err := wildcardOverlap(order.Names); err != nil {
 		return nil, err
 	}
@@ -1874,8 +2473,8 @@ func (ra *RegistrationAuthorityImpl) NewOrder(ctx context.Context, req *rapb.New
 	if err != nil && !errors.Is(err, berrors.NotFound) {
 		return nil, err
 	}
-	// If there was an order, return it
-	if existingOrder != nil {
+	// If there was an order for the same certificate profile, return it
+	if existingOrder != nil && existingOrder.CertificateProfileName == order.CertificateProfileName {
 		return existingOrder, nil
 	}
 
@@ -1912,12 +2511,18 @@ func (ra *RegistrationAuthorityImpl) NewOrder(ctx context.Context, req *rapb.New
 	// Collect up the authorizations we found into a map keyed by the domains the
 	// authorizations correspond to
 	nameToExistingAuthz := make(map[string]*corepb.Authorization, len(order.Names))
+	revalidateWildcards := ra.requireWildcardRevalidation()
 	for _, v := range existingAuthz.Authz {
 		// Don't reuse a valid authorization if the reuseValidAuthz flag is
 		// disabled.
 		if v.Authz.Status == string(core.StatusValid) && !ra.reuseValidAuthz {
 			continue
 		}
+		// Don't reuse a valid authorization for a wildcard name if the PA
+		// requires wildcard names to be revalidated for each order.
+		if v.Authz.Status == string(core.StatusValid) && revalidateWildcards && strings.HasPrefix(v.Domain, "*.") {
+			continue
+		}
 		nameToExistingAuthz[v.Domain] = v.Authz
 	}
 
@@ -1977,10 +2582,7 @@ func (ra *RegistrationAuthorityImpl) NewOrder(ctx context.Context, req *rapb.New
 	// authorization for each.
 	var newAuthzs []*corepb.Authorization
 	for _, name := range missingAuthzNames {
-		pb, err := ra.createPendingAuthz(ctx, order.RegistrationID, identifier.ACMEIdentifier{
-			Type:  identifier.DNS,
-			Value: name,
-		})
+		pb, err := ra.createPendingAuthz(ctx, order.RegistrationID, identifier.FromValue(name), pendingAuthzLifetime)
 		if err != nil {
 			return nil, err
 		}
@@ -1989,7 +2591,7 @@ func (ra *RegistrationAuthorityImpl) NewOrder(ctx context.Context, req *rapb.New
 
 	// Start with the order's own expiry as the minExpiry. We only care
 	// about authz expiries that are sooner than the order's expiry
-	minExpiry := ra.clk.Now().Add(ra.orderLifetime)
+	minExpiry := ra.clk.Now().Add(orderLifetime)
 
 	// Check the reused authorizations to see if any have an expiry before the
 	// minExpiry (the order's lifetime)
@@ -2008,18 +2610,27 @@ func (ra *RegistrationAuthorityImpl) NewOrder(ctx context.Context, req *rapb.New
 		}
 	}
 
+	// StreamlineOrderAndAuthzs may be rolled out to a percentage of accounts,
+	// or overridden for this one, so decide once whether it applies to this
+	// order.
+	streamline := features.EnabledCtx(features.WithAccountID(ctx, order.RegistrationID), features.StreamlineOrderAndAuthzs)
+
 	// If new authorizations are needed, call AddPendingAuthorizations. Also check
 	// whether the newly created pending authz's have an expiry lower than minExpiry
 	if len(newAuthzs) > 0 {
-		req := sapb.AddPendingAuthorizationsRequest{Authz: newAuthzs}
-		authzIDs, err := ra.SA.NewAuthorizations2(ctx, &req)
-		if err != nil {
-			return nil, err
+		// When StreamlineOrderAndAuthzs is enabled the new authorizations are
+		// stored by the SA in the same transaction as the order itself, below.
+		if !streamline {
+			req := sapb.AddPendingAuthorizationsRequest{Authz: newAuthzs}
+			authzIDs, err := ra.SA.NewAuthorizations2(ctx, &req)
+			if err != nil {
+				return nil, err
+			}
+			order.V2Authorizations = append(order.V2Authorizations, authzIDs.Ids...)
 		}
-		order.V2Authorizations = append(order.V2Authorizations, authzIDs.Ids...)
 		// If the newly created pending authz's have an expiry closer than the
 		// minExpiry the minExpiry is the pending authz expiry.
-		newPendingAuthzExpires := ra.clk.Now().Add(ra.pendingAuthorizationLifetime)
+		newPendingAuthzExpires := ra.clk.Now().Add(pendingAuthzLifetime)
 		if newPendingAuthzExpires.Before(minExpiry) {
 			minExpiry = newPendingAuthzExpires
 		}
@@ -2033,23 +2644,38 @@ func (ra *RegistrationAuthorityImpl) NewOrder(ctx context.Context, req *rapb.New
 	// Set the order's expiry to the minimum expiry. The db doesn't store
 	// sub-second values, so truncate here.
 	order.Expires = minExpiry.Truncate(time.Second).UnixNano()
-	storedOrder, err := ra.SA.NewOrder(ctx, order)
+	var storedOrder *corepb.Order
+	if streamline {
+		storedOrder, err = ra.SA.NewOrderAndAuthzs(ctx, &sapb.NewOrderAndAuthzsRequest{
+			NewOrder:  order,
+			NewAuthzs: newAuthzs,
+		})
+	} else {
+		storedOrder, err = ra.SA.NewOrder(ctx, order)
+	}
 	if err != nil {
 		return nil, err
 	}
 
+	ra.recordAccountEvent(ctx, order.RegistrationID, core.AccountEventNewOrder, map[string]interface{}{
+		"orderID":                storedOrder.Id,
+		"names":                  storedOrder.Names,
+		"certificateProfileName": storedOrder.CertificateProfileName,
+	})
+
 	return storedOrder, nil
 }
 
 // createPendingAuthz checks that a name is allowed for issuance and creates the
 // necessary challenges for it and puts this and all of the relevant information
-// into a corepb.Authorization for transmission to the SA to be stored
-func (ra *RegistrationAuthorityImpl) createPendingAuthz(ctx context.Context, reg int64, identifier identifier.ACMEIdentifier) (*corepb.Authorization, error) {
+// into a corepb.Authorization, which expires after lifetime, for transmission
+// to the SA to be stored
+func (ra *RegistrationAuthorityImpl) createPendingAuthz(ctx context.Context, reg int64, identifier identifier.ACMEIdentifier, lifetime time.Duration) (*corepb.Authorization, error) {
 	authz := &corepb.Authorization{
 		Identifier:     identifier.Value,
 		RegistrationID: reg,
 		Status:         string(core.StatusPending),
-		Expires:        ra.clk.Now().Add(ra.pendingAuthorizationLifetime).Truncate(time.Second).UnixNano(),
+		Expires:        ra.clk.Now().Add(lifetime).Truncate(time.Second).UnixNano(),
 	}
 
 	// Create challenges. The WFE will update them with URIs before sending them out.
@@ -12,6 +12,7 @@ import (
 	"crypto/x509/pkix"
 	"encoding/json"
 	"encoding/pem"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"math/big"
@@ -29,6 +30,7 @@ import (
 	ctpkix "github.com/google/certificate-transparency-go/x509/pkix"
 	"github.com/jmhodges/clock"
 	akamaipb "github.com/letsencrypt/boulder/akamai/proto"
+	"github.com/letsencrypt/boulder/bdns"
 	capb "github.com/letsencrypt/boulder/ca/proto"
 	"github.com/letsencrypt/boulder/cmd"
 	"github.com/letsencrypt/boulder/core"
@@ -287,7 +289,7 @@ func initAuthorities(t *testing.T) (*DummyValidationAuthority, *sa.SQLStorageAut
 	if err != nil {
 		t.Fatalf("Failed to create dbMap: %s", err)
 	}
-	ssa, err := sa.NewSQLStorageAuthority(dbMap, fc, log, metrics.NoopRegisterer, 1)
+	ssa, err := sa.NewSQLStorageAuthority(dbMap, fc, log, metrics.NoopRegisterer, 1, 0, nil)
 	if err != nil {
 		t.Fatalf("Failed to create SA: %s", err)
 	}
@@ -322,7 +324,8 @@ func initAuthorities(t *testing.T) (*DummyValidationAuthority, *sa.SQLStorageAut
 		Status:    core.StatusValid,
 	})
 
-	ctp := ctpolicy.New(&mocks.Publisher{}, nil, nil, log, metrics.NoopRegisterer)
+	ctp, err := ctpolicy.New(&mocks.Publisher{}, nil, nil, log, metrics.NoopRegisterer)
+	test.AssertNotError(t, err, "Couldn't create CT policy")
 
 	ra := NewRegistrationAuthorityImpl(fc,
 		log,
@@ -422,6 +425,49 @@ func TestValidateContacts(t *testing.T) {
 	test.AssertError(t, err, "Too long contacts")
 }
 
+func TestContactDomainChecks(t *testing.T) {
+	ra := NewRegistrationAuthorityImpl(clock.NewFake(), blog.NewMock(), metrics.NoopRegisterer,
+		0, testKeyPolicy, 100, true, 300*24*time.Hour, 7*24*time.Hour, nil, noopCAA{}, 0, nil, nil, nil)
+	ctx := context.Background()
+
+	// Without the optional checks, any domain accepted by policy.ValidEmail is
+	// fine.
+	err := ra.validateContacts(ctx, &[]string{"mailto:admin@gmial.com"})
+	test.AssertNotError(t, err, "contact rejected without domain checks")
+
+	ra.SetContactDomainChecks([]string{"gmial.com", "typo.example.net."}, &bdns.MockClient{Log: blog.NewMock()})
+	testCases := []struct {
+		contact string
+		reason  string
+	}{
+		{"mailto:admin@email.com", ""},
+		{"mailto:admin@gmial.com", "blocked_domain"},
+		{"mailto:admin@mail.GMIAL.com", "blocked_domain"},
+		{"mailto:admin@typo.example.net", "blocked_domain"},
+		{"mailto:admin@nxdomain.com", "nxdomain"},
+		{"mailto:admin@null-mx.com", "null_mx"},
+		{"mailto:admin@no-mx.com", "no_mx"},
+		// Temporary DNS failures don't get contacts rejected.
+		{"mailto:admin@servfail.com", ""},
+		{"ansible:earth.sol.milkyway.laniakea/letsencrypt", "unsupported_scheme"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.contact, func(t *testing.T) {
+			var before int
+			if tc.reason != "" {
+				before = test.CountCounter(ra.contactRejections.WithLabelValues(tc.reason))
+			}
+			err := ra.validateContacts(ctx, &[]string{tc.contact})
+			if tc.reason == "" {
+				test.AssertNotError(t, err, "contact rejected")
+				return
+			}
+			test.AssertErrorIs(t, err, berrors.InvalidEmail)
+			test.AssertEquals(t, test.CountCounter(ra.contactRejections.WithLabelValues(tc.reason)), before+1)
+		})
+	}
+}
+
 func TestNewRegistration(t *testing.T) {
 	_, sa, ra, _, cleanUp := initAuthorities(t)
 	defer cleanUp()
@@ -1818,6 +1864,28 @@ func (cr noopCAA) IsCAAValid(
 	return &vapb.IsCAAValidResponse{}, nil
 }
 
+func (cr noopCAA) IsCAAValidBatch(
+	ctx context.Context,
+	in *vapb.IsCAAValidBatchRequest,
+	opts ...grpc.CallOption,
+) (*vapb.IsCAAValidBatchResponse, error) {
+	return isCAAValidEach(ctx, cr, in)
+}
+
+// isCAAValidEach implements IsCAAValidBatch for a mock caaChecker by calling
+// its IsCAAValid for each check, failing if any call does.
+func isCAAValidEach(ctx context.Context, checker caaChecker, in *vapb.IsCAAValidBatchRequest) (*vapb.IsCAAValidBatchResponse, error) {
+	resp := &vapb.IsCAAValidBatchResponse{}
+	for _, check := range in.Checks {
+		result, err := checker.IsCAAValid(ctx, check)
+		if err != nil {
+			return nil, err
+		}
+		resp.Results = append(resp.Results, result)
+	}
+	return resp, nil
+}
+
 // caaRecorder implements caaChecker, always returning nil, but recording the
 // names it was called for.
 type caaRecorder struct {
@@ -1836,6 +1904,14 @@ func (cr *caaRecorder) IsCAAValid(
 	return &vapb.IsCAAValidResponse{}, nil
 }
 
+func (cr *caaRecorder) IsCAAValidBatch(
+	ctx context.Context,
+	in *vapb.IsCAAValidBatchRequest,
+	opts ...grpc.CallOption,
+) (*vapb.IsCAAValidBatchResponse, error) {
+	return isCAAValidEach(ctx, cr, in)
+}
+
 // A mock SA that returns special authzs for testing rechecking of CAA (in
 // TestRecheckCAADates below)
 type mockSAWithRecentAndOlder struct {
@@ -1967,6 +2043,14 @@ func (cf *caaFailer) IsCAAValid(
 	return cvrpb, nil
 }
 
+func (cf *caaFailer) IsCAAValidBatch(
+	ctx context.Context,
+	in *vapb.IsCAAValidBatchRequest,
+	opts ...grpc.CallOption,
+) (*vapb.IsCAAValidBatchResponse, error) {
+	return isCAAValidEach(ctx, cf, in)
+}
+
 func TestRecheckCAAEmpty(t *testing.T) {
 	_, _, ra, _, cleanUp := initAuthorities(t)
 	defer cleanUp()
@@ -2060,6 +2144,74 @@ func TestRecheckCAAInternalServerError(t *testing.T) {
 	test.AssertErrorIs(t, err, berrors.InternalServer)
 }
 
+// caaBatchRecorder implements caaChecker, recording the names of each
+// IsCAAValidBatch call, and failing IsCAAValid calls.
+type caaBatchRecorder struct {
+	batches [][]string
+}
+
+func (cr *caaBatchRecorder) IsCAAValid(
+	ctx context.Context,
+	in *vapb.IsCAAValidRequest,
+	opts ...grpc.CallOption,
+) (*vapb.IsCAAValidResponse, error) {
+	return nil, fmt.Errorf("unexpected unbatched CAA recheck")
+}
+
+func (cr *caaBatchRecorder) IsCAAValidBatch(
+	ctx context.Context,
+	in *vapb.IsCAAValidBatchRequest,
+	opts ...grpc.CallOption,
+) (*vapb.IsCAAValidBatchResponse, error) {
+	var names []string
+	for _, check := range in.Checks {
+		names = append(names, check.Domain)
+	}
+	cr.batches = append(cr.batches, names)
+	return isCAAValidEach(ctx, noopCAA{}, in)
+}
+
+func TestRecheckCAABatch(t *testing.T) {
+	_, _, ra, _, cleanUp := initAuthorities(t)
+	defer cleanUp()
+	_ = features.Set(map[string]bool{"BatchCAARecheck": true})
+	defer features.Reset()
+
+	// All of the names are rechecked with a single call.
+	recorder := &caaBatchRecorder{}
+	ra.caa = recorder
+	authzs := []*core.Authorization{
+		makeHTTP01Authorization("a.com"),
+		makeHTTP01Authorization("b.com"),
+		makeHTTP01Authorization("c.com"),
+	}
+	err := ra.recheckCAA(context.Background(), authzs)
+	test.AssertNotError(t, err, "recheckCAA failed")
+	test.AssertDeepEquals(t, recorder.batches, [][]string{{"a.com", "b.com", "c.com"}})
+
+	// Failures have a sub-problem for each name, in the order of the names.
+	ra.caa = &caaFailer{}
+	err = ra.recheckCAA(context.Background(), authzs)
+	var berr *berrors.BoulderError
+	test.AssertErrorWraps(t, err, &berr)
+	test.AssertErrorIs(t, berr, berrors.CAA)
+	test.AssertEquals(t, berr.Detail, `Rechecking CAA for "a.com" and 1 more identifiers failed. Refer to sub-problems for more information`)
+	test.AssertEquals(t, len(berr.SubErrors), 2)
+	test.AssertEquals(t, berr.SubErrors[0].Identifier.Value, "a.com")
+	test.AssertEquals(t, berr.SubErrors[1].Identifier.Value, "c.com")
+
+	// A failed call fails the recheck of every name.
+	err = ra.recheckCAA(context.Background(), append(authzs, makeHTTP01Authorization("d.com")))
+	test.AssertErrorIs(t, err, berrors.InternalServer)
+
+	// As does an authorization without a valid challenge.
+	ra.caa = noopCAA{}
+	pending := makeHTTP01Authorization("e.com")
+	pending.Challenges[0].Status = core.StatusPending
+	err = ra.recheckCAA(context.Background(), append(authzs, pending))
+	test.AssertErrorIs(t, err, berrors.InternalServer)
+}
+
 func TestNewOrder(t *testing.T) {
 	_, _, ra, fc, cleanUp := initAuthorities(t)
 	defer cleanUp()
@@ -2121,6 +2273,58 @@ func TestNewOrder(t *testing.T) {
 	test.AssertEquals(t, err.Error(), "Cannot issue for \"a\": Domain name needs at least one dot")
 }
 
+// streamlineCheckingSA is a StorageAuthority which fails any attempt to store
+// new authorizations separately from their order.
+type streamlineCheckingSA struct {
+	core.StorageAuthority
+	t *testing.T
+}
+
+func (sa streamlineCheckingSA) NewAuthorizations2(_ context.Context, _ *sapb.AddPendingAuthorizationsRequest) (*sapb.Authorization2IDs, error) {
+	sa.t.Fatal("NewAuthorizations2 called with StreamlineOrderAndAuthzs enabled")
+	return nil, nil
+}
+
+func TestNewOrderStreamlined(t *testing.T) {
+	_, ssa, ra, fc, cleanUp := initAuthorities(t)
+	defer cleanUp()
+	_ = features.Set(map[string]bool{"StreamlineOrderAndAuthzs": true})
+	defer features.Reset()
+	ra.orderLifetime = time.Hour
+	ra.SA = streamlineCheckingSA{StorageAuthority: ssa, t: t}
+
+	// The order and its new authorizations are stored together.
+	orderA, err := ra.NewOrder(context.Background(), &rapb.NewOrderRequest{
+		RegistrationID: Registration.ID,
+		Names:          []string{"b.com", "a.com", "C.COM"},
+	})
+	test.AssertNotError(t, err, "ra.NewOrder failed")
+	test.AssertEquals(t, orderA.Expires, fc.Now().Add(time.Hour).UnixNano())
+	test.AssertDeepEquals(t, orderA.Names, []string{"a.com", "b.com", "c.com"})
+	test.AssertEquals(t, numAuthorizations(orderA), 3)
+	test.AssertEquals(t, orderA.Status, string(core.StatusPending))
+	stored, err := ssa.GetOrder(context.Background(), &sapb.OrderRequest{Id: orderA.Id})
+	test.AssertNotError(t, err, "sa.GetOrder failed")
+	test.AssertDeepEquals(t, stored.V2Authorizations, orderA.V2Authorizations)
+	for _, id := range orderA.V2Authorizations {
+		authz, err := ssa.GetAuthorization2(context.Background(), &sapb.AuthorizationID2{Id: id})
+		test.AssertNotError(t, err, "sa.GetAuthorization2 failed")
+		test.AssertEquals(t, authz.Status, string(core.StatusPending))
+		test.AssertEquals(t, authz.RegistrationID, Registration.ID)
+	}
+
+	// A new order reuses the existing authorizations, and its one new
+	// authorization is stored with it.
+	orderB, err := ra.NewOrder(context.Background(), &rapb.NewOrderRequest{
+		RegistrationID: Registration.ID,
+		Names:          []string{"a.com", "b.com", "c.com", "d.com"},
+	})
+	test.AssertNotError(t, err, "ra.NewOrder failed")
+	test.AssertNotEquals(t, orderB.Id, orderA.Id)
+	test.AssertEquals(t, numAuthorizations(orderB), 4)
+	test.AssertDeepEquals(t, orderB.V2Authorizations[:3], orderA.V2Authorizations)
+}
+
 // TestNewOrderLegacyAuthzReuse tests that a legacy acme v1 authorization from
 // the `new-authz` endpoint isn't reused by a V2 order created by the same
 // account.
@@ -2793,6 +2997,100 @@ func TestNewOrderExpiry(t *testing.T) {
 	test.AssertEquals(t, order.Expires, expectedOrderExpiry)
 }
 
+func TestNewOrderProfileLifetimes(t *testing.T) {
+	_, _, ra, clk, cleanUp := initAuthorities(t)
+	defer cleanUp()
+	ra.orderLifetime = 48 * time.Hour
+	ra.pendingAuthorizationLifetime = 7 * 24 * time.Hour
+	err := ra.SetProfileLifetimes(map[string]ProfileLifetimes{
+		"shortlived": {OrderLifetime: 8 * time.Hour, PendingAuthorizationLifetime: 4 * time.Hour},
+		"default":    {},
+	})
+	test.AssertNotError(t, err, "SetProfileLifetimes failed")
+
+	// An order for a configured profile, and its new pending authorization,
+	// get the profile's lifetimes.
+	shortOrder, err := ra.NewOrder(ctx, &rapb.NewOrderRequest{
+		RegistrationID:         Registration.ID,
+		Names:                  []string{"short.example.com"},
+		CertificateProfileName: "shortlived",
+	})
+	test.AssertNotError(t, err, "NewOrder for shortlived profile failed")
+	test.AssertEquals(t, shortOrder.CertificateProfileName, "shortlived")
+	test.AssertEquals(t, shortOrder.Expires, clk.Now().Add(4*time.Hour).UnixNano())
+	authz, err := ra.SA.GetAuthorization2(ctx, &sapb.AuthorizationID2{Id: shortOrder.V2Authorizations[0]})
+	test.AssertNotError(t, err, "GetAuthorization2 failed")
+	test.AssertEquals(t, authz.Expires, clk.Now().Add(4*time.Hour).UnixNano())
+
+	// A profile without lifetimes gets the default lifetimes.
+	order, err := ra.NewOrder(ctx, &rapb.NewOrderRequest{
+		RegistrationID:         Registration.ID,
+		Names:                  []string{"default.example.com"},
+		CertificateProfileName: "default",
+	})
+	test.AssertNotError(t, err, "NewOrder for default profile failed")
+	test.AssertEquals(t, order.Expires, clk.Now().Add(ra.orderLifetime).UnixNano())
+
+	// A pending order for the same names isn't reused for a different profile.
+	order, err = ra.NewOrder(ctx, &rapb.NewOrderRequest{
+		RegistrationID:         Registration.ID,
+		Names:                  []string{"short.example.com"},
+		CertificateProfileName: "default",
+	})
+	test.AssertNotError(t, err, "NewOrder for a second profile failed")
+	test.Assert(t, order.Id != shortOrder.Id, "order for a different profile was reused")
+	test.AssertEquals(t, order.CertificateProfileName, "default")
+
+	// Profiles which aren't configured are rejected.
+	_, err = ra.NewOrder(ctx, &rapb.NewOrderRequest{
+		RegistrationID:         Registration.ID,
+		Names:                  []string{"unconfigured.example.com"},
+		CertificateProfileName: "unconfigured",
+	})
+	test.AssertErrorIs(t, err, berrors.Malformed)
+
+	// Overlong profile names are rejected.
+	_, err = ra.NewOrder(ctx, &rapb.NewOrderRequest{
+		RegistrationID:         Registration.ID,
+		Names:                  []string{"long.example.com"},
+		CertificateProfileName: strings.Repeat("a", maxProfileNameLength+1),
+	})
+	test.AssertErrorIs(t, err, berrors.Malformed)
+}
+
+func TestProfileLifetimes(t *testing.T) {
+	ra := &RegistrationAuthorityImpl{orderLifetime: 48 * time.Hour, pendingAuthorizationLifetime: 24 * time.Hour}
+	test.AssertError(t, ra.SetProfileLifetimes(map[string]ProfileLifetimes{"": {}}), "empty profile name accepted")
+	test.AssertError(t, ra.SetProfileLifetimes(map[string]ProfileLifetimes{
+		"negative": {OrderLifetime: -time.Hour},
+	}), "negative lifetime accepted")
+	err := ra.SetProfileLifetimes(map[string]ProfileLifetimes{
+		"shortlived": {OrderLifetime: 8 * time.Hour},
+	})
+	test.AssertNotError(t, err, "SetProfileLifetimes failed")
+
+	orderLifetime, pendingAuthzLifetime := ra.lifetimesFor("shortlived")
+	test.AssertEquals(t, orderLifetime, 8*time.Hour)
+	test.AssertEquals(t, pendingAuthzLifetime, 24*time.Hour)
+	orderLifetime, pendingAuthzLifetime = ra.lifetimesFor("")
+	test.AssertEquals(t, orderLifetime, 48*time.Hour)
+	test.AssertEquals(t, pendingAuthzLifetime, 24*time.Hour)
+
+	// At finalization, orders which outlive their profile's order lifetime,
+	// because it's been shortened since they were created, are rejected.
+	created := time.Date(2021, 2, 10, 0, 0, 0, 0, time.UTC)
+	order := &corepb.Order{
+		Created:                created.UnixNano(),
+		Expires:                created.Add(8 * time.Hour).UnixNano(),
+		CertificateProfileName: "shortlived",
+	}
+	test.AssertNotError(t, ra.checkOrderLifetime(order), "order within its profile's lifetime rejected")
+	order.Expires = created.Add(48 * time.Hour).UnixNano()
+	test.AssertErrorIs(t, ra.checkOrderLifetime(order), berrors.Unauthorized)
+	order.CertificateProfileName = ""
+	test.AssertNotError(t, ra.checkOrderLifetime(order), "order without a profile rejected")
+}
+
 func TestFinalizeOrder(t *testing.T) {
 	_, sa, ra, fc, cleanUp := initAuthorities(t)
 	defer cleanUp()
@@ -3451,6 +3749,10 @@ func (mp *timeoutPub) SubmitToSingleCTWithResult(_ context.Context, _ *pubpb.Req
 	return nil, context.DeadlineExceeded
 }
 
+func (mp *timeoutPub) QueueFinalCert(_ context.Context, _ *pubpb.FinalCertRequest) (*pubpb.FinalCertQueued, error) {
+	return nil, context.DeadlineExceeded
+}
+
 func TestCTPolicyMeasurements(t *testing.T) {
 	_, ssa, _, fc, cleanup := initAuthorities(t)
 	defer cleanup()
@@ -3460,7 +3762,8 @@ func TestCTPolicyMeasurements(t *testing.T) {
 		PEM: eeCertPEM,
 	}
 
-	ctp := ctpolicy.New(&timeoutPub{}, []ctconfig.CTGroup{{}}, nil, log, metrics.NoopRegisterer)
+	ctp, err := ctpolicy.New(&timeoutPub{}, []ctconfig.CTGroup{{}}, nil, log, metrics.NoopRegisterer)
+	test.AssertNotError(t, err, "Couldn't create CT policy")
 	ra := NewRegistrationAuthorityImpl(fc,
 		log,
 		stats,
@@ -3476,9 +3779,9 @@ func TestCTPolicyMeasurements(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
 	defer cancel()
 
-	_, err := ra.issueCertificate(ctx, core.CertificateRequest{
+	_, err = ra.issueCertificate(ctx, core.CertificateRequest{
 		CSR: ExampleCSR,
-	}, accountID(Registration.ID), 0, 0)
+	}, accountID(Registration.ID), 0, "", 0)
 	test.AssertError(t, err, "ra.issueCertificate didn't fail when CTPolicy.GetSCTs timed out")
 	test.AssertEquals(t, test.CountHistogramSamples(ra.ctpolicyResults.With(prometheus.Labels{"result": "failure"})), 1)
 }
@@ -3689,7 +3992,7 @@ func TestIssueCertificateInnerErrs(t *testing.T) {
 			// Mock the CA
 			ra.CA = tc.Mock
 			// Attempt issuance
-			_, err = ra.issueCertificateInner(ctx, req, accountID(Registration.ID), orderID(order.Id), issuance.IssuerNameID(0), logEvent)
+			_, err = ra.issueCertificateInner(ctx, req, accountID(Registration.ID), orderID(order.Id), "", issuance.IssuerNameID(0), logEvent)
 			// We expect all of the testcases to fail because all use mocked CAs that deliberately error
 			test.AssertError(t, err, "issueCertificateInner with failing mock CA did not fail")
 			// If there is an expected `error` then match the error message
@@ -3904,3 +4207,328 @@ func TestRevocationAddBlockedKey(t *testing.T) {
 	test.AssertEquals(t, test.CountCounterVec(
 		"reason", "keyCompromise", ra.revocationReasonCounter), 2)
 }
+
+type ocspObjectRecorder struct {
+	objects map[string][]byte
+	err     error
+}
+
+func (r *ocspObjectRecorder) Put(name string, body []byte, contentType string) error {
+	if r.err != nil {
+		return r.err
+	}
+	r.objects[name] = body
+	return nil
+}
+
+func TestRevocationWritesOCSPObject(t *testing.T) {
+	k, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	test.AssertNotError(t, err, "ecdsa.GenerateKey failed")
+	template := x509.Certificate{PublicKey: k.Public(), SerialNumber: big.NewInt(258)}
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, k.Public(), k)
+	test.AssertNotError(t, err, "x509.CreateCertificate failed")
+	cert, err := x509.ParseCertificate(der)
+	test.AssertNotError(t, err, "x509.ParseCertificate failed")
+	ic := issuance.Certificate{Certificate: cert}
+
+	ra := NewRegistrationAuthorityImpl(clock.NewFake(), blog.NewMock(), metrics.NoopRegisterer,
+		0, testKeyPolicy, 100, true, 300*24*time.Hour, 7*24*time.Hour, nil, noopCAA{}, 0, nil,
+		&mockPurger{}, []*issuance.Certificate{&ic})
+	ra.SA = &mockSABlockedKey{}
+	ra.CA = &mockCAOCSP{}
+	objects := &ocspObjectRecorder{objects: make(map[string][]byte)}
+	ra.SetOCSPObjectStorage(objects)
+
+	err = ra.RevokeCertificateWithReg(context.Background(), *cert, ocsp.Unspecified, 0)
+	test.AssertNotError(t, err, "RevokeCertificateWithReg failed")
+	test.AssertByteEquals(t, objects.objects[core.SerialToString(cert.SerialNumber)], []byte{1, 2, 3})
+
+	// Failing to write the response doesn't fail the revocation, which is
+	// already stored.
+	objects.err = errors.New("bucket unavailable")
+	err = ra.AdministrativelyRevokeCertificate(context.Background(), *cert, ocsp.Unspecified, "root")
+	test.AssertNotError(t, err, "AdministrativelyRevokeCertificate failed when the bucket was unavailable")
+}
+
+type mockSAWithAllowlist struct {
+	mocks.StorageAuthority
+	restricted bool
+	domains    []string
+}
+
+func (msa *mockSAWithAllowlist) GetAccountAllowlist(_ context.Context, _ *sapb.RegistrationID) (*sapb.AccountAllowlist, error) {
+	return &sapb.AccountAllowlist{Domains: msa.domains, Restricted: msa.restricted}, nil
+}
+
+func TestCheckAccountAllowlist(t *testing.T) {
+	ra := &RegistrationAuthorityImpl{SA: &mockSAWithAllowlist{}}
+
+	// An account without an allowlist may order any names.
+	err := ra.checkAccountAllowlist(ctx, 1, []string{"example.com", "*.example.net"})
+	test.AssertNotError(t, err, "checkAccountAllowlist failed for an unrestricted account")
+
+	ra.SA = &mockSAWithAllowlist{restricted: true, domains: []string{"example.com", "example.co.uk"}}
+	err = ra.checkAccountAllowlist(ctx, 1, []string{"example.com", "www.example.com", "*.example.co.uk"})
+	test.AssertNotError(t, err, "checkAccountAllowlist failed for allowed names")
+
+	err = ra.checkAccountAllowlist(ctx, 1, []string{"example.com", "notexample.com"})
+	test.AssertErrorIs(t, err, berrors.RejectedIdentifier)
+	test.AssertEquals(t, err.Error(), `Cannot issue for "notexample.com": This account may only issue certificates for names under its allowed domains: example.com, example.co.uk`)
+
+	err = ra.checkAccountAllowlist(ctx, 1, []string{"example.org", "*.example.net"})
+	var berr *berrors.BoulderError
+	test.AssertErrorWraps(t, err, &berr)
+	test.AssertEquals(t, berr.Type, berrors.RejectedIdentifier)
+	test.AssertEquals(t, len(berr.SubErrors), 2)
+	test.AssertEquals(t, berr.SubErrors[1].Identifier.Value, "*.example.net")
+
+	// A restricted account whose allowlist is empty may issue for nothing.
+	ra.SA = &mockSAWithAllowlist{restricted: true}
+	err = ra.checkAccountAllowlist(ctx, 1, []string{"example.com"})
+	test.AssertErrorIs(t, err, berrors.RejectedIdentifier)
+	test.AssertEquals(t, err.Error(), `Cannot issue for "example.com": This account may not issue certificates for any names`)
+}
+
+func TestAccountAllowlistEnforced(t *testing.T) {
+	pa, err := policy.New(map[core.AcmeChallenge]bool{core.ChallengeTypeHTTP01: true})
+	test.AssertNotError(t, err, "Couldn't create PA")
+	err = pa.SetHostnamePolicyFile("../test/hostname-policy.yaml")
+	test.AssertNotError(t, err, "Couldn't set hostname policy")
+	ra := &RegistrationAuthorityImpl{
+		SA:  &mockSAWithAllowlist{restricted: true, domains: []string{"example.com"}},
+		PA:  pa,
+		clk: clock.NewFake(),
+		log: blog.NewMock(),
+	}
+
+	// ACMEv1 authorizations.
+	_, err = ra.NewAuthorization(ctx, core.Authorization{Identifier: identifier.DNSIdentifier("not-example.com")}, 1)
+	test.AssertErrorIs(t, err, berrors.RejectedIdentifier)
+
+	// Issuance, both for ACMEv1 and for ACMEv2 orders, which may have been
+	// created before the account was restricted.
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	test.AssertNotError(t, err, "generating key")
+	csrDER, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{DNSNames: []string{"not-example.com"}}, key)
+	test.AssertNotError(t, err, "creating CSR")
+	csr, err := x509.ParseCertificateRequest(csrDER)
+	test.AssertNotError(t, err, "parsing CSR")
+	for _, oID := range []orderID{0, 1} {
+		_, err = ra.issueCertificateInner(ctx, core.CertificateRequest{CSR: csr}, accountID(1), oID, "", 0, &certificateRequestEvent{})
+		test.AssertErrorIs(t, err, berrors.RejectedIdentifier)
+	}
+}
+
+type mockSAWithCertificateMetadata struct {
+	mocks.StorageAuthority
+	metadata []*sapb.CertificateMetadata
+	err      error
+}
+
+func (msa *mockSAWithCertificateMetadata) AddCertificateMetadata(_ context.Context, req *sapb.CertificateMetadata) (*corepb.Empty, error) {
+	msa.metadata = append(msa.metadata, req)
+	return &corepb.Empty{}, msa.err
+}
+
+func TestStoreCertificateMetadata(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	test.AssertNotError(t, err, "generating key")
+	cert := &x509.Certificate{SerialNumber: big.NewInt(1337), PublicKey: &key.PublicKey}
+
+	fc := clock.NewFake()
+	msa := &mockSAWithCertificateMetadata{}
+	log := blog.NewMock()
+	ra := &RegistrationAuthorityImpl{SA: msa, clk: fc, log: log}
+
+	ra.storeCertificateMetadata(ctx, cert, 1, "shortlived", map[string]certificateRequestAuthz{
+		"a.com": {ID: "1", ChallengeType: core.ChallengeTypeHTTP01},
+		"b.com": {ID: "2", ChallengeType: core.ChallengeTypeDNS01},
+		"c.com": {ID: "3", ChallengeType: core.ChallengeTypeHTTP01},
+	})
+	test.AssertEquals(t, len(msa.metadata), 1)
+	test.AssertDeepEquals(t, msa.metadata[0], &sapb.CertificateMetadata{
+		Serial:                 core.SerialToString(cert.SerialNumber),
+		RegistrationID:         1,
+		Issued:                 fc.Now().UnixNano(),
+		CertificateProfileName: "shortlived",
+		KeyAlgorithm:           "ECDSA P-256",
+		ValidationMethods:      []string{"dns-01", "http-01"},
+	})
+
+	// The certificate has already been issued, so failures are only logged.
+	msa.err = fmt.Errorf("oops")
+	ra.storeCertificateMetadata(ctx, cert, 1, "", nil)
+	test.AssertEquals(t, len(log.GetAllMatching("Failed to store metadata for certificate")), 1)
+}
+
+type mockSAWithAccountEvents struct {
+	mocks.StorageAuthority
+	events []*sapb.AccountEvent
+}
+
+func (msa *mockSAWithAccountEvents) AddAccountEvent(_ context.Context, req *sapb.AccountEvent) (*corepb.Empty, error) {
+	msa.events = append(msa.events, req)
+	return &corepb.Empty{}, nil
+}
+
+func TestUpdateRegistrationAccountEvents(t *testing.T) {
+	var keyA, keyB jose.JSONWebKey
+	test.AssertNotError(t, json.Unmarshal(AccountKeyJSONA, &keyA), "unmarshalling key A")
+	test.AssertNotError(t, json.Unmarshal(AccountKeyJSONB, &keyB), "unmarshalling key B")
+	base := core.Registration{ID: 1, Key: &keyA}
+
+	msa := &mockSAWithAccountEvents{}
+	ra := &RegistrationAuthorityImpl{SA: msa, log: blog.NewMock(), clk: clock.NewFake()}
+
+	// Nothing is recorded unless the feature is enabled.
+	_, err := ra.UpdateRegistration(ctx, base, core.Registration{Key: &keyB})
+	test.AssertNotError(t, err, "UpdateRegistration failed")
+	test.AssertEquals(t, len(msa.events), 0)
+
+	_ = features.Set(map[string]bool{"AccountEvents": true})
+	defer features.Reset()
+
+	_, err = ra.UpdateRegistration(ctx, base, core.Registration{Key: &keyA})
+	test.AssertNotError(t, err, "UpdateRegistration failed")
+	test.AssertEquals(t, len(msa.events), 0)
+
+	_, err = ra.UpdateRegistration(ctx, base, core.Registration{Key: &keyB})
+	test.AssertNotError(t, err, "UpdateRegistration failed")
+	test.AssertEquals(t, len(msa.events), 1)
+	test.AssertEquals(t, msa.events[0].RegistrationID, int64(1))
+	test.AssertEquals(t, msa.events[0].Type, string(core.AccountEventKeyRollover))
+	test.AssertEquals(t, msa.events[0].Details, fmt.Sprintf(`{"newKeyThumbprint":%q,"oldKeyThumbprint":%q}`,
+		keyThumbprint(&keyB), keyThumbprint(&keyA)))
+
+	// Contacts aren't validated when they're all removed.
+	base.Contact = &[]string{"mailto:admin@example.com"}
+	_, err = ra.UpdateRegistration(ctx, base, core.Registration{Contact: &[]string{}})
+	test.AssertNotError(t, err, "UpdateRegistration failed")
+	test.AssertEquals(t, len(msa.events), 2)
+	test.AssertEquals(t, msa.events[1].Type, string(core.AccountEventContactChange))
+	test.AssertEquals(t, msa.events[1].Details, `{"contactCount":0,"contactsAdded":0,"contactsRemoved":1}`)
+	test.AssertNotContains(t, msa.events[1].Details, "admin@example.com")
+}
+
+type mockSAWithKeyRollovers struct {
+	mocks.StorageAuthority
+	rollovers []*sapb.KeyRollover
+	requests  []*sapb.GetKeyRolloversRequest
+}
+
+func (msa *mockSAWithKeyRollovers) GetKeyRollovers(_ context.Context, req *sapb.GetKeyRolloversRequest) (*sapb.KeyRollovers, error) {
+	msa.requests = append(msa.requests, req)
+	return &sapb.KeyRollovers{Rollovers: msa.rollovers}, nil
+}
+
+func TestContactChanges(t *testing.T) {
+	a, b, c := "mailto:a@example.com", "mailto:b@example.com", "mailto:c@example.com"
+	added, removed := contactChanges([]string{a, b}, []string{b, a})
+	test.AssertEquals(t, added, 0)
+	test.AssertEquals(t, removed, 0)
+	added, removed = contactChanges([]string{a, b}, []string{b, c, c})
+	test.AssertEquals(t, added, 1)
+	test.AssertEquals(t, removed, 1)
+	added, removed = contactChanges(nil, []string{a, b})
+	test.AssertEquals(t, added, 2)
+	test.AssertEquals(t, removed, 0)
+}
+
+func TestCheckRolledOverKey(t *testing.T) {
+	var keyA jose.JSONWebKey
+	test.AssertNotError(t, json.Unmarshal(AccountKeyJSONA, &keyA), "unmarshalling key A")
+	hashA, err := core.KeyDigest(&keyA)
+	test.AssertNotError(t, err, "hashing key A")
+	otherHash := bytes.Repeat([]byte{1}, 32)
+
+	fc := clock.NewFake()
+	msa := &mockSAWithKeyRollovers{}
+	ra := &RegistrationAuthorityImpl{SA: msa, log: blog.NewMock(), clk: fc}
+
+	// Without a block period the SA isn't asked about rollovers.
+	err = ra.checkRolledOverKey(ctx, &keyA)
+	test.AssertNotError(t, err, "checkRolledOverKey failed without a block period")
+	test.AssertEquals(t, len(msa.requests), 0)
+
+	ra.SetRolledOverKeyBlockPeriod(24 * time.Hour)
+	err = ra.checkRolledOverKey(ctx, &keyA)
+	test.AssertNotError(t, err, "checkRolledOverKey failed for a key without rollovers")
+	test.AssertEquals(t, len(msa.requests), 1)
+	test.AssertByteEquals(t, msa.requests[0].KeyHash, hashA[:])
+	test.AssertEquals(t, msa.requests[0].Since, fc.Now().Add(-24*time.Hour).UnixNano())
+
+	// A key which an account rolled over to may be used again.
+	msa.rollovers = []*sapb.KeyRollover{{RegistrationID: 1, OldKeyHash: otherHash, NewKeyHash: hashA[:], RolledOver: fc.Now().UnixNano()}}
+	err = ra.checkRolledOverKey(ctx, &keyA)
+	test.AssertNotError(t, err, "checkRolledOverKey failed for a rolled-to key")
+
+	// A key which an account rolled over away from may not.
+	msa.rollovers = append(msa.rollovers, &sapb.KeyRollover{RegistrationID: 2, OldKeyHash: hashA[:], NewKeyHash: otherHash, RolledOver: fc.Now().UnixNano()})
+	err = ra.checkRolledOverKey(ctx, &keyA)
+	test.AssertErrorIs(t, err, berrors.Unauthorized)
+}
+
+// mockSAWithCounts returns the same count for every registration and order
+// count.
+type mockSAWithCounts struct {
+	mocks.StorageAuthority
+	count int
+}
+
+func (msa *mockSAWithCounts) CountRegistrationsByIP(_ context.Context, _ net.IP, _, _ time.Time) (int, error) {
+	return msa.count, nil
+}
+
+func (msa *mockSAWithCounts) CountRegistrationsByIPRange(_ context.Context, _ net.IP, _, _ time.Time) (int, error) {
+	return msa.count, nil
+}
+
+func (msa *mockSAWithCounts) CountOrders(_ context.Context, _ int64, _, _ time.Time) (int, error) {
+	return msa.count, nil
+}
+
+func TestRateLimitExemptions(t *testing.T) {
+	policies := ratelimit.New()
+	err := policies.LoadPolicies([]byte(`
+registrationsPerIP:
+  window: 168h
+  threshold: 1
+  exemptCIDRs: [10.77.77.0/24, "2001:db8::/32"]
+registrationsPerIPRange:
+  window: 168h
+  threshold: 1
+newOrdersPerAccount:
+  window: 3h
+  threshold: 1
+  exemptRegistrations: [101]
+`))
+	test.AssertNotError(t, err, "loading rate limit policies")
+	ra := &RegistrationAuthorityImpl{
+		SA:         &mockSAWithCounts{count: 1},
+		rlPolicies: policies,
+		log:        blog.NewMock(),
+		clk:        clock.NewFake(),
+		rateLimitCounter: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "ra_ratelimits",
+		}, []string{"limit", "result"}),
+	}
+
+	err = ra.checkRegistrationLimits(ctx, net.ParseIP("10.77.77.1"))
+	test.AssertNotError(t, err, "exempt IP was rate limited")
+	test.AssertEquals(t, test.CountCounter(ra.rateLimitCounter.WithLabelValues("registrations_by_ip", "exempt")), 1)
+	err = ra.checkRegistrationLimits(ctx, net.ParseIP("10.77.78.1"))
+	test.AssertErrorIs(t, err, berrors.RateLimit)
+
+	// An IPv6 address exempt from the per-IP limit is still subject to the
+	// per-range limit.
+	err = ra.checkRegistrationLimits(ctx, net.ParseIP("2001:db8::1"))
+	test.AssertErrorIs(t, err, berrors.RateLimit)
+	test.AssertEquals(t, test.CountCounter(ra.rateLimitCounter.WithLabelValues("registrations_by_ip", "exempt")), 2)
+	test.AssertEquals(t, test.CountCounter(ra.rateLimitCounter.WithLabelValues("registrations_by_ip_range", "exempt")), 0)
+
+	err = ra.checkNewOrdersPerAccountLimit(ctx, 101)
+	test.AssertNotError(t, err, "exempt registration was rate limited")
+	test.AssertEquals(t, test.CountCounter(ra.rateLimitCounter.WithLabelValues("new_order_by_registration_id", "exempt")), 1)
+	err = ra.checkNewOrdersPerAccountLimit(ctx, 102)
+	test.AssertErrorIs(t, err, berrors.RateLimit)
+}
@@ -1,6 +1,9 @@
 package ratelimit
 
 import (
+	"fmt"
+	"net"
+	"strings"
 	"sync"
 	"time"
 
@@ -112,6 +115,20 @@ func (r *limitsImpl) LoadPolicies(contents []byte) error {
 	if err != nil {
 		return err
 	}
+	for name, policy := range newPolicy.policies() {
+		// Each limit is counted either by IP or by registration, and only
+		// checks exemptions of the same kind.
+		if limitsCountedByIP[name] && len(policy.ExemptRegistrations) > 0 {
+			return fmt.Errorf("%s: exemptRegistrations can't be set on a limit counted by IP", name)
+		}
+		if !limitsCountedByIP[name] && len(policy.ExemptCIDRs) > 0 {
+			return fmt.Errorf("%s: exemptCIDRs can only be set on limits counted by IP", name)
+		}
+		err = policy.parseExemptCIDRs()
+		if err != nil {
+			return fmt.Errorf("%s: %s", name, err)
+		}
+	}
 
 	r.Lock()
 	r.rlPolicy = &newPolicy
@@ -159,6 +176,28 @@ type rateLimitConfig struct {
 	CertificatesPerFQDNSet RateLimitPolicy `yaml:"certificatesPerFQDNSet"`
 }
 
+// policies returns pointers to each of the config's policies, by their names
+// in the YAML configuration.
+// limitsCountedByIP are the names of the limits, keyed as in policies, which
+// are counted by IP rather than by registration.
+var limitsCountedByIP = map[string]bool{
+	"registrationsPerIP":      true,
+	"registrationsPerIPRange": true,
+}
+
+func (c *rateLimitConfig) policies() map[string]*RateLimitPolicy {
+	return map[string]*RateLimitPolicy{
+		"certificatesPerName":             &c.CertificatesPerName,
+		"registrationsPerIP":              &c.RegistrationsPerIP,
+		"registrationsPerIPRange":         &c.RegistrationsPerIPRange,
+		"pendingAuthorizationsPerAccount": &c.PendingAuthorizationsPerAccount,
+		"invalidAuthorizationsPerAccount": &c.InvalidAuthorizationsPerAccount,
+		"pendingOrdersPerAccount":         &c.PendingOrdersPerAccount,
+		"newOrdersPerAccount":             &c.NewOrdersPerAccount,
+		"certificatesPerFQDNSet":          &c.CertificatesPerFQDNSet,
+	}
+}
+
 // RateLimitPolicy describes a general limiting policy
 type RateLimitPolicy struct {
 	// How long to count items for
@@ -179,6 +218,65 @@ type RateLimitPolicy struct {
 	// than the default. If both key-based and registration-based overrides are
 	// available, the registration-based on takes priority.
 	RegistrationOverrides map[int64]int `yaml:"registrationOverrides"`
+	// Requests from IP addresses within these CIDR ranges, such as those of
+	// monitoring probes, aren't subject to the limit at all. Single addresses
+	// may be given without a prefix length. They may only be set on the
+	// limits counted by IP, RegistrationsPerIP and RegistrationsPerIPRange.
+	ExemptCIDRs []string `yaml:"exemptCIDRs"`
+	// Requests by these registrations, such as those used by internal
+	// tooling, aren't subject to the limit at all. They may not be set on the
+	// limits counted by IP, which are checked before a registration exists.
+	ExemptRegistrations []int64 `yaml:"exemptRegistrations"`
+
+	// exemptNets are the parsed ExemptCIDRs.
+	exemptNets []*net.IPNet
+}
+
+// parseExemptCIDRs parses the policy's ExemptCIDRs into exemptNets.
+func (rlp *RateLimitPolicy) parseExemptCIDRs() error {
+	rlp.exemptNets = nil
+	for _, cidr := range rlp.ExemptCIDRs {
+		if !strings.Contains(cidr, "/") {
+			ip := net.ParseIP(cidr)
+			if ip == nil {
+				return fmt.Errorf("invalid exempt IP address %q", cidr)
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip = ip.To4()
+				bits = 8 * net.IPv4len
+			}
+			rlp.exemptNets = append(rlp.exemptNets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return fmt.Errorf("invalid exempt CIDR %q: %s", cidr, err)
+		}
+		rlp.exemptNets = append(rlp.exemptNets, ipNet)
+	}
+	return nil
+}
+
+// ExemptIP returns true if ip is within one of the policy's ExemptCIDRs.
+func (rlp *RateLimitPolicy) ExemptIP(ip net.IP) bool {
+	for _, ipNet := range rlp.exemptNets {
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// ExemptRegistration returns true if regID is one of the policy's
+// ExemptRegistrations.
+func (rlp *RateLimitPolicy) ExemptRegistration(regID int64) bool {
+	for _, exempt := range rlp.ExemptRegistrations {
+		if exempt == regID {
+			return true
+		}
+	}
+	return false
 }
 
 // Enabled returns true iff the RateLimitPolicy is enabled.
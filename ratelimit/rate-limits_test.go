@@ -2,6 +2,7 @@ package ratelimit
 
 import (
 	"io/ioutil"
+	"net"
 	"testing"
 	"time"
 
@@ -178,3 +179,62 @@ func TestLoadPolicies(t *testing.T) {
 	test.AssertEquals(t, emptyPolicy.PendingAuthorizationsPerAccount().Threshold, 0)
 	test.AssertEquals(t, emptyPolicy.CertificatesPerFQDNSet().Threshold, 0)
 }
+
+func TestExemptions(t *testing.T) {
+	policy := New()
+	err := policy.LoadPolicies([]byte(`
+registrationsPerIP:
+  window: 168h
+  threshold: 10
+  exemptCIDRs:
+    - 10.77.77.0/24
+    - 2001:db8::/32
+    - 192.0.2.1
+newOrdersPerAccount:
+  window: 3h
+  threshold: 10
+  exemptRegistrations: [101, 102]
+`))
+	test.AssertNotError(t, err, "Failed to load policies with exemptions")
+
+	regsPerIP := policy.RegistrationsPerIP()
+	test.Assert(t, regsPerIP.ExemptIP(net.ParseIP("10.77.77.77")), "IP within exempt CIDR not exempt")
+	test.Assert(t, regsPerIP.ExemptIP(net.ParseIP("2001:db8::1")), "IPv6 address within exempt CIDR not exempt")
+	test.Assert(t, regsPerIP.ExemptIP(net.ParseIP("192.0.2.1")), "exempt IP not exempt")
+	test.Assert(t, !regsPerIP.ExemptIP(net.ParseIP("192.0.2.2")), "IP next to exempt IP exempt")
+	test.Assert(t, !regsPerIP.ExemptIP(net.ParseIP("10.77.78.1")), "IP outside exempt CIDR exempt")
+	test.Assert(t, !regsPerIP.ExemptRegistration(101), "registration exempt from limit without exempt registrations")
+
+	newOrders := policy.NewOrdersPerAccount()
+	test.Assert(t, newOrders.ExemptRegistration(102), "exempt registration not exempt")
+	test.Assert(t, !newOrders.ExemptRegistration(103), "registration which isn't exempt exempt")
+	test.Assert(t, !newOrders.ExemptIP(net.ParseIP("10.77.77.77")), "IP exempt from limit without exempt CIDRs")
+
+	// Invalid exemptions are rejected, leaving the loaded policies in place.
+	err = policy.LoadPolicies([]byte(`
+registrationsPerIP:
+  exemptCIDRs: [10.77.77.0/33]
+`))
+	test.AssertError(t, err, "Loaded policies with an invalid exempt CIDR")
+	err = policy.LoadPolicies([]byte(`
+registrationsPerIPRange:
+  exemptCIDRs: [example.com]
+`))
+	test.AssertError(t, err, "Loaded policies with an invalid exempt IP")
+
+	// As are exemptions which the limit wouldn't check.
+	err = policy.LoadPolicies([]byte(`
+newOrdersPerAccount:
+  exemptCIDRs: [10.77.77.0/24]
+`))
+	test.AssertError(t, err, "Loaded policies with exempt CIDRs on a per-account limit")
+	test.AssertContains(t, err.Error(), "newOrdersPerAccount")
+	err = policy.LoadPolicies([]byte(`
+registrationsPerIP:
+  exemptRegistrations: [101]
+`))
+	test.AssertError(t, err, "Loaded policies with exempt registrations on a per-IP limit")
+	test.AssertContains(t, err.Error(), "registrationsPerIP")
+	regsPerIP = policy.RegistrationsPerIP()
+	test.Assert(t, regsPerIP.ExemptIP(net.ParseIP("10.77.77.77")), "exemption lost by failed load")
+}
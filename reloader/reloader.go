@@ -33,6 +33,17 @@ var readFile = ioutil.ReadFile
 // fast. New will return an error if it occurs on the first load. Otherwise all
 // errors are sent to the callback.
 func New(filename string, dataCallback func([]byte) error, errorCallback func(error)) (*Reloader, error) {
+	return start(filename, dataCallback, errorCallback, true)
+}
+
+// Watch is like New, except that the callback isn't called with the file's
+// current contents, only with new contents once the file changes. It's for
+// callers which have already loaded the file themselves.
+func Watch(filename string, dataCallback func([]byte) error, errorCallback func(error)) (*Reloader, error) {
+	return start(filename, dataCallback, errorCallback, false)
+}
+
+func start(filename string, dataCallback func([]byte) error, errorCallback func(error), loadNow bool) (*Reloader, error) {
 	if errorCallback == nil {
 		errorCallback = func(e error) {}
 	}
@@ -40,9 +51,15 @@ func New(filename string, dataCallback func([]byte) error, errorCallback func(er
 	if err != nil {
 		return nil, err
 	}
-	b, err := readFile(filename)
-	if err != nil {
-		return nil, err
+	if loadNow {
+		b, err := readFile(filename)
+		if err != nil {
+			return nil, err
+		}
+		err = dataCallback(b)
+		if err != nil {
+			return nil, err
+		}
 	}
 	stopChan := make(chan struct{})
 	tickerStop, tickChan := makeTicker()
@@ -74,11 +91,6 @@ func New(filename string, dataCallback func([]byte) error, errorCallback func(er
 			}
 		}
 	}
-	err = dataCallback(b)
-	if err != nil {
-		tickerStop()
-		return nil, err
-	}
 	go loop()
 	return &Reloader{stopChan}, nil
 }
@@ -141,6 +141,44 @@ func TestReload(t *testing.T) {
 	}
 }
 
+func TestWatch(t *testing.T) {
+	// Mock out makeTicker
+	fakeTick, restoreMakeTicker := makeFakeMakeTicker()
+	defer restoreMakeTicker()
+
+	f, _ := ioutil.TempFile("", "test-watch.txt")
+	filename := f.Name()
+	defer os.Remove(filename)
+
+	_, _ = f.Write([]byte("first body"))
+	_ = f.Close()
+
+	reloads := make(chan []byte, 1)
+	r, err := Watch(filename, func(b []byte) error {
+		reloads <- b
+		return nil
+	}, testFatalCb(t))
+	if err != nil {
+		t.Fatalf("Expected Watch to succeed, got %s", err)
+	}
+	defer r.Stop()
+	select {
+	case b := <-reloads:
+		t.Fatalf("Expected no callback before the file changed, got %q", b)
+	default:
+	}
+
+	time.Sleep(1 * time.Second)
+	err = ioutil.WriteFile(filename, []byte("second body"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fakeTick <- time.Now()
+	if b := <-reloads; string(b) != "second body" {
+		t.Errorf("Expected body %q, got %q", "second body", b)
+	}
+}
+
 func TestReloadFailure(t *testing.T) {
 	// Mock out makeTicker
 	fakeTick, restoreMakeTicker := makeFakeMakeTicker()
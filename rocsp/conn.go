@@ -0,0 +1,141 @@
+package rocsp
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"time"
+)
+
+// redisError is an error reply sent by the Redis server. Unlike network or
+// protocol errors, it leaves the connection usable.
+type redisError string
+
+func (e redisError) Error() string {
+	return "redis: " + string(e)
+}
+
+// conn is a single connection to a Redis server, speaking the RESP2 protocol.
+type conn struct {
+	net.Conn
+	r *bufio.Reader
+}
+
+// dial connects to the server, performs the TLS handshake if tlsConfig is
+// non-nil, and authenticates if password is non-empty.
+func dial(ctx context.Context, addr, password string, tlsConfig *tls.Config, timeout time.Duration) (*conn, error) {
+	dialer := &net.Dialer{Timeout: timeout}
+	nc, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	if tlsConfig != nil {
+		tc := tls.Client(nc, tlsConfig)
+		if timeout > 0 {
+			_ = tc.SetDeadline(time.Now().Add(timeout))
+		}
+		err = tc.Handshake()
+		if err != nil {
+			_ = nc.Close()
+			return nil, err
+		}
+		nc = tc
+	}
+	c := &conn{Conn: nc, r: bufio.NewReader(nc)}
+	if password != "" {
+		_, err = c.do(ctx, timeout, "AUTH", password)
+		if err != nil {
+			_ = c.Close()
+			return nil, fmt.Errorf("authenticating to %s: %w", addr, err)
+		}
+	}
+	return c, nil
+}
+
+// do sends a single command and reads its reply. The returned value is a
+// string, int64, []byte, []interface{}, or nil, depending on the reply type.
+// If ctx has no deadline, timeout is used to bound the round trip.
+func (c *conn) do(ctx context.Context, timeout time.Duration, args ...string) (interface{}, error) {
+	deadline, ok := ctx.Deadline()
+	if !ok && timeout > 0 {
+		deadline = time.Now().Add(timeout)
+	}
+	err := c.SetDeadline(deadline)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, 0, 64)
+	buf = append(buf, '*')
+	buf = strconv.AppendInt(buf, int64(len(args)), 10)
+	buf = append(buf, '\r', '\n')
+	for _, arg := range args {
+		buf = append(buf, '$')
+		buf = strconv.AppendInt(buf, int64(len(arg)), 10)
+		buf = append(buf, '\r', '\n')
+		buf = append(buf, arg...)
+		buf = append(buf, '\r', '\n')
+	}
+	_, err = c.Write(buf)
+	if err != nil {
+		return nil, err
+	}
+	return readReply(c.r)
+}
+
+// readReply reads a single RESP2 reply.
+func readReply(r *bufio.Reader) (interface{}, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 3 || line[len(line)-2] != '\r' {
+		return nil, errors.New("redis: malformed reply line")
+	}
+	prefix, body := line[0], line[1:len(line)-2]
+	switch prefix {
+	case '+':
+		return body, nil
+	case '-':
+		return nil, redisError(body)
+	case ':':
+		return strconv.ParseInt(body, 10, 64)
+	case '$':
+		n, err := strconv.Atoi(body)
+		if err != nil {
+			return nil, fmt.Errorf("redis: malformed bulk string length: %w", err)
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		data := make([]byte, n+2)
+		_, err = io.ReadFull(r, data)
+		if err != nil {
+			return nil, err
+		}
+		return data[:n], nil
+	case '*':
+		n, err := strconv.Atoi(body)
+		if err != nil {
+			return nil, fmt.Errorf("redis: malformed array length: %w", err)
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		elems := make([]interface{}, n)
+		for i := range elems {
+			elems[i], err = readReply(r)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return elems, nil
+	default:
+		return nil, fmt.Errorf("redis: unknown reply type %q", prefix)
+	}
+}
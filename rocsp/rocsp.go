@@ -0,0 +1,153 @@
+// Package rocsp stores and retrieves pre-signed OCSP responses in Redis, keyed
+// by certificate serial. It also provides the shared nonce storage used by
+// the nonce service.
+package rocsp
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// ErrRedisNotFound is returned when no response is stored for a serial.
+var ErrRedisNotFound = errors.New("redis key not found")
+
+// Client is a Redis client for storing OCSP responses. It maintains a pool of
+// up to poolSize idle connections to a single server, and is safe for
+// concurrent use.
+type Client struct {
+	addr      string
+	password  string
+	tlsConfig *tls.Config
+	timeout   time.Duration
+	idle      chan *conn
+}
+
+// NewClient returns a Client for the Redis server at addr. If tlsConfig is
+// nil the connection is made in plaintext, and if password is empty the
+// client doesn't authenticate. Each command is bounded by timeout unless its
+// context has an earlier deadline.
+func NewClient(addr, password string, tlsConfig *tls.Config, timeout time.Duration, poolSize int) *Client {
+	if poolSize < 1 {
+		poolSize = 1
+	}
+	return &Client{
+		addr:      addr,
+		password:  password,
+		tlsConfig: tlsConfig,
+		timeout:   timeout,
+		idle:      make(chan *conn, poolSize),
+	}
+}
+
+// responseKey returns the key under which the OCSP response for serial is
+// stored.
+func responseKey(serial string) string {
+	return "r:" + serial
+}
+
+// nonceKey returns the key under which an unredeemed nonce is stored.
+func nonceKey(nonce string) string {
+	return "n:" + nonce
+}
+
+// do runs a single command on a pooled connection, dialing a new one if none
+// are idle. Connections which see a network or protocol error are discarded.
+func (c *Client) do(ctx context.Context, args ...string) (interface{}, error) {
+	var rc *conn
+	select {
+	case rc = <-c.idle:
+	default:
+		var err error
+		rc, err = dial(ctx, c.addr, c.password, c.tlsConfig, c.timeout)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	reply, err := rc.do(ctx, c.timeout, args...)
+	var redisErr redisError
+	if err != nil && !errors.As(err, &redisErr) {
+		_ = rc.Close()
+		return nil, err
+	}
+	select {
+	case c.idle <- rc:
+	default:
+		_ = rc.Close()
+	}
+	return reply, err
+}
+
+// StoreResponse stores an OCSP response for serial, which expires from Redis
+// after ttl.
+func (c *Client) StoreResponse(ctx context.Context, serial string, response []byte, ttl time.Duration) error {
+	seconds := int64(ttl / time.Second)
+	if seconds < 1 {
+		return fmt.Errorf("TTL %s for serial %s is too short", ttl, serial)
+	}
+	_, err := c.do(ctx, "SET", responseKey(serial), string(response), "EX", strconv.FormatInt(seconds, 10))
+	if err != nil {
+		return fmt.Errorf("storing response for serial %s: %w", serial, err)
+	}
+	return nil
+}
+
+// GetResponse returns the OCSP response stored for serial, or
+// ErrRedisNotFound if there is none.
+func (c *Client) GetResponse(ctx context.Context, serial string) ([]byte, error) {
+	reply, err := c.do(ctx, "GET", responseKey(serial))
+	if err != nil {
+		return nil, fmt.Errorf("getting response for serial %s: %w", serial, err)
+	}
+	if reply == nil {
+		return nil, ErrRedisNotFound
+	}
+	response, ok := reply.([]byte)
+	if !ok {
+		return nil, fmt.Errorf("getting response for serial %s: unexpected reply type %T", serial, reply)
+	}
+	return response, nil
+}
+
+// StoreNonce records an unredeemed nonce, which expires from Redis after ttl.
+func (c *Client) StoreNonce(ctx context.Context, nonce string, ttl time.Duration) error {
+	millis := int64(ttl / time.Millisecond)
+	if millis < 1 {
+		return fmt.Errorf("TTL %s for nonce is too short", ttl)
+	}
+	_, err := c.do(ctx, "SET", nonceKey(nonce), "1", "PX", strconv.FormatInt(millis, 10))
+	if err != nil {
+		return fmt.Errorf("storing nonce: %w", err)
+	}
+	return nil
+}
+
+// RedeemNonce atomically removes a nonce, returning true if it was present.
+// A nonce can therefore only be redeemed once, by any client of the server.
+func (c *Client) RedeemNonce(ctx context.Context, nonce string) (bool, error) {
+	reply, err := c.do(ctx, "DEL", nonceKey(nonce))
+	if err != nil {
+		return false, fmt.Errorf("redeeming nonce: %w", err)
+	}
+	deleted, ok := reply.(int64)
+	if !ok {
+		return false, fmt.Errorf("redeeming nonce: unexpected reply type %T", reply)
+	}
+	return deleted == 1, nil
+}
+
+// Close closes all idle connections.
+func (c *Client) Close() {
+	for {
+		select {
+		case rc := <-c.idle:
+			_ = rc.Close()
+		default:
+			return
+		}
+	}
+}
@@ -0,0 +1,193 @@
+package rocsp
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/letsencrypt/boulder/test"
+)
+
+// fakeRedis is a minimal in-process Redis server which supports the AUTH,
+// DEL, GET, and SET commands.
+type fakeRedis struct {
+	sync.Mutex
+	listener net.Listener
+	password string
+	data     map[string]string
+	ttls     map[string]string
+}
+
+func newFakeRedis(t *testing.T, password string) *fakeRedis {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	test.AssertNotError(t, err, "listening")
+	fr := &fakeRedis{
+		listener: l,
+		password: password,
+		data:     make(map[string]string),
+		ttls:     make(map[string]string),
+	}
+	go fr.serve()
+	return fr
+}
+
+func (fr *fakeRedis) serve() {
+	for {
+		c, err := fr.listener.Accept()
+		if err != nil {
+			return
+		}
+		go fr.handle(c)
+	}
+}
+
+func (fr *fakeRedis) handle(c net.Conn) {
+	defer c.Close()
+	r := bufio.NewReader(c)
+	authed := fr.password == ""
+	for {
+		reply, err := readReply(r)
+		if err != nil {
+			return
+		}
+		var args []string
+		for _, arg := range reply.([]interface{}) {
+			args = append(args, string(arg.([]byte)))
+		}
+		fr.Lock()
+		var resp string
+		switch {
+		case args[0] == "AUTH":
+			if args[1] == fr.password {
+				authed = true
+				resp = "+OK\r\n"
+			} else {
+				resp = "-WRONGPASS invalid password\r\n"
+			}
+		case !authed:
+			resp = "-NOAUTH Authentication required.\r\n"
+		case args[0] == "SET":
+			fr.data[args[1]] = args[2]
+			fr.ttls[args[1]] = strings.Join(args[3:], " ")
+			resp = "+OK\r\n"
+		case args[0] == "GET":
+			v, ok := fr.data[args[1]]
+			if ok {
+				resp = fmt.Sprintf("$%d\r\n%s\r\n", len(v), v)
+			} else {
+				resp = "$-1\r\n"
+			}
+		case args[0] == "DEL":
+			_, ok := fr.data[args[1]]
+			delete(fr.data, args[1])
+			if ok {
+				resp = ":1\r\n"
+			} else {
+				resp = ":0\r\n"
+			}
+		default:
+			resp = "-ERR unknown command\r\n"
+		}
+		fr.Unlock()
+		_, err = c.Write([]byte(resp))
+		if err != nil {
+			return
+		}
+	}
+}
+
+func TestStoreAndGetResponse(t *testing.T) {
+	fr := newFakeRedis(t, "hunter2")
+	defer fr.listener.Close()
+
+	client := NewClient(fr.listener.Addr().String(), "hunter2", nil, time.Second, 2)
+	defer client.Close()
+	ctx := context.Background()
+
+	_, err := client.GetResponse(ctx, "00ff")
+	test.AssertErrorIs(t, err, ErrRedisNotFound)
+
+	response := []byte("an\r\nocsp\x00response")
+	err = client.StoreResponse(ctx, "00ff", response, time.Hour)
+	test.AssertNotError(t, err, "StoreResponse failed")
+	test.AssertEquals(t, fr.ttls["r:00ff"], "EX 3600")
+
+	got, err := client.GetResponse(ctx, "00ff")
+	test.AssertNotError(t, err, "GetResponse failed")
+	test.AssertByteEquals(t, got, response)
+
+	err = client.StoreResponse(ctx, "00ff", response, time.Millisecond)
+	test.AssertError(t, err, "StoreResponse didn't fail with a short TTL")
+}
+
+func TestStoreAndRedeemNonce(t *testing.T) {
+	fr := newFakeRedis(t, "")
+	defer fr.listener.Close()
+
+	client := NewClient(fr.listener.Addr().String(), "", nil, time.Second, 1)
+	defer client.Close()
+	ctx := context.Background()
+
+	redeemed, err := client.RedeemNonce(ctx, "taroabcd")
+	test.AssertNotError(t, err, "RedeemNonce failed")
+	test.Assert(t, !redeemed, "redeemed a nonce that was never stored")
+
+	err = client.StoreNonce(ctx, "taroabcd", 90*time.Second)
+	test.AssertNotError(t, err, "StoreNonce failed")
+	test.AssertEquals(t, fr.ttls["n:taroabcd"], "PX 90000")
+
+	redeemed, err = client.RedeemNonce(ctx, "taroabcd")
+	test.AssertNotError(t, err, "RedeemNonce failed")
+	test.Assert(t, redeemed, "didn't redeem a stored nonce")
+
+	redeemed, err = client.RedeemNonce(ctx, "taroabcd")
+	test.AssertNotError(t, err, "RedeemNonce failed")
+	test.Assert(t, !redeemed, "redeemed the same nonce twice")
+}
+
+func TestBadPassword(t *testing.T) {
+	fr := newFakeRedis(t, "hunter2")
+	defer fr.listener.Close()
+
+	client := NewClient(fr.listener.Addr().String(), "wrong", nil, time.Second, 1)
+	_, err := client.GetResponse(context.Background(), "00ff")
+	test.AssertError(t, err, "GetResponse didn't fail with a bad password")
+	var redisErr redisError
+	test.Assert(t, errors.As(err, &redisErr), "expected a redis error reply")
+}
+
+func TestReadReply(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected interface{}
+		err      bool
+	}{
+		{"+OK\r\n", "OK", false},
+		{":42\r\n", int64(42), false},
+		{"$3\r\nfoo\r\n", []byte("foo"), false},
+		{"$-1\r\n", nil, false},
+		{"*2\r\n$1\r\na\r\n:1\r\n", []interface{}{[]byte("a"), int64(1)}, false},
+		{"-ERR oops\r\n", nil, true},
+		{"?what\r\n", nil, true},
+		{"+OK\n", nil, true},
+		{"$5\r\nfoo\r\n", nil, true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			reply, err := readReply(bufio.NewReader(strings.NewReader(tc.input)))
+			if tc.err {
+				test.AssertError(t, err, "expected error")
+				return
+			}
+			test.AssertNotError(t, err, "unexpected error")
+			test.AssertDeepEquals(t, reply, tc.expected)
+		})
+	}
+}
@@ -0,0 +1,10 @@
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+
+-- Custom reminder schedules are stored in notificationNagTimes.
+ALTER TABLE `notificationPreferences` DROP COLUMN `nagTimes`;
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+
+ALTER TABLE `notificationPreferences` ADD COLUMN `nagTimes` varchar(255) NOT NULL DEFAULT '';
@@ -0,0 +1,29 @@
+
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+
+CREATE TABLE `incidents` (
+    `id` bigint(20) NOT NULL AUTO_INCREMENT,
+    `name` varchar(255) NOT NULL UNIQUE,
+    `url` varchar(1024) NOT NULL,
+    `renewBy` datetime NOT NULL,
+    `enabled` boolean DEFAULT false,
+    `resolved` boolean DEFAULT false,
+    `created` datetime NOT NULL,
+    PRIMARY KEY (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+CREATE TABLE `incidentSerials` (
+    `incidentID` bigint(20) NOT NULL,
+    `serial` varchar(255) NOT NULL,
+    `registrationID` bigint(20) DEFAULT NULL,
+    PRIMARY KEY (`incidentID`, `serial`),
+    KEY `serial_idx` (`serial`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+
+DROP TABLE `incidentSerials`;
+
+DROP TABLE `incidents`;
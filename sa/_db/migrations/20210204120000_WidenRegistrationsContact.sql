@@ -0,0 +1,12 @@
+
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+
+-- Encrypted contacts are longer than their plaintext, so the contact column
+-- needs more room than the 191 bytes the RA allows for plaintext contacts.
+ALTER TABLE `registrations` MODIFY COLUMN `contact` varchar(512) CHARACTER SET utf8mb4 NOT NULL;
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+
+ALTER TABLE `registrations` MODIFY COLUMN `contact` varchar(191) CHARACTER SET utf8mb4 NOT NULL;
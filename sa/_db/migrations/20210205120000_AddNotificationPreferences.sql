@@ -0,0 +1,16 @@
+
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+
+CREATE TABLE `notificationPreferences` (
+    `registrationID` bigint(20) NOT NULL,
+    `webhookURL` varchar(255) NOT NULL DEFAULT '',
+    `webhookSecret` varbinary(64) DEFAULT NULL,
+    `updated` datetime NOT NULL,
+    PRIMARY KEY (`registrationID`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+
+DROP TABLE `notificationPreferences`;
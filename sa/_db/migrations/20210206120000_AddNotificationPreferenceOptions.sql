@@ -0,0 +1,16 @@
+
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+
+ALTER TABLE `notificationPreferences`
+    ADD COLUMN `emailOptOut` boolean NOT NULL DEFAULT false,
+    ADD COLUMN `nagTimes` varchar(255) NOT NULL DEFAULT '',
+    ADD COLUMN `email` varchar(255) NOT NULL DEFAULT '';
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+
+ALTER TABLE `notificationPreferences`
+    DROP COLUMN `emailOptOut`,
+    DROP COLUMN `nagTimes`,
+    DROP COLUMN `email`;
@@ -0,0 +1,16 @@
+
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+
+CREATE TABLE `accountFeatureOverrides` (
+    `registrationID` bigint(20) NOT NULL,
+    `feature` varchar(255) NOT NULL,
+    `enabled` tinyint(1) NOT NULL,
+    `updated` datetime NOT NULL,
+    PRIMARY KEY (`registrationID`, `feature`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+
+DROP TABLE `accountFeatureOverrides`;
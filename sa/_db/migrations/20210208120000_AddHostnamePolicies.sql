@@ -0,0 +1,17 @@
+
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+
+CREATE TABLE `hostnamePolicies` (
+    `version` bigint(20) NOT NULL AUTO_INCREMENT,
+    `policy` mediumblob NOT NULL,
+    `created` datetime NOT NULL,
+    `createdBy` varchar(255) NOT NULL,
+    `comment` varchar(255) NOT NULL DEFAULT '',
+    PRIMARY KEY (`version`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+
+DROP TABLE `hostnamePolicies`;
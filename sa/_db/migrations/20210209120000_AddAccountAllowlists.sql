@@ -0,0 +1,15 @@
+
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+
+CREATE TABLE `accountAllowlists` (
+    `registrationID` bigint(20) NOT NULL,
+    `domain` varchar(255) NOT NULL,
+    `created` datetime NOT NULL,
+    PRIMARY KEY (`registrationID`, `domain`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+
+DROP TABLE `accountAllowlists`;
@@ -0,0 +1,21 @@
+
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+
+CREATE TABLE `certificateMetadata` (
+    `id` bigint(20) NOT NULL AUTO_INCREMENT,
+    `serial` varchar(255) NOT NULL,
+    `registrationID` bigint(20) NOT NULL,
+    `issued` datetime NOT NULL,
+    `certificateProfileName` varchar(32) NOT NULL DEFAULT '',
+    `keyAlgorithm` varchar(32) NOT NULL,
+    `validationMethods` varchar(255) NOT NULL,
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `serial` (`serial`),
+    KEY `issued_profile_keyAlgorithm_validationMethods_idx` (`issued`, `certificateProfileName`, `keyAlgorithm`, `validationMethods`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+
+DROP TABLE `certificateMetadata`;
@@ -0,0 +1,19 @@
+
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+
+CREATE TABLE `accountEvents` (
+    `id` bigint(20) NOT NULL AUTO_INCREMENT,
+    `registrationID` bigint(20) NOT NULL,
+    `created` datetime NOT NULL,
+    `eventType` varchar(32) NOT NULL,
+    `details` mediumtext NOT NULL,
+    PRIMARY KEY (`id`),
+    KEY `registrationID_id_idx` (`registrationID`, `id`),
+    KEY `registrationID_created_idx` (`registrationID`, `created`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+
+DROP TABLE `accountEvents`;
@@ -0,0 +1,20 @@
+
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+
+CREATE TABLE `keyRollovers` (
+    `id` bigint(20) NOT NULL AUTO_INCREMENT,
+    `registrationID` bigint(20) NOT NULL,
+    `oldKeyHash` binary(32) NOT NULL,
+    `newKeyHash` binary(32) NOT NULL,
+    `rolledOver` datetime NOT NULL,
+    PRIMARY KEY (`id`),
+    KEY `registrationID_rolledOver_idx` (`registrationID`, `rolledOver`),
+    KEY `oldKeyHash_rolledOver_idx` (`oldKeyHash`, `rolledOver`),
+    KEY `newKeyHash_rolledOver_idx` (`newKeyHash`, `rolledOver`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+
+DROP TABLE `keyRollovers`;
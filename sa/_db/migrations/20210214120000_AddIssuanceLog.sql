@@ -0,0 +1,23 @@
+
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+
+CREATE TABLE `issuanceLog` (
+    `chainID` varchar(255) NOT NULL,
+    `seq` bigint(20) NOT NULL,
+    `entryType` varchar(32) NOT NULL,
+    `serial` varchar(255) NOT NULL,
+    `certificateProfileName` varchar(32) NOT NULL,
+    `namesHash` binary(32) NOT NULL,
+    `csrHash` varbinary(32) NOT NULL,
+    `issued` datetime NOT NULL,
+    `prevHash` binary(32) NOT NULL,
+    `hash` binary(32) NOT NULL,
+    PRIMARY KEY (`chainID`, `seq`),
+    KEY `serial_idx` (`serial`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+
+DROP TABLE `issuanceLog`;
@@ -0,0 +1,17 @@
+
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+
+CREATE TABLE `restrictedAccounts` (
+    `registrationID` bigint(20) NOT NULL,
+    `created` datetime NOT NULL,
+    PRIMARY KEY (`registrationID`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+INSERT INTO `restrictedAccounts` (`registrationID`, `created`)
+    SELECT `registrationID`, MIN(`created`) FROM `accountAllowlists` GROUP BY `registrationID`;
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+
+DROP TABLE `restrictedAccounts`;
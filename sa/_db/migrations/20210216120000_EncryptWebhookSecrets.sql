@@ -0,0 +1,13 @@
+
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+
+-- Encrypted webhook secrets are longer than their plaintext, so the
+-- webhookSecret column needs more room than the 64 bytes the SA allows for
+-- plaintext secrets.
+ALTER TABLE `notificationPreferences` MODIFY COLUMN `webhookSecret` varbinary(255) DEFAULT NULL;
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+
+ALTER TABLE `notificationPreferences` MODIFY COLUMN `webhookSecret` varbinary(64) DEFAULT NULL;
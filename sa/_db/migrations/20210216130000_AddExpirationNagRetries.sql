@@ -0,0 +1,20 @@
+
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+
+-- expirationNagRetries holds the expiration notices which weren't delivered
+-- over one of an account's channels, after the certificate was marked as
+-- notified because another channel succeeded.
+CREATE TABLE `expirationNagRetries` (
+    `serial` varchar(255) NOT NULL,
+    `channel` varchar(16) NOT NULL,
+    `registrationID` bigint(20) NOT NULL,
+    `created` datetime NOT NULL,
+    PRIMARY KEY (`serial`, `channel`),
+    KEY `created_idx` (`created`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+
+DROP TABLE `expirationNagRetries`;
@@ -0,0 +1,19 @@
+
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+
+-- notificationNagTimes holds each account's custom reminder schedule, one row
+-- per nag time in seconds, so that the expiration-mailer can select only the
+-- certificates which are due a notice under their account's schedule. The
+-- notificationPreferences nagTimes column remains the schedule returned by
+-- the SA.
+CREATE TABLE `notificationNagTimes` (
+    `registrationID` bigint(20) NOT NULL,
+    `nagTime` bigint(20) NOT NULL,
+    PRIMARY KEY (`registrationID`, `nagTime`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+
+DROP TABLE `notificationNagTimes`;
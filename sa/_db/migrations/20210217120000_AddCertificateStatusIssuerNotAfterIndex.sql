@@ -0,0 +1,15 @@
+
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+
+-- issuerID_notAfter_idx lets the ocsp-responder find whether an issuer has any
+-- unexpired certificates with a single index lookup, rather than scanning
+-- every unexpired certificate's row in notAfter_idx.
+ALTER TABLE certificateStatus
+       ADD INDEX `issuerID_notAfter_idx` (`issuerID`, `notAfter`);
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+
+ALTER TABLE certificateStatus
+       DROP INDEX `issuerID_notAfter_idx`;
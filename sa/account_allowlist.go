@@ -0,0 +1,150 @@
+package sa
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	corepb "github.com/letsencrypt/boulder/core/proto"
+	"github.com/letsencrypt/boulder/db"
+	berrors "github.com/letsencrypt/boulder/errors"
+	"github.com/letsencrypt/boulder/policy"
+	sapb "github.com/letsencrypt/boulder/sa/proto"
+)
+
+// accountAllowlistModel represents a row in the accountAllowlists table. An
+// account with a row in the restrictedAccounts table may only be issued
+// certificates for names under the registered domains its allowlist rows
+// list, of which it may have none.
+type accountAllowlistModel struct {
+	RegistrationID int64     `db:"registrationID"`
+	Domain         string    `db:"domain"`
+	Created        time.Time `db:"created"`
+}
+
+// GetAccountAllowlist returns whether the given registration is restricted
+// to issuing for the registered domains on its allowlist, and those domains.
+func (ssa *SQLStorageAuthority) GetAccountAllowlist(ctx context.Context, req *sapb.RegistrationID) (*sapb.AccountAllowlist, error) {
+	if req == nil || req.Id == 0 {
+		return nil, errIncompleteRequest
+	}
+	var restricted int64
+	err := ssa.dbMap.WithContext(ctx).SelectOne(
+		&restricted,
+		`SELECT COUNT(1) FROM restrictedAccounts WHERE registrationID = ?`,
+		req.Id,
+	)
+	if err != nil {
+		return nil, err
+	}
+	var domains []string
+	_, err = ssa.dbMap.WithContext(ctx).Select(
+		&domains,
+		`SELECT domain FROM accountAllowlists WHERE registrationID = ? ORDER BY domain`,
+		req.Id,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &sapb.AccountAllowlist{Domains: domains, Restricted: restricted != 0}, nil
+}
+
+// AddAccountAllowlistEntry adds a registered domain to a registration's
+// allowlist, restricting the registration to the domains on it if it wasn't
+// already. Adding a domain which is already listed isn't an error.
+func (ssa *SQLStorageAuthority) AddAccountAllowlistEntry(ctx context.Context, req *sapb.AccountAllowlistEntry) (*corepb.Empty, error) {
+	if req == nil || req.RegistrationID == 0 || req.Domain == "" {
+		return nil, errIncompleteRequest
+	}
+	registered, err := policy.RegisteredDomain(req.Domain)
+	if err != nil {
+		return nil, berrors.MalformedError("allowlist domain %q is invalid: %s", req.Domain, err)
+	}
+	if registered != req.Domain || strings.ToLower(req.Domain) != req.Domain {
+		return nil, berrors.MalformedError("allowlist domain %q must be a lowercase registered domain, like %q", req.Domain, strings.ToLower(registered))
+	}
+	_, err = ssa.withTransaction(ctx, func(txWithCtx db.Executor) (interface{}, error) {
+		now := ssa.clk.Now()
+		_, err := txWithCtx.Exec(
+			`INSERT IGNORE INTO restrictedAccounts (registrationID, created)
+			VALUES (?, ?)`,
+			req.RegistrationID,
+			now,
+		)
+		if err != nil {
+			return nil, err
+		}
+		_, err = txWithCtx.Exec(
+			`INSERT IGNORE INTO accountAllowlists (registrationID, domain, created)
+			VALUES (?, ?, ?)`,
+			req.RegistrationID,
+			req.Domain,
+			now,
+		)
+		return nil, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &corepb.Empty{}, nil
+}
+
+// RemoveAccountAllowlistEntry removes a registered domain from a
+// registration's allowlist. The registration stays restricted, so removing
+// the last one leaves it unable to issue for any names until its allowlist
+// is cleared. It returns a berrors.NotFound error if the domain wasn't
+// listed.
+func (ssa *SQLStorageAuthority) RemoveAccountAllowlistEntry(ctx context.Context, req *sapb.AccountAllowlistEntry) (*corepb.Empty, error) {
+	if req == nil || req.RegistrationID == 0 || req.Domain == "" {
+		return nil, errIncompleteRequest
+	}
+	var result sql.Result
+	err := ssa.retryWrite(ctx, func() error {
+		var err error
+		result, err = ssa.dbMap.WithContext(ctx).Exec(
+			`DELETE FROM accountAllowlists WHERE registrationID = ? AND domain = ?`,
+			req.RegistrationID,
+			req.Domain,
+		)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if rows == 0 {
+		return nil, berrors.NotFoundError("registration %d has no allowlist entry for %q", req.RegistrationID, req.Domain)
+	}
+	return &corepb.Empty{}, nil
+}
+
+// ClearAccountAllowlist removes all of a registration's allowlist entries and
+// lifts its restriction to them. It returns a berrors.NotFound error if the
+// registration wasn't restricted.
+func (ssa *SQLStorageAuthority) ClearAccountAllowlist(ctx context.Context, req *sapb.RegistrationID) (*corepb.Empty, error) {
+	if req == nil || req.Id == 0 {
+		return nil, errIncompleteRequest
+	}
+	restricted, err := ssa.withTransaction(ctx, func(txWithCtx db.Executor) (interface{}, error) {
+		_, err := txWithCtx.Exec(`DELETE FROM accountAllowlists WHERE registrationID = ?`, req.Id)
+		if err != nil {
+			return nil, err
+		}
+		result, err := txWithCtx.Exec(`DELETE FROM restrictedAccounts WHERE registrationID = ?`, req.Id)
+		if err != nil {
+			return nil, err
+		}
+		return result.RowsAffected()
+	})
+	if err != nil {
+		return nil, err
+	}
+	if restricted.(int64) == 0 {
+		return nil, berrors.NotFoundError("registration %d isn't restricted to an allowlist", req.Id)
+	}
+	return &corepb.Empty{}, nil
+}
@@ -0,0 +1,130 @@
+package sa
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	corepb "github.com/letsencrypt/boulder/core/proto"
+	berrors "github.com/letsencrypt/boulder/errors"
+	sapb "github.com/letsencrypt/boulder/sa/proto"
+)
+
+const (
+	// defaultAccountEventRetention is how long accounts' audit events are kept
+	// for unless SetAccountEventRetention says otherwise.
+	defaultAccountEventRetention = 90 * 24 * time.Hour
+
+	// maxAccountEventsPerPage caps the number of events GetAccountEvents
+	// returns at once.
+	maxAccountEventsPerPage = 100
+
+	// accountEventPruneBatchSize caps the number of expired events deleted
+	// each time an event is added to an account.
+	accountEventPruneBatchSize = 100
+)
+
+// accountEventModel represents a row in the accountEvents table, which holds
+// the audit trail of the actions taken with each account.
+type accountEventModel struct {
+	ID             int64     `db:"id"`
+	RegistrationID int64     `db:"registrationID"`
+	Created        time.Time `db:"created"`
+	EventType      string    `db:"eventType"`
+	Details        string    `db:"details"`
+}
+
+// SetAccountEventRetention sets how long accounts' audit events are kept for.
+// Older events are no longer returned by GetAccountEvents, and are deleted as
+// new events are added to their accounts. The events of accounts which get no
+// new events are deleted by boulder-janitor's accountEvents job, whose grace
+// period should be at least the retention period.
+func (ssa *SQLStorageAuthority) SetAccountEventRetention(retention time.Duration) {
+	ssa.accountEventRetention = retention
+}
+
+// AddAccountEvent adds an event to a registration's audit trail, and deletes
+// any of the registration's events which are older than the retention period.
+// The event's details must be a JSON object.
+func (ssa *SQLStorageAuthority) AddAccountEvent(ctx context.Context, req *sapb.AccountEvent) (*corepb.Empty, error) {
+	if req == nil || req.RegistrationID == 0 || req.Type == "" || req.Details == "" {
+		return nil, errIncompleteRequest
+	}
+	var details map[string]interface{}
+	err := json.Unmarshal([]byte(req.Details), &details)
+	if err != nil {
+		return nil, berrors.MalformedError("account event details must be a JSON object: %s", err)
+	}
+	now := ssa.clk.Now()
+	err = ssa.retryWrite(ctx, func() error {
+		return ssa.dbMap.WithContext(ctx).Insert(&accountEventModel{
+			RegistrationID: req.RegistrationID,
+			Created:        now,
+			EventType:      req.Type,
+			Details:        req.Details,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The event has been stored, so failing to prune old ones isn't an error.
+	// They'll be deleted along with the account's next event instead.
+	err = ssa.retryWrite(ctx, func() error {
+		_, err := ssa.dbMap.WithContext(ctx).Exec(
+			`DELETE FROM accountEvents WHERE registrationID = ? AND created < ? LIMIT ?`,
+			req.RegistrationID,
+			now.Add(-ssa.accountEventRetention),
+			accountEventPruneBatchSize,
+		)
+		return err
+	})
+	if err != nil {
+		ssa.log.Warningf("Pruning events for registration %d: %s", req.RegistrationID, err)
+	}
+	return &corepb.Empty{}, nil
+}
+
+// GetAccountEvents returns a registration's events from within the retention
+// period, newest first. At most the request's limit, or
+// maxAccountEventsPerPage, events are returned, and the next page can be read
+// by requesting the events before the last one's ID.
+func (ssa *SQLStorageAuthority) GetAccountEvents(ctx context.Context, req *sapb.GetAccountEventsRequest) (*sapb.AccountEvents, error) {
+	if req == nil || req.RegistrationID == 0 {
+		return nil, errIncompleteRequest
+	}
+	limit := req.Limit
+	if limit <= 0 || limit > maxAccountEventsPerPage {
+		limit = maxAccountEventsPerPage
+	}
+	query := `SELECT id, registrationID, created, eventType, details
+		FROM accountEvents
+		WHERE registrationID = :regID AND created >= :earliest`
+	args := map[string]interface{}{
+		"regID":    req.RegistrationID,
+		"earliest": ssa.clk.Now().Add(-ssa.accountEventRetention),
+		"limit":    limit,
+	}
+	if req.BeforeID != 0 {
+		query += ` AND id < :beforeID`
+		args["beforeID"] = req.BeforeID
+	}
+	query += ` ORDER BY id DESC LIMIT :limit`
+
+	var models []accountEventModel
+	_, err := ssa.dbMap.WithContext(ctx).Select(&models, query, args)
+	if err != nil {
+		return nil, err
+	}
+	events := make([]*sapb.AccountEvent, 0, len(models))
+	for _, m := range models {
+		events = append(events, &sapb.AccountEvent{
+			Id:             m.ID,
+			RegistrationID: m.RegistrationID,
+			Created:        m.Created.UnixNano(),
+			Type:           m.EventType,
+			Details:        m.Details,
+		})
+	}
+	return &sapb.AccountEvents{Events: events}, nil
+}
@@ -0,0 +1,143 @@
+package sa
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	corepb "github.com/letsencrypt/boulder/core/proto"
+	"github.com/letsencrypt/boulder/db"
+	berrors "github.com/letsencrypt/boulder/errors"
+	sapb "github.com/letsencrypt/boulder/sa/proto"
+)
+
+// certificateMetadataModel represents a row in the certificateMetadata table,
+// which records the properties of each certificate that issuance reports are
+// broken down by.
+type certificateMetadataModel struct {
+	ID                     int64     `db:"id"`
+	Serial                 string    `db:"serial"`
+	RegistrationID         int64     `db:"registrationID"`
+	Issued                 time.Time `db:"issued"`
+	CertificateProfileName string    `db:"certificateProfileName"`
+	KeyAlgorithm           string    `db:"keyAlgorithm"`
+	// ValidationMethods is the sorted, comma separated, set of challenge types
+	// the certificate's authorizations were validated with.
+	ValidationMethods string `db:"validationMethods"`
+}
+
+// issuanceCountModel is a row of the results of GetIssuanceCounts' query.
+type issuanceCountModel struct {
+	Day                    string `db:"day"`
+	CertificateProfileName string `db:"certificateProfileName"`
+	KeyAlgorithm           string `db:"keyAlgorithm"`
+	ValidationMethods      string `db:"validationMethods"`
+	Count                  int64  `db:"count"`
+}
+
+// issuanceCountDimensions maps the dimensions GetIssuanceCounts can group by
+// to the expressions selecting them from the certificateMetadata table.
+var issuanceCountDimensions = map[string]string{
+	"day":                    "DATE_FORMAT(issued, '%Y-%m-%d')",
+	"certificateProfileName": "certificateProfileName",
+	"keyAlgorithm":           "keyAlgorithm",
+	"validationMethods":      "validationMethods",
+}
+
+// issuanceCountColumns is the order of issuanceCountModel's dimensions.
+var issuanceCountColumns = []string{"day", "certificateProfileName", "keyAlgorithm", "validationMethods"}
+
+// maxIssuanceCountsRange is the longest period GetIssuanceCounts will count
+// certificates over in one query.
+const maxIssuanceCountsRange = 366 * 24 * time.Hour
+
+// AddCertificateMetadata records the metadata of a newly issued certificate.
+// It returns a berrors.Duplicate error if the certificate's metadata has
+// already been recorded.
+func (ssa *SQLStorageAuthority) AddCertificateMetadata(ctx context.Context, req *sapb.CertificateMetadata) (*corepb.Empty, error) {
+	if req == nil || req.Serial == "" || req.RegistrationID == 0 || req.Issued == 0 || req.KeyAlgorithm == "" {
+		return nil, errIncompleteRequest
+	}
+	methods := make([]string, len(req.ValidationMethods))
+	copy(methods, req.ValidationMethods)
+	sort.Strings(methods)
+	cm := &certificateMetadataModel{
+		Serial:                 req.Serial,
+		RegistrationID:         req.RegistrationID,
+		Issued:                 time.Unix(0, req.Issued),
+		CertificateProfileName: req.CertificateProfileName,
+		KeyAlgorithm:           req.KeyAlgorithm,
+		ValidationMethods:      strings.Join(methods, ","),
+	}
+	err := ssa.retryWrite(ctx, func() error {
+		return ssa.dbMap.WithContext(ctx).Insert(cm)
+	})
+	if err != nil {
+		if db.IsDuplicate(err) {
+			return nil, berrors.DuplicateError("metadata for certificate %q already exists", req.Serial)
+		}
+		return nil, err
+	}
+	return &corepb.Empty{}, nil
+}
+
+// GetIssuanceCounts counts the certificates issued at or after the request's
+// earliest time and before its latest, grouped by the requested dimensions.
+// The counts are ordered by their dimensions. Certificates issued before their
+// metadata was recorded aren't counted.
+func (ssa *SQLStorageAuthority) GetIssuanceCounts(ctx context.Context, req *sapb.IssuanceCountsRequest) (*sapb.IssuanceCounts, error) {
+	if req == nil || req.Range == nil || req.Range.Earliest == 0 || req.Range.Latest == 0 {
+		return nil, errIncompleteRequest
+	}
+	earliest := time.Unix(0, req.Range.Earliest)
+	latest := time.Unix(0, req.Range.Latest)
+	if !earliest.Before(latest) {
+		return nil, berrors.MalformedError("issuance count range must end after it begins")
+	}
+	if latest.Sub(earliest) > maxIssuanceCountsRange {
+		return nil, berrors.MalformedError("issuance count range must be at most %s", maxIssuanceCountsRange)
+	}
+
+	grouped := make(map[string]bool, len(req.GroupBy))
+	for _, dimension := range req.GroupBy {
+		if _, ok := issuanceCountDimensions[dimension]; !ok {
+			return nil, berrors.MalformedError("can't group issuance counts by %q", dimension)
+		}
+		grouped[dimension] = true
+	}
+	// Every column is selected, so that the results fit issuanceCountModel, but
+	// those which aren't grouped by are left empty.
+	var selects, groupBy []string
+	for _, column := range issuanceCountColumns {
+		if grouped[column] {
+			selects = append(selects, issuanceCountDimensions[column]+" AS "+column)
+			groupBy = append(groupBy, column)
+		} else {
+			selects = append(selects, "'' AS "+column)
+		}
+	}
+	query := `SELECT ` + strings.Join(selects, ", ") + `, COUNT(1) AS count
+		FROM certificateMetadata
+		WHERE issued >= ? AND issued < ?`
+	if len(groupBy) > 0 {
+		query += ` GROUP BY ` + strings.Join(groupBy, ", ") + ` ORDER BY ` + strings.Join(groupBy, ", ")
+	}
+
+	var rows []issuanceCountModel
+	_, err := ssa.dbMap.WithContext(ctx).Select(&rows, query, earliest, latest)
+	if err != nil {
+		return nil, err
+	}
+	counts := make([]*sapb.IssuanceCount, 0, len(rows))
+	for _, row := range rows {
+		counts = append(counts, &sapb.IssuanceCount{
+			Day:                    row.Day,
+			CertificateProfileName: row.CertificateProfileName,
+			KeyAlgorithm:           row.KeyAlgorithm,
+			ValidationMethods:      row.ValidationMethods,
+			Count:                  row.Count,
+		})
+	}
+	return &sapb.IssuanceCounts{Counts: counts}, nil
+}
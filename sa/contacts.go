@@ -0,0 +1,205 @@
+package sa
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// encryptedContactPrefix marks a registration contact entry as ciphertext
+// produced by a ContactCipher. Encrypted contacts are stored in the contact
+// column as a JSON list containing a single string of the form
+// "enc1:<key ID>:<base64url(nonce || sealed contacts)>". Keeping the JSON list
+// framing means the column can still be parsed by BoulderTypeConverter, and
+// by tools which read the registrations table directly, regardless of whether
+// the row has been encrypted yet.
+const encryptedContactPrefix = "enc1:"
+
+// ContactCipher encrypts and decrypts the contents of the registrations
+// table's contact column, and the notificationPreferences table's
+// webhookSecret column, with AES-256-GCM. It holds a set of keys indexed by
+// key ID, one of which is active and used for all new encryptions. The other
+// keys are only used to decrypt existing rows, which allows keys to be rotated
+// by adding a new active key and re-encrypting existing rows before removing
+// the old one.
+type ContactCipher struct {
+	activeKeyID string
+	aeads       map[string]cipher.AEAD
+}
+
+// NewContactCipher returns a ContactCipher using the given 32 byte keys, which
+// are indexed by key ID. The activeKeyID must be present in keys.
+func NewContactCipher(keys map[string][]byte, activeKeyID string) (*ContactCipher, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("no contact encryption keys provided")
+	}
+	if _, ok := keys[activeKeyID]; !ok {
+		return nil, fmt.Errorf("active contact encryption key %q not found", activeKeyID)
+	}
+	cc := &ContactCipher{
+		activeKeyID: activeKeyID,
+		aeads:       make(map[string]cipher.AEAD, len(keys)),
+	}
+	for id, key := range keys {
+		if id == "" || strings.Contains(id, ":") {
+			return nil, fmt.Errorf("invalid contact encryption key ID %q", id)
+		}
+		if len(key) != 32 {
+			return nil, fmt.Errorf("contact encryption key %q is %d bytes, expected 32", id, len(key))
+		}
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, err
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, err
+		}
+		cc.aeads[id] = aead
+	}
+	return cc, nil
+}
+
+// Encrypt seals a list of contacts with the active key, returning the list
+// which should be stored in the contact column in its place. Empty lists are
+// returned unchanged, so that registrations without contacts can still be
+// identified without decrypting them.
+func (cc *ContactCipher) Encrypt(contacts []string) ([]string, error) {
+	if len(contacts) == 0 {
+		return contacts, nil
+	}
+	plaintext, err := json.Marshal(contacts)
+	if err != nil {
+		return nil, err
+	}
+	sealed, err := cc.seal(plaintext, "")
+	if err != nil {
+		return nil, err
+	}
+	return []string{sealed}, nil
+}
+
+// seal encrypts plaintext with the active key, returning it in the form
+// "enc1:<key ID>:<base64url(nonce || sealed plaintext)>". The key ID and
+// label are used as additional data, so that a ciphertext can't be relabelled
+// as belonging to a different key, or moved to a column with a different
+// label.
+func (cc *ContactCipher) seal(plaintext []byte, label string) (string, error) {
+	aead := cc.aeads[cc.activeKeyID]
+	nonce := make([]byte, aead.NonceSize())
+	_, err := rand.Read(nonce)
+	if err != nil {
+		return "", err
+	}
+	sealed := aead.Seal(nonce, nonce, plaintext, []byte(cc.activeKeyID+label))
+	return fmt.Sprintf("%s%s:%s", encryptedContactPrefix, cc.activeKeyID, base64.RawURLEncoding.EncodeToString(sealed)), nil
+}
+
+// open decrypts a ciphertext produced by seal with the given key ID and label.
+func (cc *ContactCipher) open(keyID string, ciphertext []byte, label string) ([]byte, error) {
+	aead, ok := cc.aeads[keyID]
+	if !ok {
+		return nil, fmt.Errorf("unknown contact encryption key %q", keyID)
+	}
+	if len(ciphertext) < aead.NonceSize() {
+		return nil, errors.New("encrypted value is too short")
+	}
+	nonce, sealed := ciphertext[:aead.NonceSize()], ciphertext[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, sealed, []byte(keyID+label))
+	if err != nil {
+		return nil, fmt.Errorf("decrypting with key %q: %s", keyID, err)
+	}
+	return plaintext, nil
+}
+
+// Decrypt returns the plaintext contacts for a list read from the contact
+// column. Lists which aren't encrypted are returned unchanged.
+func (cc *ContactCipher) Decrypt(stored []string) ([]string, error) {
+	keyID, ciphertext, err := parseEncryptedContact(stored)
+	if err != nil || keyID == "" {
+		return stored, err
+	}
+	plaintext, err := cc.open(keyID, ciphertext, "")
+	if err != nil {
+		return nil, fmt.Errorf("contact: %s", err)
+	}
+	var contacts []string
+	err = json.Unmarshal(plaintext, &contacts)
+	if err != nil {
+		return nil, badJSONError("failed to unmarshal decrypted contact", plaintext, err)
+	}
+	return contacts, nil
+}
+
+// NeedsEncryption returns true if a non-empty list read from the contact
+// column is either plaintext or encrypted with a key other than the active key.
+func (cc *ContactCipher) NeedsEncryption(stored []string) bool {
+	if len(stored) == 0 {
+		return false
+	}
+	keyID, _, err := parseEncryptedContact(stored)
+	return err == nil && keyID != cc.activeKeyID
+}
+
+// parseEncryptedContact splits an encrypted contact list into its key ID and
+// ciphertext. If the list isn't encrypted, an empty key ID is returned.
+func parseEncryptedContact(stored []string) (string, []byte, error) {
+	if len(stored) != 1 {
+		return "", nil, nil
+	}
+	keyID, ciphertext, err := parseSealed(stored[0])
+	if err != nil {
+		return "", nil, fmt.Errorf("malformed encrypted contact: %s", err)
+	}
+	return keyID, ciphertext, nil
+}
+
+// parseSealed splits a value produced by seal into its key ID and ciphertext.
+// If the value isn't encrypted, an empty key ID is returned.
+func parseSealed(stored string) (string, []byte, error) {
+	if !strings.HasPrefix(stored, encryptedContactPrefix) {
+		return "", nil, nil
+	}
+	fields := strings.SplitN(strings.TrimPrefix(stored, encryptedContactPrefix), ":", 2)
+	if len(fields) != 2 || fields[0] == "" {
+		return "", nil, errors.New("missing key ID")
+	}
+	ciphertext, err := base64.RawURLEncoding.DecodeString(fields[1])
+	if err != nil {
+		return "", nil, err
+	}
+	return fields[0], ciphertext, nil
+}
+
+// encryptContacts returns the list which should be written to the contact
+// column for the given plaintext contacts. If cc is nil contacts are stored
+// in plaintext.
+func encryptContacts(cc *ContactCipher, contacts []string) ([]string, error) {
+	if cc == nil {
+		return contacts, nil
+	}
+	return cc.Encrypt(contacts)
+}
+
+// DecryptContacts returns the plaintext contacts for a list read from the
+// contact column. If cc is nil, encrypted contacts result in an error. Tools
+// which read the registrations table directly must use it, since the column
+// may hold ciphertext.
+func DecryptContacts(cc *ContactCipher, stored []string) ([]string, error) {
+	if cc == nil {
+		keyID, _, err := parseEncryptedContact(stored)
+		if err != nil {
+			return nil, err
+		}
+		if keyID != "" {
+			return nil, fmt.Errorf("contact is encrypted with key %q but contact encryption is not configured", keyID)
+		}
+		return stored, nil
+	}
+	return cc.Decrypt(stored)
+}
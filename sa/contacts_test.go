@@ -0,0 +1,112 @@
+package sa
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/letsencrypt/boulder/test"
+)
+
+func TestNewContactCipher(t *testing.T) {
+	key := bytes.Repeat([]byte{1}, 32)
+
+	_, err := NewContactCipher(nil, "a")
+	test.AssertError(t, err, "NewContactCipher didn't fail with no keys")
+
+	_, err = NewContactCipher(map[string][]byte{"a": key}, "b")
+	test.AssertError(t, err, "NewContactCipher didn't fail with a missing active key")
+
+	_, err = NewContactCipher(map[string][]byte{"a": key[:16]}, "a")
+	test.AssertError(t, err, "NewContactCipher didn't fail with a short key")
+
+	_, err = NewContactCipher(map[string][]byte{"a:b": key}, "a:b")
+	test.AssertError(t, err, "NewContactCipher didn't fail with an invalid key ID")
+
+	_, err = NewContactCipher(map[string][]byte{"a": key}, "a")
+	test.AssertNotError(t, err, "NewContactCipher failed")
+}
+
+func TestContactCipherRoundTrip(t *testing.T) {
+	oldKey := bytes.Repeat([]byte{1}, 32)
+	newKey := bytes.Repeat([]byte{2}, 32)
+	contacts := []string{"mailto:one@example.com", "mailto:two@example.com"}
+
+	oldCipher, err := NewContactCipher(map[string][]byte{"old": oldKey}, "old")
+	test.AssertNotError(t, err, "NewContactCipher failed")
+
+	test.Assert(t, oldCipher.NeedsEncryption(contacts), "plaintext contacts should need encryption")
+	test.Assert(t, !oldCipher.NeedsEncryption([]string{}), "empty contacts shouldn't need encryption")
+
+	encrypted, err := oldCipher.Encrypt(contacts)
+	test.AssertNotError(t, err, "Encrypt failed")
+	test.AssertEquals(t, len(encrypted), 1)
+	test.Assert(t, strings.HasPrefix(encrypted[0], "enc1:old:"), "unexpected encrypted contact format")
+	test.Assert(t, !oldCipher.NeedsEncryption(encrypted), "contacts encrypted with the active key shouldn't need encryption")
+
+	decrypted, err := oldCipher.Decrypt(encrypted)
+	test.AssertNotError(t, err, "Decrypt failed")
+	test.AssertDeepEquals(t, decrypted, contacts)
+
+	// Plaintext contacts are returned unchanged.
+	decrypted, err = oldCipher.Decrypt(contacts)
+	test.AssertNotError(t, err, "Decrypt failed for plaintext contacts")
+	test.AssertDeepEquals(t, decrypted, contacts)
+
+	// After rotation, contacts encrypted with the old key can still be
+	// decrypted, but need re-encrypting.
+	newCipher, err := NewContactCipher(map[string][]byte{"old": oldKey, "new": newKey}, "new")
+	test.AssertNotError(t, err, "NewContactCipher failed")
+	test.Assert(t, newCipher.NeedsEncryption(encrypted), "contacts encrypted with an old key should need encryption")
+	decrypted, err = newCipher.Decrypt(encrypted)
+	test.AssertNotError(t, err, "Decrypt failed after rotation")
+	test.AssertDeepEquals(t, decrypted, contacts)
+
+	// A ciphertext can't be relabelled with a different key ID.
+	relabelled := []string{strings.Replace(encrypted[0], "enc1:old:", "enc1:new:", 1)}
+	_, err = newCipher.Decrypt(relabelled)
+	test.AssertError(t, err, "Decrypt didn't fail for relabelled ciphertext")
+
+	// Without the old key, decryption fails.
+	_, err = newCipher.Decrypt([]string{"enc1:unknown:AAAA"})
+	test.AssertError(t, err, "Decrypt didn't fail for an unknown key")
+	_, err = newCipher.Decrypt([]string{"enc1:new:!!!"})
+	test.AssertError(t, err, "Decrypt didn't fail for malformed ciphertext")
+
+	// Encrypted contacts can't be read without a cipher.
+	_, err = DecryptContacts(nil, encrypted)
+	test.AssertError(t, err, "DecryptContacts didn't fail without a cipher")
+	decrypted, err = DecryptContacts(nil, contacts)
+	test.AssertNotError(t, err, "DecryptContacts failed for plaintext contacts")
+	test.AssertDeepEquals(t, decrypted, contacts)
+}
+
+func TestWebhookSecretEncryption(t *testing.T) {
+	cc, err := NewContactCipher(map[string][]byte{"a": bytes.Repeat([]byte{1}, 32)}, "a")
+	test.AssertNotError(t, err, "NewContactCipher failed")
+	secret := []byte("0123456789abcdef")
+
+	stored, err := encryptWebhookSecret(cc, secret)
+	test.AssertNotError(t, err, "encryptWebhookSecret failed")
+	test.Assert(t, bytes.HasPrefix(stored, []byte("enc1:a:")), "unexpected encrypted secret format")
+	test.Assert(t, len(stored) <= 255, "encrypted secret doesn't fit in the webhookSecret column")
+	decrypted, err := decryptWebhookSecret(cc, stored)
+	test.AssertNotError(t, err, "decryptWebhookSecret failed")
+	test.AssertByteEquals(t, decrypted, secret)
+
+	// Plaintext secrets are returned unchanged.
+	decrypted, err = decryptWebhookSecret(cc, secret)
+	test.AssertNotError(t, err, "decryptWebhookSecret failed for a plaintext secret")
+	test.AssertByteEquals(t, decrypted, secret)
+
+	// An encrypted secret can't be read as a contact, or vice versa.
+	_, err = cc.Decrypt([]string{string(stored)})
+	test.AssertError(t, err, "decrypted a webhook secret as a contact")
+	contacts, err := cc.Encrypt([]string{"mailto:one@example.com"})
+	test.AssertNotError(t, err, "Encrypt failed")
+	_, err = decryptWebhookSecret(cc, []byte(contacts[0]))
+	test.AssertError(t, err, "decrypted a contact as a webhook secret")
+
+	_, err = decryptWebhookSecret(nil, stored)
+	test.AssertError(t, err, "decrypted a webhook secret without a cipher")
+}
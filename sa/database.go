@@ -196,4 +196,13 @@ func initTables(dbMap *gorp.DbMap) {
 	dbMap.AddTableWithName(recordedSerialModel{}, "serials").SetKeys(true, "ID")
 	dbMap.AddTableWithName(precertificateModel{}, "precertificates").SetKeys(true, "ID")
 	dbMap.AddTableWithName(keyHashModel{}, "keyHashToSerial").SetKeys(true, "ID")
+	dbMap.AddTableWithName(incidentModel{}, "incidents").SetKeys(true, "ID")
+	dbMap.AddTableWithName(notificationPreferencesModel{}, "notificationPreferences").SetKeys(false, "RegistrationID")
+	dbMap.AddTableWithName(featureOverrideModel{}, "accountFeatureOverrides").SetKeys(false, "RegistrationID", "Feature")
+	dbMap.AddTableWithName(hostnamePolicyModel{}, "hostnamePolicies").SetKeys(true, "Version")
+	dbMap.AddTableWithName(accountAllowlistModel{}, "accountAllowlists").SetKeys(false, "RegistrationID", "Domain")
+	dbMap.AddTableWithName(certificateMetadataModel{}, "certificateMetadata").SetKeys(true, "ID")
+	dbMap.AddTableWithName(accountEventModel{}, "accountEvents").SetKeys(true, "ID")
+	dbMap.AddTableWithName(keyRolloverModel{}, "keyRollovers").SetKeys(true, "ID")
+	dbMap.AddTableWithName(issuanceLogModel{}, "issuanceLog").SetKeys(false, "ChainID", "Seq")
 }
@@ -0,0 +1,110 @@
+package sa
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	corepb "github.com/letsencrypt/boulder/core/proto"
+	berrors "github.com/letsencrypt/boulder/errors"
+	"github.com/letsencrypt/boulder/features"
+	sapb "github.com/letsencrypt/boulder/sa/proto"
+)
+
+// featureOverrideModel represents a row in the accountFeatureOverrides table,
+// which enables or disables a feature for a single account regardless of the
+// feature's process-wide setting.
+type featureOverrideModel struct {
+	RegistrationID int64     `db:"registrationID"`
+	Feature        string    `db:"feature"`
+	Enabled        bool      `db:"enabled"`
+	Updated        time.Time `db:"updated"`
+}
+
+// GetFeatureOverrides returns the feature overrides for the given
+// registration, which may be none.
+func (ssa *SQLStorageAuthority) GetFeatureOverrides(ctx context.Context, req *sapb.RegistrationID) (*sapb.FeatureOverrides, error) {
+	if req == nil || req.Id == 0 {
+		return nil, errIncompleteRequest
+	}
+	var models []featureOverrideModel
+	_, err := ssa.dbMap.WithContext(ctx).Select(
+		&models,
+		`SELECT registrationID, feature, enabled, updated
+		FROM accountFeatureOverrides
+		WHERE registrationID = ?
+		ORDER BY feature`,
+		req.Id,
+	)
+	if err != nil {
+		return nil, err
+	}
+	overrides := &sapb.FeatureOverrides{}
+	for _, model := range models {
+		overrides.Overrides = append(overrides.Overrides, &sapb.FeatureOverride{
+			Feature: model.Feature,
+			Enabled: model.Enabled,
+		})
+	}
+	return overrides, nil
+}
+
+// SetFeatureOverride creates or replaces a registration's override for a
+// feature, which must be one that honours per-account overrides.
+func (ssa *SQLStorageAuthority) SetFeatureOverride(ctx context.Context, req *sapb.FeatureOverrideRequest) (*corepb.Empty, error) {
+	if req == nil || req.RegistrationID == 0 || req.Feature == "" {
+		return nil, errIncompleteRequest
+	}
+	if !features.Exists(req.Feature) {
+		return nil, berrors.MalformedError("feature %q doesn't exist", req.Feature)
+	}
+	if !features.Overridable(req.Feature) {
+		return nil, berrors.MalformedError("feature %q doesn't honour per-account overrides", req.Feature)
+	}
+	err := ssa.retryWrite(ctx, func() error {
+		_, err := ssa.dbMap.WithContext(ctx).Exec(
+			`INSERT INTO accountFeatureOverrides (registrationID, feature, enabled, updated)
+			VALUES (?, ?, ?, ?)
+			ON DUPLICATE KEY UPDATE enabled = VALUES(enabled), updated = VALUES(updated)`,
+			req.RegistrationID,
+			req.Feature,
+			req.Enabled,
+			ssa.clk.Now(),
+		)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &corepb.Empty{}, nil
+}
+
+// ClearFeatureOverride removes a registration's override for a feature, so
+// that the feature's process-wide setting applies to it again. It returns a
+// berrors.NotFound error if there was no override.
+func (ssa *SQLStorageAuthority) ClearFeatureOverride(ctx context.Context, req *sapb.FeatureOverrideRequest) (*corepb.Empty, error) {
+	if req == nil || req.RegistrationID == 0 || req.Feature == "" {
+		return nil, errIncompleteRequest
+	}
+	var result sql.Result
+	err := ssa.retryWrite(ctx, func() error {
+		var err error
+		result, err = ssa.dbMap.WithContext(ctx).Exec(
+			`DELETE FROM accountFeatureOverrides WHERE registrationID = ? AND feature = ?`,
+			req.RegistrationID,
+			req.Feature,
+		)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if rows == 0 {
+		return nil, berrors.NotFoundError("registration %d has no override for feature %q", req.RegistrationID, req.Feature)
+	}
+	return &corepb.Empty{}, nil
+}
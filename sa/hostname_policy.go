@@ -0,0 +1,133 @@
+package sa
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/letsencrypt/boulder/db"
+	berrors "github.com/letsencrypt/boulder/errors"
+	"github.com/letsencrypt/boulder/policy"
+	sapb "github.com/letsencrypt/boulder/sa/proto"
+)
+
+// maxHostnamePolicyCommentLength is the width of the hostnamePolicies comment
+// and createdBy columns.
+const maxHostnamePolicyCommentLength = 255
+
+// hostnamePolicyModel represents a row in the hostnamePolicies table. Each row
+// is a complete version of the PA's hostname policy, which replaces the
+// previous one. Policy holds the policy's lists of names as JSON.
+type hostnamePolicyModel struct {
+	Version   int64     `db:"version"`
+	Policy    []byte    `db:"policy"`
+	Created   time.Time `db:"created"`
+	CreatedBy string    `db:"createdBy"`
+	Comment   string    `db:"comment"`
+}
+
+// hostnamePolicyNames is the form of the lists of names stored in the policy
+// column of the hostnamePolicies table.
+type hostnamePolicyNames struct {
+	ExactBlockedNames    []string
+	HighRiskBlockedNames []string
+	AdminBlockedNames    []string
+}
+
+// GetHostnamePolicy returns the latest version of the hostname policy, or a
+// berrors.NotFound error if none has been stored. If the latest version is
+// req.KnownVersion, the names in it are omitted.
+func (ssa *SQLStorageAuthority) GetHostnamePolicy(ctx context.Context, req *sapb.GetHostnamePolicyRequest) (*sapb.HostnamePolicy, error) {
+	if req == nil {
+		return nil, errIncompleteRequest
+	}
+	var latest int64
+	err := ssa.dbMap.WithContext(ctx).SelectOne(
+		&latest,
+		`SELECT COALESCE(MAX(version), 0) FROM hostnamePolicies`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if latest == 0 {
+		return nil, berrors.NotFoundError("no hostname policy has been stored")
+	}
+	if latest == req.KnownVersion {
+		return &sapb.HostnamePolicy{Version: latest}, nil
+	}
+
+	var model hostnamePolicyModel
+	err = ssa.dbMap.WithContext(ctx).SelectOne(
+		&model,
+		`SELECT version, policy, created, createdBy, comment
+		FROM hostnamePolicies
+		WHERE version = ?`,
+		latest,
+	)
+	if err != nil {
+		if db.IsNoRows(err) {
+			return nil, berrors.NotFoundError("hostname policy version %d not found", latest)
+		}
+		return nil, err
+	}
+	var names hostnamePolicyNames
+	err = json.Unmarshal(model.Policy, &names)
+	if err != nil {
+		return nil, err
+	}
+	return &sapb.HostnamePolicy{
+		Version:              model.Version,
+		ExactBlockedNames:    names.ExactBlockedNames,
+		HighRiskBlockedNames: names.HighRiskBlockedNames,
+		AdminBlockedNames:    names.AdminBlockedNames,
+		Created:              model.Created.UnixNano(),
+		CreatedBy:            model.CreatedBy,
+		Comment:              model.Comment,
+	}, nil
+}
+
+// AddHostnamePolicy stores a new version of the hostname policy, which the
+// PAs load in place of the previous one, and returns it with its version. The
+// policy must be one the PA would accept. Previous versions are kept, so that
+// a change can be reverted by adding an earlier version again.
+func (ssa *SQLStorageAuthority) AddHostnamePolicy(ctx context.Context, req *sapb.HostnamePolicy) (*sapb.HostnamePolicy, error) {
+	if req == nil || req.CreatedBy == "" {
+		return nil, errIncompleteRequest
+	}
+	err := policy.ValidateHostnamePolicy(req)
+	if err != nil {
+		return nil, berrors.MalformedError("invalid hostname policy: %s", err)
+	}
+	if len(req.CreatedBy) > maxHostnamePolicyCommentLength || len(req.Comment) > maxHostnamePolicyCommentLength {
+		return nil, berrors.MalformedError("createdBy and comment must be at most %d characters", maxHostnamePolicyCommentLength)
+	}
+	names, err := json.Marshal(hostnamePolicyNames{
+		ExactBlockedNames:    req.ExactBlockedNames,
+		HighRiskBlockedNames: req.HighRiskBlockedNames,
+		AdminBlockedNames:    req.AdminBlockedNames,
+	})
+	if err != nil {
+		return nil, err
+	}
+	model := &hostnamePolicyModel{
+		Policy:    names,
+		Created:   ssa.clk.Now(),
+		CreatedBy: req.CreatedBy,
+		Comment:   req.Comment,
+	}
+	err = ssa.retryWrite(ctx, func() error {
+		return ssa.dbMap.WithContext(ctx).Insert(model)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &sapb.HostnamePolicy{
+		Version:              model.Version,
+		ExactBlockedNames:    req.ExactBlockedNames,
+		HighRiskBlockedNames: req.HighRiskBlockedNames,
+		AdminBlockedNames:    req.AdminBlockedNames,
+		Created:              model.Created.UnixNano(),
+		CreatedBy:            model.CreatedBy,
+		Comment:              model.Comment,
+	}, nil
+}
@@ -0,0 +1,180 @@
+package sa
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/letsencrypt/boulder/core"
+	corepb "github.com/letsencrypt/boulder/core/proto"
+	"github.com/letsencrypt/boulder/db"
+	berrors "github.com/letsencrypt/boulder/errors"
+	sapb "github.com/letsencrypt/boulder/sa/proto"
+)
+
+// incidentModel represents a row in the incidents table. An incident groups
+// together a set of certificate serials (stored in the incidentSerials table)
+// which were affected by the same problem, along with the URL describing the
+// problem and the time by which subscribers should have renewed.
+type incidentModel struct {
+	ID       int64     `db:"id"`
+	Name     string    `db:"name"`
+	URL      string    `db:"url"`
+	RenewBy  time.Time `db:"renewBy"`
+	Enabled  bool      `db:"enabled"`
+	Resolved bool      `db:"resolved"`
+	Created  time.Time `db:"created"`
+}
+
+const incidentFields = "id, name, url, renewBy, enabled, resolved, created"
+
+func incidentModelToPB(im incidentModel) *sapb.Incident {
+	return &sapb.Incident{
+		Id:       im.ID,
+		Name:     im.Name,
+		Url:      im.URL,
+		RenewBy:  im.RenewBy.UnixNano(),
+		Enabled:  im.Enabled,
+		Resolved: im.Resolved,
+		Created:  im.Created.UnixNano(),
+	}
+}
+
+// AddIncident creates a new, disabled incident. Serials affected by the
+// incident can then be imported with AddIncidentSerials before the incident is
+// enabled with SetIncidentStatus.
+func (ssa *SQLStorageAuthority) AddIncident(ctx context.Context, req *sapb.AddIncidentRequest) (*sapb.Incident, error) {
+	if core.IsAnyNilOrZero(req.Name, req.Url, req.RenewBy) {
+		return nil, errIncompleteRequest
+	}
+	im := &incidentModel{
+		Name:    req.Name,
+		URL:     req.Url,
+		RenewBy: time.Unix(0, req.RenewBy),
+		Created: ssa.clk.Now(),
+	}
+	err := ssa.retryWrite(ctx, func() error {
+		return ssa.dbMap.WithContext(ctx).Insert(im)
+	})
+	if err != nil {
+		if db.IsDuplicate(err) {
+			return nil, berrors.DuplicateError("incident %q already exists", req.Name)
+		}
+		return nil, err
+	}
+	return incidentModelToPB(*im), nil
+}
+
+// getIncident returns the incident with the given ID, or a berrors.NotFound
+// error if there is no such incident.
+func getIncident(s db.OneSelector, id int64) (*incidentModel, error) {
+	var im incidentModel
+	err := s.SelectOne(
+		&im,
+		fmt.Sprintf("SELECT %s FROM incidents WHERE id = ?", incidentFields),
+		id,
+	)
+	if err != nil {
+		if db.IsNoRows(err) {
+			return nil, berrors.NotFoundError("incident %d not found", id)
+		}
+		return nil, err
+	}
+	return &im, nil
+}
+
+// AddIncidentSerials records that the given serials are affected by an
+// incident. Serials which are already associated with the incident are
+// ignored, so that an interrupted import can safely be restarted from the
+// beginning. Serials can't be added to an incident which has been resolved.
+func (ssa *SQLStorageAuthority) AddIncidentSerials(ctx context.Context, req *sapb.AddIncidentSerialsRequest) (*corepb.Empty, error) {
+	if core.IsAnyNilOrZero(req.IncidentID, req.Serials) {
+		return nil, errIncompleteRequest
+	}
+	_, overallError := ssa.withTransaction(ctx, func(txWithCtx db.Executor) (interface{}, error) {
+		im, err := getIncident(txWithCtx, req.IncidentID)
+		if err != nil {
+			return nil, err
+		}
+		if im.Resolved {
+			return nil, berrors.MalformedError("incident %d is already resolved", req.IncidentID)
+		}
+
+		inserter, err := db.NewMultiInserter("incidentSerials", []string{"incidentID", "serial", "registrationID"}, "", ssa.maxInsertBatchSize)
+		if err != nil {
+			return nil, err
+		}
+		inserter.IgnoreDuplicates()
+		for _, is := range req.Serials {
+			if is.Serial == "" {
+				return nil, errIncompleteRequest
+			}
+			var regID interface{}
+			if is.RegistrationID != 0 {
+				regID = is.RegistrationID
+			}
+			if err := inserter.Add([]interface{}{req.IncidentID, is.Serial, regID}); err != nil {
+				return nil, err
+			}
+		}
+		_, err = inserter.Insert(txWithCtx)
+		return nil, err
+	})
+	if overallError != nil {
+		return nil, overallError
+	}
+	return &corepb.Empty{}, nil
+}
+
+// SetIncidentStatus updates the enabled and resolved flags of an incident.
+// Only enabled, unresolved incidents are returned by IncidentsForSerial.
+func (ssa *SQLStorageAuthority) SetIncidentStatus(ctx context.Context, req *sapb.SetIncidentStatusRequest) (*corepb.Empty, error) {
+	if req.IncidentID == 0 {
+		return nil, errIncompleteRequest
+	}
+	_, overallError := ssa.withTransaction(ctx, func(txWithCtx db.Executor) (interface{}, error) {
+		// Check that the incident exists first, since an UPDATE which doesn't
+		// change any values reports zero affected rows.
+		_, err := getIncident(txWithCtx, req.IncidentID)
+		if err != nil {
+			return nil, err
+		}
+		_, err = txWithCtx.Exec(
+			"UPDATE incidents SET enabled = ?, resolved = ? WHERE id = ?",
+			req.Enabled,
+			req.Resolved,
+			req.IncidentID,
+		)
+		return nil, err
+	})
+	if overallError != nil {
+		return nil, overallError
+	}
+	return &corepb.Empty{}, nil
+}
+
+// IncidentsForSerial returns every enabled, unresolved incident which affects
+// the certificate with the given serial. If the serial isn't affected by any
+// such incident, an empty list is returned.
+func (ssa *SQLStorageAuthority) IncidentsForSerial(ctx context.Context, req *sapb.Serial) (*sapb.Incidents, error) {
+	if req == nil || req.Serial == "" {
+		return nil, errIncompleteRequest
+	}
+	var incidentModels []incidentModel
+	_, err := ssa.dbMap.WithContext(ctx).Select(
+		&incidentModels,
+		`SELECT i.id, i.name, i.url, i.renewBy, i.enabled, i.resolved, i.created
+		FROM incidentSerials AS s
+		JOIN incidents AS i ON i.id = s.incidentID
+		WHERE s.serial = ? AND i.enabled = true AND i.resolved = false`,
+		req.Serial,
+	)
+	if err != nil {
+		return nil, err
+	}
+	resp := &sapb.Incidents{}
+	for _, im := range incidentModels {
+		resp.Incidents = append(resp.Incidents, incidentModelToPB(im))
+	}
+	return resp, nil
+}
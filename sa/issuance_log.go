@@ -0,0 +1,89 @@
+package sa
+
+import (
+	"context"
+	"time"
+
+	corepb "github.com/letsencrypt/boulder/core/proto"
+	"github.com/letsencrypt/boulder/db"
+	berrors "github.com/letsencrypt/boulder/errors"
+	sapb "github.com/letsencrypt/boulder/sa/proto"
+)
+
+// issuanceLogModel represents a row in the issuanceLog table, which holds
+// copies of the entries of the CAs' hash-chained issuance logs.
+type issuanceLogModel struct {
+	ChainID                string    `db:"chainID"`
+	Seq                    int64     `db:"seq"`
+	EntryType              string    `db:"entryType"`
+	Serial                 string    `db:"serial"`
+	CertificateProfileName string    `db:"certificateProfileName"`
+	NamesHash              []byte    `db:"namesHash"`
+	CSRHash                []byte    `db:"csrHash"`
+	Issued                 time.Time `db:"issued"`
+	PrevHash               []byte    `db:"prevHash"`
+	Hash                   []byte    `db:"hash"`
+}
+
+// AddIssuanceLogEntry stores a copy of an entry of a CA's issuance log. The
+// entry's hashes aren't checked, since the issuance log verifier compares the
+// copies with the log itself. Storing an entry again has no effect.
+func (ssa *SQLStorageAuthority) AddIssuanceLogEntry(ctx context.Context, req *sapb.IssuanceLogEntry) (*corepb.Empty, error) {
+	if req == nil || req.ChainID == "" || req.Seq == 0 || req.Type == "" || req.Serial == "" ||
+		len(req.NamesHash) == 0 || req.Issued == 0 || len(req.PrevHash) == 0 || len(req.Hash) == 0 {
+		return nil, errIncompleteRequest
+	}
+	err := ssa.retryWrite(ctx, func() error {
+		return ssa.dbMap.WithContext(ctx).Insert(&issuanceLogModel{
+			ChainID:                req.ChainID,
+			Seq:                    req.Seq,
+			EntryType:              req.Type,
+			Serial:                 req.Serial,
+			CertificateProfileName: req.CertificateProfileName,
+			NamesHash:              req.NamesHash,
+			CSRHash:                req.CsrHash,
+			Issued:                 time.Unix(0, req.Issued),
+			PrevHash:               req.PrevHash,
+			Hash:                   req.Hash,
+		})
+	})
+	if err != nil && !db.IsDuplicate(err) {
+		return nil, err
+	}
+	return &corepb.Empty{}, nil
+}
+
+// GetIssuanceLogEntry returns the stored copy of an entry of a CA's issuance
+// log.
+func (ssa *SQLStorageAuthority) GetIssuanceLogEntry(ctx context.Context, req *sapb.GetIssuanceLogEntryRequest) (*sapb.IssuanceLogEntry, error) {
+	if req == nil || req.ChainID == "" || req.Seq == 0 {
+		return nil, errIncompleteRequest
+	}
+	var m issuanceLogModel
+	err := ssa.dbMap.WithContext(ctx).SelectOne(
+		&m,
+		`SELECT chainID, seq, entryType, serial, certificateProfileName, namesHash, csrHash, issued, prevHash, hash
+		FROM issuanceLog
+		WHERE chainID = ? AND seq = ?`,
+		req.ChainID,
+		req.Seq,
+	)
+	if err != nil {
+		if db.IsNoRows(err) {
+			return nil, berrors.NotFoundError("no entry %d in issuance log chain %q", req.Seq, req.ChainID)
+		}
+		return nil, err
+	}
+	return &sapb.IssuanceLogEntry{
+		ChainID:                m.ChainID,
+		Seq:                    m.Seq,
+		Type:                   m.EntryType,
+		Serial:                 m.Serial,
+		CertificateProfileName: m.CertificateProfileName,
+		NamesHash:              m.NamesHash,
+		CsrHash:                m.CSRHash,
+		Issued:                 m.Issued.UnixNano(),
+		PrevHash:               m.PrevHash,
+		Hash:                   m.Hash,
+	}, nil
+}
@@ -0,0 +1,87 @@
+package sa
+
+import (
+	"context"
+	"encoding/base64"
+	"time"
+
+	"github.com/letsencrypt/boulder/db"
+	sapb "github.com/letsencrypt/boulder/sa/proto"
+)
+
+// keyRolloverModel represents a row in the keyRollovers table, which holds
+// the history of accounts' key rollovers.
+type keyRolloverModel struct {
+	ID             int64     `db:"id"`
+	RegistrationID int64     `db:"registrationID"`
+	OldKeyHash     []byte    `db:"oldKeyHash"`
+	NewKeyHash     []byte    `db:"newKeyHash"`
+	RolledOver     time.Time `db:"rolledOver"`
+}
+
+// addKeyRollover records that a registration's key has changed from one
+// whose jwk_sha256 column was oldKeySHA256 to one whose is newKeySHA256.
+// Nothing is recorded if the key hasn't changed.
+func addKeyRollover(tx db.Inserter, regID int64, oldKeySHA256, newKeySHA256 string, rolledOver time.Time) error {
+	if oldKeySHA256 == newKeySHA256 {
+		return nil
+	}
+	oldKeyHash, err := base64.StdEncoding.DecodeString(oldKeySHA256)
+	if err != nil {
+		return err
+	}
+	newKeyHash, err := base64.StdEncoding.DecodeString(newKeySHA256)
+	if err != nil {
+		return err
+	}
+	return tx.Insert(&keyRolloverModel{
+		RegistrationID: regID,
+		OldKeyHash:     oldKeyHash,
+		NewKeyHash:     newKeyHash,
+		RolledOver:     rolledOver,
+	})
+}
+
+// GetKeyRollovers returns the key rollovers of either a registration or a
+// key, newest first. A key's rollovers are those in which it was either the
+// old or the new key.
+func (ssa *SQLStorageAuthority) GetKeyRollovers(ctx context.Context, req *sapb.GetKeyRolloversRequest) (*sapb.KeyRollovers, error) {
+	if req == nil || (req.RegistrationID == 0) == (len(req.KeyHash) == 0) {
+		return nil, errIncompleteRequest
+	}
+	query := `SELECT id, registrationID, oldKeyHash, newKeyHash, rolledOver
+		FROM keyRollovers
+		WHERE `
+	args := map[string]interface{}{
+		"since": time.Unix(0, req.Since),
+	}
+	if req.RegistrationID != 0 {
+		query += `registrationID = :regID AND rolledOver >= :since`
+		args["regID"] = req.RegistrationID
+	} else {
+		// Each of the key hash indexes can only be used by one side of the
+		// union.
+		query += `oldKeyHash = :keyHash AND rolledOver >= :since
+		UNION SELECT id, registrationID, oldKeyHash, newKeyHash, rolledOver
+		FROM keyRollovers
+		WHERE newKeyHash = :keyHash AND rolledOver >= :since`
+		args["keyHash"] = req.KeyHash
+	}
+	query += ` ORDER BY rolledOver DESC, id DESC`
+
+	var models []keyRolloverModel
+	_, err := ssa.dbMap.WithContext(ctx).Select(&models, query, args)
+	if err != nil {
+		return nil, err
+	}
+	rollovers := make([]*sapb.KeyRollover, 0, len(models))
+	for _, m := range models {
+		rollovers = append(rollovers, &sapb.KeyRollover{
+			RegistrationID: m.RegistrationID,
+			OldKeyHash:     m.OldKeyHash,
+			NewKeyHash:     m.NewKeyHash,
+			RolledOver:     m.RolledOver.UnixNano(),
+		})
+	}
+	return &sapb.KeyRollovers{Rollovers: rollovers}, nil
+}
@@ -60,6 +60,11 @@ var (
 		"db_max_lifetime_closed",
 		"Total number of connections closed due to SetConnMaxLifetime.",
 		nil, nil)
+
+	maxIdleTimeClosed = prometheus.NewDesc(
+		"db_max_idle_time_closed",
+		"Total number of connections closed due to SetConnMaxIdleTime.",
+		nil, nil)
 )
 
 type dbMetricsCollector struct {
@@ -103,6 +108,7 @@ func (dbc dbMetricsCollector) Collect(ch chan<- prometheus.Metric) {
 	writeCounter(waitDuration, dbMapStats.WaitDuration.Seconds())
 	writeCounter(maxIdleClosed, float64(dbMapStats.MaxIdleClosed))
 	writeCounter(maxLifetimeClosed, float64(dbMapStats.MaxLifetimeClosed))
+	writeCounter(maxIdleTimeClosed, float64(dbMapStats.MaxIdleTimeClosed))
 }
 
 // InitDBMetrics will register a Collector that translates the provided dbMap's
@@ -0,0 +1,413 @@
+package sa
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jmhodges/clock"
+
+	blog "github.com/letsencrypt/boulder/log"
+)
+
+// ExpectedSchemaVersion is the version of the newest migration in
+// sa/_db/migrations, which the SA requires the database to have been migrated
+// to. It must be updated whenever a migration is added.
+const ExpectedSchemaVersion int64 = 20210217120000
+
+// schemaSource is the Source of the migrations in sa/_db, whose version
+// CheckSchemaVersion checks. Those in sa/_db-next, which are only applied to
+// test databases, are recorded separately.
+const schemaSource = "_db"
+
+// createSchemaMigrations creates the table recording the migrations which
+// have been applied, with the checksums of their files when they were.
+const createSchemaMigrations = "CREATE TABLE IF NOT EXISTS `schemaMigrations` (" +
+	"`source` varchar(255) NOT NULL, " +
+	"`version` bigint(20) NOT NULL, " +
+	"`name` varchar(255) NOT NULL, " +
+	"`checksum` char(64) NOT NULL, " +
+	"`applied` datetime NOT NULL, " +
+	"PRIMARY KEY (`source`, `version`)" +
+	") ENGINE=InnoDB DEFAULT CHARSET=utf8"
+
+// Migration is one of the SA's versioned schema migrations, read from a file
+// in goose's format.
+type Migration struct {
+	// Source is the name of the directory the migration was read from, such
+	// as _db or _db-next. The migrations from each are recorded separately.
+	Source  string
+	Version int64
+	// Name is the migration's file name.
+	Name string
+	// Checksum is the hex SHA-256 hash of the migration's file.
+	Checksum string
+	// Statements are the statements of the migration's Up section.
+	Statements []string
+}
+
+// Transactional returns whether the migration can be applied in a
+// transaction. MariaDB implicitly commits the current transaction before and
+// after DDL statements, so migrations containing them can't be.
+func (m Migration) Transactional() bool {
+	for _, stmt := range m.Statements {
+		fields := strings.Fields(stmt)
+		switch strings.ToUpper(fields[0]) {
+		case "CREATE", "ALTER", "DROP", "RENAME", "TRUNCATE":
+			return false
+		}
+	}
+	return true
+}
+
+// LoadMigrations reads the migrations in the migrations directory of dir,
+// which is laid out as goose expects (e.g. sa/_db), ordered by version. Their
+// Source is the name of dir.
+func LoadMigrations(dir string) ([]Migration, error) {
+	source := filepath.Base(filepath.Clean(dir))
+	files, err := ioutil.ReadDir(filepath.Join(dir, "migrations"))
+	if err != nil {
+		return nil, err
+	}
+	var migrations []Migration
+	versions := make(map[int64]string)
+	for _, f := range files {
+		if f.IsDir() || filepath.Ext(f.Name()) != ".sql" {
+			continue
+		}
+		contents, err := ioutil.ReadFile(filepath.Join(dir, "migrations", f.Name()))
+		if err != nil {
+			return nil, err
+		}
+		m, err := parseMigration(f.Name(), contents)
+		if err != nil {
+			return nil, err
+		}
+		m.Source = source
+		if other, ok := versions[m.Version]; ok {
+			return nil, fmt.Errorf("migrations %s and %s have the same version", other, m.Name)
+		}
+		versions[m.Version] = m.Name
+		migrations = append(migrations, m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// parseMigration parses a migration file named <version>_<description>.sql.
+// As goose does, it splits the Up section into statements at lines ending in
+// a semicolon, except between StatementBegin and StatementEnd annotations,
+// and skips comment lines.
+func parseMigration(name string, contents []byte) (Migration, error) {
+	prefix := strings.SplitN(name, "_", 2)[0]
+	version, err := strconv.ParseInt(prefix, 10, 64)
+	if err != nil || version <= 0 {
+		return Migration{}, fmt.Errorf("migration %s: file name doesn't start with a version", name)
+	}
+	checksum := sha256.Sum256(contents)
+	m := Migration{
+		Version:  version,
+		Name:     name,
+		Checksum: hex.EncodeToString(checksum[:]),
+	}
+
+	var inUp, sawUp, inBlock bool
+	var stmt strings.Builder
+	for _, line := range strings.Split(string(contents), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "--") {
+			switch strings.TrimSpace(strings.TrimPrefix(trimmed, "--")) {
+			case "+goose Up":
+				inUp, sawUp = true, true
+			case "+goose Down":
+				inUp = false
+			case "+goose StatementBegin":
+				inBlock = true
+			case "+goose StatementEnd":
+				inBlock = false
+				if inUp && stmt.Len() != 0 {
+					m.Statements = append(m.Statements, strings.TrimSpace(stmt.String()))
+					stmt.Reset()
+				}
+			}
+			continue
+		}
+		if !inUp || (trimmed == "" && stmt.Len() == 0) {
+			continue
+		}
+		stmt.WriteString(line + "\n")
+		if !inBlock && strings.HasSuffix(trimmed, ";") {
+			m.Statements = append(m.Statements, strings.TrimSpace(stmt.String()))
+			stmt.Reset()
+		}
+	}
+	if !sawUp {
+		return Migration{}, fmt.Errorf("migration %s: no Up section", name)
+	}
+	if stmt.Len() != 0 || inBlock {
+		return Migration{}, fmt.Errorf("migration %s: unterminated statement in Up section", name)
+	}
+	if len(m.Statements) == 0 {
+		return Migration{}, fmt.Errorf("migration %s: no statements in Up section", name)
+	}
+	return m, nil
+}
+
+// MigrationPlan is the work needed to bring a database up to date with a set
+// of migrations.
+type MigrationPlan struct {
+	// Apply are the migrations to apply, in order.
+	Apply []Migration
+	// Adopt are migrations which were applied by goose before the SA's
+	// migration runner was used, and only need recording as applied.
+	Adopt []Migration
+}
+
+// appliedMigration is a row of the schemaMigrations table.
+type appliedMigration struct {
+	Version  int64
+	Name     string
+	Checksum string
+}
+
+// planMigrations works out which of the available migrations should be
+// applied to reach the target version, or all of them if to is zero. It
+// fails if any applied migration's file has changed since it was applied.
+// Applied migrations which aren't available are ignored.
+func planMigrations(available []Migration, applied map[int64]appliedMigration, gooseApplied map[int64]bool, to int64) (MigrationPlan, error) {
+	var plan MigrationPlan
+	foundTarget := to == 0
+	for _, m := range available {
+		if m.Version == to {
+			foundTarget = true
+		}
+		if record, ok := applied[m.Version]; ok {
+			if record.Checksum != m.Checksum {
+				return MigrationPlan{}, fmt.Errorf(
+					"migration %s has changed since it was applied: its checksum is %s, but was %s",
+					m.Name, m.Checksum, record.Checksum)
+			}
+			continue
+		}
+		if gooseApplied[m.Version] {
+			plan.Adopt = append(plan.Adopt, m)
+			continue
+		}
+		if to == 0 || m.Version <= to {
+			plan.Apply = append(plan.Apply, m)
+		}
+	}
+	if !foundTarget {
+		return MigrationPlan{}, fmt.Errorf("there's no migration with version %d", to)
+	}
+	return plan, nil
+}
+
+// Migrator applies the SA's migrations to a database, recording them in the
+// schemaMigrations table.
+type Migrator struct {
+	db  *sql.DB
+	clk clock.Clock
+	log blog.Logger
+}
+
+// NewMigrator returns a Migrator for a database. Its user must be able to
+// create and alter tables.
+func NewMigrator(db *sql.DB, clk clock.Clock, logger blog.Logger) *Migrator {
+	return &Migrator{db: db, clk: clk, log: logger}
+}
+
+// Plan verifies the checksums of the available migrations which have been
+// applied, and returns the plan for migrating the database to the target
+// version, or to the newest available migration if to is zero. The available
+// migrations must all have the same Source, as those returned by
+// LoadMigrations do. It doesn't change the database.
+func (m *Migrator) Plan(ctx context.Context, available []Migration, to int64) (MigrationPlan, error) {
+	if len(available) == 0 {
+		return planMigrations(nil, nil, nil, to)
+	}
+	applied := make(map[int64]appliedMigration)
+	exists, err := m.tableExists(ctx, "schemaMigrations")
+	if err != nil {
+		return MigrationPlan{}, err
+	}
+	if exists {
+		rows, err := m.db.QueryContext(ctx,
+			"SELECT version, name, checksum FROM schemaMigrations WHERE source = ?",
+			available[0].Source)
+		if err != nil {
+			return MigrationPlan{}, err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var a appliedMigration
+			err = rows.Scan(&a.Version, &a.Name, &a.Checksum)
+			if err != nil {
+				return MigrationPlan{}, err
+			}
+			applied[a.Version] = a
+		}
+		if err = rows.Err(); err != nil {
+			return MigrationPlan{}, err
+		}
+	}
+
+	gooseApplied, err := m.gooseApplied(ctx)
+	if err != nil {
+		return MigrationPlan{}, err
+	}
+	return planMigrations(available, applied, gooseApplied, to)
+}
+
+// gooseApplied returns the versions goose has recorded as applied, if it has
+// been used on the database.
+func (m *Migrator) gooseApplied(ctx context.Context) (map[int64]bool, error) {
+	versions := make(map[int64]bool)
+	exists, err := m.tableExists(ctx, "goose_db_version")
+	if err != nil || !exists {
+		return versions, err
+	}
+	// goose records each migration and rollback as a new row, so the latest
+	// row for a version says whether it's applied.
+	rows, err := m.db.QueryContext(ctx, "SELECT version_id, is_applied FROM goose_db_version ORDER BY id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var version int64
+		var isApplied bool
+		err = rows.Scan(&version, &isApplied)
+		if err != nil {
+			return nil, err
+		}
+		versions[version] = isApplied
+	}
+	return versions, rows.Err()
+}
+
+func (m *Migrator) tableExists(ctx context.Context, table string) (bool, error) {
+	var count int
+	err := m.db.QueryRowContext(ctx,
+		"SELECT COUNT(*) FROM information_schema.tables WHERE table_schema = DATABASE() AND table_name = ?",
+		table).Scan(&count)
+	return count != 0, err
+}
+
+// Apply carries out a plan returned by Plan. Each migration which can be is
+// applied in a transaction along with its record in schemaMigrations, so it
+// either applies completely or not at all. A migration containing DDL that
+// fails part way through must be repaired by hand before migrating again.
+func (m *Migrator) Apply(ctx context.Context, plan MigrationPlan) error {
+	// Migrations may change session variables, e.g. FOREIGN_KEY_CHECKS, so
+	// they're all applied on one connection.
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	_, err = conn.ExecContext(ctx, createSchemaMigrations)
+	if err != nil {
+		return fmt.Errorf("creating schemaMigrations table: %s", err)
+	}
+	for _, migration := range plan.Adopt {
+		err = m.record(ctx, conn, migration)
+		if err != nil {
+			return fmt.Errorf("recording migration %s applied by goose: %s", migration.Name, err)
+		}
+		m.log.Infof("Recorded migration %s, previously applied by goose", migration.Name)
+	}
+	for _, migration := range plan.Apply {
+		if migration.Transactional() {
+			err = m.applyInTransaction(ctx, conn, migration)
+		} else {
+			err = m.applyDirectly(ctx, conn, migration)
+		}
+		if err != nil {
+			return err
+		}
+		m.log.Infof("Applied migration %s", migration.Name)
+	}
+	return nil
+}
+
+func (m *Migrator) applyInTransaction(ctx context.Context, conn *sql.Conn, migration Migration) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	for _, stmt := range migration.Statements {
+		_, err = tx.ExecContext(ctx, stmt)
+		if err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("applying migration %s: %s", migration.Name, err)
+		}
+	}
+	err = m.record(ctx, tx, migration)
+	if err != nil {
+		_ = tx.Rollback()
+		return fmt.Errorf("recording migration %s: %s", migration.Name, err)
+	}
+	return tx.Commit()
+}
+
+func (m *Migrator) applyDirectly(ctx context.Context, conn *sql.Conn, migration Migration) error {
+	for i, stmt := range migration.Statements {
+		_, err := conn.ExecContext(ctx, stmt)
+		if err != nil {
+			if i == 0 {
+				return fmt.Errorf("applying migration %s: %s", migration.Name, err)
+			}
+			return fmt.Errorf(
+				"applying migration %s failed after %d of its %d statements, which must be repaired by hand: %s",
+				migration.Name, i, len(migration.Statements), err)
+		}
+	}
+	err := m.record(ctx, conn, migration)
+	if err != nil {
+		return fmt.Errorf("recording applied migration %s, which must be done by hand: %s", migration.Name, err)
+	}
+	return nil
+}
+
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+func (m *Migrator) record(ctx context.Context, e execer, migration Migration) error {
+	_, err := e.ExecContext(ctx,
+		"INSERT INTO schemaMigrations (source, version, name, checksum, applied) VALUES (?, ?, ?, ?, ?)",
+		migration.Source, migration.Version, migration.Name, migration.Checksum, m.clk.Now())
+	return err
+}
+
+// CheckSchemaVersion returns an error if the newest migration from sa/_db
+// recorded as applied to the database is older than the expected one, so that
+// an SA doesn't start against a database which is missing part of its schema.
+// Newer migrations are allowed, since the database is migrated before the SAs
+// expecting them are deployed.
+func CheckSchemaVersion(ctx context.Context, db *sql.DB, expected int64) error {
+	var version sql.NullInt64
+	err := db.QueryRowContext(ctx,
+		"SELECT MAX(version) FROM schemaMigrations WHERE source = ?",
+		schemaSource).Scan(&version)
+	if err != nil {
+		return fmt.Errorf("reading schema version: %s", err)
+	}
+	if !version.Valid {
+		return errors.New("no migrations are recorded as applied to the database")
+	}
+	if version.Int64 < expected {
+		return fmt.Errorf("database schema version %d is older than the expected version %d", version.Int64, expected)
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package sa
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+
+	"github.com/letsencrypt/boulder/test"
+	"github.com/letsencrypt/boulder/test/vars"
+)
+
+func TestParseMigration(t *testing.T) {
+	contents := `
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+
+ALTER TABLE ` + "`orders`" + `
+    ADD COLUMN ` + "`profile`" + ` varchar(32) NOT NULL DEFAULT '';
+
+-- +goose StatementBegin
+UPDATE orders SET profile = 'default';
+UPDATE orders SET profile = '' WHERE id = 1;
+-- +goose StatementEnd
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+
+ALTER TABLE ` + "`orders`" + ` DROP COLUMN ` + "`profile`" + `;
+`
+	m, err := parseMigration("20210210120000_AddProfile.sql", []byte(contents))
+	test.AssertNotError(t, err, "parsing migration")
+	test.AssertEquals(t, m.Version, int64(20210210120000))
+	test.AssertEquals(t, len(m.Checksum), 64)
+	test.AssertDeepEquals(t, m.Statements, []string{
+		"ALTER TABLE `orders`\n    ADD COLUMN `profile` varchar(32) NOT NULL DEFAULT '';",
+		"UPDATE orders SET profile = 'default';\nUPDATE orders SET profile = '' WHERE id = 1;",
+	})
+	test.Assert(t, !m.Transactional(), "migration with DDL is transactional")
+	m.Statements = m.Statements[1:]
+	test.Assert(t, m.Transactional(), "migration without DDL isn't transactional")
+
+	_, err = parseMigration("AddProfile.sql", []byte(contents))
+	test.AssertError(t, err, "parsed migration without a version")
+	_, err = parseMigration("20210210120000_AddProfile.sql", []byte("DROP TABLE orders;\n"))
+	test.AssertError(t, err, "parsed migration without an Up section")
+	_, err = parseMigration("20210210120000_AddProfile.sql", []byte("-- +goose Up\nDROP TABLE orders\n"))
+	test.AssertError(t, err, "parsed migration with an unterminated statement")
+}
+
+// TestRepoMigrations checks that the repo's migrations parse, and that
+// ExpectedSchemaVersion has been updated for the newest of them.
+func TestRepoMigrations(t *testing.T) {
+	migrations, err := LoadMigrations("_db")
+	test.AssertNotError(t, err, "loading migrations")
+	test.AssertEquals(t, migrations[len(migrations)-1].Version, ExpectedSchemaVersion)
+	test.AssertEquals(t, migrations[0].Source, schemaSource)
+
+	next, err := LoadMigrations("_db-next/")
+	test.AssertNotError(t, err, "loading next migrations")
+	for _, m := range next {
+		test.AssertEquals(t, m.Source, "_db-next")
+	}
+}
+
+func TestPlanMigrations(t *testing.T) {
+	available := []Migration{
+		{Version: 1, Name: "1_A.sql", Checksum: "aa"},
+		{Version: 2, Name: "2_B.sql", Checksum: "bb"},
+		{Version: 3, Name: "3_C.sql", Checksum: "cc"},
+		{Version: 4, Name: "4_D.sql", Checksum: "dd"},
+	}
+	applied := map[int64]appliedMigration{
+		1: {Version: 1, Name: "1_A.sql", Checksum: "aa"},
+		// An applied migration which isn't available.
+		5: {Version: 5, Name: "5_E.sql", Checksum: "ee"},
+	}
+	gooseApplied := map[int64]bool{1: true, 2: true}
+
+	plan, err := planMigrations(available, applied, gooseApplied, 0)
+	test.AssertNotError(t, err, "planning migrations")
+	test.AssertDeepEquals(t, plan.Adopt, available[1:2])
+	test.AssertDeepEquals(t, plan.Apply, available[2:])
+
+	plan, err = planMigrations(available, applied, gooseApplied, 3)
+	test.AssertNotError(t, err, "planning migrations to version 3")
+	test.AssertDeepEquals(t, plan.Apply, available[2:3])
+
+	_, err = planMigrations(available, applied, gooseApplied, 6)
+	test.AssertError(t, err, "planned migrations to a missing version")
+
+	// Migrations left unapplied behind newer ones are still applied.
+	applied[4] = appliedMigration{Version: 4, Name: "4_D.sql", Checksum: "dd"}
+	plan, err = planMigrations(available, applied, gooseApplied, 0)
+	test.AssertNotError(t, err, "planning migrations")
+	test.AssertDeepEquals(t, plan.Apply, available[2:3])
+
+	applied[1] = appliedMigration{Version: 1, Name: "1_A.sql", Checksum: "a0"}
+	_, err = planMigrations(available, applied, gooseApplied, 0)
+	test.AssertError(t, err, "planned migrations after an applied one changed")
+	test.Assert(t, strings.Contains(err.Error(), "1_A.sql has changed"), "wrong error: "+err.Error())
+}
+
+func TestCheckSchemaVersion(t *testing.T) {
+	db, err := sql.Open("mysql", vars.DBConnSA)
+	test.AssertNotError(t, err, "opening database")
+	defer db.Close()
+
+	err = CheckSchemaVersion(context.Background(), db, ExpectedSchemaVersion)
+	test.AssertNotError(t, err, "test database doesn't have the expected schema version")
+	err = CheckSchemaVersion(context.Background(), db, ExpectedSchemaVersion-1)
+	test.AssertNotError(t, err, "test database newer than expected failed check")
+	err = CheckSchemaVersion(context.Background(), db, ExpectedSchemaVersion+1)
+	test.AssertError(t, err, "test database older than expected passed check")
+}
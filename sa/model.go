@@ -17,6 +17,7 @@ import (
 	corepb "github.com/letsencrypt/boulder/core/proto"
 	"github.com/letsencrypt/boulder/db"
 	"github.com/letsencrypt/boulder/grpc"
+	"github.com/letsencrypt/boulder/identifier"
 	"github.com/letsencrypt/boulder/probs"
 )
 
@@ -142,6 +143,25 @@ func SelectCertificateStatuses(s db.Selector, q string, args ...interface{}) ([]
 	return models, err
 }
 
+// certificateStatusRow is a certificate status along with the ID of its row,
+// which SelectCertificateStatuses doesn't return.
+type certificateStatusRow struct {
+	ID int64 `db:"id"`
+	core.CertificateStatus
+}
+
+// selectCertificateStatusRows selects all fields of multiple certificate
+// status objects, along with their row IDs
+func selectCertificateStatusRows(s db.Selector, q string, args ...interface{}) ([]certificateStatusRow, error) {
+	var models []certificateStatusRow
+	_, err := s.Select(
+		&models,
+		fmt.Sprintf("SELECT id,%s FROM certificateStatus %s", strings.Join(certStatusFields(), ","), q),
+		args...,
+	)
+	return models, err
+}
+
 var mediumBlobSize = int(math.Pow(2, 24))
 
 type issuedNameModel struct {
@@ -299,13 +319,14 @@ type precertificateModel struct {
 }
 
 type orderModel struct {
-	ID                int64
-	RegistrationID    int64
-	Expires           time.Time
-	Created           time.Time
-	Error             []byte
-	CertificateSerial string
-	BeganProcessing   bool
+	ID                     int64
+	RegistrationID         int64
+	Expires                time.Time
+	Created                time.Time
+	Error                  []byte
+	CertificateSerial      string
+	BeganProcessing        bool
+	CertificateProfileName string
 }
 
 type requestedNameModel struct {
@@ -321,12 +342,13 @@ type orderToAuthzModel struct {
 
 func orderToModel(order *corepb.Order) (*orderModel, error) {
 	om := &orderModel{
-		ID:                order.Id,
-		RegistrationID:    order.RegistrationID,
-		Expires:           time.Unix(0, order.Expires),
-		Created:           time.Unix(0, order.Created),
-		BeganProcessing:   order.BeganProcessing,
-		CertificateSerial: order.CertificateSerial,
+		ID:                     order.Id,
+		RegistrationID:         order.RegistrationID,
+		Expires:                time.Unix(0, order.Expires),
+		Created:                time.Unix(0, order.Created),
+		BeganProcessing:        order.BeganProcessing,
+		CertificateSerial:      order.CertificateSerial,
+		CertificateProfileName: order.CertificateProfileName,
 	}
 
 	if order.Error != nil {
@@ -344,12 +366,13 @@ func orderToModel(order *corepb.Order) (*orderModel, error) {
 
 func modelToOrder(om *orderModel) (*corepb.Order, error) {
 	order := &corepb.Order{
-		Id:                om.ID,
-		RegistrationID:    om.RegistrationID,
-		Expires:           om.Expires.UnixNano(),
-		Created:           om.Created.UnixNano(),
-		CertificateSerial: om.CertificateSerial,
-		BeganProcessing:   om.BeganProcessing,
+		Id:                     om.ID,
+		RegistrationID:         om.RegistrationID,
+		Expires:                om.Expires.UnixNano(),
+		Created:                om.Created.UnixNano(),
+		CertificateSerial:      om.CertificateSerial,
+		BeganProcessing:        om.BeganProcessing,
+		CertificateProfileName: om.CertificateProfileName,
 	}
 	if len(om.Error) > 0 {
 		var problem corepb.ProblemDetails
@@ -366,23 +389,27 @@ func modelToOrder(om *orderModel) (*corepb.Order, error) {
 }
 
 var challTypeToUint = map[string]uint8{
-	"http-01":     0,
-	"dns-01":      1,
-	"tls-alpn-01": 2,
+	"http-01":        0,
+	"dns-01":         1,
+	"tls-alpn-01":    2,
+	"email-reply-00": 3,
 }
 
 var uintToChallType = map[uint8]string{
 	0: "http-01",
 	1: "dns-01",
 	2: "tls-alpn-01",
+	3: "email-reply-00",
 }
 
 var identifierTypeToUint = map[string]uint8{
-	"dns": 0,
+	"dns":   0,
+	"email": 1,
 }
 
 var uintToIdentifierType = map[uint8]string{
 	0: "dns",
+	1: "email",
 }
 
 var statusToUint = map[string]uint8{
@@ -407,6 +434,10 @@ func statusUint(status core.AcmeStatus) uint8 {
 
 const authzFields = "id, identifierType, identifierValue, registrationID, status, expires, challenges, attempted, token, validationError, validationRecord"
 
+// authzInsertFields are the authz2 columns written when inserting a new
+// authorization, i.e. authzFields without the autogenerated id.
+const authzInsertFields = "identifierType, identifierValue, registrationID, status, expires, challenges, attempted, token, validationError, validationRecord"
+
 type authzModel struct {
 	ID               int64     `db:"id"`
 	IdentifierType   uint8     `db:"identifierType"`
@@ -441,6 +472,7 @@ func hasMultipleNonPendingChallenges(challenges []*corepb.Challenge) bool {
 // authzModel storage representation.
 func authzPBToModel(authz *corepb.Authorization) (*authzModel, error) {
 	am := &authzModel{
+		IdentifierType:  identifierTypeToUint[string(identifier.FromValue(authz.Identifier).Type)],
 		IdentifierValue: authz.Identifier,
 		RegistrationID:  authz.RegistrationID,
 		Status:          statusToUint[authz.Status],
@@ -612,4 +644,5 @@ type keyHashModel struct {
 var stringToSourceInt = map[string]int{
 	"API":           1,
 	"admin-revoker": 2,
+	"feed":          3,
 }
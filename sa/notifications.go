@@ -0,0 +1,268 @@
+package sa
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	netmail "net/mail"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/letsencrypt/boulder/bdns"
+	corepb "github.com/letsencrypt/boulder/core/proto"
+	"github.com/letsencrypt/boulder/db"
+	berrors "github.com/letsencrypt/boulder/errors"
+	sapb "github.com/letsencrypt/boulder/sa/proto"
+)
+
+const (
+	// maxWebhookURLLength is the width of the notificationPreferences
+	// webhookURL column.
+	maxWebhookURLLength = 255
+	// minWebhookSecretLength and maxWebhookSecretLength bound the size of the
+	// key used to sign webhook notifications.
+	minWebhookSecretLength = 16
+	maxWebhookSecretLength = 64
+	// maxNagTimes bounds the number of reminders in a custom schedule.
+	maxNagTimes = 10
+	// maxNagTime is the longest time before expiry a reminder can be
+	// requested. Certificates are valid for at most 90 days.
+	maxNagTime = 90 * 24 * time.Hour
+	// maxEmailLength is the width of the notificationPreferences email
+	// column.
+	maxEmailLength = 255
+	// webhookSecretLabel is used when encrypting webhook secrets, so that
+	// they can't be swapped with encrypted contacts.
+	webhookSecretLabel = ":webhookSecret"
+)
+
+// notificationPreferencesModel represents a row in the notificationPreferences
+// table, which holds per-account settings used by the expiration-mailer. An
+// account's custom reminder schedule is stored separately, a row per nag time
+// in seconds, in the notificationNagTimes table.
+type notificationPreferencesModel struct {
+	RegistrationID int64     `db:"registrationID"`
+	WebhookURL     string    `db:"webhookURL"`
+	WebhookSecret  []byte    `db:"webhookSecret"`
+	EmailOptOut    bool      `db:"emailOptOut"`
+	Email          string    `db:"email"`
+	Updated        time.Time `db:"updated"`
+}
+
+// GetNotificationPreferences returns the notification preferences for the
+// given registration, or a berrors.NotFound error if none have been set.
+func (ssa *SQLStorageAuthority) GetNotificationPreferences(ctx context.Context, req *sapb.RegistrationID) (*sapb.NotificationPreferences, error) {
+	if req == nil || req.Id == 0 {
+		return nil, errIncompleteRequest
+	}
+	var model notificationPreferencesModel
+	err := ssa.dbMap.WithContext(ctx).SelectOne(
+		&model,
+		`SELECT registrationID, webhookURL, webhookSecret, emailOptOut, email, updated
+		FROM notificationPreferences
+		WHERE registrationID = ?`,
+		req.Id,
+	)
+	if err != nil {
+		if db.IsNoRows(err) {
+			return nil, berrors.NotFoundError("no notification preferences for registration %d", req.Id)
+		}
+		return nil, err
+	}
+	secret, err := decryptWebhookSecret(ssa.contactCipher, model.WebhookSecret)
+	if err != nil {
+		return nil, err
+	}
+	var seconds []int64
+	_, err = ssa.dbMap.WithContext(ctx).Select(
+		&seconds,
+		"SELECT nagTime FROM notificationNagTimes WHERE registrationID = ? ORDER BY nagTime",
+		req.Id,
+	)
+	if err != nil {
+		return nil, err
+	}
+	var nagTimes []int64
+	for _, s := range seconds {
+		nagTimes = append(nagTimes, int64(time.Duration(s)*time.Second))
+	}
+	return &sapb.NotificationPreferences{
+		RegistrationID: model.RegistrationID,
+		WebhookURL:     model.WebhookURL,
+		WebhookSecret:  secret,
+		EmailOptOut:    model.EmailOptOut,
+		NagTimes:       nagTimes,
+		Email:          model.Email,
+	}, nil
+}
+
+// SetNotificationPreferences creates or replaces the notification preferences
+// for a registration. An empty webhookURL disables webhook notifications; if
+// a webhookURL is given it must be an HTTPS URL and be accompanied by a
+// secret used to sign the notifications sent to it. Empty nagTimes and email
+// fields restore the expiration-mailer's default schedule and recipients.
+func (ssa *SQLStorageAuthority) SetNotificationPreferences(ctx context.Context, req *sapb.NotificationPreferences) (*corepb.Empty, error) {
+	if req == nil || req.RegistrationID == 0 {
+		return nil, errIncompleteRequest
+	}
+	var secret []byte
+	var err error
+	if req.WebhookURL != "" {
+		err = validateWebhookURL(req.WebhookURL)
+		if err != nil {
+			return nil, err
+		}
+		if len(req.WebhookSecret) < minWebhookSecretLength || len(req.WebhookSecret) > maxWebhookSecretLength {
+			return nil, berrors.MalformedError(
+				"webhook secret must be between %d and %d bytes", minWebhookSecretLength, maxWebhookSecretLength)
+		}
+		// Stored secrets with this prefix are taken to be encrypted.
+		if bytes.HasPrefix(req.WebhookSecret, []byte(encryptedContactPrefix)) {
+			return nil, berrors.MalformedError("webhook secret must not begin with %q", encryptedContactPrefix)
+		}
+		secret, err = encryptWebhookSecret(ssa.contactCipher, req.WebhookSecret)
+		if err != nil {
+			return nil, err
+		}
+	}
+	nagTimes, err := validateNagTimes(req.NagTimes)
+	if err != nil {
+		return nil, err
+	}
+	if req.Email != "" {
+		err := validateNotificationEmail(req.Email)
+		if err != nil {
+			return nil, err
+		}
+	}
+	// The schedule is stored a row per nag time in notificationNagTimes, where
+	// the expiration-mailer can query it.
+	_, err = ssa.withTransaction(ctx, func(txWithCtx db.Executor) (interface{}, error) {
+		_, err := txWithCtx.Exec(
+			`INSERT INTO notificationPreferences (registrationID, webhookURL, webhookSecret, emailOptOut, email, updated)
+			VALUES (?, ?, ?, ?, ?, ?)
+			ON DUPLICATE KEY UPDATE webhookURL = VALUES(webhookURL), webhookSecret = VALUES(webhookSecret),
+				emailOptOut = VALUES(emailOptOut), email = VALUES(email), updated = VALUES(updated)`,
+			req.RegistrationID,
+			req.WebhookURL,
+			secret,
+			req.EmailOptOut,
+			req.Email,
+			ssa.clk.Now(),
+		)
+		if err != nil {
+			return nil, err
+		}
+		_, err = txWithCtx.Exec("DELETE FROM notificationNagTimes WHERE registrationID = ?", req.RegistrationID)
+		if err != nil {
+			return nil, err
+		}
+		for _, nagTime := range nagTimes {
+			_, err = txWithCtx.Exec(
+				"INSERT INTO notificationNagTimes (registrationID, nagTime) VALUES (?, ?)",
+				req.RegistrationID,
+				int64(nagTime/time.Second),
+			)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return nil, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &corepb.Empty{}, nil
+}
+
+// encryptWebhookSecret returns the value which should be written to the
+// webhookSecret column for the given secret. If cc is nil the secret is stored
+// in plaintext.
+func encryptWebhookSecret(cc *ContactCipher, secret []byte) ([]byte, error) {
+	if cc == nil {
+		return secret, nil
+	}
+	sealed, err := cc.seal(secret, webhookSecretLabel)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(sealed), nil
+}
+
+// decryptWebhookSecret returns the plaintext secret for a value read from the
+// webhookSecret column. Secrets which aren't encrypted are returned unchanged.
+func decryptWebhookSecret(cc *ContactCipher, stored []byte) ([]byte, error) {
+	keyID, ciphertext, err := parseSealed(string(stored))
+	if err != nil {
+		return nil, fmt.Errorf("malformed encrypted webhook secret: %s", err)
+	}
+	if keyID == "" {
+		return stored, nil
+	}
+	if cc == nil {
+		return nil, fmt.Errorf("webhook secret is encrypted with key %q but contact encryption is not configured", keyID)
+	}
+	secret, err := cc.open(keyID, ciphertext, webhookSecretLabel)
+	if err != nil {
+		return nil, fmt.Errorf("webhook secret: %s", err)
+	}
+	return secret, nil
+}
+
+func validateWebhookURL(webhookURL string) error {
+	if len(webhookURL) > maxWebhookURLLength {
+		return berrors.MalformedError("webhook URL must be at most %d characters", maxWebhookURLLength)
+	}
+	parsed, err := url.Parse(webhookURL)
+	if err != nil {
+		return berrors.MalformedError("invalid webhook URL: %s", err)
+	}
+	if parsed.Scheme != "https" || parsed.Host == "" {
+		return berrors.MalformedError("webhook URL must be an absolute https URL")
+	}
+	// Names are checked by the expiration-mailer once they're resolved, but
+	// a private address can be rejected straight away.
+	if ip := net.ParseIP(parsed.Hostname()); ip != nil && bdns.IsPrivateIP(ip) {
+		return berrors.MalformedError("webhook URL must not be at a private address")
+	}
+	return nil
+}
+
+// validateNagTimes validates a custom reminder schedule and returns it, in
+// whole seconds, sorted and without duplicates.
+func validateNagTimes(nagTimes []int64) ([]time.Duration, error) {
+	if len(nagTimes) > maxNagTimes {
+		return nil, berrors.MalformedError("at most %d nag times may be set", maxNagTimes)
+	}
+	durations := make([]time.Duration, 0, len(nagTimes))
+	for _, nt := range nagTimes {
+		d := time.Duration(nt)
+		if d < time.Second || d > maxNagTime {
+			return nil, berrors.MalformedError("nag time %s must be at least 1s and at most %s", d, maxNagTime)
+		}
+		durations = append(durations, d)
+	}
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+	var unique []time.Duration
+	for _, d := range durations {
+		d = d.Truncate(time.Second)
+		if len(unique) > 0 && unique[len(unique)-1] == d {
+			continue
+		}
+		unique = append(unique, d)
+	}
+	return unique, nil
+}
+
+func validateNotificationEmail(email string) error {
+	if len(email) > maxEmailLength {
+		return berrors.MalformedError("email address must be at most %d characters", maxEmailLength)
+	}
+	addr, err := netmail.ParseAddress(email)
+	if err != nil || addr.Address != email {
+		return berrors.MalformedError("invalid email address %q", email)
+	}
+	return nil
+}
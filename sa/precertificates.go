@@ -23,11 +23,13 @@ func (ssa *SQLStorageAuthority) AddSerial(ctx context.Context, req *sapb.AddSeri
 	if core.IsAnyNilOrZero(req.Created, req.Expires, req.Serial, req.RegID) {
 		return nil, errIncompleteRequest
 	}
-	err := ssa.dbMap.WithContext(ctx).Insert(&recordedSerialModel{
-		Serial:         req.Serial,
-		RegistrationID: req.RegID,
-		Created:        time.Unix(0, req.Created),
-		Expires:        time.Unix(0, req.Expires),
+	err := ssa.retryWrite(ctx, func() error {
+		return ssa.dbMap.WithContext(ctx).Insert(&recordedSerialModel{
+			Serial:         req.Serial,
+			RegistrationID: req.RegID,
+			Created:        time.Unix(0, req.Created),
+			Expires:        time.Unix(0, req.Expires),
+		})
 	})
 	if err != nil {
 		return nil, err
@@ -55,7 +57,7 @@ func (ssa *SQLStorageAuthority) AddPrecertificate(ctx context.Context, req *sapb
 		Expires:        parsed.NotAfter,
 	}
 
-	_, overallError := db.WithTransaction(ctx, ssa.dbMap, func(txWithCtx db.Executor) (interface{}, error) {
+	_, overallError := ssa.withTransaction(ctx, func(txWithCtx db.Executor) (interface{}, error) {
 		if err := txWithCtx.Insert(preCertModel); err != nil {
 			if db.IsDuplicate(err) {
 				return nil, berrors.DuplicateError("cannot add a duplicate precertificate")
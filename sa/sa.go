@@ -4,17 +4,20 @@ import (
 	"context"
 	"crypto/sha256"
 	"crypto/x509"
+	"database/sql"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"math/big"
 	"net"
+	"path"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/jmhodges/clock"
 	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
 	jose "gopkg.in/square/go-jose.v2"
 
 	"github.com/letsencrypt/boulder/core"
@@ -42,6 +45,17 @@ type SQLStorageAuthority struct {
 	// threads).
 	parallelismPerRPC int
 
+	// For RPCs that insert many rows into the same table (e.g. the
+	// authorizations for a large new order), this is the max number of rows
+	// written by a single multi-row INSERT statement. Values less than one
+	// mean that all of the rows are written by a single statement.
+	maxInsertBatchSize int
+
+	// contactCipher, if non-nil, is used to encrypt the contents of the
+	// registrations table's contact column on write. Contacts are stored in
+	// plaintext if it is nil.
+	contactCipher *ContactCipher
+
 	// We use function types here so we can mock out this internal function in
 	// unittests.
 	countCertificatesByName certCountFunc
@@ -52,6 +66,15 @@ type SQLStorageAuthority struct {
 	// transactions fail and so use this stat to maintain visibility into the rate
 	// this occurs.
 	rateLimitWriteErrors prometheus.Counter
+
+	// retrier retries transactions and writes which fail with transient
+	// errors, such as deadlocks. By default it doesn't retry anything; see
+	// SetTransactionRetryPolicy.
+	retrier *db.Retrier
+
+	// accountEventRetention is how long accounts' audit events are kept for.
+	// See SetAccountEventRetention.
+	accountEventRetention time.Duration
 }
 
 // orderFQDNSet contains the SHA256 hash of the lowercased, comma joined names
@@ -66,6 +89,13 @@ type orderFQDNSet struct {
 	Expires        time.Time
 }
 
+// newOrderResult is the value passed out of the NewOrderAndAuthzs transaction:
+// the inserted order row and the IDs of every authorization linked to it.
+type newOrderResult struct {
+	order    *orderModel
+	authzIDs []int64
+}
+
 // NewSQLStorageAuthority provides persistence using a SQL backend for
 // Boulder. It will modify the given gorp.DbMap by adding relevant tables.
 func NewSQLStorageAuthority(
@@ -74,6 +104,8 @@ func NewSQLStorageAuthority(
 	logger blog.Logger,
 	stats prometheus.Registerer,
 	parallelismPerRPC int,
+	maxInsertBatchSize int,
+	contactCipher *ContactCipher,
 ) (*SQLStorageAuthority, error) {
 	SetSQLDebug(dbMap, logger)
 
@@ -84,11 +116,15 @@ func NewSQLStorageAuthority(
 	stats.MustRegister(rateLimitWriteErrors)
 
 	ssa := &SQLStorageAuthority{
-		dbMap:                dbMap,
-		clk:                  clk,
-		log:                  logger,
-		parallelismPerRPC:    parallelismPerRPC,
-		rateLimitWriteErrors: rateLimitWriteErrors,
+		dbMap:                 dbMap,
+		clk:                   clk,
+		log:                   logger,
+		parallelismPerRPC:     parallelismPerRPC,
+		maxInsertBatchSize:    maxInsertBatchSize,
+		contactCipher:         contactCipher,
+		rateLimitWriteErrors:  rateLimitWriteErrors,
+		retrier:               db.NewRetrier(stats),
+		accountEventRetention: defaultAccountEventRetention,
 	}
 
 	ssa.countCertificatesByName = ssa.countCertificates
@@ -96,6 +132,35 @@ func NewSQLStorageAuthority(
 	return ssa, nil
 }
 
+// SetTransactionRetryPolicy sets how the SA retries transactions and writes
+// which fail with deadlocks, lock wait timeouts or lost connections.
+func (ssa *SQLStorageAuthority) SetTransactionRetryPolicy(policy db.RetryPolicy) {
+	ssa.retrier.SetPolicy(policy)
+}
+
+// withTransaction runs f in a transaction, retrying it if it fails with a
+// transient error. Since f may be run more than once it mustn't have side
+// effects outside of the transaction.
+func (ssa *SQLStorageAuthority) withTransaction(ctx context.Context, f func(txWithCtx db.Executor) (interface{}, error)) (interface{}, error) {
+	return ssa.retrier.WithTransaction(ctx, rpcMethod(ctx), ssa.dbMap, f)
+}
+
+// retryWrite runs f, a single write outside of a transaction, retrying it if
+// it fails with a deadlock or a lock wait timeout.
+func (ssa *SQLStorageAuthority) retryWrite(ctx context.Context, f func() error) error {
+	return ssa.retrier.Do(ctx, rpcMethod(ctx), f)
+}
+
+// rpcMethod returns the name of the gRPC method being served with ctx, like
+// "AddCertificate", for labelling metrics.
+func rpcMethod(ctx context.Context) string {
+	method, ok := grpc.Method(ctx)
+	if !ok {
+		return "unknown"
+	}
+	return path.Base(method)
+}
+
 // GetRegistration obtains a Registration by ID
 func (ssa *SQLStorageAuthority) GetRegistration(ctx context.Context, id int64) (core.Registration, error) {
 	const query = "WHERE id = ?"
@@ -107,6 +172,10 @@ func (ssa *SQLStorageAuthority) GetRegistration(ctx context.Context, id int64) (
 		return core.Registration{}, err
 	}
 
+	model.Contact, err = DecryptContacts(ssa.contactCipher, model.Contact)
+	if err != nil {
+		return core.Registration{}, err
+	}
 	return modelToRegistration(model)
 }
 
@@ -128,6 +197,10 @@ func (ssa *SQLStorageAuthority) GetRegistrationByKey(ctx context.Context, key *j
 		return core.Registration{}, err
 	}
 
+	model.Contact, err = DecryptContacts(ssa.contactCipher, model.Contact)
+	if err != nil {
+		return core.Registration{}, err
+	}
 	return modelToRegistration(model)
 }
 
@@ -336,6 +409,115 @@ func (ssa *SQLStorageAuthority) GetCertificateStatus(ctx context.Context, serial
 	return certStatus, nil
 }
 
+// MaxCertificateStatusesPerRequest is the most serials whose statuses may be
+// requested in one call to GetCertificateStatuses.
+const MaxCertificateStatusesPerRequest = 1000
+
+// GetCertificateStatuses returns the statuses of the certificates with the
+// given serials, in the order the serials were given. Serials with no status
+// are omitted, so callers must match the statuses to their serials.
+func (ssa *SQLStorageAuthority) GetCertificateStatuses(ctx context.Context, req *sapb.Serials) (*sapb.CertificateStatuses, error) {
+	if req == nil || len(req.Serials) == 0 {
+		return nil, errIncompleteRequest
+	}
+	if len(req.Serials) > MaxCertificateStatusesPerRequest {
+		return nil, berrors.MalformedError("%d serials requested, but at most %d may be requested at once",
+			len(req.Serials), MaxCertificateStatusesPerRequest)
+	}
+
+	qmarks := make([]string, len(req.Serials))
+	params := make([]interface{}, len(req.Serials))
+	for i, serial := range req.Serials {
+		if !core.ValidSerial(serial) {
+			return nil, fmt.Errorf("Invalid certificate serial %s", serial)
+		}
+		params[i] = serial
+		qmarks[i] = "?"
+	}
+	statuses, err := SelectCertificateStatuses(
+		ssa.dbMap.WithContext(ctx),
+		"WHERE serial IN ("+strings.Join(qmarks, ",")+")",
+		params...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	bySerial := make(map[string]core.CertificateStatus, len(statuses))
+	for _, status := range statuses {
+		bySerial[status.Serial] = status
+	}
+	resp := &sapb.CertificateStatuses{}
+	for _, serial := range req.Serials {
+		status, ok := bySerial[serial]
+		if !ok {
+			continue
+		}
+		// A serial requested twice is only returned once.
+		delete(bySerial, serial)
+		resp.Statuses = append(resp.Statuses, bgrpc.CertStatusToPB(status))
+	}
+	return resp, nil
+}
+
+// certificateStatusStreamBatchSize is the number of rows read from the
+// database at a time by GetCertificateStatusesByID.
+var certificateStatusStreamBatchSize = 1000
+
+// GetMaxCertificateStatusID returns the largest ID in the certificateStatus
+// table, or zero if the table is empty.
+func (ssa *SQLStorageAuthority) GetMaxCertificateStatusID(ctx context.Context, req *corepb.Empty) (*sapb.CertificateStatusID, error) {
+	if req == nil {
+		return nil, errIncompleteRequest
+	}
+	var id int64
+	err := ssa.dbMap.WithContext(ctx).SelectOne(
+		&id,
+		`SELECT COALESCE(MAX(id), 0) FROM certificateStatus`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &sapb.CertificateStatusID{Id: id}, nil
+}
+
+// GetCertificateStatusesByID streams, in order of ID, the certificateStatus
+// rows whose IDs are greater than req.AfterID and at most req.MaxID. The rows
+// are read in batches, so that a large range doesn't hold a single query open
+// for as long as the stream is being consumed.
+func (ssa *SQLStorageAuthority) GetCertificateStatusesByID(req *sapb.CertificateStatusesByIDRequest, stream sapb.StorageAuthority_GetCertificateStatusesByIDServer) error {
+	if req == nil || req.MaxID <= req.AfterID {
+		return errIncompleteRequest
+	}
+	ctx := stream.Context()
+	after := req.AfterID
+	for {
+		rows, err := selectCertificateStatusRows(
+			ssa.dbMap.WithContext(ctx),
+			"WHERE id > ? AND id <= ? ORDER BY id LIMIT ?",
+			after,
+			req.MaxID,
+			certificateStatusStreamBatchSize,
+		)
+		if err != nil {
+			return err
+		}
+		for _, row := range rows {
+			err = stream.Send(&sapb.CertificateStatusRow{
+				Id:     row.ID,
+				Status: bgrpc.CertStatusToPB(row.CertificateStatus),
+			})
+			if err != nil {
+				return err
+			}
+		}
+		if len(rows) < certificateStatusStreamBatchSize {
+			return nil
+		}
+		after = rows[len(rows)-1].ID
+	}
+}
+
 // NewRegistration stores a new Registration
 func (ssa *SQLStorageAuthority) NewRegistration(ctx context.Context, reg core.Registration) (core.Registration, error) {
 	reg.CreatedAt = ssa.clk.Now()
@@ -343,7 +525,14 @@ func (ssa *SQLStorageAuthority) NewRegistration(ctx context.Context, reg core.Re
 	if err != nil {
 		return reg, err
 	}
-	err = ssa.dbMap.WithContext(ctx).Insert(rm)
+	contact := rm.Contact
+	rm.Contact, err = encryptContacts(ssa.contactCipher, contact)
+	if err != nil {
+		return reg, err
+	}
+	err = ssa.retryWrite(ctx, func() error {
+		return ssa.dbMap.WithContext(ctx).Insert(rm)
+	})
 	if err != nil {
 		if db.IsDuplicate(err) {
 			// duplicate entry error can only happen when jwk_sha256 collides, indicate
@@ -352,6 +541,7 @@ func (ssa *SQLStorageAuthority) NewRegistration(ctx context.Context, reg core.Re
 		}
 		return reg, err
 	}
+	rm.Contact = contact
 	return modelToRegistration(rm)
 }
 
@@ -370,11 +560,39 @@ func (ssa *SQLStorageAuthority) UpdateRegistration(ctx context.Context, reg core
 	if err != nil {
 		return err
 	}
+	updatedRegModel.Contact, err = encryptContacts(ssa.contactCipher, updatedRegModel.Contact)
+	if err != nil {
+		return err
+	}
+
+	if !features.Enabled(features.KeyRolloverHistory) {
+		// Copy the existing registration model's LockCol to the new updated
+		// registration model's LockCol
+		updatedRegModel.LockCol = model.LockCol
+		n, err := ssa.dbMap.WithContext(ctx).Update(updatedRegModel)
+		return updateRegistrationResult(reg.ID, n, err)
+	}
+
+	// Record any change to the registration's key along with the update, so
+	// that the key rollover history can't miss one.
+	now := ssa.clk.Now()
+	n, err := ssa.withTransaction(ctx, func(txWithCtx db.Executor) (interface{}, error) {
+		updatedRegModel.LockCol = model.LockCol
+		n, err := txWithCtx.Update(updatedRegModel)
+		if err != nil || n == 0 {
+			return n, err
+		}
+		return n, addKeyRollover(txWithCtx, reg.ID, model.KeySHA256, updatedRegModel.KeySHA256, now)
+	})
+	if n == nil {
+		n = int64(0)
+	}
+	return updateRegistrationResult(reg.ID, n.(int64), err)
+}
 
-	// Copy the existing registration model's LockCol to the new updated
-	// registration model's LockCol
-	updatedRegModel.LockCol = model.LockCol
-	n, err := ssa.dbMap.WithContext(ctx).Update(updatedRegModel)
+// updateRegistrationResult converts the result of updating a registration's
+// row into UpdateRegistration's error.
+func updateRegistrationResult(regID int64, n int64, err error) error {
 	if err != nil {
 		if db.IsDuplicate(err) {
 			// duplicate entry error can only happen when jwk_sha256 collides, indicate
@@ -384,7 +602,7 @@ func (ssa *SQLStorageAuthority) UpdateRegistration(ctx context.Context, reg core
 		return err
 	}
 	if n == 0 {
-		return berrors.NotFoundError("registration with ID '%d' not found", reg.ID)
+		return berrors.NotFoundError("registration with ID '%d' not found", regID)
 	}
 
 	return nil
@@ -414,7 +632,7 @@ func (ssa *SQLStorageAuthority) AddCertificate(
 		Expires:        parsedCertificate.NotAfter,
 	}
 
-	isRenewalRaw, overallError := db.WithTransaction(ctx, ssa.dbMap, func(txWithCtx db.Executor) (interface{}, error) {
+	isRenewalRaw, overallError := ssa.withTransaction(ctx, func(txWithCtx db.Executor) (interface{}, error) {
 		// Save the final certificate
 		err = txWithCtx.Insert(cert)
 		if err != nil {
@@ -443,12 +661,12 @@ func (ssa *SQLStorageAuthority) AddCertificate(
 		return "", overallError
 	}
 
-	// Recast the interface{} return from db.WithTransaction as a bool, returning
+	// Recast the interface{} return from withTransaction as a bool, returning
 	// an error if we can't.
 	var isRenewal bool
 	if boolVal, ok := isRenewalRaw.(bool); !ok {
 		return "", fmt.Errorf(
-			"AddCertificate db.WithTransaction returned %T out var, expected bool",
+			"AddCertificate withTransaction returned %T out var, expected bool",
 			isRenewalRaw)
 	} else {
 		isRenewal = boolVal
@@ -458,7 +676,7 @@ func (ssa *SQLStorageAuthority) AddCertificate(
 	// for rate limits. Since the effects of failing these writes is slight
 	// miscalculation of rate limits we choose to not fail the AddCertificate
 	// operation if the rate limit update transaction fails.
-	_, rlTransactionErr := db.WithTransaction(ctx, ssa.dbMap, func(txWithCtx db.Executor) (interface{}, error) {
+	_, rlTransactionErr := ssa.withTransaction(ctx, func(txWithCtx db.Executor) (interface{}, error) {
 		// Add to the rate limit table, but only for new certificates. Renewals
 		// don't count against the certificatesPerName limit.
 		if !isRenewal {
@@ -810,29 +1028,130 @@ func (ssa *SQLStorageAuthority) PreviousCertificateExists(
 	return notExists, nil
 }
 
-// DeactivateRegistration deactivates a currently valid registration
-func (ssa *SQLStorageAuthority) DeactivateRegistration(ctx context.Context, id int64) error {
-	_, err := ssa.dbMap.WithContext(ctx).Exec(
-		"UPDATE registrations SET status = ? WHERE status = ? AND id = ?",
-		string(core.StatusDeactivated),
-		string(core.StatusValid),
-		id,
+// ReplacementCertificateExists returns true if a certificate issued to the
+// given account after issuedAfter covers all of the given names, and so
+// replaces the certificate with the given serial. Unlike the fqdnSets
+// renewal check, the replacement may include additional names. Revoked
+// certificates aren't considered replacements.
+func (ssa *SQLStorageAuthority) ReplacementCertificateExists(
+	ctx context.Context,
+	req *sapb.ReplacementCertificateExistsRequest,
+) (*sapb.Exists, error) {
+	if core.IsAnyNilOrZero(req, req.Serial, req.RegID, req.DnsNames, req.IssuedAfter) {
+		return nil, errIncompleteRequest
+	}
+	names := core.UniqueLowerNames(req.DnsNames)
+	var qmarks []string
+	var args []interface{}
+	for _, name := range names {
+		qmarks = append(qmarks, "?")
+		args = append(args, ReverseName(name))
+	}
+	args = append(args, time.Unix(0, req.IssuedAfter), req.Serial, req.RegID, string(core.OCSPStatusRevoked), len(names))
+
+	var serials []string
+	_, err := ssa.dbMap.WithContext(ctx).Select(
+		&serials,
+		`SELECT n.serial FROM issuedNames AS n
+		JOIN certificates AS c ON c.serial = n.serial
+		JOIN certificateStatus AS cs ON cs.serial = n.serial
+		WHERE n.reversedName IN (`+strings.Join(qmarks, ", ")+`)
+		AND n.notBefore > ?
+		AND n.serial != ?
+		AND c.registrationID = ?
+		AND cs.status != ?
+		GROUP BY n.serial
+		HAVING COUNT(DISTINCT n.reversedName) = ?
+		LIMIT 1`,
+		args...,
 	)
+	if err != nil {
+		return nil, err
+	}
+	return &sapb.Exists{Exists: len(serials) > 0}, nil
+}
+
+// GetSerialsByKey returns the serials of all unexpired certificates whose
+// SubjectPublicKeyInfo has the given SHA-256 hash, regardless of which
+// account they were issued to.
+func (ssa *SQLStorageAuthority) GetSerialsByKey(ctx context.Context, req *sapb.SPKIHash) (*sapb.Serials, error) {
+	if req == nil || len(req.KeyHash) == 0 {
+		return nil, errIncompleteRequest
+	}
+	var serials []string
+	_, err := ssa.dbMap.WithContext(ctx).Select(
+		&serials,
+		"SELECT certSerial FROM keyHashToSerial WHERE keyHash = ? AND certNotAfter > ?",
+		req.KeyHash,
+		ssa.clk.Now(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &sapb.Serials{Serials: serials}, nil
+}
+
+// GetSerialsByAccount returns the serials of all unexpired certificates
+// issued to the given account.
+func (ssa *SQLStorageAuthority) GetSerialsByAccount(ctx context.Context, req *sapb.RegistrationID) (*sapb.Serials, error) {
+	if req == nil || req.Id == 0 {
+		return nil, errIncompleteRequest
+	}
+	var serials []string
+	_, err := ssa.dbMap.WithContext(ctx).Select(
+		&serials,
+		"SELECT serial FROM certificates WHERE registrationID = ? AND expires > ?",
+		req.Id,
+		ssa.clk.Now(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &sapb.Serials{Serials: serials}, nil
+}
+
+// DeactivateRegistration deactivates a currently valid registration, along
+// with its unexpired pending authorizations. Since an order's status is
+// derived from those of its authorizations, this also invalidates the
+// registration's pending orders.
+func (ssa *SQLStorageAuthority) DeactivateRegistration(ctx context.Context, id int64) error {
+	_, err := ssa.withTransaction(ctx, func(txWithCtx db.Executor) (interface{}, error) {
+		_, err := txWithCtx.Exec(
+			"UPDATE registrations SET status = ? WHERE status = ? AND id = ?",
+			string(core.StatusDeactivated),
+			string(core.StatusValid),
+			id,
+		)
+		if err != nil {
+			return nil, err
+		}
+		_, err = txWithCtx.Exec(
+			"UPDATE authz2 SET status = ? WHERE registrationID = ? AND status = ? AND expires > ?",
+			statusUint(core.StatusDeactivated),
+			id,
+			statusUint(core.StatusPending),
+			ssa.clk.Now(),
+		)
+		return nil, err
+	})
 	return err
 }
 
 // DeactivateAuthorization2 deactivates a currently valid or pending authorization.
 // This method is intended to deprecate DeactivateAuthorization.
 func (ssa *SQLStorageAuthority) DeactivateAuthorization2(ctx context.Context, req *sapb.AuthorizationID2) (*corepb.Empty, error) {
-	_, err := ssa.dbMap.Exec(
-		`UPDATE authz2 SET status = :deactivated WHERE id = :id and status IN (:valid,:pending)`,
-		map[string]interface{}{
-			"deactivated": statusUint(core.StatusDeactivated),
-			"id":          req.Id,
-			"valid":       statusUint(core.StatusValid),
-			"pending":     statusUint(core.StatusPending),
-		},
-	)
+	err := ssa.retryWrite(ctx, func() error {
+		_, err := ssa.dbMap.Exec(
+			`UPDATE authz2 SET status = :deactivated WHERE id = :id and status IN (:valid,:pending)`,
+			map[string]interface{}{
+				"deactivated": statusUint(core.StatusDeactivated),
+				"id":          req.Id,
+				"valid":       statusUint(core.StatusValid),
+				"pending":     statusUint(core.StatusPending),
+			},
+		)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -841,67 +1160,142 @@ func (ssa *SQLStorageAuthority) DeactivateAuthorization2(ctx context.Context, re
 
 // NewOrder adds a new v2 style order to the database
 func (ssa *SQLStorageAuthority) NewOrder(ctx context.Context, req *corepb.Order) (*corepb.Order, error) {
-	output, err := db.WithTransaction(ctx, ssa.dbMap, func(txWithCtx db.Executor) (interface{}, error) {
-		order := &orderModel{
-			RegistrationID: req.RegistrationID,
-			Expires:        time.Unix(0, req.Expires),
-			Created:        ssa.clk.Now(),
+	return ssa.NewOrderAndAuthzs(ctx, &sapb.NewOrderAndAuthzsRequest{NewOrder: req})
+}
+
+// insertAuthzs adds the given pending authorizations to the authz2 table using
+// multi-row inserts and returns their autogenerated IDs, in the same order as
+// the provided authorizations. The caller is expected to provide a transaction
+// if the insertion needs to be atomic with other writes.
+func (ssa *SQLStorageAuthority) insertAuthzs(exec db.SelectExecer, authzs []*corepb.Authorization) ([]int64, error) {
+	if len(authzs) == 0 {
+		return nil, nil
+	}
+	inserter, err := db.NewMultiInserter("authz2", strings.Split(authzInsertFields, ", "), "id", ssa.maxInsertBatchSize)
+	if err != nil {
+		return nil, err
+	}
+	for _, authz := range authzs {
+		if authz.Status != string(core.StatusPending) {
+			return nil, berrors.InternalServerError("authorization must be pending")
+		}
+		am, err := authzPBToModel(authz)
+		if err != nil {
+			return nil, err
+		}
+		err = inserter.Add([]interface{}{
+			am.IdentifierType,
+			am.IdentifierValue,
+			am.RegistrationID,
+			am.Status,
+			am.Expires,
+			am.Challenges,
+			am.Attempted,
+			am.Token,
+			am.ValidationError,
+			am.ValidationRecord,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return inserter.Insert(exec)
+}
+
+// NewOrderAndAuthzs adds the given authorizations to the database, adds their
+// autogenerated IDs to the given order, and then adds the order to the
+// database. This is done inside a single transaction to prevent situations
+// where new authorizations are created, but then their corresponding order is
+// never created, leading to "invisible" pending authorizations. The
+// authorizations, order-to-authorization links and requested names are each
+// written with multi-row inserts of at most maxInsertBatchSize rows, so the
+// number of database round trips doesn't grow linearly with the number of
+// names in the order.
+func (ssa *SQLStorageAuthority) NewOrderAndAuthzs(ctx context.Context, req *sapb.NewOrderAndAuthzsRequest) (*corepb.Order, error) {
+	if req.NewOrder == nil {
+		return nil, errIncompleteRequest
+	}
+
+	output, err := ssa.withTransaction(ctx, func(txWithCtx db.Executor) (interface{}, error) {
+		// First, insert all of the new authorizations and record their IDs.
+		newAuthzIDs, err := ssa.insertAuthzs(txWithCtx, req.NewAuthzs)
+		if err != nil {
+			return nil, err
 		}
 
+		// Second, insert the new order.
+		order := &orderModel{
+			RegistrationID:         req.NewOrder.RegistrationID,
+			Expires:                time.Unix(0, req.NewOrder.Expires),
+			Created:                ssa.clk.Now(),
+			CertificateProfileName: req.NewOrder.CertificateProfileName,
+		}
 		if err := txWithCtx.Insert(order); err != nil {
 			return nil, err
 		}
 
-		for _, id := range req.V2Authorizations {
-			otoa := &orderToAuthzModel{
-				OrderID: order.ID,
-				AuthzID: id,
-			}
-			if err := txWithCtx.Insert(otoa); err != nil {
+		// Third, link every authorization (reused and new) to the order.
+		authzIDs := make([]int64, 0, len(req.NewOrder.V2Authorizations)+len(newAuthzIDs))
+		authzIDs = append(authzIDs, req.NewOrder.V2Authorizations...)
+		authzIDs = append(authzIDs, newAuthzIDs...)
+		otoaInserter, err := db.NewMultiInserter("orderToAuthz2", []string{"orderID", "authzID"}, "", ssa.maxInsertBatchSize)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range authzIDs {
+			if err := otoaInserter.Add([]interface{}{order.ID, id}); err != nil {
 				return nil, err
 			}
 		}
+		if _, err := otoaInserter.Insert(txWithCtx); err != nil {
+			return nil, err
+		}
 
-		for _, name := range req.Names {
-			reqdName := &requestedNameModel{
-				OrderID:      order.ID,
-				ReversedName: ReverseName(name),
-			}
-			if err := txWithCtx.Insert(reqdName); err != nil {
+		// Fourth, record the names requested by the order.
+		namesInserter, err := db.NewMultiInserter("requestedNames", []string{"orderID", "reversedName"}, "", ssa.maxInsertBatchSize)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range req.NewOrder.Names {
+			if err := namesInserter.Add([]interface{}{order.ID, ReverseName(name)}); err != nil {
 				return nil, err
 			}
 		}
+		if _, err := namesInserter.Insert(txWithCtx); err != nil {
+			return nil, err
+		}
 
-		// Add an FQDNSet entry for the order
+		// Finally, add an FQDNSet entry for the order
 		if err := addOrderFQDNSet(
-			txWithCtx, req.Names, order.ID, order.RegistrationID, order.Expires); err != nil {
+			txWithCtx, req.NewOrder.Names, order.ID, order.RegistrationID, order.Expires); err != nil {
 			return nil, err
 		}
 
-		return order, nil
+		return &newOrderResult{order: order, authzIDs: authzIDs}, nil
 	})
 	if err != nil {
 		return nil, err
 	}
-	var order *orderModel
-	var ok bool
-	if order, ok = output.(*orderModel); !ok {
-		return nil, fmt.Errorf("shouldn't happen: casting error in NewOrder")
+	result, ok := output.(*newOrderResult)
+	if !ok {
+		return nil, fmt.Errorf("shouldn't happen: casting error in NewOrderAndAuthzs")
 	}
+	order := result.order
 
 	if features.Enabled(features.FasterNewOrdersRateLimit) {
 		// Increment the order creation count
-		if err := addNewOrdersRateLimit(ctx, ssa.dbMap, req.RegistrationID, ssa.clk.Now().Truncate(time.Minute)); err != nil {
+		if err := addNewOrdersRateLimit(ctx, ssa.dbMap, req.NewOrder.RegistrationID, ssa.clk.Now().Truncate(time.Minute)); err != nil {
 			return nil, err
 		}
 	}
 
 	res := &corepb.Order{
 		// Carry some fields over the from input new order request.
-		RegistrationID:   req.RegistrationID,
-		Expires:          req.Expires,
-		Names:            req.Names,
-		V2Authorizations: req.V2Authorizations,
+		RegistrationID:         req.NewOrder.RegistrationID,
+		Expires:                req.NewOrder.Expires,
+		Names:                  req.NewOrder.Names,
+		V2Authorizations:       result.authzIDs,
+		CertificateProfileName: req.NewOrder.CertificateProfileName,
 		// Some fields were generated by the database transaction.
 		Id:      order.ID,
 		Created: order.Created.UnixNano(),
@@ -919,26 +1313,37 @@ func (ssa *SQLStorageAuthority) NewOrder(ctx context.Context, req *corepb.Order)
 	return res, nil
 }
 
-// SetOrderProcessing updates a provided *corepb.Order in pending status to be
+// SetOrderProcessing updates a provided *corepb.Order in ready status to be
 // in processing status by updating the `beganProcessing` field of the
-// corresponding Order table row in the DB.
+// corresponding Order table row in the DB. The statuses of all of the order's
+// authorizations are checked by the same statement, so however many names the
+// order has, a single round trip both confirms that it's still ready and
+// marks it processing.
 func (ssa *SQLStorageAuthority) SetOrderProcessing(ctx context.Context, req *corepb.Order) error {
-	_, overallError := db.WithTransaction(ctx, ssa.dbMap, func(txWithCtx db.Executor) (interface{}, error) {
+	_, overallError := ssa.withTransaction(ctx, func(txWithCtx db.Executor) (interface{}, error) {
 		result, err := txWithCtx.Exec(`
 		UPDATE orders
 		SET beganProcessing = ?
 		WHERE id = ?
-		AND beganProcessing = ?`,
+		AND beganProcessing = ?
+		AND NOT EXISTS (
+			SELECT 1 FROM orderToAuthz2
+			JOIN authz2 ON authz2.id = orderToAuthz2.authzID
+			WHERE orderToAuthz2.orderID = orders.id
+			AND (authz2.status != ? OR authz2.expires <= ?)
+		)`,
 			true,
 			req.Id,
-			false)
+			false,
+			statusUint(core.StatusValid),
+			ssa.clk.Now())
 		if err != nil {
 			return nil, berrors.InternalServerError("error updating order to beganProcessing status")
 		}
 
 		n, err := result.RowsAffected()
 		if err != nil || n == 0 {
-			return nil, berrors.OrderNotReadyError("Order was already processing. This may indicate your client finalized the same order multiple times, possibly due to a client bug.")
+			return nil, berrors.OrderNotReadyError("Order was already processing or is no longer ready. This may indicate your client finalized the same order multiple times, possibly due to a client bug.")
 		}
 
 		return nil, nil
@@ -948,7 +1353,7 @@ func (ssa *SQLStorageAuthority) SetOrderProcessing(ctx context.Context, req *cor
 
 // SetOrderError updates a provided Order's error field.
 func (ssa *SQLStorageAuthority) SetOrderError(ctx context.Context, order *corepb.Order) error {
-	_, overallError := db.WithTransaction(ctx, ssa.dbMap, func(txWithCtx db.Executor) (interface{}, error) {
+	_, overallError := ssa.withTransaction(ctx, func(txWithCtx db.Executor) (interface{}, error) {
 		om, err := orderToModel(order)
 		if err != nil {
 			return nil, err
@@ -979,7 +1384,7 @@ func (ssa *SQLStorageAuthority) SetOrderError(ctx context.Context, order *corepb
 // CertificateSerial and the order ID on the provided order are processed (e.g.
 // this is not a generic update RPC).
 func (ssa *SQLStorageAuthority) FinalizeOrder(ctx context.Context, req *corepb.Order) error {
-	_, overallError := db.WithTransaction(ctx, ssa.dbMap, func(txWithCtx db.Executor) (interface{}, error) {
+	_, overallError := ssa.withTransaction(ctx, func(txWithCtx db.Executor) (interface{}, error) {
 		result, err := txWithCtx.Exec(`
 		UPDATE orders
 		SET certificateSerial = ?
@@ -1328,22 +1733,17 @@ func AuthzMapToPB(m map[string]*core.Authorization) (*sapb.Authorizations, error
 // either the IDs of the authorizations or an error. It will only process corepb.Authorization
 // objects if the V2 field is set. This method is intended to deprecate AddPendingAuthorizations
 func (ssa *SQLStorageAuthority) NewAuthorizations2(ctx context.Context, req *sapb.AddPendingAuthorizationsRequest) (*sapb.Authorization2IDs, error) {
-	ids := &sapb.Authorization2IDs{}
-	for _, authz := range req.Authz {
-		if authz.Status != string(core.StatusPending) {
-			return nil, berrors.InternalServerError("authorization must be pending")
-		}
-		am, err := authzPBToModel(authz)
-		if err != nil {
-			return nil, err
-		}
-		err = ssa.dbMap.Insert(am)
-		if err != nil {
-			return nil, err
-		}
-		ids.Ids = append(ids.Ids, am.ID)
+	output, err := ssa.withTransaction(ctx, func(txWithCtx db.Executor) (interface{}, error) {
+		return ssa.insertAuthzs(txWithCtx, req.Authz)
+	})
+	if err != nil {
+		return nil, err
 	}
-	return ids, nil
+	ids, ok := output.([]int64)
+	if !ok {
+		return nil, fmt.Errorf("shouldn't happen: casting error in NewAuthorizations2")
+	}
+	return &sapb.Authorization2IDs{Ids: ids}, nil
 }
 
 // GetAuthorization2 returns the authz2 style authorization identified by the provided ID or an error.
@@ -1518,7 +1918,12 @@ func (ssa *SQLStorageAuthority) FinalizeAuthorization2(ctx context.Context, req
 		"validationError": veJSON,
 	}
 
-	res, err := ssa.dbMap.Exec(query, params)
+	var res sql.Result
+	err = ssa.retryWrite(ctx, func() error {
+		var err error
+		res, err = ssa.dbMap.Exec(query, params)
+		return err
+	})
 	if err != nil {
 		return err
 	}
@@ -1538,22 +1943,27 @@ func (ssa *SQLStorageAuthority) FinalizeAuthorization2(ctx context.Context, req
 // information if the certificate is not already marked as revoked.
 func (ssa *SQLStorageAuthority) RevokeCertificate(ctx context.Context, req *sapb.RevokeCertificateRequest) error {
 	revokedDate := time.Unix(0, req.Date)
-	res, err := ssa.dbMap.Exec(
-		`UPDATE certificateStatus SET
-				status = ?,
-				revokedReason = ?,
-				revokedDate = ?,
-				ocspLastUpdated = ?,
-				ocspResponse = ?
-			WHERE serial = ? AND status != ?`,
-		string(core.OCSPStatusRevoked),
-		revocation.Reason(req.Reason),
-		revokedDate,
-		revokedDate,
-		req.Response,
-		req.Serial,
-		string(core.OCSPStatusRevoked),
-	)
+	var res sql.Result
+	err := ssa.retryWrite(ctx, func() error {
+		var err error
+		res, err = ssa.dbMap.Exec(
+			`UPDATE certificateStatus SET
+					status = ?,
+					revokedReason = ?,
+					revokedDate = ?,
+					ocspLastUpdated = ?,
+					ocspResponse = ?
+				WHERE serial = ? AND status != ?`,
+			string(core.OCSPStatusRevoked),
+			revocation.Reason(req.Reason),
+			revokedDate,
+			revokedDate,
+			req.Response,
+			req.Serial,
+			string(core.OCSPStatusRevoked),
+		)
+		return err
+	})
 	if err != nil {
 		return err
 	}
@@ -1650,7 +2060,7 @@ func (ssa *SQLStorageAuthority) GetValidOrderAuthorizations2(ctx context.Context
 
 	byName := make(map[string]authzModel)
 	for _, am := range ams {
-		if uintToIdentifierType[am.IdentifierType] != string(identifier.DNS) {
+		if _, ok := uintToIdentifierType[am.IdentifierType]; !ok {
 			return nil, fmt.Errorf("unknown identifier type: %q on authz id %d", am.IdentifierType, am.ID)
 		}
 		existing, present := byName[am.IdentifierValue]
@@ -1778,10 +2188,13 @@ func (ssa *SQLStorageAuthority) AddBlockedKey(ctx context.Context, req *sapb.Add
 		qs += ", ?"
 		vals = append(vals, req.RevokedBy)
 	}
-	_, err := ssa.dbMap.Exec(
-		fmt.Sprintf("INSERT INTO blockedKeys (%s) VALUES (%s)", cols, qs),
-		vals...,
-	)
+	err := ssa.retryWrite(ctx, func() error {
+		_, err := ssa.dbMap.Exec(
+			fmt.Sprintf("INSERT INTO blockedKeys (%s) VALUES (%s)", cols, qs),
+			vals...,
+		)
+		return err
+	})
 	if err != nil {
 		if db.IsDuplicate(err) {
 			// Ignore duplicate inserts so multiple certs with the same key can
@@ -1,6 +1,7 @@
 package sa
 
 import (
+	"bytes"
 	"context"
 	"crypto/rand"
 	"crypto/rsa"
@@ -13,6 +14,8 @@ import (
 	"math/bits"
 	"net"
 	"reflect"
+	"sort"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -33,6 +36,7 @@ import (
 	"github.com/letsencrypt/boulder/sa/satest"
 	"github.com/letsencrypt/boulder/test"
 	"github.com/letsencrypt/boulder/test/vars"
+	"google.golang.org/grpc"
 	jose "gopkg.in/square/go-jose.v2"
 )
 
@@ -52,7 +56,7 @@ func initSA(t *testing.T) (*SQLStorageAuthority, clock.FakeClock, func()) {
 	fc := clock.NewFake()
 	fc.Set(time.Date(2015, 3, 4, 5, 0, 0, 0, time.UTC))
 
-	sa, err := NewSQLStorageAuthority(dbMap, fc, log, metrics.NoopRegisterer, 1)
+	sa, err := NewSQLStorageAuthority(dbMap, fc, log, metrics.NoopRegisterer, 1, 0, nil)
 	if err != nil {
 		t.Fatalf("Failed to create SA: %s", err)
 	}
@@ -673,6 +677,68 @@ func TestPreviousCertificateExists(t *testing.T) {
 	}
 }
 
+func TestReplacementCertificateExists(t *testing.T) {
+	sa, fc, cleanUp := initSA(t)
+	defer cleanUp()
+
+	reg := satest.CreateWorkingRegistration(t, sa)
+	otherReg := satest.CreateWorkingRegistration(t, sa)
+	testKey, err := rsa.GenerateKey(rand.Reader, 512)
+	test.AssertNotError(t, err, "error generating test key")
+
+	addCert := func(serial int64, names []string, notBefore time.Time, regID int64) string {
+		template := &x509.Certificate{
+			SerialNumber: big.NewInt(serial),
+			DNSNames:     names,
+			NotBefore:    notBefore,
+			NotAfter:     notBefore.Add(90 * 24 * time.Hour),
+		}
+		der, err := x509.CreateCertificate(rand.Reader, template, template, testKey.Public(), testKey)
+		test.AssertNotError(t, err, "Failed to create test cert")
+		_, err = sa.AddPrecertificate(ctx, &sapb.AddCertificateRequest{
+			Der:      der,
+			Issued:   notBefore.UnixNano(),
+			RegID:    regID,
+			IssuerID: 1,
+		})
+		test.AssertNotError(t, err, "Failed to add precertificate")
+		_, err = sa.AddCertificate(ctx, der, regID, nil, &notBefore)
+		test.AssertNotError(t, err, "Failed to add certificate")
+		return core.SerialToString(big.NewInt(serial))
+	}
+
+	original := fc.Now().Add(-48 * time.Hour)
+	names := []string{"example.com", "www.example.com"}
+	serial := addCert(1, names, original, reg.ID)
+	replaced := func() bool {
+		exists, err := sa.ReplacementCertificateExists(ctx, &sapb.ReplacementCertificateExistsRequest{
+			Serial:      serial,
+			RegID:       reg.ID,
+			DnsNames:    names,
+			IssuedAfter: original.UnixNano(),
+		})
+		test.AssertNotError(t, err, "ReplacementCertificateExists failed")
+		return exists.Exists
+	}
+	test.Assert(t, !replaced(), "certificate is its own replacement")
+
+	// Neither a certificate covering only some of the names nor one issued to
+	// another account is a replacement.
+	addCert(2, []string{"example.com"}, original.Add(time.Hour), reg.ID)
+	addCert(3, names, original.Add(time.Hour), otherReg.ID)
+	test.Assert(t, !replaced(), "unexpected replacement")
+
+	// A certificate from the same account covering all of the names, and
+	// more, is.
+	replacement := addCert(4, append(names, "mail.example.com"), original.Add(2*time.Hour), reg.ID)
+	test.Assert(t, replaced(), "replacement not found")
+
+	// Unless it has been revoked.
+	_, err = sa.dbMap.Exec("UPDATE certificateStatus SET status = ? WHERE serial = ?", string(core.OCSPStatusRevoked), replacement)
+	test.AssertNotError(t, err, "revoking replacement")
+	test.Assert(t, !replaced(), "revoked certificate treated as a replacement")
+}
+
 func TestDeactivateAuthorization2(t *testing.T) {
 	sa, fc, cleanUp := initSA(t)
 	defer cleanUp()
@@ -703,6 +769,55 @@ func TestDeactivateAccount(t *testing.T) {
 	test.AssertEquals(t, dbReg.Status, core.StatusDeactivated)
 }
 
+func TestDeactivateAccountAuthorizations(t *testing.T) {
+	sa, fc, cleanUp := initSA(t)
+	defer cleanUp()
+
+	reg := satest.CreateWorkingRegistration(t, sa)
+	exp := fc.Now().Add(time.Hour)
+	pendingID := createPendingAuthorization(t, sa, "example.com", exp)
+	validID := createFinalizedAuthorization(t, sa, "example.net", exp, "valid")
+
+	err := sa.DeactivateRegistration(context.Background(), reg.ID)
+	test.AssertNotError(t, err, "DeactivateRegistration failed")
+
+	// Pending authorizations are deactivated, so that any pending orders
+	// using them become invalid.
+	pending, err := sa.GetAuthorization2(context.Background(), &sapb.AuthorizationID2{Id: pendingID})
+	test.AssertNotError(t, err, "GetAuthorization2 failed")
+	test.AssertEquals(t, pending.Status, string(core.StatusDeactivated))
+	valid, err := sa.GetAuthorization2(context.Background(), &sapb.AuthorizationID2{Id: validID})
+	test.AssertNotError(t, err, "GetAuthorization2 failed")
+	test.AssertEquals(t, valid.Status, string(core.StatusValid))
+}
+
+func TestGetSerialsByAccount(t *testing.T) {
+	sa, fc, cleanUp := initSA(t)
+	defer cleanUp()
+
+	reg := satest.CreateWorkingRegistration(t, sa)
+	// Test cert generated locally by Boulder / CFSSL, names [example.com,
+	// www.example.com, admin.example.com]
+	certDER, err := ioutil.ReadFile("test-cert.der")
+	test.AssertNotError(t, err, "Couldn't read example cert DER")
+	cert, err := x509.ParseCertificate(certDER)
+	test.AssertNotError(t, err, "Couldn't parse example cert DER")
+	issued := cert.NotBefore
+	_, err = sa.AddCertificate(ctx, certDER, reg.ID, nil, &issued)
+	test.AssertNotError(t, err, "Couldn't add test-cert.der")
+
+	fc.Set(cert.NotBefore.Add(time.Hour))
+	serials, err := sa.GetSerialsByAccount(ctx, &sapb.RegistrationID{Id: reg.ID})
+	test.AssertNotError(t, err, "GetSerialsByAccount failed")
+	test.AssertDeepEquals(t, serials.Serials, []string{core.SerialToString(cert.SerialNumber)})
+
+	// Expired certificates aren't returned.
+	fc.Set(cert.NotAfter.Add(time.Hour))
+	serials, err = sa.GetSerialsByAccount(ctx, &sapb.RegistrationID{Id: reg.ID})
+	test.AssertNotError(t, err, "GetSerialsByAccount failed")
+	test.AssertEquals(t, len(serials.Serials), 0)
+}
+
 func TestReverseName(t *testing.T) {
 	testCases := []struct {
 		inputDomain   string
@@ -945,6 +1060,81 @@ func TestNewOrder(t *testing.T) {
 	test.AssertDeepEquals(t, names, []string{"com.example", "com.example.another.just"})
 }
 
+func TestNewOrderAndAuthzs(t *testing.T) {
+	sa, fc, cleanup := initSA(t)
+	defer cleanup()
+
+	// Use a small batch size so that the authorizations are split across
+	// several multi-row inserts.
+	sa.maxInsertBatchSize = 2
+
+	reg := satest.CreateWorkingRegistration(t, sa)
+	expires := fc.Now().Add(time.Hour).UTC().UnixNano()
+
+	var newAuthzs []*corepb.Authorization
+	var names []string
+	for i, token := range []string{
+		"YXNkAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA",
+		"ZmdoAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA",
+		"aGprAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA",
+	} {
+		name := fmt.Sprintf("%d.example.com", i)
+		names = append(names, name)
+		newAuthzs = append(newAuthzs, &corepb.Authorization{
+			Identifier:     name,
+			RegistrationID: reg.ID,
+			Status:         string(core.StatusPending),
+			Expires:        expires,
+			Challenges: []*corepb.Challenge{
+				{
+					Status: string(core.StatusPending),
+					Type:   string(core.ChallengeTypeDNS01),
+					Token:  token,
+				},
+			},
+		})
+	}
+
+	order, err := sa.NewOrderAndAuthzs(context.Background(), &sapb.NewOrderAndAuthzsRequest{
+		NewOrder: &corepb.Order{
+			RegistrationID: reg.ID,
+			Expires:        expires,
+			Names:          names,
+		},
+		NewAuthzs: newAuthzs,
+	})
+	test.AssertNotError(t, err, "sa.NewOrderAndAuthzs failed")
+	test.AssertEquals(t, len(order.V2Authorizations), 3)
+	test.AssertEquals(t, order.Status, string(core.StatusPending))
+
+	var authzIDs []int64
+	_, err = sa.dbMap.Select(&authzIDs, "SELECT authzID FROM orderToAuthz2 WHERE orderID = ? ORDER BY authzID;", order.Id)
+	test.AssertNotError(t, err, "Failed to select orderToAuthz2 entries")
+	test.AssertDeepEquals(t, authzIDs, order.V2Authorizations)
+
+	for i, id := range order.V2Authorizations {
+		authz, err := sa.GetAuthorization2(context.Background(), &sapb.AuthorizationID2{Id: id})
+		test.AssertNotError(t, err, "sa.GetAuthorization2 failed")
+		test.AssertEquals(t, authz.Identifier, names[i])
+	}
+
+	reversed, err := sa.namesForOrder(context.Background(), order.Id)
+	test.AssertNotError(t, err, "namesForOrder errored")
+	test.AssertEquals(t, len(reversed), 3)
+
+	// A non-pending authorization must cause the whole order to be rejected.
+	newAuthzs[0].Status = string(core.StatusValid)
+	_, err = sa.NewOrderAndAuthzs(context.Background(), &sapb.NewOrderAndAuthzsRequest{
+		NewOrder: &corepb.Order{
+			RegistrationID: reg.ID,
+			Expires:        expires,
+			Names:          names,
+		},
+		NewAuthzs: newAuthzs,
+	})
+	test.AssertError(t, err, "sa.NewOrderAndAuthzs with a valid authz should fail")
+}
+
 func TestSetOrderProcessing(t *testing.T) {
 	sa, fc, cleanup := initSA(t)
 	defer cleanup()
@@ -988,6 +1178,22 @@ func TestSetOrderProcessing(t *testing.T) {
 	err = sa.SetOrderProcessing(context.Background(), order)
 	test.AssertError(t, err, "Set the same order processing twice. This should have been an error.")
 	test.AssertErrorIs(t, err, berrors.OrderNotReady)
+
+	// An order with an authorization which has been deactivated since the
+	// order was read can't be set processing.
+	authzID = createFinalizedAuthorization(t, sa, "example.net", expires, "valid")
+	order, err = sa.NewOrder(ctx, &corepb.Order{
+		RegistrationID:   reg.ID,
+		Expires:          sa.clk.Now().Add(365 * 24 * time.Hour).UnixNano(),
+		Names:            []string{"example.net"},
+		V2Authorizations: []int64{authzID},
+	})
+	test.AssertNotError(t, err, "NewOrder failed")
+	_, err = sa.DeactivateAuthorization2(ctx, &sapb.AuthorizationID2{Id: authzID})
+	test.AssertNotError(t, err, "DeactivateAuthorization2 failed")
+	err = sa.SetOrderProcessing(ctx, order)
+	test.AssertError(t, err, "Set an order with a deactivated authorization processing")
+	test.AssertErrorIs(t, err, berrors.OrderNotReady)
 }
 
 func TestFinalizeOrder(t *testing.T) {
@@ -1601,6 +1807,98 @@ func TestRevokeCertificate(t *testing.T) {
 	test.AssertError(t, err, "RevokeCertificate should've failed when certificate already revoked")
 }
 
+func TestGetCertificateStatuses(t *testing.T) {
+	sa, _, cleanUp := initSA(t)
+	defer cleanUp()
+
+	reg := satest.CreateWorkingRegistration(t, sa)
+	certDER, err := ioutil.ReadFile("www.eff.org.der")
+	test.AssertNotError(t, err, "Couldn't read example cert DER")
+	_, err = sa.AddPrecertificate(ctx, &sapb.AddCertificateRequest{
+		Der:      certDER,
+		RegID:    reg.ID,
+		Issued:   sa.clk.Now().UnixNano(),
+		IssuerID: 1,
+	})
+	test.AssertNotError(t, err, "Couldn't add www.eff.org.der")
+	serial := "000000000000000000000000000000021bd4"
+
+	// Unknown serials are omitted, and repeated ones returned once.
+	statuses, err := sa.GetCertificateStatuses(ctx, &sapb.Serials{
+		Serials: []string{serial, "000000000000000000000000000000000001", serial},
+	})
+	test.AssertNotError(t, err, "GetCertificateStatuses failed")
+	test.AssertEquals(t, len(statuses.Statuses), 1)
+	test.AssertEquals(t, statuses.Statuses[0].Serial, serial)
+	test.AssertEquals(t, statuses.Statuses[0].Status, string(core.OCSPStatusGood))
+
+	_, err = sa.GetCertificateStatuses(ctx, &sapb.Serials{Serials: []string{"not a serial"}})
+	test.AssertError(t, err, "GetCertificateStatuses accepted an invalid serial")
+
+	tooMany := make([]string, MaxCertificateStatusesPerRequest+1)
+	for i := range tooMany {
+		tooMany[i] = serial
+	}
+	_, err = sa.GetCertificateStatuses(ctx, &sapb.Serials{Serials: tooMany})
+	test.AssertErrorIs(t, err, berrors.Malformed)
+}
+
+// certificateStatusStream collects the rows sent by
+// GetCertificateStatusesByID.
+type certificateStatusStream struct {
+	grpc.ServerStream
+	rows []*sapb.CertificateStatusRow
+}
+
+func (s *certificateStatusStream) Context() context.Context {
+	return ctx
+}
+
+func (s *certificateStatusStream) Send(row *sapb.CertificateStatusRow) error {
+	s.rows = append(s.rows, row)
+	return nil
+}
+
+func TestGetCertificateStatusesByID(t *testing.T) {
+	sa, fc, cleanUp := initSA(t)
+	defer cleanUp()
+	defer func(size int) { certificateStatusStreamBatchSize = size }(certificateStatusStreamBatchSize)
+	certificateStatusStreamBatchSize = 2
+
+	maxID, err := sa.GetMaxCertificateStatusID(ctx, &corepb.Empty{})
+	test.AssertNotError(t, err, "GetMaxCertificateStatusID failed")
+	test.AssertEquals(t, maxID.Id, int64(0))
+
+	issuerID := int64(1)
+	for i := 0; i < 5; i++ {
+		err = sa.dbMap.Insert(&core.CertificateStatus{
+			Serial:          fmt.Sprintf("%036x", i+1),
+			Status:          core.OCSPStatusGood,
+			OCSPLastUpdated: fc.Now(),
+			OCSPResponse:    []byte{},
+			NotAfter:        fc.Now().Add(time.Hour),
+			IssuerID:        &issuerID,
+		})
+		test.AssertNotError(t, err, "inserting certificate status")
+	}
+	maxID, err = sa.GetMaxCertificateStatusID(ctx, &corepb.Empty{})
+	test.AssertNotError(t, err, "GetMaxCertificateStatusID failed")
+
+	// Every row in the range is sent, in order, across several batches.
+	stream := &certificateStatusStream{}
+	err = sa.GetCertificateStatusesByID(&sapb.CertificateStatusesByIDRequest{AfterID: maxID.Id - 4, MaxID: maxID.Id - 1}, stream)
+	test.AssertNotError(t, err, "GetCertificateStatusesByID failed")
+	test.AssertEquals(t, len(stream.rows), 3)
+	for i, row := range stream.rows {
+		test.AssertEquals(t, row.Id, maxID.Id-3+int64(i))
+		test.AssertEquals(t, row.Status.Serial, fmt.Sprintf("%036x", i+2))
+		test.AssertEquals(t, row.Status.IssuerID, issuerID)
+	}
+
+	err = sa.GetCertificateStatusesByID(&sapb.CertificateStatusesByIDRequest{AfterID: 2, MaxID: 2}, stream)
+	test.AssertError(t, err, "GetCertificateStatusesByID accepted an empty range")
+}
+
 func TestAddCertificateRenewalBit(t *testing.T) {
 	sa, fc, cleanUp := initSA(t)
 	defer cleanUp()
@@ -2287,3 +2585,606 @@ func TestBlockedKeyRevokedBy(t *testing.T) {
 	})
 	test.AssertNotError(t, err, "AddBlockedKey failed")
 }
+
+func TestGetSerialsByKey(t *testing.T) {
+	sa, fc, cleanUp := initSA(t)
+	defer cleanUp()
+
+	hashA := make([]byte, 32)
+	hashA[0] = 1
+	hashB := make([]byte, 32)
+	hashB[0] = 2
+	for _, khm := range []*keyHashModel{
+		{KeyHash: hashA, CertNotAfter: fc.Now().Add(time.Hour), CertSerial: "aa"},
+		{KeyHash: hashA, CertNotAfter: fc.Now().Add(-time.Hour), CertSerial: "bb"},
+		{KeyHash: hashA, CertNotAfter: fc.Now().Add(time.Hour), CertSerial: "cc"},
+		{KeyHash: hashB, CertNotAfter: fc.Now().Add(time.Hour), CertSerial: "dd"},
+	} {
+		err := sa.dbMap.Insert(khm)
+		test.AssertNotError(t, err, "failed to insert keyHashToSerial row")
+	}
+
+	// Expired certificates aren't returned.
+	serials, err := sa.GetSerialsByKey(ctx, &sapb.SPKIHash{KeyHash: hashA})
+	test.AssertNotError(t, err, "GetSerialsByKey failed")
+	sort.Strings(serials.Serials)
+	test.AssertDeepEquals(t, serials.Serials, []string{"aa", "cc"})
+
+	serials, err = sa.GetSerialsByKey(ctx, &sapb.SPKIHash{KeyHash: []byte{5}})
+	test.AssertNotError(t, err, "GetSerialsByKey failed")
+	test.AssertEquals(t, len(serials.Serials), 0)
+
+	_, err = sa.GetSerialsByKey(ctx, &sapb.SPKIHash{})
+	test.AssertError(t, err, "GetSerialsByKey didn't fail with an empty key hash")
+}
+
+func TestIncidents(t *testing.T) {
+	sa, fc, cleanUp := initSA(t)
+	defer cleanUp()
+
+	ctx := context.Background()
+	renewBy := fc.Now().Add(72 * time.Hour)
+	incident, err := sa.AddIncident(ctx, &sapb.AddIncidentRequest{
+		Name:    "incident-1",
+		Url:     "https://example.com/incident-1",
+		RenewBy: renewBy.UnixNano(),
+	})
+	test.AssertNotError(t, err, "AddIncident failed")
+	test.Assert(t, incident.Id != 0, "AddIncident didn't return an ID")
+	test.Assert(t, !incident.Enabled, "new incident should be disabled")
+
+	_, err = sa.AddIncident(ctx, &sapb.AddIncidentRequest{
+		Name:    "incident-1",
+		Url:     "https://example.com/incident-1",
+		RenewBy: renewBy.UnixNano(),
+	})
+	test.AssertErrorIs(t, err, berrors.Duplicate)
+
+	serials := []*sapb.IncidentSerial{
+		{Serial: "1337"},
+		{Serial: "1338", RegistrationID: 1},
+	}
+	_, err = sa.AddIncidentSerials(ctx, &sapb.AddIncidentSerialsRequest{IncidentID: incident.Id, Serials: serials})
+	test.AssertNotError(t, err, "AddIncidentSerials failed")
+	// Re-importing the same serials should be a no-op.
+	_, err = sa.AddIncidentSerials(ctx, &sapb.AddIncidentSerialsRequest{IncidentID: incident.Id, Serials: serials})
+	test.AssertNotError(t, err, "AddIncidentSerials failed for duplicate serials")
+
+	_, err = sa.AddIncidentSerials(ctx, &sapb.AddIncidentSerialsRequest{IncidentID: incident.Id + 1, Serials: serials})
+	test.AssertErrorIs(t, err, berrors.NotFound)
+
+	// Serials of a disabled incident aren't reported.
+	incidents, err := sa.IncidentsForSerial(ctx, &sapb.Serial{Serial: "1337"})
+	test.AssertNotError(t, err, "IncidentsForSerial failed")
+	test.AssertEquals(t, len(incidents.Incidents), 0)
+
+	_, err = sa.SetIncidentStatus(ctx, &sapb.SetIncidentStatusRequest{IncidentID: incident.Id, Enabled: true})
+	test.AssertNotError(t, err, "SetIncidentStatus failed")
+
+	incidents, err = sa.IncidentsForSerial(ctx, &sapb.Serial{Serial: "1338"})
+	test.AssertNotError(t, err, "IncidentsForSerial failed")
+	test.AssertEquals(t, len(incidents.Incidents), 1)
+	test.AssertEquals(t, incidents.Incidents[0].Name, "incident-1")
+
+	incidents, err = sa.IncidentsForSerial(ctx, &sapb.Serial{Serial: "1339"})
+	test.AssertNotError(t, err, "IncidentsForSerial failed")
+	test.AssertEquals(t, len(incidents.Incidents), 0)
+
+	// Resolved incidents aren't reported, and can't have serials added.
+	_, err = sa.SetIncidentStatus(ctx, &sapb.SetIncidentStatusRequest{IncidentID: incident.Id, Enabled: true, Resolved: true})
+	test.AssertNotError(t, err, "SetIncidentStatus failed")
+	incidents, err = sa.IncidentsForSerial(ctx, &sapb.Serial{Serial: "1337"})
+	test.AssertNotError(t, err, "IncidentsForSerial failed")
+	test.AssertEquals(t, len(incidents.Incidents), 0)
+	_, err = sa.AddIncidentSerials(ctx, &sapb.AddIncidentSerialsRequest{IncidentID: incident.Id, Serials: serials})
+	test.AssertErrorIs(t, err, berrors.Malformed)
+}
+
+func TestNotificationPreferences(t *testing.T) {
+	sa, _, cleanUp := initSA(t)
+	defer cleanUp()
+
+	reg := satest.CreateWorkingRegistration(t, sa)
+	_, err := sa.GetNotificationPreferences(ctx, &sapb.RegistrationID{Id: reg.ID})
+	test.AssertErrorIs(t, err, berrors.NotFound)
+
+	secret := bytes.Repeat([]byte{1}, 32)
+	_, err = sa.SetNotificationPreferences(ctx, &sapb.NotificationPreferences{
+		RegistrationID: reg.ID,
+		WebhookURL:     "https://example.com/hook",
+		WebhookSecret:  secret,
+	})
+	test.AssertNotError(t, err, "SetNotificationPreferences failed")
+	prefs, err := sa.GetNotificationPreferences(ctx, &sapb.RegistrationID{Id: reg.ID})
+	test.AssertNotError(t, err, "GetNotificationPreferences failed")
+	test.AssertEquals(t, prefs.WebhookURL, "https://example.com/hook")
+	test.AssertByteEquals(t, prefs.WebhookSecret, secret)
+
+	// Clearing the URL disables the webhook and discards the secret.
+	_, err = sa.SetNotificationPreferences(ctx, &sapb.NotificationPreferences{RegistrationID: reg.ID})
+	test.AssertNotError(t, err, "SetNotificationPreferences failed")
+	prefs, err = sa.GetNotificationPreferences(ctx, &sapb.RegistrationID{Id: reg.ID})
+	test.AssertNotError(t, err, "GetNotificationPreferences failed")
+	test.AssertEquals(t, prefs.WebhookURL, "")
+	test.AssertEquals(t, len(prefs.WebhookSecret), 0)
+
+	// Reminder schedules are stored sorted.
+	_, err = sa.SetNotificationPreferences(ctx, &sapb.NotificationPreferences{
+		RegistrationID: reg.ID,
+		EmailOptOut:    true,
+		NagTimes:       []int64{int64(72 * time.Hour), int64(24 * time.Hour)},
+		Email:          "ops@example.com",
+	})
+	test.AssertNotError(t, err, "SetNotificationPreferences failed")
+	prefs, err = sa.GetNotificationPreferences(ctx, &sapb.RegistrationID{Id: reg.ID})
+	test.AssertNotError(t, err, "GetNotificationPreferences failed")
+	test.Assert(t, prefs.EmailOptOut, "EmailOptOut not stored")
+	test.AssertDeepEquals(t, prefs.NagTimes, []int64{int64(24 * time.Hour), int64(72 * time.Hour)})
+	test.AssertEquals(t, prefs.Email, "ops@example.com")
+	var nagTimes []int64
+	_, err = sa.dbMap.Select(&nagTimes, "SELECT nagTime FROM notificationNagTimes WHERE registrationID = ? ORDER BY nagTime", reg.ID)
+	test.AssertNotError(t, err, "selecting nag times")
+	test.AssertDeepEquals(t, nagTimes, []int64{24 * 60 * 60, 72 * 60 * 60})
+
+	for _, bad := range []*sapb.NotificationPreferences{
+		{RegistrationID: reg.ID, WebhookURL: "http://example.com/hook", WebhookSecret: secret},
+		{RegistrationID: reg.ID, WebhookURL: "https://example.com/" + strings.Repeat("a", 255), WebhookSecret: secret},
+		{RegistrationID: reg.ID, WebhookURL: "https://example.com/hook"},
+		{RegistrationID: reg.ID, WebhookURL: "https://127.0.0.1/hook", WebhookSecret: secret},
+		{RegistrationID: reg.ID, WebhookURL: "https://[fe80::1]:8443/hook", WebhookSecret: secret},
+		{RegistrationID: reg.ID, NagTimes: []int64{0}},
+		{RegistrationID: reg.ID, NagTimes: []int64{int64(time.Millisecond)}},
+		{RegistrationID: reg.ID, NagTimes: []int64{int64(100 * 24 * time.Hour)}},
+		{RegistrationID: reg.ID, Email: "Ops <ops@example.com>"},
+		{RegistrationID: reg.ID, WebhookURL: "https://example.com/hook", WebhookSecret: []byte("enc1:0123456789abcdef")},
+	} {
+		_, err = sa.SetNotificationPreferences(ctx, bad)
+		test.AssertErrorIs(t, err, berrors.Malformed)
+	}
+
+	// With contact encryption configured, the secret is stored encrypted.
+	contactCipher, err := NewContactCipher(map[string][]byte{"test": bytes.Repeat([]byte{1}, 32)}, "test")
+	test.AssertNotError(t, err, "NewContactCipher failed")
+	sa.contactCipher = contactCipher
+	_, err = sa.SetNotificationPreferences(ctx, &sapb.NotificationPreferences{
+		RegistrationID: reg.ID,
+		WebhookURL:     "https://example.com/hook",
+		WebhookSecret:  secret,
+	})
+	test.AssertNotError(t, err, "SetNotificationPreferences failed")
+	var stored []byte
+	err = sa.dbMap.SelectOne(&stored, "SELECT webhookSecret FROM notificationPreferences WHERE registrationID = ?", reg.ID)
+	test.AssertNotError(t, err, "selecting stored secret")
+	test.Assert(t, bytes.HasPrefix(stored, []byte("enc1:test:")), "webhook secret wasn't stored encrypted")
+	prefs, err = sa.GetNotificationPreferences(ctx, &sapb.RegistrationID{Id: reg.ID})
+	test.AssertNotError(t, err, "GetNotificationPreferences failed")
+	test.AssertByteEquals(t, prefs.WebhookSecret, secret)
+	sa.contactCipher = nil
+	_, err = sa.GetNotificationPreferences(ctx, &sapb.RegistrationID{Id: reg.ID})
+	test.AssertError(t, err, "read an encrypted webhook secret without a cipher")
+}
+
+func TestValidateNagTimes(t *testing.T) {
+	nagTimes, err := validateNagTimes([]int64{
+		int64(72 * time.Hour),
+		int64(24*time.Hour + 500*time.Millisecond),
+		int64(24 * time.Hour),
+	})
+	test.AssertNotError(t, err, "validateNagTimes failed")
+	test.AssertDeepEquals(t, nagTimes, []time.Duration{24 * time.Hour, 72 * time.Hour})
+
+	_, err = validateNagTimes(make([]int64, maxNagTimes+1))
+	test.AssertErrorIs(t, err, berrors.Malformed)
+}
+
+func TestFeatureOverrides(t *testing.T) {
+	sa, _, cleanUp := initSA(t)
+	defer cleanUp()
+
+	reg := satest.CreateWorkingRegistration(t, sa)
+	overrides, err := sa.GetFeatureOverrides(ctx, &sapb.RegistrationID{Id: reg.ID})
+	test.AssertNotError(t, err, "GetFeatureOverrides failed")
+	test.AssertEquals(t, len(overrides.Overrides), 0)
+
+	for _, req := range []*sapb.FeatureOverrideRequest{
+		{RegistrationID: reg.ID, Feature: "EnforceMultiVA", Enabled: true},
+		{RegistrationID: reg.ID, Feature: "StreamlineOrderAndAuthzs", Enabled: true},
+		{RegistrationID: reg.ID, Feature: "StreamlineOrderAndAuthzs", Enabled: false},
+	} {
+		_, err = sa.SetFeatureOverride(ctx, req)
+		test.AssertNotError(t, err, "SetFeatureOverride failed")
+	}
+	overrides, err = sa.GetFeatureOverrides(ctx, &sapb.RegistrationID{Id: reg.ID})
+	test.AssertNotError(t, err, "GetFeatureOverrides failed")
+	test.AssertDeepEquals(t, overrides.Overrides, []*sapb.FeatureOverride{
+		{Feature: "EnforceMultiVA", Enabled: true},
+		{Feature: "StreamlineOrderAndAuthzs", Enabled: false},
+	})
+
+	_, err = sa.ClearFeatureOverride(ctx, &sapb.FeatureOverrideRequest{RegistrationID: reg.ID, Feature: "EnforceMultiVA"})
+	test.AssertNotError(t, err, "ClearFeatureOverride failed")
+	_, err = sa.ClearFeatureOverride(ctx, &sapb.FeatureOverrideRequest{RegistrationID: reg.ID, Feature: "EnforceMultiVA"})
+	test.AssertErrorIs(t, err, berrors.NotFound)
+	overrides, err = sa.GetFeatureOverrides(ctx, &sapb.RegistrationID{Id: reg.ID})
+	test.AssertNotError(t, err, "GetFeatureOverrides failed")
+	test.AssertEquals(t, len(overrides.Overrides), 1)
+
+	_, err = sa.SetFeatureOverride(ctx, &sapb.FeatureOverrideRequest{RegistrationID: reg.ID, Feature: "NoSuchFeature", Enabled: true})
+	test.AssertErrorIs(t, err, berrors.Malformed)
+	_, err = sa.SetFeatureOverride(ctx, &sapb.FeatureOverrideRequest{RegistrationID: reg.ID, Feature: "CAAAccountURI", Enabled: true})
+	test.AssertErrorIs(t, err, berrors.Malformed)
+}
+
+func TestHostnamePolicies(t *testing.T) {
+	sa, fc, cleanUp := initSA(t)
+	defer cleanUp()
+
+	_, err := sa.GetHostnamePolicy(ctx, &sapb.GetHostnamePolicyRequest{})
+	test.AssertErrorIs(t, err, berrors.NotFound)
+
+	first := &sapb.HostnamePolicy{
+		HighRiskBlockedNames: []string{"example.org"},
+		ExactBlockedNames:    []string{"highvalue.example.net"},
+		CreatedBy:            "operator",
+		Comment:              "initial policy",
+	}
+	stored, err := sa.AddHostnamePolicy(ctx, first)
+	test.AssertNotError(t, err, "AddHostnamePolicy failed")
+	test.Assert(t, stored.Version > 0, "AddHostnamePolicy didn't assign a version")
+	test.AssertEquals(t, stored.Created, fc.Now().UnixNano())
+
+	latest, err := sa.GetHostnamePolicy(ctx, &sapb.GetHostnamePolicyRequest{})
+	test.AssertNotError(t, err, "GetHostnamePolicy failed")
+	test.AssertEquals(t, latest.Version, stored.Version)
+	test.AssertDeepEquals(t, latest.HighRiskBlockedNames, first.HighRiskBlockedNames)
+	test.AssertDeepEquals(t, latest.ExactBlockedNames, first.ExactBlockedNames)
+	test.AssertEquals(t, latest.CreatedBy, "operator")
+
+	// Asking with the latest version returns only the version.
+	latest, err = sa.GetHostnamePolicy(ctx, &sapb.GetHostnamePolicyRequest{KnownVersion: stored.Version})
+	test.AssertNotError(t, err, "GetHostnamePolicy failed")
+	test.AssertEquals(t, latest.Version, stored.Version)
+	test.AssertEquals(t, len(latest.HighRiskBlockedNames), 0)
+
+	second := &sapb.HostnamePolicy{
+		HighRiskBlockedNames: []string{"example.org", "example.com"},
+		ExactBlockedNames:    []string{"highvalue.example.net"},
+		AdminBlockedNames:    []string{"blocked.example"},
+		CreatedBy:            "operator",
+	}
+	stored2, err := sa.AddHostnamePolicy(ctx, second)
+	test.AssertNotError(t, err, "AddHostnamePolicy failed")
+	test.Assert(t, stored2.Version > stored.Version, "versions should increase")
+	latest, err = sa.GetHostnamePolicy(ctx, &sapb.GetHostnamePolicyRequest{KnownVersion: stored.Version})
+	test.AssertNotError(t, err, "GetHostnamePolicy failed")
+	test.AssertEquals(t, latest.Version, stored2.Version)
+	test.AssertDeepEquals(t, latest.AdminBlockedNames, second.AdminBlockedNames)
+
+	_, err = sa.AddHostnamePolicy(ctx, &sapb.HostnamePolicy{
+		HighRiskBlockedNames: []string{"example.org"},
+		CreatedBy:            "operator",
+	})
+	test.AssertErrorIs(t, err, berrors.Malformed)
+	_, err = sa.AddHostnamePolicy(ctx, &sapb.HostnamePolicy{HighRiskBlockedNames: []string{"example.org"}})
+	test.AssertError(t, err, "AddHostnamePolicy accepted a policy without createdBy")
+}
+
+func TestEncryptedContacts(t *testing.T) {
+	sa, _, cleanUp := initSA(t)
+	defer cleanUp()
+
+	contactCipher, err := NewContactCipher(map[string][]byte{"test": bytes.Repeat([]byte{1}, 32)}, "test")
+	test.AssertNotError(t, err, "NewContactCipher failed")
+	sa.contactCipher = contactCipher
+
+	contacts := &[]string{"mailto:foo@example.com"}
+	reg, err := sa.NewRegistration(ctx, core.Registration{
+		Key:       satest.GoodJWK(),
+		Contact:   contacts,
+		InitialIP: net.ParseIP("43.34.43.34"),
+	})
+	test.AssertNotError(t, err, "NewRegistration failed")
+	test.AssertDeepEquals(t, reg.Contact, contacts)
+
+	var stored string
+	err = sa.dbMap.SelectOne(&stored, "SELECT contact FROM registrations WHERE id = ?", reg.ID)
+	test.AssertNotError(t, err, "selecting contact failed")
+	test.AssertNotContains(t, stored, "foo@example.com")
+	test.AssertContains(t, stored, encryptedContactPrefix)
+
+	dbReg, err := sa.GetRegistration(ctx, reg.ID)
+	test.AssertNotError(t, err, "GetRegistration failed")
+	test.AssertDeepEquals(t, dbReg.Contact, contacts)
+
+	newContacts := &[]string{"mailto:bar@example.com"}
+	dbReg.Contact = newContacts
+	err = sa.UpdateRegistration(ctx, dbReg)
+	test.AssertNotError(t, err, "UpdateRegistration failed")
+	dbReg, err = sa.GetRegistrationByKey(ctx, satest.GoodJWK())
+	test.AssertNotError(t, err, "GetRegistrationByKey failed")
+	test.AssertDeepEquals(t, dbReg.Contact, newContacts)
+
+	// Without the cipher, encrypted contacts can't be read.
+	sa.contactCipher = nil
+	_, err = sa.GetRegistration(ctx, reg.ID)
+	test.AssertError(t, err, "GetRegistration didn't fail without a contact cipher")
+}
+
+func TestAccountAllowlist(t *testing.T) {
+	sa, _, cleanUp := initSA(t)
+	defer cleanUp()
+
+	reg := satest.CreateWorkingRegistration(t, sa)
+	allowlist, err := sa.GetAccountAllowlist(ctx, &sapb.RegistrationID{Id: reg.ID})
+	test.AssertNotError(t, err, "GetAccountAllowlist failed")
+	test.AssertEquals(t, len(allowlist.Domains), 0)
+	test.Assert(t, !allowlist.Restricted, "new registration is restricted")
+
+	for _, domain := range []string{"example.org", "example.co.uk", "example.org"} {
+		_, err = sa.AddAccountAllowlistEntry(ctx, &sapb.AccountAllowlistEntry{RegistrationID: reg.ID, Domain: domain})
+		test.AssertNotError(t, err, "AddAccountAllowlistEntry failed")
+	}
+	allowlist, err = sa.GetAccountAllowlist(ctx, &sapb.RegistrationID{Id: reg.ID})
+	test.AssertNotError(t, err, "GetAccountAllowlist failed")
+	test.AssertDeepEquals(t, allowlist.Domains, []string{"example.co.uk", "example.org"})
+
+	for _, domain := range []string{"www.example.org", "co.uk", "Example.com"} {
+		_, err = sa.AddAccountAllowlistEntry(ctx, &sapb.AccountAllowlistEntry{RegistrationID: reg.ID, Domain: domain})
+		test.AssertErrorIs(t, err, berrors.Malformed)
+	}
+
+	_, err = sa.RemoveAccountAllowlistEntry(ctx, &sapb.AccountAllowlistEntry{RegistrationID: reg.ID, Domain: "example.org"})
+	test.AssertNotError(t, err, "RemoveAccountAllowlistEntry failed")
+	_, err = sa.RemoveAccountAllowlistEntry(ctx, &sapb.AccountAllowlistEntry{RegistrationID: reg.ID, Domain: "example.org"})
+	test.AssertErrorIs(t, err, berrors.NotFound)
+	allowlist, err = sa.GetAccountAllowlist(ctx, &sapb.RegistrationID{Id: reg.ID})
+	test.AssertNotError(t, err, "GetAccountAllowlist failed")
+	test.AssertDeepEquals(t, allowlist.Domains, []string{"example.co.uk"})
+
+	// Removing the last entry leaves the registration restricted.
+	_, err = sa.RemoveAccountAllowlistEntry(ctx, &sapb.AccountAllowlistEntry{RegistrationID: reg.ID, Domain: "example.co.uk"})
+	test.AssertNotError(t, err, "RemoveAccountAllowlistEntry failed")
+	allowlist, err = sa.GetAccountAllowlist(ctx, &sapb.RegistrationID{Id: reg.ID})
+	test.AssertNotError(t, err, "GetAccountAllowlist failed")
+	test.AssertEquals(t, len(allowlist.Domains), 0)
+	test.Assert(t, allowlist.Restricted, "registration unrestricted by removing its last allowlist entry")
+
+	_, err = sa.AddAccountAllowlistEntry(ctx, &sapb.AccountAllowlistEntry{RegistrationID: reg.ID, Domain: "example.org"})
+	test.AssertNotError(t, err, "AddAccountAllowlistEntry failed")
+	_, err = sa.ClearAccountAllowlist(ctx, &sapb.RegistrationID{Id: reg.ID})
+	test.AssertNotError(t, err, "ClearAccountAllowlist failed")
+	allowlist, err = sa.GetAccountAllowlist(ctx, &sapb.RegistrationID{Id: reg.ID})
+	test.AssertNotError(t, err, "GetAccountAllowlist failed")
+	test.AssertEquals(t, len(allowlist.Domains), 0)
+	test.Assert(t, !allowlist.Restricted, "registration restricted after clearing its allowlist")
+	_, err = sa.ClearAccountAllowlist(ctx, &sapb.RegistrationID{Id: reg.ID})
+	test.AssertErrorIs(t, err, berrors.NotFound)
+}
+
+func TestCertificateMetadata(t *testing.T) {
+	sa, fc, cleanUp := initSA(t)
+	defer cleanUp()
+
+	reg := satest.CreateWorkingRegistration(t, sa)
+	day := time.Date(2021, 2, 11, 0, 0, 0, 0, time.UTC)
+	metadata := []*sapb.CertificateMetadata{
+		{Serial: "1", KeyAlgorithm: "RSA 2048", ValidationMethods: []string{"http-01"}, Issued: day.Add(time.Hour).UnixNano()},
+		{Serial: "2", KeyAlgorithm: "RSA 2048", ValidationMethods: []string{"http-01", "dns-01"}, Issued: day.Add(2 * time.Hour).UnixNano()},
+		{Serial: "3", KeyAlgorithm: "ECDSA P-256", ValidationMethods: []string{"http-01"}, CertificateProfileName: "shortlived", Issued: day.Add(3 * time.Hour).UnixNano()},
+		{Serial: "4", KeyAlgorithm: "ECDSA P-256", ValidationMethods: []string{"dns-01"}, Issued: day.Add(25 * time.Hour).UnixNano()},
+		// Outside of the range counted below.
+		{Serial: "5", KeyAlgorithm: "ECDSA P-256", ValidationMethods: []string{"dns-01"}, Issued: day.Add(49 * time.Hour).UnixNano()},
+	}
+	for _, m := range metadata {
+		m.RegistrationID = reg.ID
+		_, err := sa.AddCertificateMetadata(ctx, m)
+		test.AssertNotError(t, err, "AddCertificateMetadata failed")
+	}
+	_, err := sa.AddCertificateMetadata(ctx, metadata[0])
+	test.AssertErrorIs(t, err, berrors.Duplicate)
+	_, err = sa.AddCertificateMetadata(ctx, &sapb.CertificateMetadata{Serial: "6", RegistrationID: reg.ID})
+	test.AssertError(t, err, "AddCertificateMetadata accepted incomplete metadata")
+
+	dayRange := &sapb.Range{Earliest: day.UnixNano(), Latest: day.Add(48 * time.Hour).UnixNano()}
+	counts, err := sa.GetIssuanceCounts(ctx, &sapb.IssuanceCountsRequest{Range: dayRange})
+	test.AssertNotError(t, err, "GetIssuanceCounts failed")
+	test.AssertDeepEquals(t, counts.Counts, []*sapb.IssuanceCount{{Count: 4}})
+
+	counts, err = sa.GetIssuanceCounts(ctx, &sapb.IssuanceCountsRequest{
+		Range:   dayRange,
+		GroupBy: []string{"keyAlgorithm", "day"},
+	})
+	test.AssertNotError(t, err, "GetIssuanceCounts failed")
+	test.AssertDeepEquals(t, counts.Counts, []*sapb.IssuanceCount{
+		{Day: "2021-02-11", KeyAlgorithm: "ECDSA P-256", Count: 1},
+		{Day: "2021-02-11", KeyAlgorithm: "RSA 2048", Count: 2},
+		{Day: "2021-02-12", KeyAlgorithm: "ECDSA P-256", Count: 1},
+	})
+
+	counts, err = sa.GetIssuanceCounts(ctx, &sapb.IssuanceCountsRequest{
+		Range:   dayRange,
+		GroupBy: []string{"certificateProfileName", "validationMethods"},
+	})
+	test.AssertNotError(t, err, "GetIssuanceCounts failed")
+	test.AssertDeepEquals(t, counts.Counts, []*sapb.IssuanceCount{
+		{ValidationMethods: "dns-01", Count: 1},
+		{ValidationMethods: "dns-01,http-01", Count: 1},
+		{ValidationMethods: "http-01", Count: 1},
+		{CertificateProfileName: "shortlived", ValidationMethods: "http-01", Count: 1},
+	})
+
+	_, err = sa.GetIssuanceCounts(ctx, &sapb.IssuanceCountsRequest{Range: dayRange, GroupBy: []string{"serial"}})
+	test.AssertErrorIs(t, err, berrors.Malformed)
+	_, err = sa.GetIssuanceCounts(ctx, &sapb.IssuanceCountsRequest{
+		Range: &sapb.Range{Earliest: dayRange.Latest, Latest: dayRange.Earliest},
+	})
+	test.AssertErrorIs(t, err, berrors.Malformed)
+	_, err = sa.GetIssuanceCounts(ctx, &sapb.IssuanceCountsRequest{
+		Range: &sapb.Range{Earliest: fc.Now().Add(-400 * 24 * time.Hour).UnixNano(), Latest: fc.Now().UnixNano()},
+	})
+	test.AssertErrorIs(t, err, berrors.Malformed)
+}
+
+func TestAddAndGetAccountEvents(t *testing.T) {
+	sa, fc, cleanUp := initSA(t)
+	defer cleanUp()
+	sa.SetAccountEventRetention(24 * time.Hour)
+
+	reg := satest.CreateWorkingRegistration(t, sa)
+	for i := 0; i < 3; i++ {
+		_, err := sa.AddAccountEvent(ctx, &sapb.AccountEvent{
+			RegistrationID: reg.ID,
+			Type:           string(core.AccountEventNewOrder),
+			Details:        fmt.Sprintf(`{"orderID":%d}`, i),
+		})
+		test.AssertNotError(t, err, "AddAccountEvent failed")
+		fc.Add(time.Hour)
+	}
+	_, err := sa.AddAccountEvent(ctx, &sapb.AccountEvent{RegistrationID: reg.ID, Type: "newOrder", Details: "[]"})
+	test.AssertErrorIs(t, err, berrors.Malformed)
+	_, err = sa.AddAccountEvent(ctx, &sapb.AccountEvent{RegistrationID: reg.ID, Type: "newOrder"})
+	test.AssertError(t, err, "AddAccountEvent accepted an event without details")
+
+	// Events are returned newest first, and can be paged through.
+	events, err := sa.GetAccountEvents(ctx, &sapb.GetAccountEventsRequest{RegistrationID: reg.ID, Limit: 2})
+	test.AssertNotError(t, err, "GetAccountEvents failed")
+	test.AssertEquals(t, len(events.Events), 2)
+	test.AssertEquals(t, events.Events[0].Details, `{"orderID":2}`)
+	test.AssertEquals(t, events.Events[1].Details, `{"orderID":1}`)
+	test.AssertEquals(t, events.Events[1].Created, fc.Now().Add(-2*time.Hour).UnixNano())
+	events, err = sa.GetAccountEvents(ctx, &sapb.GetAccountEventsRequest{
+		RegistrationID: reg.ID,
+		BeforeID:       events.Events[1].Id,
+		Limit:          2,
+	})
+	test.AssertNotError(t, err, "GetAccountEvents failed")
+	test.AssertEquals(t, len(events.Events), 1)
+	test.AssertEquals(t, events.Events[0].Details, `{"orderID":0}`)
+
+	// Other registrations' events aren't returned.
+	other := satest.CreateWorkingRegistration(t, sa)
+	events, err = sa.GetAccountEvents(ctx, &sapb.GetAccountEventsRequest{RegistrationID: other.ID})
+	test.AssertNotError(t, err, "GetAccountEvents failed")
+	test.AssertEquals(t, len(events.Events), 0)
+
+	// Events older than the retention period aren't returned, and are deleted
+	// when the registration's next event is added.
+	fc.Add(22 * time.Hour)
+	events, err = sa.GetAccountEvents(ctx, &sapb.GetAccountEventsRequest{RegistrationID: reg.ID})
+	test.AssertNotError(t, err, "GetAccountEvents failed")
+	test.AssertEquals(t, len(events.Events), 2)
+	_, err = sa.AddAccountEvent(ctx, &sapb.AccountEvent{
+		RegistrationID: reg.ID,
+		Type:           string(core.AccountEventContactChange),
+		Details:        `{"contactCount":0}`,
+	})
+	test.AssertNotError(t, err, "AddAccountEvent failed")
+	var count int64
+	err = sa.dbMap.SelectOne(&count, "SELECT COUNT(1) FROM accountEvents WHERE registrationID = ?", reg.ID)
+	test.AssertNotError(t, err, "counting events")
+	test.AssertEquals(t, count, int64(3))
+}
+
+func TestKeyRolloverHistory(t *testing.T) {
+	sa, fc, cleanUp := initSA(t)
+	defer cleanUp()
+
+	reg := satest.CreateWorkingRegistration(t, sa)
+	var newJWK jose.JSONWebKey
+	err := json.Unmarshal([]byte(anotherKey), &newJWK)
+	test.AssertNotError(t, err, "couldn't unmarshal anotherKey")
+	oldHash, err := core.KeyDigest(reg.Key)
+	test.AssertNotError(t, err, "hashing old key")
+	newHash, err := core.KeyDigest(&newJWK)
+	test.AssertNotError(t, err, "hashing new key")
+
+	// Nothing is recorded unless the feature is enabled.
+	rolledOver := reg
+	rolledOver.Key = &newJWK
+	err = sa.UpdateRegistration(ctx, rolledOver)
+	test.AssertNotError(t, err, "UpdateRegistration failed")
+	rollovers, err := sa.GetKeyRollovers(ctx, &sapb.GetKeyRolloversRequest{RegistrationID: reg.ID})
+	test.AssertNotError(t, err, "GetKeyRollovers failed")
+	test.AssertEquals(t, len(rollovers.Rollovers), 0)
+
+	_ = features.Set(map[string]bool{"KeyRolloverHistory": true})
+	defer features.Reset()
+
+	// Updates which don't change the key aren't rollovers.
+	rolledOver.Agreement = "yes"
+	err = sa.UpdateRegistration(ctx, rolledOver)
+	test.AssertNotError(t, err, "UpdateRegistration failed")
+	rollovers, err = sa.GetKeyRollovers(ctx, &sapb.GetKeyRolloversRequest{RegistrationID: reg.ID})
+	test.AssertNotError(t, err, "GetKeyRollovers failed")
+	test.AssertEquals(t, len(rollovers.Rollovers), 0)
+
+	fc.Add(time.Hour)
+	err = sa.UpdateRegistration(ctx, reg)
+	test.AssertNotError(t, err, "UpdateRegistration failed")
+	rollovers, err = sa.GetKeyRollovers(ctx, &sapb.GetKeyRolloversRequest{RegistrationID: reg.ID})
+	test.AssertNotError(t, err, "GetKeyRollovers failed")
+	test.AssertEquals(t, len(rollovers.Rollovers), 1)
+	test.AssertByteEquals(t, rollovers.Rollovers[0].OldKeyHash, newHash[:])
+	test.AssertByteEquals(t, rollovers.Rollovers[0].NewKeyHash, oldHash[:])
+	test.AssertEquals(t, rollovers.Rollovers[0].RolledOver, fc.Now().UnixNano())
+
+	// A key's rollovers include those both from and to it, and can be limited
+	// to recent ones.
+	fc.Add(time.Hour)
+	err = sa.UpdateRegistration(ctx, rolledOver)
+	test.AssertNotError(t, err, "UpdateRegistration failed")
+	rollovers, err = sa.GetKeyRollovers(ctx, &sapb.GetKeyRolloversRequest{KeyHash: newHash[:]})
+	test.AssertNotError(t, err, "GetKeyRollovers failed")
+	test.AssertEquals(t, len(rollovers.Rollovers), 2)
+	test.AssertByteEquals(t, rollovers.Rollovers[0].NewKeyHash, newHash[:])
+	test.AssertByteEquals(t, rollovers.Rollovers[1].OldKeyHash, newHash[:])
+	rollovers, err = sa.GetKeyRollovers(ctx, &sapb.GetKeyRolloversRequest{
+		KeyHash: oldHash[:],
+		Since:   fc.Now().Add(-time.Minute).UnixNano(),
+	})
+	test.AssertNotError(t, err, "GetKeyRollovers failed")
+	test.AssertEquals(t, len(rollovers.Rollovers), 1)
+	test.AssertByteEquals(t, rollovers.Rollovers[0].OldKeyHash, oldHash[:])
+
+	_, err = sa.GetKeyRollovers(ctx, &sapb.GetKeyRolloversRequest{RegistrationID: reg.ID, KeyHash: oldHash[:]})
+	test.AssertError(t, err, "GetKeyRollovers accepted both a registration and a key")
+}
+
+func TestIssuanceLogEntries(t *testing.T) {
+	sa, fc, cleanUp := initSA(t)
+	defer cleanUp()
+
+	hash := func(b byte) []byte { return bytes.Repeat([]byte{b}, 32) }
+	entry := &sapb.IssuanceLogEntry{
+		ChainID:                "ca-a",
+		Seq:                    1,
+		Type:                   "precertificate",
+		Serial:                 "0000000000000000000000000000000000aa",
+		CertificateProfileName: "default",
+		NamesHash:              hash(1),
+		CsrHash:                hash(2),
+		Issued:                 fc.Now().UnixNano(),
+		PrevHash:               hash(0),
+		Hash:                   hash(3),
+	}
+
+	_, err := sa.GetIssuanceLogEntry(ctx, &sapb.GetIssuanceLogEntryRequest{ChainID: "ca-a", Seq: 1})
+	test.AssertErrorIs(t, err, berrors.NotFound)
+
+	_, err = sa.AddIssuanceLogEntry(ctx, entry)
+	test.AssertNotError(t, err, "AddIssuanceLogEntry failed")
+	// Storing an entry again has no effect.
+	_, err = sa.AddIssuanceLogEntry(ctx, entry)
+	test.AssertNotError(t, err, "AddIssuanceLogEntry failed for an existing entry")
+
+	stored, err := sa.GetIssuanceLogEntry(ctx, &sapb.GetIssuanceLogEntryRequest{ChainID: "ca-a", Seq: 1})
+	test.AssertNotError(t, err, "GetIssuanceLogEntry failed")
+	test.AssertDeepEquals(t, stored, entry)
+
+	// Chains are numbered independently.
+	_, err = sa.GetIssuanceLogEntry(ctx, &sapb.GetIssuanceLogEntryRequest{ChainID: "ca-b", Seq: 1})
+	test.AssertErrorIs(t, err, berrors.NotFound)
+
+	_, err = sa.AddIssuanceLogEntry(ctx, &sapb.IssuanceLogEntry{ChainID: "ca-a", Seq: 2})
+	test.AssertError(t, err, "AddIssuanceLogEntry accepted an incomplete entry")
+}
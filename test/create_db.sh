@@ -19,6 +19,10 @@ mysql $dbconn -e "SET GLOBAL binlog_format = 'MIXED';"
 # to give headroom for other components (ocsp-updater for example).
 mysql $dbconn -e "SET GLOBAL max_connections = 500;"
 
+# Build sa-migrate once rather than for each database.
+migrate="$(mktemp -d)/sa-migrate"
+GO111MODULE=on go build -mod=vendor -o "$migrate" ./cmd/sa-migrate || die "unable to build sa-migrate"
+
 for dbenv in $DBENVS; do
   db="boulder_sa_${dbenv}"
 
@@ -32,19 +36,15 @@ for dbenv in $DBENVS; do
     echo "created empty ${db} database"
   fi
 
-  goose -path=./sa/_db/ -env=$dbenv up || die "unable to migrate ${db} with ./sa/_db/"
+  dbURL="root@tcp(boulder-mysql:3306)/${db}"
+  $migrate -dir ./sa/_db/ -db-connect "$dbURL" || die "unable to migrate ${db} with ./sa/_db/"
   echo "migrated ${db} database with ./sa/_db/"
 
   if [[ "$BOULDER_CONFIG_DIR" = "test/config-next" ]]; then
     nextDir="./sa/_db-next/"
 
-    # Goose exits non-zero if there are no migrations to apply with the error
-    # message:
-    #   "2016/09/26 15:43:38 no valid version found"
-    # so we only want to run goose with the nextDir if there is a migrations
-    # directory present with at least one migration
-    if [ $(find "$nextDir/migrations" -maxdepth 0 -type d -not -empty 2>/dev/null) ]; then
-      goose -path=${nextDir} -env=$dbenv up || die "unable to migrate ${db} with ${nextDir}"
+    if [ -d "$nextDir/migrations" ]; then
+      $migrate -dir ${nextDir} -db-connect "$dbURL" || die "unable to migrate ${db} with ${nextDir}"
       echo "migrated ${db} database with ${nextDir}"
     else
       echo "no ${nextDir} migrations to apply"
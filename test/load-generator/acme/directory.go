@@ -25,6 +25,9 @@ const (
 	RevokeCertEndpoint Endpoint = "revokeCert"
 	// KeyChangeEndpoint is the directory key for the keyChange endpoint.
 	KeyChangeEndpoint Endpoint = "keyChange"
+	// RenewalInfoEndpoint is the directory key for the ACME Renewal Information
+	// (ARI) renewalInfo endpoint.
+	RenewalInfoEndpoint Endpoint = "renewalInfo"
 )
 
 var (
@@ -54,6 +57,13 @@ var (
 		NewNonceEndpoint, NewAccountEndpoint,
 		NewOrderEndpoint, RevokeCertEndpoint,
 	}
+
+	// OptionalEndpoints is a slice of Endpoint keys that the load-generator
+	// uses if they're present in the ACME server's directory. Only the actions
+	// which use them require them.
+	OptionalEndpoints = []Endpoint{
+		RenewalInfoEndpoint,
+	}
 )
 
 // Endpoint represents a string key used for looking up an endpoint URL in an ACME
@@ -227,6 +237,17 @@ func NewDirectory(directoryURL string) (*Directory, error) {
 		directory.endpointURLs[endpointName] = url.String()
 	}
 
+	// Optional endpoints are populated if present, but must have a valid URL
+	for _, endpointName := range OptionalEndpoints {
+		url, err := serverURL(endpointName)
+		if _, missing := err.(ErrMissingEndpoint); missing {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		directory.endpointURLs[endpointName] = url.String()
+	}
+
 	// Populate the terms-of-service
 	tos, err := termsOfService(dirResource)
 	if err != nil {
@@ -20,6 +20,8 @@ const (
 	validDirectoryPath          = "/dir-valid"
 	invalidMetaDirectoryPath    = "/dir-valid-meta-invalid"
 	invalidMetaDirectoryToSPath = "/dir-valid-meta-valid-tos-invalid"
+	invalidOptionalEndpointPath = "/dir-invalid-optional-endpoint"
+	renewalInfoDirectoryPath    = "/dir-valid-renewal-info"
 )
 
 // mockDirectoryServer is an httptest.Server that returns mock data for ACME
@@ -95,6 +97,34 @@ func newMockDirectoryServer() *mockDirectoryServer {
 		fmt.Fprint(w, validDir)
 	})
 
+	m.HandleFunc(invalidOptionalEndpointPath, func(w http.ResponseWriter, r *http.Request) {
+		invalidRenewalInfoDir := `{
+			 "meta": {
+					"termsOfService": "data:text/plain,Do%20what%20thou%20wilt"
+			 },
+			 "newAccount": "https://localhost:14000/sign-me-up",
+			 "newNonce": "https://localhost:14000/nonce-plz",
+			 "newOrder": "https://localhost:14000/order-plz",
+			 "renewalInfo": "http://%zz",
+			 "revokeCert": "https://localhost:14000/revoke-cert"
+		}`
+		fmt.Fprint(w, invalidRenewalInfoDir)
+	})
+
+	m.HandleFunc(renewalInfoDirectoryPath, func(w http.ResponseWriter, r *http.Request) {
+		renewalInfoDir := `{
+			 "meta": {
+					"termsOfService": "data:text/plain,Do%20what%20thou%20wilt"
+			 },
+			 "newAccount": "https://localhost:14000/sign-me-up",
+			 "newNonce": "https://localhost:14000/nonce-plz",
+			 "newOrder": "https://localhost:14000/order-plz",
+			 "renewalInfo": "https://localhost:14000/renewal-info",
+			 "revokeCert": "https://localhost:14000/revoke-cert"
+		}`
+		fmt.Fprint(w, renewalInfoDir)
+	})
+
 	srv := &mockDirectoryServer{
 		Server: httptest.NewUnstartedServer(m),
 	}
@@ -137,10 +167,19 @@ func TestNew(t *testing.T) {
 
 	validDirectoryURL := testURL(validDirectoryPath)
 
+	invalidOptionalEndpointURL := testURL(invalidOptionalEndpointPath)
+	invalidOptionalEndpointErr := ErrInvalidEndpointURL{
+		endpoint: RenewalInfoEndpoint,
+		value:    "http://%zz",
+	}
+
+	renewalInfoDirectoryURL := testURL(renewalInfoDirectoryPath)
+
 	testCases := []struct {
-		Name          string
-		DirectoryURL  string
-		ExpectedError string
+		Name                string
+		DirectoryURL        string
+		ExpectedError       string
+		ExpectedRenewalInfo string
 	}{
 		{
 			Name:          "empty directory URL",
@@ -189,19 +228,31 @@ func TestNew(t *testing.T) {
 			DirectoryURL:  invalidDirectoryToSURL,
 			ExpectedError: ErrInvalidTermsOfService.Error(),
 		},
+		{
+			Name:          "directory JSON with invalid optional endpoint URL",
+			DirectoryURL:  invalidOptionalEndpointURL,
+			ExpectedError: invalidOptionalEndpointErr.Error(),
+		},
 		{
 			Name:         "valid directory",
 			DirectoryURL: validDirectoryURL,
 		},
+		{
+			Name:                "valid directory with renewalInfo",
+			DirectoryURL:        renewalInfoDirectoryURL,
+			ExpectedRenewalInfo: "https://localhost:14000/renewal-info",
+		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.Name, func(t *testing.T) {
-			_, err := NewDirectory(tc.DirectoryURL)
+			dir, err := NewDirectory(tc.DirectoryURL)
 			if err == nil && tc.ExpectedError != "" {
 				t.Errorf("expected error %q got nil", tc.ExpectedError)
 			} else if err != nil {
 				test.AssertEquals(t, err.Error(), tc.ExpectedError)
+			} else {
+				test.AssertEquals(t, dir.EndpointURL(RenewalInfoEndpoint), tc.ExpectedRenewalInfo)
 			}
 		})
 	}
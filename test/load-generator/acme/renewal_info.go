@@ -0,0 +1,55 @@
+package acme
+
+import (
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// RenewalInfo is the ACME Renewal Information (ARI) resource returned by an
+// ACME server's renewalInfo endpoint for a certificate.
+type RenewalInfo struct {
+	SuggestedWindow struct {
+		Start time.Time `json:"start"`
+		End   time.Time `json:"end"`
+	} `json:"suggestedWindow"`
+	ExplanationURL string `json:"explanationURL,omitempty"`
+}
+
+// Validate returns an error if the RenewalInfo's suggested window is missing or
+// ends before it starts.
+func (ri RenewalInfo) Validate() error {
+	if ri.SuggestedWindow.Start.IsZero() || ri.SuggestedWindow.End.IsZero() {
+		return errors.New("renewalInfo is missing its suggestedWindow")
+	}
+	if !ri.SuggestedWindow.End.After(ri.SuggestedWindow.Start) {
+		return fmt.Errorf("renewalInfo suggestedWindow ends (%s) before it starts (%s)",
+			ri.SuggestedWindow.End, ri.SuggestedWindow.Start)
+	}
+	return nil
+}
+
+// CertID returns the ARI unique identifier for the certificate, which is used
+// both to fetch its renewalInfo and as the "replaces" field of an order which
+// renews it. It's made of the base64url encoded key identifier of the
+// certificate's Authority Key Identifier extension and its DER encoded serial
+// number, separated by a period.
+func CertID(cert *x509.Certificate) (string, error) {
+	if len(cert.AuthorityKeyId) == 0 {
+		return "", errors.New("certificate has no Authority Key Identifier")
+	}
+	if cert.SerialNumber == nil || cert.SerialNumber.Sign() <= 0 {
+		return "", errors.New("certificate has no positive serial number")
+	}
+	// The DER encoding of a positive INTEGER has a leading zero byte if its
+	// most significant bit would otherwise be set.
+	serial := cert.SerialNumber.Bytes()
+	if serial[0]&0x80 != 0 {
+		serial = append([]byte{0}, serial...)
+	}
+	return fmt.Sprintf("%s.%s",
+		base64.RawURLEncoding.EncodeToString(cert.AuthorityKeyId),
+		base64.RawURLEncoding.EncodeToString(serial)), nil
+}
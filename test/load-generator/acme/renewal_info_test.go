@@ -0,0 +1,46 @@
+package acme
+
+import (
+	"crypto/x509"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/letsencrypt/boulder/test"
+)
+
+func TestCertID(t *testing.T) {
+	_, err := CertID(&x509.Certificate{SerialNumber: big.NewInt(1)})
+	test.AssertError(t, err, "CertID didn't fail without an Authority Key Identifier")
+
+	_, err = CertID(&x509.Certificate{AuthorityKeyId: []byte{1}})
+	test.AssertError(t, err, "CertID didn't fail without a serial number")
+
+	// The example from the ARI specification.
+	serial, ok := new(big.Int).SetString("0087654321", 16)
+	test.Assert(t, ok, "parsing serial")
+	id, err := CertID(&x509.Certificate{
+		AuthorityKeyId: []byte{0x69, 0x88, 0x5B, 0x6B, 0x87, 0x46, 0x40, 0x41, 0xE1, 0xB3, 0x7B, 0x84, 0x7B, 0xA0, 0xAE, 0x2C, 0xDE, 0x01, 0xC8, 0xD4},
+		SerialNumber:   serial,
+	})
+	test.AssertNotError(t, err, "CertID failed")
+	test.AssertEquals(t, id, "aYhba4dGQEHhs3uEe6CuLN4ByNQ.AIdlQyE")
+
+	// Serials without the most significant bit set have no leading zero.
+	id, err = CertID(&x509.Certificate{AuthorityKeyId: []byte{1}, SerialNumber: big.NewInt(0x7f)})
+	test.AssertNotError(t, err, "CertID failed")
+	test.AssertEquals(t, id, "AQ.fw")
+}
+
+func TestRenewalInfoValidate(t *testing.T) {
+	var ri RenewalInfo
+	test.AssertError(t, ri.Validate(), "Validate didn't fail without a suggestedWindow")
+
+	now := time.Now()
+	ri.SuggestedWindow.Start = now
+	ri.SuggestedWindow.End = now.Add(-time.Hour)
+	test.AssertError(t, ri.Validate(), "Validate didn't fail with a window ending before it starts")
+
+	ri.SuggestedWindow.End = now.Add(time.Hour)
+	test.AssertNotError(t, ri.Validate(), "Validate failed with a valid window")
+}
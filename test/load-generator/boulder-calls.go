@@ -38,6 +38,10 @@ var (
 		"fulfillOrder":      fulfillOrder,
 		"finalizeOrder":     finalizeOrder,
 		"revokeCertificate": revokeCertificate,
+
+		"revokeCertificateByKey": revokeCertificateByKey,
+		"getRenewalInfo":         getRenewalInfo,
+		"newReplacementOrder":    newReplacementOrder,
 	}
 )
 
@@ -57,6 +61,8 @@ type OrderJSON struct {
 	Finalize       string                      `json:"finalize"`
 	Certificate    string                      `json:"certificate,omitempty"`
 	Error          *probs.ProblemDetails       `json:"error,omitempty"`
+	Profile        string                      `json:"profile,omitempty"`
+	Replaces       string                      `json:"replaces,omitempty"`
 }
 
 // getAccount takes a randomly selected v2 account from `state.accts` and puts it
@@ -72,7 +78,7 @@ func getAccount(s *State, ctx *context) error {
 
 	// Select a random account from the state and put it into the context
 	ctx.acct = s.accts[mrand.Intn(len(s.accts))]
-	ctx.ns = &nonceSource{s: s}
+	ctx.ns = &nonceSource{s: s, scenario: ctx.scenario}
 	return nil
 }
 
@@ -96,7 +102,7 @@ func newAccount(s *State, ctx *context) error {
 	ctx.acct = &account{
 		key: signKey,
 	}
-	ctx.ns = &nonceSource{s: s}
+	ctx.ns = &nonceSource{s: s, scenario: ctx.scenario}
 
 	// Prepare an account registration message body
 	reqBody := struct {
@@ -126,7 +132,7 @@ func newAccount(s *State, ctx *context) error {
 	resp, err := s.post(
 		newAccountURL,
 		bodyBuf,
-		ctx.ns,
+		ctx,
 		string(acme.NewAccountEndpoint),
 		http.StatusCreated)
 	if err != nil {
@@ -159,7 +165,8 @@ func randDomain(base string) string {
 }
 
 // newOrder creates a new pending order object for a random set of domains using
-// the context's account.
+// the context's account, requesting a certificate profile picked from the
+// state's profile mix.
 func newOrder(s *State, ctx *context) error {
 	// Pick a random number of names within the constraints of the maxNamesPerCert
 	// parameter
@@ -174,11 +181,22 @@ func newOrder(s *State, ctx *context) error {
 		})
 	}
 
+	return submitOrder(s, ctx, dnsNames, "")
+}
+
+// submitOrder creates a new pending order object for the provided identifiers
+// using the context's account, and stores it in the context. If replaces isn't
+// empty, it's the ARI certificate ID of the certificate the order renews.
+func submitOrder(s *State, ctx *context, dnsNames []identifier.ACMEIdentifier, replaces string) error {
 	// create the new order request object
 	initOrder := struct {
 		Identifiers []identifier.ACMEIdentifier
+		Profile     string `json:"profile,omitempty"`
+		Replaces    string `json:"replaces,omitempty"`
 	}{
 		Identifiers: dnsNames,
+		Profile:     s.pickProfile(),
+		Replaces:    replaces,
 	}
 	initOrderStr, err := json.Marshal(&initOrder)
 	if err != nil {
@@ -196,7 +214,7 @@ func newOrder(s *State, ctx *context) error {
 	resp, err := s.post(
 		newOrderURL,
 		bodyBuf,
-		ctx.ns,
+		ctx,
 		string(acme.NewOrderEndpoint),
 		http.StatusCreated)
 	if err != nil {
@@ -320,7 +338,7 @@ func completeAuthorization(authz *core.Authorization, s *State, ctx *context) er
 	resp, err := s.post(
 		chalToSolve.URL,
 		requestPayload,
-		ctx.ns,
+		ctx,
 		"/acme/challenge/{ID}", // We want all challenge POST latencies to be grouped
 		http.StatusOK,
 	)
@@ -527,7 +545,7 @@ func finalizeOrder(s *State, ctx *context) error {
 	resp, err := s.post(
 		finalizeURL,
 		requestPayload,
-		ctx.ns,
+		ctx,
 		"/acme/order/finalize", // We want all order finalizations to be grouped.
 		http.StatusOK,
 	)
@@ -574,7 +592,7 @@ func postAsGet(s *State, ctx *context, url string, latencyTag string) (*http.Res
 	}
 	requestPayload := []byte(jws.FullSerialize())
 
-	return s.post(url, requestPayload, ctx.ns, latencyTag, http.StatusOK)
+	return s.post(url, requestPayload, ctx, latencyTag, http.StatusOK)
 }
 
 func popCertificate(ctx *context) string {
@@ -599,6 +617,20 @@ func getCert(s *State, ctx *context, url string) ([]byte, error) {
 // The revocation request is signed with the account key rather than the certificate
 // key.
 func revokeCertificate(s *State, ctx *context) error {
+	return revoke(s, ctx, false)
+}
+
+// revokeCertificateByKey is like revokeCertificate, but the revocation request
+// is signed with the certificate key, using an embedded JWK, rather than the
+// account key.
+func revokeCertificateByKey(s *State, ctx *context) error {
+	return revoke(s, ctx, true)
+}
+
+// revoke removes a certificate url from the context, retrieves it, and sends
+// a revocation request for it to the ACME server, signed with either the
+// certificate key or the account key.
+func revoke(s *State, ctx *context, byCertKey bool) error {
 	if len(ctx.certs) < 1 {
 		return errors.New("No certificates in the context that can be revoked")
 	}
@@ -614,6 +646,9 @@ func revokeCertificate(s *State, ctx *context) error {
 	}
 
 	pemBlock, _ := pem.Decode(certPEM)
+	if pemBlock == nil {
+		return fmt.Errorf("%s, bad response: no PEM certificate", certURL)
+	}
 	revokeObj := struct {
 		Certificate string
 		Reason      int
@@ -627,9 +662,15 @@ func revokeCertificate(s *State, ctx *context) error {
 		return err
 	}
 	revokeURL := s.directory.EndpointURL(acme.RevokeCertEndpoint)
-	// TODO(roland): randomly use the certificate key to sign the request instead of
-	// the account key
-	jws, err := ctx.signKeyIDV2Request(revokeJSON, revokeURL)
+	latencyTag := "/acme/revoke-cert"
+	var jws *jose.JSONWebSignature
+	if byCertKey {
+		// Every certificate's key is the state's certKey.
+		jws, err = ctx.signEmbeddedRequestWithKey(s.certKey, revokeJSON, revokeURL)
+		latencyTag = "/acme/revoke-cert (cert key)"
+	} else {
+		jws, err = ctx.signKeyIDV2Request(revokeJSON, revokeURL)
+	}
 	if err != nil {
 		return err
 	}
@@ -638,8 +679,8 @@ func revokeCertificate(s *State, ctx *context) error {
 	resp, err := s.post(
 		revokeURL,
 		requestPayload,
-		ctx.ns,
-		"/acme/revoke-cert",
+		ctx,
+		latencyTag,
 		http.StatusOK,
 	)
 	if err != nil {
@@ -654,3 +695,84 @@ func revokeCertificate(s *State, ctx *context) error {
 
 	return nil
 }
+
+// getParsedCert retrieves the certificate at the provided URL and parses the
+// end-entity certificate at the start of the returned chain.
+func getParsedCert(s *State, ctx *context, url string) (*x509.Certificate, error) {
+	certPEM, err := getCert(s, ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	pemBlock, _ := pem.Decode(certPEM)
+	if pemBlock == nil {
+		return nil, fmt.Errorf("%s, bad response: no PEM certificate", url)
+	}
+	return x509.ParseCertificate(pemBlock.Bytes)
+}
+
+// getRenewalInfo retrieves a random certificate from the context's list of
+// certificates and polls the ACME server's renewalInfo endpoint for it, checking
+// that the server suggests a valid renewal window. The certificate stays in the
+// context so that it can be polled again, or renewed.
+func getRenewalInfo(s *State, ctx *context) error {
+	if len(ctx.certs) < 1 {
+		return errors.New("No certificates in the context to get renewal information for")
+	}
+
+	certURL := ctx.certs[mrand.Intn(len(ctx.certs))]
+	cert, err := getParsedCert(s, ctx, certURL)
+	if err != nil {
+		return err
+	}
+	certID, err := acme.CertID(cert)
+	if err != nil {
+		return fmt.Errorf("%s, computing ARI certificate ID: %s", certURL, err)
+	}
+
+	renewalInfoURL := fmt.Sprintf("%s/%s", s.directory.EndpointURL(acme.RenewalInfoEndpoint), certID)
+	resp, err := s.get(renewalInfoURL, ctx, "GET renewalInfo/{ID}", http.StatusOK)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	var renewalInfo acme.RenewalInfo
+	err = json.Unmarshal(body, &renewalInfo)
+	if err != nil {
+		return fmt.Errorf("%s, bad response: %s", renewalInfoURL, body)
+	}
+	return renewalInfo.Validate()
+}
+
+// newReplacementOrder removes a certificate from the context's list of
+// certificates and creates a new pending order for the same names which
+// replaces it, identified by its ARI certificate ID. A certificate can only be
+// replaced once, so it isn't returned to the context.
+func newReplacementOrder(s *State, ctx *context) error {
+	if len(ctx.certs) < 1 {
+		return errors.New("No certificates in the context that can be replaced")
+	}
+
+	certURL := popCertificate(ctx)
+	cert, err := getParsedCert(s, ctx, certURL)
+	if err != nil {
+		return err
+	}
+	certID, err := acme.CertID(cert)
+	if err != nil {
+		return fmt.Errorf("%s, computing ARI certificate ID: %s", certURL, err)
+	}
+
+	var dnsNames []identifier.ACMEIdentifier
+	for _, name := range cert.DNSNames {
+		dnsNames = append(dnsNames, identifier.ACMEIdentifier{
+			Type:  identifier.DNS,
+			Value: name,
+		})
+	}
+	return submitOrder(s, ctx, dnsNames, certID)
+}
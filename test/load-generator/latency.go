@@ -13,16 +13,17 @@ type point struct {
 	Took     int64     `json:"took"`
 	PType    string    `json:"type"`
 	Action   string    `json:"action"`
+	Scenario string    `json:"scenario,omitempty"`
 }
 
 type latencyWriter interface {
-	Add(action string, sent, finished time.Time, pType string)
+	Add(action, scenario string, sent, finished time.Time, pType string)
 	Close()
 }
 
 type latencyNoop struct{}
 
-func (ln *latencyNoop) Add(_ string, _, _ time.Time, _ string) {}
+func (ln *latencyNoop) Add(_, _ string, _, _ time.Time, _ string) {}
 
 func (ln *latencyNoop) Close() {}
 
@@ -69,13 +70,14 @@ func (f *latencyFile) write() {
 }
 
 // Add writes a point to the file
-func (f *latencyFile) Add(action string, sent, finished time.Time, pType string) {
+func (f *latencyFile) Add(action, scenario string, sent, finished time.Time, pType string) {
 	f.metrics <- &point{
 		Sent:     sent,
 		Finished: finished,
 		Took:     finished.Sub(sent).Nanoseconds(),
 		PType:    pType,
 		Action:   action,
+		Scenario: scenario,
 	}
 }
 
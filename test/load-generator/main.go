@@ -16,10 +16,11 @@ import (
 type Config struct {
 	// Execution plan parameters
 	Plan struct {
-		Actions   []string // things to do
-		Rate      int64    // requests / s
-		RateDelta string   // requests / s^2
-		Runtime   string   // how long to run for
+		Actions   []string   // things to do
+		Scenarios []Scenario // named sets of things to do, weighted; used instead of Actions
+		Rate      int64      // requests / s
+		RateDelta string     // requests / s^2
+		Runtime   string     // how long to run for
 	}
 	ExternalState     string   // path to file to load/save registrations etc to/from
 	DontSaveState     bool     // don't save changes to external state
@@ -37,6 +38,10 @@ type Config struct {
 	MaxNamesPerCert   int      // maximum number of names on one certificate/order
 	ChallengeStrategy string   // challenge selection strategy ("random", "http-01", "dns-01", "tls-alpn-01")
 	RevokeChance      float32  // chance of revoking certificate after issuance, between 0.0 and 1.0
+	// Profiles maps certificate profile names to the relative weight of new
+	// orders requesting them, with "" for the server's default profile. If
+	// empty, orders don't request a profile.
+	Profiles map[string]int
 }
 
 func main() {
@@ -77,6 +82,13 @@ func main() {
 		config.Plan.RateDelta = *deltaArg
 	}
 
+	scenarios := config.Plan.Scenarios
+	if len(scenarios) == 0 {
+		scenarios = []Scenario{{Name: "default", Weight: 1, Actions: config.Plan.Actions}}
+	} else if len(config.Plan.Actions) != 0 {
+		cmd.Fail("Only one of Plan.Actions and Plan.Scenarios may be set")
+	}
+
 	s, err := New(
 		config.DirectoryURL,
 		config.CertKeySize,
@@ -86,9 +98,10 @@ func main() {
 		config.MaxNamesPerCert,
 		config.Results,
 		config.RegEmail,
-		config.Plan.Actions,
+		scenarios,
 		config.ChallengeStrategy,
 		config.RevokeChance,
+		config.Profiles,
 	)
 	cmd.FailOnError(err, "Failed to create load generator")
 
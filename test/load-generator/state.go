@@ -12,6 +12,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"log"
+	mrand "math/rand"
 	"net"
 	"net/http"
 	"os"
@@ -55,6 +56,8 @@ func (acct *account) update(finalizedOrders, certs []string) {
 }
 
 type context struct {
+	// The name of the scenario being run
+	scenario string
 	// The current V2 account (may be nil for legacy load generation)
 	acct *account
 	// Pending orders waiting for authorization challenge validation
@@ -76,9 +79,17 @@ type context struct {
 // this function primarily applicable to new account requests where no key ID is
 // known.
 func (c *context) signEmbeddedV2Request(data []byte, url string) (*jose.JSONWebSignature, error) {
-	// Create a signing key for the account's private key
+	return c.signEmbeddedRequestWithKey(c.acct.key, data, url)
+}
+
+// signEmbeddedRequestWithKey signs the provided request data like
+// signEmbeddedV2Request, but using the provided private key rather than the
+// account's. This is used for requests authorized by a certificate's key, like
+// revocation by certificate key.
+func (c *context) signEmbeddedRequestWithKey(key *ecdsa.PrivateKey, data []byte, url string) (*jose.JSONWebSignature, error) {
+	// Create a signing key for the private key
 	signingKey := jose.SigningKey{
-		Key:       c.acct.key,
+		Key:       key,
 		Algorithm: jose.ES256,
 	}
 	// Create a signer, setting the URL protected header
@@ -154,6 +165,72 @@ type Plan struct {
 	Delta   *RateDelta
 }
 
+// Scenario is a named sequence of actions. Each call the load-generator makes
+// runs one scenario, picked at random with a probability proportional to its
+// weight.
+type Scenario struct {
+	Name    string
+	Weight  int
+	Actions []string
+}
+
+// scenario is a Scenario whose actions have been converted to operations. It
+// counts the runs of the scenario so that they can be reported separately from
+// other scenarios' runs.
+type scenario struct {
+	name       string
+	weight     int
+	operations []func(*State, *context) error
+
+	runs     int64
+	failures int64
+	// took is the total duration of the scenario's runs, in nanoseconds
+	took int64
+}
+
+// record counts a run of the scenario which took the given duration.
+func (sc *scenario) record(took time.Duration, failed bool) {
+	atomic.AddInt64(&sc.runs, 1)
+	atomic.AddInt64(&sc.took, int64(took))
+	if failed {
+		atomic.AddInt64(&sc.failures, 1)
+	}
+}
+
+// String summarizes the runs of the scenario.
+func (sc *scenario) String() string {
+	runs := atomic.LoadInt64(&sc.runs)
+	var mean time.Duration
+	if runs > 0 {
+		mean = time.Duration(atomic.LoadInt64(&sc.took) / runs)
+	}
+	return fmt.Sprintf("%q: %d runs, %d failed, mean duration %s",
+		sc.name, runs, atomic.LoadInt64(&sc.failures), mean)
+}
+
+// pickWeighted returns a random index into weights, with the probability of
+// each index being proportional to its weight. The weights must be positive.
+func pickWeighted(weights []int) int {
+	total := 0
+	for _, w := range weights {
+		total += w
+	}
+	pick := mrand.Intn(total)
+	for i, w := range weights {
+		if pick < w {
+			return i
+		}
+		pick -= w
+	}
+	return len(weights) - 1
+}
+
+// ariOperations are the operations which use the server's renewalInfo
+// endpoint, and so can't be run against a server without one.
+var ariOperations = map[string]bool{
+	"getRenewalInfo": true,
+}
+
 type respCode struct {
 	code int
 	num  int
@@ -168,7 +245,13 @@ type State struct {
 	realIP          string
 	certKey         *ecdsa.PrivateKey
 
-	operations []func(*State, *context) error
+	scenarios []*scenario
+
+	// profiles are the certificate profiles requested by new orders, in
+	// proportion to their profileWeights. The empty string is the server's
+	// default profile.
+	profiles       []string
+	profileWeights []int
 
 	rMu sync.RWMutex
 
@@ -283,9 +366,10 @@ func New(
 	maxRegs, maxNamesPerCert int,
 	latencyPath string,
 	userEmail string,
-	operations []string,
+	scenarios []Scenario,
 	challStrat string,
-	revokeChance float32) (*State, error) {
+	revokeChance float32,
+	profiles map[string]int) (*State, error) {
 	certKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
 	if err != nil {
 		return nil, err
@@ -336,13 +420,51 @@ func New(
 		revokeChance:    revokeChance,
 	}
 
-	// convert operations strings to methods
-	for _, opName := range operations {
-		op, present := stringToOperation[opName]
-		if !present {
-			return nil, fmt.Errorf("unknown operation %q", opName)
+	// convert each scenario's operations strings to methods
+	if len(scenarios) == 0 {
+		return nil, errors.New("at least one scenario is required")
+	}
+	seen := make(map[string]bool)
+	for _, sc := range scenarios {
+		if sc.Name == "" {
+			return nil, errors.New("scenarios must have a name")
+		}
+		if seen[sc.Name] {
+			return nil, fmt.Errorf("duplicate scenario %q", sc.Name)
+		}
+		seen[sc.Name] = true
+		if sc.Weight <= 0 {
+			return nil, fmt.Errorf("scenario %q must have a positive weight", sc.Name)
+		}
+		if len(sc.Actions) == 0 {
+			return nil, fmt.Errorf("scenario %q has no actions", sc.Name)
+		}
+		converted := &scenario{name: sc.Name, weight: sc.Weight}
+		for _, opName := range sc.Actions {
+			op, present := stringToOperation[opName]
+			if !present {
+				return nil, fmt.Errorf("unknown operation %q", opName)
+			}
+			if ariOperations[opName] && directory.EndpointURL(acme.RenewalInfoEndpoint) == "" {
+				return nil, fmt.Errorf("operation %q requires the server's directory to have a %q endpoint",
+					opName, acme.RenewalInfoEndpoint)
+			}
+			converted.operations = append(converted.operations, op)
+		}
+		s.scenarios = append(s.scenarios, converted)
+	}
+
+	// The profiles are sorted so that the mix of orders doesn't depend on map
+	// iteration order.
+	for profile := range profiles {
+		s.profiles = append(s.profiles, profile)
+	}
+	sort.Strings(s.profiles)
+	for _, profile := range s.profiles {
+		if profiles[profile] <= 0 {
+			return nil, fmt.Errorf("profile %q must have a positive weight", profile)
 		}
-		s.operations = append(s.operations, op)
+		s.profileWeights = append(s.profileWeights, profiles[profile])
 	}
 
 	return s, nil
@@ -436,6 +558,9 @@ func (s *State) Run(
 	stop <- true
 	fmt.Println("[+] Waiting for pending flows to finish before killing challenge server")
 	s.wg.Wait()
+	for _, sc := range s.scenarios {
+		fmt.Printf("[+] Scenario %s\n", sc)
+	}
 	fmt.Println("[+] Shutting down challenge server")
 	s.challSrv.Shutdown()
 	return nil
@@ -492,7 +617,7 @@ var userAgent = "boulder load-generator -- heyo ^_^"
 func (s *State) post(
 	url string,
 	payload []byte,
-	ns *nonceSource,
+	ctx *context,
 	latencyTag string,
 	expectedCode int) (*http.Response, error) {
 	req, err := http.NewRequest("POST", url, bytes.NewBuffer(payload))
@@ -509,14 +634,14 @@ func (s *State) post(
 	state := "error"
 	// Defer logging the latency and result
 	defer func() {
-		s.callLatency.Add(latencyTag, started, finished, state)
+		s.callLatency.Add(latencyTag, ctx.scenario, started, finished, state)
 	}()
 	if err != nil {
 		return nil, err
 	}
 	go s.addRespCode(resp.StatusCode)
 	if newNonce := resp.Header.Get("Replay-Nonce"); newNonce != "" {
-		ns.addNonce(newNonce)
+		ctx.ns.addNonce(newNonce)
 	}
 	if resp.StatusCode != expectedCode {
 		return nil, fmt.Errorf("POST %q returned HTTP status %d, expected %d",
@@ -526,10 +651,47 @@ func (s *State) post(
 	return resp, nil
 }
 
+// get makes an unauthenticated GET request to the provided URL, recording its
+// latency and result under the context's scenario.
+func (s *State) get(
+	url string,
+	ctx *context,
+	latencyTag string,
+	expectedCode int) (*http.Response, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Add("X-Real-IP", s.realIP)
+	req.Header.Add("User-Agent", userAgent)
+	atomic.AddInt64(&s.reqTotal, 1)
+	started := time.Now()
+	resp, err := s.httpClient.Do(req)
+	finished := time.Now()
+	state := "error"
+	// Defer logging the latency and result
+	defer func() {
+		s.callLatency.Add(latencyTag, ctx.scenario, started, finished, state)
+	}()
+	if err != nil {
+		return nil, err
+	}
+	go s.addRespCode(resp.StatusCode)
+	if resp.StatusCode != expectedCode {
+		resp.Body.Close()
+		return nil, fmt.Errorf("GET %q returned HTTP status %d, expected %d",
+			url, resp.StatusCode, expectedCode)
+	}
+	state = "good"
+	return resp, nil
+}
+
 type nonceSource struct {
 	mu        sync.Mutex
 	noncePool []string
 	s         *State
+	// scenario is the name of the scenario the nonces are used by
+	scenario string
 }
 
 func (ns *nonceSource) getNonce() (string, error) {
@@ -540,7 +702,7 @@ func (ns *nonceSource) getNonce() (string, error) {
 	finished := time.Now()
 	state := "error"
 	defer func() {
-		ns.s.callLatency.Add(fmt.Sprintf("HEAD %s", latencyTag),
+		ns.s.callLatency.Add(fmt.Sprintf("HEAD %s", latencyTag), ns.scenario,
 			started, finished, state)
 	}()
 	if err != nil {
@@ -585,18 +747,49 @@ func (s *State) addAccount(acct *account) {
 	s.accts = append(s.accts, acct)
 }
 
+// pickScenario returns a random scenario, in proportion to the scenarios'
+// weights.
+func (s *State) pickScenario() *scenario {
+	weights := make([]int, len(s.scenarios))
+	for i, sc := range s.scenarios {
+		weights[i] = sc.weight
+	}
+	return s.scenarios[pickWeighted(weights)]
+}
+
+// pickProfile returns a random certificate profile for a new order, in
+// proportion to the profiles' weights. If no profiles are configured it
+// returns the empty string, for the server's default profile.
+func (s *State) pickProfile() string {
+	if len(s.profiles) == 0 {
+		return ""
+	}
+	return s.profiles[pickWeighted(s.profileWeights)]
+}
+
 func (s *State) sendCall() {
 	defer s.wg.Done()
-	ctx := &context{}
+	sc := s.pickScenario()
+	ctx := &context{scenario: sc.name}
 
-	for _, op := range s.operations {
+	started := time.Now()
+	failed := false
+	for _, op := range sc.operations {
 		err := op(s, ctx)
 		if err != nil {
 			method := runtime.FuncForPC(reflect.ValueOf(op).Pointer()).Name()
-			fmt.Printf("[FAILED] %s: %s\n", method, err)
+			fmt.Printf("[FAILED] %s: %s: %s\n", sc.name, method, err)
+			failed = true
 			break
 		}
 	}
+	finished := time.Now()
+	sc.record(finished.Sub(started), failed)
+	state := "good"
+	if failed {
+		state = "error"
+	}
+	s.callLatency.Add("scenario", sc.name, started, finished, state)
 	// If the context's V2 account isn't nil, update it based on the context's
 	// finalizedOrders and certs.
 	if ctx.acct != nil {
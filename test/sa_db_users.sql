@@ -33,6 +33,19 @@ GRANT SELECT,INSERT ON precertificates TO 'sa'@'localhost';
 GRANT SELECT,INSERT ON keyHashToSerial TO 'sa'@'localhost';
 GRANT SELECT,INSERT ON blockedKeys TO 'sa'@'localhost';
 GRANT SELECT,INSERT,UPDATE ON newOrdersRL TO 'sa'@'localhost';
+GRANT SELECT,INSERT,UPDATE ON incidents TO 'sa'@'localhost';
+GRANT SELECT,INSERT ON incidentSerials TO 'sa'@'localhost';
+GRANT SELECT,INSERT,UPDATE ON notificationPreferences TO 'sa'@'localhost';
+GRANT SELECT,INSERT,DELETE ON notificationNagTimes TO 'sa'@'localhost';
+GRANT SELECT,INSERT,UPDATE,DELETE ON accountFeatureOverrides TO 'sa'@'localhost';
+GRANT SELECT,INSERT ON hostnamePolicies TO 'sa'@'localhost';
+GRANT SELECT,INSERT,DELETE ON accountAllowlists TO 'sa'@'localhost';
+GRANT SELECT,INSERT,DELETE ON restrictedAccounts TO 'sa'@'localhost';
+GRANT SELECT,INSERT ON certificateMetadata TO 'sa'@'localhost';
+GRANT SELECT,INSERT,DELETE ON accountEvents TO 'sa'@'localhost';
+GRANT SELECT,INSERT ON keyRollovers TO 'sa'@'localhost';
+GRANT SELECT,INSERT ON issuanceLog TO 'sa'@'localhost';
+GRANT SELECT ON schemaMigrations TO 'sa'@'localhost';
 
 -- OCSP Responder
 GRANT SELECT ON certificateStatus TO 'ocsp_resp'@'localhost';
@@ -51,6 +64,8 @@ GRANT SELECT ON certificates TO 'mailer'@'localhost';
 GRANT SELECT ON registrations TO 'mailer'@'localhost';
 GRANT SELECT,UPDATE ON certificateStatus TO 'mailer'@'localhost';
 GRANT SELECT ON fqdnSets TO 'mailer'@'localhost';
+GRANT SELECT,INSERT,DELETE ON expirationNagRetries TO 'mailer'@'localhost';
+GRANT SELECT ON notificationNagTimes TO 'mailer'@'localhost';
 
 -- Cert checker
 GRANT SELECT ON certificates TO 'cert_checker'@'localhost';
@@ -68,6 +83,8 @@ GRANT SELECT,DELETE ON orders TO 'janitor'@'localhost';
 GRANT SELECT,DELETE ON requestedNames TO 'janitor'@'localhost';
 GRANT SELECT,DELETE ON orderFqdnSets TO 'janitor'@'localhost';
 GRANT SELECT,DELETE ON orderToAuthz2 TO 'janitor'@'localhost';
+GRANT SELECT,DELETE ON authz2 TO 'janitor'@'localhost';
+GRANT SELECT,DELETE ON accountEvents TO 'janitor'@'localhost';
 
 -- Bad Key Revoker
 GRANT SELECT,UPDATE ON blockedKeys TO 'badkeyrevoker'@'localhost';
@@ -40,11 +40,11 @@ SERVICES = (
         None),
     Service('boulder-publisher-1',
         8009, 'publisher1.boulder:9091',
-        ('./bin/boulder-publisher', '--config', os.path.join(config_dir, 'publisher.json'), '--addr', 'publisher1.boulder:9091', '--debug-addr', ':8009'),
+        ('./bin/boulder-publisher', '--config', os.path.join(config_dir, 'publisher.json'), '--addr', 'publisher1.boulder:9091', '--debug-addr', ':8009', '--final-cert-queue-dir', '/tmp/publisher-final-cert-queue-1'),
         ('sd-test-srv',)),
     Service('boulder-publisher-2',
         8109, 'publisher2.boulder:9091',
-        ('./bin/boulder-publisher', '--config', os.path.join(config_dir, 'publisher.json'), '--addr', 'publisher2.boulder:9091', '--debug-addr', ':8109'),
+        ('./bin/boulder-publisher', '--config', os.path.join(config_dir, 'publisher.json'), '--addr', 'publisher2.boulder:9091', '--debug-addr', ':8109', '--final-cert-queue-dir', '/tmp/publisher-final-cert-queue-2'),
         ('sd-test-srv',)),
     Service('mail-test-srv',
         9380, None,
@@ -6,7 +6,9 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/letsencrypt/boulder/bdns"
 	corepb "github.com/letsencrypt/boulder/core/proto"
+	berrors "github.com/letsencrypt/boulder/errors"
 	"github.com/letsencrypt/boulder/features"
 	"github.com/letsencrypt/boulder/identifier"
 	"github.com/letsencrypt/boulder/probs"
@@ -20,6 +22,40 @@ type caaParams struct {
 }
 
 func (va *ValidationAuthorityImpl) IsCAAValid(ctx context.Context, req *vapb.IsCAAValidRequest) (*vapb.IsCAAValidResponse, error) {
+	return va.isCAAValid(bdns.WithAttemptCache(ctx), req), nil
+}
+
+// IsCAAValidBatch checks CAA for each of the requested names in parallel,
+// within the deadline of the request, and returns the results in the same
+// order. The checks share their DNS lookups, such as those of the parent
+// domains of several names.
+func (va *ValidationAuthorityImpl) IsCAAValidBatch(ctx context.Context, req *vapb.IsCAAValidBatchRequest) (*vapb.IsCAAValidBatchResponse, error) {
+	if len(req.Checks) == 0 {
+		return nil, berrors.InternalServerError("Incomplete CAA batch request")
+	}
+	for _, check := range req.Checks {
+		if check == nil || check.Domain == "" {
+			return nil, berrors.InternalServerError("Incomplete CAA batch request")
+		}
+	}
+
+	ctx = bdns.WithAttemptCache(ctx)
+	results := make([]*vapb.IsCAAValidResponse, len(req.Checks))
+	var wg sync.WaitGroup
+	for i, check := range req.Checks {
+		wg.Add(1)
+		go func(i int, check *vapb.IsCAAValidRequest) {
+			defer wg.Done()
+			results[i] = va.isCAAValid(ctx, check)
+		}(i, check)
+	}
+	wg.Wait()
+	return &vapb.IsCAAValidBatchResponse{Results: results}, nil
+}
+
+// isCAAValid checks CAA for a name, returning a response with a problem if the
+// CA may not issue for it.
+func (va *ValidationAuthorityImpl) isCAAValid(ctx context.Context, req *vapb.IsCAAValidRequest) *vapb.IsCAAValidResponse {
 	acmeID := identifier.ACMEIdentifier{
 		Type:  identifier.DNS,
 		Value: req.Domain,
@@ -34,9 +70,9 @@ func (va *ValidationAuthorityImpl) IsCAAValid(ctx context.Context, req *vapb.IsC
 				ProblemType: string(prob.Type),
 				Detail:      fmt.Sprintf("While processing CAA for %s: %s", req.Domain, prob.Detail),
 			},
-		}, nil
+		}
 	}
-	return &vapb.IsCAAValidResponse{}, nil
+	return &vapb.IsCAAValidResponse{}
 }
 
 // checkCAA performs a CAA lookup & validation for the provided identifier. If
@@ -34,6 +34,10 @@ func (mock caaMockDNS) LookupHost(_ context.Context, hostname string) ([]net.IP,
 	return []net.IP{ip}, nil
 }
 
+func (mock caaMockDNS) LookupMX(_ context.Context, domain string) ([]string, error) {
+	return nil, nil
+}
+
 func (mock caaMockDNS) LookupCAA(_ context.Context, domain string) ([]*dns.CAA, string, error) {
 	var results []*dns.CAA
 	var record dns.CAA
@@ -577,6 +581,35 @@ func TestIsCAAValidErrMessage(t *testing.T) {
 	test.AssertEquals(t, resp.Problem.Detail, fmt.Sprintf("While processing CAA for %s: error", domain))
 }
 
+func TestIsCAAValidBatch(t *testing.T) {
+	va, _ := setup(nil, 0, "", nil)
+	va.dnsClient = caaMockDNS{}
+
+	resp, err := va.IsCAAValidBatch(ctx, &vapb.IsCAAValidBatchRequest{
+		Checks: []*vapb.IsCAAValidRequest{
+			{Domain: "present.com"},
+			{Domain: "reserved.com"},
+			{Domain: "caa-timeout.com"},
+			{Domain: "com"},
+		},
+	})
+	test.AssertNotError(t, err, "IsCAAValidBatch failed")
+	test.AssertEquals(t, len(resp.Results), 4)
+	test.Assert(t, resp.Results[0].Problem == nil, "CAA rejected for present.com")
+	test.AssertNotNil(t, resp.Results[1].Problem, "CAA allowed for reserved.com")
+	test.AssertEquals(t, resp.Results[1].Problem.ProblemType, string(probs.CAAProblem))
+	test.AssertNotNil(t, resp.Results[2].Problem, "CAA allowed despite lookup error")
+	test.AssertEquals(t, resp.Results[2].Problem.Detail, "While processing CAA for caa-timeout.com: error")
+	test.Assert(t, resp.Results[3].Problem == nil, "CAA rejected for com")
+
+	_, err = va.IsCAAValidBatch(ctx, &vapb.IsCAAValidBatchRequest{})
+	test.AssertError(t, err, "empty batch accepted")
+	_, err = va.IsCAAValidBatch(ctx, &vapb.IsCAAValidBatchRequest{
+		Checks: []*vapb.IsCAAValidRequest{{Domain: "present.com"}, {}},
+	})
+	test.AssertError(t, err, "batch with an empty check accepted")
+}
+
 func TestCAAFailure(t *testing.T) {
 	chall := createChallenge(core.ChallengeTypeHTTP01)
 	hs := httpSrv(t, chall.Token)
@@ -0,0 +1,298 @@
+package va
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/letsencrypt/boulder/core"
+	bgrpc "github.com/letsencrypt/boulder/grpc"
+	"github.com/letsencrypt/boulder/identifier"
+	bmail "github.com/letsencrypt/boulder/mail"
+	"github.com/letsencrypt/boulder/probs"
+	vapb "github.com/letsencrypt/boulder/va/proto"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// acmeSubjectPrefix begins the subject of challenge emails, and of the
+	// replies to them, and is followed by token-part1 (RFC 8823 section 3).
+	acmeSubjectPrefix = "ACME: "
+
+	beginACMEResponse = "-----BEGIN ACME RESPONSE-----"
+	endACMEResponse   = "-----END ACME RESPONSE-----"
+
+	// maxReplySize bounds how much of a reply's body is read.
+	maxReplySize = 64 * 1024
+)
+
+// EmailReplyConfig configures the validation of email-reply-00 challenges
+// (RFC 8823).
+type EmailReplyConfig struct {
+	// Mailer sends challenge emails. It's connected for each email, and
+	// needn't be safe for concurrent use.
+	Mailer bmail.Mailer
+	// Replies finds the replies to challenge emails.
+	Replies ReplySource
+	// Secret is the key with which token-part1 is derived from a challenge's
+	// token, so that it needn't be stored. It must be at least 32 bytes.
+	Secret []byte
+	// ResendInterval is how long after a challenge email is sent that it's
+	// sent again, if the challenge is retried without a reply having been
+	// received.
+	ResendInterval time.Duration
+}
+
+// ReplySource finds replies to the challenge emails sent for email-reply-00
+// challenges.
+type ReplySource interface {
+	// FindReply returns the body of a reply from the address to the challenge
+	// email for token-part1, or "" if there isn't one yet.
+	FindReply(ctx context.Context, from, tokenPart1 string) (string, error)
+}
+
+// emailReplyValidator validates email-reply-00 challenges.
+type emailReplyValidator struct {
+	EmailReplyConfig
+	// mu serializes use of the Mailer, and protects sent.
+	mu sync.Mutex
+	// sent holds when the challenge email for each token-part1 was last sent.
+	sent map[string]time.Time
+}
+
+// SetEmailReply enables the validation of email-reply-00 challenges, which
+// are otherwise rejected.
+func (va *ValidationAuthorityImpl) SetEmailReply(config EmailReplyConfig) error {
+	if config.Mailer == nil || config.Replies == nil {
+		return errors.New("email-reply-00 validation needs a Mailer and a ReplySource")
+	}
+	if len(config.Secret) < 32 {
+		return errors.New("email-reply-00 validation secret must be at least 32 bytes")
+	}
+	if config.ResendInterval <= 0 {
+		return errors.New("email-reply-00 validation resend interval must be positive")
+	}
+	va.emailReply = &emailReplyValidator{
+		EmailReplyConfig: config,
+		sent:             make(map[string]time.Time),
+	}
+	return nil
+}
+
+// tokenPart1 returns the token-part1 sent in the challenge email for a
+// challenge's token.
+func (e *emailReplyValidator) tokenPart1(token string) string {
+	mac := hmac.New(sha256.New, e.Secret)
+	_, _ = mac.Write([]byte(token))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// send sends the challenge email for token-part1 to the address, unless it was
+// already sent within the ResendInterval.
+func (e *emailReplyValidator) send(now time.Time, address, tokenPart1 string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for sentPart1, sentAt := range e.sent {
+		if now.Sub(sentAt) >= e.ResendInterval {
+			delete(e.sent, sentPart1)
+		}
+	}
+	if _, ok := e.sent[tokenPart1]; ok {
+		return nil
+	}
+	err := e.Mailer.Connect()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = e.Mailer.Close()
+	}()
+	body := fmt.Sprintf("This email was sent by an ACME server because a certificate was requested for %s.\n\n"+
+		"If you requested it, reply to this email with the ACME response computed by your ACME client, "+
+		"keeping its subject. Otherwise, ignore it.\n", address)
+	err = e.Mailer.SendMail([]string{address}, acmeSubjectPrefix+tokenPart1, body)
+	if err != nil {
+		return err
+	}
+	e.sent[tokenPart1] = now
+	return nil
+}
+
+// validateEmailReply00 validates an email-reply-00 challenge, as described in
+// RFC 8823, without waiting for a reply to the challenge email. If a reply from
+// the address being validated has been received, it must have the SHA-256
+// digest of token-part1 and the key authorization, which holds token-part2.
+// Otherwise token-part1 is emailed to the address, and awaiting is true: the
+// challenge stays pending, and is validated when it's retried after the reply
+// has been sent.
+func (va *ValidationAuthorityImpl) validateEmailReply00(ctx context.Context, ident identifier.ACMEIdentifier, challenge core.Challenge) (records []core.ValidationRecord, prob *probs.ProblemDetails, awaiting bool) {
+	if ident.Type != identifier.Email {
+		va.log.Infof("Got non-email identifier for email-reply-00 challenge: %s", ident.Value)
+		return nil, probs.Malformed("Identifier type for email-reply-00 challenge was not email"), false
+	}
+	if va.emailReply == nil {
+		return nil, probs.Malformed("email-reply-00 challenges are not supported"), false
+	}
+	records = []core.ValidationRecord{{Hostname: ident.Value}}
+
+	tokenPart1 := va.emailReply.tokenPart1(challenge.Token)
+	body, err := va.emailReply.Replies.FindReply(ctx, ident.Value, tokenPart1)
+	if err != nil {
+		va.log.Warningf("Checking for email-reply-00 reply from %s: %s", ident.Value, err)
+		return records, probs.ServerInternal("Error checking for a reply to the challenge email"), false
+	}
+	if body == "" {
+		err := va.emailReply.send(va.clk.Now(), ident.Value, tokenPart1)
+		if err != nil {
+			va.log.Warningf("Sending email-reply-00 challenge email to %s: %s", ident.Value, err)
+			return records, probs.ConnectionFailure(fmt.Sprintf("Error sending the challenge email to %s", ident.Value)), false
+		}
+		return records, nil, true
+	}
+
+	response, ok := acmeResponse(body)
+	if !ok {
+		return records, probs.Unauthorized(fmt.Sprintf("Reply to the challenge email sent to %s had no ACME response", ident.Value)), false
+	}
+	digest := sha256.Sum256([]byte(tokenPart1 + challenge.ProvidedKeyAuthorization))
+	expected := base64.RawURLEncoding.EncodeToString(digest[:])
+	if subtle.ConstantTimeCompare([]byte(response), []byte(expected)) != 1 {
+		return records, probs.Unauthorized(fmt.Sprintf("Incorrect ACME response in reply to the challenge email sent to %s", ident.Value)), false
+	}
+	return records, nil, false
+}
+
+// performEmailReply00 performs the validation of an email-reply-00 challenge
+// for PerformValidation. It's never performed by the remote VAs, each of which
+// would send its own challenge email.
+func (va *ValidationAuthorityImpl) performEmailReply00(ctx context.Context, req *vapb.PerformValidationRequest, challenge core.Challenge, logEvent verificationRequestEvent) (*vapb.ValidationResult, error) {
+	vStart := va.clk.Now()
+	records, prob, awaiting := va.validateEmailReply00(ctx, identifier.FromValue(req.Domain), challenge)
+	challenge.ValidationRecord = records
+
+	var problemType string
+	if prob != nil {
+		problemType = string(prob.Type)
+		challenge.Status = core.StatusInvalid
+		challenge.Error = prob
+		logEvent.Error = prob.Error()
+	} else if awaiting {
+		challenge.Status = core.StatusPending
+	} else {
+		challenge.Status = core.StatusValid
+		challenge.Validated = &vStart
+	}
+	logEvent.Challenge = challenge
+
+	validationLatency := time.Since(vStart)
+	logEvent.ValidationLatency = validationLatency.Round(time.Millisecond).Seconds()
+	va.metrics.validationTime.With(prometheus.Labels{
+		"type":         string(challenge.Type),
+		"result":       string(challenge.Status),
+		"problem_type": problemType,
+	}).Observe(validationLatency.Seconds())
+	va.log.AuditObject("Validation result", logEvent)
+
+	result, err := bgrpc.ValidationResultToPB(records, prob)
+	if err != nil {
+		return nil, err
+	}
+	result.AwaitingReply = awaiting
+	return result, nil
+}
+
+// acmeResponse returns the ACME response in the body of a reply, which is
+// between the BEGIN and END ACME RESPONSE lines.
+func acmeResponse(body string) (string, bool) {
+	begin := strings.Index(body, beginACMEResponse)
+	if begin == -1 {
+		return "", false
+	}
+	rest := body[begin+len(beginACMEResponse):]
+	end := strings.Index(rest, endACMEResponse)
+	if end == -1 {
+		return "", false
+	}
+	return strings.TrimSpace(rest[:end]), true
+}
+
+// MaildirReplies is a ReplySource which reads replies from the new and cur
+// directories of a Maildir, into which the mail server receiving them
+// delivers them. MaildirReplies trusts the From header of the messages it
+// reads, so the mail server must only deliver messages whose sender it has
+// authenticated, with DMARC for instance, as RFC 8823 requires.
+type MaildirReplies struct {
+	Dir string
+}
+
+// FindReply implements ReplySource.
+func (m MaildirReplies) FindReply(ctx context.Context, from, tokenPart1 string) (string, error) {
+	for _, sub := range []string{"new", "cur"} {
+		entries, err := ioutil.ReadDir(filepath.Join(m.Dir, sub))
+		if err != nil {
+			return "", err
+		}
+		for _, entry := range entries {
+			if ctx.Err() != nil {
+				return "", ctx.Err()
+			}
+			if entry.IsDir() {
+				continue
+			}
+			body, err := readReply(filepath.Join(m.Dir, sub, entry.Name()), from, tokenPart1)
+			if err != nil {
+				return "", err
+			}
+			if body != "" {
+				return body, nil
+			}
+		}
+	}
+	return "", nil
+}
+
+// readReply returns the body of the message in the file if it's a reply from
+// the address to the challenge email for token-part1, and "" otherwise.
+// Messages which can't be parsed, or which are moved by the mail server while
+// being read, are skipped.
+func readReply(path, from, tokenPart1 string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	defer f.Close()
+
+	msg, err := mail.ReadMessage(f)
+	if err != nil {
+		return "", nil
+	}
+	sender, err := mail.ParseAddress(msg.Header.Get("From"))
+	if err != nil || !strings.EqualFold(sender.Address, from) {
+		return "", nil
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	if err != nil || !strings.Contains(subject, acmeSubjectPrefix+tokenPart1) {
+		return "", nil
+	}
+	body, err := ioutil.ReadAll(io.LimitReader(msg.Body, maxReplySize))
+	if err != nil {
+		return "", nil
+	}
+	return string(body), nil
+}
@@ -0,0 +1,159 @@
+package va
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jmhodges/clock"
+	"github.com/letsencrypt/boulder/core"
+	"github.com/letsencrypt/boulder/identifier"
+	"github.com/letsencrypt/boulder/mocks"
+	"github.com/letsencrypt/boulder/probs"
+	"github.com/letsencrypt/boulder/test"
+	vapb "github.com/letsencrypt/boulder/va/proto"
+	"google.golang.org/grpc"
+)
+
+// setupEmailReply returns a VA which validates email-reply-00 challenges with
+// a mock Mailer, reading replies from a temporary Maildir, which the caller
+// must remove.
+func setupEmailReply(t *testing.T, remoteVAs []RemoteVA) (*ValidationAuthorityImpl, *mocks.Mailer, string) {
+	va, _ := setup(nil, 0, "", remoteVAs)
+	dir, err := ioutil.TempDir("", "maildir")
+	test.AssertNotError(t, err, "creating Maildir")
+	for _, sub := range []string{"new", "cur", "tmp"} {
+		test.AssertNotError(t, os.Mkdir(filepath.Join(dir, sub), 0700), "creating Maildir")
+	}
+	mailer := &mocks.Mailer{}
+	err = va.SetEmailReply(EmailReplyConfig{
+		Mailer:         mailer,
+		Replies:        MaildirReplies{Dir: dir},
+		Secret:         []byte("0123456789abcdef0123456789abcdef"),
+		ResendInterval: time.Hour,
+	})
+	test.AssertNotError(t, err, "SetEmailReply failed")
+	return va, mailer, dir
+}
+
+// deliverReply writes a reply to the Maildir, with the correct response for
+// the challenge if response is empty.
+func deliverReply(t *testing.T, va *ValidationAuthorityImpl, dir, from string, chall core.Challenge, response string) {
+	tokenPart1 := va.emailReply.tokenPart1(chall.Token)
+	if response == "" {
+		digest := sha256.Sum256([]byte(tokenPart1 + chall.ProvidedKeyAuthorization))
+		response = base64.RawURLEncoding.EncodeToString(digest[:])
+	}
+	msg := fmt.Sprintf("From: %s\r\nSubject: Re: ACME: %s\r\n\r\n%s\r\n%s\r\n%s\r\n",
+		from, tokenPart1, beginACMEResponse, response, endACMEResponse)
+	err := ioutil.WriteFile(filepath.Join(dir, "new", "reply"), []byte(msg), 0600)
+	test.AssertNotError(t, err, "delivering reply")
+}
+
+func emailReplyChallenge() core.Challenge {
+	chall := core.EmailReplyChallenge00(expectedToken)
+	chall.ProvidedKeyAuthorization = expectedKeyAuthorization
+	return chall
+}
+
+func TestValidateEmailReply00(t *testing.T) {
+	ident := identifier.EmailIdentifier("user@example.com")
+	chall := emailReplyChallenge()
+
+	// Without a configuration, email-reply-00 challenges are rejected.
+	va, _ := setup(nil, 0, "", nil)
+	_, prob, _ := va.validateEmailReply00(ctx, ident, chall)
+	test.AssertEquals(t, prob.Type, probs.MalformedProblem)
+
+	// Without a reply, a challenge email is sent and the challenge is left
+	// pending.
+	va, mailer, dir := setupEmailReply(t, nil)
+	defer os.RemoveAll(dir)
+	records, prob, awaiting := va.validateEmailReply00(ctx, ident, chall)
+	test.Assert(t, prob == nil, fmt.Sprintf("validation failed: %s", prob))
+	test.Assert(t, awaiting, "validation without a reply isn't awaiting one")
+	test.AssertDeepEquals(t, records, []core.ValidationRecord{{Hostname: "user@example.com"}})
+	test.AssertEquals(t, len(mailer.Messages), 1)
+	test.AssertEquals(t, mailer.Messages[0].To, "user@example.com")
+	test.AssertEquals(t, mailer.Messages[0].Subject, "ACME: "+va.emailReply.tokenPart1(chall.Token))
+
+	// A reply from another address is ignored, and the challenge email isn't
+	// sent again until the resend interval is over.
+	mailer.Clear()
+	deliverReply(t, va, dir, "other@example.com", chall, "")
+	_, prob, awaiting = va.validateEmailReply00(ctx, ident, chall)
+	test.Assert(t, prob == nil && awaiting, "validation with a reply from another address isn't awaiting one")
+	test.AssertEquals(t, len(mailer.Messages), 0)
+	va.clk.(clock.FakeClock).Add(time.Hour)
+	_, _, awaiting = va.validateEmailReply00(ctx, ident, chall)
+	test.Assert(t, awaiting, "validation without a reply isn't awaiting one")
+	test.AssertEquals(t, len(mailer.Messages), 1)
+
+	// A reply with the wrong response fails validation.
+	deliverReply(t, va, dir, "User <user@example.com>", chall, "wrong")
+	_, prob, _ = va.validateEmailReply00(ctx, ident, chall)
+	test.AssertEquals(t, prob.Type, probs.UnauthorizedProblem)
+	test.AssertContains(t, prob.Detail, "Incorrect ACME response")
+
+	// A reply with the right response passes, without another email being
+	// sent.
+	mailer.Clear()
+	deliverReply(t, va, dir, "User <user@example.com>", chall, "")
+	records, prob, awaiting = va.validateEmailReply00(ctx, ident, chall)
+	test.Assert(t, prob == nil, fmt.Sprintf("validation failed: %s", prob))
+	test.Assert(t, !awaiting, "validation with a reply is awaiting one")
+	test.AssertDeepEquals(t, records, []core.ValidationRecord{{Hostname: "user@example.com"}})
+	test.AssertEquals(t, len(mailer.Messages), 0)
+
+	// DNS identifiers can't be validated with email-reply-00.
+	_, prob, _ = va.validateEmailReply00(ctx, identifier.DNSIdentifier("example.com"), chall)
+	test.AssertEquals(t, prob.Type, probs.MalformedProblem)
+}
+
+// countingRemoteVA is a remote VA which counts the validations it's asked to
+// perform.
+type countingRemoteVA struct {
+	calls int
+}
+
+func (v *countingRemoteVA) PerformValidation(_ context.Context, _ *vapb.PerformValidationRequest, _ ...grpc.CallOption) (*vapb.ValidationResult, error) {
+	v.calls++
+	return &vapb.ValidationResult{}, nil
+}
+
+func TestPerformValidationEmailReply00(t *testing.T) {
+	remote := &countingRemoteVA{}
+	va, mailer, dir := setupEmailReply(t, []RemoteVA{{remote, "remote"}})
+	defer os.RemoveAll(dir)
+
+	req := createValidationRequest("user@example.com", core.ChallengeTypeEmailReply00)
+	res, err := va.PerformValidation(ctx, req)
+	test.AssertNotError(t, err, "PerformValidation failed")
+	test.Assert(t, res.AwaitingReply, "validation without a reply isn't awaiting one")
+	test.Assert(t, res.Problems == nil, "validation without a reply has problems")
+	test.AssertEquals(t, len(mailer.Messages), 1)
+
+	deliverReply(t, va, dir, "user@example.com", emailReplyChallenge(), "")
+	res, err = va.PerformValidation(ctx, req)
+	test.AssertNotError(t, err, "PerformValidation failed")
+	test.Assert(t, !res.AwaitingReply, "validation with a reply is awaiting one")
+	test.Assert(t, res.Problems == nil, "validation with a reply has problems")
+
+	// Each remote VA would send its own challenge email.
+	test.AssertEquals(t, remote.calls, 0)
+}
+
+func TestACMEResponse(t *testing.T) {
+	response, ok := acmeResponse("> quoted\n" + beginACMEResponse + "\n  abc-_123 \n" + endACMEResponse + "\n")
+	test.Assert(t, ok, "response not found")
+	test.AssertEquals(t, response, "abc-_123")
+
+	_, ok = acmeResponse(beginACMEResponse + "\nabc\n")
+	test.Assert(t, !ok, "response without an END line found")
+}
@@ -13,7 +13,9 @@ import (
 	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 
+	"github.com/letsencrypt/boulder/bdns"
 	"github.com/letsencrypt/boulder/core"
 	berrors "github.com/letsencrypt/boulder/errors"
 	"github.com/letsencrypt/boulder/iana"
@@ -127,7 +129,7 @@ func (d *preresolvedDialer) DialContext(
 	targetAddr := net.JoinHostPort(d.ip.String(), strconv.Itoa(d.port))
 
 	// Create a throw-away dialer using default values and the dialer timeout
-	// (populated from the VA singleDialTimeout).
+	// (populated from the VA's HTTP-01 connect timeout).
 	throwAwayDialer := &net.Dialer{
 		Timeout: d.timeout,
 		// Default KeepAlive - see Golang src/net/http/transport.go DefaultTransport
@@ -214,10 +216,17 @@ func (va *ValidationAuthorityImpl) newHTTPValidationTarget(
 	port int,
 	path string,
 	query string) (*httpValidationTarget, error) {
-	// Resolve IP addresses for the hostname
-	addrs, err := va.getAddrs(ctx, host)
-	if err != nil {
-		return nil, err
+	// Resolve IP addresses for the hostname, unless it's an IP address, which
+	// the redirect policy allowed.
+	var addrs []net.IP
+	if ip := net.ParseIP(host); ip != nil {
+		addrs = []net.IP{ip}
+	} else {
+		var err error
+		addrs, err = va.getAddrs(ctx, host)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	target := &httpValidationTarget{
@@ -270,11 +279,14 @@ func (va *ValidationAuthorityImpl) extractRequestTarget(req *http.Request) (stri
 
 	reqScheme := req.URL.Scheme
 
-	// The redirect request must use HTTP or HTTPs protocol schemes regardless of the port..
-	if reqScheme != "http" && reqScheme != "https" {
+	// The redirect request must use a protocol scheme allowed by the redirect
+	// policy, HTTP or HTTPS by default, regardless of the port.
+	schemeAllowed, allowedSchemes := va.redirectPolicy.schemeAllowed(reqScheme)
+	va.redirectDecision("scheme", schemeAllowed)
+	if !schemeAllowed {
 		return "", 0, berrors.ConnectionFailureError(
 			"Invalid protocol scheme in redirect target. "+
-				`Only "http" and "https" protocol schemes are supported, not %q`, reqScheme)
+				`Only %s protocol schemes are supported, not %q`, allowedSchemes, reqScheme)
 	}
 
 	// Try and split an explicit port number from the request URL host. If there is
@@ -289,11 +301,18 @@ func (va *ValidationAuthorityImpl) extractRequestTarget(req *http.Request) (stri
 			return "", 0, err
 		}
 
-		// The explicit port must match the VA's configured HTTP or HTTPS port.
-		if reqPort != va.httpPort && reqPort != va.httpsPort {
+		// The explicit port must be allowed by the redirect policy, which by
+		// default only allows the VA's configured HTTP or HTTPS port.
+		portAllowed := va.redirectPolicy.portAllowed(reqPort, va.httpPort, va.httpsPort)
+		va.redirectDecision("port", portAllowed)
+		if !portAllowed && len(va.redirectPolicy.AllowedPorts) == 0 {
 			return "", 0, berrors.ConnectionFailureError(
 				"Invalid port in redirect target. Only ports %d and %d are supported, not %d",
 				va.httpPort, va.httpsPort, reqPort)
+		} else if !portAllowed {
+			return "", 0, berrors.ConnectionFailureError(
+				"Invalid port in redirect target. Only ports %v are supported, not %d",
+				va.redirectPolicy.AllowedPorts, reqPort)
 		}
 	} else if reqScheme == "http" {
 		reqPort = va.httpPort
@@ -309,12 +328,18 @@ func (va *ValidationAuthorityImpl) extractRequestTarget(req *http.Request) (stri
 		return "", 0, berrors.ConnectionFailureError("Invalid empty hostname in redirect target")
 	}
 
-	// Check that the request host isn't a bare IP address. We only follow
-	// redirects to hostnames.
-	if net.ParseIP(reqHost) != nil {
-		return "", 0, berrors.ConnectionFailureError(
-			"Invalid host in redirect target %q. "+
-				"Only domain names are supported, not IP addresses", reqHost)
+	// Check that the request host isn't a bare IP address. Unless the redirect
+	// policy allows public IP addresses, we only follow redirects to hostnames.
+	// IPv6 addresses without an explicit port are still bracketed.
+	if ip := net.ParseIP(strings.TrimSuffix(strings.TrimPrefix(reqHost, "["), "]")); ip != nil {
+		ipAllowed := va.redirectPolicy.AllowIPTargets && !bdns.IsPrivateIP(ip)
+		va.redirectDecision("ip_target", ipAllowed)
+		if !ipAllowed {
+			return "", 0, berrors.ConnectionFailureError(
+				"Invalid host in redirect target %q. "+
+					"Only domain names are supported, not IP addresses", reqHost)
+		}
+		return ip.String(), reqPort, nil
 	}
 
 	// Often folks will misconfigure their webserver to send an HTTP redirect
@@ -386,11 +411,35 @@ func (va *ValidationAuthorityImpl) setupHTTPValidation(
 		ip:       targetIP,
 		port:     target.port,
 		hostname: target.host,
-		timeout:  va.singleDialTimeout,
+		timeout:  va.connectTimeout(core.ChallengeTypeHTTP01),
 	}
 	return dialer, record, nil
 }
 
+// maxServerHeaderSize is the number of bytes of a response's Server header
+// which are kept in its validation record.
+const maxServerHeaderSize = 256
+
+// recordHTTPResponse notes in a validation record what the server which sent
+// an HTTP response presented: its Server header and, if the response was
+// received over HTTPS, its TLS connection. The Server header is made valid
+// UTF-8, since the record is marshalled into proto3 strings, and truncated on
+// a rune boundary so that it stays valid.
+func recordHTTPResponse(record *core.ValidationRecord, resp *http.Response) {
+	server := strings.ToValidUTF8(resp.Header.Get("Server"), string(utf8.RuneError))
+	if len(server) > maxServerHeaderSize {
+		end := maxServerHeaderSize
+		for end > 0 && !utf8.RuneStart(server[end]) {
+			end--
+		}
+		server = server[:end]
+	}
+	record.ServerHeader = server
+	if resp.TLS != nil {
+		recordTLSConnection(record, resp.TLS)
+	}
+}
+
 // fetchHTTP invokes processHTTPValidation and if an error result is
 // returned, converts it to a problem. Otherwise the results from
 // processHTTPValidation are returned.
@@ -399,6 +448,7 @@ func (va *ValidationAuthorityImpl) fetchHTTP(
 	host string,
 	path string) ([]byte, []core.ValidationRecord, *probs.ProblemDetails) {
 	body, records, err := va.processHTTPValidation(ctx, host, path)
+	records = va.redirectPolicy.records(records)
 	if err != nil {
 		// Use detailedError to convert the error into a problem
 		return body, records, detailedError(err)
@@ -498,13 +548,20 @@ func (va *ValidationAuthorityImpl) processHTTPValidation(
 	numRedirects := 0
 	processRedirect := func(req *http.Request, via []*http.Request) error {
 		va.log.Debugf("processing a HTTP redirect from the server to %q", req.URL.String())
-		// Only process up to maxRedirect redirects
-		if numRedirects > maxRedirect {
+		// Only process up to the redirect policy's maximum number of redirects
+		depthAllowed := numRedirects <= va.redirectPolicy.maxRedirects()
+		va.redirectDecision("depth", depthAllowed)
+		if !depthAllowed {
 			return berrors.ConnectionFailureError("Too many redirects")
 		}
 		numRedirects++
 		va.metrics.http01Redirects.Inc()
 
+		// The redirect response came from the target of the latest record.
+		if req.Response != nil {
+			recordHTTPResponse(&records[len(records)-1], req.Response)
+		}
+
 		// Lowercase the redirect host immediately, as the dialer and redirect
 		// validation expect it to have been lowercased already.
 		req.URL.Host = strings.ToLower(req.URL.Host)
@@ -604,6 +661,7 @@ func (va *ValidationAuthorityImpl) processHTTPValidation(
 
 	// At this point we've made a successful request (be it from a retry or
 	// otherwise) and can read and process the response body.
+	recordHTTPResponse(&records[len(records)-1], httpResponse)
 	body, err := ioutil.ReadAll(&io.LimitedReader{R: httpResponse.Body, N: maxResponseSize})
 	closeErr := httpResponse.Body.Close()
 	if err == nil {
@@ -1418,6 +1418,67 @@ func TestHTTPRedirectLookup(t *testing.T) {
 				va.httpPort)))
 }
 
+func TestHTTPServerMetadata(t *testing.T) {
+	chall := httpChallenge()
+	setChallengeToken(&chall, pathMoved)
+	m := http.NewServeMux()
+	hs := httptest.NewUnstartedServer(m)
+	m.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, pathMoved) {
+			w.Header().Set("Server", "redirector/1.0")
+			http.Redirect(w, r, pathValid, http.StatusMovedPermanently)
+			return
+		}
+		w.Header().Set("Server", strings.Repeat("a", maxServerHeaderSize+1))
+		fmt.Fprint(w, chall.ProvidedKeyAuthorization)
+	})
+	hs.Start()
+	defer hs.Close()
+	va, _ := setup(hs, 0, "", nil)
+
+	records, prob := va.validateHTTP01(ctx, dnsi("localhost.com"), chall)
+	test.Assert(t, prob == nil, fmt.Sprintf("Unexpected problem: %s", prob))
+	test.AssertEquals(t, len(records), 2)
+	test.AssertEquals(t, records[0].ServerHeader, "redirector/1.0")
+	test.AssertEquals(t, records[1].ServerHeader, strings.Repeat("a", maxServerHeaderSize))
+	// The TLS fields are only set for responses received over HTTPS.
+	test.AssertEquals(t, records[1].NegotiatedProtocol, "")
+	test.AssertEquals(t, records[1].CertificateSHA256, "")
+}
+
+func TestRecordHTTPResponseServerHeader(t *testing.T) {
+	testCases := []struct {
+		name     string
+		header   string
+		expected string
+	}{
+		{
+			name:     "Valid",
+			header:   "nginx/1.18.0",
+			expected: "nginx/1.18.0",
+		},
+		{
+			name:     "Invalid UTF-8",
+			header:   "server\xff\xfe/1.0",
+			expected: "server\uFFFD/1.0",
+		},
+		{
+			name:     "Truncated on a rune boundary",
+			header:   strings.Repeat("a", maxServerHeaderSize-1) + "\u00e9",
+			expected: strings.Repeat("a", maxServerHeaderSize-1),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp := &http.Response{Header: http.Header{"Server": []string{tc.header}}}
+			var record core.ValidationRecord
+			recordHTTPResponse(&record, resp)
+			test.AssertEquals(t, record.ServerHeader, tc.expected)
+			test.Assert(t, utf8.ValidString(record.ServerHeader), "Server header isn't valid UTF-8")
+		})
+	}
+}
+
 func TestHTTPRedirectLoop(t *testing.T) {
 	hs := httpSrv(t, expectedToken)
 	defer hs.Close()
@@ -0,0 +1,132 @@
+package va
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/letsencrypt/boulder/bdns"
+	"github.com/letsencrypt/boulder/cmd"
+	"github.com/letsencrypt/boulder/core"
+	"github.com/letsencrypt/boulder/identifier"
+	"github.com/letsencrypt/boulder/probs"
+)
+
+// ChallengeLimits configures how long the VA spends validating a type of
+// challenge, and whether it retries validations which fail with transient
+// errors. Its zero value is the VA's default behaviour.
+type ChallengeLimits struct {
+	// ConnectTimeout bounds each connection made to the applicant's server.
+	// If zero, the VA's default of 10 seconds is used. It doesn't apply to
+	// dns-01 challenges, whose lookups are bounded by the DNS timeout.
+	ConnectTimeout cmd.ConfigDuration
+	// AttemptBudget bounds the whole validation, including any retries. If
+	// zero, only the deadline of the validation request applies.
+	AttemptBudget cmd.ConfigDuration
+	// Retries is the number of times a validation which fails with a
+	// connection or DNS problem is retried, as long as AttemptBudget allows.
+	Retries int
+	// RetryDelay is how long the VA waits before each retry.
+	RetryDelay cmd.ConfigDuration
+}
+
+// SetChallengeLimits configures limits for the given challenge types, which
+// must be http-01, dns-01 or tls-alpn-01. It must be called before the VA
+// starts validating challenges.
+func (va *ValidationAuthorityImpl) SetChallengeLimits(limits map[string]ChallengeLimits) error {
+	byType := make(map[core.AcmeChallenge]ChallengeLimits, len(limits))
+	for name, l := range limits {
+		challType := core.AcmeChallenge(name)
+		switch challType {
+		case core.ChallengeTypeHTTP01, core.ChallengeTypeDNS01, core.ChallengeTypeTLSALPN01:
+		default:
+			return fmt.Errorf("can't configure limits for challenge type %q", name)
+		}
+		if l.ConnectTimeout.Duration < 0 || l.AttemptBudget.Duration < 0 || l.RetryDelay.Duration < 0 {
+			return fmt.Errorf("%s limits must not be negative", name)
+		}
+		if l.Retries < 0 {
+			return fmt.Errorf("%s retries must not be negative", name)
+		}
+		if l.AttemptBudget.Duration != 0 && l.ConnectTimeout.Duration > l.AttemptBudget.Duration {
+			return fmt.Errorf("%s connect timeout must not exceed its attempt budget", name)
+		}
+		byType[challType] = l
+	}
+	va.challengeLimits = byType
+	return nil
+}
+
+// connectTimeout returns how long each connection made while validating a
+// challenge of the given type may take.
+func (va *ValidationAuthorityImpl) connectTimeout(challType core.AcmeChallenge) time.Duration {
+	if l, ok := va.challengeLimits[challType]; ok && l.ConnectTimeout.Duration != 0 {
+		return l.ConnectTimeout.Duration
+	}
+	return va.singleDialTimeout
+}
+
+// transientProblem returns true if a validation which failed with prob might
+// succeed if it were retried.
+func transientProblem(prob *probs.ProblemDetails) bool {
+	return prob.Type == probs.ConnectionProblem || prob.Type == probs.DNSProblem
+}
+
+// validateWithLimits validates the challenge within the limits configured for
+// its type, retrying it after transient failures, and records the limits that
+// applied in each of the final attempt's validation records.
+func (va *ValidationAuthorityImpl) validateWithLimits(
+	ctx context.Context,
+	ident identifier.ACMEIdentifier,
+	challenge core.Challenge,
+	limits ChallengeLimits,
+) ([]core.ValidationRecord, *probs.ProblemDetails) {
+	if limits.AttemptBudget.Duration != 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, limits.AttemptBudget.Duration)
+		defer cancel()
+	}
+
+	var records []core.ValidationRecord
+	var prob *probs.ProblemDetails
+	var attempts int64
+	for {
+		attempts++
+		attemptCtx := ctx
+		if attempts > 1 {
+			// Give each retry its own DNS attempt cache, so that it doesn't
+			// reuse the responses which may have caused the failure.
+			attemptCtx = bdns.WithAttemptCache(ctx)
+		}
+		records, prob = va.validateChallengeType(attemptCtx, ident, challenge)
+		if prob == nil || !transientProblem(prob) || attempts > int64(limits.Retries) {
+			break
+		}
+		va.metrics.validationRetries.WithLabelValues(string(challenge.Type)).Inc()
+		va.log.Infof("Retrying %s validation for %s after transient problem: %s", challenge.Type, ident, prob)
+		if limits.RetryDelay.Duration != 0 {
+			select {
+			case <-ctx.Done():
+			case <-va.clk.After(limits.RetryDelay.Duration):
+			}
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	connectTimeout := ""
+	if challenge.Type != core.ChallengeTypeDNS01 {
+		connectTimeout = va.connectTimeout(challenge.Type).String()
+	}
+	attemptBudget := ""
+	if limits.AttemptBudget.Duration != 0 {
+		attemptBudget = limits.AttemptBudget.Duration.String()
+	}
+	for i := range records {
+		records[i].ConnectTimeout = connectTimeout
+		records[i].AttemptBudget = attemptBudget
+		records[i].Attempts = attempts
+	}
+	return records, prob
+}
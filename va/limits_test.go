@@ -0,0 +1,105 @@
+package va
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/letsencrypt/boulder/bdns"
+	"github.com/letsencrypt/boulder/cmd"
+	"github.com/letsencrypt/boulder/core"
+	"github.com/letsencrypt/boulder/probs"
+	"github.com/letsencrypt/boulder/test"
+)
+
+func TestSetChallengeLimits(t *testing.T) {
+	va, _ := setup(nil, 0, "", nil)
+	second := cmd.ConfigDuration{Duration: time.Second}
+	test.AssertError(t, va.SetChallengeLimits(map[string]ChallengeLimits{
+		"email-reply-00": {Retries: 1},
+	}), "limits for email-reply-00 accepted")
+	test.AssertError(t, va.SetChallengeLimits(map[string]ChallengeLimits{
+		"http-01": {Retries: -1},
+	}), "negative retries accepted")
+	test.AssertError(t, va.SetChallengeLimits(map[string]ChallengeLimits{
+		"http-01": {ConnectTimeout: cmd.ConfigDuration{Duration: -time.Second}},
+	}), "negative connect timeout accepted")
+	test.AssertError(t, va.SetChallengeLimits(map[string]ChallengeLimits{
+		"tls-alpn-01": {ConnectTimeout: cmd.ConfigDuration{Duration: 2 * time.Second}, AttemptBudget: second},
+	}), "connect timeout longer than attempt budget accepted")
+
+	test.AssertNotError(t, va.SetChallengeLimits(map[string]ChallengeLimits{
+		"http-01": {ConnectTimeout: second},
+		"dns-01":  {Retries: 2},
+	}), "valid limits rejected")
+	test.AssertEquals(t, va.connectTimeout(core.ChallengeTypeHTTP01), time.Second)
+	test.AssertEquals(t, va.connectTimeout(core.ChallengeTypeTLSALPN01), 10*time.Second)
+}
+
+// flakyTXT fails the first failures TXT lookups it's asked to make.
+type flakyTXT struct {
+	*bdns.MockClient
+	failures int
+	lookups  int
+}
+
+func (f *flakyTXT) LookupTXT(ctx context.Context, hostname string) ([]string, error) {
+	f.lookups++
+	if f.lookups <= f.failures {
+		return nil, errors.New("SERVFAIL")
+	}
+	return f.MockClient.LookupTXT(ctx, hostname)
+}
+
+func TestValidateWithLimitsRetries(t *testing.T) {
+	va, _ := setup(nil, 0, "", nil)
+	err := va.SetChallengeLimits(map[string]ChallengeLimits{
+		"dns-01": {AttemptBudget: cmd.ConfigDuration{Duration: time.Minute}, Retries: 2},
+	})
+	test.AssertNotError(t, err, "setting challenge limits")
+
+	// A transient failure is retried, and the records note the limits which
+	// applied and how many attempts were made.
+	client := &flakyTXT{MockClient: &bdns.MockClient{}, failures: 2}
+	va.dnsClient = client
+	records, prob := va.validateChallenge(context.Background(), dnsi("good-dns01.com"), dnsChallenge())
+	test.Assert(t, prob == nil, "validation failed despite retries")
+	test.AssertEquals(t, client.lookups, 3)
+	test.AssertEquals(t, len(records), 1)
+	test.AssertEquals(t, records[0].AttemptBudget, "1m0s")
+	test.AssertEquals(t, records[0].ConnectTimeout, "")
+	test.AssertEquals(t, records[0].Attempts, int64(3))
+
+	// Retries stop once they've been used up.
+	client = &flakyTXT{MockClient: &bdns.MockClient{}, failures: 5}
+	va.dnsClient = client
+	_, prob = va.validateChallenge(context.Background(), dnsi("good-dns01.com"), dnsChallenge())
+	test.Assert(t, prob != nil, "validation succeeded despite failing lookups")
+	test.AssertEquals(t, prob.Type, probs.DNSProblem)
+	test.AssertEquals(t, client.lookups, 3)
+
+	// Problems which aren't transient aren't retried.
+	client = &flakyTXT{MockClient: &bdns.MockClient{}}
+	va.dnsClient = client
+	_, prob = va.validateChallenge(context.Background(), dnsi("wrong-dns01.com"), dnsChallenge())
+	test.Assert(t, prob != nil, "validation succeeded with wrong TXT record")
+	test.AssertEquals(t, prob.Type, probs.UnauthorizedProblem)
+	test.AssertEquals(t, client.lookups, 1)
+}
+
+func TestValidateWithoutLimits(t *testing.T) {
+	va, _ := setup(nil, 0, "", nil)
+	client := &flakyTXT{MockClient: &bdns.MockClient{}, failures: 1}
+	va.dnsClient = client
+
+	// Without limits for the challenge's type it's attempted once, and its
+	// records don't mention limits.
+	_, prob := va.validateChallenge(context.Background(), dnsi("good-dns01.com"), dnsChallenge())
+	test.Assert(t, prob != nil, "validation succeeded despite failing lookup")
+	test.AssertEquals(t, client.lookups, 1)
+	records, prob := va.validateChallenge(context.Background(), dnsi("good-dns01.com"), dnsChallenge())
+	test.Assert(t, prob == nil, "validation failed")
+	test.AssertEquals(t, records[0].Attempts, int64(0))
+	test.AssertEquals(t, records[0].AttemptBudget, "")
+}
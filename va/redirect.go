@@ -0,0 +1,111 @@
+package va
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/letsencrypt/boulder/core"
+)
+
+// RedirectPolicy configures which redirects the VA follows while validating
+// HTTP-01 challenges, and what it records of them. Its zero value is the VA's
+// default policy.
+type RedirectPolicy struct {
+	// MaxRedirects is the number of redirects followed before validation
+	// fails. If zero, maxRedirect is used.
+	MaxRedirects int
+	// AllowIPTargets permits redirects to hosts which are bare IP addresses,
+	// rather than domain names, as long as they aren't private or reserved.
+	AllowIPTargets bool
+	// AllowedPorts are the explicit ports permitted in redirect targets. If
+	// empty, only the VA's HTTP and HTTPS ports are. Targets without an
+	// explicit port always use the VA's port for their scheme.
+	AllowedPorts []int
+	// AllowedSchemes are the schemes permitted in redirect targets, which
+	// must be "http" or "https". If empty, both are.
+	AllowedSchemes []string
+	// OmitIntermediateRecords keeps only the first and last validation
+	// records, rather than one for every request in the redirect chain.
+	OmitIntermediateRecords bool
+}
+
+// SetRedirectPolicy replaces the VA's default RedirectPolicy. It must be
+// called before the VA starts validating challenges.
+func (va *ValidationAuthorityImpl) SetRedirectPolicy(policy RedirectPolicy) error {
+	if policy.MaxRedirects < 0 {
+		return errors.New("redirect policy's maximum redirects must not be negative")
+	}
+	for _, port := range policy.AllowedPorts {
+		if port <= 0 || port > 65535 {
+			return fmt.Errorf("redirect policy has invalid port %d", port)
+		}
+	}
+	for _, scheme := range policy.AllowedSchemes {
+		if scheme != "http" && scheme != "https" {
+			return fmt.Errorf("redirect policy has unsupported scheme %q", scheme)
+		}
+	}
+	va.redirectPolicy = policy
+	return nil
+}
+
+// maxRedirects returns the number of redirects the policy allows.
+func (p RedirectPolicy) maxRedirects() int {
+	if p.MaxRedirects == 0 {
+		return maxRedirect
+	}
+	return p.MaxRedirects
+}
+
+// schemeAllowed returns true if redirects to the scheme are permitted, and
+// a description of the permitted schemes for error messages.
+func (p RedirectPolicy) schemeAllowed(scheme string) (bool, string) {
+	if len(p.AllowedSchemes) == 0 {
+		return scheme == "http" || scheme == "https", `"http" and "https"`
+	}
+	var quoted []string
+	allowed := false
+	for _, s := range p.AllowedSchemes {
+		quoted = append(quoted, fmt.Sprintf("%q", s))
+		if s == scheme {
+			allowed = true
+		}
+	}
+	return allowed, strings.Join(quoted, " and ")
+}
+
+// portAllowed returns true if an explicit port is permitted in redirect
+// targets. httpPort and httpsPort are the VA's ports, which are the only ones
+// permitted by default.
+func (p RedirectPolicy) portAllowed(port, httpPort, httpsPort int) bool {
+	if len(p.AllowedPorts) == 0 {
+		return port == httpPort || port == httpsPort
+	}
+	for _, allowed := range p.AllowedPorts {
+		if port == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// records returns the validation records to keep for a redirect chain.
+func (p RedirectPolicy) records(records []core.ValidationRecord) []core.ValidationRecord {
+	if !p.OmitIntermediateRecords || len(records) <= 2 {
+		return records
+	}
+	return []core.ValidationRecord{records[0], records[len(records)-1]}
+}
+
+// redirectDecision counts a decision the redirect policy made about a
+// redirect, by the check which made it and whether the redirect was allowed.
+func (va *ValidationAuthorityImpl) redirectDecision(check string, allowed bool) {
+	result := "denied"
+	if allowed {
+		result = "allowed"
+	}
+	va.metrics.http01RedirectDecisions.With(prometheus.Labels{"check": check, "result": result}).Inc()
+}
@@ -0,0 +1,112 @@
+package va
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/letsencrypt/boulder/core"
+	berrors "github.com/letsencrypt/boulder/errors"
+	"github.com/letsencrypt/boulder/probs"
+	"github.com/letsencrypt/boulder/test"
+)
+
+func TestSetRedirectPolicy(t *testing.T) {
+	va, _ := setup(nil, 0, "", nil)
+	test.AssertError(t, va.SetRedirectPolicy(RedirectPolicy{MaxRedirects: -1}), "negative MaxRedirects accepted")
+	test.AssertError(t, va.SetRedirectPolicy(RedirectPolicy{AllowedPorts: []int{0}}), "port 0 accepted")
+	test.AssertError(t, va.SetRedirectPolicy(RedirectPolicy{AllowedSchemes: []string{"gopher"}}), "gopher scheme accepted")
+	test.AssertNotError(t, va.SetRedirectPolicy(RedirectPolicy{
+		MaxRedirects:   3,
+		AllowedPorts:   []int{80, 8080},
+		AllowedSchemes: []string{"https"},
+	}), "SetRedirectPolicy failed")
+}
+
+func TestRedirectPolicyTargets(t *testing.T) {
+	va, _ := setup(nil, 0, "", nil)
+	err := va.SetRedirectPolicy(RedirectPolicy{
+		AllowIPTargets: true,
+		AllowedPorts:   []int{8080},
+		AllowedSchemes: []string{"https"},
+	})
+	test.AssertNotError(t, err, "SetRedirectPolicy failed")
+
+	extract := func(target string) (string, int, error) {
+		u, err := url.Parse(target)
+		test.AssertNotError(t, err, "parsing redirect target")
+		return va.extractRequestTarget(&http.Request{URL: u})
+	}
+	decisions := func(check, result string) int {
+		return test.CountCounter(va.metrics.http01RedirectDecisions.With(prometheus.Labels{"check": check, "result": result}))
+	}
+
+	// Only the allowed schemes are followed.
+	_, _, err = extract("http://example.com/")
+	test.AssertErrorIs(t, err, berrors.ConnectionFailure)
+	test.AssertContains(t, err.Error(), `Only "https" protocol schemes are supported`)
+	test.AssertEquals(t, decisions("scheme", "denied"), 1)
+
+	// Only the allowed explicit ports are followed.
+	host, port, err := extract("https://example.com:8080/")
+	test.AssertNotError(t, err, "redirect to allowed port rejected")
+	test.AssertEquals(t, host, "example.com")
+	test.AssertEquals(t, port, 8080)
+	_, _, err = extract("https://example.com:443/")
+	test.AssertErrorIs(t, err, berrors.ConnectionFailure)
+	test.AssertContains(t, err.Error(), "Only ports [8080] are supported, not 443")
+	test.AssertEquals(t, decisions("port", "allowed"), 1)
+	test.AssertEquals(t, decisions("port", "denied"), 1)
+
+	// Public IP addresses are followed, but not private ones.
+	host, port, err = extract("https://8.8.8.8/")
+	test.AssertNotError(t, err, "redirect to public IP rejected")
+	test.AssertEquals(t, host, "8.8.8.8")
+	test.AssertEquals(t, port, va.httpsPort)
+	_, _, err = extract("https://127.0.0.1/")
+	test.AssertErrorIs(t, err, berrors.ConnectionFailure)
+	_, _, err = extract("https://[::1]/")
+	test.AssertErrorIs(t, err, berrors.ConnectionFailure)
+	test.AssertEquals(t, decisions("ip_target", "allowed"), 1)
+	test.AssertEquals(t, decisions("ip_target", "denied"), 2)
+
+	// With the default policy IP addresses aren't followed.
+	va, _ = setup(nil, 0, "", nil)
+	_, _, err = extract("https://8.8.8.8/")
+	test.AssertErrorIs(t, err, berrors.ConnectionFailure)
+}
+
+func TestRedirectPolicyFetch(t *testing.T) {
+	testSrv := httpTestSrv(t)
+	defer testSrv.Close()
+	va, _ := setup(testSrv, 0, "", nil)
+
+	// The redirect depth is limited by the policy, and only the first and last
+	// records are kept if intermediate ones are omitted.
+	err := va.SetRedirectPolicy(RedirectPolicy{MaxRedirects: 2, OmitIntermediateRecords: true})
+	test.AssertNotError(t, err, "SetRedirectPolicy failed")
+	_, records, prob := va.fetchHTTP(ctx, "example.com", "/max-redirect/0")
+	test.AssertNotNil(t, prob, "too many redirects followed")
+	test.AssertEquals(t, prob.Type, probs.ConnectionProblem)
+	test.AssertContains(t, prob.Detail, "Too many redirects")
+	test.AssertEquals(t, len(records), 2)
+	test.AssertEquals(t, records[0].URL, "http://example.com/max-redirect/0")
+	test.AssertContains(t, records[1].URL, "/max-redirect/3")
+	test.AssertEquals(t, test.CountCounter(va.metrics.http01RedirectDecisions.With(prometheus.Labels{"check": "depth", "result": "denied"})), 1)
+
+	// Without omitting them, every redirect is recorded.
+	err = va.SetRedirectPolicy(RedirectPolicy{MaxRedirects: 2})
+	test.AssertNotError(t, err, "SetRedirectPolicy failed")
+	_, records, _ = va.fetchHTTP(ctx, "example.com", "/max-redirect/0")
+	test.AssertEquals(t, len(records), 4)
+}
+
+func TestRedirectPolicyRecords(t *testing.T) {
+	records := []core.ValidationRecord{{URL: "a"}, {URL: "b"}, {URL: "c"}}
+	test.AssertDeepEquals(t, RedirectPolicy{}.records(records), records)
+	test.AssertDeepEquals(t, RedirectPolicy{OmitIntermediateRecords: true}.records(records),
+		[]core.ValidationRecord{{URL: "a"}, {URL: "c"}})
+	test.AssertDeepEquals(t, RedirectPolicy{OmitIntermediateRecords: true}.records(records[:2]), records[:2])
+}
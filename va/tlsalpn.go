@@ -54,6 +54,17 @@ func certNames(cert *x509.Certificate) []string {
 	return names
 }
 
+// recordTLSConnection notes in a validation record the ALPN protocol
+// negotiated over a TLS connection and the fingerprint of the leaf
+// certificate the server presented.
+func recordTLSConnection(record *core.ValidationRecord, cs *tls.ConnectionState) {
+	record.NegotiatedProtocol = cs.NegotiatedProtocol
+	if len(cs.PeerCertificates) > 0 {
+		fingerprint := sha256.Sum256(cs.PeerCertificates[0].Raw)
+		record.CertificateSHA256 = hex.EncodeToString(fingerprint[:])
+	}
+}
+
 func (va *ValidationAuthorityImpl) tryGetTLSCerts(ctx context.Context,
 	identifier identifier.ACMEIdentifier, challenge core.Challenge,
 	tlsConfig *tls.Config) ([]*x509.Certificate, *tls.ConnectionState, []core.ValidationRecord, *probs.ProblemDetails) {
@@ -153,7 +164,7 @@ func (va *ValidationAuthorityImpl) getTLSCerts(
 // tlsDial does the equivalent of tls.Dial, but obeying a context. Once
 // tls.DialContextWithDialer is available, switch to that.
 func (va *ValidationAuthorityImpl) tlsDial(ctx context.Context, hostPort string, config *tls.Config) (*tls.Conn, error) {
-	ctx, cancel := context.WithTimeout(ctx, va.singleDialTimeout)
+	ctx, cancel := context.WithTimeout(ctx, va.connectTimeout(core.ChallengeTypeTLSALPN01))
 	defer cancel()
 	dialer := &net.Dialer{}
 	netConn, err := dialer.DialContext(ctx, "tcp", hostPort)
@@ -187,6 +198,7 @@ func (va *ValidationAuthorityImpl) validateTLSALPN01(ctx context.Context, identi
 	if problem != nil {
 		return validationRecords, problem
 	}
+	recordTLSConnection(&validationRecords[0], cs)
 
 	if !cs.NegotiatedProtocolIsMutual || cs.NegotiatedProtocol != ACMETLS1Protocol {
 		errText := fmt.Sprintf(
@@ -370,11 +370,21 @@ func TestTLSALPN01Success(t *testing.T) {
 
 	va, _ := setup(hs, 0, "", nil)
 
-	_, prob := va.validateChallenge(ctx, dnsi("localhost"), chall)
+	records, prob := va.validateChallenge(ctx, dnsi("localhost"), chall)
 	if prob != nil {
 		t.Errorf("Validation failed: %v", prob)
 	}
 	test.AssertEquals(t, test.CountCounterVec("oid", IdPeAcmeIdentifier.String(), va.metrics.tlsALPNOIDCounter), 1)
+	// The record describes the server that answered.
+	acmeCert, err := hs.TLS.GetCertificate(&tls.ClientHelloInfo{
+		ServerName:      "localhost",
+		SupportedProtos: []string{ACMETLS1Protocol},
+	})
+	test.AssertNotError(t, err, "getting challenge certificate")
+	fingerprint := sha256.Sum256(acmeCert.Certificate[0])
+	test.AssertEquals(t, len(records), 1)
+	test.AssertEquals(t, records[0].NegotiatedProtocol, ACMETLS1Protocol)
+	test.AssertEquals(t, records[0].CertificateSHA256, hex.EncodeToString(fingerprint[:]))
 
 	hs.Close()
 	chall = tlsalpnChallenge()
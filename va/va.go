@@ -83,8 +83,10 @@ type vaMetrics struct {
 	tlsALPNOIDCounter                   *prometheus.CounterVec
 	http01Fallbacks                     prometheus.Counter
 	http01Redirects                     prometheus.Counter
+	http01RedirectDecisions             *prometheus.CounterVec
 	caaCounter                          *prometheus.CounterVec
 	ipv4FallbackCounter                 prometheus.Counter
+	validationRetries                   *prometheus.CounterVec
 }
 
 func initMetrics(stats prometheus.Registerer) *vaMetrics {
@@ -144,6 +146,13 @@ func initMetrics(stats prometheus.Registerer) *vaMetrics {
 			Help: "Number of HTTP-01 redirects followed",
 		})
 	stats.MustRegister(http01Redirects)
+	http01RedirectDecisions := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "http01_redirect_policy_decisions",
+			Help: "Number of HTTP-01 redirect policy decisions, by the check which made them and whether the redirect was allowed",
+		},
+		[]string{"check", "result"})
+	stats.MustRegister(http01RedirectDecisions)
 	caaCounter := prometheus.NewCounterVec(prometheus.CounterOpts{
 		Name: "caa_sets_processed",
 		Help: "A counter of CAA sets processed labelled by result",
@@ -154,6 +163,11 @@ func initMetrics(stats prometheus.Registerer) *vaMetrics {
 		Help: "A counter of IPv4 fallbacks during TLS ALPN validation",
 	})
 	stats.MustRegister(ipv4FallbackCounter)
+	validationRetries := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "validation_retries",
+		Help: "Number of validations retried after transient problems, by challenge type",
+	}, []string{"type"})
+	stats.MustRegister(validationRetries)
 
 	return &vaMetrics{
 		validationTime:                      validationTime,
@@ -164,8 +178,10 @@ func initMetrics(stats prometheus.Registerer) *vaMetrics {
 		tlsALPNOIDCounter:                   tlsALPNOIDCounter,
 		http01Fallbacks:                     http01Fallbacks,
 		http01Redirects:                     http01Redirects,
+		http01RedirectDecisions:             http01RedirectDecisions,
 		caaCounter:                          caaCounter,
 		ipv4FallbackCounter:                 ipv4FallbackCounter,
+		validationRetries:                   validationRetries,
 	}
 }
 
@@ -183,6 +199,9 @@ type ValidationAuthorityImpl struct {
 	maxRemoteFailures  int
 	accountURIPrefixes []string
 	singleDialTimeout  time.Duration
+	emailReply         *emailReplyValidator
+	redirectPolicy     RedirectPolicy
+	challengeLimits    map[core.AcmeChallenge]ChallengeLimits
 
 	metrics *vaMetrics
 }
@@ -360,6 +379,15 @@ func (va *ValidationAuthorityImpl) validateChallenge(ctx context.Context, identi
 	if err := challenge.CheckConsistencyForValidation(); err != nil {
 		return nil, probs.Malformed("Challenge failed consistency check: %s", err)
 	}
+	if limits, ok := va.challengeLimits[challenge.Type]; ok {
+		return va.validateWithLimits(ctx, identifier, challenge, limits)
+	}
+	return va.validateChallengeType(ctx, identifier, challenge)
+}
+
+// validateChallengeType makes a single attempt to validate the challenge with
+// the method for its type.
+func (va *ValidationAuthorityImpl) validateChallengeType(ctx context.Context, identifier identifier.ACMEIdentifier, challenge core.Challenge) ([]core.ValidationRecord, *probs.ProblemDetails) {
 	switch challenge.Type {
 	case core.ChallengeTypeHTTP01:
 		return va.validateHTTP01(ctx, identifier, challenge)
@@ -606,18 +634,23 @@ func (va *ValidationAuthorityImpl) PerformValidation(ctx context.Context, req *v
 	}
 	vStart := va.clk.Now()
 
+	challenge, err := bgrpc.PBToChallenge(req.Challenge)
+	if err != nil {
+		return nil, probs.ServerInternal("Challenge failed to deserialize")
+	}
+	if challenge.Type == core.ChallengeTypeEmailReply00 {
+		return va.performEmailReply00(ctx, req, challenge, logEvent)
+	}
+
 	var remoteResults chan *remoteValidationResult
 	if remoteVACount := len(va.remoteVAs); remoteVACount > 0 {
 		remoteResults = make(chan *remoteValidationResult, remoteVACount)
 		go va.performRemoteValidation(ctx, req, remoteResults)
 	}
 
-	challenge, err := bgrpc.PBToChallenge(req.Challenge)
-	if err != nil {
-		return nil, probs.ServerInternal("Challenge failed to deserialize")
-	}
-
-	records, prob := va.validate(ctx, identifier.DNSIdentifier(req.Domain), req.Authz.RegID, challenge)
+	// Lookups repeated during the attempt, for example of the CAA records of a
+	// name the challenge also queries, are only made once.
+	records, prob := va.validate(bdns.WithAttemptCache(ctx), identifier.FromValue(req.Domain), req.Authz.RegID, challenge)
 	challenge.ValidationRecord = records
 	localValidationLatency := time.Since(vStart)
 
@@ -633,7 +666,9 @@ func (va *ValidationAuthorityImpl) PerformValidation(ctx context.Context, req *v
 		challenge.Error = prob
 		logEvent.Error = prob.Error()
 	} else if remoteResults != nil {
-		if !features.Enabled(features.EnforceMultiVA) && features.Enabled(features.MultiVAFullResults) {
+		// EnforceMultiVA may be rolled out to a percentage of accounts.
+		enforceMultiVA := features.EnabledCtx(features.WithAccountID(ctx, req.Authz.RegID), features.EnforceMultiVA)
+		if !enforceMultiVA && features.Enabled(features.MultiVAFullResults) {
 			// If we're not going to enforce multi VA but we are logging the
 			// differentials then collect and log the remote results in a separate go
 			// routine to avoid blocking the primary VA.
@@ -652,7 +687,7 @@ func (va *ValidationAuthorityImpl) PerformValidation(ctx context.Context, req *v
 			challenge.Status = core.StatusValid
 			// Timestamp the valid challenge.
 			challenge.Validated = &vStart
-		} else if features.Enabled(features.EnforceMultiVA) {
+		} else if enforceMultiVA {
 			remoteProb := va.processRemoteResults(
 				req.Domain,
 				req.Authz.RegID,
@@ -0,0 +1,28 @@
+// Package webhook signs the requests Boulder makes to webhook endpoints, such
+// as the expiration-mailer's notifications and the akamai-purger's purge
+// requests, so that receivers can authenticate them.
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// SignatureHeader carries the signature of a webhook request, in the form
+// "t=<unix timestamp>,v1=<hex HMAC-SHA256>". The HMAC is computed with the
+// webhook's secret over the timestamp, a period, and the request body, so
+// receivers can reject both forged and replayed requests.
+const SignatureHeader = "Boulder-Signature"
+
+// Sign returns the value of the SignatureHeader for body, sent at now.
+func Sign(secret []byte, now time.Time, body []byte) string {
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return fmt.Sprintf("t=%s,v1=%s", timestamp, hex.EncodeToString(mac.Sum(nil)))
+}
@@ -0,0 +1,19 @@
+package webhook
+
+import (
+	"testing"
+	"time"
+
+	"github.com/letsencrypt/boulder/test"
+)
+
+func TestSign(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	body := []byte(`{"a":1}`)
+	test.AssertEquals(t, Sign([]byte("secret"), now, body),
+		"t=1600000000,v1=4e107d82910257d43758070322323c95b92af39939824d6610e2c9809a43b8d5")
+	test.Assert(t, Sign([]byte("other"), now, body) != Sign([]byte("secret"), now, body),
+		"signature doesn't depend on the secret")
+	test.Assert(t, Sign([]byte("secret"), now.Add(time.Second), body) != Sign([]byte("secret"), now, body),
+		"signature doesn't depend on the time")
+}
@@ -832,6 +832,14 @@ func (cr noopCAA) IsCAAValid(
 	return &vapb.IsCAAValidResponse{}, nil
 }
 
+func (cr noopCAA) IsCAAValidBatch(
+	ctx context.Context,
+	in *vapb.IsCAAValidBatchRequest,
+	opts ...grpc.CallOption,
+) (*vapb.IsCAAValidBatchResponse, error) {
+	return &vapb.IsCAAValidBatchResponse{Results: make([]*vapb.IsCAAValidResponse, len(in.Checks))}, nil
+}
+
 func TestRelativeDirectory(t *testing.T) {
 	wfe, _ := setupWFE(t)
 	mux := wfe.Handler(metrics.NoopRegisterer)
@@ -898,7 +906,8 @@ func TestIssueCertificate(t *testing.T) {
 	// authorized, etc.
 	stats := metrics.NoopRegisterer
 
-	ctp := ctpolicy.New(&mocks.Publisher{}, nil, nil, wfe.log, metrics.NoopRegisterer)
+	ctp, err := ctpolicy.New(&mocks.Publisher{}, nil, nil, wfe.log, metrics.NoopRegisterer)
+	test.AssertNotError(t, err, "Couldn't create CT policy")
 	ra := ra.NewRegistrationAuthorityImpl(
 		fc,
 		wfe.log,
@@ -0,0 +1,227 @@
+// Package policy normalizes the identifiers clients submit to the WFE, so that
+// equivalent spellings of a name reach the RA in a single canonical form and
+// names the CA won't issue for are rejected with a problem explaining why.
+package policy
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"golang.org/x/net/idna"
+
+	"github.com/letsencrypt/boulder/probs"
+)
+
+// The confusable-script policies an IDNPolicy can enforce on the labels of
+// internationalized domain names, after the restriction levels of Unicode
+// Technical Standard #39, section 5.2.
+const (
+	// ScriptPolicyAny permits labels to mix any scripts.
+	ScriptPolicyAny = "any"
+	// ScriptPolicySingle requires each label's characters to be from a
+	// single script.
+	ScriptPolicySingle = "single-script"
+	// ScriptPolicyHighlyRestrictive additionally permits Latin to be mixed
+	// with the scripts conventionally written alongside it in Chinese,
+	// Japanese and Korean.
+	ScriptPolicyHighlyRestrictive = "highly-restrictive"
+)
+
+// The outcomes of normalizing a DNS name, for metrics.
+const (
+	OutcomeUnchanged       = "unchanged"
+	OutcomeLowercased      = "lowercased"
+	OutcomeConverted       = "converted"
+	OutcomeUnicodeRejected = "unicode_rejected"
+	OutcomeInvalidIDN      = "invalid_idn"
+	OutcomeScriptRejected  = "script_rejected"
+)
+
+// highlyRestrictiveSets are the sets of scripts which labels may mix under
+// ScriptPolicyHighlyRestrictive.
+var highlyRestrictiveSets = [][]string{
+	{"Latin", "Han", "Hiragana", "Katakana"},
+	{"Latin", "Han", "Bopomofo"},
+	{"Latin", "Han", "Hangul"},
+}
+
+// profile converts between U-labels and A-labels per IDNA2008 (RFC 5891),
+// with the UTS #46 non-transitional mapping for lookup so that, for example,
+// uppercase and full-width characters are accepted in U-labels.
+var profile = idna.New(
+	idna.MapForLookup(),
+	idna.BidiRule(),
+	idna.VerifyDNSLength(true),
+)
+
+// IDNPolicy configures how internationalized domain names are normalized.
+type IDNPolicy struct {
+	// AllowUnicode permits names containing U-labels, which are converted to
+	// A-labels. Otherwise clients must submit them as A-labels.
+	AllowUnicode bool
+	// ScriptPolicy is the confusable-script policy enforced on the labels of
+	// internationalized names: "any", "single-script" or
+	// "highly-restrictive". Defaults to "any".
+	ScriptPolicy string
+	// AllowedScripts, if set, are the only scripts, named as in the Unicode
+	// standard such as "Cyrillic", which labels may use besides the Common
+	// and Inherited characters, like digits and combining marks, shared by
+	// all of them.
+	AllowedScripts []string
+}
+
+// Validate checks that the policy's script policy and scripts are known.
+func (p IDNPolicy) Validate() error {
+	switch p.ScriptPolicy {
+	case "", ScriptPolicyAny, ScriptPolicySingle, ScriptPolicyHighlyRestrictive:
+	default:
+		return fmt.Errorf("unknown script policy %q", p.ScriptPolicy)
+	}
+	for _, script := range p.AllowedScripts {
+		if _, ok := unicode.Scripts[script]; !ok {
+			return fmt.Errorf("unknown script %q", script)
+		}
+	}
+	return nil
+}
+
+// NormalizeDNSName returns the canonical form of a DNS name: lowercase, with
+// any U-labels converted to A-labels. It also returns the outcome of the
+// normalization, one of the Outcome constants. Names which contain U-labels
+// the policy doesn't allow, or A-labels which aren't valid IDNA2008, are
+// rejected with a problem. Other problems with the name, such as invalid
+// characters, are left for the RA to find.
+func (p IDNPolicy) NormalizeDNSName(name string) (string, string, *probs.ProblemDetails) {
+	// Only the rest of a wildcard name is an IDN.
+	prefix, rest := "", name
+	if strings.HasPrefix(name, "*.") {
+		prefix, rest = "*.", name[2:]
+	}
+
+	outcome := OutcomeUnchanged
+	var normalized string
+	if isASCII(rest) {
+		normalized = strings.ToLower(rest)
+		if normalized != rest {
+			outcome = OutcomeLowercased
+		}
+	} else {
+		if !p.AllowUnicode {
+			return "", OutcomeUnicodeRejected, probs.RejectedIdentifier(fmt.Sprintf(
+				"Domain name %q contains Unicode characters; internationalized names must be submitted as A-labels (punycode)", name))
+		}
+		var err error
+		normalized, err = profile.ToASCII(rest)
+		if err != nil {
+			return "", OutcomeInvalidIDN, probs.Malformed(fmt.Sprintf(
+				"Domain name %q is not a valid IDNA2008 name: %s", name, err))
+		}
+		outcome = OutcomeConverted
+	}
+
+	for _, label := range strings.Split(normalized, ".") {
+		if !strings.HasPrefix(label, "xn--") {
+			continue
+		}
+		ulabel, err := profile.ToUnicode(label)
+		if err != nil {
+			return "", OutcomeInvalidIDN, probs.Malformed(fmt.Sprintf(
+				"Domain name %q contains label %q, which is not a valid IDNA2008 A-label: %s", name, label, err))
+		}
+		// Each U-label has only one A-label, so reject other encodings of it.
+		alabel, err := profile.ToASCII(ulabel)
+		if err != nil || alabel != label {
+			return "", OutcomeInvalidIDN, probs.Malformed(fmt.Sprintf(
+				"Domain name %q contains label %q, which is not the canonical A-label for %q", name, label, ulabel))
+		}
+		if detail := p.checkScripts(ulabel); detail != "" {
+			return "", OutcomeScriptRejected, probs.RejectedIdentifier(fmt.Sprintf(
+				"Domain name %q contains label %q (%q), which %s", name, label, ulabel, detail))
+		}
+	}
+
+	return prefix + normalized, outcome, nil
+}
+
+// checkScripts returns a description of how a U-label breaks the policy's
+// script rules, or "" if it doesn't.
+func (p IDNPolicy) checkScripts(ulabel string) string {
+	scripts := labelScripts(ulabel)
+	if len(p.AllowedScripts) > 0 {
+		allowed := make(map[string]bool)
+		for _, script := range p.AllowedScripts {
+			allowed[script] = true
+		}
+		for _, script := range scripts {
+			if !allowed[script] {
+				return fmt.Sprintf("uses the %s script, which is not allowed", script)
+			}
+		}
+	}
+	if len(scripts) <= 1 {
+		return ""
+	}
+	switch p.ScriptPolicy {
+	case ScriptPolicySingle:
+		return fmt.Sprintf("mixes the %s scripts", strings.Join(scripts, " and "))
+	case ScriptPolicyHighlyRestrictive:
+		for _, set := range highlyRestrictiveSets {
+			if subset(scripts, set) {
+				return ""
+			}
+		}
+		return fmt.Sprintf("mixes the %s scripts", strings.Join(scripts, " and "))
+	}
+	return ""
+}
+
+// labelScripts returns the sorted names of the scripts used by a U-label,
+// other than Common and Inherited, which are used with every script.
+func labelScripts(ulabel string) []string {
+	found := make(map[string]bool)
+	for _, r := range ulabel {
+		if unicode.In(r, unicode.Common, unicode.Inherited) {
+			continue
+		}
+		for name, table := range unicode.Scripts {
+			if unicode.Is(table, r) {
+				found[name] = true
+				break
+			}
+		}
+	}
+	var scripts []string
+	for name := range found {
+		scripts = append(scripts, name)
+	}
+	sort.Strings(scripts)
+	return scripts
+}
+
+// subset returns true if every element of a is in b.
+func subset(a, b []string) bool {
+	for _, x := range a {
+		in := false
+		for _, y := range b {
+			if x == y {
+				in = true
+				break
+			}
+		}
+		if !in {
+			return false
+		}
+	}
+	return true
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] > unicode.MaxASCII {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,139 @@
+package policy
+
+import (
+	"testing"
+
+	"github.com/letsencrypt/boulder/probs"
+	"github.com/letsencrypt/boulder/test"
+)
+
+func TestIDNPolicyValidate(t *testing.T) {
+	test.AssertNotError(t, IDNPolicy{}.Validate(), "zero policy rejected")
+	test.AssertNotError(t, IDNPolicy{
+		ScriptPolicy:   ScriptPolicyHighlyRestrictive,
+		AllowedScripts: []string{"Latin", "Cyrillic"},
+	}.Validate(), "valid policy rejected")
+	test.AssertError(t, IDNPolicy{ScriptPolicy: "lax"}.Validate(), "unknown script policy accepted")
+	test.AssertError(t, IDNPolicy{AllowedScripts: []string{"Klingon"}}.Validate(), "unknown script accepted")
+}
+
+func TestNormalizeDNSName(t *testing.T) {
+	testCases := []struct {
+		name       string
+		policy     IDNPolicy
+		expected   string
+		outcome    string
+		probType   probs.ProblemType
+		probDetail string
+	}{
+		{
+			name:     "example.com",
+			expected: "example.com",
+			outcome:  OutcomeUnchanged,
+		},
+		{
+			name:     "*.Example.COM",
+			expected: "*.example.com",
+			outcome:  OutcomeLowercased,
+		},
+		{
+			name:     "XN--BCHER-KVA.example",
+			expected: "xn--bcher-kva.example",
+			outcome:  OutcomeLowercased,
+		},
+		{
+			name:       "bücher.example",
+			outcome:    OutcomeUnicodeRejected,
+			probType:   probs.RejectedIdentifierProblem,
+			probDetail: "internationalized names must be submitted as A-labels",
+		},
+		{
+			name:     "*.Bücher.example",
+			policy:   IDNPolicy{AllowUnicode: true},
+			expected: "*.xn--bcher-kva.example",
+			outcome:  OutcomeConverted,
+		},
+		{
+			// IDNA2008 doesn't map ß to ss, as the transitional mapping does.
+			name:     "straße.example",
+			policy:   IDNPolicy{AllowUnicode: true},
+			expected: "xn--strae-oqa.example",
+			outcome:  OutcomeConverted,
+		},
+		{
+			// A zero width joiner is only permitted in some contexts.
+			name:       "a‍b.example",
+			policy:     IDNPolicy{AllowUnicode: true},
+			outcome:    OutcomeInvalidIDN,
+			probType:   probs.MalformedProblem,
+			probDetail: "is not a valid IDNA2008 name",
+		},
+		{
+			name:       "xn--zz.example",
+			outcome:    OutcomeInvalidIDN,
+			probType:   probs.MalformedProblem,
+			probDetail: `label "xn--zz", which is not a valid IDNA2008 A-label`,
+		},
+		{
+			// The Cyrillic "а" looks like the Latin "a".
+			name:     "xn--pple-43d.com",
+			expected: "xn--pple-43d.com",
+			outcome:  OutcomeUnchanged,
+		},
+		{
+			name:       "xn--pple-43d.com",
+			policy:     IDNPolicy{ScriptPolicy: ScriptPolicySingle},
+			outcome:    OutcomeScriptRejected,
+			probType:   probs.RejectedIdentifierProblem,
+			probDetail: "mixes the Cyrillic and Latin scripts",
+		},
+		{
+			name:       "аpple.com",
+			policy:     IDNPolicy{AllowUnicode: true, ScriptPolicy: ScriptPolicyHighlyRestrictive},
+			outcome:    OutcomeScriptRejected,
+			probType:   probs.RejectedIdentifierProblem,
+			probDetail: "mixes the Cyrillic and Latin scripts",
+		},
+		{
+			// Japanese mixes Han, Hiragana and Katakana, and often Latin.
+			name:     "例えばtest.jp",
+			policy:   IDNPolicy{AllowUnicode: true, ScriptPolicy: ScriptPolicyHighlyRestrictive},
+			expected: "xn--test-p63c6it40r.jp",
+			outcome:  OutcomeConverted,
+		},
+		{
+			name:       "例えばtest.jp",
+			policy:     IDNPolicy{AllowUnicode: true, ScriptPolicy: ScriptPolicySingle},
+			outcome:    OutcomeScriptRejected,
+			probType:   probs.RejectedIdentifierProblem,
+			probDetail: "mixes the Han and Hiragana and Latin scripts",
+		},
+		{
+			name:     "пример.example",
+			policy:   IDNPolicy{AllowUnicode: true, AllowedScripts: []string{"Cyrillic", "Latin"}},
+			expected: "xn--e1afmkfd.example",
+			outcome:  OutcomeConverted,
+		},
+		{
+			name:       "παράδειγμα.example",
+			policy:     IDNPolicy{AllowUnicode: true, AllowedScripts: []string{"Cyrillic", "Latin"}},
+			outcome:    OutcomeScriptRejected,
+			probType:   probs.RejectedIdentifierProblem,
+			probDetail: "uses the Greek script, which is not allowed",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			normalized, outcome, prob := tc.policy.NormalizeDNSName(tc.name)
+			test.AssertEquals(t, outcome, tc.outcome)
+			if tc.probType == "" {
+				test.Assert(t, prob == nil, "unexpected problem")
+				test.AssertEquals(t, normalized, tc.expected)
+				return
+			}
+			test.AssertNotNil(t, prob, "expected a problem")
+			test.AssertEquals(t, prob.Type, tc.probType)
+			test.AssertContains(t, prob.Detail, tc.probDetail)
+		})
+	}
+}
@@ -17,6 +17,14 @@ type wfe2Stats struct {
 	// improperECFieldLengths counts the number of ACME account EC JWKs we see
 	// with improper X and Y lengths for their curve
 	improperECFieldLengths prometheus.Counter
+	// budgetSaturation observes the fraction of its endpoint's timeout budget
+	// each request used, and budgetExhausted counts the requests whose context
+	// ran out of it, by endpoint
+	budgetSaturation *prometheus.HistogramVec
+	budgetExhausted  *prometheus.CounterVec
+	// idnNormalizations counts the outcomes of normalizing the DNS names in
+	// new orders with the WFE's IDNPolicy
+	idnNormalizations *prometheus.CounterVec
 }
 
 func initStats(stats prometheus.Registerer) wfe2Stats {
@@ -53,10 +61,41 @@ func initStats(stats prometheus.Registerer) wfe2Stats {
 	)
 	stats.MustRegister(improperECFieldLengths)
 
+	budgetSaturation := prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "request_budget_saturation",
+			Help:    "Fraction of the endpoint's timeout budget used by each request",
+			Buckets: []float64{0.05, 0.1, 0.25, 0.5, 0.75, 0.9, 1},
+		},
+		[]string{"endpoint"},
+	)
+	stats.MustRegister(budgetSaturation)
+
+	budgetExhausted := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "request_budget_exhausted",
+			Help: "Number of requests which ran out of their endpoint's timeout budget",
+		},
+		[]string{"endpoint"},
+	)
+	stats.MustRegister(budgetExhausted)
+
+	idnNormalizations := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "idn_normalizations",
+			Help: "Number of DNS names in new orders normalized by the IDN policy, by outcome",
+		},
+		[]string{"outcome"},
+	)
+	stats.MustRegister(idnNormalizations)
+
 	return wfe2Stats{
 		httpErrorCount:         httpErrorCount,
 		joseErrorCount:         joseErrorCount,
 		csrSignatureAlgs:       csrSignatureAlgs,
 		improperECFieldLengths: improperECFieldLengths,
+		budgetSaturation:       budgetSaturation,
+		budgetExhausted:        budgetExhausted,
+		idnNormalizations:      idnNormalizations,
 	}
 }
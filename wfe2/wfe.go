@@ -32,7 +32,10 @@ import (
 	"github.com/letsencrypt/boulder/revocation"
 	sapb "github.com/letsencrypt/boulder/sa/proto"
 	"github.com/letsencrypt/boulder/web"
+	"github.com/letsencrypt/boulder/wfe2/policy"
 	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	jose "gopkg.in/square/go-jose.v2"
 )
 
@@ -56,6 +59,7 @@ const (
 	newOrderPath      = "/acme/new-order"
 	orderPath         = "/acme/order/"
 	finalizeOrderPath = "/acme/finalize/"
+	acctEventsPath    = "/acme/acct-events/"
 
 	getAPIPrefix     = "/get/"
 	getOrderPath     = getAPIPrefix + "order/"
@@ -98,6 +102,11 @@ type WebFrontEndImpl struct {
 	// "website" field.
 	DirectoryWebsite string
 
+	// EmailChallengeFrom is the address from which challenge emails for
+	// email-reply-00 challenges are sent, shown in those challenges' "from"
+	// field.
+	EmailChallengeFrom string
+
 	// Allowed prefix for legacy accounts used by verify.go's `lookupJWK`.
 	// See `cmd/boulder-wfe2/main.go`'s comment on the configuration field
 	// `LegacyKeyIDPrefix` for more information.
@@ -108,6 +117,10 @@ type WebFrontEndImpl struct {
 	remoteNonceService noncepb.NonceServiceClient
 	noncePrefixMap     map[string]noncepb.NonceServiceClient
 
+	// NoncePool, if set, provides the nonces which would otherwise each be
+	// fetched from remoteNonceService.
+	NoncePool *nonce.Pool
+
 	// Key policy.
 	keyPolicy goodkey.KeyPolicy
 
@@ -117,6 +130,28 @@ type WebFrontEndImpl struct {
 	// Maximum duration of a request
 	RequestTimeout time.Duration
 
+	// GETRequestTimeout, if non-zero, is the maximum duration of GET and HEAD
+	// requests, which can be held to a tighter budget than RequestTimeout.
+	GETRequestTimeout time.Duration
+
+	// EndpointTimeouts are the maximum durations of requests to particular
+	// endpoints, keyed by their path, such as "/acme/finalize/". They take
+	// precedence over RequestTimeout and GETRequestTimeout.
+	EndpointTimeouts map[string]time.Duration
+
+	// OverloadRetryAfter is sent as the Retry-After of the 503 responses to
+	// requests which run out of time. Defaults to 30 seconds.
+	OverloadRetryAfter time.Duration
+
+	// IDNPolicy, if set, normalizes the DNS names in new orders, converting
+	// U-labels to A-labels and enforcing its script rules, before they're
+	// sent to the RA.
+	IDNPolicy *policy.IDNPolicy
+
+	// CertificateProfiles are the names of the certificate profiles which new
+	// orders may request. They should match those configured in the RA.
+	CertificateProfiles []string
+
 	// StaleTimeout determines the required staleness for resources allowed to be
 	// accessed via Boulder-specific GET-able APIs. Resources newer than
 	// staleTimeout must be accessed via POST-as-GET and the RFC 8555 ACME API. We
@@ -210,12 +245,12 @@ func (wfe *WebFrontEndImpl) HandleFunc(mux *http.ServeMux, pattern string, h web
 				// clearer both in our metrics and to the client that
 				// something is wrong.
 				if wfe.remoteNonceService != nil {
-					nonceMsg, err := wfe.remoteNonceService.Nonce(ctx, &corepb.Empty{})
+					nonce, err := wfe.remoteNonce(ctx)
 					if err != nil {
 						wfe.sendError(response, logEvent, probs.ServerInternal("unable to get nonce"), err)
 						return
 					}
-					response.Header().Set("Replay-Nonce", nonceMsg.Nonce)
+					response.Header().Set("Replay-Nonce", nonce)
 				} else {
 					nonce, err := wfe.nonceService.Nonce()
 					if err == nil {
@@ -259,21 +294,43 @@ func (wfe *WebFrontEndImpl) HandleFunc(mux *http.ServeMux, pattern string, h web
 
 			wfe.setCORSHeaders(response, request, "")
 
-			timeout := wfe.RequestTimeout
-			if timeout == 0 {
-				timeout = 5 * time.Minute
-			}
+			timeout := wfe.requestTimeout(pattern, request.Method)
 			ctx, cancel := context.WithTimeout(ctx, timeout)
 			// TODO(riking): add request context using WithValue
 
-			// Call the wrapped handler.
+			// Call the wrapped handler, recording how much of its budget it
+			// used. The context's deadline is in real time, so the budget is
+			// measured in it too.
+			begin := time.Now()
 			h(ctx, logEvent, response, request)
+			wfe.stats.budgetSaturation.With(prometheus.Labels{"endpoint": pattern}).Observe(
+				time.Since(begin).Seconds() / timeout.Seconds())
+			// Only the request's own context running out counts as exhausting
+			// its budget, not a backend call's shorter gRPC timeout.
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				wfe.stats.budgetExhausted.With(prometheus.Labels{"endpoint": pattern}).Inc()
+			}
 			cancel()
 		}),
 	))
 	mux.Handle(pattern, handler)
 }
 
+// requestTimeout returns the timeout budget for a request to the endpoint
+// registered at pattern, using the given method.
+func (wfe *WebFrontEndImpl) requestTimeout(pattern, method string) time.Duration {
+	if timeout, ok := wfe.EndpointTimeouts[pattern]; ok && timeout > 0 {
+		return timeout
+	}
+	if (method == "GET" || method == "HEAD") && wfe.GETRequestTimeout > 0 {
+		return wfe.GETRequestTimeout
+	}
+	if wfe.RequestTimeout > 0 {
+		return wfe.RequestTimeout
+	}
+	return 5 * time.Minute
+}
+
 func marshalIndent(v interface{}) ([]byte, error) {
 	return json.MarshalIndent(v, "", "  ")
 }
@@ -362,6 +419,7 @@ func (wfe *WebFrontEndImpl) Handler(stats prometheus.Registerer) http.Handler {
 	wfe.HandleFunc(m, rolloverPath, wfe.KeyRollover, "POST")
 	wfe.HandleFunc(m, newOrderPath, wfe.NewOrder, "POST")
 	wfe.HandleFunc(m, finalizeOrderPath, wfe.FinalizeOrder, "POST")
+	wfe.HandleFunc(m, acctEventsPath, wfe.AccountEvents, "POST")
 
 	// GETable and POST-as-GETable ACME endpoints
 	wfe.HandleFunc(m, directoryPath, wfe.Directory, "GET", "POST")
@@ -525,10 +583,28 @@ func (wfe *WebFrontEndImpl) Nonce(
 
 // sendError wraps web.SendError
 func (wfe *WebFrontEndImpl) sendError(response http.ResponseWriter, logEvent *web.RequestEvent, prob *probs.ProblemDetails, ierr error) {
+	// An internal error caused by the request running out of its timeout
+	// budget, or by a backend call's own timeout, most likely waiting on an
+	// overloaded backend, is sent as a 503 so that clients back off and retry
+	// rather than treating it as a failure.
+	if prob.Type == probs.ServerInternalProblem && deadlineExceeded(ierr) {
+		prob = probs.ServiceUnavailable(prob.Detail + " :: Service is overloaded, please retry later")
+		retryAfter := wfe.OverloadRetryAfter
+		if retryAfter == 0 {
+			retryAfter = 30 * time.Second
+		}
+		response.Header().Set("Retry-After", strconv.Itoa(int(retryAfter.Seconds())))
+	}
 	wfe.stats.httpErrorCount.With(prometheus.Labels{"type": string(prob.Type)}).Inc()
 	web.SendError(wfe.log, probs.V2ErrorNS, response, logEvent, prob, ierr)
 }
 
+// deadlineExceeded returns true if err was caused by a context deadline,
+// either locally or in a gRPC backend.
+func deadlineExceeded(err error) bool {
+	return errors.Is(err, context.DeadlineExceeded) || status.Code(err) == codes.DeadlineExceeded
+}
+
 func link(url, relation string) string {
 	return fmt.Sprintf("<%s>;rel=\"%s\"", url, relation)
 }
@@ -1086,6 +1162,10 @@ func (wfe *WebFrontEndImpl) prepChallengeForDisplay(request *http.Request, authz
 	// ACMEv2 never sends the KeyAuthorization back in a challenge object.
 	challenge.ProvidedKeyAuthorization = ""
 
+	if challenge.Type == core.ChallengeTypeEmailReply00 {
+		challenge.From = wfe.EmailChallengeFrom
+	}
+
 	// Historically the Type field of a problem was always prefixed with a static
 	// error namespace. To support the V2 API and migrating to the correct IETF
 	// namespace we now prefix the Type with the correct namespace at runtime when
@@ -1298,6 +1378,96 @@ func (wfe *WebFrontEndImpl) Account(
 	}
 }
 
+// accountEventsPageSize is the number of events each page of an account's
+// audit events holds.
+const accountEventsPageSize = 50
+
+// accountEvent is an event from an account's audit trail, as shown to the
+// account's owner.
+type accountEvent struct {
+	ID      int64           `json:"id"`
+	Type    string          `json:"type"`
+	Created time.Time       `json:"created"`
+	Details json.RawMessage `json:"details"`
+}
+
+// AccountEvents handles POST-as-GET requests for a page of an account's audit
+// events, newest first. The path is the account's ID, optionally followed by
+// the ID of the event to page back from, like "1/500". If there may be older
+// events, a "next" Link header points to the next page.
+func (wfe *WebFrontEndImpl) AccountEvents(
+	ctx context.Context,
+	logEvent *web.RequestEvent,
+	response http.ResponseWriter,
+	request *http.Request) {
+	if !features.Enabled(features.AccountEvents) {
+		wfe.sendError(response, logEvent, probs.NotFound("Account events are not available"), nil)
+		return
+	}
+	acct, prob := wfe.validPOSTAsGETForAccount(request, ctx, logEvent)
+	addRequesterHeader(response, logEvent.Requester)
+	if prob != nil {
+		// validPOSTAsGETForAccount handles its own setting of logEvent.Errors
+		wfe.sendError(response, logEvent, prob, nil)
+		return
+	}
+
+	// Path prefix is stripped, so this should be like "<account ID>" or
+	// "<account ID>/<before event ID>"
+	fields := strings.SplitN(request.URL.Path, "/", 2)
+	id, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		wfe.sendError(response, logEvent, probs.Malformed("Account ID must be an integer"), err)
+		return
+	} else if id != acct.ID {
+		wfe.sendError(response, logEvent,
+			probs.Unauthorized("Request signing key did not match account key"), nil)
+		return
+	}
+	var beforeID int64
+	if len(fields) == 2 {
+		beforeID, err = strconv.ParseInt(fields[1], 10, 64)
+		if err != nil || beforeID <= 0 {
+			wfe.sendError(response, logEvent, probs.Malformed("Event ID must be a positive integer"), err)
+			return
+		}
+	}
+
+	events, err := wfe.SA.GetAccountEvents(ctx, &sapb.GetAccountEventsRequest{
+		RegistrationID: acct.ID,
+		BeforeID:       beforeID,
+		Limit:          accountEventsPageSize,
+	})
+	if err != nil {
+		wfe.sendError(response, logEvent, probs.ServerInternal("Failed to retrieve account events"), err)
+		return
+	}
+	respObj := struct {
+		Events []accountEvent `json:"events"`
+	}{
+		Events: make([]accountEvent, 0, len(events.Events)),
+	}
+	for _, event := range events.Events {
+		respObj.Events = append(respObj.Events, accountEvent{
+			ID:      event.Id,
+			Type:    event.Type,
+			Created: time.Unix(0, event.Created).UTC(),
+			Details: json.RawMessage(event.Details),
+		})
+	}
+	if len(respObj.Events) == accountEventsPageSize {
+		last := respObj.Events[len(respObj.Events)-1]
+		response.Header().Add("Link", link(
+			web.RelativeEndpoint(request, fmt.Sprintf("%s%d/%d", acctEventsPath, acct.ID, last.ID)), "next"))
+	}
+
+	err = wfe.writeJsonResponse(response, logEvent, http.StatusOK, respObj)
+	if err != nil {
+		wfe.sendError(response, logEvent, probs.ServerInternal("Failed to marshal account events"), err)
+		return
+	}
+}
+
 // updateAccount unmarshals an account update request from the provided
 // requestBody to update the given registration. Important: It is assumed the
 // request has already been authenticated by the caller. If the request is
@@ -1838,6 +2008,7 @@ type orderJSON struct {
 	Finalize       string                      `json:"finalize"`
 	Certificate    string                      `json:"certificate,omitempty"`
 	Error          *probs.ProblemDetails       `json:"error,omitempty"`
+	Profile        string                      `json:"profile,omitempty"`
 }
 
 // orderToOrderJSON converts a *corepb.Order instance into an orderJSON struct
@@ -1847,7 +2018,7 @@ type orderJSON struct {
 func (wfe *WebFrontEndImpl) orderToOrderJSON(request *http.Request, order *corepb.Order) orderJSON {
 	idents := make([]identifier.ACMEIdentifier, len(order.Names))
 	for i, name := range order.Names {
-		idents[i] = identifier.ACMEIdentifier{Type: identifier.DNS, Value: name}
+		idents[i] = identifier.FromValue(name)
 	}
 	finalizeURL := web.RelativeEndpoint(request,
 		fmt.Sprintf("%s%d/%d", finalizeOrderPath, order.RegistrationID, order.Id))
@@ -1856,6 +2027,7 @@ func (wfe *WebFrontEndImpl) orderToOrderJSON(request *http.Request, order *corep
 		Expires:     time.Unix(0, order.Expires).UTC(),
 		Identifiers: idents,
 		Finalize:    finalizeURL,
+		Profile:     order.CertificateProfileName,
 	}
 	// If there is an order error, prefix its type with the V2 namespace
 	if order.Error != nil {
@@ -1878,6 +2050,17 @@ func (wfe *WebFrontEndImpl) orderToOrderJSON(request *http.Request, order *corep
 	return respObj
 }
 
+// profileConfigured returns whether new orders may request the named
+// certificate profile.
+func (wfe *WebFrontEndImpl) profileConfigured(name string) bool {
+	for _, profile := range wfe.CertificateProfiles {
+		if profile == name {
+			return true
+		}
+	}
+	return false
+}
+
 // NewOrder is used by clients to create a new order object from a CSR
 func (wfe *WebFrontEndImpl) NewOrder(
 	ctx context.Context,
@@ -1892,11 +2075,13 @@ func (wfe *WebFrontEndImpl) NewOrder(
 		return
 	}
 
-	// We only allow specifying Identifiers in a new order request - if the
-	// `notBefore` and/or `notAfter` fields described in Section 7.4 of acme-08
-	// are sent we return a probs.Malformed as we do not support them
+	// We only allow specifying Identifiers, and optionally the name of a
+	// certificate profile, in a new order request - if the `notBefore` and/or
+	// `notAfter` fields described in Section 7.4 of acme-08 are sent we return a
+	// probs.Malformed as we do not support them
 	var newOrderRequest struct {
 		Identifiers         []identifier.ACMEIdentifier `json:"identifiers"`
+		Profile             string                      `json:"profile"`
 		NotBefore, NotAfter string
 	}
 	err := json.Unmarshal(body, &newOrderRequest)
@@ -1915,12 +2100,20 @@ func (wfe *WebFrontEndImpl) NewOrder(
 		wfe.sendError(response, logEvent, probs.Malformed("NotBefore and NotAfter are not supported"), nil)
 		return
 	}
+	if newOrderRequest.Profile != "" && !wfe.profileConfigured(newOrderRequest.Profile) {
+		wfe.sendError(response, logEvent,
+			probs.Malformed("NewOrder request specified unknown certificate profile %q", newOrderRequest.Profile), nil)
+		return
+	}
 
-	// Collect up all of the DNS identifier values into a []string for subsequent
-	// layers to process. We reject anything with a non-DNS type identifier here.
+	// Collect up all of the identifier values into a []string for subsequent
+	// layers to process, which tell DNS names and email addresses apart by
+	// whether they contain an "@". We reject anything with a type identifier
+	// other than DNS, or email if email identifiers are enabled, here.
 	names := make([]string, len(newOrderRequest.Identifiers))
 	for i, ident := range newOrderRequest.Identifiers {
-		if ident.Type != identifier.DNS {
+		emailOK := ident.Type == identifier.Email && features.Enabled(features.EmailIdentifiers)
+		if ident.Type != identifier.DNS && !emailOK {
 			wfe.sendError(response, logEvent,
 				probs.Malformed("NewOrder request included invalid non-DNS type identifier: type %q, value %q",
 					ident.Type, ident.Value),
@@ -1931,12 +2124,30 @@ func (wfe *WebFrontEndImpl) NewOrder(
 			wfe.sendError(response, logEvent, probs.Malformed("NewOrder request included empty domain name"), nil)
 			return
 		}
+		if identifier.FromValue(ident.Value).Type != ident.Type {
+			wfe.sendError(response, logEvent,
+				probs.Malformed("NewOrder request included invalid %s identifier %q", ident.Type, ident.Value),
+				nil)
+			return
+		}
 		names[i] = ident.Value
+		if ident.Type == identifier.DNS && wfe.IDNPolicy != nil {
+			normalized, outcome, prob := wfe.IDNPolicy.NormalizeDNSName(ident.Value)
+			wfe.stats.idnNormalizations.With(prometheus.Labels{"outcome": outcome}).Inc()
+			if prob != nil {
+				wfe.sendError(response, logEvent, prob.WithSubProblems([]probs.SubProblemDetails{
+					{ProblemDetails: *prob, Identifier: ident},
+				}), nil)
+				return
+			}
+			names[i] = normalized
+		}
 	}
 
 	order, err := wfe.RA.NewOrder(ctx, &rapb.NewOrderRequest{
-		RegistrationID: acct.ID,
-		Names:          names,
+		RegistrationID:         acct.ID,
+		Names:                  names,
+		CertificateProfileName: newOrderRequest.Profile,
 	})
 	if err != nil {
 		wfe.sendError(response, logEvent, web.ProblemDetailsForError(err, "Error creating new order"), err)
@@ -2164,3 +2375,16 @@ func extractRequesterIP(req *http.Request) (net.IP, error) {
 func urlForAuthz(authz core.Authorization, request *http.Request) string {
 	return web.RelativeEndpoint(request, authzPath+string(authz.ID))
 }
+
+// remoteNonce gets a nonce from the NoncePool, if there is one, or otherwise
+// from the remote nonce service.
+func (wfe *WebFrontEndImpl) remoteNonce(ctx context.Context) (string, error) {
+	if wfe.NoncePool != nil {
+		return wfe.NoncePool.Nonce(ctx)
+	}
+	nonceMsg, err := wfe.remoteNonceService.Nonce(ctx, &corepb.Empty{})
+	if err != nil {
+		return "", err
+	}
+	return nonceMsg.Nonce, nil
+}
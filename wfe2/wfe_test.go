@@ -25,6 +25,8 @@ import (
 	"time"
 
 	"github.com/jmhodges/clock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	jose "gopkg.in/square/go-jose.v2"
 
 	"github.com/letsencrypt/boulder/core"
@@ -44,6 +46,7 @@ import (
 	sapb "github.com/letsencrypt/boulder/sa/proto"
 	"github.com/letsencrypt/boulder/test"
 	"github.com/letsencrypt/boulder/web"
+	"github.com/letsencrypt/boulder/wfe2/policy"
 )
 
 const (
@@ -263,6 +266,8 @@ func (ra *MockRegistrationAuthority) NewOrder(ctx context.Context, req *rapb.New
 		Names:            req.Names,
 		Status:           string(core.StatusPending),
 		V2Authorizations: []int64{1},
+
+		CertificateProfileName: req.CertificateProfileName,
 	}, nil
 }
 
@@ -636,6 +641,72 @@ func TestHandleFunc(t *testing.T) {
 	}
 }
 
+func TestHandleFuncTimeouts(t *testing.T) {
+	wfe, _ := setupWFE(t)
+	wfe.RequestTimeout = time.Minute
+	wfe.GETRequestTimeout = 10 * time.Second
+	wfe.EndpointTimeouts = map[string]time.Duration{"/slow": 2 * time.Minute}
+
+	// The stub handler records its budget, and fails POSTs as though a backend
+	// ran out of time. PUTs wait for the request's own budget to run out.
+	var budget time.Duration
+	mux := http.NewServeMux()
+	stub := func(ctx context.Context, logEvent *web.RequestEvent, response http.ResponseWriter, request *http.Request) {
+		deadline, _ := ctx.Deadline()
+		budget = time.Until(deadline)
+		if request.Method == "PUT" {
+			<-ctx.Done()
+			wfe.sendError(response, logEvent, probs.ServerInternal("Error finalizing order"), ctx.Err())
+		}
+		if request.Method == "POST" {
+			wfe.sendError(response, logEvent, probs.ServerInternal("Error finalizing order"),
+				status.Error(codes.DeadlineExceeded, "context deadline exceeded"))
+		}
+	}
+	wfe.HandleFunc(mux, "/fast", stub, "GET", "POST")
+	wfe.HandleFunc(mux, "/slow", stub, "GET", "POST")
+	wfe.HandleFunc(mux, "/expiring", stub, "PUT")
+	serve := func(method, pattern string) *httptest.ResponseRecorder {
+		rw := httptest.NewRecorder()
+		mux.ServeHTTP(rw, &http.Request{Method: method, URL: mustParseURL(pattern)})
+		return rw
+	}
+	assertBudget := func(expected time.Duration) {
+		t.Helper()
+		test.Assert(t, budget > expected-time.Second && budget <= expected,
+			fmt.Sprintf("expected a budget of %s, got %s", expected, budget))
+	}
+
+	// GETs get the tighter budget, unless their endpoint has its own.
+	rw := serve("GET", "/fast")
+	test.AssertEquals(t, rw.Code, http.StatusOK)
+	assertBudget(10 * time.Second)
+	serve("GET", "/slow")
+	assertBudget(2 * time.Minute)
+
+	// A backend running out of time is a 503 with a Retry-After, but isn't
+	// counted as the request exhausting its own budget.
+	rw = serve("POST", "/fast")
+	assertBudget(time.Minute)
+	test.AssertEquals(t, rw.Code, http.StatusServiceUnavailable)
+	test.AssertEquals(t, rw.Header().Get("Retry-After"), "30")
+	test.AssertUnmarshaledEquals(t, rw.Body.String(),
+		`{"type":"`+probs.V2ErrorNS+`serverInternal","detail":"Error finalizing order :: Service is overloaded, please retry later","status":503}`)
+	test.AssertEquals(t, test.CountCounterVec("endpoint", "/fast", wfe.stats.budgetExhausted), 0)
+
+	wfe.OverloadRetryAfter = time.Minute
+	rw = serve("POST", "/slow")
+	assertBudget(2 * time.Minute)
+	test.AssertEquals(t, rw.Header().Get("Retry-After"), "60")
+
+	// Running out of the request's own budget is counted, by endpoint.
+	wfe.EndpointTimeouts["/expiring"] = time.Millisecond
+	rw = serve("PUT", "/expiring")
+	test.AssertEquals(t, rw.Code, http.StatusServiceUnavailable)
+	test.AssertEquals(t, test.CountCounterVec("endpoint", "/expiring", wfe.stats.budgetExhausted), 1)
+	test.AssertEquals(t, test.CountCounterVec("endpoint", "/slow", wfe.stats.budgetExhausted), 0)
+}
+
 func TestPOST404(t *testing.T) {
 	wfe, _ := setupWFE(t)
 	responseWriter := httptest.NewRecorder()
@@ -953,6 +1024,11 @@ func TestHTTPMethods(t *testing.T) {
 			Path:    acctPath,
 			Allowed: postOnly,
 		},
+		{
+			Name:    "Acct events path should be POST only",
+			Path:    acctEventsPath,
+			Allowed: postOnly,
+		},
 		// TODO(@cpu): Remove GET authz support, support only POST-as-GET
 		{
 			Name:    "Authz path should be GET or POST only",
@@ -2172,6 +2248,7 @@ func TestDeactivateAccount(t *testing.T) {
 
 func TestNewOrder(t *testing.T) {
 	wfe, _ := setupWFE(t)
+	wfe.CertificateProfiles = []string{"shortlived"}
 	responseWriter := httptest.NewRecorder()
 
 	targetHost := "localhost"
@@ -2243,6 +2320,11 @@ func TestNewOrder(t *testing.T) {
 			Request:      signAndPost(t, targetPath, signedURL, `{"identifiers":[{"type": "dns", "value": "not-example.com"}], "notBefore":"now", "notAfter": "later"}`, 1, wfe.nonceService),
 			ExpectedBody: `{"type":"` + probs.V2ErrorNS + `malformed","detail":"NotBefore and NotAfter are not supported","status":400}`,
 		},
+		{
+			Name:         "POST, unknown certificate profile in payload",
+			Request:      signAndPost(t, targetPath, signedURL, `{"identifiers":[{"type": "dns", "value": "not-example.com"}], "profile": "unknown"}`, 1, wfe.nonceService),
+			ExpectedBody: `{"type":"` + probs.V2ErrorNS + `malformed","detail":"NewOrder request specified unknown certificate profile \"unknown\"","status":400}`,
+		},
 		{
 			Name:    "POST, good payload",
 			Request: signAndPost(t, targetPath, signedURL, validOrderBody, 1, wfe.nonceService),
@@ -2260,6 +2342,23 @@ func TestNewOrder(t *testing.T) {
 						"finalize": "http://localhost/acme/finalize/1/1"
 					}`,
 		},
+		{
+			Name:    "POST, good payload with a certificate profile",
+			Request: signAndPost(t, targetPath, signedURL, `{"identifiers":[{"type": "dns", "value": "not-example.com"}], "profile": "shortlived"}`, 1, wfe.nonceService),
+			ExpectedBody: `
+					{
+						"status": "pending",
+						"expires": "1970-01-01T00:00:00Z",
+						"identifiers": [
+							{ "type": "dns", "value": "not-example.com"}
+						],
+						"authorizations": [
+							"http://localhost/acme/authz-v3/1"
+						],
+						"finalize": "http://localhost/acme/finalize/1/1",
+						"profile": "shortlived"
+					}`,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -2287,6 +2386,47 @@ func TestNewOrder(t *testing.T) {
 	}
 }
 
+func TestNewOrderIDNPolicy(t *testing.T) {
+	wfe, _ := setupWFE(t)
+	wfe.IDNPolicy = &policy.IDNPolicy{AllowUnicode: true, ScriptPolicy: policy.ScriptPolicySingle}
+	signedURL := "http://localhost/new-order"
+
+	// Unicode and mixed-case names reach the RA as lowercase A-labels.
+	responseWriter := httptest.NewRecorder()
+	wfe.NewOrder(ctx, newRequestEvent(), responseWriter, signAndPost(t, "new-order", signedURL,
+		`{"identifiers":[{"type":"dns","value":"*.Bücher.example"},{"type":"dns","value":"XN--BCHER-KVA.example"}]}`,
+		1, wfe.nonceService))
+	test.AssertEquals(t, responseWriter.Code, http.StatusCreated)
+	var order orderJSON
+	test.AssertNotError(t, json.Unmarshal(responseWriter.Body.Bytes(), &order), "unmarshaling order")
+	test.AssertDeepEquals(t, order.Identifiers, []identifier.ACMEIdentifier{
+		identifier.DNSIdentifier("*.xn--bcher-kva.example"),
+		identifier.DNSIdentifier("xn--bcher-kva.example"),
+	})
+	test.AssertEquals(t, test.CountCounterVec("outcome", policy.OutcomeConverted, wfe.stats.idnNormalizations), 1)
+	test.AssertEquals(t, test.CountCounterVec("outcome", policy.OutcomeLowercased, wfe.stats.idnNormalizations), 1)
+
+	// Names which break the script policy are rejected with a subproblem for
+	// the identifier.
+	responseWriter = httptest.NewRecorder()
+	wfe.NewOrder(ctx, newRequestEvent(), responseWriter, signAndPost(t, "new-order", signedURL,
+		`{"identifiers":[{"type":"dns","value":"аpple.com"}]}`,
+		1, wfe.nonceService))
+	detail := `Domain name \"аpple.com\" contains label \"xn--pple-43d\" (\"аpple\"), which mixes the Cyrillic and Latin scripts`
+	test.AssertUnmarshaledEquals(t, responseWriter.Body.String(), `{
+		"type": "`+probs.V2ErrorNS+`rejectedIdentifier",
+		"detail": "`+detail+`",
+		"status": 400,
+		"subproblems": [{
+			"type": "`+probs.V2ErrorNS+`rejectedIdentifier",
+			"detail": "`+detail+`",
+			"status": 400,
+			"identifier": {"type": "dns", "value": "аpple.com"}
+		}]
+	}`)
+	test.AssertEquals(t, test.CountCounterVec("outcome", policy.OutcomeScriptRejected, wfe.stats.idnNormalizations), 1)
+}
+
 func TestFinalizeOrder(t *testing.T) {
 	wfe, _ := setupWFE(t)
 	responseWriter := httptest.NewRecorder()
@@ -3333,3 +3473,81 @@ func TestGetAPIAndMandatoryPOSTAsGET(t *testing.T) {
 	wfe.Certificate(context.Background(), event, resp, req)
 	test.AssertEquals(t, resp.Code, 200)
 }
+
+// mockSAWithAccountEvents returns pages of events, counting down from its
+// newest event ID, for account 1.
+type mockSAWithAccountEvents struct {
+	core.StorageGetter
+	newest   int64
+	requests []*sapb.GetAccountEventsRequest
+}
+
+func (sa *mockSAWithAccountEvents) GetAccountEvents(_ context.Context, req *sapb.GetAccountEventsRequest) (*sapb.AccountEvents, error) {
+	sa.requests = append(sa.requests, req)
+	id := sa.newest
+	if req.BeforeID != 0 {
+		id = req.BeforeID - 1
+	}
+	var events []*sapb.AccountEvent
+	for ; id > 0 && int64(len(events)) < req.Limit; id-- {
+		events = append(events, &sapb.AccountEvent{
+			Id:             id,
+			RegistrationID: req.RegistrationID,
+			Created:        time.Date(2021, 2, 12, 0, 0, 0, 0, time.UTC).UnixNano(),
+			Type:           string(core.AccountEventNewOrder),
+			Details:        fmt.Sprintf(`{"orderID":%d}`, id),
+		})
+	}
+	return &sapb.AccountEvents{Events: events}, nil
+}
+
+func TestAccountEvents(t *testing.T) {
+	wfe, _ := setupWFE(t)
+	msa := &mockSAWithAccountEvents{StorageGetter: wfe.SA, newest: accountEventsPageSize + 1}
+	wfe.SA = msa
+	mux := wfe.Handler(metrics.NoopRegisterer)
+
+	postAsGet := func(path string) *httptest.ResponseRecorder {
+		responseWriter := httptest.NewRecorder()
+		signedURL := "http://localhost" + path
+		_, _, body := signRequestKeyID(t, 1, nil, signedURL, "", wfe.nonceService)
+		mux.ServeHTTP(responseWriter, makePostRequestWithPath(path, body))
+		return responseWriter
+	}
+
+	// The endpoint doesn't exist unless the feature is enabled.
+	responseWriter := postAsGet(acctEventsPath + "1")
+	test.AssertEquals(t, responseWriter.Code, http.StatusNotFound)
+
+	_ = features.Set(map[string]bool{"AccountEvents": true})
+	defer features.Reset()
+
+	responseWriter = postAsGet(acctEventsPath + "1")
+	test.AssertEquals(t, responseWriter.Code, http.StatusOK)
+	var page struct {
+		Events []accountEvent
+	}
+	err := json.Unmarshal(responseWriter.Body.Bytes(), &page)
+	test.AssertNotError(t, err, "unmarshalling events")
+	test.AssertEquals(t, len(page.Events), accountEventsPageSize)
+	test.AssertEquals(t, page.Events[0].ID, int64(accountEventsPageSize+1))
+	test.AssertEquals(t, page.Events[0].Type, "newOrder")
+	test.AssertUnmarshaledEquals(t, string(page.Events[0].Details), fmt.Sprintf(`{"orderID":%d}`, accountEventsPageSize+1))
+	test.AssertContains(t, strings.Join(responseWriter.Header()["Link"], ", "), `<http://localhost/acme/acct-events/1/2>;rel="next"`)
+	test.AssertEquals(t, msa.requests[0].RegistrationID, int64(1))
+
+	// The last page has no next link.
+	responseWriter = postAsGet(acctEventsPath + "1/2")
+	test.AssertEquals(t, responseWriter.Code, http.StatusOK)
+	test.AssertUnmarshaledEquals(t, responseWriter.Body.String(),
+		`{"events":[{"id":1,"type":"newOrder","created":"2021-02-12T00:00:00Z","details":{"orderID":1}}]}`)
+	test.AssertNotContains(t, strings.Join(responseWriter.Header()["Link"], ", "), `rel="next"`)
+	test.AssertEquals(t, msa.requests[1].BeforeID, int64(2))
+
+	// Accounts may only read their own events.
+	responseWriter = postAsGet(acctEventsPath + "2")
+	test.AssertEquals(t, responseWriter.Code, http.StatusForbidden)
+	responseWriter = postAsGet(acctEventsPath + "1/oops")
+	test.AssertEquals(t, responseWriter.Code, http.StatusBadRequest)
+	test.AssertEquals(t, len(msa.requests), 2)
+}